@@ -16,3 +16,32 @@ type Adaptor interface {
 type Porter interface {
 	Port() string
 }
+
+// PWMPinner is a hardware PWM pin, such as a sysfs PWM pin, whose period and
+// duty cycle are set in nanoseconds.
+type PWMPinner interface {
+	// Export exports the pin for use by the operating system
+	Export() error
+	// Unexport unexports the pin and releases the pin from the operating system
+	Unexport() error
+	// Enable enables/disables the PWM pin
+	Enable(bool) (err error)
+	// Polarity returns the polarity either normal or inverted
+	Polarity() (polarity string, err error)
+	// InvertPolarity sets the polarity to inverted if called with true
+	InvertPolarity(invert bool) (err error)
+	// Period returns the current PWM period for pin
+	Period() (period uint32, err error)
+	// SetPeriod sets the current PWM period for pin
+	SetPeriod(period uint32) (err error)
+	// DutyCycle returns the duty cycle for the pin
+	DutyCycle() (duty uint32, err error)
+	// SetDutyCycle writes the duty cycle to the pin
+	SetDutyCycle(duty uint32) (err error)
+}
+
+// PWMPinnerProvider is the interface that an Adaptor should implement to allow
+// clients to obtain access to any PWMPin's available on that board.
+type PWMPinnerProvider interface {
+	PWMPin(string) (PWMPinner, error)
+}
@@ -4,10 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/bmizerany/pat"
 	"gobot.io/x/gobot"
@@ -24,6 +25,14 @@ type API struct {
 	Key      string
 	handlers []func(http.ResponseWriter, *http.Request)
 	start    func(*API)
+	peers    map[string]Peer
+	mounts   []mount
+	// MaxJobs is the number of finished Jobs kept, DefaultMaxJobs when 0
+	MaxJobs int
+	jobs    map[string]*Job
+	jobIDs  []string
+	jobSeq  uint64
+	mutex   sync.Mutex
 }
 
 // NewAPI returns a new api instance
@@ -32,15 +41,16 @@ func NewAPI(m *gobot.Master) *API {
 		master: m,
 		router: pat.New(),
 		Port:   "3000",
+		peers:  make(map[string]Peer),
 		start: func(a *API) {
-			log.Println("Initializing API on " + a.Host + ":" + a.Port + "...")
+			a.logger().Info("Initializing API", "host", a.Host, "port", a.Port)
 			http.Handle("/", a)
 
 			go func() {
 				if a.Cert != "" && a.Key != "" {
 					http.ListenAndServeTLS(a.Host+":"+a.Port, a.Cert, a.Key, nil)
 				} else {
-					log.Println("WARNING: API using insecure connection. " +
+					a.logger().Warn("API using insecure connection. " +
 						"We recommend using an SSL certificate with Gobot.")
 					http.ListenAndServe(a.Host+":"+a.Port, nil)
 				}
@@ -49,17 +59,31 @@ func NewAPI(m *gobot.Master) *API {
 	}
 }
 
-// ServeHTTP calls api handlers and then serves request using api router
+// ServeHTTP calls api handlers and then serves request using api router, in
+// the version of the API requested
 func (a *API) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	version, req, ok := negotiate(req)
+	if !ok {
+		http.Error(res, "Not Acceptable", http.StatusNotAcceptable)
+		return
+	}
+	if version != V1 {
+		res = &versionedWriter{ResponseWriter: res, version: version}
+	}
+
 	for _, handler := range a.handlers {
 		rec := httptest.NewRecorder()
 		handler(rec, req)
 		for k, v := range rec.Header() {
 			res.Header()[k] = v
 		}
-		if rec.Code == http.StatusUnauthorized {
+		switch rec.Code {
+		case http.StatusUnauthorized:
 			http.Error(res, "Not Authorized", http.StatusUnauthorized)
 			return
+		case http.StatusForbidden:
+			http.Error(res, "Forbidden", http.StatusForbidden)
+			return
 		}
 	}
 	a.router.ServeHTTP(res, req)
@@ -102,6 +126,12 @@ func (a *API) AddHandler(f func(http.ResponseWriter, *http.Request)) {
 
 // Start initializes the api by setting up c3pio routes and robeaux
 func (a *API) Start() {
+	a.routes()
+	a.start(a)
+}
+
+// routes sets up the c3pio routes and robeaux
+func (a *API) routes() {
 	mcpCommandRoute := "/api/commands/:command"
 	robotDeviceCommandRoute := "/api/robots/:robot/devices/:device/commands/:command"
 	robotCommandRoute := "/api/robots/:robot/commands/:command"
@@ -112,6 +142,14 @@ func (a *API) Start() {
 	a.Get("/api/robots", a.robots)
 	a.Get("/api/robots/:robot", a.robot)
 	a.Get("/api/robots/:robot/commands", a.robotCommands)
+	a.Get("/api/robots/:robot/telemetry", a.robotTelemetry)
+	a.Get("/api/robots/:robot/events", a.robotEvents)
+	a.Post("/api/robots/:robot/estop", a.robotEmergencyStop)
+	a.Get("/api/robots/:robot/tasks", a.robotTasks)
+	a.Get("/api/robots/:robot/tasks/:task", a.robotTask)
+	a.Post("/api/robots/:robot/tasks/:task/pause", a.robotTaskControl((*gobot.Robot).PauseTask))
+	a.Post("/api/robots/:robot/tasks/:task/resume", a.robotTaskControl((*gobot.Robot).ResumeTask))
+	a.Post("/api/robots/:robot/tasks/:task/stop", a.robotTaskControl((*gobot.Robot).StopTask))
 	a.Get(robotCommandRoute, a.executeRobotCommand)
 	a.Post(robotCommandRoute, a.executeRobotCommand)
 	a.Get("/api/robots/:robot/devices", a.robotDevices)
@@ -122,6 +160,13 @@ func (a *API) Start() {
 	a.Post(robotDeviceCommandRoute, a.executeRobotDeviceCommand)
 	a.Get("/api/robots/:robot/connections", a.robotConnections)
 	a.Get("/api/robots/:robot/connections/:connection", a.robotConnection)
+	a.Get("/api/jobs", a.listJobs)
+	a.Get("/api/jobs/:job", a.job)
+	a.Delete("/api/jobs/:job", a.cancelJob)
+	a.Get("/api/peers", a.listPeers)
+	a.Post("/api/peers", a.registerPeer)
+	a.Delete("/api/peers/:peer", a.unregisterPeer)
+	a.Get("/api/masters", a.masters)
 	a.Get("/api/", a.mcp)
 
 	a.Get("/", func(res http.ResponseWriter, req *http.Request) {
@@ -136,8 +181,6 @@ func (a *API) Start() {
 	a.Get("/css/:a/", a.robeaux)
 	a.Get("/css/:a/:b", a.robeaux)
 	a.Get("/partials/:a", a.robeaux)
-
-	a.start(a)
 }
 
 // robeaux returns handler for robeaux routes.
@@ -173,11 +216,19 @@ func (a *API) mcpCommands(res http.ResponseWriter, req *http.Request) {
 }
 
 // robots returns route handler.
-// Writes JSON with robots representation
+// Writes JSON with robots representation, limited to the robots whose
+// metadata matches the label selectors when given
 func (a *API) robots(res http.ResponseWriter, req *http.Request) {
+	labels := req.URL.Query()["label"]
+	if _, err := gobot.MatchLabels(nil, labels...); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+		return
+	}
 	jsonRobots := []*gobot.JSONRobot{}
 	a.master.Robots().Each(func(r *gobot.Robot) {
-		jsonRobots = append(jsonRobots, gobot.NewJSONRobot(r))
+		if match, _ := gobot.MatchLabels(r.Metadata, labels...); match {
+			jsonRobots = append(jsonRobots, gobot.NewJSONRobot(r))
+		}
 	})
 	a.writeJSON(map[string]interface{}{"robots": jsonRobots}, res)
 }
@@ -202,20 +253,120 @@ func (a *API) robotCommands(res http.ResponseWriter, req *http.Request) {
 	}
 }
 
-// robotDevices returns devices route handler.
-// Writes JSON with robot devices representation
-func (a *API) robotDevices(res http.ResponseWriter, req *http.Request) {
+// robotTelemetry returns telemetry route handler.
+// Writes JSON with a snapshot of the robot telemetry
+func (a *API) robotTelemetry(res http.ResponseWriter, req *http.Request) {
 	if robot := a.master.Robot(req.URL.Query().Get(":robot")); robot != nil {
-		jsonDevices := []*gobot.JSONDevice{}
-		robot.Devices().Each(func(d gobot.Device) {
-			jsonDevices = append(jsonDevices, gobot.NewJSONDevice(d))
-		})
-		a.writeJSON(map[string]interface{}{"devices": jsonDevices}, res)
+		a.writeJSON(map[string]interface{}{"telemetry": robot.Telemetry()}, res)
 	} else {
 		a.writeJSON(map[string]interface{}{"error": "No Robot found with the name " + req.URL.Query().Get(":robot")}, res)
 	}
 }
 
+// robotEvents returns event history route handler.
+// Writes JSON with the events recorded after the since sequence number,
+// whose name matches the name pattern when given
+func (a *API) robotEvents(res http.ResponseWriter, req *http.Request) {
+	robot := a.master.Robot(req.URL.Query().Get(":robot"))
+	if robot == nil {
+		a.writeJSON(map[string]interface{}{"error": "No Robot found with the name " + req.URL.Query().Get(":robot")}, res)
+		return
+	}
+	var since uint64
+	if s := req.URL.Query().Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			a.writeJSON(map[string]interface{}{"error": "Invalid since sequence number " + s}, res)
+			return
+		}
+	}
+	a.writeJSON(map[string]interface{}{"events": robot.EventHistory(since, req.URL.Query().Get("name"))}, res)
+}
+
+// robotEmergencyStop returns the emergency stop route handler.
+// Puts the devices of the robot in a safe state and stops it, writing JSON
+// with the error of the safe states, if any
+func (a *API) robotEmergencyStop(res http.ResponseWriter, req *http.Request) {
+	robot := a.master.Robot(req.URL.Query().Get(":robot"))
+	if robot == nil {
+		a.writeJSON(map[string]interface{}{"error": "No Robot found with the name " + req.URL.Query().Get(":robot")}, res)
+		return
+	}
+	if err := robot.EmergencyStop(); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+		return
+	}
+	a.writeJSON(map[string]interface{}{"result": "stopped"}, res)
+}
+
+// robotTasks returns tasks route handler.
+// Writes JSON with the tasks of the robot and their state
+func (a *API) robotTasks(res http.ResponseWriter, req *http.Request) {
+	robot := a.master.Robot(req.URL.Query().Get(":robot"))
+	if robot == nil {
+		a.writeJSON(map[string]interface{}{"error": "No Robot found with the name " + req.URL.Query().Get(":robot")}, res)
+		return
+	}
+	jsonTasks := []*gobot.JSONTask{}
+	for _, t := range robot.Tasks() {
+		jsonTasks = append(jsonTasks, gobot.NewJSONTask(t))
+	}
+	a.writeJSON(map[string]interface{}{"tasks": jsonTasks}, res)
+}
+
+// robotTask returns task route handler.
+// Writes JSON with the task and its state
+func (a *API) robotTask(res http.ResponseWriter, req *http.Request) {
+	if task, err := a.jsonTaskFor(req.URL.Query().Get(":robot"), req.URL.Query().Get(":task")); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+	} else {
+		a.writeJSON(map[string]interface{}{"task": task}, res)
+	}
+}
+
+// robotTaskControl returns a route handler pausing, resuming or stopping a
+// task with control, then writing JSON with the task and its new state
+func (a *API) robotTaskControl(control func(*gobot.Robot, string) error) func(http.ResponseWriter, *http.Request) {
+	return func(res http.ResponseWriter, req *http.Request) {
+		robot := a.master.Robot(req.URL.Query().Get(":robot"))
+		if robot == nil {
+			a.writeJSON(map[string]interface{}{"error": "No Robot found with the name " + req.URL.Query().Get(":robot")}, res)
+			return
+		}
+		if err := control(robot, req.URL.Query().Get(":task")); err != nil {
+			a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+			return
+		}
+		a.robotTask(res, req)
+	}
+}
+
+// robotDevices returns devices route handler.
+// Writes JSON with robot devices representation, limited to the devices
+// whose metadata matches the label selectors when given
+func (a *API) robotDevices(res http.ResponseWriter, req *http.Request) {
+	robot := a.master.Robot(req.URL.Query().Get(":robot"))
+	if robot == nil {
+		a.writeJSON(map[string]interface{}{"error": "No Robot found with the name " + req.URL.Query().Get(":robot")}, res)
+		return
+	}
+	labels := req.URL.Query()["label"]
+	if _, err := gobot.MatchLabels(nil, labels...); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+		return
+	}
+	jsonDevices := []*gobot.JSONDevice{}
+	robot.Devices().Each(func(d gobot.Device) {
+		metadata := robot.DeviceMetadata(d.Name())
+		if match, _ := gobot.MatchLabels(metadata, labels...); match {
+			jsonDevice := gobot.NewJSONDevice(d)
+			jsonDevice.Metadata = metadata
+			jsonDevices = append(jsonDevices, jsonDevice)
+		}
+	})
+	a.writeJSON(map[string]interface{}{"devices": jsonDevices}, res)
+}
+
 // robotDevice returns device route handler.
 // Writes JSON with robot device representation
 func (a *API) robotDevice(res http.ResponseWriter, req *http.Request) {
@@ -248,13 +399,17 @@ func (a *API) robotDeviceEvent(res http.ResponseWriter, req *http.Request) {
 			dataChan <- string(d)
 		})
 
+		// send the headers at once so that clients know the stream is open
+		res.WriteHeader(http.StatusOK)
+		f.Flush()
+
 		for {
 			select {
 			case data := <-dataChan:
 				fmt.Fprintf(res, "data: %v\n\n", data)
 				f.Flush()
 			case <-closer:
-				log.Println("Closing connection")
+				a.logger().Info("Closing connection")
 				return
 			}
 		}
@@ -338,7 +493,8 @@ func (a *API) executeRobotCommand(res http.ResponseWriter, req *http.Request) {
 	}
 }
 
-// executeCommand writes JSON response with `f` returned value.
+// executeCommand writes JSON response with `f` returned value. With the
+// async query parameter `f` runs as a Job, and the response is the Job.
 func (a *API) executeCommand(f func(map[string]interface{}) interface{},
 	res http.ResponseWriter,
 	req *http.Request,
@@ -347,15 +503,31 @@ func (a *API) executeCommand(f func(map[string]interface{}) interface{},
 	body := make(map[string]interface{})
 	json.NewDecoder(req.Body).Decode(&body)
 
-	if f != nil {
-		a.writeJSON(map[string]interface{}{"result": f(body)}, res)
+	query := req.URL.Query()
+	ctx, span := gobot.StartSpan(traceContext(req), CommandSpan, commandAttributes(query)...)
+	defer span.End()
+	if async, _ := strconv.ParseBool(query.Get("async")); async && f != nil {
+		span.SetAttributes(gobot.Attr("gobot.async", true))
+		job := a.startJob(ctx, query.Get(":robot"), query.Get(":device"), query.Get(":command"), f, body)
+		a.writeJSON(map[string]interface{}{"job": job}, res)
+	} else if f != nil {
+		result := f(body)
+		if err, ok := result.(error); ok {
+			span.RecordError(err)
+		}
+		a.writeJSON(map[string]interface{}{"result": result}, res)
 	} else {
+		span.RecordError(errors.New("Unknown Command"))
 		a.writeJSON(map[string]interface{}{"error": "Unknown Command"}, res)
 	}
 }
 
 // writeJSON writes `j` as JSON in response
 func (a *API) writeJSON(j interface{}, res http.ResponseWriter) {
+	if w, ok := res.(*versionedWriter); ok {
+		a.writeVersionedJSON(j, w)
+		return
+	}
 	data, _ := json.Marshal(j)
 	res.Header().Set("Content-Type", "application/json; charset=utf-8")
 	res.Write(data)
@@ -364,10 +536,15 @@ func (a *API) writeJSON(j interface{}, res http.ResponseWriter) {
 // Debug add handler to api that prints each request
 func (a *API) Debug() {
 	a.AddHandler(func(res http.ResponseWriter, req *http.Request) {
-		log.Println(req)
+		a.logger().Info("Request", "method", req.Method, "url", req.URL, "remote", req.RemoteAddr)
 	})
 }
 
+// logger returns the Master's Logger scoped to the api component
+func (a *API) logger() gobot.Logger {
+	return a.master.Logger().WithComponent("api")
+}
+
 func (a *API) jsonRobotFor(name string) (jrobot *gobot.JSONRobot, err error) {
 	if robot := a.master.Robot(name); robot != nil {
 		jrobot = gobot.NewJSONRobot(robot)
@@ -380,12 +557,26 @@ func (a *API) jsonRobotFor(name string) (jrobot *gobot.JSONRobot, err error) {
 func (a *API) jsonDeviceFor(robot string, name string) (jdevice *gobot.JSONDevice, err error) {
 	if device := a.master.Robot(robot).Device(name); device != nil {
 		jdevice = gobot.NewJSONDevice(device)
+		jdevice.Metadata = a.master.Robot(robot).DeviceMetadata(name)
 	} else {
 		err = errors.New("No Device found with the name " + name)
 	}
 	return
 }
 
+func (a *API) jsonTaskFor(robot string, name string) (jtask *gobot.JSONTask, err error) {
+	r := a.master.Robot(robot)
+	if r == nil {
+		return nil, errors.New("No Robot found with the name " + robot)
+	}
+	if task := r.Task(name); task != nil {
+		jtask = gobot.NewJSONTask(task)
+	} else {
+		err = errors.New("No Task found with the name " + name)
+	}
+	return
+}
+
 func (a *API) jsonConnectionFor(robot string, name string) (jconnection *gobot.JSONConnection, err error) {
 	if connection := a.master.Robot(robot).Connection(name); connection != nil {
 		jconnection = gobot.NewJSONConnection(connection)
@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -132,6 +134,36 @@ func TestRobots(t *testing.T) {
 	gobottest.Assert(t, len(body["robots"].([]interface{})), 3)
 }
 
+func TestRobotsLabels(t *testing.T) {
+	a := initTestAPI()
+	a.master.Robot("Robot1").Metadata = map[string]string{"zone": "garage"}
+	a.master.Robot("Robot2").Metadata = map[string]string{"zone": "kitchen"}
+
+	var body map[string]interface{}
+	request, _ := http.NewRequest("GET", "/api/robots?label=zone=garage", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	robots := body["robots"].([]interface{})
+	gobottest.Assert(t, len(robots), 1)
+	gobottest.Assert(t, robots[0].(map[string]interface{})["name"], "Robot1")
+	gobottest.Assert(t, robots[0].(map[string]interface{})["metadata"], map[string]interface{}{"zone": "garage"})
+
+	request, _ = http.NewRequest("GET", "/api/robots?label=zone&label=zone!=garage", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	robots = body["robots"].([]interface{})
+	gobottest.Assert(t, len(robots), 1)
+	gobottest.Assert(t, robots[0].(map[string]interface{})["name"], "Robot2")
+
+	request, _ = http.NewRequest("GET", "/api/robots?label=!=garage", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], `invalid label selector "!=garage"`)
+}
+
 func TestRobot(t *testing.T) {
 	a := initTestAPI()
 
@@ -172,6 +204,215 @@ func TestRobotDevices(t *testing.T) {
 	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
 }
 
+func TestRobotDevicesLabels(t *testing.T) {
+	a := initTestAPI()
+	a.master.Robot("Robot1").SetDeviceMetadata("Device2", map[string]string{"firmware": "1.2"})
+
+	var body map[string]interface{}
+	request, _ := http.NewRequest("GET", "/api/robots/Robot1/devices?label=firmware=1.2", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	devices := body["devices"].([]interface{})
+	gobottest.Assert(t, len(devices), 1)
+	gobottest.Assert(t, devices[0].(map[string]interface{})["name"], "Device2")
+	gobottest.Assert(t, devices[0].(map[string]interface{})["metadata"], map[string]interface{}{"firmware": "1.2"})
+
+	request, _ = http.NewRequest("GET", "/api/robots/Robot1/devices/Device2", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["device"].(map[string]interface{})["metadata"], map[string]interface{}{"firmware": "1.2"})
+
+	request, _ = http.NewRequest("GET", "/api/robots/Robot1/devices?label==1.2", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], `invalid label selector "=1.2"`)
+}
+
+func TestRobotTelemetry(t *testing.T) {
+	a := initTestAPI()
+
+	// known robot
+	request, _ := http.NewRequest("GET", "/api/robots/Robot1/telemetry", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	telemetry := body["telemetry"].(map[string]interface{})
+	gobottest.Assert(t, telemetry["name"], "Robot1")
+	gobottest.Assert(t, len(telemetry["devices"].([]interface{})), 3)
+
+	// unknown robot
+	request, _ = http.NewRequest("GET", "/api/robots/UnknownRobot1/telemetry", nil)
+	a.ServeHTTP(response, request)
+
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
+}
+
+func TestRobotEvents(t *testing.T) {
+	log.SetOutput(NullReadWriteCloser{})
+	g := gobot.NewMaster()
+	a := NewAPI(g)
+	a.start = func(m *API) {}
+	a.Start()
+
+	r := newTestRobot("Robot1")
+	r.EventHistorySize = 10
+	g.AddRobot(r)
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	device := r.Device("Device1").(gobot.Eventer)
+	device.Publish("TestEvent", "a")
+	device.Publish("OtherEvent", "b")
+	device.Publish("TestEvent", "c")
+	deadline := time.Now().Add(time.Second)
+	for len(r.EventHistory(0, "")) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	request, _ := http.NewRequest("GET", "/api/robots/Robot1/events?since=1&name=Test*", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	events := body["events"].([]interface{})
+	gobottest.Assert(t, len(events), 1)
+	event := events[0].(map[string]interface{})
+	gobottest.Assert(t, event["seq"], 3.0)
+	gobottest.Assert(t, event["device"], "Device1")
+	gobottest.Assert(t, event["name"], "TestEvent")
+	gobottest.Assert(t, event["data"], "c")
+
+	// invalid sequence number
+	request, _ = http.NewRequest("GET", "/api/robots/Robot1/events?since=last", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	body = map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], "Invalid since sequence number last")
+
+	// unknown robot
+	request, _ = http.NewRequest("GET", "/api/robots/UnknownRobot1/events", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	body = map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
+}
+
+// safeStateTestDriver fails to reach a safe state
+type safeStateTestDriver struct {
+	*testDriver
+}
+
+func (s *safeStateTestDriver) SafeState() error { return errors.New("motor stuck") }
+
+func TestRobotEmergencyStop(t *testing.T) {
+	log.SetOutput(NullReadWriteCloser{})
+	g := gobot.NewMaster()
+	a := NewAPI(g)
+	a.start = func(m *API) {}
+	a.Start()
+
+	r := newTestRobot("Robot1")
+	g.AddRobot(r)
+	gobottest.Assert(t, r.Start(false), nil)
+
+	request, _ := http.NewRequest("POST", "/api/robots/Robot1/estop", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["result"], "stopped")
+	gobottest.Assert(t, r.Running(), false)
+
+	// failing safe state
+	r.AddDevice(&safeStateTestDriver{newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "Motor", "3")})
+	request, _ = http.NewRequest("POST", "/api/robots/Robot1/estop", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	body = map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, strings.Contains(body["error"].(string), "safe state Motor: motor stuck"), true)
+
+	// unknown robot
+	request, _ = http.NewRequest("POST", "/api/robots/UnknownRobot1/estop", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	body = map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
+}
+
+func TestRobotTasks(t *testing.T) {
+	log.SetOutput(NullReadWriteCloser{})
+	g := gobot.NewMaster()
+	a := NewAPI(g)
+	a.start = func(m *API) {}
+	a.Start()
+
+	r := newTestRobot("Robot1")
+	r.AddTask("patrol", func(t *gobot.Task) {
+		for t.Wait() {
+			<-time.After(time.Millisecond)
+		}
+	})
+	g.AddRobot(r)
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	request, _ := http.NewRequest("GET", "/api/robots/Robot1/tasks", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	var tasks map[string][]*gobot.JSONTask
+	json.NewDecoder(response.Body).Decode(&tasks)
+	gobottest.Assert(t, tasks["tasks"], []*gobot.JSONTask{{Name: "patrol", State: gobot.TaskRunning}})
+
+	control := func(method string, path string) map[string]interface{} {
+		request, _ := http.NewRequest(method, path, nil)
+		response := httptest.NewRecorder()
+		a.ServeHTTP(response, request)
+		var body map[string]interface{}
+		json.NewDecoder(response.Body).Decode(&body)
+		return body
+	}
+
+	body := control("POST", "/api/robots/Robot1/tasks/patrol/pause")
+	gobottest.Assert(t, body["task"], map[string]interface{}{"name": "patrol", "state": "paused"})
+	gobottest.Assert(t, r.Task("patrol").State(), gobot.TaskPaused)
+
+	body = control("POST", "/api/robots/Robot1/tasks/patrol/resume")
+	gobottest.Assert(t, body["task"], map[string]interface{}{"name": "patrol", "state": "running"})
+
+	body = control("POST", "/api/robots/Robot1/tasks/patrol/stop")
+	gobottest.Assert(t, body["task"], map[string]interface{}{"name": "patrol", "state": "stopped"})
+	gobottest.Assert(t, r.Running(), true)
+
+	body = control("POST", "/api/robots/Robot1/tasks/patrol/resume")
+	gobottest.Assert(t, body["error"], "Task patrol is stopped")
+
+	body = control("GET", "/api/robots/Robot1/tasks/patrol")
+	gobottest.Assert(t, body["task"], map[string]interface{}{"name": "patrol", "state": "stopped"})
+
+	// unknown task
+	body = control("POST", "/api/robots/Robot1/tasks/unknown/pause")
+	gobottest.Assert(t, body["error"], "No Task found with the name unknown")
+	body = control("GET", "/api/robots/Robot1/tasks/unknown")
+	gobottest.Assert(t, body["error"], "No Task found with the name unknown")
+
+	// unknown robot
+	body = control("GET", "/api/robots/UnknownRobot1/tasks")
+	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
+	body = control("POST", "/api/robots/UnknownRobot1/tasks/patrol/pause")
+	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
+}
+
 func TestRobotCommands(t *testing.T) {
 	a := initTestAPI()
 
@@ -0,0 +1,397 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// Client talks to a remote Gobot API, such as the API of a Master running on
+// another board. A Client is also a gobot.Connection, so that the robots
+// returned by Proxy can be added to a local Master.
+type Client struct {
+	name string
+	// URL of the remote API, for example "http://192.168.1.20:3000"
+	URL string
+	// Username and Password are used for basic authentication when set
+	Username string
+	Password string
+	// Token is sent as a bearer token when set, see RBAC
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a new Client for the API at url
+func NewClient(url string) *Client {
+	return &Client{
+		name:       gobot.DefaultName("Client"),
+		URL:        strings.TrimRight(url, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Name returns the Client name
+func (c *Client) Name() string { return c.name }
+
+// SetName sets the Client name
+func (c *Client) SetName(n string) { c.name = n }
+
+// Connect checks that the remote API is reachable
+func (c *Client) Connect() error {
+	var master interface{}
+	return c.get("/api/", "MCP", &master)
+}
+
+// Finalize does nothing, a Client holds no connection between requests
+func (c *Client) Finalize() error { return nil }
+
+// Robots returns the robots of the remote Master, limited to the robots
+// whose metadata matches the label selectors when given, see
+// gobot.MatchLabels
+func (c *Client) Robots(labels ...string) (robots []*gobot.JSONRobot, err error) {
+	path := "/api/robots"
+	if len(labels) > 0 {
+		path += "?" + url.Values{"label": labels}.Encode()
+	}
+	err = c.get(path, "robots", &robots)
+	return
+}
+
+// Robot returns the remote robot named name
+func (c *Client) Robot(name string) (robot *gobot.JSONRobot, err error) {
+	err = c.get("/api/robots/"+url.PathEscape(name), "robot", &robot)
+	return
+}
+
+// Command runs the command of the remote robot and returns its result
+func (c *Client) Command(robot string, command string, params map[string]interface{}) (result interface{}, err error) {
+	err = c.post("/api/robots/"+url.PathEscape(robot)+"/commands/"+url.PathEscape(command),
+		params, "result", &result)
+	return
+}
+
+// DeviceCommand runs the command of a device of the remote robot and returns
+// its result
+func (c *Client) DeviceCommand(robot string, device string, command string, params map[string]interface{}) (result interface{}, err error) {
+	err = c.post("/api/robots/"+url.PathEscape(robot)+"/devices/"+url.PathEscape(device)+
+		"/commands/"+url.PathEscape(command), params, "result", &result)
+	return
+}
+
+// CommandAsync starts the command of the remote robot as a Job and returns
+// it at once, see Job to follow it
+func (c *Client) CommandAsync(robot string, command string, params map[string]interface{}) (job *Job, err error) {
+	err = c.post("/api/robots/"+url.PathEscape(robot)+"/commands/"+url.PathEscape(command)+"?async=true",
+		params, "job", &job)
+	return
+}
+
+// DeviceCommandAsync starts the command of a device of the remote robot as a
+// Job and returns it at once
+func (c *Client) DeviceCommandAsync(robot string, device string, command string, params map[string]interface{}) (job *Job, err error) {
+	err = c.post("/api/robots/"+url.PathEscape(robot)+"/devices/"+url.PathEscape(device)+
+		"/commands/"+url.PathEscape(command)+"?async=true", params, "job", &job)
+	return
+}
+
+// Job returns the Job of the remote API with the id, its result set once it
+// is done
+func (c *Client) Job(id string) (job *Job, err error) {
+	err = c.get("/api/jobs/"+url.PathEscape(id), "job", &job)
+	return
+}
+
+// CancelJob cancels the running Job of the remote API with the id
+func (c *Client) CancelJob(id string) (job *Job, err error) {
+	resp, err := c.do("DELETE", "/api/jobs/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	err = decodeResponse(resp.Body, "job", &job)
+	return
+}
+
+// Tasks returns the tasks of the remote robot and their state
+func (c *Client) Tasks(robot string) (tasks []*gobot.JSONTask, err error) {
+	err = c.get("/api/robots/"+url.PathEscape(robot)+"/tasks", "tasks", &tasks)
+	return
+}
+
+// PauseTask pauses the task of the remote robot
+func (c *Client) PauseTask(robot string, task string) (*gobot.JSONTask, error) {
+	return c.controlTask(robot, task, "pause")
+}
+
+// ResumeTask resumes the paused task of the remote robot
+func (c *Client) ResumeTask(robot string, task string) (*gobot.JSONTask, error) {
+	return c.controlTask(robot, task, "resume")
+}
+
+// StopTask stops the task of the remote robot
+func (c *Client) StopTask(robot string, task string) (*gobot.JSONTask, error) {
+	return c.controlTask(robot, task, "stop")
+}
+
+func (c *Client) controlTask(robot string, task string, action string) (jtask *gobot.JSONTask, err error) {
+	err = c.post("/api/robots/"+url.PathEscape(robot)+"/tasks/"+url.PathEscape(task)+"/"+action,
+		nil, "task", &jtask)
+	return
+}
+
+// Subscription is a stream of events of a remote device
+type Subscription struct {
+	// C receives the data of the events
+	C    <-chan interface{}
+	body io.Closer
+	once sync.Once
+}
+
+// Close ends the Subscription
+func (s *Subscription) Close() error {
+	var err error
+	s.once.Do(func() { err = s.body.Close() })
+	return err
+}
+
+// Subscribe streams the event of a device of the remote robot
+func (c *Client) Subscribe(robot string, device string, event string) (*Subscription, error) {
+	resp, err := c.do("GET", "/api/robots/"+url.PathEscape(robot)+"/devices/"+
+		url.PathEscape(device)+"/events/"+url.PathEscape(event), nil)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		defer resp.Body.Close()
+		if err := decodeResponse(resp.Body, "", nil); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("No event stream for " + event)
+	}
+
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var data interface{}
+			if json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data) == nil {
+				out <- data
+			}
+		}
+	}()
+	return &Subscription{C: out, body: resp.Body}, nil
+}
+
+// Peers returns the peers registered with the remote API
+func (c *Client) Peers() (peers []Peer, err error) {
+	err = c.get("/api/peers", "peers", &peers)
+	return
+}
+
+// Register registers p with the remote API, so that a coordinating Master
+// can find it
+func (c *Client) Register(p Peer) error {
+	return c.post("/api/peers", p, "peer", nil)
+}
+
+// Unregister removes the peer named name from the remote API
+func (c *Client) Unregister(name string) error {
+	resp, err := c.do("DELETE", "/api/peers/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeResponse(resp.Body, "peer", nil)
+}
+
+// Proxy returns a local Robot standing in for the remote robot named name.
+// Its commands and the commands of its devices run on the remote robot, and
+// the devices can watch remote events. The Robot can be added to a local
+// Master like any other.
+func (c *Client) Proxy(name string) (*gobot.Robot, error) {
+	remote, err := c.Robot(name)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := []gobot.Device{}
+	for _, d := range remote.Devices {
+		devices = append(devices, newRemoteDevice(c, name, d))
+	}
+	r := gobot.NewRobot(remote.Name, []gobot.Connection{c}, devices)
+	for _, command := range remote.Commands {
+		command := command
+		r.AddCommand(command, func(params map[string]interface{}) interface{} {
+			result, err := c.Command(name, command, params)
+			if err != nil {
+				return err.Error()
+			}
+			return result
+		})
+	}
+	return r, nil
+}
+
+// RemoteDevice stands in for a device of a remote robot
+type RemoteDevice struct {
+	name   string
+	remote string
+	robot  string
+	client *Client
+	subs   map[string]*Subscription
+	mutex  sync.Mutex
+	gobot.Commander
+	gobot.Eventer
+}
+
+func newRemoteDevice(c *Client, robot string, d *gobot.JSONDevice) *RemoteDevice {
+	device := &RemoteDevice{
+		name:      d.Name,
+		remote:    d.Name,
+		robot:     robot,
+		client:    c,
+		subs:      make(map[string]*Subscription),
+		Commander: gobot.NewCommander(),
+		Eventer:   gobot.NewEventer(),
+	}
+	for _, command := range d.Commands {
+		command := command
+		device.AddCommand(command, func(params map[string]interface{}) interface{} {
+			result, err := c.DeviceCommand(robot, d.Name, command, params)
+			if err != nil {
+				return err.Error()
+			}
+			return result
+		})
+	}
+	return device
+}
+
+// Name returns the RemoteDevice name
+func (d *RemoteDevice) Name() string { return d.name }
+
+// SetName sets the RemoteDevice name
+func (d *RemoteDevice) SetName(n string) { d.name = n }
+
+// Connection returns the Client of the RemoteDevice
+func (d *RemoteDevice) Connection() gobot.Connection { return d.client }
+
+// Start does nothing, the remote device is started by its own robot
+func (d *RemoteDevice) Start() error { return nil }
+
+// Halt stops watching the remote events
+func (d *RemoteDevice) Halt() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for event, sub := range d.subs {
+		sub.Close()
+		delete(d.subs, event)
+	}
+	return nil
+}
+
+// Watch publishes the event of the remote device on the RemoteDevice, until
+// it is halted.
+func (d *RemoteDevice) Watch(event string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if _, ok := d.subs[event]; ok {
+		return nil
+	}
+	sub, err := d.client.Subscribe(d.robot, d.remote, event)
+	if err != nil {
+		return err
+	}
+	d.subs[event] = sub
+	d.AddEvent(event)
+	go func() {
+		for data := range sub.C {
+			d.Publish(event, data)
+		}
+	}()
+	return nil
+}
+
+func (c *Client) do(method string, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.URL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	} else if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.New(c.URL + path + ": " + resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *Client) get(path string, key string, v interface{}) error {
+	resp, err := c.do("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeResponse(resp.Body, key, v)
+}
+
+func (c *Client) post(path string, body interface{}, key string, v interface{}) error {
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	resp, err := c.do("POST", path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeResponse(resp.Body, key, v)
+}
+
+// decodeResponse decodes the value of key in an API response into v, or
+// returns the error reported by the API.
+func decodeResponse(r io.Reader, key string, v interface{}) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r).Decode(&fields); err != nil {
+		return err
+	}
+	if raw, ok := fields["error"]; ok {
+		var message string
+		json.Unmarshal(raw, &message)
+		return errors.New(message)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(fields[key], v)
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func initTestClient() (*API, *httptest.Server, *Client) {
+	a := initTestAPI()
+	server := httptest.NewServer(a)
+	return a, server, NewClient(server.URL)
+}
+
+func TestClientConnect(t *testing.T) {
+	_, server, c := initTestClient()
+	gobottest.Assert(t, c.Connect(), nil)
+	gobottest.Assert(t, c.Finalize(), nil)
+	server.Close()
+	gobottest.Refute(t, c.Connect(), nil)
+}
+
+func TestClientRobots(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+
+	robots, err := c.Robots()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(robots), 3)
+	gobottest.Assert(t, robots[0].Name, "Robot1")
+
+	a.master.Robot("Robot3").Metadata = map[string]string{"owner": "ada"}
+	robots, err = c.Robots("owner=ada")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(robots), 1)
+	gobottest.Assert(t, robots[0].Metadata, map[string]string{"owner": "ada"})
+
+	robot, err := c.Robot("Robot2")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(robot.Devices), 3)
+
+	_, err = c.Robot("UnknownRobot")
+	gobottest.Assert(t, err.Error(), "No Robot found with the name UnknownRobot")
+}
+
+func TestClientCommands(t *testing.T) {
+	_, server, c := initTestClient()
+	defer server.Close()
+
+	result, err := c.Command("Robot1", "robotTestFunction",
+		map[string]interface{}{"message": "Beep Boop", "robot": "Robot1"})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, result, "hey Robot1, Beep Boop")
+
+	result, err = c.DeviceCommand("Robot1", "Device1", "TestDriverCommand",
+		map[string]interface{}{"name": "human"})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, result, "hello human")
+
+	_, err = c.Command("Robot1", "robotTestFuntion1", nil)
+	gobottest.Assert(t, err.Error(), "Unknown Command")
+}
+
+func TestClientJobs(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+
+	a.master.Robot("Robot1").AddCommand("calibrate", func(params map[string]interface{}) interface{} {
+		<-Canceled(params)
+		return nil
+	})
+	job, err := c.CommandAsync("Robot1", "calibrate", nil)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, job.State, JobRunning)
+
+	job, err = c.Job(job.ID)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, job.Command, "calibrate")
+
+	job, err = c.CancelJob(job.ID)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, job.State, JobCanceled)
+
+	job, err = c.DeviceCommandAsync("Robot1", "Device1", "TestDriverCommand",
+		map[string]interface{}{"name": "human"})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, waitJob(t, a, job.ID).Result, "hello human")
+
+	_, err = c.Job("42")
+	gobottest.Assert(t, err.Error(), "No Job found with the id 42")
+}
+
+func TestClientTasks(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+
+	r := a.master.Robot("Robot1")
+	r.AddTask("patrol", func(t *gobot.Task) { <-t.Halt() })
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	tasks, err := c.Tasks("Robot1")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, tasks, []*gobot.JSONTask{{Name: "patrol", State: gobot.TaskRunning}})
+
+	task, err := c.PauseTask("Robot1", "patrol")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, task.State, gobot.TaskPaused)
+
+	task, err = c.ResumeTask("Robot1", "patrol")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, task.State, gobot.TaskRunning)
+
+	task, err = c.StopTask("Robot1", "patrol")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, task.State, gobot.TaskStopped)
+
+	_, err = c.PauseTask("Robot1", "unknown")
+	gobottest.Assert(t, err.Error(), "No Task found with the name unknown")
+}
+
+func TestClientBasicAuth(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+	a.AddHandler(BasicAuth("admin", "password"))
+
+	_, err := c.Robots()
+	gobottest.Refute(t, err, nil)
+
+	c.Username = "admin"
+	c.Password = "password"
+	_, err = c.Robots()
+	gobottest.Assert(t, err, nil)
+}
+
+func TestClientSubscribe(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+
+	sub, err := c.Subscribe("Robot1", "Device1", "TestEvent")
+	gobottest.Assert(t, err, nil)
+	defer sub.Close()
+
+	a.master.Robot("Robot1").Device("Device1").(gobot.Eventer).Publish("TestEvent", "event-data")
+
+	select {
+	case data := <-sub.C:
+		gobottest.Assert(t, data, "event-data")
+	case <-time.After(time.Second):
+		t.Error("Not receiving data")
+	}
+
+	_, err = c.Subscribe("Robot1", "Device1", "UnknownEvent")
+	gobottest.Assert(t, err.Error(), "No Event found with the name UnknownEvent")
+}
+
+func TestClientRegister(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+
+	gobottest.Assert(t, c.Register(Peer{Name: "pi1", URL: "http://pi1:3000"}), nil)
+	gobottest.Assert(t, a.Peers(), []Peer{{Name: "pi1", URL: "http://pi1:3000"}})
+
+	peers, err := c.Peers()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, peers, a.Peers())
+
+	gobottest.Assert(t, c.Register(Peer{Name: "pi2"}).Error(), "Peer name and url are required")
+
+	gobottest.Assert(t, c.Unregister("pi1"), nil)
+	gobottest.Assert(t, len(a.Peers()), 0)
+	gobottest.Refute(t, c.Unregister("pi1"), nil)
+}
+
+func TestClientProxy(t *testing.T) {
+	a, server, c := initTestClient()
+	defer server.Close()
+
+	r, err := c.Proxy("Robot1")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r.Name, "Robot1")
+	gobottest.Assert(t, r.Devices().Len(), 3)
+
+	result := r.Command("robotTestFunction")(map[string]interface{}{"message": "hi", "robot": "Robot1"})
+	gobottest.Assert(t, result, "hey Robot1, hi")
+
+	device := r.Device("Device1").(*RemoteDevice)
+	gobottest.Assert(t, device.Connection().(*Client), c)
+	result = device.Command("DriverCommand")(map[string]interface{}{"name": "proxy"})
+	gobottest.Assert(t, result, "hello proxy")
+
+	events := make(chan interface{}, 1)
+	device.On("TestEvent", func(data interface{}) { events <- data })
+	gobottest.Assert(t, device.Watch("TestEvent"), nil)
+
+	a.master.Robot("Robot1").Device("Device1").(gobot.Eventer).Publish("TestEvent", "remote-data")
+	select {
+	case data := <-events:
+		gobottest.Assert(t, data, "remote-data")
+	case <-time.After(time.Second):
+		t.Error("Not receiving remote event")
+	}
+	gobottest.Assert(t, device.Halt(), nil)
+
+	_, err = c.Proxy("UnknownRobot")
+	gobottest.Assert(t, err.Error(), "No Robot found with the name UnknownRobot")
+}
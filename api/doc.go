@@ -33,6 +33,76 @@ Example:
     	gbot.Start()
     }
 
+Masters on different machines can work together: a Raspberry Pi registers with
+the API of a coordinating Master, which uses a Client to run commands on the
+remote robots and to follow their events:
+
+    // on the robot
+    api.NewClient("http://brain:3000").Register(api.Peer{Name: "pi1", URL: "http://pi1:3000"})
+
+    // on the brain
+    client, _ := brainAPI.Peer("pi1")
+    rover, _ := client.Proxy("rover")
+    master.AddRobot(rover)
+
+One API can front the Masters of several processes, each one served under
+its own prefix, and list them all at /api/masters:
+
+    gateway := api.NewAPI(gobot.NewMaster())
+    gateway.Mount("lab", labMaster)                                 // /lab/api/robots
+    gateway.MountPeer(api.Peer{Name: "pi1", URL: "http://pi1:3000"}) // /pi1/api/robots
+    gateway.Start()
+
+The metadata of robots and devices, such as their location or firmware
+version, is part of their representation. The robots, devices and masters
+lists select them by label, e.g. GET /api/robots?label=zone=garage, with
+selectors of the form "key=value", "key!=value" or "key", all of which must
+match:
+
+    rover.Metadata = map[string]string{"zone": "garage"}
+    rover.SetDeviceMetadata("lidar", map[string]string{"firmware": "1.2"})
+    garage, _ := client.Robots("zone=garage")
+
+A long running command, such as a motion sequence or a calibration, runs as
+a job when requested with the async query parameter, e.g. POST
+/api/robots/rover/commands/calibrate?async=true. The request returns the job
+at once, whose state and result are polled at /api/jobs/:job, and which is
+canceled by a DELETE of the same path:
+
+    job, _ := client.CommandAsync("rover", "calibrate", nil)
+    job, _ = client.Job(job.ID)
+
+The tasks of a robot are listed at /api/robots/:robot/tasks with their state,
+and each one is paused, resumed or stopped while the robot keeps running by a
+POST to /api/robots/:robot/tasks/:task/pause, resume or stop:
+
+    rover.AddPeriodicTask("patrol", time.Second, patrol)
+    client.PauseTask("rover", "patrol")
+
+A Recorder keeps the events and telemetry of a robot in rotated JSON lines
+files, to analyze field deployments after the fact:
+
+    recorder := api.NewRecorder(rover, "/var/log/gobot")
+    recorder.MaxSize = 1024 * 1024
+    recorder.Start()
+    defer recorder.Stop()
+
+A shared dashboard exposes read-only views safely with an RBAC, giving the
+users and bearer tokens the viewer, operator or admin role, and limiting the
+commands each role runs by robot, device and command name:
+
+    rbac, _ := api.LoadRBAC("rbac.yaml")
+    rbac.SetPermissions(api.RoleOperator, api.Permission{Robot: "*", Device: "arm", Command: "*"})
+    server.AddHandler(rbac.Handler())
+
+The routes are versioned: /api/... and /api/v1/... keep the payloads of the
+existing clients and dashboards, while /api/v2/..., or an Accept header of
+"application/vnd.gobot.v2+json", reports the errors with their HTTP status and
+lists the commands as objects:
+
+    GET /api/v2/robots/rover/commands/unknown
+    404 {"error": {"status": 404, "message": "Unknown Command"}}
+
 It follows Common Protocol for Programming Physical Input and Output (CPPP-IO) spec:
 https://gobot.io/x/cppp-io
 */
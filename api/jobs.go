@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// JobState is the state of a Job
+type JobState string
+
+const (
+	// JobRunning is the state of a Job whose command is running
+	JobRunning JobState = "running"
+	// JobDone is the state of a Job whose command returned its result
+	JobDone JobState = "done"
+	// JobFailed is the state of a Job whose command panicked
+	JobFailed JobState = "failed"
+	// JobCanceled is the state of a canceled Job
+	JobCanceled JobState = "canceled"
+)
+
+// DefaultMaxJobs is the default number of finished Jobs an API keeps
+const DefaultMaxJobs = 100
+
+// jobParam is the param holding the Job of a command run asynchronously
+const jobParam = "_job"
+
+// Job is a command run asynchronously, requested with the async query
+// parameter, e.g. POST /api/robots/rover/commands/calibrate?async=true. The
+// request returns at once with the Job, whose state and result are polled at
+// /api/jobs/:job until it is finished.
+type Job struct {
+	ID       string      `json:"id"`
+	Robot    string      `json:"robot,omitempty"`
+	Device   string      `json:"device,omitempty"`
+	Command  string      `json:"command"`
+	State    JobState    `json:"state"`
+	Result   interface{} `json:"result,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Started  time.Time   `json:"started"`
+	Finished *time.Time  `json:"finished,omitempty"`
+	canceled chan bool
+}
+
+// Canceled returns a channel closed when the Job of the command called with
+// params is canceled, so that a long running command can stop early:
+//
+//	robot.AddCommand("sweep", func(params map[string]interface{}) interface{} {
+//		for angle := 0; angle <= 180; angle++ {
+//			select {
+//			case <-api.Canceled(params):
+//				return "canceled"
+//			case <-time.After(20 * time.Millisecond):
+//				servo.Move(uint8(angle))
+//			}
+//		}
+//		return "done"
+//	})
+//
+// The channel is nil, which never fires, when the command does not run as a
+// Job.
+func Canceled(params map[string]interface{}) <-chan bool {
+	if job, ok := params[jobParam].(*Job); ok {
+		return job.canceled
+	}
+	return nil
+}
+
+// Jobs returns the Jobs of the API, oldest first
+func (a *API) Jobs() []Job {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	jobs := []Job{}
+	for _, id := range a.jobIDs {
+		jobs = append(jobs, *a.jobs[id])
+	}
+	return jobs
+}
+
+// Job returns the Job with the id
+func (a *API) Job(id string) (Job, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	job, ok := a.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("No Job found with the id %v", id)
+	}
+	return *job, nil
+}
+
+// CancelJob cancels the running Job with the id. The command is told through
+// Canceled, its result is dropped. Canceling a finished Job is an error.
+func (a *API) CancelJob(id string) (Job, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	job, ok := a.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("No Job found with the id %v", id)
+	}
+	if job.State != JobRunning {
+		return *job, fmt.Errorf("Job %v is %v", id, job.State)
+	}
+	now := time.Now()
+	job.State, job.Finished = JobCanceled, &now
+	close(job.canceled)
+	return *job, nil
+}
+
+// startJob runs the command f with params in its own goroutine and returns
+// its Job. The Span of the Job is the child of the one in ctx.
+func (a *API) startJob(ctx context.Context, robot, device, command string,
+	f func(map[string]interface{}) interface{},
+	params map[string]interface{},
+) Job {
+	a.mutex.Lock()
+	if a.jobs == nil {
+		a.jobs = make(map[string]*Job)
+	}
+	a.jobSeq++
+	job := &Job{
+		ID:       strconv.FormatUint(a.jobSeq, 10),
+		Robot:    robot,
+		Device:   device,
+		Command:  command,
+		State:    JobRunning,
+		Started:  time.Now(),
+		canceled: make(chan bool),
+	}
+	a.jobs[job.ID] = job
+	a.jobIDs = append(a.jobIDs, job.ID)
+	a.pruneJobs()
+	started := *job
+	a.mutex.Unlock()
+
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params[jobParam] = job
+	go func() {
+		_, span := gobot.StartSpan(ctx, JobSpan, gobot.Attr("gobot.job", job.ID))
+		var result interface{}
+		var failure string
+		func() {
+			defer func() {
+				if v := recover(); v != nil {
+					failure = fmt.Sprint(v)
+				}
+			}()
+			result = f(params)
+		}()
+		if failure != "" {
+			span.RecordError(errors.New(failure))
+		}
+		span.End()
+
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		if job.State != JobRunning {
+			return
+		}
+		now := time.Now()
+		job.State, job.Result, job.Finished = JobDone, result, &now
+		if failure != "" {
+			job.State, job.Error = JobFailed, failure
+			a.logger().Error("Job failed", "job", job.ID, "command", command, "error", failure)
+		}
+	}()
+	return started
+}
+
+// pruneJobs forgets the oldest finished Jobs beyond MaxJobs, the running
+// ones are kept
+func (a *API) pruneJobs() {
+	max := a.MaxJobs
+	if max <= 0 {
+		max = DefaultMaxJobs
+	}
+	finished := 0
+	for _, id := range a.jobIDs {
+		if a.jobs[id].State != JobRunning {
+			finished++
+		}
+	}
+	ids := a.jobIDs[:0]
+	for _, id := range a.jobIDs {
+		if finished > max && a.jobs[id].State != JobRunning {
+			delete(a.jobs, id)
+			finished--
+			continue
+		}
+		ids = append(ids, id)
+	}
+	a.jobIDs = ids
+}
+
+// listJobs returns jobs route handler.
+// Writes JSON with the jobs, oldest first
+func (a *API) listJobs(res http.ResponseWriter, req *http.Request) {
+	a.writeJSON(map[string]interface{}{"jobs": a.Jobs()}, res)
+}
+
+// job returns job route handler.
+// Writes JSON with the job, its state and its result once done
+func (a *API) job(res http.ResponseWriter, req *http.Request) {
+	if job, err := a.Job(req.URL.Query().Get(":job")); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+	} else {
+		a.writeJSON(map[string]interface{}{"job": job}, res)
+	}
+}
+
+// cancelJob returns job cancellation route handler.
+// Cancels the running job and writes JSON with it
+func (a *API) cancelJob(res http.ResponseWriter, req *http.Request) {
+	if job, err := a.CancelJob(req.URL.Query().Get(":job")); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+	} else {
+		a.writeJSON(map[string]interface{}{"job": job}, res)
+	}
+}
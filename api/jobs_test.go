@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// serveJSON serves the request to a and returns the decoded JSON response
+func serveJSON(a *API, method string, path string) map[string]interface{} {
+	request, _ := http.NewRequest(method, path, http.NoBody)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	return body
+}
+
+// waitJob waits for the job with the id to be finished
+func waitJob(t *testing.T, a *API, id string) Job {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		job, err := a.Job(id)
+		gobottest.Assert(t, err, nil)
+		if job.State != JobRunning {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Job %v is still running", id)
+	return Job{}
+}
+
+func TestAsyncCommand(t *testing.T) {
+	a := initTestAPI()
+	release := make(chan bool)
+	a.master.Robot("Robot1").AddCommand("move", func(params map[string]interface{}) interface{} {
+		<-release
+		return params["distance"]
+	})
+
+	body := serveJSON(a, "POST", "/api/robots/Robot1/commands/move?async=true")
+	job := body["job"].(map[string]interface{})
+	gobottest.Assert(t, job["id"], "1")
+	gobottest.Assert(t, job["robot"], "Robot1")
+	gobottest.Assert(t, job["command"], "move")
+	gobottest.Assert(t, job["state"], "running")
+	gobottest.Assert(t, job["finished"], nil)
+
+	body = serveJSON(a, "GET", "/api/jobs")
+	gobottest.Assert(t, len(body["jobs"].([]interface{})), 1)
+
+	close(release)
+	gobottest.Assert(t, waitJob(t, a, "1").State, JobDone)
+	body = serveJSON(a, "GET", "/api/jobs/1")
+	gobottest.Assert(t, body["job"].(map[string]interface{})["state"], "done")
+	gobottest.Refute(t, body["job"].(map[string]interface{})["finished"], nil)
+
+	// device and master commands
+	request, _ := http.NewRequest("POST", "/api/robots/Robot1/devices/Device1/commands/TestDriverCommand?async=true",
+		strings.NewReader(`{"name":"human"}`))
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	job = body["job"].(map[string]interface{})
+	gobottest.Assert(t, job["device"], "Device1")
+	gobottest.Assert(t, waitJob(t, a, job["id"].(string)).Result, "hello human")
+
+	body = serveJSON(a, "POST", "/api/commands/TestFunction?async=true")
+	job = body["job"].(map[string]interface{})
+	gobottest.Assert(t, job["robot"], nil)
+	gobottest.Assert(t, job["command"], "TestFunction")
+
+	// unknown command
+	body = serveJSON(a, "POST", "/api/robots/Robot1/commands/unknown?async=true")
+	gobottest.Assert(t, body["error"], "Unknown Command")
+
+	body = serveJSON(a, "GET", "/api/jobs/42")
+	gobottest.Assert(t, body["error"], "No Job found with the id 42")
+}
+
+func TestCancelJob(t *testing.T) {
+	a := initTestAPI()
+	stopped := make(chan bool)
+	a.master.Robot("Robot1").AddCommand("sweep", func(params map[string]interface{}) interface{} {
+		<-Canceled(params)
+		close(stopped)
+		return "canceled"
+	})
+	gobottest.Assert(t, Canceled(map[string]interface{}{}), (<-chan bool)(nil))
+
+	body := serveJSON(a, "POST", "/api/robots/Robot1/commands/sweep?async=true")
+	id := body["job"].(map[string]interface{})["id"].(string)
+
+	body = serveJSON(a, "DELETE", "/api/jobs/"+id)
+	gobottest.Assert(t, body["job"].(map[string]interface{})["state"], "canceled")
+	<-stopped
+
+	// the result of a canceled job is dropped
+	job, _ := a.Job(id)
+	gobottest.Assert(t, job.State, JobCanceled)
+	gobottest.Assert(t, job.Result, nil)
+
+	body = serveJSON(a, "DELETE", "/api/jobs/"+id)
+	gobottest.Assert(t, body["error"], "Job 1 is canceled")
+	body = serveJSON(a, "DELETE", "/api/jobs/42")
+	gobottest.Assert(t, body["error"], "No Job found with the id 42")
+}
+
+func TestFailedJob(t *testing.T) {
+	a := initTestAPI()
+	a.master.Robot("Robot1").AddCommand("faulty", func(params map[string]interface{}) interface{} {
+		panic("motor stuck")
+	})
+
+	body := serveJSON(a, "POST", "/api/robots/Robot1/commands/faulty?async=true")
+	job := waitJob(t, a, body["job"].(map[string]interface{})["id"].(string))
+	gobottest.Assert(t, job.State, JobFailed)
+	gobottest.Assert(t, job.Error, "motor stuck")
+}
+
+func TestPruneJobs(t *testing.T) {
+	a := initTestAPI()
+	a.MaxJobs = 2
+	release := make(chan bool)
+	a.master.Robot("Robot1").AddCommand("wait", func(params map[string]interface{}) interface{} {
+		<-release
+		return nil
+	})
+	a.master.Robot("Robot1").AddCommand("noop", func(params map[string]interface{}) interface{} {
+		return nil
+	})
+	serveJSON(a, "POST", "/api/robots/Robot1/commands/wait?async=true")
+	for i := 0; i < 4; i++ {
+		body := serveJSON(a, "POST", "/api/robots/Robot1/commands/noop?async=true")
+		waitJob(t, a, body["job"].(map[string]interface{})["id"].(string))
+	}
+	serveJSON(a, "POST", "/api/robots/Robot1/commands/noop?async=true")
+
+	// the running job is kept along with the last finished ones
+	ids := []string{}
+	for _, job := range a.Jobs() {
+		ids = append(ids, job.ID)
+	}
+	gobottest.Assert(t, ids, []string{"1", "4", "5", "6"})
+	close(release)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"gobot.io/x/gobot"
+)
+
+// JSONMount is the JSON representation of a Master served by the API, either
+// its own or a mounted one.
+type JSONMount struct {
+	Name   string             `json:"name"`
+	Prefix string             `json:"prefix"`
+	URL    string             `json:"url,omitempty"`
+	Robots []*gobot.JSONRobot `json:"robots"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// mount is a Master, local or remote, served under a prefix
+type mount struct {
+	name    string
+	master  *gobot.Master
+	client  *Client
+	handler http.Handler
+}
+
+// Mount serves the API of the Master m under the prefix "/name", e.g.
+// "/lab/api/robots" for the robots of the Master mounted as "lab", so that
+// one API fronts the Masters of several processes. The handlers of the API,
+// such as its basic authentication, apply to the mounted routes.
+func (a *API) Mount(name string, m *gobot.Master) error {
+	sub := NewAPI(m)
+	sub.routes()
+	return a.addMount(mount{name: name, master: m, handler: sub})
+}
+
+// MountPeer serves the API of the peer under the prefix "/<peer name>", the
+// requests being proxied to its URL, events streams included.
+func (a *API) MountPeer(p Peer) error {
+	target, err := url.Parse(p.URL)
+	if err != nil {
+		return err
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.FlushInterval = -1
+	client := NewClient(p.URL)
+	client.SetName(p.Name)
+	return a.addMount(mount{name: p.Name, client: client, handler: proxy})
+}
+
+// Mounts returns the names of the mounted Masters, in mount order
+func (a *API) Mounts() []string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	names := []string{}
+	for _, m := range a.mounts {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// addMount routes the requests under the prefix of m to its handler
+func (a *API) addMount(m mount) error {
+	if m.name == "" || strings.ContainsAny(m.name, "/:") {
+		return errors.New("Invalid mount name " + m.name)
+	}
+	switch m.name {
+	case "api", "images", "js", "css", "partials", "index.html":
+		return errors.New("Mount name " + m.name + " is reserved by the API")
+	}
+
+	a.mutex.Lock()
+	for _, mounted := range a.mounts {
+		if mounted.name == m.name {
+			a.mutex.Unlock()
+			return errors.New("A Master is already mounted with the name " + m.name)
+		}
+	}
+	a.mounts = append(a.mounts, m)
+	a.mutex.Unlock()
+
+	prefix := "/" + m.name
+	h := http.StripPrefix(prefix, m.handler).ServeHTTP
+	a.Get(prefix+"/", h)
+	a.Post(prefix+"/", h)
+	a.Put(prefix+"/", h)
+	a.Delete(prefix+"/", h)
+	a.Options(prefix+"/", h)
+	a.Head(prefix+"/", h)
+	return nil
+}
+
+// masters returns the aggregated index route handler.
+// Writes JSON with the robots of the Master of the API and of the mounted
+// ones, a remote Master which can't be reached holding the error instead.
+// The robots are limited to the ones whose metadata matches the label
+// selectors when given.
+func (a *API) masters(res http.ResponseWriter, req *http.Request) {
+	labels := req.URL.Query()["label"]
+	if _, err := gobot.MatchLabels(nil, labels...); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+		return
+	}
+
+	a.mutex.Lock()
+	mounts := append([]mount{}, a.mounts...)
+	a.mutex.Unlock()
+
+	masters := []JSONMount{{Name: "", Prefix: "", Robots: labeledRobots(gobot.NewJSONMaster(a.master).Robots, labels)}}
+	for _, m := range mounts {
+		jm := JSONMount{Name: m.name, Prefix: "/" + m.name}
+		if m.client != nil {
+			jm.URL = m.client.URL
+			robots, err := m.client.Robots(labels...)
+			if err != nil {
+				jm.Error = err.Error()
+			}
+			jm.Robots = robots
+		} else {
+			jm.Robots = labeledRobots(gobot.NewJSONMaster(m.master).Robots, labels)
+		}
+		if jm.Robots == nil {
+			jm.Robots = []*gobot.JSONRobot{}
+		}
+		masters = append(masters, jm)
+	}
+	a.writeJSON(map[string]interface{}{"masters": masters}, res)
+}
+
+// labeledRobots returns the robots whose metadata matches the valid label
+// selectors
+func labeledRobots(robots []*gobot.JSONRobot, labels []string) []*gobot.JSONRobot {
+	matching := []*gobot.JSONRobot{}
+	for _, r := range robots {
+		if match, _ := gobot.MatchLabels(r.Metadata, labels...); match {
+			matching = append(matching, r)
+		}
+	}
+	return matching
+}
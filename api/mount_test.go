@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestMount(t *testing.T) {
+	a := initTestAPI()
+	lab := gobot.NewMaster()
+	lab.AddRobot(newTestRobot("LabRobot"))
+	gobottest.Assert(t, a.Mount("lab", lab), nil)
+	gobottest.Assert(t, a.Mounts(), []string{"lab"})
+
+	request, _ := http.NewRequest("GET", "/lab/api/robots/LabRobot", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string]gobot.JSONRobot
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["robot"].Name, "LabRobot")
+
+	request, _ = http.NewRequest("POST", "/lab/api/robots/LabRobot/devices/Device1/commands/TestDriverCommand",
+		bytes.NewBufferString(`{"name":"fred"}`))
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var result map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&result)
+	gobottest.Assert(t, result["result"], "hello fred")
+
+	// the robots of the API Master are not served under the prefix
+	request, _ = http.NewRequest("GET", "/lab/api/robots/Robot1", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	json.NewDecoder(response.Body).Decode(&result)
+	gobottest.Assert(t, result["error"], "No Robot found with the name Robot1")
+}
+
+func TestMountInvalid(t *testing.T) {
+	a := initTestAPI()
+	gobottest.Assert(t, a.Mount("lab", gobot.NewMaster()), nil)
+	gobottest.Refute(t, a.Mount("lab", gobot.NewMaster()), nil)
+	gobottest.Refute(t, a.Mount("", gobot.NewMaster()), nil)
+	gobottest.Refute(t, a.Mount("lab/1", gobot.NewMaster()), nil)
+	gobottest.Refute(t, a.Mount("api", gobot.NewMaster()), nil)
+	gobottest.Refute(t, a.MountPeer(Peer{Name: "pi1", URL: "://pi1"}), nil)
+	gobottest.Assert(t, a.Mounts(), []string{"lab"})
+}
+
+func TestMountPeer(t *testing.T) {
+	remote := initTestAPI()
+	server := httptest.NewServer(remote)
+	defer server.Close()
+
+	a := initTestAPI()
+	gobottest.Assert(t, a.MountPeer(Peer{Name: "pi1", URL: server.URL}), nil)
+
+	request, _ := http.NewRequest("POST", "/pi1/api/robots/Robot2/commands/robotTestFunction",
+		bytes.NewBufferString(`{"message":"hi","robot":"pi1"}`))
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var result map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&result)
+	gobottest.Assert(t, result["result"], "hey pi1, hi")
+
+	// a gateway mounting the peer is reached by a Client as the peer itself
+	gateway := httptest.NewServer(a)
+	defer gateway.Close()
+	robots, err := NewClient(gateway.URL + "/pi1").Robots()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(robots), 3)
+}
+
+func TestMasters(t *testing.T) {
+	remote := initTestAPI()
+	server := httptest.NewServer(remote)
+	defer server.Close()
+
+	a := initTestAPI()
+	lab := gobot.NewMaster()
+	lab.AddRobot(newTestRobot("LabRobot"))
+	a.Mount("lab", lab)
+	a.MountPeer(Peer{Name: "pi1", URL: server.URL})
+	a.MountPeer(Peer{Name: "pi2", URL: "http://127.0.0.1:1"})
+
+	request, _ := http.NewRequest("GET", "/api/masters", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string][]JSONMount
+	json.NewDecoder(response.Body).Decode(&body)
+	masters := body["masters"]
+	gobottest.Assert(t, len(masters), 4)
+
+	gobottest.Assert(t, masters[0].Prefix, "")
+	gobottest.Assert(t, len(masters[0].Robots), 3)
+
+	gobottest.Assert(t, masters[1].Name, "lab")
+	gobottest.Assert(t, masters[1].Prefix, "/lab")
+	gobottest.Assert(t, masters[1].Robots[0].Name, "LabRobot")
+
+	gobottest.Assert(t, masters[2].Name, "pi1")
+	gobottest.Assert(t, masters[2].URL, server.URL)
+	gobottest.Assert(t, len(masters[2].Robots), 3)
+	gobottest.Assert(t, masters[2].Error, "")
+
+	gobottest.Assert(t, masters[3].Name, "pi2")
+	gobottest.Refute(t, masters[3].Error, "")
+	gobottest.Assert(t, len(masters[3].Robots), 0)
+
+	// the robots of every master are selected by their labels
+	remote.master.Robot("Robot2").Metadata = map[string]string{"zone": "garage"}
+	lab.Robot("LabRobot").Metadata = map[string]string{"zone": "garage"}
+	request, _ = http.NewRequest("GET", "/api/masters?label=zone=garage", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	json.NewDecoder(response.Body).Decode(&body)
+	masters = body["masters"]
+	gobottest.Assert(t, len(masters[0].Robots), 0)
+	gobottest.Assert(t, masters[1].Robots[0].Name, "LabRobot")
+	gobottest.Assert(t, len(masters[2].Robots), 1)
+	gobottest.Assert(t, masters[2].Robots[0].Name, "Robot2")
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// Peer is a remote Gobot API, usually the API of another Master, that has
+// registered with this API.
+type Peer struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// AddPeer registers p, replacing any peer with the same name
+func (a *API) AddPeer(p Peer) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.peers[p.Name] = p
+}
+
+// RemovePeer unregisters the peer named name
+func (a *API) RemovePeer(name string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.peers, name)
+}
+
+// Peers returns the registered peers sorted by name
+func (a *API) Peers() []Peer {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	peers := []Peer{}
+	for _, p := range a.peers {
+		peers = append(peers, p)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Name < peers[j].Name })
+	return peers
+}
+
+// Peer returns a Client for the peer named name
+func (a *API) Peer(name string) (*Client, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	p, ok := a.peers[name]
+	if !ok {
+		return nil, errors.New("No Peer found with the name " + name)
+	}
+	c := NewClient(p.URL)
+	c.SetName(p.Name)
+	return c, nil
+}
+
+// listPeers returns peers route handler.
+// Writes JSON with the registered peers
+func (a *API) listPeers(res http.ResponseWriter, req *http.Request) {
+	a.writeJSON(map[string]interface{}{"peers": a.Peers()}, res)
+}
+
+// registerPeer returns peer registration route handler.
+// Registers the peer in the request body and writes JSON with it
+func (a *API) registerPeer(res http.ResponseWriter, req *http.Request) {
+	var p Peer
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		a.writeJSON(map[string]interface{}{"error": err.Error()}, res)
+		return
+	}
+	if p.Name == "" || p.URL == "" {
+		a.writeJSON(map[string]interface{}{"error": "Peer name and url are required"}, res)
+		return
+	}
+	a.AddPeer(p)
+	a.logger().Info("Peer registered", "peer", p.Name, "url", p.URL)
+	a.writeJSON(map[string]interface{}{"peer": p}, res)
+}
+
+// unregisterPeer returns peer removal route handler
+func (a *API) unregisterPeer(res http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get(":peer")
+	a.mutex.Lock()
+	_, ok := a.peers[name]
+	a.mutex.Unlock()
+	if !ok {
+		a.writeJSON(map[string]interface{}{"error": "No Peer found with the name " + name}, res)
+		return
+	}
+	a.RemovePeer(name)
+	a.writeJSON(map[string]interface{}{"peer": name}, res)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestRegisterPeer(t *testing.T) {
+	a := initTestAPI()
+
+	request, _ := http.NewRequest("POST", "/api/peers",
+		bytes.NewBufferString(`{"name":"pi1","url":"http://pi1:3000"}`))
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["peer"].(map[string]interface{})["name"], "pi1")
+	gobottest.Assert(t, a.Peers(), []Peer{{Name: "pi1", URL: "http://pi1:3000"}})
+
+	request, _ = http.NewRequest("GET", "/api/peers", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var peers map[string][]Peer
+	json.NewDecoder(response.Body).Decode(&peers)
+	gobottest.Assert(t, peers["peers"], []Peer{{Name: "pi1", URL: "http://pi1:3000"}})
+}
+
+func TestRegisterPeerInvalid(t *testing.T) {
+	a := initTestAPI()
+
+	request, _ := http.NewRequest("POST", "/api/peers", bytes.NewBufferString(`{"name":"pi1"}`))
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], "Peer name and url are required")
+	gobottest.Assert(t, len(a.Peers()), 0)
+}
+
+func TestUnregisterPeer(t *testing.T) {
+	a := initTestAPI()
+	a.AddPeer(Peer{Name: "pi1", URL: "http://pi1:3000"})
+
+	request, _ := http.NewRequest("DELETE", "/api/peers/pi1", nil)
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	gobottest.Assert(t, len(a.Peers()), 0)
+
+	request, _ = http.NewRequest("DELETE", "/api/peers/pi1", nil)
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+
+	var body map[string]interface{}
+	json.NewDecoder(response.Body).Decode(&body)
+	gobottest.Assert(t, body["error"], "No Peer found with the name pi1")
+}
+
+func TestPeersSorted(t *testing.T) {
+	a := initTestAPI()
+	a.AddPeer(Peer{Name: "pi2", URL: "http://pi2:3000"})
+	a.AddPeer(Peer{Name: "pi1", URL: "http://pi1:3000"})
+	gobottest.Assert(t, a.Peers()[0].Name, "pi1")
+	gobottest.Assert(t, a.Peers()[1].Name, "pi2")
+}
+
+func TestAPIPeer(t *testing.T) {
+	a := initTestAPI()
+	a.AddPeer(Peer{Name: "pi1", URL: "http://pi1:3000/"})
+
+	c, err := a.Peer("pi1")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Name(), "pi1")
+	gobottest.Assert(t, c.URL, "http://pi1:3000")
+
+	_, err = a.Peer("pi2")
+	gobottest.Assert(t, err.Error(), "No Peer found with the name pi2")
+}
@@ -0,0 +1,333 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Role is the role of a user or token of an RBAC, granting it access to
+// parts of the API.
+type Role string
+
+// Roles of an RBAC, each one granted the access of the previous ones
+const (
+	// RoleViewer reads the robots, devices, events and jobs, and runs no
+	// command but the ones of its Permissions
+	RoleViewer Role = "viewer"
+	// RoleOperator also runs the commands of its Permissions, any by
+	// default, stops the robots in emergency, controls their tasks and
+	// cancels jobs
+	RoleOperator Role = "operator"
+	// RoleAdmin also registers peers, and runs any command
+	RoleAdmin Role = "admin"
+)
+
+// roleLevels orders the Roles, a Role being granted the access of the lower
+// levels
+var roleLevels = map[Role]int{RoleViewer: 1, RoleOperator: 2, RoleAdmin: 3}
+
+// Permission grants running the commands matching Command, of the devices
+// matching Device, of the robots matching Robot. The fields are path.Match
+// patterns, "*" matching any name: an empty Device only matches the commands
+// of the robots, and an empty Robot the commands of the Master.
+type Permission struct {
+	Robot   string `yaml:"robot" json:"robot"`
+	Device  string `yaml:"device" json:"device"`
+	Command string `yaml:"command" json:"command"`
+}
+
+// matches returns whether p grants running command of the device of robot
+func (p Permission) matches(robot, device, command string) bool {
+	return matchName(p.Robot, robot) && matchName(p.Device, device) && matchName(p.Command, command)
+}
+
+// matchName returns whether name matches pattern, an empty pattern only
+// matching an empty name
+func matchName(pattern, name string) bool {
+	if pattern == "" || name == "" {
+		return pattern == name
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// rbacUser is a user authenticating with basic authentication
+type rbacUser struct {
+	password string
+	role     Role
+}
+
+// RBAC is the role-based access control of an API: the users and tokens
+// authenticating the requests are given a Role, which grants them access to
+// the routes and commands of the API. It is added to the API as a handler:
+//
+//	rbac := api.NewRBAC()
+//	rbac.AddUser("gort", "klatuu", api.RoleAdmin)
+//	rbac.AddToken("d4shb04rd", api.RoleViewer)
+//	rbac.SetPermissions(api.RoleOperator, api.Permission{Robot: "*", Device: "arm", Command: "*"})
+//	server.AddHandler(rbac.Handler())
+//
+// The tokens are sent as "Authorization: Bearer <token>" headers.
+type RBAC struct {
+	// Anonymous is the Role of the requests without credentials, such as
+	// RoleViewer for a shared read-only dashboard. They are rejected when
+	// empty, the default.
+	Anonymous Role
+
+	users       map[string]rbacUser
+	tokens      map[string]Role
+	permissions map[Role][]Permission
+	mutex       sync.Mutex
+}
+
+// NewRBAC returns a new RBAC without users nor tokens, whose operators run
+// any command.
+func NewRBAC() *RBAC {
+	return &RBAC{
+		users:  make(map[string]rbacUser),
+		tokens: make(map[string]Role),
+		permissions: map[Role][]Permission{
+			RoleOperator: {
+				{Command: "*"},
+				{Robot: "*", Command: "*"},
+				{Robot: "*", Device: "*", Command: "*"},
+			},
+		},
+	}
+}
+
+// AddUser adds a user authenticating with basic authentication.
+func (r *RBAC) AddUser(username, password string, role Role) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.users[username] = rbacUser{password: password, role: role}
+}
+
+// AddToken adds a bearer token.
+func (r *RBAC) AddToken(token string, role Role) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tokens[token] = role
+}
+
+// SetPermissions sets the commands the viewers or operators run, replacing
+// the previous Permissions of role. The admins run any command.
+func (r *RBAC) SetPermissions(role Role, permissions ...Permission) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.permissions[role] = append([]Permission{}, permissions...)
+}
+
+// Permissions returns the Permissions of role
+func (r *RBAC) Permissions(role Role) []Permission {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Permission{}, r.permissions[role]...)
+}
+
+// Role returns the Role of the credentials of req, and false when they are
+// invalid, or missing without an Anonymous Role.
+func (r *RBAC) Role(req *http.Request) (Role, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if username, password, ok := req.BasicAuth(); ok {
+		user, found := r.users[username]
+		// compare the password anyway to take the same time
+		if !secureCompare(password, user.password) || !found {
+			return "", false
+		}
+		return user.role, true
+	}
+
+	header := req.Header.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		given := strings.TrimPrefix(header, "Bearer ")
+		for token, role := range r.tokens {
+			if secureCompare(given, token) {
+				return role, true
+			}
+		}
+		return "", false
+	}
+	if header != "" {
+		return "", false
+	}
+
+	return r.Anonymous, r.Anonymous != ""
+}
+
+// Allowed returns whether role is granted the request req.
+func (r *RBAC) Allowed(role Role, req *http.Request) bool {
+	level := roleLevels[role]
+	if level == 0 {
+		return false
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	// strip the prefix of a mounted Master
+	for i, segment := range segments {
+		if segment == "api" {
+			segments = segments[i+1:]
+			break
+		}
+	}
+	// and the version of the route
+	if len(segments) > 0 && len(segments[0]) > 1 && segments[0][0] == 'v' {
+		if _, err := strconv.Atoi(segments[0][1:]); err == nil {
+			segments = segments[1:]
+		}
+	}
+
+	robot, device, command, isCommand := commandOf(segments)
+	switch {
+	case isCommand:
+		if role == RoleAdmin {
+			return true
+		}
+		for _, p := range r.Permissions(role) {
+			if p.matches(robot, device, command) {
+				return true
+			}
+		}
+		return false
+	case len(segments) > 0 && segments[0] == "peers" && !readOnly(req.Method):
+		return level >= roleLevels[RoleAdmin]
+	case !readOnly(req.Method):
+		return level >= roleLevels[RoleOperator]
+	}
+	return true
+}
+
+// Handler returns the API handler authenticating the requests and rejecting
+// the ones not granted to their Role, with a 401 Not Authorized and a 403
+// Forbidden status respectively.
+func (r *RBAC) Handler() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		role, ok := r.Role(req)
+		if !ok {
+			res.Header().Set("WWW-Authenticate",
+				"Basic realm=\"Authorization Required\"",
+			)
+			http.Error(res, "Not Authorized", http.StatusUnauthorized)
+			return
+		}
+		if !r.Allowed(role, req) {
+			http.Error(res, "Forbidden", http.StatusForbidden)
+		}
+	}
+}
+
+// commandOf returns the robot, device and command run by the route of the
+// segments of a path following "/api/"
+func commandOf(segments []string) (robot, device, command string, ok bool) {
+	switch {
+	case len(segments) == 2 && segments[0] == "commands":
+		return "", "", segments[1], true
+	case len(segments) == 4 && segments[0] == "robots" && segments[2] == "commands":
+		return segments[1], "", segments[3], true
+	case len(segments) == 6 && segments[0] == "robots" && segments[2] == "devices" && segments[4] == "commands":
+		return segments[1], segments[3], segments[5], true
+	}
+	return "", "", "", false
+}
+
+func readOnly(method string) bool {
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
+
+// RBACConfig describes the users, tokens and Permissions of an RBAC.
+type RBACConfig struct {
+	Anonymous Role `yaml:"anonymous" json:"anonymous"`
+	Users     []struct {
+		Name     string `yaml:"name" json:"name"`
+		Password string `yaml:"password" json:"password"`
+		Role     Role   `yaml:"role" json:"role"`
+	} `yaml:"users" json:"users"`
+	Tokens []struct {
+		Token string `yaml:"token" json:"token"`
+		Role  Role   `yaml:"role" json:"role"`
+	} `yaml:"tokens" json:"tokens"`
+	Permissions map[Role][]Permission `yaml:"permissions" json:"permissions"`
+}
+
+// LoadRBAC reads an RBAC from a configuration file, such as:
+//
+//	anonymous: viewer
+//	users:
+//	  - name: gort
+//	    password: klatuu
+//	    role: admin
+//	tokens:
+//	  - token: d4shb04rd
+//	    role: operator
+//	permissions:
+//	  operator:
+//	    - robot: "*"
+//	      device: arm
+//	      command: "*"
+//
+// Files ending in ".json" are parsed as JSON, all others as YAML. The
+// operators run any command unless their permissions are given.
+func LoadRBAC(file string) (*RBAC, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := RBACConfig{}
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		err = json.Unmarshal(data, &c)
+	} else {
+		err = yaml.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.Build()
+}
+
+// Build returns the RBAC described by c.
+func (c RBACConfig) Build() (*RBAC, error) {
+	r := NewRBAC()
+	if c.Anonymous != "" {
+		if err := checkRole(c.Anonymous); err != nil {
+			return nil, err
+		}
+		r.Anonymous = c.Anonymous
+	}
+	for _, u := range c.Users {
+		if err := checkRole(u.Role); err != nil {
+			return nil, err
+		}
+		r.AddUser(u.Name, u.Password, u.Role)
+	}
+	for _, t := range c.Tokens {
+		if err := checkRole(t.Role); err != nil {
+			return nil, err
+		}
+		r.AddToken(t.Token, t.Role)
+	}
+	for role, permissions := range c.Permissions {
+		if err := checkRole(role); err != nil {
+			return nil, err
+		}
+		r.SetPermissions(role, permissions...)
+	}
+	return r, nil
+}
+
+func checkRole(role Role) error {
+	if roleLevels[role] == 0 {
+		return fmt.Errorf("Unknown role %q", role)
+	}
+	return nil
+}
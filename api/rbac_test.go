@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func initTestRBACAPI() (*API, *RBAC) {
+	a := initTestAPI()
+	r := NewRBAC()
+	r.AddUser("admin", "password", RoleAdmin)
+	r.AddToken("operator-token", RoleOperator)
+	r.AddToken("viewer-token", RoleViewer)
+	a.AddHandler(r.Handler())
+	return a, r
+}
+
+func rbacRequest(a *API, method, path, token string) int {
+	request, _ := http.NewRequest(method, path, bytes.NewBufferString(`{"name":"bot","message":"bot","robot":"bot"}`))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	return response.Code
+}
+
+func TestRBACAuthentication(t *testing.T) {
+	a, r := initTestRBACAPI()
+
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots", ""), 401)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots", "wrong"), 401)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots", "viewer-token"), 200)
+
+	request, _ := http.NewRequest("GET", "/api/robots", nil)
+	request.SetBasicAuth("admin", "password")
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	gobottest.Assert(t, response.Code, 200)
+
+	request.SetBasicAuth("admin", "wrongPassword")
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	gobottest.Assert(t, response.Code, 401)
+
+	request.SetBasicAuth("nobody", "password")
+	response = httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	gobottest.Assert(t, response.Code, 401)
+
+	r.Anonymous = RoleViewer
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots", ""), 200)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/estop", ""), 403)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots", "wrong"), 401)
+}
+
+func TestRBACViewer(t *testing.T) {
+	a, _ := initTestRBACAPI()
+
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots/Robot1/devices", "viewer-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/jobs", "viewer-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/index.html", "viewer-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/commands/TestFunction", "viewer-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/commands/robotTestFunction", "viewer-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots/Robot1/devices/Device1/commands/TestDriverCommand", "viewer-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/estop", "viewer-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "DELETE", "/api/jobs/1", "viewer-token"), 403)
+}
+
+func TestRBACOperator(t *testing.T) {
+	a, r := initTestRBACAPI()
+
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/commands/TestFunction", "operator-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/commands/robotTestFunction", "operator-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/devices/Device1/commands/TestDriverCommand", "operator-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/tasks/patrol/pause", "operator-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/peers", "operator-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "DELETE", "/api/peers/pi1", "operator-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/peers", "operator-token"), 200)
+
+	r.SetPermissions(RoleOperator, Permission{Robot: "Robot*", Device: "Device1", Command: "Test*"})
+	gobottest.Assert(t, len(r.Permissions(RoleOperator)), 1)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot2/devices/Device1/commands/TestDriverCommand", "operator-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot2/devices/Device1/commands/DriverCommand", "operator-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot2/devices/Device2/commands/TestDriverCommand", "operator-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/commands/robotTestFunction", "operator-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/commands/TestFunction", "operator-token"), 403)
+}
+
+func TestRBACViewerPermissions(t *testing.T) {
+	a, r := initTestRBACAPI()
+	r.SetPermissions(RoleViewer, Permission{Robot: "*", Command: "robotTestFunction"})
+
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots/Robot3/commands/robotTestFunction", "viewer-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/robots/Robot3/devices/Device1/commands/robotTestFunction", "viewer-token"), 403)
+}
+
+func TestRBACAdmin(t *testing.T) {
+	a, r := initTestRBACAPI()
+	r.SetPermissions(RoleOperator)
+	r.AddToken("admin-token", RoleAdmin)
+
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/commands/robotTestFunction", "operator-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "POST", "/api/robots/Robot1/commands/robotTestFunction", "admin-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "DELETE", "/api/peers/pi1", "admin-token"), 200)
+}
+
+func TestRBACMountedRoutes(t *testing.T) {
+	r := NewRBAC()
+	r.AddToken("viewer-token", RoleViewer)
+	req, _ := http.NewRequest("POST", "/lab/api/robots/Robot1/commands/robotTestFunction", nil)
+	gobottest.Assert(t, r.Allowed(RoleViewer, req), false)
+	gobottest.Assert(t, r.Allowed(RoleOperator, req), true)
+	gobottest.Assert(t, r.Allowed(Role("guest"), req), false)
+
+	req, _ = http.NewRequest("POST", "/lab/api/peers", nil)
+	gobottest.Assert(t, r.Allowed(RoleOperator, req), false)
+	gobottest.Assert(t, r.Allowed(RoleAdmin, req), true)
+}
+
+func TestLoadRBAC(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rbac")
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "rbac.yaml")
+	ioutil.WriteFile(file, []byte(`
+anonymous: viewer
+users:
+  - name: gort
+    password: klatuu
+    role: admin
+tokens:
+  - token: t0k3n
+    role: operator
+permissions:
+  operator:
+    - robot: "*"
+      device: arm
+      command: "*"
+`), 0644)
+	r, err := LoadRBAC(file)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r.Anonymous, RoleViewer)
+	gobottest.Assert(t, r.Permissions(RoleOperator), []Permission{{Robot: "*", Device: "arm", Command: "*"}})
+
+	req, _ := http.NewRequest("GET", "/api/robots", nil)
+	req.Header.Set("Authorization", "Bearer t0k3n")
+	role, ok := r.Role(req)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, role, RoleOperator)
+	req.SetBasicAuth("gort", "klatuu")
+	role, _ = r.Role(req)
+	gobottest.Assert(t, role, RoleAdmin)
+
+	file = filepath.Join(dir, "rbac.json")
+	ioutil.WriteFile(file, []byte(`{"tokens": [{"token": "t0k3n", "role": "root"}]}`), 0644)
+	_, err = LoadRBAC(file)
+	gobottest.Assert(t, err.Error(), `Unknown role "root"`)
+
+	_, err = LoadRBAC(filepath.Join(dir, "missing.yaml"))
+	gobottest.Refute(t, err, nil)
+}
+
+func TestClientToken(t *testing.T) {
+	a, _ := initTestRBACAPI()
+	server := httptest.NewServer(a)
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	_, err := c.Robots()
+	gobottest.Refute(t, err, nil)
+
+	c.Token = "viewer-token"
+	robots, err := c.Robots()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(robots), 3)
+	_, err = c.Command("Robot1", "robotTestFunction", nil)
+	gobottest.Assert(t, err.Error(), server.URL+"/api/robots/Robot1/commands/robotTestFunction: 403 Forbidden")
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Record is a line of the files written by a Recorder, holding either an
+// Event of a Device or a telemetry snapshot of the Robot.
+type Record struct {
+	// Type is "event" or "telemetry"
+	Type      string                `json:"type"`
+	Time      time.Time             `json:"time"`
+	Robot     string                `json:"robot"`
+	Device    string                `json:"device,omitempty"`
+	Name      string                `json:"name,omitempty"`
+	Seq       uint64                `json:"seq,omitempty"`
+	Data      interface{}           `json:"data,omitempty"`
+	Telemetry *gobot.RobotTelemetry `json:"telemetry,omitempty"`
+}
+
+// Recorder appends the Events of the Devices of a Robot, and periodic
+// snapshots of its telemetry, to a file of JSON lines in Dir named after the
+// Robot, e.g. "rover.jsonl", so that field deployments can be analyzed after
+// the fact.
+//
+// The file is rotated once it reaches MaxSize bytes or is MaxAge old,
+// whichever comes first: it is renamed after the time of the rotation, e.g.
+// "rover-20170302T154105.123.jsonl", and gzipped when Compress is set. Only
+// the MaxBackups newest rotated files are kept, unless MaxBackups is zero.
+type Recorder struct {
+	Dir               string
+	MaxSize           int64
+	MaxAge            time.Duration
+	MaxBackups        int
+	Compress          bool
+	TelemetryInterval time.Duration
+	robot             *gobot.Robot
+	file              *os.File
+	size              int64
+	opened            time.Time
+	halt              chan bool
+	wg                sync.WaitGroup
+	mutex             sync.Mutex
+}
+
+// NewRecorder returns a Recorder of robot writing to dir, rotating the file
+// every 10MB or day, keeping 10 compressed backups, with a telemetry
+// snapshot every minute.
+func NewRecorder(robot *gobot.Robot, dir string) *Recorder {
+	return &Recorder{
+		Dir:               dir,
+		MaxSize:           10 * 1024 * 1024,
+		MaxAge:            24 * time.Hour,
+		MaxBackups:        10,
+		Compress:          true,
+		TelemetryInterval: time.Minute,
+		robot:             robot,
+	}
+}
+
+// Path returns the path of the file being written
+func (r *Recorder) Path() string {
+	return filepath.Join(r.Dir, r.robot.Name+".jsonl")
+}
+
+// Start opens the file, appending to it if it exists, and starts recording
+// the Events of the Devices of the Robot and its telemetry.
+func (r *Recorder) Start() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.halt = make(chan bool)
+	r.robot.Devices().Each(func(d gobot.Device) {
+		e, ok := d.(gobot.Eventer)
+		if !ok {
+			return
+		}
+		out := e.SubscribeWith(gobot.SubscribeOptions{Policy: gobot.DropOldest, QueueSize: 100})
+		r.wg.Add(1)
+		go func(name string, halt chan bool) {
+			defer r.wg.Done()
+			defer e.Unsubscribe(out)
+			for {
+				select {
+				case evt := <-out:
+					r.write(Record{Type: "event", Time: evt.Time, Device: name, Name: evt.Name, Seq: evt.Seq, Data: evt.Data})
+				case <-halt:
+					return
+				}
+			}
+		}(d.Name(), r.halt)
+	})
+
+	if r.TelemetryInterval > 0 {
+		ticker := gobot.DefaultClock().NewTicker(r.TelemetryInterval)
+		r.wg.Add(1)
+		go func(halt chan bool) {
+			defer r.wg.Done()
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C():
+					r.RecordTelemetry()
+				case <-halt:
+					return
+				}
+			}
+		}(r.halt)
+	}
+	return nil
+}
+
+// Stop stops recording, and closes the file.
+func (r *Recorder) Stop() error {
+	r.mutex.Lock()
+	if r.halt != nil {
+		close(r.halt)
+		r.halt = nil
+	}
+	r.mutex.Unlock()
+	r.wg.Wait()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// RecordTelemetry records a snapshot of the telemetry of the Robot now
+func (r *Recorder) RecordTelemetry() {
+	telemetry := r.robot.Telemetry()
+	r.write(Record{Type: "telemetry", Time: gobot.DefaultClock().Now(), Telemetry: &telemetry})
+}
+
+// Rotate renames the file being written, compressing it when Compress is
+// set, and opens a new one.
+func (r *Recorder) Rotate() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rotate()
+}
+
+func (r *Recorder) write(record Record) {
+	record.Robot = r.robot.Name
+	line, err := json.Marshal(record)
+	if err != nil {
+		r.logger().Error("Recorder failed to encode record", "error", err)
+		return
+	}
+	line = append(line, '\n')
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file == nil {
+		return
+	}
+	if r.size > 0 && ((r.MaxSize > 0 && r.size+int64(len(line)) > r.MaxSize) ||
+		(r.MaxAge > 0 && gobot.DefaultClock().Now().Sub(r.opened) >= r.MaxAge)) {
+		if err := r.rotate(); err != nil {
+			r.logger().Error("Recorder failed to rotate", "path", r.Path(), "error", err)
+			// keep on writing to the file when it could not be renamed
+			if r.file == nil && r.open() != nil {
+				return
+			}
+		}
+	}
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		r.logger().Error("Recorder failed to write", "path", r.Path(), "error", err)
+	}
+}
+
+// open opens the file for appending, the mutex being held
+func (r *Recorder) open() error {
+	f, err := os.OpenFile(r.Path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	r.opened = gobot.DefaultClock().Now()
+	return nil
+}
+
+// rotate rotates the file, the mutex being held
+func (r *Recorder) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+		r.file = nil
+	}
+
+	stamp := gobot.DefaultClock().Now().UTC().Format("20060102T150405.000")
+	base := filepath.Join(r.Dir, r.robot.Name+"-"+stamp)
+	rotated := base + ".jsonl"
+	for i := 1; exists(rotated) || exists(rotated+".gz"); i++ {
+		rotated = fmt.Sprintf("%s-%d.jsonl", base, i)
+	}
+	if err := os.Rename(r.Path(), rotated); err != nil {
+		return err
+	}
+	if r.Compress {
+		if err := compress(rotated); err != nil {
+			return err
+		}
+	}
+	if err := r.removeBackups(); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// removeBackups removes the oldest rotated files beyond MaxBackups
+func (r *Recorder) removeBackups() error {
+	if r.MaxBackups <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(r.Dir, r.robot.Name+"-[0-9]*T[0-9]*.jsonl*"))
+	if err != nil {
+		return err
+	}
+	// the time stamps sort in chronological order
+	sort.Strings(matches)
+	for len(matches) > r.MaxBackups {
+		if err := os.Remove(matches[0]); err != nil {
+			return err
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
+
+func (r *Recorder) logger() gobot.Logger {
+	return r.robot.Logger().WithComponent("recorder")
+}
+
+// compress replaces the file at path with its gzipped copy
+func compress(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err == nil {
+		err = zw.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func readTestRecords(t *testing.T, r io.Reader) (records []Record) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var record Record
+		gobottest.Assert(t, json.Unmarshal(scanner.Bytes(), &record), nil)
+		records = append(records, record)
+	}
+	return
+}
+
+func readTestRecordFile(t *testing.T, path string) []Record {
+	f, err := os.Open(path)
+	gobottest.Assert(t, err, nil)
+	defer f.Close()
+	if filepath.Ext(path) != ".gz" {
+		return readTestRecords(t, f)
+	}
+	zr, err := gzip.NewReader(f)
+	gobottest.Assert(t, err, nil)
+	return readTestRecords(t, zr)
+}
+
+func waitForTestRecords(t *testing.T, path string, n int) []Record {
+	deadline := time.Now().Add(time.Second)
+	for {
+		records := readTestRecordFile(t, path)
+		if len(records) >= n || time.Now().After(deadline) {
+			return records
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gobot-recorder")
+	defer os.RemoveAll(dir)
+	clock := gobot.NewFakeClock(time.Unix(1488469265, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	robot := newTestRobot("Robot1")
+	r := NewRecorder(robot, filepath.Join(dir, "logs"))
+	gobottest.Assert(t, r.TelemetryInterval, time.Minute)
+	gobottest.Assert(t, r.Start(), nil)
+
+	robot.Device("Device1").(gobot.Eventer).Publish("TestEvent", "hello")
+	records := waitForTestRecords(t, r.Path(), 1)
+	gobottest.Assert(t, len(records), 1)
+	gobottest.Assert(t, records[0].Type, "event")
+	gobottest.Assert(t, records[0].Robot, "Robot1")
+	gobottest.Assert(t, records[0].Device, "Device1")
+	gobottest.Assert(t, records[0].Name, "TestEvent")
+	gobottest.Assert(t, records[0].Data, "hello")
+	gobottest.Assert(t, records[0].Seq, uint64(1))
+	gobottest.Assert(t, records[0].Time.Equal(time.Unix(1488469265, 0)), true)
+
+	// the telemetry is recorded periodically
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	records = waitForTestRecords(t, r.Path(), 2)
+	gobottest.Assert(t, len(records), 2)
+	gobottest.Assert(t, records[1].Type, "telemetry")
+	gobottest.Assert(t, records[1].Telemetry.Name, "Robot1")
+	gobottest.Assert(t, len(records[1].Telemetry.Devices), 3)
+
+	gobottest.Assert(t, r.Stop(), nil)
+	robot.Device("Device1").(gobot.Eventer).Publish("TestEvent", "late")
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, len(readTestRecordFile(t, r.Path())), 2)
+}
+
+func TestRecorderRotation(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gobot-recorder")
+	defer os.RemoveAll(dir)
+	clock := gobot.NewFakeClock(time.Unix(1488469265, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	robot := newTestRobot("Robot1")
+	r := NewRecorder(robot, dir)
+	r.TelemetryInterval = 0
+	r.MaxSize = 400
+	r.MaxAge = time.Hour
+	r.MaxBackups = 2
+	gobottest.Assert(t, r.Start(), nil)
+	defer r.Stop()
+
+	// the telemetry snapshots are about 300 bytes long
+	r.RecordTelemetry()
+	r.RecordTelemetry()
+	rotated := filepath.Join(dir, "Robot1-20170302T154105.000.jsonl.gz")
+	gobottest.Assert(t, len(readTestRecordFile(t, rotated)), 1)
+	gobottest.Assert(t, len(readTestRecordFile(t, r.Path())), 1)
+
+	// the file is rotated after MaxAge
+	clock.Advance(30 * time.Minute)
+	r.Compress = false
+	gobottest.Assert(t, r.Rotate(), nil)
+	clock.Advance(time.Hour)
+	robot.Device("Device1").(gobot.Eventer).Publish("TestEvent", "a")
+	robot.Device("Device1").(gobot.Eventer).Publish("TestEvent", "b")
+	waitForTestRecords(t, r.Path(), 1)
+
+	// only the 2 newest backups are kept
+	matches, _ := filepath.Glob(filepath.Join(dir, "Robot1-*"))
+	gobottest.Assert(t, matches, []string{
+		filepath.Join(dir, "Robot1-20170302T161105.000.jsonl"),
+		filepath.Join(dir, "Robot1-20170302T171105.000.jsonl"),
+	})
+	gobottest.Assert(t, len(readTestRecordFile(t, matches[1])), 1)
+}
+
+func TestRecorderStartError(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gobot-recorder")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	ioutil.WriteFile(file, []byte{}, 0644)
+
+	r := NewRecorder(newTestRobot("Robot1"), filepath.Join(file, "logs"))
+	gobottest.Refute(t, r.Start(), nil)
+	gobottest.Assert(t, r.Stop(), nil)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"gobot.io/x/gobot"
+)
+
+// Names of the Spans started by the API
+const (
+	// CommandSpan times the execution of a command
+	CommandSpan = "api.command"
+	// JobSpan times the command of a Job, as the child of its CommandSpan
+	JobSpan = "api.job"
+)
+
+// TraceExtractor is implemented by the Tracers able to continue the trace of
+// a remote caller, such as a peer API, from the headers of its request.
+type TraceExtractor interface {
+	// Extract returns ctx holding the remote Span found in header, if any
+	Extract(ctx context.Context, header http.Header) context.Context
+}
+
+// traceContext returns the context of req, holding the Span of the caller
+// when the DefaultTracer is a TraceExtractor
+func traceContext(req *http.Request) context.Context {
+	if extractor, ok := gobot.DefaultTracer().(TraceExtractor); ok {
+		return extractor.Extract(req.Context(), req.Header)
+	}
+	return req.Context()
+}
+
+// commandAttributes returns the Attributes of the CommandSpan of the command
+// requested with query
+func commandAttributes(query url.Values) []gobot.Attribute {
+	attrs := []gobot.Attribute{gobot.Attr("gobot.command", query.Get(":command"))}
+	if robot := query.Get(":robot"); robot != "" {
+		attrs = append(attrs, gobot.Attr("gobot.robot", robot))
+	}
+	if device := query.Get(":device"); device != "" {
+		attrs = append(attrs, gobot.Attr("gobot.device", device))
+	}
+	return attrs
+}
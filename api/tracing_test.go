@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/gobottest/tracetest"
+)
+
+// extractingRecorder continues the trace of the caller whose Span is named
+// by the Traceparent header
+type extractingRecorder struct {
+	*tracetest.Recorder
+}
+
+func (r extractingRecorder) Extract(ctx context.Context, header http.Header) context.Context {
+	if name := header.Get("Traceparent"); name != "" {
+		ctx, _ = r.Start(ctx, name)
+	}
+	return ctx
+}
+
+func TestCommandTracing(t *testing.T) {
+	rec := tracetest.NewRecorder()
+	gobot.SetDefaultTracer(rec)
+	defer gobot.SetDefaultTracer(nil)
+	a := initTestAPI()
+
+	request, _ := http.NewRequest("GET", "/api/robots/Robot1/devices/Device1/commands/TestDriverCommand",
+		bytes.NewBufferString(`{"name":"human"}`))
+	a.ServeHTTP(httptest.NewRecorder(), request)
+	spans := rec.Spans(CommandSpan)
+	gobottest.Assert(t, len(spans), 1)
+	gobottest.Assert(t, spans[0].Attributes, map[string]interface{}{
+		"gobot.robot":   "Robot1",
+		"gobot.device":  "Device1",
+		"gobot.command": "TestDriverCommand",
+	})
+	gobottest.Assert(t, spans[0].ParentID, 0)
+	gobottest.Assert(t, spans[0].Ended, true)
+
+	rec.Reset()
+	serveJSON(a, "GET", "/api/commands/Unknown")
+	spans = rec.Spans(CommandSpan)
+	gobottest.Assert(t, spans[0].Attributes, map[string]interface{}{"gobot.command": "Unknown"})
+	gobottest.Assert(t, len(spans[0].Errors), 1)
+}
+
+func TestCommandTracingJob(t *testing.T) {
+	rec := tracetest.NewRecorder()
+	gobot.SetDefaultTracer(rec)
+	defer gobot.SetDefaultTracer(nil)
+	a := initTestAPI()
+	a.master.Robot("Robot1").AddCommand("faulty", func(params map[string]interface{}) interface{} {
+		panic("motor stuck")
+	})
+
+	body := serveJSON(a, "POST", "/api/robots/Robot1/commands/faulty?async=true")
+	waitJob(t, a, body["job"].(map[string]interface{})["id"].(string))
+	command := rec.Spans(CommandSpan)[0]
+	gobottest.Assert(t, command.Attributes["gobot.async"], true)
+	job := rec.Spans(JobSpan)[0]
+	gobottest.Assert(t, job.ParentID, command.ID)
+	gobottest.Assert(t, job.Attributes["gobot.job"], "1")
+	gobottest.Assert(t, job.Errors[0].Error(), "motor stuck")
+	gobottest.Assert(t, job.Ended, true)
+}
+
+func TestCommandTracingExtract(t *testing.T) {
+	rec := tracetest.NewRecorder()
+	gobot.SetDefaultTracer(extractingRecorder{rec})
+	defer gobot.SetDefaultTracer(nil)
+	a := initTestAPI()
+
+	request, _ := http.NewRequest("GET", "/api/commands/TestFunction",
+		bytes.NewBufferString(`{"message":"world"}`))
+	request.Header.Set("Traceparent", "peer")
+	a.ServeHTTP(httptest.NewRecorder(), request)
+	peer := rec.Spans("peer")[0]
+	gobottest.Assert(t, rec.Spans(CommandSpan)[0].ParentID, peer.ID)
+}
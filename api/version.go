@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Versions of the API. Version 1, the default, keeps the payloads of the
+// clients and dashboards written before version 2, which:
+//
+//   - reports the errors with their HTTP status, 404 Not Found for an unknown
+//     robot, device or command, 400 Bad Request for an invalid request, and
+//     the error as an object: {"error": {"status": 404, "message": "..."}}
+//   - lists the commands as objects: [{"name": "say_hello"}], leaving room
+//     for their parameters
+//   - always includes the metadata of the robots and devices, {} when empty
+//
+// A version is requested by the route, e.g. /api/v2/robots, or by the Accept
+// header of a request to /api/..., e.g. "application/vnd.gobot.v2+json".
+const (
+	V1 = 1
+	V2 = 2
+	// LatestVersion is the latest version of the API
+	LatestVersion = V2
+)
+
+// versionMediaType returns the media type of version
+func versionMediaType(version int) string {
+	return "application/vnd.gobot.v" + strconv.Itoa(version) + "+json"
+}
+
+// versionedWriter writes the responses of a version other than V1
+type versionedWriter struct {
+	http.ResponseWriter
+	version int
+}
+
+func (w *versionedWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *versionedWriter) CloseNotify() <-chan bool {
+	if c, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return c.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// negotiate returns the version requested by req, and req routed to the
+// unversioned route. It returns false when the Accept header only lists
+// unknown versions.
+func negotiate(req *http.Request) (int, *http.Request, bool) {
+	p := req.URL.Path
+	for _, version := range []int{V1, V2} {
+		prefix := "/api/v" + strconv.Itoa(version)
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			routed := new(http.Request)
+			*routed = *req
+			routed.URL = new(url.URL)
+			*routed.URL = *req.URL
+			routed.URL.Path = "/api" + strings.TrimPrefix(p, prefix)
+			if routed.URL.Path == "/api" {
+				routed.URL.Path = "/api/"
+			}
+			routed.URL.RawPath = ""
+			return version, routed, true
+		}
+	}
+
+	if !strings.HasPrefix(p, "/api/") || req.Header.Get("Accept") == "" {
+		return V1, req, true
+	}
+	acceptable := false
+	for _, accepted := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(mediaType, "application/vnd.gobot.v") {
+			for _, version := range []int{V1, V2} {
+				if mediaType == versionMediaType(version) {
+					return version, req, true
+				}
+			}
+			continue
+		}
+		acceptable = true
+	}
+	return V1, req, acceptable
+}
+
+// writeVersionedJSON writes j in the shape of the version of the writer
+func (a *API) writeVersionedJSON(j interface{}, w *versionedWriter) {
+	data, _ := json.Marshal(j)
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(data, &payload); err == nil {
+		if message, ok := payload["error"].(string); ok {
+			status := errorStatus(message)
+			payload["error"] = map[string]interface{}{"status": status, "message": message}
+			data, _ = json.Marshal(payload)
+			w.Header().Set("Content-Type", versionMediaType(w.version)+"; charset=utf-8")
+			w.WriteHeader(status)
+			w.Write(data)
+			return
+		}
+		v2Payload(payload)
+		data, _ = json.Marshal(payload)
+	}
+	w.Header().Set("Content-Type", versionMediaType(w.version)+"; charset=utf-8")
+	w.Write(data)
+}
+
+// errorStatus returns the HTTP status of an error of the API
+func errorStatus(message string) int {
+	switch {
+	case strings.HasPrefix(message, "No ") || message == "Unknown Command":
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "Job "):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// v2Payload converts the robots, devices and commands of a version 1
+// payload to version 2
+func v2Payload(payload map[string]interface{}) {
+	for key, value := range payload {
+		switch key {
+		case "commands":
+			payload[key] = v2Commands(value)
+		case "robot", "device":
+			v2Resource(value)
+		case "robots", "devices":
+			for _, r := range asSlice(value) {
+				v2Resource(r)
+			}
+		case "MCP":
+			if m, ok := value.(map[string]interface{}); ok {
+				v2Payload(m)
+			}
+		case "masters":
+			for _, m := range asSlice(value) {
+				if m, ok := m.(map[string]interface{}); ok {
+					v2Payload(m)
+				}
+			}
+		}
+	}
+}
+
+// v2Resource converts a robot or a device to version 2
+func v2Resource(value interface{}) {
+	r, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	r["commands"] = v2Commands(r["commands"])
+	if r["metadata"] == nil {
+		r["metadata"] = map[string]interface{}{}
+	}
+	for _, d := range asSlice(r["devices"]) {
+		v2Resource(d)
+	}
+}
+
+// v2Commands converts a list of command names to command objects
+func v2Commands(value interface{}) interface{} {
+	commands := []interface{}{}
+	for _, c := range asSlice(value) {
+		if name, ok := c.(string); ok {
+			commands = append(commands, map[string]interface{}{"name": name})
+		} else {
+			commands = append(commands, c)
+		}
+	}
+	return commands
+}
+
+func asSlice(value interface{}) []interface{} {
+	s, _ := value.([]interface{})
+	return s
+}
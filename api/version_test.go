@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func versionedRequest(a *API, method, path, accept string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	request, _ := http.NewRequest(method, path, bytes.NewBufferString(`{"message":"bot","robot":"bot"}`))
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	response := httptest.NewRecorder()
+	a.ServeHTTP(response, request)
+	body := map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&body)
+	return response, body
+}
+
+func TestAPIV1Routes(t *testing.T) {
+	a := initTestAPI()
+
+	response, body := versionedRequest(a, "GET", "/api/v1/robots/Robot1", "")
+	gobottest.Assert(t, response.Code, 200)
+	gobottest.Assert(t, response.Header().Get("Content-Type"), "application/json; charset=utf-8")
+	robot := body["robot"].(map[string]interface{})
+	gobottest.Assert(t, robot["commands"], []interface{}{"robotTestFunction"})
+	gobottest.Assert(t, robot["metadata"], nil)
+
+	response, body = versionedRequest(a, "GET", "/api/robots/UnknownRobot1", "")
+	gobottest.Assert(t, response.Code, 200)
+	gobottest.Assert(t, body["error"], "No Robot found with the name UnknownRobot1")
+
+	response, body = versionedRequest(a, "GET", "/api/v1", "")
+	gobottest.Assert(t, response.Code, 200)
+	gobottest.Refute(t, body["MCP"], nil)
+}
+
+func TestAPIV2Routes(t *testing.T) {
+	a := initTestAPI()
+
+	response, body := versionedRequest(a, "GET", "/api/v2/robots/Robot1", "")
+	gobottest.Assert(t, response.Code, 200)
+	gobottest.Assert(t, response.Header().Get("Content-Type"), "application/vnd.gobot.v2+json; charset=utf-8")
+	robot := body["robot"].(map[string]interface{})
+	gobottest.Assert(t, robot["commands"], []interface{}{map[string]interface{}{"name": "robotTestFunction"}})
+	gobottest.Assert(t, robot["metadata"], map[string]interface{}{})
+	device := robot["devices"].([]interface{})[0].(map[string]interface{})
+	gobottest.Assert(t, device["metadata"], map[string]interface{}{})
+	gobottest.Assert(t, len(device["commands"].([]interface{})), 2)
+
+	_, body = versionedRequest(a, "GET", "/api/v2/robots/Robot1/devices/Device1/commands", "")
+	gobottest.Assert(t, len(body["commands"].([]interface{})), 2)
+
+	_, body = versionedRequest(a, "GET", "/api/v2/robots", "")
+	gobottest.Assert(t, body["robots"].([]interface{})[0].(map[string]interface{})["metadata"], map[string]interface{}{})
+
+	_, body = versionedRequest(a, "GET", "/api/v2/", "")
+	mcp := body["MCP"].(map[string]interface{})
+	gobottest.Assert(t, mcp["commands"], []interface{}{map[string]interface{}{"name": "TestFunction"}})
+
+	response, body = versionedRequest(a, "POST", "/api/v2/commands/TestFunction", "")
+	gobottest.Assert(t, response.Code, 200)
+	gobottest.Assert(t, body["result"], "hey bot")
+}
+
+func TestAPIV2Errors(t *testing.T) {
+	a := initTestAPI()
+
+	response, body := versionedRequest(a, "GET", "/api/v2/robots/UnknownRobot1", "")
+	gobottest.Assert(t, response.Code, 404)
+	gobottest.Assert(t, body["error"], map[string]interface{}{
+		"status":  float64(404),
+		"message": "No Robot found with the name UnknownRobot1",
+	})
+
+	response, _ = versionedRequest(a, "POST", "/api/v2/robots/Robot1/commands/UnknownCommand", "")
+	gobottest.Assert(t, response.Code, 404)
+
+	response, _ = versionedRequest(a, "GET", "/api/v2/robots/Robot1/events?since=abc", "")
+	gobottest.Assert(t, response.Code, 400)
+}
+
+func TestAPIVersionNegotiation(t *testing.T) {
+	a := initTestAPI()
+
+	response, body := versionedRequest(a, "GET", "/api/robots/Robot1", "application/vnd.gobot.v2+json")
+	gobottest.Assert(t, response.Code, 200)
+	gobottest.Assert(t, response.Header().Get("Content-Type"), "application/vnd.gobot.v2+json; charset=utf-8")
+	gobottest.Assert(t, body["robot"].(map[string]interface{})["metadata"], map[string]interface{}{})
+
+	response, _ = versionedRequest(a, "GET", "/api/robots/Robot1", "application/vnd.gobot.v9+json, application/vnd.gobot.v1+json")
+	gobottest.Assert(t, response.Header().Get("Content-Type"), "application/json; charset=utf-8")
+
+	response, _ = versionedRequest(a, "GET", "/api/robots/Robot1", "application/json")
+	gobottest.Assert(t, response.Header().Get("Content-Type"), "application/json; charset=utf-8")
+
+	response, _ = versionedRequest(a, "GET", "/api/robots/Robot1", "application/vnd.gobot.v9+json")
+	gobottest.Assert(t, response.Code, 406)
+}
+
+func TestAPIV2RBAC(t *testing.T) {
+	a, _ := initTestRBACAPI()
+
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/v2/robots", "viewer-token"), 200)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/api/v2/robots/Robot1/commands/robotTestFunction", "viewer-token"), 403)
+	gobottest.Assert(t, rbacRequest(a, "GET", "/lab/api/v2/robots/Robot1/commands/robotTestFunction", "viewer-token"), 403)
+}
@@ -0,0 +1,361 @@
+package behavior
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Status is the result of ticking a Node
+type Status int
+
+const (
+	// Running means the Node has not finished yet and wants to be ticked again
+	Running Status = iota
+	// Success means the Node finished successfully
+	Success
+	// Failure means the Node finished unsuccessfully
+	Failure
+)
+
+// String returns the name of the Status
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Success:
+		return "success"
+	case Failure:
+		return "failure"
+	}
+	return "unknown"
+}
+
+// Node is an element of a behavior tree.
+type Node interface {
+	// Tick advances the Node and returns its Status
+	Tick() Status
+	// Reset returns the Node to its initial state
+	Reset()
+}
+
+// ActionFunc is a leaf Node running a function on every tick.
+type ActionFunc func() Status
+
+// Tick calls the function
+func (a ActionFunc) Tick() Status { return a() }
+
+// Reset implements the Node interface
+func (a ActionFunc) Reset() {}
+
+// Action returns a leaf Node which calls f on every tick.
+func Action(f func() Status) Node {
+	return ActionFunc(f)
+}
+
+// Do returns a leaf Node wrapping a driver call such as led.On. It succeeds when
+// f returns nil and fails otherwise.
+func Do(f func() error) Node {
+	return ActionFunc(func() Status {
+		if err := f(); err != nil {
+			return Failure
+		}
+		return Success
+	})
+}
+
+// Condition returns a leaf Node which succeeds when f returns true and fails
+// otherwise.
+func Condition(f func() bool) Node {
+	return ActionFunc(func() Status {
+		if f() {
+			return Success
+		}
+		return Failure
+	})
+}
+
+type composite struct {
+	children []Node
+	current  int
+}
+
+func (c *composite) Reset() {
+	c.current = 0
+	for _, child := range c.children {
+		child.Reset()
+	}
+}
+
+type sequence struct{ composite }
+
+// Sequence returns a Node which ticks its children in order until one of them
+// fails or is running. It succeeds when all children succeed. A running child is
+// resumed on the next tick.
+func Sequence(children ...Node) Node {
+	return &sequence{composite{children: children}}
+}
+
+func (s *sequence) Tick() Status {
+	for s.current < len(s.children) {
+		switch s.children[s.current].Tick() {
+		case Running:
+			return Running
+		case Failure:
+			s.Reset()
+			return Failure
+		}
+		s.current++
+	}
+	s.Reset()
+	return Success
+}
+
+type selector struct{ composite }
+
+// Selector returns a Node which ticks its children in order until one of them
+// succeeds or is running. It fails when all children fail. A running child is
+// resumed on the next tick.
+func Selector(children ...Node) Node {
+	return &selector{composite{children: children}}
+}
+
+func (s *selector) Tick() Status {
+	for s.current < len(s.children) {
+		switch s.children[s.current].Tick() {
+		case Running:
+			return Running
+		case Success:
+			s.Reset()
+			return Success
+		}
+		s.current++
+	}
+	s.Reset()
+	return Failure
+}
+
+type parallel struct {
+	composite
+	required int
+	results  []Status
+}
+
+// Parallel returns a Node which ticks all unfinished children on every tick.
+// It succeeds once required children have succeeded, and fails once that is no
+// longer possible.
+func Parallel(required int, children ...Node) Node {
+	return &parallel{
+		composite: composite{children: children},
+		required:  required,
+		results:   make([]Status, len(children)),
+	}
+}
+
+func (p *parallel) Tick() Status {
+	succeeded, failed := 0, 0
+	for i, child := range p.children {
+		if p.results[i] == Running {
+			p.results[i] = child.Tick()
+		}
+		switch p.results[i] {
+		case Success:
+			succeeded++
+		case Failure:
+			failed++
+		}
+	}
+	switch {
+	case succeeded >= p.required:
+		p.Reset()
+		return Success
+	case len(p.children)-failed < p.required:
+		p.Reset()
+		return Failure
+	}
+	return Running
+}
+
+func (p *parallel) Reset() {
+	p.composite.Reset()
+	for i := range p.results {
+		p.results[i] = Running
+	}
+}
+
+type decorator struct {
+	child Node
+	tick  func(child Node) Status
+	reset func()
+}
+
+func (d *decorator) Tick() Status { return d.tick(d.child) }
+
+func (d *decorator) Reset() {
+	if d.reset != nil {
+		d.reset()
+	}
+	d.child.Reset()
+}
+
+// Invert returns a Node which swaps the Success and Failure of child.
+func Invert(child Node) Node {
+	return &decorator{child: child, tick: func(child Node) Status {
+		switch s := child.Tick(); s {
+		case Success:
+			return Failure
+		case Failure:
+			return Success
+		default:
+			return s
+		}
+	}}
+}
+
+// Succeed returns a Node which reports Success whenever child finishes.
+func Succeed(child Node) Node {
+	return &decorator{child: child, tick: func(child Node) Status {
+		if child.Tick() == Running {
+			return Running
+		}
+		return Success
+	}}
+}
+
+// Repeat returns a Node which runs child to completion n times, failing as soon
+// as child fails. A negative n repeats forever.
+func Repeat(n int, child Node) Node {
+	count := 0
+	d := &decorator{child: child, reset: func() { count = 0 }}
+	d.tick = func(child Node) Status {
+		switch child.Tick() {
+		case Running:
+			return Running
+		case Failure:
+			d.Reset()
+			return Failure
+		}
+		child.Reset()
+		count++
+		if n >= 0 && count >= n {
+			d.Reset()
+			return Success
+		}
+		return Running
+	}
+	return d
+}
+
+// Retry returns a Node which runs child again after a failure, up to n attempts.
+func Retry(n int, child Node) Node {
+	count := 0
+	d := &decorator{child: child, reset: func() { count = 0 }}
+	d.tick = func(child Node) Status {
+		switch child.Tick() {
+		case Running:
+			return Running
+		case Success:
+			d.Reset()
+			return Success
+		}
+		child.Reset()
+		count++
+		if count >= n {
+			d.Reset()
+			return Failure
+		}
+		return Running
+	}
+	return d
+}
+
+// Timeout returns a Node which fails if child is still running after d.
+func Timeout(d time.Duration, child Node) Node {
+	var started time.Time
+	dec := &decorator{child: child, reset: func() { started = time.Time{} }}
+	dec.tick = func(child Node) Status {
+		if started.IsZero() {
+			started = time.Now()
+		}
+		s := child.Tick()
+		if s == Running && time.Since(started) >= d {
+			dec.Reset()
+			return Failure
+		}
+		if s != Running {
+			started = time.Time{}
+		}
+		return s
+	}
+	return dec
+}
+
+// Tree is a behavior tree which can be ticked periodically from a Robot's work
+// function. A StatusChanged event is published when the root Status changes.
+type Tree struct {
+	root   Node
+	status Status
+	ticker gobot.Ticker
+	mutex  sync.Mutex
+	gobot.Eventer
+}
+
+// StatusChanged event
+const StatusChanged = "status-changed"
+
+// NewTree returns a new Tree with the given root Node.
+func NewTree(root Node) *Tree {
+	t := &Tree{
+		root:    root,
+		status:  Running,
+		Eventer: gobot.NewEventer(),
+	}
+	t.AddEvent(StatusChanged)
+	return t
+}
+
+// Tick ticks the root Node once and returns its Status.
+func (t *Tree) Tick() Status {
+	t.mutex.Lock()
+	s := t.root.Tick()
+	changed := s != t.status
+	t.status = s
+	t.mutex.Unlock()
+
+	if changed {
+		t.Publish(StatusChanged, s)
+	}
+	return s
+}
+
+// Status returns the Status of the latest tick
+func (t *Tree) Status() Status {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.status
+}
+
+// Start ticks the Tree every interval until Stop is called.
+func (t *Tree) Start(interval time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.ticker != nil {
+		return
+	}
+	t.ticker = gobot.Every(interval, func() {
+		t.Tick()
+	})
+}
+
+// Stop stops ticking the Tree and resets it.
+func (t *Tree) Stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.ticker != nil {
+		t.ticker.Stop()
+		t.ticker = nil
+	}
+	t.root.Reset()
+	t.status = Running
+}
@@ -0,0 +1,139 @@
+package behavior
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// script returns a Node which returns the given statuses in turn, repeating the
+// last one, and counts its ticks.
+func script(ticks *int, statuses ...Status) Node {
+	return Action(func() Status {
+		i := *ticks
+		*ticks++
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		return statuses[i]
+	})
+}
+
+func TestStatusString(t *testing.T) {
+	gobottest.Assert(t, Running.String(), "running")
+	gobottest.Assert(t, Success.String(), "success")
+	gobottest.Assert(t, Failure.String(), "failure")
+	gobottest.Assert(t, Status(9).String(), "unknown")
+}
+
+func TestLeaves(t *testing.T) {
+	gobottest.Assert(t, Do(func() error { return nil }).Tick(), Success)
+	gobottest.Assert(t, Do(func() error { return errors.New("fail") }).Tick(), Failure)
+	gobottest.Assert(t, Condition(func() bool { return true }).Tick(), Success)
+	gobottest.Assert(t, Condition(func() bool { return false }).Tick(), Failure)
+}
+
+func TestSequence(t *testing.T) {
+	var a, b int
+	s := Sequence(script(&a, Success), script(&b, Running, Success))
+	gobottest.Assert(t, s.Tick(), Running)
+	gobottest.Assert(t, s.Tick(), Success)
+	// the first child is not re-run while the second is running
+	gobottest.Assert(t, a, 1)
+	gobottest.Assert(t, b, 2)
+
+	var c int
+	s = Sequence(script(&c, Failure), script(&b, Success))
+	gobottest.Assert(t, s.Tick(), Failure)
+	gobottest.Assert(t, b, 2)
+}
+
+func TestSelector(t *testing.T) {
+	var a, b int
+	s := Selector(script(&a, Failure), script(&b, Running, Success))
+	gobottest.Assert(t, s.Tick(), Running)
+	gobottest.Assert(t, s.Tick(), Success)
+	gobottest.Assert(t, a, 1)
+
+	s = Selector(script(&a, Failure), script(&b, Failure))
+	gobottest.Assert(t, s.Tick(), Failure)
+}
+
+func TestParallel(t *testing.T) {
+	var a, b, c int
+	p := Parallel(2, script(&a, Success), script(&b, Running, Success), script(&c, Failure))
+	gobottest.Assert(t, p.Tick(), Running)
+	gobottest.Assert(t, p.Tick(), Success)
+	gobottest.Assert(t, a, 1)
+
+	a, b = 0, 0
+	p = Parallel(2, script(&a, Failure), script(&b, Running), script(&c, Failure))
+	gobottest.Assert(t, p.Tick(), Failure)
+}
+
+func TestDecorators(t *testing.T) {
+	var a int
+	gobottest.Assert(t, Invert(script(&a, Success)).Tick(), Failure)
+	gobottest.Assert(t, Invert(script(&a, Failure)).Tick(), Success)
+	gobottest.Assert(t, Invert(script(&a, Running)).Tick(), Running)
+	gobottest.Assert(t, Succeed(script(&a, Failure)).Tick(), Success)
+	gobottest.Assert(t, Succeed(script(&a, Running)).Tick(), Running)
+
+	a = 0
+	r := Repeat(3, script(&a, Success))
+	gobottest.Assert(t, r.Tick(), Running)
+	gobottest.Assert(t, r.Tick(), Running)
+	gobottest.Assert(t, r.Tick(), Success)
+	gobottest.Assert(t, a, 3)
+	gobottest.Assert(t, Repeat(3, script(&a, Failure)).Tick(), Failure)
+
+	a = 0
+	r = Retry(2, script(&a, Failure))
+	gobottest.Assert(t, r.Tick(), Running)
+	gobottest.Assert(t, r.Tick(), Failure)
+	a = 0
+	gobottest.Assert(t, Retry(2, script(&a, Failure, Success)).Tick(), Running)
+
+	to := Timeout(5*time.Millisecond, script(&a, Running))
+	gobottest.Assert(t, to.Tick(), Running)
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, to.Tick(), Failure)
+}
+
+func TestTree(t *testing.T) {
+	var a int
+	tree := NewTree(script(&a, Running, Running, Success))
+	events := tree.Subscribe()
+
+	gobottest.Assert(t, tree.Tick(), Running)
+	gobottest.Assert(t, tree.Status(), Running)
+	gobottest.Assert(t, tree.Tick(), Running)
+	gobottest.Assert(t, tree.Tick(), Success)
+
+	evt := <-events
+	gobottest.Assert(t, evt.Name, StatusChanged)
+	gobottest.Assert(t, evt.Data, Success)
+}
+
+func TestTreeStartStop(t *testing.T) {
+	sem := make(chan bool, 1)
+	tree := NewTree(Action(func() Status {
+		select {
+		case sem <- true:
+		default:
+		}
+		return Running
+	}))
+	tree.Start(time.Millisecond)
+	tree.Start(time.Millisecond)
+
+	select {
+	case <-sem:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Tree was not ticked")
+	}
+	tree.Stop()
+	gobottest.Assert(t, tree.Status(), Running)
+}
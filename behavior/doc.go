@@ -0,0 +1,46 @@
+/*
+Package behavior provides a small behavior tree implementation for composing
+autonomous robot behaviors out of simple driver calls.
+
+Example:
+
+    package main
+
+    import (
+    	"time"
+
+    	"gobot.io/x/gobot"
+    	"gobot.io/x/gobot/behavior"
+    	"gobot.io/x/gobot/drivers/gpio"
+    	"gobot.io/x/gobot/platforms/firmata"
+    )
+
+    func main() {
+    	firmataAdaptor := firmata.NewAdaptor("/dev/ttyACM0")
+    	button := gpio.NewButtonDriver(firmataAdaptor, "2")
+    	led := gpio.NewLedDriver(firmataAdaptor, "13")
+
+    	tree := behavior.NewTree(
+    		behavior.Selector(
+    			behavior.Sequence(
+    				behavior.Condition(func() bool { return button.Active }),
+    				behavior.Do(led.On),
+    			),
+    			behavior.Do(led.Off),
+    		),
+    	)
+
+    	work := func() {
+    		tree.Start(100 * time.Millisecond)
+    	}
+
+    	robot := gobot.NewRobot("bot",
+    		[]gobot.Connection{firmataAdaptor},
+    		[]gobot.Device{button, led},
+    		work,
+    	)
+
+    	robot.Start()
+    }
+*/
+package behavior // import "gobot.io/x/gobot/behavior"
@@ -0,0 +1,123 @@
+package gobot
+
+import (
+	"sync"
+	"time"
+)
+
+// CalmEventer is an Eventer republishing the events of another Eventer, the
+// noisy events of one name calmed down, see Debounce and Throttle. The
+// CalmEventers can be chained to calm several events of an Eventer.
+type CalmEventer struct {
+	Eventer
+	source Eventer
+	name   string
+	events eventChannel
+	// limit returns the event to publish, if any, after evt arrived, or
+	// after the timer fired when evt is nil. A positive wait (re)starts the
+	// timer.
+	limit func(evt *Event, now time.Time) (publish *Event, wait time.Duration)
+	done  chan bool
+	once  sync.Once
+}
+
+// Debounce returns a CalmEventer publishing the events of e, except the
+// events named name which are only published once they have settled: the
+// last event of a burst of events less than window apart is published,
+// window after it. For example a button chattering while pressed publishes
+// a single event.
+func Debounce(e Eventer, name string, window time.Duration) *CalmEventer {
+	var pending *Event
+	return newCalmEventer(e, name, func(evt *Event, now time.Time) (*Event, time.Duration) {
+		if evt == nil {
+			evt, pending = pending, nil
+			return evt, 0
+		}
+		pending = evt
+		return nil, window
+	})
+}
+
+// Throttle returns a CalmEventer publishing the events of e, except the
+// events named name which are published at most maxRate times per second.
+// The first event is published at once, the following ones are dropped
+// until the end of the period, when the last one is published. For example
+// an analog sensor read every millisecond publishes its latest reading a few
+// times per second.
+func Throttle(e Eventer, name string, maxRate float64) *CalmEventer {
+	period := time.Duration(float64(time.Second) / maxRate)
+	var pending *Event
+	var last time.Time
+	return newCalmEventer(e, name, func(evt *Event, now time.Time) (*Event, time.Duration) {
+		if evt == nil {
+			evt, pending = pending, nil
+			if evt != nil {
+				last = now
+			}
+			return evt, 0
+		}
+		if pending == nil && (last.IsZero() || now.Sub(last) >= period) {
+			last = now
+			return evt, 0
+		}
+		var wait time.Duration
+		if pending == nil {
+			wait = last.Add(period).Sub(now)
+		}
+		pending = evt
+		return nil, wait
+	})
+}
+
+func newCalmEventer(e Eventer, name string, limit func(*Event, time.Time) (*Event, time.Duration)) *CalmEventer {
+	c := &CalmEventer{
+		Eventer: NewEventer(),
+		source:  e,
+		name:    name,
+		events:  e.Subscribe(),
+		limit:   limit,
+		done:    make(chan bool),
+	}
+	for n := range e.Events() {
+		c.AddEvent(n)
+	}
+	go c.run()
+	return c
+}
+
+// Close stops republishing the events, dropping the pending one if any
+func (c *CalmEventer) Close() {
+	c.once.Do(func() {
+		c.source.Unsubscribe(c.events)
+		close(c.done)
+	})
+}
+
+// run republishes the events until Close
+func (c *CalmEventer) run() {
+	clock := DefaultClock()
+	// timer fires when the pending event, if any, is due
+	var timer <-chan time.Time
+	for {
+		var evt *Event
+		select {
+		case evt = <-c.events:
+			if evt.Name != c.name {
+				c.Publish(evt.Name, evt.Data)
+				continue
+			}
+		case <-timer:
+			timer = nil
+		case <-c.done:
+			return
+		}
+
+		publish, wait := c.limit(evt, clock.Now())
+		if publish != nil {
+			c.Publish(publish.Name, publish.Data)
+		}
+		if wait > 0 {
+			timer = clock.After(wait)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package gobot
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// nextEvent returns the next event of events, nil if none comes in time
+func nextEvent(events eventChannel) *Event {
+	select {
+	case evt := <-events:
+		return evt
+	case <-time.After(50 * time.Millisecond):
+		return nil
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	e := NewEventer()
+	e.AddEvent("push")
+	d := Debounce(e, "push", 20*time.Millisecond)
+	defer d.Close()
+	gobottest.Assert(t, d.Event("push"), "push")
+	events := d.Subscribe()
+
+	e.Publish("push", 1)
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Millisecond)
+	e.Publish("push", 2)
+	clock.BlockUntil(2)
+	clock.Advance(10 * time.Millisecond)
+	gobottest.Assert(t, nextEvent(events), (*Event)(nil))
+
+	// the other events are not delayed
+	e.Publish("release", 3)
+	gobottest.Assert(t, nextEvent(events).Data, 3)
+
+	clock.Advance(10 * time.Millisecond)
+	evt := nextEvent(events)
+	gobottest.Assert(t, evt.Name, "push")
+	gobottest.Assert(t, evt.Data, 2)
+	gobottest.Assert(t, nextEvent(events), (*Event)(nil))
+}
+
+func TestThrottle(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	e := NewEventer()
+	th := Throttle(e, "data", 10)
+	events := th.Subscribe()
+
+	e.Publish("data", 1)
+	gobottest.Assert(t, nextEvent(events).Data, 1)
+	e.Publish("data", 2)
+	e.Publish("data", 3)
+	clock.BlockUntil(1)
+	gobottest.Assert(t, nextEvent(events), (*Event)(nil))
+
+	// the last event is published at the end of the period
+	clock.Advance(100 * time.Millisecond)
+	gobottest.Assert(t, nextEvent(events).Data, 3)
+	gobottest.Assert(t, nextEvent(events), (*Event)(nil))
+
+	clock.Advance(100 * time.Millisecond)
+	e.Publish("data", 4)
+	gobottest.Assert(t, nextEvent(events).Data, 4)
+
+	th.Close()
+	th.Close()
+	e.Publish("data", 5)
+	gobottest.Assert(t, nextEvent(events), (*Event)(nil))
+	gobottest.Assert(t, e.Metrics().Subscribers, 0)
+}
+
+func TestCalmEventerChain(t *testing.T) {
+	e := NewEventer()
+	c := Throttle(Debounce(e, "push", time.Millisecond), "data", 1000)
+	events := c.Subscribe()
+
+	e.Publish("push", true)
+	e.Publish("data", 1.0)
+	received := map[string]interface{}{}
+	for i := 0; i < 2; i++ {
+		if evt := nextEvent(events); evt != nil {
+			received[evt.Name] = evt.Data
+		}
+	}
+	gobottest.Assert(t, received, map[string]interface{}{"push": true, "data": 1.0})
+}
@@ -0,0 +1,136 @@
+package gobot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TemperatureSensor is implemented by the Devices measuring a temperature.
+type TemperatureSensor interface {
+	// Temperature returns the current temperature, in celsius degrees
+	Temperature() (float32, error)
+}
+
+// PressureSensor is implemented by the Devices measuring a barometric
+// pressure.
+type PressureSensor interface {
+	// Pressure returns the current pressure, in the unit documented by the
+	// Device, pascals for most of them
+	Pressure() (float32, error)
+}
+
+// HumiditySensor is implemented by the Devices measuring a relative humidity.
+type HumiditySensor interface {
+	// Humidity returns the current relative humidity, in percent
+	Humidity() (float32, error)
+}
+
+// DistanceSensor is implemented by the Devices measuring a distance.
+type DistanceSensor interface {
+	// Distance returns the current distance, in centimeters
+	Distance() (int, error)
+}
+
+// HeadingSensor is implemented by the Devices measuring a compass heading.
+type HeadingSensor interface {
+	// Heading returns the current heading, in degrees
+	Heading() (uint16, error)
+}
+
+// ColorSensor is implemented by the Devices measuring the color of light.
+type ColorSensor interface {
+	// Color returns the current red, green, blue and clear light intensities
+	Color() (r, g, b, c uint16, err error)
+}
+
+// GestureSensor is implemented by the Devices recognizing gestures.
+type GestureSensor interface {
+	// Gesture returns the last gesture recognized, such as "up" or "left",
+	// or an empty string when none was
+	Gesture() (string, error)
+}
+
+// Display is implemented by the Devices displaying text or graphics.
+type Display interface {
+	// Clear clears the display
+	Clear() error
+}
+
+// TextDisplay is implemented by the Displays showing lines of characters.
+type TextDisplay interface {
+	Display
+	// Write shows message at the cursor position
+	Write(message string) error
+	// SetPosition moves the cursor to pos, counted in characters from the
+	// start of the first line
+	SetPosition(pos int) error
+}
+
+// MotorController is implemented by the Devices driving a DC motor.
+type MotorController interface {
+	// Speed sets the speed of the motor
+	Speed(value byte) error
+	// Forward runs the motor forward at speed
+	Forward(speed byte) error
+	// Backward runs the motor backward at speed
+	Backward(speed byte) error
+}
+
+// ReadTemperature returns the current Temperature of s.
+func ReadTemperature(s TemperatureSensor) (Temperature, error) {
+	t, err := s.Temperature()
+	return Celsius(float64(t)), err
+}
+
+// ReadDistance returns the current Distance measured by s.
+func ReadDistance(s DistanceSensor) (Distance, error) {
+	d, err := s.Distance()
+	return Distance(d) * Centimeter, err
+}
+
+// ReadHeading returns the current compass heading of s, clockwise from the
+// north.
+func ReadHeading(s HeadingSensor) (Angle, error) {
+	h, err := s.Heading()
+	return Angle(h) * Degree, err
+}
+
+// capabilities are the standard capabilities by name, as reported by
+// CapabilitiesOf.
+var capabilities = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"TemperatureSensor", reflect.TypeOf((*TemperatureSensor)(nil)).Elem()},
+	{"PressureSensor", reflect.TypeOf((*PressureSensor)(nil)).Elem()},
+	{"HumiditySensor", reflect.TypeOf((*HumiditySensor)(nil)).Elem()},
+	{"DistanceSensor", reflect.TypeOf((*DistanceSensor)(nil)).Elem()},
+	{"HeadingSensor", reflect.TypeOf((*HeadingSensor)(nil)).Elem()},
+	{"ColorSensor", reflect.TypeOf((*ColorSensor)(nil)).Elem()},
+	{"GestureSensor", reflect.TypeOf((*GestureSensor)(nil)).Elem()},
+	{"Display", reflect.TypeOf((*Display)(nil)).Elem()},
+	{"TextDisplay", reflect.TypeOf((*TextDisplay)(nil)).Elem()},
+	{"MotorController", reflect.TypeOf((*MotorController)(nil)).Elem()},
+}
+
+// CapabilitiesOf returns the names of the standard capabilities implemented
+// by device, e.g. "TemperatureSensor".
+func CapabilitiesOf(device Device) []string {
+	names := []string{}
+	t := reflect.TypeOf(device)
+	for _, c := range capabilities {
+		if t.Implements(c.typ) {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// capabilityType returns the interface type capability points to.
+func capabilityType(capability interface{}) reflect.Type {
+	t := reflect.TypeOf(capability)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("capability must be a pointer to an interface, e.g. (*gobot.TemperatureSensor)(nil), not %v", t))
+	}
+	return t.Elem()
+}
@@ -0,0 +1,65 @@
+package gobot
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testThermometer struct {
+	*testDriver
+}
+
+func (t *testThermometer) Temperature() (float32, error) { return 21.5, nil }
+func (t *testThermometer) Humidity() (float32, error)    { return 40, nil }
+
+func TestCapabilitiesOf(t *testing.T) {
+	d := newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "Device1", "0")
+	gobottest.Assert(t, CapabilitiesOf(d), []string{})
+	gobottest.Assert(t, CapabilitiesOf(&testThermometer{d}), []string{"TemperatureSensor", "HumiditySensor"})
+}
+
+func TestRobotDevicesByCapability(t *testing.T) {
+	r := newTestRobot("Robot1")
+	thermometer := &testThermometer{newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "Thermometer", "3")}
+	r.AddDevice(thermometer)
+
+	devices := r.DevicesByCapability((*TemperatureSensor)(nil))
+	gobottest.Assert(t, devices.Len(), 1)
+	gobottest.Assert(t, (*devices)[0], Device(thermometer))
+	temperature, _ := (*devices)[0].(TemperatureSensor).Temperature()
+	gobottest.Assert(t, temperature, float32(21.5))
+
+	gobottest.Assert(t, r.DevicesByCapability((*Driver)(nil)).Len(), 4)
+	gobottest.Assert(t, r.DevicesByCapability((*MotorController)(nil)).Len(), 0)
+}
+
+func TestRobotDevicesByCapabilityNotInterface(t *testing.T) {
+	defer func() {
+		gobottest.Refute(t, recover(), nil)
+	}()
+	newTestRobot("Robot1").DevicesByCapability(TemperatureSensor(nil))
+}
+
+type testRanger struct {
+	*testDriver
+}
+
+func (r *testRanger) Distance() (int, error)   { return 42, nil }
+func (r *testRanger) Heading() (uint16, error) { return 270, nil }
+
+func TestReadQuantities(t *testing.T) {
+	d := newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "Device1", "0")
+
+	temperature, err := ReadTemperature(&testThermometer{d})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, temperature, Celsius(21.5))
+
+	distance, err := ReadDistance(&testRanger{d})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, distance.String(), "0.42m")
+
+	heading, err := ReadHeading(&testRanger{d})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, heading.Degrees(), 270.0)
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/hil"
+)
+
+// The diagnostic commands use the generic linux adaptor of the hil package:
+// pins are sysfs GPIO numbers, PWM pins are channels of pwmchip0 and I2C
+// buses are /dev/i2c-N.
+
+var busFlag = cli.IntFlag{
+	Name:  "bus, b",
+	Value: 1,
+	Usage: "I2C bus number, for /dev/i2c-N",
+}
+
+// I2c returns the i2c diagnostic command
+func I2c() cli.Command {
+	return cli.Command{
+		Name:  "i2c",
+		Usage: "Scan I2C buses and dump the registers of I2C devices",
+		Subcommands: []cli.Command{
+			{
+				Name:  "scan",
+				Usage: "List the addresses answering on a bus",
+				Flags: []cli.Flag{busFlag},
+				Action: func(c *cli.Context) {
+					a := hil.NewSysfsAdaptor()
+					defer a.Finalize()
+					if _, err := scanI2c(os.Stdout, a, c.Int("bus")); err != nil {
+						fmt.Println(err)
+					}
+				},
+			},
+			{
+				Name:      "dump",
+				Usage:     "Print the registers of a device",
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					busFlag,
+					cli.StringFlag{
+						Name:  "chip, c",
+						Usage: "chip annotating the registers, detected by default: " + strings.Join(chipNames(), ", "),
+					},
+				},
+				Action: func(c *cli.Context) {
+					address, err := parseAddress(c.Args().First())
+					if err != nil {
+						fmt.Println(err)
+						return
+					}
+					a := hil.NewSysfsAdaptor()
+					defer a.Finalize()
+					conn, err := a.GetConnection(address, c.Int("bus"))
+					if err != nil {
+						fmt.Println(err)
+						return
+					}
+					if err := dumpI2c(os.Stdout, conn, address, c.String("chip")); err != nil {
+						fmt.Println(err)
+					}
+				},
+			},
+		},
+	}
+}
+
+// Gpio returns the gpio diagnostic command
+func Gpio() cli.Command {
+	return cli.Command{
+		Name:  "gpio",
+		Usage: "Read, write and toggle GPIO pins",
+		Subcommands: []cli.Command{
+			{
+				Name:      "read",
+				Usage:     "Print the value of a pin, or its changes with --watch",
+				ArgsUsage: "<pin>",
+				Flags: []cli.Flag{
+					cli.BoolFlag{Name: "watch, w", Usage: "print the changes until interrupted"},
+					cli.DurationFlag{Name: "interval, i", Value: 10 * time.Millisecond, Usage: "polling interval when watching"},
+				},
+				Action: func(c *cli.Context) {
+					a := hil.NewSysfsAdaptor()
+					defer a.Finalize()
+					count := 1
+					if c.Bool("watch") {
+						count = -1
+					}
+					if err := readGpio(os.Stdout, a, c.Args().First(), count, c.Duration("interval")); err != nil {
+						fmt.Println(err)
+					}
+				},
+			},
+			{
+				Name:      "write",
+				Usage:     "Set a pin to 0 or 1",
+				ArgsUsage: "<pin> <0|1>",
+				Action: func(c *cli.Context) {
+					level, err := strconv.ParseUint(c.Args().Get(1), 10, 1)
+					if err != nil {
+						fmt.Println("Please provide a level of 0 or 1.")
+						return
+					}
+					// the pin is left exported to keep its level
+					if err := hil.NewSysfsAdaptor().DigitalWrite(c.Args().First(), byte(level)); err != nil {
+						fmt.Println(err)
+					}
+				},
+			},
+			{
+				Name:      "toggle",
+				Usage:     "Toggle a pin, to find it with a LED or a meter",
+				ArgsUsage: "<pin>",
+				Flags: []cli.Flag{
+					cli.IntFlag{Name: "count, n", Value: 10, Usage: "number of toggles"},
+					cli.DurationFlag{Name: "interval, i", Value: 500 * time.Millisecond, Usage: "time between toggles"},
+				},
+				Action: func(c *cli.Context) {
+					a := hil.NewSysfsAdaptor()
+					defer a.Finalize()
+					if err := toggleGpio(os.Stdout, a, c.Args().First(), c.Int("count"), c.Duration("interval")); err != nil {
+						fmt.Println(err)
+					}
+				},
+			},
+		},
+	}
+}
+
+// Pwm returns the pwm diagnostic command
+func Pwm() cli.Command {
+	return cli.Command{
+		Name:  "pwm",
+		Usage: "Write and sweep PWM and servo pins",
+		Subcommands: []cli.Command{
+			{
+				Name:      "write",
+				Usage:     "Set the duty cycle of a pin, from 0 to 255, or the angle of a servo with --servo",
+				ArgsUsage: "<pin> <value>",
+				Flags: []cli.Flag{
+					cli.BoolFlag{Name: "servo, s", Usage: "write a servo angle, from 0 to 180"},
+				},
+				Action: func(c *cli.Context) {
+					val, err := strconv.ParseUint(c.Args().Get(1), 10, 8)
+					if err != nil {
+						fmt.Println("Please provide a value from 0 to 255.")
+						return
+					}
+					a := hil.NewSysfsAdaptor()
+					if c.Bool("servo") {
+						err = a.ServoWrite(c.Args().First(), byte(val))
+					} else {
+						err = a.PwmWrite(c.Args().First(), byte(val))
+					}
+					if err != nil {
+						fmt.Println(err)
+					}
+				},
+			},
+			{
+				Name:      "sweep",
+				Usage:     "Sweep the duty cycle of a pin up and down, or the angle of a servo with --servo",
+				ArgsUsage: "<pin>",
+				Flags: []cli.Flag{
+					cli.BoolFlag{Name: "servo, s", Usage: "sweep a servo from 0 to 180 degrees"},
+					cli.IntFlag{Name: "step", Value: 5, Usage: "change of value at each step"},
+					cli.DurationFlag{Name: "interval, i", Value: 20 * time.Millisecond, Usage: "time between steps"},
+					cli.IntFlag{Name: "count, n", Value: 1, Usage: "number of sweeps"},
+				},
+				Action: func(c *cli.Context) {
+					a := hil.NewSysfsAdaptor()
+					defer a.Finalize()
+					var write func(string, byte) error = a.PwmWrite
+					max := 255
+					if c.Bool("servo") {
+						write, max = a.ServoWrite, 180
+					}
+					for i := 0; i < c.Int("count"); i++ {
+						if err := sweep(write, c.Args().First(), max, c.Int("step"), c.Duration("interval")); err != nil {
+							fmt.Println(err)
+							return
+						}
+					}
+				},
+			},
+		},
+	}
+}
+
+// knownChip describes the registers of a device supported by the i2c package
+type knownChip struct {
+	name      string
+	addresses []int
+	// idRegister holds id on the devices which can be identified, so that
+	// devices sharing an address can be told apart
+	idRegister uint8
+	id         uint8
+	identified bool
+	registers  map[uint8]string
+}
+
+var knownChips = []knownChip{
+	{
+		name: "mpu6050", addresses: []int{0x68, 0x69},
+		idRegister: 0x75, id: 0x68, identified: true,
+		registers: map[uint8]string{
+			0x19: "SMPLRT_DIV", 0x1A: "CONFIG", 0x1B: "GYRO_CONFIG", 0x1C: "ACCEL_CONFIG",
+			0x3B: "ACCEL_XOUT_H", 0x3D: "ACCEL_YOUT_H", 0x3F: "ACCEL_ZOUT_H", 0x41: "TEMP_OUT_H",
+			0x43: "GYRO_XOUT_H", 0x45: "GYRO_YOUT_H", 0x47: "GYRO_ZOUT_H",
+			0x6B: "PWR_MGMT_1", 0x75: "WHO_AM_I",
+		},
+	},
+	{
+		name: "bmp180", addresses: []int{0x77},
+		idRegister: 0xD0, id: 0x55, identified: true,
+		registers: map[uint8]string{
+			0xAA: "AC1_MSB", 0xD0: "ID", 0xE0: "SOFT_RESET",
+			0xF4: "CTRL_MEAS", 0xF6: "OUT_MSB", 0xF7: "OUT_LSB", 0xF8: "OUT_XLSB",
+		},
+	},
+	{
+		name: "bmp280", addresses: []int{0x76, 0x77},
+		idRegister: 0xD0, id: 0x58, identified: true,
+		registers: map[uint8]string{
+			0x88: "CALIB00", 0xD0: "ID", 0xE0: "RESET", 0xF3: "STATUS",
+			0xF4: "CTRL_MEAS", 0xF5: "CONFIG", 0xF7: "PRESS_MSB", 0xFA: "TEMP_MSB",
+		},
+	},
+	{
+		name: "bme280", addresses: []int{0x76, 0x77},
+		idRegister: 0xD0, id: 0x60, identified: true,
+		registers: map[uint8]string{
+			0x88: "CALIB00", 0xA1: "CALIB_H1", 0xD0: "ID", 0xE0: "RESET", 0xE1: "CALIB_H2",
+			0xF2: "CTRL_HUM", 0xF3: "STATUS", 0xF4: "CTRL_MEAS", 0xF5: "CONFIG",
+			0xF7: "PRESS_MSB", 0xFA: "TEMP_MSB", 0xFD: "HUM_MSB",
+		},
+	},
+	{
+		name: "l3gd20h", addresses: []int{0x6A, 0x6B},
+		idRegister: 0x0F, id: 0xD7, identified: true,
+		registers: map[uint8]string{
+			0x0F: "WHO_AM_I", 0x20: "CTRL1", 0x23: "CTRL4", 0x26: "OUT_TEMP",
+			0x27: "STATUS", 0x28: "OUT_X_L", 0x2A: "OUT_Y_L", 0x2C: "OUT_Z_L",
+		},
+	},
+	{
+		name: "pca9685", addresses: []int{0x40},
+		registers: map[uint8]string{
+			0x00: "MODE1", 0x01: "MODE2", 0x02: "SUBADR1", 0x03: "SUBADR2", 0x04: "SUBADR3",
+			0x06: "LED0_ON_L", 0x07: "LED0_ON_H", 0x08: "LED0_OFF_L", 0x09: "LED0_OFF_H",
+			0xFA: "ALL_LED_ON_L", 0xFB: "ALL_LED_ON_H", 0xFC: "ALL_LED_OFF_L", 0xFD: "ALL_LED_OFF_H",
+			0xFE: "PRE_SCALE",
+		},
+	},
+	{
+		name: "mcp23017", addresses: []int{0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27},
+		registers: map[uint8]string{
+			0x00: "IODIRA", 0x01: "IODIRB", 0x02: "IPOLA", 0x03: "IPOLB",
+			0x04: "GPINTENA", 0x05: "GPINTENB", 0x0A: "IOCON", 0x0C: "GPPUA", 0x0D: "GPPUB",
+			0x12: "GPIOA", 0x13: "GPIOB", 0x14: "OLATA", 0x15: "OLATB",
+		},
+	},
+	{
+		name: "mma7660", addresses: []int{0x4C},
+		registers: map[uint8]string{
+			0x00: "XOUT", 0x01: "YOUT", 0x02: "ZOUT", 0x03: "TILT", 0x04: "SRST",
+			0x05: "SPCNT", 0x06: "INTSU", 0x07: "MODE", 0x08: "SR", 0x09: "PDET", 0x0A: "PD",
+		},
+	},
+	{
+		name: "drv2605l", addresses: []int{0x5A},
+		registers: map[uint8]string{
+			0x00: "STATUS", 0x01: "MODE", 0x02: "RTP_INPUT", 0x03: "LIBRARY", 0x04: "WAV_SEQ1",
+			0x0C: "GO", 0x16: "RATED_VOLTAGE", 0x17: "OD_CLAMP", 0x1A: "FEEDBACK",
+			0x1B: "CONTROL1", 0x1C: "CONTROL2", 0x1D: "CONTROL3", 0x1E: "CONTROL4", 0x21: "VBAT",
+		},
+	},
+}
+
+func chipNames() []string {
+	names := []string{}
+	for _, c := range knownChips {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// detectChip returns the chip named name, or else the chip found at address:
+// the identifiable chips whose id register matches, then the only chip
+// which cannot be identified at this address.
+func detectChip(conn i2c.Connection, address int, name string) (*knownChip, error) {
+	if name != "" {
+		for i := range knownChips {
+			if knownChips[i].name == name {
+				return &knownChips[i], nil
+			}
+		}
+		return nil, errors.New("No chip found with the name " + name)
+	}
+
+	var candidates []*knownChip
+	for i := range knownChips {
+		c := &knownChips[i]
+		for _, a := range c.addresses {
+			if a != address {
+				continue
+			}
+			if !c.identified {
+				candidates = append(candidates, c)
+			} else if id, err := conn.ReadByteData(c.idRegister); err == nil && id == c.id {
+				return c, nil
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return nil, nil
+}
+
+func parseAddress(s string) (int, error) {
+	address, err := strconv.ParseUint(s, 0, 7)
+	if err != nil {
+		return 0, fmt.Errorf("Please provide an address from 0x03 to 0x77, got %q", s)
+	}
+	return int(address), nil
+}
+
+// scanI2c prints the table of the addresses answering a read on bus, like
+// i2cdetect, and the knownChips which may be found at them. The last error is
+// returned when no device answered, as the bus may not exist.
+func scanI2c(w io.Writer, connector i2c.Connector, bus int) ([]int, error) {
+	found := []int{}
+	var lastErr error
+	fmt.Fprintln(w, "     0  1  2  3  4  5  6  7  8  9  a  b  c  d  e  f")
+	for row := 0; row < 0x80; row += 0x10 {
+		fmt.Fprintf(w, "%02x:", row)
+		for address := row; address < row+0x10; address++ {
+			if address < 0x03 || address > 0x77 {
+				fmt.Fprint(w, "   ")
+				continue
+			}
+			conn, err := connector.GetConnection(address, bus)
+			if err == nil {
+				_, err = conn.ReadByte()
+			}
+			if err != nil {
+				lastErr = err
+				fmt.Fprint(w, " --")
+				continue
+			}
+			found = append(found, address)
+			fmt.Fprintf(w, " %02x", address)
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, address := range found {
+		names := []string{}
+		for _, c := range knownChips {
+			for _, a := range c.addresses {
+				if a == address {
+					names = append(names, c.name)
+				}
+			}
+		}
+		if len(names) > 0 {
+			fmt.Fprintf(w, "0x%02x: %s\n", address, strings.Join(names, ", "))
+		}
+	}
+	if len(found) == 0 {
+		return found, lastErr
+	}
+	return found, nil
+}
+
+// dumpI2c prints the table of the 256 registers of the device, unreadable
+// registers showing as XX, followed by the known registers of its chip.
+func dumpI2c(w io.Writer, conn i2c.Connection, address int, name string) error {
+	c, err := detectChip(conn, address, name)
+	if err != nil {
+		return err
+	}
+
+	values := map[uint8]uint8{}
+	fmt.Fprintln(w, "     0  1  2  3  4  5  6  7  8  9  a  b  c  d  e  f")
+	for row := 0; row < 0x100; row += 0x10 {
+		fmt.Fprintf(w, "%02x:", row)
+		for reg := row; reg < row+0x10; reg++ {
+			val, err := conn.ReadByteData(uint8(reg))
+			if err != nil {
+				fmt.Fprint(w, " XX")
+				continue
+			}
+			values[uint8(reg)] = val
+			fmt.Fprintf(w, " %02x", val)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if c == nil {
+		return nil
+	}
+	fmt.Fprintf(w, "\n%s registers:\n", c.name)
+	regs := []int{}
+	for reg := range c.registers {
+		regs = append(regs, int(reg))
+	}
+	sort.Ints(regs)
+	for _, reg := range regs {
+		if val, ok := values[uint8(reg)]; ok {
+			fmt.Fprintf(w, "  0x%02x %-14s 0x%02x\n", reg, c.registers[uint8(reg)], val)
+		} else {
+			fmt.Fprintf(w, "  0x%02x %-14s XX\n", reg, c.registers[uint8(reg)])
+		}
+	}
+	return nil
+}
+
+// readGpio prints the value of pin, then its changes until count values
+// have been printed, forever with a negative count.
+func readGpio(w io.Writer, a gpio.DigitalReader, pin string, count int, interval time.Duration) error {
+	last := -1
+	for count != 0 {
+		val, err := a.DigitalRead(pin)
+		if err != nil {
+			return err
+		}
+		if val != last {
+			last = val
+			fmt.Fprintf(w, "%s %d\n", time.Now().Format("15:04:05.000"), val)
+			count--
+		}
+		if count != 0 {
+			time.Sleep(interval)
+		}
+	}
+	return nil
+}
+
+// toggleGpio toggles pin count times, starting high and ending low.
+func toggleGpio(w io.Writer, a gpio.DigitalWriter, pin string, count int, interval time.Duration) error {
+	for i := 0; i < count; i++ {
+		level := byte(1 - i%2)
+		if err := a.DigitalWrite(pin, level); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s %d\n", pin, level)
+		time.Sleep(interval)
+	}
+	return a.DigitalWrite(pin, 0)
+}
+
+// sweep writes the values from 0 to max then back to 0, step by step.
+func sweep(write func(string, byte) error, pin string, max int, step int, interval time.Duration) error {
+	if step <= 0 {
+		return errors.New("Please provide a positive step.")
+	}
+	values := []int{}
+	for val := 0; val < max; val += step {
+		values = append(values, val)
+	}
+	for val := max; val > 0; val -= step {
+		values = append(values, val)
+	}
+	values = append(values, 0)
+
+	for _, val := range values {
+		if err := write(pin, byte(val)); err != nil {
+			return err
+		}
+		time.Sleep(interval)
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -16,6 +18,14 @@ type config struct {
 	UpperName   string
 	FirstLetter string
 	Example     string
+	Kind        string
+	// Qualifier prefixes the identifiers of the bus package in generated
+	// drivers which are not part of it, such as "i2c."
+	Qualifier string
+	// TestPackage is the package of the generated tests, and Driver
+	// prefixes the identifiers of the driver in them when it is external
+	TestPackage string
+	Driver      string
 	dir         string
 }
 
@@ -25,7 +35,7 @@ func Generate() cli.Command {
 		Usage: "Generate new Gobot adaptors, drivers, and platforms",
 		Action: func(c *cli.Context) {
 			valid := false
-			for _, s := range []string{"adaptor", "driver", "platform"} {
+			for _, s := range []string{"adaptor", "driver", "platform", "i2c", "gpio", "spi", "board"} {
 				if s == c.Args().First() {
 					valid = true
 				}
@@ -36,6 +46,10 @@ func Generate() cli.Command {
 				fmt.Println(" gobot generate adaptor <name> [package] # generate a new Gobot adaptor")
 				fmt.Println(" gobot generate driver  <name> [package] # generate a new Gobot driver")
 				fmt.Println(" gobot generate platform <name> [package] # generate a new Gobot platform")
+				fmt.Println(" gobot generate i2c <name> [package] # generate a new i2c driver, in package i2c by default")
+				fmt.Println(" gobot generate gpio <name> [package] # generate a new gpio driver, in package gpio by default")
+				fmt.Println(" gobot generate spi <name> [package] # generate a new spi driver, in package spi by default")
+				fmt.Println(" gobot generate board <name> [package] # generate a new linux board adaptor")
 				return
 			}
 
@@ -46,6 +60,10 @@ func Generate() cli.Command {
 
 			name := strings.ToLower(c.Args()[1])
 			packageName := name
+			switch c.Args().First() {
+			case "i2c", "gpio", "spi":
+				packageName = c.Args().First()
+			}
 			if len(c.Args()) > 2 {
 				packageName = strings.ToLower(c.Args()[2])
 			}
@@ -68,6 +86,10 @@ func Generate() cli.Command {
 				if err := generateDriver(cfg); err != nil {
 					fmt.Println(err)
 				}
+			case "i2c", "gpio", "spi", "board":
+				if err := generateScaffold(scaffold(c.Args().First(), cfg)); err != nil {
+					fmt.Println(err)
+				}
 			case "platform":
 				pwd, err := os.Getwd()
 				if err != nil {
@@ -101,18 +123,24 @@ func generate(c config, file string, tmpl string) error {
 	fileLocation := c.dir + "/" + file
 	fmt.Println("Creating", fileLocation)
 
-	f, err := os.Create(fileLocation)
-	defer f.Close()
+	t, err := template.New("").Parse(tmpl)
 	if err != nil {
 		return err
 	}
 
-	t, err := template.New("").Parse(tmpl)
-	if err != nil {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, c); err != nil {
 		return err
 	}
 
-	return t.Execute(f, c)
+	out := buf.Bytes()
+	if strings.HasSuffix(file, ".go") {
+		if formatted, err := format.Source(out); err == nil {
+			out = formatted
+		}
+	}
+
+	return ioutil.WriteFile(fileLocation, out, 0644)
 }
 
 func generateDriver(c config) error {
@@ -16,6 +16,11 @@ func main() {
 	app.Usage = "Command Line Utility for generating new Gobot adaptors, drivers, and platforms"
 	app.Commands = []cli.Command{
 		Generate(),
+		I2c(),
+		Gpio(),
+		Pwm(),
+		Run(),
+		Monitor(),
 	}
 	app.Run(os.Args)
 }
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/api"
+)
+
+// Monitor returns the command tailing the events of a running API
+func Monitor() cli.Command {
+	return cli.Command{
+		Name:  "monitor",
+		Usage: "Tail the events of the robots served by an API and run their commands",
+		Flags: []cli.Flag{
+			cli.StringFlag{Name: "url, u", Value: "http://localhost:3000", Usage: "URL of the API"},
+			cli.StringFlag{Name: "username", Usage: "username of the API basic authentication"},
+			cli.StringFlag{Name: "password", Usage: "password of the API basic authentication"},
+			cli.StringFlag{Name: "filter, f", Usage: "events shown, as robot/device/event patterns separated by commas, e.g. \"*/button/*\""},
+		},
+		Action: func(c *cli.Context) {
+			client := api.NewClient(c.String("url"))
+			client.Username = c.String("username")
+			client.Password = c.String("password")
+
+			m := newMonitor(client, os.Stdout)
+			m.setFilter(c.String("filter"))
+			if err := m.subscribe(); err != nil {
+				fmt.Println(err)
+				return
+			}
+			defer m.close()
+			m.list()
+			m.repl(os.Stdin)
+		},
+	}
+}
+
+const monitorHelp = `Commands:
+  robots                                  list the robots, devices, commands and events
+  filter [pattern,...]                    show the events matching robot/device/event patterns, all without pattern
+  call <robot> [device] <command> [k=v]   run a command, the values being JSON or strings
+  help                                    show this help
+  quit                                    exit`
+
+// monitor prints the events of the robots served by an API, and runs the
+// commands typed by the user.
+type monitor struct {
+	client  *api.Client
+	out     io.Writer
+	robots  []*gobot.JSONRobot
+	filters []string
+	subs    []*api.Subscription
+	mutex   sync.Mutex
+}
+
+func newMonitor(client *api.Client, out io.Writer) *monitor {
+	return &monitor{client: client, out: out}
+}
+
+// subscribe fetches the robots and subscribes to all the events of their
+// devices. Filters apply when printing, so that they can be changed.
+func (m *monitor) subscribe() error {
+	robots, err := m.client.Robots()
+	if err != nil {
+		return err
+	}
+	m.robots = robots
+
+	for _, r := range m.robots {
+		for _, d := range r.Devices {
+			for _, event := range d.Events {
+				sub, err := m.client.Subscribe(r.Name, d.Name, event)
+				if err != nil {
+					m.printf("%s/%s/%s: %v\n", r.Name, d.Name, event, err)
+					continue
+				}
+				m.subs = append(m.subs, sub)
+				go m.tail(r.Name+"/"+d.Name+"/"+event, sub)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *monitor) tail(name string, sub *api.Subscription) {
+	for data := range sub.C {
+		if !m.matches(name) {
+			continue
+		}
+		value, _ := json.Marshal(data)
+		m.printf("%s %s %s\n", time.Now().Format("15:04:05.000"), name, value)
+	}
+}
+
+func (m *monitor) close() {
+	for _, sub := range m.subs {
+		sub.Close()
+	}
+}
+
+// setFilter sets the robot/device/event patterns, separated by commas, of the
+// events printed. An empty filter prints all events.
+func (m *monitor) setFilter(filter string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.filters = nil
+	for _, f := range strings.Split(filter, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			m.filters = append(m.filters, f)
+		}
+	}
+}
+
+func (m *monitor) matches(name string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.filters) == 0 {
+		return true
+	}
+	for _, f := range m.filters {
+		if ok, _ := path.Match(f, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *monitor) printf(format string, a ...interface{}) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	fmt.Fprintf(m.out, format, a...)
+}
+
+// list prints the robots with their commands, devices and events
+func (m *monitor) list() {
+	for _, r := range m.robots {
+		m.printf("%s %v\n", r.Name, sorted(r.Commands))
+		for _, d := range r.Devices {
+			m.printf("  %s commands: %v events: %v\n", d.Name, sorted(d.Commands), d.Events)
+		}
+	}
+}
+
+// repl runs the commands read from in until quit or the end of the input
+func (m *monitor) repl(in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "robots":
+			m.list()
+		case "filter":
+			m.setFilter(strings.Join(args[1:], ","))
+		case "call":
+			m.call(args[1:])
+		case "help":
+			m.printf("%s\n", monitorHelp)
+		case "quit", "exit":
+			return
+		default:
+			m.printf("Unknown command %q, type help for the list of commands\n", args[0])
+		}
+	}
+}
+
+// call runs the robot or device command described by args and prints its
+// result
+func (m *monitor) call(args []string) {
+	names := []string{}
+	params := map[string]interface{}{}
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) == 1 {
+			names = append(names, arg)
+			continue
+		}
+		var v interface{}
+		if json.Unmarshal([]byte(kv[1]), &v) != nil {
+			v = kv[1]
+		}
+		params[kv[0]] = v
+	}
+
+	var result interface{}
+	var err error
+	switch len(names) {
+	case 2:
+		result, err = m.client.Command(names[0], names[1], params)
+	case 3:
+		result, err = m.client.DeviceCommand(names[0], names[1], names[2], params)
+	default:
+		m.printf("Usage: call <robot> [device] <command> [key=value ...]\n")
+		return
+	}
+	if err != nil {
+		m.printf("%v\n", err)
+		return
+	}
+	value, _ := json.Marshal(result)
+	m.printf("%s\n", value)
+}
+
+func sorted(s []string) []string {
+	s = append([]string{}, s...)
+	sort.Strings(s)
+	return s
+}
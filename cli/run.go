@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/codegangsta/cli"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/api"
+	robotconfig "gobot.io/x/gobot/config"
+	"gobot.io/x/gobot/hil"
+	"gobot.io/x/gobot/platforms/beaglebone"
+	"gobot.io/x/gobot/platforms/chip"
+	"gobot.io/x/gobot/platforms/firmata"
+	"gobot.io/x/gobot/platforms/raspi"
+)
+
+// The adaptors available to the configuration files run by the CLI, the
+// drivers of the gpio, aio and i2c packages being registered by the config
+// package itself.
+func init() {
+	robotconfig.RegisterAdaptor("firmata", func(o robotconfig.Options) (gobot.Adaptor, error) {
+		return firmata.NewAdaptor(o.String("port", "/dev/ttyACM0")), nil
+	})
+	robotconfig.RegisterAdaptor("firmata-tcp", func(o robotconfig.Options) (gobot.Adaptor, error) {
+		return firmata.NewTCPAdaptor(o.String("address", "")), nil
+	})
+	robotconfig.RegisterAdaptor("raspi", func(o robotconfig.Options) (gobot.Adaptor, error) {
+		return raspi.NewAdaptor(), nil
+	})
+	robotconfig.RegisterAdaptor("beaglebone", func(o robotconfig.Options) (gobot.Adaptor, error) {
+		return beaglebone.NewAdaptor(), nil
+	})
+	robotconfig.RegisterAdaptor("chip", func(o robotconfig.Options) (gobot.Adaptor, error) {
+		if o.Bool("pro", false) {
+			return chip.NewProAdaptor(), nil
+		}
+		return chip.NewAdaptor(), nil
+	})
+	robotconfig.RegisterAdaptor("linux", func(o robotconfig.Options) (gobot.Adaptor, error) {
+		return hil.NewSysfsAdaptor(), nil
+	})
+}
+
+// Run returns the command running the robots of a configuration file
+func Run() cli.Command {
+	return cli.Command{
+		Name:      "run",
+		Usage:     "Build and start the robots described by a configuration file",
+		ArgsUsage: "<robot.yaml|robot.json>",
+		Flags: []cli.Flag{
+			cli.BoolFlag{Name: "api", Usage: "serve the robots with the REST API"},
+			cli.StringFlag{Name: "host", Usage: "host of the REST API"},
+			cli.StringFlag{Name: "port, p", Value: "3000", Usage: "port of the REST API"},
+			cli.BoolFlag{Name: "check", Usage: "only check that the robots can be built"},
+			cli.StringSliceFlag{Name: "plugin", Value: &cli.StringSlice{}, Usage: "Go plugin (.so) registering adaptors and drivers"},
+		},
+		Action: func(c *cli.Context) {
+			for _, path := range c.StringSlice("plugin") {
+				// the init functions of the plugin register its hardware
+				if _, err := plugin.Open(path); err != nil {
+					fmt.Println(err)
+					return
+				}
+			}
+
+			if c.Args().First() == "" {
+				fmt.Println("Please provide a configuration file.")
+				for _, p := range robotconfig.Plugins() {
+					fmt.Printf("Plugin: %s %s %s\n", p.Name, p.Version, p.Description)
+				}
+				fmt.Println("Adaptors:", robotconfig.Adaptors())
+				fmt.Println("Drivers:", robotconfig.Drivers())
+				return
+			}
+
+			cfg, err := robotconfig.Load(c.Args().First())
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			master, err := cfg.Build()
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			if c.Bool("check") {
+				master.Robots().Each(func(r *gobot.Robot) {
+					fmt.Printf("%s: %d connections, %d devices\n", r.Name, r.Connections().Len(), r.Devices().Len())
+				})
+				return
+			}
+
+			if c.Bool("api") {
+				a := api.NewAPI(master)
+				a.Host = c.String("host")
+				a.Port = c.String("port")
+				a.Start()
+			}
+
+			if err := master.Start(); err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+}
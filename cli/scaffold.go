@@ -0,0 +1,754 @@
+package main
+
+// scaffold sets the fields used by the i2c, gpio, spi and board templates,
+// which generate code for the Gobot tree itself when the package is the one
+// of the bus, or for a package of its own otherwise.
+func scaffold(kind string, c config) config {
+	c.Kind = kind
+	c.TestPackage = c.Package
+	if kind == "board" {
+		return c
+	}
+
+	if c.Package == kind {
+		// generated in drivers/<kind>, the tests go in an external test
+		// package as the mock adaptors import the package of the bus
+		if kind != "spi" {
+			c.TestPackage = kind + "_test"
+			c.Driver = kind + "."
+		}
+	} else {
+		c.Qualifier = kind + "."
+	}
+	return c
+}
+
+func generateScaffold(c config) error {
+	var source, test string
+	switch c.Kind {
+	case "i2c":
+		source, test = i2cDriver(), i2cDriverTest()
+	case "gpio":
+		source, test = gpioDriver(), gpioDriverTest()
+	case "spi":
+		source, test = spiDriver(), spiDriverTest()
+	case "board":
+		if err := generate(c, "doc.go", boardDoc()); err != nil {
+			return err
+		}
+		if err := generate(c, c.Name+"_adaptor.go", boardAdaptor()); err != nil {
+			return err
+		}
+		return generate(c, c.Name+"_adaptor_test.go", boardAdaptorTest())
+	}
+
+	if err := generate(c, c.Name+"_driver.go", source); err != nil {
+		return err
+	}
+	return generate(c, c.Name+"_driver_test.go", test)
+}
+
+func i2cDriver() string {
+	return `package {{.Package}}
+
+import (
+	"gobot.io/x/gobot"{{if .Qualifier}}
+	"gobot.io/x/gobot/drivers/i2c"{{end}}
+)
+
+// {{.UpperName}}DefaultAddress is the default I2C address of the {{.UpperName}}
+// TODO: set the address given by the datasheet
+const {{.UpperName}}DefaultAddress = 0x00
+
+// TODO: set the registers given by the datasheet
+const (
+	{{.Name}}RegisterID      = 0x00
+	{{.Name}}RegisterControl = 0x01
+
+	{{.Name}}PowerOn  = 0x01
+	{{.Name}}PowerOff = 0x00
+)
+
+// {{.UpperName}}Driver is a driver for the {{.UpperName}} I2C device
+type {{.UpperName}}Driver struct {
+	name       string
+	connector  {{.Qualifier}}Connector
+	connection {{.Qualifier}}Connection
+	{{.Qualifier}}Config
+}
+
+// New{{.UpperName}}Driver creates a new driver with specified i2c interface
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//
+func New{{.UpperName}}Driver(a {{.Qualifier}}Connector, options ...func({{.Qualifier}}Config)) *{{.UpperName}}Driver {
+	d := &{{.UpperName}}Driver{
+		name:      gobot.DefaultName("{{.UpperName}}"),
+		connector: a,
+		Config:    {{.Qualifier}}NewConfig(),
+	}
+
+	for _, option := range options {
+		option(d)
+	}
+
+	return d
+}
+
+// Name returns the name of the device
+func (d *{{.UpperName}}Driver) Name() string { return d.name }
+
+// SetName sets the name of the device
+func (d *{{.UpperName}}Driver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device
+func (d *{{.UpperName}}Driver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Start connects to the device and powers it on
+func (d *{{.UpperName}}Driver) Start() (err error) {
+	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
+	address := d.GetAddressOrDefault({{.UpperName}}DefaultAddress)
+
+	if d.connection, err = d.connector.GetConnection(address, bus); err != nil {
+		return err
+	}
+	return d.connection.WriteByteData({{.Name}}RegisterControl, {{.Name}}PowerOn)
+}
+
+// Halt powers the device off
+func (d *{{.UpperName}}Driver) Halt() (err error) {
+	if d.connection == nil {
+		return
+	}
+	return d.connection.WriteByteData({{.Name}}RegisterControl, {{.Name}}PowerOff)
+}
+
+// ID returns the identification of the device
+func (d *{{.UpperName}}Driver) ID() (uint8, error) {
+	return d.connection.ReadByteData({{.Name}}RegisterID)
+}
+`
+}
+
+func i2cDriverTest() string {
+	return `package {{.TestPackage}}
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/drivers/i2c/i2ctest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*{{.Driver}}{{.UpperName}}Driver)(nil)
+
+func initTest{{.UpperName}}Driver() (*{{.Driver}}{{.UpperName}}Driver, *i2ctest.Device) {
+	a := i2ctest.NewAdaptor()
+	dev := a.AddDevice({{.Driver}}{{.UpperName}}DefaultAddress)
+	return {{.Driver}}New{{.UpperName}}Driver(a), dev
+}
+
+func TestNew{{.UpperName}}Driver(t *testing.T) {
+	d, _ := initTest{{.UpperName}}Driver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "{{.UpperName}}"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+	gobottest.Refute(t, d.Connection(), nil)
+}
+
+func Test{{.UpperName}}DriverOptions(t *testing.T) {
+	d := {{.Driver}}New{{.UpperName}}Driver(i2ctest.NewAdaptor(), i2c.WithBus(2), i2c.WithAddress(0x42))
+	gobottest.Assert(t, d.GetBusOrDefault(1), 2)
+	gobottest.Assert(t, d.GetAddressOrDefault(0), 0x42)
+}
+
+func Test{{.UpperName}}DriverStart(t *testing.T) {
+	d, dev := initTest{{.UpperName}}Driver()
+	dev.Expect(i2ctest.Write(0x01, 0x01))
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, dev.Verify(), nil)
+}
+
+func Test{{.UpperName}}DriverStartConnectError(t *testing.T) {
+	d := {{.Driver}}New{{.UpperName}}Driver(i2ctest.NewAdaptor())
+	gobottest.Refute(t, d.Start(), nil)
+}
+
+func Test{{.UpperName}}DriverHalt(t *testing.T) {
+	d, dev := initTest{{.UpperName}}Driver()
+	gobottest.Assert(t, d.Halt(), nil)
+
+	d.Start()
+	dev.Reset()
+	dev.Expect(i2ctest.Write(0x01, 0x00))
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, dev.Verify(), nil)
+}
+
+func Test{{.UpperName}}DriverID(t *testing.T) {
+	d, dev := initTest{{.UpperName}}Driver()
+	d.Start()
+
+	dev.SetRegister(0x00, 0x5A)
+	id, err := d.ID()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, id, uint8(0x5A))
+
+	dev.FailRead(0x00, errors.New("read error"))
+	_, err = d.ID()
+	gobottest.Assert(t, err, errors.New("read error"))
+}
+`
+}
+
+func gpioDriver() string {
+	return `package {{.Package}}
+
+import (
+	"time"
+
+	"gobot.io/x/gobot"{{if .Qualifier}}
+	"gobot.io/x/gobot/drivers/gpio"{{end}}
+)
+
+// {{.UpperName}}Change is the event published when the value read by the
+// {{.UpperName}}Driver changes. Its data is the new value.
+const {{.UpperName}}Change = "change"
+
+// {{.UpperName}}Driver is a driver for the {{.UpperName}} digital sensor
+type {{.UpperName}}Driver struct {
+	name       string
+	pin        string
+	connection {{.Qualifier}}DigitalReader
+	interval   time.Duration
+	halt       chan bool
+	gobot.Eventer
+	gobot.Commander
+}
+
+// New{{.UpperName}}Driver returns a new {{.UpperName}}Driver polling the pin
+// every 10ms by default, or at the given interval.
+//
+// Adds the following API Commands:
+//	"Read" - See {{.UpperName}}Driver.Read
+func New{{.UpperName}}Driver(a {{.Qualifier}}DigitalReader, pin string, v ...time.Duration) *{{.UpperName}}Driver {
+	d := &{{.UpperName}}Driver{
+		name:       gobot.DefaultName("{{.UpperName}}"),
+		pin:        pin,
+		connection: a,
+		interval:   10 * time.Millisecond,
+		halt:       make(chan bool),
+		Eventer:    gobot.NewEventer(),
+		Commander:  gobot.NewCommander(),
+	}
+
+	if len(v) > 0 {
+		d.interval = v[0]
+	}
+
+	d.AddEvent({{.UpperName}}Change)
+	d.AddEvent({{.Qualifier}}Error)
+
+	d.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		val, err := d.Read()
+		if err != nil {
+			return err
+		}
+		return val
+	})
+
+	return d
+}
+
+// Name returns the name of the device
+func (d *{{.UpperName}}Driver) Name() string { return d.name }
+
+// SetName sets the name of the device
+func (d *{{.UpperName}}Driver) SetName(n string) { d.name = n }
+
+// Pin returns the pin of the device
+func (d *{{.UpperName}}Driver) Pin() string { return d.pin }
+
+// Connection returns the connection of the device
+func (d *{{.UpperName}}Driver) Connection() gobot.Connection { return d.connection.(gobot.Connection) }
+
+// Start starts polling the pin, publishing a {{.UpperName}}Change event when
+// its value changes and an Error event when it cannot be read.
+func (d *{{.UpperName}}Driver) Start() (err error) {
+	go func() {
+		value := -1
+		for {
+			newValue, err := d.Read()
+			if err != nil {
+				d.Publish({{.Qualifier}}Error, err)
+			} else if newValue != value {
+				value = newValue
+				d.Publish({{.UpperName}}Change, value)
+			}
+			select {
+			case <-gobot.DefaultClock().After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Halt stops polling the pin
+func (d *{{.UpperName}}Driver) Halt() (err error) {
+	d.halt <- true
+	return
+}
+
+// Read returns the current value of the pin
+func (d *{{.UpperName}}Driver) Read() (int, error) {
+	return d.connection.DigitalRead(d.pin)
+}
+`
+}
+
+func gpioDriverTest() string {
+	return `package {{.TestPackage}}
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/gpio/gpiotest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*{{.Driver}}{{.UpperName}}Driver)(nil)
+
+func initTest{{.UpperName}}Driver() (*{{.Driver}}{{.UpperName}}Driver, *gpiotest.Adaptor) {
+	a := gpiotest.NewAdaptor()
+	return {{.Driver}}New{{.UpperName}}Driver(a, "1", time.Millisecond), a
+}
+
+func TestNew{{.UpperName}}Driver(t *testing.T) {
+	d, _ := initTest{{.UpperName}}Driver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "{{.UpperName}}"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+	gobottest.Assert(t, d.Pin(), "1")
+	gobottest.Refute(t, d.Connection(), nil)
+}
+
+func Test{{.UpperName}}DriverStart(t *testing.T) {
+	d, a := initTest{{.UpperName}}Driver()
+	events := gobottest.RecordEvents(t, d, {{.Driver}}{{.UpperName}}Change, gpio.Error)
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	gobottest.Assert(t, events.ExpectEvent({{.Driver}}{{.UpperName}}Change, 100*time.Millisecond, nil), 0)
+
+	a.SetInput("1", 1)
+	gobottest.Assert(t, events.ExpectEvent({{.Driver}}{{.UpperName}}Change, 100*time.Millisecond, nil), 1)
+
+	a.FailRead("1", errors.New("read error"))
+	events.ExpectEvent(gpio.Error, 100*time.Millisecond, gobottest.Equals(errors.New("read error")))
+}
+
+func Test{{.UpperName}}DriverRead(t *testing.T) {
+	d, a := initTest{{.UpperName}}Driver()
+	a.SetInput("1", 1)
+	gobottest.Assert(t, d.Command("Read")(nil), 1)
+
+	a.FailRead("1", errors.New("read error"))
+	_, err := d.Read()
+	gobottest.Assert(t, err, errors.New("read error"))
+}
+`
+}
+
+func spiDriver() string {
+	return `package {{.Package}}
+
+import (
+	"gobot.io/x/gobot"{{if .Qualifier}}
+	"gobot.io/x/gobot/drivers/spi"{{end}}
+)
+
+// TODO: set the command given by the datasheet
+const {{.Name}}CommandRead = 0x01
+
+// {{.UpperName}}Driver is a driver for the {{.UpperName}} SPI device
+type {{.UpperName}}Driver struct {
+	name       string
+	connector  {{.Qualifier}}Connector
+	connection {{.Qualifier}}Connection
+	{{.Qualifier}}Config
+}
+
+// New{{.UpperName}}Driver creates a new driver with specified spi interface
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		spi.WithBus(int):	bus to use with this driver
+//
+func New{{.UpperName}}Driver(a {{.Qualifier}}Connector, options ...func({{.Qualifier}}Config)) *{{.UpperName}}Driver {
+	d := &{{.UpperName}}Driver{
+		name:      gobot.DefaultName("{{.UpperName}}"),
+		connector: a,
+		Config:    {{.Qualifier}}NewConfig(),
+	}
+
+	for _, option := range options {
+		option(d)
+	}
+
+	return d
+}
+
+// Name returns the name of the device
+func (d *{{.UpperName}}Driver) Name() string { return d.name }
+
+// SetName sets the name of the device
+func (d *{{.UpperName}}Driver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device
+func (d *{{.UpperName}}Driver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Start connects to the device
+func (d *{{.UpperName}}Driver) Start() (err error) {
+	bus := d.GetBusOrDefault(d.connector.GetSpiDefaultBus())
+	mode := d.connector.GetSpiDefaultMode()
+	maxSpeed := d.connector.GetSpiDefaultMaxSpeed()
+	d.connection, err = d.connector.GetSpiConnection(bus, mode, maxSpeed)
+	return
+}
+
+// Halt closes the connection to the device
+func (d *{{.UpperName}}Driver) Halt() (err error) {
+	if d.connection == nil {
+		return
+	}
+	return d.connection.Close()
+}
+
+// Read sends the read command and returns the two bytes of the answer
+func (d *{{.UpperName}}Driver) Read() (result int, err error) {
+	tx := []byte{ {{.Name}}CommandRead, 0x00, 0x00}
+	rx := make([]byte, len(tx))
+	if err = d.connection.Tx(tx, rx); err != nil {
+		return
+	}
+	return int(rx[1])<<8 | int(rx[2]), nil
+}
+`
+}
+
+func spiDriverTest() string {
+	return `package {{.TestPackage}}
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	xspi "golang.org/x/exp/io/spi"
+	"gobot.io/x/gobot"{{if .Qualifier}}
+	"gobot.io/x/gobot/drivers/spi"{{end}}
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*{{.UpperName}}Driver)(nil)
+
+// {{.Name}}TestConnection answers the transfers with rx and records them
+type {{.Name}}TestConnection struct {
+	tx  [][]byte
+	rx  []byte
+	err error
+}
+
+func (c *{{.Name}}TestConnection) Close() error                      { return nil }
+func (c *{{.Name}}TestConnection) SetBitOrder(o xspi.Order) error    { return nil }
+func (c *{{.Name}}TestConnection) SetBitsPerWord(bits int) error     { return nil }
+func (c *{{.Name}}TestConnection) SetCSChange(leaveEnabled bool) error { return nil }
+func (c *{{.Name}}TestConnection) SetDelay(t time.Duration) error    { return nil }
+func (c *{{.Name}}TestConnection) SetMaxSpeed(speed int) error       { return nil }
+func (c *{{.Name}}TestConnection) SetMode(mode xspi.Mode) error      { return nil }
+
+func (c *{{.Name}}TestConnection) Tx(w, r []byte) error {
+	c.tx = append(c.tx, w)
+	copy(r, c.rx)
+	return c.err
+}
+
+type {{.Name}}TestConnector struct {
+	connection *{{.Name}}TestConnection
+}
+
+func (a *{{.Name}}TestConnector) Name() string       { return "{{.Name}}TestConnector" }
+func (a *{{.Name}}TestConnector) SetName(string)     {}
+func (a *{{.Name}}TestConnector) Connect() error     { return nil }
+func (a *{{.Name}}TestConnector) Finalize() error    { return nil }
+func (a *{{.Name}}TestConnector) GetSpiDefaultBus() int           { return 0 }
+func (a *{{.Name}}TestConnector) GetSpiDefaultMode() int          { return 0 }
+func (a *{{.Name}}TestConnector) GetSpiDefaultMaxSpeed() int64    { return 500000 }
+
+func (a *{{.Name}}TestConnector) GetSpiConnection(busNum, mode int, maxSpeed int64) ({{.Qualifier}}Connection, error) {
+	return a.connection, nil
+}
+
+func initTest{{.UpperName}}Driver() (*{{.UpperName}}Driver, *{{.Name}}TestConnection) {
+	c := &{{.Name}}TestConnection{}
+	return New{{.UpperName}}Driver(&{{.Name}}TestConnector{connection: c}), c
+}
+
+func TestNew{{.UpperName}}Driver(t *testing.T) {
+	d, _ := initTest{{.UpperName}}Driver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "{{.UpperName}}"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+	gobottest.Refute(t, d.Connection(), nil)
+}
+
+func Test{{.UpperName}}DriverStartHalt(t *testing.T) {
+	d, _ := initTest{{.UpperName}}Driver()
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func Test{{.UpperName}}DriverRead(t *testing.T) {
+	d, c := initTest{{.UpperName}}Driver()
+	d.Start()
+
+	c.rx = []byte{0x00, 0x01, 0x02}
+	val, err := d.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 0x0102)
+	gobottest.Assert(t, c.tx, [][]byte{ { {{.Name}}CommandRead, 0x00, 0x00} })
+
+	c.err = errors.New("tx error")
+	_, err = d.Read()
+	gobottest.Assert(t, err, errors.New("tx error"))
+}
+`
+}
+
+func boardDoc() string {
+	return `/*
+Package {{.Package}} contains the Gobot adaptor for the {{.UpperName}} board.
+*/
+package {{.Package}}
+`
+}
+
+func boardAdaptor() string {
+	return `package {{.Package}}
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/sysfs"
+)
+
+// pins maps the pins of the header to the sysfs GPIO numbers
+// TODO: fill in the pins of the board
+var pins = map[string]int{
+	"7":  4,
+	"11": 17,
+}
+
+// Adaptor is the Gobot adaptor for the {{.UpperName}} board
+type Adaptor struct {
+	name        string
+	digitalPins map[int]*sysfs.DigitalPin
+	i2cBuses    map[int]i2c.I2cDevice
+	mutex       sync.Mutex
+}
+
+// NewAdaptor creates a {{.UpperName}} Adaptor
+func NewAdaptor() *Adaptor {
+	return &Adaptor{
+		name:        gobot.DefaultName("{{.UpperName}}"),
+		digitalPins: make(map[int]*sysfs.DigitalPin),
+		i2cBuses:    make(map[int]i2c.I2cDevice),
+	}
+}
+
+// Name returns the name of the Adaptor
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the name of the Adaptor
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Connect initializes the board
+func (a *Adaptor) Connect() (err error) { return }
+
+// Finalize unexports the pins and closes the buses
+func (a *Adaptor) Finalize() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, pin := range a.digitalPins {
+		if e := pin.Unexport(); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	for _, bus := range a.i2cBuses {
+		if e := i2c.CloseBus(bus); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	return
+}
+
+// DigitalPin returns the sysfs pin of the header pin, exported and set to
+// the direction dir
+func (a *Adaptor) DigitalPin(pin string, dir string) (sysfs.DigitalPinner, error) {
+	i, ok := pins[pin]
+	if !ok {
+		return nil, errors.New("Not a valid pin")
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.digitalPins[i] == nil {
+		a.digitalPins[i] = sysfs.NewDigitalPin(i)
+		if err := a.digitalPins[i].Export(); err != nil {
+			return nil, err
+		}
+	}
+	return a.digitalPins[i], a.digitalPins[i].Direction(dir)
+}
+
+// DigitalRead reads the value of the pin
+func (a *Adaptor) DigitalRead(pin string) (int, error) {
+	sysfsPin, err := a.DigitalPin(pin, sysfs.IN)
+	if err != nil {
+		return 0, err
+	}
+	return sysfsPin.Read()
+}
+
+// DigitalWrite writes val to the pin
+func (a *Adaptor) DigitalWrite(pin string, val byte) error {
+	sysfsPin, err := a.DigitalPin(pin, sysfs.OUT)
+	if err != nil {
+		return err
+	}
+	return sysfsPin.Write(int(val))
+}
+
+// GetConnection returns an i2c connection to a device on a specified bus.
+// Valid bus numbers are 0 and 1, for /dev/i2c-0 and /dev/i2c-1.
+func (a *Adaptor) GetConnection(address int, bus int) (i2c.Connection, error) {
+	if bus < 0 || bus > 1 {
+		return nil, fmt.Errorf("Bus number %d out of range", bus)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.i2cBuses[bus] == nil {
+		device, err := sysfs.NewI2cDevice(fmt.Sprintf("/dev/i2c-%d", bus))
+		if err != nil {
+			return nil, err
+		}
+		a.i2cBuses[bus] = device
+	}
+	return i2c.NewConnection(a.i2cBuses[bus], address), nil
+}
+
+// GetDefaultBus returns the default i2c bus of the board
+func (a *Adaptor) GetDefaultBus() int { return 1 }
+`
+}
+
+func boardAdaptorTest() string {
+	return `package {{.Package}}
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/sysfs"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+var _ gpio.DigitalReader = (*Adaptor)(nil)
+var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ i2c.Connector = (*Adaptor)(nil)
+
+func TestAdaptor(t *testing.T) {
+	a := NewAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "{{.UpperName}}"), true)
+	a.SetName("NewName")
+	gobottest.Assert(t, a.Name(), "NewName")
+	gobottest.Assert(t, a.Connect(), nil)
+}
+
+func TestAdaptorDigitalIO(t *testing.T) {
+	fs := sysfs.NewMockFilesystem([]string{
+		"/sys/class/gpio/export",
+		"/sys/class/gpio/unexport",
+		"/sys/class/gpio/gpio4/value",
+		"/sys/class/gpio/gpio4/direction",
+		"/sys/class/gpio/gpio17/value",
+		"/sys/class/gpio/gpio17/direction",
+	})
+	sysfs.SetFilesystem(fs)
+
+	a := NewAdaptor()
+	gobottest.Assert(t, a.DigitalWrite("7", 1), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/value"].Contents, "1")
+
+	fs.Files["/sys/class/gpio/gpio17/value"].Contents = "1"
+	val, err := a.DigitalRead("11")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+
+	gobottest.Assert(t, a.DigitalWrite("notexist", 1), errors.New("Not a valid pin"))
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+
+func TestAdaptorI2c(t *testing.T) {
+	fs := sysfs.NewMockFilesystem([]string{
+		"/dev/i2c-1",
+	})
+	sysfs.SetFilesystem(fs)
+	sysfs.SetSyscall(&sysfs.MockSyscall{})
+
+	a := NewAdaptor()
+	con, err := a.GetConnection(0xff, a.GetDefaultBus())
+	gobottest.Assert(t, err, nil)
+
+	con.Write([]byte{0x00, 0x01})
+	data := []byte{42, 42}
+	con.Read(data)
+	gobottest.Assert(t, data, []byte{0x00, 0x01})
+
+	_, err = a.GetConnection(0xff, 51)
+	gobottest.Assert(t, err, errors.New("Bus number 51 out of range"))
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+`
+}
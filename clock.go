@@ -0,0 +1,265 @@
+package gobot
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by the core and the drivers, so that
+// timing dependent behavior can be tested with a FakeClock.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Sleep pauses the calling goroutine for at least d
+	Sleep(d time.Duration)
+	// After returns a channel receiving the current time after d
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a Ticker sending the time every d
+	NewTicker(d time.Duration) Ticker
+	// AfterFunc calls f in its own goroutine after d, unless the returned
+	// Timer is stopped first
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the Ticker
+	Stop()
+}
+
+// Timer calls a function once, like the time.Timer of time.AfterFunc.
+type Timer interface {
+	// Stop prevents the Timer from firing, returning false if it has
+	// already fired or been stopped
+	Stop() bool
+}
+
+var (
+	defaultClock      Clock = systemClock{}
+	defaultClockMutex sync.RWMutex
+)
+
+// SystemClock returns the Clock based on the time package
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// DefaultClock returns the Clock used by the core and the drivers, the
+// SystemClock unless replaced with SetDefaultClock.
+func DefaultClock() Clock {
+	defaultClockMutex.RLock()
+	defer defaultClockMutex.RUnlock()
+	return defaultClock
+}
+
+// SetDefaultClock replaces the Clock used by the core and the drivers.
+// Components read it when they start timing something, so it should be set
+// before starting the Robots.
+func SetDefaultClock(c Clock) {
+	defaultClockMutex.Lock()
+	defer defaultClockMutex.Unlock()
+	defaultClock = c
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// FakeClock is a Clock which only moves when told to, making timing
+// dependent tests instant and deterministic:
+//
+//	clock := gobot.NewFakeClock(time.Now())
+//	gobot.SetDefaultClock(clock)
+//	defer gobot.SetDefaultClock(gobot.SystemClock())
+//
+//	button.Start()
+//	clock.BlockUntil(1) // the button waits for its next poll
+//	clock.Advance(10 * time.Millisecond)
+type FakeClock struct {
+	now     time.Time
+	waiters []*fakeWaiter
+	changed chan bool
+	mutex   sync.Mutex
+}
+
+type fakeWaiter struct {
+	when   time.Time
+	period time.Duration
+	c      chan time.Time
+	f      func()
+}
+
+// NewFakeClock returns a new FakeClock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan bool)}
+}
+
+// Now returns the time of the FakeClock
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Sleep blocks until the FakeClock has been advanced by d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// After returns a channel receiving the time once the FakeClock has been
+// advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.add(d, 0).c
+}
+
+// NewTicker returns a Ticker firing each time the FakeClock is advanced by d
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+	return &fakeTicker{clock: c, waiter: c.add(d, d)}
+}
+
+// AfterFunc calls f in its own goroutine once the FakeClock has been advanced
+// by d
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	w := &fakeWaiter{when: c.now.Add(d), f: f}
+	if d <= 0 {
+		go f()
+		return &fakeTimer{clock: c, waiter: w}
+	}
+	c.waiters = append(c.waiters, w)
+	c.notify()
+	return &fakeTimer{clock: c, waiter: w}
+}
+
+func (c *FakeClock) add(d time.Duration, period time.Duration) *fakeWaiter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	w := &fakeWaiter{when: c.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	if d <= 0 && period == 0 {
+		w.c <- c.now
+		return w
+	}
+	c.waiters = append(c.waiters, w)
+	c.notify()
+	return w
+}
+
+// remove removes w, returning whether it was pending
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, waiter := range c.waiters {
+		if waiter == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// notify wakes up BlockUntil, the mutex must be held.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan bool)
+}
+
+// Advance moves the FakeClock forward by d, firing in order the timers and
+// tickers due meanwhile. Like time.Ticker, a Ticker whose channel is full
+// drops ticks.
+func (c *FakeClock) Advance(d time.Duration) {
+	if d < 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	end := c.now.Add(d)
+	for {
+		sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].when.Before(c.waiters[j].when) })
+		if len(c.waiters) == 0 || c.waiters[0].when.After(end) {
+			break
+		}
+		w := c.waiters[0]
+		c.now = w.when
+		if w.f != nil {
+			go w.f()
+		} else {
+			select {
+			case w.c <- c.now:
+			default:
+			}
+		}
+		if w.period > 0 {
+			w.when = w.when.Add(w.period)
+		} else {
+			c.waiters = c.waiters[1:]
+		}
+	}
+	c.now = end
+	c.notify()
+}
+
+// Set moves the FakeClock to t, firing the timers and tickers due. t before
+// the current time is ignored.
+func (c *FakeClock) Set(t time.Time) {
+	c.Advance(t.Sub(c.Now()))
+}
+
+// Waiters returns the number of pending sleeps, timers and tickers
+func (c *FakeClock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n sleeps, timers or tickers are pending,
+// so that a test can advance the FakeClock once the goroutines it exercises
+// are waiting on it.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mutex.Lock()
+		count := len(c.waiters)
+		changed := c.changed
+		c.mutex.Unlock()
+		if count >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.c }
+func (t *fakeTicker) Stop()               { t.clock.remove(t.waiter) }
+
+type fakeTimer struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTimer) Stop() bool { return t.clock.remove(t.waiter) }
@@ -0,0 +1,183 @@
+package gobot
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestSystemClock(t *testing.T) {
+	c := SystemClock()
+	begin := c.Now()
+	c.Sleep(time.Millisecond)
+	gobottest.Assert(t, c.Now().Sub(begin) >= time.Millisecond, true)
+
+	select {
+	case <-c.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Errorf("After did not fire")
+	}
+
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	fired := make(chan bool)
+	c.AfterFunc(time.Millisecond, func() { close(fired) })
+	<-fired
+}
+
+func TestDefaultClock(t *testing.T) {
+	gobottest.Assert(t, DefaultClock(), SystemClock())
+	fake := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(fake)
+	defer SetDefaultClock(SystemClock())
+	gobottest.Assert(t, DefaultClock(), Clock(fake))
+}
+
+func TestFakeClockAfter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewFakeClock(start)
+	gobottest.Assert(t, c.Now(), start)
+
+	after := c.After(10 * time.Millisecond)
+	gobottest.Assert(t, c.Waiters(), 1)
+
+	c.Advance(9 * time.Millisecond)
+	select {
+	case <-after:
+		t.Errorf("After fired too early")
+	default:
+	}
+
+	c.Advance(time.Millisecond)
+	gobottest.Assert(t, <-after, start.Add(10*time.Millisecond))
+	gobottest.Assert(t, c.Waiters(), 0)
+
+	// past or present deadlines fire at once
+	<-c.After(0)
+
+	// going back is ignored
+	c.Set(start)
+	gobottest.Assert(t, c.Now(), start.Add(10*time.Millisecond))
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	woken := make(chan bool)
+	go func() {
+		c.Sleep(time.Hour)
+		woken <- true
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Hour)
+	select {
+	case <-woken:
+	case <-time.After(time.Second):
+		t.Errorf("Sleep did not return")
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	start := time.Unix(0, 0)
+	c := NewFakeClock(start)
+	ticker := c.NewTicker(10 * time.Millisecond)
+
+	c.Advance(10 * time.Millisecond)
+	gobottest.Assert(t, <-ticker.C(), start.Add(10*time.Millisecond))
+
+	// ticks are dropped when not received
+	c.Advance(50 * time.Millisecond)
+	gobottest.Assert(t, <-ticker.C(), start.Add(20*time.Millisecond))
+	select {
+	case <-ticker.C():
+		t.Errorf("Ticker did not drop ticks")
+	default:
+	}
+
+	ticker.Stop()
+	gobottest.Assert(t, c.Waiters(), 0)
+	c.Advance(time.Second)
+	select {
+	case <-ticker.C():
+		t.Errorf("Stopped ticker fired")
+	default:
+	}
+}
+
+func TestFakeClockAfterFunc(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	fired := make(chan bool, 1)
+	timer := c.AfterFunc(10*time.Millisecond, func() { fired <- true })
+	stopped := c.AfterFunc(10*time.Millisecond, func() { t.Errorf("Stopped timer fired") })
+	gobottest.Assert(t, stopped.Stop(), true)
+	gobottest.Assert(t, stopped.Stop(), false)
+
+	c.Advance(10 * time.Millisecond)
+	<-fired
+	gobottest.Assert(t, timer.Stop(), false)
+	gobottest.Assert(t, c.Waiters(), 0)
+}
+
+func TestFakeClockOrder(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	late := c.After(20 * time.Millisecond)
+	early := c.After(10 * time.Millisecond)
+	c.Advance(30 * time.Millisecond)
+	gobottest.Assert(t, (<-early).Before(<-late), true)
+}
+
+func TestFakeClockLoop(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(c)
+	defer SetDefaultClock(SystemClock())
+
+	runs := make(chan bool, 10)
+	l := Loop(100, func() {
+		runs <- true
+	})
+	defer l.Stop()
+
+	<-runs
+	for i := 0; i < 5; {
+		select {
+		case <-runs:
+			i++
+		case <-time.After(time.Millisecond):
+			c.Advance(10 * time.Millisecond)
+		}
+	}
+	gobottest.Assert(t, l.Iterations() >= 6, true)
+}
+
+func TestFakeClockStartTimeout(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(c)
+	defer SetDefaultClock(SystemClock())
+
+	block := make(chan bool)
+	defer close(block)
+	stuck := &testStartDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "stuck", "0"),
+		start: func() error {
+			<-block
+			return nil
+		},
+	}
+
+	result := make(chan error)
+	go func() {
+		_, err := startDevice(DefaultLogger(), stuck, time.Minute)
+		result <- err
+	}()
+	for {
+		select {
+		case err := <-result:
+			gobottest.Assert(t, err.Error(), "timeout after 1m0s")
+			return
+		case <-time.After(time.Millisecond):
+			c.Advance(time.Minute)
+		}
+	}
+}
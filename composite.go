@@ -0,0 +1,169 @@
+package gobot
+
+import (
+	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// Composite is implemented by virtual Devices made of other Devices, their
+// components. A Robot looks its Devices up among the components of its
+// Composite Devices as well, so that they remain reachable by name, from the
+// API, and by capability.
+type Composite interface {
+	// Components returns the Devices the Device is made of
+	Components() []Device
+}
+
+// CompositeDevice is a virtual Device wrapping several Devices, such as a
+// gripper made of two servos and a force sensor:
+//
+//	gripper := gobot.NewCompositeDevice("gripper", left, right, force)
+//	gripper.AddCommand("Close", func(params map[string]interface{}) interface{} {
+//		...
+//	})
+//	robot := gobot.NewRobot("bot", []gobot.Connection{adaptor}, []gobot.Device{gripper})
+//
+// The components are started with the CompositeDevice one at a time, in
+// their order unless they implement Dependent, and halted with it in the
+// reverse order. They must not be added to the Robot themselves.
+//
+// The events of the components are published by the CompositeDevice once it
+// has started, prefixed by the name of their component, e.g.
+// "force:touched", so that they can be subscribed to with a pattern such as
+// "force:*".
+type CompositeDevice struct {
+	name       string
+	components *Devices
+	stop       chan bool
+	mutex      *sync.Mutex
+	logger     Logger
+	Commander
+	Eventer
+}
+
+// NewCompositeDevice returns a new CompositeDevice named name, made of
+// components.
+func NewCompositeDevice(name string, components ...Device) *CompositeDevice {
+	c := &CompositeDevice{
+		name:       name,
+		components: &Devices{},
+		mutex:      &sync.Mutex{},
+		Commander:  NewCommander(),
+		Eventer:    NewEventer(),
+	}
+
+	for _, component := range components {
+		c.AddComponent(component)
+	}
+
+	return c
+}
+
+// Name returns the name of the CompositeDevice
+func (c *CompositeDevice) Name() string { return c.name }
+
+// SetName sets the name of the CompositeDevice
+func (c *CompositeDevice) SetName(n string) { c.name = n }
+
+// Connection returns nil, the components having their own Connections
+func (c *CompositeDevice) Connection() Connection { return nil }
+
+// SetLogger sets the Logger used when starting the components
+func (c *CompositeDevice) SetLogger(l Logger) { c.logger = l }
+
+// AddComponent adds a Device to the components, and registers its events.
+// Returns the added Device.
+func (c *CompositeDevice) AddComponent(d Device) Device {
+	*c.components = append(*c.components, d)
+	if eventer, ok := d.(Eventer); ok {
+		for event := range eventer.Events() {
+			c.AddEvent(d.Name() + ":" + event)
+		}
+	}
+	return d
+}
+
+// Components returns the Devices the CompositeDevice is made of
+func (c *CompositeDevice) Components() []Device {
+	return append([]Device{}, *c.components...)
+}
+
+// Component returns the component named name, or nil if there is none.
+func (c *CompositeDevice) Component(name string) Device {
+	for _, d := range *c.components {
+		if d.Name() == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Start starts the components, and the publication of their events. The
+// components are halted when one of them fails to start.
+func (c *CompositeDevice) Start() error {
+	l := c.logger
+	if l == nil {
+		l = DefaultLogger().WithComponent(c.name)
+	}
+	if err := c.components.start(l, nil); err != nil {
+		c.components.Halt()
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stop = make(chan bool)
+	for _, d := range *c.components {
+		if eventer, ok := d.(Eventer); ok {
+			go c.forward(d.Name(), eventer, eventer.Subscribe(), c.stop)
+		}
+	}
+	return nil
+}
+
+// Halt stops the publication of the events of the components, and halts
+// them in the reverse order.
+func (c *CompositeDevice) Halt() (err error) {
+	c.mutex.Lock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	c.mutex.Unlock()
+
+	for i := len(*c.components) - 1; i >= 0; i-- {
+		d := (*c.components)[i]
+		if derr := d.Halt(); derr != nil {
+			err = multierror.Append(err, WrapError("halt", d.Name(), derr))
+		}
+	}
+	return err
+}
+
+// forward publishes the events of a component until stop is closed
+func (c *CompositeDevice) forward(name string, eventer Eventer, events eventChannel, stop chan bool) {
+	defer eventer.Unsubscribe(events)
+	for {
+		select {
+		case evt := <-events:
+			c.Publish(name+":"+evt.Name, evt.Data)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// flatten returns the Devices of d, each Composite Device being followed by
+// its components.
+func (d *Devices) flatten() Devices {
+	all := Devices{}
+	for _, device := range *d {
+		all = append(all, device)
+		if composite, ok := device.(Composite); ok {
+			components := Devices(composite.Components())
+			all = append(all, components.flatten()...)
+		}
+	}
+	return all
+}
@@ -0,0 +1,148 @@
+package gobot
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ Device = (*CompositeDevice)(nil)
+var _ Composite = (*CompositeDevice)(nil)
+var _ Loggable = (*CompositeDevice)(nil)
+
+// compositeTestDriver is a Device with events, recording when it is started
+// and halted
+type compositeTestDriver struct {
+	*testDriver
+	Eventer
+	log      *[]string
+	mutex    *sync.Mutex
+	startErr error
+}
+
+func (c *compositeTestDriver) Start() error {
+	c.record("start " + c.Name())
+	return c.startErr
+}
+
+func (c *compositeTestDriver) Halt() error {
+	c.record("halt " + c.Name())
+	return nil
+}
+
+func (c *compositeTestDriver) Temperature() (float32, error) { return 21, nil }
+
+func (c *compositeTestDriver) record(s string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	*c.log = append(*c.log, s)
+}
+
+func newCompositeTestDrivers(names ...string) ([]Device, *[]string) {
+	log := &[]string{}
+	mutex := &sync.Mutex{}
+	devices := []Device{}
+	for _, name := range names {
+		d := &compositeTestDriver{
+			testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), name, "0"),
+			Eventer:    NewEventer(),
+			log:        log,
+			mutex:      mutex,
+		}
+		d.AddEvent("touched")
+		devices = append(devices, d)
+	}
+	return devices, log
+}
+
+func TestCompositeDevice(t *testing.T) {
+	components, _ := newCompositeTestDrivers("left", "force")
+	c := NewCompositeDevice("gripper", components...)
+	gobottest.Assert(t, c.Name(), "gripper")
+	c.SetName("claw")
+	gobottest.Assert(t, c.Name(), "claw")
+	gobottest.Assert(t, c.Connection(), nil)
+	gobottest.Assert(t, len(c.Components()), 2)
+	gobottest.Assert(t, c.Component("force"), components[1])
+	gobottest.Assert(t, c.Component("right"), nil)
+	gobottest.Assert(t, c.Event("force:touched"), "force:touched")
+
+	right, _ := newCompositeTestDrivers("right")
+	gobottest.Assert(t, c.AddComponent(right[0]), right[0])
+	gobottest.Assert(t, c.Event("right:touched"), "right:touched")
+}
+
+func TestCompositeDeviceStartHalt(t *testing.T) {
+	components, log := newCompositeTestDrivers("left", "right")
+	c := NewCompositeDevice("gripper", components...)
+	gobottest.Assert(t, c.Start(), nil)
+	gobottest.Assert(t, len(*log), 2)
+
+	*log = nil
+	gobottest.Assert(t, c.Halt(), nil)
+	gobottest.Assert(t, *log, []string{"halt right", "halt left"})
+}
+
+func TestCompositeDeviceStartError(t *testing.T) {
+	components, log := newCompositeTestDrivers("left", "right")
+	components[1].(*compositeTestDriver).startErr = errors.New("no servo")
+	c := NewCompositeDevice("gripper", components...)
+	err := c.Start()
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.Contains(err.Error(), "no servo"), true)
+	gobottest.Assert(t, (*log)[2:], []string{"halt left", "halt right"})
+}
+
+func TestCompositeDeviceEvents(t *testing.T) {
+	components, _ := newCompositeTestDrivers("left", "force")
+	c := NewCompositeDevice("gripper", components...)
+	events := c.SubscribeWith(SubscribeOptions{Pattern: "force:*"})
+	gobottest.Assert(t, c.Start(), nil)
+
+	components[0].(Eventer).Publish("touched", 1)
+	components[1].(Eventer).Publish("touched", 2)
+	select {
+	case evt := <-events:
+		gobottest.Assert(t, evt.Name, "force:touched")
+		gobottest.Assert(t, evt.Data, 2)
+	case <-time.After(time.Second):
+		t.Errorf("force:touched was not published")
+	}
+
+	// the events are no longer published once halted
+	gobottest.Assert(t, c.Halt(), nil)
+	time.Sleep(10 * time.Millisecond)
+	components[1].(Eventer).Publish("touched", 3)
+	select {
+	case evt := <-events:
+		t.Errorf("%v was published after Halt", evt.Name)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestRobotCompositeDevice(t *testing.T) {
+	components, log := newCompositeTestDrivers("left", "force", "arm")
+	gripper := NewCompositeDevice("gripper", components[:2]...)
+	r := NewRobot("bot", []Connection{newTestAdaptor("Connection1", "/dev/null")}, []Device{gripper, components[2]})
+
+	// the components are reachable by name and by capability
+	gobottest.Assert(t, r.Device("gripper"), gripper)
+	gobottest.Assert(t, r.Device("force"), components[1])
+	gobottest.Assert(t, r.DevicesByCapability((*TemperatureSensor)(nil)).Len(), 3)
+
+	// depending on a component is depending on its Composite Device
+	r.DependsOn("arm", "force")
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, (*log)[2], "start arm")
+	gobottest.Assert(t, r.Stop(), nil)
+
+	json := NewJSONRobot(r)
+	gobottest.Assert(t, len(json.Devices), 2)
+	gobottest.Assert(t, json.Devices[0].Components, []string{"left", "force"})
+	gobottest.Assert(t, json.Devices[0].Events, []string{"force:touched", "left:touched"})
+	gobottest.Assert(t, len(json.Connections), 1)
+}
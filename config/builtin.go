@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+// The drivers of the gpio, aio and i2c packages are registered under the
+// kebab-cased name of their constructor, e.g. "led" for gpio.NewLedDriver.
+func init() {
+	digitalWriters := map[string]func(gpio.DigitalWriter, string) gobot.Driver{
+		"led":          func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewLedDriver(a, p) },
+		"relay":        func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewRelayDriver(a, p) },
+		"buzzer":       func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewBuzzerDriver(a, p) },
+		"motor":        func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewMotorDriver(a, p) },
+		"grove-led":    func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewGroveLedDriver(a, p) },
+		"grove-relay":  func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewGroveRelayDriver(a, p) },
+		"grove-buzzer": func(a gpio.DigitalWriter, p string) gobot.Driver { return gpio.NewGroveBuzzerDriver(a, p) },
+	}
+	for name, f := range digitalWriters {
+		RegisterDriver(name, digitalWriterDriver(f))
+	}
+
+	digitalReaders := map[string]func(gpio.DigitalReader, string, ...time.Duration) gobot.Driver{
+		"button": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewButtonDriver(a, p, v...)
+		},
+		"makey-button": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewMakeyButtonDriver(a, p, v...)
+		},
+		"pir-motion": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewPIRMotionDriver(a, p, v...)
+		},
+		"endstop": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewEndstopDriver(a, p, v...)
+		},
+		"pulse-counter": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewPulseCounterDriver(a, p, v...)
+		},
+		"grove-button": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewGroveButtonDriver(a, p, v...)
+		},
+		"grove-touch": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewGroveTouchDriver(a, p, v...)
+		},
+		"grove-magnetic-switch": func(a gpio.DigitalReader, p string, v ...time.Duration) gobot.Driver {
+			return gpio.NewGroveMagneticSwitchDriver(a, p, v...)
+		},
+	}
+	for name, f := range digitalReaders {
+		RegisterDriver(name, digitalReaderDriver(f))
+	}
+
+	analogReaders := map[string]func(aio.AnalogReader, string, ...time.Duration) gobot.Driver{
+		"analog-sensor": func(a aio.AnalogReader, p string, v ...time.Duration) gobot.Driver {
+			return aio.NewAnalogSensorDriver(a, p, v...)
+		},
+		"grove-rotary": func(a aio.AnalogReader, p string, v ...time.Duration) gobot.Driver {
+			return aio.NewGroveRotaryDriver(a, p, v...)
+		},
+		"grove-light-sensor": func(a aio.AnalogReader, p string, v ...time.Duration) gobot.Driver {
+			return aio.NewGroveLightSensorDriver(a, p, v...)
+		},
+		"grove-piezo-vibration-sensor": func(a aio.AnalogReader, p string, v ...time.Duration) gobot.Driver {
+			return aio.NewGrovePiezoVibrationSensorDriver(a, p, v...)
+		},
+		"grove-sound-sensor": func(a aio.AnalogReader, p string, v ...time.Duration) gobot.Driver {
+			return aio.NewGroveSoundSensorDriver(a, p, v...)
+		},
+		"grove-temperature-sensor": func(a aio.AnalogReader, p string, v ...time.Duration) gobot.Driver {
+			return aio.NewGroveTemperatureSensorDriver(a, p, v...)
+		},
+	}
+	for name, f := range analogReaders {
+		RegisterDriver(name, analogReaderDriver(f))
+	}
+
+	RegisterDriver("analog-actuator", func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		a, ok := conn.(aio.AnalogWriter)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support analog writes", conn.Name())
+		}
+		return aio.NewAnalogActuatorDriver(a, d.Pin), nil
+	})
+	RegisterDriver("direct-pin", func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		return gpio.NewDirectPinDriver(conn, d.Pin), nil
+	})
+	RegisterDriver("servo", func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		a, ok := conn.(gpio.ServoWriter)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support servo writes", conn.Name())
+		}
+		return gpio.NewServoDriver(a, d.Pin), nil
+	})
+	RegisterDriver("rgb-led", func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		a, ok := conn.(gpio.DigitalWriter)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support digital writes", conn.Name())
+		}
+		if len(d.Pins) != 3 {
+			return nil, fmt.Errorf("rgb-led requires 3 pins, found %d", len(d.Pins))
+		}
+		return gpio.NewRgbLedDriver(a, d.Pins[0], d.Pins[1], d.Pins[2]), nil
+	})
+
+	i2cDrivers := map[string]func(i2c.Connector, ...func(i2c.Config)) gobot.Driver{
+		"adafruit-motor-hat": func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver {
+			return i2c.NewAdafruitMotorHatDriver(c, o...)
+		},
+		"ads1015":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewADS1015Driver(c, o...) },
+		"ads1115":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewADS1115Driver(c, o...) },
+		"blinkm":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewBlinkMDriver(c, o...) },
+		"bme280":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewBME280Driver(c, o...) },
+		"bmp180":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewBMP180Driver(c, o...) },
+		"bmp280":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewBMP280Driver(c, o...) },
+		"bmp388":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewBMP388Driver(c, o...) },
+		"drv2605l":  func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewDRV2605LDriver(c, o...) },
+		"grove-lcd": func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewGroveLcdDriver(c, o...) },
+		"grove-accelerometer": func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver {
+			return i2c.NewGroveAccelerometerDriver(c, o...)
+		},
+		"hmc6352":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewHMC6352Driver(c, o...) },
+		"ina3221":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewINA3221Driver(c, o...) },
+		"jhd1313m1": func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewJHD1313M1Driver(c, o...) },
+		"l3gd20h":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewL3GD20HDriver(c, o...) },
+		"lidarlite": func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewLIDARLiteDriver(c, o...) },
+		"lis3dh":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewLIS3DHDriver(c, o...) },
+		"mcp23017":  func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewMCP23017Driver(c, o...) },
+		"mcp4725":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewMCP4725Driver(c, o...) },
+		"mma7660":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewMMA7660Driver(c, o...) },
+		"mpl115a2":  func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewMPL115A2Driver(c, o...) },
+		"mpu6050":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewMPU6050Driver(c, o...) },
+		"pca9685":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewPCA9685Driver(c, o...) },
+		"pcf8591":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewPCF8591Driver(c, o...) },
+		"sht3x":     func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewSHT3xDriver(c, o...) },
+		"sht4x":     func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewSHT4xDriver(c, o...) },
+		"ssd1306":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewSSD1306Driver(c, o...) },
+		"tfmini":    func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewTFMiniDriver(c, o...) },
+		"tsl2561":   func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewTSL2561Driver(c, o...) },
+		"wiichuck":  func(c i2c.Connector, o ...func(i2c.Config)) gobot.Driver { return i2c.NewWiichuckDriver(c, o...) },
+	}
+	for name, f := range i2cDrivers {
+		RegisterDriver(name, i2cDriver(f))
+	}
+}
+
+func digitalWriterDriver(f func(gpio.DigitalWriter, string) gobot.Driver) DriverFactory {
+	return func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		a, ok := conn.(gpio.DigitalWriter)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support digital writes", conn.Name())
+		}
+		return f(a, d.Pin), nil
+	}
+}
+
+func digitalReaderDriver(f func(gpio.DigitalReader, string, ...time.Duration) gobot.Driver) DriverFactory {
+	return func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		a, ok := conn.(gpio.DigitalReader)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support digital reads", conn.Name())
+		}
+		return withPolling(f(a, d.Pin, interval(d)...), d), nil
+	}
+}
+
+func analogReaderDriver(f func(aio.AnalogReader, string, ...time.Duration) gobot.Driver) DriverFactory {
+	return func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		a, ok := conn.(aio.AnalogReader)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support analog reads", conn.Name())
+		}
+		return withPolling(f(a, d.Pin, interval(d)...), d), nil
+	}
+}
+
+func i2cDriver(f func(i2c.Connector, ...func(i2c.Config)) gobot.Driver) DriverFactory {
+	return func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		c, ok := conn.(i2c.Connector)
+		if !ok {
+			return nil, fmt.Errorf("connection %v does not support i2c", conn.Name())
+		}
+		options := []func(i2c.Config){}
+		if d.Bus != nil {
+			options = append(options, i2c.WithBus(*d.Bus))
+		}
+		if d.Address != nil {
+			options = append(options, i2c.WithAddress(*d.Address))
+		}
+		if len(d.Addresses) > 0 {
+			options = append(options, i2c.WithAddresses(d.Addresses))
+		}
+		if _, ok := d.Options["speed"]; ok {
+			options = append(options, i2c.WithSpeed(d.Options.Int("speed", i2c.StandardSpeed)))
+		}
+		return withPolling(f(c, options...), d), nil
+	}
+}
+
+// interval returns the polling interval set with the "interval" option.
+func interval(d Device) []time.Duration {
+	if _, ok := d.Options["interval"]; !ok {
+		return nil
+	}
+	return []time.Duration{d.Options.Duration("interval", 10*time.Millisecond)}
+}
+
+// polled is implemented by the drivers polling their sensor with a
+// gobot.Poller.
+type polled interface {
+	SetPolling(gobot.PollerConfig)
+}
+
+// withPolling configures the polling of drv with the "interval",
+// "max_interval" and "backoff" options, when drv polls its sensor and one of
+// them is set.
+func withPolling(drv gobot.Driver, d Device) gobot.Driver {
+	p, ok := drv.(polled)
+	if !ok {
+		return drv
+	}
+	for _, key := range []string{"interval", "max_interval", "backoff"} {
+		if _, ok := d.Options[key]; ok {
+			p.SetPolling(gobot.PollerConfig{
+				Interval:    d.Options.Duration("interval", 0),
+				MaxInterval: d.Options.Duration("max_interval", 0),
+				Backoff:     d.Options.Float("backoff", 0),
+			})
+			break
+		}
+	}
+	return drv
+}
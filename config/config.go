@@ -0,0 +1,159 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gobot.io/x/gobot"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Config describes a Master and its Robots.
+type Config struct {
+	Robots []Robot `yaml:"robots" json:"robots"`
+}
+
+// Robot describes a Robot, its connections and devices.
+type Robot struct {
+	Name        string            `yaml:"name" json:"name"`
+	Metadata    map[string]string `yaml:"metadata" json:"metadata"`
+	Connections []Connection      `yaml:"connections" json:"connections"`
+	Devices     []Device          `yaml:"devices" json:"devices"`
+}
+
+// Connection describes an Adaptor by its registered name and options.
+type Connection struct {
+	Name    string  `yaml:"name" json:"name"`
+	Adaptor string  `yaml:"adaptor" json:"adaptor"`
+	Options Options `yaml:"options" json:"options"`
+}
+
+// Device describes a Driver by its registered name, the connection it uses,
+// and its pins, bus, address and options. An i2c device may list the
+// addresses to try in order instead of its address.
+type Device struct {
+	Name       string            `yaml:"name" json:"name"`
+	Driver     string            `yaml:"driver" json:"driver"`
+	Connection string            `yaml:"connection" json:"connection"`
+	Pin        string            `yaml:"pin" json:"pin"`
+	Pins       []string          `yaml:"pins" json:"pins"`
+	Bus        *int              `yaml:"bus" json:"bus"`
+	Address    *int              `yaml:"address" json:"address"`
+	Addresses  []int             `yaml:"addresses" json:"addresses"`
+	Options    Options           `yaml:"options" json:"options"`
+	Metadata   map[string]string `yaml:"metadata" json:"metadata"`
+}
+
+// Load reads a configuration file. Files ending in ".json" are parsed as JSON,
+// all others as YAML.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return ParseJSON(data)
+	}
+	return ParseYAML(data)
+}
+
+// ParseYAML parses a YAML configuration.
+func ParseYAML(data []byte) (*Config, error) {
+	c := &Config{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ParseJSON parses a JSON configuration.
+func ParseJSON(data []byte) (*Config, error) {
+	c := &Config{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Build creates a new Master containing the configured Robots.
+func (c *Config) Build() (*gobot.Master, error) {
+	m := gobot.NewMaster()
+	for _, rc := range c.Robots {
+		r, err := rc.Build()
+		if err != nil {
+			return nil, err
+		}
+		m.AddRobot(r)
+	}
+	return m, nil
+}
+
+// Build creates a new Robot with the configured connections and devices. A
+// device without a connection uses the Robot's first connection.
+func (rc Robot) Build() (*gobot.Robot, error) {
+	r := gobot.NewRobot()
+	if rc.Name != "" {
+		r.Name = rc.Name
+	}
+	r.Metadata = rc.Metadata
+
+	for _, cc := range rc.Connections {
+		f, err := adaptorFactory(cc.Adaptor)
+		if err != nil {
+			return nil, err
+		}
+		if cc.Options == nil {
+			cc.Options = Options{}
+		}
+		a, err := f(cc.Options)
+		if err != nil {
+			return nil, fmt.Errorf("connection %v: %v", cc.Name, err)
+		}
+		if cc.Name != "" {
+			a.SetName(cc.Name)
+		}
+		r.AddConnection(a)
+	}
+
+	for _, dc := range rc.Devices {
+		conn, err := connectionFor(r, dc.Connection)
+		if err != nil {
+			return nil, fmt.Errorf("device %v: %v", dc.Name, err)
+		}
+		f, err := driverFactory(dc.Driver)
+		if err != nil {
+			return nil, err
+		}
+		if dc.Options == nil {
+			dc.Options = Options{}
+		}
+		d, err := f(conn, dc)
+		if err != nil {
+			return nil, fmt.Errorf("device %v: %v", dc.Name, err)
+		}
+		if dc.Name != "" {
+			d.SetName(dc.Name)
+		}
+		r.AddDevice(d)
+		if dc.Metadata != nil {
+			r.SetDeviceMetadata(d.Name(), dc.Metadata)
+		}
+	}
+	return r, nil
+}
+
+func connectionFor(r *gobot.Robot, name string) (gobot.Connection, error) {
+	if name == "" {
+		if r.Connections().Len() == 0 {
+			return nil, fmt.Errorf("no connection available")
+		}
+		return (*r.Connections())[0], nil
+	}
+	if c := r.Connection(name); c != nil {
+		return c, nil
+	}
+	return nil, fmt.Errorf("No Connection found with the name %v", name)
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+)
+
+const testYAML = `
+robots:
+  - name: blinker
+    metadata:
+      location: garage
+    connections:
+      - name: arduino
+        adaptor: test
+        options:
+          port: /dev/ttyACM0
+    devices:
+      - name: led
+        driver: led
+        connection: arduino
+        pin: "13"
+        metadata:
+          firmware: "1.2"
+      - name: button
+        driver: button
+        pin: "2"
+        options:
+          interval: 50ms
+      - name: rgb
+        driver: rgb-led
+        pins: ["3", "5", "6"]
+      - name: light
+        driver: tsl2561
+        bus: 1
+        address: 0x29
+`
+
+const testJSON = `{
+  "robots": [{
+    "name": "sensor",
+    "connections": [{"name": "arduino", "adaptor": "test"}],
+    "devices": [{"name": "temp", "driver": "analog-sensor", "pin": "0"}]
+  }]
+}`
+
+func TestParseYAML(t *testing.T) {
+	c, err := ParseYAML([]byte(testYAML))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(c.Robots), 1)
+
+	r := c.Robots[0]
+	gobottest.Assert(t, r.Name, "blinker")
+	gobottest.Assert(t, r.Metadata["location"], "garage")
+	gobottest.Assert(t, r.Connections[0].Adaptor, "test")
+	gobottest.Assert(t, r.Connections[0].Options.String("port", ""), "/dev/ttyACM0")
+	gobottest.Assert(t, len(r.Devices), 4)
+	gobottest.Assert(t, r.Devices[0].Pin, "13")
+	gobottest.Assert(t, r.Devices[0].Metadata, map[string]string{"firmware": "1.2"})
+	gobottest.Assert(t, r.Devices[2].Pins, []string{"3", "5", "6"})
+	gobottest.Assert(t, *r.Devices[3].Bus, 1)
+	gobottest.Assert(t, *r.Devices[3].Address, 0x29)
+}
+
+func TestParseYAMLError(t *testing.T) {
+	_, err := ParseYAML([]byte("robots: [{"))
+	gobottest.Refute(t, err, nil)
+}
+
+func TestParseJSON(t *testing.T) {
+	c, err := ParseJSON([]byte(testJSON))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Robots[0].Name, "sensor")
+	gobottest.Assert(t, c.Robots[0].Devices[0].Driver, "analog-sensor")
+
+	_, err = ParseJSON([]byte("{"))
+	gobottest.Refute(t, err, nil)
+}
+
+func TestLoad(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gobot-config")
+	defer os.RemoveAll(dir)
+
+	yamlPath := filepath.Join(dir, "robot.yaml")
+	ioutil.WriteFile(yamlPath, []byte(testYAML), 0644)
+	c, err := Load(yamlPath)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Robots[0].Name, "blinker")
+
+	jsonPath := filepath.Join(dir, "robot.json")
+	ioutil.WriteFile(jsonPath, []byte(testJSON), 0644)
+	c, err = Load(jsonPath)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Robots[0].Name, "sensor")
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	gobottest.Refute(t, err, nil)
+}
+
+func TestBuild(t *testing.T) {
+	c, _ := ParseYAML([]byte(testYAML))
+	m, err := c.Build()
+	gobottest.Assert(t, err, nil)
+
+	r := m.Robot("blinker")
+	gobottest.Refute(t, r, nil)
+	gobottest.Assert(t, r.Metadata["location"], "garage")
+	gobottest.Assert(t, r.DeviceMetadata("led"), map[string]string{"firmware": "1.2"})
+	gobottest.Assert(t, r.DeviceMetadata("button"), map[string]string(nil))
+	gobottest.Assert(t, r.Connections().Len(), 1)
+	gobottest.Assert(t, r.Connection("arduino").(*testAdaptor).Port(), "/dev/ttyACM0")
+	gobottest.Assert(t, r.Devices().Len(), 4)
+
+	led := r.Device("led").(*gpio.LedDriver)
+	gobottest.Assert(t, led.Pin(), "13")
+	gobottest.Assert(t, led.Connection().Name(), "arduino")
+
+	button := r.Device("button").(*gpio.ButtonDriver)
+	gobottest.Assert(t, button.Connection().Name(), "arduino")
+
+	_, ok := r.Device("rgb").(*gpio.RgbLedDriver)
+	gobottest.Assert(t, ok, true)
+
+	light := r.Device("light").(*i2c.TSL2561Driver)
+	gobottest.Assert(t, light.GetBusOrDefault(0), 1)
+	gobottest.Assert(t, light.GetAddressOrDefault(0), 0x29)
+}
+
+func TestBuildI2cAddresses(t *testing.T) {
+	c, err := ParseYAML([]byte(`
+robots:
+  - name: lights
+    connections:
+      - name: arduino
+        adaptor: test
+    devices:
+      - name: light
+        driver: tsl2561
+        addresses: [0x29, 0x39, 0x49]
+        options:
+          speed: 400000
+`))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Robots[0].Devices[0].Addresses, []int{0x29, 0x39, 0x49})
+
+	r, err := c.Robots[0].Build()
+	gobottest.Assert(t, err, nil)
+	light := r.Device("light").(*i2c.TSL2561Driver)
+	gobottest.Assert(t, light.Config.(i2c.AddressesConfig).GetAddresses(), []int{0x29, 0x39, 0x49})
+	gobottest.Assert(t, light.Config.(i2c.SpeedConfig).GetSpeedOrDefault(0), i2c.FastSpeed)
+}
+
+func TestBuildJSON(t *testing.T) {
+	c, _ := ParseJSON([]byte(testJSON))
+	m, err := c.Build()
+	gobottest.Assert(t, err, nil)
+	_, ok := m.Robot("sensor").Device("temp").(*aio.AnalogSensorDriver)
+	gobottest.Assert(t, ok, true)
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		robot Robot
+		err   string
+	}{
+		{
+			Robot{Connections: []Connection{{Adaptor: "unknown"}}},
+			"No adaptor registered with the name unknown",
+		},
+		{
+			Robot{Connections: []Connection{{Name: "b", Adaptor: "broken"}}},
+			"connection b: broken adaptor",
+		},
+		{
+			Robot{Devices: []Device{{Name: "led", Driver: "led"}}},
+			"device led: no connection available",
+		},
+		{
+			Robot{
+				Connections: []Connection{{Adaptor: "test"}},
+				Devices:     []Device{{Name: "led", Driver: "led", Connection: "other"}},
+			},
+			"device led: No Connection found with the name other",
+		},
+		{
+			Robot{
+				Connections: []Connection{{Adaptor: "test"}},
+				Devices:     []Device{{Name: "x", Driver: "unknown"}},
+			},
+			"No driver registered with the name unknown",
+		},
+		{
+			Robot{
+				Connections: []Connection{{Adaptor: "pinless"}},
+				Devices:     []Device{{Name: "led", Driver: "led"}},
+			},
+			"device led: connection pinless does not support digital writes",
+		},
+		{
+			Robot{
+				Connections: []Connection{{Adaptor: "test"}},
+				Devices:     []Device{{Name: "rgb", Driver: "rgb-led", Pins: []string{"1"}}},
+			},
+			"device rgb: rgb-led requires 3 pins, found 1",
+		},
+	}
+	for _, test := range tests {
+		_, err := test.robot.Build()
+		gobottest.Refute(t, err, nil)
+		gobottest.Assert(t, err.Error(), test.err)
+	}
+
+	c := &Config{Robots: []Robot{tests[0].robot}}
+	_, err := c.Build()
+	gobottest.Assert(t, strings.HasPrefix(err.Error(), "No adaptor"), true)
+}
+
+func TestInterval(t *testing.T) {
+	gobottest.Assert(t, len(interval(Device{})), 0)
+	gobottest.Assert(t, interval(Device{Options: Options{"interval": "50ms"}}), []time.Duration{50 * time.Millisecond})
+	gobottest.Assert(t, interval(Device{Options: Options{"interval": 20}}), []time.Duration{20 * time.Millisecond})
+}
+
+type polledDriver struct {
+	*gpio.ButtonDriver
+	polling *gobot.PollerConfig
+}
+
+func (d *polledDriver) SetPolling(c gobot.PollerConfig) { d.polling = &c }
+
+func TestWithPolling(t *testing.T) {
+	d := &polledDriver{ButtonDriver: gpio.NewButtonDriver(newTestAdaptor(""), "1")}
+	withPolling(d, Device{})
+	gobottest.Assert(t, d.polling == nil, true)
+
+	withPolling(d, Device{Options: Options{"max_interval": "1s", "backoff": 1.5}})
+	gobottest.Assert(t, *d.polling, gobot.PollerConfig{MaxInterval: time.Second, Backoff: 1.5})
+
+	withPolling(d, Device{Options: Options{"interval": 20}})
+	gobottest.Assert(t, *d.polling, gobot.PollerConfig{Interval: 20 * time.Millisecond})
+
+	led := gpio.NewLedDriver(newTestAdaptor(""), "1")
+	gobottest.Assert(t, withPolling(led, Device{Options: Options{"max_interval": "1s"}}), gobot.Driver(led))
+}
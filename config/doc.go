@@ -0,0 +1,63 @@
+/*
+Package config builds Gobot robots from a declarative YAML or JSON description
+of their connections and devices, so that simple robots can be rewired without
+recompiling.
+
+Example robot.yaml:
+
+	robots:
+	  - name: blinker
+	    metadata:
+	      location: garage
+	    connections:
+	      - name: arduino
+	        adaptor: firmata
+	        options:
+	          port: /dev/ttyACM0
+	    devices:
+	      - name: led
+	        driver: led
+	        connection: arduino
+	        pin: "13"
+	        metadata:
+	          color: red
+	      - name: light
+	        driver: tsl2561
+	        connection: arduino
+	        bus: 1
+	        address: 0x39
+
+The drivers polling a sensor, such as button or analog-sensor, read the
+"interval", "max_interval" and "backoff" options configuring their
+gobot.Poller: while the sensor value does not change, the polling interval
+grows by backoff up to max_interval.
+
+The i2c drivers may list the "addresses" to try in order instead of their
+address, for the boards with selectable addresses, and read the "speed"
+option setting the clock of their bus in Hertz.
+
+Adaptors and drivers are looked up by name in a registry. The drivers from the
+gpio, aio and i2c packages are registered by default; adaptors are registered
+using RegisterAdaptor:
+
+	config.RegisterAdaptor("firmata", func(o config.Options) (gobot.Adaptor, error) {
+		return firmata.NewAdaptor(o.String("port", "/dev/ttyACM0")), nil
+	})
+
+	cfg, err := config.Load("robot.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	master, err := cfg.Build()
+	if err != nil {
+		log.Fatal(err)
+	}
+	master.Start()
+
+Out-of-tree modules register their adaptors and drivers with RegisterPlugin,
+from the init function of their package, so that importing the package is
+enough to use them in a configuration:
+
+	import _ "example.com/acme/gobot-acme"
+*/
+package config // import "gobot.io/x/gobot/config"
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+type testAdaptor struct {
+	name string
+	port string
+}
+
+func newTestAdaptor(port string) *testAdaptor {
+	return &testAdaptor{name: "test", port: port}
+}
+
+func (t *testAdaptor) Name() string                              { return t.name }
+func (t *testAdaptor) SetName(n string)                          { t.name = n }
+func (t *testAdaptor) Connect() error                            { return nil }
+func (t *testAdaptor) Finalize() error                           { return nil }
+func (t *testAdaptor) Port() string                              { return t.port }
+func (t *testAdaptor) DigitalWrite(pin string, level byte) error { return nil }
+func (t *testAdaptor) DigitalRead(pin string) (int, error)       { return 0, nil }
+func (t *testAdaptor) AnalogRead(pin string) (int, error)        { return 0, nil }
+func (t *testAdaptor) GetDefaultBus() int                        { return 0 }
+func (t *testAdaptor) GetConnection(address int, bus int) (i2c.Connection, error) {
+	return nil, errors.New("no i2c bus")
+}
+
+type pinlessAdaptor struct {
+	name string
+}
+
+func (t *pinlessAdaptor) Name() string     { return t.name }
+func (t *pinlessAdaptor) SetName(n string) { t.name = n }
+func (t *pinlessAdaptor) Connect() error   { return nil }
+func (t *pinlessAdaptor) Finalize() error  { return nil }
+
+func init() {
+	RegisterAdaptor("test", func(o Options) (gobot.Adaptor, error) {
+		return newTestAdaptor(o.String("port", "/dev/null")), nil
+	})
+	RegisterAdaptor("pinless", func(o Options) (gobot.Adaptor, error) {
+		return &pinlessAdaptor{name: "pinless"}, nil
+	})
+	RegisterAdaptor("broken", func(o Options) (gobot.Adaptor, error) {
+		return nil, errors.New("broken adaptor")
+	})
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Plugin describes the hardware support of an out-of-tree module: the
+// Adaptors and Drivers it makes available to the configurations under their
+// name.
+type Plugin struct {
+	Name        string
+	Version     string
+	Description string
+	Adaptors    map[string]AdaptorFactory
+	Drivers     map[string]DriverFactory
+}
+
+var plugins = make(map[string]Plugin)
+
+// RegisterPlugin registers the Adaptors and Drivers of the plugin. It is
+// meant to be called from the init function of the plugin package, so that
+// importing the package, or loading it with the run command of the CLI, is
+// enough to use its hardware in a configuration:
+//
+//	func init() {
+//		config.RegisterPlugin(config.Plugin{
+//			Name:    "acme",
+//			Version: "1.0.0",
+//			Drivers: map[string]config.DriverFactory{
+//				"acme-thermometer": newThermometer,
+//			},
+//		})
+//	}
+//
+// As sql.Register does, RegisterPlugin panics when the plugin has no name,
+// or when the plugin or one of its Adaptors or Drivers is already
+// registered, since two plugins providing the same name is a build mistake.
+func RegisterPlugin(p Plugin) {
+	if p.Name == "" {
+		panic("config: RegisterPlugin plugin has no name")
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if _, ok := plugins[p.Name]; ok {
+		panic(fmt.Sprintf("config: RegisterPlugin called twice for plugin %v", p.Name))
+	}
+	for name, f := range p.Adaptors {
+		if _, ok := adaptors[name]; ok {
+			panic(fmt.Sprintf("config: plugin %v registers adaptor %v, which is already registered", p.Name, name))
+		}
+		if f == nil {
+			panic(fmt.Sprintf("config: plugin %v registers a nil adaptor %v", p.Name, name))
+		}
+	}
+	for name, f := range p.Drivers {
+		if _, ok := drivers[name]; ok {
+			panic(fmt.Sprintf("config: plugin %v registers driver %v, which is already registered", p.Name, name))
+		}
+		if f == nil {
+			panic(fmt.Sprintf("config: plugin %v registers a nil driver %v", p.Name, name))
+		}
+	}
+
+	for name, f := range p.Adaptors {
+		adaptors[name] = f
+	}
+	for name, f := range p.Drivers {
+		drivers[name] = f
+	}
+	plugins[p.Name] = p
+}
+
+// unregisterPlugin removes the plugin and its Adaptors and Drivers, so that
+// the tests registering a plugin can run more than once
+func unregisterPlugin(name string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	p, ok := plugins[name]
+	if !ok {
+		return
+	}
+	for adaptor := range p.Adaptors {
+		delete(adaptors, adaptor)
+	}
+	for driver := range p.Drivers {
+		delete(drivers, driver)
+	}
+	delete(plugins, name)
+}
+
+// Plugins returns the registered plugins sorted by name
+func Plugins() []Plugin {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	list := []Plugin{}
+	for _, p := range plugins {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestRegisterPlugin(t *testing.T) {
+	defer unregisterPlugin("plugin-test")
+	RegisterPlugin(Plugin{
+		Name:    "plugin-test",
+		Version: "1.0.0",
+		Adaptors: map[string]AdaptorFactory{
+			"plugin-test-adaptor": func(o Options) (gobot.Adaptor, error) {
+				return newTestAdaptor(o.String("port", "")), nil
+			},
+		},
+		Drivers: map[string]DriverFactory{
+			"plugin-test-led": func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+				f, _ := driverFactory("led")
+				return f(conn, d)
+			},
+		},
+	})
+
+	found := false
+	for _, p := range Plugins() {
+		if p.Name == "plugin-test" {
+			found = true
+			gobottest.Assert(t, p.Version, "1.0.0")
+		}
+	}
+	gobottest.Assert(t, found, true)
+
+	cfg, err := ParseYAML([]byte(`
+robots:
+  - name: plugged
+    connections:
+      - name: board
+        adaptor: plugin-test-adaptor
+        options:
+          port: /dev/plugin
+    devices:
+      - name: led
+        driver: plugin-test-led
+        pin: "13"
+`))
+	gobottest.Assert(t, err, nil)
+	r, err := cfg.Robots[0].Build()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r.Connection("board").(*testAdaptor).Port(), "/dev/plugin")
+	gobottest.Refute(t, r.Device("led"), nil)
+}
+
+func TestUnregisterPlugin(t *testing.T) {
+	RegisterPlugin(Plugin{
+		Name: "plugin-unregister",
+		Adaptors: map[string]AdaptorFactory{
+			"plugin-unregister-adaptor": func(o Options) (gobot.Adaptor, error) { return nil, nil },
+		},
+		Drivers: map[string]DriverFactory{
+			"plugin-unregister-driver": func(conn gobot.Connection, d Device) (gobot.Driver, error) { return nil, nil },
+		},
+	})
+	unregisterPlugin("plugin-unregister")
+	for _, p := range Plugins() {
+		gobottest.Refute(t, p.Name, "plugin-unregister")
+	}
+	_, err := adaptorFactory("plugin-unregister-adaptor")
+	gobottest.Refute(t, err, nil)
+	_, err = driverFactory("plugin-unregister-driver")
+	gobottest.Refute(t, err, nil)
+	unregisterPlugin("plugin-unregister")
+}
+
+func TestRegisterPluginTwice(t *testing.T) {
+	RegisterPlugin(Plugin{Name: "plugin-twice"})
+	defer unregisterPlugin("plugin-twice")
+	defer func() {
+		gobottest.Refute(t, recover(), nil)
+	}()
+	RegisterPlugin(Plugin{Name: "plugin-twice"})
+}
+
+func TestRegisterPluginNoName(t *testing.T) {
+	defer func() {
+		gobottest.Refute(t, recover(), nil)
+	}()
+	RegisterPlugin(Plugin{})
+}
+
+func TestRegisterPluginDriverClash(t *testing.T) {
+	defer func() {
+		gobottest.Refute(t, recover(), nil)
+		for _, p := range Plugins() {
+			gobottest.Refute(t, p.Name, "plugin-clash")
+		}
+	}()
+	RegisterPlugin(Plugin{
+		Name: "plugin-clash",
+		Drivers: map[string]DriverFactory{
+			"led": func(conn gobot.Connection, d Device) (gobot.Driver, error) { return nil, nil },
+		},
+	})
+}
+
+func TestRegisterPluginNilAdaptor(t *testing.T) {
+	defer func() {
+		gobottest.Refute(t, recover(), nil)
+	}()
+	RegisterPlugin(Plugin{
+		Name:     "plugin-nil",
+		Adaptors: map[string]AdaptorFactory{"plugin-nil-adaptor": nil},
+	})
+}
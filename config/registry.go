@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Options holds the free-form options of a connection or device.
+type Options map[string]interface{}
+
+// AdaptorFactory creates a new Adaptor from its options.
+type AdaptorFactory func(o Options) (gobot.Adaptor, error)
+
+// DriverFactory creates a new Driver for the given connection and device
+// configuration.
+type DriverFactory func(conn gobot.Connection, d Device) (gobot.Driver, error)
+
+var (
+	registryMutex sync.RWMutex
+	adaptors      = make(map[string]AdaptorFactory)
+	drivers       = make(map[string]DriverFactory)
+)
+
+// RegisterAdaptor makes an Adaptor available to configurations under name.
+// Registering a name twice replaces the previous factory.
+func RegisterAdaptor(name string, f AdaptorFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	adaptors[name] = f
+}
+
+// RegisterDriver makes a Driver available to configurations under name.
+// Registering a name twice replaces the previous factory.
+func RegisterDriver(name string, f DriverFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	drivers[name] = f
+}
+
+// Adaptors returns the sorted names of the registered Adaptors
+func Adaptors() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	names := []string{}
+	for name := range adaptors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Drivers returns the sorted names of the registered Drivers
+func Drivers() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	names := []string{}
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func adaptorFactory(name string) (AdaptorFactory, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	if f, ok := adaptors[name]; ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("No adaptor registered with the name %v", name)
+}
+
+func driverFactory(name string) (DriverFactory, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	if f, ok := drivers[name]; ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("No driver registered with the name %v", name)
+}
+
+// String returns the option key as a string, or def if it is not set.
+func (o Options) String(key string, def string) string {
+	v, ok := o[key]
+	if !ok {
+		return def
+	}
+	return fmt.Sprint(v)
+}
+
+// Int returns the option key as an int, or def if it is not set or is not a
+// number.
+func (o Options) Int(key string, def int) int {
+	switch v := o[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		if i, err := strconv.ParseInt(v, 0, 0); err == nil {
+			return int(i)
+		}
+	}
+	return def
+}
+
+// Float returns the option key as a float64, or def if it is not set or is not
+// a number.
+func (o Options) Float(key string, def float64) float64 {
+	switch v := o[key].(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// Bool returns the option key as a bool, or def if it is not set.
+func (o Options) Bool(key string, def bool) bool {
+	switch v := o[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// Duration returns the option key parsed as a duration such as "10ms", or def
+// if it is not set or invalid. Plain numbers are read as milliseconds.
+func (o Options) Duration(key string, def time.Duration) time.Duration {
+	switch v := o[key].(type) {
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	case int, int64, float64:
+		return time.Duration(o.Float(key, 0) * float64(time.Millisecond))
+	}
+	return def
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestRegisterAdaptor(t *testing.T) {
+	RegisterAdaptor("registry-test", func(o Options) (gobot.Adaptor, error) {
+		return newTestAdaptor("registry"), nil
+	})
+	f, err := adaptorFactory("registry-test")
+	gobottest.Assert(t, err, nil)
+	a, _ := f(Options{})
+	gobottest.Assert(t, a.(*testAdaptor).Port(), "registry")
+
+	found := false
+	for _, name := range Adaptors() {
+		if name == "registry-test" {
+			found = true
+		}
+	}
+	gobottest.Assert(t, found, true)
+
+	_, err = adaptorFactory("missing")
+	gobottest.Assert(t, err.Error(), "No adaptor registered with the name missing")
+}
+
+func TestRegisterDriver(t *testing.T) {
+	RegisterDriver("registry-test", func(conn gobot.Connection, d Device) (gobot.Driver, error) {
+		return nil, nil
+	})
+	_, err := driverFactory("registry-test")
+	gobottest.Assert(t, err, nil)
+
+	_, err = driverFactory("missing")
+	gobottest.Assert(t, err.Error(), "No driver registered with the name missing")
+}
+
+func TestBuiltinDrivers(t *testing.T) {
+	names := Drivers()
+	for _, name := range []string{"led", "button", "servo", "rgb-led", "analog-sensor", "analog-actuator", "mpu6050"} {
+		found := false
+		for _, n := range names {
+			if n == name {
+				found = true
+			}
+		}
+		gobottest.Assert(t, found, true)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	o := Options{
+		"name":     "bob",
+		"int":      12,
+		"hex":      "0x10",
+		"float":    1.5,
+		"bool":     true,
+		"boolstr":  "false",
+		"duration": "2s",
+		"ms":       250,
+	}
+
+	gobottest.Assert(t, o.String("name", ""), "bob")
+	gobottest.Assert(t, o.String("int", ""), "12")
+	gobottest.Assert(t, o.String("missing", "def"), "def")
+
+	gobottest.Assert(t, o.Int("int", 0), 12)
+	gobottest.Assert(t, o.Int("hex", 0), 16)
+	gobottest.Assert(t, o.Int("float", 0), 1)
+	gobottest.Assert(t, o.Int("name", 7), 7)
+
+	gobottest.Assert(t, o.Float("float", 0), 1.5)
+	gobottest.Assert(t, o.Float("int", 0), 12.0)
+	gobottest.Assert(t, o.Float("missing", 2.5), 2.5)
+
+	gobottest.Assert(t, o.Bool("bool", false), true)
+	gobottest.Assert(t, o.Bool("boolstr", true), false)
+	gobottest.Assert(t, o.Bool("name", true), true)
+
+	gobottest.Assert(t, o.Duration("duration", 0), 2*time.Second)
+	gobottest.Assert(t, o.Duration("ms", 0), 250*time.Millisecond)
+	gobottest.Assert(t, o.Duration("name", time.Second), time.Second)
+}
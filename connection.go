@@ -1,7 +1,6 @@
 package gobot
 
 import (
-	"log"
 	"reflect"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -41,18 +40,26 @@ func (c *Connections) Each(f func(Connection)) {
 
 // Start calls Connect on each Connection in c
 func (c *Connections) Start() (err error) {
-	log.Println("Starting connections...")
+	return c.start(DefaultLogger(), nil)
+}
+
+// start connects each Connection, calling started, unless nil, with those
+// which connected
+func (c *Connections) start(l Logger, started func(Connection)) (err error) {
+	l.Info("Starting connections...")
 	for _, connection := range *c {
-		info := "Starting connection " + connection.Name()
+		keyvals := []interface{}{"connection", connection.Name()}
 
 		if porter, ok := connection.(Porter); ok {
-			info = info + " on port " + porter.Port()
+			keyvals = append(keyvals, "port", porter.Port())
 		}
 
-		log.Println(info + "...")
+		l.Info("Starting connection", keyvals...)
 
 		if cerr := connection.Connect(); cerr != nil {
-			err = multierror.Append(err, cerr)
+			err = multierror.Append(err, WrapError("connect", connection.Name(), cerr))
+		} else if started != nil {
+			started(connection)
 		}
 	}
 	return err
@@ -62,7 +69,7 @@ func (c *Connections) Start() (err error) {
 func (c *Connections) Finalize() (err error) {
 	for _, connection := range *c {
 		if cerr := connection.Finalize(); cerr != nil {
-			err = multierror.Append(err, cerr)
+			err = multierror.Append(err, WrapError("finalize", connection.Name(), cerr))
 		}
 	}
 	return err
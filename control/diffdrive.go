@@ -0,0 +1,177 @@
+package control
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// Motor is a motor that can turn both ways, such as gpio.MotorDriver.
+type Motor interface {
+	Forward(speed byte) error
+	Backward(speed byte) error
+}
+
+// DifferentialDrive describes a robot with two independently driven wheels,
+// and converts between the velocity of the robot and the speed of each wheel.
+// Distances are in meters, angles in radians and times in seconds.
+type DifferentialDrive struct {
+	// WheelRadius is the radius of the wheels
+	WheelRadius float64
+	// TrackWidth is the distance between the wheels
+	TrackWidth float64
+	// MaxWheelSpeed is the ground speed of a wheel driven at full speed
+	MaxWheelSpeed float64
+	// TicksPerRevolution is the resolution of the wheel encoders
+	TicksPerRevolution float64
+}
+
+// NewDifferentialDrive returns a new DifferentialDrive for the given wheel
+// radius and track width.
+func NewDifferentialDrive(wheelRadius, trackWidth float64) *DifferentialDrive {
+	return &DifferentialDrive{
+		WheelRadius: wheelRadius,
+		TrackWidth:  trackWidth,
+	}
+}
+
+// WheelSpeeds returns the ground speed of each wheel needed to move at linear
+// speed while turning at angular speed, counterclockwise being positive.
+func (d *DifferentialDrive) WheelSpeeds(linear, angular float64) (left, right float64) {
+	return linear - angular*d.TrackWidth/2, linear + angular*d.TrackWidth/2
+}
+
+// Velocity returns the linear and angular speeds of the robot for the given
+// ground speeds of the wheels.
+func (d *DifferentialDrive) Velocity(left, right float64) (linear, angular float64) {
+	return (left + right) / 2, (right - left) / d.TrackWidth
+}
+
+// MotorSpeeds returns the speed of each wheel, between -1 and 1, relative to
+// MaxWheelSpeed. When a wheel would need to turn faster than MaxWheelSpeed
+// both speeds are scaled down, so that the robot still follows the same
+// curve, only slower.
+func (d *DifferentialDrive) MotorSpeeds(linear, angular float64) (left, right float64, err error) {
+	if d.MaxWheelSpeed <= 0 {
+		return 0, 0, errors.New("MaxWheelSpeed must be set to compute motor speeds")
+	}
+	left, right = d.WheelSpeeds(linear, angular)
+	left /= d.MaxWheelSpeed
+	right /= d.MaxWheelSpeed
+	if m := math.Max(math.Abs(left), math.Abs(right)); m > 1 {
+		left /= m
+		right /= m
+	}
+	return left, right, nil
+}
+
+// Drive sets the left and right Motors to move at linear speed while turning
+// at angular speed.
+func (d *DifferentialDrive) Drive(left, right Motor, linear, angular float64) error {
+	l, r, err := d.MotorSpeeds(linear, angular)
+	if err != nil {
+		return err
+	}
+	if err := driveMotor(left, l); err != nil {
+		return err
+	}
+	return driveMotor(right, r)
+}
+
+func driveMotor(m Motor, speed float64) error {
+	value := byte(math.Round(math.Abs(speed) * 255))
+	if speed < 0 {
+		return m.Backward(value)
+	}
+	return m.Forward(value)
+}
+
+// Distance returns the ground distance covered by a wheel for ticks encoder
+// ticks.
+func (d *DifferentialDrive) Distance(ticks int64) float64 {
+	if d.TicksPerRevolution <= 0 {
+		return 0
+	}
+	return float64(ticks) / d.TicksPerRevolution * 2 * math.Pi * d.WheelRadius
+}
+
+// Pose is the position and heading of a robot. Theta is between -Pi and Pi,
+// 0 facing the X axis.
+type Pose struct {
+	X, Y, Theta float64
+}
+
+// Odometry estimates the Pose of a DifferentialDrive robot from the tick
+// counts of its wheel encoders.
+type Odometry struct {
+	drive       *DifferentialDrive
+	pose        Pose
+	left, right int64
+	initialized bool
+	mutex       sync.Mutex
+}
+
+// NewOdometry returns a new Odometry for d, starting at the origin.
+func NewOdometry(d *DifferentialDrive) *Odometry {
+	return &Odometry{drive: d}
+}
+
+// Update integrates the total tick counts of the left and right encoders and
+// returns the new Pose. The first Update only records the counts.
+func (o *Odometry) Update(leftTicks, rightTicks int64) Pose {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.initialized {
+		o.left, o.right = leftTicks, rightTicks
+		o.initialized = true
+		return o.pose
+	}
+
+	left := o.drive.Distance(leftTicks - o.left)
+	right := o.drive.Distance(rightTicks - o.right)
+	o.left, o.right = leftTicks, rightTicks
+	o.integrate(left, right)
+	return o.pose
+}
+
+// Move integrates the ground distances covered by the left and right wheels,
+// for robots measuring them without encoders, and returns the new Pose.
+func (o *Odometry) Move(left, right float64) Pose {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.integrate(left, right)
+	return o.pose
+}
+
+func (o *Odometry) integrate(left, right float64) {
+	distance, dtheta := o.drive.Velocity(left, right)
+	// follow the arc using the heading at its middle
+	heading := o.pose.Theta + dtheta/2
+	o.pose.X += distance * math.Cos(heading)
+	o.pose.Y += distance * math.Sin(heading)
+	o.pose.Theta = normalizeAngle(o.pose.Theta + dtheta)
+}
+
+// Pose returns the current Pose
+func (o *Odometry) Pose() Pose {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.pose
+}
+
+// Reset sets the current Pose, keeping the encoder counts.
+func (o *Odometry) Reset(p Pose) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	p.Theta = normalizeAngle(p.Theta)
+	o.pose = p
+}
+
+func normalizeAngle(a float64) float64 {
+	a = math.Mod(a+math.Pi, 2*math.Pi)
+	if a <= 0 {
+		a += 2 * math.Pi
+	}
+	return a - math.Pi
+}
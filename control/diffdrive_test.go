@@ -0,0 +1,127 @@
+package control
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ Motor = (*gpio.MotorDriver)(nil)
+
+type testMotor struct {
+	direction string
+	speed     byte
+	err       error
+}
+
+func (m *testMotor) Forward(speed byte) error {
+	m.direction, m.speed = "forward", speed
+	return m.err
+}
+
+func (m *testMotor) Backward(speed byte) error {
+	m.direction, m.speed = "backward", speed
+	return m.err
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestDifferentialDriveWheelSpeeds(t *testing.T) {
+	d := NewDifferentialDrive(0.03, 0.2)
+	left, right := d.WheelSpeeds(1, 0)
+	gobottest.Assert(t, []float64{left, right}, []float64{1, 1})
+
+	left, right = d.WheelSpeeds(0, 2)
+	gobottest.Assert(t, []float64{left, right}, []float64{-0.2, 0.2})
+
+	linear, angular := d.Velocity(-0.2, 0.2)
+	gobottest.Assert(t, near(linear, 0), true)
+	gobottest.Assert(t, near(angular, 2), true)
+}
+
+func TestDifferentialDriveMotorSpeeds(t *testing.T) {
+	d := NewDifferentialDrive(0.03, 0.2)
+	_, _, err := d.MotorSpeeds(1, 0)
+	gobottest.Refute(t, err, nil)
+
+	d.MaxWheelSpeed = 0.5
+	left, right, err := d.MotorSpeeds(0.25, 0)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, []float64{left, right}, []float64{0.5, 0.5})
+
+	// saturated speeds keep their ratio
+	left, right, _ = d.MotorSpeeds(1, 5)
+	gobottest.Assert(t, near(right, 1), true)
+	gobottest.Assert(t, near(left, 0.5/1.5), true)
+}
+
+func TestDifferentialDriveDrive(t *testing.T) {
+	d := NewDifferentialDrive(0.03, 0.2)
+	d.MaxWheelSpeed = 1
+	left, right := &testMotor{}, &testMotor{}
+
+	gobottest.Assert(t, d.Drive(left, right, 0, 5), nil)
+	gobottest.Assert(t, left.direction, "backward")
+	gobottest.Assert(t, left.speed, byte(128))
+	gobottest.Assert(t, right.direction, "forward")
+	gobottest.Assert(t, right.speed, byte(128))
+
+	left.err = errors.New("write error")
+	gobottest.Assert(t, d.Drive(left, right, 1, 0), left.err)
+}
+
+func TestOdometryStraight(t *testing.T) {
+	d := NewDifferentialDrive(1/(2*math.Pi), 0.2)
+	d.TicksPerRevolution = 100
+	gobottest.Assert(t, d.Distance(50), 0.5)
+
+	o := NewOdometry(d)
+	gobottest.Assert(t, o.Update(1000, 1000), Pose{})
+	p := o.Update(1100, 1100)
+	gobottest.Assert(t, near(p.X, 1), true)
+	gobottest.Assert(t, near(p.Y, 0), true)
+	gobottest.Assert(t, p.Theta, 0.0)
+}
+
+func TestOdometryTurn(t *testing.T) {
+	d := NewDifferentialDrive(0.03, 0.2)
+	o := NewOdometry(d)
+
+	// spin a quarter turn in place
+	quarter := math.Pi / 2 * 0.1
+	p := o.Move(-quarter, quarter)
+	gobottest.Assert(t, near(p.X, 0), true)
+	gobottest.Assert(t, near(p.Theta, math.Pi/2), true)
+
+	// then drive forward along Y
+	p = o.Move(1, 1)
+	gobottest.Assert(t, near(p.X, 0), true)
+	gobottest.Assert(t, near(p.Y, 1), true)
+}
+
+func TestOdometryCircle(t *testing.T) {
+	d := NewDifferentialDrive(0.03, 0.2)
+	o := NewOdometry(d)
+	// a full circle of radius 1 in small steps
+	for i := 0; i < 1000; i++ {
+		step := 2 * math.Pi / 1000
+		o.Move(step*0.9, step*1.1)
+	}
+	p := o.Pose()
+	gobottest.Assert(t, math.Abs(p.X) < 1e-3, true)
+	gobottest.Assert(t, math.Abs(p.Y) < 1e-3, true)
+	gobottest.Assert(t, math.Abs(p.Theta) < 1e-6, true)
+}
+
+func TestOdometryReset(t *testing.T) {
+	o := NewOdometry(NewDifferentialDrive(0.03, 0.2))
+	o.Reset(Pose{X: 1, Y: 2, Theta: 3 * math.Pi})
+	p := o.Pose()
+	gobottest.Assert(t, p.X, 1.0)
+	gobottest.Assert(t, near(p.Theta, math.Pi), true)
+}
@@ -0,0 +1,42 @@
+/*
+Package control provides the control math shared by many robots: a PID
+controller, filters to smooth noisy sensor readings, the kinematics and
+odometry of differential drive robots, the localization of outdoor robots
+fusing a GPS and an IMU, and a WaypointFollower driving a robot through a
+list of waypoints.
+
+A balancing robot could use them like this:
+
+	pid := control.NewPID(25, 1.5, 0.8)
+	pid.SetOutputLimits(-255, 255)
+	filter := control.NewLowPass(0.2)
+
+	robot.Loop(100, func() {
+		angle := filter.Update(readAngle())
+		speed := pid.Update(0, angle, 10*time.Millisecond)
+		setMotors(speed)
+	})
+
+A rover could estimate its Pose, published as the "pose" event of a
+LocalizationDriver, like this:
+
+	localization := control.NewLocalizationDriver(gps, imu,
+		control.NewEKFEstimator(0.01, 4, 0.05))
+
+	localization.On(control.PoseEvent, func(data interface{}) {
+		navigate(data.(control.Pose))
+	})
+
+and drive it around a field, stopping in front of the obstacles:
+
+	follower := control.NewWaypointFollower(localization, drive, leftMotor, rightMotor)
+	follower.SetWaypoints(control.Waypoint{X: 10}, control.Waypoint{X: 10, Y: 10})
+	follower.OnObstacle(func(control.Pose) bool {
+		distance, _ := sonar.Distance()
+		return distance < 30
+	})
+	follower.On(control.ArrivedEvent, func(data interface{}) {
+		fmt.Println("arrived")
+	})
+*/
+package control // import "gobot.io/x/gobot/control"
@@ -0,0 +1,128 @@
+package control
+
+import (
+	"math"
+	"sync"
+)
+
+// Filter smooths a stream of samples.
+type Filter interface {
+	// Update adds a sample and returns the filtered value
+	Update(sample float64) float64
+	// Value returns the current filtered value
+	Value() float64
+	// Reset forgets the samples
+	Reset()
+}
+
+// LowPass is an exponential moving average filter.
+type LowPass struct {
+	alpha       float64
+	value       float64
+	initialized bool
+	mutex       sync.Mutex
+}
+
+// NewLowPass returns a new LowPass filter giving weight alpha, between 0 and
+// 1, to the filtered value and 1 - alpha to each new sample. Values closer to
+// 1 smooth more but react more slowly.
+func NewLowPass(alpha float64) *LowPass {
+	return &LowPass{alpha: clamp(alpha, 0, 1)}
+}
+
+// NewLowPassCutoff returns a new LowPass filter attenuating the frequencies
+// above cutoff, in Hz, for samples taken sampleRate times per second.
+func NewLowPassCutoff(cutoff, sampleRate float64) *LowPass {
+	rc := 1 / (2 * math.Pi * cutoff)
+	dt := 1 / sampleRate
+	return NewLowPass(rc / (rc + dt))
+}
+
+// Update adds a sample and returns the filtered value. The first sample is
+// returned as is.
+func (f *LowPass) Update(sample float64) float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.initialized {
+		f.value = sample
+		f.initialized = true
+	} else {
+		f.value = f.alpha*f.value + (1-f.alpha)*sample
+	}
+	return f.value
+}
+
+// Value returns the current filtered value
+func (f *LowPass) Value() float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.value
+}
+
+// Reset forgets the samples
+func (f *LowPass) Reset() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.value = 0
+	f.initialized = false
+}
+
+// Kalman is a one dimensional Kalman filter for a value that is expected to
+// stay constant between samples, such as a distance or a temperature.
+type Kalman struct {
+	q, r        float64
+	value       float64
+	p           float64
+	initialized bool
+	mutex       sync.Mutex
+}
+
+// NewKalman returns a new Kalman filter. processNoise is the variance of the
+// actual changes of the value between two samples, measurementNoise the
+// variance of the sensor readings. A lower processNoise smooths more.
+func NewKalman(processNoise, measurementNoise float64) *Kalman {
+	return &Kalman{q: processNoise, r: measurementNoise}
+}
+
+// Update adds a sample and returns the estimated value. The first sample is
+// returned as is.
+func (f *Kalman) Update(sample float64) float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.initialized {
+		f.value = sample
+		f.p = f.r
+		f.initialized = true
+		return f.value
+	}
+	// predict
+	f.p += f.q
+	// correct
+	gain := f.p / (f.p + f.r)
+	f.value += gain * (sample - f.value)
+	f.p *= 1 - gain
+	return f.value
+}
+
+// Value returns the current estimate
+func (f *Kalman) Value() float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.value
+}
+
+// Variance returns the variance of the current estimate
+func (f *Kalman) Variance() float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.p
+}
+
+// Reset forgets the samples
+func (f *Kalman) Reset() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.value = 0
+	f.p = 0
+	f.initialized = false
+}
@@ -0,0 +1,59 @@
+package control
+
+import (
+	"math"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ Filter = (*LowPass)(nil)
+var _ Filter = (*Kalman)(nil)
+
+func TestLowPass(t *testing.T) {
+	f := NewLowPass(0.5)
+	gobottest.Assert(t, f.Update(10), 10.0)
+	gobottest.Assert(t, f.Update(20), 15.0)
+	gobottest.Assert(t, f.Update(20), 17.5)
+	gobottest.Assert(t, f.Value(), 17.5)
+
+	f.Reset()
+	gobottest.Assert(t, f.Value(), 0.0)
+	gobottest.Assert(t, f.Update(4), 4.0)
+}
+
+func TestLowPassAlphaLimits(t *testing.T) {
+	gobottest.Assert(t, NewLowPass(2).alpha, 1.0)
+	gobottest.Assert(t, NewLowPass(-1).alpha, 0.0)
+}
+
+func TestLowPassCutoff(t *testing.T) {
+	f := NewLowPassCutoff(1, 100)
+	gobottest.Assert(t, math.Abs(f.alpha-0.9408) < 0.0001, true)
+}
+
+func TestKalman(t *testing.T) {
+	f := NewKalman(0.01, 4)
+	gobottest.Assert(t, f.Update(10), 10.0)
+	gobottest.Assert(t, f.Variance(), 4.0)
+
+	// alternating noise around 10 is smoothed out
+	for i := 0; i < 100; i++ {
+		f.Update(10 + 2*float64(1-2*(i%2)))
+	}
+	gobottest.Assert(t, math.Abs(f.Value()-10) < 0.5, true)
+	gobottest.Assert(t, f.Variance() < 4, true)
+
+	f.Reset()
+	gobottest.Assert(t, f.Value(), 0.0)
+	gobottest.Assert(t, f.Update(3), 3.0)
+}
+
+func TestKalmanTracksChange(t *testing.T) {
+	f := NewKalman(1, 1)
+	f.Update(0)
+	for i := 0; i < 20; i++ {
+		f.Update(50)
+	}
+	gobottest.Assert(t, math.Abs(f.Value()-50) < 0.1, true)
+}
@@ -0,0 +1,295 @@
+package control
+
+import (
+	"math"
+	"sync"
+)
+
+// PoseEstimator fuses the turns measured by a gyroscope with the positions
+// and headings measured by a GPS and a compass into a Pose. Distances are in
+// meters, angles in radians and times in seconds.
+type PoseEstimator interface {
+	// Predict moves the estimate forward by dt, the robot turning at yawRate,
+	// counterclockwise being positive
+	Predict(yawRate, dt float64) Pose
+	// CorrectPosition corrects the estimate with a measured position
+	CorrectPosition(x, y float64) Pose
+	// CorrectHeading corrects the estimate with a measured heading
+	CorrectHeading(theta float64) Pose
+	// Pose returns the current estimate
+	Pose() Pose
+	// Speed returns the estimated ground speed
+	Speed() float64
+	// Reset forgets the measurements
+	Reset()
+}
+
+// ComplementaryEstimator is a PoseEstimator trusting the gyroscope and the
+// speed for short term changes, and the GPS and compass in the long term.
+type ComplementaryEstimator struct {
+	alpha       float64
+	pose        Pose
+	speed       float64
+	elapsed     float64
+	lastX       float64
+	lastY       float64
+	initialized bool
+	mutex       sync.Mutex
+}
+
+// NewComplementaryEstimator returns a new ComplementaryEstimator giving
+// weight alpha, between 0 and 1, to the predicted pose and 1 - alpha to each
+// measurement. Values closer to 1 smooth more but drift longer.
+func NewComplementaryEstimator(alpha float64) *ComplementaryEstimator {
+	return &ComplementaryEstimator{alpha: clamp(alpha, 0, 1)}
+}
+
+// Predict moves the estimate forward by dt at the estimated speed
+func (e *ComplementaryEstimator) Predict(yawRate, dt float64) Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.pose.Theta = normalizeAngle(e.pose.Theta + yawRate*dt)
+	e.pose.X += e.speed * math.Cos(e.pose.Theta) * dt
+	e.pose.Y += e.speed * math.Sin(e.pose.Theta) * dt
+	e.elapsed += dt
+	return e.pose
+}
+
+// CorrectPosition blends the estimated position with a measured one, and
+// updates the speed from the distance covered since the previous one. The
+// first position is used as is.
+func (e *ComplementaryEstimator) CorrectPosition(x, y float64) Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.initialized {
+		e.pose.X, e.pose.Y = x, y
+		e.initialized = true
+	} else {
+		if e.elapsed > 0 {
+			// the speed is negative when moving backward
+			dx, dy := x-e.lastX, y-e.lastY
+			speed := (dx*math.Cos(e.pose.Theta) + dy*math.Sin(e.pose.Theta)) / e.elapsed
+			e.speed = e.alpha*e.speed + (1-e.alpha)*speed
+		}
+		e.pose.X = e.alpha*e.pose.X + (1-e.alpha)*x
+		e.pose.Y = e.alpha*e.pose.Y + (1-e.alpha)*y
+	}
+	e.lastX, e.lastY = x, y
+	e.elapsed = 0
+	return e.pose
+}
+
+// CorrectHeading blends the estimated heading with a measured one
+func (e *ComplementaryEstimator) CorrectHeading(theta float64) Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.pose.Theta = normalizeAngle(e.pose.Theta + (1-e.alpha)*normalizeAngle(theta-e.pose.Theta))
+	return e.pose
+}
+
+// Pose returns the current estimate
+func (e *ComplementaryEstimator) Pose() Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.pose
+}
+
+// Speed returns the estimated ground speed
+func (e *ComplementaryEstimator) Speed() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.speed
+}
+
+// Reset forgets the measurements
+func (e *ComplementaryEstimator) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.pose = Pose{}
+	e.speed = 0
+	e.elapsed = 0
+	e.initialized = false
+}
+
+// EKFEstimator is a PoseEstimator using an extended Kalman filter, whose
+// state is the position, heading and ground speed of the robot.
+type EKFEstimator struct {
+	processNoise  float64
+	positionNoise float64
+	headingNoise  float64
+	// state is X, Y, Theta and the speed
+	state       [4]float64
+	p           [4][4]float64
+	initialized bool
+	mutex       sync.Mutex
+}
+
+// NewEKFEstimator returns a new EKFEstimator. processNoise is the variance
+// of the changes of speed and of the gyroscope drift per second,
+// positionNoise the variance of the GPS positions and headingNoise the
+// variance of the compass headings.
+func NewEKFEstimator(processNoise, positionNoise, headingNoise float64) *EKFEstimator {
+	e := &EKFEstimator{
+		processNoise:  processNoise,
+		positionNoise: positionNoise,
+		headingNoise:  headingNoise,
+	}
+	e.reset()
+	return e
+}
+
+// Predict moves the estimate forward by dt at the estimated speed
+func (e *EKFEstimator) Predict(yawRate, dt float64) Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	theta, speed := e.state[2], e.state[3]
+	cos, sin := math.Cos(theta), math.Sin(theta)
+	e.state[0] += speed * cos * dt
+	e.state[1] += speed * sin * dt
+	e.state[2] = normalizeAngle(theta + yawRate*dt)
+
+	// jacobian of the motion
+	f := identity()
+	f[0][2], f[0][3] = -speed*sin*dt, cos*dt
+	f[1][2], f[1][3] = speed*cos*dt, sin*dt
+
+	var p [4][4]float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				for l := 0; l < 4; l++ {
+					p[i][j] += f[i][k] * e.p[k][l] * f[j][l]
+				}
+			}
+		}
+	}
+	p[2][2] += e.processNoise * dt
+	p[3][3] += e.processNoise * dt
+	e.p = p
+	return e.pose()
+}
+
+// CorrectPosition corrects the estimate with a measured position. The first
+// position is used as is.
+func (e *EKFEstimator) CorrectPosition(x, y float64) Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if !e.initialized {
+		e.state[0], e.state[1] = x, y
+		e.p[0][0], e.p[1][1] = e.positionNoise, e.positionNoise
+		e.initialized = true
+		return e.pose()
+	}
+
+	// innovation covariance, and its inverse
+	s00, s01 := e.p[0][0]+e.positionNoise, e.p[0][1]
+	s10, s11 := e.p[1][0], e.p[1][1]+e.positionNoise
+	det := s00*s11 - s01*s10
+	if det == 0 {
+		return e.pose()
+	}
+	i00, i01, i10, i11 := s11/det, -s01/det, -s10/det, s00/det
+
+	var k [4][2]float64
+	for i := 0; i < 4; i++ {
+		k[i][0] = e.p[i][0]*i00 + e.p[i][1]*i10
+		k[i][1] = e.p[i][0]*i01 + e.p[i][1]*i11
+	}
+
+	dx, dy := x-e.state[0], y-e.state[1]
+	for i := 0; i < 4; i++ {
+		e.state[i] += k[i][0]*dx + k[i][1]*dy
+	}
+	e.state[2] = normalizeAngle(e.state[2])
+
+	var p [4][4]float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			p[i][j] = e.p[i][j] - k[i][0]*e.p[0][j] - k[i][1]*e.p[1][j]
+		}
+	}
+	e.p = p
+	return e.pose()
+}
+
+// CorrectHeading corrects the estimate with a measured heading
+func (e *EKFEstimator) CorrectHeading(theta float64) Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	s := e.p[2][2] + e.headingNoise
+	if s == 0 {
+		return e.pose()
+	}
+	var k [4]float64
+	for i := 0; i < 4; i++ {
+		k[i] = e.p[i][2] / s
+	}
+
+	innovation := normalizeAngle(theta - e.state[2])
+	for i := 0; i < 4; i++ {
+		e.state[i] += k[i] * innovation
+	}
+	e.state[2] = normalizeAngle(e.state[2])
+
+	var p [4][4]float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			p[i][j] = e.p[i][j] - k[i]*e.p[2][j]
+		}
+	}
+	e.p = p
+	return e.pose()
+}
+
+// Pose returns the current estimate
+func (e *EKFEstimator) Pose() Pose {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.pose()
+}
+
+// Speed returns the estimated ground speed
+func (e *EKFEstimator) Speed() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.state[3]
+}
+
+// Variance returns the variances of the estimated X, Y and Theta
+func (e *EKFEstimator) Variance() (x, y, theta float64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.p[0][0], e.p[1][1], e.p[2][2]
+}
+
+// Reset forgets the measurements
+func (e *EKFEstimator) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.reset()
+}
+
+func (e *EKFEstimator) reset() {
+	e.state = [4]float64{}
+	// nothing is known until the first measurements: the heading could be
+	// any, and the speed up to a few meters per second
+	e.p = [4][4]float64{}
+	e.p[0][0], e.p[1][1] = 1e6, 1e6
+	e.p[2][2] = math.Pi * math.Pi
+	e.p[3][3] = 10
+	e.initialized = false
+}
+
+func (e *EKFEstimator) pose() Pose {
+	return Pose{X: e.state[0], Y: e.state[1], Theta: e.state[2]}
+}
+
+func identity() (m [4][4]float64) {
+	for i := range m {
+		m[i][i] = 1
+	}
+	return
+}
@@ -0,0 +1,224 @@
+package control
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const (
+	// PoseEvent is published by the LocalizationDriver with each estimated
+	// Pose
+	PoseEvent = "pose"
+	// ErrorEvent is published by the LocalizationDriver with the errors
+	// reading its sensors
+	ErrorEvent = "error"
+)
+
+// earthRadius is the mean radius of the Earth, in meters
+const earthRadius = 6371000
+
+// ErrNoFix is returned by a GPS which has not computed its position yet.
+var ErrNoFix = errors.New("no GPS fix")
+
+// GPSFix is a position computed by a GPS.
+type GPSFix struct {
+	// Latitude and Longitude are in degrees
+	Latitude, Longitude float64
+	// Time is the time of the fix, which tells the new fixes apart
+	Time time.Time
+}
+
+// GPS is a satellite positioning receiver.
+type GPS interface {
+	// Fix returns the last fix, or ErrNoFix
+	Fix() (GPSFix, error)
+}
+
+// IMU is an inertial measurement unit. When it also implements
+// gobot.HeadingSensor, its compass headings correct the drift of the
+// gyroscope.
+type IMU interface {
+	// YawRate returns how fast the robot turns around the vertical axis, in
+	// radians per second, counterclockwise being positive
+	YawRate() (float64, error)
+}
+
+// LocalizationDriver estimates the Pose of an outdoor robot, such as a
+// rover, from the readings of a GPS and an IMU fused by a PoseEstimator. The
+// Pose is in meters from the first GPS fix, X pointing east and Y pointing
+// north, Theta being 0 when facing east.
+type LocalizationDriver struct {
+	name      string
+	gps       GPS
+	imu       IMU
+	estimator PoseEstimator
+	interval  time.Duration
+	halt      chan bool
+	mutex     *sync.Mutex
+	origin    GPSFix
+	lastFix   time.Time
+	hasOrigin bool
+	gobot.Eventer
+}
+
+// NewLocalizationDriver returns a new LocalizationDriver fusing the readings
+// of gps and imu with estimator, e.g. a ComplementaryEstimator or an
+// EKFEstimator.
+//
+// Optionally accepts:
+// 	time.Duration: Interval at which the sensors are read, 100ms by default
+func NewLocalizationDriver(gps GPS, imu IMU, estimator PoseEstimator, v ...time.Duration) *LocalizationDriver {
+	d := &LocalizationDriver{
+		name:      gobot.DefaultName("Localization"),
+		gps:       gps,
+		imu:       imu,
+		estimator: estimator,
+		interval:  100 * time.Millisecond,
+		halt:      make(chan bool),
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		d.interval = v[0]
+	}
+
+	d.AddEvent(PoseEvent)
+	d.AddEvent(ErrorEvent)
+
+	return d
+}
+
+// Name returns the Driver Name
+func (d *LocalizationDriver) Name() string { return d.name }
+
+// SetName sets the Driver Name
+func (d *LocalizationDriver) SetName(n string) { d.name = n }
+
+// Connection returns nil, the LocalizationDriver only using other Devices
+func (d *LocalizationDriver) Connection() gobot.Connection { return nil }
+
+// Dependencies returns the names of the GPS and IMU Devices, which must
+// start first
+func (d *LocalizationDriver) Dependencies() []string {
+	names := []string{}
+	for _, sensor := range []interface{}{d.gps, d.imu} {
+		if device, ok := sensor.(gobot.Device); ok {
+			names = append(names, device.Name())
+		}
+	}
+	return names
+}
+
+// Start starts estimating the Pose at the interval of the Driver
+//
+// Emits the Events:
+// 	PoseEvent Pose - The estimated Pose, after each reading of the sensors
+// 	ErrorEvent error - On error reading a sensor
+func (d *LocalizationDriver) Start() (err error) {
+	go func() {
+		clock := gobot.DefaultClock()
+		last := clock.Now()
+		for {
+			now := clock.Now()
+			d.update(now.Sub(last).Seconds())
+			last = now
+
+			select {
+			case <-clock.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Halt stops estimating the Pose
+func (d *LocalizationDriver) Halt() (err error) {
+	d.halt <- true
+	return
+}
+
+// Pose returns the last estimated Pose
+func (d *LocalizationDriver) Pose() Pose {
+	return d.estimator.Pose()
+}
+
+// Position returns the latitude and longitude, in degrees, of the last
+// estimated Pose
+func (d *LocalizationDriver) Position() (latitude, longitude float64) {
+	d.mutex.Lock()
+	origin := d.origin
+	d.mutex.Unlock()
+
+	p := d.estimator.Pose()
+	latitude = origin.Latitude + p.Y/earthRadius*180/math.Pi
+	longitude = origin.Longitude + p.X/(earthRadius*math.Cos(origin.Latitude*math.Pi/180))*180/math.Pi
+	return
+}
+
+// Origin returns the first GPS fix, from which the Pose is measured
+func (d *LocalizationDriver) Origin() GPSFix {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.origin
+}
+
+// update reads the sensors, dt seconds after the previous reading, and
+// publishes the new Pose
+func (d *LocalizationDriver) update(dt float64) {
+	if yawRate, err := d.imu.YawRate(); err != nil {
+		d.Publish(ErrorEvent, err)
+	} else {
+		d.estimator.Predict(yawRate, dt)
+	}
+
+	if compass, ok := d.imu.(gobot.HeadingSensor); ok {
+		if heading, err := compass.Heading(); err != nil {
+			d.Publish(ErrorEvent, err)
+		} else {
+			// compass headings are clockwise from north
+			d.estimator.CorrectHeading(normalizeAngle(math.Pi/2 - float64(heading)*math.Pi/180))
+		}
+	}
+
+	fix, err := d.gps.Fix()
+	switch {
+	case err == ErrNoFix:
+	case err != nil:
+		d.Publish(ErrorEvent, err)
+	default:
+		if x, y, ok := d.project(fix); ok {
+			d.estimator.CorrectPosition(x, y)
+		}
+	}
+
+	d.Publish(PoseEvent, d.estimator.Pose())
+}
+
+// project returns the position of a new fix in meters from the origin,
+// which is the first fix
+func (d *LocalizationDriver) project(fix GPSFix) (x, y float64, ok bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.hasOrigin && !fix.Time.After(d.lastFix) {
+		return 0, 0, false
+	}
+	if !d.hasOrigin {
+		d.origin = fix
+		d.hasOrigin = true
+	}
+	d.lastFix = fix.Time
+
+	// equirectangular projection, accurate over the few kilometers a rover
+	// covers
+	x = (fix.Longitude - d.origin.Longitude) * math.Pi / 180 * earthRadius * math.Cos(d.origin.Latitude*math.Pi/180)
+	y = (fix.Latitude - d.origin.Latitude) * math.Pi / 180 * earthRadius
+	return x, y, true
+}
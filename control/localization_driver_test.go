@@ -0,0 +1,161 @@
+package control
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*LocalizationDriver)(nil)
+var _ gobot.Dependent = (*LocalizationDriver)(nil)
+
+// testGPS is a GPS returning the fixes set by the tests
+type testGPS struct {
+	mutex sync.Mutex
+	fix   GPSFix
+	err   error
+}
+
+func (g *testGPS) Fix() (GPSFix, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.fix, g.err
+}
+
+func (g *testGPS) set(fix GPSFix, err error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.fix, g.err = fix, err
+}
+
+// testIMU is an IMU Device with a compass
+type testIMU struct {
+	mutex   sync.Mutex
+	yawRate float64
+	heading uint16
+	err     error
+}
+
+func (i *testIMU) Name() string                 { return "IMU" }
+func (i *testIMU) SetName(string)               {}
+func (i *testIMU) Start() error                 { return nil }
+func (i *testIMU) Halt() error                  { return nil }
+func (i *testIMU) Connection() gobot.Connection { return nil }
+
+func (i *testIMU) YawRate() (float64, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.yawRate, i.err
+}
+
+func (i *testIMU) Heading() (uint16, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.heading, nil
+}
+
+// startTestLocalizationDriver starts a LocalizationDriver trusting the
+// measurements, on a fake clock, and returns a function reading the next
+// Pose
+func startTestLocalizationDriver(t *testing.T, gps *testGPS, imu *testIMU) (*LocalizationDriver, func() Pose) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+
+	d := NewLocalizationDriver(gps, imu, NewComplementaryEstimator(0), 10*time.Millisecond)
+	poses := d.SubscribeWith(gobot.SubscribeOptions{Pattern: PoseEvent})
+	gobottest.Assert(t, d.Start(), nil)
+
+	first := true
+	return d, func() Pose {
+		if !first {
+			clock.BlockUntil(1)
+			clock.Advance(10 * time.Millisecond)
+		}
+		first = false
+		select {
+		case evt := <-poses:
+			return evt.Data.(Pose)
+		case <-time.After(time.Second):
+			t.Fatalf("pose was not published")
+			return Pose{}
+		}
+	}
+}
+
+func TestLocalizationDriver(t *testing.T) {
+	d := NewLocalizationDriver(&testGPS{}, &testIMU{}, NewEKFEstimator(0.01, 4, 0.05))
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "Localization"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+	gobottest.Assert(t, d.Connection(), nil)
+	gobottest.Assert(t, d.interval, 100*time.Millisecond)
+	gobottest.Assert(t, d.Dependencies(), []string{"IMU"})
+}
+
+func TestLocalizationDriverPose(t *testing.T) {
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+	gps := &testGPS{err: ErrNoFix}
+	imu := &testIMU{heading: 90}
+	d, next := startTestLocalizationDriver(t, gps, imu)
+	defer d.Halt()
+
+	// facing east, no fix yet
+	gobottest.Assert(t, nearPose(next(), Pose{}, 1e-9), true)
+
+	start := time.Unix(100, 0)
+	gps.set(GPSFix{Latitude: 48.85, Longitude: 2.35, Time: start}, nil)
+	gobottest.Assert(t, nearPose(next(), Pose{}, 1e-9), true)
+	gobottest.Assert(t, d.Origin().Latitude, 48.85)
+
+	// 0.001 degree north is about 111 meters
+	imu.mutex.Lock()
+	imu.heading = 0
+	imu.mutex.Unlock()
+	gps.set(GPSFix{Latitude: 48.851, Longitude: 2.35, Time: start.Add(time.Second)}, nil)
+	p := next()
+	gobottest.Assert(t, nearPose(p, Pose{Y: 111.19, Theta: math.Pi / 2}, 0.01), true)
+	gobottest.Assert(t, d.Pose(), p)
+
+	latitude, longitude := d.Position()
+	gobottest.Assert(t, math.Abs(latitude-48.851) < 1e-9, true)
+	gobottest.Assert(t, math.Abs(longitude-2.35) < 1e-9, true)
+}
+
+func TestLocalizationDriverOldFix(t *testing.T) {
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+	start := time.Unix(100, 0)
+	gps := &testGPS{fix: GPSFix{Latitude: 48.85, Longitude: 2.35, Time: start}}
+	d, next := startTestLocalizationDriver(t, gps, &testIMU{heading: 90})
+	defer d.Halt()
+	next()
+
+	// a fix older than the last one is ignored
+	gps.set(GPSFix{Latitude: 48.86, Longitude: 2.35, Time: start.Add(-time.Second)}, nil)
+	gobottest.Assert(t, nearPose(next(), Pose{}, 1e-9), true)
+}
+
+func TestLocalizationDriverErrors(t *testing.T) {
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+	gps := &testGPS{err: errors.New("gps error")}
+	imu := &testIMU{err: errors.New("imu error")}
+
+	d := NewLocalizationDriver(gps, imu, NewComplementaryEstimator(0.5), 10*time.Millisecond)
+	errs := d.SubscribeWith(gobot.SubscribeOptions{Pattern: ErrorEvent})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	for _, expected := range []string{"imu error", "gps error"} {
+		select {
+		case evt := <-errs:
+			gobottest.Assert(t, evt.Data, errors.New(expected))
+		case <-time.After(time.Second):
+			t.Fatalf("%s was not published", expected)
+		}
+	}
+}
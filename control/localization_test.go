@@ -0,0 +1,109 @@
+package control
+
+import (
+	"math"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ PoseEstimator = (*ComplementaryEstimator)(nil)
+var _ PoseEstimator = (*EKFEstimator)(nil)
+
+func nearPose(a, b Pose, tolerance float64) bool {
+	return math.Abs(a.X-b.X) < tolerance &&
+		math.Abs(a.Y-b.Y) < tolerance &&
+		math.Abs(normalizeAngle(a.Theta-b.Theta)) < tolerance
+}
+
+func TestComplementaryEstimator(t *testing.T) {
+	e := NewComplementaryEstimator(0.5)
+
+	// the first position is used as is
+	gobottest.Assert(t, e.CorrectPosition(10, 20), Pose{X: 10, Y: 20})
+
+	p := e.Predict(1, 0.5)
+	gobottest.Assert(t, p, Pose{X: 10, Y: 20, Theta: 0.5})
+
+	p = e.CorrectHeading(0)
+	gobottest.Assert(t, near(p.Theta, 0.25), true)
+
+	e.Reset()
+	gobottest.Assert(t, e.Pose(), Pose{})
+	gobottest.Assert(t, e.Speed(), 0.0)
+}
+
+func TestComplementaryEstimatorHeadingWraps(t *testing.T) {
+	e := NewComplementaryEstimator(0.5)
+	e.CorrectHeading(3)
+	e.Reset()
+	e.Predict(3, 1)
+
+	// blended across Pi rather than through 0
+	p := e.CorrectHeading(-3)
+	gobottest.Assert(t, near(math.Abs(p.Theta), math.Pi), true)
+}
+
+func TestComplementaryEstimatorSpeed(t *testing.T) {
+	e := NewComplementaryEstimator(0)
+	e.CorrectPosition(0, 0)
+	e.Predict(0, 1)
+	e.CorrectPosition(2, 0)
+	gobottest.Assert(t, e.Speed(), 2.0)
+
+	// the speed moves the robot between the positions
+	gobottest.Assert(t, e.Predict(0, 0.5), Pose{X: 3})
+
+	// backward
+	e.Predict(0, 0.5)
+	e.CorrectPosition(1, 0)
+	gobottest.Assert(t, e.Speed(), -1.0)
+}
+
+func TestEKFEstimatorStraightLine(t *testing.T) {
+	e := NewEKFEstimator(0.01, 1, 0.01)
+
+	// heading east at 1 m/s, with noisy positions
+	noise := []float64{0.5, -0.4, 0.3, -0.6, 0.2, -0.1, 0.4, -0.3}
+	for i := 0; i < 80; i++ {
+		e.Predict(0, 1)
+		e.CorrectHeading(0)
+		e.CorrectPosition(float64(i)+noise[i%len(noise)], noise[(i+3)%len(noise)])
+	}
+
+	gobottest.Assert(t, math.Abs(e.Speed()-1) < 0.1, true)
+	gobottest.Assert(t, nearPose(e.Pose(), Pose{X: 79}, 0.5), true)
+
+	x, y, theta := e.Variance()
+	gobottest.Assert(t, x < 1, true)
+	gobottest.Assert(t, y < 1, true)
+	gobottest.Assert(t, theta < 0.01, true)
+}
+
+func TestEKFEstimatorTurn(t *testing.T) {
+	e := NewEKFEstimator(0.01, 1, 0.1)
+	e.CorrectHeading(0)
+	for i := 0; i < 10; i++ {
+		e.Predict(math.Pi/20, 1)
+	}
+	// the gyroscope alone turned the robot to face north
+	gobottest.Assert(t, nearPose(e.Pose(), Pose{Theta: math.Pi / 2}, 0.1), true)
+
+	// a compass heading across Pi does not spin the robot the other way
+	e.Reset()
+	e.CorrectHeading(3)
+	p := e.CorrectHeading(-3)
+	gobottest.Assert(t, math.Abs(p.Theta) > 3, true)
+}
+
+func TestEKFEstimatorReset(t *testing.T) {
+	e := NewEKFEstimator(0.01, 1, 0.1)
+	gobottest.Assert(t, e.CorrectPosition(5, 5), Pose{X: 5, Y: 5})
+	x, _, _ := e.Variance()
+	gobottest.Assert(t, x, 1.0)
+
+	e.Reset()
+	gobottest.Assert(t, e.Pose(), Pose{})
+	gobottest.Assert(t, e.Speed(), 0.0)
+	gobottest.Assert(t, e.CorrectPosition(1, 2), Pose{X: 1, Y: 2})
+}
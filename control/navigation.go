@@ -0,0 +1,281 @@
+package control
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const (
+	// WaypointEvent is published by the WaypointFollower with the index of
+	// each Waypoint reached
+	WaypointEvent = "waypoint"
+	// ArrivedEvent is published by the WaypointFollower when the last
+	// Waypoint is reached
+	ArrivedEvent = "arrived"
+	// ObstacleEvent is published by the WaypointFollower with the Pose at
+	// which it stopped in front of an obstacle
+	ObstacleEvent = "obstacle"
+	// ClearedEvent is published by the WaypointFollower with the Pose at
+	// which it goes on once the obstacle is cleared
+	ClearedEvent = "cleared"
+)
+
+// PoseSource gives the current Pose of a robot, such as an Odometry, a
+// LocalizationDriver or a PoseEstimator.
+type PoseSource interface {
+	Pose() Pose
+}
+
+// Waypoint is a position to drive to, in the frame of the Poses.
+type Waypoint struct {
+	X, Y float64
+}
+
+// WaypointFollower drives a DifferentialDrive robot through a list of
+// Waypoints, steering towards the next one from the Pose given by its
+// odometry or localization. Its obstacle hook makes the robot wait while the
+// way is blocked.
+type WaypointFollower struct {
+	// MaxSpeed is the linear speed of the robot on straight lines, 0.3m/s by
+	// default
+	MaxSpeed float64
+	// MaxAngularSpeed bounds how fast the robot turns, 1.5rad/s by default
+	MaxAngularSpeed float64
+	// HeadingGain is the angular speed commanded per radian between the
+	// heading of the robot and the Waypoint, 2 by default
+	HeadingGain float64
+	// Tolerance is the distance at which a Waypoint is reached, 0.1m by
+	// default
+	Tolerance float64
+
+	name        string
+	pose        PoseSource
+	drive       *DifferentialDrive
+	left, right Motor
+	interval    time.Duration
+	waypoints   []Waypoint
+	current     int
+	obstacle    func(Pose) bool
+	blocked     bool
+	paused      bool
+	halt        chan bool
+	mutex       *sync.Mutex
+	gobot.Eventer
+}
+
+// NewWaypointFollower returns a new WaypointFollower driving the left and
+// right Motors of drive, whose MaxWheelSpeed must be set, from the Poses of
+// pose.
+//
+// Optionally accepts:
+// 	time.Duration: Interval at which the speeds are updated, 50ms by default
+func NewWaypointFollower(pose PoseSource, drive *DifferentialDrive, left, right Motor, v ...time.Duration) *WaypointFollower {
+	d := &WaypointFollower{
+		MaxSpeed:        0.3,
+		MaxAngularSpeed: 1.5,
+		HeadingGain:     2,
+		Tolerance:       0.1,
+		name:            gobot.DefaultName("WaypointFollower"),
+		pose:            pose,
+		drive:           drive,
+		left:            left,
+		right:           right,
+		interval:        50 * time.Millisecond,
+		halt:            make(chan bool),
+		mutex:           &sync.Mutex{},
+		Eventer:         gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		d.interval = v[0]
+	}
+
+	d.AddEvent(WaypointEvent)
+	d.AddEvent(ArrivedEvent)
+	d.AddEvent(ObstacleEvent)
+	d.AddEvent(ClearedEvent)
+	d.AddEvent(ErrorEvent)
+
+	return d
+}
+
+// Name returns the Driver Name
+func (d *WaypointFollower) Name() string { return d.name }
+
+// SetName sets the Driver Name
+func (d *WaypointFollower) SetName(n string) { d.name = n }
+
+// Connection returns nil, the WaypointFollower only using other Devices
+func (d *WaypointFollower) Connection() gobot.Connection { return nil }
+
+// Dependencies returns the names of the pose source and Motor Devices, which
+// must start first
+func (d *WaypointFollower) Dependencies() []string {
+	names := []string{}
+	for _, dependency := range []interface{}{d.pose, d.left, d.right} {
+		if device, ok := dependency.(gobot.Device); ok {
+			names = append(names, device.Name())
+		}
+	}
+	return names
+}
+
+// SetWaypoints sets the Waypoints to drive through, from the first one.
+func (d *WaypointFollower) SetWaypoints(waypoints ...Waypoint) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.waypoints = append([]Waypoint{}, waypoints...)
+	d.current = 0
+}
+
+// Waypoints returns the Waypoints to drive through.
+func (d *WaypointFollower) Waypoints() []Waypoint {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]Waypoint{}, d.waypoints...)
+}
+
+// Current returns the index of the Waypoint the robot drives to, the number
+// of Waypoints once arrived.
+func (d *WaypointFollower) Current() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.current
+}
+
+// Arrived returns whether the last Waypoint was reached.
+func (d *WaypointFollower) Arrived() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.current >= len(d.waypoints)
+}
+
+// OnObstacle sets the hook telling whether an obstacle blocks the way at the
+// current Pose, e.g. a sonar reading or a mapping.Grid query. The robot stops
+// while it returns true, and goes on once it returns false.
+func (d *WaypointFollower) OnObstacle(f func(Pose) bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.obstacle = f
+}
+
+// Pause stops the robot until Resume.
+func (d *WaypointFollower) Pause() error {
+	d.mutex.Lock()
+	d.paused = true
+	d.mutex.Unlock()
+	return d.stop()
+}
+
+// Resume lets the robot go on to the next Waypoint.
+func (d *WaypointFollower) Resume() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.paused = false
+}
+
+// Paused returns whether the robot was paused.
+func (d *WaypointFollower) Paused() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.paused
+}
+
+// Start starts driving to the Waypoints, updating the speeds at the interval
+// of the Driver
+//
+// Emits the Events:
+// 	WaypointEvent int - The index of each Waypoint reached
+// 	ArrivedEvent Waypoint - The last Waypoint, once reached
+// 	ObstacleEvent Pose - When the robot stops in front of an obstacle
+// 	ClearedEvent Pose - When the robot goes on once the obstacle is cleared
+// 	ErrorEvent error - On error driving the Motors
+func (d *WaypointFollower) Start() (err error) {
+	go func() {
+		clock := gobot.DefaultClock()
+		for {
+			d.update()
+
+			select {
+			case <-clock.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Halt stops driving and stops the Motors
+func (d *WaypointFollower) Halt() (err error) {
+	d.halt <- true
+	return d.stop()
+}
+
+// update steers the robot towards the current Waypoint from its Pose
+func (d *WaypointFollower) update() {
+	pose := d.pose.Pose()
+
+	d.mutex.Lock()
+	if d.paused || d.current >= len(d.waypoints) {
+		d.mutex.Unlock()
+		return
+	}
+	obstacle := d.obstacle
+	d.mutex.Unlock()
+
+	blocked := obstacle != nil && obstacle(pose)
+	d.mutex.Lock()
+	changed := blocked != d.blocked
+	d.blocked = blocked
+	d.mutex.Unlock()
+	if blocked {
+		if changed {
+			d.publishError(d.stop())
+			d.Publish(ObstacleEvent, pose)
+		}
+		return
+	}
+	if changed {
+		d.Publish(ClearedEvent, pose)
+	}
+
+	d.mutex.Lock()
+	target := d.waypoints[d.current]
+	dx, dy := target.X-pose.X, target.Y-pose.Y
+	distance := math.Hypot(dx, dy)
+	if distance <= d.Tolerance {
+		reached := d.current
+		d.current++
+		arrived := d.current >= len(d.waypoints)
+		d.mutex.Unlock()
+
+		d.Publish(WaypointEvent, reached)
+		if arrived {
+			d.publishError(d.stop())
+			d.Publish(ArrivedEvent, target)
+		}
+		return
+	}
+	d.mutex.Unlock()
+
+	// turn on the spot towards a Waypoint behind, and slow down when
+	// getting close to it
+	heading := normalizeAngle(math.Atan2(dy, dx) - pose.Theta)
+	angular := math.Max(-d.MaxAngularSpeed, math.Min(d.MaxAngularSpeed, d.HeadingGain*heading))
+	linear := math.Min(d.MaxSpeed*math.Max(0, math.Cos(heading)), distance)
+	d.publishError(d.drive.Drive(d.left, d.right, linear, angular))
+}
+
+func (d *WaypointFollower) stop() error {
+	return d.drive.Drive(d.left, d.right, 0, 0)
+}
+
+func (d *WaypointFollower) publishError(err error) {
+	if err != nil {
+		d.Publish(ErrorEvent, err)
+	}
+}
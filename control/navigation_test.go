@@ -0,0 +1,180 @@
+package control
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*WaypointFollower)(nil)
+var _ gobot.Dependent = (*WaypointFollower)(nil)
+var _ PoseSource = (*Odometry)(nil)
+var _ PoseSource = (*LocalizationDriver)(nil)
+
+// testPose is a PoseSource returning the Pose set by the tests
+type testPose struct {
+	mutex sync.Mutex
+	pose  Pose
+}
+
+func (p *testPose) Pose() Pose {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.pose
+}
+
+func (p *testPose) set(pose Pose) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.pose = pose
+}
+
+func initTestWaypointFollower() (*WaypointFollower, *testPose, *testMotor, *testMotor) {
+	drive := NewDifferentialDrive(0.03, 0.2)
+	drive.MaxWheelSpeed = 0.5
+	pose, left, right := &testPose{}, &testMotor{}, &testMotor{}
+	return NewWaypointFollower(pose, drive, left, right), pose, left, right
+}
+
+func TestWaypointFollower(t *testing.T) {
+	d, _, _, _ := initTestWaypointFollower()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "WaypointFollower"), true)
+	d.SetName("nav")
+	gobottest.Assert(t, d.Name(), "nav")
+	gobottest.Assert(t, d.Connection(), nil)
+	gobottest.Assert(t, d.Dependencies(), []string{})
+
+	d.SetWaypoints(Waypoint{1, 0}, Waypoint{1, 1})
+	gobottest.Assert(t, d.Waypoints(), []Waypoint{{1, 0}, {1, 1}})
+	gobottest.Assert(t, d.Current(), 0)
+	gobottest.Assert(t, d.Arrived(), false)
+}
+
+func TestWaypointFollowerSteering(t *testing.T) {
+	d, pose, left, right := initTestWaypointFollower()
+	d.SetWaypoints(Waypoint{1, 0})
+
+	// straight ahead at full speed
+	d.update()
+	gobottest.Assert(t, left.direction, "forward")
+	gobottest.Assert(t, left.speed, byte(153))
+	gobottest.Assert(t, right.speed, byte(153))
+
+	// the Waypoint on the left, the robot turns left
+	pose.set(Pose{Theta: -math.Pi / 4})
+	d.update()
+	gobottest.Assert(t, right.speed > left.speed, true)
+
+	// the Waypoint behind, the robot turns on the spot
+	pose.set(Pose{X: 2, Theta: 0})
+	d.update()
+	gobottest.Assert(t, left.direction, "backward")
+	gobottest.Assert(t, right.direction, "forward")
+	gobottest.Assert(t, left.speed, right.speed)
+
+	// slowing down when getting close
+	pose.set(Pose{X: 0.8})
+	d.update()
+	gobottest.Assert(t, left.speed, byte(102))
+}
+
+func TestWaypointFollowerArrival(t *testing.T) {
+	d, pose, left, _ := initTestWaypointFollower()
+	events := d.Subscribe()
+	d.SetWaypoints(Waypoint{1, 0}, Waypoint{1, 1})
+
+	pose.set(Pose{X: 0.95})
+	d.update()
+	evt := <-events
+	gobottest.Assert(t, evt.Name, WaypointEvent)
+	gobottest.Assert(t, evt.Data, 0)
+	gobottest.Assert(t, d.Current(), 1)
+
+	pose.set(Pose{X: 1, Y: 0.92, Theta: math.Pi / 2})
+	d.update()
+	gobottest.Assert(t, (<-events).Data, 1)
+	evt = <-events
+	gobottest.Assert(t, evt.Name, ArrivedEvent)
+	gobottest.Assert(t, evt.Data, Waypoint{1, 1})
+	gobottest.Assert(t, d.Arrived(), true)
+	gobottest.Assert(t, left.speed, byte(0))
+
+	// nothing more to do
+	left.speed = 42
+	d.update()
+	gobottest.Assert(t, left.speed, byte(42))
+}
+
+func TestWaypointFollowerObstacle(t *testing.T) {
+	d, pose, left, _ := initTestWaypointFollower()
+	events := d.Subscribe()
+	d.SetWaypoints(Waypoint{1, 0})
+	blocked := true
+	d.OnObstacle(func(p Pose) bool { return blocked })
+
+	pose.set(Pose{X: 0.5})
+	d.update()
+	evt := <-events
+	gobottest.Assert(t, evt.Name, ObstacleEvent)
+	gobottest.Assert(t, evt.Data, Pose{X: 0.5})
+	gobottest.Assert(t, left.speed, byte(0))
+	// still blocked, no new event
+	d.update()
+
+	blocked = false
+	d.update()
+	evt = <-events
+	gobottest.Assert(t, evt.Name, ClearedEvent)
+	gobottest.Assert(t, left.speed, byte(153))
+}
+
+func TestWaypointFollowerPause(t *testing.T) {
+	d, _, left, _ := initTestWaypointFollower()
+	d.SetWaypoints(Waypoint{1, 0})
+	d.update()
+	gobottest.Assert(t, d.Pause(), nil)
+	gobottest.Assert(t, d.Paused(), true)
+	gobottest.Assert(t, left.speed, byte(0))
+	d.update()
+	gobottest.Assert(t, left.speed, byte(0))
+
+	d.Resume()
+	d.update()
+	gobottest.Assert(t, left.speed, byte(153))
+}
+
+func TestWaypointFollowerDriveError(t *testing.T) {
+	d, _, left, _ := initTestWaypointFollower()
+	errs := d.SubscribeWith(gobot.SubscribeOptions{Pattern: ErrorEvent})
+	left.err = errors.New("motor error")
+	d.SetWaypoints(Waypoint{1, 0})
+	d.update()
+	gobottest.Assert(t, (<-errs).Data, errors.New("motor error"))
+}
+
+func TestWaypointFollowerStart(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	d, pose, _, _ := initTestWaypointFollower()
+	arrived := d.SubscribeWith(gobot.SubscribeOptions{Pattern: ArrivedEvent})
+	d.SetWaypoints(Waypoint{1, 0})
+	gobottest.Assert(t, d.Start(), nil)
+
+	clock.BlockUntil(1)
+	pose.set(Pose{X: 1})
+	clock.Advance(50 * time.Millisecond)
+	select {
+	case <-arrived:
+	case <-time.After(time.Second):
+		t.Errorf("arrival was not published")
+	}
+	gobottest.Assert(t, d.Halt(), nil)
+}
@@ -0,0 +1,107 @@
+package control
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// PID is a proportional-integral-derivative controller.
+//
+// The derivative is computed on the measurement rather than on the error, so
+// that changing the setpoint does not make the output jump, and can be low
+// pass filtered. The integral is clamped to the output limits to prevent
+// windup while the output is saturated.
+type PID struct {
+	kp, ki, kd float64
+	min, max   float64
+	alpha      float64
+
+	integral    float64
+	derivative  float64
+	last        float64
+	initialized bool
+	mutex       sync.Mutex
+}
+
+// NewPID returns a new PID controller with the given gains and no output
+// limits.
+func NewPID(kp, ki, kd float64) *PID {
+	return &PID{
+		kp:  kp,
+		ki:  ki,
+		kd:  kd,
+		min: math.Inf(-1),
+		max: math.Inf(1),
+	}
+}
+
+// SetTunings changes the gains. It can be called while the controller runs:
+// the accumulated integral is kept, so the output does not jump.
+func (p *PID) SetTunings(kp, ki, kd float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.kp, p.ki, p.kd = kp, ki, kd
+}
+
+// Tunings returns the gains
+func (p *PID) Tunings() (kp, ki, kd float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.kp, p.ki, p.kd
+}
+
+// SetOutputLimits clamps the output, and the integral, to min...max.
+func (p *PID) SetOutputLimits(min, max float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if min > max {
+		min, max = max, min
+	}
+	p.min, p.max = min, max
+	p.integral = clamp(p.integral, min, max)
+}
+
+// SetDerivativeFilter smooths the derivative term with a low pass filter of
+// factor alpha, between 0 and 1. An alpha of 0, the default, disables the
+// filter, values closer to 1 smooth more.
+func (p *PID) SetDerivativeFilter(alpha float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.alpha = clamp(alpha, 0, 1)
+}
+
+// Update returns the output for measurement, dt after the previous Update.
+func (p *PID) Update(setpoint, measurement float64, dt time.Duration) float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err := setpoint - measurement
+	seconds := dt.Seconds()
+
+	derivative := 0.0
+	if p.initialized && seconds > 0 {
+		derivative = -(measurement - p.last) / seconds
+	}
+	p.derivative = p.alpha*p.derivative + (1-p.alpha)*derivative
+	p.last = measurement
+	p.initialized = true
+
+	p.integral = clamp(p.integral+p.ki*err*seconds, p.min, p.max)
+
+	return clamp(p.kp*err+p.integral+p.kd*p.derivative, p.min, p.max)
+}
+
+// Reset clears the integral and derivative state, for example after the
+// robot was stopped.
+func (p *PID) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.integral = 0
+	p.derivative = 0
+	p.initialized = false
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
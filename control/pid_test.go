@@ -0,0 +1,83 @@
+package control
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestPIDProportional(t *testing.T) {
+	p := NewPID(2, 0, 0)
+	gobottest.Assert(t, p.Update(10, 4, 10*time.Millisecond), 12.0)
+	gobottest.Assert(t, p.Update(10, 10, 10*time.Millisecond), 0.0)
+}
+
+func TestPIDIntegral(t *testing.T) {
+	p := NewPID(0, 1, 0)
+	gobottest.Assert(t, p.Update(1, 0, time.Second), 1.0)
+	gobottest.Assert(t, p.Update(1, 0, time.Second), 2.0)
+	gobottest.Assert(t, p.Update(1, 2, time.Second), 1.0)
+}
+
+func TestPIDDerivativeOnMeasurement(t *testing.T) {
+	p := NewPID(0, 0, 1)
+	// no derivative on the first update
+	gobottest.Assert(t, p.Update(0, 5, time.Second), 0.0)
+	// changing the setpoint does not kick
+	gobottest.Assert(t, p.Update(100, 5, time.Second), 0.0)
+	gobottest.Assert(t, p.Update(100, 7, time.Second), -2.0)
+}
+
+func TestPIDDerivativeFilter(t *testing.T) {
+	p := NewPID(0, 0, 1)
+	p.SetDerivativeFilter(0.5)
+	p.Update(0, 0, time.Second)
+	gobottest.Assert(t, p.Update(0, 4, time.Second), -2.0)
+	gobottest.Assert(t, p.Update(0, 4, time.Second), -1.0)
+
+	p.SetDerivativeFilter(3)
+	gobottest.Assert(t, p.alpha, 1.0)
+}
+
+func TestPIDOutputLimitsAntiWindup(t *testing.T) {
+	p := NewPID(1, 1, 0)
+	p.SetOutputLimits(10, -10)
+	gobottest.Assert(t, p.min, -10.0)
+	gobottest.Assert(t, p.max, 10.0)
+
+	for i := 0; i < 100; i++ {
+		gobottest.Assert(t, p.Update(100, 0, time.Second), 10.0)
+	}
+	gobottest.Assert(t, p.integral, 10.0)
+
+	// without windup the output reacts at once
+	gobottest.Assert(t, p.Update(0, 5, time.Second), 0.0)
+}
+
+func TestPIDSetTunings(t *testing.T) {
+	p := NewPID(1, 1, 0)
+	p.Update(1, 0, time.Second)
+	p.SetTunings(2, 0, 0.5)
+	kp, ki, kd := p.Tunings()
+	gobottest.Assert(t, []float64{kp, ki, kd}, []float64{2, 0, 0.5})
+	// the integral accumulated with the previous gains is kept
+	gobottest.Assert(t, p.Update(1, 0, time.Second), 3.0)
+}
+
+func TestPIDReset(t *testing.T) {
+	p := NewPID(0, 1, 1)
+	p.Update(1, 0, time.Second)
+	p.Reset()
+	gobottest.Assert(t, p.Update(1, 3, time.Second), -2.0)
+}
+
+func TestPIDConverges(t *testing.T) {
+	p := NewPID(4, 4, 0.05)
+	position := 0.0
+	for i := 0; i < 2000; i++ {
+		position += p.Update(10, position, 10*time.Millisecond) * 0.01
+	}
+	gobottest.Assert(t, math.Abs(position-10) < 0.01, true)
+}
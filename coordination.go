@@ -0,0 +1,129 @@
+package gobot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Topic returns the Topic named name shared by the Robots of the Master,
+// creating it with the payload type of payload on first use, so that robots
+// in the same program can exchange messages:
+//
+//	positions, _ := master.Topic("positions", Position{})
+//	positions.Publish(Position{X: 1, Y: 2})
+//
+// Values are also published on the Master's Eventer under the Topic name. An
+// error is returned if the Topic already exists with another payload type.
+func (g *Master) Topic(name string, payload interface{}) (*Topic, error) {
+	g.coordMutex.Lock()
+	defer g.coordMutex.Unlock()
+
+	if g.topics == nil {
+		g.topics = make(map[string]*Topic)
+	}
+	if t, ok := g.topics[name]; ok {
+		if t.payload != reflect.TypeOf(payload) {
+			return nil, fmt.Errorf("Topic %v already exists with another payload type", name)
+		}
+		return t, nil
+	}
+	t := NewTopic(name, payload, g.Eventer)
+	g.topics[name] = t
+	return t, nil
+}
+
+// Topics returns the sorted names of the Master's Topics
+func (g *Master) Topics() []string {
+	g.coordMutex.Lock()
+	defer g.coordMutex.Unlock()
+	names := []string{}
+	for name := range g.topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Barrier is a meeting point for a fixed number of parties, typically robots
+// of a swarm: each party calling Wait blocks until all of them have arrived.
+// A Barrier can be reused once all parties have been released.
+type Barrier struct {
+	name    string
+	parties int
+	waiting int
+	release chan bool
+	mutex   sync.Mutex
+}
+
+// Barrier returns the Barrier named name for parties parties, creating it on
+// first use. Later calls with the same name return the existing Barrier
+// regardless of parties.
+func (g *Master) Barrier(name string, parties int) *Barrier {
+	g.coordMutex.Lock()
+	defer g.coordMutex.Unlock()
+
+	if g.barriers == nil {
+		g.barriers = make(map[string]*Barrier)
+	}
+	if b, ok := g.barriers[name]; ok {
+		return b
+	}
+	b := &Barrier{name: name, parties: parties, release: make(chan bool)}
+	g.barriers[name] = b
+	return b
+}
+
+// Name returns the Barrier name
+func (b *Barrier) Name() string { return b.name }
+
+// Parties returns the number of parties needed to release the Barrier
+func (b *Barrier) Parties() int { return b.parties }
+
+// Wait blocks until all parties are waiting, and returns the order of arrival
+// of the caller, starting at 0.
+func (b *Barrier) Wait() int {
+	arrival, _ := b.WaitTimeout(0)
+	return arrival
+}
+
+// WaitTimeout is like Wait, but gives up and returns an error after timeout.
+// A timeout of zero waits forever.
+func (b *Barrier) WaitTimeout(timeout time.Duration) (int, error) {
+	b.mutex.Lock()
+	arrival := b.waiting
+	release := b.release
+	b.waiting++
+	if b.waiting >= b.parties {
+		close(b.release)
+		b.release = make(chan bool)
+		b.waiting = 0
+		b.mutex.Unlock()
+		return arrival, nil
+	}
+	b.mutex.Unlock()
+
+	if timeout <= 0 {
+		<-release
+		return arrival, nil
+	}
+
+	select {
+	case <-release:
+		return arrival, nil
+	case <-DefaultClock().After(timeout):
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	select {
+	case <-release:
+		// released while timing out
+		return arrival, nil
+	default:
+	}
+	b.waiting--
+	return arrival, fmt.Errorf("Timeout waiting at barrier %v", b.name)
+}
@@ -0,0 +1,121 @@
+package gobot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testPosition struct {
+	X, Y int
+}
+
+func TestMasterTopic(t *testing.T) {
+	m := NewMaster()
+	topic, err := m.Topic("positions", testPosition{})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, topic.Name(), "positions")
+
+	same, _ := m.Topic("positions", testPosition{})
+	gobottest.Assert(t, same == topic, true)
+
+	_, err = m.Topic("positions", "")
+	gobottest.Assert(t, err.Error(), "Topic positions already exists with another payload type")
+
+	m.Topic("chat", "")
+	gobottest.Assert(t, m.Topics(), []string{"chat", "positions"})
+}
+
+func TestMasterTopicMessaging(t *testing.T) {
+	m := NewMaster()
+	leader := m.AddRobot(NewRobot("leader"))
+	follower := m.AddRobot(NewRobot("follower"))
+
+	received := make(chan testPosition, 1)
+	follower.Work = func() {
+		positions, _ := m.Topic("positions", testPosition{})
+		positions.On(func(data interface{}) { received <- data.(testPosition) })
+	}
+	follower.Work()
+
+	mirrored := make(chan interface{}, 1)
+	m.On("positions", func(data interface{}) { mirrored <- data })
+
+	leader.Work = func() {
+		positions, _ := m.Topic("positions", testPosition{})
+		positions.Publish(testPosition{X: 1, Y: 2})
+	}
+	leader.Work()
+
+	select {
+	case p := <-received:
+		gobottest.Assert(t, p, testPosition{X: 1, Y: 2})
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("message was not delivered")
+	}
+	select {
+	case data := <-mirrored:
+		gobottest.Assert(t, data, testPosition{X: 1, Y: 2})
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("message was not mirrored on the Master")
+	}
+}
+
+func TestMasterBarrier(t *testing.T) {
+	m := NewMaster()
+	b := m.Barrier("formation", 3)
+	gobottest.Assert(t, b.Name(), "formation")
+	gobottest.Assert(t, b.Parties(), 3)
+	gobottest.Assert(t, m.Barrier("formation", 5) == b, true)
+
+	var wg sync.WaitGroup
+	arrivals := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			arrivals <- b.Wait()
+		}()
+	}
+	wg.Wait()
+	close(arrivals)
+
+	seen := map[int]bool{}
+	for a := range arrivals {
+		seen[a] = true
+	}
+	gobottest.Assert(t, seen, map[int]bool{0: true, 1: true, 2: true})
+}
+
+func TestBarrierReuse(t *testing.T) {
+	b := NewMaster().Barrier("sync", 2)
+	for round := 0; round < 3; round++ {
+		done := make(chan bool)
+		go func() {
+			b.Wait()
+			done <- true
+		}()
+		b.Wait()
+		<-done
+	}
+}
+
+func TestBarrierTimeout(t *testing.T) {
+	b := NewMaster().Barrier("lonely", 2)
+	_, err := b.WaitTimeout(10 * time.Millisecond)
+	gobottest.Assert(t, err.Error(), "Timeout waiting at barrier lonely")
+
+	// the timed out party no longer counts
+	done := make(chan error)
+	go func() {
+		_, err := b.WaitTimeout(time.Second)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	arrival, err := b.WaitTimeout(time.Second)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, arrival, 1)
+	gobottest.Assert(t, <-done, nil)
+}
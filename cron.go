@@ -0,0 +1,373 @@
+package gobot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule describes when a scheduled Job runs.
+type Schedule interface {
+	// Next returns the first activation time strictly after t
+	Next(t time.Time) time.Time
+}
+
+// CronSchedule is a Schedule parsed from a standard five field cron
+// expression: minute, hour, day of month, month and day of week.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type everySchedule time.Duration
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = []cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are both Sunday
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression into a Schedule. Besides the five field
+// syntax supporting "*", lists, ranges and steps (e.g. "*/5 8-18 * * 1-5"),
+// the descriptors "@hourly", "@daily", "@midnight", "@weekly", "@monthly",
+// "@yearly" and "@every <duration>" are accepted.
+func ParseCron(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, errors.New("cron: @every duration must be positive")
+		}
+		return everySchedule(d), nil
+	}
+	if expanded, ok := cronDescriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron: expected %d fields, found %d in %q", len(cronFields), len(fields), spec)
+	}
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	// fold Sunday (7) onto 0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &CronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+	}, nil
+}
+
+func parseCronField(field string, bounds cronField) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("cron: invalid step in %q", part)
+			}
+			part = part[:i]
+		}
+		low, high := bounds.min, bounds.max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			r := strings.SplitN(part, "-", 2)
+			if low, err = strconv.Atoi(r[0]); err != nil {
+				return 0, fmt.Errorf("cron: invalid range %q", part)
+			}
+			if high, err = strconv.Atoi(r[1]); err != nil {
+				return 0, fmt.Errorf("cron: invalid range %q", part)
+			}
+		default:
+			if low, err = strconv.Atoi(part); err != nil {
+				return 0, fmt.Errorf("cron: invalid value %q", part)
+			}
+			high = low
+			if step > 1 {
+				high = bounds.max
+			}
+		}
+		if low < bounds.min || high > bounds.max || low > high {
+			return 0, fmt.Errorf("cron: %q out of range %d-%d", part, bounds.min, bounds.max)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first activation time strictly after t, or the zero time if
+// the schedule can never be satisfied within five years.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows the cron convention that when both day of month and day
+// of week are restricted, either may match.
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Job is a function run according to a Schedule.
+type Job struct {
+	name     string
+	schedule Schedule
+	f        func()
+	next     time.Time
+	paused   bool
+	halt     chan bool
+	mutex    sync.Mutex
+}
+
+// Cron triggers f according to the cron expression spec until Stop is called
+// on the returned Job. See ParseCron for the accepted syntax.
+func Cron(spec string, f func()) (*Job, error) {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	j := newJob("", schedule, f)
+	j.start()
+	return j, nil
+}
+
+func newJob(name string, schedule Schedule, f func()) *Job {
+	return &Job{
+		name:     name,
+		schedule: schedule,
+		f:        f,
+		halt:     make(chan bool),
+	}
+}
+
+func (j *Job) start() {
+	j.mutex.Lock()
+	j.next = j.schedule.Next(DefaultClock().Now())
+	next := j.next
+	j.mutex.Unlock()
+
+	go func() {
+		for !next.IsZero() {
+			fired := make(chan bool, 1)
+			timer := DefaultClock().AfterFunc(next.Sub(DefaultClock().Now()), func() { fired <- true })
+			select {
+			case <-fired:
+				j.mutex.Lock()
+				paused := j.paused
+				j.next = j.schedule.Next(next)
+				next = j.next
+				j.mutex.Unlock()
+				if !paused {
+					j.f()
+				}
+			case <-j.halt:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Name returns the Job name
+func (j *Job) Name() string { return j.name }
+
+// Next returns the time the Job will next run
+func (j *Job) Next() time.Time {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.next
+}
+
+// Pause skips the Job's activations until Resume is called
+func (j *Job) Pause() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.paused = true
+}
+
+// Resume re-enables a paused Job
+func (j *Job) Resume() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.paused = false
+}
+
+// Paused returns true if the Job is paused
+func (j *Job) Paused() bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.paused
+}
+
+// Stop ends the Job. A stopped Job can not be restarted.
+func (j *Job) Stop() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	select {
+	case <-j.halt:
+	default:
+		close(j.halt)
+	}
+}
+
+// Scheduler is a collection of named Jobs.
+type Scheduler struct {
+	jobs  map[string]*Job
+	mutex sync.Mutex
+}
+
+// NewScheduler returns a new Scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{jobs: make(map[string]*Job)}
+}
+
+// Cron adds a Job named name running f according to the cron expression spec.
+// An existing Job with the same name is stopped and replaced.
+func (s *Scheduler) Cron(name string, spec string, f func()) (*Job, error) {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.Add(name, schedule, f), nil
+}
+
+// Add adds a Job named name running f according to schedule. An existing Job
+// with the same name is stopped and replaced.
+func (s *Scheduler) Add(name string, schedule Schedule, f func()) *Job {
+	j := newJob(name, schedule, f)
+	s.mutex.Lock()
+	if old, ok := s.jobs[name]; ok {
+		old.Stop()
+	}
+	s.jobs[name] = j
+	s.mutex.Unlock()
+	j.start()
+	return j
+}
+
+// Job returns the Job with the given name, or nil
+func (s *Scheduler) Job(name string) *Job {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.jobs[name]
+}
+
+// Jobs returns the Scheduler's Jobs sorted by name
+func (s *Scheduler) Jobs() []*Job {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].name < jobs[b].name })
+	return jobs
+}
+
+// Pause pauses the named Job
+func (s *Scheduler) Pause(name string) error {
+	j := s.Job(name)
+	if j == nil {
+		return fmt.Errorf("No Job found with the name %v", name)
+	}
+	j.Pause()
+	return nil
+}
+
+// Resume resumes the named Job
+func (s *Scheduler) Resume(name string) error {
+	j := s.Job(name)
+	if j == nil {
+		return fmt.Errorf("No Job found with the name %v", name)
+	}
+	j.Resume()
+	return nil
+}
+
+// Remove stops and removes the named Job
+func (s *Scheduler) Remove(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if j, ok := s.jobs[name]; ok {
+		j.Stop()
+		delete(s.jobs, name)
+	}
+}
+
+// Stop stops and removes all Jobs
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, j := range s.jobs {
+		j.Stop()
+		delete(s.jobs, name)
+	}
+}
@@ -0,0 +1,126 @@
+package gobot
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func parseTestTime(s string) time.Time {
+	t, _ := time.Parse("2006-01-02 15:04", s)
+	return t
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"5-1 * * * *",
+		"@every nope",
+		"@every -1s",
+	} {
+		_, err := ParseCron(spec)
+		gobottest.Refute(t, err, nil)
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	cases := []struct {
+		spec, from, next string
+	}{
+		{"*/5 * * * *", "2017-05-01 10:02", "2017-05-01 10:05"},
+		{"*/5 * * * *", "2017-05-01 10:05", "2017-05-01 10:10"},
+		{"0 * * * *", "2017-05-01 23:30", "2017-05-02 00:00"},
+		{"30 8-18/2 * * *", "2017-05-01 19:00", "2017-05-02 08:30"},
+		{"0 9 * * 1-5", "2017-05-05 10:00", "2017-05-08 09:00"},
+		{"0 0 1 * *", "2017-12-15 00:00", "2018-01-01 00:00"},
+		{"0 0 29 2 *", "2017-03-01 00:00", "2020-02-29 00:00"},
+		{"0 0 * * 7", "2017-05-01 00:00", "2017-05-07 00:00"},
+		{"0 0 13 * 5", "2017-05-01 00:00", "2017-05-05 00:00"},
+		{"15,45 * * * *", "2017-05-01 10:20", "2017-05-01 10:45"},
+		{"@daily", "2017-05-01 10:20", "2017-05-02 00:00"},
+		{"@hourly", "2017-05-01 10:20", "2017-05-01 11:00"},
+	}
+	for _, c := range cases {
+		s, err := ParseCron(c.spec)
+		gobottest.Assert(t, err, nil)
+		gobottest.Assert(t, s.Next(parseTestTime(c.from)), parseTestTime(c.next))
+	}
+
+	s, _ := ParseCron("0 0 31 2 *")
+	gobottest.Assert(t, s.Next(parseTestTime("2017-05-01 00:00")).IsZero(), true)
+}
+
+func TestCronEvery(t *testing.T) {
+	sem := make(chan bool, 1)
+	j, err := Cron("@every 5ms", func() {
+		select {
+		case sem <- true:
+		default:
+		}
+	})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, j.Next().After(time.Now()), true)
+
+	select {
+	case <-sem:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Cron job was not called")
+	}
+	j.Stop()
+	j.Stop()
+
+	_, err = Cron("bad", func() {})
+	gobottest.Refute(t, err, nil)
+}
+
+func TestSchedulerJobs(t *testing.T) {
+	s := NewScheduler()
+	sem := make(chan string, 10)
+	_, err := s.Cron("b", "@every 5ms", func() { sem <- "b" })
+	gobottest.Assert(t, err, nil)
+	_, err = s.Cron("a", "0 0 1 1 *", func() { sem <- "a" })
+	gobottest.Assert(t, err, nil)
+	_, err = s.Cron("c", "bad", func() {})
+	gobottest.Refute(t, err, nil)
+
+	jobs := s.Jobs()
+	gobottest.Assert(t, len(jobs), 2)
+	gobottest.Assert(t, jobs[0].Name(), "a")
+	gobottest.Assert(t, jobs[1].Name(), "b")
+
+	gobottest.Assert(t, s.Pause("b"), nil)
+	gobottest.Assert(t, s.Job("b").Paused(), true)
+	gobottest.Refute(t, s.Pause("z"), nil)
+	for len(sem) > 0 {
+		<-sem
+	}
+	time.Sleep(20 * time.Millisecond)
+	gobottest.Assert(t, len(sem), 0)
+
+	gobottest.Assert(t, s.Resume("b"), nil)
+	gobottest.Refute(t, s.Resume("z"), nil)
+	select {
+	case name := <-sem:
+		gobottest.Assert(t, name, "b")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("resumed job was not called")
+	}
+
+	s.Remove("b")
+	gobottest.Assert(t, s.Job("b"), (*Job)(nil))
+	s.Stop()
+	gobottest.Assert(t, len(s.Jobs()), 0)
+}
+
+func TestRobotScheduler(t *testing.T) {
+	r := newTestRobot("Robot1")
+	r.Scheduler().Add("tick", everySchedule(time.Hour), func() {})
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, r.Stop(), nil)
+	gobottest.Assert(t, len(r.Scheduler().Jobs()), 0)
+}
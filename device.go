@@ -1,27 +1,37 @@
 package gobot
 
 import (
-	"log"
+	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 )
 
 // JSONDevice is a JSON representation of a Device.
 type JSONDevice struct {
-	Name       string   `json:"name"`
-	Driver     string   `json:"driver"`
-	Connection string   `json:"connection"`
-	Commands   []string `json:"commands"`
+	Name         string            `json:"name"`
+	Driver       string            `json:"driver"`
+	Connection   string            `json:"connection"`
+	Commands     []string          `json:"commands"`
+	Events       []string          `json:"events"`
+	Capabilities []string          `json:"capabilities"`
+	Components   []string          `json:"components,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
 }
 
 // NewJSONDevice returns a JSONDevice given a Device.
 func NewJSONDevice(device Device) *JSONDevice {
 	jsonDevice := &JSONDevice{
-		Name:       device.Name(),
-		Driver:     reflect.TypeOf(device).String(),
-		Commands:   []string{},
-		Connection: "",
+		Name:         device.Name(),
+		Driver:       reflect.TypeOf(device).String(),
+		Commands:     []string{},
+		Events:       []string{},
+		Capabilities: CapabilitiesOf(device),
+		Connection:   "",
 	}
 	if device.Connection() != nil {
 		jsonDevice.Connection = device.Connection().Name()
@@ -31,6 +41,17 @@ func NewJSONDevice(device Device) *JSONDevice {
 			jsonDevice.Commands = append(jsonDevice.Commands, command)
 		}
 	}
+	if eventer, ok := device.(Eventer); ok {
+		for event := range eventer.Events() {
+			jsonDevice.Events = append(jsonDevice.Events, event)
+		}
+		sort.Strings(jsonDevice.Events)
+	}
+	if composite, ok := device.(Composite); ok {
+		for _, component := range composite.Components() {
+			jsonDevice.Components = append(jsonDevice.Components, component.Name())
+		}
+	}
 	return jsonDevice
 }
 
@@ -52,29 +73,381 @@ func (d *Devices) Each(f func(Device)) {
 	}
 }
 
-// Start calls Start on each Device in d
+// Dependent is implemented by Devices which must only be started once other
+// Devices of their Robot have started successfully.
+type Dependent interface {
+	// Dependencies returns the names of the Devices or Connections required
+	Dependencies() []string
+}
+
+// PinChecker is implemented by the Connections knowing the capabilities of
+// their pins, so that a Device assigned a pin unable to do what it needs fails
+// to start with a helpful error rather than misbehaving.
+type PinChecker interface {
+	// CheckPin returns an error when the pin of device does not support it
+	CheckPin(device Device) error
+}
+
+// DeviceFailure is the Robot event published when an optional Device fails to
+// start. Its data is the start error, an *Error whose Component is the name of
+// the Device.
+const DeviceFailure = "device-failed"
+
+// StartPolicy selects what happens when a Device fails to start.
+type StartPolicy struct {
+	// Optional Devices that fail to start do not prevent their Robot from
+	// starting, a DeviceFailure event is published instead.
+	Optional bool
+	// Retries is the number of additional attempts, RetryDelay apart, made
+	// before giving up.
+	Retries    int
+	RetryDelay time.Duration
+}
+
+var (
+	// StartRequired is the default policy: the Robot does not start without
+	// the Device.
+	StartRequired = StartPolicy{}
+	// StartOptional lets the Robot start without the Device.
+	StartOptional = StartPolicy{Optional: true}
+)
+
+// StartRetry returns a policy trying to start a required Device up to n more
+// times, delay apart.
+func StartRetry(n int, delay time.Duration) StartPolicy {
+	return StartPolicy{Retries: n, RetryDelay: delay}
+}
+
+// startOptions holds the dependencies, timeouts and policies used when
+// starting Devices.
+type startOptions struct {
+	connections  *Connections
+	dependencies map[string][]string
+	timeout      time.Duration
+	timeouts     map[string]time.Duration
+	policies     map[string]StartPolicy
+	failed       func(device Device, err error)
+	started      func(device Device)
+	// parallel starts the Devices in their own goroutines
+	parallel bool
+	// ctx holds the Span the Spans of the Devices are children of
+	ctx context.Context
+}
+
+func (o *startOptions) traceContext() context.Context {
+	if o == nil || o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+func (o *startOptions) policyOf(device Device) StartPolicy {
+	if o == nil {
+		return StartRequired
+	}
+	return o.policies[device.Name()]
+}
+
+func (o *startOptions) dependenciesOf(device Device) []string {
+	names := []string{}
+	if o != nil {
+		names = append(names, o.dependencies[device.Name()]...)
+	}
+	if dependent, ok := device.(Dependent); ok {
+		names = append(names, dependent.Dependencies()...)
+	}
+	return names
+}
+
+func (o *startOptions) timeoutOf(device Device) time.Duration {
+	if o == nil {
+		return 0
+	}
+	if timeout, ok := o.timeouts[device.Name()]; ok {
+		return timeout
+	}
+	return o.timeout
+}
+
+func (o *startOptions) hasConnection(name string) bool {
+	if o == nil || o.connections == nil {
+		return false
+	}
+	for _, c := range *o.connections {
+		if c.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Start calls Start on each Device in d, one at a time in the order of d. A
+// Device implementing Dependent is started after its dependencies, and only
+// once they have started successfully. Errors are reported in the order of d.
 func (d *Devices) Start() (err error) {
-	log.Println("Starting devices...")
-	for _, device := range *d {
-		info := "Starting device " + device.Name()
+	return d.start(DefaultLogger(), nil)
+}
+
+// start starts the Devices in d. With the parallel option, the Devices are
+// started in their own goroutines, those sharing a Connection still being
+// started one at a time so that the initializations of the devices of a bus
+// are not interleaved.
+func (d *Devices) start(l Logger, o *startOptions) (err error) {
+	l.Info("Starting devices...")
+	deps, err := d.dependencyGraph(o)
+	if err != nil {
+		return err
+	}
 
-		if pinner, ok := device.(Pinner); ok {
-			info = info + " on pin " + pinner.Pin()
+	errs := make([]error, len(*d))
+	if o == nil || !o.parallel {
+		for _, i := range startOrder(deps, len(*d)) {
+			errs[i] = d.startOne(l, o, i, deps[i], errs, &sync.Mutex{})
+		}
+	} else {
+		done := make([]chan bool, len(*d))
+		for i := range done {
+			done[i] = make(chan bool)
+		}
+		locks := connectionLocks(*d)
+		var wg sync.WaitGroup
+		for i := range *d {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer close(done[i])
+				for _, j := range deps[i] {
+					<-done[j]
+				}
+				errs[i] = d.startOne(l, o, i, deps[i], errs, locks[i])
+			}(i)
 		}
+		wg.Wait()
+	}
 
-		log.Println(info + "...")
-		if derr := device.Start(); derr != nil {
-			err = multierror.Append(err, derr)
+	for i, derr := range errs {
+		if derr == nil {
+			continue
+		}
+		device := (*d)[i]
+		if o.policyOf(device).Optional {
+			l.Warn("Optional device failed to start", "device", device.Name(), "error", derr)
+			if o.failed != nil {
+				o.failed(device, derr)
+			}
+			continue
 		}
+		err = multierror.Append(err, derr)
 	}
 	return err
 }
 
+// startOne starts the Device i of d once its dependencies deps have started,
+// holding lock during each attempt, and returns its error
+func (d *Devices) startOne(l Logger, o *startOptions, i int, deps []int, errs []error, lock *sync.Mutex) error {
+	device := (*d)[i]
+	for _, j := range deps {
+		if errs[j] != nil {
+			return WrapError("start", device.Name(), fmt.Errorf("dependency %v failed", (*d)[j].Name()))
+		}
+	}
+
+	keyvals := []interface{}{"device", device.Name()}
+	if pinner, ok := device.(Pinner); ok {
+		keyvals = append(keyvals, "pin", pinner.Pin())
+	}
+	l.Info("Starting device", keyvals...)
+
+	_, span := StartSpan(o.traceContext(), DeviceStartSpan, deviceAttributes(device)...)
+	policy := o.policyOf(device)
+	var settled <-chan bool
+	attempt := func() (err error) {
+		// a retry waits for the Start which timed out to be over, so that
+		// its Halt does not stop the device started again
+		if settled != nil {
+			<-settled
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if err := checkPin(device); err != nil {
+			return err
+		}
+		settled, err = startDevice(l, device, o.timeoutOf(device))
+		return err
+	}
+	derr := attempt()
+	for n := 1; derr != nil && n <= policy.Retries; n++ {
+		l.Warn("Retrying device start", "device", device.Name(), "attempt", n, "error", derr)
+		span.SetAttributes(Attr("gobot.retries", n))
+		DefaultClock().Sleep(policy.RetryDelay)
+		derr = attempt()
+	}
+	EndSpan(span, derr)
+	if derr == nil && o != nil && o.started != nil {
+		o.started(device)
+	}
+	return WrapError("start", device.Name(), derr)
+}
+
+// startOrder returns the indexes of n Devices in their order, each Device
+// being moved after its dependencies deps
+func startOrder(deps map[int][]int, n int) []int {
+	order := []int{}
+	visited := make([]bool, n)
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, j := range deps[i] {
+			visit(j)
+		}
+		order = append(order, i)
+	}
+	for i := 0; i < n; i++ {
+		visit(i)
+	}
+	return order
+}
+
+// connectionLocks returns the locks held while starting each Device, shared
+// by the Devices with the same Connection
+func connectionLocks(devices []Device) []*sync.Mutex {
+	byConnection := make(map[Connection]*sync.Mutex)
+	locks := make([]*sync.Mutex, len(devices))
+	for i, device := range devices {
+		c := device.Connection()
+		if c == nil || !reflect.TypeOf(c).Comparable() {
+			locks[i] = &sync.Mutex{}
+			continue
+		}
+		if _, ok := byConnection[c]; !ok {
+			byConnection[c] = &sync.Mutex{}
+		}
+		locks[i] = byConnection[c]
+	}
+	return locks
+}
+
+// dependencyGraph returns the indexes of the Devices each Device depends on.
+// Connections are always started before Devices, so dependencies on them only
+// need to exist.
+func (d *Devices) dependencyGraph(o *startOptions) (map[int][]int, error) {
+	index := make(map[string]int)
+	for i, device := range *d {
+		// depending on a component is depending on its Composite Device
+		for _, named := range (&Devices{device}).flatten() {
+			if _, ok := index[named.Name()]; !ok {
+				index[named.Name()] = i
+			}
+		}
+	}
+
+	deps := make(map[int][]int)
+	for i, device := range *d {
+		for _, name := range o.dependenciesOf(device) {
+			if j, ok := index[name]; ok {
+				deps[i] = append(deps[i], j)
+				continue
+			}
+			if !o.hasConnection(name) {
+				return nil, fmt.Errorf("Device %v depends on %v: no Device or Connection found with that name", device.Name(), name)
+			}
+		}
+	}
+
+	// detect cycles with a depth first search
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(*d))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("Dependency cycle detected at device %v", (*d)[i].Name())
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, j := range deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+	for i := range *d {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// checkPin returns the error of the Connection of device when it is a
+// PinChecker and the pin of device does not support it.
+func checkPin(device Device) error {
+	if _, ok := device.(Pinner); !ok {
+		return nil
+	}
+	if checker, ok := device.Connection().(PinChecker); ok {
+		return checker.CheckPin(device)
+	}
+	return nil
+}
+
+// startDevice starts device, giving up after timeout unless it is zero.
+// Start is not cancelled by the timeout: once a Start which timed out
+// returns, the device is halted if it started, and the returned settled
+// channel is closed. settled is nil when Start returned in time.
+func startDevice(l Logger, device Device, timeout time.Duration) (settled <-chan bool, err error) {
+	if timeout <= 0 {
+		return nil, device.Start()
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- device.Start()
+	}()
+	select {
+	case err := <-result:
+		return nil, err
+	case <-DefaultClock().After(timeout):
+	}
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		if err := <-result; err != nil {
+			l.Warn("Device failed to start after its timeout", "device", device.Name(), "error", err)
+			return
+		}
+		l.Warn("Halting device started after its timeout", "device", device.Name())
+		if err := device.Halt(); err != nil {
+			l.Error("Device started after its timeout failed to halt", "device", device.Name(), "error", err)
+		}
+	}()
+	return done, fmt.Errorf("timeout after %v", timeout)
+}
+
 // Halt calls Halt on each Device in d
 func (d *Devices) Halt() (err error) {
+	return d.halt(context.Background())
+}
+
+// halt calls Halt on each Device in d, their Spans being children of the one
+// in ctx
+func (d *Devices) halt(ctx context.Context) (err error) {
 	for _, device := range *d {
-		if derr := device.Halt(); derr != nil {
-			err = multierror.Append(err, derr)
+		_, span := StartSpan(ctx, DeviceHaltSpan, deviceAttributes(device)...)
+		derr := device.Halt()
+		EndSpan(span, derr)
+		if derr != nil {
+			err = multierror.Append(err, WrapError("halt", device.Name(), derr))
 		}
 	}
 	return err
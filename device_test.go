@@ -0,0 +1,372 @@
+package gobot
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testStartDriver struct {
+	*testDriver
+	start        func() error
+	halted       chan bool
+	dependencies []string
+}
+
+func (t *testStartDriver) Start() error { return t.start() }
+func (t *testStartDriver) Halt() error {
+	if t.halted != nil {
+		t.halted <- true
+		return nil
+	}
+	return t.testDriver.Halt()
+}
+func (t *testStartDriver) Dependencies() []string { return t.dependencies }
+
+// startRecorder records the order in which Devices were started.
+type startRecorder struct {
+	order []string
+	mutex sync.Mutex
+}
+
+func (s *startRecorder) driver(name string, delay time.Duration, err error) *testStartDriver {
+	return &testStartDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), name, "0"),
+		start: func() error {
+			time.Sleep(delay)
+			s.mutex.Lock()
+			s.order = append(s.order, name)
+			s.mutex.Unlock()
+			return err
+		},
+	}
+}
+
+func (s *startRecorder) index(name string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, n := range s.order {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestDevicesStartSequential(t *testing.T) {
+	s := &startRecorder{}
+	d := &Devices{
+		s.driver("slow1", 30*time.Millisecond, nil),
+		s.driver("slow2", 20*time.Millisecond, nil),
+		s.driver("slow3", 10*time.Millisecond, nil),
+	}
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, s.order, []string{"slow1", "slow2", "slow3"})
+}
+
+func TestDevicesStartParallel(t *testing.T) {
+	s := &startRecorder{}
+	d := &Devices{
+		s.driver("slow1", 50*time.Millisecond, nil),
+		s.driver("slow2", 50*time.Millisecond, nil),
+		s.driver("slow3", 50*time.Millisecond, nil),
+	}
+	begin := time.Now()
+	gobottest.Assert(t, d.start(DefaultLogger(), &startOptions{parallel: true}), nil)
+	gobottest.Assert(t, time.Since(begin) < 140*time.Millisecond, true)
+	gobottest.Assert(t, len(s.order), 3)
+}
+
+func TestDevicesStartParallelSharedConnection(t *testing.T) {
+	s := &startRecorder{}
+	bus := newTestAdaptor("bus", "/dev/i2c-1")
+	var running, overlaps int32
+	d := &Devices{}
+	for _, name := range []string{"imu", "barometer", "display"} {
+		drv := s.driver(name, 0, nil)
+		drv.connection = bus
+		drv.start = func() error {
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		}
+		*d = append(*d, drv)
+	}
+	*d = append(*d, s.driver("led", 0, nil))
+
+	gobottest.Assert(t, d.start(DefaultLogger(), &startOptions{parallel: true}), nil)
+	gobottest.Assert(t, atomic.LoadInt32(&overlaps), int32(0))
+}
+
+func TestDevicesStartErrorsInOrder(t *testing.T) {
+	s := &startRecorder{}
+	d := &Devices{
+		s.driver("first", 20*time.Millisecond, errors.New("first failed")),
+		s.driver("second", 0, errors.New("second failed")),
+	}
+	err := d.Start()
+	gobottest.Refute(t, err, nil)
+	msg := err.Error()
+	gobottest.Assert(t, strings.Index(msg, "first failed") < strings.Index(msg, "second failed"), true)
+}
+
+func TestRobotDependsOn(t *testing.T) {
+	s := &startRecorder{}
+	imu := s.driver("imu", 30*time.Millisecond, nil)
+	fusion := s.driver("fusion", 0, nil)
+	display := s.driver("display", 0, nil)
+	r := NewRobot("deps", []Connection{imu.Connection()}, []Device{fusion, display, imu})
+	r.DependsOn("fusion", "imu", "Connection1")
+
+	gobottest.Assert(t, r.Devices().start(r.Logger(), r.startOptions()), nil)
+	gobottest.Assert(t, s.index("imu") < s.index("fusion"), true)
+	gobottest.Assert(t, s.index("display"), 0)
+}
+
+func TestDevicesStartDependent(t *testing.T) {
+	s := &startRecorder{}
+	bus := s.driver("bus", 20*time.Millisecond, nil)
+	sensor := s.driver("sensor", 0, nil)
+	sensor.dependencies = []string{"bus"}
+	d := &Devices{sensor, bus}
+
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, s.order, []string{"bus", "sensor"})
+}
+
+func TestDevicesStartFailedDependency(t *testing.T) {
+	s := &startRecorder{}
+	bus := s.driver("bus", 0, errors.New("bus failed"))
+	sensor := s.driver("sensor", 0, nil)
+	sensor.dependencies = []string{"bus"}
+	d := &Devices{bus, sensor}
+
+	err := d.Start()
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.Contains(err.Error(), "bus failed"), true)
+	gobottest.Assert(t, strings.Contains(err.Error(), "start sensor: dependency bus failed"), true)
+	gobottest.Assert(t, s.index("sensor"), -1)
+}
+
+func TestDevicesStartUnknownDependency(t *testing.T) {
+	s := &startRecorder{}
+	sensor := s.driver("sensor", 0, nil)
+	sensor.dependencies = []string{"missing"}
+	d := &Devices{sensor}
+
+	err := d.Start()
+	gobottest.Assert(t, err.Error(), "Device sensor depends on missing: no Device or Connection found with that name")
+	gobottest.Assert(t, len(s.order), 0)
+}
+
+func TestDevicesStartDependencyCycle(t *testing.T) {
+	s := &startRecorder{}
+	a := s.driver("a", 0, nil)
+	b := s.driver("b", 0, nil)
+	a.dependencies = []string{"b"}
+	b.dependencies = []string{"a"}
+	d := &Devices{a, b}
+
+	err := d.Start()
+	gobottest.Assert(t, err.Error(), "Dependency cycle detected at device a")
+	gobottest.Assert(t, len(s.order), 0)
+}
+
+func TestRobotStartTimeout(t *testing.T) {
+	s := &startRecorder{}
+	slow := s.driver("slow", 100*time.Millisecond, nil)
+	patient := s.driver("patient", 30*time.Millisecond, nil)
+	r := NewRobot("timeouts", []Device{slow, patient})
+	r.StartTimeout = 10 * time.Millisecond
+	r.SetStartTimeout("patient", time.Second)
+
+	err := r.Devices().start(r.Logger(), r.startOptions())
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.Contains(err.Error(), "start slow: timeout after 10ms"), true)
+	gobottest.Assert(t, strings.Contains(err.Error(), "patient"), false)
+}
+
+func TestStartDeviceHaltsLateStart(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(c)
+	defer SetDefaultClock(SystemClock())
+
+	release := make(chan error)
+	late := &testStartDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "late", "0"),
+		start:      func() error { return <-release },
+		halted:     make(chan bool, 1),
+	}
+
+	result := make(chan error)
+	var settled <-chan bool
+	go func() {
+		var err error
+		settled, err = startDevice(DefaultLogger(), late, time.Second)
+		result <- err
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	gobottest.Assert(t, (<-result).Error(), "timeout after 1s")
+	gobottest.Assert(t, len(late.halted), 0)
+
+	release <- nil
+	<-settled
+	gobottest.Assert(t, len(late.halted), 1)
+
+	// a late Start which fails is not halted
+	late.halted = make(chan bool, 1)
+	go func() {
+		var err error
+		settled, err = startDevice(DefaultLogger(), late, time.Second)
+		result <- err
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	<-result
+	release <- errors.New("no device")
+	<-settled
+	gobottest.Assert(t, len(late.halted), 0)
+}
+
+func TestRobotStartOptionalDevice(t *testing.T) {
+	s := &startRecorder{}
+	r := NewRobot("policies", []Device{
+		s.driver("motor", 0, nil),
+		s.driver("sensor", 0, errors.New("no device")),
+	})
+	r.SetStartPolicy("sensor", StartOptional)
+
+	failures := make(chan interface{}, 1)
+	r.On(DeviceFailure, func(data interface{}) {
+		failures <- data
+	})
+
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+	gobottest.Assert(t, r.Running(), true)
+
+	select {
+	case data := <-failures:
+		e := data.(*Error)
+		gobottest.Assert(t, e.Component, "sensor")
+		gobottest.Assert(t, e.Error(), "start sensor: no device")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("DeviceFailure event was not published")
+	}
+	gobottest.Assert(t, r.Telemetry().Devices[1].State, DeviceFailed)
+}
+
+func TestRobotStartOptionalDependency(t *testing.T) {
+	s := &startRecorder{}
+	r := NewRobot("policies", []Device{
+		s.driver("sensor", 0, errors.New("no device")),
+		s.driver("display", 0, nil),
+	})
+	r.SetStartPolicy("sensor", StartOptional)
+	r.DependsOn("display", "sensor")
+
+	err := r.Devices().start(r.Logger(), r.startOptions())
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.Contains(err.Error(), "start display: dependency sensor failed"), true)
+}
+
+func TestRobotStartRequiredDevice(t *testing.T) {
+	s := &startRecorder{}
+	r := NewRobot("policies", []Device{
+		s.driver("sensor", 0, errors.New("no device")),
+	})
+	r.SetStartPolicy("sensor", StartRequired)
+	gobottest.Refute(t, r.Start(false), nil)
+	gobottest.Assert(t, r.Running(), false)
+}
+
+func TestRobotStartRetry(t *testing.T) {
+	attempts := 0
+	flaky := &testStartDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "flaky", "0"),
+		start: func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("not ready")
+			}
+			return nil
+		},
+	}
+	r := NewRobot("policies", []Device{flaky})
+	r.SetStartPolicy("flaky", StartRetry(2, time.Millisecond))
+
+	gobottest.Assert(t, r.Devices().start(r.Logger(), r.startOptions()), nil)
+	gobottest.Assert(t, attempts, 3)
+
+	attempts = 0
+	r.SetStartPolicy("flaky", StartRetry(1, time.Millisecond))
+	err := r.Devices().start(r.Logger(), r.startOptions())
+	gobottest.Assert(t, strings.Contains(err.Error(), "start flaky: not ready"), true)
+	gobottest.Assert(t, attempts, 2)
+}
+
+// pinCheckAdaptor only supports the pins in pins.
+type pinCheckAdaptor struct {
+	*testAdaptor
+	pins map[string]bool
+}
+
+func (p *pinCheckAdaptor) CheckPin(device Device) error {
+	pin := device.(Pinner).Pin()
+	if !p.pins[pin] {
+		return errors.New("pin " + pin + " has no PWM")
+	}
+	return nil
+}
+
+func TestDevicesStartCheckPin(t *testing.T) {
+	a := &pinCheckAdaptor{testAdaptor: newTestAdaptor("Connection1", "/dev/null"), pins: map[string]bool{"3": true}}
+	started := []string{}
+	driver := func(name, pin string) *testStartDriver {
+		d := &testStartDriver{testDriver: newTestDriver(nil, name, pin)}
+		d.connection = a
+		d.start = func() error {
+			started = append(started, name)
+			return nil
+		}
+		return d
+	}
+
+	d := &Devices{driver("good", "3")}
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, started, []string{"good"})
+
+	d = &Devices{driver("bad", "13")}
+	err := d.Start()
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.Contains(err.Error(), "pin 13 has no PWM"), true)
+	gobottest.Assert(t, started, []string{"good"})
+}
+
+func TestNewJSONDevice(t *testing.T) {
+	d := newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "Device1", "0")
+	json := NewJSONDevice(d)
+	gobottest.Assert(t, json.Name, "Device1")
+	gobottest.Assert(t, json.Connection, "Connection1")
+	gobottest.Assert(t, json.Commands, []string{"DriverCommand"})
+	gobottest.Assert(t, json.Events, []string{})
+	gobottest.Assert(t, json.Capabilities, []string{})
+
+	e := &struct {
+		*testDriver
+		Eventer
+	}{d, NewEventer()}
+	e.AddEvent("push")
+	e.AddEvent("release")
+	e.AddEvent("error")
+	gobottest.Assert(t, NewJSONDevice(e).Events, []string{"error", "push", "release"})
+}
@@ -8,6 +8,9 @@ var (
 	// ErrAnalogReadUnsupported is error resulting when a driver attempts to use
 	// hardware capabilities which a connection does not support
 	ErrAnalogReadUnsupported = errors.New("AnalogRead is not supported by this platform")
+	// ErrAnalogWriteUnsupported is error resulting when a driver attempts to use
+	// hardware capabilities which a connection does not support
+	ErrAnalogWriteUnsupported = errors.New("AnalogWrite is not supported by this platform")
 )
 
 const (
@@ -24,3 +27,11 @@ type AnalogReader interface {
 	//gobot.Adaptor
 	AnalogRead(string) (val int, err error)
 }
+
+// AnalogWriter interface represents an Adaptor which has analog output
+// capabilities, such as a DAC. The value is in the resolution of the output,
+// e.g. from 0 to 4095 for a 12 bits DAC.
+type AnalogWriter interface {
+	//gobot.Adaptor
+	AnalogWrite(string, int) (err error)
+}
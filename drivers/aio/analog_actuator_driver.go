@@ -0,0 +1,126 @@
+package aio
+
+import (
+	"math"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// AnalogActuatorDriver represents an analog output, such as the output of a
+// DAC driving a motor controller or a gauge
+type AnalogActuatorDriver struct {
+	name       string
+	pin        string
+	connection AnalogWriter
+	scale      func(float64) int
+	value      float64
+	raw        int
+	mutex      *sync.Mutex
+	gobot.Commander
+}
+
+// NewAnalogActuatorDriver returns a new AnalogActuatorDriver given an
+// AnalogWriter and pin. The values written are rounded to the raw value of
+// the output until a scaler is set with SetScaler.
+//
+// Adds the following API Commands:
+// 	"Write" - See AnalogActuatorDriver.Write
+// 	"RawWrite" - See AnalogActuatorDriver.RawWrite
+func NewAnalogActuatorDriver(a AnalogWriter, pin string) *AnalogActuatorDriver {
+	d := &AnalogActuatorDriver{
+		name:       gobot.DefaultName("AnalogActuator"),
+		connection: a,
+		pin:        pin,
+		scale:      func(v float64) int { return int(math.Round(v)) },
+		mutex:      &sync.Mutex{},
+		Commander:  gobot.NewCommander(),
+	}
+
+	d.AddCommand("Write", func(params map[string]interface{}) interface{} {
+		return d.Write(params["val"].(float64))
+	})
+	d.AddCommand("RawWrite", func(params map[string]interface{}) interface{} {
+		return d.RawWrite(int(params["val"].(float64)))
+	})
+
+	return d
+}
+
+// AnalogActuatorLinearScaler returns a scaler converting the values from
+// fromMin to fromMax into raw values from toMin to toMax, the values out of
+// range being clamped, e.g. AnalogActuatorLinearScaler(0, 5, 0, 4095) for a 5V
+// 12 bits DAC written in volts.
+func AnalogActuatorLinearScaler(fromMin, fromMax float64, toMin, toMax int) func(float64) int {
+	return func(v float64) int {
+		v = math.Max(math.Min(v, math.Max(fromMin, fromMax)), math.Min(fromMin, fromMax))
+		raw := float64(toMin) + (v-fromMin)/(fromMax-fromMin)*float64(toMax-toMin)
+		return int(math.Round(raw))
+	}
+}
+
+// Start starts the AnalogActuatorDriver
+func (a *AnalogActuatorDriver) Start() (err error) { return }
+
+// Halt halts the AnalogActuatorDriver, the output keeping its value
+func (a *AnalogActuatorDriver) Halt() (err error) { return }
+
+// Name returns the AnalogActuatorDrivers name
+func (a *AnalogActuatorDriver) Name() string { return a.name }
+
+// SetName sets the AnalogActuatorDrivers name
+func (a *AnalogActuatorDriver) SetName(n string) { a.name = n }
+
+// Pin returns the AnalogActuatorDrivers pin
+func (a *AnalogActuatorDriver) Pin() string { return a.pin }
+
+// Connection returns the AnalogActuatorDrivers Connection
+func (a *AnalogActuatorDriver) Connection() gobot.Connection { return a.connection.(gobot.Connection) }
+
+// SetScaler sets the function converting the values written into the raw
+// values of the output, e.g. an AnalogActuatorLinearScaler.
+func (a *AnalogActuatorDriver) SetScaler(scale func(float64) int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.scale = scale
+}
+
+// Write converts val with the scaler and writes the raw value to the output
+func (a *AnalogActuatorDriver) Write(val float64) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	raw := a.scale(val)
+	if err = a.connection.AnalogWrite(a.pin, raw); err != nil {
+		return err
+	}
+	a.value, a.raw = val, raw
+	return nil
+}
+
+// RawWrite writes the raw value to the output, in the resolution of the
+// output
+func (a *AnalogActuatorDriver) RawWrite(raw int) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err = a.connection.AnalogWrite(a.pin, raw); err != nil {
+		return err
+	}
+	a.value, a.raw = float64(raw), raw
+	return nil
+}
+
+// Value returns the last value written, before scaling
+func (a *AnalogActuatorDriver) Value() float64 {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.value
+}
+
+// RawValue returns the last raw value written to the output
+func (a *AnalogActuatorDriver) RawValue() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.raw
+}
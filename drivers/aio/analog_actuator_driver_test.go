@@ -0,0 +1,93 @@
+package aio
+
+import (
+	"errors"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*AnalogActuatorDriver)(nil)
+
+func TestAnalogActuatorDriver(t *testing.T) {
+	a := newAioTestAdaptor()
+	d := NewAnalogActuatorDriver(a, "47")
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, d.Pin(), "47")
+
+	var pin string
+	var written int
+	a.TestAdaptorAnalogWrite(func(p string, val int) (err error) {
+		pin, written = p, val
+		return
+	})
+
+	ret := d.Command("Write")(map[string]interface{}{"val": 99.6})
+	gobottest.Assert(t, ret, nil)
+	gobottest.Assert(t, pin, "47")
+	gobottest.Assert(t, written, 100)
+	gobottest.Assert(t, d.Value(), 99.6)
+	gobottest.Assert(t, d.RawValue(), 100)
+
+	ret = d.Command("RawWrite")(map[string]interface{}{"val": 1234.0})
+	gobottest.Assert(t, ret, nil)
+	gobottest.Assert(t, written, 1234)
+	gobottest.Assert(t, d.RawValue(), 1234)
+}
+
+func TestAnalogActuatorDriverScaler(t *testing.T) {
+	a := newAioTestAdaptor()
+	d := NewAnalogActuatorDriver(a, "1")
+	d.SetScaler(AnalogActuatorLinearScaler(0, 5, 0, 4095))
+
+	var written int
+	a.TestAdaptorAnalogWrite(func(p string, val int) (err error) {
+		written = val
+		return
+	})
+
+	gobottest.Assert(t, d.Write(2.5), nil)
+	gobottest.Assert(t, written, 2048)
+	gobottest.Assert(t, d.Value(), 2.5)
+	gobottest.Assert(t, d.RawValue(), 2048)
+
+	gobottest.Assert(t, d.Write(7), nil)
+	gobottest.Assert(t, written, 4095)
+	gobottest.Assert(t, d.Write(-1), nil)
+	gobottest.Assert(t, written, 0)
+}
+
+func TestAnalogActuatorLinearScalerReversed(t *testing.T) {
+	scale := AnalogActuatorLinearScaler(-100, 100, 255, 0)
+	gobottest.Assert(t, scale(-100), 255)
+	gobottest.Assert(t, scale(0), 128)
+	gobottest.Assert(t, scale(100), 0)
+	gobottest.Assert(t, scale(200), 0)
+}
+
+func TestAnalogActuatorDriverWriteError(t *testing.T) {
+	a := newAioTestAdaptor()
+	d := NewAnalogActuatorDriver(a, "1")
+	gobottest.Assert(t, d.Write(10), nil)
+
+	a.TestAdaptorAnalogWrite(func(p string, val int) (err error) {
+		return errors.New("write error")
+	})
+	gobottest.Assert(t, d.Write(20), errors.New("write error"))
+	gobottest.Assert(t, d.RawWrite(30), errors.New("write error"))
+	gobottest.Assert(t, d.Value(), 10.0)
+	gobottest.Assert(t, d.RawValue(), 10)
+}
+
+func TestAnalogActuatorDriverStartHalt(t *testing.T) {
+	d := NewAnalogActuatorDriver(newAioTestAdaptor(), "1")
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestAnalogActuatorDriverSetName(t *testing.T) {
+	d := NewAnalogActuatorDriver(newAioTestAdaptor(), "1")
+	d.SetName("mybot")
+	gobottest.Assert(t, d.Name(), "mybot")
+}
@@ -12,6 +12,7 @@ type AnalogSensorDriver struct {
 	pin        string
 	halt       chan bool
 	interval   time.Duration
+	polling    gobot.PollerConfig
 	connection AnalogReader
 	gobot.Eventer
 	gobot.Commander
@@ -57,30 +58,32 @@ func NewAnalogSensorDriver(a AnalogReader, pin string, v ...time.Duration) *Anal
 //	Error error - Event is emitted on error reading from the sensor.
 func (a *AnalogSensorDriver) Start() (err error) {
 	var value int = 0
-	go func() {
-		timer := time.NewTimer(a.interval)
-		timer.Stop()
-		for {
-			newValue, err := a.Read()
-			if err != nil {
-				a.Publish(a.Event(Error), err)
-			} else if newValue != value && newValue != -1 {
-				value = newValue
-				a.Publish(a.Event(Data), value)
-			}
-
-			timer.Reset(a.interval)
-			select {
-			case <-timer.C:
-			case <-a.halt:
-				timer.Stop()
-				return
-			}
+	config := a.polling
+	config.Interval = a.interval
+	gobot.NewPoller(config).Go(a, a.halt, func() bool {
+		newValue, err := a.Read()
+		if err != nil {
+			a.Publish(a.Event(Error), err)
+		} else if newValue != value && newValue != -1 {
+			value = newValue
+			a.Publish(a.Event(Data), value)
+			return true
 		}
-	}()
+		return false
+	})
 	return
 }
 
+// SetPolling configures the polling of the sensor, for example to read it
+// less often while its value is steady. The interval given to
+// NewAnalogSensorDriver is kept when c.Interval is not set.
+func (a *AnalogSensorDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		a.interval = c.Interval
+	}
+	a.polling = c
+}
+
 // Halt stops polling the analog sensor for new information
 func (a *AnalogSensorDriver) Halt() (err error) {
 	a.halt <- true
@@ -8,6 +8,7 @@ import (
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/gobottest/leaktest"
 )
 
 var _ gobot.Driver = (*AnalogSensorDriver)(nil)
@@ -97,6 +98,14 @@ func TestAnalogSensorDriverStart(t *testing.T) {
 	}
 }
 
+func TestAnalogSensorDriverReleasesPoller(t *testing.T) {
+	d := NewAnalogSensorDriver(newAioTestAdaptor(), "1")
+	gobottest.Assert(t, d.Start(), nil)
+	leaktest.Held(t, d)
+	gobottest.Assert(t, d.Halt(), nil)
+	leaktest.Check(t, d)
+}
+
 func TestAnalogSensorDriverHalt(t *testing.T) {
 	d := NewAnalogSensorDriver(newAioTestAdaptor(), "1")
 	done := make(chan struct{})
@@ -122,3 +131,13 @@ func TestAnalogSensorDriverSetName(t *testing.T) {
 	d.SetName("mybot")
 	gobottest.Assert(t, d.Name(), "mybot")
 }
+
+func TestAnalogSensorDriverSetPolling(t *testing.T) {
+	d := NewAnalogSensorDriver(newAioTestAdaptor(), "1")
+	d.SetPolling(gobot.PollerConfig{MaxInterval: time.Second, Backoff: 1.5})
+	gobottest.Assert(t, d.interval, 10*time.Millisecond)
+	gobottest.Assert(t, d.polling, gobot.PollerConfig{MaxInterval: time.Second, Backoff: 1.5})
+
+	d.SetPolling(gobot.PollerConfig{Interval: time.Millisecond})
+	gobottest.Assert(t, d.interval, time.Millisecond)
+}
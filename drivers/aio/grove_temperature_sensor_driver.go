@@ -2,6 +2,7 @@ package aio
 
 import (
 	"math"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -16,7 +17,9 @@ type GroveTemperatureSensorDriver struct {
 	pin         string
 	halt        chan bool
 	temperature float64
+	mutex       *sync.Mutex
 	interval    time.Duration
+	polling     gobot.PollerConfig
 	connection  AnalogReader
 	gobot.Eventer
 }
@@ -37,6 +40,7 @@ func NewGroveTemperatureSensorDriver(a AnalogReader, pin string, v ...time.Durat
 		Eventer:    gobot.NewEventer(),
 		interval:   10 * time.Millisecond,
 		halt:       make(chan bool),
+		mutex:      &sync.Mutex{},
 	}
 
 	if len(v) > 0 {
@@ -55,31 +59,37 @@ func NewGroveTemperatureSensorDriver(a AnalogReader, pin string, v ...time.Durat
 //	Error error - Event is emitted on error reading from the sensor.
 func (a *GroveTemperatureSensorDriver) Start() (err error) {
 	thermistor := 3975.0
-	a.temperature = 0
-
-	go func() {
-		for {
-			rawValue, err := a.Read()
-
-			resistance := float64(1023.0-rawValue) * 10000 / float64(rawValue)
-			newValue := 1/(math.Log(resistance/10000.0)/thermistor+1/298.15) - 273.15
-
-			if err != nil {
-				a.Publish(Error, err)
-			} else if newValue != a.temperature && newValue != -1 {
-				a.temperature = newValue
-				a.Publish(Data, a.temperature)
-			}
-			select {
-			case <-time.After(a.interval):
-			case <-a.halt:
-				return
-			}
+	a.setTemperature(0)
+
+	config := a.polling
+	config.Interval = a.interval
+	gobot.NewPoller(config).Go(a, a.halt, func() bool {
+		rawValue, err := a.Read()
+
+		resistance := float64(1023.0-rawValue) * 10000 / float64(rawValue)
+		newValue := 1/(math.Log(resistance/10000.0)/thermistor+1/298.15) - 273.15
+
+		if err != nil {
+			a.Publish(Error, err)
+		} else if newValue != a.Temperature() && newValue != -1 {
+			a.setTemperature(newValue)
+			a.Publish(Data, newValue)
+			return true
 		}
-	}()
+		return false
+	})
 	return
 }
 
+// SetPolling configures the polling of the sensor. The interval given to
+// NewGroveTemperatureSensorDriver is kept when c.Interval is not set.
+func (a *GroveTemperatureSensorDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		a.interval = c.Interval
+	}
+	a.polling = c
+}
+
 // Halt stops polling the analog sensor for new information
 func (a *GroveTemperatureSensorDriver) Halt() (err error) {
 	a.halt <- true
@@ -102,9 +112,17 @@ func (a *GroveTemperatureSensorDriver) Connection() gobot.Connection {
 
 // Read returns the current Temperature from the Sensor
 func (a *GroveTemperatureSensorDriver) Temperature() (val float64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 	return a.temperature
 }
 
+func (a *GroveTemperatureSensorDriver) setTemperature(val float64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.temperature = val
+}
+
 // Read returns the raw reading from the Sensor
 func (a *GroveTemperatureSensorDriver) Read() (val int, err error) {
 	return a.connection.AnalogRead(a.Pin())
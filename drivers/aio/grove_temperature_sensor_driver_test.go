@@ -96,3 +96,10 @@ func TestGroveTempDriverSetName(t *testing.T) {
 	d.SetName("mybot")
 	gobottest.Assert(t, d.Name(), "mybot")
 }
+
+func TestGroveTempSensorSetPolling(t *testing.T) {
+	d := NewGroveTemperatureSensorDriver(newAioTestAdaptor(), "123")
+	d.SetPolling(gobot.PollerConfig{Interval: time.Second, MaxInterval: time.Minute})
+	gobottest.Assert(t, d.interval, time.Second)
+	gobottest.Assert(t, d.polling.MaxInterval, time.Minute)
+}
@@ -10,10 +10,11 @@ func (t *aioTestBareAdaptor) Name() string          { return "" }
 func (t *aioTestBareAdaptor) SetName(n string)      {}
 
 type aioTestAdaptor struct {
-	name                  string
-	port                  string
-	mtx                   sync.Mutex
-	testAdaptorAnalogRead func() (val int, err error)
+	name                   string
+	port                   string
+	mtx                    sync.Mutex
+	testAdaptorAnalogRead  func() (val int, err error)
+	testAdaptorAnalogWrite func(pin string, val int) (err error)
 }
 
 func (t *aioTestAdaptor) TestAdaptorAnalogRead(f func() (val int, err error)) {
@@ -27,6 +28,18 @@ func (t *aioTestAdaptor) AnalogRead(string) (val int, err error) {
 	defer t.mtx.Unlock()
 	return t.testAdaptorAnalogRead()
 }
+
+func (t *aioTestAdaptor) TestAdaptorAnalogWrite(f func(pin string, val int) (err error)) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.testAdaptorAnalogWrite = f
+}
+
+func (t *aioTestAdaptor) AnalogWrite(pin string, val int) (err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.testAdaptorAnalogWrite(pin, val)
+}
 func (t *aioTestAdaptor) Connect() (err error)  { return }
 func (t *aioTestAdaptor) Finalize() (err error) { return }
 func (t *aioTestAdaptor) Name() string          { return t.name }
@@ -39,5 +52,8 @@ func newAioTestAdaptor() *aioTestAdaptor {
 		testAdaptorAnalogRead: func() (val int, err error) {
 			return 99, nil
 		},
+		testAdaptorAnalogWrite: func(pin string, val int) (err error) {
+			return nil
+		},
 	}
 }
@@ -0,0 +1,9 @@
+/*
+Package font provides the bitmap fonts of the Gobot drivers showing text on
+LED matrices and graphic displays.
+
+Installing:
+
+	go get -d -u gobot.io/x/gobot
+*/
+package font // import "gobot.io/x/gobot/drivers/font"
@@ -0,0 +1,76 @@
+package font
+
+// Font is a bitmap font of consecutive characters, for the displays showing
+// text. Each glyph is a slice of columns of pixels, the least significant bit
+// being the top pixel.
+type Font struct {
+	width  int
+	height int
+	first  rune
+	glyphs [][]byte
+}
+
+// New returns a new Font given the size of its glyphs, in pixels, and the
+// glyphs of the characters from first. The glyphs may be narrower than width
+// in a proportional font.
+func New(width int, height int, first rune, glyphs [][]byte) *Font {
+	return &Font{
+		width:  width,
+		height: height,
+		first:  first,
+		glyphs: glyphs,
+	}
+}
+
+// Width returns the width of the widest glyph, in pixels
+func (f *Font) Width() int { return f.width }
+
+// Height returns the height of the glyphs, in pixels
+func (f *Font) Height() int { return f.height }
+
+// Glyph returns the columns of the glyph of r, a question mark for the
+// characters without one
+func (f *Font) Glyph(r rune) []byte {
+	if r < f.first || int(r-f.first) >= len(f.glyphs) {
+		r = '?'
+		if r < f.first || int(r-f.first) >= len(f.glyphs) {
+			return make([]byte, f.width)
+		}
+	}
+	return f.glyphs[r-f.first]
+}
+
+// Proportional returns a copy of the Font whose glyphs are trimmed of their
+// empty columns on both sides, the empty glyphs, such as the space, keeping
+// half of their width.
+func (f *Font) Proportional() *Font {
+	glyphs := make([][]byte, len(f.glyphs))
+	for i, g := range f.glyphs {
+		start, end := 0, len(g)
+		for start < end && g[start] == 0 {
+			start++
+		}
+		for end > start && g[end-1] == 0 {
+			end--
+		}
+		if start == end {
+			glyphs[i] = make([]byte, (len(g)+1)/2)
+			continue
+		}
+		glyphs[i] = g[start:end]
+	}
+	return New(f.width, f.height, f.first, glyphs)
+}
+
+// TextWidth returns the width of text, in pixels, its glyphs being separated
+// by spacing columns
+func (f *Font) TextWidth(text string, spacing int) int {
+	width := 0
+	for _, r := range text {
+		width += len(f.Glyph(r)) + spacing
+	}
+	if width > 0 {
+		width -= spacing
+	}
+	return width
+}
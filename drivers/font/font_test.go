@@ -0,0 +1,31 @@
+package font
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestFixed5x7(t *testing.T) {
+	gobottest.Assert(t, Fixed5x7.Width(), 5)
+	gobottest.Assert(t, Fixed5x7.Height(), 7)
+	gobottest.Assert(t, Fixed5x7.Glyph('A'), []byte{0x7E, 0x11, 0x11, 0x11, 0x7E})
+	gobottest.Assert(t, Fixed5x7.Glyph('é'), Fixed5x7.Glyph('?'))
+	gobottest.Assert(t, Fixed5x7.Glyph('\n'), Fixed5x7.Glyph('?'))
+	gobottest.Assert(t, Fixed5x7.TextWidth("Hi", 1), 11)
+	gobottest.Assert(t, Fixed5x7.TextWidth("", 1), 0)
+}
+
+func TestProportional5x7(t *testing.T) {
+	gobottest.Assert(t, Proportional5x7.Width(), 5)
+	gobottest.Assert(t, Proportional5x7.Glyph('!'), []byte{0x5F})
+	gobottest.Assert(t, Proportional5x7.Glyph(' '), []byte{0x00, 0x00, 0x00})
+	gobottest.Assert(t, Proportional5x7.Glyph('A'), Fixed5x7.Glyph('A'))
+	gobottest.Assert(t, Proportional5x7.TextWidth("i!", 1), len(Proportional5x7.Glyph('i'))+2)
+}
+
+func TestNew(t *testing.T) {
+	f := New(2, 2, 'a', [][]byte{{0x01, 0x02}})
+	gobottest.Assert(t, f.Glyph('a'), []byte{0x01, 0x02})
+	gobottest.Assert(t, f.Glyph('b'), []byte{0x00, 0x00})
+}
@@ -14,6 +14,7 @@ type ButtonDriver struct {
 	name         string
 	halt         chan bool
 	interval     time.Duration
+	polling      gobot.PollerConfig
 	connection   DigitalReader
 	gobot.Eventer
 }
@@ -54,25 +55,32 @@ func NewButtonDriver(a DigitalReader, pin string, v ...time.Duration) *ButtonDri
 //	Error error - On button error
 func (b *ButtonDriver) Start() (err error) {
 	state := b.DefaultState
-	go func() {
-		for {
-			newValue, err := b.connection.DigitalRead(b.Pin())
-			if err != nil {
-				b.Publish(Error, err)
-			} else if newValue != state && newValue != -1 {
-				state = newValue
-				b.update(newValue)
-			}
-			select {
-			case <-time.After(b.interval):
-			case <-b.halt:
-				return
-			}
+	config := b.polling
+	config.Interval = b.interval
+	gobot.NewPoller(config).Go(b, b.halt, func() bool {
+		newValue, err := b.connection.DigitalRead(b.Pin())
+		if err != nil {
+			b.Publish(Error, err)
+		} else if newValue != state && newValue != -1 {
+			state = newValue
+			b.update(newValue)
+			return true
 		}
-	}()
+		return false
+	})
 	return
 }
 
+// SetPolling configures the polling of the button, for example to poll it
+// less often while it is not pressed. The interval given to
+// NewButtonDriver is kept when c.Interval is not set.
+func (b *ButtonDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		b.interval = c.Interval
+	}
+	b.polling = c
+}
+
 // Halt stops polling the button for new information
 func (b *ButtonDriver) Halt() (err error) {
 	b.halt <- true
@@ -8,6 +8,7 @@ import (
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/gobottest/leaktest"
 )
 
 var _ gobot.Driver = (*ButtonDriver)(nil)
@@ -18,6 +19,20 @@ func initTestButtonDriver() *ButtonDriver {
 	return NewButtonDriver(newGpioTestAdaptor(), "1")
 }
 
+func TestButtonDriverReleasesPoller(t *testing.T) {
+	d := NewButtonDriver(newGpioTestAdaptor(), "1")
+	gobottest.Assert(t, d.Start(), nil)
+	leaktest.Held(t, d)
+	gobottest.Assert(t, d.Halt(), nil)
+	leaktest.Check(t, d)
+}
+
+func TestButtonDriverZeroInterval(t *testing.T) {
+	d := NewButtonDriver(newGpioTestAdaptor(), "1", 0)
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
 func TestButtonDriverHalt(t *testing.T) {
 	d := initTestButtonDriver()
 	go func() {
@@ -157,3 +172,101 @@ func TestButtonDriverSetName(t *testing.T) {
 	g.SetName("mybot")
 	gobottest.Assert(t, g.Name(), "mybot")
 }
+
+func TestButtonDriverFakeClock(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := newGpioTestAdaptor()
+	d := NewButtonDriver(a, "1")
+	a.TestAdaptorDigitalRead(func() (val int, err error) {
+		return 0, nil
+	})
+
+	pushed := make(chan bool, 1)
+	d.Once(ButtonPush, func(data interface{}) {
+		pushed <- true
+	})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	clock.BlockUntil(1)
+	a.TestAdaptorDigitalRead(func() (val int, err error) {
+		return 1, nil
+	})
+
+	// without advancing the clock the button is not polled again
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case <-pushed:
+			return
+		case <-deadline:
+			t.Errorf("Button push was not detected")
+			return
+		case <-time.After(time.Millisecond):
+			clock.Advance(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestButtonDriverEvents(t *testing.T) {
+	a := newGpioTestAdaptor()
+	d := NewButtonDriver(a, "1", time.Millisecond)
+	a.TestAdaptorDigitalRead(func() (val int, err error) {
+		return 1, nil
+	})
+	events := gobottest.RecordEvents(t, d, ButtonPush, ButtonRelease)
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	events.ExpectEvent(ButtonPush, time.Second, nil)
+	events.ExpectNoEvent(ButtonRelease, 10*time.Millisecond)
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) {
+		return 0, nil
+	})
+	events.ExpectEvent(ButtonRelease, time.Second, nil)
+}
+
+func TestButtonDriverSetPolling(t *testing.T) {
+	d := NewButtonDriver(newGpioTestAdaptor(), "1", 30*time.Millisecond)
+	d.SetPolling(gobot.PollerConfig{MaxInterval: time.Second})
+	gobottest.Assert(t, d.interval, 30*time.Millisecond)
+	gobottest.Assert(t, d.polling.MaxInterval, time.Second)
+
+	d.SetPolling(gobot.PollerConfig{Interval: 5 * time.Millisecond})
+	gobottest.Assert(t, d.interval, 5*time.Millisecond)
+}
+
+func TestButtonDriverAdaptivePolling(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := newGpioTestAdaptor()
+	reads := make(chan bool, 10)
+	a.TestAdaptorDigitalRead(func() (val int, err error) {
+		reads <- true
+		return 0, nil
+	})
+	d := NewButtonDriver(a, "1")
+	d.SetPolling(gobot.PollerConfig{MaxInterval: 40 * time.Millisecond})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	<-reads
+	// the button is not pressed, it is polled after 20ms then 40ms
+	for _, wait := range []time.Duration{20, 40, 40} {
+		clock.BlockUntil(1)
+		clock.Advance(wait*time.Millisecond - time.Millisecond)
+		select {
+		case <-reads:
+			t.Errorf("Button polled before %v", wait*time.Millisecond)
+		case <-time.After(5 * time.Millisecond):
+		}
+		clock.Advance(time.Millisecond)
+		<-reads
+	}
+}
@@ -1,6 +1,7 @@
 package gpio
 
 import (
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -134,9 +135,24 @@ type BuzzerDriver struct {
 	connection DigitalWriter
 	high       bool
 	BPM        float64
+	playing    *buzzerPlay
+	mutex      *sync.Mutex
+	gobot.Eventer
+}
+
+// buzzerPlay is a tone or a melody being played
+type buzzerPlay struct {
+	// stop is closed to interrupt the play
+	stop chan bool
+	// done is closed once the play has ended
+	done chan bool
 }
 
 // NewBuzzerDriver return a new BuzzerDriver given a DigitalWriter and pin.
+//
+// The tones are played with the hardware PWM of the pin when the adaptor
+// provides one whose period can be set, such as the sysfs PWM pins of most
+// Linux boards, and by toggling the pin otherwise.
 func NewBuzzerDriver(a DigitalWriter, pin string) *BuzzerDriver {
 	l := &BuzzerDriver{
 		name:       gobot.DefaultName("Buzzer"),
@@ -144,16 +160,23 @@ func NewBuzzerDriver(a DigitalWriter, pin string) *BuzzerDriver {
 		connection: a,
 		high:       false,
 		BPM:        96.0,
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
 	}
 
+	l.AddEvent(Error)
+
 	return l
 }
 
 // Start implements the Driver interface
 func (l *BuzzerDriver) Start() (err error) { return }
 
-// Halt implements the Driver interface
-func (l *BuzzerDriver) Halt() (err error) { return }
+// Halt stops the melody playing
+func (l *BuzzerDriver) Halt() (err error) {
+	l.Stop()
+	return
+}
 
 // Name returns the BuzzerDrivers name
 func (l *BuzzerDriver) Name() string { return l.name }
@@ -202,23 +225,166 @@ func (l *BuzzerDriver) Toggle() (err error) {
 	return
 }
 
+// Tone plays a tone of frequency hz for duration beats, at the BPM of the
+// BuzzerDriver. It interrupts the tone or melody playing, and can itself be
+// interrupted by Stop.
 func (l *BuzzerDriver) Tone(hz, duration float64) (err error) {
-	// calculation based off https://www.arduino.cc/en/Tutorial/Melody
-	tone := (1.0 / (2.0 * hz)) * 1000000.0
+	d := time.Duration((60 / l.BPM) * duration * float64(time.Second))
+	p := l.begin()
+	defer close(p.done)
+	return l.tone(hz, d, p.stop)
+}
+
+// PlayNotes starts playing notes in the background, interrupting the tone
+// or melody playing.
+//
+// Emits the Events:
+// 	Error error - On error playing a note, which ends the melody
+func (l *BuzzerDriver) PlayNotes(notes []Note) {
+	p := l.begin()
+	go func() {
+		defer close(p.done)
+		for _, n := range notes {
+			select {
+			case <-p.stop:
+				return
+			default:
+			}
+			if err := l.tone(n.Frequency, n.Duration, p.stop); err != nil {
+				l.Publish(Error, err)
+				return
+			}
+		}
+	}()
+}
+
+// PlayRTTTL starts playing a ringtone in the background, see ParseRTTTL and
+// PlayNotes.
+func (l *BuzzerDriver) PlayRTTTL(rtttl string) error {
+	m, err := ParseRTTTL(rtttl)
+	if err != nil {
+		return err
+	}
+	l.PlayNotes(m.Notes)
+	return nil
+}
 
-	tempo := ((60 / l.BPM) * (duration * 1000))
+// Playing returns true while a tone or melody is playing
+func (l *BuzzerDriver) Playing() bool {
+	l.mutex.Lock()
+	p := l.playing
+	l.mutex.Unlock()
+
+	if p == nil {
+		return false
+	}
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Stop interrupts the tone or melody playing, and returns once the buzzer
+// is silent.
+func (l *BuzzerDriver) Stop() {
+	l.mutex.Lock()
+	p := l.playing
+	l.playing = nil
+	l.mutex.Unlock()
+
+	if p != nil {
+		close(p.stop)
+		<-p.done
+	}
+}
+
+// begin stops the play in progress and starts a new one
+func (l *BuzzerDriver) begin() *buzzerPlay {
+	l.Stop()
+
+	p := &buzzerPlay{stop: make(chan bool), done: make(chan bool)}
+	l.mutex.Lock()
+	l.playing = p
+	l.mutex.Unlock()
+	return p
+}
+
+// tone plays a tone of frequency hz for d, or until stop is closed
+func (l *BuzzerDriver) tone(hz float64, d time.Duration, stop chan bool) (err error) {
+	if hz <= Rest {
+		select {
+		case <-time.After(d):
+		case <-stop:
+		}
+		return
+	}
+
+	if pin := l.pwmPin(hz); pin != nil {
+		return l.hardwareTone(pin, hz, d, stop)
+	}
+
+	// calculation based off https://www.arduino.cc/en/Tutorial/Melody
+	half := time.Duration(float64(time.Second) / (2 * hz))
+	for end := time.Now().Add(d); time.Now().Before(end); {
+		select {
+		case <-stop:
+			return l.Off()
+		default:
+		}
 
-	for i := 0.0; i < tempo*1000; i += tone * 2.0 {
 		if err = l.On(); err != nil {
 			return
 		}
-		time.Sleep(time.Duration(tone) * time.Microsecond)
+		time.Sleep(half)
 
 		if err = l.Off(); err != nil {
 			return
 		}
-		time.Sleep(time.Duration(tone) * time.Microsecond)
+		time.Sleep(half)
 	}
 
 	return
 }
+
+// pwmPin returns the hardware PWM pin of the buzzer, set to the period of
+// frequency hz, or nil when the adaptor does not provide one
+func (l *BuzzerDriver) pwmPin(hz float64) gobot.PWMPinner {
+	provider, ok := l.connection.(gobot.PWMPinnerProvider)
+	if !ok {
+		return nil
+	}
+	pin, err := provider.PWMPin(l.Pin())
+	if err != nil {
+		return nil
+	}
+
+	// some pins, such as the ones of pi-blaster, have a fixed period
+	period := uint32(float64(time.Second) / hz)
+	if pin.SetPeriod(period) != nil {
+		return nil
+	}
+	if p, err := pin.Period(); err != nil || p != period {
+		return nil
+	}
+	return pin
+}
+
+// hardwareTone plays a tone with a PWM pin, whose period is set
+func (l *BuzzerDriver) hardwareTone(pin gobot.PWMPinner, hz float64, d time.Duration, stop chan bool) (err error) {
+	period := uint32(float64(time.Second) / hz)
+	if err = pin.SetDutyCycle(period / 2); err != nil {
+		return
+	}
+	if err = pin.Enable(true); err != nil {
+		return
+	}
+
+	select {
+	case <-time.After(d):
+	case <-stop:
+	}
+
+	return pin.SetDutyCycle(0)
+}
@@ -3,7 +3,9 @@ package gpio
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
@@ -79,3 +81,130 @@ func TestBuzzerDriverToneError(t *testing.T) {
 
 	gobottest.Assert(t, d.Tone(100, 0.01), errors.New("write error"))
 }
+
+// buzzerTestPWMPin is a hardware PWM pin recording its settings
+type buzzerTestPWMPin struct {
+	gobot.PWMPinner
+	mtx         sync.Mutex
+	period      uint32
+	fixedPeriod bool
+	duties      []uint32
+}
+
+func (p *buzzerTestPWMPin) Enable(bool) error { return nil }
+
+func (p *buzzerTestPWMPin) SetPeriod(period uint32) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if !p.fixedPeriod {
+		p.period = period
+	}
+	return nil
+}
+
+func (p *buzzerTestPWMPin) Period() (uint32, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.period, nil
+}
+
+func (p *buzzerTestPWMPin) SetDutyCycle(duty uint32) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.duties = append(p.duties, duty)
+	return nil
+}
+
+func (p *buzzerTestPWMPin) Duties() []uint32 {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.duties
+}
+
+// buzzerTestPWMAdaptor provides a hardware PWM pin
+type buzzerTestPWMAdaptor struct {
+	relayBoardTestAdaptor
+	pin *buzzerTestPWMPin
+}
+
+func (a *buzzerTestPWMAdaptor) PWMPin(string) (gobot.PWMPinner, error) {
+	return a.pin, nil
+}
+
+func TestBuzzerDriverToneHardwarePWM(t *testing.T) {
+	a := &buzzerTestPWMAdaptor{pin: &buzzerTestPWMPin{}}
+	d := initTestBuzzerDriver(a)
+	d.BPM = 6000
+	gobottest.Assert(t, d.Tone(1000, 1), nil)
+	period, _ := a.pin.Period()
+	gobottest.Assert(t, period, uint32(1000000))
+	gobottest.Assert(t, a.pin.Duties(), []uint32{500000, 0})
+	gobottest.Assert(t, len(a.Writes()), 0)
+
+	// the pin is toggled when its period can not be set
+	a.pin = &buzzerTestPWMPin{fixedPeriod: true}
+	gobottest.Assert(t, d.Tone(1000, 1), nil)
+	gobottest.Assert(t, len(a.pin.Duties()), 0)
+	gobottest.Refute(t, len(a.Writes()), 0)
+}
+
+func TestBuzzerDriverPlayRTTTL(t *testing.T) {
+	a := &relayBoardTestAdaptor{}
+	d := initTestBuzzerDriver(a)
+	gobottest.Refute(t, d.PlayRTTTL("Beep:b=120"), nil)
+
+	gobottest.Assert(t, d.PlayRTTTL("Beep:d=32,o=5,b=600:c,p,e"), nil)
+	gobottest.Assert(t, d.Playing(), true)
+	waitForBuzzer(t, d)
+	writes := a.Writes()
+	gobottest.Refute(t, len(writes), 0)
+	gobottest.Assert(t, writes[len(writes)-1], "1=0")
+}
+
+func TestBuzzerDriverStop(t *testing.T) {
+	a := &relayBoardTestAdaptor{}
+	d := initTestBuzzerDriver(a)
+	d.PlayNotes([]Note{{Frequency: A4, Duration: time.Hour}})
+	gobottest.Assert(t, d.Playing(), true)
+
+	d.Stop()
+	gobottest.Assert(t, d.Playing(), false)
+	// the buzzer is left off
+	if writes := a.Writes(); len(writes) > 0 {
+		gobottest.Assert(t, writes[len(writes)-1], "1=0")
+	}
+
+	// a new melody interrupts the one playing
+	d.PlayNotes([]Note{{Frequency: Rest, Duration: time.Hour}})
+	start := time.Now()
+	gobottest.Assert(t, d.Tone(A4, 0.01), nil)
+	gobottest.Assert(t, time.Since(start) < time.Second, true)
+	gobottest.Assert(t, d.Playing(), false)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestBuzzerDriverPlayNotesError(t *testing.T) {
+	a := &relayBoardTestAdaptor{err: errors.New("write error")}
+	d := initTestBuzzerDriver(a)
+	errs := d.SubscribeWith(gobot.SubscribeOptions{Pattern: Error})
+	d.PlayNotes([]Note{{Frequency: A4, Duration: time.Hour}})
+
+	select {
+	case evt := <-errs:
+		gobottest.Assert(t, evt.Data, errors.New("write error"))
+	case <-time.After(time.Second):
+		t.Errorf("Error was not published")
+	}
+	waitForBuzzer(t, d)
+}
+
+func waitForBuzzer(t *testing.T, d *BuzzerDriver) {
+	deadline := time.After(time.Second)
+	for d.Playing() {
+		select {
+		case <-deadline:
+			t.Fatalf("the buzzer is still playing")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
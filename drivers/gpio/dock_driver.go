@@ -0,0 +1,405 @@
+package gpio
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+var (
+	// ErrDockTimeout is returned by Dock when the robot is not docked within
+	// the Timeout of the DockDriver
+	ErrDockTimeout = errors.New("docking did not complete in time")
+	// ErrDockCanceled is returned by Dock when the DockDriver is halted or
+	// the docking canceled
+	ErrDockCanceled = errors.New("docking canceled")
+	// ErrDockBusy is returned by Dock and Undock while the robot is docking
+	ErrDockBusy = errors.New("docking already in progress")
+)
+
+// States of a DockDriver docking the robot, published with the DockState
+// event
+const (
+	// DockSearching is when the robot turns on itself to find the beacon
+	DockSearching = "searching"
+	// DockAligning is when the robot turns towards the beacon seen on a side
+	DockAligning = "aligning"
+	// DockApproaching is when the robot moves towards the beacon ahead
+	DockApproaching = "approaching"
+	// DockConfirming is when the robot stopped on the charging contacts,
+	// until the charge is detected for ConfirmTime
+	DockConfirming = "confirming"
+	// DockDocked is when the robot is docked
+	DockDocked = "docked"
+	// DockIdle is when the robot is not docking
+	DockIdle = "idle"
+)
+
+// DockDrive moves a robot while docking, at linear and angular speeds
+// between -1 and 1 relative to its full speed, counterclockwise being
+// positive.
+type DockDrive interface {
+	Drive(linear, angular float64) error
+}
+
+// WheelMotors is the DockDrive of a robot driven by the MotorDrivers of its
+// left and right wheels.
+type WheelMotors struct {
+	Left  *MotorDriver
+	Right *MotorDriver
+}
+
+// Drive drives the wheels at the speeds moving the robot at linear and
+// angular speeds, scaled down together when a wheel would exceed its full
+// speed.
+func (w WheelMotors) Drive(linear, angular float64) error {
+	left, right := linear-angular, linear+angular
+	if m := math.Max(math.Abs(left), math.Abs(right)); m > 1 {
+		left, right = left/m, right/m
+	}
+	if err := driveWheel(w.Left, left); err != nil {
+		return err
+	}
+	return driveWheel(w.Right, right)
+}
+
+func driveWheel(m *MotorDriver, speed float64) error {
+	value := byte(math.Round(math.Abs(speed) * 255))
+	if speed < 0 {
+		return m.Backward(value)
+	}
+	return m.Forward(value)
+}
+
+// DockDriver is a virtual device docking a mobile robot to its charging
+// dock, made of the IRBeaconDriver finding the beacon of the dock and of a
+// charge-detect input, high while the charging contacts are powered. It
+// publishes the Docked and Undocked events as the robot is put on and taken
+// off its dock, by hand or by Dock and Undock.
+//
+// Dock drives the robot to the dock: it turns on itself until the beacon is
+// seen, turns towards it until it is ahead, approaches it while keeping it
+// ahead, and stops once the charge is detected, confirming the contacts are
+// steady for ConfirmTime.
+//
+// The events of the IRBeaconDriver are published by the DockDriver prefixed
+// by its name, like the ones of the components of a gobot.CompositeDevice.
+type DockDriver struct {
+	// SearchSpeed is the angular speed searching the beacon, 0.3 by default
+	SearchSpeed float64
+	// AlignSpeed is the angular speed turning towards the beacon, 0.15 by
+	// default
+	AlignSpeed float64
+	// ApproachSpeed is the linear speed approaching the dock, 0.2 by default
+	ApproachSpeed float64
+	// ConfirmTime is how long the charge must be detected without
+	// interruption to confirm the docking, 1s by default
+	ConfirmTime time.Duration
+	// Timeout bounds the docking, 60s by default
+	Timeout time.Duration
+	// UndockTime is how long Undock backs away from the dock, 1s by default
+	UndockTime time.Duration
+	// ChargeActiveLow is set for a charge-detect input pulled low while
+	// charging
+	ChargeActiveLow bool
+
+	*gobot.CompositeDevice
+	beacon     *IRBeaconDriver
+	chargePin  string
+	connection DigitalReader
+	drive      DockDrive
+	interval   time.Duration
+	halt       chan bool
+	cancel     chan bool
+	docked     bool
+	state      string
+	mutex      *sync.Mutex
+}
+
+// NewDockDriver returns a new DockDriver given the IRBeaconDriver finding
+// the dock, the DigitalReader and pin of the charge-detect input, and the
+// DockDrive moving the robot. The charge-detect input is polled and the
+// robot steered every 10 Milliseconds.
+//
+// Optionally accepts:
+//  time.Duration: Interval at which the DockDriver is polled for new information
+func NewDockDriver(beacon *IRBeaconDriver, a DigitalReader, chargePin string, drive DockDrive, v ...time.Duration) *DockDriver {
+	d := &DockDriver{
+		SearchSpeed:     0.3,
+		AlignSpeed:      0.15,
+		ApproachSpeed:   0.2,
+		ConfirmTime:     time.Second,
+		Timeout:         time.Minute,
+		UndockTime:      time.Second,
+		CompositeDevice: gobot.NewCompositeDevice(gobot.DefaultName("Dock"), beacon),
+		beacon:          beacon,
+		chargePin:       chargePin,
+		connection:      a,
+		drive:           drive,
+		interval:        10 * time.Millisecond,
+		state:           DockIdle,
+		mutex:           &sync.Mutex{},
+	}
+
+	if len(v) > 0 {
+		d.interval = v[0]
+	}
+
+	d.AddEvent(Docked)
+	d.AddEvent(Undocked)
+	d.AddEvent(DockState)
+	d.AddEvent(Error)
+
+	d.AddCommand("Dock", func(params map[string]interface{}) interface{} {
+		return d.Dock()
+	})
+	d.AddCommand("Undock", func(params map[string]interface{}) interface{} {
+		return d.Undock()
+	})
+	d.AddCommand("Cancel", func(params map[string]interface{}) interface{} {
+		d.Cancel()
+		return nil
+	})
+	d.AddCommand("Docked", func(params map[string]interface{}) interface{} {
+		return d.Docked()
+	})
+
+	return d
+}
+
+// Start starts the IRBeaconDriver, and polls the charge-detect input at the
+// given interval.
+//
+// Emits the Events:
+// 	Docked - When the charge is detected
+// 	Undocked - When the charge is no longer detected
+// 	DockState string - When the state of the docking changes
+// 	Error error - On pin read error
+func (d *DockDriver) Start() (err error) {
+	if err = d.CompositeDevice.Start(); err != nil {
+		return
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.halt != nil {
+		return
+	}
+	d.halt = make(chan bool)
+	first := true
+	gobot.NewPoller(gobot.PollerConfig{Interval: d.interval}).Go(d, d.halt, func() bool {
+		charging, err := d.charging()
+		if err != nil {
+			d.Publish(Error, err)
+			return false
+		}
+		d.mutex.Lock()
+		changed := charging != d.docked || (first && charging)
+		d.docked, first = charging, false
+		d.mutex.Unlock()
+		if !changed {
+			return false
+		}
+		if charging {
+			d.Publish(Docked, nil)
+		} else {
+			d.Publish(Undocked, nil)
+		}
+		return true
+	})
+	return
+}
+
+// Halt cancels the docking, stops polling the charge-detect input, and halts
+// the IRBeaconDriver
+func (d *DockDriver) Halt() (err error) {
+	d.Cancel()
+	d.mutex.Lock()
+	if d.halt != nil {
+		close(d.halt)
+		d.halt = nil
+	}
+	d.mutex.Unlock()
+	return d.CompositeDevice.Halt()
+}
+
+// Beacon returns the IRBeaconDriver finding the dock
+func (d *DockDriver) Beacon() *IRBeaconDriver { return d.beacon }
+
+// Docked returns whether the charge was detected at the last poll
+func (d *DockDriver) Docked() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.docked
+}
+
+// State returns the state of the docking in progress, DockDocked once the
+// last one succeeded, and DockIdle otherwise
+func (d *DockDriver) State() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.state
+}
+
+// Dock drives the robot to the dock until the charge is confirmed, and
+// stops it. It returns ErrDockTimeout when the robot is not docked within
+// Timeout, and ErrDockCanceled when canceled. It does not need the
+// DockDriver to be started.
+func (d *DockDriver) Dock() (err error) {
+	cancel, err := d.begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := d.drive.Drive(0, 0); serr != nil && err == nil {
+			err = serr
+		}
+		d.end(err == nil)
+	}()
+
+	clock := gobot.DefaultClock()
+	deadline := clock.Now().Add(d.Timeout)
+	var chargedSince time.Time
+	for {
+		charging, err := d.charging()
+		if err != nil {
+			return err
+		}
+		now := clock.Now()
+		if charging {
+			if chargedSince.IsZero() {
+				chargedSince = now
+				d.setState(DockConfirming)
+				if err = d.drive.Drive(0, 0); err != nil {
+					return err
+				}
+			}
+			if now.Sub(chargedSince) >= d.ConfirmTime {
+				return nil
+			}
+		} else {
+			chargedSince = time.Time{}
+			if err = d.steer(); err != nil {
+				return err
+			}
+		}
+
+		if !now.Before(deadline) {
+			return ErrDockTimeout
+		}
+		select {
+		case <-clock.After(d.interval):
+		case <-cancel:
+			return ErrDockCanceled
+		}
+	}
+}
+
+// Undock backs the robot away from the dock for UndockTime, and stops it.
+func (d *DockDriver) Undock() (err error) {
+	cancel, err := d.begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := d.drive.Drive(0, 0); serr != nil && err == nil {
+			err = serr
+		}
+		d.end(false)
+	}()
+
+	if err = d.drive.Drive(-d.ApproachSpeed, 0); err != nil {
+		return err
+	}
+	select {
+	case <-gobot.DefaultClock().After(d.UndockTime):
+		return nil
+	case <-cancel:
+		return ErrDockCanceled
+	}
+}
+
+// Cancel stops the docking or undocking in progress, if any
+func (d *DockDriver) Cancel() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.cancel != nil {
+		close(d.cancel)
+		d.cancel = nil
+	}
+}
+
+// steer moves the robot depending on the bearing of the beacon
+func (d *DockDriver) steer() error {
+	bearing, err := d.beacon.Read()
+	if err != nil {
+		return err
+	}
+	approaching := d.State() == DockApproaching
+	switch {
+	case bearing == BeaconAhead:
+		d.setState(DockApproaching)
+		return d.drive.Drive(d.ApproachSpeed, 0)
+	case bearing == BeaconNone:
+		d.setState(DockSearching)
+		return d.drive.Drive(0, d.SearchSpeed)
+	}
+
+	turn := d.AlignSpeed
+	if bearing == BeaconRight {
+		turn = -turn
+	}
+	// keep approaching while correcting a drift, rather than stopping
+	if approaching {
+		return d.drive.Drive(d.ApproachSpeed, turn)
+	}
+	d.setState(DockAligning)
+	return d.drive.Drive(0, turn)
+}
+
+// begin returns the channel canceling a docking or undocking, or ErrDockBusy
+// when one is in progress
+func (d *DockDriver) begin() (chan bool, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.cancel != nil {
+		return nil, ErrDockBusy
+	}
+	d.cancel = make(chan bool)
+	return d.cancel, nil
+}
+
+// end ends the docking or undocking, docked or not
+func (d *DockDriver) end(docked bool) {
+	d.mutex.Lock()
+	d.cancel = nil
+	d.mutex.Unlock()
+	if docked {
+		d.setState(DockDocked)
+	} else {
+		d.setState(DockIdle)
+	}
+}
+
+// setState sets the state of the docking, published when it changes
+func (d *DockDriver) setState(state string) {
+	d.mutex.Lock()
+	changed := state != d.state
+	d.state = state
+	d.mutex.Unlock()
+	if changed {
+		d.Publish(DockState, state)
+	}
+}
+
+// charging returns whether the charge is detected
+func (d *DockDriver) charging() (bool, error) {
+	val, err := d.connection.DigitalRead(d.chargePin)
+	if err != nil {
+		return false, err
+	}
+	return (val == 1) != d.ChargeActiveLow, nil
+}
@@ -0,0 +1,250 @@
+package gpio_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/gpio/gpiotest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*gpio.DockDriver)(nil)
+
+// dockWorld simulates a robot in front of its dock: each Drive turns it by
+// 10 degrees and moves it by 10 centimeters at full speed. The dock is at
+// heading 0, the beacon being seen by both receivers within 5 degrees of it.
+type dockWorld struct {
+	a        *gpiotest.Adaptor
+	heading  float64
+	distance float64
+	drives   [][2]float64
+	err      error
+	mutex    sync.Mutex
+}
+
+func newDockWorld(heading, distance float64) *dockWorld {
+	w := &dockWorld{a: gpiotest.NewAdaptor(), heading: heading, distance: distance}
+	w.update()
+	return w
+}
+
+func (w *dockWorld) Drive(linear, angular float64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.drives = append(w.drives, [2]float64{linear, angular})
+	w.heading += angular * 10
+	if math.Abs(w.heading) <= 5 {
+		w.distance = math.Max(0, w.distance-linear*10)
+	}
+	w.update()
+	return nil
+}
+
+// update sets the receivers, low when receiving, and the charge-detect input
+func (w *dockWorld) update() {
+	left, right, charge := 1, 1, 0
+	switch {
+	case math.Abs(w.heading) <= 5:
+		left, right = 0, 0
+		if w.distance <= 0 {
+			charge = 1
+		}
+	case w.heading < -5 && w.heading > -30:
+		left = 0
+	case w.heading > 5 && w.heading < 30:
+		right = 0
+	}
+	w.a.SetInput("left", left)
+	w.a.SetInput("right", right)
+	w.a.SetInput("charge", charge)
+}
+
+func (w *dockWorld) lastDrive() [2]float64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.drives[len(w.drives)-1]
+}
+
+func initTestDockDriver(w *dockWorld) *gpio.DockDriver {
+	beacon := gpio.NewIRBeaconDriver(w.a, "left", "right")
+	return gpio.NewDockDriver(beacon, w.a, "charge", w)
+}
+
+// runWithClock runs f, advancing clock by step whenever f waits on it
+func runWithClock(clock *gobot.FakeClock, step time.Duration, f func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- f() }()
+	for {
+		select {
+		case err := <-done:
+			return err
+		default:
+		}
+		if clock.Waiters() > 0 {
+			clock.Advance(step)
+		} else {
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+}
+
+func TestDockDriver(t *testing.T) {
+	d := initTestDockDriver(newDockWorld(0, 10))
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "Dock"), true)
+	gobottest.Refute(t, d.Beacon(), nil)
+	gobottest.Assert(t, d.Components()[0], gobot.Device(d.Beacon()))
+	gobottest.Assert(t, d.State(), gpio.DockIdle)
+	gobottest.Assert(t, d.Docked(), false)
+	gobottest.Refute(t, d.Command("Dock"), nil)
+	gobottest.Refute(t, d.Command("Undock"), nil)
+	gobottest.Refute(t, d.Command("Cancel"), nil)
+	gobottest.Refute(t, d.Command("Docked"), nil)
+}
+
+func TestDockDriverDock(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	w := newDockWorld(-90, 30)
+	d := initTestDockDriver(w)
+	states := make(chan string, 10)
+	d.On(gpio.DockState, func(data interface{}) { states <- data.(string) })
+
+	gobottest.Assert(t, runWithClock(clock, 10*time.Millisecond, d.Dock), nil)
+	gobottest.Assert(t, d.State(), gpio.DockDocked)
+	gobottest.Assert(t, w.lastDrive(), [2]float64{0, 0})
+	gobottest.Assert(t, w.distance, 0.0)
+	// confirmed after a second on the contacts
+	gobottest.Assert(t, clock.Now().After(time.Unix(1, 0)), true)
+
+	for _, state := range []string{gpio.DockSearching, gpio.DockAligning, gpio.DockApproaching,
+		gpio.DockConfirming, gpio.DockDocked} {
+		select {
+		case s := <-states:
+			gobottest.Assert(t, s, state)
+		case <-time.After(time.Second):
+			t.Fatalf("state %v not published", state)
+		}
+	}
+}
+
+func TestDockDriverDockDrift(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	// the robot starts aligned, then drifts to the left of the dock
+	w := newDockWorld(0, 30)
+	d := initTestDockDriver(w)
+	d.ConfirmTime = 0
+	w.mutex.Lock()
+	w.heading = 8
+	w.update()
+	w.mutex.Unlock()
+
+	gobottest.Assert(t, runWithClock(clock, 10*time.Millisecond, d.Dock), nil)
+	gobottest.Assert(t, w.drives[0], [2]float64{0, -0.15})
+}
+
+func TestDockDriverDockTimeout(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	// a beacon out of order is never seen
+	w := newDockWorld(-90, 30)
+	d := initTestDockDriver(w)
+	d.Beacon().ActiveHigh = true
+	d.Timeout = time.Second
+
+	gobottest.Assert(t, runWithClock(clock, 100*time.Millisecond, d.Dock), gpio.ErrDockTimeout)
+	gobottest.Assert(t, d.State(), gpio.DockIdle)
+	gobottest.Assert(t, w.lastDrive(), [2]float64{0, 0})
+}
+
+func TestDockDriverDockCanceled(t *testing.T) {
+	w := newDockWorld(-90, 30)
+	d := initTestDockDriver(w)
+	done := make(chan error)
+	go func() { done <- d.Dock() }()
+
+	time.Sleep(20 * time.Millisecond)
+	gobottest.Assert(t, d.Dock(), gpio.ErrDockBusy)
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, <-done, gpio.ErrDockCanceled)
+	gobottest.Assert(t, w.lastDrive(), [2]float64{0, 0})
+}
+
+func TestDockDriverDockError(t *testing.T) {
+	w := newDockWorld(-90, 30)
+	d := initTestDockDriver(w)
+	w.a.FailRead("charge", errors.New("read error"))
+	gobottest.Assert(t, d.Dock(), errors.New("read error"))
+
+	w.a.FailRead("charge", nil)
+	w.err = errors.New("drive error")
+	gobottest.Assert(t, d.Dock(), w.err)
+}
+
+func TestDockDriverUndock(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	w := newDockWorld(0, 0)
+	d := initTestDockDriver(w)
+	gobottest.Assert(t, runWithClock(clock, time.Second, d.Undock), nil)
+	gobottest.Assert(t, w.drives, [][2]float64{{-0.2, 0}, {0, 0}})
+	gobottest.Assert(t, d.State(), gpio.DockIdle)
+}
+
+func TestDockDriverEvents(t *testing.T) {
+	w := newDockWorld(0, 0)
+	d := gpio.NewDockDriver(gpio.NewIRBeaconDriver(w.a, "left", "right"), w.a, "charge", w, time.Millisecond)
+	events := make(chan string, 10)
+	d.On(gpio.Docked, func(interface{}) { events <- gpio.Docked })
+	d.On(gpio.Undocked, func(interface{}) { events <- gpio.Undocked })
+
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+	for _, event := range []string{gpio.Docked, gpio.Undocked} {
+		select {
+		case e := <-events:
+			gobottest.Assert(t, e, event)
+		case <-time.After(time.Second):
+			t.Fatalf("event %v not published", event)
+		}
+		// taken off the dock
+		w.a.SetInput("charge", 0)
+	}
+	gobottest.Assert(t, d.Docked(), false)
+	gobottest.Assert(t, d.Command("Docked")(nil), false)
+}
+
+func TestWheelMotors(t *testing.T) {
+	a := gpiotest.NewAdaptor()
+	left := gpio.NewMotorDriver(a, "1")
+	right := gpio.NewMotorDriver(a, "2")
+	left.ForwardPin, left.BackwardPin = "3", "4"
+	right.ForwardPin, right.BackwardPin = "5", "6"
+	m := gpio.WheelMotors{Left: left, Right: right}
+
+	// turning counterclockwise while moving forward, scaled down
+	gobottest.Assert(t, m.Drive(1, 1), nil)
+	gobottest.Assert(t, left.CurrentSpeed, byte(0))
+	gobottest.Assert(t, right.CurrentSpeed, byte(255))
+
+	gobottest.Assert(t, m.Drive(-0.2, 0), nil)
+	gobottest.Assert(t, left.CurrentSpeed, byte(51))
+	gobottest.Assert(t, left.CurrentDirection, "backward")
+}
@@ -0,0 +1,109 @@
+package gpio
+
+import (
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// EmergencyStopDriver represents a hardware emergency stop button, which
+// calls a stop function, usually the EmergencyStop method of the Robot, when
+// it is pressed.
+type EmergencyStopDriver struct {
+	Active bool
+	// ActiveState is the level of the pin when the button is pressed, 1 by
+	// default: a normally closed button pulling the pin low, with a pull-up,
+	// also stops the Robot when its wire is cut.
+	ActiveState int
+	pin         string
+	name        string
+	halt        chan bool
+	interval    time.Duration
+	connection  DigitalReader
+	stop        func() error
+	gobot.Eventer
+}
+
+// NewEmergencyStopDriver returns a new EmergencyStopDriver with a polling
+// interval of 10 Milliseconds given a DigitalReader, pin and the function to
+// call when the button is pressed, e.g. robot.EmergencyStop.
+//
+// Optionally accepts:
+//  time.Duration: Interval at which the EmergencyStopDriver is polled for new information
+func NewEmergencyStopDriver(a DigitalReader, pin string, stop func() error, v ...time.Duration) *EmergencyStopDriver {
+	e := &EmergencyStopDriver{
+		name:        gobot.DefaultName("EmergencyStop"),
+		connection:  a,
+		pin:         pin,
+		ActiveState: 1,
+		stop:        stop,
+		Eventer:     gobot.NewEventer(),
+		interval:    10 * time.Millisecond,
+		halt:        make(chan bool),
+	}
+
+	if len(v) > 0 {
+		e.interval = v[0]
+	}
+
+	e.AddEvent(ButtonPush)
+	e.AddEvent(ButtonRelease)
+	e.AddEvent(Error)
+
+	return e
+}
+
+// Start starts the EmergencyStopDriver and polls the state of the button at
+// the given interval. The stop function is called once each time the button
+// is pressed, or the pin can not be read, since the button may then be
+// unreachable.
+//
+// Emits the Events:
+// 	Push error - On button push, with the error returned by the stop function
+//	Release int - On button release
+//	Error error - On pin read error
+func (e *EmergencyStopDriver) Start() (err error) {
+	e.Active = false
+	gobot.NewPoller(gobot.PollerConfig{Interval: e.interval}).Go(e, e.halt, func() bool {
+		newValue, err := e.connection.DigitalRead(e.Pin())
+		if err != nil {
+			e.Publish(Error, err)
+			newValue = e.ActiveState
+		}
+		active := newValue == e.ActiveState
+		if active == e.Active {
+			return false
+		}
+		e.Active = active
+		if !active {
+			e.Publish(ButtonRelease, newValue)
+			return true
+		}
+		// the stop function may halt this driver
+		go func() {
+			e.Publish(ButtonPush, e.stop())
+		}()
+		return true
+	})
+	return
+}
+
+// Halt stops polling the button for new information
+func (e *EmergencyStopDriver) Halt() (err error) {
+	e.halt <- true
+	return
+}
+
+// Name returns the EmergencyStopDrivers name
+func (e *EmergencyStopDriver) Name() string { return e.name }
+
+// SetName sets the EmergencyStopDrivers name
+func (e *EmergencyStopDriver) SetName(n string) { e.name = n }
+
+// Pin returns the EmergencyStopDrivers pin
+func (e *EmergencyStopDriver) Pin() string { return e.pin }
+
+// Connection returns the EmergencyStopDrivers Connection
+func (e *EmergencyStopDriver) Connection() gobot.Connection {
+	return e.connection.(gobot.Connection)
+}
@@ -0,0 +1,76 @@
+package gpio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*EmergencyStopDriver)(nil)
+
+func TestEmergencyStopDriver(t *testing.T) {
+	d := NewEmergencyStopDriver(newGpioTestAdaptor(), "1", func() error { return nil })
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "EmergencyStop"), true)
+	gobottest.Assert(t, d.Pin(), "1")
+	gobottest.Assert(t, d.ActiveState, 1)
+	d.SetName("estop")
+	gobottest.Assert(t, d.Name(), "estop")
+
+	d = NewEmergencyStopDriver(newGpioTestAdaptor(), "1", nil, 30*time.Second)
+	gobottest.Assert(t, d.interval, 30*time.Second)
+}
+
+func TestEmergencyStopDriverStart(t *testing.T) {
+	a := newGpioTestAdaptor()
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, nil })
+	stops := make(chan bool, 2)
+	d := NewEmergencyStopDriver(a, "1", func() error {
+		stops <- true
+		return errors.New("stop error")
+	})
+	pushes := d.Subscribe()
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 1, nil })
+	evt := <-pushes
+	gobottest.Assert(t, evt.Name, ButtonPush)
+	gobottest.Assert(t, evt.Data, errors.New("stop error"))
+	<-stops
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, nil })
+	gobottest.Assert(t, (<-pushes).Name, ButtonRelease)
+
+	// a read error stops as well
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, errors.New("read error") })
+	gobottest.Assert(t, (<-pushes).Name, Error)
+	gobottest.Assert(t, (<-pushes).Name, ButtonPush)
+	<-stops
+	gobottest.Assert(t, len(stops), 0)
+}
+
+func TestEmergencyStopDriverRobot(t *testing.T) {
+	a := newGpioTestAdaptor()
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, nil })
+	relay := NewRelayDriver(a, "2")
+	robot := gobot.NewRobot("estop", []gobot.Connection{a}, []gobot.Device{relay})
+	estop := NewEmergencyStopDriver(a, "1", robot.EmergencyStop)
+	robot.AddDevice(estop)
+	gobottest.Assert(t, robot.Start(false), nil)
+	relay.On()
+	events := robot.Subscribe()
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 1, nil })
+	select {
+	case evt := <-events:
+		gobottest.Assert(t, evt.Name, gobot.EmergencyStopped)
+	case <-time.After(time.Second):
+		t.Fatal("Emergency stop was not triggered")
+	}
+	gobottest.Assert(t, relay.State(), false)
+}
@@ -0,0 +1,273 @@
+package gpio
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// quadratureSteps gives the step of a quadrature encoder from its previous and
+// current states, each being the level of channel A shifted left once, ored
+// with the level of channel B. Channel A leads when turning forward.
+var quadratureSteps = [16]int64{0, -1, 1, 0, 1, 0, 0, -1, -1, 0, 0, 1, 0, 1, -1, 0}
+
+// EncoderSpeed is the data published with the Speed event.
+type EncoderSpeed struct {
+	// RPM is the number of revolutions per minute of the wheel, negative
+	// when a quadrature encoder turns backward
+	RPM float64
+	// Velocity is the linear velocity of the wheel in meters per second,
+	// known when the WheelDiameter is set
+	Velocity float64
+}
+
+// EncoderDriver represents a single-channel or quadrature wheel encoder.
+// The ticks are counted from the changes of the pins, reported by the
+// connection when it is a DigitalWatcher, polled otherwise.
+type EncoderDriver struct {
+	// Window is the time over which the speed is measured, 100 Milliseconds
+	// by default. A Speed event is published at the end of each window.
+	Window time.Duration
+	// WheelDiameter, in meters, gives the velocity and distance of the wheel
+	WheelDiameter float64
+	name          string
+	pinA          string
+	pinB          string
+	ppr           int
+	interval      time.Duration
+	connection    DigitalReader
+	state         int
+	count         int64
+	windowCount   int64
+	speed         EncoderSpeed
+	halt          chan bool
+	mutex         *sync.Mutex
+	gobot.Eventer
+}
+
+// NewEncoderDriver returns a new EncoderDriver given a DigitalReader, the pins
+// of channels A and B, and the number of pulses per revolution of the wheel of
+// each channel. pinB is empty for a single-channel encoder, which counts the
+// rising edges of channel A and can not tell the direction.
+//
+// Optionally accepts:
+//  time.Duration: Interval at which the pins are polled, when the connection is not a DigitalWatcher, 1 Millisecond by default
+func NewEncoderDriver(a DigitalReader, pinA string, pinB string, ppr int, v ...time.Duration) *EncoderDriver {
+	e := &EncoderDriver{
+		name:       gobot.DefaultName("Encoder"),
+		connection: a,
+		pinA:       pinA,
+		pinB:       pinB,
+		ppr:        ppr,
+		Window:     100 * time.Millisecond,
+		interval:   time.Millisecond,
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		e.interval = v[0]
+	}
+
+	e.AddEvent(Speed)
+	e.AddEvent(Error)
+
+	return e
+}
+
+// Name returns the EncoderDrivers name
+func (e *EncoderDriver) Name() string { return e.name }
+
+// SetName sets the EncoderDrivers name
+func (e *EncoderDriver) SetName(n string) { e.name = n }
+
+// Pin returns the EncoderDrivers pin of channel A
+func (e *EncoderDriver) Pin() string { return e.pinA }
+
+// Pins returns the EncoderDrivers pins of channels A and B
+func (e *EncoderDriver) Pins() (string, string) { return e.pinA, e.pinB }
+
+// Connection returns the EncoderDrivers Connection
+func (e *EncoderDriver) Connection() gobot.Connection {
+	return e.connection.(gobot.Connection)
+}
+
+// Quadrature returns true if the encoder has two channels
+func (e *EncoderDriver) Quadrature() bool { return e.pinB != "" }
+
+// Start reads the initial state of the pins, then counts the ticks and
+// measures the speed of the wheel.
+//
+// Emits the Events:
+// 	Speed EncoderSpeed - At the end of each Window
+//	Error error - On pin read error
+func (e *EncoderDriver) Start() (err error) {
+	a, b, err := e.read()
+	if err != nil {
+		return
+	}
+	e.mutex.Lock()
+	e.state = a<<1 | b
+	e.windowCount = e.count
+	e.halt = make(chan bool)
+	halt := e.halt
+	e.mutex.Unlock()
+
+	if w, ok := e.connection.(DigitalWatcher); ok {
+		if err = e.watch(w); err != nil {
+			return
+		}
+	} else {
+		gobot.NewPoller(gobot.PollerConfig{Interval: e.interval}).Go(e, halt, func() bool {
+			a, b, err := e.read()
+			if err != nil {
+				e.Publish(Error, err)
+				return false
+			}
+			e.update(a, b)
+			return false
+		})
+	}
+
+	go func() {
+		ticker := gobot.DefaultClock().NewTicker(e.Window)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				e.Publish(Speed, e.measure())
+			case <-halt:
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Halt stops counting the ticks
+func (e *EncoderDriver) Halt() (err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.halt == nil {
+		return
+	}
+	close(e.halt)
+	e.halt = nil
+	if w, ok := e.connection.(DigitalWatcher); ok {
+		err = w.UnwatchDigitalPin(e.pinA)
+		if e.Quadrature() {
+			if uerr := w.UnwatchDigitalPin(e.pinB); err == nil {
+				err = uerr
+			}
+		}
+	}
+	return
+}
+
+// Count returns the number of ticks counted, going down when a quadrature
+// encoder turns backward
+func (e *EncoderDriver) Count() int64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.count
+}
+
+// Reset sets the tick count back to zero
+func (e *EncoderDriver) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.windowCount -= e.count
+	e.count = 0
+}
+
+// Revolutions returns the number of revolutions of the wheel counted
+func (e *EncoderDriver) Revolutions() float64 {
+	return float64(e.Count()) / float64(e.ticksPerRevolution())
+}
+
+// Distance returns the distance travelled by the wheel in meters, known when
+// the WheelDiameter is set
+func (e *EncoderDriver) Distance() float64 {
+	return e.Revolutions() * math.Pi * e.WheelDiameter
+}
+
+// RPM returns the number of revolutions per minute of the wheel measured
+// over the last Window
+func (e *EncoderDriver) RPM() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.speed.RPM
+}
+
+// Velocity returns the linear velocity of the wheel in meters per second
+// measured over the last Window
+func (e *EncoderDriver) Velocity() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.speed.Velocity
+}
+
+// ticksPerRevolution counts every edge of both channels of a quadrature
+// encoder, and the rising edges of a single-channel one
+func (e *EncoderDriver) ticksPerRevolution() int {
+	if e.Quadrature() {
+		return 4 * e.ppr
+	}
+	return e.ppr
+}
+
+func (e *EncoderDriver) watch(w DigitalWatcher) (err error) {
+	if err = w.WatchDigitalPin(e.pinA, func(level int) {
+		e.mutex.Lock()
+		b := e.state & 1
+		e.mutex.Unlock()
+		e.update(level, b)
+	}); err != nil || !e.Quadrature() {
+		return
+	}
+	return w.WatchDigitalPin(e.pinB, func(level int) {
+		e.mutex.Lock()
+		a := e.state >> 1
+		e.mutex.Unlock()
+		e.update(a, level)
+	})
+}
+
+func (e *EncoderDriver) read() (a int, b int, err error) {
+	if a, err = e.connection.DigitalRead(e.pinA); err != nil || !e.Quadrature() {
+		return
+	}
+	b, err = e.connection.DigitalRead(e.pinB)
+	return
+}
+
+// update counts the ticks from the new levels of the channels
+func (e *EncoderDriver) update(a int, b int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	state := (a&1)<<1 | b&1
+	if e.Quadrature() {
+		e.count += quadratureSteps[e.state<<2|state]
+	} else if state > e.state {
+		e.count++
+	}
+	e.state = state
+}
+
+// measure computes the speed over the Window ending now
+func (e *EncoderDriver) measure() EncoderSpeed {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	ticks := e.count - e.windowCount
+	e.windowCount = e.count
+	rpm := float64(ticks) / float64(e.ticksPerRevolution()) / e.Window.Minutes()
+	e.speed = EncoderSpeed{
+		RPM:      rpm,
+		Velocity: rpm / 60 * math.Pi * e.WheelDiameter,
+	}
+	return e.speed
+}
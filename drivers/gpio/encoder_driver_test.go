@@ -0,0 +1,214 @@
+package gpio
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*EncoderDriver)(nil)
+
+// encoderTestAdaptor holds the levels of its pins
+type encoderTestAdaptor struct {
+	gpioTestBareAdaptor
+	mtx     sync.Mutex
+	levels  map[string]int
+	readErr error
+}
+
+func (t *encoderTestAdaptor) DigitalRead(pin string) (val int, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.levels[pin], t.readErr
+}
+
+func (t *encoderTestAdaptor) set(levels ...int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.levels["1"] = levels[0]
+	if len(levels) > 1 {
+		t.levels["2"] = levels[1]
+	}
+}
+
+// encoderTestWatcher calls the handlers of its pins when they are set
+type encoderTestWatcher struct {
+	encoderTestAdaptor
+	handlers map[string]func(int)
+	watchErr error
+}
+
+func (t *encoderTestWatcher) WatchDigitalPin(pin string, handler func(level int)) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.handlers[pin] = handler
+	return t.watchErr
+}
+
+func (t *encoderTestWatcher) UnwatchDigitalPin(pin string) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.handlers, pin)
+	return nil
+}
+
+func (t *encoderTestWatcher) change(pin string, level int) {
+	t.mtx.Lock()
+	t.levels[pin] = level
+	handler := t.handlers[pin]
+	t.mtx.Unlock()
+	if handler != nil {
+		handler(level)
+	}
+}
+
+func newEncoderTestWatcher() *encoderTestWatcher {
+	return &encoderTestWatcher{
+		encoderTestAdaptor: encoderTestAdaptor{levels: map[string]int{}},
+		handlers:           map[string]func(int){},
+	}
+}
+
+func TestEncoderDriver(t *testing.T) {
+	a := &encoderTestAdaptor{levels: map[string]int{}}
+	d := NewEncoderDriver(a, "1", "2", 20)
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "Encoder"), true)
+	d.SetName("left")
+	gobottest.Assert(t, d.Name(), "left")
+	gobottest.Assert(t, d.Pin(), "1")
+	pinA, pinB := d.Pins()
+	gobottest.Assert(t, pinA, "1")
+	gobottest.Assert(t, pinB, "2")
+	gobottest.Assert(t, d.Quadrature(), true)
+	gobottest.Assert(t, d.Window, 100*time.Millisecond)
+	gobottest.Assert(t, d.interval, time.Millisecond)
+
+	d = NewEncoderDriver(a, "1", "", 20, 5*time.Millisecond)
+	gobottest.Assert(t, d.Quadrature(), false)
+	gobottest.Assert(t, d.interval, 5*time.Millisecond)
+}
+
+func TestEncoderDriverQuadrature(t *testing.T) {
+	d := NewEncoderDriver(&encoderTestAdaptor{}, "1", "2", 1)
+
+	// A leads B going forward
+	for _, s := range [][2]int{{1, 0}, {1, 1}, {0, 1}, {0, 0}, {1, 0}} {
+		d.update(s[0], s[1])
+	}
+	gobottest.Assert(t, d.Count(), int64(5))
+	for _, s := range [][2]int{{0, 0}, {0, 1}, {1, 1}} {
+		d.update(s[0], s[1])
+	}
+	gobottest.Assert(t, d.Count(), int64(2))
+	// no change, and a missed state, are not counted
+	d.update(1, 1)
+	d.update(0, 0)
+	gobottest.Assert(t, d.Count(), int64(2))
+	gobottest.Assert(t, d.Revolutions(), 0.5)
+
+	d.Reset()
+	gobottest.Assert(t, d.Count(), int64(0))
+}
+
+func TestEncoderDriverSingleChannel(t *testing.T) {
+	d := NewEncoderDriver(&encoderTestAdaptor{}, "1", "", 4)
+	d.WheelDiameter = 0.1
+	for _, level := range []int{1, 0, 1, 1, 0, 1} {
+		d.update(level, 0)
+	}
+	gobottest.Assert(t, d.Count(), int64(3))
+	gobottest.Assert(t, d.Revolutions(), 0.75)
+	gobottest.Assert(t, math.Abs(d.Distance()-0.075*math.Pi) < 1e-9, true)
+}
+
+func TestEncoderDriverPolling(t *testing.T) {
+	a := &encoderTestAdaptor{levels: map[string]int{}}
+	d := NewEncoderDriver(a, "1", "2", 1)
+	d.Window = time.Hour
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	a.set(1, 0)
+	deadline := time.Now().Add(time.Second)
+	for d.Count() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	gobottest.Assert(t, d.Count(), int64(1))
+
+	errs := d.Subscribe()
+	a.mtx.Lock()
+	a.readErr = errors.New("read error")
+	a.mtx.Unlock()
+	evt := <-errs
+	gobottest.Assert(t, evt.Name, Error)
+	gobottest.Assert(t, evt.Data, errors.New("read error"))
+}
+
+func TestEncoderDriverStartError(t *testing.T) {
+	a := &encoderTestAdaptor{levels: map[string]int{}, readErr: errors.New("read error")}
+	d := NewEncoderDriver(a, "1", "2", 1)
+	gobottest.Assert(t, d.Start(), errors.New("read error"))
+	gobottest.Assert(t, d.Halt(), nil)
+
+	w := newEncoderTestWatcher()
+	w.watchErr = errors.New("watch error")
+	d = NewEncoderDriver(w, "1", "2", 1)
+	gobottest.Assert(t, d.Start(), errors.New("watch error"))
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestEncoderDriverSpeed(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(1488469265, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	w := newEncoderTestWatcher()
+	d := NewEncoderDriver(w, "1", "2", 5)
+	d.WheelDiameter = 0.2
+	speeds := d.Subscribe()
+	gobottest.Assert(t, d.Start(), nil)
+
+	// one revolution forward
+	for i := 0; i < 5; i++ {
+		w.change("1", 1)
+		w.change("2", 1)
+		w.change("1", 0)
+		w.change("2", 0)
+	}
+	gobottest.Assert(t, d.Count(), int64(20))
+	clock.BlockUntil(1)
+	clock.Advance(100 * time.Millisecond)
+	evt := <-speeds
+	gobottest.Assert(t, evt.Name, Speed)
+	speed := evt.Data.(EncoderSpeed)
+	gobottest.Assert(t, math.Abs(speed.RPM-600) < 1e-9, true)
+	gobottest.Assert(t, math.Abs(speed.Velocity-2*math.Pi) < 1e-9, true)
+	gobottest.Assert(t, math.Abs(d.RPM()-600) < 1e-9, true)
+	gobottest.Assert(t, math.Abs(d.Velocity()-2*math.Pi) < 1e-9, true)
+
+	// half a revolution backward
+	w.change("2", 1)
+	w.change("1", 1)
+	w.change("2", 0)
+	w.change("1", 0)
+	w.change("2", 1)
+	w.change("1", 1)
+	w.change("2", 0)
+	w.change("1", 0)
+	w.change("2", 1)
+	w.change("1", 1)
+	clock.Advance(100 * time.Millisecond)
+	speed = (<-speeds).Data.(EncoderSpeed)
+	gobottest.Assert(t, math.Abs(speed.RPM+300) < 1e-9, true)
+
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, len(w.handlers), 0)
+	gobottest.Assert(t, d.Halt(), nil)
+}
@@ -0,0 +1,149 @@
+package gpio
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// EndstopDriver represents a limit switch at the end of the travel of an
+// axis, such as the endstops of CNC machines and 3D printers. It debounces
+// the switch and tells whether the axis reached its end, see Homing to find
+// the origin of the axis.
+//
+// The switch is expected between the pin and the ground, with a pull-up: a
+// normally-open switch pulls the pin low when triggered, a normally-closed
+// one lets it rise. The latter is safer, a cut wire reading as triggered.
+type EndstopDriver struct {
+	// NormallyClosed is set for a switch opening when triggered
+	NormallyClosed bool
+	// PullDown is set for a switch between the pin and the supply, with a
+	// pull-down, which inverts the levels
+	PullDown bool
+	// Debounce is how long the switch must keep its new state before it
+	// changes, 5ms by default
+	Debounce time.Duration
+
+	pin        string
+	name       string
+	halt       chan bool
+	interval   time.Duration
+	connection DigitalReader
+	triggered  bool
+	mutex      *sync.Mutex
+	gobot.Eventer
+}
+
+// NewEndstopDriver returns a new EndstopDriver for a normally-open switch
+// with a polling interval of 1 Millisecond given a DigitalReader and pin.
+//
+// Optionally accepts:
+//  time.Duration: Interval at which the EndstopDriver is polled for new information
+func NewEndstopDriver(a DigitalReader, pin string, v ...time.Duration) *EndstopDriver {
+	e := &EndstopDriver{
+		Debounce:   5 * time.Millisecond,
+		name:       gobot.DefaultName("Endstop"),
+		connection: a,
+		pin:        pin,
+		interval:   time.Millisecond,
+		halt:       make(chan bool),
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		e.interval = v[0]
+	}
+
+	e.AddEvent(EndstopTriggered)
+	e.AddEvent(EndstopReleased)
+	e.AddEvent(Error)
+
+	return e
+}
+
+// Start starts the EndstopDriver and polls the state of the switch at the
+// given interval.
+//
+// Emits the Events:
+// 	EndstopTriggered - When the switch is triggered
+// 	EndstopReleased - When the switch is released
+// 	Error error - On pin read error
+func (e *EndstopDriver) Start() (err error) {
+	e.mutex.Lock()
+	e.triggered = false
+	e.mutex.Unlock()
+
+	clock := gobot.DefaultClock()
+	candidate, since := false, clock.Now()
+	gobot.NewPoller(gobot.PollerConfig{Interval: e.interval}).Go(e, e.halt, func() bool {
+		triggered, err := e.Read()
+		if err != nil {
+			e.Publish(Error, err)
+			return false
+		}
+		now := clock.Now()
+		if triggered != candidate {
+			candidate, since = triggered, now
+		}
+
+		e.mutex.Lock()
+		changed := candidate != e.triggered && now.Sub(since) >= e.Debounce
+		if changed {
+			e.triggered = candidate
+		}
+		e.mutex.Unlock()
+
+		switch {
+		case changed && candidate:
+			e.Publish(EndstopTriggered, nil)
+		case changed:
+			e.Publish(EndstopReleased, nil)
+		}
+		return changed
+	})
+	return
+}
+
+// Halt stops polling the switch for new information
+func (e *EndstopDriver) Halt() (err error) {
+	e.halt <- true
+	return
+}
+
+// Name returns the EndstopDrivers name
+func (e *EndstopDriver) Name() string { return e.name }
+
+// SetName sets the EndstopDrivers name
+func (e *EndstopDriver) SetName(n string) { e.name = n }
+
+// Pin returns the EndstopDrivers pin
+func (e *EndstopDriver) Pin() string { return e.pin }
+
+// Connection returns the EndstopDrivers Connection
+func (e *EndstopDriver) Connection() gobot.Connection { return e.connection.(gobot.Connection) }
+
+// Triggered returns the debounced state of the switch, false until started
+func (e *EndstopDriver) Triggered() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.triggered
+}
+
+// Read reads whether the switch is triggered now, without debouncing it
+func (e *EndstopDriver) Read() (triggered bool, err error) {
+	val, err := e.connection.DigitalRead(e.Pin())
+	if err != nil {
+		return false, err
+	}
+	// the levels are the ones of a normally-closed switch with a pull-up
+	triggered = val == 1
+	if !e.NormallyClosed {
+		triggered = !triggered
+	}
+	if e.PullDown {
+		triggered = !triggered
+	}
+	return triggered, nil
+}
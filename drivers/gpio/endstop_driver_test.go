@@ -0,0 +1,110 @@
+package gpio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*EndstopDriver)(nil)
+
+func TestEndstopDriver(t *testing.T) {
+	d := NewEndstopDriver(newGpioTestAdaptor(), "1")
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "Endstop"), true)
+	gobottest.Assert(t, d.Pin(), "1")
+	gobottest.Assert(t, d.Debounce, 5*time.Millisecond)
+	gobottest.Assert(t, d.interval, time.Millisecond)
+	d.SetName("x-min")
+	gobottest.Assert(t, d.Name(), "x-min")
+
+	d = NewEndstopDriver(newGpioTestAdaptor(), "1", 30*time.Second)
+	gobottest.Assert(t, d.interval, 30*time.Second)
+}
+
+func TestEndstopDriverRead(t *testing.T) {
+	a := newGpioTestAdaptor()
+	d := NewEndstopDriver(a, "1")
+	level := 0
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return level, nil })
+
+	for _, c := range []struct {
+		normallyClosed, pullDown bool
+		level                    int
+		triggered                bool
+	}{
+		{false, false, 0, true},
+		{false, false, 1, false},
+		{true, false, 0, false},
+		{true, false, 1, true},
+		{false, true, 1, true},
+		{true, true, 0, true},
+	} {
+		d.NormallyClosed, d.PullDown, level = c.normallyClosed, c.pullDown, c.level
+		triggered, err := d.Read()
+		gobottest.Assert(t, err, nil)
+		gobottest.Assert(t, triggered, c.triggered)
+	}
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, errors.New("read error") })
+	_, err := d.Read()
+	gobottest.Assert(t, err, errors.New("read error"))
+}
+
+func TestEndstopDriverStart(t *testing.T) {
+	a := newGpioTestAdaptor()
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 1, nil })
+	d := NewEndstopDriver(a, "1")
+	d.Debounce = 0
+	events := d.Subscribe()
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, nil })
+	gobottest.Assert(t, (<-events).Name, EndstopTriggered)
+	gobottest.Assert(t, d.Triggered(), true)
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 1, nil })
+	gobottest.Assert(t, (<-events).Name, EndstopReleased)
+	gobottest.Assert(t, d.Triggered(), false)
+
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, errors.New("read error") })
+	evt := <-events
+	gobottest.Assert(t, evt.Name, Error)
+	gobottest.Assert(t, evt.Data, errors.New("read error"))
+}
+
+func TestEndstopDriverDebounce(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := newGpioTestAdaptor()
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 1, nil })
+	d := NewEndstopDriver(a, "1")
+	events := d.Subscribe()
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	// a bounce shorter than the debounce is ignored
+	clock.BlockUntil(1)
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, nil })
+	clock.Advance(time.Millisecond)
+	clock.BlockUntil(1)
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 1, nil })
+	clock.Advance(time.Millisecond)
+
+	clock.BlockUntil(1)
+	a.TestAdaptorDigitalRead(func() (val int, err error) { return 0, nil })
+	for i := 0; i < 5; i++ {
+		clock.Advance(time.Millisecond)
+		clock.BlockUntil(1)
+		gobottest.Assert(t, d.Triggered(), false)
+	}
+	clock.Advance(time.Millisecond)
+	gobottest.Assert(t, (<-events).Name, EndstopTriggered)
+}
@@ -40,6 +40,26 @@ const (
 	MotionDetected = "motion-detected"
 	// MotionStopped event
 	MotionStopped = "motion-stopped"
+	// MotionStart event
+	MotionStart = "motion-start"
+	// MotionEnd event
+	MotionEnd = "motion-end"
+	// Speed event
+	Speed = "speed"
+	// Rate event
+	Rate = "rate"
+	// EndstopTriggered event
+	EndstopTriggered = "triggered"
+	// EndstopReleased event
+	EndstopReleased = "released"
+	// Beacon event
+	Beacon = "beacon"
+	// Docked event
+	Docked = "docked"
+	// Undocked event
+	Undocked = "undocked"
+	// DockState event
+	DockState = "dock-state"
 )
 
 // PwmWriter interface represents an Adaptor which has Pwm capabilities
@@ -57,7 +77,40 @@ type DigitalWriter interface {
 	DigitalWrite(string, byte) (err error)
 }
 
+// DigitalPinsWriter interface represents an Adaptor which is able to write
+// several digital pins at once, e.g. a whole port with a single register
+// access or message, rather than with a call per pin
+type DigitalPinsWriter interface {
+	DigitalWritePins(levels map[string]byte) (err error)
+}
+
+// WritePins writes levels[i] to pins[i], all at once when the adaptor is a
+// DigitalPinsWriter, pin by pin in order otherwise
+func WritePins(a DigitalWriter, pins []string, levels []byte) (err error) {
+	if w, ok := a.(DigitalPinsWriter); ok {
+		m := make(map[string]byte, len(pins))
+		for i, pin := range pins {
+			m[pin] = levels[i]
+		}
+		return w.DigitalWritePins(m)
+	}
+	for i, pin := range pins {
+		if err = a.DigitalWrite(pin, levels[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+
 // DigitalReader interface represents an Adaptor which has DigitalRead capabilities
 type DigitalReader interface {
 	DigitalRead(string) (val int, err error)
 }
+
+// DigitalWatcher interface represents an Adaptor which is able to call a
+// handler on each change of the level of a digital pin, e.g. from an
+// interrupt, rather than having it polled
+type DigitalWatcher interface {
+	WatchDigitalPin(pin string, handler func(level int)) (err error)
+	UnwatchDigitalPin(pin string) (err error)
+}
@@ -0,0 +1,188 @@
+package gpiotest
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+var (
+	_ gpio.DigitalReader = (*Adaptor)(nil)
+	_ gpio.DigitalWriter = (*Adaptor)(nil)
+	_ gpio.PwmWriter     = (*Adaptor)(nil)
+	_ gpio.ServoWriter   = (*Adaptor)(nil)
+	_ aio.AnalogReader   = (*Adaptor)(nil)
+)
+
+// Adaptor is a virtual GPIO adaptor recording writes as Waveforms and
+// answering reads with the inputs set by the test.
+type Adaptor struct {
+	name string
+	// ServoMinPulse and ServoMaxPulse are the pulse widths of a servo at 0
+	// and 180 degrees, 1ms and 2ms by default.
+	ServoMinPulse time.Duration
+	ServoMaxPulse time.Duration
+
+	started   time.Time
+	waveforms map[string]Waveform
+	digital   map[string]int
+	analog    map[string]int
+	readErrs  map[string]error
+	timers    []*time.Timer
+	mutex     sync.Mutex
+}
+
+// NewAdaptor returns a new virtual Adaptor. Sample times count from its
+// creation until Connect is called.
+func NewAdaptor() *Adaptor {
+	return &Adaptor{
+		name:          gobot.DefaultName("GPIOTest"),
+		ServoMinPulse: time.Millisecond,
+		ServoMaxPulse: 2 * time.Millisecond,
+		started:       time.Now(),
+		waveforms:     make(map[string]Waveform),
+		digital:       make(map[string]int),
+		analog:        make(map[string]int),
+		readErrs:      make(map[string]error),
+	}
+}
+
+// Name returns the Adaptor name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptor name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Connect restarts the time of the Samples
+func (a *Adaptor) Connect() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.started = time.Now()
+	return nil
+}
+
+// Finalize cancels the scheduled inputs
+func (a *Adaptor) Finalize() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, t := range a.timers {
+		t.Stop()
+	}
+	a.timers = nil
+	return nil
+}
+
+// Waveform returns the writes made to pin
+func (a *Adaptor) Waveform(pin string) Waveform {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append(Waveform{}, a.waveforms[pin]...)
+}
+
+// Clear forgets the recorded writes
+func (a *Adaptor) Clear() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.waveforms = make(map[string]Waveform)
+}
+
+// SetInput sets the value read from the digital pin
+func (a *Adaptor) SetInput(pin string, val int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.digital[pin] = val
+}
+
+// SetAnalogInput sets the value read from the analog pin
+func (a *Adaptor) SetAnalogInput(pin string, val int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.analog[pin] = val
+}
+
+// Schedule sets the value read from the digital pin after delay
+func (a *Adaptor) Schedule(pin string, delay time.Duration, val int) {
+	a.schedule(delay, func() { a.SetInput(pin, val) })
+}
+
+// ScheduleAnalog sets the value read from the analog pin after delay
+func (a *Adaptor) ScheduleAnalog(pin string, delay time.Duration, val int) {
+	a.schedule(delay, func() { a.SetAnalogInput(pin, val) })
+}
+
+// Pulse sets the digital pin to 1 after delay, and back to 0 width later,
+// like a button being pressed.
+func (a *Adaptor) Pulse(pin string, delay time.Duration, width time.Duration) {
+	a.Schedule(pin, delay, 1)
+	a.Schedule(pin, delay+width, 0)
+}
+
+func (a *Adaptor) schedule(delay time.Duration, f func()) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.timers = append(a.timers, time.AfterFunc(delay, f))
+}
+
+// FailRead makes reads of pin return err, until called again with a nil err.
+func (a *Adaptor) FailRead(pin string, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if err == nil {
+		delete(a.readErrs, pin)
+		return
+	}
+	a.readErrs[pin] = err
+}
+
+// DigitalRead returns the input value of the pin
+func (a *Adaptor) DigitalRead(pin string) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if err := a.readErrs[pin]; err != nil {
+		return 0, err
+	}
+	return a.digital[pin], nil
+}
+
+// AnalogRead returns the analog input value of the pin
+func (a *Adaptor) AnalogRead(pin string) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if err := a.readErrs[pin]; err != nil {
+		return 0, err
+	}
+	return a.analog[pin], nil
+}
+
+// DigitalWrite records the level written to the pin
+func (a *Adaptor) DigitalWrite(pin string, level byte) error {
+	a.record(pin, Sample{Op: DigitalWrite, Value: int(level)})
+	return nil
+}
+
+// PwmWrite records the duty value written to the pin
+func (a *Adaptor) PwmWrite(pin string, level byte) error {
+	a.record(pin, Sample{Op: PwmWrite, Value: int(level), Duty: float64(level) / 255})
+	return nil
+}
+
+// ServoWrite records the angle written to the pin, and the matching pulse
+// width.
+func (a *Adaptor) ServoWrite(pin string, angle byte) error {
+	a.mutex.Lock()
+	span := a.ServoMaxPulse - a.ServoMinPulse
+	width := a.ServoMinPulse + span*time.Duration(angle)/180
+	a.mutex.Unlock()
+	a.record(pin, Sample{Op: ServoWrite, Value: int(angle), PulseWidth: width})
+	return nil
+}
+
+func (a *Adaptor) record(pin string, s Sample) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	s.Time = time.Since(a.started)
+	a.waveforms[pin] = append(a.waveforms[pin], s)
+}
@@ -0,0 +1,136 @@
+package gpiotest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+func TestAdaptor(t *testing.T) {
+	a := NewAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "GPIOTest"), true)
+	a.SetName("virtual")
+	gobottest.Assert(t, a.Name(), "virtual")
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+
+func TestAdaptorWrites(t *testing.T) {
+	a := NewAdaptor()
+	a.Connect()
+	a.DigitalWrite("13", 1)
+	a.PwmWrite("3", 51)
+	a.ServoWrite("4", 90)
+
+	gobottest.Assert(t, a.Waveform("13").Values(), []int{1})
+	gobottest.Assert(t, a.Waveform("13").Last().Op, DigitalWrite)
+
+	pwm := a.Waveform("3").Last()
+	gobottest.Assert(t, pwm.Op, PwmWrite)
+	gobottest.Assert(t, pwm.Duty, 0.2)
+
+	servo := a.Waveform("4").Last()
+	gobottest.Assert(t, servo.Op, ServoWrite)
+	gobottest.Assert(t, servo.PulseWidth, 1500*time.Microsecond)
+
+	a.Clear()
+	gobottest.Assert(t, len(a.Waveform("13")), 0)
+}
+
+func TestAdaptorSampleTimes(t *testing.T) {
+	a := NewAdaptor()
+	a.Connect()
+	a.DigitalWrite("13", 1)
+	time.Sleep(10 * time.Millisecond)
+	a.DigitalWrite("13", 0)
+
+	pulses := a.Waveform("13").Pulses()
+	gobottest.Assert(t, len(pulses), 1)
+	gobottest.Assert(t, pulses[0] >= 10*time.Millisecond, true)
+}
+
+func TestAdaptorInputs(t *testing.T) {
+	a := NewAdaptor()
+	val, err := a.DigitalRead("5")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 0)
+
+	a.SetInput("5", 1)
+	val, _ = a.DigitalRead("5")
+	gobottest.Assert(t, val, 1)
+
+	a.SetAnalogInput("A0", 512)
+	val, _ = a.AnalogRead("A0")
+	gobottest.Assert(t, val, 512)
+
+	readErr := errors.New("read error")
+	a.FailRead("5", readErr)
+	_, err = a.DigitalRead("5")
+	gobottest.Assert(t, err, readErr)
+	a.FailRead("5", nil)
+	_, err = a.DigitalRead("5")
+	gobottest.Assert(t, err, nil)
+}
+
+func TestAdaptorSchedule(t *testing.T) {
+	a := NewAdaptor()
+	a.Schedule("5", 5*time.Millisecond, 1)
+	a.ScheduleAnalog("A0", 5*time.Millisecond, 300)
+	a.Schedule("6", time.Hour, 1)
+
+	time.Sleep(20 * time.Millisecond)
+	val, _ := a.DigitalRead("5")
+	gobottest.Assert(t, val, 1)
+	val, _ = a.AnalogRead("A0")
+	gobottest.Assert(t, val, 300)
+
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, len(a.timers), 0)
+}
+
+func TestAdaptorServoDriver(t *testing.T) {
+	a := NewAdaptor()
+	servo := gpio.NewServoDriver(a, "3")
+	gobottest.Assert(t, servo.Move(45), nil)
+	gobottest.Assert(t, servo.Max(), nil)
+
+	for _, s := range a.Waveform("3") {
+		gobottest.Assert(t, s.PulseWidth >= time.Millisecond && s.PulseWidth <= 2*time.Millisecond, true)
+	}
+	gobottest.Assert(t, a.Waveform("3").Last().PulseWidth, 2*time.Millisecond)
+}
+
+func TestAdaptorButtonDriver(t *testing.T) {
+	a := NewAdaptor()
+	button := gpio.NewButtonDriver(a, "5", 2*time.Millisecond)
+	pushed := make(chan bool, 1)
+	button.Once(gpio.ButtonPush, func(data interface{}) {
+		pushed <- true
+	})
+	gobottest.Assert(t, button.Start(), nil)
+	defer button.Halt()
+
+	a.Pulse("5", 10*time.Millisecond, 20*time.Millisecond)
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Errorf("Button push was not detected")
+	}
+}
+
+func TestAdaptorAnalogSensorDriver(t *testing.T) {
+	a := NewAdaptor()
+	a.SetAnalogInput("A0", 99)
+	sensor := aio.NewAnalogSensorDriver(a, "A0")
+	val, err := sensor.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 99)
+}
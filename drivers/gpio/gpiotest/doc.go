@@ -0,0 +1,19 @@
+/*
+Package gpiotest provides a virtual GPIO adaptor to test gpio drivers
+without hardware.
+
+The Adaptor records every digital, PWM and servo write as a timestamped
+Waveform, and plays input transitions on digital and analog pins on
+schedule:
+
+	a := gpiotest.NewAdaptor()
+	servo := gpio.NewServoDriver(a, "3")
+	servo.Move(90)
+
+	width := a.Waveform("3").Last().PulseWidth
+	gobottest.Assert(t, width >= time.Millisecond && width <= 2*time.Millisecond, true)
+
+	button := gpio.NewButtonDriver(a, "5")
+	a.Schedule("5", 20*time.Millisecond, 1)
+*/
+package gpiotest // import "gobot.io/x/gobot/drivers/gpio/gpiotest"
@@ -0,0 +1,86 @@
+package gpiotest
+
+import "time"
+
+// Operations stored in a Sample
+const (
+	DigitalWrite = "DigitalWrite"
+	PwmWrite     = "PwmWrite"
+	ServoWrite   = "ServoWrite"
+)
+
+// Sample is a single write to a pin.
+type Sample struct {
+	// Time since the Adaptor was connected
+	Time time.Duration
+	// Op is one of the operation constants
+	Op string
+	// Value written: a level, a PWM duty value or a servo angle
+	Value int
+	// Duty is the PWM duty cycle, between 0 and 1, of PwmWrite samples
+	Duty float64
+	// PulseWidth is the pulse width of ServoWrite samples
+	PulseWidth time.Duration
+}
+
+// Waveform is the sequence of writes made to a pin.
+type Waveform []Sample
+
+// Last returns the last Sample, or an empty Sample
+func (w Waveform) Last() Sample {
+	if len(w) == 0 {
+		return Sample{}
+	}
+	return w[len(w)-1]
+}
+
+// Values returns the values of the Samples
+func (w Waveform) Values() []int {
+	values := []int{}
+	for _, s := range w {
+		values = append(values, s.Value)
+	}
+	return values
+}
+
+// At returns the value of the pin at t, and false if nothing was written
+// yet.
+func (w Waveform) At(t time.Duration) (int, bool) {
+	value, ok := 0, false
+	for _, s := range w {
+		if s.Time > t {
+			break
+		}
+		value, ok = s.Value, true
+	}
+	return value, ok
+}
+
+// Transitions returns the Samples changing the value of the pin
+func (w Waveform) Transitions() Waveform {
+	transitions := Waveform{}
+	for i, s := range w {
+		if i == 0 || s.Value != w[i-1].Value {
+			transitions = append(transitions, s)
+		}
+	}
+	return transitions
+}
+
+// Pulses returns the durations for which the pin stayed at a non zero value
+// before going back to zero. A pulse still in progress is not included.
+func (w Waveform) Pulses() []time.Duration {
+	pulses := []time.Duration{}
+	var start time.Duration
+	high := false
+	for _, s := range w.Transitions() {
+		switch {
+		case s.Value != 0 && !high:
+			start, high = s.Time, true
+		case s.Value == 0 && high:
+			pulses = append(pulses, s.Time-start)
+			high = false
+		}
+	}
+	return pulses
+}
@@ -0,0 +1,49 @@
+package gpiotest
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func testWaveform() Waveform {
+	ms := time.Millisecond
+	return Waveform{
+		{Time: 0, Value: 0},
+		{Time: 10 * ms, Value: 1},
+		{Time: 12 * ms, Value: 1},
+		{Time: 15 * ms, Value: 0},
+		{Time: 20 * ms, Value: 1},
+		{Time: 22 * ms, Value: 0},
+		{Time: 30 * ms, Value: 1},
+	}
+}
+
+func TestWaveformValues(t *testing.T) {
+	w := testWaveform()
+	gobottest.Assert(t, w.Values(), []int{0, 1, 1, 0, 1, 0, 1})
+	gobottest.Assert(t, w.Last().Time, 30*time.Millisecond)
+	gobottest.Assert(t, Waveform{}.Last(), Sample{})
+}
+
+func TestWaveformAt(t *testing.T) {
+	w := testWaveform()
+	val, ok := w.At(13 * time.Millisecond)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, val, 1)
+	val, _ = w.At(16 * time.Millisecond)
+	gobottest.Assert(t, val, 0)
+
+	_, ok = Waveform{{Time: time.Second}}.At(0)
+	gobottest.Assert(t, ok, false)
+}
+
+func TestWaveformTransitions(t *testing.T) {
+	w := testWaveform().Transitions()
+	gobottest.Assert(t, w.Values(), []int{0, 1, 0, 1, 0, 1})
+}
+
+func TestWaveformPulses(t *testing.T) {
+	gobottest.Assert(t, testWaveform().Pulses(), []time.Duration{5 * time.Millisecond, 2 * time.Millisecond})
+}
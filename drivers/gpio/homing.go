@@ -0,0 +1,156 @@
+package gpio
+
+import (
+	"errors"
+)
+
+var (
+	// ErrEndstopNotFound is returned by Homing when the endstop is not
+	// triggered within the travel of the axis
+	ErrEndstopNotFound = errors.New("endstop not triggered within MaxSteps")
+	// ErrEndstopStuck is returned by Homing when the endstop stays triggered
+	// while moving away from it
+	ErrEndstopStuck = errors.New("endstop still triggered after backing off")
+)
+
+// HomingStepper is the motor of an axis found by Homing, such as a
+// StepperDriver
+type HomingStepper interface {
+	Move(stepsToMove int) error
+	SetSpeed(rpm uint) error
+}
+
+// Homing finds the origin of an axis driven by a stepper, the position at
+// which its endstop triggers, the way CNC machines and 3D printers do: it
+// moves fast until the endstop triggers, backs off, and approaches it again
+// slowly so that the origin does not depend on the speed.
+type Homing struct {
+	// Direction of the endstop, -1 for backward moves, the default, or 1
+	Direction int
+	// MaxSteps bounds the moves searching the endstop, the travel of the axis
+	// at least
+	MaxSteps int
+	// BackOff is the number of steps moved away from the endstop once
+	// released, before approaching it again, 10 by default
+	BackOff int
+	// FastSpeed is the speed searching the endstop, in RPM, the maximum speed
+	// of the stepper by default
+	FastSpeed uint
+	// SlowSpeed is the speed approaching the endstop again, in RPM, a tenth of
+	// FastSpeed by default
+	SlowSpeed uint
+
+	stepper HomingStepper
+	endstop *EndstopDriver
+}
+
+// NewHoming returns a new Homing moving stepper until endstop triggers, for
+// an axis of up to maxSteps steps.
+func NewHoming(stepper HomingStepper, endstop *EndstopDriver, maxSteps int) *Homing {
+	h := &Homing{
+		Direction: -1,
+		MaxSteps:  maxSteps,
+		BackOff:   10,
+		stepper:   stepper,
+		endstop:   endstop,
+	}
+	if s, ok := stepper.(*StepperDriver); ok {
+		h.FastSpeed = s.GetMaxSpeed()
+	}
+	return h
+}
+
+// Home moves the axis to its origin, where the endstop triggers when
+// approached slowly, and leaves the stepper at FastSpeed. The endstop is read
+// between the steps, its driver does not need to be started.
+func (h *Homing) Home() (err error) {
+	fast, slow := h.FastSpeed, h.SlowSpeed
+	if slow == 0 {
+		slow = fast / 10
+	}
+	if slow == 0 {
+		slow = 1
+	}
+	direction := 1
+	if h.Direction < 0 {
+		direction = -1
+	}
+
+	if fast > 0 {
+		if err = h.stepper.SetSpeed(fast); err != nil {
+			return
+		}
+	}
+	// an axis starting on the endstop moves away first
+	triggered, err := h.endstop.Read()
+	if err != nil {
+		return
+	}
+	if triggered {
+		if err = h.clear(-direction); err != nil {
+			return
+		}
+	}
+	if err = h.approach(direction); err != nil {
+		return
+	}
+	if err = h.clear(-direction); err != nil {
+		return
+	}
+
+	if err = h.stepper.SetSpeed(slow); err != nil {
+		return
+	}
+	err = h.approach(direction)
+	if fast > 0 {
+		if e := h.stepper.SetSpeed(fast); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// approach moves towards the endstop until it is triggered
+func (h *Homing) approach(direction int) error {
+	found, err := h.moveUntil(direction, true, h.MaxSteps)
+	if err == nil && !found {
+		err = ErrEndstopNotFound
+	}
+	return err
+}
+
+// clear moves away from the endstop until it is released, then BackOff more
+// steps
+func (h *Homing) clear(direction int) error {
+	released, err := h.moveUntil(direction, false, h.MaxSteps)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return ErrEndstopStuck
+	}
+	if h.BackOff > 0 {
+		return h.stepper.Move(direction * h.BackOff)
+	}
+	return nil
+}
+
+// moveUntil moves one step at a time in direction until the endstop is
+// triggered, or released, for at most max steps, and returns whether it did
+func (h *Homing) moveUntil(direction int, triggered bool, max int) (bool, error) {
+	for steps := 0; ; steps++ {
+		t, err := h.endstop.Read()
+		if err != nil {
+			return false, err
+		}
+		if t == triggered {
+			return true, nil
+		}
+		if steps >= max {
+			return false, nil
+		}
+		if err = h.stepper.Move(direction); err != nil {
+			return false, err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gpio
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// homingTestAxis is an axis whose normally-open endstop triggers at the
+// position 0 and below
+type homingTestAxis struct {
+	position int
+	speeds   []uint
+	moves    int
+	mutex    sync.Mutex
+}
+
+func (a *homingTestAxis) Move(steps int) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.position += steps
+	a.moves++
+	return nil
+}
+
+func (a *homingTestAxis) SetSpeed(rpm uint) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.speeds = append(a.speeds, rpm)
+	return nil
+}
+
+func (a *homingTestAxis) endstop() *EndstopDriver {
+	adaptor := newGpioTestAdaptor()
+	adaptor.TestAdaptorDigitalRead(func() (val int, err error) {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		if a.position <= 0 {
+			return 0, nil
+		}
+		return 1, nil
+	})
+	return NewEndstopDriver(adaptor, "1")
+}
+
+func TestHomingHome(t *testing.T) {
+	axis := &homingTestAxis{position: 100}
+	h := NewHoming(axis, axis.endstop(), 1000)
+	h.FastSpeed = 50
+	gobottest.Assert(t, h.Home(), nil)
+
+	gobottest.Assert(t, axis.position, 0)
+	gobottest.Assert(t, axis.speeds, []uint{50, 5, 50})
+	// 100 steps to the endstop, 1 step to release it and 10 back, and 11
+	// steps back to it
+	gobottest.Assert(t, axis.moves, 100+1+1+11)
+}
+
+func TestHomingHomeFromEndstop(t *testing.T) {
+	axis := &homingTestAxis{position: -5}
+	h := NewHoming(axis, axis.endstop(), 1000)
+	h.BackOff = 3
+	gobottest.Assert(t, h.Home(), nil)
+	gobottest.Assert(t, axis.position, 0)
+	gobottest.Assert(t, axis.speeds, []uint{1})
+}
+
+func TestHomingHomeForward(t *testing.T) {
+	axis := &homingTestAxis{position: -100}
+	endstop := axis.endstop()
+	// the endstop at the other end triggers above the position 0
+	endstop.PullDown = true
+	h := NewHoming(axis, endstop, 1000)
+	h.Direction = 1
+	h.FastSpeed, h.SlowSpeed = 30, 10
+	gobottest.Assert(t, h.Home(), nil)
+	gobottest.Assert(t, axis.position, 1)
+	gobottest.Assert(t, axis.speeds, []uint{30, 10, 30})
+}
+
+func TestHomingHomeErrors(t *testing.T) {
+	axis := &homingTestAxis{position: 100}
+	h := NewHoming(axis, axis.endstop(), 50)
+	gobottest.Assert(t, h.Home(), ErrEndstopNotFound)
+	gobottest.Assert(t, axis.moves, 50)
+
+	axis = &homingTestAxis{position: -100}
+	h = NewHoming(axis, axis.endstop(), 50)
+	gobottest.Assert(t, h.Home(), ErrEndstopStuck)
+
+	adaptor := newGpioTestAdaptor()
+	adaptor.TestAdaptorDigitalRead(func() (val int, err error) { return 0, errors.New("read error") })
+	h = NewHoming(axis, NewEndstopDriver(adaptor, "1"), 50)
+	gobottest.Assert(t, h.Home(), errors.New("read error"))
+}
+
+func TestHomingStepperDriver(t *testing.T) {
+	stepper := NewStepperDriver(newGpioTestAdaptor(), [4]string{"7", "11", "13", "15"}, StepperModes.DualPhaseStepping, 32)
+	h := NewHoming(stepper, NewEndstopDriver(newGpioTestAdaptor(), "1"), 50)
+	gobottest.Assert(t, h.FastSpeed, stepper.GetMaxSpeed())
+}
@@ -0,0 +1,165 @@
+package gpio
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// BeaconBearing is where an IRBeaconDriver sees the beacon
+type BeaconBearing string
+
+// Bearings of the beacon
+const (
+	// BeaconNone is when neither receiver sees the beacon
+	BeaconNone BeaconBearing = "none"
+	// BeaconLeft is when only the left receiver sees the beacon
+	BeaconLeft BeaconBearing = "left"
+	// BeaconRight is when only the right receiver sees the beacon
+	BeaconRight BeaconBearing = "right"
+	// BeaconAhead is when both receivers see the beacon
+	BeaconAhead BeaconBearing = "ahead"
+)
+
+// IRBeaconDriver represents a pair of infrared receivers, such as TSOP38238
+// modules, finding the infrared beacon of a charging dock. The receivers
+// point slightly to the left and to the right of the robot, so that both see
+// the beacon when it is straight ahead.
+//
+// The receivers pull their output low while they receive the modulated light
+// of the beacon.
+type IRBeaconDriver struct {
+	// ActiveHigh is set for receivers driving their output high while
+	// receiving the beacon
+	ActiveHigh bool
+
+	leftPin    string
+	rightPin   string
+	name       string
+	halt       chan bool
+	interval   time.Duration
+	connection DigitalReader
+	bearing    BeaconBearing
+	mutex      *sync.Mutex
+	gobot.Eventer
+}
+
+// NewIRBeaconDriver returns a new IRBeaconDriver with a polling interval of
+// 10 Milliseconds given a DigitalReader and the pins of the left and right
+// receivers.
+//
+// Optionally accepts:
+//  time.Duration: Interval at which the IRBeaconDriver is polled for new information
+func NewIRBeaconDriver(a DigitalReader, leftPin string, rightPin string, v ...time.Duration) *IRBeaconDriver {
+	b := &IRBeaconDriver{
+		name:       gobot.DefaultName("IRBeacon"),
+		connection: a,
+		leftPin:    leftPin,
+		rightPin:   rightPin,
+		interval:   10 * time.Millisecond,
+		bearing:    BeaconNone,
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		b.interval = v[0]
+	}
+
+	b.AddEvent(Beacon)
+	b.AddEvent(Error)
+
+	return b
+}
+
+// Start starts the IRBeaconDriver and polls the receivers at the given
+// interval.
+//
+// Emits the Events:
+// 	Beacon BeaconBearing - When the bearing of the beacon changes
+// 	Error error - On pin read error
+func (b *IRBeaconDriver) Start() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.halt != nil {
+		return
+	}
+	b.halt = make(chan bool)
+	gobot.NewPoller(gobot.PollerConfig{Interval: b.interval}).Go(b, b.halt, func() bool {
+		bearing, err := b.Read()
+		if err != nil {
+			b.Publish(Error, err)
+			return false
+		}
+		b.mutex.Lock()
+		changed := bearing != b.bearing
+		b.bearing = bearing
+		b.mutex.Unlock()
+		if changed {
+			b.Publish(Beacon, bearing)
+		}
+		return changed
+	})
+	return
+}
+
+// Halt stops polling the receivers
+func (b *IRBeaconDriver) Halt() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.halt != nil {
+		close(b.halt)
+		b.halt = nil
+	}
+	return
+}
+
+// Name returns the IRBeaconDrivers name
+func (b *IRBeaconDriver) Name() string { return b.name }
+
+// SetName sets the IRBeaconDrivers name
+func (b *IRBeaconDriver) SetName(n string) { b.name = n }
+
+// Connection returns the IRBeaconDrivers Connection
+func (b *IRBeaconDriver) Connection() gobot.Connection {
+	return b.connection.(gobot.Connection)
+}
+
+// Bearing returns the bearing of the beacon at the last poll
+func (b *IRBeaconDriver) Bearing() BeaconBearing {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.bearing
+}
+
+// Read reads the receivers and returns the bearing of the beacon, whether
+// the driver is started or not.
+func (b *IRBeaconDriver) Read() (BeaconBearing, error) {
+	left, err := b.receives(b.leftPin)
+	if err != nil {
+		return BeaconNone, err
+	}
+	right, err := b.receives(b.rightPin)
+	if err != nil {
+		return BeaconNone, err
+	}
+	switch {
+	case left && right:
+		return BeaconAhead, nil
+	case left:
+		return BeaconLeft, nil
+	case right:
+		return BeaconRight, nil
+	}
+	return BeaconNone, nil
+}
+
+// receives returns whether the receiver on pin receives the beacon
+func (b *IRBeaconDriver) receives(pin string) (bool, error) {
+	val, err := b.connection.DigitalRead(pin)
+	if err != nil {
+		return false, err
+	}
+	return (val == 1) == b.ActiveHigh, nil
+}
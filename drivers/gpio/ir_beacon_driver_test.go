@@ -0,0 +1,79 @@
+package gpio_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/gpio/gpiotest"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/gobottest/leaktest"
+)
+
+var _ gobot.Driver = (*gpio.IRBeaconDriver)(nil)
+
+func TestIRBeaconDriver(t *testing.T) {
+	a := gpiotest.NewAdaptor()
+	b := gpio.NewIRBeaconDriver(a, "1", "2")
+	gobottest.Assert(t, strings.HasPrefix(b.Name(), "IRBeacon"), true)
+	gobottest.Assert(t, b.Connection(), gobot.Connection(a))
+	gobottest.Assert(t, b.Bearing(), gpio.BeaconNone)
+
+	b.SetName("beacon")
+	gobottest.Assert(t, b.Name(), "beacon")
+}
+
+func TestIRBeaconDriverRead(t *testing.T) {
+	a := gpiotest.NewAdaptor()
+	b := gpio.NewIRBeaconDriver(a, "1", "2")
+
+	for _, tc := range []struct {
+		left, right int
+		bearing     gpio.BeaconBearing
+	}{
+		{1, 1, gpio.BeaconNone},
+		{0, 1, gpio.BeaconLeft},
+		{1, 0, gpio.BeaconRight},
+		{0, 0, gpio.BeaconAhead},
+	} {
+		a.SetInput("1", tc.left)
+		a.SetInput("2", tc.right)
+		bearing, err := b.Read()
+		gobottest.Assert(t, err, nil)
+		gobottest.Assert(t, bearing, tc.bearing)
+	}
+
+	b.ActiveHigh = true
+	bearing, _ := b.Read()
+	gobottest.Assert(t, bearing, gpio.BeaconNone)
+
+	a.FailRead("2", errors.New("read error"))
+	_, err := b.Read()
+	gobottest.Assert(t, err, errors.New("read error"))
+}
+
+func TestIRBeaconDriverStart(t *testing.T) {
+	a := gpiotest.NewAdaptor()
+	a.SetInput("1", 1)
+	a.SetInput("2", 1)
+	b := gpio.NewIRBeaconDriver(a, "1", "2", time.Millisecond)
+	bearings := make(chan gpio.BeaconBearing, 10)
+	b.On(gpio.Beacon, func(data interface{}) { bearings <- data.(gpio.BeaconBearing) })
+
+	gobottest.Assert(t, b.Start(), nil)
+	a.SetInput("1", 0)
+	select {
+	case bearing := <-bearings:
+		gobottest.Assert(t, bearing, gpio.BeaconLeft)
+	case <-time.After(time.Second):
+		t.Errorf("Beacon was not published")
+	}
+	gobottest.Assert(t, b.Bearing(), gpio.BeaconLeft)
+
+	gobottest.Assert(t, b.Halt(), nil)
+	gobottest.Assert(t, b.Halt(), nil)
+	leaktest.Check(t, b)
+}
@@ -14,6 +14,7 @@ type MakeyButtonDriver struct {
 	connection DigitalReader
 	Active     bool
 	interval   time.Duration
+	polling    gobot.PollerConfig
 	gobot.Eventer
 }
 
@@ -64,34 +65,37 @@ func (b *MakeyButtonDriver) Connection() gobot.Connection { return b.connection.
 //	Error error - On button error
 func (b *MakeyButtonDriver) Start() (err error) {
 	state := 1
-	go func() {
-		timer := time.NewTimer(b.interval)
-		timer.Stop()
-		for {
-			newValue, err := b.connection.DigitalRead(b.Pin())
-			if err != nil {
-				b.Publish(Error, err)
-			} else if newValue != state && newValue != -1 {
-				state = newValue
-				if newValue == 0 {
-					b.Active = true
-					b.Publish(ButtonPush, newValue)
-				} else {
-					b.Active = false
-					b.Publish(ButtonRelease, newValue)
-				}
-			}
-			timer.Reset(b.interval)
-			select {
-			case <-timer.C:
-			case <-b.halt:
-				return
+	config := b.polling
+	config.Interval = b.interval
+	gobot.NewPoller(config).Go(b, b.halt, func() bool {
+		newValue, err := b.connection.DigitalRead(b.Pin())
+		if err != nil {
+			b.Publish(Error, err)
+		} else if newValue != state && newValue != -1 {
+			state = newValue
+			if newValue == 0 {
+				b.Active = true
+				b.Publish(ButtonPush, newValue)
+			} else {
+				b.Active = false
+				b.Publish(ButtonRelease, newValue)
 			}
+			return true
 		}
-	}()
+		return false
+	})
 	return
 }
 
+// SetPolling configures the polling of the button. The interval given to
+// NewMakeyButtonDriver is kept when c.Interval is not set.
+func (b *MakeyButtonDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		b.interval = c.Interval
+	}
+	b.polling = c
+}
+
 // Halt stops polling the makey button for new information
 func (b *MakeyButtonDriver) Halt() (err error) {
 	b.halt <- true
@@ -115,3 +115,10 @@ func TestMakeyButtonDriverStart(t *testing.T) {
 	case <-time.After(makeyTestDelay * time.Millisecond):
 	}
 }
+
+func TestMakeyButtonDriverSetPolling(t *testing.T) {
+	d := initTestMakeyButtonDriver()
+	d.SetPolling(gobot.PollerConfig{MaxInterval: time.Second})
+	gobottest.Assert(t, d.interval, 10*time.Millisecond)
+	gobottest.Assert(t, d.polling.MaxInterval, time.Second)
+}
@@ -0,0 +1,482 @@
+package gpio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/font"
+)
+
+// The registers of the MAX7219
+const (
+	MAX7219Noop        = 0x00
+	MAX7219Digit0      = 0x01
+	MAX7219DecodeMode  = 0x09
+	MAX7219Intensity   = 0x0A
+	MAX7219ScanLimit   = 0x0B
+	MAX7219Shutdown    = 0x0C
+	MAX7219DisplayTest = 0x0F
+)
+
+// Rotation is the clockwise rotation of the 8x8 matrix of a module, for the
+// modules whose rows are wired as columns, such as those of most 4-in-1
+// displays, or which are mounted upside down.
+type Rotation int
+
+const (
+	// Rotation0 shows the digit registers as the rows, from the top, their
+	// most significant bit on the left
+	Rotation0 Rotation = iota
+	// Rotation90 turns the matrix by 90 degrees clockwise
+	Rotation90
+	// Rotation180 turns the matrix upside down
+	Rotation180
+	// Rotation270 turns the matrix by 90 degrees counterclockwise
+	Rotation270
+)
+
+// MAX7219Driver represents a chain of MAX7219 8x8 LED matrix modules, such
+// as a 4-in-1 display, shown as one framebuffer 8 pixels high and 8 pixels
+// wide per module. The module wired to the data pin is the rightmost one, as
+// on the 4-in-1 displays, so that the text reads from the last module.
+type MAX7219Driver struct {
+	name       string
+	connection DigitalWriter
+	pinClock   string
+	pinData    string
+	pinCS      string
+	count      int
+	rotations  []Rotation
+	columns    []byte
+	// Font is the font of the text, font.Proportional5x7 by default
+	Font *font.Font
+	// Spacing is the number of empty columns between two characters, 1 by
+	// default
+	Spacing int
+	scroll  chan bool
+	mutex   *sync.Mutex
+	gobot.Commander
+	gobot.Eventer
+}
+
+// NewMAX7219Driver return a new MAX7219Driver given a DigitalWriter, the
+// clock, data and chip select (LOAD) pins, and the number of chained modules.
+//
+// Adds the following API Commands:
+//	"SetIntensity" - See MAX7219Driver.SetIntensity
+//	"Text" - See MAX7219Driver.Text
+//	"ScrollText" - See MAX7219Driver.ScrollText
+//	"StopScroll" - See MAX7219Driver.StopScroll
+//	"Clear" - See MAX7219Driver.Clear
+func NewMAX7219Driver(a DigitalWriter, clockPin string, dataPin string, csPin string, count uint) *MAX7219Driver {
+	d := &MAX7219Driver{
+		name:       gobot.DefaultName("MAX7219"),
+		connection: a,
+		pinClock:   clockPin,
+		pinData:    dataPin,
+		pinCS:      csPin,
+		count:      int(count),
+		rotations:  make([]Rotation, count),
+		columns:    make([]byte, 8*count),
+		Font:       font.Proportional5x7,
+		Spacing:    1,
+		mutex:      &sync.Mutex{},
+		Commander:  gobot.NewCommander(),
+		Eventer:    gobot.NewEventer(),
+	}
+
+	d.AddEvent(Error)
+
+	d.AddCommand("SetIntensity", func(params map[string]interface{}) interface{} {
+		return d.SetIntensity(byte(params["level"].(float64)))
+	})
+
+	d.AddCommand("Text", func(params map[string]interface{}) interface{} {
+		return d.Text(fmt.Sprint(params["text"]))
+	})
+
+	d.AddCommand("ScrollText", func(params map[string]interface{}) interface{} {
+		interval, err := time.ParseDuration(params["interval"].(string))
+		if err != nil {
+			return err
+		}
+		d.ScrollText(fmt.Sprint(params["text"]), interval)
+		return nil
+	})
+
+	d.AddCommand("StopScroll", func(params map[string]interface{}) interface{} {
+		d.StopScroll()
+		return nil
+	})
+
+	d.AddCommand("Clear", func(params map[string]interface{}) interface{} {
+		d.Clear()
+		return d.Display()
+	})
+
+	return d
+}
+
+// Name returns the MAX7219Drivers name
+func (d *MAX7219Driver) Name() string { return d.name }
+
+// SetName sets the MAX7219Drivers name
+func (d *MAX7219Driver) SetName(n string) { d.name = n }
+
+// Connection returns the MAX7219Drivers Connection
+func (d *MAX7219Driver) Connection() gobot.Connection {
+	return d.connection.(gobot.Connection)
+}
+
+// Count returns the number of chained modules
+func (d *MAX7219Driver) Count() int { return d.count }
+
+// Width returns the width of the framebuffer, in pixels
+func (d *MAX7219Driver) Width() int { return len(d.columns) }
+
+// Start initializes the modules for the LED matrices, and clears them
+func (d *MAX7219Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err = d.connection.DigitalWrite(d.pinCS, 1); err != nil {
+		return
+	}
+	if err = d.connection.DigitalWrite(d.pinClock, 0); err != nil {
+		return
+	}
+	for _, r := range [][2]byte{
+		{MAX7219DisplayTest, 0},
+		{MAX7219ScanLimit, 7},
+		{MAX7219DecodeMode, 0},
+		{MAX7219Shutdown, 1},
+	} {
+		if err = d.all(r[0], r[1]); err != nil {
+			return
+		}
+	}
+	for i := range d.columns {
+		d.columns[i] = 0
+	}
+	return d.display()
+}
+
+// Halt stops scrolling, clears the modules and shuts them down
+func (d *MAX7219Driver) Halt() (err error) {
+	d.StopScroll()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i := range d.columns {
+		d.columns[i] = 0
+	}
+	if err = d.display(); err != nil {
+		return
+	}
+	return d.all(MAX7219Shutdown, 0)
+}
+
+// SetIntensity sets the brightness of the modules, from 0 to 15
+func (d *MAX7219Driver) SetIntensity(level byte) error {
+	if level > 15 {
+		level = 15
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.all(MAX7219Intensity, level)
+}
+
+// SetModuleIntensity sets the brightness of a module, from 0 to 15, the
+// modules of a chain being often unequally bright
+func (d *MAX7219Driver) SetModuleIntensity(module int, level byte) error {
+	if level > 15 {
+		level = 15
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.one(module, MAX7219Intensity, level)
+}
+
+// SetRotation sets the rotation of all the modules
+func (d *MAX7219Driver) SetRotation(rotation Rotation) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i := range d.rotations {
+		d.rotations[i] = rotation
+	}
+}
+
+// SetModuleRotation sets the rotation of a module, the module wired to the
+// data pin being 0
+func (d *MAX7219Driver) SetModuleRotation(module int, rotation Rotation) error {
+	if module < 0 || module >= d.count {
+		return fmt.Errorf("Invalid MAX7219 module %d", module)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.rotations[module] = rotation
+	return nil
+}
+
+// SetPixel sets the pixel at column x, from the left, and row y, from the
+// top, in the framebuffer. The pixels out of the framebuffer are ignored.
+func (d *MAX7219Driver) SetPixel(x int, y int, on bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.setPixel(x, y, on)
+}
+
+// Pixel returns whether the pixel at column x and row y is on
+func (d *MAX7219Driver) Pixel(x int, y int) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if x < 0 || x >= len(d.columns) || y < 0 || y > 7 {
+		return false
+	}
+	return d.columns[x]&(1<<uint(y)) != 0
+}
+
+// Clear turns off all the pixels of the framebuffer
+func (d *MAX7219Driver) Clear() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i := range d.columns {
+		d.columns[i] = 0
+	}
+}
+
+// DrawText draws text in the framebuffer from column x, which may be
+// negative, and returns its width in pixels
+func (d *MAX7219Driver) DrawText(x int, text string) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.drawText(x, text)
+}
+
+// Display writes the framebuffer to the modules
+func (d *MAX7219Driver) Display() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.display()
+}
+
+// Text stops scrolling, and shows text from the left of the display
+func (d *MAX7219Driver) Text(text string) error {
+	d.StopScroll()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i := range d.columns {
+		d.columns[i] = 0
+	}
+	d.drawText(0, text)
+	return d.display()
+}
+
+// ScrollText scrolls text from the right to the left of the display by a
+// column every interval, until StopScroll is called or the Driver halts. The
+// text enters again from the right once it left the display.
+//
+// Emits the Events:
+// 	Error error - On error writing to the modules
+func (d *MAX7219Driver) ScrollText(text string, interval time.Duration) {
+	d.StopScroll()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	halt := make(chan bool)
+	d.scroll = halt
+
+	go func() {
+		x := len(d.columns)
+		for {
+			d.mutex.Lock()
+			select {
+			case <-halt:
+				d.mutex.Unlock()
+				return
+			default:
+			}
+			for i := range d.columns {
+				d.columns[i] = 0
+			}
+			width := d.drawText(x, text)
+			err := d.display()
+			d.mutex.Unlock()
+			if err != nil {
+				d.Publish(Error, err)
+			}
+
+			x--
+			if x < -width {
+				x = len(d.columns)
+			}
+			select {
+			case <-gobot.DefaultClock().After(interval):
+			case <-halt:
+				return
+			}
+		}
+	}()
+}
+
+// StopScroll stops scrolling the text, which stays as it is
+func (d *MAX7219Driver) StopScroll() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.scroll != nil {
+		close(d.scroll)
+		d.scroll = nil
+	}
+}
+
+// All writes data to the register of all the modules
+func (d *MAX7219Driver) All(register byte, data byte) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.all(register, data)
+}
+
+// One writes data to the register of a module, the module wired to the data
+// pin being 0
+func (d *MAX7219Driver) One(module int, register byte, data byte) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.one(module, register, data)
+}
+
+func (d *MAX7219Driver) setPixel(x int, y int, on bool) {
+	if x < 0 || x >= len(d.columns) || y < 0 || y > 7 {
+		return
+	}
+	if on {
+		d.columns[x] |= 1 << uint(y)
+	} else {
+		d.columns[x] &^= 1 << uint(y)
+	}
+}
+
+// drawText draws the glyphs of text from column x, the mutex being held
+func (d *MAX7219Driver) drawText(x int, text string) int {
+	start := x
+	for i, r := range text {
+		if i > 0 {
+			x += d.Spacing
+		}
+		for _, column := range d.Font.Glyph(r) {
+			if x >= 0 && x < len(d.columns) {
+				d.columns[x] |= column
+			}
+			x++
+		}
+	}
+	return x - start
+}
+
+// display writes the framebuffer to the digit registers of the modules, the
+// mutex being held
+func (d *MAX7219Driver) display() error {
+	rows := make([][8]byte, d.count)
+	for module := range rows {
+		left := (d.count - 1 - module) * 8
+		for x := 0; x < 8; x++ {
+			for y := 0; y < 8; y++ {
+				if d.columns[left+x]&(1<<uint(y)) == 0 {
+					continue
+				}
+				rx, ry := rotate(d.rotations[module], x, y)
+				rows[module][ry] |= 0x80 >> uint(rx)
+			}
+		}
+	}
+
+	for row := 0; row < 8; row++ {
+		data := make([]byte, d.count)
+		for module := range data {
+			data[module] = rows[module][row]
+		}
+		if err := d.write(MAX7219Digit0+byte(row), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotate returns the position in the matrix of a module of the pixel x, y
+func rotate(rotation Rotation, x int, y int) (int, int) {
+	switch rotation {
+	case Rotation90:
+		return 7 - y, x
+	case Rotation180:
+		return 7 - x, 7 - y
+	case Rotation270:
+		return y, 7 - x
+	}
+	return x, y
+}
+
+func (d *MAX7219Driver) all(register byte, data byte) error {
+	values := make([]byte, d.count)
+	for i := range values {
+		values[i] = data
+	}
+	return d.write(register, values)
+}
+
+func (d *MAX7219Driver) one(module int, register byte, data byte) error {
+	if module < 0 || module >= d.count {
+		return fmt.Errorf("Invalid MAX7219 module %d", module)
+	}
+	registers := make([]byte, d.count)
+	values := make([]byte, d.count)
+	for i := range registers {
+		registers[i] = MAX7219Noop
+	}
+	registers[module] = register
+	values[module] = data
+	return d.send(registers, values)
+}
+
+// write writes the data of each module to the same register
+func (d *MAX7219Driver) write(register byte, data []byte) error {
+	registers := make([]byte, d.count)
+	for i := range registers {
+		registers[i] = register
+	}
+	return d.send(registers, data)
+}
+
+// send shifts the register and data of each module out, the data of the last
+// module of the chain first, then latches them
+func (d *MAX7219Driver) send(registers []byte, data []byte) (err error) {
+	if err = d.connection.DigitalWrite(d.pinCS, 0); err != nil {
+		return
+	}
+	for module := d.count - 1; module >= 0; module-- {
+		if err = d.shiftOut(registers[module]); err != nil {
+			return
+		}
+		if err = d.shiftOut(data[module]); err != nil {
+			return
+		}
+	}
+	return d.connection.DigitalWrite(d.pinCS, 1)
+}
+
+// shiftOut writes the bits of b to the data pin, most significant first
+func (d *MAX7219Driver) shiftOut(b byte) (err error) {
+	for i := 7; i >= 0; i-- {
+		if err = d.connection.DigitalWrite(d.pinData, (b>>uint(i))&1); err != nil {
+			return
+		}
+		if err = d.connection.DigitalWrite(d.pinClock, 1); err != nil {
+			return
+		}
+		if err = d.connection.DigitalWrite(d.pinClock, 0); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,236 @@
+package gpio
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*MAX7219Driver)(nil)
+
+// max7219TestAdaptor decodes the frames shifted out to a chain of MAX7219,
+// keeping the registers of each module
+type max7219TestAdaptor struct {
+	gpioTestBareAdaptor
+	mtx       sync.Mutex
+	count     int
+	data      byte
+	bits      []byte
+	registers []map[byte]byte
+	writeErr  error
+}
+
+func (t *max7219TestAdaptor) DigitalWrite(pin string, level byte) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.writeErr != nil {
+		return t.writeErr
+	}
+	switch pin {
+	case "data":
+		t.data = level
+	case "clock":
+		if level == 1 {
+			t.bits = append(t.bits, t.data)
+		}
+	case "cs":
+		if level == 0 {
+			t.bits = nil
+			return nil
+		}
+		// the first frame shifted out ends in the last module
+		for i := 0; i+16 <= len(t.bits); i += 16 {
+			module := t.count - 1 - i/16
+			var register, data byte
+			for b := 0; b < 8; b++ {
+				register = register<<1 | t.bits[i+b]
+				data = data<<1 | t.bits[i+8+b]
+			}
+			if register != MAX7219Noop && module >= 0 {
+				t.registers[module][register] = data
+			}
+		}
+	}
+	return nil
+}
+
+func (t *max7219TestAdaptor) register(module int, register byte) byte {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.registers[module][register]
+}
+
+func newMax7219TestAdaptor(count int) *max7219TestAdaptor {
+	a := &max7219TestAdaptor{count: count}
+	for i := 0; i < count; i++ {
+		a.registers = append(a.registers, map[byte]byte{})
+	}
+	return a
+}
+
+func initTestMAX7219Driver(count uint) (*MAX7219Driver, *max7219TestAdaptor) {
+	a := newMax7219TestAdaptor(int(count))
+	return NewMAX7219Driver(a, "clock", "data", "cs", count), a
+}
+
+func TestMAX7219DriverDefaultName(t *testing.T) {
+	d, _ := initTestMAX7219Driver(4)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "MAX7219"), true)
+	gobottest.Assert(t, d.Count(), 4)
+	gobottest.Assert(t, d.Width(), 32)
+}
+
+func TestMAX7219DriverSetName(t *testing.T) {
+	d, _ := initTestMAX7219Driver(1)
+	d.SetName("mybot")
+	gobottest.Assert(t, d.Name(), "mybot")
+}
+
+func TestMAX7219DriverStart(t *testing.T) {
+	d, a := initTestMAX7219Driver(2)
+	gobottest.Assert(t, d.Start(), nil)
+	for module := 0; module < 2; module++ {
+		gobottest.Assert(t, a.register(module, MAX7219ScanLimit), byte(7))
+		gobottest.Assert(t, a.register(module, MAX7219Shutdown), byte(1))
+	}
+}
+
+func TestMAX7219DriverStartError(t *testing.T) {
+	d, a := initTestMAX7219Driver(2)
+	a.writeErr = errors.New("write error")
+	gobottest.Assert(t, d.Start(), errors.New("write error"))
+}
+
+func TestMAX7219DriverHalt(t *testing.T) {
+	d, a := initTestMAX7219Driver(2)
+	d.Start()
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, a.register(1, MAX7219Shutdown), byte(0))
+}
+
+func TestMAX7219DriverIntensity(t *testing.T) {
+	d, a := initTestMAX7219Driver(4)
+	gobottest.Assert(t, d.SetIntensity(20), nil)
+	gobottest.Assert(t, a.register(0, MAX7219Intensity), byte(15))
+	gobottest.Assert(t, a.register(3, MAX7219Intensity), byte(15))
+
+	gobottest.Assert(t, d.SetModuleIntensity(2, 3), nil)
+	gobottest.Assert(t, a.register(2, MAX7219Intensity), byte(3))
+	gobottest.Assert(t, a.register(1, MAX7219Intensity), byte(15))
+	gobottest.Refute(t, d.SetModuleIntensity(4, 3), nil)
+
+	gobottest.Assert(t, d.Command("SetIntensity")(map[string]interface{}{"level": 5.0}), nil)
+	gobottest.Assert(t, a.register(3, MAX7219Intensity), byte(5))
+}
+
+func TestMAX7219DriverDisplay(t *testing.T) {
+	d, a := initTestMAX7219Driver(2)
+	// the leftmost pixel is in the last module of the chain
+	d.SetPixel(0, 0, true)
+	d.SetPixel(15, 7, true)
+	d.SetPixel(16, 0, true)
+	gobottest.Assert(t, d.Pixel(0, 0), true)
+	gobottest.Assert(t, d.Pixel(1, 0), false)
+	gobottest.Assert(t, d.Pixel(16, 0), false)
+	gobottest.Assert(t, d.Display(), nil)
+	gobottest.Assert(t, a.register(1, MAX7219Digit0), byte(0x80))
+	gobottest.Assert(t, a.register(0, MAX7219Digit0+7), byte(0x01))
+
+	d.SetPixel(0, 0, false)
+	gobottest.Assert(t, d.Pixel(0, 0), false)
+	d.Clear()
+	gobottest.Assert(t, d.Pixel(15, 7), false)
+}
+
+func TestMAX7219DriverRotation(t *testing.T) {
+	d, a := initTestMAX7219Driver(2)
+	d.SetPixel(0, 0, true)
+	d.SetPixel(8, 0, true)
+
+	d.SetRotation(Rotation90)
+	gobottest.Assert(t, d.Display(), nil)
+	gobottest.Assert(t, a.register(1, MAX7219Digit0), byte(0x01))
+	gobottest.Assert(t, a.register(0, MAX7219Digit0), byte(0x01))
+
+	gobottest.Assert(t, d.SetModuleRotation(0, Rotation180), nil)
+	gobottest.Assert(t, d.Display(), nil)
+	gobottest.Assert(t, a.register(0, MAX7219Digit0), byte(0))
+	gobottest.Assert(t, a.register(0, MAX7219Digit0+7), byte(0x01))
+
+	gobottest.Assert(t, d.SetModuleRotation(1, Rotation270), nil)
+	gobottest.Assert(t, d.Display(), nil)
+	gobottest.Assert(t, a.register(1, MAX7219Digit0+7), byte(0x80))
+
+	gobottest.Refute(t, d.SetModuleRotation(2, Rotation0), nil)
+}
+
+func TestMAX7219DriverText(t *testing.T) {
+	d, _ := initTestMAX7219Driver(4)
+	gobottest.Assert(t, d.Text("!!"), nil)
+	// the proportional '!' is one column wide
+	gobottest.Assert(t, d.Pixel(0, 0), true)
+	gobottest.Assert(t, d.Pixel(1, 0), false)
+	gobottest.Assert(t, d.Pixel(2, 0), true)
+
+	d.Clear()
+	gobottest.Assert(t, d.DrawText(-2, "!!"), 3)
+	gobottest.Assert(t, d.Pixel(0, 0), true)
+	gobottest.Assert(t, d.Pixel(2, 0), false)
+
+	gobottest.Assert(t, d.Command("Text")(map[string]interface{}{"text": "!"}), nil)
+	gobottest.Assert(t, d.Pixel(0, 0), true)
+	gobottest.Assert(t, d.Command("Clear")(map[string]interface{}{}), nil)
+	gobottest.Assert(t, d.Pixel(0, 0), false)
+}
+
+func TestMAX7219DriverScrollText(t *testing.T) {
+	d, _ := initTestMAX7219Driver(1)
+	d.ScrollText("!", time.Millisecond)
+	// the text enters from the right of the display
+	time.Sleep(50 * time.Millisecond)
+	d.StopScroll()
+
+	d.Clear()
+	d.StopScroll()
+	time.Sleep(5 * time.Millisecond)
+	for x := 0; x < d.Width(); x++ {
+		gobottest.Assert(t, d.Pixel(x, 0), false)
+	}
+
+	gobottest.Refute(t, d.Command("ScrollText")(map[string]interface{}{"text": "!", "interval": "1"}), nil)
+	gobottest.Assert(t, d.Command("ScrollText")(map[string]interface{}{"text": "!", "interval": "1ms"}), nil)
+	gobottest.Assert(t, d.Command("StopScroll")(map[string]interface{}{}), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestMAX7219DriverScrollTextError(t *testing.T) {
+	d, a := initTestMAX7219Driver(1)
+	a.writeErr = errors.New("write error")
+	errs := make(chan error, 1)
+	d.Once(Error, func(data interface{}) {
+		errs <- data.(error)
+	})
+	d.ScrollText("!", time.Millisecond)
+	select {
+	case err := <-errs:
+		gobottest.Assert(t, err, errors.New("write error"))
+	case <-time.After(time.Second):
+		t.Errorf("MAX7219 Event \"Error\" was not published")
+	}
+	d.StopScroll()
+}
+
+func TestMAX7219DriverOne(t *testing.T) {
+	d, a := initTestMAX7219Driver(3)
+	gobottest.Assert(t, d.One(1, MAX7219DecodeMode, 0xFF), nil)
+	gobottest.Assert(t, a.register(1, MAX7219DecodeMode), byte(0xFF))
+	gobottest.Assert(t, a.register(0, MAX7219DecodeMode), byte(0))
+	gobottest.Assert(t, d.All(MAX7219DisplayTest, 1), nil)
+	gobottest.Assert(t, a.register(2, MAX7219DisplayTest), byte(1))
+	gobottest.Refute(t, d.One(-1, MAX7219DisplayTest, 1), nil)
+}
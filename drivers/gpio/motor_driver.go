@@ -57,6 +57,9 @@ func (m *MotorDriver) Off() (err error) {
 	return
 }
 
+// SafeState implements the gobot.SafeStater interface and turns the motor off
+func (m *MotorDriver) SafeState() error { return m.Off() }
+
 // On turns the motor on or sets the motor to a maximum speed
 func (m *MotorDriver) On() (err error) {
 	if m.isDigital() {
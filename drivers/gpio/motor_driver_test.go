@@ -9,6 +9,8 @@ import (
 )
 
 var _ gobot.Driver = (*MotorDriver)(nil)
+var _ gobot.MotorController = (*MotorDriver)(nil)
+var _ gobot.SafeStater = (*MotorDriver)(nil)
 
 func initTestMotorDriver() *MotorDriver {
 	return NewMotorDriver(newGpioTestAdaptor(), "1")
@@ -136,3 +138,10 @@ func TestMotorDriverSetName(t *testing.T) {
 	d.SetName("mybot")
 	gobottest.Assert(t, d.Name(), "mybot")
 }
+
+func TestMotorDriverSafeState(t *testing.T) {
+	d := initTestMotorDriver()
+	d.Forward(100)
+	gobottest.Assert(t, d.SafeState(), nil)
+	gobottest.Assert(t, d.IsOff(), true)
+}
@@ -13,6 +13,13 @@ type PIRMotionDriver struct {
 	name       string
 	halt       chan bool
 	interval   time.Duration
+	polling    gobot.PollerConfig
+	warmUp     time.Duration
+	retrigger  time.Duration
+	motion     bool
+	started    time.Time
+	motionAt   time.Time
+	lastActive time.Time
 	connection DigitalReader
 	gobot.Eventer
 }
@@ -39,6 +46,8 @@ func NewPIRMotionDriver(a DigitalReader, pin string, v ...time.Duration) *PIRMot
 
 	b.AddEvent(MotionDetected)
 	b.AddEvent(MotionStopped)
+	b.AddEvent(MotionStart)
+	b.AddEvent(MotionEnd)
 	b.AddEvent(Error)
 
 	return b
@@ -49,42 +58,96 @@ func NewPIRMotionDriver(a DigitalReader, pin string, v ...time.Duration) *PIRMot
 // Emits the Events:
 // 	MotionDetected - On motion detected
 //	MotionStopped int - On motion stopped
+//	MotionStart - On motion detected, once per motion
+//	MotionEnd time.Duration - On motion ended, with the duration of the motion
 //	Error error - On button error
 //
-// The PIRMotionDriver will send the MotionDetected event over and over,
-// just as long as motion is still being detected.
-// It will only send the MotionStopped event once, however, until
-// motion starts being detected again
+// MotionDetected and MotionStopped follow the output of the sensor, which
+// many sensors drop and raise again while the motion goes on. MotionStart
+// and MotionEnd are sent once per motion instead: the motion only ends once
+// the output has stayed low for the retrigger window, see
+// SetRetriggerWindow. No event is sent during the warm-up of the sensor,
+// see SetWarmUp.
 func (p *PIRMotionDriver) Start() (err error) {
-	go func() {
-		for {
-			newValue, err := p.connection.DigitalRead(p.Pin())
-			if err != nil {
-				p.Publish(Error, err)
-			}
-			switch newValue {
-			case 1:
-				if !p.Active {
-					p.Active = true
-					p.Publish(MotionDetected, newValue)
-				}
-			case 0:
-				if p.Active {
-					p.Active = false
-					p.Publish(MotionStopped, newValue)
-				}
+	config := p.polling
+	config.Interval = p.interval
+	clock := gobot.DefaultClock()
+	p.started = clock.Now()
+	gobot.NewPoller(config).Go(p, p.halt, func() bool {
+		now := clock.Now()
+		if now.Sub(p.started) < p.warmUp {
+			return false
+		}
+		newValue, err := p.connection.DigitalRead(p.Pin())
+		changed := false
+		if err != nil {
+			p.Publish(Error, err)
+		} else {
+			changed = p.update(newValue, now)
+		}
+		switch newValue {
+		case 1:
+			if !p.Active {
+				p.Active = true
+				p.Publish(MotionDetected, newValue)
+				return true
 			}
-
-			select {
-			case <-time.After(p.interval):
-			case <-p.halt:
-				return
+		case 0:
+			if p.Active {
+				p.Active = false
+				p.Publish(MotionStopped, newValue)
+				return true
 			}
 		}
-	}()
+		return changed
+	})
 	return
 }
 
+// update starts or ends the motion given the output of the sensor read at
+// now, and reports whether it did
+func (p *PIRMotionDriver) update(value int, now time.Time) bool {
+	if value == 1 {
+		p.lastActive = now
+		if !p.motion {
+			p.motion = true
+			p.motionAt = now
+			p.Publish(MotionStart, value)
+			return true
+		}
+		return false
+	}
+	if p.motion && now.Sub(p.lastActive) >= p.retrigger {
+		p.motion = false
+		p.Publish(MotionEnd, p.lastActive.Sub(p.motionAt))
+		return true
+	}
+	return false
+}
+
+// SetWarmUp sets the time the sensor needs to settle once powered, usually
+// 30 to 60 seconds, during which its output is ignored. Defaults to 0, the
+// sensor being powered before the robot starts.
+func (p *PIRMotionDriver) SetWarmUp(d time.Duration) {
+	p.warmUp = d
+}
+
+// SetRetriggerWindow sets how long the output of the sensor must stay low
+// for the motion to end, so that a motion retriggering the sensor within
+// the window goes on rather than starting again. Defaults to 0.
+func (p *PIRMotionDriver) SetRetriggerWindow(d time.Duration) {
+	p.retrigger = d
+}
+
+// SetPolling configures the polling of the sensor. The interval given to
+// NewPIRMotionDriver is kept when c.Interval is not set.
+func (p *PIRMotionDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		p.interval = c.Interval
+	}
+	p.polling = c
+}
+
 // Halt stops polling the button for new information
 func (p *PIRMotionDriver) Halt() (err error) {
 	p.halt <- true
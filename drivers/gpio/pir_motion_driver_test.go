@@ -3,6 +3,7 @@ package gpio
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -99,3 +100,83 @@ func TestPIRDriverSetName(t *testing.T) {
 	d.SetName("mybot")
 	gobottest.Assert(t, d.Name(), "mybot")
 }
+
+func TestPIRMotionDriverSetPolling(t *testing.T) {
+	d := initTestPIRMotionDriver()
+	d.SetPolling(gobot.PollerConfig{Interval: 50 * time.Millisecond, MaxInterval: time.Second})
+	gobottest.Assert(t, d.interval, 50*time.Millisecond)
+	gobottest.Assert(t, d.polling.MaxInterval, time.Second)
+}
+
+func TestPIRMotionDriverWarmUpAndRetrigger(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	var mtx sync.Mutex
+	value := 1
+	setValue := func(v int) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		value = v
+	}
+	a := newGpioTestAdaptor()
+	a.TestAdaptorDigitalRead(func() (int, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return value, nil
+	})
+
+	d := NewPIRMotionDriver(a, "1", 10*time.Millisecond)
+	d.SetWarmUp(100 * time.Millisecond)
+	d.SetRetriggerWindow(50 * time.Millisecond)
+	starts := d.SubscribeWith(gobot.SubscribeOptions{Pattern: MotionStart})
+	ends := d.SubscribeWith(gobot.SubscribeOptions{Pattern: MotionEnd})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	// advance polls n more times, and waits for the last poll
+	advance := func(n int) {
+		for i := 0; i < n; i++ {
+			clock.BlockUntil(1)
+			clock.Advance(10 * time.Millisecond)
+		}
+		clock.BlockUntil(1)
+	}
+	expectNone := func(events <-chan *gobot.Event, name string) {
+		select {
+		case <-events:
+			t.Errorf("PIRMotionDriver Event %q was published", name)
+		default:
+		}
+	}
+
+	// the output is ignored during the warm-up
+	advance(9)
+	expectNone(starts, MotionStart)
+	gobottest.Assert(t, d.Active, false)
+	advance(1)
+	select {
+	case <-starts:
+	case <-time.After(motionTestDelay * time.Millisecond):
+		t.Errorf("PIRMotionDriver Event \"MotionStart\" was not published")
+	}
+
+	// retriggered within the window, the motion goes on
+	setValue(0)
+	advance(3)
+	setValue(1)
+	advance(1)
+	setValue(0)
+	advance(4)
+	expectNone(starts, MotionStart)
+	expectNone(ends, MotionEnd)
+
+	advance(1)
+	select {
+	case evt := <-ends:
+		gobottest.Assert(t, evt.Data, 40*time.Millisecond)
+	case <-time.After(motionTestDelay * time.Millisecond):
+		t.Errorf("PIRMotionDriver Event \"MotionEnd\" was not published")
+	}
+}
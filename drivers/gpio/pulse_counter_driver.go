@@ -0,0 +1,227 @@
+package gpio
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// PulseRate is the data published with the Rate event.
+type PulseRate struct {
+	// Frequency is the number of pulses per second
+	Frequency float64
+	// Rate is the Frequency multiplied by the Factor of the driver, e.g. a
+	// flow in L/min or a wind speed in m/s
+	Rate float64
+	// Count is the number of pulses counted since the start or the last
+	// Reset
+	Count int64
+}
+
+// PulseCounterDriver counts the pulses of a sensor such as a water flow
+// sensor, an anemometer or a rain gauge, and measures their rate. The pulses
+// are counted from the changes of the pin, reported by the connection when
+// it is a DigitalWatcher, polled otherwise.
+type PulseCounterDriver struct {
+	// Window is the time over which the rate is measured, 1 Second by
+	// default. A Rate event is published at the end of each window.
+	Window time.Duration
+	// Windows is the number of the last Windows the rate is averaged over, 1
+	// by default. More Windows steady the rate of slow pulses, e.g. of an
+	// anemometer in a light wind, at the cost of a slower response.
+	Windows int
+	// Factor converts the Frequency into the Rate, 1 by default: 1/7.5 gives
+	// the L/min of a YF-S201 water flow sensor, 0.667 the m/s of a cup
+	// anemometer closing its switch once per revolution.
+	Factor      float64
+	name        string
+	pin         string
+	interval    time.Duration
+	connection  DigitalReader
+	level       int
+	count       int64
+	windowCount int64
+	counts      []int64
+	rate        PulseRate
+	halt        chan bool
+	mutex       *sync.Mutex
+	gobot.Eventer
+}
+
+// NewPulseCounterDriver returns a new PulseCounterDriver given a
+// DigitalReader and the pin of the sensor, counting its rising edges.
+//
+// Optionally accepts:
+//  time.Duration: Interval at which the pin is polled, when the connection is not a DigitalWatcher, 1 Millisecond by default
+func NewPulseCounterDriver(a DigitalReader, pin string, v ...time.Duration) *PulseCounterDriver {
+	p := &PulseCounterDriver{
+		name:       gobot.DefaultName("PulseCounter"),
+		connection: a,
+		pin:        pin,
+		Window:     time.Second,
+		Windows:    1,
+		Factor:     1,
+		interval:   time.Millisecond,
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		p.interval = v[0]
+	}
+
+	p.AddEvent(Rate)
+	p.AddEvent(Error)
+
+	return p
+}
+
+// Name returns the PulseCounterDrivers name
+func (p *PulseCounterDriver) Name() string { return p.name }
+
+// SetName sets the PulseCounterDrivers name
+func (p *PulseCounterDriver) SetName(n string) { p.name = n }
+
+// Pin returns the PulseCounterDrivers pin
+func (p *PulseCounterDriver) Pin() string { return p.pin }
+
+// Connection returns the PulseCounterDrivers Connection
+func (p *PulseCounterDriver) Connection() gobot.Connection {
+	return p.connection.(gobot.Connection)
+}
+
+// Start reads the initial level of the pin, then counts the pulses and
+// measures their rate.
+//
+// Emits the Events:
+// 	Rate PulseRate - At the end of each Window
+//	Error error - On pin read error
+func (p *PulseCounterDriver) Start() (err error) {
+	level, err := p.connection.DigitalRead(p.pin)
+	if err != nil {
+		return
+	}
+	p.mutex.Lock()
+	p.level = level
+	p.windowCount = p.count
+	p.counts = nil
+	p.halt = make(chan bool)
+	halt := p.halt
+	p.mutex.Unlock()
+
+	if w, ok := p.connection.(DigitalWatcher); ok {
+		if err = w.WatchDigitalPin(p.pin, p.update); err != nil {
+			return
+		}
+	} else {
+		gobot.NewPoller(gobot.PollerConfig{Interval: p.interval}).Go(p, halt, func() bool {
+			level, err := p.connection.DigitalRead(p.pin)
+			if err != nil {
+				p.Publish(Error, err)
+				return false
+			}
+			p.update(level)
+			return false
+		})
+	}
+
+	go func() {
+		ticker := gobot.DefaultClock().NewTicker(p.Window)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				p.Publish(Rate, p.measure())
+			case <-halt:
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Halt stops counting the pulses
+func (p *PulseCounterDriver) Halt() (err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.halt == nil {
+		return
+	}
+	close(p.halt)
+	p.halt = nil
+	if w, ok := p.connection.(DigitalWatcher); ok {
+		err = w.UnwatchDigitalPin(p.pin)
+	}
+	return
+}
+
+// Count returns the number of pulses counted
+func (p *PulseCounterDriver) Count() int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.count
+}
+
+// Reset sets the pulse count back to zero, e.g. once the volume of water
+// measured by a flow sensor has been recorded
+func (p *PulseCounterDriver) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.windowCount -= p.count
+	p.count = 0
+}
+
+// Frequency returns the number of pulses per second measured over the last
+// Windows
+func (p *PulseCounterDriver) Frequency() float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.rate.Frequency
+}
+
+// Rate returns the Frequency multiplied by the Factor
+func (p *PulseCounterDriver) Rate() float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.rate.Rate
+}
+
+// update counts a pulse on the rising edge of the pin
+func (p *PulseCounterDriver) update(level int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if level == 1 && p.level == 0 {
+		p.count++
+	}
+	p.level = level
+}
+
+// measure computes the rate over the Windows ending now
+func (p *PulseCounterDriver) measure() PulseRate {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.counts = append(p.counts, p.count-p.windowCount)
+	p.windowCount = p.count
+	windows := p.Windows
+	if windows < 1 {
+		windows = 1
+	}
+	if len(p.counts) > windows {
+		p.counts = p.counts[len(p.counts)-windows:]
+	}
+
+	var pulses int64
+	for _, c := range p.counts {
+		pulses += c
+	}
+	frequency := float64(pulses) / (float64(len(p.counts)) * p.Window.Seconds())
+	p.rate = PulseRate{
+		Frequency: frequency,
+		Rate:      frequency * p.Factor,
+		Count:     p.count,
+	}
+	return p.rate
+}
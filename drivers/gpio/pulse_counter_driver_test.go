@@ -0,0 +1,124 @@
+package gpio
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*PulseCounterDriver)(nil)
+
+func TestPulseCounterDriver(t *testing.T) {
+	a := &encoderTestAdaptor{levels: map[string]int{}}
+	d := NewPulseCounterDriver(a, "1")
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "PulseCounter"), true)
+	d.SetName("flow")
+	gobottest.Assert(t, d.Name(), "flow")
+	gobottest.Assert(t, d.Pin(), "1")
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, d.Window, time.Second)
+	gobottest.Assert(t, d.Factor, 1.0)
+
+	d = NewPulseCounterDriver(a, "1", 5*time.Millisecond)
+	gobottest.Assert(t, d.interval, 5*time.Millisecond)
+}
+
+func TestPulseCounterDriverPoll(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := &encoderTestAdaptor{levels: map[string]int{}}
+	d := NewPulseCounterDriver(a, "1", time.Millisecond)
+	gobottest.Assert(t, d.Start(), nil)
+
+	// the window ticker and the poller wait on the clock, the poller waits
+	// again once it has read the pin
+	poll := func(level int) {
+		a.set(level)
+		clock.Advance(time.Millisecond)
+		clock.BlockUntil(2)
+	}
+	clock.BlockUntil(2)
+	for i := 0; i < 3; i++ {
+		poll(1)
+		poll(0)
+	}
+	gobottest.Assert(t, d.Count(), int64(3))
+	d.Reset()
+	gobottest.Assert(t, d.Count(), int64(0))
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestPulseCounterDriverPollError(t *testing.T) {
+	a := &encoderTestAdaptor{levels: map[string]int{}, readErr: errors.New("read error")}
+	d := NewPulseCounterDriver(a, "1")
+	gobottest.Assert(t, d.Start(), errors.New("read error"))
+
+	a.readErr = nil
+	gobottest.Assert(t, d.Start(), nil)
+	sem := make(chan error, 1)
+	d.Once(Error, func(data interface{}) { sem <- data.(error) })
+	a.mtx.Lock()
+	a.readErr = errors.New("read error")
+	a.mtx.Unlock()
+	select {
+	case err := <-sem:
+		gobottest.Assert(t, err, errors.New("read error"))
+	case <-time.After(time.Second):
+		t.Errorf("PulseCounter Error event was not published")
+	}
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestPulseCounterDriverRate(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(1488469265, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	w := newEncoderTestWatcher()
+	d := NewPulseCounterDriver(w, "1")
+	d.Factor = 1 / 7.5
+	d.Windows = 2
+	rates := d.Subscribe()
+	gobottest.Assert(t, d.Start(), nil)
+
+	pulse := func(n int) {
+		for i := 0; i < n; i++ {
+			w.change("1", 1)
+			w.change("1", 0)
+		}
+	}
+
+	// 15 Hz is 2 L/min for a YF-S201
+	pulse(15)
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	evt := <-rates
+	gobottest.Assert(t, evt.Name, Rate)
+	gobottest.Assert(t, evt.Data.(PulseRate).Frequency, 15.0)
+	gobottest.Assert(t, math.Abs(evt.Data.(PulseRate).Rate-2) < 1e-9, true)
+	gobottest.Assert(t, evt.Data.(PulseRate).Count, int64(15))
+
+	// averaged over the last two windows
+	pulse(5)
+	clock.Advance(time.Second)
+	rate := (<-rates).Data.(PulseRate)
+	gobottest.Assert(t, rate.Frequency, 10.0)
+	pulse(1)
+	clock.Advance(time.Second)
+	rate = (<-rates).Data.(PulseRate)
+	gobottest.Assert(t, rate.Frequency, 3.0)
+	gobottest.Assert(t, rate.Count, int64(21))
+	gobottest.Assert(t, d.Frequency(), 3.0)
+	gobottest.Assert(t, math.Abs(d.Rate()-0.4) < 1e-9, true)
+
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, len(w.handlers), 0)
+	gobottest.Assert(t, d.Halt(), nil)
+}
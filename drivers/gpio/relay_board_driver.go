@@ -0,0 +1,298 @@
+package gpio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// RelayBoardDriver represents a board of relays, such as the 4 or 8 channel
+// relay modules. Its channels can be interlocked, so that at most one relay
+// of a group is on at once, e.g. the up and down relays of a garage door.
+type RelayBoardDriver struct {
+	name       string
+	connection DigitalWriter
+	relays     []*RelayDriver
+	groups     map[int][]int
+	pulses     map[int]chan bool
+	scheduler  *gobot.Scheduler
+	mutex      *sync.Mutex
+	gobot.Commander
+	gobot.Eventer
+}
+
+// NewRelayBoardDriver return a new RelayBoardDriver given a DigitalWriter and
+// the pins of its channels, the first pin being channel 0.
+//
+// Adds the following API Commands:
+//	"On" - See RelayBoardDriver.On
+//	"Off" - See RelayBoardDriver.Off
+//	"Toggle" - See RelayBoardDriver.Toggle
+//	"PulseFor" - See RelayBoardDriver.PulseFor
+//	"AllOff" - See RelayBoardDriver.AllOff
+func NewRelayBoardDriver(a DigitalWriter, pins ...string) *RelayBoardDriver {
+	b := &RelayBoardDriver{
+		name:       gobot.DefaultName("RelayBoard"),
+		connection: a,
+		groups:     map[int][]int{},
+		pulses:     map[int]chan bool{},
+		scheduler:  gobot.NewScheduler(),
+		mutex:      &sync.Mutex{},
+		Commander:  gobot.NewCommander(),
+		Eventer:    gobot.NewEventer(),
+	}
+
+	for _, pin := range pins {
+		b.relays = append(b.relays, NewRelayDriver(a, pin))
+	}
+
+	b.AddEvent(Error)
+
+	b.AddCommand("On", func(params map[string]interface{}) interface{} {
+		return b.On(int(params["channel"].(float64)))
+	})
+
+	b.AddCommand("Off", func(params map[string]interface{}) interface{} {
+		return b.Off(int(params["channel"].(float64)))
+	})
+
+	b.AddCommand("Toggle", func(params map[string]interface{}) interface{} {
+		return b.Toggle(int(params["channel"].(float64)))
+	})
+
+	b.AddCommand("PulseFor", func(params map[string]interface{}) interface{} {
+		d, err := time.ParseDuration(params["duration"].(string))
+		if err != nil {
+			return err
+		}
+		return b.PulseFor(int(params["channel"].(float64)), d)
+	})
+
+	b.AddCommand("AllOff", func(params map[string]interface{}) interface{} {
+		return b.AllOff()
+	})
+
+	return b
+}
+
+// Start implements the Driver interface
+func (b *RelayBoardDriver) Start() (err error) { return }
+
+// Halt stops the scheduled switching and the pending pulses, and turns all
+// the relays off
+func (b *RelayBoardDriver) Halt() (err error) {
+	b.scheduler.Stop()
+	return b.AllOff()
+}
+
+// Name returns the RelayBoardDrivers name
+func (b *RelayBoardDriver) Name() string { return b.name }
+
+// SetName sets the RelayBoardDrivers name
+func (b *RelayBoardDriver) SetName(n string) { b.name = n }
+
+// Connection returns the RelayBoardDrivers Connection
+func (b *RelayBoardDriver) Connection() gobot.Connection {
+	return b.connection.(gobot.Connection)
+}
+
+// Channels returns the number of relays of the board
+func (b *RelayBoardDriver) Channels() int { return len(b.relays) }
+
+// Pin returns the pin of channel
+func (b *RelayBoardDriver) Pin(channel int) string {
+	if err := b.check(channel); err != nil {
+		return ""
+	}
+	return b.relays[channel].Pin()
+}
+
+// Interlock groups channels so that turning one of them on first turns the
+// others off. A channel belongs to one group at most.
+func (b *RelayBoardDriver) Interlock(channels ...int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, channel := range channels {
+		if err := b.check(channel); err != nil {
+			return err
+		}
+		if _, ok := b.groups[channel]; ok {
+			return fmt.Errorf("relay channel %d is already interlocked", channel)
+		}
+	}
+
+	group := append([]int{}, channels...)
+	for _, channel := range channels {
+		b.groups[channel] = group
+	}
+	return nil
+}
+
+// State return true if the relay of channel is On and false if it is Off
+func (b *RelayBoardDriver) State(channel int) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.check(channel) != nil {
+		return false
+	}
+	return b.relays[channel].State()
+}
+
+// On turns the relay of channel on, once the relays interlocked with it are
+// off.
+func (b *RelayBoardDriver) On(channel int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.on(channel)
+}
+
+// Off turns the relay of channel off
+func (b *RelayBoardDriver) Off(channel int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.off(channel)
+}
+
+// Toggle sets the relay of channel to the opposite of it's current state
+func (b *RelayBoardDriver) Toggle(channel int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err := b.check(channel); err != nil {
+		return err
+	}
+	if b.relays[channel].State() {
+		return b.off(channel)
+	}
+	return b.on(channel)
+}
+
+// AllOff turns all the relays off
+func (b *RelayBoardDriver) AllOff() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for channel := range b.relays {
+		if e := b.off(channel); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// SafeState implements the gobot.SafeStater interface and turns all the
+// relays off
+func (b *RelayBoardDriver) SafeState() error { return b.AllOff() }
+
+// PulseFor turns the relay of channel on for d, e.g. to press the button of
+// a garage door. Switching the relay again before d has elapsed ends the
+// pulse.
+//
+// Emits the Events:
+// 	Error error - On error turning the relay off at the end of the pulse
+func (b *RelayBoardDriver) PulseFor(channel int, d time.Duration) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err := b.on(channel); err != nil {
+		return err
+	}
+
+	cancel := make(chan bool)
+	b.pulses[channel] = cancel
+	go func() {
+		select {
+		case <-gobot.DefaultClock().After(d):
+		case <-cancel:
+			return
+		}
+
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		if b.pulses[channel] != cancel {
+			return
+		}
+		if err := b.off(channel); err != nil {
+			b.Publish(Error, err)
+		}
+	}()
+	return nil
+}
+
+// Schedule turns the relay of channel on for d at the times of the cron
+// expression spec, e.g. "0 6 * * *" to water a garden every morning, until
+// the Driver halts or the job is removed with Unschedule. An existing job
+// with the same name is replaced. See gobot.ParseCron for the accepted
+// syntax.
+//
+// Emits the Events:
+// 	Error error - On error switching the relay
+func (b *RelayBoardDriver) Schedule(name string, spec string, channel int, d time.Duration) (*gobot.Job, error) {
+	if err := b.check(channel); err != nil {
+		return nil, err
+	}
+	return b.scheduler.Cron(name, spec, func() {
+		if err := b.PulseFor(channel, d); err != nil {
+			b.Publish(Error, err)
+		}
+	})
+}
+
+// Unschedule removes the scheduled job name
+func (b *RelayBoardDriver) Unschedule(name string) {
+	b.scheduler.Remove(name)
+}
+
+// Jobs returns the scheduled jobs sorted by name
+func (b *RelayBoardDriver) Jobs() []*gobot.Job {
+	return b.scheduler.Jobs()
+}
+
+// check returns an error if channel is not a channel of the board
+func (b *RelayBoardDriver) check(channel int) error {
+	if channel < 0 || channel >= len(b.relays) {
+		return fmt.Errorf("relay channel %d out of range, the board has %d channels", channel, len(b.relays))
+	}
+	return nil
+}
+
+// on turns the relay of channel on, the mutex being held
+func (b *RelayBoardDriver) on(channel int) error {
+	if err := b.check(channel); err != nil {
+		return err
+	}
+	for _, other := range b.groups[channel] {
+		if other == channel {
+			continue
+		}
+		// break before make
+		if err := b.off(other); err != nil {
+			return err
+		}
+	}
+	b.cancelPulse(channel)
+	return b.relays[channel].On()
+}
+
+// off turns the relay of channel off, the mutex being held
+func (b *RelayBoardDriver) off(channel int) error {
+	if err := b.check(channel); err != nil {
+		return err
+	}
+	b.cancelPulse(channel)
+	return b.relays[channel].Off()
+}
+
+// cancelPulse ends the pending pulse of channel, the mutex being held
+func (b *RelayBoardDriver) cancelPulse(channel int) {
+	if cancel, ok := b.pulses[channel]; ok {
+		close(cancel)
+		delete(b.pulses, channel)
+	}
+}
@@ -0,0 +1,236 @@
+package gpio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*RelayBoardDriver)(nil)
+var _ gobot.SafeStater = (*RelayBoardDriver)(nil)
+
+// relayBoardTestAdaptor records the writes to each pin
+type relayBoardTestAdaptor struct {
+	gpioTestBareAdaptor
+	mtx    sync.Mutex
+	writes []string
+	err    error
+}
+
+func (t *relayBoardTestAdaptor) DigitalWrite(pin string, level byte) (err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	t.writes = append(t.writes, fmt.Sprintf("%s=%d", pin, level))
+	return
+}
+
+func (t *relayBoardTestAdaptor) Writes() []string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	writes := t.writes
+	t.writes = nil
+	return writes
+}
+
+func initTestRelayBoardDriver() (*RelayBoardDriver, *relayBoardTestAdaptor) {
+	a := &relayBoardTestAdaptor{}
+	return NewRelayBoardDriver(a, "1", "2", "3", "4"), a
+}
+
+func TestRelayBoardDriver(t *testing.T) {
+	d, _ := initTestRelayBoardDriver()
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "RelayBoard"), true)
+	d.SetName("mybot")
+	gobottest.Assert(t, d.Name(), "mybot")
+	gobottest.Assert(t, d.Channels(), 4)
+	gobottest.Assert(t, d.Pin(2), "3")
+	gobottest.Assert(t, d.Pin(4), "")
+	gobottest.Assert(t, d.Start(), nil)
+}
+
+func TestRelayBoardDriverOnOff(t *testing.T) {
+	d, a := initTestRelayBoardDriver()
+	gobottest.Assert(t, d.On(0), nil)
+	gobottest.Assert(t, d.On(1), nil)
+	gobottest.Assert(t, d.State(0), true)
+	gobottest.Assert(t, d.State(1), true)
+
+	gobottest.Assert(t, d.Toggle(1), nil)
+	gobottest.Assert(t, d.State(1), false)
+	gobottest.Assert(t, d.Off(0), nil)
+	gobottest.Assert(t, d.State(0), false)
+	gobottest.Assert(t, a.Writes(), []string{"1=1", "2=1", "2=0", "1=0"})
+
+	gobottest.Assert(t, d.On(4), errors.New("relay channel 4 out of range, the board has 4 channels"))
+	gobottest.Assert(t, d.Off(-1), errors.New("relay channel -1 out of range, the board has 4 channels"))
+	gobottest.Assert(t, d.State(4), false)
+}
+
+func TestRelayBoardDriverSafeState(t *testing.T) {
+	d, a := initTestRelayBoardDriver()
+	d.On(0)
+	d.On(2)
+	a.Writes()
+	gobottest.Assert(t, d.SafeState(), nil)
+	gobottest.Assert(t, d.State(0), false)
+	gobottest.Assert(t, d.State(2), false)
+	gobottest.Assert(t, a.Writes(), []string{"1=0", "2=0", "3=0", "4=0"})
+}
+
+func TestRelayBoardDriverInterlock(t *testing.T) {
+	d, a := initTestRelayBoardDriver()
+	gobottest.Assert(t, d.Interlock(0, 1), nil)
+	gobottest.Assert(t, d.Interlock(1, 2), errors.New("relay channel 1 is already interlocked"))
+	gobottest.Assert(t, d.Interlock(3, 5), errors.New("relay channel 5 out of range, the board has 4 channels"))
+
+	d.On(0)
+	d.On(3)
+	a.Writes()
+
+	// the other relay of the group is turned off first
+	gobottest.Assert(t, d.On(1), nil)
+	gobottest.Assert(t, a.Writes(), []string{"1=0", "2=1"})
+	gobottest.Assert(t, d.State(0), false)
+	gobottest.Assert(t, d.State(1), true)
+	gobottest.Assert(t, d.State(3), true)
+
+	gobottest.Assert(t, d.Toggle(0), nil)
+	gobottest.Assert(t, d.State(0), true)
+	gobottest.Assert(t, d.State(1), false)
+}
+
+func TestRelayBoardDriverInterlockError(t *testing.T) {
+	d, a := initTestRelayBoardDriver()
+	d.Interlock(0, 1)
+	d.On(0)
+	a.mtx.Lock()
+	a.err = errors.New("write error")
+	a.mtx.Unlock()
+
+	// the relay is not turned on when the other one may still be
+	gobottest.Assert(t, d.On(1), errors.New("write error"))
+	gobottest.Assert(t, d.State(0), true)
+	gobottest.Assert(t, d.State(1), false)
+	gobottest.Assert(t, d.AllOff(), errors.New("write error"))
+}
+
+func TestRelayBoardDriverPulseFor(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	d, a := initTestRelayBoardDriver()
+	gobottest.Assert(t, d.PulseFor(2, time.Second), nil)
+	gobottest.Assert(t, d.State(2), true)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	waitForRelayState(t, d, 2, false)
+	gobottest.Assert(t, a.Writes(), []string{"3=1", "3=0"})
+
+	// turning the relay off ends the pulse
+	d.PulseFor(2, time.Second)
+	clock.BlockUntil(1)
+	d.Off(2)
+	d.On(2)
+	clock.Advance(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, d.State(2), true)
+
+	// a new pulse replaces the pending one
+	d.PulseFor(2, time.Second)
+	clock.BlockUntil(1)
+	d.PulseFor(2, 2*time.Second)
+	clock.BlockUntil(2)
+	clock.Advance(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, d.State(2), true)
+	clock.Advance(time.Second)
+	waitForRelayState(t, d, 2, false)
+}
+
+func TestRelayBoardDriverPulseForError(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	d, a := initTestRelayBoardDriver()
+	errs := d.SubscribeWith(gobot.SubscribeOptions{Pattern: Error})
+	gobottest.Assert(t, d.PulseFor(4, time.Second), errors.New("relay channel 4 out of range, the board has 4 channels"))
+
+	d.PulseFor(0, time.Second)
+	clock.BlockUntil(1)
+	a.mtx.Lock()
+	a.err = errors.New("write error")
+	a.mtx.Unlock()
+	clock.Advance(time.Second)
+
+	select {
+	case evt := <-errs:
+		gobottest.Assert(t, evt.Data, errors.New("write error"))
+	case <-time.After(time.Second):
+		t.Errorf("Error was not published")
+	}
+}
+
+func TestRelayBoardDriverSchedule(t *testing.T) {
+	d, _ := initTestRelayBoardDriver()
+	_, err := d.Schedule("water", "0 6 * * *", 4, time.Minute)
+	gobottest.Assert(t, err, errors.New("relay channel 4 out of range, the board has 4 channels"))
+	_, err = d.Schedule("water", "not a spec", 0, time.Minute)
+	gobottest.Refute(t, err, nil)
+
+	job, err := d.Schedule("water", "0 6 * * *", 0, 20*time.Minute)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, job.Next().Hour(), 6)
+	gobottest.Assert(t, len(d.Jobs()), 1)
+
+	d.Unschedule("water")
+	gobottest.Assert(t, len(d.Jobs()), 0)
+}
+
+func TestRelayBoardDriverHalt(t *testing.T) {
+	d, _ := initTestRelayBoardDriver()
+	d.Schedule("water", "0 6 * * *", 0, 20*time.Minute)
+	d.On(1)
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, d.State(1), false)
+	gobottest.Assert(t, len(d.Jobs()), 0)
+}
+
+func TestRelayBoardDriverCommands(t *testing.T) {
+	d, _ := initTestRelayBoardDriver()
+	gobottest.Assert(t, d.Command("On")(map[string]interface{}{"channel": 1.0}), nil)
+	gobottest.Assert(t, d.State(1), true)
+	gobottest.Assert(t, d.Command("Toggle")(map[string]interface{}{"channel": 1.0}), nil)
+	gobottest.Assert(t, d.State(1), false)
+	gobottest.Assert(t, d.Command("PulseFor")(map[string]interface{}{"channel": 0.0, "duration": "1h"}), nil)
+	gobottest.Assert(t, d.State(0), true)
+	gobottest.Refute(t, d.Command("PulseFor")(map[string]interface{}{"channel": 0.0, "duration": "long"}), nil)
+	gobottest.Assert(t, d.Command("Off")(map[string]interface{}{"channel": 0.0}), nil)
+	gobottest.Assert(t, d.State(0), false)
+	d.On(2)
+	gobottest.Assert(t, d.Command("AllOff")(nil), nil)
+	gobottest.Assert(t, d.State(2), false)
+}
+
+func waitForRelayState(t *testing.T, d *RelayBoardDriver, channel int, state bool) {
+	deadline := time.After(time.Second)
+	for d.State(channel) != state {
+		select {
+		case <-deadline:
+			t.Fatalf("relay channel %d did not switch", channel)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -84,6 +84,10 @@ func (l *RelayDriver) Off() (err error) {
 	return
 }
 
+// SafeState implements the gobot.SafeStater interface and turns the relay
+// off
+func (l *RelayDriver) SafeState() error { return l.Off() }
+
 // Toggle sets the relay to the opposite of it's current state
 func (l *RelayDriver) Toggle() (err error) {
 	if l.State() {
@@ -9,6 +9,7 @@ import (
 )
 
 var _ gobot.Driver = (*RelayDriver)(nil)
+var _ gobot.SafeStater = (*RelayDriver)(nil)
 
 func initTestRelayDriver() *RelayDriver {
 	a := newGpioTestAdaptor()
@@ -52,6 +53,13 @@ func TestRelayDriverToggle(t *testing.T) {
 	gobottest.Assert(t, d.State(), false)
 }
 
+func TestRelayDriverSafeState(t *testing.T) {
+	d := initTestRelayDriver()
+	d.On()
+	gobottest.Assert(t, d.SafeState(), nil)
+	gobottest.Assert(t, d.State(), false)
+}
+
 func TestRelayDriverCommands(t *testing.T) {
 	d := initTestRelayDriver()
 	gobottest.Assert(t, d.Command("Off")(nil), nil)
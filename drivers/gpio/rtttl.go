@@ -0,0 +1,146 @@
+package gpio
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Note is a tone of a melody
+type Note struct {
+	// Frequency is the frequency of the tone in Hz, or Rest for a silence
+	Frequency float64
+	// Duration is how long the tone is played
+	Duration time.Duration
+}
+
+// Melody is a named sequence of Notes
+type Melody struct {
+	Name  string
+	Notes []Note
+}
+
+// semitones are the positions of the notes within an octave, from C
+var semitones = map[byte]int{
+	'c': 0, 'd': 2, 'e': 4, 'f': 5, 'g': 7, 'a': 9, 'b': 11,
+	// some ringtones use the german name of B
+	'h': 11,
+}
+
+// ParseRTTTL parses a ringtone in the Ring Tone Text Transfer Language used
+// by Nokia phones, such as:
+//
+//	"Beep:d=4,o=5,b=120:c,8e,8g,2c6,p"
+//
+// The name is followed by the default duration, octave and beats per minute
+// of the notes, and by the notes. Each note is made of an optional duration,
+// the note or p for a pause, an optional sharp, an optional dot making it
+// one half longer, and an optional octave.
+func ParseRTTTL(rtttl string) (m Melody, err error) {
+	sections := strings.Split(rtttl, ":")
+	if len(sections) != 3 {
+		return m, fmt.Errorf("RTTTL must have a name, defaults and notes sections separated by colons")
+	}
+	m.Name = strings.TrimSpace(sections[0])
+
+	duration, octave, bpm := 4, 6, 63
+	for _, setting := range strings.Split(sections[1], ",") {
+		setting = strings.ToLower(strings.TrimSpace(setting))
+		if setting == "" {
+			continue
+		}
+		kv := strings.SplitN(setting, "=", 2)
+		if len(kv) != 2 {
+			return m, fmt.Errorf("invalid RTTTL default %q", setting)
+		}
+		value, err := strconv.Atoi(kv[1])
+		if err != nil || value <= 0 {
+			return m, fmt.Errorf("invalid RTTTL default %q", setting)
+		}
+		switch kv[0] {
+		case "d":
+			duration = value
+		case "o":
+			octave = value
+		case "b":
+			bpm = value
+		default:
+			return m, fmt.Errorf("invalid RTTTL default %q", setting)
+		}
+	}
+
+	// the durations are fractions of a whole note, which lasts 4 beats
+	whole := 4 * time.Minute / time.Duration(bpm)
+	for _, token := range strings.Split(sections[2], ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token == "" {
+			continue
+		}
+		n, err := parseRTTTLNote(token, duration, octave, whole)
+		if err != nil {
+			return m, err
+		}
+		m.Notes = append(m.Notes, n)
+	}
+	return m, nil
+}
+
+func parseRTTTLNote(token string, duration int, octave int, whole time.Duration) (n Note, err error) {
+	invalid := fmt.Errorf("invalid RTTTL note %q", token)
+	i := 0
+	digits := func() string {
+		start := i
+		for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+			i++
+		}
+		return token[start:i]
+	}
+
+	if d := digits(); d != "" {
+		if duration, err = strconv.Atoi(d); err != nil || duration <= 0 {
+			return n, invalid
+		}
+	}
+
+	if i == len(token) {
+		return n, invalid
+	}
+	note := token[i]
+	i++
+	semitone, ok := semitones[note]
+	if !ok && note != 'p' {
+		return n, invalid
+	}
+	if i < len(token) && token[i] == '#' {
+		semitone++
+		i++
+	}
+
+	dotted := false
+	if i < len(token) && token[i] == '.' {
+		dotted = true
+		i++
+	}
+	if o := digits(); o != "" {
+		octave, _ = strconv.Atoi(o)
+	}
+	if i < len(token) && token[i] == '.' {
+		dotted = true
+		i++
+	}
+	if i != len(token) {
+		return n, invalid
+	}
+
+	n.Duration = whole / time.Duration(duration)
+	if dotted {
+		n.Duration += n.Duration / 2
+	}
+	if note != 'p' {
+		// A4 is 440Hz, each semitone a twelfth root of two apart
+		n.Frequency = 440 * math.Pow(2, float64((octave-4)*12+semitone-9)/12)
+	}
+	return n, nil
+}
@@ -0,0 +1,47 @@
+package gpio
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestParseRTTTL(t *testing.T) {
+	m, err := ParseRTTTL("Beep:d=4,o=5,b=120:c,8e,8g.,2c6,p,a#4,16h.")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, m.Name, "Beep")
+	gobottest.Assert(t, len(m.Notes), 7)
+
+	// at 120 bpm a quarter note lasts half a second
+	durations := []time.Duration{500000, 250000, 375000, 1000000, 500000, 500000, 187500}
+	frequencies := []float64{C5, E5, G5, C6, Rest, Bb4, B5}
+	for i, n := range m.Notes {
+		gobottest.Assert(t, n.Duration, durations[i]*time.Microsecond)
+		gobottest.Assert(t, math.Abs(n.Frequency-frequencies[i]) < 0.01, true)
+	}
+}
+
+func TestParseRTTTLDefaults(t *testing.T) {
+	m, err := ParseRTTTL("Default::a")
+	gobottest.Assert(t, err, nil)
+	// a quarter note at 63 bpm, in the 6th octave
+	gobottest.Assert(t, m.Notes, []Note{{Frequency: A6, Duration: time.Minute / 63}})
+}
+
+func TestParseRTTTLError(t *testing.T) {
+	_, err := ParseRTTTL("Beep:d=4")
+	gobottest.Assert(t, err, errors.New("RTTTL must have a name, defaults and notes sections separated by colons"))
+	_, err = ParseRTTTL("Beep:x=4:c")
+	gobottest.Assert(t, err, errors.New(`invalid RTTTL default "x=4"`))
+	_, err = ParseRTTTL("Beep:b=0:c")
+	gobottest.Assert(t, err, errors.New(`invalid RTTTL default "b=0"`))
+	_, err = ParseRTTTL("Beep:b=120:c,8x")
+	gobottest.Assert(t, err, errors.New(`invalid RTTTL note "8x"`))
+	_, err = ParseRTTTL("Beep:b=120:8")
+	gobottest.Assert(t, err, errors.New(`invalid RTTTL note "8"`))
+	_, err = ParseRTTTL("Beep:b=120:c5!")
+	gobottest.Assert(t, err, errors.New(`invalid RTTTL note "c5!"`))
+}
@@ -72,6 +72,16 @@ func (s *ServoDriver) Move(angle uint8) (err error) {
 	return s.connection.ServoWrite(s.Pin(), angle)
 }
 
+// SafeState implements the gobot.SafeStater interface and detaches the
+// servo, stopping its pulses so that it no longer holds its position, when
+// the connection is a PwmWriter.
+func (s *ServoDriver) SafeState() error {
+	if p, ok := s.connection.(PwmWriter); ok {
+		return p.PwmWrite(s.Pin(), 0)
+	}
+	return nil
+}
+
 // Min sets the servo to it's minimum position
 func (s *ServoDriver) Min() (err error) {
 	return s.Move(0)
@@ -10,6 +10,7 @@ import (
 )
 
 var _ gobot.Driver = (*ServoDriver)(nil)
+var _ gobot.SafeStater = (*ServoDriver)(nil)
 
 func initTestServoDriver() *ServoDriver {
 	return NewServoDriver(newGpioTestAdaptor(), "1")
@@ -87,3 +88,24 @@ func TestServoDriverSetName(t *testing.T) {
 	d.SetName("mybot")
 	gobottest.Assert(t, d.Name(), "mybot")
 }
+
+func TestServoDriverSafeState(t *testing.T) {
+	a := newGpioTestAdaptor()
+	d := NewServoDriver(a, "1")
+	gobottest.Assert(t, d.SafeState(), nil)
+
+	a.TestAdaptorPwmWrite(func() (err error) {
+		return errors.New("pwm error")
+	})
+	gobottest.Assert(t, d.SafeState(), errors.New("pwm error"))
+
+	// the servo can not be detached without PWM
+	d = NewServoDriver(&servoTestWriter{}, "1")
+	gobottest.Assert(t, d.SafeState(), nil)
+}
+
+type servoTestWriter struct {
+	gpioTestBareAdaptor
+}
+
+func (t *servoTestWriter) ServoWrite(string, byte) (err error) { return }
@@ -97,7 +97,7 @@ func (s *StepperDriver) Start() (err error) { return }
 // Run continuously runs the stepper
 func (s *StepperDriver) Run() (err error) {
 	//halt if already moving
-	if s.moving == true {
+	if s.IsMoving() {
 		s.Halt()
 	}
 
@@ -107,7 +107,7 @@ func (s *StepperDriver) Run() (err error) {
 
 	go func() {
 		for {
-			if s.moving == false {
+			if !s.IsMoving() {
 				break
 			}
 			s.step()
@@ -125,6 +125,20 @@ func (s *StepperDriver) Halt() (err error) {
 	return nil
 }
 
+// SafeState implements the gobot.SafeStater interface, halting the stepper
+// and releasing its coils
+func (s *StepperDriver) SafeState() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.moving = false
+	for _, pin := range s.pins {
+		if e := s.connection.DigitalWrite(pin, 0); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
+}
+
 // SetDirection sets the direction in which motor should be moving, Default is forward
 func (s *StepperDriver) SetDirection(direction string) error {
 	direction = strings.ToLower(direction)
@@ -140,11 +154,21 @@ func (s *StepperDriver) SetDirection(direction string) error {
 
 // IsMoving returns a bool stating whether motor is currently in motion
 func (s *StepperDriver) IsMoving() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	return s.moving
 }
 
 // Step moves motor one step in giving direction
 func (s *StepperDriver) step() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// the stepper was halted meanwhile
+	if !s.moving {
+		return nil
+	}
+
 	if s.direction == "forward" {
 		s.stepNum++
 	} else {
@@ -159,13 +183,7 @@ func (s *StepperDriver) step() error {
 
 	r := int(math.Abs(float64(s.stepNum))) % len(s.phase)
 
-	for i, v := range s.phase[r] {
-		if err := s.connection.DigitalWrite(s.pins[i], v); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return WritePins(s.connection, s.pins[:], s.phase[r][:])
 }
 
 // Move moves the motor for given number of steps
@@ -174,7 +192,7 @@ func (s *StepperDriver) Move(stepsToMove int) error {
 		return s.Halt()
 	}
 
-	if s.moving == true {
+	if s.IsMoving() {
 		//stop previous motion
 		s.Halt()
 	}
@@ -190,9 +208,11 @@ func (s *StepperDriver) Move(stepsToMove int) error {
 
 	stepsLeft := int64(math.Abs(float64(stepsToMove)))
 	//Do not remove *1000 and change duration to time.Millisecond. It has been done for a reason
+	s.mutex.Lock()
 	delay := time.Duration(60000*1000/(s.stepsPerRev*s.speed)) * time.Microsecond
+	s.mutex.Unlock()
 
-	for stepsLeft > 0 {
+	for stepsLeft > 0 && s.IsMoving() {
 		if err := s.step(); err != nil {
 			return err
 		}
@@ -200,12 +220,16 @@ func (s *StepperDriver) Move(stepsToMove int) error {
 		time.Sleep(delay)
 	}
 
+	s.mutex.Lock()
 	s.moving = false
+	s.mutex.Unlock()
 	return nil
 }
 
 // GetCurrentStep gives the current step of motor
 func (s *StepperDriver) GetCurrentStep() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	return s.stepNum
 }
 
@@ -226,6 +250,8 @@ func (s *StepperDriver) SetSpeed(rpm uint) error {
 		rpm = m
 	}
 
+	s.mutex.Lock()
 	s.speed = rpm
+	s.mutex.Unlock()
 	return nil
 }
@@ -6,9 +6,12 @@ import (
 	"testing"
 	"time"
 
+	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
 )
 
+var _ gobot.SafeStater = (*StepperDriver)(nil)
+
 const (
 	stepsInRev = 32
 )
@@ -103,3 +106,66 @@ func TestStepperDriverMotorSetSpeedLessOrEqualMax(t *testing.T) {
 	d.SetSpeed(m)
 	gobottest.Assert(t, m, d.speed)
 }
+
+func TestStepperDriverSafeState(t *testing.T) {
+	a := &relayBoardTestAdaptor{}
+	d := NewStepperDriver(a, [4]string{"7", "11", "13", "15"}, StepperModes.DualPhaseStepping, stepsInRev)
+	d.Run()
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, d.SafeState(), nil)
+	gobottest.Assert(t, d.IsMoving(), false)
+	writes := a.Writes()
+	gobottest.Assert(t, writes[len(writes)-4:], []string{"7=0", "11=0", "13=0", "15=0"})
+
+	// the coils stay released
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, len(a.Writes()), 0)
+
+	a.err = errors.New("write error")
+	gobottest.Assert(t, d.SafeState(), errors.New("write error"))
+}
+
+// pinsTestAdaptor writes several pins at once
+type pinsTestAdaptor struct {
+	relayBoardTestAdaptor
+	batches []map[string]byte
+}
+
+func (t *pinsTestAdaptor) DigitalWritePins(levels map[string]byte) (err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.batches = append(t.batches, levels)
+	return
+}
+
+func TestStepperDriverStepWritesPins(t *testing.T) {
+	a := &pinsTestAdaptor{}
+	d := NewStepperDriver(a, [4]string{"7", "11", "13", "15"}, StepperModes.DualPhaseStepping, stepsInRev)
+	d.moving = true
+	gobottest.Assert(t, d.step(), nil)
+	gobottest.Assert(t, a.batches, []map[string]byte{{"7": 1, "11": 1, "13": 0, "15": 0}})
+	gobottest.Assert(t, len(a.Writes()), 0)
+}
+
+func TestWritePins(t *testing.T) {
+	a := &relayBoardTestAdaptor{}
+	gobottest.Assert(t, WritePins(a, []string{"3", "1"}, []byte{1, 0}), nil)
+	gobottest.Assert(t, a.Writes(), []string{"3=1", "1=0"})
+
+	a.err = errors.New("write error")
+	gobottest.Assert(t, WritePins(a, []string{"3"}, []byte{1}), errors.New("write error"))
+}
+
+func TestStepperDriverMoveHalted(t *testing.T) {
+	d := initStepperMotorDriver()
+	done := make(chan error)
+	go func() { done <- d.Move(stepsInRev * 100) }()
+	time.Sleep(10 * time.Millisecond)
+	d.Halt()
+	select {
+	case err := <-done:
+		gobottest.Assert(t, err, nil)
+	case <-time.After(time.Second):
+		t.Error("Move was not halted")
+	}
+}
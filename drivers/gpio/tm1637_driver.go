@@ -0,0 +1,327 @@
+package gpio
+
+import (
+	"fmt"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// The commands of the TM1637
+const (
+	TM1637DataCommand    = 0x40
+	TM1637AddressCommand = 0xC0
+	TM1637DisplayOff     = 0x80
+	TM1637DisplayOn      = 0x88
+)
+
+// The segments of a digit of the TM1637, to combine for SetSegments
+const (
+	TM1637SegmentA = 1 << iota
+	TM1637SegmentB
+	TM1637SegmentC
+	TM1637SegmentD
+	TM1637SegmentE
+	TM1637SegmentF
+	TM1637SegmentG
+	// TM1637SegmentDP is the decimal point, or the colon after the second
+	// digit of the clock displays
+	TM1637SegmentDP
+)
+
+// TM1637Digits holds the segments of the hexadecimal digits
+var TM1637Digits = [16]byte{
+	0x3F, 0x06, 0x5B, 0x4F, 0x66, 0x6D, 0x7D, 0x07,
+	0x7F, 0x6F, 0x77, 0x7C, 0x39, 0x5E, 0x79, 0x71,
+}
+
+// tm1637Minus is the segment showing a minus sign
+const tm1637Minus = TM1637SegmentG
+
+// TM1637Driver represents a 4-digit 7-segment display driven by a TM1637,
+// such as those of the robot kits, whose colon lights with the decimal point
+// of the second digit.
+type TM1637Driver struct {
+	name       string
+	connection DigitalWriter
+	pinClock   string
+	pinData    string
+	digits     [4]byte
+	colon      bool
+	brightness byte
+	on         bool
+	mutex      *sync.Mutex
+	gobot.Commander
+}
+
+// NewTM1637Driver return a new TM1637Driver given a DigitalWriter and the
+// clock (CLK) and data (DIO) pins.
+//
+// Adds the following API Commands:
+//	"SetBrightness" - See TM1637Driver.SetBrightness
+//	"Number" - See TM1637Driver.Number
+//	"Time" - See TM1637Driver.Time
+//	"SetColon" - See TM1637Driver.SetColon
+//	"Clear" - See TM1637Driver.Clear
+func NewTM1637Driver(a DigitalWriter, clockPin string, dataPin string) *TM1637Driver {
+	d := &TM1637Driver{
+		name:       gobot.DefaultName("TM1637"),
+		connection: a,
+		pinClock:   clockPin,
+		pinData:    dataPin,
+		brightness: 7,
+		on:         true,
+		mutex:      &sync.Mutex{},
+		Commander:  gobot.NewCommander(),
+	}
+
+	d.AddCommand("SetBrightness", func(params map[string]interface{}) interface{} {
+		return d.SetBrightness(byte(params["level"].(float64)))
+	})
+
+	d.AddCommand("Number", func(params map[string]interface{}) interface{} {
+		return d.Number(int(params["number"].(float64)))
+	})
+
+	d.AddCommand("Time", func(params map[string]interface{}) interface{} {
+		return d.Time(int(params["hours"].(float64)), int(params["minutes"].(float64)))
+	})
+
+	d.AddCommand("SetColon", func(params map[string]interface{}) interface{} {
+		return d.SetColon(params["on"].(bool))
+	})
+
+	d.AddCommand("Clear", func(params map[string]interface{}) interface{} {
+		return d.Clear()
+	})
+
+	return d
+}
+
+// Name returns the TM1637Drivers name
+func (d *TM1637Driver) Name() string { return d.name }
+
+// SetName sets the TM1637Drivers name
+func (d *TM1637Driver) SetName(n string) { d.name = n }
+
+// Connection returns the TM1637Drivers Connection
+func (d *TM1637Driver) Connection() gobot.Connection {
+	return d.connection.(gobot.Connection)
+}
+
+// Start clears the display and turns it on
+func (d *TM1637Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err = d.connection.DigitalWrite(d.pinClock, 1); err != nil {
+		return
+	}
+	if err = d.connection.DigitalWrite(d.pinData, 1); err != nil {
+		return
+	}
+	d.digits = [4]byte{}
+	d.colon = false
+	d.on = true
+	if err = d.display(); err != nil {
+		return
+	}
+	return d.control()
+}
+
+// Halt clears the display and turns it off
+func (d *TM1637Driver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.digits = [4]byte{}
+	d.colon = false
+	if err = d.display(); err != nil {
+		return
+	}
+	d.on = false
+	return d.control()
+}
+
+// SetBrightness sets the brightness of the display, from 0 to 7
+func (d *TM1637Driver) SetBrightness(level byte) error {
+	if level > 7 {
+		level = 7
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.brightness = level
+	return d.control()
+}
+
+// On turns the display on, showing its digits again
+func (d *TM1637Driver) On() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.on = true
+	return d.control()
+}
+
+// Off turns the display off, keeping its digits
+func (d *TM1637Driver) Off() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.on = false
+	return d.control()
+}
+
+// SetSegments sets the segments of the digit at position, from 0 on the
+// left, the TM1637Segment constants being combined
+func (d *TM1637Driver) SetSegments(position int, segments byte) error {
+	if position < 0 || position >= len(d.digits) {
+		return fmt.Errorf("Invalid TM1637 digit position %d", position)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.digits[position] = segments
+	return d.display()
+}
+
+// Segments returns the segments of the digit at position, without the colon
+func (d *TM1637Driver) Segments(position int) byte {
+	if position < 0 || position >= len(d.digits) {
+		return 0
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.digits[position]
+}
+
+// SetColon turns the colon on or off
+func (d *TM1637Driver) SetColon(on bool) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.colon = on
+	return d.display()
+}
+
+// Clear turns off all the segments of the display, and the colon
+func (d *TM1637Driver) Clear() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.digits = [4]byte{}
+	d.colon = false
+	return d.display()
+}
+
+// Number shows n, from -999 to 9999, on the right of the display
+func (d *TM1637Driver) Number(n int) error {
+	if n < -999 || n > 9999 {
+		return fmt.Errorf("TM1637 number %d out of range", n)
+	}
+	digits := [4]byte{}
+	negative := n < 0
+	if negative {
+		n = -n
+	}
+	i := len(digits) - 1
+	for {
+		digits[i] = TM1637Digits[n%10]
+		n /= 10
+		i--
+		if n == 0 {
+			break
+		}
+	}
+	if negative {
+		digits[i] = tm1637Minus
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.digits = digits
+	return d.display()
+}
+
+// Time shows hours and minutes, with leading zeros, separated by the colon
+func (d *TM1637Driver) Time(hours int, minutes int) error {
+	if hours < 0 || hours > 99 || minutes < 0 || minutes > 59 {
+		return fmt.Errorf("Invalid TM1637 time %d:%d", hours, minutes)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.digits = [4]byte{
+		TM1637Digits[hours/10],
+		TM1637Digits[hours%10],
+		TM1637Digits[minutes/10],
+		TM1637Digits[minutes%10],
+	}
+	d.colon = true
+	return d.display()
+}
+
+// display writes the digits to the display registers from the first
+// address, the mutex being held
+func (d *TM1637Driver) display() error {
+	data := d.digits
+	if d.colon {
+		data[1] |= TM1637SegmentDP
+	}
+	if err := d.send(TM1637DataCommand); err != nil {
+		return err
+	}
+	return d.send(append([]byte{TM1637AddressCommand}, data[:]...)...)
+}
+
+// control writes the brightness and whether the display is on, the mutex
+// being held
+func (d *TM1637Driver) control() error {
+	if d.on {
+		return d.send(TM1637DisplayOn | d.brightness)
+	}
+	return d.send(TM1637DisplayOff)
+}
+
+// send writes the bytes between a start and a stop condition
+func (d *TM1637Driver) send(data ...byte) (err error) {
+	if err = d.connection.DigitalWrite(d.pinData, 0); err != nil {
+		return
+	}
+	for _, b := range data {
+		if err = d.writeByte(b); err != nil {
+			return
+		}
+	}
+	if err = d.connection.DigitalWrite(d.pinClock, 0); err != nil {
+		return
+	}
+	if err = d.connection.DigitalWrite(d.pinData, 0); err != nil {
+		return
+	}
+	if err = d.connection.DigitalWrite(d.pinClock, 1); err != nil {
+		return
+	}
+	return d.connection.DigitalWrite(d.pinData, 1)
+}
+
+// writeByte writes the bits of b to the data pin, least significant first,
+// then clocks the acknowledgment of the TM1637, which is not read back. The
+// data pin is kept low meanwhile, as the TM1637 pulls it low to acknowledge.
+func (d *TM1637Driver) writeByte(b byte) (err error) {
+	for i := uint(0); i < 8; i++ {
+		if err = d.connection.DigitalWrite(d.pinClock, 0); err != nil {
+			return
+		}
+		if err = d.connection.DigitalWrite(d.pinData, (b>>i)&1); err != nil {
+			return
+		}
+		if err = d.connection.DigitalWrite(d.pinClock, 1); err != nil {
+			return
+		}
+	}
+	if err = d.connection.DigitalWrite(d.pinClock, 0); err != nil {
+		return
+	}
+	if err = d.connection.DigitalWrite(d.pinData, 0); err != nil {
+		return
+	}
+	if err = d.connection.DigitalWrite(d.pinClock, 1); err != nil {
+		return
+	}
+	return d.connection.DigitalWrite(d.pinClock, 0)
+}
@@ -0,0 +1,172 @@
+package gpio
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*TM1637Driver)(nil)
+
+// tm1637TestAdaptor decodes the frames written to a TM1637 between the start
+// and stop conditions
+type tm1637TestAdaptor struct {
+	gpioTestBareAdaptor
+	mtx      sync.Mutex
+	clock    byte
+	data     byte
+	bits     []byte
+	frames   [][]byte
+	writeErr error
+}
+
+func (t *tm1637TestAdaptor) DigitalWrite(pin string, level byte) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.writeErr != nil {
+		return t.writeErr
+	}
+	switch pin {
+	case "clk":
+		if t.clock == 0 && level == 1 {
+			t.bits = append(t.bits, t.data)
+		}
+		t.clock = level
+	case "dio":
+		if t.clock == 1 && t.data == 1 && level == 0 {
+			t.bits = nil
+		}
+		if t.clock == 1 && t.data == 0 && level == 1 {
+			// 8 bits, least significant first, then the acknowledgment
+			frame := []byte{}
+			for i := 0; i+9 <= len(t.bits); i += 9 {
+				var b byte
+				for j := 0; j < 8; j++ {
+					b |= t.bits[i+j] << uint(j)
+				}
+				frame = append(frame, b)
+			}
+			t.frames = append(t.frames, frame)
+		}
+		t.data = level
+	}
+	return nil
+}
+
+func (t *tm1637TestAdaptor) lastFrame() []byte {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.frames[len(t.frames)-1]
+}
+
+// lastDigits returns the data of the last address command
+func (t *tm1637TestAdaptor) lastDigits() []byte {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for i := len(t.frames) - 1; i >= 0; i-- {
+		if t.frames[i][0] == TM1637AddressCommand {
+			return t.frames[i][1:]
+		}
+	}
+	return nil
+}
+
+func initTestTM1637Driver() (*TM1637Driver, *tm1637TestAdaptor) {
+	a := &tm1637TestAdaptor{clock: 1, data: 1}
+	return NewTM1637Driver(a, "clk", "dio"), a
+}
+
+func TestTM1637DriverDefaultName(t *testing.T) {
+	d, _ := initTestTM1637Driver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "TM1637"), true)
+}
+
+func TestTM1637DriverSetName(t *testing.T) {
+	d, _ := initTestTM1637Driver()
+	d.SetName("mybot")
+	gobottest.Assert(t, d.Name(), "mybot")
+}
+
+func TestTM1637DriverStart(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, a.frames[0], []byte{TM1637DataCommand})
+	gobottest.Assert(t, a.lastDigits(), []byte{0, 0, 0, 0})
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOn | 7})
+}
+
+func TestTM1637DriverStartError(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	a.writeErr = errors.New("write error")
+	gobottest.Assert(t, d.Start(), errors.New("write error"))
+}
+
+func TestTM1637DriverHalt(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	d.Number(42)
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0, 0, 0, 0})
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOff})
+}
+
+func TestTM1637DriverBrightness(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	gobottest.Assert(t, d.SetBrightness(2), nil)
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOn | 2})
+	gobottest.Assert(t, d.SetBrightness(10), nil)
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOn | 7})
+	gobottest.Assert(t, d.Off(), nil)
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOff})
+	gobottest.Assert(t, d.On(), nil)
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOn | 7})
+	gobottest.Assert(t, d.Command("SetBrightness")(map[string]interface{}{"level": 1.0}), nil)
+	gobottest.Assert(t, a.lastFrame(), []byte{TM1637DisplayOn | 1})
+}
+
+func TestTM1637DriverNumber(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	gobottest.Assert(t, d.Number(1234), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0x06, 0x5B, 0x4F, 0x66})
+	gobottest.Assert(t, d.Number(0), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0, 0, 0, 0x3F})
+	gobottest.Assert(t, d.Number(-42), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0, TM1637SegmentG, 0x66, 0x5B})
+	gobottest.Assert(t, d.Number(-999), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{TM1637SegmentG, 0x6F, 0x6F, 0x6F})
+	gobottest.Refute(t, d.Number(10000), nil)
+	gobottest.Refute(t, d.Number(-1000), nil)
+	gobottest.Assert(t, d.Command("Number")(map[string]interface{}{"number": 7.0}), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0, 0, 0, 0x07})
+}
+
+func TestTM1637DriverTime(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	gobottest.Assert(t, d.Time(9, 5), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0x3F, 0x6F | TM1637SegmentDP, 0x3F, 0x6D})
+	gobottest.Assert(t, d.SetColon(false), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0x3F, 0x6F, 0x3F, 0x6D})
+	gobottest.Refute(t, d.Time(9, 60), nil)
+	gobottest.Refute(t, d.Time(-1, 0), nil)
+	gobottest.Assert(t, d.Command("Time")(map[string]interface{}{"hours": 12.0, "minutes": 30.0}), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0x06, 0x5B | TM1637SegmentDP, 0x4F, 0x3F})
+	gobottest.Assert(t, d.Command("SetColon")(map[string]interface{}{"on": false}), nil)
+	gobottest.Assert(t, d.Command("Clear")(map[string]interface{}{}), nil)
+	gobottest.Assert(t, a.lastDigits(), []byte{0, 0, 0, 0})
+}
+
+func TestTM1637DriverSegments(t *testing.T) {
+	d, a := initTestTM1637Driver()
+	gobottest.Assert(t, d.SetSegments(3, TM1637SegmentA|TM1637SegmentD), nil)
+	gobottest.Assert(t, d.Segments(3), byte(0x09))
+	gobottest.Assert(t, d.Segments(4), byte(0))
+	gobottest.Assert(t, a.lastDigits(), []byte{0, 0, 0, 0x09})
+	d.SetColon(true)
+	gobottest.Assert(t, d.Segments(1), byte(0))
+	gobottest.Refute(t, d.SetSegments(-1, 0), nil)
+	gobottest.Assert(t, d.Clear(), nil)
+	gobottest.Assert(t, d.Segments(3), byte(0))
+}
@@ -2,8 +2,8 @@ package i2c
 
 import (
 	"errors"
-	"log"
 	"math"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -35,14 +35,15 @@ type AdafruitMotorHatDriver struct {
 	connector          Connector
 	motorHatConnection Connection
 	servoHatConnection Connection
+	mutex              *sync.Mutex
 	Config
+	driverConfig
 	gobot.Commander
 	dcMotors      []adaFruitDCMotor
 	stepperMotors []adaFruitStepperMotor
+	logger        gobot.Logger
 }
 
-var adafruitDebug = false // Set this to true to see debug output
-
 var (
 	// Each Adafruit HAT must have a unique I2C address. The default address for
 	// the DC and Stepper Motor HAT is 0x60. The addresses of the Motor HATs can
@@ -127,11 +128,13 @@ func NewAdafruitMotorHatDriver(conn Connector, options ...func(Config)) *Adafrui
 		name:          gobot.DefaultName("AdafruitMotorHat"),
 		connector:     conn,
 		Config:        NewConfig(),
+		mutex:         &sync.Mutex{},
 		Commander:     gobot.NewCommander(),
 		dcMotors:      dc,
 		stepperMotors: st,
 	}
 
+	driver.driverConfig = driverConfig{config: &driver.Config}
 	for _, option := range options {
 		option(driver)
 	}
@@ -163,6 +166,17 @@ func (a *AdafruitMotorHatDriver) SetName(n string) { a.name = n }
 // Connection identifies the particular adapter object
 func (a *AdafruitMotorHatDriver) Connection() gobot.Connection { return a.connector.(gobot.Connection) }
 
+// Logger returns the driver Logger
+func (a *AdafruitMotorHatDriver) Logger() gobot.Logger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return gobot.DefaultLogger().WithComponent(a.name)
+}
+
+// SetLogger sets the driver Logger
+func (a *AdafruitMotorHatDriver) SetLogger(l gobot.Logger) { a.logger = l }
+
 func (a *AdafruitMotorHatDriver) startDriver(connection Connection) (err error) {
 	if err = a.setAllPWM(connection, 0, 0); err != nil {
 		return
@@ -199,6 +213,9 @@ func (a *AdafruitMotorHatDriver) startDriver(connection Connection) (err error)
 
 // Start initializes both I2C-addressable Adafruit Motor HAT drivers
 func (a *AdafruitMotorHatDriver) Start() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	bus := a.GetBusOrDefault(a.connector.GetDefaultBus())
 
 	if a.servoHatConnection, err = a.connector.GetConnection(servoHatAddress, bus); err != nil {
@@ -242,6 +259,9 @@ func (a *AdafruitMotorHatDriver) setPWM(conn Connection, pin byte, on, off int32
 
 // SetServoMotorFreq sets the frequency for the currently addressed PWM Servo HAT.
 func (a *AdafruitMotorHatDriver) SetServoMotorFreq(freq float64) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	if err = a.setPWMFreq(a.servoHatConnection, freq); err != nil {
 		return
 	}
@@ -251,6 +271,9 @@ func (a *AdafruitMotorHatDriver) SetServoMotorFreq(freq float64) (err error) {
 // SetServoMotorPulse is a convenience function to specify the 'tick' value,
 // between 0-4095, when the signal will turn on, and when it will turn off.
 func (a *AdafruitMotorHatDriver) SetServoMotorPulse(channel byte, on, off int32) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	if err = a.setPWM(a.servoHatConnection, channel, on, off); err != nil {
 		return
 	}
@@ -269,11 +292,10 @@ func (a *AdafruitMotorHatDriver) setPWMFreq(conn Connection, freq float64) (err
 	preScaleVal /= freq
 	preScaleVal -= 1.0
 	preScale := math.Floor(preScaleVal + 0.5)
-	if adafruitDebug {
-		log.Printf("Setting PWM frequency to:	%.2f Hz", freq)
-		log.Printf("Estimated pre-scale: 		%.2f", preScaleVal)
-		log.Printf("Final pre-scale: 			%.2f", preScale)
-	}
+	a.Logger().Debug("Setting PWM frequency",
+		"freq", freq,
+		"estimatedPreScale", preScaleVal,
+		"preScale", preScale)
 	// default (and only) reads register 0
 	oldMode := []byte{0}
 	_, err = conn.Read(oldMode)
@@ -333,6 +355,9 @@ func (a *AdafruitMotorHatDriver) setPin(conn Connection, pin byte, value int32)
 // SetDCMotorSpeed will set the appropriate pins to run the specified DC motor
 // for the given speed.
 func (a *AdafruitMotorHatDriver) SetDCMotorSpeed(dcMotor int, speed int32) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	if err = a.setPWM(a.motorHatConnection, a.dcMotors[dcMotor].pwmPin, 0, speed*16); err != nil {
 		return
 	}
@@ -342,6 +367,8 @@ func (a *AdafruitMotorHatDriver) SetDCMotorSpeed(dcMotor int, speed int32) (err
 // RunDCMotor will set the appropriate pins to run the specified DC motor for
 // the given direction
 func (a *AdafruitMotorHatDriver) RunDCMotor(dcMotor int, dir AdafruitDirection) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
 	switch {
 	case dir == AdafruitForward:
@@ -370,6 +397,9 @@ func (a *AdafruitMotorHatDriver) RunDCMotor(dcMotor int, dir AdafruitDirection)
 }
 
 func (a *AdafruitMotorHatDriver) oneStep(motor int, dir AdafruitDirection, style AdafruitStepStyle) (steps int, err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	pwmA := 255
 	pwmB := 255
 
@@ -469,11 +499,10 @@ func (a *AdafruitMotorHatDriver) oneStep(motor int, dir AdafruitDirection, style
 		// step-2-coils is initialized in init()
 		coils = step2coils[(currStep / (stepperMicrosteps / 2))]
 	}
-	if adafruitDebug {
-		log.Printf("[adafruit_driver] currStep: %d, index into step2coils: %d\n",
-			currStep, (currStep / (stepperMicrosteps / 2)))
-		log.Printf("[adafruit_driver] coils state = %v", coils)
-	}
+	a.Logger().Debug("Stepping",
+		"currStep", currStep,
+		"step2coilsIndex", currStep/(stepperMicrosteps/2),
+		"coils", coils)
 	if err = a.setPin(a.motorHatConnection, a.stepperMotors[motor].ain2, coils[0]); err != nil {
 		return
 	}
@@ -491,6 +520,9 @@ func (a *AdafruitMotorHatDriver) oneStep(motor int, dir AdafruitDirection, style
 
 // SetStepperMotorSpeed sets the seconds-per-step for the given Stepper Motor.
 func (a *AdafruitMotorHatDriver) SetStepperMotorSpeed(stepperMotor int, rpm int) (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	revSteps := a.stepperMotors[stepperMotor].revSteps
 	a.stepperMotors[stepperMotor].secPerStep = 60.0 / float64(revSteps*rpm)
 	a.stepperMotors[stepperMotor].stepCounter = 0
@@ -499,7 +531,9 @@ func (a *AdafruitMotorHatDriver) SetStepperMotorSpeed(stepperMotor int, rpm int)
 
 // Step will rotate the stepper motor the given number of steps, in the given direction and step style.
 func (a *AdafruitMotorHatDriver) Step(motor, steps int, dir AdafruitDirection, style AdafruitStepStyle) (err error) {
+	a.mutex.Lock()
 	secPerStep := a.stepperMotors[motor].secPerStep
+	a.mutex.Unlock()
 	latestStep := 0
 	if style == AdafruitInterleave {
 		secPerStep = secPerStep / 2.0
@@ -508,9 +542,7 @@ func (a *AdafruitMotorHatDriver) Step(motor, steps int, dir AdafruitDirection, s
 		secPerStep /= float64(stepperMicrosteps)
 		steps *= stepperMicrosteps
 	}
-	if adafruitDebug {
-		log.Printf("[adafruit_driver] %f seconds per step", secPerStep)
-	}
+	a.Logger().Debug("Step", "secPerStep", secPerStep)
 	for i := 0; i < steps; i++ {
 		if latestStep, err = a.oneStep(motor, dir, style); err != nil {
 			return
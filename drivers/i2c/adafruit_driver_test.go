@@ -240,6 +240,14 @@ func TestAdafruitMotorHatDriverSetName(t *testing.T) {
 	gobottest.Assert(t, d.Name(), "TESTME")
 }
 
+func TestAdafruitMotorHatDriverLogger(t *testing.T) {
+	d := initTestAdafruitMotorHatDriver()
+	gobottest.Refute(t, d.Logger(), nil)
+	l := debugLogger()
+	d.SetLogger(l)
+	gobottest.Assert(t, d.Logger(), l)
+}
+
 func TestAdafruitMotorHatDriverOptions(t *testing.T) {
 	d := NewAdafruitMotorHatDriver(newI2cTestAdaptor(), WithBus(2))
 	gobottest.Assert(t, d.GetBusOrDefault(1), 2)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"strconv"
+	"sync"
 	"time"
 
 	"fmt"
@@ -44,7 +45,9 @@ type ADS1x15Driver struct {
 	converter       func([]byte) float64
 	DefaultGain     int
 	DefaultDataRate int
+	mutex           *sync.Mutex
 	Config
+	driverConfig
 }
 
 // NewADS1015Driver creates a new driver for the ADS1015 (12-bit ADC)
@@ -134,8 +137,10 @@ func newADS1x15Driver(a Connector, options ...func(Config)) *ADS1x15Driver {
 		DefaultGain: 1,
 
 		Config: NewConfig(),
+		mutex:  &sync.Mutex{},
 	}
 
+	l.driverConfig = driverConfig{config: &l.Config}
 	for _, option := range options {
 		option(l)
 	}
@@ -146,10 +151,10 @@ func newADS1x15Driver(a Connector, options ...func(Config)) *ADS1x15Driver {
 
 // Start initializes the sensor
 func (d *ADS1x15Driver) Start() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(ADS1x15DefaultAddress)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	if d.connection, err = d.connector.GetConnection(address, bus); err != nil {
+	if d.connection, err = connect(d, d.connector, d.Config, ADS1x15DefaultAddress); err != nil {
 		return err
 	}
 
@@ -237,6 +242,9 @@ func (d *ADS1x15Driver) ReadDifferenceWithDefaults(diff int) (value float64, err
 // * 2: Channel 1 - channel 3
 // * 3: Channel 2 - channel 3
 func (d *ADS1x15Driver) ReadDifference(diff int, gain int, dataRate int) (value float64, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if err = d.checkChannel(diff); err != nil {
 		return
 	}
@@ -252,6 +260,9 @@ func (d *ADS1x15Driver) ReadWithDefaults(channel int) (value float64, err error)
 
 // Read reads the voltage at the specified channel (between 0 and 3). The result is in V.
 func (d *ADS1x15Driver) Read(channel int, gain int, dataRate int) (value float64, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if err = d.checkChannel(channel); err != nil {
 		return
 	}
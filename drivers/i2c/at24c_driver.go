@@ -0,0 +1,336 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// AT24CDefaultAddress is the I2C address of an AT24C EEPROM with its address
+// pins grounded
+const AT24CDefaultAddress = 0x50
+
+const (
+	// at24cWriteCycle is the longest write cycle of the AT24C family, after
+	// which the EEPROM acknowledges its address again
+	at24cWriteCycle = 10 * time.Millisecond
+	// at24cPollInterval is the interval of the acknowledge polling during a
+	// write cycle
+	at24cPollInterval = time.Millisecond
+	// at24cBlockSize is the memory addressed by the word address of the
+	// EEPROMs addressed with a single byte, the next blocks being selected
+	// by the low bits of the I2C address
+	at24cBlockSize = 256
+)
+
+// AT24CModel is a model of the AT24C family of EEPROMs, which differ in
+// their size, their page size and the length of their word address.
+type AT24CModel struct {
+	// Size of the memory, in bytes
+	Size int
+	// PageSize is the most bytes written at once, within a page
+	PageSize int
+	// AddressBytes is the length of the word address, 1 or 2
+	AddressBytes int
+}
+
+// Models of the AT24C family, and compatible EEPROMs such as the 24LC
+// family of Microchip
+var (
+	AT24C01  = AT24CModel{Size: 128, PageSize: 8, AddressBytes: 1}
+	AT24C02  = AT24CModel{Size: 256, PageSize: 8, AddressBytes: 1}
+	AT24C04  = AT24CModel{Size: 512, PageSize: 16, AddressBytes: 1}
+	AT24C08  = AT24CModel{Size: 1024, PageSize: 16, AddressBytes: 1}
+	AT24C16  = AT24CModel{Size: 2048, PageSize: 16, AddressBytes: 1}
+	AT24C32  = AT24CModel{Size: 4096, PageSize: 32, AddressBytes: 2}
+	AT24C64  = AT24CModel{Size: 8192, PageSize: 32, AddressBytes: 2}
+	AT24C128 = AT24CModel{Size: 16384, PageSize: 64, AddressBytes: 2}
+	AT24C256 = AT24CModel{Size: 32768, PageSize: 64, AddressBytes: 2}
+	AT24C512 = AT24CModel{Size: 65536, PageSize: 128, AddressBytes: 2}
+)
+
+// at24cMagic starts the JSON records of an AT24CDriver
+var at24cMagic = [2]byte{'G', 'J'}
+
+// at24cHeaderSize is the size of the header of a JSON record: the magic, the
+// length of the JSON and its CRC-32
+const at24cHeaderSize = 2 + 2 + 4
+
+var (
+	// ErrAT24COutOfRange is returned when reading or writing beyond the size
+	// of the EEPROM
+	ErrAT24COutOfRange = errors.New("AT24C access out of range")
+	// ErrAT24CWriteTimeout is returned when the EEPROM does not complete its
+	// write cycle in time
+	ErrAT24CWriteTimeout = errors.New("AT24C write cycle did not complete")
+	// ErrAT24CNoRecord is returned by ReadJSON when no JSON record is stored
+	// at the offset, such as on a blank EEPROM
+	ErrAT24CNoRecord = errors.New("No AT24C JSON record")
+	// ErrAT24CCorruptRecord is returned by ReadJSON when the checksum of the
+	// JSON record does not match
+	ErrAT24CCorruptRecord = errors.New("AT24C JSON record is corrupt")
+)
+
+// AT24CDriver is a driver for the AT24C family of I2C EEPROMs, from the 128
+// bytes AT24C01 to the 64KB AT24C512, keeping data such as the identity or
+// the calibration of a robot on its hardware. Reads and writes start at any
+// offset: the writes are split at the pages of the EEPROM, each one waiting
+// for the write cycle of the previous one.
+type AT24CDriver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+
+	model AT24CModel
+	// blocks are the connections to the blocks of 256 bytes of the EEPROMs
+	// selecting them by the I2C address, by block
+	blocks []Connection
+}
+
+// NewAT24CDriver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithAT24CModel(AT24CModel):	model of the EEPROM, AT24C32 by default
+//
+func NewAT24CDriver(c Connector, options ...func(Config)) *AT24CDriver {
+	d := &AT24CDriver{
+		name:      gobot.DefaultName("AT24C"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
+		model:     AT24C32,
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		data := make([]byte, int(params["length"].(float64)))
+		err := d.ReadAt(data, int(params["offset"].(float64)))
+		return map[string]interface{}{"data": data, "err": err}
+	})
+	d.AddCommand("Write", func(params map[string]interface{}) interface{} {
+		values := params["data"].([]interface{})
+		data := make([]byte, len(values))
+		for i, v := range values {
+			data[i] = byte(v.(float64))
+		}
+		return d.WriteAt(data, int(params["offset"].(float64)))
+	})
+
+	return d
+}
+
+// WithAT24CModel option sets the model of the EEPROM, AT24C32 by default.
+func WithAT24CModel(model AT24CModel) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*AT24CDriver); ok {
+			d.model = model
+		}
+	}
+}
+
+// Name returns the name of the device.
+func (d *AT24CDriver) Name() string { return d.name }
+
+// SetName sets the name of the device.
+func (d *AT24CDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device.
+func (d *AT24CDriver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Model returns the model of the EEPROM.
+func (d *AT24CDriver) Model() AT24CModel { return d.model }
+
+// Size returns the size of the EEPROM, in bytes.
+func (d *AT24CDriver) Size() int { return d.model.Size }
+
+// Start connects to the EEPROM, and to each of its blocks for the models
+// addressing them by the I2C address.
+func (d *AT24CDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.connection, err = connect(d, d.connector, d.Config, AT24CDefaultAddress); err != nil {
+		return err
+	}
+	d.blocks = []Connection{d.connection}
+	if d.model.AddressBytes == 1 {
+		bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
+		address := d.GetAddressOrDefault(AT24CDefaultAddress)
+		for block := 1; block*at24cBlockSize < d.model.Size; block++ {
+			conn, err := d.connector.GetConnection(address|block, bus)
+			if err != nil {
+				return err
+			}
+			d.blocks = append(d.blocks, conn)
+		}
+	}
+	return nil
+}
+
+// Halt does nothing, the EEPROM keeping its data.
+func (d *AT24CDriver) Halt() (err error) { return }
+
+// ReadAt reads len(b) bytes from offset into b.
+func (d *AT24CDriver) ReadAt(b []byte, offset int) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.readAt(b, offset)
+}
+
+// WriteAt writes b at offset, page by page, waiting for the end of the write
+// cycle of each page. The EEPROM is left partially written when it fails.
+func (d *AT24CDriver) WriteAt(b []byte, offset int) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.writeAt(b, offset)
+}
+
+// WriteJSON stores v as a JSON record at offset, with its length and
+// checksum, for ReadJSON to read it back. The record takes 8 bytes more than
+// the JSON encoding of v.
+func (d *AT24CDriver) WriteJSON(offset int, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > 0xFFFF {
+		return ErrAT24COutOfRange
+	}
+	record := make([]byte, at24cHeaderSize+len(data))
+	copy(record, at24cMagic[:])
+	binary.BigEndian.PutUint16(record[2:], uint16(len(data)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(data))
+	copy(record[at24cHeaderSize:], data)
+	return d.WriteAt(record, offset)
+}
+
+// ReadJSON reads the JSON record stored at offset by WriteJSON into v. It
+// returns ErrAT24CNoRecord when there is none, and ErrAT24CCorruptRecord when
+// its checksum does not match, such as after a write interrupted by a power
+// loss.
+func (d *AT24CDriver) ReadJSON(offset int, v interface{}) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	header := make([]byte, at24cHeaderSize)
+	if err := d.readAt(header, offset); err != nil {
+		return err
+	}
+	if header[0] != at24cMagic[0] || header[1] != at24cMagic[1] {
+		return ErrAT24CNoRecord
+	}
+	data := make([]byte, binary.BigEndian.Uint16(header[2:]))
+	if err := d.readAt(data, offset+at24cHeaderSize); err != nil {
+		if err == ErrAT24COutOfRange {
+			return ErrAT24CCorruptRecord
+		}
+		return err
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:]) {
+		return ErrAT24CCorruptRecord
+	}
+	return json.Unmarshal(data, v)
+}
+
+// readAt reads b from offset, the mutex must be held. The reads are split at
+// the blocks of the EEPROM, the memory being read sequentially within them.
+func (d *AT24CDriver) readAt(b []byte, offset int) error {
+	if err := d.checkRange(len(b), offset); err != nil {
+		return err
+	}
+	for len(b) > 0 {
+		conn, address := d.address(offset)
+		n := len(b)
+		if end := at24cBlockSize - offset%at24cBlockSize; d.model.AddressBytes == 1 && n > end {
+			n = end
+		}
+		if _, err := conn.Write(address); err != nil {
+			return err
+		}
+		read, err := conn.Read(b[:n])
+		if err != nil {
+			return err
+		}
+		if read != n {
+			return ErrNotEnoughBytes
+		}
+		b, offset = b[n:], offset+n
+	}
+	return nil
+}
+
+// writeAt writes b at offset, the mutex must be held. The writes are split at
+// the pages of the EEPROM, a write beyond a page wrapping around to its start.
+func (d *AT24CDriver) writeAt(b []byte, offset int) error {
+	if err := d.checkRange(len(b), offset); err != nil {
+		return err
+	}
+	for len(b) > 0 {
+		conn, address := d.address(offset)
+		n := len(b)
+		if end := d.model.PageSize - offset%d.model.PageSize; n > end {
+			n = end
+		}
+		if _, err := conn.Write(append(address, b[:n]...)); err != nil {
+			return err
+		}
+		if err := d.waitWriteCycle(conn, address); err != nil {
+			return err
+		}
+		b, offset = b[n:], offset+n
+	}
+	return nil
+}
+
+// waitWriteCycle polls the EEPROM until it acknowledges its address again,
+// at the end of its write cycle
+func (d *AT24CDriver) waitWriteCycle(conn Connection, address []byte) error {
+	clock := gobot.DefaultClock()
+	deadline := clock.Now().Add(at24cWriteCycle)
+	for {
+		<-clock.After(at24cPollInterval)
+		// writing the word address alone writes nothing
+		if _, err := conn.Write(address); err == nil {
+			return nil
+		}
+		if !clock.Now().Before(deadline) {
+			return ErrAT24CWriteTimeout
+		}
+	}
+}
+
+// address returns the connection and the word address of offset
+func (d *AT24CDriver) address(offset int) (Connection, []byte) {
+	if d.model.AddressBytes == 2 {
+		return d.connection, []byte{byte(offset >> 8), byte(offset)}
+	}
+	return d.blocks[offset/at24cBlockSize], []byte{byte(offset)}
+}
+
+func (d *AT24CDriver) checkRange(length int, offset int) error {
+	if d.connection == nil {
+		return ErrNotConnected
+	}
+	if offset < 0 || offset+length > d.model.Size {
+		return ErrAT24COutOfRange
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*AT24CDriver)(nil)
+
+// at24cTestEEPROM simulates an AT24C EEPROM: its memory, its word address
+// and its write cycles, during which it does not acknowledge its address
+type at24cTestEEPROM struct {
+	model   AT24CModel
+	mem     []byte
+	pointer int
+	// busy is the number of polls the write cycles last
+	busy      int
+	remaining int
+	writes    int
+	// writeErr fails the writes of data
+	writeErr error
+}
+
+func newAT24CTestEEPROM(model AT24CModel) *at24cTestEEPROM {
+	mem := make([]byte, model.Size)
+	for i := range mem {
+		mem[i] = 0xFF
+	}
+	return &at24cTestEEPROM{model: model, mem: mem, busy: 2}
+}
+
+func (e *at24cTestEEPROM) GetConnection(address int, bus int) (Connection, error) {
+	block := address - AT24CDefaultAddress
+	if block < 0 || (block > 0 && (e.model.AddressBytes == 2 || block*at24cBlockSize >= e.model.Size)) {
+		return nil, errors.New("no device at address")
+	}
+	return &at24cTestConnection{eeprom: e, block: block}, nil
+}
+
+func (e *at24cTestEEPROM) GetDefaultBus() int { return 1 }
+
+type at24cTestConnection struct {
+	i2cTestAdaptor
+	eeprom *at24cTestEEPROM
+	block  int
+}
+
+func (c *at24cTestConnection) Write(b []byte) (int, error) {
+	e := c.eeprom
+	if e.remaining > 0 {
+		e.remaining--
+		return 0, errors.New("NACK")
+	}
+	offset := c.block * at24cBlockSize
+	if e.model.AddressBytes == 2 {
+		offset += int(b[0])<<8 | int(b[1])
+	} else {
+		offset += int(b[0])
+	}
+	data := b[e.model.AddressBytes:]
+	e.pointer = offset
+	if len(data) == 0 {
+		return len(b), nil
+	}
+	if e.writeErr != nil {
+		return 0, e.writeErr
+	}
+	// the writes wrap around within the page
+	page := offset - offset%e.model.PageSize
+	for i, v := range data {
+		e.mem[page+(offset-page+i)%e.model.PageSize] = v
+	}
+	e.writes++
+	e.remaining = e.busy
+	return len(b), nil
+}
+
+func (c *at24cTestConnection) Read(b []byte) (int, error) {
+	e := c.eeprom
+	for i := range b {
+		b[i] = e.mem[(e.pointer+i)%len(e.mem)]
+	}
+	e.pointer += len(b)
+	return len(b), nil
+}
+
+func initTestAT24CDriver(model AT24CModel) (*AT24CDriver, *at24cTestEEPROM) {
+	e := newAT24CTestEEPROM(model)
+	d := NewAT24CDriver(e, WithAT24CModel(model))
+	if err := d.Start(); err != nil {
+		panic(err)
+	}
+	return d, e
+}
+
+func TestAT24CDriver(t *testing.T) {
+	d := NewAT24CDriver(newI2cTestAdaptor())
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "AT24C"), true)
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, d.Model(), AT24C32)
+	gobottest.Assert(t, d.Size(), 4096)
+	gobottest.Refute(t, d.Command("Read"), nil)
+	gobottest.Refute(t, d.Command("Write"), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+
+	d.SetName("identity")
+	gobottest.Assert(t, d.Name(), "identity")
+	gobottest.Assert(t, d.ReadAt(make([]byte, 1), 0), ErrNotConnected)
+}
+
+func TestAT24CDriverWriteAtPages(t *testing.T) {
+	d, e := initTestAT24CDriver(AT24C32)
+	data := []byte("gobot robots are fun to build and to program")
+
+	// 40 to 84 crosses the pages at 64
+	gobottest.Assert(t, d.WriteAt(data, 40), nil)
+	gobottest.Assert(t, e.writes, 2)
+	gobottest.Assert(t, string(e.mem[40:40+len(data)]), string(data))
+	gobottest.Assert(t, e.mem[39], byte(0xFF))
+
+	b := make([]byte, len(data))
+	gobottest.Assert(t, d.ReadAt(b, 40), nil)
+	gobottest.Assert(t, string(b), string(data))
+}
+
+func TestAT24CDriverBlocks(t *testing.T) {
+	d, e := initTestAT24CDriver(AT24C04)
+	data := make([]byte, 40)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	// 240 to 280 crosses the block at 256, in pages of 16
+	gobottest.Assert(t, d.WriteAt(data, 240), nil)
+	gobottest.Assert(t, e.writes, 3)
+	gobottest.Assert(t, e.mem[256], byte(16))
+
+	b := make([]byte, 40)
+	gobottest.Assert(t, d.ReadAt(b, 240), nil)
+	gobottest.Assert(t, b, data)
+}
+
+func TestAT24CDriverOutOfRange(t *testing.T) {
+	d, e := initTestAT24CDriver(AT24C02)
+	gobottest.Assert(t, d.WriteAt([]byte{1, 2}, 255), ErrAT24COutOfRange)
+	gobottest.Assert(t, d.ReadAt(make([]byte, 1), -1), ErrAT24COutOfRange)
+	gobottest.Assert(t, e.writes, 0)
+}
+
+func TestAT24CDriverWriteTimeout(t *testing.T) {
+	d, e := initTestAT24CDriver(AT24C32)
+	e.busy = 100
+	gobottest.Assert(t, d.WriteAt([]byte{1}, 0), ErrAT24CWriteTimeout)
+}
+
+func TestAT24CDriverWriteError(t *testing.T) {
+	d, e := initTestAT24CDriver(AT24C32)
+	e.writeErr = errors.New("write error")
+	gobottest.Assert(t, d.WriteAt([]byte{1}, 0), e.writeErr)
+}
+
+func TestAT24CDriverStartError(t *testing.T) {
+	// the blocks of an AT24C16 beyond the ones of an AT24C04 do not answer
+	e := newAT24CTestEEPROM(AT24C04)
+	d := NewAT24CDriver(e, WithAT24CModel(AT24C16))
+	gobottest.Refute(t, d.Start(), nil)
+}
+
+func TestAT24CDriverJSON(t *testing.T) {
+	type identity struct {
+		Name   string  `json:"name"`
+		Serial int     `json:"serial"`
+		Offset float64 `json:"offset"`
+	}
+	d, e := initTestAT24CDriver(AT24C32)
+
+	var id identity
+	gobottest.Assert(t, d.ReadJSON(100, &id), ErrAT24CNoRecord)
+
+	gobottest.Assert(t, d.WriteJSON(100, identity{Name: "gort", Serial: 42, Offset: -1.5}), nil)
+	gobottest.Assert(t, string(e.mem[100:102]), "GJ")
+	gobottest.Assert(t, d.ReadJSON(100, &id), nil)
+	gobottest.Assert(t, id, identity{Name: "gort", Serial: 42, Offset: -1.5})
+
+	e.mem[110] ^= 0x01
+	gobottest.Assert(t, d.ReadJSON(100, &id), ErrAT24CCorruptRecord)
+
+	// a header, then a JSON, beyond the end of the EEPROM
+	e.mem[4094], e.mem[4095] = 'G', 'J'
+	gobottest.Assert(t, d.ReadJSON(4094, &id), ErrAT24COutOfRange)
+	copy(e.mem[4000:], []byte{'G', 'J', 0xFF, 0xFF, 0, 0, 0, 0})
+	gobottest.Assert(t, d.ReadJSON(4000, &id), ErrAT24CCorruptRecord)
+
+	gobottest.Refute(t, d.WriteJSON(0, func() {}), nil)
+}
+
+func TestAT24CDriverCommands(t *testing.T) {
+	d, e := initTestAT24CDriver(AT24C32)
+	gobottest.Assert(t, d.Command("Write")(map[string]interface{}{
+		"offset": 10.0, "data": []interface{}{1.0, 2.0, 3.0},
+	}), nil)
+	gobottest.Assert(t, e.mem[10:13], []byte{1, 2, 3})
+
+	result := d.Command("Read")(map[string]interface{}{"offset": 10.0, "length": 2.0}).(map[string]interface{})
+	gobottest.Assert(t, result["data"], []byte{1, 2})
+	gobottest.Assert(t, result["err"], nil)
+}
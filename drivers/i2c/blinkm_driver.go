@@ -2,6 +2,7 @@ package i2c
 
 import (
 	"fmt"
+	"sync"
 
 	"gobot.io/x/gobot"
 )
@@ -13,7 +14,9 @@ type BlinkMDriver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
 	gobot.Commander
 }
 
@@ -32,8 +35,10 @@ func NewBlinkMDriver(a Connector, options ...func(Config)) *BlinkMDriver {
 		Commander: gobot.NewCommander(),
 		connector: a,
 		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
 	}
 
+	b.driverConfig = driverConfig{config: &b.Config}
 	for _, option := range options {
 		option(b)
 	}
@@ -76,10 +81,10 @@ func (b *BlinkMDriver) Connection() gobot.Connection { return b.connection.(gobo
 
 // Start starts the Driver up, and writes start command
 func (b *BlinkMDriver) Start() (err error) {
-	bus := b.GetBusOrDefault(b.connector.GetDefaultBus())
-	address := b.GetAddressOrDefault(blinkmAddress)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
 
-	b.connection, err = b.connector.GetConnection(address, bus)
+	b.connection, err = connect(b, b.connector, b.Config, blinkmAddress)
 	if err != nil {
 		return
 	}
@@ -95,6 +100,9 @@ func (b *BlinkMDriver) Halt() (err error) { return }
 
 // Rgb sets color using r,g,b params
 func (b *BlinkMDriver) Rgb(red byte, green byte, blue byte) (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
 	if _, err = b.connection.Write([]byte("n")); err != nil {
 		return
 	}
@@ -104,6 +112,9 @@ func (b *BlinkMDriver) Rgb(red byte, green byte, blue byte) (err error) {
 
 // Fade removes color using r,g,b params
 func (b *BlinkMDriver) Fade(red byte, green byte, blue byte) (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
 	if _, err = b.connection.Write([]byte("c")); err != nil {
 		return
 	}
@@ -113,6 +124,9 @@ func (b *BlinkMDriver) Fade(red byte, green byte, blue byte) (err error) {
 
 // FirmwareVersion returns version with MAYOR.minor format
 func (b *BlinkMDriver) FirmwareVersion() (version string, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
 	if _, err = b.connection.Write([]byte("Z")); err != nil {
 		return
 	}
@@ -126,6 +140,9 @@ func (b *BlinkMDriver) FirmwareVersion() (version string, err error) {
 
 // Color returns an array with current rgb color
 func (b *BlinkMDriver) Color() (color []byte, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
 	if _, err = b.connection.Write([]byte("g")); err != nil {
 		return
 	}
@@ -20,6 +20,13 @@ type bmeHumidityCalibrationCoefficients struct {
 	h6 int8
 }
 
+// bme280Registers are the registers shown by DebugDump
+var bme280Registers = append(RegisterMap{
+	{Name: "ctrl_hum", Address: bme280RegisterControlHumidity, Fields: []RegisterField{
+		{Name: "osrs_h", Shift: 0, Width: 3},
+	}},
+}, bmp280Registers...)
+
 // BME280Driver is a driver for the BME280 temperature/humidity sensor.
 // It implements all of the same functions as the BMP280Driver, but also
 // adds the Humidity() function by reading the BME280's humidity sensor.
@@ -49,16 +56,18 @@ func NewBME280Driver(c Connector, options ...func(Config)) *BME280Driver {
 		option(b)
 	}
 
+	addDebugDumpCommand(b, b)
+
 	// TODO: expose commands to API
 	return b
 }
 
 // Start initializes the BME280 and loads the calibration coefficients.
 func (d *BME280Driver) Start() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(bmp180Address)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	if d.connection, err = d.connector.GetConnection(address, bus); err != nil {
+	if d.connection, err = connect(d, d.connector, d.Config, bmp180Address); err != nil {
 		return err
 	}
 
@@ -73,8 +82,19 @@ func (d *BME280Driver) Start() (err error) {
 	return nil
 }
 
+// DebugDump reads ctrl_hum in addition to the registers dumped for the BMP280.
+func (d *BME280Driver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, bme280Registers)
+}
+
 // Humidity returns the current humidity in percentage of relative humidity
 func (d *BME280Driver) Humidity() (humidity float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	var rawH uint32
 	if rawH, err = d.rawHumidity(); err != nil {
 		return 0.0, err
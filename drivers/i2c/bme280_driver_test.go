@@ -10,6 +10,9 @@ import (
 )
 
 var _ gobot.Driver = (*BME280Driver)(nil)
+var _ gobot.TemperatureSensor = (*BME280Driver)(nil)
+var _ gobot.PressureSensor = (*BME280Driver)(nil)
+var _ gobot.HumiditySensor = (*BME280Driver)(nil)
 
 // --------- HELPERS
 func initTestBME280Driver() (driver *BME280Driver) {
@@ -198,3 +201,19 @@ func TestBME280DriverOptions(t *testing.T) {
 	b := NewBME280Driver(newI2cTestAdaptor(), WithBus(2))
 	gobottest.Assert(t, b.GetBusOrDefault(1), 2)
 }
+
+func TestBME280DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestBME280DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x27
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["ctrl_hum"].(map[string]interface{})["osrs_h"], uint8(7))
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
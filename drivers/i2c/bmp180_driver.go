@@ -3,6 +3,7 @@ package i2c
 import (
 	"bytes"
 	"encoding/binary"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -46,6 +47,16 @@ type calibrationCoefficients struct {
 	md  int16
 }
 
+// bmp180Registers are the registers shown by DebugDump
+var bmp180Registers = RegisterMap{
+	{Name: "id", Address: 0xD0},
+	{Name: "ctrl_meas", Address: bmp180RegisterCtl, Fields: []RegisterField{
+		{Name: "oss", Shift: 6, Width: 2},
+		{Name: "sco", Shift: 5, Width: 1},
+		{Name: "measurement", Shift: 0, Width: 5, Values: map[uint8]string{0x0E: "temperature", 0x14: "pressure"}},
+	}},
+}
+
 // BMP180Driver is the gobot driver for the Bosch pressure sensor BMP180.
 // Device datasheet: https://cdn-shop.adafruit.com/datasheets/BST-BMP180-DS000-09.pdf
 type BMP180Driver struct {
@@ -53,7 +64,10 @@ type BMP180Driver struct {
 	Mode       BMP180OversamplingMode
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 	calibrationCoefficients *calibrationCoefficients
 }
 
@@ -71,13 +85,18 @@ func NewBMP180Driver(c Connector, options ...func(Config)) *BMP180Driver {
 		connector:               c,
 		Mode:                    BMP180UltraLowPower,
 		Config:                  NewConfig(),
+		Commander:               gobot.NewCommander(),
+		mutex:                   &sync.Mutex{},
 		calibrationCoefficients: &calibrationCoefficients{},
 	}
 
+	b.driverConfig = driverConfig{config: &b.Config}
 	for _, option := range options {
 		option(b)
 	}
 
+	addDebugDumpCommand(b, b)
+
 	// TODO: expose commands to API
 	return b
 }
@@ -99,10 +118,10 @@ func (d *BMP180Driver) Connection() gobot.Connection {
 
 // Start initializes the BMP180 and loads the calibration coefficients.
 func (d *BMP180Driver) Start() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(bmp180Address)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	if d.connection, err = d.connector.GetConnection(address, bus); err != nil {
+	if d.connection, err = connect(d, d.connector, d.Config, bmp180Address); err != nil {
 		return err
 	}
 	if err := d.initialization(); err != nil {
@@ -138,8 +157,19 @@ func (d *BMP180Driver) Halt() (err error) {
 	return nil
 }
 
+// DebugDump reads the chip id and ctrl_meas, which holds the oversampling setting and the running measurement.
+func (d *BMP180Driver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, bmp180Registers)
+}
+
 // Temperature returns the current temperature, in celsius degrees.
 func (d *BMP180Driver) Temperature() (temp float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	var rawTemp int16
 	if rawTemp, err = d.rawTemp(); err != nil {
 		return 0, err
@@ -149,6 +179,9 @@ func (d *BMP180Driver) Temperature() (temp float32, err error) {
 
 // Pressure returns the current pressure, in pascals.
 func (d *BMP180Driver) Pressure() (pressure float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	var rawTemp int16
 	var rawPressure int32
 	if rawTemp, err = d.rawTemp(); err != nil {
@@ -12,6 +12,8 @@ import (
 )
 
 var _ gobot.Driver = (*BMP180Driver)(nil)
+var _ gobot.TemperatureSensor = (*BMP180Driver)(nil)
+var _ gobot.PressureSensor = (*BMP180Driver)(nil)
 
 // --------- HELPERS
 func initTestBMP180Driver() (driver *BMP180Driver) {
@@ -192,3 +194,19 @@ func TestBMP180PauseForReading(t *testing.T) {
 	gobottest.Assert(t, pauseForReading(BMP180HighResolution), time.Duration(14*time.Millisecond))
 	gobottest.Assert(t, pauseForReading(BMP180UltraHighResolution), time.Duration(26*time.Millisecond))
 }
+
+func TestBMP180DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestBMP180DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x2E
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["ctrl_meas"].(map[string]interface{})["measurement"], "temperature")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math"
+	"sync"
 
 	"gobot.io/x/gobot"
 )
@@ -32,12 +33,34 @@ type bmp280CalibrationCoefficients struct {
 	p9 int16
 }
 
+// bmp280Registers are the registers shown by DebugDump
+var bmp280Registers = RegisterMap{
+	{Name: "id", Address: 0xD0},
+	{Name: "status", Address: 0xF3, Fields: []RegisterField{
+		{Name: "measuring", Shift: 3, Width: 1},
+		{Name: "im_update", Shift: 0, Width: 1},
+	}},
+	{Name: "ctrl_meas", Address: bmp280RegisterControl, Fields: []RegisterField{
+		{Name: "osrs_t", Shift: 5, Width: 3},
+		{Name: "osrs_p", Shift: 2, Width: 3},
+		{Name: "mode", Shift: 0, Width: 2, Values: map[uint8]string{0: "sleep", 1: "forced", 2: "forced", 3: "normal"}},
+	}},
+	{Name: "config", Address: bmp280RegisterConfig, Fields: []RegisterField{
+		{Name: "t_sb", Shift: 5, Width: 3},
+		{Name: "filter", Shift: 2, Width: 3},
+		{Name: "spi3w_en", Shift: 0, Width: 1},
+	}},
+}
+
 // BMP280Driver is a driver for the BMP280 temperature/pressure sensor
 type BMP280Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 
 	tpc *bmp280CalibrationCoefficients
 }
@@ -55,13 +78,18 @@ func NewBMP280Driver(c Connector, options ...func(Config)) *BMP280Driver {
 		name:      gobot.DefaultName("BMP280"),
 		connector: c,
 		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
 		tpc:       &bmp280CalibrationCoefficients{},
 	}
 
+	b.driverConfig = driverConfig{config: &b.Config}
 	for _, option := range options {
 		option(b)
 	}
 
+	addDebugDumpCommand(b, b)
+
 	// TODO: expose commands to API
 	return b
 }
@@ -83,10 +111,10 @@ func (d *BMP280Driver) Connection() gobot.Connection {
 
 // Start initializes the BMP280 and loads the calibration coefficients.
 func (d *BMP280Driver) Start() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(bmp180Address)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	if d.connection, err = d.connector.GetConnection(address, bus); err != nil {
+	if d.connection, err = connect(d, d.connector, d.Config, bmp180Address); err != nil {
 		return err
 	}
 
@@ -102,8 +130,19 @@ func (d *BMP280Driver) Halt() (err error) {
 	return nil
 }
 
+// DebugDump reads the id, status, ctrl_meas and config registers. The calibration coefficients are not dumped.
+func (d *BMP280Driver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, bmp280Registers)
+}
+
 // Temperature returns the current temperature, in celsius degrees.
 func (d *BMP280Driver) Temperature() (temp float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	var rawT int32
 	if rawT, err = d.rawTemp(); err != nil {
 		return 0.0, err
@@ -114,6 +153,9 @@ func (d *BMP280Driver) Temperature() (temp float32, err error) {
 
 // Pressure returns the current barometric pressure, in Pa
 func (d *BMP280Driver) Pressure() (press float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	var rawT, rawP int32
 	if rawT, err = d.rawTemp(); err != nil {
 		return 0.0, err
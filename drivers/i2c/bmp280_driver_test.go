@@ -10,6 +10,8 @@ import (
 )
 
 var _ gobot.Driver = (*BMP280Driver)(nil)
+var _ gobot.TemperatureSensor = (*BMP280Driver)(nil)
+var _ gobot.PressureSensor = (*BMP280Driver)(nil)
 
 // --------- HELPERS
 func initTestBMP280Driver() (driver *BMP280Driver) {
@@ -156,3 +158,34 @@ func TestBMP280DriverOptions(t *testing.T) {
 	b := NewBMP280Driver(newI2cTestAdaptor(), WithBus(2))
 	gobottest.Assert(t, b.GetBusOrDefault(1), 2)
 }
+
+func TestBMP280DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestBMP280DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x27
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["ctrl_meas"].(map[string]interface{})["mode"], "normal")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
+
+func TestBMP280DriverDebugDumpError(t *testing.T) {
+	d, adaptor := initTestBMP280DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return 0, errors.New("read error")
+	}
+
+	_, err := d.DebugDump()
+	gobottest.Assert(t, err, ErrRegisterRead{Reg: 0xD0, Name: "id", Err: errors.New("read error")})
+	gobottest.Assert(t, err.Error(), "reading register id (0xD0): read error")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, map[string]interface{}{"err": err})
+}
@@ -0,0 +1,519 @@
+package i2c
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// VerticalSpeed event is published by the BMP388Driver with its estimate of
+// the vertical speed, in meters per second, positive upwards
+const VerticalSpeed = "vertical-speed"
+
+const bmp388Address = 0x77
+
+const (
+	bmp388RegisterChipID    = 0x00
+	bmp388RegisterErr       = 0x02
+	bmp388RegisterStatus    = 0x03
+	bmp388RegisterData      = 0x04
+	bmp388RegisterPwrCtrl   = 0x1B
+	bmp388RegisterOSR       = 0x1C
+	bmp388RegisterODR       = 0x1D
+	bmp388RegisterConfig    = 0x1F
+	bmp388RegisterCalib00   = 0x31
+	bmp388RegisterCmd       = 0x7E
+	bmp388ChipIDBMP388      = 0x50
+	bmp388ChipIDBMP390      = 0x60
+	bmp388PwrCtrlNormalMode = 0x33
+	bmp388CmdSoftReset      = 0xB6
+	bmp388SeaLevelPressure  = 1013.25
+)
+
+// The oversampling settings of the pressure and temperature measurements of
+// the BMP388, from the lowest power to the lowest noise
+const (
+	BMP388Oversampling1x uint8 = iota
+	BMP388Oversampling2x
+	BMP388Oversampling4x
+	BMP388Oversampling8x
+	BMP388Oversampling16x
+	BMP388Oversampling32x
+)
+
+// The coefficients of the IIR filter of the BMP388, smoothing the short
+// pressure changes such as the ones of a slammed door or of the wind
+const (
+	BMP388IIRFilterCoef0 uint8 = iota
+	BMP388IIRFilterCoef1
+	BMP388IIRFilterCoef3
+	BMP388IIRFilterCoef7
+	BMP388IIRFilterCoef15
+	BMP388IIRFilterCoef31
+	BMP388IIRFilterCoef63
+	BMP388IIRFilterCoef127
+)
+
+// bmp388CalibrationCoefficients are the compensation parameters of the
+// BMP388, scaled as described in its datasheet
+type bmp388CalibrationCoefficients struct {
+	t1, t2, t3                                   float64
+	p1, p2, p3, p4, p5, p6, p7, p8, p9, p10, p11 float64
+}
+
+// bmp388Registers are the registers shown by DebugDump
+var bmp388Registers = RegisterMap{
+	{Name: "CHIP_ID", Address: bmp388RegisterChipID},
+	{Name: "ERR_REG", Address: bmp388RegisterErr, Fields: []RegisterField{
+		{Name: "conf_err", Shift: 2, Width: 1},
+		{Name: "cmd_err", Shift: 1, Width: 1},
+		{Name: "fatal_err", Shift: 0, Width: 1},
+	}},
+	{Name: "STATUS", Address: bmp388RegisterStatus, Fields: []RegisterField{
+		{Name: "drdy_temp", Shift: 6, Width: 1},
+		{Name: "drdy_press", Shift: 5, Width: 1},
+		{Name: "cmd_rdy", Shift: 4, Width: 1},
+	}},
+	{Name: "PWR_CTRL", Address: bmp388RegisterPwrCtrl, Fields: []RegisterField{
+		{Name: "mode", Shift: 4, Width: 2, Values: map[uint8]string{0: "sleep", 1: "forced", 2: "forced", 3: "normal"}},
+		{Name: "temp_en", Shift: 1, Width: 1},
+		{Name: "press_en", Shift: 0, Width: 1},
+	}},
+	{Name: "OSR", Address: bmp388RegisterOSR, Fields: []RegisterField{
+		{Name: "osr_t", Shift: 3, Width: 3},
+		{Name: "osr_p", Shift: 0, Width: 3},
+	}},
+	{Name: "ODR", Address: bmp388RegisterODR, Fields: []RegisterField{
+		{Name: "odr_sel", Shift: 0, Width: 5},
+	}},
+	{Name: "CONFIG", Address: bmp388RegisterConfig, Fields: []RegisterField{
+		{Name: "iir_filter", Shift: 1, Width: 3},
+	}},
+}
+
+// BMP388Driver is a driver for the BMP388 and BMP390 temperature/pressure
+// sensors. Once started, it polls the sensor to estimate the vertical speed,
+// published with the VerticalSpeed event, for example to detect the climb of
+// a drone or the moves of an elevator.
+type BMP388Driver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+	gobot.Eventer
+
+	tpc                  *bmp388CalibrationCoefficients
+	pressureOversampling uint8
+	tempOversampling     uint8
+	iirFilter            uint8
+	seaLevelPressure     float64
+
+	interval time.Duration
+	polling  gobot.PollerConfig
+	halt     chan bool
+	// smoothing is the time constant of the low pass filter of the
+	// vertical speed
+	smoothing     time.Duration
+	lastAltitude  float64
+	lastTime      time.Time
+	verticalSpeed float64
+}
+
+// NewBMP388Driver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithBMP388PressureOversampling(uint8):	oversampling of the pressure
+//		i2c.WithBMP388TemperatureOversampling(uint8):	oversampling of the temperature
+//		i2c.WithBMP388IIRFilter(uint8):	coefficient of the IIR filter
+//		i2c.WithBMP388VerticalSpeedSmoothing(time.Duration):	time constant of the vertical speed filter
+//
+func NewBMP388Driver(c Connector, options ...func(Config)) *BMP388Driver {
+	b := &BMP388Driver{
+		name:                 gobot.DefaultName("BMP388"),
+		connector:            c,
+		Config:               NewConfig(),
+		Commander:            gobot.NewCommander(),
+		Eventer:              gobot.NewEventer(),
+		mutex:                &sync.Mutex{},
+		tpc:                  &bmp388CalibrationCoefficients{},
+		pressureOversampling: BMP388Oversampling8x,
+		tempOversampling:     BMP388Oversampling1x,
+		iirFilter:            BMP388IIRFilterCoef3,
+		seaLevelPressure:     bmp388SeaLevelPressure,
+		interval:             100 * time.Millisecond,
+		smoothing:            time.Second,
+	}
+
+	b.driverConfig = driverConfig{config: &b.Config}
+	for _, option := range options {
+		option(b)
+	}
+
+	b.AddEvent(VerticalSpeed)
+	b.AddEvent(Error)
+
+	b.AddCommand("Altitude", func(params map[string]interface{}) interface{} {
+		alt, err := b.Altitude()
+		return map[string]interface{}{"altitude": alt, "err": err}
+	})
+	b.AddCommand("SetReferenceAltitude", func(params map[string]interface{}) interface{} {
+		return b.SetReferenceAltitude(float32(params["altitude"].(float64)))
+	})
+	addDebugDumpCommand(b, b)
+
+	return b
+}
+
+// Name returns the name of the device.
+func (d *BMP388Driver) Name() string {
+	return d.name
+}
+
+// SetName sets the name of the device.
+func (d *BMP388Driver) SetName(n string) {
+	d.name = n
+}
+
+// Connection returns the connection of the device.
+func (d *BMP388Driver) Connection() gobot.Connection {
+	return d.connector.(gobot.Connection)
+}
+
+// Start checks the BMP388, loads its calibration coefficients, configures
+// its measurements and starts the polling of the vertical speed.
+func (d *BMP388Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.connection, err = connect(d, d.connector, d.Config, bmp388Address); err != nil {
+		return err
+	}
+	if err = d.initialization(); err != nil {
+		return err
+	}
+
+	config := d.polling
+	config.Interval = d.interval
+	config.MaxInterval = 0
+	if config.Scheduler == nil {
+		config.Scheduler = BusScheduler(d.connector, d.GetBusOrDefault(d.connector.GetDefaultBus()))
+	}
+	d.halt = make(chan bool)
+	d.lastTime = time.Time{}
+	d.verticalSpeed = 0
+	gobot.NewPoller(config).Go(d, d.halt, func() bool {
+		if err := d.poll(); err != nil {
+			d.Publish(d.Event(Error), err)
+		}
+		return true
+	})
+	return nil
+}
+
+// Halt stops the polling of the vertical speed.
+func (d *BMP388Driver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.halt != nil {
+		close(d.halt)
+		d.halt = nil
+	}
+	return nil
+}
+
+// SetPolling configures the polling of the BMP388 estimating the vertical
+// speed, every 100ms by default. The BMP388 is polled at a fixed interval,
+// c.MaxInterval is ignored.
+func (d *BMP388Driver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		d.interval = c.Interval
+	}
+	d.polling = c
+}
+
+// DebugDump reads the chip id, error, status and power, oversampling and filter control registers of the BMP388.
+func (d *BMP388Driver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, bmp388Registers)
+}
+
+// Temperature returns the current temperature, in celsius degrees.
+func (d *BMP388Driver) Temperature() (temp float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	t, _, err := d.measure()
+	return float32(t), err
+}
+
+// Pressure returns the current barometric pressure, in Pa
+func (d *BMP388Driver) Pressure() (press float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	_, p, err := d.measure()
+	return float32(p), err
+}
+
+// Altitude returns the current altitude in meters based on the current
+// barometric pressure and the pressure at sea level, see
+// SetSeaLevelPressure.
+func (d *BMP388Driver) Altitude() (alt float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	a, err := d.altitude()
+	return float32(a), err
+}
+
+// SeaLevelPressure returns the pressure at sea level the altitude is
+// computed from, in hPa
+func (d *BMP388Driver) SeaLevelPressure() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return float32(d.seaLevelPressure)
+}
+
+// SetSeaLevelPressure sets the pressure at sea level the altitude is
+// computed from, in hPa, 1013.25 by default. The local value, given by
+// weather services as QNH, makes the altitude accurate.
+func (d *BMP388Driver) SetSeaLevelPressure(hPa float32) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.seaLevelPressure = float64(hPa)
+}
+
+// SetReferenceAltitude sets the pressure at sea level from the current
+// pressure, the BMP388 being at the known altitude, in meters.
+func (d *BMP388Driver) SetReferenceAltitude(alt float32) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	_, p, err := d.measure()
+	if err != nil {
+		return err
+	}
+	d.seaLevelPressure = p / 100 / math.Pow(1-float64(alt)/44330, 5.255)
+	return nil
+}
+
+// VerticalSpeed returns the latest estimate of the vertical speed, in
+// meters per second, positive upwards
+func (d *BMP388Driver) VerticalSpeed() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return float32(d.verticalSpeed)
+}
+
+// initialization checks the chip ID, resets the BMP388, reads its
+// calibration coefficients and configures its measurements.
+func (d *BMP388Driver) initialization() (err error) {
+	var id []byte
+	if id, err = d.read(bmp388RegisterChipID, 1); err != nil {
+		return err
+	}
+	if id[0] != bmp388ChipIDBMP388 && id[0] != bmp388ChipIDBMP390 {
+		return ErrBadDevice{Device: "BMP388", ID: int(id[0])}
+	}
+	if err = d.connection.WriteByteData(bmp388RegisterCmd, bmp388CmdSoftReset); err != nil {
+		return err
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	var coefficients []byte
+	if coefficients, err = d.read(bmp388RegisterCalib00, 21); err != nil {
+		return err
+	}
+	var raw struct {
+		T1  uint16
+		T2  uint16
+		T3  int8
+		P1  int16
+		P2  int16
+		P3  int8
+		P4  int8
+		P5  uint16
+		P6  uint16
+		P7  int8
+		P8  int8
+		P9  int16
+		P10 int8
+		P11 int8
+	}
+	binary.Read(bytes.NewBuffer(coefficients), binary.LittleEndian, &raw)
+	d.tpc.t1 = float64(raw.T1) * math.Pow(2, 8)
+	d.tpc.t2 = float64(raw.T2) / math.Pow(2, 30)
+	d.tpc.t3 = float64(raw.T3) / math.Pow(2, 48)
+	d.tpc.p1 = (float64(raw.P1) - math.Pow(2, 14)) / math.Pow(2, 20)
+	d.tpc.p2 = (float64(raw.P2) - math.Pow(2, 14)) / math.Pow(2, 29)
+	d.tpc.p3 = float64(raw.P3) / math.Pow(2, 32)
+	d.tpc.p4 = float64(raw.P4) / math.Pow(2, 37)
+	d.tpc.p5 = float64(raw.P5) * math.Pow(2, 3)
+	d.tpc.p6 = float64(raw.P6) / math.Pow(2, 6)
+	d.tpc.p7 = float64(raw.P7) / math.Pow(2, 8)
+	d.tpc.p8 = float64(raw.P8) / math.Pow(2, 15)
+	d.tpc.p9 = float64(raw.P9) / math.Pow(2, 48)
+	d.tpc.p10 = float64(raw.P10) / math.Pow(2, 48)
+	d.tpc.p11 = float64(raw.P11) / math.Pow(2, 65)
+
+	if err = d.connection.WriteByteData(bmp388RegisterOSR, d.tempOversampling<<3|d.pressureOversampling); err != nil {
+		return err
+	}
+	if err = d.connection.WriteByteData(bmp388RegisterODR, d.outputDataRate()); err != nil {
+		return err
+	}
+	if err = d.connection.WriteByteData(bmp388RegisterConfig, d.iirFilter<<1); err != nil {
+		return err
+	}
+	return d.connection.WriteByteData(bmp388RegisterPwrCtrl, bmp388PwrCtrlNormalMode)
+}
+
+// outputDataRate returns the fastest odr_sel setting leaving the time for
+// the measurements at the configured oversampling, as the BMP388 rejects a
+// faster one.
+func (d *BMP388Driver) outputDataRate() uint8 {
+	// measurement time in microseconds, from the datasheet
+	conversion := 234 + 392 + (1<<d.pressureOversampling)*2020 + 163 + (1<<d.tempOversampling)*2020
+	var odr uint8
+	for period := 5000; period < conversion; period *= 2 {
+		odr++
+	}
+	return odr
+}
+
+// measure reads the compensated temperature, in celsius degrees, and
+// pressure, in Pa.
+func (d *BMP388Driver) measure() (temp float64, press float64, err error) {
+	var data []byte
+	if data, err = d.read(bmp388RegisterData, 6); err != nil {
+		return 0, 0, err
+	}
+	rawP := float64(uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16)
+	rawT := float64(uint32(data[3]) | uint32(data[4])<<8 | uint32(data[5])<<16)
+
+	c := d.tpc
+	pd1 := rawT - c.t1
+	temp = pd1*c.t2 + pd1*pd1*c.t3
+
+	out1 := c.p5 + c.p6*temp + c.p7*temp*temp + c.p8*temp*temp*temp
+	out2 := rawP * (c.p1 + c.p2*temp + c.p3*temp*temp + c.p4*temp*temp*temp)
+	out3 := rawP*rawP*(c.p9+c.p10*temp) + rawP*rawP*rawP*c.p11
+	return temp, out1 + out2 + out3, nil
+}
+
+func (d *BMP388Driver) altitude() (float64, error) {
+	_, p, err := d.measure()
+	if err != nil {
+		return 0, err
+	}
+	return 44330.0 * (1.0 - math.Pow(p/100/d.seaLevelPressure, 0.1903)), nil
+}
+
+// poll updates the estimate of the vertical speed and publishes it
+func (d *BMP388Driver) poll() error {
+	d.mutex.Lock()
+	alt, err := d.altitude()
+	if err != nil {
+		d.mutex.Unlock()
+		return err
+	}
+	speed, ok := d.updateVerticalSpeed(alt, gobot.DefaultClock().Now())
+	d.mutex.Unlock()
+
+	if ok {
+		d.Publish(d.Event(VerticalSpeed), float32(speed))
+	}
+	return nil
+}
+
+// updateVerticalSpeed filters the rate of change of the altitude, measured
+// at now, with a low pass filter. It returns the new estimate, unless this
+// is the first altitude.
+func (d *BMP388Driver) updateVerticalSpeed(alt float64, now time.Time) (float64, bool) {
+	last, lastAlt := d.lastTime, d.lastAltitude
+	d.lastTime, d.lastAltitude = now, alt
+	if last.IsZero() || !now.After(last) {
+		return d.verticalSpeed, !last.IsZero()
+	}
+
+	dt := now.Sub(last).Seconds()
+	alpha := dt / (d.smoothing.Seconds() + dt)
+	d.verticalSpeed += alpha * ((alt-lastAlt)/dt - d.verticalSpeed)
+	return d.verticalSpeed, true
+}
+
+func (d *BMP388Driver) read(address byte, n int) ([]byte, error) {
+	if _, err := d.connection.Write([]byte{address}); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	bytesRead, err := d.connection.Read(buf)
+	if err != nil {
+		return nil, ErrRegisterRead{Reg: address, Err: err}
+	}
+	if bytesRead != n {
+		return nil, ErrRegisterRead{Reg: address, Err: ErrNotEnoughBytes}
+	}
+	return buf, nil
+}
+
+// WithBMP388PressureOversampling option sets the oversampling of the
+// pressure measurements of the BMP388Driver, one of the
+// BMP388Oversampling* values
+func WithBMP388PressureOversampling(val uint8) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*BMP388Driver); ok {
+			d.pressureOversampling = val
+		}
+	}
+}
+
+// WithBMP388TemperatureOversampling option sets the oversampling of the
+// temperature measurements of the BMP388Driver, one of the
+// BMP388Oversampling* values
+func WithBMP388TemperatureOversampling(val uint8) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*BMP388Driver); ok {
+			d.tempOversampling = val
+		}
+	}
+}
+
+// WithBMP388IIRFilter option sets the coefficient of the IIR filter of the
+// BMP388Driver, one of the BMP388IIRFilterCoef* values
+func WithBMP388IIRFilter(val uint8) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*BMP388Driver); ok {
+			d.iirFilter = val
+		}
+	}
+}
+
+// WithBMP388VerticalSpeedSmoothing option sets the time constant of the low
+// pass filter of the vertical speed of the BMP388Driver, one second by
+// default. A longer time constant reduces the noise of the estimate at the
+// cost of a slower response.
+func WithBMP388VerticalSpeedSmoothing(val time.Duration) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*BMP388Driver); ok {
+			d.smoothing = val
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*BMP388Driver)(nil)
+
+// bmp388Calibration are the calibration coefficients of a BMP388
+var bmp388Calibration = []byte{
+	0x70, 0x6B, 0x43, 0x67, 0xF9, 0x94, 0xF6, 0x25, 0xF4, 0x23, 0x01,
+	0xF0, 0x61, 0x98, 0x75, 0x03, 0xF9, 0xE8, 0x43, 0xFD, 0xFB,
+}
+
+// initTestBMP388DriverWithStubbedAdaptor returns a BMP388Driver reading
+// the registers of regs, which measures 35.87 celsius degrees and 95131 Pa
+func initTestBMP388DriverWithStubbedAdaptor(options ...func(Config)) (*BMP388Driver, *i2cTestAdaptor, []byte) {
+	adaptor := newI2cTestAdaptor()
+	regs := make([]byte, 256)
+	regs[bmp388RegisterChipID] = bmp388ChipIDBMP388
+	copy(regs[bmp388RegisterData:], []byte{0xA0, 0x2E, 0x63, 0x20, 0xB3, 0x81})
+	copy(regs[bmp388RegisterCalib00:], bmp388Calibration)
+	var reg byte
+	adaptor.i2cWriteImpl = func(b []byte) (int, error) {
+		reg = b[0]
+		return len(b), nil
+	}
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, regs[reg:]), nil
+	}
+	return NewBMP388Driver(adaptor, options...), adaptor, regs
+}
+
+func TestBMP388DriverName(t *testing.T) {
+	d, _, _ := initTestBMP388DriverWithStubbedAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "BMP388"), true)
+	d.SetName("barometer")
+	gobottest.Assert(t, d.Name(), "barometer")
+	gobottest.Refute(t, d.Connection(), nil)
+}
+
+func TestBMP388DriverStart(t *testing.T) {
+	d, adaptor, _ := initTestBMP388DriverWithStubbedAdaptor(
+		WithBMP388PressureOversampling(BMP388Oversampling16x),
+		WithBMP388TemperatureOversampling(BMP388Oversampling2x),
+		WithBMP388IIRFilter(BMP388IIRFilterCoef15))
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	// reset, oversampling, 25ms output data rate, IIR filter and normal mode
+	gobottest.Assert(t, bytes.Contains(adaptor.written, []byte{
+		bmp388RegisterCmd, bmp388CmdSoftReset,
+	}), true)
+	gobottest.Assert(t, bytes.Contains(adaptor.written, []byte{
+		bmp388RegisterOSR, 0x0C,
+		bmp388RegisterODR, 0x03,
+		bmp388RegisterConfig, 0x08,
+		bmp388RegisterPwrCtrl, 0x33,
+	}), true)
+}
+
+func TestBMP388DriverStartBadDevice(t *testing.T) {
+	d, _, regs := initTestBMP388DriverWithStubbedAdaptor()
+	regs[bmp388RegisterChipID] = 0x58
+	gobottest.Assert(t, d.Start(), ErrBadDevice{Device: "BMP388", ID: 0x58})
+
+	regs[bmp388RegisterChipID] = bmp388ChipIDBMP390
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestBMP388DriverStartConnectError(t *testing.T) {
+	d, adaptor, _ := initTestBMP388DriverWithStubbedAdaptor()
+	adaptor.Testi2cConnectErr(true)
+	gobottest.Assert(t, d.Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestBMP388DriverMeasurements(t *testing.T) {
+	d, adaptor, _ := initTestBMP388DriverWithStubbedAdaptor()
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	d.Start()
+	defer d.Halt()
+
+	temp, err := d.Temperature()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, math.Abs(float64(temp)-35.87) < 0.01, true)
+
+	press, err := d.Pressure()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, math.Abs(float64(press)-95131.45) < 0.1, true)
+
+	alt, err := d.Altitude()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, math.Abs(float64(alt)-528.91) < 0.1, true)
+
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return 0, errors.New("read error")
+	})
+	_, err = d.Pressure()
+	gobottest.Assert(t, err, ErrRegisterRead{Reg: bmp388RegisterData, Err: errors.New("read error")})
+}
+
+func TestBMP388DriverSeaLevelPressure(t *testing.T) {
+	d, _, _ := initTestBMP388DriverWithStubbedAdaptor()
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	d.Start()
+	defer d.Halt()
+
+	gobottest.Assert(t, d.SeaLevelPressure(), float32(1013.25))
+	d.SetSeaLevelPressure(951.3145)
+	alt, _ := d.Altitude()
+	gobottest.Assert(t, math.Abs(float64(alt)) < 0.01, true)
+
+	gobottest.Assert(t, d.SetReferenceAltitude(120), nil)
+	alt, _ = d.Altitude()
+	gobottest.Assert(t, math.Abs(float64(alt)-120) < 0.1, true)
+
+	gobottest.Assert(t, d.Command("SetReferenceAltitude")(map[string]interface{}{"altitude": 300.0}), nil)
+	result := d.Command("Altitude")(map[string]interface{}{}).(map[string]interface{})
+	gobottest.Assert(t, math.Abs(float64(result["altitude"].(float32))-300) < 0.1, true)
+}
+
+func TestBMP388DriverVerticalSpeed(t *testing.T) {
+	d, _, _ := initTestBMP388DriverWithStubbedAdaptor(WithBMP388VerticalSpeedSmoothing(0))
+	now := time.Unix(0, 0)
+
+	_, ok := d.updateVerticalSpeed(100, now)
+	gobottest.Assert(t, ok, false)
+	speed, ok := d.updateVerticalSpeed(102, now.Add(time.Second))
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, speed, 2.0)
+
+	// the speed is smoothed with the time constant
+	d.smoothing = time.Second
+	speed, _ = d.updateVerticalSpeed(102, now.Add(2*time.Second))
+	gobottest.Assert(t, speed, 1.0)
+	gobottest.Assert(t, d.VerticalSpeed(), float32(1.0))
+}
+
+func TestBMP388DriverVerticalSpeedEvent(t *testing.T) {
+	d, _, _ := initTestBMP388DriverWithStubbedAdaptor()
+	d.SetPolling(gobot.PollerConfig{Interval: time.Millisecond})
+	sem := make(chan interface{}, 1)
+	d.Once(VerticalSpeed, func(data interface{}) { sem <- data })
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	select {
+	case data := <-sem:
+		// the pressure does not change
+		gobottest.Assert(t, data, float32(0))
+	case <-time.After(time.Second):
+		t.Errorf("BMP388 VerticalSpeed event was not published")
+	}
+}
+
+func TestBMP388DriverDebugDump(t *testing.T) {
+	d, adaptor, _ := initTestBMP388DriverWithStubbedAdaptor()
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	d.Start()
+	defer d.Halt()
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		b[0] = 0x33
+		return len(b), nil
+	})
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["PWR_CTRL"].(map[string]interface{})["mode"], "normal")
+}
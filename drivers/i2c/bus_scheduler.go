@@ -0,0 +1,32 @@
+package i2c
+
+import (
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+type busKey struct {
+	connector Connector
+	bus       int
+}
+
+var (
+	busSchedulers      = map[busKey]*gobot.PollScheduler{}
+	busSchedulersMutex sync.Mutex
+)
+
+// BusScheduler returns the PollScheduler shared by the polling drivers of a
+// bus of the Connector, so that they read their sensors in turn instead of
+// contending for the bus. Its gap is zero until changed with SetGap.
+func BusScheduler(c Connector, bus int) *gobot.PollScheduler {
+	busSchedulersMutex.Lock()
+	defer busSchedulersMutex.Unlock()
+	key := busKey{connector: c, bus: bus}
+	s, ok := busSchedulers[key]
+	if !ok {
+		s = gobot.NewPollScheduler(0)
+		busSchedulers[key] = s
+	}
+	return s
+}
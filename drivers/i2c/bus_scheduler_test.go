@@ -0,0 +1,16 @@
+package i2c
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestBusScheduler(t *testing.T) {
+	a := newI2cTestAdaptor()
+	s := BusScheduler(a, 1)
+	gobottest.Refute(t, s, nil)
+	gobottest.Assert(t, BusScheduler(a, 1) == s, true)
+	gobottest.Assert(t, BusScheduler(a, 2) == s, false)
+	gobottest.Assert(t, BusScheduler(newI2cTestAdaptor(), 1) == s, false)
+}
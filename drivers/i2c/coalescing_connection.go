@@ -0,0 +1,137 @@
+package i2c
+
+import "sync"
+
+// RegisterBlock is a range of contiguous registers which a device lets read
+// in a single transaction.
+type RegisterBlock struct {
+	Start  uint8
+	Length int
+}
+
+func (b RegisterBlock) contains(reg uint8, n int) bool {
+	return int(reg) >= int(b.Start) && int(reg)+n <= int(b.Start)+b.Length
+}
+
+// CoalescingConnection is a Connection coalescing the reads of the registers
+// of its blocks: the first ReadByteData or ReadWordData of a register of a
+// block reads the whole block at once, the next ones are served from the
+// block until Invalidate is called, typically at the start of each poll
+// cycle. A driver reading 8 registers of a block in each cycle thus makes 1
+// transaction instead of 8. Writes invalidate the blocks read.
+type CoalescingConnection struct {
+	Connection
+	blocks []RegisterBlock
+	cache  map[int][]byte
+	mutex  sync.Mutex
+}
+
+// NewCoalescingConnection returns a new CoalescingConnection reading the
+// registers of blocks from c.
+func NewCoalescingConnection(c Connection, blocks ...RegisterBlock) *CoalescingConnection {
+	return &CoalescingConnection{
+		Connection: c,
+		blocks:     blocks,
+		cache:      map[int][]byte{},
+	}
+}
+
+// Invalidate discards the blocks read, so that the next reads get the
+// current values of the registers.
+func (c *CoalescingConnection) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cache = map[int][]byte{}
+}
+
+// ReadByteData reads a register, from its block when it belongs to one.
+func (c *CoalescingConnection) ReadByteData(reg uint8) (val uint8, err error) {
+	data, ok, err := c.read(reg, 1)
+	if !ok {
+		return c.Connection.ReadByteData(reg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// ReadWordData reads a register as the low byte and the next one as the high
+// byte of a word, from their block when they belong to the same one.
+func (c *CoalescingConnection) ReadWordData(reg uint8) (val uint16, err error) {
+	data, ok, err := c.read(reg, 2)
+	if !ok {
+		return c.Connection.ReadWordData(reg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uint16(data[0]) | uint16(data[1])<<8, nil
+}
+
+// read returns the n registers starting at reg from their block, reading it
+// if needed. ok is false when they do not belong to a block.
+func (c *CoalescingConnection) read(reg uint8, n int) (data []byte, ok bool, err error) {
+	for i, b := range c.blocks {
+		if !b.contains(reg, n) {
+			continue
+		}
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		block, cached := c.cache[i]
+		if !cached {
+			if block, err = c.readBlock(b); err != nil {
+				return nil, true, err
+			}
+			c.cache[i] = block
+		}
+		offset := int(reg - b.Start)
+		return block[offset : offset+n], true, nil
+	}
+	return nil, false, nil
+}
+
+func (c *CoalescingConnection) readBlock(b RegisterBlock) ([]byte, error) {
+	if _, err := c.Connection.Write([]byte{b.Start}); err != nil {
+		return nil, err
+	}
+	block := make([]byte, b.Length)
+	read, err := c.Connection.Read(block)
+	if err != nil {
+		return nil, err
+	}
+	if read != b.Length {
+		return nil, ErrNotEnoughBytes
+	}
+	return block, nil
+}
+
+// Write invalidates the blocks read and writes data to the device.
+func (c *CoalescingConnection) Write(data []byte) (written int, err error) {
+	c.Invalidate()
+	return c.Connection.Write(data)
+}
+
+// WriteByte invalidates the blocks read and writes a byte to the device.
+func (c *CoalescingConnection) WriteByte(val byte) (err error) {
+	c.Invalidate()
+	return c.Connection.WriteByte(val)
+}
+
+// WriteByteData invalidates the blocks read and writes a register.
+func (c *CoalescingConnection) WriteByteData(reg uint8, val uint8) (err error) {
+	c.Invalidate()
+	return c.Connection.WriteByteData(reg, val)
+}
+
+// WriteWordData invalidates the blocks read and writes a word.
+func (c *CoalescingConnection) WriteWordData(reg uint8, val uint16) (err error) {
+	c.Invalidate()
+	return c.Connection.WriteWordData(reg, val)
+}
+
+// WriteBlockData invalidates the blocks read and writes a block of bytes.
+func (c *CoalescingConnection) WriteBlockData(reg uint8, b []byte) (err error) {
+	c.Invalidate()
+	return c.Connection.WriteBlockData(reg, b)
+}
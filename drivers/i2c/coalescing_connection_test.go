@@ -0,0 +1,107 @@
+package i2c_test
+
+import (
+	"errors"
+	"testing"
+
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/drivers/i2c/i2ctest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ i2c.Connection = (*i2c.CoalescingConnection)(nil)
+
+func initTestCoalescingConnection() (*i2c.CoalescingConnection, *i2ctest.Device) {
+	a := i2ctest.NewAdaptor()
+	d := a.AddDevice(0x39)
+	d.SetRegisters(0x94, 1, 2, 3, 4, 5, 6, 7, 8)
+	conn, _ := a.GetConnection(0x39, a.GetDefaultBus())
+	return i2c.NewCoalescingConnection(conn, i2c.RegisterBlock{Start: 0x94, Length: 8}), d
+}
+
+func TestCoalescingConnectionReadByteData(t *testing.T) {
+	c, d := initTestCoalescingConnection()
+	d.Expect(i2ctest.Write(0x94), i2ctest.Read(0x94, 8))
+
+	for reg := uint8(0x94); reg < 0x9C; reg++ {
+		val, err := c.ReadByteData(reg)
+		gobottest.Assert(t, err, nil)
+		gobottest.Assert(t, val, reg-0x93)
+	}
+	gobottest.Assert(t, d.Verify(), nil)
+}
+
+func TestCoalescingConnectionReadWordData(t *testing.T) {
+	c, d := initTestCoalescingConnection()
+
+	val, err := c.ReadWordData(0x96)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, uint16(0x0403))
+
+	// the word crosses the end of the block
+	d.Reset()
+	d.Expect(i2ctest.Read(0x9B, 2))
+	_, err = c.ReadWordData(0x9B)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, d.Verify(), nil)
+}
+
+func TestCoalescingConnectionOutsideBlocks(t *testing.T) {
+	c, d := initTestCoalescingConnection()
+	d.SetRegister(0x92, 0xAB)
+	d.Expect(i2ctest.Read(0x92, 1), i2ctest.Read(0x92, 1))
+
+	val, _ := c.ReadByteData(0x92)
+	gobottest.Assert(t, val, uint8(0xAB))
+	c.ReadByteData(0x92)
+	gobottest.Assert(t, d.Verify(), nil)
+}
+
+func TestCoalescingConnectionInvalidate(t *testing.T) {
+	c, d := initTestCoalescingConnection()
+	c.ReadByteData(0x94)
+	d.SetRegister(0x94, 42)
+
+	val, _ := c.ReadByteData(0x94)
+	gobottest.Assert(t, val, uint8(1))
+
+	c.Invalidate()
+	val, _ = c.ReadByteData(0x94)
+	gobottest.Assert(t, val, uint8(42))
+}
+
+func TestCoalescingConnectionWriteInvalidates(t *testing.T) {
+	c, d := initTestCoalescingConnection()
+	c.ReadByteData(0x94)
+
+	gobottest.Assert(t, c.WriteByteData(0x94, 42), nil)
+	val, _ := c.ReadByteData(0x94)
+	gobottest.Assert(t, val, uint8(42))
+
+	c.WriteWordData(0x95, 0x0102)
+	val, _ = c.ReadByteData(0x96)
+	gobottest.Assert(t, val, uint8(1))
+
+	c.WriteBlockData(0x97, []byte{9})
+	val, _ = c.ReadByteData(0x97)
+	gobottest.Assert(t, val, uint8(9))
+
+	c.Write([]byte{0x98, 10})
+	val, _ = c.ReadByteData(0x98)
+	gobottest.Assert(t, val, uint8(10))
+
+	d.SetRegister(0x99, 11)
+	c.WriteByte(0x00)
+	val, _ = c.ReadByteData(0x99)
+	gobottest.Assert(t, val, uint8(11))
+}
+
+func TestCoalescingConnectionReadError(t *testing.T) {
+	c, d := initTestCoalescingConnection()
+	d.FailRead(0x97, errors.New("read error"))
+
+	_, err := c.ReadByteData(0x94)
+	gobottest.Assert(t, err, errors.New("read error"))
+	_, err = c.ReadWordData(0x94)
+	gobottest.Assert(t, err, errors.New("read error"))
+}
@@ -0,0 +1,167 @@
+package i2c
+
+import (
+	"fmt"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// Displayer is the interface which describes a display showing lines of text,
+// which a Console prints to. It is implemented by the character LCDs and by
+// the graphic displays drawing text with a font.
+type Displayer interface {
+	// TextSize returns the number of columns and rows of characters
+	TextSize() (cols int, rows int)
+	// WriteLine shows text on row, counted from 0, replacing the previous
+	// text of the row
+	WriteLine(row int, text string) error
+	// Clear clears the display
+	Clear() error
+}
+
+// Console is a Gobot Driver printing scrolling lines of text to a Displayer,
+// such as a tiny OLED or a LCD. The lines longer than the display are
+// wrapped, and the text scrolls up once the last row is full. A Console is an
+// io.Writer, so that a log can be mirrored to the display, e.g.
+//
+//	log.SetOutput(io.MultiWriter(os.Stderr, console))
+type Console struct {
+	name    string
+	display Displayer
+	cols    int
+	rows    int
+	lines   []string
+	shown   []string
+	pending bool
+	mutex   *sync.Mutex
+}
+
+// NewConsole creates a new Console printing to display, which must be
+// started separately.
+func NewConsole(display Displayer) *Console {
+	cols, rows := display.TextSize()
+	return &Console{
+		name:    gobot.DefaultName("Console"),
+		display: display,
+		cols:    cols,
+		rows:    rows,
+		lines:   []string{""},
+		shown:   make([]string, rows),
+		mutex:   &sync.Mutex{},
+	}
+}
+
+// Name returns the Name for the Driver
+func (c *Console) Name() string { return c.name }
+
+// SetName sets the Name for the Driver
+func (c *Console) SetName(n string) { c.name = n }
+
+// Connection returns the connection of the display, if it is a Device
+func (c *Console) Connection() gobot.Connection {
+	if d, ok := c.display.(gobot.Device); ok {
+		return d.Connection()
+	}
+	return nil
+}
+
+// Start implements the Driver interface
+func (c *Console) Start() (err error) { return }
+
+// Halt implements the Driver interface
+func (c *Console) Halt() (err error) { return }
+
+// Display returns the Displayer the Console prints to
+func (c *Console) Display() Displayer { return c.display }
+
+// Lines returns the lines of text currently displayed, from the top row
+func (c *Console) Lines() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	lines := make([]string, len(c.lines))
+	copy(lines, c.lines)
+	return lines
+}
+
+// Write implements the io.Writer interface, printing p as text. A newline
+// starts a new line once more text is printed, so that the last line of
+// the text stays on the bottom row.
+func (c *Console) Write(p []byte) (n int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, r := range string(p) {
+		switch {
+		case r == '\r':
+		case r == '\n':
+			if c.pending {
+				c.newline()
+			}
+			c.pending = true
+		default:
+			last := []rune(c.lines[len(c.lines)-1])
+			if c.pending || len(last) >= c.cols {
+				c.newline()
+				c.pending = false
+			}
+			c.lines[len(c.lines)-1] += string(r)
+		}
+	}
+	if err = c.refresh(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Printf prints the text formatted according to format, like fmt.Printf
+func (c *Console) Printf(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(c, format, a...)
+	return err
+}
+
+// Println prints the operands and a newline, like fmt.Println
+func (c *Console) Println(a ...interface{}) error {
+	_, err := fmt.Fprintln(c, a...)
+	return err
+}
+
+// Clear clears the display and the lines of text
+func (c *Console) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lines = []string{""}
+	c.shown = make([]string, c.rows)
+	c.pending = false
+	return c.display.Clear()
+}
+
+// newline starts a new line, scrolling up when the last row is full, the
+// mutex being held
+func (c *Console) newline() {
+	if len(c.lines) < c.rows {
+		c.lines = append(c.lines, "")
+		return
+	}
+	c.lines = append(c.lines[1:], "")
+}
+
+// refresh writes the rows whose text changed, the mutex being held
+func (c *Console) refresh() error {
+	for row := 0; row < c.rows; row++ {
+		text := ""
+		if row < len(c.lines) {
+			text = c.lines[row]
+		}
+		if text == c.shown[row] {
+			continue
+		}
+		if err := c.display.WriteLine(row, text); err != nil {
+			return err
+		}
+		c.shown[row] = text
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*Console)(nil)
+
+// consoleTestDisplay records the lines written to it
+type consoleTestDisplay struct {
+	rows   []string
+	writes []string
+	err    error
+}
+
+func (d *consoleTestDisplay) TextSize() (int, int) { return 8, len(d.rows) }
+
+func (d *consoleTestDisplay) WriteLine(row int, text string) error {
+	if d.err != nil {
+		return d.err
+	}
+	d.rows[row] = text
+	d.writes = append(d.writes, fmt.Sprintf("%d:%s", row, text))
+	return nil
+}
+
+func (d *consoleTestDisplay) Clear() error {
+	d.writes = append(d.writes, "clear")
+	for i := range d.rows {
+		d.rows[i] = ""
+	}
+	return d.err
+}
+
+func (d *consoleTestDisplay) Writes() []string {
+	writes := d.writes
+	d.writes = nil
+	return writes
+}
+
+func TestConsole(t *testing.T) {
+	c := NewConsole(&consoleTestDisplay{rows: make([]string, 2)})
+	gobottest.Assert(t, strings.HasPrefix(c.Name(), "Console"), true)
+	c.SetName("status")
+	gobottest.Assert(t, c.Name(), "status")
+	gobottest.Assert(t, c.Connection(), nil)
+	gobottest.Assert(t, c.Start(), nil)
+	gobottest.Assert(t, c.Halt(), nil)
+
+	a := newI2cTestAdaptor()
+	c = NewConsole(NewHD44780Driver(a, 20, 4))
+	gobottest.Assert(t, c.Connection(), a)
+	gobottest.Assert(t, c.Display(), c.display)
+}
+
+func TestConsoleScrolling(t *testing.T) {
+	d := &consoleTestDisplay{rows: make([]string, 3)}
+	c := NewConsole(d)
+
+	gobottest.Assert(t, c.Println("booting"), nil)
+	gobottest.Assert(t, d.Writes(), []string{"0:booting"})
+	gobottest.Assert(t, c.Printf("wifi %s\nip ", "ok"), nil)
+	gobottest.Assert(t, d.Writes(), []string{"1:wifi ok", "2:ip "})
+	gobottest.Assert(t, c.Printf("1.2.3\n"), nil)
+	gobottest.Assert(t, d.Writes(), []string{"2:ip 1.2.3"})
+
+	// the text scrolls up, and long lines wrap
+	gobottest.Assert(t, c.Println("ready"), nil)
+	gobottest.Assert(t, d.Writes(), []string{"0:wifi ok", "1:ip 1.2.3", "2:ready"})
+	gobottest.Assert(t, c.Lines(), []string{"wifi ok", "ip 1.2.3", "ready"})
+	gobottest.Assert(t, d.rows, []string{"wifi ok", "ip 1.2.3", "ready"})
+
+	// an empty line
+	gobottest.Assert(t, c.Printf("\na\r\n"), nil)
+	gobottest.Assert(t, d.rows, []string{"ready", "", "a"})
+	d.Writes()
+
+	gobottest.Assert(t, c.Clear(), nil)
+	gobottest.Assert(t, c.Lines(), []string{""})
+	gobottest.Assert(t, c.Println("again"), nil)
+	gobottest.Assert(t, d.Writes(), []string{"clear", "0:again"})
+}
+
+func TestConsoleWrap(t *testing.T) {
+	d := &consoleTestDisplay{rows: make([]string, 2)}
+	c := NewConsole(d)
+	gobottest.Assert(t, c.Printf("temp:21.5°C humidity"), nil)
+	gobottest.Assert(t, d.rows, []string{"5°C humi", "dity"})
+}
+
+func TestConsoleLog(t *testing.T) {
+	d := &consoleTestDisplay{rows: make([]string, 2)}
+	l := log.New(NewConsole(d), "", 0)
+	l.Println("started")
+	l.Printf("%d dev", 3)
+	gobottest.Assert(t, d.rows, []string{"started", "3 dev"})
+}
+
+func TestConsoleWriteError(t *testing.T) {
+	d := &consoleTestDisplay{rows: make([]string, 2), err: errors.New("write error")}
+	c := NewConsole(d)
+	n, err := c.Write([]byte("hello"))
+	gobottest.Assert(t, n, 0)
+	gobottest.Assert(t, err, errors.New("write error"))
+
+	// the line is written again once the display works
+	d.err = nil
+	gobottest.Assert(t, c.Println("!"), nil)
+	gobottest.Assert(t, d.rows, []string{"hello!", ""})
+	gobottest.Assert(t, c.Clear(), nil)
+	d.err = errors.New("clear error")
+	gobottest.Assert(t, c.Clear(), errors.New("clear error"))
+}
@@ -1,6 +1,8 @@
 package i2c
 
 import (
+	"sync"
+
 	"gobot.io/x/gobot"
 )
 
@@ -61,6 +63,38 @@ const (
 	drv2605RegLRAResoPeriod = 0x22
 )
 
+// drv2605Registers are the registers shown by DebugDump
+var drv2605Registers = RegisterMap{
+	{Name: "STATUS", Address: drv2605RegStatus, Fields: []RegisterField{
+		{Name: "DEVICE_ID", Shift: 5, Width: 3, Values: map[uint8]string{3: "DRV2605", 4: "DRV2604", 6: "DRV2604L", 7: "DRV2605L"}},
+		{Name: "DIAG_RESULT", Shift: 3, Width: 1},
+		{Name: "OVER_TEMP", Shift: 1, Width: 1},
+		{Name: "OC_DETECT", Shift: 0, Width: 1},
+	}},
+	{Name: "MODE", Address: drv2605RegMode, Fields: []RegisterField{
+		{Name: "DEV_RESET", Shift: 7, Width: 1},
+		{Name: "STANDBY", Shift: 6, Width: 1},
+		{Name: "MODE", Shift: 0, Width: 3, Values: map[uint8]string{
+			uint8(DRV2605ModeIntTrig): "internal trigger",
+			DRV2605ModeExtTrigEdge:    "external trigger edge",
+			DRV2605ModeExtTrigLvl:     "external trigger level",
+			DRV2605ModePWMAnalog:      "PWM/analog",
+			DRV2605ModeAudioVibe:      "audio-to-vibe",
+			DRV2605ModeRealtime:       "real-time playback",
+			DRV2605ModeDiagnose:       "diagnostics",
+			DRV2605ModeAutocal:        "auto calibration",
+		}},
+	}},
+	{Name: "LIBRARY_SEL", Address: drv2605RegLibrary, Fields: []RegisterField{
+		{Name: "HI_Z", Shift: 4, Width: 1},
+		{Name: "LIBRARY_SEL", Shift: 0, Width: 3},
+	}},
+	{Name: "FEEDBACK_CONTROL", Address: drv2605RegFeedback, Fields: []RegisterField{
+		{Name: "N_ERM_LRA", Shift: 7, Width: 1, Values: map[uint8]string{0: "ERM", 1: "LRA"}},
+	}},
+	{Name: "VBAT", Address: drv2605RegVBat},
+}
+
 // DRV2605LDriver is the gobot driver for the TI/Adafruit DRV2605L Haptic Controller
 //
 // Device datasheet: http://www.ti.com/lit/ds/symlink/drv2605l.pdf
@@ -77,7 +111,10 @@ type DRV2605LDriver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 }
 
 // NewDRV2605LDriver creates a new driver for the DRV2605L device.
@@ -94,12 +131,17 @@ func NewDRV2605LDriver(conn Connector, options ...func(Config)) *DRV2605LDriver
 		name:      gobot.DefaultName("DRV2605L"),
 		connector: conn,
 		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
 	}
 
+	driver.driverConfig = driverConfig{config: &driver.Config}
 	for _, option := range options {
 		option(driver)
 	}
 
+	addDebugDumpCommand(driver, driver)
+
 	return driver
 }
 
@@ -120,6 +162,9 @@ func (d *DRV2605LDriver) Connection() gobot.Connection {
 
 // Start initializes the device.
 func (d *DRV2605LDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if err := d.initialize(); err != nil {
 		return err
 	}
@@ -136,10 +181,7 @@ func (d *DRV2605LDriver) writeByteRegisters(regValPairs []struct{ reg, val uint8
 }
 
 func (d *DRV2605LDriver) initialize() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(drv2605Address)
-
-	d.connection, err = d.connector.GetConnection(address, bus)
+	d.connection, err = connect(d, d.connector, d.Config, drv2605Address)
 	if err != nil {
 		return
 	}
@@ -177,6 +219,9 @@ func (d *DRV2605LDriver) initialize() (err error) {
 // SetMode sets the device in one of the eight modes as described in the
 // datasheet. Defaults to mode 0, internal trig.
 func (d *DRV2605LDriver) SetMode(newMode DRV2605Mode) (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	mode, err := d.connection.ReadByteData(drv2605RegMode)
 	if err != nil {
 		return err
@@ -194,6 +239,9 @@ func (d *DRV2605LDriver) SetMode(newMode DRV2605Mode) (err error) {
 
 // SetStandbyMode controls device low power mode
 func (d *DRV2605LDriver) SetStandbyMode(standby bool) (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	modeVal, err := d.connection.ReadByteData(drv2605RegMode)
 	if err != nil {
 		return err
@@ -212,6 +260,9 @@ func (d *DRV2605LDriver) SetStandbyMode(standby bool) (err error) {
 // SelectLibrary selects which waveform library to play from, 1-7.
 // See datasheet for more info.
 func (d *DRV2605LDriver) SelectLibrary(library uint8) (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	err = d.connection.WriteByteData(drv2605RegLibrary, library&0x7)
 	return err
 }
@@ -235,6 +286,9 @@ func (d *DRV2605LDriver) GetPauseWaveform(delayTime10MS uint8) (pauseID uint8) {
 // A waveform id of zero marks the end of the sequence.
 // Pauses can be encoded using GetPauseWaveform().
 func (d *DRV2605LDriver) SetSequence(waveforms []uint8) (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if len(waveforms) < 8 {
 		waveforms = append(waveforms, 0)
 	}
@@ -251,6 +305,9 @@ func (d *DRV2605LDriver) SetSequence(waveforms []uint8) (err error) {
 
 // Go plays the current sequence of waveforms.
 func (d *DRV2605LDriver) Go() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	err = d.connection.WriteByteData(drv2605RegGo, 1)
 	return err
 }
@@ -268,3 +325,11 @@ func (d *DRV2605LDriver) Halt() (err error) {
 	}
 	return
 }
+
+// DebugDump reads the status, mode, library, feedback and supply voltage registers of the haptic controller.
+func (d *DRV2605LDriver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, drv2605Registers)
+}
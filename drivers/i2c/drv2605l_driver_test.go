@@ -168,3 +168,19 @@ func TestDRV2605LDriverGo(t *testing.T) {
 	d.Start()
 	gobottest.Assert(t, d.Go(), nil)
 }
+
+func TestDRV2605LDriverDebugDump(t *testing.T) {
+	d, adaptor := initTestDriverAndAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0xE0
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["STATUS"].(map[string]interface{})["DEVICE_ID"], "DRV2605L")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
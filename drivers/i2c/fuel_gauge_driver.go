@@ -0,0 +1,472 @@
+package i2c
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const (
+	// Battery event is published by the FuelGaugeDriver with each
+	// FuelGaugeReading
+	Battery = "battery"
+	// BatteryLow event is published by the FuelGaugeDriver when the state of
+	// charge falls to the low threshold, for the robot to return to its dock
+	BatteryLow = "battery-low"
+	// BatteryCritical event is published by the FuelGaugeDriver when the
+	// state of charge falls to the critical threshold, for the robot to stop
+	// before the battery cuts out
+	BatteryCritical = "battery-critical"
+	// BatteryRecovered event is published by the FuelGaugeDriver when the
+	// state of charge rises back above the low threshold, once charged
+	BatteryRecovered = "battery-recovered"
+)
+
+// FuelGaugeChip is a fuel gauge chip supported by the FuelGaugeDriver.
+type FuelGaugeChip int
+
+const (
+	// MAX17048 is the Maxim MAX17048, or its MAX17049 sibling for two cells
+	MAX17048 FuelGaugeChip = iota
+	// LC709203F is the ON Semiconductor LC709203F
+	LC709203F
+)
+
+// Default addresses of the fuel gauges
+const (
+	MAX17048Address  = 0x36
+	LC709203FAddress = 0x0B
+)
+
+// MAX17048 registers, big endian words
+const (
+	max17048RegVCell   = 0x02
+	max17048RegSOC     = 0x04
+	max17048RegVersion = 0x08
+	max17048RegCRate   = 0x16
+	// max17048VersionMask is the part of the version common to the MAX17048
+	// and MAX17049
+	max17048VersionMask = 0xFFF0
+	max17048Version     = 0x0010
+)
+
+// LC709203F registers, little endian words
+const (
+	lc709203fRegInitialRSOC   = 0x07
+	lc709203fRegCellVoltage   = 0x09
+	lc709203fRegAPA           = 0x0B
+	lc709203fRegITE           = 0x0F
+	lc709203fRegProfile       = 0x12
+	lc709203fRegPowerMode     = 0x15
+	lc709203fRegThermistor    = 0x16
+	lc709203fInitRSOC         = 0xAA55
+	lc709203fPowerOperational = 0x0001
+)
+
+// LC709203FPack is the adjustment pack application (APA) of the LC709203F
+// for the capacity of its battery
+type LC709203FPack uint16
+
+// APA of the LC709203F by capacity, from its datasheet
+const (
+	LC709203FPack100mAh  LC709203FPack = 0x08
+	LC709203FPack200mAh  LC709203FPack = 0x0B
+	LC709203FPack500mAh  LC709203FPack = 0x10
+	LC709203FPack1000mAh LC709203FPack = 0x19
+	LC709203FPack2000mAh LC709203FPack = 0x2D
+	LC709203FPack3000mAh LC709203FPack = 0x36
+)
+
+const (
+	// fuelGaugeHysteresis is how far above a threshold the state of charge
+	// must rise to leave it, so that the events do not flap around it
+	fuelGaugeHysteresis = 1.0
+	// fuelGaugeRateWindow is the time over which the LC709203F, which does
+	// not measure it, estimates the charge rate
+	fuelGaugeRateWindow = time.Minute
+)
+
+// battery levels crossed by the state of charge
+const (
+	batteryUnknown = iota
+	batteryOK
+	batteryLow
+	batteryCritical
+)
+
+// FuelGaugeReading is a measurement of a FuelGaugeDriver, the data published
+// with the Battery event
+type FuelGaugeReading struct {
+	// StateOfCharge of the battery, in percent
+	StateOfCharge float64
+	// Voltage of the cell
+	Voltage gobot.Voltage
+	// Rate of charge, in percent per hour, negative while discharging
+	Rate float64
+}
+
+// TimeToEmpty returns the time until the battery is empty at the current
+// Rate, 0 when it is not discharging.
+func (r FuelGaugeReading) TimeToEmpty() time.Duration {
+	if r.Rate >= 0 {
+		return 0
+	}
+	return time.Duration(r.StateOfCharge / -r.Rate * float64(time.Hour))
+}
+
+// FuelGaugeDriver is a driver for the MAX17048 and LC709203F fuel gauges of
+// single cell lithium batteries. Once started, it polls the state of charge,
+// voltage and charge rate of the battery, publishes them with the Battery
+// event, and publishes the BatteryLow, BatteryCritical and BatteryRecovered
+// events as the state of charge crosses the thresholds, so that a mobile
+// robot returns to its dock in time.
+type FuelGaugeDriver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+	gobot.Eventer
+
+	chip     FuelGaugeChip
+	pack     LC709203FPack
+	low      float64
+	critical float64
+	level    int
+	interval time.Duration
+	polling  gobot.PollerConfig
+	halt     chan bool
+
+	// rateFrom is the reading the LC709203F estimates the charge rate from
+	rateFrom     float64
+	rateFromTime time.Time
+	rate         float64
+}
+
+// NewFuelGaugeDriver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithFuelGaugeChip(FuelGaugeChip):	chip of the fuel gauge, MAX17048 by default
+//		i2c.WithLC709203FPack(LC709203FPack):	capacity of the battery of a LC709203F, 1000mAh by default
+//		i2c.WithFuelGaugeThresholds(float64, float64):	low and critical states of charge, 20% and 5% by default
+//
+func NewFuelGaugeDriver(c Connector, options ...func(Config)) *FuelGaugeDriver {
+	d := &FuelGaugeDriver{
+		name:      gobot.DefaultName("FuelGauge"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		Eventer:   gobot.NewEventer(),
+		mutex:     &sync.Mutex{},
+		chip:      MAX17048,
+		pack:      LC709203FPack1000mAh,
+		low:       20,
+		critical:  5,
+		interval:  5 * time.Second,
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddEvent(Battery)
+	d.AddEvent(BatteryLow)
+	d.AddEvent(BatteryCritical)
+	d.AddEvent(BatteryRecovered)
+	d.AddEvent(Error)
+
+	d.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		r, err := d.Read()
+		return map[string]interface{}{
+			"stateOfCharge": r.StateOfCharge, "voltage": r.Voltage.Volts(), "rate": r.Rate, "err": err,
+		}
+	})
+
+	return d
+}
+
+// WithFuelGaugeChip option sets the chip of the fuel gauge, MAX17048 by
+// default. The default address is the one of the chip.
+func WithFuelGaugeChip(chip FuelGaugeChip) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*FuelGaugeDriver); ok {
+			d.chip = chip
+		}
+	}
+}
+
+// WithLC709203FPack option sets the adjustment pack application of a
+// LC709203F for the capacity of its battery, LC709203FPack1000mAh by default.
+func WithLC709203FPack(pack LC709203FPack) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*FuelGaugeDriver); ok {
+			d.pack = pack
+		}
+	}
+}
+
+// WithFuelGaugeThresholds option sets the states of charge, in percent, at
+// which the BatteryLow and BatteryCritical events are published, 20% and 5%
+// by default.
+func WithFuelGaugeThresholds(low, critical float64) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*FuelGaugeDriver); ok {
+			d.low = low
+			d.critical = critical
+		}
+	}
+}
+
+// Name returns the name of the device.
+func (d *FuelGaugeDriver) Name() string { return d.name }
+
+// SetName sets the name of the device.
+func (d *FuelGaugeDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device.
+func (d *FuelGaugeDriver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Start initializes the fuel gauge and starts the polling of its readings.
+//
+// Emits the Events:
+// 	Battery FuelGaugeReading - On each reading
+// 	BatteryLow FuelGaugeReading - When the state of charge falls to the low threshold
+// 	BatteryCritical FuelGaugeReading - When the state of charge falls to the critical threshold
+// 	BatteryRecovered FuelGaugeReading - When the state of charge rises back above the low threshold
+// 	Error error - On read error
+func (d *FuelGaugeDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	address := MAX17048Address
+	if d.chip == LC709203F {
+		address = LC709203FAddress
+	}
+	if d.connection, err = connect(d, d.connector, d.Config, address); err != nil {
+		return err
+	}
+	if err = d.initialize(); err != nil {
+		return err
+	}
+	d.level = batteryUnknown
+	d.rateFromTime = time.Time{}
+
+	config := d.polling
+	config.Interval = d.interval
+	config.MaxInterval = 0
+	if config.Scheduler == nil {
+		config.Scheduler = BusScheduler(d.connector, d.GetBusOrDefault(d.connector.GetDefaultBus()))
+	}
+	d.halt = make(chan bool)
+	gobot.NewPoller(config).Go(d, d.halt, func() bool {
+		r, err := d.Read()
+		if err != nil {
+			d.Publish(d.Event(Error), err)
+			return false
+		}
+		d.Publish(d.Event(Battery), r)
+		if event := d.crossed(r.StateOfCharge); event != "" {
+			d.Publish(d.Event(event), r)
+		}
+		return true
+	})
+	return nil
+}
+
+// Halt stops the polling of the readings.
+func (d *FuelGaugeDriver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.halt != nil {
+		close(d.halt)
+		d.halt = nil
+	}
+	return nil
+}
+
+// SetPolling configures the polling of the readings, every 5s by default.
+// The fuel gauge is polled at a fixed interval, c.MaxInterval is ignored.
+func (d *FuelGaugeDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		d.interval = c.Interval
+	}
+	d.polling = c
+}
+
+// Read returns the current state of charge, voltage and charge rate of the
+// battery. The LC709203F not measuring the charge rate, it is estimated from
+// the states of charge read over a minute, and 0 until then.
+func (d *FuelGaugeDriver) Read() (FuelGaugeReading, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.connection == nil {
+		return FuelGaugeReading{}, ErrNotConnected
+	}
+	if d.chip == LC709203F {
+		return d.readLC709203F()
+	}
+	return d.readMAX17048()
+}
+
+// StateOfCharge returns the state of charge of the battery, in percent.
+func (d *FuelGaugeDriver) StateOfCharge() (float64, error) {
+	r, err := d.Read()
+	return r.StateOfCharge, err
+}
+
+// Voltage returns the voltage of the cell.
+func (d *FuelGaugeDriver) Voltage() (gobot.Voltage, error) {
+	r, err := d.Read()
+	return r.Voltage, err
+}
+
+// initialize checks the MAX17048, and sets the LC709203F up for its battery,
+// the mutex must be held
+func (d *FuelGaugeDriver) initialize() error {
+	if d.chip == LC709203F {
+		for _, w := range [][2]uint16{
+			{lc709203fRegPowerMode, lc709203fPowerOperational},
+			{lc709203fRegAPA, uint16(d.pack)},
+			{lc709203fRegProfile, 0x0001},
+			// the temperature is set by I2C rather than by a thermistor
+			{lc709203fRegThermistor, 0x0000},
+			{lc709203fRegInitialRSOC, lc709203fInitRSOC},
+		} {
+			if err := d.writeLC709203F(uint8(w[0]), w[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	version, err := d.readMAX17048Word(max17048RegVersion)
+	if err != nil {
+		return err
+	}
+	if version&max17048VersionMask != max17048Version {
+		return ErrBadDevice{Device: "MAX17048", ID: int(version)}
+	}
+	return nil
+}
+
+// readMAX17048 reads the cell voltage, in 78.125µV, the state of charge, in
+// 1/256%, and the charge rate, in 0.208%/h, the mutex must be held
+func (d *FuelGaugeDriver) readMAX17048() (FuelGaugeReading, error) {
+	vcell, err := d.readMAX17048Word(max17048RegVCell)
+	if err != nil {
+		return FuelGaugeReading{}, err
+	}
+	soc, err := d.readMAX17048Word(max17048RegSOC)
+	if err != nil {
+		return FuelGaugeReading{}, err
+	}
+	crate, err := d.readMAX17048Word(max17048RegCRate)
+	if err != nil {
+		return FuelGaugeReading{}, err
+	}
+	return FuelGaugeReading{
+		StateOfCharge: float64(soc) / 256,
+		Voltage:       gobot.Voltage(vcell) * 78.125 * gobot.Microvolt,
+		Rate:          float64(int16(crate)) * 0.208,
+	}, nil
+}
+
+// readLC709203F reads the cell voltage, in mV, and the state of charge, in
+// 0.1%, the mutex must be held
+func (d *FuelGaugeDriver) readLC709203F() (FuelGaugeReading, error) {
+	mv, err := d.connection.ReadWordData(lc709203fRegCellVoltage)
+	if err != nil {
+		return FuelGaugeReading{}, ErrRegisterRead{Reg: lc709203fRegCellVoltage, Err: err}
+	}
+	ite, err := d.connection.ReadWordData(lc709203fRegITE)
+	if err != nil {
+		return FuelGaugeReading{}, ErrRegisterRead{Reg: lc709203fRegITE, Err: err}
+	}
+	soc := float64(ite) / 10
+
+	now := gobot.DefaultClock().Now()
+	if d.rateFromTime.IsZero() {
+		d.rateFrom, d.rateFromTime = soc, now
+	} else if elapsed := now.Sub(d.rateFromTime); elapsed >= fuelGaugeRateWindow {
+		d.rate = (soc - d.rateFrom) / elapsed.Hours()
+		d.rateFrom, d.rateFromTime = soc, now
+	}
+	return FuelGaugeReading{
+		StateOfCharge: soc,
+		Voltage:       gobot.Voltage(mv) * gobot.Millivolt,
+		Rate:          d.rate,
+	}, nil
+}
+
+// crossed returns the event of the threshold crossed by soc, if any
+func (d *FuelGaugeDriver) crossed(soc float64) string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	level := batteryOK
+	switch {
+	case soc <= d.critical || (d.level == batteryCritical && soc <= d.critical+fuelGaugeHysteresis):
+		level = batteryCritical
+	case soc <= d.low || (d.level >= batteryLow && soc <= d.low+fuelGaugeHysteresis):
+		level = batteryLow
+	}
+
+	previous := d.level
+	d.level = level
+	switch {
+	case level == previous:
+		return ""
+	case level == batteryCritical:
+		return BatteryCritical
+	case level == batteryLow && previous != batteryCritical:
+		return BatteryLow
+	case level == batteryOK && previous != batteryUnknown:
+		return BatteryRecovered
+	}
+	return ""
+}
+
+// readMAX17048Word reads a big endian register of the MAX17048
+func (d *FuelGaugeDriver) readMAX17048Word(reg uint8) (uint16, error) {
+	w, err := d.connection.ReadWordData(reg)
+	if err != nil {
+		return 0, ErrRegisterRead{Reg: reg, Err: err}
+	}
+	return w<<8 | w>>8, nil
+}
+
+// writeLC709203F writes a little endian register of the LC709203F, followed
+// by the CRC-8 of the write the LC709203F requires
+func (d *FuelGaugeDriver) writeLC709203F(reg uint8, val uint16) error {
+	address := byte(d.GetAddressOrDefault(LC709203FAddress))
+	b := []byte{reg, byte(val), byte(val >> 8)}
+	crc := lc709203fCRC(append([]byte{address << 1}, b...))
+	_, err := d.connection.Write(append(b, crc))
+	return err
+}
+
+// lc709203fCRC returns the CRC-8 of b, of polynomial 0x07
+func lc709203fCRC(b []byte) byte {
+	var crc byte
+	for _, v := range b {
+		crc ^= v
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,172 @@
+package i2c_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/drivers/i2c/i2ctest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*i2c.FuelGaugeDriver)(nil)
+
+// setMAX17048 sets the big endian registers of a MAX17048
+func setMAX17048(dev *i2ctest.Device, reg uint8, val uint16) {
+	dev.SetRegisters(reg, byte(val>>8), byte(val))
+}
+
+func initTestMAX17048() (*i2c.FuelGaugeDriver, *i2ctest.Device) {
+	a := i2ctest.NewAdaptor()
+	dev := a.AddDevice(i2c.MAX17048Address)
+	setMAX17048(dev, 0x08, 0x0012)
+	// 3.7V, 55.5% and -10.4%/h
+	setMAX17048(dev, 0x02, 0xB900)
+	setMAX17048(dev, 0x04, 0x3780)
+	setMAX17048(dev, 0x16, 0xFFCE)
+	return i2c.NewFuelGaugeDriver(a), dev
+}
+
+func TestFuelGaugeDriver(t *testing.T) {
+	d, _ := initTestMAX17048()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "FuelGauge"), true)
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Refute(t, d.Command("Read"), nil)
+	gobottest.Assert(t, d.Events()[i2c.BatteryLow], i2c.BatteryLow)
+
+	d.SetName("battery")
+	gobottest.Assert(t, d.Name(), "battery")
+	_, err := d.Read()
+	gobottest.Assert(t, err, i2c.ErrNotConnected)
+}
+
+func TestFuelGaugeDriverMAX17048(t *testing.T) {
+	d, _ := initTestMAX17048()
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	r, err := d.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r.StateOfCharge, 55.5)
+	gobottest.Assert(t, math.Abs(r.Voltage.Volts()-3.7) < 1e-9, true)
+	gobottest.Assert(t, math.Abs(r.Rate+10.4) < 1e-9, true)
+	gobottest.Assert(t, r.TimeToEmpty().Round(time.Minute), 5*time.Hour+20*time.Minute)
+
+	soc, err := d.StateOfCharge()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, soc, 55.5)
+	v, err := d.Voltage()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, v.Millivolts() > 3699 && v.Millivolts() < 3701, true)
+
+	result := d.Command("Read")(map[string]interface{}{}).(map[string]interface{})
+	gobottest.Assert(t, result["stateOfCharge"], 55.5)
+	gobottest.Assert(t, result["err"], nil)
+}
+
+func TestFuelGaugeDriverBadDevice(t *testing.T) {
+	d, dev := initTestMAX17048()
+	setMAX17048(dev, 0x08, 0x0400)
+	gobottest.Assert(t, d.Start(), i2c.ErrBadDevice{Device: "MAX17048", ID: 0x0400})
+}
+
+func TestFuelGaugeDriverReadError(t *testing.T) {
+	d, dev := initTestMAX17048()
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	dev.FailRead(0x04, errors.New("read error"))
+	_, err := d.Read()
+	gobottest.Assert(t, err, i2c.ErrRegisterRead{Reg: 0x04, Err: errors.New("read error")})
+}
+
+func TestFuelGaugeDriverLC709203F(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := i2ctest.NewAdaptor()
+	dev := a.AddDevice(i2c.LC709203FAddress)
+	// 80.0%
+	dev.SetRegisters(0x0F, 0x20, 0x03)
+	d := i2c.NewFuelGaugeDriver(a, i2c.WithFuelGaugeChip(i2c.LC709203F), i2c.WithLC709203FPack(i2c.LC709203FPack500mAh))
+	readings := make(chan bool, 10)
+	d.On(i2c.Battery, func(interface{}) { readings <- true })
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+	<-readings
+
+	// the writes are followed by their CRC-8, of the address byte too
+	ops := []i2ctest.Op{}
+	for _, op := range dev.Ops() {
+		if op.Kind == i2ctest.WriteOp {
+			ops = append(ops, op)
+		}
+	}
+	gobottest.Assert(t, len(ops), 5)
+	gobottest.Assert(t, ops[0], i2ctest.Write(0x15, 0x01, 0x00, 0x64))
+	gobottest.Assert(t, ops[1].Data[:2], []byte{0x10, 0x00})
+	gobottest.Assert(t, ops[4].Data[:2], []byte{0x55, 0xAA})
+
+	// 3.9V, the register being overwritten by the CRC of the last write
+	dev.SetRegisters(0x09, 0x3C, 0x0F)
+	r, err := d.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r.StateOfCharge, 80.0)
+	gobottest.Assert(t, r.Voltage.Millivolts(), 3900.0)
+	gobottest.Assert(t, r.Rate, 0.0)
+
+	// the rate is estimated after a minute, 79.5% after 3 minutes
+	dev.SetRegisters(0x0F, 0x1B, 0x03)
+	clock.Advance(3 * time.Minute)
+	r, err = d.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, math.Abs(r.Rate+10) < 1e-9, true)
+}
+
+func TestFuelGaugeDriverThresholds(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := i2ctest.NewAdaptor()
+	dev := a.AddDevice(i2c.MAX17048Address)
+	setMAX17048(dev, 0x08, 0x0012)
+	setMAX17048(dev, 0x04, 50*256)
+	d := i2c.NewFuelGaugeDriver(a, i2c.WithFuelGaugeThresholds(20, 5))
+	d.SetPolling(gobot.PollerConfig{Interval: time.Second})
+	events := d.Subscribe()
+	defer d.Unsubscribe(events)
+
+	// next returns the event published with the next reading, if any
+	next := func() string {
+		evt := <-events
+		gobottest.Assert(t, evt.Name, i2c.Battery)
+		select {
+		case evt := <-events:
+			return evt.Name
+		case <-time.After(20 * time.Millisecond):
+			return ""
+		}
+	}
+	poll := func(soc float64) string {
+		setMAX17048(dev, 0x04, uint16(soc*256))
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		return next()
+	}
+
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+	gobottest.Assert(t, next(), "")
+	gobottest.Assert(t, poll(19), i2c.BatteryLow)
+	gobottest.Assert(t, poll(20.5), "")
+	gobottest.Assert(t, poll(4), i2c.BatteryCritical)
+	gobottest.Assert(t, poll(15), "")
+	gobottest.Assert(t, poll(30), i2c.BatteryRecovered)
+	gobottest.Assert(t, poll(21.5), "")
+}
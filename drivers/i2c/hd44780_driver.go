@@ -0,0 +1,367 @@
+package i2c
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const hd44780Address = 0x27
+
+// The pins of the PCF8574 backpack wired to the HD44780, its data lines D4 to
+// D7 being wired to P4 to P7.
+const (
+	HD44780_RS        = 0x01
+	HD44780_RW        = 0x02
+	HD44780_EN        = 0x04
+	HD44780_BACKLIGHT = 0x08
+)
+
+// HD44780Driver is a driver for the character LCDs with a HD44780 controller,
+// such as the LCD1602 and LCD2004 modules, driven through a PCF8574 I2C
+// backpack in 4-bit mode.
+type HD44780Driver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	cols       int
+	rows       int
+	control    byte
+	entryMode  byte
+	backlight  byte
+	Config
+	driverConfig
+}
+
+// NewHD44780Driver creates a new driver for a LCD of cols columns and rows
+// rows, e.g. 16 and 2 for a LCD1602 or 20 and 4 for a LCD2004.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//		cols int - the number of characters per line
+//		rows int - the number of lines
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver, 0x27 by default,
+//					0x3F for the PCF8574A backpacks
+//
+func NewHD44780Driver(a Connector, cols int, rows int, options ...func(Config)) *HD44780Driver {
+	h := &HD44780Driver{
+		name:      gobot.DefaultName("HD44780"),
+		connector: a,
+		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
+		cols:      cols,
+		rows:      rows,
+		control:   LCD_DISPLAYON | LCD_CURSOROFF | LCD_BLINKOFF,
+		entryMode: LCD_ENTRYLEFT | LCD_ENTRYSHIFTDECREMENT,
+		backlight: HD44780_BACKLIGHT,
+	}
+
+	h.driverConfig = driverConfig{config: &h.Config}
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+// Name returns the name the HD44780 Driver was given when created.
+func (h *HD44780Driver) Name() string { return h.name }
+
+// SetName sets the name for the HD44780 Driver.
+func (h *HD44780Driver) SetName(n string) { h.name = n }
+
+// Connection returns the driver connection to the device.
+func (h *HD44780Driver) Connection() gobot.Connection {
+	return h.connector.(gobot.Connection)
+}
+
+// Columns returns the number of characters per line
+func (h *HD44780Driver) Columns() int { return h.cols }
+
+// Rows returns the number of lines
+func (h *HD44780Driver) Rows() int { return h.rows }
+
+// Start initializes the LCD in 4-bit mode, and clears it.
+func (h *HD44780Driver) Start() (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.connection, err = connect(h, h.connector, h.Config, hd44780Address); err != nil {
+		return err
+	}
+
+	// according to datasheet, we need at least 40ms after power rises above
+	// 2.7V before sending commands
+	time.Sleep(50 * time.Millisecond)
+
+	// this is according to the hitachi HD44780 datasheet
+	// page 46 figure 24: set 8-bit mode three times, whatever the current
+	// mode, then 4-bit mode
+	for _, delay := range []time.Duration{4500 * time.Microsecond, 4500 * time.Microsecond, 150 * time.Microsecond} {
+		if err = h.writeNibble(0x03, 0); err != nil {
+			return
+		}
+		time.Sleep(delay)
+	}
+	if err = h.writeNibble(0x02, 0); err != nil {
+		return
+	}
+
+	function := byte(LCD_FUNCTIONSET)
+	if h.rows > 1 {
+		function |= LCD_2LINE
+	}
+	if err = h.command(function); err != nil {
+		return
+	}
+	if err = h.command(LCD_DISPLAYCONTROL | h.control); err != nil {
+		return
+	}
+	if err = h.clear(); err != nil {
+		return
+	}
+	return h.command(LCD_ENTRYMODESET | h.entryMode)
+}
+
+// Halt is a noop function.
+func (h *HD44780Driver) Halt() error { return nil }
+
+// Clear clears the text on the LCD display, and moves the cursor to the
+// origin.
+func (h *HD44780Driver) Clear() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.clear()
+}
+
+func (h *HD44780Driver) clear() error {
+	err := h.command(LCD_CLEARDISPLAY)
+	// clearing the display takes 1.52ms
+	time.Sleep(2 * time.Millisecond)
+	return err
+}
+
+// Home moves the cursor to the origin position on the display, and undoes
+// the scrolling.
+func (h *HD44780Driver) Home() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	err := h.command(LCD_RETURNHOME)
+	time.Sleep(2 * time.Millisecond)
+	return err
+}
+
+// Write displays message at the cursor position. A newline moves the cursor
+// to the start of the next line.
+func (h *HD44780Driver) Write(message string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	row := 0
+	for _, val := range message {
+		if val == '\n' {
+			row++
+			if err := h.setCursor(0, row); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := h.send(byte(val), HD44780_RS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteLines displays lines from the first line of the display, each line
+// being truncated or padded with spaces to the width of the display, which
+// does not flicker like clearing it.
+func (h *HD44780Driver) WriteLines(lines []string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(lines) > h.rows {
+		return fmt.Errorf("%d lines can not be displayed on %d rows", len(lines), h.rows)
+	}
+	for row, line := range lines {
+		if err := h.writeLine(row, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TextSize implements the Displayer interface and returns the number of
+// columns and rows of the display
+func (h *HD44780Driver) TextSize() (cols int, rows int) { return h.cols, h.rows }
+
+// WriteLine implements the Displayer interface and displays text on row,
+// truncated or padded with spaces to the width of the display
+func (h *HD44780Driver) WriteLine(row int, text string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.writeLine(row, text)
+}
+
+func (h *HD44780Driver) writeLine(row int, line string) error {
+	if err := h.setCursor(0, row); err != nil {
+		return err
+	}
+	if len(line) > h.cols {
+		line = line[:h.cols]
+	}
+	line += strings.Repeat(" ", h.cols-len(line))
+	for i := 0; i < len(line); i++ {
+		if err := h.send(line[i], HD44780_RS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPosition moves the cursor to pos, counted in characters from the start
+// of the first line, e.g. 16 is the start of the second line of a LCD1602.
+func (h *HD44780Driver) SetPosition(pos int) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if pos < 0 || pos >= h.cols*h.rows {
+		return ErrInvalidPosition
+	}
+	return h.setCursor(pos%h.cols, pos/h.cols)
+}
+
+// SetCursor moves the cursor to column col of line row, counted from 0.
+func (h *HD44780Driver) SetCursor(col int, row int) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.setCursor(col, row)
+}
+
+func (h *HD44780Driver) setCursor(col int, row int) error {
+	if col < 0 || col >= h.cols || row < 0 || row >= h.rows {
+		return ErrInvalidPosition
+	}
+	// the third and fourth lines continue the first and second ones
+	offsets := []int{0x00, 0x40, h.cols, 0x40 + h.cols}
+	return h.command(LCD_SETDDRAMADDR | byte(offsets[row]+col))
+}
+
+// Display turns the display on or off, keeping its content.
+func (h *HD44780Driver) Display(on bool) error {
+	return h.setControl(LCD_DISPLAYON, on)
+}
+
+// Cursor shows or hides the underline cursor.
+func (h *HD44780Driver) Cursor(on bool) error {
+	return h.setControl(LCD_CURSORON, on)
+}
+
+// Blink turns the blinking block cursor on or off.
+func (h *HD44780Driver) Blink(on bool) error {
+	return h.setControl(LCD_BLINKON, on)
+}
+
+func (h *HD44780Driver) setControl(flag byte, on bool) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if on {
+		h.control |= flag
+	} else {
+		h.control &^= flag
+	}
+	return h.command(LCD_DISPLAYCONTROL | h.control)
+}
+
+// Backlight turns the backlight on or off.
+func (h *HD44780Driver) Backlight(on bool) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if on {
+		h.backlight = HD44780_BACKLIGHT
+	} else {
+		h.backlight = 0
+	}
+	return h.connection.WriteByte(h.backlight)
+}
+
+// Scroll moves the whole content of the display by one character, to the
+// left when leftToRight is true, so that the text scrolls like a marquee.
+func (h *HD44780Driver) Scroll(leftToRight bool) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if leftToRight {
+		return h.command(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVELEFT)
+	}
+	return h.command(LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVERIGHT)
+}
+
+// Autoscroll makes the display scroll with each character written, so that
+// the text appears to come from the cursor position.
+func (h *HD44780Driver) Autoscroll(on bool) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if on {
+		h.entryMode |= LCD_ENTRYSHIFTINCREMENT
+	} else {
+		h.entryMode &^= LCD_ENTRYSHIFTINCREMENT
+	}
+	return h.command(LCD_ENTRYMODESET | h.entryMode)
+}
+
+// SetCustomChar sets one of the 8 CGRAM locations with a custom character,
+// shown by writing a byte of value 0 to 7. See JHD1313M1Driver.SetCustomChar
+// and CustomLCDChars.
+func (h *HD44780Driver) SetCustomChar(pos int, charMap [8]byte) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if pos < 0 || pos > 7 {
+		return fmt.Errorf("can't set a custom character at a position greater than 7")
+	}
+	if err := h.command(LCD_SETCGRAMADDR | byte(pos)<<3); err != nil {
+		return err
+	}
+	for _, row := range charMap {
+		// the characters are 5 dots wide
+		if err := h.send(row&0x1F, HD44780_RS); err != nil {
+			return err
+		}
+	}
+	// back to the display data
+	return h.command(LCD_SETDDRAMADDR)
+}
+
+func (h *HD44780Driver) command(value byte) error {
+	return h.send(value, 0)
+}
+
+// send sends value as two nibbles, the high one first, in a single I2C
+// transaction
+func (h *HD44780Driver) send(value byte, mode byte) error {
+	high := value&0xF0 | mode | h.backlight
+	low := value<<4 | mode | h.backlight
+	_, err := h.connection.Write([]byte{high | HD44780_EN, high, low | HD44780_EN, low})
+	return err
+}
+
+// writeNibble sends the low nibble of value, during the initialization
+func (h *HD44780Driver) writeNibble(value byte, mode byte) error {
+	data := value<<4 | mode | h.backlight
+	_, err := h.connection.Write([]byte{data | HD44780_EN, data})
+	return err
+}
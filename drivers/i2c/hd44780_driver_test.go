@@ -0,0 +1,184 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*HD44780Driver)(nil)
+var _ gobot.TextDisplay = (*HD44780Driver)(nil)
+var _ Displayer = (*HD44780Driver)(nil)
+
+// --------- HELPERS
+func initTestHD44780DriverWithStubbedAdaptor() (*HD44780Driver, *i2cTestAdaptor) {
+	adaptor := newI2cTestAdaptor()
+	d := NewHD44780Driver(adaptor, 16, 2)
+	d.Start()
+	adaptor.written = []byte{}
+	return d, adaptor
+}
+
+// hd44780Bytes decodes the commands and data written to the PCF8574, as
+// "c" or "d" followed by the byte
+func hd44780Bytes(written []byte) (decoded []string) {
+	for i := 0; i+3 < len(written); i += 4 {
+		kind := "c"
+		if written[i]&HD44780_RS != 0 {
+			kind = "d"
+		}
+		decoded = append(decoded, kind+string([]byte{written[i]&0xF0 | written[i+2]>>4}))
+	}
+	return
+}
+
+// --------- TESTS
+
+func TestHD44780Driver(t *testing.T) {
+	d := NewHD44780Driver(newI2cTestAdaptor(), 20, 4)
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "HD44780"), true)
+	d.SetName("TESTME")
+	gobottest.Assert(t, d.Name(), "TESTME")
+	gobottest.Assert(t, d.Columns(), 20)
+	gobottest.Assert(t, d.Rows(), 4)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestHD44780DriverOptions(t *testing.T) {
+	d := NewHD44780Driver(newI2cTestAdaptor(), 16, 2, WithBus(2), WithAddress(0x3F))
+	gobottest.Assert(t, d.GetBusOrDefault(1), 2)
+	gobottest.Assert(t, d.GetAddressOrDefault(hd44780Address), 0x3F)
+}
+
+func TestHD44780DriverStart(t *testing.T) {
+	a := newI2cTestAdaptor()
+	d := NewHD44780Driver(a, 16, 2)
+	gobottest.Assert(t, d.Start(), nil)
+
+	// the 4-bit mode is set with single nibbles, strobing EN, with the
+	// backlight on
+	gobottest.Assert(t, a.written[:8], []byte{0x3C, 0x38, 0x3C, 0x38, 0x3C, 0x38, 0x2C, 0x28})
+	gobottest.Assert(t, hd44780Bytes(a.written[8:]), []string{
+		"c\x28", "c\x0C", "c\x01", "c\x06",
+	})
+}
+
+func TestHD44780DriverStartConnectError(t *testing.T) {
+	a := newI2cTestAdaptor()
+	d := NewHD44780Driver(a, 16, 2)
+	a.Testi2cConnectErr(true)
+	gobottest.Assert(t, d.Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestHD44780DriverStartWriteError(t *testing.T) {
+	a := newI2cTestAdaptor()
+	d := NewHD44780Driver(a, 16, 2)
+	a.Testi2cWriteImpl(func([]byte) (int, error) {
+		return 0, errors.New("write error")
+	})
+	gobottest.Assert(t, d.Start(), errors.New("write error"))
+}
+
+func TestHD44780DriverWrite(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Write("Hi\nyo"), nil)
+	gobottest.Assert(t, hd44780Bytes(a.written), []string{"dH", "di", "c\xC0", "dy", "do"})
+	gobottest.Assert(t, d.Write("\n\n"), ErrInvalidPosition)
+}
+
+func TestHD44780DriverWriteLines(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.WriteLines([]string{"Temperature: 21.5C", "ok"}), nil)
+
+	var text string
+	for _, b := range hd44780Bytes(a.written) {
+		text += b[1:]
+	}
+	gobottest.Assert(t, text, "\x80Temperature: 21.\xC0ok              ")
+	gobottest.Assert(t, d.WriteLines([]string{"a", "b", "c"}), errors.New("3 lines can not be displayed on 2 rows"))
+}
+
+func TestHD44780DriverWriteLine(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	cols, rows := d.TextSize()
+	gobottest.Assert(t, cols, 16)
+	gobottest.Assert(t, rows, 2)
+	gobottest.Assert(t, d.WriteLine(1, "ok"), nil)
+
+	var text string
+	for _, b := range hd44780Bytes(a.written) {
+		text += b[1:]
+	}
+	gobottest.Assert(t, text, "\xC0ok              ")
+	gobottest.Assert(t, d.WriteLine(2, "ok"), ErrInvalidPosition)
+}
+
+func TestHD44780DriverSetPosition(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.SetPosition(17), nil)
+	gobottest.Assert(t, d.SetPosition(32), ErrInvalidPosition)
+	gobottest.Assert(t, d.SetPosition(-1), ErrInvalidPosition)
+	gobottest.Assert(t, hd44780Bytes(a.written), []string{"c\xC1"})
+}
+
+func TestHD44780DriverSetCursor(t *testing.T) {
+	a := newI2cTestAdaptor()
+	d := NewHD44780Driver(a, 20, 4)
+	d.Start()
+	a.written = []byte{}
+
+	// the third line continues the first one
+	gobottest.Assert(t, d.SetCursor(3, 2), nil)
+	gobottest.Assert(t, d.SetCursor(0, 3), nil)
+	gobottest.Assert(t, d.SetCursor(20, 0), ErrInvalidPosition)
+	gobottest.Assert(t, hd44780Bytes(a.written), []string{"c\x97", "c\xD4"})
+}
+
+func TestHD44780DriverDisplayControl(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Cursor(true), nil)
+	gobottest.Assert(t, d.Blink(true), nil)
+	gobottest.Assert(t, d.Display(false), nil)
+	gobottest.Assert(t, d.Cursor(false), nil)
+	gobottest.Assert(t, hd44780Bytes(a.written), []string{"c\x0E", "c\x0F", "c\x0B", "c\x09"})
+}
+
+func TestHD44780DriverBacklight(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Backlight(false), nil)
+	gobottest.Assert(t, d.Write("A"), nil)
+	gobottest.Assert(t, d.Backlight(true), nil)
+	gobottest.Assert(t, a.written, []byte{0x00, 0x45, 0x41, 0x15, 0x11, 0x08})
+}
+
+func TestHD44780DriverScroll(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Scroll(true), nil)
+	gobottest.Assert(t, d.Scroll(false), nil)
+	gobottest.Assert(t, d.Autoscroll(true), nil)
+	gobottest.Assert(t, d.Autoscroll(false), nil)
+	gobottest.Assert(t, hd44780Bytes(a.written), []string{"c\x18", "c\x1C", "c\x07", "c\x06"})
+}
+
+func TestHD44780DriverClearHome(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Clear(), nil)
+	gobottest.Assert(t, d.Home(), nil)
+	gobottest.Assert(t, hd44780Bytes(a.written), []string{"c\x01", "c\x02"})
+}
+
+func TestHD44780DriverSetCustomChar(t *testing.T) {
+	d, a := initTestHD44780DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.SetCustomChar(1, CustomLCDChars["smiley"]), nil)
+	decoded := hd44780Bytes(a.written)
+	gobottest.Assert(t, len(decoded), 10)
+	gobottest.Assert(t, decoded[0], "c\x48")
+	gobottest.Assert(t, decoded[1][0], byte('d'))
+	gobottest.Assert(t, decoded[9], "c\x80")
+
+	gobottest.Assert(t, d.SetCustomChar(8, CustomLCDChars["smiley"]), errors.New("can't set a custom character at a position greater than 7"))
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+
+	"gobot.io/x/gobot"
 )
 
 var rgb = map[string]interface{}{
@@ -16,6 +18,12 @@ func castColor(color string) byte {
 	return byte(rgb[color].(float64))
 }
 
+func debugLogger() gobot.Logger {
+	l := gobot.NewLogger()
+	l.SetLevel("", gobot.LogDebug)
+	return l
+}
+
 var red = castColor("red")
 var green = castColor("green")
 var blue = castColor("blue")
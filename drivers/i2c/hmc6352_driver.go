@@ -1,6 +1,11 @@
 package i2c
 
-import "gobot.io/x/gobot"
+import (
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
 
 const hmc6352Address = 0x21
 
@@ -9,7 +14,9 @@ type HMC6352Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
 }
 
 // NewHMC6352Driver creates a new driver with specified i2c interface
@@ -25,8 +32,10 @@ func NewHMC6352Driver(a Connector, options ...func(Config)) *HMC6352Driver {
 		name:      gobot.DefaultName("HMC6352"),
 		connector: a,
 		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
 	}
 
+	hmc.driverConfig = driverConfig{config: &hmc.Config}
 	for _, option := range options {
 		option(hmc)
 	}
@@ -45,10 +54,10 @@ func (h *HMC6352Driver) Connection() gobot.Connection { return h.connector.(gobo
 
 // Start initializes the hmc6352
 func (h *HMC6352Driver) Start() (err error) {
-	bus := h.GetBusOrDefault(h.connector.GetDefaultBus())
-	address := h.GetAddressOrDefault(hmc6352Address)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
 
-	h.connection, err = h.connector.GetConnection(address, bus)
+	h.connection, err = connect(h, h.connector, h.Config, hmc6352Address)
 	if err != nil {
 		return err
 	}
@@ -64,6 +73,9 @@ func (h *HMC6352Driver) Halt() (err error) { return }
 
 // Heading returns the current heading
 func (h *HMC6352Driver) Heading() (heading uint16, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	if _, err = h.connection.Write([]byte("A")); err != nil {
 		return
 	}
@@ -9,6 +9,7 @@ import (
 )
 
 var _ gobot.Driver = (*HMC6352Driver)(nil)
+var _ gobot.HeadingSensor = (*HMC6352Driver)(nil)
 
 // --------- HELPERS
 func initTestHMC6352Driver() (driver *HMC6352Driver) {
@@ -1,9 +1,14 @@
 package i2c
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
 	"sync"
+
+	"gobot.io/x/gobot"
 )
 
 const (
@@ -11,12 +16,27 @@ const (
 	Error = "error"
 )
 
+// Names of the Spans of the I/O operations on the I2C devices
+const (
+	I2cReadSpan  = "i2c.read"
+	I2cWriteSpan = "i2c.write"
+)
+
 const (
 	// BusNotInitialized is the initial value for a bus
 	BusNotInitialized = -1
 
 	// AddressNotInitialized is the initial value for an address
 	AddressNotInitialized = -1
+
+	// SpeedNotInitialized is the initial value for a bus speed
+	SpeedNotInitialized = -1
+
+	// StandardSpeed is the 100kHz clock of the standard mode
+	StandardSpeed = 100000
+
+	// FastSpeed is the 400kHz clock of the fast mode
+	FastSpeed = 400000
 )
 
 var (
@@ -24,8 +44,41 @@ var (
 	ErrNotEnoughBytes  = errors.New("Not enough bytes read")
 	ErrNotReady        = errors.New("Device is not ready")
 	ErrInvalidPosition = errors.New("Invalid position value")
+	// ErrNotConnected is returned when talking to a device without a bus,
+	// such as a driver used before Start
+	ErrNotConnected = errors.New("Device is not connected")
 )
 
+// ErrBadDevice is returned by the drivers checking the identifier of their
+// device on Start, when another one answers at the address.
+type ErrBadDevice struct {
+	Device string
+	ID     int
+}
+
+func (e ErrBadDevice) Error() string {
+	return fmt.Sprintf("%s device not found, unexpected ID 0x%02X", e.Device, e.ID)
+}
+
+// ErrRegisterRead is returned when reading a register of a device fails. The
+// original error is available using Unwrap.
+type ErrRegisterRead struct {
+	Reg uint8
+	// Name is the name of the register, if known
+	Name string
+	Err  error
+}
+
+func (e ErrRegisterRead) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("reading register %s (0x%02X): %v", e.Name, e.Reg, e.Err)
+	}
+	return fmt.Sprintf("reading register 0x%02X: %v", e.Reg, e.Err)
+}
+
+// Unwrap returns the original error
+func (e ErrRegisterRead) Unwrap() error { return e.Err }
+
 type I2cOperations interface {
 	io.ReadWriteCloser
 	ReadByte() (val byte, err error)
@@ -55,6 +108,15 @@ type Connector interface {
 	GetDefaultBus() int
 }
 
+// BusSpeedSetter is implemented by the Connectors able to change the clock
+// of their buses. The Linux i2c-dev interface is not, the speed of its buses
+// being set by the device tree, e.g. with "dtparam=i2c_arm_baudrate" on a
+// Raspberry Pi.
+type BusSpeedSetter interface {
+	// SetBusSpeed sets the clock of the bus, in Hertz
+	SetBusSpeed(bus int, hz int) error
+}
+
 // Connection is a connection to an I2C device with a specified address
 // on a specific bus. Used as an alternative to the I2c interface.
 // Implements I2cOperations to talk to the device, wrapping the
@@ -65,21 +127,86 @@ type Connection I2cOperations
 type i2cConnection struct {
 	bus     I2cDevice
 	address int
-	mutex   *sync.Mutex
+	lock    *busLock
+	closed  bool
+}
+
+// busLock is the lock shared by the Connections to the devices of a bus,
+// their transactions being made of a SetAddress and of a transfer. It is
+// kept as long as a Connection to the bus is open.
+type busLock struct {
+	sync.Mutex
+	connections int
 }
 
+// busLocks holds the lock of each bus with open Connections
+var (
+	busLocks      = make(map[I2cDevice]*busLock)
+	busLocksMutex sync.Mutex
+)
+
 // NewConnection creates and returns a new connection to a specific
-// i2c device on a bus and address.
+// i2c device on a bus and address. The Connections to the devices of a bus
+// share a lock, so that their transactions are not interleaved.
 func NewConnection(bus I2cDevice, address int) (connection *i2cConnection) {
-	return &i2cConnection{bus: bus, address: address, mutex: &sync.Mutex{}}
+	return &i2cConnection{bus: bus, address: address, lock: acquireBusLock(bus)}
+}
+
+// CloseBus closes a bus once its ongoing transaction is done, and forgets
+// the lock of its Connections. Adaptors close their buses with it when they
+// are finalized.
+func CloseBus(bus I2cDevice) error {
+	busLocksMutex.Lock()
+	lock, ok := busLocks[bus]
+	delete(busLocks, bus)
+	busLocksMutex.Unlock()
+	if ok {
+		lock.Lock()
+		defer lock.Unlock()
+	}
+	return bus.Close()
+}
+
+// acquireBusLock returns the lock of the Connections to bus, counting one
+// more Connection
+func acquireBusLock(bus I2cDevice) *busLock {
+	if bus == nil || !reflect.TypeOf(bus).Comparable() {
+		return &busLock{}
+	}
+	busLocksMutex.Lock()
+	defer busLocksMutex.Unlock()
+	lock, ok := busLocks[bus]
+	if !ok {
+		lock = &busLock{}
+		busLocks[bus] = lock
+	}
+	lock.connections++
+	return lock
+}
+
+// releaseBusLock counts one Connection to bus less, forgetting its lock
+// once the last one is closed
+func releaseBusLock(bus I2cDevice, lock *busLock) {
+	busLocksMutex.Lock()
+	defer busLocksMutex.Unlock()
+	if busLocks[bus] != lock {
+		return
+	}
+	lock.connections--
+	if lock.connections == 0 {
+		delete(busLocks, bus)
+	}
 }
 
 // Read data from an i2c device.
 func (c *i2cConnection) Read(data []byte) (read int, err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cReadSpan, "Read", -1, len(data))
+	defer func() { gobot.EndSpan(span, err) }()
 
-	if err = c.bus.SetAddress(c.address); err != nil {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err = c.setAddress(); err != nil {
 		return 0, err
 	}
 	read, err = c.bus.Read(data)
@@ -88,10 +215,13 @@ func (c *i2cConnection) Read(data []byte) (read int, err error) {
 
 // Write data to an i2c device.
 func (c *i2cConnection) Write(data []byte) (written int, err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cWriteSpan, "Write", -1, len(data))
+	defer func() { gobot.EndSpan(span, err) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	if err = c.bus.SetAddress(c.address); err != nil {
+	if err = c.setAddress(); err != nil {
 		return 0, err
 	}
 	written, err = c.bus.Write(data)
@@ -100,18 +230,28 @@ func (c *i2cConnection) Write(data []byte) (written int, err error) {
 
 // Close connection to i2c device.
 func (c *i2cConnection) Close() error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
+	if c.bus == nil {
+		return ErrNotConnected
+	}
+	if !c.closed {
+		c.closed = true
+		releaseBusLock(c.bus, c.lock)
+	}
 	return c.bus.Close()
 }
 
 // ReadByte reads a single byte from the i2c device.
 func (c *i2cConnection) ReadByte() (val byte, err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cReadSpan, "ReadByte", -1, -1)
+	defer func() { gobot.EndSpan(span, err) }()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.setAddress(); err != nil {
 		return 0, err
 	}
 	return c.bus.ReadByte()
@@ -119,10 +259,13 @@ func (c *i2cConnection) ReadByte() (val byte, err error) {
 
 // ReadByteData reads a byte value for a register on the i2c device.
 func (c *i2cConnection) ReadByteData(reg uint8) (val uint8, err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cReadSpan, "ReadByteData", int(reg), -1)
+	defer func() { gobot.EndSpan(span, err) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	if err := c.setAddress(); err != nil {
 		return 0, err
 	}
 	return c.bus.ReadByteData(reg)
@@ -130,10 +273,13 @@ func (c *i2cConnection) ReadByteData(reg uint8) (val uint8, err error) {
 
 // ReadWordData reads a word value for a register on the i2c device.
 func (c *i2cConnection) ReadWordData(reg uint8) (val uint16, err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cReadSpan, "ReadWordData", int(reg), -1)
+	defer func() { gobot.EndSpan(span, err) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	if err := c.setAddress(); err != nil {
 		return 0, err
 	}
 	return c.bus.ReadWordData(reg)
@@ -141,10 +287,13 @@ func (c *i2cConnection) ReadWordData(reg uint8) (val uint16, err error) {
 
 // WriteByte writes a single byte to the i2c device.
 func (c *i2cConnection) WriteByte(val byte) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cWriteSpan, "WriteByte", -1, -1)
+	defer func() { gobot.EndSpan(span, err) }()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.setAddress(); err != nil {
 		return err
 	}
 	return c.bus.WriteByte(val)
@@ -152,10 +301,13 @@ func (c *i2cConnection) WriteByte(val byte) (err error) {
 
 // WriteByteData writes a byte value to a register on the i2c device.
 func (c *i2cConnection) WriteByteData(reg uint8, val uint8) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cWriteSpan, "WriteByteData", int(reg), -1)
+	defer func() { gobot.EndSpan(span, err) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	if err := c.setAddress(); err != nil {
 		return err
 	}
 	return c.bus.WriteByteData(reg, val)
@@ -163,10 +315,13 @@ func (c *i2cConnection) WriteByteData(reg uint8, val uint8) (err error) {
 
 // WriteWordData writes a word value to a register on the i2c device.
 func (c *i2cConnection) WriteWordData(reg uint8, val uint16) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cWriteSpan, "WriteWordData", int(reg), -1)
+	defer func() { gobot.EndSpan(span, err) }()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.setAddress(); err != nil {
 		return err
 	}
 	return c.bus.WriteWordData(reg, val)
@@ -174,11 +329,48 @@ func (c *i2cConnection) WriteWordData(reg uint8, val uint16) (err error) {
 
 // WriteBlockData writes a block of bytes to a register on the i2c device.
 func (c *i2cConnection) WriteBlockData(reg uint8, b []byte) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	span := c.startSpan(I2cWriteSpan, "WriteBlockData", int(reg), len(b))
+	defer func() { gobot.EndSpan(span, err) }()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	if err := c.bus.SetAddress(c.address); err != nil {
+	if err := c.setAddress(); err != nil {
 		return err
 	}
 	return c.bus.WriteBlockData(reg, b)
 }
+
+// startSpan starts the Span of the operation op on the device, with the
+// register and the length of the transfer unless they are negative. It
+// returns nil when Tracing is off, so that the I/O does not pay for the
+// Spans. Connections are not given a context, so the Span is the root of
+// its own trace.
+func (c *i2cConnection) startSpan(name string, op string, reg int, length int) gobot.Span {
+	if !gobot.Tracing() {
+		return nil
+	}
+	attrs := []gobot.Attribute{
+		gobot.Attr("i2c.operation", op),
+		gobot.Attr("i2c.address", c.address),
+	}
+	if reg >= 0 {
+		attrs = append(attrs, gobot.Attr("i2c.register", reg))
+	}
+	if length >= 0 {
+		attrs = append(attrs, gobot.Attr("i2c.length", length))
+	}
+	if bus, ok := c.bus.(fmt.Stringer); ok {
+		attrs = append(attrs, gobot.Attr("i2c.bus", bus.String()))
+	}
+	_, span := gobot.StartSpan(context.Background(), name, attrs...)
+	return span
+}
+
+// setAddress targets the device on the bus, the lock of the bus being held
+func (c *i2cConnection) setAddress() error {
+	if c.bus == nil {
+		return ErrNotConnected
+	}
+	return c.bus.SetAddress(c.address)
+}
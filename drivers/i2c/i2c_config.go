@@ -1,8 +1,18 @@
 package i2c
 
+import (
+	"fmt"
+	"strings"
+
+	"gobot.io/x/gobot"
+)
+
 type i2cConfig struct {
-	bus     int
-	address int
+	bus       int
+	address   int
+	addresses []int
+	found     int
+	speed     int
 }
 
 // Config is the interface which describes how a Driver can specify
@@ -21,9 +31,42 @@ type Config interface {
 	GetAddressOrDefault(def int) int
 }
 
+// AddressesConfig is the interface of the Configs which can hold a list of
+// addresses to try, in addition to Config. The Config of NewConfig
+// implements it.
+type AddressesConfig interface {
+	// WithAddresses sets the addresses to try in order
+	WithAddresses(addresses []int)
+
+	// GetAddresses gets the addresses to try, if any
+	GetAddresses() []int
+}
+
+// SpeedConfig is the interface of the Configs which can hold the clock of
+// the bus, in addition to Config. The Config of NewConfig implements it.
+type SpeedConfig interface {
+	// WithSpeed sets the clock of the bus
+	WithSpeed(hz int)
+
+	// GetSpeedOrDefault gets the clock of the bus
+	GetSpeedOrDefault(def int) int
+}
+
+// addressFinder is implemented by the Configs holding the address found
+// among the addresses to try apart from them, for the next Start to try them
+// again.
+type addressFinder interface {
+	setFoundAddress(address int)
+}
+
 // NewConfig returns a new I2c Config.
 func NewConfig() Config {
-	return &i2cConfig{bus: BusNotInitialized, address: AddressNotInitialized}
+	return &i2cConfig{
+		bus:     BusNotInitialized,
+		address: AddressNotInitialized,
+		found:   AddressNotInitialized,
+		speed:   SpeedNotInitialized,
+	}
 }
 
 // WithBus sets preferred bus to use.
@@ -48,20 +91,28 @@ func WithBus(bus int) func(Config) {
 	}
 }
 
-// WithAddress sets which address to use.
+// WithAddress sets which address to use, replacing the addresses set using
+// WithAddresses().
 func (i *i2cConfig) WithAddress(address int) {
 	i.address = address
+	i.addresses = nil
+	i.found = AddressNotInitialized
 }
 
 // GetAddressOrDefault returns which address to use, either
-// the one set using WithBus(), or the default value which
-// is passed in as the param.
+// the one set using WithAddress(), or the default value which
+// is passed in as the param. Once a driver is started with
+// addresses set using WithAddresses(), it returns the one which
+// matched.
 func (i *i2cConfig) GetAddressOrDefault(a int) int {
-	if i.address == AddressNotInitialized {
-		return a
+	if i.address != AddressNotInitialized {
+		return i.address
+	}
+	if i.found != AddressNotInitialized {
+		return i.found
 	}
 
-	return i.address
+	return a
 }
 
 // WithAddress sets which address to use as a optional param.
@@ -70,3 +121,186 @@ func WithAddress(address int) func(Config) {
 		i.WithAddress(address)
 	}
 }
+
+// WithAddresses sets the addresses to try in order, for the boards shipping
+// with selectable addresses. The driver uses the first one answering a read
+// when it starts.
+func (i *i2cConfig) WithAddresses(addresses []int) {
+	i.addresses = append([]int(nil), addresses...)
+	i.address = AddressNotInitialized
+	i.found = AddressNotInitialized
+}
+
+// GetAddresses returns the addresses set using WithAddresses(), which are
+// tried until one matches.
+func (i *i2cConfig) GetAddresses() []int {
+	return i.addresses
+}
+
+func (i *i2cConfig) setFoundAddress(address int) {
+	i.found = address
+}
+
+// WithAddresses sets the addresses to try in order as a optional param. The
+// drivers of this package fail to start when their Config is not an
+// AddressesConfig, the other Configs ignore it.
+func WithAddresses(addresses []int) func(Config) {
+	return func(i Config) {
+		if a, ok := i.(AddressesConfig); ok {
+			a.WithAddresses(addresses)
+		}
+	}
+}
+
+// WithSpeed sets the clock of the bus, in Hertz, such as FastSpeed.
+func (i *i2cConfig) WithSpeed(hz int) {
+	i.speed = hz
+}
+
+// GetSpeedOrDefault returns the clock of the bus, either the one set using
+// WithSpeed(), or the default value which is passed in as the param.
+func (i *i2cConfig) GetSpeedOrDefault(d int) int {
+	if i.speed == SpeedNotInitialized {
+		return d
+	}
+
+	return i.speed
+}
+
+// WithSpeed sets the clock of the bus as a optional param. The clock is
+// shared by the devices of the bus, the driver started last setting it. A
+// Connector which can't change it, not being a BusSpeedSetter, keeps its
+// own. The drivers of this package fail to start when their Config is not a
+// SpeedConfig, the other Configs ignore it.
+func WithSpeed(hz int) func(Config) {
+	return func(i Config) {
+		if s, ok := i.(SpeedConfig); ok {
+			s.WithSpeed(hz)
+		}
+	}
+}
+
+// driverConfig is embedded by the drivers of this package along with their
+// Config, making them AddressesConfigs and SpeedConfigs as well. The methods
+// are forwarded to the Config; when it does not implement them, the setters
+// record an error returned by the next connect of the driver.
+type driverConfig struct {
+	config *Config
+	err    error
+}
+
+// extended returns the Config of the driver
+func (d *driverConfig) extended() Config {
+	if d.config == nil {
+		return nil
+	}
+	return *d.config
+}
+
+// WithAddresses sets the addresses to try in order on the Config
+func (d *driverConfig) WithAddresses(addresses []int) {
+	if a, ok := d.extended().(AddressesConfig); ok {
+		a.WithAddresses(addresses)
+		return
+	}
+	d.err = fmt.Errorf("The i2c Config %T can't hold the addresses to try", d.extended())
+}
+
+// GetAddresses returns the addresses to try of the Config, if any
+func (d *driverConfig) GetAddresses() []int {
+	if a, ok := d.extended().(AddressesConfig); ok {
+		return a.GetAddresses()
+	}
+	return nil
+}
+
+// WithSpeed sets the clock of the bus on the Config
+func (d *driverConfig) WithSpeed(hz int) {
+	if s, ok := d.extended().(SpeedConfig); ok {
+		s.WithSpeed(hz)
+		return
+	}
+	d.err = fmt.Errorf("The i2c Config %T can't hold the speed of the bus", d.extended())
+}
+
+// GetSpeedOrDefault returns the clock of the bus of the Config, or def
+func (d *driverConfig) GetSpeedOrDefault(def int) int {
+	if s, ok := d.extended().(SpeedConfig); ok {
+		return s.GetSpeedOrDefault(def)
+	}
+	return def
+}
+
+func (d *driverConfig) configError() error {
+	return d.err
+}
+
+// connect returns a connection to the device of driver on the bus of the
+// Config, at its address or def, after setting the speed of the bus. With
+// addresses set using WithAddresses(), the connection is to the first one
+// answering a read, which is the address of the Config until the next
+// connect tries them again. It fails when an option could not be set on the
+// Config.
+func connect(driver gobot.Driver, c Connector, cfg Config, def int) (Connection, error) {
+	if c == nil {
+		return nil, ErrNotConnected
+	}
+	if d, ok := driver.(interface{ configError() error }); ok {
+		if err := d.configError(); err != nil {
+			return nil, err
+		}
+	}
+	bus := cfg.GetBusOrDefault(c.GetDefaultBus())
+	speed := SpeedNotInitialized
+	if s, ok := cfg.(SpeedConfig); ok {
+		speed = s.GetSpeedOrDefault(SpeedNotInitialized)
+	}
+	if speed != SpeedNotInitialized {
+		if s, ok := c.(BusSpeedSetter); ok {
+			if err := s.SetBusSpeed(bus, speed); err != nil {
+				return nil, err
+			}
+		} else {
+			driverLogger(driver).Warn("bus speed can't be changed by the connector",
+				"bus", bus, "speed", speed)
+		}
+	}
+
+	var addresses []int
+	if a, ok := cfg.(AddressesConfig); ok {
+		addresses = a.GetAddresses()
+	}
+	if len(addresses) == 0 {
+		return c.GetConnection(cfg.GetAddressOrDefault(def), bus)
+	}
+
+	tried := []string{}
+	var lastErr error
+	for _, address := range addresses {
+		conn, err := c.GetConnection(address, bus)
+		if err == nil {
+			_, err = conn.ReadByte()
+		}
+		if err == nil {
+			if f, ok := cfg.(addressFinder); ok {
+				f.setFoundAddress(address)
+			}
+			driverLogger(driver).Info("device found",
+				"address", fmt.Sprintf("0x%02x", address), "bus", bus)
+			return conn, nil
+		}
+		lastErr = err
+		tried = append(tried, fmt.Sprintf("0x%02x", address))
+	}
+	return nil, fmt.Errorf("No i2c device answered at the addresses %v on bus %d: %v",
+		strings.Join(tried, ", "), bus, lastErr)
+}
+
+// driverLogger returns the Logger of driver, the DefaultLogger scoped to its
+// name unless it has its own
+func driverLogger(driver gobot.Driver) gobot.Logger {
+	if l, ok := driver.(interface{ Logger() gobot.Logger }); ok {
+		return l.Logger()
+	}
+	return gobot.DefaultLogger().WithComponent(driver.Name())
+}
@@ -0,0 +1,109 @@
+package i2c_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/drivers/i2c/i2ctest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestConfig(t *testing.T) {
+	c := i2c.NewConfig()
+	gobottest.Assert(t, c.GetBusOrDefault(1), 1)
+	gobottest.Assert(t, c.GetAddressOrDefault(0x39), 0x39)
+	gobottest.Assert(t, len(c.(i2c.AddressesConfig).GetAddresses()), 0)
+
+	i2c.WithBus(2)(c)
+	i2c.WithAddress(0x29)(c)
+	gobottest.Assert(t, c.GetBusOrDefault(1), 2)
+	gobottest.Assert(t, c.GetAddressOrDefault(0x39), 0x29)
+
+	i2c.WithAddresses([]int{0x29, 0x49})(c)
+	gobottest.Assert(t, c.(i2c.AddressesConfig).GetAddresses(), []int{0x29, 0x49})
+	gobottest.Assert(t, c.GetAddressOrDefault(0x39), 0x39)
+
+	i2c.WithAddress(0x29)(c)
+	gobottest.Assert(t, len(c.(i2c.AddressesConfig).GetAddresses()), 0)
+
+	gobottest.Assert(t, c.(i2c.SpeedConfig).GetSpeedOrDefault(i2c.StandardSpeed), i2c.StandardSpeed)
+	i2c.WithSpeed(i2c.FastSpeed)(c)
+	gobottest.Assert(t, c.(i2c.SpeedConfig).GetSpeedOrDefault(i2c.StandardSpeed), i2c.FastSpeed)
+}
+
+func TestConfigSpeed(t *testing.T) {
+	a := i2ctest.NewAdaptor()
+	a.AddBusDevice(1, 0x40)
+	d := i2c.NewPCA9685Driver(a, i2c.WithBus(1), i2c.WithSpeed(i2c.FastSpeed))
+
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, a.BusSpeed(1), i2c.FastSpeed)
+	gobottest.Assert(t, a.BusSpeed(0), 0)
+}
+
+func TestConfigAddressesFallback(t *testing.T) {
+	a := i2ctest.NewAdaptor()
+	a.AddDevice(0x41)
+	d := i2c.NewPCA9685Driver(a, i2c.WithAddresses([]int{0x40, 0x41, 0x42}))
+
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.GetAddressOrDefault(0x40), 0x41)
+	gobottest.Refute(t, len(a.Device(0, 0x41).Ops()), 0)
+}
+
+func TestConfigAddressesProbedAtEachStart(t *testing.T) {
+	a := i2ctest.NewAdaptor()
+	a.AddDevice(0x41)
+	d := i2c.NewPCA9685Driver(a, i2c.WithAddresses([]int{0x40, 0x41}))
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.GetAddressOrDefault(0x40), 0x41)
+	gobottest.Assert(t, d.Halt(), nil)
+
+	a.AddDevice(0x40)
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.GetAddressOrDefault(0x41), 0x40)
+	gobottest.Assert(t, d.Config.(i2c.AddressesConfig).GetAddresses(), []int{0x40, 0x41})
+}
+
+// fixedConfig is a Config of an other package, which is neither an
+// AddressesConfig nor a SpeedConfig
+type fixedConfig struct{}
+
+func (fixedConfig) WithBus(bus int)                 {}
+func (fixedConfig) GetBusOrDefault(def int) int     { return 1 }
+func (fixedConfig) WithAddress(address int)         {}
+func (fixedConfig) GetAddressOrDefault(def int) int { return 0x40 }
+
+func TestConfigExtensionsUnsupported(t *testing.T) {
+	a := i2ctest.NewAdaptor()
+	a.AddBusDevice(1, 0x40)
+	d := i2c.NewPCA9685Driver(a)
+	d.Config = fixedConfig{}
+	gobottest.Assert(t, d.Start(), nil)
+
+	i2c.WithAddresses([]int{0x41, 0x42})(d)
+	gobottest.Assert(t, d.Start(), errors.New("The i2c Config i2c_test.fixedConfig can't hold the addresses to try"))
+
+	d = i2c.NewPCA9685Driver(a)
+	d.Config = fixedConfig{}
+	i2c.WithSpeed(i2c.FastSpeed)(d)
+	gobottest.Assert(t, d.Start(), errors.New("The i2c Config i2c_test.fixedConfig can't hold the speed of the bus"))
+	gobottest.Assert(t, a.BusSpeed(1), 0)
+}
+
+func TestConfigAddressesNotFound(t *testing.T) {
+	a := i2ctest.NewAdaptor()
+	a.AddDevice(0x70)
+	d := i2c.NewPCA9685Driver(a, i2c.WithAddresses([]int{0x40, 0x41}))
+
+	err := d.Start()
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.HasPrefix(err.Error(), "No i2c device answered at the addresses 0x40, 0x41 on bus 0"), true)
+}
+
+func TestConfigNotConnected(t *testing.T) {
+	d := i2c.NewPCA9685Driver(nil)
+	gobottest.Assert(t, d.Start(), i2c.ErrNotConnected)
+}
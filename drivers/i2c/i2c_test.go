@@ -4,12 +4,16 @@ package i2c
 
 import (
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"syscall"
 	"unsafe"
 
+	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/gobottest/tracetest"
 	"gobot.io/x/gobot/sysfs"
 )
 
@@ -173,3 +177,176 @@ func TestI2CWriteBlockDataAddressError(t *testing.T) {
 	err := c.WriteBlockData(0x01, []byte{0x01, 0x02})
 	gobottest.Assert(t, err, errors.New("Setting address failed with syscall.Errno operation not permitted"))
 }
+
+func TestI2CNotConnected(t *testing.T) {
+	c := NewConnection(nil, 0x06)
+	_, err := c.Read([]byte{0})
+	gobottest.Assert(t, err, ErrNotConnected)
+	gobottest.Assert(t, c.WriteByteData(0x01, 0x01), ErrNotConnected)
+	gobottest.Assert(t, c.Close(), ErrNotConnected)
+}
+
+func TestI2CErrors(t *testing.T) {
+	err := ErrRegisterRead{Reg: 0x0F, Err: ErrNotEnoughBytes}
+	gobottest.Assert(t, err.Error(), "reading register 0x0F: Not enough bytes read")
+	gobottest.Assert(t, errors.Is(err, ErrNotEnoughBytes), true)
+	gobottest.Assert(t, ErrBadDevice{Device: "MPU6050", ID: 0x70}.Error(), "MPU6050 device not found, unexpected ID 0x70")
+
+	var bad ErrBadDevice
+	gobottest.Assert(t, errors.As(error(ErrBadDevice{Device: "seesaw", ID: 1}), &bad), true)
+	gobottest.Assert(t, bad.ID, 1)
+}
+
+func TestI2CTracing(t *testing.T) {
+	rec := tracetest.NewRecorder()
+	gobot.SetDefaultTracer(rec)
+	defer gobot.SetDefaultTracer(nil)
+
+	c := NewConnection(initI2CDevice(), 0x06)
+	c.ReadByteData(0x01)
+	c.WriteBlockData(0x02, []byte{0x01, 0x02})
+	gobottest.Assert(t, rec.Spans(), []tracetest.Span{
+		{ID: 1, Name: I2cReadSpan, Ended: true, Attributes: map[string]interface{}{
+			"i2c.operation": "ReadByteData",
+			"i2c.address":   0x06,
+			"i2c.register":  0x01,
+			"i2c.bus":       "/dev/i2c-1",
+		}},
+		{ID: 2, Name: I2cWriteSpan, Ended: true, Attributes: map[string]interface{}{
+			"i2c.operation": "WriteBlockData",
+			"i2c.address":   0x06,
+			"i2c.register":  0x02,
+			"i2c.length":    2,
+			"i2c.bus":       "/dev/i2c-1",
+		}},
+	})
+
+	rec.Reset()
+	c = NewConnection(initI2CDeviceAddressError(), 0x06)
+	c.Read([]byte{0})
+	span := rec.Spans()[0]
+	gobottest.Assert(t, span.Attributes["i2c.length"], 1)
+	gobottest.Assert(t, span.Errors, []error{errors.New("Setting address failed with syscall.Errno operation not permitted")})
+}
+
+// quietBus accepts the writes without recording them
+type quietBus struct {
+	I2cDevice
+}
+
+func (quietBus) SetAddress(address int) error                  { return nil }
+func (quietBus) WriteByteData(reg uint8, val uint8) (err error) { return nil }
+
+func TestI2CNoTracing(t *testing.T) {
+	c := NewConnection(quietBus{}, 0x06)
+	gobottest.Assert(t, c.startSpan(I2cWriteSpan, "WriteByteData", 0x01, -1), nil)
+	allocs := testing.AllocsPerRun(100, func() { c.WriteByteData(0x01, 0x02) })
+	gobottest.Assert(t, allocs, 0.0)
+}
+
+// sharedBus records the address targeted by each write, yielding between
+// the SetAddress and the write of a transaction
+type sharedBus struct {
+	I2cDevice
+	address int
+	writes  map[int][]byte
+	mtx     sync.Mutex
+}
+
+func (b *sharedBus) SetAddress(address int) error {
+	b.mtx.Lock()
+	b.address = address
+	b.mtx.Unlock()
+	time.Sleep(10 * time.Microsecond)
+	return nil
+}
+
+func (b *sharedBus) Write(data []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.writes[b.address] = append(b.writes[b.address], data...)
+	return len(data), nil
+}
+
+func (b *sharedBus) Close() error { return nil }
+
+// sharedBusConnector connects the devices at any address to the sharedBus
+type sharedBusConnector struct {
+	bus *sharedBus
+}
+
+func (c sharedBusConnector) GetConnection(address int, bus int) (Connection, error) {
+	return NewConnection(c.bus, address), nil
+}
+
+func (c sharedBusConnector) GetDefaultBus() int { return 1 }
+
+func TestI2CSharedBus(t *testing.T) {
+	bus := &sharedBus{writes: make(map[int][]byte)}
+	c := sharedBusConnector{bus: bus}
+	d1 := NewMCP4725Driver(c, WithAddress(0x60))
+	d2 := NewMCP4725Driver(c, WithAddress(0x61))
+	gobottest.Assert(t, d1.Start(), nil)
+	gobottest.Assert(t, d2.Start(), nil)
+
+	var wg sync.WaitGroup
+	for _, w := range []struct {
+		d     *MCP4725Driver
+		value uint16
+	}{{d1, 0x0111}, {d2, 0x0222}} {
+		wg.Add(1)
+		go func(d *MCP4725Driver, value uint16) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				d.Write(value)
+			}
+		}(w.d, w.value)
+	}
+	wg.Wait()
+
+	for address, value := range map[int]byte{0x60: 0x11, 0x61: 0x22} {
+		writes := bus.writes[address]
+		gobottest.Assert(t, len(writes), 200)
+		for i := 1; i < len(writes); i += 2 {
+			gobottest.Assert(t, writes[i], value)
+		}
+	}
+
+}
+
+func TestI2CBusLockShared(t *testing.T) {
+	bus := &sharedBus{writes: make(map[int][]byte)}
+	c1 := NewConnection(bus, 0x60)
+	c2 := NewConnection(bus, 0x61)
+	gobottest.Assert(t, c1.lock == c2.lock, true)
+	gobottest.Assert(t, c1.Close(), nil)
+	gobottest.Assert(t, c1.Close(), nil)
+	c3 := NewConnection(bus, 0x62)
+	gobottest.Assert(t, c2.lock == c3.lock, true)
+	gobottest.Assert(t, c3.lock.connections, 2)
+
+	// the lock is forgotten once the last Connection is closed
+	gobottest.Assert(t, c2.Close(), nil)
+	gobottest.Assert(t, c3.Close(), nil)
+	busLocksMutex.Lock()
+	_, ok := busLocks[bus]
+	busLocksMutex.Unlock()
+	gobottest.Assert(t, ok, false)
+}
+
+func TestI2CCloseBus(t *testing.T) {
+	bus := &sharedBus{writes: make(map[int][]byte)}
+	c := NewConnection(bus, 0x60)
+	gobottest.Assert(t, CloseBus(bus), nil)
+	busLocksMutex.Lock()
+	_, ok := busLocks[bus]
+	busLocksMutex.Unlock()
+	gobottest.Assert(t, ok, false)
+
+	// a Connection closed after its bus does not release the lock of a new
+	// Connection to it
+	c2 := NewConnection(bus, 0x61)
+	gobottest.Assert(t, c.Close(), nil)
+	gobottest.Assert(t, c2.lock.connections, 1)
+	gobottest.Assert(t, c2.Close(), nil)
+}
@@ -0,0 +1,93 @@
+package i2ctest
+
+import (
+	"fmt"
+	"sync"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+var _ i2c.Connector = (*Adaptor)(nil)
+var _ i2c.BusSpeedSetter = (*Adaptor)(nil)
+
+// Adaptor is a mock i2c.Connector giving access to the mock Devices added
+// to it.
+type Adaptor struct {
+	name string
+	// DefaultBus is the bus returned by GetDefaultBus
+	DefaultBus int
+	devices    map[[2]int]*Device
+	speeds     map[int]int
+	mutex      sync.Mutex
+}
+
+// NewAdaptor returns a new mock Adaptor without Devices
+func NewAdaptor() *Adaptor {
+	return &Adaptor{
+		name:    gobot.DefaultName("I2CTest"),
+		devices: make(map[[2]int]*Device),
+		speeds:  make(map[int]int),
+	}
+}
+
+// Name returns the Adaptor name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptor name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Connect implements the Adaptor interface
+func (a *Adaptor) Connect() error { return nil }
+
+// Finalize implements the Adaptor interface
+func (a *Adaptor) Finalize() error { return nil }
+
+// AddDevice adds a mock Device at address on the default bus
+func (a *Adaptor) AddDevice(address int) *Device {
+	return a.AddBusDevice(a.DefaultBus, address)
+}
+
+// AddBusDevice adds a mock Device at address on bus
+func (a *Adaptor) AddBusDevice(bus int, address int) *Device {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	d := newDevice(bus, address)
+	a.devices[[2]int{bus, address}] = d
+	return d
+}
+
+// Device returns the mock Device at address on bus, or nil
+func (a *Adaptor) Device(bus int, address int) *Device {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.devices[[2]int{bus, address}]
+}
+
+// GetConnection returns the mock Device at address on bus
+func (a *Adaptor) GetConnection(address int, bus int) (i2c.Connection, error) {
+	if d := a.Device(bus, address); d != nil {
+		return d, nil
+	}
+	return nil, fmt.Errorf("No I2C device at address 0x%02X on bus %d", address, bus)
+}
+
+// GetDefaultBus returns the DefaultBus
+func (a *Adaptor) GetDefaultBus() int {
+	return a.DefaultBus
+}
+
+// SetBusSpeed records the clock of the bus
+func (a *Adaptor) SetBusSpeed(bus int, hz int) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.speeds[bus] = hz
+	return nil
+}
+
+// BusSpeed returns the clock of the bus set with SetBusSpeed, or 0
+func (a *Adaptor) BusSpeed(bus int) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.speeds[bus]
+}
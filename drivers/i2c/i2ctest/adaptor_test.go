@@ -0,0 +1,48 @@
+package i2ctest
+
+import (
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+func TestAdaptor(t *testing.T) {
+	a := NewAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "I2CTest"), true)
+	a.SetName("mock")
+	gobottest.Assert(t, a.Name(), "mock")
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, a.GetDefaultBus(), 0)
+}
+
+func TestAdaptorGetConnection(t *testing.T) {
+	a := NewAdaptor()
+	a.DefaultBus = 1
+	d := a.AddDevice(0x39)
+	gobottest.Assert(t, d.Bus(), 1)
+	gobottest.Assert(t, d.Address(), 0x39)
+	other := a.AddBusDevice(2, 0x39)
+
+	conn, err := a.GetConnection(0x39, 1)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, conn.(*Device), d)
+
+	conn, _ = a.GetConnection(0x39, 2)
+	gobottest.Assert(t, conn.(*Device), other)
+	gobottest.Assert(t, a.Device(2, 0x39), other)
+
+	_, err = a.GetConnection(0x40, 1)
+	gobottest.Assert(t, err.Error(), "No I2C device at address 0x40 on bus 1")
+}
+
+func TestAdaptorBusSpeed(t *testing.T) {
+	a := NewAdaptor()
+	gobottest.Assert(t, a.BusSpeed(1), 0)
+	gobottest.Assert(t, a.SetBusSpeed(1, 400000), nil)
+	gobottest.Assert(t, a.BusSpeed(1), 400000)
+}
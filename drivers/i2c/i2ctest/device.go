@@ -0,0 +1,336 @@
+package i2ctest
+
+import (
+	"fmt"
+	"sync"
+
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+var _ i2c.Connection = (*Device)(nil)
+
+// Operation kinds
+const (
+	ReadOp  = "read"
+	WriteOp = "write"
+)
+
+// Op is a read or write of consecutive registers made by a driver.
+type Op struct {
+	Kind     string
+	Register uint8
+	// Data holds the bytes read or written. For expected reads only its
+	// length is compared.
+	Data []byte
+}
+
+// Read returns an expected read of n bytes starting at reg
+func Read(reg uint8, n int) Op {
+	return Op{Kind: ReadOp, Register: reg, Data: make([]byte, n)}
+}
+
+// Write returns an expected write of data starting at reg
+func Write(reg uint8, data ...byte) Op {
+	return Op{Kind: WriteOp, Register: reg, Data: data}
+}
+
+func (o Op) String() string {
+	if o.Kind == ReadOp {
+		return fmt.Sprintf("read 0x%02X (%d bytes)", o.Register, len(o.Data))
+	}
+	return fmt.Sprintf("write 0x%02X % X", o.Register, o.Data)
+}
+
+func (o Op) matches(actual Op) bool {
+	if o.Kind != actual.Kind || o.Register != actual.Register || len(o.Data) != len(actual.Data) {
+		return false
+	}
+	if o.Kind == ReadOp {
+		return true
+	}
+	for i := range o.Data {
+		if o.Data[i] != actual.Data[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Device is a mock I2C device with 256 registers. Reads and writes without a
+// register, such as Read, Write and ReadByte, use a register pointer which is
+// set by writing a single byte and advances like on most devices.
+type Device struct {
+	bus, address int
+	registers    [256]byte
+	pointer      uint8
+	queued       map[uint8][]byte
+	readErrs     map[uint8]error
+	writeErrs    map[uint8]error
+	onWrite      map[uint8]func(val byte)
+	ops          []Op
+	expected     []Op
+	closed       bool
+	mutex        sync.Mutex
+}
+
+func newDevice(bus int, address int) *Device {
+	return &Device{
+		bus:       bus,
+		address:   address,
+		queued:    make(map[uint8][]byte),
+		readErrs:  make(map[uint8]error),
+		writeErrs: make(map[uint8]error),
+		onWrite:   make(map[uint8]func(val byte)),
+	}
+}
+
+// Bus returns the bus of the Device
+func (d *Device) Bus() int { return d.bus }
+
+// Address returns the address of the Device
+func (d *Device) Address() int { return d.address }
+
+// SetRegister sets the value of reg
+func (d *Device) SetRegister(reg uint8, val byte) {
+	d.SetRegisters(reg, val)
+}
+
+// SetRegisters sets the values of the registers starting at reg
+func (d *Device) SetRegisters(reg uint8, vals ...byte) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i, val := range vals {
+		d.registers[reg+uint8(i)] = val
+	}
+}
+
+// Register returns the value of reg, for example to check what a driver
+// wrote to it.
+func (d *Device) Register(reg uint8) byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.registers[reg]
+}
+
+// QueueReads makes the next reads of reg return vals, one per read, before
+// falling back to the value of the register. This simulates status registers
+// and measurements changing over time.
+func (d *Device) QueueReads(reg uint8, vals ...byte) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.queued[reg] = append(d.queued[reg], vals...)
+}
+
+// FailRead makes reads of reg return err, until called again with a nil err.
+func (d *Device) FailRead(reg uint8, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if err == nil {
+		delete(d.readErrs, reg)
+		return
+	}
+	d.readErrs[reg] = err
+}
+
+// FailWrite makes writes to reg return err, until called again with a nil
+// err.
+func (d *Device) FailWrite(reg uint8, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if err == nil {
+		delete(d.writeErrs, reg)
+		return
+	}
+	d.writeErrs[reg] = err
+}
+
+// OnWrite calls f with each value written to reg, after the register has
+// been set, to simulate side effects such as a reset bit clearing itself.
+// f may call the other methods of the Device.
+func (d *Device) OnWrite(reg uint8, f func(val byte)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.onWrite[reg] = f
+}
+
+// Ops returns the reads and writes made so far
+func (d *Device) Ops() []Op {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]Op{}, d.ops...)
+}
+
+// Expect sets the sequence of reads and writes checked by Verify
+func (d *Device) Expect(ops ...Op) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.expected = ops
+}
+
+// Verify returns an error describing the first difference between the
+// operations made and the ones set with Expect.
+func (d *Device) Verify() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i, expected := range d.expected {
+		if i >= len(d.ops) {
+			return fmt.Errorf("operation %d: expected %v, got nothing", i+1, expected)
+		}
+		if !expected.matches(d.ops[i]) {
+			return fmt.Errorf("operation %d: expected %v, got %v", i+1, expected, d.ops[i])
+		}
+	}
+	if len(d.ops) > len(d.expected) {
+		return fmt.Errorf("operation %d: unexpected %v", len(d.expected)+1, d.ops[len(d.expected)])
+	}
+	return nil
+}
+
+// Reset clears the recorded operations and the expectations
+func (d *Device) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.ops = nil
+	d.expected = nil
+}
+
+// Closed returns whether the connection to the Device was closed
+func (d *Device) Closed() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.closed
+}
+
+func (d *Device) read(reg uint8, n int) ([]byte, error) {
+	d.mutex.Lock()
+	data := make([]byte, n)
+	for i := range data {
+		r := reg + uint8(i)
+		if err := d.readErrs[r]; err != nil {
+			d.mutex.Unlock()
+			return nil, err
+		}
+		if queued := d.queued[r]; len(queued) > 0 {
+			data[i] = queued[0]
+			d.queued[r] = queued[1:]
+		} else {
+			data[i] = d.registers[r]
+		}
+	}
+	d.pointer = reg + uint8(n)
+	d.ops = append(d.ops, Op{Kind: ReadOp, Register: reg, Data: data})
+	d.mutex.Unlock()
+	return data, nil
+}
+
+func (d *Device) write(reg uint8, data []byte) error {
+	d.mutex.Lock()
+	for i := range data {
+		if err := d.writeErrs[reg+uint8(i)]; err != nil {
+			d.mutex.Unlock()
+			return err
+		}
+	}
+	hooks := []func(){}
+	for i, val := range data {
+		r := reg + uint8(i)
+		d.registers[r] = val
+		if f := d.onWrite[r]; f != nil {
+			val := val
+			hooks = append(hooks, func() { f(val) })
+		}
+	}
+	d.pointer = reg + uint8(len(data))
+	d.ops = append(d.ops, Op{Kind: WriteOp, Register: reg, Data: append([]byte{}, data...)})
+	d.mutex.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// Read reads len(b) registers starting at the register pointer
+func (d *Device) Read(b []byte) (int, error) {
+	d.mutex.Lock()
+	reg := d.pointer
+	d.mutex.Unlock()
+	data, err := d.read(reg, len(b))
+	if err != nil {
+		return 0, err
+	}
+	return copy(b, data), nil
+}
+
+// Write sets the register pointer to the first byte of b, and writes the
+// next bytes to the registers starting there.
+func (d *Device) Write(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if len(b) == 1 {
+		return 1, d.WriteByte(b[0])
+	}
+	if err := d.write(b[0], b[1:]); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close marks the Device as closed
+func (d *Device) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.closed = true
+	return nil
+}
+
+// ReadByte reads the register at the register pointer
+func (d *Device) ReadByte() (byte, error) {
+	b := []byte{0}
+	_, err := d.Read(b)
+	return b[0], err
+}
+
+// ReadByteData reads reg
+func (d *Device) ReadByteData(reg uint8) (uint8, error) {
+	data, err := d.read(reg, 1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// ReadWordData reads reg as the low byte and the next register as the high
+// byte of a word, as SMBus does.
+func (d *Device) ReadWordData(reg uint8) (uint16, error) {
+	data, err := d.read(reg, 2)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(data[0]) | uint16(data[1])<<8, nil
+}
+
+// WriteByte sets the register pointer
+func (d *Device) WriteByte(val byte) error {
+	return d.write(val, nil)
+}
+
+// WriteByteData writes val to reg
+func (d *Device) WriteByteData(reg uint8, val uint8) error {
+	return d.write(reg, []byte{val})
+}
+
+// WriteWordData writes the low byte of val to reg and its high byte to the
+// next register, as SMBus does.
+func (d *Device) WriteWordData(reg uint8, val uint16) error {
+	return d.write(reg, []byte{byte(val), byte(val >> 8)})
+}
+
+// WriteBlockData writes b to the registers starting at reg
+func (d *Device) WriteBlockData(reg uint8, b []byte) error {
+	if len(b) > 32 {
+		return fmt.Errorf("Writing blocks larger than 32 bytes (%v) not supported", len(b))
+	}
+	return d.write(reg, b)
+}
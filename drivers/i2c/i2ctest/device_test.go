@@ -0,0 +1,161 @@
+package i2ctest
+
+import (
+	"errors"
+	"testing"
+
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestDeviceRegisters(t *testing.T) {
+	d := newDevice(0, 0x10)
+	d.SetRegisters(0x20, 1, 2, 3)
+
+	val, err := d.ReadByteData(0x21)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, uint8(2))
+
+	word, _ := d.ReadWordData(0x20)
+	gobottest.Assert(t, word, uint16(0x0201))
+
+	gobottest.Assert(t, d.WriteByteData(0x30, 0xAB), nil)
+	gobottest.Assert(t, d.Register(0x30), byte(0xAB))
+
+	gobottest.Assert(t, d.WriteWordData(0x40, 0x1234), nil)
+	gobottest.Assert(t, d.Register(0x40), byte(0x34))
+	gobottest.Assert(t, d.Register(0x41), byte(0x12))
+
+	gobottest.Assert(t, d.WriteBlockData(0x50, []byte{9, 8}), nil)
+	gobottest.Assert(t, d.Register(0x51), byte(8))
+	gobottest.Assert(t, d.WriteBlockData(0x50, make([]byte, 33)).Error(),
+		"Writing blocks larger than 32 bytes (33) not supported")
+}
+
+func TestDeviceRegisterPointer(t *testing.T) {
+	d := newDevice(0, 0x10)
+	d.SetRegisters(0xFE, 7, 8, 9)
+
+	n, err := d.Write([]byte{0xFE})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, n, 1)
+
+	buf := make([]byte, 3)
+	n, _ = d.Read(buf)
+	gobottest.Assert(t, n, 3)
+	// the pointer wraps around
+	gobottest.Assert(t, buf, []byte{7, 8, 9})
+
+	gobottest.Assert(t, d.WriteByte(0xFF), nil)
+	val, _ := d.ReadByte()
+	gobottest.Assert(t, val, byte(8))
+	val, _ = d.ReadByte()
+	gobottest.Assert(t, val, byte(9))
+
+	n, _ = d.Write([]byte{0x10, 1, 2})
+	gobottest.Assert(t, n, 3)
+	gobottest.Assert(t, d.Register(0x11), byte(2))
+
+	n, _ = d.Write(nil)
+	gobottest.Assert(t, n, 0)
+}
+
+func TestDeviceQueueReads(t *testing.T) {
+	d := newDevice(0, 0x10)
+	d.SetRegister(0x01, 0xFF)
+	d.QueueReads(0x01, 0x00, 0x80)
+
+	val, _ := d.ReadByteData(0x01)
+	gobottest.Assert(t, val, uint8(0x00))
+	val, _ = d.ReadByteData(0x01)
+	gobottest.Assert(t, val, uint8(0x80))
+	val, _ = d.ReadByteData(0x01)
+	gobottest.Assert(t, val, uint8(0xFF))
+}
+
+func TestDeviceErrors(t *testing.T) {
+	d := newDevice(0, 0x10)
+	readErr := errors.New("read error")
+	writeErr := errors.New("write error")
+	d.FailRead(0x02, readErr)
+	d.FailWrite(0x05, writeErr)
+
+	_, err := d.ReadWordData(0x01)
+	gobottest.Assert(t, err, readErr)
+	_, err = d.ReadByteData(0x00)
+	gobottest.Assert(t, err, nil)
+
+	gobottest.Assert(t, d.WriteWordData(0x04, 1), writeErr)
+	gobottest.Assert(t, d.Register(0x04), byte(0))
+	_, err = d.Write([]byte{0x05, 1})
+	gobottest.Assert(t, err, writeErr)
+
+	d.FailRead(0x02, nil)
+	d.FailWrite(0x05, nil)
+	_, err = d.ReadWordData(0x01)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, d.WriteByteData(0x05, 1), nil)
+}
+
+func TestDeviceOnWrite(t *testing.T) {
+	d := newDevice(0, 0x10)
+	d.OnWrite(0x00, func(val byte) {
+		if val&0x80 != 0 {
+			// self clearing reset bit
+			d.SetRegister(0x00, val&^0x80)
+		}
+	})
+	d.WriteByteData(0x00, 0x81)
+	gobottest.Assert(t, d.Register(0x00), byte(0x01))
+}
+
+func TestDeviceExpect(t *testing.T) {
+	d := newDevice(0, 0x10)
+	d.Expect(
+		Write(0x80, 0x03),
+		Read(0x8A, 1),
+	)
+	gobottest.Assert(t, d.Verify().Error(), "operation 1: expected write 0x80 03, got nothing")
+
+	d.WriteByteData(0x80, 0x03)
+	d.ReadByteData(0x8A)
+	gobottest.Assert(t, d.Verify(), nil)
+
+	d.ReadByteData(0x8B)
+	gobottest.Assert(t, d.Verify().Error(), "operation 3: unexpected read 0x8B (1 bytes)")
+	gobottest.Assert(t, len(d.Ops()), 3)
+
+	d.Reset()
+	d.Expect(Write(0x80, 0x03))
+	d.WriteByteData(0x80, 0x00)
+	gobottest.Assert(t, d.Verify().Error(), "operation 1: expected write 0x80 03, got write 0x80 00")
+}
+
+func TestDeviceClose(t *testing.T) {
+	d := newDevice(0, 0x10)
+	gobottest.Assert(t, d.Closed(), false)
+	gobottest.Assert(t, d.Close(), nil)
+	gobottest.Assert(t, d.Closed(), true)
+}
+
+func TestDeviceTSL2561Start(t *testing.T) {
+	a := NewAdaptor()
+	dev := a.AddDevice(i2c.TSL2561AddressFloat)
+	dev.SetRegister(0x0A, 0x0A)
+
+	d := i2c.NewTSL2561Driver(a)
+	gobottest.Assert(t, d.Start(), nil)
+
+	ops := dev.Ops()
+	gobottest.Assert(t, ops[0], Write(0x80, 0x03))
+	gobottest.Assert(t, ops[1].Kind, ReadOp)
+	gobottest.Assert(t, ops[1].Register, uint8(0x0A))
+	// powered off at the end of Start
+	gobottest.Assert(t, dev.Register(0x80), byte(0x00))
+
+	dev.SetRegister(0x0A, 0x00)
+	gobottest.Assert(t, d.Start(), i2c.ErrBadDevice{Device: "TSL2561", ID: 0x00})
+
+	dev.FailWrite(0x80, errors.New("nack"))
+	gobottest.Assert(t, d.Start().Error(), "nack")
+}
@@ -0,0 +1,22 @@
+/*
+Package i2ctest provides a mock I2C adaptor to test i2c drivers end to end,
+Start included, without hardware.
+
+Each mock Device has a map of 256 registers. Tests preset the registers the
+driver reads, queue changing values, inject errors and check the sequence of
+reads and writes made by the driver:
+
+	a := i2ctest.NewAdaptor()
+	dev := a.AddDevice(0x1E)
+	dev.SetRegisters(0x03, 0x00, 0x10, 0x00, 0x20, 0x00, 0x30)
+	dev.Expect(
+		i2ctest.Write(0x00, 0x70),
+		i2ctest.Write(0x01, 0xA0),
+		i2ctest.Write(0x02, 0x00),
+	)
+
+	d := NewMyCompassDriver(a)
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, dev.Verify(), nil)
+*/
+package i2ctest // import "gobot.io/x/gobot/drivers/i2c/i2ctest"
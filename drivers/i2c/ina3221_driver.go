@@ -49,6 +49,7 @@ type INA3221Driver struct {
 	connector  Connector
 	connection Connection
 	Config
+	driverConfig
 	halt chan bool
 }
 
@@ -66,6 +67,7 @@ func NewINA3221Driver(c Connector, options ...func(Config)) *INA3221Driver {
 		Config:    NewConfig(),
 	}
 
+	i.driverConfig = driverConfig{config: &i.Config}
 	for _, option := range options {
 		option(i)
 	}
@@ -91,10 +93,7 @@ func (i *INA3221Driver) Connection() gobot.Connection {
 // Start initializes the INA3221
 func (i *INA3221Driver) Start() error {
 	var err error
-	bus := i.GetBusOrDefault(i.connector.GetDefaultBus())
-	address := i.GetAddressOrDefault(int(ina3221Address))
-
-	if i.connection, err = i.connector.GetConnection(address, bus); err != nil {
+	if i.connection, err = connect(i, i.connector, i.Config, int(ina3221Address)); err != nil {
 		return err
 	}
 
@@ -2,6 +2,8 @@ package i2c
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -73,7 +75,9 @@ var CustomLCDChars = map[string][8]byte{
 type JHD1313M1Driver struct {
 	name      string
 	connector Connector
+	mutex     *sync.Mutex
 	Config
+	driverConfig
 	lcdAddress    int
 	lcdConnection Connection
 	rgbAddress    int
@@ -92,10 +96,12 @@ func NewJHD1313M1Driver(a Connector, options ...func(Config)) *JHD1313M1Driver {
 		name:       gobot.DefaultName("JHD1313M1"),
 		connector:  a,
 		Config:     NewConfig(),
+		mutex:      &sync.Mutex{},
 		lcdAddress: 0x3E,
 		rgbAddress: 0x62,
 	}
 
+	j.driverConfig = driverConfig{config: &j.Config}
 	for _, option := range options {
 		option(j)
 	}
@@ -116,6 +122,9 @@ func (h *JHD1313M1Driver) Connection() gobot.Connection {
 
 // Start starts the backlit and the screen and initializes the states.
 func (h *JHD1313M1Driver) Start() (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	bus := h.GetBusOrDefault(h.connector.GetDefaultBus())
 
 	if h.lcdConnection, err = h.connector.GetConnection(h.lcdAddress, bus); err != nil {
@@ -157,7 +166,7 @@ func (h *JHD1313M1Driver) Start() (err error) {
 	}
 
 	time.Sleep(100 * time.Microsecond)
-	if err := h.Clear(); err != nil {
+	if err := h.clear(); err != nil {
 		return err
 	}
 
@@ -175,7 +184,7 @@ func (h *JHD1313M1Driver) Start() (err error) {
 		return err
 	}
 
-	if err := h.SetRGB(255, 255, 255); err != nil {
+	if err := h.setRGB(255, 255, 255); err != nil {
 		return err
 	}
 
@@ -184,6 +193,13 @@ func (h *JHD1313M1Driver) Start() (err error) {
 
 // SetRGB sets the Red Green Blue value of backlit.
 func (h *JHD1313M1Driver) SetRGB(r, g, b int) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.setRGB(r, g, b)
+}
+
+func (h *JHD1313M1Driver) setRGB(r, g, b int) error {
 	if err := h.setReg(REG_RED, r); err != nil {
 		return err
 	}
@@ -195,12 +211,22 @@ func (h *JHD1313M1Driver) SetRGB(r, g, b int) error {
 
 // Clear clears the text on the lCD display.
 func (h *JHD1313M1Driver) Clear() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.clear()
+}
+
+func (h *JHD1313M1Driver) clear() error {
 	err := h.command([]byte{LCD_CLEARDISPLAY})
 	return err
 }
 
 // Home sets the cursor to the origin position on the display.
 func (h *JHD1313M1Driver) Home() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	err := h.command([]byte{LCD_RETURNHOME})
 	// This wait fixes a race condition when calling home and clear back to back.
 	time.Sleep(2 * time.Millisecond)
@@ -209,11 +235,14 @@ func (h *JHD1313M1Driver) Home() error {
 
 // Write displays the passed message on the screen.
 func (h *JHD1313M1Driver) Write(message string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	// This wait fixes an odd bug where the clear function doesn't always work properly.
 	time.Sleep(1 * time.Millisecond)
 	for _, val := range message {
 		if val == '\n' {
-			if err := h.SetPosition(16); err != nil {
+			if err := h.setPosition(16); err != nil {
 				return err
 			}
 			continue
@@ -225,10 +254,45 @@ func (h *JHD1313M1Driver) Write(message string) error {
 	return nil
 }
 
+// TextSize implements the Displayer interface and returns the 16 columns and
+// 2 rows of the display
+func (h *JHD1313M1Driver) TextSize() (cols int, rows int) { return 16, 2 }
+
+// WriteLine implements the Displayer interface and displays text on row,
+// truncated or padded with spaces to the width of the display
+func (h *JHD1313M1Driver) WriteLine(row int, text string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if row < 0 || row > 1 {
+		return ErrInvalidPosition
+	}
+	if err := h.setPosition(row * 16); err != nil {
+		return err
+	}
+	if len(text) > 16 {
+		text = text[:16]
+	}
+	text += strings.Repeat(" ", 16-len(text))
+	for i := 0; i < len(text); i++ {
+		if _, err := h.lcdConnection.Write([]byte{LCD_DATA, text[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetPosition sets the cursor and the data display to pos.
 // 0..15 are the positions in the first display line.
 // 16..32 are the positions in the second display line.
 func (h *JHD1313M1Driver) SetPosition(pos int) (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.setPosition(pos)
+}
+
+func (h *JHD1313M1Driver) setPosition(pos int) (err error) {
 	if pos < 0 || pos > 31 {
 		err = ErrInvalidPosition
 		return
@@ -243,6 +307,9 @@ func (h *JHD1313M1Driver) SetPosition(pos int) (err error) {
 }
 
 func (h *JHD1313M1Driver) Scroll(leftToRight bool) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	if leftToRight {
 		_, err := h.lcdConnection.Write([]byte{LCD_CMD, LCD_CURSORSHIFT | LCD_DISPLAYMOVE | LCD_MOVELEFT})
 		return err
@@ -264,6 +331,9 @@ func (h *JHD1313M1Driver) Halt() error { return nil }
 // To use a custom character, write byte value of the custom character position as a string after
 // having setup the custom character.
 func (h *JHD1313M1Driver) SetCustomChar(pos int, charMap [8]byte) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	if pos > 7 {
 		return fmt.Errorf("can't set a custom character at a position greater than 7")
 	}
@@ -3,13 +3,17 @@ package i2c
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
 )
 
 var _ gobot.Driver = (*JHD1313M1Driver)(nil)
+var _ gobot.TextDisplay = (*JHD1313M1Driver)(nil)
+var _ Displayer = (*JHD1313M1Driver)(nil)
 
 // --------- HELPERS
 func initTestJHD1313M1Driver() (driver *JHD1313M1Driver) {
@@ -121,6 +125,28 @@ func TestJHD1313MDriverWrite(t *testing.T) {
 	gobottest.Assert(t, d.Write("Hello"), nil)
 }
 
+func TestJHD1313MDriverWriteLine(t *testing.T) {
+	d, a := initTestJHD1313M1DriverWithStubbedAdaptor()
+	d.Start()
+	cols, rows := d.TextSize()
+	gobottest.Assert(t, cols, 16)
+	gobottest.Assert(t, rows, 2)
+	a.written = []byte{}
+	gobottest.Assert(t, d.WriteLine(1, "Temperature: 21.5C"), nil)
+	gobottest.Assert(t, a.written[:2], []byte{0x80, LCD_SETDDRAMADDR | LCD_2NDLINEOFFSET})
+	var text string
+	for i := 3; i < len(a.written); i += 2 {
+		text += string(a.written[i])
+	}
+	gobottest.Assert(t, text, "Temperature: 21.")
+	gobottest.Assert(t, d.WriteLine(2, "ok"), ErrInvalidPosition)
+
+	a.i2cWriteImpl = func([]byte) (int, error) {
+		return 0, errors.New("write error")
+	}
+	gobottest.Assert(t, d.WriteLine(0, "ok"), errors.New("write error"))
+}
+
 func TestJHD1313MDriverWriteError(t *testing.T) {
 	d, a := initTestJHD1313M1DriverWithStubbedAdaptor()
 	d.Start()
@@ -131,6 +157,32 @@ func TestJHD1313MDriverWriteError(t *testing.T) {
 	gobottest.Assert(t, d.Write("Hello"), errors.New("write error"))
 }
 
+func TestJHD1313MDriverWriteConcurrently(t *testing.T) {
+	d, a := initTestJHD1313M1DriverWithStubbedAdaptor()
+	d.Start()
+	a.written = []byte{}
+	a.i2cWriteImpl = func(b []byte) (int, error) {
+		time.Sleep(10 * time.Microsecond)
+		return len(b), nil
+	}
+
+	var wg sync.WaitGroup
+	for _, message := range []string{"aaaaaaaa", "bbbbbbbb"} {
+		wg.Add(1)
+		go func(message string) {
+			defer wg.Done()
+			d.Write(message)
+		}(message)
+	}
+	wg.Wait()
+
+	var written []byte
+	for i := 1; i < len(a.written); i += 2 {
+		written = append(written, a.written[i])
+	}
+	gobottest.Assert(t, string(written) == "aaaaaaaabbbbbbbb" || string(written) == "bbbbbbbbaaaaaaaa", true)
+}
+
 func TestJHD1313MDriverWriteTwoLines(t *testing.T) {
 	d, _ := initTestJHD1313M1DriverWithStubbedAdaptor()
 	d.Start()
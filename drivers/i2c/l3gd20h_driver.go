@@ -3,6 +3,7 @@ package i2c
 import (
 	"bytes"
 	"encoding/binary"
+	"sync"
 
 	"gobot.io/x/gobot"
 )
@@ -33,13 +34,38 @@ const (
 // L3GD20HScale is the scale sensitivity of degrees-per-second.
 type L3GD20HScale byte
 
+// l3gd20hRegisters are the registers shown by DebugDump
+var l3gd20hRegisters = RegisterMap{
+	{Name: "WHO_AM_I", Address: 0x0F},
+	{Name: "CTRL1", Address: l3gd20hRegisterCtl1, Fields: []RegisterField{
+		{Name: "DR", Shift: 6, Width: 2},
+		{Name: "BW", Shift: 4, Width: 2},
+		{Name: "PD", Shift: 3, Width: 1, Values: map[uint8]string{0: "power-down", 1: "normal"}},
+		{Name: "Zen", Shift: 2, Width: 1},
+		{Name: "Yen", Shift: 1, Width: 1},
+		{Name: "Xen", Shift: 0, Width: 1},
+	}},
+	{Name: "CTRL4", Address: l3gd20hRegisterCtl4, Fields: []RegisterField{
+		{Name: "BDU", Shift: 7, Width: 1},
+		{Name: "BLE", Shift: 6, Width: 1},
+		{Name: "FS", Shift: 4, Width: 2, Values: map[uint8]string{0: "250dps", 1: "500dps", 2: "2000dps", 3: "2000dps"}},
+	}},
+	{Name: "STATUS", Address: 0x27, Fields: []RegisterField{
+		{Name: "ZYXOR", Shift: 7, Width: 1},
+		{Name: "ZYXDA", Shift: 3, Width: 1},
+	}},
+}
+
 // L3GD20HDriver is the gobot driver for the Adafruit Triple-Axis Gyroscope L3GD20H.
 // Device datasheet: http://www.st.com/internet/com/TECHNICAL_RESOURCES/TECHNICAL_LITERATURE/DATASHEET/DM00036465.pdf
 type L3GD20HDriver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 	scale L3GD20HScale
 }
 
@@ -58,14 +84,19 @@ func NewL3GD20HDriver(c Connector, options ...func(Config)) *L3GD20HDriver {
 		name:      gobot.DefaultName("L3GD20H"),
 		connector: c,
 		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
 		scale:     L3GD20HScale250dps,
 	}
 
+	l.driverConfig = driverConfig{config: &l.Config}
 	for _, option := range options {
 		option(l)
 	}
 
 	// TODO: add commands to API
+	addDebugDumpCommand(l, l)
+
 	return l
 }
 
@@ -86,16 +117,25 @@ func (d *L3GD20HDriver) Connection() gobot.Connection {
 
 // Scale returns the scale sensitivity of the device.
 func (d *L3GD20HDriver) Scale() L3GD20HScale {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	return d.scale
 }
 
 // SetScale sets the scale sensitivity of the device.
 func (d *L3GD20HDriver) SetScale(s L3GD20HScale) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	d.scale = s
 }
 
 // Start initializes the device.
 func (d *L3GD20HDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if err := d.initialization(); err != nil {
 		return err
 	}
@@ -107,8 +147,19 @@ func (d *L3GD20HDriver) Halt() (err error) {
 	return nil
 }
 
+// DebugDump reads WHO_AM_I, CTRL1, CTRL4 and STATUS of the gyroscope.
+func (d *L3GD20HDriver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, l3gd20hRegisters)
+}
+
 // XYZ returns the current change in degrees per second, for the 3 axis.
 func (d *L3GD20HDriver) XYZ() (x float32, y float32, z float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if _, err = d.connection.Write([]byte{l3gd20hRegisterOutXLSB}); err != nil {
 		return 0, 0, 0, err
 	}
@@ -132,10 +183,7 @@ func (d *L3GD20HDriver) XYZ() (x float32, y float32, z float32, err error) {
 }
 
 func (d *L3GD20HDriver) initialization() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(l3gd20hAddress)
-
-	d.connection, err = d.connector.GetConnection(address, bus)
+	d.connection, err = connect(d, d.connector, d.Config, l3gd20hAddress)
 	if err != nil {
 		return err
 	}
@@ -135,3 +135,19 @@ func TestL3GD20HDriverOptions(t *testing.T) {
 	d := NewL3GD20HDriver(newI2cTestAdaptor(), WithBus(2))
 	gobottest.Assert(t, d.GetBusOrDefault(1), 2)
 }
+
+func TestL3GD20HDriverDebugDump(t *testing.T) {
+	d, adaptor := initTestL3GD20HDriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x30
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["CTRL4"].(map[string]interface{})["FS"], "2000dps")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
@@ -2,6 +2,7 @@ package i2c
 
 import (
 	"gobot.io/x/gobot"
+	"sync"
 
 	"time"
 )
@@ -13,7 +14,9 @@ type LIDARLiteDriver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
 }
 
 // NewLIDARLiteDriver creates a new driver for the LIDARLite I2C LIDAR device.
@@ -30,8 +33,10 @@ func NewLIDARLiteDriver(a Connector, options ...func(Config)) *LIDARLiteDriver {
 		name:      gobot.DefaultName("LIDARLite"),
 		connector: a,
 		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
 	}
 
+	l.driverConfig = driverConfig{config: &l.Config}
 	for _, option := range options {
 		option(l)
 	}
@@ -51,10 +56,10 @@ func (h *LIDARLiteDriver) Connection() gobot.Connection { return h.connector.(go
 
 // Start initialized the LIDAR
 func (h *LIDARLiteDriver) Start() (err error) {
-	bus := h.GetBusOrDefault(h.connector.GetDefaultBus())
-	address := h.GetAddressOrDefault(lidarliteAddress)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
 
-	h.connection, err = h.connector.GetConnection(address, bus)
+	h.connection, err = connect(h, h.connector, h.Config, lidarliteAddress)
 	if err != nil {
 		return err
 	}
@@ -66,6 +71,9 @@ func (h *LIDARLiteDriver) Halt() (err error) { return }
 
 // Distance returns the current distance in cm
 func (h *LIDARLiteDriver) Distance() (distance int, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	if _, err = h.connection.Write([]byte{0x00, 0x04}); err != nil {
 		return
 	}
@@ -10,6 +10,7 @@ import (
 )
 
 var _ gobot.Driver = (*LIDARLiteDriver)(nil)
+var _ gobot.DistanceSensor = (*LIDARLiteDriver)(nil)
 
 // --------- HELPERS
 func initTestLIDARLiteDriver() (driver *LIDARLiteDriver) {
@@ -0,0 +1,530 @@
+package i2c
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const (
+	// Click event is published by the LIS3DHDriver with a LIS3DHEvent when
+	// the LIS3DH detects a single click, such as a knock on the robot
+	Click = "click"
+	// DoubleClick event is published by the LIS3DHDriver with a LIS3DHEvent
+	// when the LIS3DH detects a double click
+	DoubleClick = "double-click"
+	// FreeFall event is published by the LIS3DHDriver with a LIS3DHEvent when
+	// the LIS3DH falls, all its axes measuring less than the threshold
+	FreeFall = "free-fall"
+	// Activity event is published by the LIS3DHDriver with a LIS3DHEvent when
+	// the LIS3DH moves, such as a robot picked up, an axis measuring more
+	// than the threshold once gravity is filtered out
+	Activity = "activity"
+)
+
+// LIS3DHDefaultAddress is the I2C address of the LIS3DH with its SDO pin low,
+// 0x19 when it is high
+const LIS3DHDefaultAddress = 0x18
+
+const (
+	lis3dhRegisterWhoAmI      = 0x0F
+	lis3dhRegisterCtrl1       = 0x20
+	lis3dhRegisterCtrl2       = 0x21
+	lis3dhRegisterCtrl3       = 0x22
+	lis3dhRegisterCtrl4       = 0x23
+	lis3dhRegisterCtrl5       = 0x24
+	lis3dhRegisterCtrl6       = 0x25
+	lis3dhRegisterStatus      = 0x27
+	lis3dhRegisterOutX        = 0x28
+	lis3dhRegisterInt1Cfg     = 0x30
+	lis3dhRegisterInt1Src     = 0x31
+	lis3dhRegisterInt1Ths     = 0x32
+	lis3dhRegisterInt1Dur     = 0x33
+	lis3dhRegisterInt2Cfg     = 0x34
+	lis3dhRegisterInt2Src     = 0x35
+	lis3dhRegisterInt2Ths     = 0x36
+	lis3dhRegisterInt2Dur     = 0x37
+	lis3dhRegisterClickCfg    = 0x38
+	lis3dhRegisterClickSrc    = 0x39
+	lis3dhRegisterClickThs    = 0x3A
+	lis3dhRegisterTimeLimit   = 0x3B
+	lis3dhRegisterTimeLatency = 0x3C
+	lis3dhRegisterTimeWindow  = 0x3D
+	// lis3dhAutoIncrement makes a read go on with the next registers
+	lis3dhAutoIncrement = 0x80
+	lis3dhWhoAmI        = 0x33
+	// lis3dhInterruptActive is the IA bit of the source registers
+	lis3dhInterruptActive = 0x40
+	// lis3dhFreeFallCfg is the AND combination of the low events of all axes
+	lis3dhFreeFallCfg = 0x95
+	// lis3dhActivityCfg is the OR combination of the high events of all axes
+	lis3dhActivityCfg = 0x2A
+	// lis3dhHighPassInt2 filters the gravity out of the INT2 engine
+	lis3dhHighPassInt2 = 0x02
+)
+
+// The full scales of the LIS3DH
+const (
+	LIS3DHRange2G uint8 = iota
+	LIS3DHRange4G
+	LIS3DHRange8G
+	LIS3DHRange16G
+)
+
+// The output data rates of the LIS3DH, which are also the rates at which it
+// runs its click and interrupt engines
+const (
+	LIS3DHDataRate1Hz uint8 = iota + 1
+	LIS3DHDataRate10Hz
+	LIS3DHDataRate25Hz
+	LIS3DHDataRate50Hz
+	LIS3DHDataRate100Hz
+	LIS3DHDataRate200Hz
+	LIS3DHDataRate400Hz
+)
+
+// lis3dhDataRates are the output data rates, in Hz
+var lis3dhDataRates = map[uint8]float64{
+	LIS3DHDataRate1Hz:   1,
+	LIS3DHDataRate10Hz:  10,
+	LIS3DHDataRate25Hz:  25,
+	LIS3DHDataRate50Hz:  50,
+	LIS3DHDataRate100Hz: 100,
+	LIS3DHDataRate200Hz: 200,
+	LIS3DHDataRate400Hz: 400,
+}
+
+// lis3dhSensitivities are the mg per digit of the 12 bits measurements, and
+// lis3dhThresholds the mg per unit of the thresholds, by full scale
+var (
+	lis3dhSensitivities = []float64{1, 2, 4, 12}
+	lis3dhThresholds    = []float64{16, 32, 62, 186}
+)
+
+// lis3dhRegisters are the registers shown by DebugDump
+var lis3dhRegisters = RegisterMap{
+	{Name: "WHO_AM_I", Address: lis3dhRegisterWhoAmI},
+	{Name: "CTRL_REG1", Address: lis3dhRegisterCtrl1, Fields: []RegisterField{
+		{Name: "ODR", Shift: 4, Width: 4, Values: map[uint8]string{0: "power-down", 1: "1Hz", 2: "10Hz", 3: "25Hz", 4: "50Hz", 5: "100Hz", 6: "200Hz", 7: "400Hz"}},
+		{Name: "LPen", Shift: 3, Width: 1},
+		{Name: "XYZen", Shift: 0, Width: 3},
+	}},
+	{Name: "CTRL_REG2", Address: lis3dhRegisterCtrl2},
+	{Name: "CTRL_REG3", Address: lis3dhRegisterCtrl3},
+	{Name: "CTRL_REG4", Address: lis3dhRegisterCtrl4, Fields: []RegisterField{
+		{Name: "BDU", Shift: 7, Width: 1},
+		{Name: "FS", Shift: 4, Width: 2, Values: map[uint8]string{0: "2g", 1: "4g", 2: "8g", 3: "16g"}},
+		{Name: "HR", Shift: 3, Width: 1},
+	}},
+	{Name: "CTRL_REG5", Address: lis3dhRegisterCtrl5},
+	{Name: "CTRL_REG6", Address: lis3dhRegisterCtrl6},
+	{Name: "STATUS_REG", Address: lis3dhRegisterStatus},
+	{Name: "INT1_CFG", Address: lis3dhRegisterInt1Cfg},
+	{Name: "INT1_THS", Address: lis3dhRegisterInt1Ths},
+	{Name: "INT1_DURATION", Address: lis3dhRegisterInt1Dur},
+	{Name: "INT2_CFG", Address: lis3dhRegisterInt2Cfg},
+	{Name: "INT2_THS", Address: lis3dhRegisterInt2Ths},
+	{Name: "INT2_DURATION", Address: lis3dhRegisterInt2Dur},
+	{Name: "CLICK_CFG", Address: lis3dhRegisterClickCfg},
+	{Name: "CLICK_THS", Address: lis3dhRegisterClickThs},
+	{Name: "TIME_LIMIT", Address: lis3dhRegisterTimeLimit},
+	{Name: "TIME_LATENCY", Address: lis3dhRegisterTimeLatency},
+	{Name: "TIME_WINDOW", Address: lis3dhRegisterTimeWindow},
+}
+
+// LIS3DHEvent is the data of the events of the LIS3DHDriver, telling which
+// axes triggered them
+type LIS3DHEvent struct {
+	X, Y, Z bool
+	// Negative is the sign of a click
+	Negative bool
+}
+
+// LIS3DHClickConfig configures the click detection of the LIS3DH. A click is
+// an acceleration above Threshold shorter than TimeLimit, and a double click
+// a second one starting after Latency and within Window. For example, at
+// 400Hz and 2g:
+//
+//	LIS3DHClickConfig{Single: true, Double: true, Threshold: 1.25,
+//		TimeLimit: 25 * time.Millisecond, Latency: 50 * time.Millisecond,
+//		Window: 600 * time.Millisecond}
+type LIS3DHClickConfig struct {
+	// Single and Double enable the detection of the single and double clicks
+	Single, Double bool
+	// Threshold of the clicks, in g
+	Threshold float64
+	// TimeLimit is the longest a click lasts
+	TimeLimit time.Duration
+	// Latency is the time after a click during which the second click of a
+	// double click is ignored
+	Latency time.Duration
+	// Window is the time after the latency during which the second click of
+	// a double click is detected
+	Window time.Duration
+}
+
+// LIS3DHDriver is a driver for the LIS3DH 3-axis accelerometer. Besides the
+// acceleration, it runs the click, free-fall and activity engines of the
+// LIS3DH and publishes their detections as events, polling their latched
+// sources. The engines also drive the INT1 pin of the LIS3DH for clicks and
+// free-falls, and the INT2 pin for activity, to wake up a sleeping host.
+type LIS3DHDriver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+	gobot.Eventer
+
+	fullScale uint8
+	dataRate  uint8
+	click     bool
+	freeFall  bool
+	activity  bool
+	polling   gobot.PollerConfig
+	halt      chan bool
+}
+
+// NewLIS3DHDriver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithLIS3DHRange(uint8):	full scale of the measurements, 2g by default
+//		i2c.WithLIS3DHDataRate(uint8):	output data rate, 400Hz by default
+//
+func NewLIS3DHDriver(c Connector, options ...func(Config)) *LIS3DHDriver {
+	d := &LIS3DHDriver{
+		name:      gobot.DefaultName("LIS3DH"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		Eventer:   gobot.NewEventer(),
+		mutex:     &sync.Mutex{},
+		fullScale: LIS3DHRange2G,
+		dataRate:  LIS3DHDataRate400Hz,
+		polling:   gobot.PollerConfig{Interval: 50 * time.Millisecond},
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddEvent(Click)
+	d.AddEvent(DoubleClick)
+	d.AddEvent(FreeFall)
+	d.AddEvent(Activity)
+	d.AddEvent(Error)
+
+	d.AddCommand("XYZ", func(params map[string]interface{}) interface{} {
+		x, y, z, err := d.XYZ()
+		return map[string]interface{}{"x": x, "y": y, "z": z, "err": err}
+	})
+	addDebugDumpCommand(d, d)
+
+	return d
+}
+
+// WithLIS3DHRange option sets the full scale of the LIS3DH, one of the
+// LIS3DHRange values
+func WithLIS3DHRange(val uint8) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*LIS3DHDriver); ok && val <= LIS3DHRange16G {
+			d.fullScale = val
+		}
+	}
+}
+
+// WithLIS3DHDataRate option sets the output data rate of the LIS3DH, one of
+// the LIS3DHDataRate values
+func WithLIS3DHDataRate(val uint8) func(Config) {
+	return func(c Config) {
+		if _, valid := lis3dhDataRates[val]; !valid {
+			return
+		}
+		if d, ok := c.(*LIS3DHDriver); ok {
+			d.dataRate = val
+		}
+	}
+}
+
+// Name returns the name of the device.
+func (d *LIS3DHDriver) Name() string { return d.name }
+
+// SetName sets the name of the device.
+func (d *LIS3DHDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device.
+func (d *LIS3DHDriver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// SetPolling configures the polling of the sources of the engines, every
+// 50ms by default.
+func (d *LIS3DHDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		d.polling = c
+	}
+}
+
+// Start checks the LIS3DH, configures its measurements and starts polling
+// the sources of its engines, which are off until enabled with SetClick,
+// SetFreeFall and SetActivity.
+//
+// Emits the Events:
+// 	Click LIS3DHEvent - On a single click
+// 	DoubleClick LIS3DHEvent - On a double click
+// 	FreeFall LIS3DHEvent - When falling
+// 	Activity LIS3DHEvent - When moving
+// 	Error error - On error polling the sources
+func (d *LIS3DHDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.connection, err = connect(d, d.connector, d.Config, LIS3DHDefaultAddress); err != nil {
+		return err
+	}
+	id, err := d.read(lis3dhRegisterWhoAmI, 1)
+	if err != nil {
+		return err
+	}
+	if id[0] != lis3dhWhoAmI {
+		return ErrBadDevice{Device: "LIS3DH", ID: int(id[0])}
+	}
+
+	// all axes, block data update, high resolution, latched interrupts, the
+	// click and free-fall engines on INT1 and the activity on INT2
+	if err = d.writeRegisters([][2]byte{
+		{lis3dhRegisterCtrl1, d.dataRate<<4 | 0x07},
+		{lis3dhRegisterCtrl2, 0x00},
+		{lis3dhRegisterCtrl3, 0xC0},
+		{lis3dhRegisterCtrl4, 0x88 | d.fullScale<<4},
+		{lis3dhRegisterCtrl5, 0x0A},
+		{lis3dhRegisterCtrl6, 0x20},
+		{lis3dhRegisterClickCfg, 0x00},
+		{lis3dhRegisterInt1Cfg, 0x00},
+		{lis3dhRegisterInt2Cfg, 0x00},
+	}); err != nil {
+		return err
+	}
+	d.click, d.freeFall, d.activity = false, false, false
+
+	config := d.polling
+	if config.Scheduler == nil {
+		config.Scheduler = BusScheduler(d.connector, d.GetBusOrDefault(d.connector.GetDefaultBus()))
+	}
+	d.halt = make(chan bool)
+	gobot.NewPoller(config).Go(d, d.halt, d.poll)
+	return nil
+}
+
+// Halt stops the polling of the sources of the engines.
+func (d *LIS3DHDriver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.halt != nil {
+		close(d.halt)
+		d.halt = nil
+	}
+	return nil
+}
+
+// DebugDump reads WHO_AM_I, CTRL_REG1 to CTRL_REG6, STATUS_REG and the interrupt, click and timing registers.
+func (d *LIS3DHDriver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, lis3dhRegisters)
+}
+
+// RawXYZ returns the raw acceleration of the x, y and z axes, left justified
+// 12 bits values
+func (d *LIS3DHDriver) RawXYZ() (x, y, z int16, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	data, err := d.read(lis3dhRegisterOutX|lis3dhAutoIncrement, 6)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	x = int16(uint16(data[0]) | uint16(data[1])<<8)
+	y = int16(uint16(data[2]) | uint16(data[3])<<8)
+	z = int16(uint16(data[4]) | uint16(data[5])<<8)
+	return x, y, z, nil
+}
+
+// XYZ returns the acceleration of the x, y and z axes, in g
+func (d *LIS3DHDriver) XYZ() (x, y, z float64, err error) {
+	rx, ry, rz, err := d.RawXYZ()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	sensitivity := lis3dhSensitivities[d.fullScale] / 1000
+	return float64(rx>>4) * sensitivity, float64(ry>>4) * sensitivity, float64(rz>>4) * sensitivity, nil
+}
+
+// SetClick configures the click engine, publishing the Click and DoubleClick
+// events. It is off when neither c.Single nor c.Double is set.
+func (d *LIS3DHDriver) SetClick(c LIS3DHClickConfig) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var cfg byte
+	if c.Single {
+		cfg |= 0x15
+	}
+	if c.Double {
+		cfg |= 0x2A
+	}
+	// the latched clicks are kept until polled
+	if err := d.writeRegisters([][2]byte{
+		{lis3dhRegisterClickThs, 0x80 | d.threshold(c.Threshold)},
+		{lis3dhRegisterTimeLimit, d.duration(c.TimeLimit, 0x7F)},
+		{lis3dhRegisterTimeLatency, d.duration(c.Latency, 0xFF)},
+		{lis3dhRegisterTimeWindow, d.duration(c.Window, 0xFF)},
+		{lis3dhRegisterClickCfg, cfg},
+	}); err != nil {
+		return err
+	}
+	d.click = cfg != 0
+	return nil
+}
+
+// SetFreeFall configures the free-fall engine on INT1, publishing the
+// FreeFall event when all the axes measure less than threshold, in g, for
+// duration. A threshold around 0.35g and a duration of 30ms detect a short
+// drop. A zero threshold turns it off.
+func (d *LIS3DHDriver) SetFreeFall(threshold float64, duration time.Duration) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var cfg byte
+	if threshold > 0 {
+		cfg = lis3dhFreeFallCfg
+	}
+	if err := d.writeRegisters([][2]byte{
+		{lis3dhRegisterInt1Ths, d.threshold(threshold)},
+		{lis3dhRegisterInt1Dur, d.duration(duration, 0x7F)},
+		{lis3dhRegisterInt1Cfg, cfg},
+	}); err != nil {
+		return err
+	}
+	d.freeFall = cfg != 0
+	return nil
+}
+
+// SetActivity configures the activity engine on INT2, publishing the
+// Activity event when an axis measures more than threshold, in g, for
+// duration, gravity being filtered out. A zero threshold turns it off.
+func (d *LIS3DHDriver) SetActivity(threshold float64, duration time.Duration) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var cfg, filter byte
+	if threshold > 0 {
+		cfg, filter = lis3dhActivityCfg, lis3dhHighPassInt2
+	}
+	if err := d.writeRegisters([][2]byte{
+		{lis3dhRegisterCtrl2, filter},
+		{lis3dhRegisterInt2Ths, d.threshold(threshold)},
+		{lis3dhRegisterInt2Dur, d.duration(duration, 0x7F)},
+		{lis3dhRegisterInt2Cfg, cfg},
+	}); err != nil {
+		return err
+	}
+	d.activity = cfg != 0
+	return nil
+}
+
+// poll reads the sources of the enabled engines, which clears them, and
+// publishes their detections
+func (d *LIS3DHDriver) poll() (changed bool) {
+	d.mutex.Lock()
+	type detection struct {
+		event string
+		data  LIS3DHEvent
+	}
+	detections := []detection{}
+	var err error
+	if d.click {
+		var src []byte
+		if src, err = d.read(lis3dhRegisterClickSrc, 1); err == nil && src[0]&lis3dhInterruptActive != 0 {
+			e := LIS3DHEvent{X: src[0]&0x01 != 0, Y: src[0]&0x02 != 0, Z: src[0]&0x04 != 0, Negative: src[0]&0x08 != 0}
+			if src[0]&0x10 != 0 {
+				detections = append(detections, detection{Click, e})
+			}
+			if src[0]&0x20 != 0 {
+				detections = append(detections, detection{DoubleClick, e})
+			}
+		}
+	}
+	if d.freeFall && err == nil {
+		var src []byte
+		if src, err = d.read(lis3dhRegisterInt1Src, 1); err == nil && src[0]&lis3dhInterruptActive != 0 {
+			e := LIS3DHEvent{X: src[0]&0x01 != 0, Y: src[0]&0x04 != 0, Z: src[0]&0x10 != 0}
+			detections = append(detections, detection{FreeFall, e})
+		}
+	}
+	if d.activity && err == nil {
+		var src []byte
+		if src, err = d.read(lis3dhRegisterInt2Src, 1); err == nil && src[0]&lis3dhInterruptActive != 0 {
+			e := LIS3DHEvent{X: src[0]&0x02 != 0, Y: src[0]&0x08 != 0, Z: src[0]&0x20 != 0}
+			detections = append(detections, detection{Activity, e})
+		}
+	}
+	d.mutex.Unlock()
+
+	if err != nil {
+		d.Publish(d.Event(Error), err)
+	}
+	for _, detection := range detections {
+		d.Publish(d.Event(detection.event), detection.data)
+	}
+	return len(detections) > 0
+}
+
+// threshold returns the threshold register value of g
+func (d *LIS3DHDriver) threshold(g float64) byte {
+	return byte(math.Max(0, math.Min(0x7F, math.Round(g*1000/lis3dhThresholds[d.fullScale]))))
+}
+
+// duration returns the number of output data periods of t, up to max
+func (d *LIS3DHDriver) duration(t time.Duration, max float64) byte {
+	return byte(math.Max(0, math.Min(max, math.Round(t.Seconds()*lis3dhDataRates[d.dataRate]))))
+}
+
+func (d *LIS3DHDriver) writeRegisters(values [][2]byte) error {
+	if d.connection == nil {
+		return ErrNotConnected
+	}
+	for _, v := range values {
+		if err := d.connection.WriteByteData(v[0], v[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *LIS3DHDriver) read(address byte, n int) ([]byte, error) {
+	if d.connection == nil {
+		return nil, ErrNotConnected
+	}
+	if _, err := d.connection.Write([]byte{address}); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	bytesRead, err := d.connection.Read(buf)
+	if err != nil {
+		return nil, ErrRegisterRead{Reg: address, Err: err}
+	}
+	if bytesRead != n {
+		return nil, ErrRegisterRead{Reg: address, Err: ErrNotEnoughBytes}
+	}
+	return buf, nil
+}
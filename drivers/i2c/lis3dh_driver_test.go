@@ -0,0 +1,242 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*LIS3DHDriver)(nil)
+
+// lis3dhTestRegisters are the registers of a LIS3DH read by the test adaptor
+type lis3dhTestRegisters struct {
+	regs  []byte
+	mutex sync.Mutex
+}
+
+func (r *lis3dhTestRegisters) set(reg byte, val ...byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	copy(r.regs[reg:], val)
+}
+
+func initTestLIS3DHDriverWithStubbedAdaptor(options ...func(Config)) (*LIS3DHDriver, *i2cTestAdaptor, *lis3dhTestRegisters) {
+	adaptor := newI2cTestAdaptor()
+	regs := &lis3dhTestRegisters{regs: make([]byte, 256)}
+	regs.regs[lis3dhRegisterWhoAmI] = lis3dhWhoAmI
+	var reg byte
+	adaptor.i2cWriteImpl = func(b []byte) (int, error) {
+		reg = b[0] &^ lis3dhAutoIncrement
+		return len(b), nil
+	}
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		regs.mutex.Lock()
+		defer regs.mutex.Unlock()
+		n := copy(b, regs.regs[reg:])
+		// the sources are cleared once read
+		switch reg {
+		case lis3dhRegisterClickSrc, lis3dhRegisterInt1Src, lis3dhRegisterInt2Src:
+			regs.regs[reg] = 0
+		}
+		return n, nil
+	}
+	d := NewLIS3DHDriver(adaptor, options...)
+	// the tests poll the sources themselves
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	return d, adaptor, regs
+}
+
+// startTestLIS3DHDriver starts d with a FakeClock, which is never advanced,
+// and waits for the first poll so that the tests poll the sources themselves
+// without racing with the poller. It returns the function halting d.
+func startTestLIS3DHDriver(t *testing.T, d *LIS3DHDriver) func() {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	gobottest.Assert(t, d.Start(), nil)
+	clock.BlockUntil(1)
+	return func() {
+		d.Halt()
+		gobot.SetDefaultClock(gobot.SystemClock())
+	}
+}
+
+func TestLIS3DHDriverName(t *testing.T) {
+	d, _, _ := initTestLIS3DHDriverWithStubbedAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "LIS3DH"), true)
+	d.SetName("accelerometer")
+	gobottest.Assert(t, d.Name(), "accelerometer")
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Refute(t, d.Command("XYZ"), nil)
+}
+
+func TestLIS3DHDriverStart(t *testing.T) {
+	d, adaptor, _ := initTestLIS3DHDriverWithStubbedAdaptor(
+		WithLIS3DHRange(LIS3DHRange8G), WithLIS3DHDataRate(LIS3DHDataRate100Hz))
+	defer startTestLIS3DHDriver(t, d)()
+
+	gobottest.Assert(t, bytes.Contains(adaptor.written, []byte{
+		lis3dhRegisterCtrl1, 0x57,
+		lis3dhRegisterCtrl2, 0x00,
+		lis3dhRegisterCtrl3, 0xC0,
+		lis3dhRegisterCtrl4, 0xA8,
+		lis3dhRegisterCtrl5, 0x0A,
+		lis3dhRegisterCtrl6, 0x20,
+	}), true)
+}
+
+func TestLIS3DHDriverStartErrors(t *testing.T) {
+	d, adaptor, regs := initTestLIS3DHDriverWithStubbedAdaptor()
+	regs.set(lis3dhRegisterWhoAmI, 0x44)
+	gobottest.Assert(t, d.Start(), ErrBadDevice{Device: "LIS3DH", ID: 0x44})
+
+	adaptor.Testi2cConnectErr(true)
+	gobottest.Assert(t, d.Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestLIS3DHDriverXYZ(t *testing.T) {
+	d, _, regs := initTestLIS3DHDriverWithStubbedAdaptor(WithLIS3DHRange(LIS3DHRange4G))
+	defer startTestLIS3DHDriver(t, d)()
+
+	// 500, -250 and 1000 digits
+	regs.set(lis3dhRegisterOutX, 0x40, 0x1F, 0x60, 0xF0, 0x80, 0x3E)
+	x, y, z, err := d.RawXYZ()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, []int16{x, y, z}, []int16{8000, -4000, 16000})
+
+	ax, ay, az, err := d.XYZ()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, []float64{ax, ay, az}, []float64{1, -0.5, 2})
+}
+
+func TestLIS3DHDriverXYZNotStarted(t *testing.T) {
+	d, _, _ := initTestLIS3DHDriverWithStubbedAdaptor()
+	_, _, _, err := d.XYZ()
+	gobottest.Assert(t, err, ErrNotConnected)
+	gobottest.Assert(t, d.SetFreeFall(0.35, 30*time.Millisecond), ErrNotConnected)
+}
+
+func TestLIS3DHDriverClick(t *testing.T) {
+	d, adaptor, regs := initTestLIS3DHDriverWithStubbedAdaptor()
+	defer startTestLIS3DHDriver(t, d)()
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.SetClick(LIS3DHClickConfig{Single: true, Double: true, Threshold: 1.25,
+		TimeLimit: 25 * time.Millisecond, Latency: 50 * time.Millisecond, Window: 600 * time.Millisecond}), nil)
+	gobottest.Assert(t, adaptor.written, []byte{
+		lis3dhRegisterClickThs, 0xCE,
+		lis3dhRegisterTimeLimit, 10,
+		lis3dhRegisterTimeLatency, 20,
+		lis3dhRegisterTimeWindow, 240,
+		lis3dhRegisterClickCfg, 0x3F,
+	})
+
+	clicks, doubleClicks := make(chan interface{}, 1), make(chan interface{}, 1)
+	d.Once(d.Event(Click), func(data interface{}) { clicks <- data })
+	d.Once(d.Event(DoubleClick), func(data interface{}) { doubleClicks <- data })
+
+	regs.set(lis3dhRegisterClickSrc, 0x5C)
+	gobottest.Assert(t, d.poll(), true)
+	select {
+	case data := <-clicks:
+		gobottest.Assert(t, data, LIS3DHEvent{Z: true, Negative: true})
+	case <-time.After(time.Second):
+		t.Errorf("Click event was not published")
+	}
+
+	regs.set(lis3dhRegisterClickSrc, 0x61)
+	gobottest.Assert(t, d.poll(), true)
+	gobottest.Assert(t, d.poll(), false)
+	select {
+	case data := <-doubleClicks:
+		gobottest.Assert(t, data, LIS3DHEvent{X: true})
+	case <-time.After(time.Second):
+		t.Errorf("DoubleClick event was not published")
+	}
+}
+
+func TestLIS3DHDriverFreeFall(t *testing.T) {
+	d, adaptor, regs := initTestLIS3DHDriverWithStubbedAdaptor()
+	defer startTestLIS3DHDriver(t, d)()
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.SetFreeFall(0.35, 30*time.Millisecond), nil)
+	gobottest.Assert(t, adaptor.written, []byte{
+		lis3dhRegisterInt1Ths, 22,
+		lis3dhRegisterInt1Dur, 12,
+		lis3dhRegisterInt1Cfg, 0x95,
+	})
+
+	falls := make(chan interface{}, 1)
+	d.Once(d.Event(FreeFall), func(data interface{}) { falls <- data })
+	regs.set(lis3dhRegisterInt1Src, 0x55)
+	gobottest.Assert(t, d.poll(), true)
+	select {
+	case data := <-falls:
+		gobottest.Assert(t, data, LIS3DHEvent{X: true, Y: true, Z: true})
+	case <-time.After(time.Second):
+		t.Errorf("FreeFall event was not published")
+	}
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.SetFreeFall(0, 0), nil)
+	gobottest.Assert(t, adaptor.written[len(adaptor.written)-2:], []byte{lis3dhRegisterInt1Cfg, 0x00})
+}
+
+func TestLIS3DHDriverActivity(t *testing.T) {
+	d, adaptor, regs := initTestLIS3DHDriverWithStubbedAdaptor()
+	defer startTestLIS3DHDriver(t, d)()
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.SetActivity(0.2, 0), nil)
+	gobottest.Assert(t, adaptor.written, []byte{
+		lis3dhRegisterCtrl2, 0x02,
+		lis3dhRegisterInt2Ths, 13,
+		lis3dhRegisterInt2Dur, 0,
+		lis3dhRegisterInt2Cfg, 0x2A,
+	})
+
+	moves := make(chan interface{}, 1)
+	d.Once(d.Event(Activity), func(data interface{}) { moves <- data })
+	regs.set(lis3dhRegisterInt2Src, 0x48)
+	gobottest.Assert(t, d.poll(), true)
+	select {
+	case data := <-moves:
+		gobottest.Assert(t, data, LIS3DHEvent{Y: true})
+	case <-time.After(time.Second):
+		t.Errorf("Activity event was not published")
+	}
+}
+
+func TestLIS3DHDriverPollError(t *testing.T) {
+	d, adaptor, _ := initTestLIS3DHDriverWithStubbedAdaptor()
+	defer startTestLIS3DHDriver(t, d)()
+	gobottest.Assert(t, d.SetActivity(0.2, 0), nil)
+
+	errs := make(chan interface{}, 1)
+	d.Once(d.Event(Error), func(data interface{}) { errs <- data })
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return 0, errors.New("read error")
+	})
+	gobottest.Assert(t, d.poll(), false)
+	select {
+	case data := <-errs:
+		gobottest.Assert(t, data, ErrRegisterRead{Reg: lis3dhRegisterInt2Src, Err: errors.New("read error")})
+	case <-time.After(time.Second):
+		t.Errorf("Error event was not published")
+	}
+}
+
+func TestLIS3DHDriverDebugDump(t *testing.T) {
+	d, _, _ := initTestLIS3DHDriverWithStubbedAdaptor()
+	defer startTestLIS3DHDriver(t, d)()
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(dump), len(lis3dhRegisters))
+}
@@ -18,16 +18,14 @@ package i2c
 
 import (
 	"fmt"
-	"log"
 	"strings"
+	"sync"
 
 	"gobot.io/x/gobot"
 )
 
 const mcp23017Address = 0x20
 
-var debug = false // Set this to true to see debugging information
-
 // Port contains all the registers for the device.
 type port struct {
 	IODIR   uint8 // I/O direction register: 0=output / 1=input
@@ -50,6 +48,18 @@ type bank struct {
 	PortB port
 }
 
+// mcp23017IOCONFields are the fields of the IOCON register, as set from
+// MCP23017Config
+var mcp23017IOCONFields = []RegisterField{
+	{Name: "BANK", Shift: 7, Width: 1},
+	{Name: "MIRROR", Shift: 6, Width: 1},
+	{Name: "SEQOP", Shift: 5, Width: 1},
+	{Name: "DISSLW", Shift: 4, Width: 1},
+	{Name: "HAEN", Shift: 3, Width: 1},
+	{Name: "ODR", Shift: 2, Width: 1},
+	{Name: "INTPOL", Shift: 1, Width: 1},
+}
+
 // MCP23017Config contains the device configuration for the IOCON register.
 // These fields should only be set with values 0 or 1.
 type MCP23017Config struct {
@@ -67,8 +77,11 @@ type MCP23017Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
 	MCPConf MCP23017Config
+	logger  gobot.Logger
 	gobot.Commander
 	gobot.Eventer
 }
@@ -93,11 +106,13 @@ func NewMCP23017Driver(a Connector, options ...func(Config)) *MCP23017Driver {
 		name:      gobot.DefaultName("MCP23017"),
 		connector: a,
 		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
 		MCPConf:   MCP23017Config{},
 		Commander: gobot.NewCommander(),
 		Eventer:   gobot.NewEventer(),
 	}
 
+	m.driverConfig = driverConfig{config: &m.Config}
 	for _, option := range options {
 		option(m)
 	}
@@ -117,6 +132,8 @@ func NewMCP23017Driver(a Connector, options ...func(Config)) *MCP23017Driver {
 		return map[string]interface{}{"val": val, "err": err}
 	})
 
+	addDebugDumpCommand(m, m)
+
 	return m
 }
 
@@ -129,15 +146,34 @@ func (m *MCP23017Driver) SetName(n string) { m.name = n }
 // Connection returns the I2c connection.
 func (m *MCP23017Driver) Connection() gobot.Connection { return m.connector.(gobot.Connection) }
 
+// Logger returns the driver Logger.
+func (m *MCP23017Driver) Logger() gobot.Logger {
+	if m.logger != nil {
+		return m.logger
+	}
+	return gobot.DefaultLogger().WithComponent(m.name)
+}
+
+// SetLogger sets the driver Logger.
+func (m *MCP23017Driver) SetLogger(l gobot.Logger) { m.logger = l }
+
 // Halt stops the driver.
 func (m *MCP23017Driver) Halt() (err error) { return }
 
+// DebugDump reads the registers of both ports of the device, for debugging it.
+func (m *MCP23017Driver) DebugDump() (RegisterDump, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return DumpRegisters(m.connection, m.registers())
+}
+
 // Start writes the device configuration.
 func (m *MCP23017Driver) Start() (err error) {
-	bus := m.GetBusOrDefault(m.connector.GetDefaultBus())
-	address := m.GetAddressOrDefault(mcp23017Address)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
-	m.connection, err = m.connector.GetConnection(address, bus)
+	m.connection, err = connect(m, m.connector, m.Config, mcp23017Address)
 	if err != nil {
 		return err
 	}
@@ -152,6 +188,9 @@ func (m *MCP23017Driver) Start() (err error) {
 
 // WriteGPIO writes a value to a gpio pin (0-7) and a port (A or B).
 func (m *MCP23017Driver) WriteGPIO(pin uint8, val uint8, portStr string) (err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	selectedPort := m.getPort(portStr)
 	// Set IODIR register bit for given pin to an output.
 	if err := m.write(selectedPort.IODIR, uint8(pin), 0); err != nil {
@@ -168,6 +207,9 @@ func (m *MCP23017Driver) WriteGPIO(pin uint8, val uint8, portStr string) (err er
 // val (0 output 1 input)
 // port (A or B).
 func (m *MCP23017Driver) PinMode(pin, val uint8, portStr string) (err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	selectedPort := m.getPort(portStr)
 	// Set IODIR register bit for given pin to an output/input.
 	if err = m.write(selectedPort.IODIR, uint8(pin), val); err != nil {
@@ -179,6 +221,9 @@ func (m *MCP23017Driver) PinMode(pin, val uint8, portStr string) (err error) {
 // ReadGPIO reads a value from a given gpio pin (0-7) and a
 // port (A or B).
 func (m *MCP23017Driver) ReadGPIO(pin uint8, portStr string) (val uint8, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	selectedPort := m.getPort(portStr)
 	val, err = m.read(selectedPort.GPIO)
 	if err != nil {
@@ -191,6 +236,9 @@ func (m *MCP23017Driver) ReadGPIO(pin uint8, portStr string) (val uint8, err err
 // val = 1 pull up enabled.
 // val = 0 pull up disabled.
 func (m *MCP23017Driver) SetPullUp(pin uint8, val uint8, portStr string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	selectedPort := m.getPort(portStr)
 	return m.write(selectedPort.GPPU, pin, val)
 }
@@ -199,6 +247,9 @@ func (m *MCP23017Driver) SetPullUp(pin uint8, val uint8, portStr string) error {
 // val = 1 opposite logic state of the input pin.
 // val = 0 same logic state of the input pin.
 func (m *MCP23017Driver) SetGPIOPolarity(pin uint8, val uint8, portStr string) (err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	selectedPort := m.getPort(portStr)
 	return m.write(selectedPort.IPOL, pin, val)
 }
@@ -300,9 +351,10 @@ func (m *MCP23017Driver) write(reg uint8, pin uint8, val uint8) (err error) {
 	} else if val == 1 {
 		ioval = setBit(iodir, uint8(pin))
 	}
-	if debug {
-		log.Printf("Writing: MCP address: 0x%X, register: 0x%X\t, value: 0x%X\n", m.GetAddressOrDefault(mcp23017Address), reg, ioval)
-	}
+	m.Logger().Debug("Writing",
+		"address", fmt.Sprintf("0x%X", m.GetAddressOrDefault(mcp23017Address)),
+		"register", fmt.Sprintf("0x%X", reg),
+		"value", fmt.Sprintf("0x%X", ioval))
 	if _, err = m.connection.Write([]uint8{reg, ioval}); err != nil {
 		return err
 	}
@@ -322,14 +374,41 @@ func (m *MCP23017Driver) read(reg uint8) (val uint8, err error) {
 		return val, err
 	}
 	if bytesRead != bytesToRead {
-		return val, fmt.Errorf("Read was unable to get %d bytes for register: 0x%X\n", bytesToRead, reg)
-	}
-	if debug {
-		log.Printf("Reading: MCP address: 0x%X, register:0x%X\t,value: 0x%X\n", m.GetAddressOrDefault(mcp23017Address), reg, buf[register])
+		return val, ErrRegisterRead{Reg: reg, Err: ErrNotEnoughBytes}
 	}
+	m.Logger().Debug("Reading",
+		"address", fmt.Sprintf("0x%X", m.GetAddressOrDefault(mcp23017Address)),
+		"register", fmt.Sprintf("0x%X", reg),
+		"value", fmt.Sprintf("0x%X", buf[register]))
 	return buf[register], nil
 }
 
+// registers returns the registers of both ports for the current bank, named
+// after the datasheet, e.g. IODIRA.
+func (m *MCP23017Driver) registers() RegisterMap {
+	b := getBank(m.MCPConf.Bank)
+	regs := RegisterMap{}
+	for _, p := range []struct {
+		name string
+		port port
+	}{{"A", b.PortA}, {"B", b.PortB}} {
+		regs = append(regs,
+			Register{Name: "IODIR" + p.name, Address: p.port.IODIR},
+			Register{Name: "IPOL" + p.name, Address: p.port.IPOL},
+			Register{Name: "GPINTEN" + p.name, Address: p.port.GPINTEN},
+			Register{Name: "DEFVAL" + p.name, Address: p.port.DEFVAL},
+			Register{Name: "INTCON" + p.name, Address: p.port.INTCON},
+			Register{Name: "IOCON" + p.name, Address: p.port.IOCON, Fields: mcp23017IOCONFields},
+			Register{Name: "GPPU" + p.name, Address: p.port.GPPU},
+			Register{Name: "INTF" + p.name, Address: p.port.INTF},
+			Register{Name: "INTCAP" + p.name, Address: p.port.INTCAP},
+			Register{Name: "GPIO" + p.name, Address: p.port.GPIO},
+			Register{Name: "OLAT" + p.name, Address: p.port.OLAT},
+		)
+	}
+	return regs
+}
+
 // getPort return the port (A or B) given a string and the bank.
 // Port A is the default if an incorrect or no port is specified.
 func (m *MCP23017Driver) getPort(portStr string) (selectedPort port) {
@@ -92,6 +92,25 @@ func TestMCP23017DriverStart(t *testing.T) {
 	gobottest.Assert(t, err, errors.New("write error"))
 }
 
+// infoLogger records the messages logged at LogInfo level
+type infoLogger struct {
+	gobot.Logger
+	infos []string
+}
+
+func (l *infoLogger) Info(msg string, keyvals ...interface{}) { l.infos = append(l.infos, msg) }
+
+func TestMCP23017DriverStartLogsFoundAddress(t *testing.T) {
+	adaptor := newI2cTestAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) { return len(b), nil }
+	mcp := NewMCP23017Driver(adaptor, WithAddresses([]int{0x20, 0x21}))
+	l := &infoLogger{Logger: gobot.NewLogger()}
+	mcp.SetLogger(l)
+
+	gobottest.Assert(t, mcp.Start(), nil)
+	gobottest.Assert(t, l.infos, []string{"device found"})
+}
+
 func TestMCP23017StartConnectError(t *testing.T) {
 	d, adaptor := initTestMCP23017DriverWithStubbedAdaptor(0)
 	adaptor.Testi2cConnectErr(true)
@@ -341,7 +360,7 @@ func TestMCP23017DriverWrite(t *testing.T) {
 	gobottest.Assert(t, err, errors.New("read error"))
 
 	//debug
-	debug = true
+	mcp.SetLogger(debugLogger())
 	log.SetOutput(ioutil.Discard)
 	adaptor.i2cReadImpl = func(b []byte) (int, error) {
 		return len(b), nil
@@ -351,7 +370,6 @@ func TestMCP23017DriverWrite(t *testing.T) {
 	}
 	err = mcp.write(port.IODIR, uint8(7), 1)
 	gobottest.Assert(t, err, nil)
-	debug = false
 	log.SetOutput(os.Stdout)
 }
 
@@ -389,12 +407,13 @@ func TestMCP23017DriverReadPort(t *testing.T) {
 		return 0, nil
 	}
 	_, err = mcp.read(port.IODIR)
-	gobottest.Assert(t, err, errors.New("Read was unable to get 1 bytes for register: 0x0\n"))
+	gobottest.Assert(t, err, ErrRegisterRead{Reg: 0, Err: ErrNotEnoughBytes})
+	gobottest.Assert(t, err.Error(), "reading register 0x00: Not enough bytes read")
 
 	// debug
-	debug = true
 	log.SetOutput(ioutil.Discard)
 	mcp, adaptor = initTestMCP23017DriverWithStubbedAdaptor(0)
+	mcp.SetLogger(debugLogger())
 	gobottest.Assert(t, mcp.Start(), nil)
 
 	port = mcp.getPort("A")
@@ -406,7 +425,6 @@ func TestMCP23017DriverReadPort(t *testing.T) {
 
 	val, _ = mcp.read(port.IODIR)
 	gobottest.Assert(t, val, uint8(255))
-	debug = false
 	log.SetOutput(os.Stdout)
 }
 
@@ -453,3 +471,19 @@ func TestMCP23017DriverSetName(t *testing.T) {
 	d.SetName("TESTME")
 	gobottest.Assert(t, d.Name(), "TESTME")
 }
+
+func TestMCP23017DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestMCP23017DriverWithStubbedAdaptor(0)
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x08
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["IOCONA"].(map[string]interface{})["HAEN"], uint8(1))
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
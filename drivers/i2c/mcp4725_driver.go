@@ -0,0 +1,233 @@
+package i2c
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// MCP4725DefaultAddress is the default I2C address of the MCP4725, 0x62 on
+// the Adafruit boards
+const MCP4725DefaultAddress = 0x60
+
+// MCP4725 commands
+const (
+	mcp4725CmdWriteDAC       = 0x40
+	mcp4725CmdWriteDACEEPROM = 0x60
+	// mcp4725StatusReady is the bit of the status cleared while the EEPROM
+	// is written
+	mcp4725StatusReady = 0x80
+	mcp4725MaxValue    = 0x0FFF
+)
+
+// MCP4725PowerDown is a power-down mode of the MCP4725, in which the output
+// is off and pulled down through a resistor.
+type MCP4725PowerDown byte
+
+const (
+	// MCP4725Normal is the normal mode, the output being on
+	MCP4725Normal MCP4725PowerDown = iota
+	// MCP4725PowerDown1K pulls the output down through 1 kOhm
+	MCP4725PowerDown1K
+	// MCP4725PowerDown100K pulls the output down through 100 kOhm
+	MCP4725PowerDown100K
+	// MCP4725PowerDown500K pulls the output down through 500 kOhm
+	MCP4725PowerDown500K
+)
+
+// ErrMCP4725EEPROMBusy is returned when the EEPROM of a MCP4725 is still
+// being written after 50ms.
+var ErrMCP4725EEPROMBusy = errors.New("MCP4725 EEPROM write did not complete")
+
+// MCP4725Driver is a driver for the MCP4725 12 bits DAC, whose output ranges
+// from 0 to its supply voltage. Its EEPROM keeps the value output at power
+// on.
+type MCP4725Driver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+
+	reference float64
+	value     uint16
+}
+
+// NewMCP4725Driver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithMCP4725Reference(float64):	supply voltage of the DAC, 3.3V by default
+//
+func NewMCP4725Driver(c Connector, options ...func(Config)) *MCP4725Driver {
+	d := &MCP4725Driver{
+		name:      gobot.DefaultName("MCP4725"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
+		reference: 3.3,
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddCommand("Write", func(params map[string]interface{}) interface{} {
+		return d.Write(uint16(params["value"].(float64)))
+	})
+	d.AddCommand("SetVoltage", func(params map[string]interface{}) interface{} {
+		return d.SetVoltage(params["voltage"].(float64))
+	})
+	d.AddCommand("WriteEEPROM", func(params map[string]interface{}) interface{} {
+		return d.WriteEEPROM(uint16(params["value"].(float64)))
+	})
+
+	return d
+}
+
+// WithMCP4725Reference option sets the supply voltage of the MCP4725, which
+// is its full scale output, 3.3V by default.
+func WithMCP4725Reference(volts float64) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*MCP4725Driver); ok {
+			d.reference = volts
+		}
+	}
+}
+
+// Name returns the name of the device.
+func (d *MCP4725Driver) Name() string { return d.name }
+
+// SetName sets the name of the device.
+func (d *MCP4725Driver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device.
+func (d *MCP4725Driver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Start initializes the DAC, keeping its current output.
+func (d *MCP4725Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.connection, err = connect(d, d.connector, d.Config, MCP4725DefaultAddress)
+	return err
+}
+
+// Halt does nothing, the DAC keeping its output.
+func (d *MCP4725Driver) Halt() (err error) { return }
+
+// Write sets the output to value, from 0 to 4095, with a fast write.
+func (d *MCP4725Driver) Write(value uint16) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	value = clampMCP4725(value)
+	if _, err := d.connection.Write([]byte{byte(value >> 8), byte(value)}); err != nil {
+		return err
+	}
+	d.value = value
+	return nil
+}
+
+// Value returns the last value written.
+func (d *MCP4725Driver) Value() uint16 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.value
+}
+
+// AnalogWrite sets the output to value, from 0 to 4095. The DAC has a single
+// output, the pin is ignored.
+func (d *MCP4725Driver) AnalogWrite(pin string, value int) error {
+	if value < 0 {
+		value = 0
+	}
+	return d.Write(uint16(math.Min(float64(value), mcp4725MaxValue)))
+}
+
+// SetVoltage sets the output to volts, relative to the reference voltage.
+func (d *MCP4725Driver) SetVoltage(volts float64) error {
+	v := math.Round(volts / d.reference * mcp4725MaxValue)
+	return d.Write(uint16(math.Max(0, math.Min(v, mcp4725MaxValue))))
+}
+
+// WriteEEPROM sets the output to value and saves it in the EEPROM, as the
+// value output at power on. The EEPROM is written in up to 50ms.
+func (d *MCP4725Driver) WriteEEPROM(value uint16) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	value = clampMCP4725(value)
+	if _, err := d.connection.Write([]byte{mcp4725CmdWriteDACEEPROM, byte(value >> 4), byte(value << 4)}); err != nil {
+		return err
+	}
+	d.value = value
+
+	clock := gobot.DefaultClock()
+	deadline := clock.Now().Add(50 * time.Millisecond)
+	for {
+		b, err := d.read()
+		if err != nil {
+			return err
+		}
+		if b[0]&mcp4725StatusReady != 0 {
+			return nil
+		}
+		if !clock.Now().Before(deadline) {
+			return ErrMCP4725EEPROMBusy
+		}
+		<-clock.After(5 * time.Millisecond)
+	}
+}
+
+// PowerDown turns the output off in one of the power-down modes, or back on
+// with MCP4725Normal at the last value written. A Write turns it back on too.
+func (d *MCP4725Driver) PowerDown(mode MCP4725PowerDown) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	_, err := d.connection.Write([]byte{mcp4725CmdWriteDAC | byte(mode)<<1, byte(d.value >> 4), byte(d.value << 4)})
+	return err
+}
+
+// Read returns the current output value and the one saved in the EEPROM.
+func (d *MCP4725Driver) Read() (value uint16, eeprom uint16, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	b, err := d.read()
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint16(b[1])<<4 | uint16(b[2])>>4, uint16(b[3]&0x0F)<<8 | uint16(b[4]), nil
+}
+
+// read returns the status, the DAC register and the EEPROM
+func (d *MCP4725Driver) read() ([]byte, error) {
+	b := make([]byte, 5)
+	n, err := d.connection.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(b) {
+		return nil, ErrNotEnoughBytes
+	}
+	return b, nil
+}
+
+func clampMCP4725(value uint16) uint16 {
+	if value > mcp4725MaxValue {
+		return mcp4725MaxValue
+	}
+	return value
+}
@@ -0,0 +1,113 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*MCP4725Driver)(nil)
+var _ aio.AnalogWriter = (*MCP4725Driver)(nil)
+
+// initTestMCP4725DriverWithStubbedAdaptor returns a MCP4725Driver whose DAC
+// outputs 0x800 and whose EEPROM holds 0x123
+func initTestMCP4725DriverWithStubbedAdaptor(options ...func(Config)) (*MCP4725Driver, *i2cTestAdaptor) {
+	adaptor := newI2cTestAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0xC0, 0x80, 0x00, 0x01, 0x23}), nil
+	}
+	d := NewMCP4725Driver(adaptor, options...)
+	d.Start()
+	return d, adaptor
+}
+
+func TestMCP4725Driver(t *testing.T) {
+	d, _ := initTestMCP4725DriverWithStubbedAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "MCP4725"), true)
+	d.SetName("dac")
+	gobottest.Assert(t, d.Name(), "dac")
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestMCP4725DriverStartConnectError(t *testing.T) {
+	adaptor := newI2cTestAdaptor()
+	adaptor.Testi2cConnectErr(true)
+	gobottest.Assert(t, NewMCP4725Driver(adaptor).Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestMCP4725DriverWrite(t *testing.T) {
+	d, adaptor := initTestMCP4725DriverWithStubbedAdaptor(WithMCP4725Reference(5))
+	gobottest.Assert(t, d.Write(0xABC), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x0A, 0xBC})
+	gobottest.Assert(t, d.Value(), uint16(0xABC))
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.Write(5000), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x0F, 0xFF})
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.SetVoltage(2.5), nil)
+	gobottest.Assert(t, d.Value(), uint16(2048))
+	gobottest.Assert(t, d.SetVoltage(-1), nil)
+	gobottest.Assert(t, d.Value(), uint16(0))
+
+	gobottest.Assert(t, d.AnalogWrite("0", 100000), nil)
+	gobottest.Assert(t, d.Value(), uint16(4095))
+	gobottest.Assert(t, d.Command("Write")(map[string]interface{}{"value": 1000.0}), nil)
+	gobottest.Assert(t, d.Value(), uint16(1000))
+
+	adaptor.Testi2cWriteImpl(func(b []byte) (int, error) {
+		return 0, errors.New("write error")
+	})
+	gobottest.Assert(t, d.Write(1), errors.New("write error"))
+	gobottest.Assert(t, d.Value(), uint16(1000))
+}
+
+func TestMCP4725DriverRead(t *testing.T) {
+	d, adaptor := initTestMCP4725DriverWithStubbedAdaptor()
+	value, eeprom, err := d.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, value, uint16(0x800))
+	gobottest.Assert(t, eeprom, uint16(0x123))
+
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return 2, nil
+	})
+	_, _, err = d.Read()
+	gobottest.Assert(t, err, ErrNotEnoughBytes)
+}
+
+func TestMCP4725DriverWriteEEPROM(t *testing.T) {
+	d, adaptor := initTestMCP4725DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.WriteEEPROM(0x123), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x60, 0x12, 0x30})
+
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return copy(b, []byte{0x40, 0, 0, 0, 0}), nil
+	})
+	done := make(chan error)
+	go func() { done <- d.WriteEEPROM(0x123) }()
+	for i := 0; i < 10; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Millisecond)
+	}
+	gobottest.Assert(t, <-done, ErrMCP4725EEPROMBusy)
+}
+
+func TestMCP4725DriverPowerDown(t *testing.T) {
+	d, adaptor := initTestMCP4725DriverWithStubbedAdaptor()
+	d.Write(0xABC)
+	adaptor.written = nil
+	gobottest.Assert(t, d.PowerDown(MCP4725PowerDown500K), nil)
+	gobottest.Assert(t, d.PowerDown(MCP4725Normal), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x46, 0xAB, 0xC0, 0x40, 0xAB, 0xC0})
+}
@@ -1,6 +1,8 @@
 package i2c
 
 import (
+	"sync"
+
 	"gobot.io/x/gobot"
 )
 
@@ -30,11 +32,36 @@ const (
 	MMA7660_PD             = 0x0A
 )
 
+// mma7660Registers are the registers shown by DebugDump
+var mma7660Registers = RegisterMap{
+	{Name: "TILT", Address: MMA7660_TILT, Fields: []RegisterField{
+		{Name: "Shake", Shift: 7, Width: 1},
+		{Name: "Alert", Shift: 6, Width: 1},
+		{Name: "Tap", Shift: 5, Width: 1},
+		{Name: "PoLa", Shift: 2, Width: 3, Values: map[uint8]string{0: "unknown", 1: "left", 2: "right", 5: "down", 6: "up"}},
+		{Name: "BaFro", Shift: 0, Width: 2, Values: map[uint8]string{0: "unknown", 1: "front", 2: "back"}},
+	}},
+	{Name: "SRST", Address: MMA7660_SRST},
+	{Name: "SPCNT", Address: MMA7660_SPCNT},
+	{Name: "INTSU", Address: MMA7660_INTSU},
+	{Name: "MODE", Address: MMA7660_MODE, Fields: []RegisterField{
+		{Name: "MODE", Shift: 0, Width: 1, Values: map[uint8]string{MMA7660_STAND_BY: "standby", MMA7660_ACTIVE: "active"}},
+	}},
+	{Name: "SR", Address: MMA7660_SR, Fields: []RegisterField{
+		{Name: "AMSR", Shift: 0, Width: 3, Values: map[uint8]string{0: "120", 1: "64", 2: "32", 3: "16", 4: "8", 5: "4", 6: "2", 7: "1"}},
+	}},
+	{Name: "PDET", Address: MMA7660_PDET},
+	{Name: "PD", Address: MMA7660_PD},
+}
+
 type MMA7660Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 }
 
 // NewMMA7660Driver creates a new driver with specified i2c interface
@@ -50,13 +77,18 @@ func NewMMA7660Driver(a Connector, options ...func(Config)) *MMA7660Driver {
 		name:      gobot.DefaultName("MMA7660"),
 		connector: a,
 		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
 	}
 
+	m.driverConfig = driverConfig{config: &m.Config}
 	for _, option := range options {
 		option(m)
 	}
 
 	// TODO: add commands for API
+	addDebugDumpCommand(m, m)
+
 	return m
 }
 
@@ -71,10 +103,10 @@ func (h *MMA7660Driver) Connection() gobot.Connection { return h.connector.(gobo
 
 // Start initialized the mma7660
 func (h *MMA7660Driver) Start() (err error) {
-	bus := h.GetBusOrDefault(h.connector.GetDefaultBus())
-	address := h.GetAddressOrDefault(mma7660Address)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
 
-	h.connection, err = h.connector.GetConnection(address, bus)
+	h.connection, err = connect(h, h.connector, h.Config, mma7660Address)
 	if err != nil {
 		return err
 	}
@@ -97,6 +129,14 @@ func (h *MMA7660Driver) Start() (err error) {
 // Halt returns true if devices is halted successfully
 func (h *MMA7660Driver) Halt() (err error) { return }
 
+// DebugDump reads the tilt status and the sampling, interrupt, mode and tap detection setup registers, from TILT to PD.
+func (h *MMA7660Driver) DebugDump() (RegisterDump, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return DumpRegisters(h.connection, mma7660Registers)
+}
+
 // Acceleration returns the acceleration of the provided x, y, z
 func (h *MMA7660Driver) Acceleration(x, y, z float64) (ax, ay, az float64) {
 	return x / 21.0, y / 21.0, z / 21.0
@@ -104,6 +144,9 @@ func (h *MMA7660Driver) Acceleration(x, y, z float64) (ax, ay, az float64) {
 
 // XYZ returns the raw x,y and z axis from the mma7660
 func (h *MMA7660Driver) XYZ() (x float64, y float64, z float64, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	buf := []byte{0, 0, 0}
 	bytesRead, err := h.connection.Read(buf)
 	if err != nil {
@@ -137,3 +137,19 @@ func TestMMA7660DriverXYZNotReady(t *testing.T) {
 	_, _, _, err := d.XYZ()
 	gobottest.Assert(t, err, ErrNotReady)
 }
+
+func TestMMA7660DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestMMA7660DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x01
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["MODE"].(map[string]interface{})["MODE"], "active")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
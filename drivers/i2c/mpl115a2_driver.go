@@ -2,6 +2,7 @@ package i2c
 
 import (
 	"gobot.io/x/gobot"
+	"sync"
 
 	"bytes"
 	"encoding/binary"
@@ -29,7 +30,9 @@ type MPL115A2Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
 	gobot.Eventer
 	A0  float32
 	B1  float32
@@ -52,9 +55,11 @@ func NewMPL115A2Driver(a Connector, options ...func(Config)) *MPL115A2Driver {
 		name:      gobot.DefaultName("MPL115A2"),
 		connector: a,
 		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
 		Eventer:   gobot.NewEventer(),
 	}
 
+	m.driverConfig = driverConfig{config: &m.Config}
 	for _, option := range options {
 		option(m)
 	}
@@ -77,6 +82,9 @@ func (h *MPL115A2Driver) Connection() gobot.Connection { return h.connector.(gob
 // Start writes initialization bytes and reads from adaptor
 // using specified interval to accelerometer andtemperature data
 func (h *MPL115A2Driver) Start() (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	if err := h.initialization(); err != nil {
 		return err
 	}
@@ -87,14 +95,21 @@ func (h *MPL115A2Driver) Start() (err error) {
 // Halt returns true if devices is halted successfully
 func (h *MPL115A2Driver) Halt() (err error) { return }
 
-// Pressure fetches the latest data from the MPL115A2, and returns the pressure
+// Pressure fetches the latest data from the MPL115A2, and returns the pressure,
+// in kPa
 func (h *MPL115A2Driver) Pressure() (p float32, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	p, _, err = h.getData()
 	return
 }
 
 // Temperature fetches the latest data from the MPL115A2, and returns the temperature
 func (h *MPL115A2Driver) Temperature() (t float32, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	_, t, err = h.getData()
 	return
 }
@@ -105,10 +120,7 @@ func (h *MPL115A2Driver) initialization() (err error) {
 	var coB2 int16
 	var coC12 int16
 
-	bus := h.GetBusOrDefault(h.connector.GetDefaultBus())
-	address := h.GetAddressOrDefault(mpl115a2Address)
-
-	h.connection, err = h.connector.GetConnection(address, bus)
+	h.connection, err = connect(h, h.connector, h.Config, mpl115a2Address)
 	if err != nil {
 		return err
 	}
@@ -10,6 +10,8 @@ import (
 )
 
 var _ gobot.Driver = (*MPL115A2Driver)(nil)
+var _ gobot.TemperatureSensor = (*MPL115A2Driver)(nil)
+var _ gobot.PressureSensor = (*MPL115A2Driver)(nil)
 
 // --------- HELPERS
 func initTestMPL115A2Driver() (driver *MPL115A2Driver) {
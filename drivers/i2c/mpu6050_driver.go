@@ -3,6 +3,9 @@ package i2c
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -25,6 +28,60 @@ const MPU6050_ACONFIG_AFS_SEL_LENGTH = 2
 const MPU6050_ACCEL_FS_2 = 0x00
 const MPU6050_PWR1_SLEEP_BIT = 6
 const MPU6050_PWR1_ENABLE_BIT = 0
+const MPU6050_RA_SMPLRT_DIV = 0x19
+const MPU6050_RA_CONFIG = 0x1A
+const MPU6050_RA_FIFO_EN = 0x23
+const MPU6050_RA_USER_CTRL = 0x6A
+const MPU6050_RA_BANK_SEL = 0x6D
+const MPU6050_RA_MEM_START_ADDR = 0x6E
+const MPU6050_RA_MEM_R_W = 0x6F
+const MPU6050_RA_DMP_CFG_1 = 0x70
+const MPU6050_RA_FIFO_COUNTH = 0x72
+const MPU6050_RA_FIFO_R_W = 0x74
+const MPU6050_FIFO_EN_ACCEL_GYRO = 0x78
+const MPU6050_USERCTRL_DMP_EN = 0x80
+const MPU6050_USERCTRL_FIFO_EN = 0x40
+const MPU6050_USERCTRL_DMP_RESET = 0x08
+const MPU6050_USERCTRL_FIFO_RESET = 0x04
+
+// The digital low pass filter settings of the MPU6050, named after the
+// bandwidth of the accelerometer
+const (
+	MPU6050DLPF260Hz uint8 = iota
+	MPU6050DLPF184Hz
+	MPU6050DLPF94Hz
+	MPU6050DLPF44Hz
+	MPU6050DLPF21Hz
+	MPU6050DLPF10Hz
+	MPU6050DLPF5Hz
+)
+
+// MPU6050FIFOSize is the size of the FIFO of the MPU6050, in bytes
+const MPU6050FIFOSize = 1024
+
+// mpu6050FIFOSampleSize is the size of a sample of the accelerometer and
+// gyroscope in the FIFO
+const mpu6050FIFOSampleSize = 12
+
+// MPU6050DMPPacketSize is the size of the packets written to the FIFO by the
+// MotionApps 2.0 DMP firmware, which start with the quaternion
+const MPU6050DMPPacketSize = 42
+
+// mpu6050MemChunkSize is the size of the chunks of the DMP firmware written
+// to the memory of the MPU6050, a divisor of the size of its banks
+const mpu6050MemChunkSize = 16
+
+// mpu6050AccelOneG is the reading of the accelerometer for 1g in the range
+// of +/-2g
+const mpu6050AccelOneG = 16384
+
+// ErrMPU6050FIFOOverflow is returned when the FIFO of the MPU6050 is full, its
+// samples are lost and the FIFO is reset
+var ErrMPU6050FIFOOverflow = errors.New("MPU6050 FIFO overflow")
+
+// ErrMPU6050NoData is returned by ReadQuaternion when the DMP has not
+// written a complete packet to the FIFO yet
+var ErrMPU6050NoData = errors.New("MPU6050 DMP packet not available")
 
 type ThreeDData struct {
 	X int16
@@ -32,16 +89,66 @@ type ThreeDData struct {
 	Z int16
 }
 
+// MPU6050Calibration is the offsets subtracted from the readings of an
+// MPU6050, measured by Calibrate. It can be saved and given back with
+// SetCalibration to skip the calibration at the next start.
+type MPU6050Calibration struct {
+	Accelerometer ThreeDData
+	Gyroscope     ThreeDData
+}
+
+// MPU6050Sample is a sample of the accelerometer and gyroscope read from the
+// FIFO of an MPU6050
+type MPU6050Sample struct {
+	Accelerometer ThreeDData
+	Gyroscope     ThreeDData
+}
+
+// MPU6050Quaternion is the orientation computed by the DMP of an MPU6050
+type MPU6050Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// mpu6050Registers are the registers shown by DebugDump
+var mpu6050Registers = RegisterMap{
+	{Name: "WHO_AM_I", Address: 0x75},
+	{Name: "PWR_MGMT_1", Address: MPU6050_RA_PWR_MGMT_1, Fields: []RegisterField{
+		{Name: "DEVICE_RESET", Shift: 7, Width: 1},
+		{Name: "SLEEP", Shift: MPU6050_PWR1_SLEEP_BIT, Width: 1},
+		{Name: "CYCLE", Shift: 5, Width: 1},
+		{Name: "TEMP_DIS", Shift: 3, Width: 1},
+		{Name: "CLKSEL", Shift: 0, Width: MPU6050_PWR1_CLKSEL_LENGTH},
+	}},
+	{Name: "SMPLRT_DIV", Address: 0x19},
+	{Name: "CONFIG", Address: 0x1A, Fields: []RegisterField{
+		{Name: "EXT_SYNC_SET", Shift: 3, Width: 3},
+		{Name: "DLPF_CFG", Shift: 0, Width: 3},
+	}},
+	{Name: "GYRO_CONFIG", Address: MPU6050_RA_GYRO_CONFIG, Fields: []RegisterField{
+		{Name: "FS_SEL", Shift: 3, Width: MPU6050_GCONFIG_FS_SEL_LENGTH, Values: map[uint8]string{0: "250dps", 1: "500dps", 2: "1000dps", 3: "2000dps"}},
+	}},
+	{Name: "ACCEL_CONFIG", Address: MPU6050_RA_ACCEL_CONFIG, Fields: []RegisterField{
+		{Name: "AFS_SEL", Shift: 3, Width: MPU6050_ACONFIG_AFS_SEL_LENGTH, Values: map[uint8]string{0: "2g", 1: "4g", 2: "8g", 3: "16g"}},
+	}},
+}
+
 // MPU6050Driver is a new Gobot Driver for an MPU6050 I2C Accelerometer/Gyroscope.
+// The accelerometer and gyroscope of the MPU6500 and MPU9250 are compatible.
 type MPU6050Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 	interval      time.Duration
 	Accelerometer ThreeDData
 	Gyroscope     ThreeDData
 	Temperature   int16
+	dlpf          uint8
+	sampleRate    int
+	calibration   MPU6050Calibration
 	gobot.Eventer
 }
 
@@ -53,20 +160,38 @@ type MPU6050Driver struct {
 // Optional params:
 //		i2c.WithBus(int):	bus to use with this driver
 //		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithMPU6050DLPF(uint8):	digital low pass filter setting
+//		i2c.WithMPU6050SampleRate(int):	sample rate in Hertz
+//		i2c.WithMPU6050Calibration(MPU6050Calibration):	offsets of a previous calibration
 //
 func NewMPU6050Driver(a Connector, options ...func(Config)) *MPU6050Driver {
 	m := &MPU6050Driver{
 		name:      gobot.DefaultName("MPU6050"),
 		connector: a,
 		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
 		Eventer:   gobot.NewEventer(),
 	}
 
+	m.driverConfig = driverConfig{config: &m.Config}
 	for _, option := range options {
 		option(m)
 	}
 
-	// TODO: add commands to API
+	m.AddCommand("Calibrate", func(params map[string]interface{}) interface{} {
+		samples := 100
+		if val, ok := params["samples"]; ok {
+			samples = int(val.(float64))
+		}
+		if err := m.Calibrate(samples); err != nil {
+			return map[string]interface{}{"err": err}
+		}
+		return m.Calibration()
+	})
+
+	addDebugDumpCommand(m, m)
+
 	return m
 }
 
@@ -81,6 +206,9 @@ func (h *MPU6050Driver) Connection() gobot.Connection { return h.connector.(gobo
 
 // Start writes initialization bytes to sensor
 func (h *MPU6050Driver) Start() (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	if err := h.initialize(); err != nil {
 		return err
 	}
@@ -91,31 +219,288 @@ func (h *MPU6050Driver) Start() (err error) {
 // Halt returns true if devices is halted successfully
 func (h *MPU6050Driver) Halt() (err error) { return }
 
-// GetData fetches the latest data from the MPU6050
+// DebugDump reads the identity, power management, sample rate and filter, gyroscope and accelerometer configuration registers.
+func (h *MPU6050Driver) DebugDump() (RegisterDump, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return DumpRegisters(h.connection, mpu6050Registers)
+}
+
+// GetData fetches the latest data from the MPU6050, the offsets of the
+// calibration subtracted
 func (h *MPU6050Driver) GetData() (err error) {
-	if _, err = h.connection.Write([]byte{MPU6050_RA_ACCEL_XOUT_H}); err != nil {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	accel, temp, gyro, err := h.read()
+	if err != nil {
 		return
 	}
+	h.Accelerometer = accel.sub(h.calibration.Accelerometer)
+	h.Temperature = temp
+	h.Gyroscope = gyro.sub(h.calibration.Gyroscope)
+	h.convertToCelsius()
+	return
+}
 
+// read reads the raw accelerometer, temperature and gyroscope registers
+func (h *MPU6050Driver) read() (accel ThreeDData, temp int16, gyro ThreeDData, err error) {
 	data := make([]byte, 14)
-	_, err = h.connection.Read(data)
-	if err != nil {
+	if err = h.readRegisters(MPU6050_RA_ACCEL_XOUT_H, data); err != nil {
 		return
 	}
 
 	buf := bytes.NewBuffer(data)
-	binary.Read(buf, binary.BigEndian, &h.Accelerometer)
-	binary.Read(buf, binary.BigEndian, &h.Temperature)
-	binary.Read(buf, binary.BigEndian, &h.Gyroscope)
-	h.convertToCelsius()
+	binary.Read(buf, binary.BigEndian, &accel)
+	binary.Read(buf, binary.BigEndian, &temp)
+	binary.Read(buf, binary.BigEndian, &gyro)
 	return
 }
 
-func (h *MPU6050Driver) initialize() (err error) {
-	bus := h.GetBusOrDefault(h.connector.GetDefaultBus())
-	address := h.GetAddressOrDefault(mpu6050Address)
+// SetDLPF sets the digital low pass filter of the accelerometer and
+// gyroscope, one of the MPU6050DLPF* values. Higher settings filter more
+// noise at the cost of a longer delay.
+func (h *MPU6050Driver) SetDLPF(dlpf uint8) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if dlpf > MPU6050DLPF5Hz {
+		return fmt.Errorf("MPU6050 DLPF setting %d out of range", dlpf)
+	}
+	h.dlpf = dlpf
+	return h.writeRegisters(MPU6050_RA_CONFIG, dlpf)
+}
+
+// SetSampleRate sets the rate at which the sensors are sampled, in Hertz,
+// which is also the rate at which samples are written to the FIFO. The
+// sample rate is derived from the 8kHz gyroscope output rate without DLPF,
+// or 1kHz with it, so the rate set may be rounded.
+func (h *MPU6050Driver) SetSampleRate(hz int) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.setSampleRate(hz)
+}
+
+func (h *MPU6050Driver) setSampleRate(hz int) error {
+	output := 1000
+	if h.dlpf == MPU6050DLPF260Hz {
+		output = 8000
+	}
+	if hz <= 0 || output/hz-1 > 0xFF {
+		return fmt.Errorf("MPU6050 sample rate %dHz out of range", hz)
+	}
+	divider := output/hz - 1
+	if divider < 0 {
+		divider = 0
+	}
+	h.sampleRate = hz
+	return h.writeRegisters(MPU6050_RA_SMPLRT_DIV, uint8(divider))
+}
+
+// Calibrate measures the offsets of the accelerometer and gyroscope,
+// averaging samples readings while the MPU6050 lies still, flat, with its Z
+// axis up. The offsets are subtracted from the following readings.
+func (h *MPU6050Driver) Calibrate(samples int) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if samples <= 0 {
+		return fmt.Errorf("MPU6050 calibration needs samples, got %d", samples)
+	}
+	var accel, gyro [3]int64
+	for i := 0; i < samples; i++ {
+		a, _, g, err := h.read()
+		if err != nil {
+			return err
+		}
+		accel[0], accel[1], accel[2] = accel[0]+int64(a.X), accel[1]+int64(a.Y), accel[2]+int64(a.Z)
+		gyro[0], gyro[1], gyro[2] = gyro[0]+int64(g.X), gyro[1]+int64(g.Y), gyro[2]+int64(g.Z)
+		if h.sampleRate > 0 {
+			time.Sleep(time.Second / time.Duration(h.sampleRate))
+		}
+	}
+
+	n := int64(samples)
+	h.calibration = MPU6050Calibration{
+		Accelerometer: ThreeDData{X: int16(accel[0] / n), Y: int16(accel[1] / n), Z: int16(accel[2]/n - mpu6050AccelOneG)},
+		Gyroscope:     ThreeDData{X: int16(gyro[0] / n), Y: int16(gyro[1] / n), Z: int16(gyro[2] / n)},
+	}
+	return nil
+}
+
+// Calibration returns the offsets subtracted from the readings
+func (h *MPU6050Driver) Calibration() MPU6050Calibration {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.calibration
+}
+
+// SetCalibration sets the offsets subtracted from the readings, as returned
+// by Calibration after a previous calibration
+func (h *MPU6050Driver) SetCalibration(c MPU6050Calibration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.calibration = c
+}
+
+// EnableFIFO resets the FIFO and starts writing the samples of the
+// accelerometer and gyroscope to it at the sample rate, to be read in bursts
+// with ReadFIFO.
+func (h *MPU6050Driver) EnableFIFO() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
 
-	h.connection, err = h.connector.GetConnection(address, bus)
+	if err := h.writeRegisters(MPU6050_RA_USER_CTRL, MPU6050_USERCTRL_FIFO_RESET); err != nil {
+		return err
+	}
+	if err := h.writeRegisters(MPU6050_RA_FIFO_EN, MPU6050_FIFO_EN_ACCEL_GYRO); err != nil {
+		return err
+	}
+	return h.writeRegisters(MPU6050_RA_USER_CTRL, MPU6050_USERCTRL_FIFO_EN)
+}
+
+// ReadFIFO reads the samples written to the FIFO since the last read, the
+// offsets of the calibration subtracted. When the FIFO is full, it is reset
+// and ErrMPU6050FIFOOverflow is returned: the sample rate is too high for the
+// rate of the reads.
+func (h *MPU6050Driver) ReadFIFO() ([]MPU6050Sample, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	data, err := h.readFIFO(mpu6050FIFOSampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]MPU6050Sample, 0, len(data)/mpu6050FIFOSampleSize)
+	buf := bytes.NewBuffer(data)
+	for buf.Len() > 0 {
+		var sample MPU6050Sample
+		binary.Read(buf, binary.BigEndian, &sample.Accelerometer)
+		binary.Read(buf, binary.BigEndian, &sample.Gyroscope)
+		sample.Accelerometer = sample.Accelerometer.sub(h.calibration.Accelerometer)
+		sample.Gyroscope = sample.Gyroscope.sub(h.calibration.Gyroscope)
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// readFIFO reads the complete packets of size bytes available in the FIFO
+func (h *MPU6050Driver) readFIFO(size int) ([]byte, error) {
+	count := make([]byte, 2)
+	if err := h.readRegisters(MPU6050_RA_FIFO_COUNTH, count); err != nil {
+		return nil, err
+	}
+	n := int(binary.BigEndian.Uint16(count))
+	if n >= MPU6050FIFOSize {
+		if err := h.writeRegisters(MPU6050_RA_USER_CTRL, MPU6050_USERCTRL_FIFO_RESET|h.userCtrl()); err != nil {
+			return nil, err
+		}
+		return nil, ErrMPU6050FIFOOverflow
+	}
+
+	data := make([]byte, n-n%size)
+	if len(data) == 0 {
+		return data, nil
+	}
+	if err := h.readRegisters(MPU6050_RA_FIFO_R_W, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// EnableDMP uploads the firmware of the Digital Motion Processor, such as
+// the MotionApps 2.0 image of InvenSense which is not distributed with
+// Gobot, and starts it at startAddress, 0x0400 for MotionApps 2.0. The DMP
+// then writes the orientation of the sensor to the FIFO, read with
+// ReadQuaternion. The DMP is not available on all the compatible sensors.
+func (h *MPU6050Driver) EnableDMP(firmware []byte, startAddress uint16) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for address := 0; address < len(firmware); address += mpu6050MemChunkSize {
+		end := address + mpu6050MemChunkSize
+		if end > len(firmware) {
+			end = len(firmware)
+		}
+		if err := h.writeRegisters(MPU6050_RA_BANK_SEL, uint8(address>>8)); err != nil {
+			return err
+		}
+		if err := h.writeRegisters(MPU6050_RA_MEM_START_ADDR, uint8(address)); err != nil {
+			return err
+		}
+		if err := h.writeRegisters(MPU6050_RA_MEM_R_W, firmware[address:end]...); err != nil {
+			return err
+		}
+	}
+
+	if err := h.writeRegisters(MPU6050_RA_DMP_CFG_1, uint8(startAddress>>8), uint8(startAddress)); err != nil {
+		return err
+	}
+	if err := h.writeRegisters(MPU6050_RA_USER_CTRL, MPU6050_USERCTRL_DMP_RESET|MPU6050_USERCTRL_FIFO_RESET); err != nil {
+		return err
+	}
+	return h.writeRegisters(MPU6050_RA_USER_CTRL, MPU6050_USERCTRL_DMP_EN|MPU6050_USERCTRL_FIFO_EN)
+}
+
+// ReadQuaternion reads the latest orientation written to the FIFO by the
+// DMP, skipping the older ones. It returns ErrMPU6050NoData when no packet
+// is available yet.
+func (h *MPU6050Driver) ReadQuaternion() (MPU6050Quaternion, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	data, err := h.readFIFO(MPU6050DMPPacketSize)
+	if err != nil {
+		return MPU6050Quaternion{}, err
+	}
+	if len(data) == 0 {
+		return MPU6050Quaternion{}, ErrMPU6050NoData
+	}
+
+	packet := data[len(data)-MPU6050DMPPacketSize:]
+	var q [4]int32
+	binary.Read(bytes.NewBuffer(packet), binary.BigEndian, &q)
+	const scale = 1 << 30
+	return MPU6050Quaternion{
+		W: float64(q[0]) / scale,
+		X: float64(q[1]) / scale,
+		Y: float64(q[2]) / scale,
+		Z: float64(q[3]) / scale,
+	}, nil
+}
+
+// userCtrl returns the USER_CTRL bits enabling the FIFO and the DMP
+// according to the last write
+func (h *MPU6050Driver) userCtrl() uint8 {
+	data := []byte{0}
+	if err := h.readRegisters(MPU6050_RA_USER_CTRL, data); err != nil {
+		return 0
+	}
+	return data[0] & (MPU6050_USERCTRL_DMP_EN | MPU6050_USERCTRL_FIFO_EN)
+}
+
+// readRegisters reads the registers starting at reg into data
+func (h *MPU6050Driver) readRegisters(reg uint8, data []byte) error {
+	if _, err := h.connection.Write([]byte{reg}); err != nil {
+		return err
+	}
+	_, err := h.connection.Read(data)
+	return err
+}
+
+// writeRegisters writes values to the registers starting at reg
+func (h *MPU6050Driver) writeRegisters(reg uint8, values ...uint8) error {
+	_, err := h.connection.Write(append([]byte{reg}, values...))
+	return err
+}
+
+func (h *MPU6050Driver) initialize() (err error) {
+	h.connection, err = connect(h, h.connector, h.Config, mpu6050Address)
 	if err != nil {
 		return err
 	}
@@ -151,6 +536,12 @@ func (h *MPU6050Driver) initialize() (err error) {
 		return
 	}
 
+	if err = h.writeRegisters(MPU6050_RA_CONFIG, h.dlpf); err != nil {
+		return
+	}
+	if h.sampleRate > 0 {
+		return h.setSampleRate(h.sampleRate)
+	}
 	return nil
 }
 
@@ -162,3 +553,38 @@ func (h *MPU6050Driver) initialize() (err error) {
 func (h *MPU6050Driver) convertToCelsius() {
 	h.Temperature = (h.Temperature + 12412) / 340
 }
+
+// sub returns the difference of d and offset
+func (d ThreeDData) sub(offset ThreeDData) ThreeDData {
+	return ThreeDData{X: d.X - offset.X, Y: d.Y - offset.Y, Z: d.Z - offset.Z}
+}
+
+// WithMPU6050DLPF option sets the digital low pass filter of the
+// MPU6050Driver, one of the MPU6050DLPF* values
+func WithMPU6050DLPF(val uint8) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*MPU6050Driver); ok {
+			d.dlpf = val
+		}
+	}
+}
+
+// WithMPU6050SampleRate option sets the sample rate of the MPU6050Driver, in
+// Hertz
+func WithMPU6050SampleRate(hz int) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*MPU6050Driver); ok {
+			d.sampleRate = hz
+		}
+	}
+}
+
+// WithMPU6050Calibration option sets the offsets subtracted from the
+// readings of the MPU6050Driver, saved after a previous calibration
+func WithMPU6050Calibration(val MPU6050Calibration) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*MPU6050Driver); ok {
+			d.calibration = val
+		}
+	}
+}
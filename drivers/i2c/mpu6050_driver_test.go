@@ -1,6 +1,7 @@
 package i2c
 
 import (
+	"encoding/binary"
 	"errors"
 	"strings"
 	"testing"
@@ -23,6 +24,45 @@ func initTestMPU6050DriverWithStubbedAdaptor() (*MPU6050Driver, *i2cTestAdaptor)
 	return NewMPU6050Driver(adaptor), adaptor
 }
 
+// mpu6050Fake simulates the registers of an MPU6050, its FIFO and the
+// memory of its DMP
+type mpu6050Fake struct {
+	regs   [256]byte
+	reg    uint8
+	fifo   []byte
+	memory []byte
+}
+
+func initTestMPU6050DriverWithFake(options ...func(Config)) (*MPU6050Driver, *mpu6050Fake) {
+	adaptor := newI2cTestAdaptor()
+	f := &mpu6050Fake{}
+	adaptor.i2cWriteImpl = func(b []byte) (int, error) {
+		f.reg = b[0]
+		for i, v := range b[1:] {
+			switch f.reg {
+			case MPU6050_RA_MEM_R_W:
+				f.memory = append(f.memory, v)
+			default:
+				f.regs[int(f.reg)+i] = v
+			}
+		}
+		return len(b), nil
+	}
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		if f.reg == MPU6050_RA_FIFO_R_W {
+			n := copy(b, f.fifo)
+			f.fifo = f.fifo[n:]
+			return n, nil
+		}
+		if f.reg == MPU6050_RA_FIFO_COUNTH {
+			binary.BigEndian.PutUint16(b, uint16(len(f.fifo)))
+			return 2, nil
+		}
+		return copy(b, f.regs[f.reg:]), nil
+	}
+	return NewMPU6050Driver(adaptor, options...), f
+}
+
 // --------- TESTS
 
 func TestNewMPU6050Driver(t *testing.T) {
@@ -111,3 +151,119 @@ func TestMPU6050DriverSetName(t *testing.T) {
 	mpu.SetName("TESTME")
 	gobottest.Assert(t, mpu.Name(), "TESTME")
 }
+
+func TestMPU6050DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestMPU6050DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x18
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["GYRO_CONFIG"].(map[string]interface{})["FS_SEL"], "2000dps")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
+
+func TestMPU6050DriverDLPFAndSampleRate(t *testing.T) {
+	d, f := initTestMPU6050DriverWithFake(WithMPU6050DLPF(MPU6050DLPF44Hz), WithMPU6050SampleRate(100))
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, f.regs[MPU6050_RA_CONFIG], MPU6050DLPF44Hz)
+	gobottest.Assert(t, f.regs[MPU6050_RA_SMPLRT_DIV], uint8(9))
+
+	// without DLPF the gyroscope output rate is 8kHz
+	gobottest.Assert(t, d.SetDLPF(MPU6050DLPF260Hz), nil)
+	gobottest.Assert(t, d.SetSampleRate(1000), nil)
+	gobottest.Assert(t, f.regs[MPU6050_RA_SMPLRT_DIV], uint8(7))
+
+	gobottest.Refute(t, d.SetDLPF(7), nil)
+	gobottest.Refute(t, d.SetSampleRate(0), nil)
+	gobottest.Refute(t, d.SetSampleRate(10), nil)
+}
+
+func TestMPU6050DriverCalibrate(t *testing.T) {
+	d, f := initTestMPU6050DriverWithFake()
+	d.Start()
+	binary.BigEndian.PutUint16(f.regs[MPU6050_RA_ACCEL_XOUT_H:], uint16(100))
+	binary.BigEndian.PutUint16(f.regs[MPU6050_RA_ACCEL_XOUT_H+4:], uint16(16384+50))
+	binary.BigEndian.PutUint16(f.regs[MPU6050_RA_ACCEL_XOUT_H+8:], uint16(0xFFFF))
+
+	gobottest.Assert(t, d.Calibrate(10), nil)
+	c := d.Calibration()
+	gobottest.Assert(t, c, MPU6050Calibration{
+		Accelerometer: ThreeDData{X: 100, Z: 50},
+		Gyroscope:     ThreeDData{X: -1},
+	})
+	gobottest.Assert(t, d.GetData(), nil)
+	gobottest.Assert(t, d.Accelerometer, ThreeDData{Z: 16384})
+	gobottest.Assert(t, d.Gyroscope, ThreeDData{})
+	gobottest.Refute(t, d.Calibrate(0), nil)
+
+	// a saved calibration is restored
+	d2, _ := initTestMPU6050DriverWithFake(WithMPU6050Calibration(c))
+	gobottest.Assert(t, d2.Calibration(), c)
+	d2.SetCalibration(MPU6050Calibration{})
+	gobottest.Assert(t, d2.Calibration(), MPU6050Calibration{})
+
+	result := d.Command("Calibrate")(map[string]interface{}{"samples": 5.0})
+	gobottest.Assert(t, result, c)
+}
+
+func TestMPU6050DriverFIFO(t *testing.T) {
+	d, f := initTestMPU6050DriverWithFake()
+	d.Start()
+	gobottest.Assert(t, d.EnableFIFO(), nil)
+	gobottest.Assert(t, f.regs[MPU6050_RA_FIFO_EN], uint8(MPU6050_FIFO_EN_ACCEL_GYRO))
+	gobottest.Assert(t, f.regs[MPU6050_RA_USER_CTRL], uint8(MPU6050_USERCTRL_FIFO_EN))
+
+	// two samples and an incomplete one
+	f.fifo = []byte{
+		0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 0, 6,
+		0xFF, 0xFF, 0, 0, 0x40, 0, 0, 0, 0, 0, 0, 0,
+		0, 1, 0, 2,
+	}
+	samples, err := d.ReadFIFO()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, samples, []MPU6050Sample{
+		{Accelerometer: ThreeDData{X: 1, Y: 2, Z: 3}, Gyroscope: ThreeDData{X: 4, Y: 5, Z: 6}},
+		{Accelerometer: ThreeDData{X: -1, Z: 16384}},
+	})
+	gobottest.Assert(t, len(f.fifo), 4)
+
+	f.fifo = make([]byte, MPU6050FIFOSize)
+	_, err = d.ReadFIFO()
+	gobottest.Assert(t, err, ErrMPU6050FIFOOverflow)
+	gobottest.Assert(t, f.regs[MPU6050_RA_USER_CTRL], uint8(MPU6050_USERCTRL_FIFO_RESET|MPU6050_USERCTRL_FIFO_EN))
+}
+
+func TestMPU6050DriverDMP(t *testing.T) {
+	d, f := initTestMPU6050DriverWithFake()
+	d.Start()
+	firmware := make([]byte, 300)
+	for i := range firmware {
+		firmware[i] = byte(i)
+	}
+	gobottest.Assert(t, d.EnableDMP(firmware, 0x0400), nil)
+	gobottest.Assert(t, f.memory, firmware)
+	gobottest.Assert(t, f.regs[MPU6050_RA_BANK_SEL], uint8(1))
+	gobottest.Assert(t, f.regs[MPU6050_RA_MEM_START_ADDR], uint8(0x20))
+	gobottest.Assert(t, f.regs[MPU6050_RA_DMP_CFG_1:MPU6050_RA_DMP_CFG_1+2], []byte{0x04, 0x00})
+	gobottest.Assert(t, f.regs[MPU6050_RA_USER_CTRL], uint8(MPU6050_USERCTRL_DMP_EN|MPU6050_USERCTRL_FIFO_EN))
+
+	_, err := d.ReadQuaternion()
+	gobottest.Assert(t, err, ErrMPU6050NoData)
+
+	// the latest of two packets is read
+	older := make([]byte, MPU6050DMPPacketSize)
+	latest := make([]byte, MPU6050DMPPacketSize)
+	binary.BigEndian.PutUint32(latest[0:], 1<<29)
+	binary.BigEndian.PutUint32(latest[4:], 0xE0000000)
+	binary.BigEndian.PutUint32(latest[12:], 1<<30)
+	f.fifo = append(older, latest...)
+	q, err := d.ReadQuaternion()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, q, MPU6050Quaternion{W: 0.5, X: -0.5, Y: 0, Z: 1})
+}
@@ -2,6 +2,7 @@ package i2c
 
 import (
 	"strconv"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -25,13 +26,34 @@ const (
 	PCA9685_ALLLED_OFF_H = 0xFD
 )
 
+// pca9685Registers are the registers shown by DebugDump
+var pca9685Registers = RegisterMap{
+	{Name: "MODE1", Address: PCA9685_MODE1, Fields: []RegisterField{
+		{Name: "RESTART", Shift: 7, Width: 1},
+		{Name: "EXTCLK", Shift: 6, Width: 1},
+		{Name: "AI", Shift: 5, Width: 1},
+		{Name: "SLEEP", Shift: 4, Width: 1},
+		{Name: "ALLCALL", Shift: 0, Width: 1},
+	}},
+	{Name: "MODE2", Address: 0x01, Fields: []RegisterField{
+		{Name: "INVRT", Shift: 4, Width: 1},
+		{Name: "OCH", Shift: 3, Width: 1},
+		{Name: "OUTDRV", Shift: 2, Width: 1, Values: map[uint8]string{0: "open-drain", 1: "totem-pole"}},
+		{Name: "OUTNE", Shift: 0, Width: 2},
+	}},
+	{Name: "PRESCALE", Address: PCA9685_PRESCALE},
+}
+
 // PCA9685Driver is a Gobot Driver for the PCA9685 16-channel 12-bit
 // PWM/Servo controller.
 type PCA9685Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 }
 
 // NewPCA9685Driver creates a new driver with specified i2c interface
@@ -47,13 +69,18 @@ func NewPCA9685Driver(a Connector, options ...func(Config)) *PCA9685Driver {
 		name:      gobot.DefaultName("PCA9685"),
 		connector: a,
 		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
 	}
 
+	p.driverConfig = driverConfig{config: &p.Config}
 	for _, option := range options {
 		option(p)
 	}
 
 	// TODO: add commands for API
+	addDebugDumpCommand(p, p)
+
 	return p
 }
 
@@ -68,10 +95,10 @@ func (p *PCA9685Driver) Connection() gobot.Connection { return p.connector.(gobo
 
 // Start initializes the pca9685
 func (p *PCA9685Driver) Start() (err error) {
-	bus := p.GetBusOrDefault(p.connector.GetDefaultBus())
-	address := p.GetAddressOrDefault(pca9685Address)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
-	p.connection, err = p.connector.GetConnection(address, bus)
+	p.connection, err = connect(p, p.connector, p.Config, pca9685Address)
 	if err != nil {
 		return err
 	}
@@ -93,8 +120,19 @@ func (p *PCA9685Driver) Halt() (err error) {
 	return
 }
 
+// DebugDump reads MODE1, MODE2 and PRESCALE. The LED channel registers are not included.
+func (p *PCA9685Driver) DebugDump() (RegisterDump, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return DumpRegisters(p.connection, pca9685Registers)
+}
+
 // SetPWM sets a specific channel to a pwm value from 0-4096
 func (p *PCA9685Driver) SetPWM(channel int, on uint16, off uint16) (err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
 	if _, err := p.connection.Write([]byte{byte(PCA9685_LED0_ON_L + 4*channel), byte(on), byte(on >> 8), byte(off), byte(off >> 8)}); err != nil {
 		return err
 	}
@@ -104,6 +142,9 @@ func (p *PCA9685Driver) SetPWM(channel int, on uint16, off uint16) (err error) {
 
 // SetPWMFreq sets the PWM frequency in Hz
 func (p *PCA9685Driver) SetPWMFreq(freq float32) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
 	freq *= 0.9
 
 	var prescalevel float32 = 25000000
@@ -129,3 +129,19 @@ func TestPCA9685DriverSetName(t *testing.T) {
 	pca.SetName("TESTME")
 	gobottest.Assert(t, pca.Name(), "TESTME")
 }
+
+func TestPCA9685DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestPCA9685DriverWithStubbedAdaptor()
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x10
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["MODE1"].(map[string]interface{})["SLEEP"], uint8(1))
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
@@ -0,0 +1,170 @@
+package i2c
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// PCF8591DefaultAddress is the default I2C address of the PCF8591
+const PCF8591DefaultAddress = 0x48
+
+// PCF8591 control byte
+const (
+	pcf8591OutputEnable = 0x40
+	pcf8591Channels     = 4
+)
+
+// PCF8591Driver is a driver for the PCF8591 combined 8 bits ADC and DAC,
+// which has 4 single ended analog inputs and one analog output, all ranging
+// from 0 to its reference voltage.
+type PCF8591Driver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+
+	output bool
+	value  byte
+}
+
+// NewPCF8591Driver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//
+func NewPCF8591Driver(c Connector, options ...func(Config)) *PCF8591Driver {
+	d := &PCF8591Driver{
+		name:      gobot.DefaultName("PCF8591"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		val, err := d.Read(int(params["channel"].(float64)))
+		return map[string]interface{}{"val": val, "err": err}
+	})
+	d.AddCommand("Write", func(params map[string]interface{}) interface{} {
+		return d.Write(byte(params["value"].(float64)))
+	})
+
+	return d
+}
+
+// Name returns the name of the device.
+func (d *PCF8591Driver) Name() string { return d.name }
+
+// SetName sets the name of the device.
+func (d *PCF8591Driver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device.
+func (d *PCF8591Driver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Start initializes the converter.
+func (d *PCF8591Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.connection, err = connect(d, d.connector, d.Config, PCF8591DefaultAddress)
+	return err
+}
+
+// Halt turns the analog output off.
+func (d *PCF8591Driver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.connection == nil || !d.output {
+		return nil
+	}
+	d.output = false
+	_, err = d.connection.Write([]byte{0x00})
+	return err
+}
+
+// Read returns the value of the input channel, from 0 to 3, from 0 to 255.
+func (d *PCF8591Driver) Read(channel int) (byte, error) {
+	if channel < 0 || channel >= pcf8591Channels {
+		return 0, fmt.Errorf("Invalid PCF8591 channel %d, must be between 0 and 3", channel)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// the output enable flag is kept, so that the output stays on
+	if _, err := d.connection.Write([]byte{d.control() | byte(channel)}); err != nil {
+		return 0, err
+	}
+	// the first byte read is the result of the previous conversion
+	b := make([]byte, 2)
+	n, err := d.connection.Read(b)
+	if err != nil {
+		return 0, err
+	}
+	if n != len(b) {
+		return 0, ErrNotEnoughBytes
+	}
+	return b[1], nil
+}
+
+// AnalogRead returns the value of the input pin, "0" to "3", from 0 to 255.
+func (d *PCF8591Driver) AnalogRead(pin string) (int, error) {
+	channel, err := strconv.Atoi(pin)
+	if err != nil {
+		return 0, err
+	}
+	v, err := d.Read(channel)
+	return int(v), err
+}
+
+// Write turns the analog output on at value, from 0 to 255.
+func (d *PCF8591Driver) Write(value byte) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, err := d.connection.Write([]byte{pcf8591OutputEnable, value}); err != nil {
+		return err
+	}
+	d.output, d.value = true, value
+	return nil
+}
+
+// AnalogWrite turns the analog output on at value, from 0 to 255. The
+// converter has a single output, the pin is ignored.
+func (d *PCF8591Driver) AnalogWrite(pin string, value int) error {
+	if value < 0 {
+		value = 0
+	} else if value > 0xFF {
+		value = 0xFF
+	}
+	return d.Write(byte(value))
+}
+
+// Value returns the last value written to the analog output.
+func (d *PCF8591Driver) Value() byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.value
+}
+
+func (d *PCF8591Driver) control() byte {
+	if d.output {
+		return pcf8591OutputEnable
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*PCF8591Driver)(nil)
+var _ aio.AnalogReader = (*PCF8591Driver)(nil)
+var _ aio.AnalogWriter = (*PCF8591Driver)(nil)
+
+// initTestPCF8591DriverWithStubbedAdaptor returns a PCF8591Driver whose
+// input channels read 10 times their number plus 5
+func initTestPCF8591DriverWithStubbedAdaptor() (*PCF8591Driver, *i2cTestAdaptor) {
+	adaptor := newI2cTestAdaptor()
+	var control byte
+	adaptor.i2cWriteImpl = func(b []byte) (int, error) {
+		control = b[0]
+		return len(b), nil
+	}
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x80, 10*(control&0x03) + 5}), nil
+	}
+	d := NewPCF8591Driver(adaptor)
+	d.Start()
+	return d, adaptor
+}
+
+func TestPCF8591Driver(t *testing.T) {
+	d, _ := initTestPCF8591DriverWithStubbedAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "PCF8591"), true)
+	d.SetName("adc")
+	gobottest.Assert(t, d.Name(), "adc")
+	gobottest.Refute(t, d.Connection(), nil)
+}
+
+func TestPCF8591DriverStartConnectError(t *testing.T) {
+	adaptor := newI2cTestAdaptor()
+	adaptor.Testi2cConnectErr(true)
+	gobottest.Assert(t, NewPCF8591Driver(adaptor).Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestPCF8591DriverRead(t *testing.T) {
+	d, adaptor := initTestPCF8591DriverWithStubbedAdaptor()
+	v, err := d.Read(2)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, v, byte(25))
+	gobottest.Assert(t, adaptor.written, []byte{0x02})
+
+	val, err := d.AnalogRead("3")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 35)
+
+	_, err = d.Read(4)
+	gobottest.Assert(t, err, errors.New("Invalid PCF8591 channel 4, must be between 0 and 3"))
+	_, err = d.AnalogRead("A0")
+	gobottest.Refute(t, err, nil)
+
+	result := d.Command("Read")(map[string]interface{}{"channel": 1.0}).(map[string]interface{})
+	gobottest.Assert(t, result["val"], byte(15))
+
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return 1, nil
+	})
+	_, err = d.Read(0)
+	gobottest.Assert(t, err, ErrNotEnoughBytes)
+}
+
+func TestPCF8591DriverWrite(t *testing.T) {
+	d, adaptor := initTestPCF8591DriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Write(200), nil)
+	gobottest.Assert(t, d.Value(), byte(200))
+	gobottest.Assert(t, d.AnalogWrite("0", 300), nil)
+	gobottest.Assert(t, d.Value(), byte(255))
+	// the output stays on while reading
+	d.Read(1)
+	gobottest.Assert(t, adaptor.written, []byte{0x40, 200, 0x40, 255, 0x41})
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.Halt(), nil)
+	d.Read(1)
+	gobottest.Assert(t, adaptor.written, []byte{0x00, 0x01})
+	gobottest.Assert(t, d.Halt(), nil)
+}
@@ -0,0 +1,125 @@
+package i2c
+
+import (
+	"bytes"
+	"fmt"
+
+	"gobot.io/x/gobot"
+)
+
+// RegisterField is a range of bits of a Register holding a setting or a
+// status of the device.
+type RegisterField struct {
+	Name string
+	// Shift is the position of the lowest bit of the field
+	Shift uint8
+	// Width is the number of bits of the field
+	Width uint8
+	// Values names the values of the field, for example the modes of a device.
+	// The values without name are shown as numbers.
+	Values map[uint8]string
+}
+
+// value returns the value of the field in the register value reg, named when
+// known
+func (f RegisterField) value(reg uint8) interface{} {
+	v := (reg >> f.Shift) & uint8(1<<f.Width-1)
+	if name, ok := f.Values[v]; ok {
+		return name
+	}
+	return v
+}
+
+// Register describes an 8-bit register of a device.
+type Register struct {
+	Name    string
+	Address uint8
+	Fields  []RegisterField
+}
+
+// RegisterMap describes the registers of a device worth showing when
+// debugging it, in the order they are shown.
+type RegisterMap []Register
+
+// RegisterValue is the value of a Register read by DumpRegisters, along with
+// the values of its fields.
+type RegisterValue struct {
+	Register
+	Value uint8
+}
+
+// RegisterDump is the result of DumpRegisters.
+type RegisterDump []RegisterValue
+
+// DumpRegisters reads the registers of m from c. The connection of a driver
+// not started being nil, it returns ErrNotConnected.
+func DumpRegisters(c Connection, m RegisterMap) (RegisterDump, error) {
+	if c == nil {
+		return nil, ErrNotConnected
+	}
+	dump := RegisterDump{}
+	for _, reg := range m {
+		val, err := c.ReadByteData(reg.Address)
+		if err != nil {
+			return nil, ErrRegisterRead{Reg: reg.Address, Name: reg.Name, Err: err}
+		}
+		dump = append(dump, RegisterValue{Register: reg, Value: val})
+	}
+	return dump, nil
+}
+
+// String returns the registers of the dump one per line, with their address,
+// name, value and decoded fields, e.g.
+//
+//	0xF4 ctrl_meas 0x27 osrs_t=1 osrs_p=1 mode=normal
+func (d RegisterDump) String() string {
+	width := 0
+	for _, r := range d {
+		if len(r.Name) > width {
+			width = len(r.Name)
+		}
+	}
+	var b bytes.Buffer
+	for _, r := range d {
+		fmt.Fprintf(&b, "0x%02X %-*s 0x%02X", r.Address, width, r.Name, r.Value)
+		for _, f := range r.Fields {
+			fmt.Fprintf(&b, " %s=%v", f.Name, f.value(r.Value))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Map returns the registers of the dump by name, each one as a map of its
+// address, value and fields.
+func (d RegisterDump) Map() map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, r := range d {
+		reg := map[string]interface{}{
+			"address": r.Address,
+			"value":   r.Value,
+		}
+		for _, f := range r.Fields {
+			reg[f.Name] = f.value(r.Value)
+		}
+		m[r.Name] = reg
+	}
+	return m
+}
+
+// debugDumper is implemented by the drivers describing their registers.
+type debugDumper interface {
+	DebugDump() (RegisterDump, error)
+}
+
+// addDebugDumpCommand adds the "DebugDump" command to c, returning the
+// registers of d as a map, or the error reading them, for remote diagnostics.
+func addDebugDumpCommand(c gobot.Commander, d debugDumper) {
+	c.AddCommand("DebugDump", func(params map[string]interface{}) interface{} {
+		dump, err := d.DebugDump()
+		if err != nil {
+			return map[string]interface{}{"err": err}
+		}
+		return dump.Map()
+	})
+}
@@ -0,0 +1,80 @@
+package i2c_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/drivers/i2c/i2ctest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var testRegisters = i2c.RegisterMap{
+	{Name: "id", Address: 0xD0},
+	{Name: "ctrl_meas", Address: 0xF4, Fields: []i2c.RegisterField{
+		{Name: "osrs_t", Shift: 5, Width: 3},
+		{Name: "osrs_p", Shift: 2, Width: 3},
+		{Name: "mode", Shift: 0, Width: 2, Values: map[uint8]string{0: "sleep", 3: "normal"}},
+	}},
+}
+
+func initTestRegisterDevice() (i2c.Connection, *i2ctest.Device) {
+	a := i2ctest.NewAdaptor()
+	d := a.AddDevice(0x77)
+	d.SetRegister(0xD0, 0x58)
+	d.SetRegister(0xF4, 0x27)
+	conn, _ := a.GetConnection(0x77, a.GetDefaultBus())
+	return conn, d
+}
+
+func TestDumpRegisters(t *testing.T) {
+	conn, _ := initTestRegisterDevice()
+
+	dump, err := i2c.DumpRegisters(conn, testRegisters)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(dump), 2)
+	gobottest.Assert(t, dump[0].Value, uint8(0x58))
+	gobottest.Assert(t, dump[1].Value, uint8(0x27))
+}
+
+func TestDumpRegistersError(t *testing.T) {
+	conn, d := initTestRegisterDevice()
+	d.FailRead(0xF4, errors.New("read error"))
+
+	_, err := i2c.DumpRegisters(conn, testRegisters)
+	gobottest.Assert(t, err, i2c.ErrRegisterRead{Reg: 0xF4, Name: "ctrl_meas", Err: errors.New("read error")})
+	gobottest.Assert(t, err.Error(), "reading register ctrl_meas (0xF4): read error")
+}
+
+func TestDumpRegistersNotConnected(t *testing.T) {
+	_, err := i2c.DumpRegisters(nil, testRegisters)
+	gobottest.Assert(t, err, i2c.ErrNotConnected)
+}
+
+func TestRegisterDumpString(t *testing.T) {
+	conn, _ := initTestRegisterDevice()
+	dump, _ := i2c.DumpRegisters(conn, testRegisters)
+
+	gobottest.Assert(t, strings.Split(dump.String(), "\n"), []string{
+		"0xD0 id        0x58",
+		"0xF4 ctrl_meas 0x27 osrs_t=1 osrs_p=1 mode=normal",
+		"",
+	})
+}
+
+func TestRegisterDumpMap(t *testing.T) {
+	conn, _ := initTestRegisterDevice()
+	dump, _ := i2c.DumpRegisters(conn, testRegisters)
+
+	gobottest.Assert(t, dump.Map(), map[string]interface{}{
+		"id": map[string]interface{}{"address": uint8(0xD0), "value": uint8(0x58)},
+		"ctrl_meas": map[string]interface{}{
+			"address": uint8(0xF4),
+			"value":   uint8(0x27),
+			"osrs_t":  uint8(1),
+			"osrs_p":  uint8(1),
+			"mode":    "normal",
+		},
+	})
+}
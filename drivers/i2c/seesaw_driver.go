@@ -0,0 +1,444 @@
+package i2c
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const seesawAddress = 0x49
+
+// The modules of the seesaw firmware, each function register of a module
+// being addressed by the module base followed by the function.
+const (
+	SEESAW_STATUS_BASE   = 0x00
+	SEESAW_GPIO_BASE     = 0x01
+	SEESAW_TIMER_BASE    = 0x08
+	SEESAW_ADC_BASE      = 0x09
+	SEESAW_NEOPIXEL_BASE = 0x0E
+	SEESAW_ENCODER_BASE  = 0x11
+
+	SEESAW_STATUS_HW_ID   = 0x01
+	SEESAW_STATUS_VERSION = 0x02
+	SEESAW_STATUS_TEMP    = 0x04
+	SEESAW_STATUS_SWRST   = 0x7F
+
+	SEESAW_GPIO_DIRSET_BULK = 0x02
+	SEESAW_GPIO_DIRCLR_BULK = 0x03
+	SEESAW_GPIO_BULK        = 0x04
+	SEESAW_GPIO_BULK_SET    = 0x05
+	SEESAW_GPIO_BULK_CLR    = 0x06
+	SEESAW_GPIO_PULLENSET   = 0x0B
+	SEESAW_GPIO_PULLENCLR   = 0x0C
+
+	SEESAW_TIMER_PWM  = 0x01
+	SEESAW_TIMER_FREQ = 0x02
+
+	SEESAW_ADC_CHANNEL_OFFSET = 0x07
+
+	SEESAW_NEOPIXEL_PIN        = 0x01
+	SEESAW_NEOPIXEL_SPEED      = 0x02
+	SEESAW_NEOPIXEL_BUF_LENGTH = 0x03
+	SEESAW_NEOPIXEL_BUF        = 0x04
+	SEESAW_NEOPIXEL_SHOW       = 0x05
+
+	SEESAW_ENCODER_POSITION = 0x30
+	SEESAW_ENCODER_DELTA    = 0x40
+)
+
+// seesawHardwareID is the hardware ID of the ATSAMD09 based boards
+const seesawHardwareID = 0x55
+
+// SeesawPinMode is the mode of a GPIO pin of a seesaw board
+type SeesawPinMode int
+
+const (
+	// SeesawInput is a floating input
+	SeesawInput SeesawPinMode = iota
+	// SeesawOutput is a push-pull output
+	SeesawOutput
+	// SeesawInputPullUp is an input pulled up
+	SeesawInputPullUp
+	// SeesawInputPullDown is an input pulled down
+	SeesawInputPullDown
+)
+
+// seesawADCPins are the pins of the analog inputs of the ATSAMD09, by
+// channel
+var seesawADCPins = []int{2, 3, 4, 5}
+
+// seesawPWMPins are the pins of the PWM outputs of the ATSAMD09, by timer
+// channel
+var seesawPWMPins = []int{4, 5, 6, 7}
+
+// SeesawDriver is a driver for the boards running the Adafruit seesaw
+// firmware on an ATSAMD09, such as the seesaw breakout and the rotary
+// encoder, gamepad and soil sensor boards.
+//
+// It implements the gpio.DigitalReader, gpio.DigitalWriter, gpio.PwmWriter
+// and aio.AnalogReader interfaces, the pins being the GPIO numbers of the
+// seesaw, so that the gpio and aio drivers can use its pins:
+//
+//	seesaw := i2c.NewSeesawDriver(adaptor)
+//	led := gpio.NewLedDriver(seesaw, "5")
+//	sensor := aio.NewAnalogSensorDriver(seesaw, "2")
+type SeesawDriver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	outputs    uint32
+	pixels     int
+	Config
+	driverConfig
+}
+
+// NewSeesawDriver creates a new driver for a seesaw board.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver, 0x49 by default
+//
+func NewSeesawDriver(a Connector, options ...func(Config)) *SeesawDriver {
+	s := &SeesawDriver{
+		name:      gobot.DefaultName("Seesaw"),
+		connector: a,
+		Config:    NewConfig(),
+		mutex:     &sync.Mutex{},
+	}
+
+	s.driverConfig = driverConfig{config: &s.Config}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Name returns the name of the device.
+func (s *SeesawDriver) Name() string { return s.name }
+
+// SetName sets the name of the device.
+func (s *SeesawDriver) SetName(n string) { s.name = n }
+
+// Connection returns the connection of the device.
+func (s *SeesawDriver) Connection() gobot.Connection {
+	return s.connector.(gobot.Connection)
+}
+
+// Start resets the board, and checks that it is a seesaw board.
+func (s *SeesawDriver) Start() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connection, err = connect(s, s.connector, s.Config, seesawAddress); err != nil {
+		return err
+	}
+
+	if err = s.write(SEESAW_STATUS_BASE, SEESAW_STATUS_SWRST, 0xFF); err != nil {
+		return err
+	}
+	s.outputs = 0
+	s.pixels = 0
+
+	// the board does not answer while it boots
+	var id []byte
+	for i := 0; i < 10; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if id, err = s.read(SEESAW_STATUS_BASE, SEESAW_STATUS_HW_ID, 1); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if id[0] != seesawHardwareID {
+		return ErrBadDevice{Device: "seesaw", ID: int(id[0])}
+	}
+	return nil
+}
+
+// Halt is a noop function.
+func (s *SeesawDriver) Halt() error { return nil }
+
+// Version returns the product code of the board, e.g. 4991 for the rotary
+// encoder board, and the date code of its firmware.
+func (s *SeesawDriver) Version() (product uint16, date uint16, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.read(SEESAW_STATUS_BASE, SEESAW_STATUS_VERSION, 4)
+	if err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:]), nil
+}
+
+// Temperature returns the temperature of the ATSAMD09 in degrees Celsius.
+func (s *SeesawDriver) Temperature() (float32, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.read(SEESAW_STATUS_BASE, SEESAW_STATUS_TEMP, 4)
+	if err != nil {
+		return 0, err
+	}
+	// the temperature is a 16.16 fixed point number
+	return float32(binary.BigEndian.Uint32(data)&0x3FFFFFFF) / (1 << 16), nil
+}
+
+// PinMode sets the mode of a GPIO pin.
+func (s *SeesawDriver) PinMode(pin int, mode SeesawPinMode) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.pinMode(pin, mode)
+}
+
+func (s *SeesawDriver) pinMode(pin int, mode SeesawPinMode) (err error) {
+	if pin < 0 || pin > 31 {
+		return fmt.Errorf("seesaw pin %d out of range", pin)
+	}
+	mask := seesawPinMask(pin)
+
+	if mode == SeesawOutput {
+		if err = s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_DIRSET_BULK, mask...); err != nil {
+			return
+		}
+		s.outputs |= 1 << uint(pin)
+		return
+	}
+
+	if err = s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_DIRCLR_BULK, mask...); err != nil {
+		return
+	}
+	s.outputs &^= 1 << uint(pin)
+	switch mode {
+	case SeesawInputPullUp:
+		if err = s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_PULLENSET, mask...); err != nil {
+			return
+		}
+		// the pull direction is set by the output level
+		return s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_BULK_SET, mask...)
+	case SeesawInputPullDown:
+		if err = s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_PULLENSET, mask...); err != nil {
+			return
+		}
+		return s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_BULK_CLR, mask...)
+	default:
+		return s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_PULLENCLR, mask...)
+	}
+}
+
+// DigitalWrite writes a level to a GPIO pin, setting it as an output first
+// if it is not one.
+func (s *SeesawDriver) DigitalWrite(pin string, level byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	p, err := seesawPin(pin)
+	if err != nil {
+		return err
+	}
+	if s.outputs&(1<<uint(p)) == 0 {
+		if err := s.pinMode(p, SeesawOutput); err != nil {
+			return err
+		}
+	}
+	if level == 0 {
+		return s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_BULK_CLR, seesawPinMask(p)...)
+	}
+	return s.write(SEESAW_GPIO_BASE, SEESAW_GPIO_BULK_SET, seesawPinMask(p)...)
+}
+
+// DigitalRead reads the level of a GPIO pin, 0 or 1.
+func (s *SeesawDriver) DigitalRead(pin string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	p, err := seesawPin(pin)
+	if err != nil {
+		return 0, err
+	}
+	data, err := s.read(SEESAW_GPIO_BASE, SEESAW_GPIO_BULK, 4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(data)>>uint(p)) & 0x01, nil
+}
+
+// PwmWrite writes a duty cycle from 0 to 255 to a PWM pin, 4 to 7.
+func (s *SeesawDriver) PwmWrite(pin string, val byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	channel, err := seesawChannel(pin, seesawPWMPins)
+	if err != nil {
+		return err
+	}
+	// the duty cycle is 16 bits wide
+	value := uint16(val) * 257
+	return s.write(SEESAW_TIMER_BASE, SEESAW_TIMER_PWM, byte(channel), byte(value>>8), byte(value))
+}
+
+// SetPWMFreq sets the frequency in Hz of the PWM signal of a PWM pin.
+func (s *SeesawDriver) SetPWMFreq(pin string, freq uint16) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	channel, err := seesawChannel(pin, seesawPWMPins)
+	if err != nil {
+		return err
+	}
+	return s.write(SEESAW_TIMER_BASE, SEESAW_TIMER_FREQ, byte(channel), byte(freq>>8), byte(freq))
+}
+
+// AnalogRead returns the 10 bits value of an analog pin, 2 to 5.
+func (s *SeesawDriver) AnalogRead(pin string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	channel, err := seesawChannel(pin, seesawADCPins)
+	if err != nil {
+		return 0, err
+	}
+	data, err := s.read(SEESAW_ADC_BASE, SEESAW_ADC_CHANNEL_OFFSET+byte(channel), 2)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(data)), nil
+}
+
+// NeoPixelStart sets up a strip of count NeoPixels connected to a GPIO pin,
+// their color being set with SetNeoPixel and shown with ShowNeoPixels.
+func (s *SeesawDriver) NeoPixelStart(pin int, count int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.write(SEESAW_NEOPIXEL_BASE, SEESAW_NEOPIXEL_SPEED, 0x01); err != nil {
+		return err
+	}
+	if err := s.write(SEESAW_NEOPIXEL_BASE, SEESAW_NEOPIXEL_PIN, byte(pin)); err != nil {
+		return err
+	}
+	length := uint16(count * 3)
+	if err := s.write(SEESAW_NEOPIXEL_BASE, SEESAW_NEOPIXEL_BUF_LENGTH, byte(length>>8), byte(length)); err != nil {
+		return err
+	}
+	s.pixels = count
+	return nil
+}
+
+// SetNeoPixel sets the color of a NeoPixel, counted from 0.
+func (s *SeesawDriver) SetNeoPixel(i int, r byte, g byte, b byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if i < 0 || i >= s.pixels {
+		return fmt.Errorf("NeoPixel %d out of range, the strip has %d pixels", i, s.pixels)
+	}
+	offset := uint16(i * 3)
+	// the NeoPixels expect the green component first
+	return s.write(SEESAW_NEOPIXEL_BASE, SEESAW_NEOPIXEL_BUF, byte(offset>>8), byte(offset), g, r, b)
+}
+
+// ShowNeoPixels sends the colors set with SetNeoPixel to the NeoPixels.
+func (s *SeesawDriver) ShowNeoPixels() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.write(SEESAW_NEOPIXEL_BASE, SEESAW_NEOPIXEL_SHOW)
+}
+
+// EncoderPosition returns the position of the rotary encoder.
+func (s *SeesawDriver) EncoderPosition() (int32, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.read(SEESAW_ENCODER_BASE, SEESAW_ENCODER_POSITION, 4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(data)), nil
+}
+
+// SetEncoderPosition sets the position of the rotary encoder.
+func (s *SeesawDriver) SetEncoderPosition(pos int32) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, uint32(pos))
+	return s.write(SEESAW_ENCODER_BASE, SEESAW_ENCODER_POSITION, data...)
+}
+
+// EncoderDelta returns how much the rotary encoder turned since the last
+// call of EncoderDelta.
+func (s *SeesawDriver) EncoderDelta() (int32, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.read(SEESAW_ENCODER_BASE, SEESAW_ENCODER_DELTA, 4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(data)), nil
+}
+
+func (s *SeesawDriver) write(base byte, function byte, data ...byte) error {
+	_, err := s.connection.Write(append([]byte{base, function}, data...))
+	return err
+}
+
+func (s *SeesawDriver) read(base byte, function byte, n int) ([]byte, error) {
+	if err := s.write(base, function); err != nil {
+		return nil, err
+	}
+	// the board needs some time to prepare the data
+	time.Sleep(time.Millisecond)
+	buf := make([]byte, n)
+	bytesRead, err := s.connection.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	if bytesRead != n {
+		return nil, ErrNotEnoughBytes
+	}
+	return buf, nil
+}
+
+// seesawPin returns the number of a GPIO pin
+func seesawPin(pin string) (int, error) {
+	p, err := strconv.Atoi(pin)
+	if err != nil || p < 0 || p > 31 {
+		return 0, fmt.Errorf("invalid seesaw pin %q", pin)
+	}
+	return p, nil
+}
+
+// seesawChannel returns the channel of a pin among the pins of a module
+func seesawChannel(pin string, pins []int) (int, error) {
+	p, err := seesawPin(pin)
+	if err != nil {
+		return 0, err
+	}
+	for channel, other := range pins {
+		if other == p {
+			return channel, nil
+		}
+	}
+	return 0, fmt.Errorf("seesaw pin %d does not support this function", p)
+}
+
+// seesawPinMask returns the 32 bits mask of a pin, most significant byte first
+func seesawPinMask(pin int) []byte {
+	mask := make([]byte, 4)
+	binary.BigEndian.PutUint32(mask, 1<<uint(pin))
+	return mask
+}
@@ -0,0 +1,208 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*SeesawDriver)(nil)
+
+// and also the standard interfaces
+var _ gpio.DigitalReader = (*SeesawDriver)(nil)
+var _ gpio.DigitalWriter = (*SeesawDriver)(nil)
+var _ gpio.PwmWriter = (*SeesawDriver)(nil)
+var _ aio.AnalogReader = (*SeesawDriver)(nil)
+
+// --------- HELPERS
+
+// initTestSeesawDriverWithStubbedAdaptor returns a started SeesawDriver whose
+// reads return the data of registers, keyed by module base and function
+func initTestSeesawDriverWithStubbedAdaptor(registers map[[2]byte][]byte) (*SeesawDriver, *i2cTestAdaptor) {
+	adaptor := newI2cTestAdaptor()
+	registers[[2]byte{SEESAW_STATUS_BASE, SEESAW_STATUS_HW_ID}] = []byte{seesawHardwareID}
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		// the adaptor is locked while reading
+		w := adaptor.written
+		return copy(b, registers[[2]byte{w[len(w)-2], w[len(w)-1]}]), nil
+	}
+	d := NewSeesawDriver(adaptor)
+	d.Start()
+	adaptor.written = []byte{}
+	return d, adaptor
+}
+
+// --------- TESTS
+
+func TestSeesawDriver(t *testing.T) {
+	d := NewSeesawDriver(newI2cTestAdaptor())
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "Seesaw"), true)
+	d.SetName("TESTME")
+	gobottest.Assert(t, d.Name(), "TESTME")
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestSeesawDriverOptions(t *testing.T) {
+	d := NewSeesawDriver(newI2cTestAdaptor(), WithBus(2), WithAddress(0x36))
+	gobottest.Assert(t, d.GetBusOrDefault(1), 2)
+	gobottest.Assert(t, d.GetAddressOrDefault(seesawAddress), 0x36)
+}
+
+func TestSeesawDriverStart(t *testing.T) {
+	a := newI2cTestAdaptor()
+	d := NewSeesawDriver(a)
+	a.Testi2cReadImpl(func(b []byte) (int, error) {
+		b[0] = seesawHardwareID
+		return 1, nil
+	})
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, a.written, []byte{0x00, 0x7F, 0xFF, 0x00, 0x01})
+}
+
+func TestSeesawDriverStartErrors(t *testing.T) {
+	a := newI2cTestAdaptor()
+	d := NewSeesawDriver(a)
+	a.Testi2cConnectErr(true)
+	gobottest.Assert(t, d.Start(), errors.New("Invalid i2c connection"))
+
+	a.Testi2cConnectErr(false)
+	a.Testi2cReadImpl(func(b []byte) (int, error) {
+		b[0] = 0x87
+		return 1, nil
+	})
+	gobottest.Assert(t, d.Start(), ErrBadDevice{Device: "seesaw", ID: 0x87})
+
+	// the board never answers
+	a.Testi2cReadImpl(func(b []byte) (int, error) {
+		return 0, errors.New("read error")
+	})
+	gobottest.Assert(t, d.Start(), errors.New("read error"))
+}
+
+func TestSeesawDriverVersion(t *testing.T) {
+	d, _ := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{
+		{SEESAW_STATUS_BASE, SEESAW_STATUS_VERSION}: {0x13, 0x7F, 0x52, 0x0C},
+		{SEESAW_STATUS_BASE, SEESAW_STATUS_TEMP}:    {0x00, 0x19, 0x80, 0x00},
+	})
+	product, date, err := d.Version()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, product, uint16(4991))
+	gobottest.Assert(t, date, uint16(0x520C))
+
+	temp, err := d.Temperature()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, temp, float32(25.5))
+}
+
+func TestSeesawDriverPinMode(t *testing.T) {
+	d, a := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{})
+	gobottest.Assert(t, d.PinMode(9, SeesawInputPullUp), nil)
+	gobottest.Assert(t, a.written, []byte{
+		0x01, 0x03, 0x00, 0x00, 0x02, 0x00,
+		0x01, 0x0B, 0x00, 0x00, 0x02, 0x00,
+		0x01, 0x05, 0x00, 0x00, 0x02, 0x00,
+	})
+
+	a.written = []byte{}
+	gobottest.Assert(t, d.PinMode(0, SeesawInput), nil)
+	gobottest.Assert(t, a.written, []byte{
+		0x01, 0x03, 0x00, 0x00, 0x00, 0x01,
+		0x01, 0x0C, 0x00, 0x00, 0x00, 0x01,
+	})
+	gobottest.Assert(t, d.PinMode(32, SeesawOutput), errors.New("seesaw pin 32 out of range"))
+}
+
+func TestSeesawDriverDigitalWrite(t *testing.T) {
+	d, a := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{})
+
+	// the pin is set as an output once
+	gobottest.Assert(t, d.DigitalWrite("24", 1), nil)
+	gobottest.Assert(t, d.DigitalWrite("24", 0), nil)
+	gobottest.Assert(t, a.written, []byte{
+		0x01, 0x02, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x05, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x06, 0x01, 0x00, 0x00, 0x00,
+	})
+	gobottest.Assert(t, d.DigitalWrite("a", 1), errors.New("invalid seesaw pin \"a\""))
+}
+
+func TestSeesawDriverDigitalRead(t *testing.T) {
+	d, _ := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{
+		{SEESAW_GPIO_BASE, SEESAW_GPIO_BULK}: {0x00, 0x00, 0x40, 0x01},
+	})
+	val, err := d.DigitalRead("14")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+	val, _ = d.DigitalRead("1")
+	gobottest.Assert(t, val, 0)
+	_, err = d.DigitalRead("40")
+	gobottest.Assert(t, err, errors.New("invalid seesaw pin \"40\""))
+}
+
+func TestSeesawDriverPwmWrite(t *testing.T) {
+	d, a := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{})
+	gobottest.Assert(t, d.PwmWrite("5", 128), nil)
+	gobottest.Assert(t, d.SetPWMFreq("7", 1000), nil)
+	gobottest.Assert(t, a.written, []byte{0x08, 0x01, 0x01, 0x80, 0x80, 0x08, 0x02, 0x03, 0x03, 0xE8})
+	gobottest.Assert(t, d.PwmWrite("2", 128), errors.New("seesaw pin 2 does not support this function"))
+}
+
+func TestSeesawDriverAnalogRead(t *testing.T) {
+	d, _ := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{
+		{SEESAW_ADC_BASE, SEESAW_ADC_CHANNEL_OFFSET + 2}: {0x02, 0x9A},
+	})
+	val, err := d.AnalogRead("4")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 666)
+	_, err = d.AnalogRead("9")
+	gobottest.Assert(t, err, errors.New("seesaw pin 9 does not support this function"))
+}
+
+func TestSeesawDriverReadError(t *testing.T) {
+	d, a := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{})
+	_, err := d.AnalogRead("2")
+	gobottest.Assert(t, err, ErrNotEnoughBytes)
+	a.Testi2cWriteImpl(func([]byte) (int, error) {
+		return 0, errors.New("write error")
+	})
+	_, err = d.EncoderPosition()
+	gobottest.Assert(t, err, errors.New("write error"))
+}
+
+func TestSeesawDriverNeoPixel(t *testing.T) {
+	d, a := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{})
+	gobottest.Assert(t, d.SetNeoPixel(0, 1, 2, 3), errors.New("NeoPixel 0 out of range, the strip has 0 pixels"))
+	gobottest.Assert(t, d.NeoPixelStart(6, 100), nil)
+	gobottest.Assert(t, d.SetNeoPixel(99, 0xFF, 0x80, 0x00), nil)
+	gobottest.Assert(t, d.ShowNeoPixels(), nil)
+	gobottest.Assert(t, a.written, []byte{
+		0x0E, 0x02, 0x01,
+		0x0E, 0x01, 0x06,
+		0x0E, 0x03, 0x01, 0x2C,
+		0x0E, 0x04, 0x01, 0x29, 0x80, 0xFF, 0x00,
+		0x0E, 0x05,
+	})
+}
+
+func TestSeesawDriverEncoder(t *testing.T) {
+	d, a := initTestSeesawDriverWithStubbedAdaptor(map[[2]byte][]byte{
+		{SEESAW_ENCODER_BASE, SEESAW_ENCODER_POSITION}: {0xFF, 0xFF, 0xFF, 0xFD},
+		{SEESAW_ENCODER_BASE, SEESAW_ENCODER_DELTA}:    {0x00, 0x00, 0x00, 0x02},
+	})
+	pos, err := d.EncoderPosition()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, pos, int32(-3))
+	delta, err := d.EncoderDelta()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, delta, int32(2))
+
+	a.written = []byte{}
+	gobottest.Assert(t, d.SetEncoderPosition(256), nil)
+	gobottest.Assert(t, a.written, []byte{0x11, 0x30, 0x00, 0x00, 0x01, 0x00})
+}
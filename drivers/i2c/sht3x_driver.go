@@ -23,12 +23,23 @@ package i2c
 
 import (
 	"errors"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/sigurn/crc8"
 	"gobot.io/x/gobot"
 )
 
+const (
+	// Alert event is published by the SHT3xDriver when its ALERT pin rises,
+	// a temperature or humidity having crossed a set alert limit
+	Alert = "alert"
+	// AlertCleared event is published by the SHT3xDriver when its ALERT pin
+	// falls, the measurements being back within the clear alert limits
+	AlertCleared = "alert-cleared"
+)
+
 // SHT3xAddressA is the default address of device
 const SHT3xAddressA = 0x44
 
@@ -44,11 +55,79 @@ const SHT3xAccuracyMedium = 0x0b
 // SHT3xAccuracyHigh is the high accuracy and slowest sample setting
 const SHT3xAccuracyHigh = 0x00
 
+// SHT3xRate is the number of measurements per second of the periodic mode
+type SHT3xRate byte
+
+const (
+	// SHT3xRateHalf is one measurement every 2 seconds
+	SHT3xRateHalf SHT3xRate = 0x20
+	// SHT3xRate1 is one measurement per second
+	SHT3xRate1 SHT3xRate = 0x21
+	// SHT3xRate2 is 2 measurements per second
+	SHT3xRate2 SHT3xRate = 0x22
+	// SHT3xRate4 is 4 measurements per second
+	SHT3xRate4 SHT3xRate = 0x23
+	// SHT3xRate10 is 10 measurements per second
+	SHT3xRate10 SHT3xRate = 0x27
+)
+
+// sht3xPeriodicCommands are the second bytes of the commands starting the
+// periodic mode, by rate and accuracy
+var sht3xPeriodicCommands = map[SHT3xRate]map[byte]byte{
+	SHT3xRateHalf: {SHT3xAccuracyHigh: 0x32, SHT3xAccuracyMedium: 0x24, SHT3xAccuracyLow: 0x2f},
+	SHT3xRate1:    {SHT3xAccuracyHigh: 0x30, SHT3xAccuracyMedium: 0x26, SHT3xAccuracyLow: 0x2d},
+	SHT3xRate2:    {SHT3xAccuracyHigh: 0x36, SHT3xAccuracyMedium: 0x20, SHT3xAccuracyLow: 0x2b},
+	SHT3xRate4:    {SHT3xAccuracyHigh: 0x34, SHT3xAccuracyMedium: 0x22, SHT3xAccuracyLow: 0x29},
+	SHT3xRate10:   {SHT3xAccuracyHigh: 0x37, SHT3xAccuracyMedium: 0x21, SHT3xAccuracyLow: 0x2a},
+}
+
+// SHT3xAlertLimit is one of the four limits of the alert of the SHT3x. The
+// ALERT pin rises when the temperature or the humidity goes above the high
+// set limit or below the low set limit, and falls once they are back between
+// the clear limits.
+type SHT3xAlertLimit byte
+
+const (
+	// SHT3xAlertHighSet is the limit above which the alert rises
+	SHT3xAlertHighSet SHT3xAlertLimit = iota
+	// SHT3xAlertHighClear is the limit below which a high alert clears
+	SHT3xAlertHighClear
+	// SHT3xAlertLowClear is the limit above which a low alert clears
+	SHT3xAlertLowClear
+	// SHT3xAlertLowSet is the limit below which the alert rises
+	SHT3xAlertLowSet
+)
+
+// sht3xAlertCommands are the second bytes of the commands reading and
+// writing the alert limits
+var sht3xAlertCommands = map[SHT3xAlertLimit][2]byte{
+	SHT3xAlertHighSet:   {0x1f, 0x1d},
+	SHT3xAlertHighClear: {0x14, 0x16},
+	SHT3xAlertLowClear:  {0x09, 0x0b},
+	SHT3xAlertLowSet:    {0x02, 0x00},
+}
+
+// Bits of the status register of the SHT3x
+const (
+	SHT3xStatusAlertPending     = 1 << 15
+	SHT3xStatusHeater           = 1 << 13
+	SHT3xStatusHumidityAlert    = 1 << 11
+	SHT3xStatusTemperatureAlert = 1 << 10
+	SHT3xStatusReset            = 1 << 4
+	SHT3xStatusCommandFailed    = 1 << 1
+	SHT3xStatusChecksumFailed   = 1 << 0
+)
+
+// sht3xAlertPolling is the interval at which the ALERT pin is read
+const sht3xAlertPolling = 10 * time.Millisecond
+
 var (
 	crc8Params         = crc8.Params{0x31, 0xff, false, false, 0x00, 0xf7, "CRC-8/SENSIRON"}
 	ErrInvalidAccuracy = errors.New("Invalid accuracy")
 	ErrInvalidCrc      = errors.New("Invalid crc")
 	ErrInvalidTemp     = errors.New("Invalid temperature units")
+	ErrInvalidRate     = errors.New("Invalid measurement rate")
+	ErrInvalidLimit    = errors.New("Invalid alert limit")
 )
 
 // SHT3xDriver is a Driver for a SHT3x humidity and temperature sensor
@@ -58,11 +137,19 @@ type SHT3xDriver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Eventer
 	sht3xAddress int
 	accuracy     byte
 	delay        time.Duration
 	crcTable     *crc8.Table
+	periodic     bool
+	alertReader  AlertReader
+	alertPin     string
+	alerting     bool
+	halt         chan bool
 }
 
 // NewSHT3xDriver creates a new driver with specified i2c interface
@@ -72,6 +159,7 @@ type SHT3xDriver struct {
 // Optional params:
 //		i2c.WithBus(int):	bus to use with this driver
 //		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithSHT3xAlertPin(i2c.AlertReader, string):	pin wired to the ALERT output
 //
 func NewSHT3xDriver(a Connector, options ...func(Config)) *SHT3xDriver {
 	s := &SHT3xDriver{
@@ -79,18 +167,44 @@ func NewSHT3xDriver(a Connector, options ...func(Config)) *SHT3xDriver {
 		name:         gobot.DefaultName("SHT3x"),
 		connector:    a,
 		Config:       NewConfig(),
+		Eventer:      gobot.NewEventer(),
+		mutex:        &sync.Mutex{},
 		sht3xAddress: SHT3xAddressA,
 		crcTable:     crc8.MakeTable(crc8Params),
 	}
 	s.SetAccuracy(SHT3xAccuracyHigh)
 
+	s.driverConfig = driverConfig{config: &s.Config}
 	for _, option := range options {
 		option(s)
 	}
 
+	s.AddEvent(Alert)
+	s.AddEvent(AlertCleared)
+	s.AddEvent(Error)
+
 	return s
 }
 
+// AlertReader reads the level of the pin wired to the alert output of a
+// device. It is implemented by the gpio.DigitalReader adaptors, without
+// package i2c importing package gpio.
+type AlertReader interface {
+	DigitalRead(pin string) (val int, err error)
+}
+
+// WithSHT3xAlertPin option sets the pin of an AlertReader wired to the ALERT
+// output of the SHT3x, which is then polled to publish the Alert and
+// AlertCleared events.
+func WithSHT3xAlertPin(reader AlertReader, pin string) func(Config) {
+	return func(c Config) {
+		if s, ok := c.(*SHT3xDriver); ok {
+			s.alertReader = reader
+			s.alertPin = pin
+		}
+	}
+}
+
 // Name returns the name for this Driver
 func (s *SHT3xDriver) Name() string { return s.name }
 
@@ -100,17 +214,44 @@ func (s *SHT3xDriver) SetName(n string) { s.name = n }
 // Connection returns the connection for this Driver
 func (s *SHT3xDriver) Connection() gobot.Connection { return s.connector.(gobot.Connection) }
 
-// Start initializes the SHT3x
+// Start initializes the SHT3x, and starts polling its ALERT pin when set
+//
+// Emits the Events:
+// 	Alert - When the ALERT pin rises
+// 	AlertCleared - When the ALERT pin falls
+// 	Error error - On error reading the ALERT pin
 func (s *SHT3xDriver) Start() (err error) {
-	bus := s.GetBusOrDefault(s.connector.GetDefaultBus())
-	address := s.GetAddressOrDefault(s.sht3xAddress)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	s.connection, err = s.connector.GetConnection(address, bus)
+	if s.connection, err = connect(s, s.connector, s.Config, s.sht3xAddress); err != nil {
+		return
+	}
+	if s.alertReader != nil {
+		s.alerting = false
+		s.halt = make(chan bool)
+		gobot.NewPoller(gobot.PollerConfig{Interval: sht3xAlertPolling}).Go(s, s.halt, func() bool {
+			s.pollAlert()
+			return true
+		})
+	}
 	return
 }
 
-// Halt returns true if devices is halted successfully
-func (s *SHT3xDriver) Halt() (err error) { return }
+// Halt stops the polling of the ALERT pin and the periodic mode
+func (s *SHT3xDriver) Halt() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.halt != nil {
+		close(s.halt)
+		s.halt = nil
+	}
+	if s.periodic {
+		err = s.stopPeriodic()
+	}
+	return
+}
 
 // SetAddress sets the address of the device
 func (s *SHT3xDriver) SetAddress(address int) { s.sht3xAddress = address }
@@ -120,6 +261,9 @@ func (s *SHT3xDriver) Accuracy() byte { return s.accuracy }
 
 // SetAccuracy sets the accuracy of the sampling
 func (s *SHT3xDriver) SetAccuracy(a byte) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	switch a {
 	case SHT3xAccuracyLow:
 		s.delay = 5 * time.Millisecond // Actual max is 4, wait 1 ms longer
@@ -139,6 +283,9 @@ func (s *SHT3xDriver) SetAccuracy(a byte) (err error) {
 
 // SerialNumber returns the serial number of the chip
 func (s *SHT3xDriver) SerialNumber() (sn uint32, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	ret, err := s.sendCommandDelayGetResponse([]byte{0x37, 0x80}, nil, 2)
 	if nil == err {
 		sn = (uint32(ret[0]) << 16) | uint32(ret[1])
@@ -149,6 +296,9 @@ func (s *SHT3xDriver) SerialNumber() (sn uint32, err error) {
 
 // Heater returns true if the heater is enabled
 func (s *SHT3xDriver) Heater() (status bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	sr, err := s.getStatusRegister()
 	if err == nil {
 		if (1 << 13) == (sr & (1 << 13)) {
@@ -160,6 +310,9 @@ func (s *SHT3xDriver) Heater() (status bool, err error) {
 
 // SetHeater enables or disables the heater on the device
 func (s *SHT3xDriver) SetHeater(enabled bool) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	out := []byte{0x30, 0x66}
 	if true == enabled {
 		out[1] = 0x6d
@@ -168,9 +321,60 @@ func (s *SHT3xDriver) SetHeater(enabled bool) (err error) {
 	return
 }
 
-// Sample returns the temperature in celsius and relative humidity for one sample
+// StartPeriodic starts the periodic mode, in which the SHT3x measures at
+// rate with the current accuracy and Sample returns its last measurement. The
+// alert of the SHT3x is only updated in the periodic mode.
+func (s *SHT3xDriver) StartPeriodic(rate SHT3xRate) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cmds, ok := sht3xPeriodicCommands[rate]
+	if !ok {
+		return ErrInvalidRate
+	}
+	if s.periodic {
+		if err = s.stopPeriodic(); err != nil {
+			return
+		}
+	}
+	if _, err = s.connection.Write([]byte{byte(rate), cmds[s.accuracy]}); err != nil {
+		return
+	}
+	s.periodic = true
+	return
+}
+
+// StopPeriodic stops the periodic mode, back to single-shot measurements
+func (s *SHT3xDriver) StopPeriodic() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.periodic {
+		return
+	}
+	return s.stopPeriodic()
+}
+
+// Periodic returns whether the SHT3x is in the periodic mode
+func (s *SHT3xDriver) Periodic() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.periodic
+}
+
+// Sample returns the temperature in celsius and relative humidity for one
+// sample, the last measurement in the periodic mode. The SHT3x fails the read
+// until its first periodic measurement, and when it was already fetched.
 func (s *SHT3xDriver) Sample() (temp float32, rh float32, err error) {
-	ret, err := s.sendCommandDelayGetResponse([]byte{0x24, s.accuracy}, &s.delay, 2)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var ret []uint16
+	if s.periodic {
+		ret, err = s.sendCommandDelayGetResponse([]byte{0xe0, 0x00}, nil, 2)
+	} else {
+		ret, err = s.sendCommandDelayGetResponse([]byte{0x24, s.accuracy}, &s.delay, 2)
+	}
 	if nil != err {
 		return
 	}
@@ -197,6 +401,115 @@ func (s *SHT3xDriver) Sample() (temp float32, rh float32, err error) {
 	return
 }
 
+// Temperature returns the temperature, in the Units of the driver, of one
+// sample
+func (s *SHT3xDriver) Temperature() (temp float32, err error) {
+	temp, _, err = s.Sample()
+	return
+}
+
+// Humidity returns the relative humidity of one sample
+func (s *SHT3xDriver) Humidity() (rh float32, err error) {
+	_, rh, err = s.Sample()
+	return
+}
+
+// Status returns the status register, see the SHT3xStatus bits
+func (s *SHT3xDriver) Status() (status uint16, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.getStatusRegister()
+}
+
+// ClearStatus clears the alert, reset and checksum bits of the status
+// register
+func (s *SHT3xDriver) ClearStatus() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err = s.connection.Write([]byte{0x30, 0x41})
+	return
+}
+
+// SetAlertLimit sets an alert limit to the temperature and relative
+// humidity. The SHT3x keeps the 9 most significant bits of the temperature
+// and the 7 of the humidity, the limits being rounded down to about 0.35C and
+// 0.8%RH.
+func (s *SHT3xDriver) SetAlertLimit(limit SHT3xAlertLimit, temp gobot.Temperature, rh float32) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cmds, ok := sht3xAlertCommands[limit]
+	if !ok {
+		return ErrInvalidLimit
+	}
+
+	tempSample := uint16(math.Max(0, math.Min(0xffff, (temp.Celsius()+45)/175*0xffff)))
+	rhSample := uint16(math.Max(0, math.Min(0xffff, float64(rh)/100*0xffff)))
+	word := rhSample&0xfe00 | tempSample>>7
+	data := []byte{byte(word >> 8), byte(word)}
+	_, err = s.connection.Write([]byte{0x61, cmds[1], data[0], data[1], crc8.Checksum(data, s.crcTable)})
+	return
+}
+
+// AlertLimit returns the temperature and the relative humidity of an alert
+// limit
+func (s *SHT3xDriver) AlertLimit(limit SHT3xAlertLimit) (temp gobot.Temperature, rh float32, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cmds, ok := sht3xAlertCommands[limit]
+	if !ok {
+		return 0, 0, ErrInvalidLimit
+	}
+	ret, err := s.sendCommandDelayGetResponse([]byte{0xe1, cmds[0]}, nil, 1)
+	if err != nil {
+		return
+	}
+
+	temp = gobot.Celsius(-45 + 175*float64((ret[0]&0x01ff)<<7)/0xffff)
+	rh = float32(100 * float64(ret[0]&0xfe00) / 0xffff)
+	return
+}
+
+// Alerting returns whether the ALERT pin was last read high
+func (s *SHT3xDriver) Alerting() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.alerting
+}
+
+// pollAlert reads the ALERT pin and publishes its changes
+func (s *SHT3xDriver) pollAlert() {
+	val, err := s.alertReader.DigitalRead(s.alertPin)
+	if err != nil {
+		s.Publish(s.Event(Error), err)
+		return
+	}
+
+	s.mutex.Lock()
+	changed := (val == 1) != s.alerting
+	s.alerting = val == 1
+	s.mutex.Unlock()
+
+	switch {
+	case changed && val == 1:
+		s.Publish(s.Event(Alert), nil)
+	case changed:
+		s.Publish(s.Event(AlertCleared), nil)
+	}
+}
+
+// stopPeriodic sends the break command, which the SHT3x takes 1ms to process
+func (s *SHT3xDriver) stopPeriodic() (err error) {
+	if _, err = s.connection.Write([]byte{0x30, 0x93}); err != nil {
+		return
+	}
+	s.periodic = false
+	time.Sleep(time.Millisecond)
+	return
+}
+
 // getStatusRegister returns the device status register
 func (s *SHT3xDriver) getStatusRegister() (status uint16, err error) {
 	ret, err := s.sendCommandDelayGetResponse([]byte{0xf3, 0x2d}, nil, 1)
@@ -226,12 +539,17 @@ func (s *SHT3xDriver) sendCommandDelayGetResponse(send []byte, delay *time.Durat
 		return
 	}
 
-	read = make([]uint16, expect)
-	for i := 0; i < expect; i++ {
-		crc := crc8.Checksum(buf[i*3:i*3+2], s.crcTable)
+	return sensirionWords(buf, s.crcTable)
+}
+
+// sensirionWords returns the 16 bits words read from a Sensirion sensor, each
+// followed by its CRC
+func sensirionWords(buf []byte, table *crc8.Table) (read []uint16, err error) {
+	read = make([]uint16, len(buf)/3)
+	for i := range read {
+		crc := crc8.Checksum(buf[i*3:i*3+2], table)
 		if buf[i*3+2] != crc {
-			err = ErrInvalidCrc
-			return
+			return nil, ErrInvalidCrc
 		}
 		read[i] = uint16(buf[i*3])<<8 | uint16(buf[i*3+1])
 	}
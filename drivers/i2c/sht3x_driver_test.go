@@ -3,13 +3,17 @@ package i2c
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
 )
 
 var _ gobot.Driver = (*SHT3xDriver)(nil)
+var _ gobot.TemperatureSensor = (*SHT3xDriver)(nil)
+var _ gobot.HumiditySensor = (*SHT3xDriver)(nil)
 
 // --------- HELPERS
 func initTestSHT3xDriver() (driver *SHT3xDriver) {
@@ -109,6 +113,22 @@ func TestSHT3xDriverSampleNormal(t *testing.T) {
 	gobottest.Assert(t, temp, float32(185.9414))
 }
 
+func TestSHT3xDriverTemperatureHumidity(t *testing.T) {
+	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
+
+	gobottest.Assert(t, sht3x.Start(), nil)
+
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		copy(b, []byte{0xbe, 0xef, 0x92, 0xbe, 0xef, 0x92})
+		return 6, nil
+	}
+
+	temp, _ := sht3x.Temperature()
+	gobottest.Assert(t, temp, float32(85.523003))
+	rh, _ := sht3x.Humidity()
+	gobottest.Assert(t, rh, float32(74.5845))
+}
+
 func TestSHT3xDriverSampleBadCrc(t *testing.T) {
 	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
 
@@ -270,3 +290,154 @@ func TestSHT3xDriverSerialNumber(t *testing.T) {
 	gobottest.Assert(t, err, nil)
 	gobottest.Assert(t, sn, uint32(0x2000beef))
 }
+
+func TestSHT3xDriverPeriodic(t *testing.T) {
+	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, sht3x.Start(), nil)
+
+	gobottest.Assert(t, sht3x.StartPeriodic(SHT3xRate(0x42)), ErrInvalidRate)
+	gobottest.Assert(t, sht3x.StartPeriodic(SHT3xRate1), nil)
+	gobottest.Assert(t, sht3x.Periodic(), true)
+	gobottest.Assert(t, adaptor.written, []byte{0x21, 0x30})
+
+	// changing the rate breaks the current periodic mode first
+	adaptor.written = nil
+	sht3x.SetAccuracy(SHT3xAccuracyLow)
+	gobottest.Assert(t, sht3x.StartPeriodic(SHT3xRate10), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x30, 0x93, 0x27, 0x2a})
+
+	adaptor.written = nil
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0xbe, 0xef, 0x92, 0xbe, 0xef, 0x92}), nil
+	}
+	temp, rh, err := sht3x.Sample()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, temp, float32(85.523003))
+	gobottest.Assert(t, rh, float32(74.5845))
+	gobottest.Assert(t, adaptor.written, []byte{0xe0, 0x00})
+
+	adaptor.written = nil
+	gobottest.Assert(t, sht3x.StopPeriodic(), nil)
+	gobottest.Assert(t, sht3x.Periodic(), false)
+	gobottest.Assert(t, sht3x.StopPeriodic(), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x30, 0x93})
+}
+
+func TestSHT3xDriverHaltPeriodic(t *testing.T) {
+	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, sht3x.Start(), nil)
+	gobottest.Assert(t, sht3x.StartPeriodic(SHT3xRateHalf), nil)
+
+	adaptor.written = nil
+	gobottest.Assert(t, sht3x.Halt(), nil)
+	gobottest.Assert(t, sht3x.Periodic(), false)
+	gobottest.Assert(t, adaptor.written, []byte{0x30, 0x93})
+}
+
+func TestSHT3xDriverSetAlertLimit(t *testing.T) {
+	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, sht3x.Start(), nil)
+
+	// the default high set limit of the datasheet
+	gobottest.Assert(t, sht3x.SetAlertLimit(SHT3xAlertHighSet, gobot.Celsius(60), 80), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x61, 0x1d, 0xcd, 0x33, 0xfd})
+
+	adaptor.written = nil
+	gobottest.Assert(t, sht3x.SetAlertLimit(SHT3xAlertLowSet, gobot.Fahrenheit(140), 80), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x61, 0x00, 0xcd, 0x33, 0xfd})
+
+	gobottest.Assert(t, sht3x.SetAlertLimit(SHT3xAlertLimit(4), 0, 0), ErrInvalidLimit)
+}
+
+func TestSHT3xDriverAlertLimit(t *testing.T) {
+	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, sht3x.Start(), nil)
+
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0xcd, 0x33, 0xfd}), nil
+	}
+	temp, rh, err := sht3x.AlertLimit(SHT3xAlertHighClear)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, temp.String(), "59.9332°C")
+	gobottest.Assert(t, rh, float32(79.68871))
+	gobottest.Assert(t, adaptor.written, []byte{0xe1, 0x14})
+
+	_, _, err = sht3x.AlertLimit(SHT3xAlertLimit(4))
+	gobottest.Assert(t, err, ErrInvalidLimit)
+
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0xcd, 0x33, 0x00}), nil
+	}
+	_, _, err = sht3x.AlertLimit(SHT3xAlertLowSet)
+	gobottest.Assert(t, err, ErrInvalidCrc)
+}
+
+func TestSHT3xDriverStatus(t *testing.T) {
+	sht3x, adaptor := initTestSHT3xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, sht3x.Start(), nil)
+
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x80, 0x00, 0xa2}), nil
+	}
+	status, err := sht3x.Status()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, status&SHT3xStatusAlertPending != 0, true)
+
+	adaptor.written = nil
+	gobottest.Assert(t, sht3x.ClearStatus(), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x30, 0x41})
+}
+
+type sht3xTestAlertPin struct {
+	val   int
+	err   error
+	mutex sync.Mutex
+}
+
+func (p *sht3xTestAlertPin) DigitalRead(pin string) (int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.val, p.err
+}
+
+func (p *sht3xTestAlertPin) set(val int, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.val, p.err = val, err
+}
+
+func TestSHT3xDriverAlertPin(t *testing.T) {
+	pin := &sht3xTestAlertPin{}
+	sht3x := NewSHT3xDriver(newI2cTestAdaptor(), WithSHT3xAlertPin(pin, "7"))
+	alerts, cleared, errs := make(chan bool, 1), make(chan bool, 1), make(chan error, 1)
+	sht3x.On(Alert, func(interface{}) { alerts <- true })
+	sht3x.On(AlertCleared, func(interface{}) { cleared <- true })
+	sht3x.On(Error, func(data interface{}) { errs <- data.(error) })
+
+	gobottest.Assert(t, sht3x.Start(), nil)
+	defer sht3x.Halt()
+
+	pin.set(1, nil)
+	select {
+	case <-alerts:
+	case <-time.After(time.Second):
+		t.Errorf("Alert event was not published")
+	}
+	gobottest.Assert(t, sht3x.Alerting(), true)
+
+	pin.set(0, nil)
+	select {
+	case <-cleared:
+	case <-time.After(time.Second):
+		t.Errorf("AlertCleared event was not published")
+	}
+	gobottest.Assert(t, sht3x.Alerting(), false)
+
+	pin.set(0, errors.New("read error"))
+	select {
+	case err := <-errs:
+		gobottest.Assert(t, err, errors.New("read error"))
+	case <-time.After(time.Second):
+		t.Errorf("Error event was not published")
+	}
+}
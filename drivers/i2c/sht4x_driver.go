@@ -0,0 +1,269 @@
+package i2c
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sigurn/crc8"
+	"gobot.io/x/gobot"
+)
+
+// SHT4xDefaultAddress is the I2C address of the SHT40-AD1B, SHT41 and SHT45
+const SHT4xDefaultAddress = 0x44
+
+// SHT4x measurement precisions, which are the commands of the measurements
+const (
+	// SHT4xPrecisionLow is the fastest, but least repeatable measurement
+	SHT4xPrecisionLow = 0xe0
+	// SHT4xPrecisionMedium is the medium repeatability and speed measurement
+	SHT4xPrecisionMedium = 0xf6
+	// SHT4xPrecisionHigh is the most repeatable and slowest measurement
+	SHT4xPrecisionHigh = 0xfd
+)
+
+// SHT4x commands
+const (
+	sht4xCmdSerialNumber = 0x89
+	sht4xCmdSoftReset    = 0x94
+)
+
+// SHT4xHeater is a pulse of the heater of the SHT4x, whose power and duration
+// are chosen for the condensation to recover from: the hotter and longer, the
+// more water is evaporated. The heater is meant to be pulsed at most 10% of
+// the time.
+type SHT4xHeater byte
+
+const (
+	// SHT4xHeater200mW1s heats at 200mW for 1s
+	SHT4xHeater200mW1s SHT4xHeater = 0x39
+	// SHT4xHeater200mW100ms heats at 200mW for 0.1s
+	SHT4xHeater200mW100ms SHT4xHeater = 0x32
+	// SHT4xHeater110mW1s heats at 110mW for 1s
+	SHT4xHeater110mW1s SHT4xHeater = 0x2f
+	// SHT4xHeater110mW100ms heats at 110mW for 0.1s
+	SHT4xHeater110mW100ms SHT4xHeater = 0x24
+	// SHT4xHeater20mW1s heats at 20mW for 1s
+	SHT4xHeater20mW1s SHT4xHeater = 0x1e
+	// SHT4xHeater20mW100ms heats at 20mW for 0.1s
+	SHT4xHeater20mW100ms SHT4xHeater = 0x15
+)
+
+// ErrInvalidHeater is returned for an unknown SHT4xHeater pulse
+var ErrInvalidHeater = errors.New("Invalid heater pulse")
+
+// sht4xDelays are the longest durations of the measurements and heater
+// pulses, plus 1ms
+var sht4xDelays = map[byte]time.Duration{
+	SHT4xPrecisionLow:           2 * time.Millisecond,
+	SHT4xPrecisionMedium:        6 * time.Millisecond,
+	SHT4xPrecisionHigh:          10 * time.Millisecond,
+	byte(SHT4xHeater200mW1s):    1101 * time.Millisecond,
+	byte(SHT4xHeater200mW100ms): 111 * time.Millisecond,
+	byte(SHT4xHeater110mW1s):    1101 * time.Millisecond,
+	byte(SHT4xHeater110mW100ms): 111 * time.Millisecond,
+	byte(SHT4xHeater20mW1s):     1101 * time.Millisecond,
+	byte(SHT4xHeater20mW100ms):  111 * time.Millisecond,
+}
+
+// SHT4xDriver is a driver for the SHT4x humidity and temperature sensors,
+// such as the SHT40 and SHT45. Unlike the SHT3x, they measure on request only
+// and their heater is pulsed, each pulse ending with a measurement.
+type SHT4xDriver struct {
+	// Units of the temperatures, "C" or "F"
+	Units string
+
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+	precision byte
+	crcTable  *crc8.Table
+}
+
+// NewSHT4xDriver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//
+func NewSHT4xDriver(c Connector, options ...func(Config)) *SHT4xDriver {
+	d := &SHT4xDriver{
+		Units:     "C",
+		name:      gobot.DefaultName("SHT4x"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		mutex:     &sync.Mutex{},
+		precision: SHT4xPrecisionHigh,
+		crcTable:  crc8.MakeTable(crc8Params),
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddCommand("Sample", func(params map[string]interface{}) interface{} {
+		temp, rh, err := d.Sample()
+		return map[string]interface{}{"temperature": temp, "humidity": rh, "err": err}
+	})
+	d.AddCommand("Heat", func(params map[string]interface{}) interface{} {
+		temp, rh, err := d.Heat(SHT4xHeater(params["heater"].(float64)))
+		return map[string]interface{}{"temperature": temp, "humidity": rh, "err": err}
+	})
+
+	return d
+}
+
+// Name returns the name of the device.
+func (d *SHT4xDriver) Name() string { return d.name }
+
+// SetName sets the name of the device.
+func (d *SHT4xDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection of the device.
+func (d *SHT4xDriver) Connection() gobot.Connection { return d.connector.(gobot.Connection) }
+
+// Start initializes the SHT4x
+func (d *SHT4xDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.connection, err = connect(d, d.connector, d.Config, SHT4xDefaultAddress)
+	return
+}
+
+// Halt does nothing, the SHT4x idling between measurements
+func (d *SHT4xDriver) Halt() (err error) { return }
+
+// Precision returns the precision of the measurements
+func (d *SHT4xDriver) Precision() byte { return d.precision }
+
+// SetPrecision sets the precision of the measurements, one of the
+// SHT4xPrecision
+func (d *SHT4xDriver) SetPrecision(p byte) (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch p {
+	case SHT4xPrecisionLow, SHT4xPrecisionMedium, SHT4xPrecisionHigh:
+		d.precision = p
+		return nil
+	}
+	return ErrInvalidAccuracy
+}
+
+// SerialNumber returns the serial number of the chip
+func (d *SHT4xDriver) SerialNumber() (sn uint32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	ret, err := d.command(sht4xCmdSerialNumber, time.Millisecond, 2)
+	if err != nil {
+		return
+	}
+	return uint32(ret[0])<<16 | uint32(ret[1]), nil
+}
+
+// Reset soft resets the SHT4x
+func (d *SHT4xDriver) Reset() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, err = d.connection.Write([]byte{sht4xCmdSoftReset}); err != nil {
+		return
+	}
+	gobot.DefaultClock().Sleep(time.Millisecond)
+	return
+}
+
+// Sample returns the temperature, in the Units of the driver, and the
+// relative humidity of one measurement.
+func (d *SHT4xDriver) Sample() (temp float32, rh float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.measure(d.precision)
+}
+
+// Temperature returns the temperature, in the Units of the driver, of one
+// measurement
+func (d *SHT4xDriver) Temperature() (temp float32, err error) {
+	temp, _, err = d.Sample()
+	return
+}
+
+// Humidity returns the relative humidity of one measurement
+func (d *SHT4xDriver) Humidity() (rh float32, err error) {
+	_, rh, err = d.Sample()
+	return
+}
+
+// Heat pulses the heater, for example to evaporate the condensation of a
+// humidity stuck near 100%RH, and returns the high precision measurement the
+// SHT4x takes at the end of the pulse. The measurement is skewed by the
+// heat, the humidity being back to normal once the sensor cooled down.
+func (d *SHT4xDriver) Heat(heater SHT4xHeater) (temp float32, rh float32, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch heater {
+	case SHT4xHeater200mW1s, SHT4xHeater200mW100ms, SHT4xHeater110mW1s,
+		SHT4xHeater110mW100ms, SHT4xHeater20mW1s, SHT4xHeater20mW100ms:
+	default:
+		return 0, 0, ErrInvalidHeater
+	}
+	return d.measure(byte(heater))
+}
+
+// measure runs the measurement command and converts its result
+func (d *SHT4xDriver) measure(cmd byte) (temp float32, rh float32, err error) {
+	ret, err := d.command(cmd, sht4xDelays[cmd], 2)
+	if err != nil {
+		return
+	}
+
+	// From the datasheet:
+	// T[C] = -45 + 175 * St / (2^16 - 1)
+	// T[F] = -49 + 315 * St / (2^16 - 1)
+	// RH = -6 + 125 * Srh / (2^16 - 1), cropped to 0-100%
+	switch d.Units {
+	case "C":
+		temp = float32(-45 + 175*float64(ret[0])/0xffff)
+	case "F":
+		temp = float32(-49 + 315*float64(ret[0])/0xffff)
+	default:
+		return 0, 0, ErrInvalidTemp
+	}
+	humidity := -6 + 125*float64(ret[1])/0xffff
+	if humidity < 0 {
+		humidity = 0
+	}
+	if humidity > 100 {
+		humidity = 100
+	}
+	return temp, float32(humidity), nil
+}
+
+// command writes cmd, waits for delay and reads expect words
+func (d *SHT4xDriver) command(cmd byte, delay time.Duration, expect int) ([]uint16, error) {
+	if _, err := d.connection.Write([]byte{cmd}); err != nil {
+		return nil, err
+	}
+	gobot.DefaultClock().Sleep(delay)
+
+	buf := make([]byte, 3*expect)
+	n, err := d.connection.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(buf) {
+		return nil, ErrNotEnoughBytes
+	}
+	return sensirionWords(buf, d.crcTable)
+}
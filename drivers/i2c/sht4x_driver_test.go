@@ -0,0 +1,155 @@
+package i2c
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*SHT4xDriver)(nil)
+var _ gobot.TemperatureSensor = (*SHT4xDriver)(nil)
+var _ gobot.HumiditySensor = (*SHT4xDriver)(nil)
+
+func initTestSHT4xDriverWithStubbedAdaptor() (*SHT4xDriver, *i2cTestAdaptor) {
+	adaptor := newI2cTestAdaptor()
+	d := NewSHT4xDriver(adaptor)
+	d.Start()
+	return d, adaptor
+}
+
+func TestNewSHT4xDriver(t *testing.T) {
+	d := NewSHT4xDriver(newI2cTestAdaptor())
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "SHT4x"), true)
+	gobottest.Assert(t, d.Precision(), byte(SHT4xPrecisionHigh))
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Refute(t, d.Command("Sample"), nil)
+	gobottest.Refute(t, d.Command("Heat"), nil)
+
+	d.SetName("Sensor")
+	gobottest.Assert(t, d.Name(), "Sensor")
+}
+
+func TestSHT4xDriverStart(t *testing.T) {
+	adaptor := newI2cTestAdaptor()
+	d := NewSHT4xDriver(adaptor)
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+
+	adaptor.Testi2cConnectErr(true)
+	gobottest.Assert(t, NewSHT4xDriver(adaptor).Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestSHT4xDriverSample(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x66, 0x66, 0x93, 0x80, 0x00, 0xa2}), nil
+	}
+
+	temp, rh, err := d.Sample()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, temp, float32(25))
+	gobottest.Assert(t, rh, float32(56.500954))
+	gobottest.Assert(t, adaptor.written, []byte{SHT4xPrecisionHigh})
+
+	d.Units = "F"
+	temp, err = d.Temperature()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, temp, float32(77))
+
+	d.Units = "K"
+	_, err = d.Humidity()
+	gobottest.Assert(t, err, ErrInvalidTemp)
+}
+
+func TestSHT4xDriverSampleHumidityCropped(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x66, 0x66, 0x93, 0x00, 0x00, 0x81}), nil
+	}
+	rh, err := d.Humidity()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, rh, float32(0))
+}
+
+func TestSHT4xDriverSampleErrors(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x66, 0x66, 0x93, 0x80, 0x00, 0x00}), nil
+	}
+	_, _, err := d.Sample()
+	gobottest.Assert(t, err, ErrInvalidCrc)
+
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return 3, nil
+	}
+	_, _, err = d.Sample()
+	gobottest.Assert(t, err, ErrNotEnoughBytes)
+
+	adaptor.i2cWriteImpl = func([]byte) (int, error) {
+		return 0, errors.New("write error")
+	}
+	_, _, err = d.Sample()
+	gobottest.Assert(t, err, errors.New("write error"))
+}
+
+func TestSHT4xDriverSetPrecision(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.SetPrecision(0x42), ErrInvalidAccuracy)
+	gobottest.Assert(t, d.SetPrecision(SHT4xPrecisionLow), nil)
+	gobottest.Assert(t, d.Precision(), byte(SHT4xPrecisionLow))
+
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x66, 0x66, 0x93, 0x80, 0x00, 0xa2}), nil
+	}
+	d.Sample()
+	gobottest.Assert(t, adaptor.written, []byte{SHT4xPrecisionLow})
+}
+
+func TestSHT4xDriverHeat(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	_, _, err := d.Heat(SHT4xHeater(SHT4xPrecisionHigh))
+	gobottest.Assert(t, err, ErrInvalidHeater)
+
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x66, 0x66, 0x93, 0x80, 0x00, 0xa2}), nil
+	}
+
+	done := make(chan error)
+	go func() {
+		_, _, err := d.Heat(SHT4xHeater200mW1s)
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	select {
+	case <-done:
+		t.Errorf("Heat returned before the end of the pulse")
+	default:
+	}
+	clock.Advance(1101 * time.Millisecond)
+	gobottest.Assert(t, <-done, nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x39})
+}
+
+func TestSHT4xDriverSerialNumber(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, []byte{0x12, 0x34, 0x37, 0x66, 0x66, 0x93}), nil
+	}
+	sn, err := d.SerialNumber()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, sn, uint32(0x12346666))
+	gobottest.Assert(t, adaptor.written, []byte{0x89})
+}
+
+func TestSHT4xDriverReset(t *testing.T) {
+	d, adaptor := initTestSHT4xDriverWithStubbedAdaptor()
+	gobottest.Assert(t, d.Reset(), nil)
+	gobottest.Assert(t, adaptor.written, []byte{0x94})
+}
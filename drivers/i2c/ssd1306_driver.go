@@ -1,7 +1,10 @@
 package i2c
 
 import (
+	"sync"
+
 	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/font"
 )
 
 const ssd1306I2CAddress = 0x3c
@@ -114,7 +117,9 @@ type SSD1306Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
 	gobot.Commander
 
 	DisplayWidth  int
@@ -139,10 +144,12 @@ func NewSSD1306Driver(a Connector, options ...func(Config)) *SSD1306Driver {
 		Commander:     gobot.NewCommander(),
 		connector:     a,
 		Config:        NewConfig(),
+		mutex:         &sync.Mutex{},
 		DisplayHeight: ssd1306Height,
 		DisplayWidth:  ssd1306Width,
 	}
 
+	s.driverConfig = driverConfig{config: &s.Config}
 	for _, option := range options {
 		option(s)
 	}
@@ -198,16 +205,16 @@ func (s *SSD1306Driver) Connection() gobot.Connection { return s.connector.(gobo
 
 // Start starts the Driver up, and writes start command
 func (s *SSD1306Driver) Start() (err error) {
-	bus := s.GetBusOrDefault(s.connector.GetDefaultBus())
-	address := s.GetAddressOrDefault(ssd1306I2CAddress)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	s.connection, err = s.connector.GetConnection(address, bus)
+	s.connection, err = connect(s, s.connector, s.Config, ssd1306I2CAddress)
 	if err != nil {
 		return
 	}
 
-	s.Init()
-	s.On()
+	s.init()
+	s.command(ssd1306SetDisplayOn)
 
 	return
 }
@@ -243,7 +250,14 @@ func WithDisplayHeight(val int) func(Config) {
 
 // Init turns display on
 func (s *SSD1306Driver) Init() (err error) {
-	s.Off()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.init()
+}
+
+func (s *SSD1306Driver) init() (err error) {
+	s.command(ssd1306SetDisplayOff)
 	s.commands(ssd1306InitSequence)
 
 	s.commands([]byte{ssd1306ColumnAddr, 0, // Start at 0,
@@ -259,42 +273,100 @@ func (s *SSD1306Driver) Init() (err error) {
 
 // On turns display on
 func (s *SSD1306Driver) On() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	return s.command(ssd1306SetDisplayOn)
 }
 
 // Off turns display off
 func (s *SSD1306Driver) Off() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	return s.command(ssd1306SetDisplayOff)
 }
 
 // Clear clears
 func (s *SSD1306Driver) Clear() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.Buffer.Clear()
 	return nil
 }
 
 // Set sets a pixel
 func (s *SSD1306Driver) Set(x, y, c int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.Buffer.Set(x, y, c)
 }
 
 // Reset sends the memory buffer to the display
 func (s *SSD1306Driver) Reset() (err error) {
-	s.Off()
-	s.Clear()
-	s.On()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.command(ssd1306SetDisplayOff)
+	s.Buffer.Clear()
+	s.command(ssd1306SetDisplayOn)
 	return nil
 }
 
 // SetContrast sets the display contrast
 func (s *SSD1306Driver) SetContrast(contrast byte) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	err = s.commands([]byte{ssd1306SetContrast, contrast})
 	return
 }
 
 // Display sends the memory buffer to the display
 func (s *SSD1306Driver) Display() (err error) {
-	// Write the buffer
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.display()
+}
+
+// TextSize implements the Displayer interface and returns the number of
+// columns and rows of characters of 5x7 pixels, with a pixel of spacing,
+// fitting on the display
+func (s *SSD1306Driver) TextSize() (cols int, rows int) {
+	return s.DisplayWidth / 6, s.DisplayHeight / ssd1306PageSize
+}
+
+// WriteLine implements the Displayer interface, drawing text on row in the
+// memory buffer, truncated or padded with spaces to the width of the display,
+// then sending the buffer to the display
+func (s *SSD1306Driver) WriteLine(row int, text string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cols, rows := s.TextSize()
+	if row < 0 || row >= rows {
+		return ErrInvalidPosition
+	}
+	line := s.Buffer.buffer[row*s.Buffer.Width : (row+1)*s.Buffer.Width]
+	for i := range line {
+		line[i] = 0
+	}
+	x := 0
+	for _, r := range text {
+		if x >= cols*6 {
+			break
+		}
+		copy(line[x:], font.Fixed5x7.Glyph(r))
+		x += 6
+	}
+	return s.display()
+}
+
+// display writes the buffer, the mutex being held
+func (s *SSD1306Driver) display() (err error) {
 	_, err = s.connection.Write(append([]byte{0x40}, s.Buffer.buffer...))
 	return err
 }
@@ -12,6 +12,8 @@ import (
 )
 
 var _ gobot.Driver = (*SSD1306Driver)(nil)
+var _ gobot.Display = (*SSD1306Driver)(nil)
+var _ Displayer = (*SSD1306Driver)(nil)
 
 func TestDisplayBuffer(t *testing.T) {
 
@@ -112,6 +114,33 @@ func TestSSD1306DriverDisplay(t *testing.T) {
 	gobottest.Assert(t, s.Display(), nil)
 }
 
+func TestSSD1306DriverWriteLine(t *testing.T) {
+	s, a := initTestSSD1306DriverWithStubbedAdaptor()
+	s.Start()
+	cols, rows := s.TextSize()
+	gobottest.Assert(t, cols, 21)
+	gobottest.Assert(t, rows, 8)
+
+	s.Set(0, 8, 1)
+	a.written = []byte{}
+	gobottest.Assert(t, s.WriteLine(1, "A!\x01"), nil)
+	line := s.Buffer.buffer[128:256]
+	gobottest.Assert(t, line[:18], []byte{
+		0x7E, 0x11, 0x11, 0x11, 0x7E, 0x00,
+		0x00, 0x00, 0x5F, 0x00, 0x00, 0x00,
+		0x02, 0x01, 0x51, 0x09, 0x06, 0x00,
+	})
+	gobottest.Assert(t, line[18:], make([]byte, 110))
+	gobottest.Assert(t, a.written[0], byte(0x40))
+	gobottest.Assert(t, a.written[1:], s.Buffer.buffer)
+
+	// long lines are truncated
+	gobottest.Assert(t, s.WriteLine(7, strings.Repeat("-", 30)), nil)
+	gobottest.Assert(t, s.Buffer.buffer[7*128+124], byte(0x08))
+	gobottest.Assert(t, s.Buffer.buffer[7*128+125:8*128], []byte{0, 0, 0})
+	gobottest.Assert(t, s.WriteLine(8, "ok"), ErrInvalidPosition)
+}
+
 func TestSSD1306DriverCommand(t *testing.T) {
 	s, adaptor := initTestSSD1306DriverWithStubbedAdaptor()
 	s.Start()
@@ -0,0 +1,338 @@
+package i2c
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Distance event is published by the TFMiniDriver with each TFMiniReading
+const Distance = "distance"
+
+const tfminiAddress = 0x10
+
+// TFMiniModel is a model of the Benewake TFMini family of lidars, which
+// differ in the unit of their temperature and in their I2C protocol
+type TFMiniModel int
+
+const (
+	// TFMiniPlus is a TFMini Plus or TFMini-S, the temperature being in 1/8
+	// celsius degrees above -256
+	TFMiniPlus TFMiniModel = iota
+	// TFLuna is a TF-Luna, the temperature being in 1/100 celsius degrees,
+	// whose I2C interface is a bank of registers
+	TFLuna
+)
+
+// TFMini frames
+const (
+	tfminiFrameHeader    = 0x59
+	tfminiFrameLength    = 9
+	tfminiCommandHeader  = 0x5A
+	tfminiCmdObtainFrame = 0x00
+	tfminiCmdFrameRate   = 0x03
+	tfminiCmdSave        = 0x11
+	// tfminiFormatCentimeters is the output format of the obtained frames
+	tfminiFormatCentimeters = 0x01
+)
+
+// TF-Luna registers
+const (
+	tflunaRegisterDistLow   = 0x00
+	tflunaRegisterSave      = 0x20
+	tflunaRegisterFrameRate = 0x26
+)
+
+// ErrTFMiniInvalidFrame is returned when a TFMini frame has a bad header or
+// checksum
+var ErrTFMiniInvalidFrame = errors.New("invalid TFMini frame")
+
+// TFMiniReading is a measurement of a TFMini lidar, the data published with
+// the Distance event
+type TFMiniReading struct {
+	// Distance in centimeters
+	Distance int
+	// Strength of the signal, the Distance being unreliable below 100 and
+	// when the sensor is saturated at 65535
+	Strength int
+	// Temperature of the sensor, in celsius degrees
+	Temperature float32
+}
+
+// ParseTFMiniFrame decodes a 9 bytes data frame of a TFMini lidar of the
+// model, as sent on its serial port
+func ParseTFMiniFrame(frame []byte, model TFMiniModel) (TFMiniReading, error) {
+	if len(frame) != tfminiFrameLength || frame[0] != tfminiFrameHeader || frame[1] != tfminiFrameHeader {
+		return TFMiniReading{}, ErrTFMiniInvalidFrame
+	}
+	if tfminiChecksum(frame[:8]) != frame[8] {
+		return TFMiniReading{}, ErrTFMiniInvalidFrame
+	}
+	return tfminiReading(frame[2:8], model), nil
+}
+
+// TFMiniCommand returns the command frame of the id with its payload, ended
+// by its checksum
+func TFMiniCommand(id byte, payload ...byte) []byte {
+	cmd := append([]byte{tfminiCommandHeader, byte(len(payload) + 4), id}, payload...)
+	return append(cmd, tfminiChecksum(cmd))
+}
+
+// TFMiniFrameRateCommand returns the command setting the frame rate of a
+// TFMini lidar, in Hz, 0 stopping the output of frames
+func TFMiniFrameRateCommand(hz uint16) []byte {
+	return TFMiniCommand(tfminiCmdFrameRate, byte(hz), byte(hz>>8))
+}
+
+// TFMiniSaveCommand returns the command saving the settings of a TFMini lidar
+func TFMiniSaveCommand() []byte {
+	return TFMiniCommand(tfminiCmdSave)
+}
+
+func tfminiChecksum(b []byte) byte {
+	var sum byte
+	for _, v := range b {
+		sum += v
+	}
+	return sum
+}
+
+// tfminiReading decodes the distance, strength and temperature, each one
+// a little endian 16 bits word
+func tfminiReading(b []byte, model TFMiniModel) TFMiniReading {
+	r := TFMiniReading{
+		Distance: int(b[0]) | int(b[1])<<8,
+		Strength: int(b[2]) | int(b[3])<<8,
+	}
+	temp := int(b[4]) | int(b[5])<<8
+	if model == TFLuna {
+		r.Temperature = float32(int16(temp)) / 100
+	} else {
+		r.Temperature = float32(temp)/8 - 256
+	}
+	return r
+}
+
+// TFMiniDriver is a driver for the Benewake TFMini Plus, TFMini-S and TF-Luna
+// lidars in I2C mode, measuring distances up to 8 to 12 meters. Once started,
+// it polls the lidar and publishes its readings with the Distance event.
+type TFMiniDriver struct {
+	name       string
+	connector  Connector
+	connection Connection
+	mutex      *sync.Mutex
+	Config
+	driverConfig
+	gobot.Commander
+	gobot.Eventer
+
+	model     TFMiniModel
+	frameRate uint16
+	interval  time.Duration
+	polling   gobot.PollerConfig
+	halt      chan bool
+}
+
+// NewTFMiniDriver creates a new driver with specified i2c interface.
+// Params:
+//		conn Connector - the Adaptor to use with this Driver
+//
+// Optional params:
+//		i2c.WithBus(int):	bus to use with this driver
+//		i2c.WithAddress(int):	address to use with this driver
+//		i2c.WithTFMiniModel(TFMiniModel):	model of the lidar, TFMiniPlus by default
+//		i2c.WithTFMiniFrameRate(uint16):	frame rate set at start, in Hz
+//
+func NewTFMiniDriver(c Connector, options ...func(Config)) *TFMiniDriver {
+	d := &TFMiniDriver{
+		name:      gobot.DefaultName("TFMini"),
+		connector: c,
+		Config:    NewConfig(),
+		Commander: gobot.NewCommander(),
+		Eventer:   gobot.NewEventer(),
+		mutex:     &sync.Mutex{},
+		model:     TFMiniPlus,
+		interval:  100 * time.Millisecond,
+	}
+
+	d.driverConfig = driverConfig{config: &d.Config}
+	for _, option := range options {
+		option(d)
+	}
+
+	d.AddEvent(Distance)
+	d.AddEvent(Error)
+
+	d.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		r, err := d.Read()
+		return map[string]interface{}{
+			"distance": r.Distance, "strength": r.Strength, "temperature": r.Temperature, "err": err,
+		}
+	})
+	d.AddCommand("SetFrameRate", func(params map[string]interface{}) interface{} {
+		return d.SetFrameRate(uint16(params["rate"].(float64)))
+	})
+
+	return d
+}
+
+// WithTFMiniModel option sets the model of the lidar, TFMiniPlus by default.
+func WithTFMiniModel(model TFMiniModel) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*TFMiniDriver); ok {
+			d.model = model
+		}
+	}
+}
+
+// WithTFMiniFrameRate option sets the frame rate of the lidar at start, in
+// Hz. The lidar keeps its own, 100Hz by default, otherwise.
+func WithTFMiniFrameRate(hz uint16) func(Config) {
+	return func(c Config) {
+		if d, ok := c.(*TFMiniDriver); ok {
+			d.frameRate = hz
+		}
+	}
+}
+
+// Name returns the name of the device.
+func (d *TFMiniDriver) Name() string {
+	return d.name
+}
+
+// SetName sets the name of the device.
+func (d *TFMiniDriver) SetName(n string) {
+	d.name = n
+}
+
+// Connection returns the connection of the device.
+func (d *TFMiniDriver) Connection() gobot.Connection {
+	return d.connector.(gobot.Connection)
+}
+
+// Start sets the frame rate of the lidar, when configured, and starts the
+// polling of its readings.
+func (d *TFMiniDriver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.connection, err = connect(d, d.connector, d.Config, tfminiAddress); err != nil {
+		return err
+	}
+	if d.frameRate > 0 {
+		if err = d.setFrameRate(d.frameRate); err != nil {
+			return err
+		}
+	}
+
+	config := d.polling
+	config.Interval = d.interval
+	config.MaxInterval = 0
+	if config.Scheduler == nil {
+		config.Scheduler = BusScheduler(d.connector, d.GetBusOrDefault(d.connector.GetDefaultBus()))
+	}
+	d.halt = make(chan bool)
+	gobot.NewPoller(config).Go(d, d.halt, func() bool {
+		r, err := d.Read()
+		if err != nil {
+			d.Publish(d.Event(Error), err)
+			return false
+		}
+		d.Publish(d.Event(Distance), r)
+		return true
+	})
+	return nil
+}
+
+// Halt stops the polling of the readings.
+func (d *TFMiniDriver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.halt != nil {
+		close(d.halt)
+		d.halt = nil
+	}
+	return nil
+}
+
+// SetPolling configures the polling of the readings, every 100ms by
+// default. The lidar is polled at a fixed interval, c.MaxInterval is ignored.
+func (d *TFMiniDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		d.interval = c.Interval
+	}
+	d.polling = c
+}
+
+// Read returns the latest measurement of the lidar.
+func (d *TFMiniDriver) Read() (TFMiniReading, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.model == TFLuna {
+		if _, err := d.connection.Write([]byte{tflunaRegisterDistLow}); err != nil {
+			return TFMiniReading{}, err
+		}
+		b := make([]byte, 6)
+		n, err := d.connection.Read(b)
+		if err != nil {
+			return TFMiniReading{}, ErrRegisterRead{Reg: tflunaRegisterDistLow, Err: err}
+		}
+		if n != len(b) {
+			return TFMiniReading{}, ErrRegisterRead{Reg: tflunaRegisterDistLow, Err: ErrNotEnoughBytes}
+		}
+		return tfminiReading(b, d.model), nil
+	}
+
+	if _, err := d.connection.Write(TFMiniCommand(tfminiCmdObtainFrame, tfminiFormatCentimeters)); err != nil {
+		return TFMiniReading{}, err
+	}
+	frame := make([]byte, tfminiFrameLength)
+	n, err := d.connection.Read(frame)
+	if err != nil {
+		return TFMiniReading{}, err
+	}
+	if n != tfminiFrameLength {
+		return TFMiniReading{}, ErrNotEnoughBytes
+	}
+	return ParseTFMiniFrame(frame, d.model)
+}
+
+// Distance returns the current distance in cm
+func (d *TFMiniDriver) Distance() (distance int, err error) {
+	r, err := d.Read()
+	return r.Distance, err
+}
+
+// SetFrameRate sets the number of measurements per second of the lidar, in
+// Hz. The readings polled are the latest measurement.
+func (d *TFMiniDriver) SetFrameRate(hz uint16) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.setFrameRate(hz)
+}
+
+// SaveSettings saves the settings of the lidar, such as its frame rate, so
+// that they survive a power cycle.
+func (d *TFMiniDriver) SaveSettings() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.model == TFLuna {
+		return d.connection.WriteByteData(tflunaRegisterSave, 0x01)
+	}
+	_, err := d.connection.Write(TFMiniSaveCommand())
+	return err
+}
+
+func (d *TFMiniDriver) setFrameRate(hz uint16) error {
+	if d.model == TFLuna {
+		return d.connection.WriteBlockData(tflunaRegisterFrameRate, []byte{byte(hz), byte(hz >> 8)})
+	}
+	_, err := d.connection.Write(TFMiniFrameRateCommand(hz))
+	return err
+}
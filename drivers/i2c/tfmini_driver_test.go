@@ -0,0 +1,134 @@
+package i2c
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*TFMiniDriver)(nil)
+
+// tfminiFrame is a TFMini Plus frame of 100cm, a strength of 1000 and 25
+// celsius degrees
+var tfminiFrame = []byte{0x59, 0x59, 0x64, 0x00, 0xE8, 0x03, 0xC8, 0x08, 0xD1}
+
+func initTestTFMiniDriverWithStubbedAdaptor(options ...func(Config)) (*TFMiniDriver, *i2cTestAdaptor) {
+	adaptor := newI2cTestAdaptor()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		return copy(b, tfminiFrame), nil
+	}
+	return NewTFMiniDriver(adaptor, options...), adaptor
+}
+
+func TestTFMiniDriverName(t *testing.T) {
+	d, _ := initTestTFMiniDriverWithStubbedAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "TFMini"), true)
+	d.SetName("lidar")
+	gobottest.Assert(t, d.Name(), "lidar")
+	gobottest.Refute(t, d.Connection(), nil)
+}
+
+func TestParseTFMiniFrame(t *testing.T) {
+	r, err := ParseTFMiniFrame(tfminiFrame, TFMiniPlus)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r, TFMiniReading{Distance: 100, Strength: 1000, Temperature: 25})
+
+	bad := append([]byte{}, tfminiFrame...)
+	bad[8]++
+	_, err = ParseTFMiniFrame(bad, TFMiniPlus)
+	gobottest.Assert(t, err, ErrTFMiniInvalidFrame)
+	_, err = ParseTFMiniFrame(tfminiFrame[1:], TFMiniPlus)
+	gobottest.Assert(t, err, ErrTFMiniInvalidFrame)
+
+	gobottest.Assert(t, TFMiniFrameRateCommand(10), []byte{0x5A, 0x06, 0x03, 0x0A, 0x00, 0x6D})
+	gobottest.Assert(t, TFMiniCommand(0x02), []byte{0x5A, 0x04, 0x02, 0x60})
+}
+
+func TestTFMiniDriverStart(t *testing.T) {
+	d, adaptor := initTestTFMiniDriverWithStubbedAdaptor(WithTFMiniFrameRate(10))
+	readings := make(chan interface{}, 1)
+	d.Once(Distance, func(data interface{}) { readings <- data })
+	d.SetPolling(gobot.PollerConfig{Interval: time.Millisecond})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	select {
+	case data := <-readings:
+		gobottest.Assert(t, data, TFMiniReading{Distance: 100, Strength: 1000, Temperature: 25})
+	case <-time.After(time.Second):
+		t.Errorf("distance was not published")
+	}
+	// frame rate, then obtain frame in centimeters
+	gobottest.Assert(t, bytes.HasPrefix(adaptor.written, []byte{
+		0x5A, 0x06, 0x03, 0x0A, 0x00, 0x6D,
+		0x5A, 0x05, 0x00, 0x01, 0x60,
+	}), true)
+}
+
+func TestTFMiniDriverStartConnectError(t *testing.T) {
+	d, adaptor := initTestTFMiniDriverWithStubbedAdaptor()
+	adaptor.Testi2cConnectErr(true)
+	gobottest.Assert(t, d.Start(), errors.New("Invalid i2c connection"))
+}
+
+func TestTFMiniDriverRead(t *testing.T) {
+	d, adaptor := initTestTFMiniDriverWithStubbedAdaptor()
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	d.Start()
+	defer d.Halt()
+
+	distance, err := d.Distance()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, distance, 100)
+
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return copy(b, tfminiFrame[:4]), nil
+	})
+	_, err = d.Read()
+	gobottest.Assert(t, err, ErrNotEnoughBytes)
+
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		return 0, errors.New("read error")
+	})
+	_, err = d.Read()
+	gobottest.Assert(t, err, errors.New("read error"))
+}
+
+func TestTFMiniDriverTFLuna(t *testing.T) {
+	d, adaptor := initTestTFMiniDriverWithStubbedAdaptor(WithTFMiniModel(TFLuna))
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		// 250cm, strength 300 and 41.5 celsius degrees
+		return copy(b, []byte{0xFA, 0x00, 0x2C, 0x01, 0x36, 0x10}), nil
+	})
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	d.Start()
+	defer d.Halt()
+
+	r, err := d.Read()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, r, TFMiniReading{Distance: 250, Strength: 300, Temperature: 41.5})
+
+	adaptor.written = nil
+	gobottest.Assert(t, d.SetFrameRate(250), nil)
+	gobottest.Assert(t, d.SaveSettings(), nil)
+	gobottest.Assert(t, adaptor.written, []byte{tflunaRegisterFrameRate, 0xFA, 0x00, tflunaRegisterSave, 0x01})
+}
+
+func TestTFMiniDriverCommands(t *testing.T) {
+	d, adaptor := initTestTFMiniDriverWithStubbedAdaptor()
+	d.SetPolling(gobot.PollerConfig{Interval: time.Hour})
+	d.Start()
+	defer d.Halt()
+
+	result := d.Command("Read")(map[string]interface{}{}).(map[string]interface{})
+	gobottest.Assert(t, result["distance"], 100)
+	adaptor.written = nil
+	gobottest.Assert(t, d.Command("SetFrameRate")(map[string]interface{}{"rate": 10.0}), nil)
+	gobottest.Assert(t, adaptor.written, TFMiniFrameRateCommand(10))
+	gobottest.Assert(t, d.SaveSettings(), nil)
+}
@@ -1,7 +1,7 @@
 package i2c
 
 import (
-	"fmt"
+	"sync"
 	"time"
 
 	"gobot.io/x/gobot"
@@ -107,6 +107,26 @@ const (
 	TSL2561Gain16X = 0x10 // 16x gain
 )
 
+// tsl2561Registers are the registers shown by DebugDump
+var tsl2561Registers = RegisterMap{
+	{Name: "CONTROL", Address: tsl2561CommandBit | tsl2561RegisterControl, Fields: []RegisterField{
+		{Name: "POWER", Shift: 0, Width: 2, Values: map[uint8]string{tsl2561ControlPowerOff: "off", tsl2561ControlPowerOn: "on"}},
+	}},
+	{Name: "TIMING", Address: tsl2561CommandBit | tsl2561RegisterTiming, Fields: []RegisterField{
+		{Name: "GAIN", Shift: 4, Width: 1, Values: map[uint8]string{0: "1x", 1: "16x"}},
+		{Name: "MANUAL", Shift: 3, Width: 1},
+		{Name: "INTEG", Shift: 0, Width: 2, Values: map[uint8]string{0: "13.7ms", 1: "101ms", 2: "402ms", 3: "manual"}},
+	}},
+	{Name: "INTERRUPT", Address: tsl2561CommandBit | tsl2561RegisterInterrupt, Fields: []RegisterField{
+		{Name: "INTR", Shift: 4, Width: 2},
+		{Name: "PERSIST", Shift: 0, Width: 4},
+	}},
+	{Name: "ID", Address: tsl2561CommandBit | tsl2561RegisterID, Fields: []RegisterField{
+		{Name: "PARTNO", Shift: 4, Width: 4},
+		{Name: "REVNO", Shift: 0, Width: 4},
+	}},
+}
+
 // TSL2561Driver is the gobot driver for the Adafruit Digital Luminosity/Lux/Light Sensor
 //
 // Datasheet: http://www.adafruit.com/datasheets/TSL2561.pdf
@@ -117,7 +137,10 @@ type TSL2561Driver struct {
 	name       string
 	connector  Connector
 	connection Connection
+	mutex      *sync.Mutex
 	Config
+	driverConfig
+	gobot.Commander
 	autoGain        bool
 	gain            TSL2561Gain
 	integrationTime TSL2561IntegrationTime
@@ -143,15 +166,20 @@ func NewTSL2561Driver(conn Connector, options ...func(Config)) *TSL2561Driver {
 		name:            gobot.DefaultName("TSL2561"),
 		connector:       conn,
 		Config:          NewConfig(),
+		Commander:       gobot.NewCommander(),
+		mutex:           &sync.Mutex{},
 		integrationTime: TSL2561IntegrationTime402MS,
 		gain:            TSL2561Gain1X,
 		autoGain:        false,
 	}
 
+	driver.driverConfig = driverConfig{config: &driver.Config}
 	for _, option := range options {
 		option(driver)
 	}
 
+	addDebugDumpCommand(driver, driver)
+
 	return driver
 }
 
@@ -231,10 +259,10 @@ func (d *TSL2561Driver) Connection() gobot.Connection {
 
 // Start initializes the device.
 func (d *TSL2561Driver) Start() (err error) {
-	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(TSL2561AddressFloat)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	if d.connection, err = d.connector.GetConnection(address, bus); err != nil {
+	if d.connection, err = connect(d, d.connector, d.Config, TSL2561AddressFloat); err != nil {
 		return err
 	}
 
@@ -246,14 +274,14 @@ func (d *TSL2561Driver) Start() (err error) {
 	if initialized, err = d.connection.ReadByteData(tsl2561RegisterID); err != nil {
 		return err
 	} else if (initialized & 0x0A) == 0 {
-		return fmt.Errorf("TSL2561 device not found (0x%X)", initialized)
+		return ErrBadDevice{Device: "TSL2561", ID: int(initialized)}
 	}
 
-	if err = d.SetIntegrationTime(d.integrationTime); err != nil {
+	if err = d.setIntegrationTime(d.integrationTime); err != nil {
 		return err
 	}
 
-	if err = d.SetGain(d.gain); err != nil {
+	if err = d.setGain(d.gain); err != nil {
 		return err
 	}
 
@@ -269,8 +297,23 @@ func (d *TSL2561Driver) Halt() error {
 	return nil
 }
 
+// DebugDump reads the control, timing, interrupt and id registers through the command bit.
+func (d *TSL2561Driver) DebugDump() (RegisterDump, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return DumpRegisters(d.connection, tsl2561Registers)
+}
+
 // SetIntegrationTime sets integrations time for the TSL2561
 func (d *TSL2561Driver) SetIntegrationTime(time TSL2561IntegrationTime) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.setIntegrationTime(time)
+}
+
+func (d *TSL2561Driver) setIntegrationTime(time TSL2561IntegrationTime) error {
 	if err := d.enable(); err != nil {
 		return err
 	}
@@ -286,6 +329,13 @@ func (d *TSL2561Driver) SetIntegrationTime(time TSL2561IntegrationTime) error {
 
 // SetGain adjusts the TSL2561 gain (sensitivity to light)
 func (d *TSL2561Driver) SetGain(gain TSL2561Gain) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.setGain(gain)
+}
+
+func (d *TSL2561Driver) setGain(gain TSL2561Gain) error {
 	if err := d.enable(); err != nil {
 		return err
 	}
@@ -302,6 +352,9 @@ func (d *TSL2561Driver) SetGain(gain TSL2561Gain) error {
 // GetLuminocity gets the broadband and IR only values from the TSL2561,
 // adjusting gain if auto-gain is enabled
 func (d *TSL2561Driver) GetLuminocity() (broadband uint16, ir uint16, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	// if auto gain disabled get a single reading and continue
 	if !d.autoGain {
 		broadband, ir, err = d.getData()
@@ -323,14 +376,14 @@ func (d *TSL2561Driver) GetLuminocity() (broadband uint16, ir uint16, err error)
 		if !agcCheck {
 			if (broadband < lo) && (d.gain == TSL2561Gain1X) {
 				// increase gain and try again
-				err = d.SetGain(TSL2561Gain16X)
+				err = d.setGain(TSL2561Gain16X)
 				if err != nil {
 					return
 				}
 				agcCheck = true
 			} else if (broadband > hi) && (d.gain == TSL2561Gain16X) {
 				// drop gain and try again
-				err = d.SetGain(TSL2561Gain1X)
+				err = d.setGain(TSL2561Gain1X)
 				if err != nil {
 					return
 				}
@@ -358,6 +411,9 @@ func (d *TSL2561Driver) GetLuminocity() (broadband uint16, ir uint16, err error)
 // CalculateLux converts raw sensor values to the standard SI Lux equivalent.
 // Returns 65536 if the sensor is saturated.
 func (d *TSL2561Driver) CalculateLux(broadband uint16, ir uint16) (lux uint32) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	var channel1 uint32
 	var channel0 uint32
 
@@ -56,7 +56,9 @@ func TestTSL2561DriverStartNotFound(t *testing.T) {
 		copy(b, buf.Bytes())
 		return buf.Len(), nil
 	}
-	gobottest.Assert(t, d.Start(), errors.New("TSL2561 device not found (0x1)"))
+	err := d.Start()
+	gobottest.Assert(t, err, ErrBadDevice{Device: "TSL2561", ID: 0x01})
+	gobottest.Assert(t, err.Error(), "TSL2561 device not found, unexpected ID 0x01")
 }
 
 func TestTSL2561DriverHalt(t *testing.T) {
@@ -346,3 +348,20 @@ func TestTSL2561getBM(t *testing.T) {
 	gobottest.Assert(t, b, uint32(tsl2561LuxB8T))
 	gobottest.Assert(t, m, uint32(tsl2561LuxM8T))
 }
+
+func TestTSL2561DriverDebugDump(t *testing.T) {
+	d, adaptor := initTestTSL2561Driver()
+	adaptor.i2cReadImpl = idReader
+	d.Start()
+	adaptor.i2cReadImpl = func(b []byte) (int, error) {
+		b[0] = 0x12
+		return len(b), nil
+	}
+
+	dump, err := d.DebugDump()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, dump.Map()["TIMING"].(map[string]interface{})["INTEG"], "402ms")
+
+	result := d.Command("DebugDump")(map[string]interface{}{})
+	gobottest.Assert(t, result, dump.Map())
+}
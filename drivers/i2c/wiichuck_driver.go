@@ -1,6 +1,7 @@
 package i2c
 
 import (
+	"bytes"
 	"sync"
 	"time"
 
@@ -25,8 +26,11 @@ type WiichuckDriver struct {
 	connector  Connector
 	connection Connection
 	Config
+	driverConfig
 	interval  time.Duration
+	polling   gobot.PollerConfig
 	pauseTime time.Duration
+	halt      chan bool
 	gobot.Eventer
 	mtx      sync.Mutex
 	joystick map[string]float64
@@ -62,6 +66,7 @@ func NewWiichuckDriver(a Connector, options ...func(Config)) *WiichuckDriver {
 		},
 	}
 
+	w.driverConfig = driverConfig{config: &w.Config}
 	for _, option := range options {
 		option(w)
 	}
@@ -86,46 +91,72 @@ func (w *WiichuckDriver) Connection() gobot.Connection { return w.connector.(gob
 // Start initilizes i2c and reads from adaptor
 // using specified interval to update with new value
 func (w *WiichuckDriver) Start() (err error) {
-	bus := w.GetBusOrDefault(w.connector.GetDefaultBus())
-	address := w.GetAddressOrDefault(wiichuckAddress)
-
-	w.connection, err = w.connector.GetConnection(address, bus)
+	w.connection, err = connect(w, w.connector, w.Config, wiichuckAddress)
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		for {
-			if _, err := w.connection.Write([]byte{0x40, 0x00}); err != nil {
-				w.Publish(w.Event(Error), err)
-				continue
-			}
-			time.Sleep(w.pauseTime)
-			if _, err := w.connection.Write([]byte{0x00}); err != nil {
-				w.Publish(w.Event(Error), err)
-				continue
-			}
-			time.Sleep(w.pauseTime)
-			newValue := make([]byte, 6)
-			bytesRead, err := w.connection.Read(newValue)
-			if err != nil {
-				w.Publish(w.Event(Error), err)
-				continue
-			}
-			if bytesRead == 6 {
-				if err = w.update(newValue); err != nil {
-					w.Publish(w.Event(Error), err)
-					continue
-				}
-			}
-			time.Sleep(w.interval)
+	config := w.polling
+	config.Interval = w.interval
+	if config.Scheduler == nil {
+		config.Scheduler = BusScheduler(w.connector, w.GetBusOrDefault(w.connector.GetDefaultBus()))
+	}
+	halt := make(chan bool)
+	w.mtx.Lock()
+	w.halt = halt
+	w.mtx.Unlock()
+	previous := make([]byte, 6)
+	gobot.NewPoller(config).Go(w, halt, func() bool {
+		if _, err := w.connection.Write([]byte{0x40, 0x00}); err != nil {
+			w.Publish(w.Event(Error), err)
+			return false
+		}
+		time.Sleep(w.pauseTime)
+		if _, err := w.connection.Write([]byte{0x00}); err != nil {
+			w.Publish(w.Event(Error), err)
+			return false
 		}
-	}()
+		time.Sleep(w.pauseTime)
+		newValue := make([]byte, 6)
+		bytesRead, err := w.connection.Read(newValue)
+		if err != nil {
+			w.Publish(w.Event(Error), err)
+			return false
+		}
+		if bytesRead != 6 {
+			return false
+		}
+		if err = w.update(newValue); err != nil {
+			w.Publish(w.Event(Error), err)
+			return false
+		}
+		changed := !bytes.Equal(newValue, previous)
+		previous = newValue
+		return changed
+	})
 	return
 }
 
-// Halt returns true if driver is halted successfully
-func (w *WiichuckDriver) Halt() (err error) { return }
+// Halt stops polling the Wiichuck
+func (w *WiichuckDriver) Halt() (err error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.halt != nil {
+		close(w.halt)
+		w.halt = nil
+	}
+	return
+}
+
+// SetPolling configures the polling of the Wiichuck, for example to read it
+// less often while it is not used. Unless c.Scheduler is set, the Wiichuck
+// takes turns with the other polling drivers of its bus using BusScheduler.
+func (w *WiichuckDriver) SetPolling(c gobot.PollerConfig) {
+	if c.Interval > 0 {
+		w.interval = c.Interval
+	}
+	w.polling = c
+}
 
 // Joystick returns the current value for the joystick
 func (w *WiichuckDriver) Joystick() map[string]float64 {
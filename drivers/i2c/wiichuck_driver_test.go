@@ -318,3 +318,36 @@ func TestWiichuckDriverOptions(t *testing.T) {
 	d := NewWiichuckDriver(newI2cTestAdaptor(), WithBus(2))
 	gobottest.Assert(t, d.GetBusOrDefault(1), 2)
 }
+
+func TestWiichuckDriverStartHalt(t *testing.T) {
+	wii, adaptor := initTestWiichuckDriverWithStubbedAdaptor()
+	reads := make(chan bool, 100)
+	adaptor.Testi2cReadImpl(func(b []byte) (int, error) {
+		reads <- true
+		copy(b, []byte{1, 2, 3, 4, 5, 6})
+		return 6, nil
+	})
+	wii.SetPolling(gobot.PollerConfig{Interval: time.Millisecond})
+	gobottest.Assert(t, wii.Start(), nil)
+	<-reads
+
+	gobottest.Assert(t, wii.Halt(), nil)
+	gobottest.Assert(t, wii.Halt(), nil)
+	time.Sleep(5 * time.Millisecond)
+	for len(reads) > 0 {
+		<-reads
+	}
+	select {
+	case <-reads:
+		t.Errorf("Wiichuck polled after Halt")
+	case <-time.After(5 * time.Millisecond):
+	}
+}
+
+func TestWiichuckDriverSetPolling(t *testing.T) {
+	wii := initTestWiichuckDriver()
+	s := gobot.NewPollScheduler(time.Millisecond)
+	wii.SetPolling(gobot.PollerConfig{MaxInterval: time.Second, Scheduler: s})
+	gobottest.Assert(t, wii.interval, 10*time.Millisecond)
+	gobottest.Assert(t, wii.polling.Scheduler, s)
+}
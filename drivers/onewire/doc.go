@@ -0,0 +1,11 @@
+/*
+Package onewire provides Gobot drivers for 1-Wire devices.
+
+Installing:
+
+	go get -d -u gobot.io/x/gobot
+
+For further information refer to onewire README:
+https://github.com/hybridgroup/gobot/blob/master/drivers/onewire/README.md
+*/
+package onewire // import "gobot.io/x/gobot/drivers/onewire"
@@ -0,0 +1,210 @@
+package onewire
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// DS18B20Family is the family code starting the IDs of the DS18B20 probes
+const DS18B20Family = "28"
+
+// ProbeTemperature is the data published with the Temperature event
+type ProbeTemperature struct {
+	// ID of the probe, e.g. "28-0316a2791bff"
+	ID string
+	// Celsius is the temperature read by the probe
+	Celsius float64
+}
+
+// DS18B20Driver represents the DS18B20 temperature probes of a 1-Wire bus.
+// The temperatures are reported in degree Celsius.
+type DS18B20Driver struct {
+	name         string
+	connection   Connector
+	probes       []string
+	interval     time.Duration
+	resolution   int
+	temperatures map[string]float64
+	halt         chan bool
+	done         chan bool
+	mutex        *sync.Mutex
+	gobot.Eventer
+}
+
+// NewDS18B20Driver returns a new DS18B20Driver with a polling interval of
+// 1 Second given a Connector and the IDs of its probes. All the DS18B20
+// found on the bus are used when no ID is given.
+//
+// Optionally accepts:
+// 	time.Duration: Interval at which the probes are read
+func NewDS18B20Driver(c Connector, ids []string, v ...time.Duration) *DS18B20Driver {
+	d := &DS18B20Driver{
+		name:         gobot.DefaultName("DS18B20"),
+		connection:   c,
+		probes:       ids,
+		interval:     time.Second,
+		temperatures: make(map[string]float64),
+		mutex:        &sync.Mutex{},
+		Eventer:      gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		d.interval = v[0]
+	}
+
+	d.AddEvent(Temperature)
+	d.AddEvent(Error)
+
+	return d
+}
+
+// Name returns the DS18B20Drivers name
+func (d *DS18B20Driver) Name() string { return d.name }
+
+// SetName sets the DS18B20Drivers name
+func (d *DS18B20Driver) SetName(n string) { d.name = n }
+
+// Connection returns the DS18B20Drivers Connection
+func (d *DS18B20Driver) Connection() gobot.Connection {
+	return d.connection.(gobot.Connection)
+}
+
+// Probes returns the IDs of the probes, known once started when none was
+// given to NewDS18B20Driver
+func (d *DS18B20Driver) Probes() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]string(nil), d.probes...)
+}
+
+// Start finds the probes when none was given, sets their resolution, and
+// reads them at the given interval.
+//
+// Emits the Events:
+//	Temperature ProbeTemperature - On each read of a probe
+//	Error error - On error reading a probe
+func (d *DS18B20Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.probes) == 0 {
+		ids, err := d.connection.Devices()
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if strings.HasPrefix(id, DS18B20Family+"-") {
+				d.probes = append(d.probes, id)
+			}
+		}
+	}
+	if d.resolution != 0 {
+		if err = d.writeResolution(d.resolution); err != nil {
+			return
+		}
+	}
+
+	d.halt = make(chan bool)
+	d.done = make(chan bool)
+	go func(halt chan bool, done chan bool) {
+		defer close(done)
+		gobot.NewPoller(gobot.PollerConfig{Interval: d.interval}).Run(halt, d.poll)
+	}(d.halt, d.done)
+	return
+}
+
+// Halt stops reading the probes, and waits for the read in progress
+func (d *DS18B20Driver) Halt() (err error) {
+	d.mutex.Lock()
+	if d.halt == nil {
+		d.mutex.Unlock()
+		return
+	}
+	close(d.halt)
+	d.halt = nil
+	done := d.done
+	d.mutex.Unlock()
+
+	<-done
+	return
+}
+
+// SetResolution sets the resolution of the probes, from 9 bits, read in
+// 94 Milliseconds to the 0.5 degree, to 12 bits, read in 750 Milliseconds to
+// the 0.0625 degree. The resolution is set when the driver starts if it is
+// not yet started.
+func (d *DS18B20Driver) SetResolution(bits int) error {
+	if bits < 9 || bits > 12 {
+		return fmt.Errorf("invalid DS18B20 resolution %d, from 9 to 12 bits are supported", bits)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.resolution = bits
+	if d.halt == nil {
+		return nil
+	}
+	return d.writeResolution(bits)
+}
+
+// Temperature returns the last temperature read by the probe
+func (d *DS18B20Driver) Temperature(id string) float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.temperatures[id]
+}
+
+// ReadTemperature reads the temperature of the probe
+func (d *DS18B20Driver) ReadTemperature(id string) (celsius float64, err error) {
+	data, err := d.connection.ReadAttribute(id, "w1_slave")
+	if err != nil {
+		return
+	}
+
+	// e.g. "72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01 4b 46 7f ff 0e 10 57 t=23125\n"
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(strings.TrimSpace(lines[0]), "YES") {
+		return 0, ErrCRC
+	}
+	i := strings.LastIndex(lines[1], "t=")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid DS18B20 data %q", data)
+	}
+	milli, err := strconv.Atoi(strings.TrimSpace(lines[1][i+2:]))
+	if err != nil {
+		return
+	}
+	celsius = float64(milli) / 1000
+
+	d.mutex.Lock()
+	d.temperatures[id] = celsius
+	d.mutex.Unlock()
+	return
+}
+
+// poll reads each probe, publishing its temperature
+func (d *DS18B20Driver) poll() bool {
+	for _, id := range d.Probes() {
+		celsius, err := d.ReadTemperature(id)
+		if err != nil {
+			d.Publish(Error, fmt.Errorf("%s: %v", id, err))
+			continue
+		}
+		d.Publish(Temperature, ProbeTemperature{ID: id, Celsius: celsius})
+	}
+	return false
+}
+
+func (d *DS18B20Driver) writeResolution(bits int) error {
+	for _, id := range d.probes {
+		if err := d.connection.WriteAttribute(id, "resolution", []byte(strconv.Itoa(bits))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
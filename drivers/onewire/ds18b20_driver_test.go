@@ -0,0 +1,117 @@
+package onewire
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*DS18B20Driver)(nil)
+
+const (
+	testW1Slave    = "72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01 4b 46 7f ff 0e 10 57 t=23125\n"
+	testW1SlaveBad = "72 01 4b 46 7f ff 0e 10 58 : crc=58 NO\n72 01 4b 46 7f ff 0e 10 58 t=23125\n"
+)
+
+func TestDS18B20Driver(t *testing.T) {
+	a, _ := initTestW1Adaptor()
+	d := NewDS18B20Driver(a, nil)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "DS18B20"), true)
+	d.SetName("probes")
+	gobottest.Assert(t, d.Name(), "probes")
+	gobottest.Assert(t, d.Connection(), a)
+	gobottest.Assert(t, d.interval, time.Second)
+
+	d = NewDS18B20Driver(a, []string{"28-0316a2791bff"}, 30*time.Second)
+	gobottest.Assert(t, d.interval, 30*time.Second)
+	gobottest.Assert(t, d.Probes(), []string{"28-0316a2791bff"})
+}
+
+func TestDS18B20DriverReadTemperature(t *testing.T) {
+	a, fs := initTestW1Adaptor()
+	d := NewDS18B20Driver(a, nil)
+
+	fs.Files["/sys/bus/w1/devices/28-0316a2791bff/w1_slave"].Contents = testW1Slave
+	celsius, err := d.ReadTemperature("28-0316a2791bff")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, celsius, 23.125)
+	gobottest.Assert(t, d.Temperature("28-0316a2791bff"), 23.125)
+
+	fs.Files["/sys/bus/w1/devices/28-0316a2791bff/w1_slave"].Contents = "6e ff 4b 46 7f ff 02 10 6c : crc=6c YES\n6e ff 4b 46 7f ff 02 10 6c t=-9125\n"
+	celsius, _ = d.ReadTemperature("28-0316a2791bff")
+	gobottest.Assert(t, celsius, -9.125)
+
+	fs.Files["/sys/bus/w1/devices/28-0316a2791bff/w1_slave"].Contents = testW1SlaveBad
+	_, err = d.ReadTemperature("28-0316a2791bff")
+	gobottest.Assert(t, err, ErrCRC)
+	gobottest.Assert(t, d.Temperature("28-0316a2791bff"), -9.125)
+
+	fs.Files["/sys/bus/w1/devices/28-0316a2791bff/w1_slave"].Contents = "72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01\n"
+	_, err = d.ReadTemperature("28-0316a2791bff")
+	gobottest.Refute(t, err, nil)
+
+	_, err = d.ReadTemperature("28-000000000000")
+	gobottest.Refute(t, err, nil)
+}
+
+func TestDS18B20DriverStart(t *testing.T) {
+	a, fs := initTestW1Adaptor()
+	fs.Files["/sys/bus/w1/devices/28-0316a2791bff/w1_slave"].Contents = testW1Slave
+	fs.Files["/sys/bus/w1/devices/28-041752d4c2ff/w1_slave"].Contents = testW1SlaveBad
+	d := NewDS18B20Driver(a, nil, 10*time.Millisecond)
+
+	temperatures := make(chan ProbeTemperature, 10)
+	errs := make(chan error, 10)
+	d.On(Temperature, func(data interface{}) { temperatures <- data.(ProbeTemperature) })
+	d.On(Error, func(data interface{}) { errs <- data.(error) })
+
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+	gobottest.Assert(t, d.Probes(), []string{"28-0316a2791bff", "28-041752d4c2ff"})
+
+	select {
+	case temperature := <-temperatures:
+		gobottest.Assert(t, temperature, ProbeTemperature{ID: "28-0316a2791bff", Celsius: 23.125})
+	case <-time.After(time.Second):
+		t.Errorf("Temperature was not published")
+	}
+	select {
+	case err := <-errs:
+		gobottest.Assert(t, err.Error(), "28-041752d4c2ff: "+ErrCRC.Error())
+	case <-time.After(time.Second):
+		t.Errorf("Error was not published")
+	}
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestDS18B20DriverResolution(t *testing.T) {
+	a, fs := initTestW1Adaptor()
+	// the probe is not read while its resolution is checked
+	delete(fs.Files, "/sys/bus/w1/devices/28-0316a2791bff/w1_slave")
+	d := NewDS18B20Driver(a, []string{"28-0316a2791bff"})
+
+	gobottest.Refute(t, d.SetResolution(8), nil)
+	gobottest.Refute(t, d.SetResolution(13), nil)
+
+	gobottest.Assert(t, d.SetResolution(10), nil)
+	gobottest.Assert(t, fs.Files["/sys/bus/w1/devices/28-0316a2791bff/resolution"].Contents, "")
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, fs.Files["/sys/bus/w1/devices/28-0316a2791bff/resolution"].Contents, "10")
+
+	gobottest.Assert(t, d.SetResolution(12), nil)
+	gobottest.Assert(t, fs.Files["/sys/bus/w1/devices/28-0316a2791bff/resolution"].Contents, "12")
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestDS18B20DriverStartError(t *testing.T) {
+	d := NewDS18B20Driver(NewW1Adaptor("w1_bus_master2"), nil)
+	gobottest.Refute(t, d.Start(), nil)
+
+	a, _ := initTestW1Adaptor()
+	d = NewDS18B20Driver(a, []string{"28-000000000000"})
+	d.SetResolution(9)
+	gobottest.Refute(t, d.Start(), nil)
+}
@@ -0,0 +1,29 @@
+package onewire
+
+import "errors"
+
+const (
+	// Error event
+	Error = "error"
+	// Temperature event
+	Temperature = "temperature"
+)
+
+var (
+	// ErrCRC is the error resulting when the data read from a device failed
+	// its CRC check, usually because of a bad wiring or a missing pull-up
+	ErrCRC = errors.New("1-wire CRC check failed")
+)
+
+// Connector interface represents an Adaptor giving access to the devices of
+// a 1-Wire bus, such as the W1Adaptor using the w1 subsystem of the Linux
+// kernel.
+type Connector interface {
+	// Devices returns the IDs of the devices on the bus, e.g. "28-0316a2791bff"
+	Devices() ([]string, error)
+	// ReadAttribute returns the value of an attribute of the device, such
+	// as "w1_slave"
+	ReadAttribute(id string, attribute string) ([]byte, error)
+	// WriteAttribute writes the value of an attribute of the device
+	WriteAttribute(id string, attribute string, data []byte) error
+}
@@ -0,0 +1,102 @@
+package onewire
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/sysfs"
+)
+
+// W1DevicesPath is the directory of the w1 devices in sysfs
+const W1DevicesPath = "/sys/bus/w1/devices"
+
+// W1Adaptor is the Gobot Adaptor for a 1-Wire bus driven by the w1 subsystem
+// of the Linux kernel, such as the w1-gpio overlay of a Raspberry Pi. The
+// kernel searches the bus, and reads the devices through their family
+// driver, e.g. w1_therm for the DS18B20.
+type W1Adaptor struct {
+	name   string
+	master string
+}
+
+// NewW1Adaptor returns a new W1Adaptor for the bus master, which defaults to
+// "w1_bus_master1".
+func NewW1Adaptor(master ...string) *W1Adaptor {
+	w := &W1Adaptor{
+		name:   gobot.DefaultName("W1"),
+		master: "w1_bus_master1",
+	}
+	if len(master) > 0 {
+		w.master = master[0]
+	}
+	return w
+}
+
+// Name returns the W1Adaptors name
+func (w *W1Adaptor) Name() string { return w.name }
+
+// SetName sets the W1Adaptors name
+func (w *W1Adaptor) SetName(n string) { w.name = n }
+
+// Master returns the bus master of the W1Adaptor
+func (w *W1Adaptor) Master() string { return w.master }
+
+// Connect checks that the bus master is found in sysfs
+func (w *W1Adaptor) Connect() (err error) {
+	_, err = w.Devices()
+	return
+}
+
+// Finalize implements the Adaptor interface
+func (w *W1Adaptor) Finalize() (err error) { return }
+
+// Devices returns the IDs of the devices found on the bus by the kernel
+func (w *W1Adaptor) Devices() (ids []string, err error) {
+	data, err := readFile(path.Join(W1DevicesPath, w.master, "w1_master_slaves"))
+	if err != nil {
+		return
+	}
+	for _, id := range strings.Split(string(data), "\n") {
+		id = strings.TrimSpace(id)
+		// the kernel lists "not found." when the bus has no device
+		if id == "" || id == "not found." {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ReadAttribute returns the value of an attribute of the device
+func (w *W1Adaptor) ReadAttribute(id string, attribute string) ([]byte, error) {
+	return readFile(path.Join(W1DevicesPath, id, attribute))
+}
+
+// WriteAttribute writes the value of an attribute of the device
+func (w *W1Adaptor) WriteAttribute(id string, attribute string, data []byte) error {
+	f, err := sysfs.OpenFile(path.Join(W1DevicesPath, id, attribute), os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+func readFile(name string) ([]byte, error) {
+	f, err := sysfs.OpenFile(name, os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 4096)
+	n, err := f.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
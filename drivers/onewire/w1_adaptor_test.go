@@ -0,0 +1,67 @@
+package onewire
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/sysfs"
+)
+
+var _ gobot.Adaptor = (*W1Adaptor)(nil)
+var _ Connector = (*W1Adaptor)(nil)
+
+func initTestW1Adaptor() (*W1Adaptor, *sysfs.MockFilesystem) {
+	fs := sysfs.NewMockFilesystem([]string{
+		"/sys/bus/w1/devices/w1_bus_master1/w1_master_slaves",
+		"/sys/bus/w1/devices/28-0316a2791bff/w1_slave",
+		"/sys/bus/w1/devices/28-0316a2791bff/resolution",
+		"/sys/bus/w1/devices/28-041752d4c2ff/w1_slave",
+		"/sys/bus/w1/devices/28-041752d4c2ff/resolution",
+	})
+	fs.Files["/sys/bus/w1/devices/w1_bus_master1/w1_master_slaves"].Contents = "28-0316a2791bff\n28-041752d4c2ff\n3a-00000011ab2c\n"
+	sysfs.SetFilesystem(fs)
+	return NewW1Adaptor(), fs
+}
+
+func TestW1Adaptor(t *testing.T) {
+	a := NewW1Adaptor()
+	gobottest.Assert(t, a.Master(), "w1_bus_master1")
+	a.SetName("bus")
+	gobottest.Assert(t, a.Name(), "bus")
+	gobottest.Assert(t, NewW1Adaptor("w1_bus_master2").Master(), "w1_bus_master2")
+}
+
+func TestW1AdaptorConnect(t *testing.T) {
+	a, _ := initTestW1Adaptor()
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.Finalize(), nil)
+
+	gobottest.Refute(t, NewW1Adaptor("w1_bus_master2").Connect(), nil)
+}
+
+func TestW1AdaptorDevices(t *testing.T) {
+	a, fs := initTestW1Adaptor()
+	ids, err := a.Devices()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, ids, []string{"28-0316a2791bff", "28-041752d4c2ff", "3a-00000011ab2c"})
+
+	fs.Files["/sys/bus/w1/devices/w1_bus_master1/w1_master_slaves"].Contents = "not found.\n"
+	ids, err = a.Devices()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(ids), 0)
+}
+
+func TestW1AdaptorAttributes(t *testing.T) {
+	a, fs := initTestW1Adaptor()
+	gobottest.Assert(t, a.WriteAttribute("28-0316a2791bff", "resolution", []byte("10")), nil)
+	gobottest.Assert(t, fs.Files["/sys/bus/w1/devices/28-0316a2791bff/resolution"].Contents, "10")
+
+	data, err := a.ReadAttribute("28-0316a2791bff", "resolution")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, string(data), "10")
+
+	_, err = a.ReadAttribute("28-000000000000", "w1_slave")
+	gobottest.Refute(t, err, nil)
+	gobottest.Refute(t, a.WriteAttribute("28-000000000000", "resolution", []byte("10")), nil)
+}
@@ -0,0 +1,261 @@
+package dryrun
+
+import (
+	"sync"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+// Operations recorded in dry-run mode
+const (
+	DigitalRead  = "DigitalRead"
+	DigitalWrite = "DigitalWrite"
+	AnalogRead   = "AnalogRead"
+	AnalogWrite  = "AnalogWrite"
+	PwmWrite     = "PwmWrite"
+	ServoWrite   = "ServoWrite"
+	I2cRead      = "I2cRead"
+	I2cWrite     = "I2cWrite"
+)
+
+// Operation is a hardware operation made in dry-run mode.
+type Operation struct {
+	// Op is one of the operation constants
+	Op string
+	// Pin read or written, the i2c address as "bus:address" for the i2c
+	// operations
+	Pin string
+	// Value read or written
+	Value int
+	// Data read or written by the i2c operations
+	Data []byte
+}
+
+// Adaptor wraps a real Adaptor, passing the operations on to it until set to
+// dry-run. In dry-run mode, it logs and records the operations instead, the
+// reads returning the configured defaults. Operations the wrapped Adaptor
+// does not support return the matching gpio and aio errors in both modes.
+type Adaptor struct {
+	gobot.Adaptor
+
+	dryRun     bool
+	digital    map[string]int
+	analog     map[string]int
+	i2c        map[int][]byte
+	operations []Operation
+	logger     gobot.Logger
+	mutex      sync.Mutex
+}
+
+// NewAdaptor returns a new Adaptor wrapping a.
+func NewAdaptor(a gobot.Adaptor) *Adaptor {
+	return &Adaptor{
+		Adaptor: a,
+		digital: make(map[string]int),
+		analog:  make(map[string]int),
+		i2c:     make(map[int][]byte),
+		logger:  gobot.DefaultLogger(),
+	}
+}
+
+// SetDryRun enables or disables the dry-run mode.
+func (a *Adaptor) SetDryRun(enable bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.dryRun = enable
+}
+
+// DryRun returns whether the Adaptor is in dry-run mode.
+func (a *Adaptor) DryRun() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.dryRun
+}
+
+// SetLogger sets the Logger of the dry-run operations, and the one of the
+// wrapped Adaptor.
+func (a *Adaptor) SetLogger(l gobot.Logger) {
+	a.mutex.Lock()
+	a.logger = l
+	a.mutex.Unlock()
+	if loggable, ok := a.Adaptor.(gobot.Loggable); ok {
+		loggable.SetLogger(l)
+	}
+}
+
+// SetDigitalRead sets the value returned by the digital reads of the pin in
+// dry-run mode, 0 by default.
+func (a *Adaptor) SetDigitalRead(pin string, val int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.digital[pin] = val
+}
+
+// SetAnalogRead sets the value returned by the analog reads of the pin in
+// dry-run mode, 0 by default.
+func (a *Adaptor) SetAnalogRead(pin string, val int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.analog[pin] = val
+}
+
+// SetI2cRead sets the bytes read from the i2c device at address in dry-run
+// mode, repeated as needed, zeros by default.
+func (a *Adaptor) SetI2cRead(address int, data []byte) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.i2c[address] = append([]byte{}, data...)
+}
+
+// Operations returns the operations made in dry-run mode.
+func (a *Adaptor) Operations() []Operation {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]Operation{}, a.operations...)
+}
+
+// Connect connects the wrapped Adaptor, unless in dry-run mode
+func (a *Adaptor) Connect() error {
+	if a.DryRun() {
+		return nil
+	}
+	return a.Adaptor.Connect()
+}
+
+// Finalize finalizes the wrapped Adaptor, unless in dry-run mode
+func (a *Adaptor) Finalize() error {
+	if a.DryRun() {
+		return nil
+	}
+	return a.Adaptor.Finalize()
+}
+
+// DigitalRead reads the pin from the wrapped Adaptor, or returns its default
+// value in dry-run mode
+func (a *Adaptor) DigitalRead(pin string) (int, error) {
+	reader, ok := a.Adaptor.(gpio.DigitalReader)
+	if !ok {
+		return 0, gpio.ErrDigitalReadUnsupported
+	}
+	if !a.DryRun() {
+		return reader.DigitalRead(pin)
+	}
+	a.mutex.Lock()
+	val := a.digital[pin]
+	a.mutex.Unlock()
+	a.record(Operation{Op: DigitalRead, Pin: pin, Value: val})
+	return val, nil
+}
+
+// DigitalWrite writes the pin using the wrapped Adaptor, or records it in
+// dry-run mode
+func (a *Adaptor) DigitalWrite(pin string, level byte) error {
+	writer, ok := a.Adaptor.(gpio.DigitalWriter)
+	if !ok {
+		return gpio.ErrDigitalWriteUnsupported
+	}
+	if !a.DryRun() {
+		return writer.DigitalWrite(pin, level)
+	}
+	a.record(Operation{Op: DigitalWrite, Pin: pin, Value: int(level)})
+	return nil
+}
+
+// AnalogRead reads the pin from the wrapped Adaptor, or returns its default
+// value in dry-run mode
+func (a *Adaptor) AnalogRead(pin string) (int, error) {
+	reader, ok := a.Adaptor.(aio.AnalogReader)
+	if !ok {
+		return 0, aio.ErrAnalogReadUnsupported
+	}
+	if !a.DryRun() {
+		return reader.AnalogRead(pin)
+	}
+	a.mutex.Lock()
+	val := a.analog[pin]
+	a.mutex.Unlock()
+	a.record(Operation{Op: AnalogRead, Pin: pin, Value: val})
+	return val, nil
+}
+
+// AnalogWrite writes the pin using the wrapped Adaptor, or records it in
+// dry-run mode
+func (a *Adaptor) AnalogWrite(pin string, value int) error {
+	writer, ok := a.Adaptor.(aio.AnalogWriter)
+	if !ok {
+		return aio.ErrAnalogWriteUnsupported
+	}
+	if !a.DryRun() {
+		return writer.AnalogWrite(pin, value)
+	}
+	a.record(Operation{Op: AnalogWrite, Pin: pin, Value: value})
+	return nil
+}
+
+// PwmWrite writes the pin using the wrapped Adaptor, or records it in dry-run
+// mode
+func (a *Adaptor) PwmWrite(pin string, level byte) error {
+	writer, ok := a.Adaptor.(gpio.PwmWriter)
+	if !ok {
+		return gpio.ErrPwmWriteUnsupported
+	}
+	if !a.DryRun() {
+		return writer.PwmWrite(pin, level)
+	}
+	a.record(Operation{Op: PwmWrite, Pin: pin, Value: int(level)})
+	return nil
+}
+
+// ServoWrite writes the pin using the wrapped Adaptor, or records it in
+// dry-run mode
+func (a *Adaptor) ServoWrite(pin string, angle byte) error {
+	writer, ok := a.Adaptor.(gpio.ServoWriter)
+	if !ok {
+		return gpio.ErrServoWriteUnsupported
+	}
+	if !a.DryRun() {
+		return writer.ServoWrite(pin, angle)
+	}
+	a.record(Operation{Op: ServoWrite, Pin: pin, Value: int(angle)})
+	return nil
+}
+
+// GetConnection returns a connection to the i2c device from the wrapped
+// Adaptor, or one recording the operations in dry-run mode
+func (a *Adaptor) GetConnection(address int, bus int) (i2c.Connection, error) {
+	connector, ok := a.Adaptor.(i2c.Connector)
+	if !ok {
+		return nil, ErrI2cUnsupported
+	}
+	if !a.DryRun() {
+		return connector.GetConnection(address, bus)
+	}
+	return &i2cConnection{adaptor: a, address: address, bus: bus}, nil
+}
+
+// GetDefaultBus returns the default i2c bus of the wrapped Adaptor
+func (a *Adaptor) GetDefaultBus() int {
+	if connector, ok := a.Adaptor.(i2c.Connector); ok {
+		return connector.GetDefaultBus()
+	}
+	return 0
+}
+
+// record records and logs op
+func (a *Adaptor) record(op Operation) {
+	a.mutex.Lock()
+	a.operations = append(a.operations, op)
+	logger := a.logger
+	a.mutex.Unlock()
+
+	keyvals := []interface{}{"op", op.Op, "pin", op.Pin}
+	if op.Data != nil {
+		keyvals = append(keyvals, "data", op.Data)
+	} else {
+		keyvals = append(keyvals, "value", op.Value)
+	}
+	logger.Info("Dry-run", keyvals...)
+}
@@ -0,0 +1,122 @@
+package dryrun
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+var _ gobot.DryRunner = (*Adaptor)(nil)
+var _ gobot.Loggable = (*Adaptor)(nil)
+var _ gpio.DigitalReader = (*Adaptor)(nil)
+var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ gpio.PwmWriter = (*Adaptor)(nil)
+var _ gpio.ServoWriter = (*Adaptor)(nil)
+var _ aio.AnalogReader = (*Adaptor)(nil)
+var _ aio.AnalogWriter = (*Adaptor)(nil)
+var _ i2c.Connector = (*Adaptor)(nil)
+
+func TestAdaptorPassesThrough(t *testing.T) {
+	a := NewAdaptor(&testAdaptor{name: "real"})
+	gobottest.Assert(t, a.Name(), "real")
+	gobottest.Assert(t, a.DryRun(), false)
+	gobottest.Assert(t, a.Connect(), errHardware)
+	gobottest.Assert(t, a.DigitalWrite("13", 1), errHardware)
+	_, err := a.AnalogRead("0")
+	gobottest.Assert(t, err, errHardware)
+	_, err = a.GetConnection(0x40, 1)
+	gobottest.Assert(t, err, errHardware)
+	gobottest.Assert(t, a.GetDefaultBus(), 1)
+	gobottest.Assert(t, a.Finalize(), errHardware)
+	gobottest.Assert(t, len(a.Operations()), 0)
+}
+
+func TestAdaptorDryRun(t *testing.T) {
+	real := &testAdaptor{name: "real"}
+	a := NewAdaptor(real)
+	a.SetDryRun(true)
+	a.SetDigitalRead("5", 1)
+	a.SetAnalogRead("0", 512)
+
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, real.connected, false)
+
+	gobottest.Assert(t, a.DigitalWrite("13", 1), nil)
+	gobottest.Assert(t, a.PwmWrite("3", 128), nil)
+	gobottest.Assert(t, a.ServoWrite("9", 90), nil)
+	gobottest.Assert(t, a.AnalogWrite("A0", 2048), nil)
+	val, err := a.DigitalRead("5")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+	val, _ = a.DigitalRead("6")
+	gobottest.Assert(t, val, 0)
+	val, _ = a.AnalogRead("0")
+	gobottest.Assert(t, val, 512)
+	gobottest.Assert(t, a.Finalize(), nil)
+
+	gobottest.Assert(t, a.Operations(), []Operation{
+		{Op: DigitalWrite, Pin: "13", Value: 1},
+		{Op: PwmWrite, Pin: "3", Value: 128},
+		{Op: ServoWrite, Pin: "9", Value: 90},
+		{Op: AnalogWrite, Pin: "A0", Value: 2048},
+		{Op: DigitalRead, Pin: "5", Value: 1},
+		{Op: DigitalRead, Pin: "6", Value: 0},
+		{Op: AnalogRead, Pin: "0", Value: 512},
+	})
+}
+
+func TestAdaptorDryRunI2c(t *testing.T) {
+	a := NewAdaptor(&testAdaptor{name: "real"})
+	a.SetDryRun(true)
+	a.SetI2cRead(0x40, []byte{0x12, 0x34})
+
+	c, err := a.GetConnection(0x40, 1)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.WriteByteData(0x01, 0xFF), nil)
+	w, _ := c.ReadWordData(0x02)
+	gobottest.Assert(t, w, uint16(0x3412))
+	b := make([]byte, 3)
+	n, _ := c.Read(b)
+	gobottest.Assert(t, n, 3)
+	gobottest.Assert(t, b, []byte{0x12, 0x34, 0x12})
+
+	other, _ := a.GetConnection(0x41, 1)
+	v, _ := other.ReadByte()
+	gobottest.Assert(t, v, byte(0))
+
+	ops := a.Operations()
+	gobottest.Assert(t, ops[0], Operation{Op: I2cWrite, Pin: "1:0x40", Data: []byte{0x01, 0xFF}})
+	gobottest.Assert(t, ops[1], Operation{Op: I2cWrite, Pin: "1:0x40", Data: []byte{0x02}})
+	gobottest.Assert(t, ops[2], Operation{Op: I2cRead, Pin: "1:0x40", Data: []byte{0x12, 0x34}})
+	gobottest.Assert(t, ops[4], Operation{Op: I2cRead, Pin: "1:0x41", Data: []byte{0}})
+}
+
+func TestAdaptorUnsupported(t *testing.T) {
+	a := NewAdaptor(nullAdaptor{})
+	a.SetDryRun(true)
+	gobottest.Assert(t, a.DigitalWrite("13", 1), gpio.ErrDigitalWriteUnsupported)
+	gobottest.Assert(t, a.AnalogWrite("0", 1), aio.ErrAnalogWriteUnsupported)
+	_, err := a.AnalogRead("0")
+	gobottest.Assert(t, err, aio.ErrAnalogReadUnsupported)
+	_, err = a.GetConnection(0x40, 0)
+	gobottest.Assert(t, err, ErrI2cUnsupported)
+}
+
+func TestRobotDryRun(t *testing.T) {
+	real := &testAdaptor{name: "real"}
+	a := NewAdaptor(real)
+	led := gpio.NewLedDriver(a, "13")
+	r := gobot.NewRobot("bot", []gobot.Connection{a}, []gobot.Device{led})
+	r.DryRun = true
+
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, real.connected, false)
+	gobottest.Assert(t, led.On(), nil)
+	gobottest.Assert(t, r.Stop(), nil)
+	gobottest.Assert(t, a.Operations(), []Operation{{Op: DigitalWrite, Pin: "13", Value: 1}})
+}
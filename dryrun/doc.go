@@ -0,0 +1,24 @@
+/*
+Package dryrun runs a Gobot robot without its hardware, so that the program
+logic and API wiring can be exercised on a laptop.
+
+An Adaptor wraps a real adaptor. Until it is set to dry-run, it passes every
+operation on to the real adaptor. In dry-run mode, set by the Robot or Master
+DryRun flag, it neither connects nor finalizes the real adaptor: the writes are
+logged and recorded, and the reads return the configured defaults:
+
+	firmataAdaptor := dryrun.NewAdaptor(firmata.NewAdaptor("/dev/ttyACM0"))
+	firmataAdaptor.SetDigitalRead("5", 1)
+	button := gpio.NewButtonDriver(firmataAdaptor, "5")
+	led := gpio.NewLedDriver(firmataAdaptor, "13")
+
+	robot := gobot.NewRobot("bot",
+		[]gobot.Connection{firmataAdaptor},
+		[]gobot.Device{button, led},
+	)
+	robot.DryRun = os.Getenv("DRY_RUN") != ""
+
+The i2c devices of a dry-run Adaptor write nowhere and read the bytes set with
+SetI2cRead, zeros by default.
+*/
+package dryrun // import "gobot.io/x/gobot/dryrun"
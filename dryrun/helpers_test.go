@@ -0,0 +1,38 @@
+package dryrun
+
+import (
+	"errors"
+
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+var errHardware = errors.New("no hardware")
+
+// testAdaptor fails every operation, like an adaptor without its hardware
+type testAdaptor struct {
+	name      string
+	connected bool
+}
+
+func (t *testAdaptor) Name() string     { return t.name }
+func (t *testAdaptor) SetName(n string) { t.name = n }
+func (t *testAdaptor) Connect() error   { t.connected = true; return errHardware }
+func (t *testAdaptor) Finalize() error  { return errHardware }
+
+func (t *testAdaptor) DigitalRead(pin string) (int, error)   { return 0, errHardware }
+func (t *testAdaptor) AnalogRead(pin string) (int, error)    { return 0, errHardware }
+func (t *testAdaptor) DigitalWrite(pin string, v byte) error { return errHardware }
+func (t *testAdaptor) AnalogWrite(pin string, v int) error   { return errHardware }
+func (t *testAdaptor) PwmWrite(pin string, v byte) error     { return errHardware }
+func (t *testAdaptor) ServoWrite(pin string, v byte) error   { return errHardware }
+func (t *testAdaptor) GetConnection(address int, bus int) (i2c.Connection, error) {
+	return nil, errHardware
+}
+func (t *testAdaptor) GetDefaultBus() int { return 1 }
+
+type nullAdaptor struct{}
+
+func (nullAdaptor) Name() string     { return "null" }
+func (nullAdaptor) SetName(n string) {}
+func (nullAdaptor) Connect() error   { return nil }
+func (nullAdaptor) Finalize() error  { return nil }
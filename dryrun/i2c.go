@@ -0,0 +1,91 @@
+package dryrun
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrI2cUnsupported is returned when getting an i2c connection from an
+// Adaptor wrapping one without i2c.
+var ErrI2cUnsupported = errors.New("I2C is not supported by this platform")
+
+// i2cConnection is the i2c.Connection of a device in dry-run mode, recording
+// the writes and reading the bytes set with SetI2cRead
+type i2cConnection struct {
+	adaptor *Adaptor
+	address int
+	bus     int
+}
+
+func (c *i2cConnection) pin() string {
+	return fmt.Sprintf("%d:0x%02X", c.bus, c.address)
+}
+
+// read fills b with the bytes set for the device
+func (c *i2cConnection) read(b []byte) {
+	c.adaptor.mutex.Lock()
+	data := c.adaptor.i2c[c.address]
+	c.adaptor.mutex.Unlock()
+
+	for i := range b {
+		b[i] = 0
+		if len(data) > 0 {
+			b[i] = data[i%len(data)]
+		}
+	}
+	c.adaptor.record(Operation{Op: I2cRead, Pin: c.pin(), Data: append([]byte{}, b...)})
+}
+
+func (c *i2cConnection) write(b ...byte) {
+	c.adaptor.record(Operation{Op: I2cWrite, Pin: c.pin(), Data: b})
+}
+
+func (c *i2cConnection) Read(b []byte) (int, error) {
+	c.read(b)
+	return len(b), nil
+}
+
+func (c *i2cConnection) Write(b []byte) (int, error) {
+	c.write(append([]byte{}, b...)...)
+	return len(b), nil
+}
+
+func (c *i2cConnection) Close() error { return nil }
+
+func (c *i2cConnection) ReadByte() (byte, error) {
+	b := make([]byte, 1)
+	c.read(b)
+	return b[0], nil
+}
+
+func (c *i2cConnection) ReadByteData(reg uint8) (uint8, error) {
+	c.write(reg)
+	return c.ReadByte()
+}
+
+func (c *i2cConnection) ReadWordData(reg uint8) (uint16, error) {
+	c.write(reg)
+	b := make([]byte, 2)
+	c.read(b)
+	return uint16(b[0]) | uint16(b[1])<<8, nil
+}
+
+func (c *i2cConnection) WriteByte(val byte) error {
+	c.write(val)
+	return nil
+}
+
+func (c *i2cConnection) WriteByteData(reg uint8, val uint8) error {
+	c.write(reg, val)
+	return nil
+}
+
+func (c *i2cConnection) WriteWordData(reg uint8, val uint16) error {
+	c.write(reg, byte(val), byte(val>>8))
+	return nil
+}
+
+func (c *i2cConnection) WriteBlockData(reg uint8, b []byte) error {
+	c.write(append([]byte{reg}, b...)...)
+	return nil
+}
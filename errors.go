@@ -0,0 +1,71 @@
+package gobot
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// Error adds the failing operation and the name of the Robot, Connection or
+// Device involved to an error. The original error is available using Unwrap,
+// so errors.Is and errors.As see through it.
+type Error struct {
+	Op        string
+	Component string
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v %v: %v", e.Op, e.Component, e.Err)
+}
+
+// Unwrap returns the original error
+func (e *Error) Unwrap() error { return e.Err }
+
+// WrapError returns err annotated with the operation op and the name of the
+// component it failed on, or nil if err is nil.
+func WrapError(op string, component string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Component: component, Err: err}
+}
+
+// JoinErrors combines errs into a single error, skipping nil errors. It returns
+// nil if no error is left. Nested aggregates are flattened, so JoinErrors also
+// adapts functions still returning a []error:
+//
+//	err := gobot.JoinErrors(legacy()...)
+func JoinErrors(errs ...error) error {
+	var result error
+	for _, err := range errs {
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// Errors returns the individual errors contained in err. It understands both
+// the aggregates returned by JoinErrors and errors implementing
+// Unwrap() []error, such as those created by errors.Join. A nil err returns an
+// empty slice.
+func Errors(err error) []error {
+	switch e := err.(type) {
+	case nil:
+		return []error{}
+	case *multierror.Error:
+		return flattenErrors(e.Errors)
+	case interface{ Unwrap() []error }:
+		return flattenErrors(e.Unwrap())
+	}
+	return []error{err}
+}
+
+func flattenErrors(errs []error) []error {
+	result := []error{}
+	for _, err := range errs {
+		result = append(result, Errors(err)...)
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package gobot
+
+import (
+	"errors"
+	"testing"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestWrapError(t *testing.T) {
+	gobottest.Assert(t, WrapError("start", "led", nil), nil)
+
+	e := errors.New("pin busy")
+	err := WrapError("start", "led", e)
+	gobottest.Assert(t, err.Error(), "start led: pin busy")
+	gobottest.Assert(t, errors.Is(err, e), true)
+
+	var gerr *Error
+	gobottest.Assert(t, errors.As(err, &gerr), true)
+	gobottest.Assert(t, gerr.Op, "start")
+	gobottest.Assert(t, gerr.Component, "led")
+}
+
+func TestJoinErrors(t *testing.T) {
+	gobottest.Assert(t, JoinErrors(), nil)
+	gobottest.Assert(t, JoinErrors(nil, nil), nil)
+
+	e1 := errors.New("one")
+	e2 := errors.New("two")
+	err := JoinErrors(e1, nil, e2)
+	gobottest.Assert(t, Errors(err), []error{e1, e2})
+
+	var legacy []error
+	legacy = append(legacy, e1)
+	gobottest.Assert(t, Errors(JoinErrors(legacy...)), []error{e1})
+}
+
+func TestErrors(t *testing.T) {
+	e1 := errors.New("one")
+	e2 := errors.New("two")
+	e3 := errors.New("three")
+
+	gobottest.Assert(t, Errors(nil), []error{})
+	gobottest.Assert(t, Errors(e1), []error{e1})
+
+	var nested error
+	nested = multierror.Append(nested, e1)
+	nested = multierror.Append(nested, errors.Join(e2, e3))
+	gobottest.Assert(t, Errors(nested), []error{e1, e2, e3})
+}
+
+func TestRobotStartErrorContext(t *testing.T) {
+	e := errors.New("driver start error")
+	testDriverStart = func() (err error) { return e }
+	defer func() { testDriverStart = func() (err error) { return } }()
+
+	r := newTestRobot("Robot1")
+	err := r.Start(false)
+	errs := Errors(err)
+	gobottest.Assert(t, len(errs), 3)
+	gobottest.Assert(t, errs[0].Error(), "start Device1: driver start error")
+	gobottest.Assert(t, errors.Is(errs[1], e), true)
+}
@@ -1,13 +1,24 @@
 package gobot
 
+import "time"
+
 // Event represents when something asynchronous happens in a Driver
 // or Adaptor
 type Event struct {
 	Name string
 	Data interface{}
+	// Time is when the Event was published, read from the DefaultClock. The
+	// SystemClock includes a monotonic clock reading, so that the latency
+	// between two Events can be measured with Sub even if the wall clock
+	// changes.
+	Time time.Time
+	// Seq is the sequence number of the Event among all the Events published
+	// by its Eventer, starting at 1. A gap between the Events of a
+	// subscription matching all Events means that Events were dropped.
+	Seq uint64
 }
 
-// NewEvent returns a new Event and its associated data.
+// NewEvent returns a new Event and its associated data, published now.
 func NewEvent(name string, data interface{}) *Event {
-	return &Event{Name: name, Data: data}
+	return &Event{Name: name, Data: data, Time: DefaultClock().Now()}
 }
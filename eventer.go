@@ -1,6 +1,10 @@
 package gobot
 
-import "sync"
+import (
+	"path"
+	"sync"
+	"sync/atomic"
+)
 
 type eventChannel chan *Event
 
@@ -8,18 +12,93 @@ type eventer struct {
 	// map of valid Event names
 	eventnames map[string]string
 
-	// new events get put in to the event channel
-	in eventChannel
+	// subscriptions, and the ones made with SubscribeWith by out channel
+	subs map[*subscription]struct{}
+	outs map[eventChannel]*subscription
 
-	// map of out channels used by subscribers
-	outs map[eventChannel]eventChannel
-
-	// mutex to protect the eventChannel map
+	// mutex to protect the subscription maps
 	eventsMutex sync.Mutex
+
+	// routes holds the current *eventRoutes. It is replaced whenever the
+	// subscriptions change, so that Publish does not lock eventsMutex for the
+	// event names already published.
+	routes atomic.Value
+
+	// count of events published and dropped
+	published uint64
+	dropped   uint64
+}
+
+// eventRoutes is an immutable snapshot of the subscriptions, along with the
+// subscriptions matching the first maxEventRoutes event names published since
+// it was taken.
+type eventRoutes struct {
+	subs   []*subscription
+	byName map[string][]*subscription
 }
 
 const eventChanBufferSize = 10
 
+// maxEventRoutes bounds the event names whose subscriptions are cached, for
+// the eventers publishing names made up on the fly, e.g. per id
+const maxEventRoutes = 256
+
+// DeliveryPolicy describes what happens when an event is published while a
+// subscriber's queue is full.
+type DeliveryPolicy int
+
+const (
+	// Block waits until the subscriber has room in its queue
+	Block DeliveryPolicy = iota
+	// DropNewest discards the event being published
+	DropNewest
+	// DropOldest discards the oldest queued event to make room
+	DropOldest
+)
+
+// SubscribeOptions configures a subscription made with SubscribeWith.
+type SubscribeOptions struct {
+	// Pattern filters events by name using path.Match syntax, e.g. "gesture:*".
+	// An empty Pattern matches all events.
+	Pattern string
+	// Policy is applied when the subscriber's queue is full
+	Policy DeliveryPolicy
+	// QueueSize is the subscriber's channel buffer size. Defaults to 10.
+	QueueSize int
+}
+
+// EventerMetrics is a snapshot of an Eventer's delivery counters.
+type EventerMetrics struct {
+	Published   uint64
+	Dropped     uint64
+	Subscribers int
+}
+
+type subscription struct {
+	// out receives the events of the subscriptions made with SubscribeWith.
+	// On and Once handlers only need the data of the events named name, they
+	// receive it on data instead so that no Event is allocated for them.
+	out     eventChannel
+	data    chan interface{}
+	name    string
+	opts    SubscribeOptions
+	dropped uint64
+	// done is closed on unsubscribe to release the blocked publishers
+	done chan struct{}
+}
+
+// matches reports whether the subscription receives the events named name
+func (s *subscription) matches(name string) bool {
+	if s.data != nil {
+		return s.name == name
+	}
+	if s.opts.Pattern != "" {
+		ok, _ := path.Match(s.opts.Pattern, name)
+		return ok
+	}
+	return true
+}
+
 // Eventer is the interface which describes how a Driver or Adaptor
 // handles events.
 type Eventer interface {
@@ -42,6 +121,16 @@ type Eventer interface {
 	// Subscribe to events
 	Subscribe() (events eventChannel)
 
+	// SubscribeWith subscribes to events using the given filter, delivery
+	// policy and queue size
+	SubscribeWith(opts SubscribeOptions) (events eventChannel)
+
+	// Dropped returns the number of events dropped for a subscription
+	Dropped(events eventChannel) uint64
+
+	// Metrics returns a snapshot of the delivery counters
+	Metrics() EventerMetrics
+
 	// Unsubscribe from an event channel
 	Unsubscribe(events eventChannel)
 
@@ -56,25 +145,126 @@ type Eventer interface {
 func NewEventer() Eventer {
 	evtr := &eventer{
 		eventnames: make(map[string]string),
-		in:         make(eventChannel, eventChanBufferSize),
-		outs:       make(map[eventChannel]eventChannel),
+		subs:       make(map[*subscription]struct{}),
+		outs:       make(map[eventChannel]*subscription),
 	}
+	evtr.routes.Store(&eventRoutes{byName: map[string][]*subscription{}})
+	return evtr
+}
 
-	// goroutine to cascade "in" events to all "out" event channels
-	go func() {
+// resolve returns the subscriptions matching the event name. The result is
+// cached until the subscriptions change, so that publishing an event known
+// to the routes only costs a map lookup. Past maxEventRoutes names, the
+// subscriptions are matched again at each call.
+func (e *eventer) resolve(name string) []*subscription {
+	routes := e.routes.Load().(*eventRoutes)
+	if subs, ok := routes.byName[name]; ok {
+		return subs
+	}
+	if len(routes.subs) == 0 {
+		return nil
+	}
+	if len(routes.byName) >= maxEventRoutes {
+		return routes.match(name)
+	}
+
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+	routes = e.routes.Load().(*eventRoutes)
+	if subs, ok := routes.byName[name]; ok {
+		return subs
+	}
+	subs := routes.match(name)
+	if len(routes.byName) >= maxEventRoutes {
+		return subs
+	}
+	byName := make(map[string][]*subscription, len(routes.byName)+1)
+	for n, s := range routes.byName {
+		byName[n] = s
+	}
+	byName[name] = subs
+	e.routes.Store(&eventRoutes{subs: routes.subs, byName: byName})
+	return subs
+}
+
+// match returns the subscriptions of the snapshot matching the event name
+func (r *eventRoutes) match(name string) []*subscription {
+	var subs []*subscription
+	for _, sub := range r.subs {
+		if sub.matches(name) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// updateRoutes replaces the routes once the subscriptions have changed. It
+// must be called with eventsMutex locked.
+func (e *eventer) updateRoutes() {
+	subs := make([]*subscription, 0, len(e.subs))
+	for sub := range e.subs {
+		subs = append(subs, sub)
+	}
+	e.routes.Store(&eventRoutes{subs: subs, byName: map[string][]*subscription{}})
+}
+
+func (e *eventer) subscribe(sub *subscription) {
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+	e.subs[sub] = struct{}{}
+	if sub.out != nil {
+		e.outs[sub.out] = sub
+	}
+	e.updateRoutes()
+}
+
+func (e *eventer) unsubscribe(sub *subscription) {
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+	if _, ok := e.subs[sub]; !ok {
+		return
+	}
+	delete(e.subs, sub)
+	if sub.out != nil {
+		delete(e.outs, sub.out)
+	}
+	e.updateRoutes()
+	close(sub.done)
+}
+
+// deliver sends evt to sub according to its delivery policy.
+func (e *eventer) deliver(sub *subscription, evt *Event) {
+	switch sub.opts.Policy {
+	case DropNewest:
+		select {
+		case sub.out <- evt:
+		default:
+			e.drop(sub)
+		}
+	case DropOldest:
 		for {
 			select {
-			case evt := <-evtr.in:
-				evtr.eventsMutex.Lock()
-				for _, out := range evtr.outs {
-					out <- evt
-				}
-				evtr.eventsMutex.Unlock()
+			case sub.out <- evt:
+				return
+			default:
+			}
+			select {
+			case <-sub.out:
+				e.drop(sub)
+			default:
 			}
 		}
-	}()
+	default:
+		select {
+		case sub.out <- evt:
+		case <-sub.done:
+		}
+	}
+}
 
-	return evtr
+func (e *eventer) drop(sub *subscription) {
+	atomic.AddUint64(&sub.dropped, 1)
+	atomic.AddUint64(&e.dropped, 1)
 }
 
 // Events returns the map of valid Event names.
@@ -98,58 +288,121 @@ func (e *eventer) DeleteEvent(name string) {
 	delete(e.eventnames, name)
 }
 
-// Publish new events to anyone that is subscribed
+// Publish new events to anyone that is subscribed. The events are delivered
+// before Publish returns, Publish waiting for the subscribers using the Block
+// policy to have room in their queue. Each Event is stamped with the time it
+// was published and its sequence number.
+//
+// A single Event is allocated for all the subscribers of the event channels,
+// and none for the On and Once handlers. The Events are not pooled, since the
+// subscribers may keep them.
 func (e *eventer) Publish(name string, data interface{}) {
-	evt := NewEvent(name, data)
-	e.in <- evt
+	seq := atomic.AddUint64(&e.published, 1)
+	var evt *Event
+	for _, sub := range e.resolve(name) {
+		if sub.data != nil {
+			select {
+			case sub.data <- data:
+			case <-sub.done:
+			}
+			continue
+		}
+		if evt == nil {
+			evt = NewEvent(name, data)
+			evt.Seq = seq
+		}
+		e.deliver(sub, evt)
+	}
 }
 
 // Subscribe to any events from this eventer
 func (e *eventer) Subscribe() eventChannel {
-	e.eventsMutex.Lock()
-	defer e.eventsMutex.Unlock()
-	out := make(eventChannel, eventChanBufferSize)
-	e.outs[out] = out
+	return e.SubscribeWith(SubscribeOptions{})
+}
+
+// SubscribeWith subscribes to the events from this eventer matching opts
+func (e *eventer) SubscribeWith(opts SubscribeOptions) eventChannel {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = eventChanBufferSize
+	}
+	out := make(eventChannel, opts.QueueSize)
+	e.subscribe(&subscription{out: out, opts: opts, done: make(chan struct{})})
 	return out
 }
 
+// Dropped returns the number of events dropped for the event channel
+func (e *eventer) Dropped(events eventChannel) uint64 {
+	e.eventsMutex.Lock()
+	sub, ok := e.outs[events]
+	e.eventsMutex.Unlock()
+	if !ok {
+		return 0
+	}
+	return atomic.LoadUint64(&sub.dropped)
+}
+
+// Metrics returns a snapshot of the delivery counters
+func (e *eventer) Metrics() EventerMetrics {
+	e.eventsMutex.Lock()
+	subscribers := len(e.subs)
+	e.eventsMutex.Unlock()
+	return EventerMetrics{
+		Published:   atomic.LoadUint64(&e.published),
+		Dropped:     atomic.LoadUint64(&e.dropped),
+		Subscribers: subscribers,
+	}
+}
+
 // Unsubscribe from the event channel
 func (e *eventer) Unsubscribe(events eventChannel) {
 	e.eventsMutex.Lock()
-	defer e.eventsMutex.Unlock()
-	delete(e.outs, events)
+	sub, ok := e.outs[events]
+	e.eventsMutex.Unlock()
+	if ok {
+		e.unsubscribe(sub)
+	}
 }
 
-// On executes the event handler f when e is Published to.
-func (e *eventer) On(n string, f func(s interface{})) (err error) {
-	out := e.Subscribe()
+// handle calls f, in its own goroutine, with the data of the events named n
+// until f returns false.
+func (e *eventer) handle(n string, f func(s interface{}) bool) {
+	sub := &subscription{
+		data: make(chan interface{}, eventChanBufferSize),
+		name: n,
+		done: make(chan struct{}),
+	}
+	e.subscribe(sub)
 	go func() {
 		for {
 			select {
-			case evt := <-out:
-				if evt.Name == n {
-					f(evt.Data)
+			case data := <-sub.data:
+				if !f(data) {
+					e.unsubscribe(sub)
+					return
 				}
+			case <-sub.done:
+				return
 			}
 		}
 	}()
+}
 
+// On executes the event handler f when e is Published to.
+func (e *eventer) On(n string, f func(s interface{})) (err error) {
+	source := "event " + n
+	e.handle(n, func(data interface{}) bool {
+		protect(source, handlePanic, func() { f(data) })
+		return true
+	})
 	return
 }
 
 // Once is similar to On except that it only executes f one time.
 func (e *eventer) Once(n string, f func(s interface{})) (err error) {
-	out := e.Subscribe()
-	go func() {
-	ProcessEvents:
-		for evt := range out {
-			if evt.Name == n {
-				f(evt.Data)
-				e.Unsubscribe(out)
-				break ProcessEvents
-			}
-		}
-	}()
-
+	source := "event " + n
+	e.handle(n, func(data interface{}) bool {
+		protect(source, handlePanic, func() { f(data) })
+		return false
+	})
 	return
 }
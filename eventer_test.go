@@ -1,6 +1,7 @@
 package gobot
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
@@ -76,3 +77,215 @@ func TestEventerOnce(t *testing.T) {
 	case <-time.After(10 * time.Millisecond):
 	}
 }
+
+func TestEventerSubscribeWithPattern(t *testing.T) {
+	e := NewEventer()
+	out := e.SubscribeWith(SubscribeOptions{Pattern: "gesture:*"})
+
+	e.Publish("proximity", 1)
+	e.Publish("gesture:up", 2)
+	e.Publish("gesture:down", 3)
+
+	gobottest.Assert(t, (<-out).Name, "gesture:up")
+	gobottest.Assert(t, (<-out).Name, "gesture:down")
+	select {
+	case evt := <-out:
+		t.Errorf("unexpected event %v", evt.Name)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestEventerSubscribeWithDropNewest(t *testing.T) {
+	e := NewEventer()
+	out := e.SubscribeWith(SubscribeOptions{Policy: DropNewest, QueueSize: 2})
+
+	for i := 0; i < 5; i++ {
+		e.Publish("test", i)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	gobottest.Assert(t, (<-out).Data, 0)
+	gobottest.Assert(t, (<-out).Data, 1)
+	gobottest.Assert(t, e.Dropped(out), uint64(3))
+	gobottest.Assert(t, e.Metrics(), EventerMetrics{Published: 5, Dropped: 3, Subscribers: 1})
+}
+
+func TestEventerSubscribeWithDropOldest(t *testing.T) {
+	e := NewEventer()
+	out := e.SubscribeWith(SubscribeOptions{Policy: DropOldest, QueueSize: 2})
+
+	for i := 0; i < 5; i++ {
+		e.Publish("test", i)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	gobottest.Assert(t, (<-out).Data, 3)
+	gobottest.Assert(t, (<-out).Data, 4)
+	gobottest.Assert(t, e.Dropped(out), uint64(3))
+
+	e.Unsubscribe(out)
+	gobottest.Assert(t, e.Dropped(out), uint64(0))
+	gobottest.Assert(t, e.Metrics().Subscribers, 0)
+}
+
+func TestEventerTimeAndSeq(t *testing.T) {
+	clock := NewFakeClock(time.Unix(100, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	e := NewEventer()
+	out := e.SubscribeWith(SubscribeOptions{Pattern: "gesture:*"})
+
+	e.Publish("gesture:up", 1)
+	clock.Advance(time.Second)
+	e.Publish("proximity", 2)
+	e.Publish("gesture:down", 3)
+
+	// the sequence numbers count all the events of the eventer
+	up, down := <-out, <-out
+	gobottest.Assert(t, up.Seq, uint64(1))
+	gobottest.Assert(t, down.Seq, uint64(3))
+	gobottest.Assert(t, up.Time, time.Unix(100, 0))
+	gobottest.Assert(t, down.Time.Sub(up.Time), time.Second)
+}
+
+func TestEventerOnExactName(t *testing.T) {
+	e := NewEventer()
+	sem := make(chan interface{}, 2)
+	e.On("gesture:*", func(data interface{}) {
+		sem <- data
+	})
+
+	e.Publish("gesture:up", 1)
+	e.Publish("gesture:*", 2)
+
+	gobottest.Assert(t, <-sem, 2)
+	select {
+	case data := <-sem:
+		t.Errorf("unexpected data %v", data)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestEventerSubscribeAfterPublish(t *testing.T) {
+	e := NewEventer()
+	e.Publish("test", 1)
+	out := e.Subscribe()
+	e.Publish("test", 2)
+
+	gobottest.Assert(t, (<-out).Data, 2)
+	gobottest.Assert(t, e.Metrics(), EventerMetrics{Published: 2, Subscribers: 1})
+}
+
+func TestEventerUnsubscribeReleasesPublish(t *testing.T) {
+	e := NewEventer()
+	out := e.SubscribeWith(SubscribeOptions{QueueSize: 1})
+	e.Publish("test", 1)
+
+	done := make(chan bool)
+	go func() {
+		e.Publish("test", 2)
+		done <- true
+	}()
+	select {
+	case <-done:
+		t.Errorf("Publish did not block")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	e.Unsubscribe(out)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Millisecond):
+		t.Errorf("Publish was not released")
+	}
+}
+
+func TestEventerRoutesBounded(t *testing.T) {
+	e := NewEventer()
+	out := e.SubscribeWith(SubscribeOptions{Pattern: "reading:*", QueueSize: 1})
+	for i := 0; i < 2*maxEventRoutes; i++ {
+		name := "reading:" + strconv.Itoa(i)
+		e.Publish(name, i)
+		gobottest.Assert(t, (<-out).Name, name)
+	}
+	e.Publish("other", 0)
+
+	routes := e.(*eventer).routes.Load().(*eventRoutes)
+	gobottest.Assert(t, len(routes.byName), maxEventRoutes)
+
+	// no route is cached without subscriptions
+	e.Unsubscribe(out)
+	e.Publish("reading:0", 0)
+	routes = e.(*eventer).routes.Load().(*eventRoutes)
+	gobottest.Assert(t, len(routes.byName), 0)
+}
+
+func TestEventerPublishAllocs(t *testing.T) {
+	e := NewEventer()
+	gobottest.Assert(t, testing.AllocsPerRun(100, func() { e.Publish("test", nil) }), 0.0)
+
+	received := make(chan bool)
+	e.On("test", func(data interface{}) { received <- true })
+	allocs := testing.AllocsPerRun(100, func() {
+		e.Publish("test", nil)
+		<-received
+	})
+	gobottest.Assert(t, allocs, 0.0)
+}
+
+func BenchmarkEventerPublish(b *testing.B) {
+	e := NewEventer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.Publish("test", nil)
+	}
+}
+
+func BenchmarkEventerPublishOn(b *testing.B) {
+	e := NewEventer()
+	done := make(chan bool)
+	count := 0
+	e.On("test", func(data interface{}) {
+		if count++; count == b.N {
+			done <- true
+		}
+	})
+	e.On("other", func(data interface{}) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Publish("test", nil)
+	}
+	<-done
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "events/s")
+}
+
+func BenchmarkEventerPublishSubscribers(b *testing.B) {
+	e := NewEventer()
+	var outs []eventChannel
+	for i := 0; i < 4; i++ {
+		outs = append(outs, e.SubscribeWith(SubscribeOptions{Pattern: "test", QueueSize: 100}))
+	}
+	e.SubscribeWith(SubscribeOptions{Pattern: "other"})
+	done := make(chan bool)
+	for _, out := range outs {
+		go func(out eventChannel) {
+			for i := 0; i < b.N; i++ {
+				<-out
+			}
+			done <- true
+		}(out)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Publish("test", nil)
+	}
+	for range outs {
+		<-done
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "events/s")
+}
@@ -0,0 +1,45 @@
+// +build example
+//
+// Do not build by default.
+
+/*
+ How to run
+ Pass the Bluetooth address or name as the first param:
+
+	go run examples/bb9e.go GB-1234
+
+ NOTE: sudo is required to use BLE in Linux
+*/
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/platforms/ble"
+	"gobot.io/x/gobot/platforms/sphero/bb9e"
+)
+
+func main() {
+	bleAdaptor := ble.NewClientAdaptor(os.Args[1])
+	bb9e := bb9e.NewDriver(bleAdaptor)
+
+	work := func() {
+		gobot.Every(1*time.Second, func() {
+			r := uint8(gobot.Rand(255))
+			g := uint8(gobot.Rand(255))
+			b := uint8(gobot.Rand(255))
+			bb9e.SetRGB(r, g, b)
+		})
+	}
+
+	robot := gobot.NewRobot("bbBot",
+		[]gobot.Connection{bleAdaptor},
+		[]gobot.Device{bb9e},
+		work,
+	)
+
+	robot.Start()
+}
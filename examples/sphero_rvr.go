@@ -0,0 +1,49 @@
+// +build example
+//
+// Do not build by default.
+
+/*
+ How to run
+ Pass the serial port of the RVR as the first param:
+
+	go run examples/sphero_rvr.go /dev/ttyS0
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/platforms/sphero/rvr"
+)
+
+func main() {
+	adaptor := rvr.NewAdaptor(os.Args[1])
+	driver := rvr.NewDriver(adaptor)
+
+	work := func() {
+		driver.On(rvr.SensorData, func(data interface{}) {
+			fmt.Println("Sensors", data)
+		})
+		driver.StartSensorStreaming(500*time.Millisecond, rvr.IMU, rvr.Speed)
+
+		gobot.Every(3*time.Second, func() {
+			r := uint8(gobot.Rand(255))
+			g := uint8(gobot.Rand(255))
+			b := uint8(gobot.Rand(255))
+			driver.SetRGB(r, g, b)
+			driver.Drive(40, uint16(gobot.Rand(360)))
+		})
+	}
+
+	robot := gobot.NewRobot("rvrBot",
+		[]gobot.Connection{adaptor},
+		[]gobot.Device{driver},
+		work,
+	)
+
+	robot.Start()
+}
@@ -0,0 +1,171 @@
+package gobot
+
+import (
+	"fmt"
+	"sync"
+)
+
+// StateChanged event
+const StateChanged = "state-changed"
+
+// AnyState matches every state when used as the source of a transition
+const AnyState = "*"
+
+// FSMTransition describes a change of state. It is passed to hooks and is the
+// data published with the StateChanged event.
+type FSMTransition struct {
+	Trigger string
+	From    string
+	To      string
+}
+
+// FSMState holds the hooks run when a state is entered or exited.
+type FSMState struct {
+	Name    string
+	OnEnter func(t FSMTransition)
+	OnExit  func(t FSMTransition)
+}
+
+type fsmTransition struct {
+	from  string
+	to    string
+	guard func() bool
+}
+
+// FSM is a finite state machine for declaring robot behaviors. Transitions are
+// fired by name, either directly with Fire, by a Commander command of the same
+// name, or by events from an Eventer bound with FireOn. Every successful
+// transition publishes a StateChanged event.
+type FSM struct {
+	current     string
+	states      map[string]*FSMState
+	transitions map[string][]*fsmTransition
+	mutex       sync.Mutex
+	Commander
+	Eventer
+}
+
+// NewFSM returns a new FSM in the initial state.
+func NewFSM(initial string) *FSM {
+	f := &FSM{
+		current:     initial,
+		states:      make(map[string]*FSMState),
+		transitions: make(map[string][]*fsmTransition),
+		Commander:   NewCommander(),
+		Eventer:     NewEventer(),
+	}
+	f.AddEvent(StateChanged)
+	f.AddCommand("State", func(params map[string]interface{}) interface{} {
+		return f.Current()
+	})
+	return f
+}
+
+// State returns the FSMState with the given name, creating it if needed, so
+// that hooks can be attached:
+//
+//	fsm.State("seeking").OnEnter = func(t gobot.FSMTransition) { motor.On() }
+func (f *FSM) State(name string) *FSMState {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.state(name)
+}
+
+func (f *FSM) state(name string) *FSMState {
+	s, ok := f.states[name]
+	if !ok {
+		s = &FSMState{Name: name}
+		f.states[name] = s
+	}
+	return s
+}
+
+// AddTransition declares that trigger moves the FSM from the state from (or
+// AnyState) to the state to. If guard is not nil, the transition only happens
+// when guard returns true. Transitions for a trigger are evaluated in the order
+// they were added.
+//
+// Adds the API Command trigger which fires the transition and returns the
+// resulting state.
+func (f *FSM) AddTransition(trigger string, from string, to string, guard func() bool) {
+	f.mutex.Lock()
+	f.state(from)
+	f.state(to)
+	_, known := f.transitions[trigger]
+	f.transitions[trigger] = append(f.transitions[trigger], &fsmTransition{from: from, to: to, guard: guard})
+	f.mutex.Unlock()
+
+	if !known {
+		f.AddCommand(trigger, func(params map[string]interface{}) interface{} {
+			if err := f.Fire(trigger); err != nil {
+				return err
+			}
+			return f.Current()
+		})
+	}
+}
+
+// Current returns the current state
+func (f *FSM) Current() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.current
+}
+
+// Is returns true if the current state is state
+func (f *FSM) Is(state string) bool {
+	return f.Current() == state
+}
+
+// Can returns true if trigger would cause a transition from the current state
+func (f *FSM) Can(trigger string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.find(trigger) != nil
+}
+
+func (f *FSM) find(trigger string) *fsmTransition {
+	for _, t := range f.transitions[trigger] {
+		if t.from != f.current && t.from != AnyState {
+			continue
+		}
+		if t.guard == nil || t.guard() {
+			return t
+		}
+	}
+	return nil
+}
+
+// Fire runs the transition for trigger from the current state. The exit hook
+// of the current state runs before the entry hook of the new state, and a
+// StateChanged event is published afterwards.
+func (f *FSM) Fire(trigger string) error {
+	f.mutex.Lock()
+	t := f.find(trigger)
+	if t == nil {
+		current := f.current
+		f.mutex.Unlock()
+		return fmt.Errorf("No transition %v from state %v", trigger, current)
+	}
+	transition := FSMTransition{Trigger: trigger, From: f.current, To: t.to}
+	from, to := f.state(transition.From), f.state(transition.To)
+	f.current = t.to
+	f.mutex.Unlock()
+
+	if from.OnExit != nil {
+		from.OnExit(transition)
+	}
+	if to.OnEnter != nil {
+		to.OnEnter(transition)
+	}
+	f.Publish(StateChanged, transition)
+	return nil
+}
+
+// FireOn fires trigger every time e publishes the event name. Events that do
+// not cause a transition from the current state are ignored.
+func (f *FSM) FireOn(e Eventer, name string, trigger string) error {
+	return e.On(name, func(data interface{}) {
+		f.Fire(trigger)
+	})
+}
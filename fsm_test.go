@@ -0,0 +1,92 @@
+package gobot
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func newTestFSM() *FSM {
+	f := NewFSM("idle")
+	f.AddTransition("seek", "idle", "seeking", nil)
+	f.AddTransition("grab", "seeking", "grabbing", nil)
+	f.AddTransition("reset", AnyState, "idle", nil)
+	return f
+}
+
+func TestFSMFire(t *testing.T) {
+	f := newTestFSM()
+	gobottest.Assert(t, f.Current(), "idle")
+	gobottest.Assert(t, f.Can("grab"), false)
+	gobottest.Refute(t, f.Fire("grab"), nil)
+
+	gobottest.Assert(t, f.Fire("seek"), nil)
+	gobottest.Assert(t, f.Is("seeking"), true)
+	gobottest.Assert(t, f.Fire("grab"), nil)
+	gobottest.Assert(t, f.Current(), "grabbing")
+	gobottest.Assert(t, f.Fire("reset"), nil)
+	gobottest.Assert(t, f.Current(), "idle")
+}
+
+func TestFSMGuards(t *testing.T) {
+	f := NewFSM("idle")
+	target := false
+	f.AddTransition("go", "idle", "chasing", func() bool { return target })
+	f.AddTransition("go", "idle", "wandering", nil)
+
+	gobottest.Assert(t, f.Fire("go"), nil)
+	gobottest.Assert(t, f.Current(), "wandering")
+
+	f = NewFSM("idle")
+	f.AddTransition("go", "idle", "chasing", func() bool { return target })
+	gobottest.Refute(t, f.Fire("go"), nil)
+	target = true
+	gobottest.Assert(t, f.Fire("go"), nil)
+	gobottest.Assert(t, f.Current(), "chasing")
+}
+
+func TestFSMHooksAndEvents(t *testing.T) {
+	f := newTestFSM()
+	calls := []string{}
+	f.State("idle").OnExit = func(tr FSMTransition) {
+		calls = append(calls, "exit "+tr.From)
+	}
+	f.State("seeking").OnEnter = func(tr FSMTransition) {
+		calls = append(calls, "enter "+tr.To)
+	}
+
+	events := f.Subscribe()
+	gobottest.Assert(t, f.Fire("seek"), nil)
+	gobottest.Assert(t, calls, []string{"exit idle", "enter seeking"})
+
+	evt := <-events
+	gobottest.Assert(t, evt.Name, StateChanged)
+	gobottest.Assert(t, evt.Data, FSMTransition{Trigger: "seek", From: "idle", To: "seeking"})
+}
+
+func TestFSMCommands(t *testing.T) {
+	f := newTestFSM()
+	gobottest.Assert(t, f.Command("seek")(nil), "seeking")
+	gobottest.Refute(t, f.Command("seek")(nil), "seeking")
+	gobottest.Assert(t, f.Command("State")(nil), "seeking")
+}
+
+func TestFSMFireOn(t *testing.T) {
+	f := newTestFSM()
+	e := NewEventer()
+	gobottest.Assert(t, f.FireOn(e, "target-found", "seek"), nil)
+
+	sem := make(chan bool)
+	f.On(StateChanged, func(data interface{}) {
+		sem <- true
+	})
+	e.Publish("target-found", nil)
+
+	select {
+	case <-sem:
+		gobottest.Assert(t, f.Current(), "seeking")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("event did not trigger transition")
+	}
+}
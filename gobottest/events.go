@@ -0,0 +1,145 @@
+package gobottest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Subscriber is implemented by gobot.Eventer.
+type Subscriber interface {
+	On(name string, f func(data interface{})) error
+}
+
+// Matcher checks the data of an event.
+type Matcher func(data interface{}) bool
+
+// Equals returns a Matcher accepting data equal to v
+func Equals(v interface{}) Matcher {
+	return func(data interface{}) bool {
+		return reflect.DeepEqual(data, v)
+	}
+}
+
+// RecordedEvent is an event received by an EventRecorder.
+type RecordedEvent struct {
+	Name string
+	Data interface{}
+	Time time.Time
+}
+
+func (e RecordedEvent) String() string {
+	return fmt.Sprintf("%v(%v)", e.Name, e.Data)
+}
+
+// EventRecorder records the events published by a Subscriber, so that tests
+// can expect them:
+//
+//	events := gobottest.RecordEvents(t, button, gpio.ButtonPush, gpio.ButtonRelease)
+//	// press the button...
+//	events.ExpectEvent(gpio.ButtonPush, 100*time.Millisecond, nil)
+//	events.ExpectNoEvent(gpio.ButtonRelease, 50*time.Millisecond)
+type EventRecorder struct {
+	t        *testing.T
+	events   []RecordedEvent
+	consumed int
+	changed  chan bool
+	mutex    sync.Mutex
+}
+
+// RecordEvents starts recording the events named names published by s.
+func RecordEvents(t *testing.T, s Subscriber, names ...string) *EventRecorder {
+	r := &EventRecorder{t: t, changed: make(chan bool)}
+	for _, name := range names {
+		name := name
+		if err := s.On(name, func(data interface{}) { r.record(name, data) }); err != nil {
+			logFailure(t, fmt.Sprintf("cannot record event %q: %v", name, err))
+		}
+	}
+	return r
+}
+
+func (r *EventRecorder) record(name string, data interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, RecordedEvent{Name: name, Data: data, Time: time.Now()})
+	close(r.changed)
+	r.changed = make(chan bool)
+}
+
+// Events returns the events received so far
+func (r *EventRecorder) Events() []RecordedEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RecordedEvent{}, r.events...)
+}
+
+// next returns the first event named name matching m received since the
+// previously expected event, marking it as expected.
+func (r *EventRecorder) next(name string, m Matcher) (RecordedEvent, bool, chan bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i := r.consumed; i < len(r.events); i++ {
+		e := r.events[i]
+		if e.Name == name && (m == nil || m(e.Data)) {
+			r.consumed = i + 1
+			return e, true, nil
+		}
+	}
+	return RecordedEvent{}, false, r.changed
+}
+
+// unexpected describes the events received since the last expected event.
+func (r *EventRecorder) unexpected() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.consumed == len(r.events) {
+		return "no events"
+	}
+	names := []string{}
+	for _, e := range r.events[r.consumed:] {
+		names = append(names, e.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// ExpectEvent waits up to within for an event named name whose data matches
+// m, a nil m matching any data, and returns its data. Events are expected in
+// order: the events received before the previously expected one are
+// ignored. A test error listing the events received is emitted on timeout.
+func (r *EventRecorder) ExpectEvent(name string, within time.Duration, m Matcher) interface{} {
+	deadline := time.After(within)
+	for {
+		e, ok, changed := r.next(name, m)
+		if ok {
+			return e.Data
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			logFailure(r.t, fmt.Sprintf("expected event %q within %v, got %v", name, within, r.unexpected()))
+			return nil
+		}
+	}
+}
+
+// ExpectNoEvent emits a test error if an event named name is received within
+// the given duration, or was received since the last expected event.
+func (r *EventRecorder) ExpectNoEvent(name string, within time.Duration) {
+	deadline := time.After(within)
+	for {
+		e, ok, changed := r.next(name, nil)
+		if ok {
+			logFailure(r.t, fmt.Sprintf("unexpected event %v", e))
+			return
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gobottest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSubscriber struct {
+	handlers map[string][]func(interface{})
+	mutex    sync.Mutex
+}
+
+func (s *testSubscriber) On(name string, f func(data interface{})) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if name == "invalid" {
+		return errors.New("no such event")
+	}
+	if s.handlers == nil {
+		s.handlers = make(map[string][]func(interface{}))
+	}
+	s.handlers[name] = append(s.handlers[name], f)
+	return nil
+}
+
+func (s *testSubscriber) publish(name string, data interface{}) {
+	s.mutex.Lock()
+	handlers := s.handlers[name]
+	s.mutex.Unlock()
+	for _, f := range handlers {
+		f(data)
+	}
+}
+
+func captureFailures() *[]string {
+	failures := []string{}
+	errFunc = func(t *testing.T, message string) {
+		failures = append(failures, message)
+	}
+	return &failures
+}
+
+func TestExpectEvent(t *testing.T) {
+	failures := captureFailures()
+	s := &testSubscriber{}
+	r := RecordEvents(t, s, "push", "release")
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		s.publish("push", 1)
+		s.publish("release", 0)
+	}()
+
+	if data := r.ExpectEvent("push", time.Second, nil); data != 1 {
+		t.Errorf("ExpectEvent returned %v", data)
+	}
+	r.ExpectEvent("release", time.Second, Equals(0))
+	if len(*failures) != 0 {
+		t.Errorf("unexpected failures %v", *failures)
+	}
+	if len(r.Events()) != 2 {
+		t.Errorf("Events returned %v", r.Events())
+	}
+}
+
+func TestExpectEventTimeout(t *testing.T) {
+	failures := captureFailures()
+	s := &testSubscriber{}
+	r := RecordEvents(t, s, "push", "release")
+	s.publish("release", 0)
+
+	if data := r.ExpectEvent("push", 10*time.Millisecond, nil); data != nil {
+		t.Errorf("ExpectEvent returned %v", data)
+	}
+	if len(*failures) != 1 || !strings.HasSuffix((*failures)[0],
+		`expected event "push" within 10ms, got release(0)`) {
+		t.Errorf("unexpected failures %v", *failures)
+	}
+}
+
+func TestExpectEventMatcher(t *testing.T) {
+	failures := captureFailures()
+	s := &testSubscriber{}
+	r := RecordEvents(t, s, "data")
+	s.publish("data", 1)
+	s.publish("data", 2)
+
+	r.ExpectEvent("data", 10*time.Millisecond, Equals(2))
+	// events before the expected one are skipped
+	r.ExpectEvent("data", 10*time.Millisecond, Equals(1))
+	if len(*failures) != 1 || !strings.HasSuffix((*failures)[0],
+		`expected event "data" within 10ms, got no events`) {
+		t.Errorf("unexpected failures %v", *failures)
+	}
+}
+
+func TestExpectNoEvent(t *testing.T) {
+	failures := captureFailures()
+	s := &testSubscriber{}
+	r := RecordEvents(t, s, "push", "release")
+	s.publish("release", 0)
+
+	r.ExpectNoEvent("push", 10*time.Millisecond)
+	if len(*failures) != 0 {
+		t.Errorf("unexpected failures %v", *failures)
+	}
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		s.publish("push", 1)
+	}()
+	r.ExpectNoEvent("push", time.Second)
+	if len(*failures) != 1 || !strings.HasSuffix((*failures)[0], "unexpected event push(1)") {
+		t.Errorf("unexpected failures %v", *failures)
+	}
+}
+
+func TestRecordEventsError(t *testing.T) {
+	failures := captureFailures()
+	RecordEvents(t, &testSubscriber{}, "invalid")
+	if len(*failures) != 1 || !strings.HasSuffix((*failures)[0], `cannot record event "invalid": no such event`) {
+		t.Errorf("unexpected failures %v", *failures)
+	}
+}
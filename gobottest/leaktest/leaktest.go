@@ -0,0 +1,41 @@
+// Package leaktest checks in tests that the Devices and Connections release
+// the goroutines, tickers and files they registered with gobot.Acquire or
+// gobot.Go once halted:
+//
+//	d := NewButtonDriver(a, "1")
+//	d.Start()
+//	d.Halt()
+//	leaktest.Check(t, d)
+package leaktest // import "gobot.io/x/gobot/gobottest/leaktest"
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Timeout is how long Check waits for the resources to be released
+var Timeout = time.Second
+
+// Check fails the test when one of the owners still holds resources after
+// Timeout, listing them.
+func Check(t testing.TB, owners ...interface{}) {
+	t.Helper()
+	for _, owner := range owners {
+		if err := gobot.WaitReleased(owner, Timeout); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// Held fails the test when owner holds no resource, for the tests checking
+// that a Device acquires them.
+func Held(t testing.TB, owner interface{}) []gobot.Resource {
+	t.Helper()
+	held := gobot.HeldResources(owner)
+	if len(held) == 0 {
+		t.Errorf("%T holds no resource", owner)
+	}
+	return held
+}
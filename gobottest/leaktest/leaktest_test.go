@@ -0,0 +1,52 @@
+package leaktest
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+type owner struct{ name string }
+
+func (o *owner) Name() string { return o.name }
+
+// recorder records the failures of a test
+type recorder struct {
+	testing.TB
+	errors []interface{}
+}
+
+func (r *recorder) Helper()                              {}
+func (r *recorder) Error(args ...interface{})            { r.errors = append(r.errors, args...) }
+func (r *recorder) Errorf(f string, args ...interface{}) { r.errors = append(r.errors, f) }
+
+func TestCheck(t *testing.T) {
+	Timeout = 20 * time.Millisecond
+	defer func() { Timeout = time.Second }()
+
+	o := &owner{name: "button"}
+	halt := make(chan bool)
+	gobot.Go(o, "poller", func() { <-halt })
+	release := gobot.Acquire(o, gobot.TickerResource, "sampling")
+
+	r := &recorder{}
+	held := Held(r, o)
+	if len(held) != 2 || held[0].Name != "poller" {
+		t.Errorf("unexpected resources %v", held)
+	}
+
+	Check(r, o)
+	if len(r.errors) != 1 || r.errors[0].(error).Error() != "button leaked goroutine poller, ticker sampling" {
+		t.Errorf("unexpected failures %v", r.errors)
+	}
+
+	close(halt)
+	release()
+	r = &recorder{}
+	Check(r, o)
+	Held(r, o)
+	if len(r.errors) != 1 {
+		t.Errorf("unexpected failures %v", r.errors)
+	}
+}
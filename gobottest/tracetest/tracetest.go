@@ -0,0 +1,126 @@
+// Package tracetest records in tests the Spans started with the
+// DefaultTracer:
+//
+//	rec := tracetest.NewRecorder()
+//	gobot.SetDefaultTracer(rec)
+//	defer gobot.SetDefaultTracer(nil)
+//	robot.Start()
+//	spans := rec.Spans(gobot.DeviceStartSpan)
+package tracetest // import "gobot.io/x/gobot/gobottest/tracetest"
+
+import (
+	"context"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// Span is a Span recorded by a Recorder. Spans are numbered from 1 in the
+// order they are started, ParentID being 0 for the root of a trace.
+type Span struct {
+	ID         int
+	ParentID   int
+	Name       string
+	Attributes map[string]interface{}
+	Errors     []error
+	Ended      bool
+}
+
+// Recorder is a gobot.Tracer recording the Spans it starts
+type Recorder struct {
+	spans []*Span
+	mutex sync.Mutex
+}
+
+type spanKey struct{}
+
+// NewRecorder returns a new Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Start starts and records a Span, the child of the one in ctx
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...gobot.Attribute) (context.Context, gobot.Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	s := &Span{ID: len(r.spans) + 1, Name: name, Attributes: make(map[string]interface{})}
+	if parent, ok := ctx.Value(spanKey{}).(*span); ok {
+		s.ParentID = parent.id
+	}
+	for _, attr := range attrs {
+		s.Attributes[attr.Key] = attr.Value
+	}
+	r.spans = append(r.spans, s)
+	sp := &span{id: s.ID, recorder: r}
+	return context.WithValue(ctx, spanKey{}, sp), sp
+}
+
+// Spans returns copies of the recorded Spans with the given names, or of
+// all of them without names
+func (r *Recorder) Spans(names ...string) []Span {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	spans := []Span{}
+	for _, s := range r.spans {
+		if len(names) > 0 && !contains(names, s.Name) {
+			continue
+		}
+		c := *s
+		c.Attributes = make(map[string]interface{})
+		for k, v := range s.Attributes {
+			c.Attributes[k] = v
+		}
+		c.Errors = append([]error(nil), s.Errors...)
+		spans = append(spans, c)
+	}
+	return spans
+}
+
+// Reset forgets the recorded Spans
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// span is the gobot.Span updating its recorded Span
+type span struct {
+	id       int
+	recorder *Recorder
+}
+
+func (s *span) update(f func(*Span)) {
+	s.recorder.mutex.Lock()
+	defer s.recorder.mutex.Unlock()
+	for _, recorded := range s.recorder.spans {
+		if recorded.ID == s.id {
+			f(recorded)
+			return
+		}
+	}
+}
+
+func (s *span) SetAttributes(attrs ...gobot.Attribute) {
+	s.update(func(recorded *Span) {
+		for _, attr := range attrs {
+			recorded.Attributes[attr.Key] = attr.Value
+		}
+	})
+}
+
+func (s *span) RecordError(err error) {
+	s.update(func(recorded *Span) { recorded.Errors = append(recorded.Errors, err) })
+}
+
+func (s *span) End() {
+	s.update(func(recorded *Span) { recorded.Ended = true })
+}
@@ -0,0 +1,37 @@
+package tracetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Tracer = (*Recorder)(nil)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	ctx, parent := r.Start(context.Background(), "parent", gobot.Attr("robot", "bot"))
+	_, child := r.Start(ctx, "child")
+	child.SetAttributes(gobot.Attr("device", "led"))
+	child.RecordError(errors.New("failed"))
+	child.End()
+
+	spans := r.Spans()
+	gobottest.Assert(t, len(spans), 2)
+	gobottest.Assert(t, spans[0], Span{ID: 1, Name: "parent",
+		Attributes: map[string]interface{}{"robot": "bot"}})
+	gobottest.Assert(t, spans[1].ParentID, 1)
+	gobottest.Assert(t, spans[1].Attributes["device"], "led")
+	gobottest.Assert(t, spans[1].Errors, []error{errors.New("failed")})
+	gobottest.Assert(t, spans[1].Ended, true)
+
+	parent.End()
+	gobottest.Assert(t, r.Spans("parent")[0].Ended, true)
+	gobottest.Assert(t, len(r.Spans("other")), 0)
+
+	r.Reset()
+	gobottest.Assert(t, len(r.Spans()), 0)
+}
@@ -0,0 +1,172 @@
+package hil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// I2cCheck reads a register of an I2C device and checks that its value is
+// between Min and Max. With Min equal to Max it checks an ID register.
+type I2cCheck struct {
+	Address  int
+	Register uint8
+	Min      uint8
+	Max      uint8
+}
+
+func (c I2cCheck) String() string {
+	if c.Min == c.Max {
+		return fmt.Sprintf("0x%02X:0x%02X=0x%02X", c.Address, c.Register, c.Min)
+	}
+	return fmt.Sprintf("0x%02X:0x%02X=0x%02X-0x%02X", c.Address, c.Register, c.Min, c.Max)
+}
+
+// AnalogCheck checks that the readings of an analog pin are between Min and
+// Max.
+type AnalogCheck struct {
+	Pin string
+	Min int
+	Max int
+}
+
+// Config describes the hardware attached to the board.
+type Config struct {
+	// I2cBus is the bus of the I2C devices
+	I2cBus int
+	I2c    []I2cCheck
+	// LoopbackOut and LoopbackIn are two digital pins wired together, used
+	// to check reads, writes and the timing of events
+	LoopbackOut string
+	LoopbackIn  string
+	Analog      []AnalogCheck
+	// EventTimeout is the time allowed for an event to be published
+	EventTimeout time.Duration
+}
+
+// DefaultConfig returns a Config with no check configured
+func DefaultConfig() Config {
+	return Config{
+		I2cBus:       1,
+		EventTimeout: 100 * time.Millisecond,
+	}
+}
+
+// ConfigFromEnv returns the Config described by the GOBOT_HIL environment
+// variables.
+func ConfigFromEnv() (Config, error) {
+	return parseConfig(os.Getenv)
+}
+
+func parseConfig(getenv func(string) string) (c Config, err error) {
+	c = DefaultConfig()
+
+	if s := getenv("GOBOT_HIL_I2C_BUS"); s != "" {
+		if c.I2cBus, err = strconv.Atoi(s); err != nil {
+			return c, fmt.Errorf("Invalid GOBOT_HIL_I2C_BUS %q", s)
+		}
+	}
+
+	for _, s := range split(getenv("GOBOT_HIL_I2C")) {
+		check, err := parseI2cCheck(s)
+		if err != nil {
+			return c, err
+		}
+		c.I2c = append(c.I2c, check)
+	}
+
+	if s := getenv("GOBOT_HIL_LOOPBACK"); s != "" {
+		pins := strings.Split(s, ":")
+		if len(pins) != 2 || pins[0] == "" || pins[1] == "" {
+			return c, fmt.Errorf("Invalid GOBOT_HIL_LOOPBACK %q, expected out:in", s)
+		}
+		c.LoopbackOut, c.LoopbackIn = pins[0], pins[1]
+	}
+
+	for _, s := range split(getenv("GOBOT_HIL_ANALOG")) {
+		parts := strings.Split(s, "=")
+		if len(parts) != 2 || parts[0] == "" {
+			return c, fmt.Errorf("Invalid analog check %q, expected pin=min-max", s)
+		}
+		min, max, err := parseRange(parts[1])
+		if err != nil {
+			return c, fmt.Errorf("Invalid analog check %q: %v", s, err)
+		}
+		c.Analog = append(c.Analog, AnalogCheck{Pin: parts[0], Min: min, Max: max})
+	}
+
+	if s := getenv("GOBOT_HIL_EVENT_TIMEOUT"); s != "" {
+		if c.EventTimeout, err = time.ParseDuration(s); err != nil {
+			return c, fmt.Errorf("Invalid GOBOT_HIL_EVENT_TIMEOUT %q", s)
+		}
+	}
+	return c, nil
+}
+
+// parseI2cCheck parses address:register=value or address:register=min-max
+func parseI2cCheck(s string) (check I2cCheck, err error) {
+	invalid := fmt.Errorf("Invalid I2C check %q, expected address:register=min-max", s)
+
+	parts := strings.Split(s, "=")
+	if len(parts) != 2 {
+		return check, invalid
+	}
+	location := strings.Split(parts[0], ":")
+	if len(location) != 2 {
+		return check, invalid
+	}
+	address, err := strconv.ParseUint(location[0], 0, 7)
+	if err != nil {
+		return check, invalid
+	}
+	register, err := strconv.ParseUint(location[1], 0, 8)
+	if err != nil {
+		return check, invalid
+	}
+	min, max, err := parseRange(parts[1])
+	if err != nil || min < 0 || max > 0xFF {
+		return check, invalid
+	}
+	return I2cCheck{
+		Address:  int(address),
+		Register: uint8(register),
+		Min:      uint8(min),
+		Max:      uint8(max),
+	}, nil
+}
+
+// parseRange parses value or min-max, the values being decimal or prefixed
+// with 0x
+func parseRange(s string) (min int, max int, err error) {
+	bounds := strings.Split(s, "-")
+	if len(bounds) > 2 {
+		return 0, 0, errors.New("invalid range " + s)
+	}
+	min64, err := strconv.ParseInt(bounds[0], 0, 32)
+	if err != nil {
+		return 0, 0, errors.New("invalid range " + s)
+	}
+	max64 := min64
+	if len(bounds) == 2 {
+		if max64, err = strconv.ParseInt(bounds[1], 0, 32); err != nil {
+			return 0, 0, errors.New("invalid range " + s)
+		}
+	}
+	if min64 > max64 {
+		return 0, 0, errors.New("invalid range " + s)
+	}
+	return int(min64), int(max64), nil
+}
+
+func split(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
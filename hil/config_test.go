@@ -0,0 +1,68 @@
+package hil
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig(env(map[string]string{
+		"GOBOT_HIL_I2C_BUS":       "0",
+		"GOBOT_HIL_I2C":           "0x68:0x75=0x68, 0x48:0=16-0x30",
+		"GOBOT_HIL_LOOPBACK":      "17:27",
+		"GOBOT_HIL_ANALOG":        "A0=100-900,A1=5",
+		"GOBOT_HIL_EVENT_TIMEOUT": "250ms",
+	}))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.I2cBus, 0)
+	gobottest.Assert(t, c.I2c, []I2cCheck{
+		{Address: 0x68, Register: 0x75, Min: 0x68, Max: 0x68},
+		{Address: 0x48, Register: 0x00, Min: 0x10, Max: 0x30},
+	})
+	gobottest.Assert(t, c.LoopbackOut, "17")
+	gobottest.Assert(t, c.LoopbackIn, "27")
+	gobottest.Assert(t, c.Analog, []AnalogCheck{{"A0", 100, 900}, {"A1", 5, 5}})
+	gobottest.Assert(t, c.EventTimeout, 250*time.Millisecond)
+}
+
+func TestParseConfigDefaults(t *testing.T) {
+	c, err := parseConfig(env(nil))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c, DefaultConfig())
+	gobottest.Assert(t, c.I2cBus, 1)
+	gobottest.Assert(t, c.EventTimeout, 100*time.Millisecond)
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	for key, val := range map[string]string{
+		"GOBOT_HIL_I2C_BUS":       "one",
+		"GOBOT_HIL_LOOPBACK":      "17",
+		"GOBOT_HIL_EVENT_TIMEOUT": "soon",
+	} {
+		_, err := parseConfig(env(map[string]string{key: val}))
+		gobottest.Refute(t, err, nil)
+	}
+
+	for _, check := range []string{"0x68=0x68", "0x68:0x75", "0x80:0x75=1", "0x68:0x100=1", "0x68:0x75=0x30-0x10", "0x68:0x75=256"} {
+		_, err := parseConfig(env(map[string]string{"GOBOT_HIL_I2C": check}))
+		gobottest.Refute(t, err, nil)
+	}
+	_, err := parseConfig(env(map[string]string{"GOBOT_HIL_I2C": "0x68"}))
+	gobottest.Assert(t, err.Error(), `Invalid I2C check "0x68", expected address:register=min-max`)
+
+	for _, check := range []string{"A0", "=1-2", "A0=x", "A0=1-2-3"} {
+		_, err := parseConfig(env(map[string]string{"GOBOT_HIL_ANALOG": check}))
+		gobottest.Refute(t, err, nil)
+	}
+}
+
+func TestI2cCheckString(t *testing.T) {
+	gobottest.Assert(t, I2cCheck{Address: 0x68, Register: 0x75, Min: 0x68, Max: 0x68}.String(), "0x68:0x75=0x68")
+	gobottest.Assert(t, I2cCheck{Address: 0x48, Min: 1, Max: 2}.String(), "0x48:0x00=0x01-0x02")
+}
@@ -0,0 +1,40 @@
+/*
+Package hil runs a standard hardware-in-the-loop test suite against real
+hardware attached to a board, to validate drivers and adaptors on the actual
+bus rather than with mocks.
+
+The suite is configured with environment variables:
+
+	GOBOT_HIL_I2C_BUS        I2C bus number, 1 by default
+	GOBOT_HIL_I2C            register checks, address:register=value or
+	                         address:register=min-max, separated by commas,
+	                         for example "0x68:0x75=0x68,0x48:0x00=0x10-0x30"
+	GOBOT_HIL_LOOPBACK       output and input pins wired together, "out:in"
+	GOBOT_HIL_ANALOG         analog read ranges, pin=min-max separated by
+	                         commas, for example "A0=100-900"
+	GOBOT_HIL_EVENT_TIMEOUT  time allowed for an event, 100ms by default
+
+The hardware tests of this package are behind the hil build tag, and use the
+generic linux sysfs adaptor of this package:
+
+	GOBOT_HIL_I2C=0x68:0x75=0x68 GOBOT_HIL_LOOPBACK=17:27 go test -tags hil gobot.io/x/gobot/hil
+
+Other boards can run the same suite from their own tests:
+
+	// +build hil
+
+	func TestHardware(t *testing.T) {
+		c, err := hil.ConfigFromEnv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := raspi.NewAdaptor()
+		a.Connect()
+		defer a.Finalize()
+		hil.Run(t, a, c)
+	}
+
+The checks which are not configured, or not supported by the adaptor, are
+skipped.
+*/
+package hil // import "gobot.io/x/gobot/hil"
@@ -0,0 +1,20 @@
+// +build hil
+
+package hil
+
+import "testing"
+
+func TestHardware(t *testing.T) {
+	c, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewSysfsAdaptor()
+	if err := a.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Finalize()
+
+	Run(t, a, c)
+}
@@ -0,0 +1,138 @@
+package hil
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+)
+
+// AnalogSamples is the number of readings checked for each analog pin
+var AnalogSamples = 10
+
+// Run runs the standard suite against the connected adaptor a, as subtests
+// of t:
+//
+//	i2c/<check>  reads the registers of the I2C checks, if a is an
+//	             i2c.Connector
+//	loopback     writes the loopback output pin and reads it back on the
+//	             input pin, then checks that a ButtonDriver on the input pin
+//	             publishes its events within the EventTimeout
+//	analog/<pin> reads the analog pins, if a is an aio.AnalogReader
+func Run(t *testing.T, a gobot.Connection, c Config) {
+	t.Run("i2c", func(t *testing.T) {
+		connector, ok := a.(i2c.Connector)
+		if !ok {
+			t.Skip(a.Name() + " is not an I2C connector")
+		}
+		if len(c.I2c) == 0 {
+			t.Skip("no I2C check configured")
+		}
+		for _, check := range c.I2c {
+			check := check
+			t.Run(check.String(), func(t *testing.T) {
+				CheckI2c(t, connector, c.I2cBus, check)
+			})
+		}
+	})
+
+	t.Run("loopback", func(t *testing.T) {
+		rw, ok := a.(digitalReadWriter)
+		if !ok {
+			t.Skip(a.Name() + " cannot read and write digital pins")
+		}
+		if c.LoopbackOut == "" {
+			t.Skip("no loopback configured")
+		}
+		CheckLoopback(t, rw, c.LoopbackOut, c.LoopbackIn, c.EventTimeout)
+	})
+
+	t.Run("analog", func(t *testing.T) {
+		reader, ok := a.(aio.AnalogReader)
+		if !ok {
+			t.Skip(a.Name() + " is not an analog reader")
+		}
+		if len(c.Analog) == 0 {
+			t.Skip("no analog check configured")
+		}
+		for _, check := range c.Analog {
+			check := check
+			t.Run(check.Pin, func(t *testing.T) {
+				CheckAnalog(t, reader, check)
+			})
+		}
+	})
+}
+
+type digitalReadWriter interface {
+	gpio.DigitalReader
+	gpio.DigitalWriter
+}
+
+// CheckI2c reads the register of check on bus and fails t if the value is
+// out of range.
+func CheckI2c(t *testing.T, connector i2c.Connector, bus int, check I2cCheck) {
+	conn, err := connector.GetConnection(check.Address, bus)
+	if err != nil {
+		t.Fatalf("Connecting to I2C device 0x%02X on bus %d: %v", check.Address, bus, err)
+	}
+
+	val, err := conn.ReadByteData(check.Register)
+	if err != nil {
+		t.Fatalf("Reading register 0x%02X: %v", check.Register, err)
+	}
+	if val < check.Min || val > check.Max {
+		t.Errorf("Register 0x%02X of device 0x%02X is 0x%02X, expected %s",
+			check.Register, check.Address, val, check)
+	}
+}
+
+// CheckAnalog reads the pin of check AnalogSamples times and fails t if a
+// reading is out of range.
+func CheckAnalog(t *testing.T, reader aio.AnalogReader, check AnalogCheck) {
+	for i := 0; i < AnalogSamples; i++ {
+		val, err := reader.AnalogRead(check.Pin)
+		if err != nil {
+			t.Fatalf("Reading pin %s: %v", check.Pin, err)
+		}
+		if val < check.Min || val > check.Max {
+			t.Errorf("Pin %s read %d, expected %d-%d", check.Pin, val, check.Min, check.Max)
+			return
+		}
+	}
+}
+
+// CheckLoopback checks the loopback wiring of the out and in pins: the levels
+// written on out must be read on in, and a ButtonDriver polling in must
+// publish its push and release events within timeout of the writes.
+func CheckLoopback(t *testing.T, rw digitalReadWriter, out string, in string, timeout time.Duration) {
+	for _, level := range []byte{1, 0} {
+		if err := rw.DigitalWrite(out, level); err != nil {
+			t.Fatalf("Writing pin %s: %v", out, err)
+		}
+		val, err := rw.DigitalRead(in)
+		if err != nil {
+			t.Fatalf("Reading pin %s: %v", in, err)
+		}
+		if val != int(level) {
+			t.Fatalf("Pin %s read %d after writing %d on pin %s", in, val, level, out)
+		}
+	}
+
+	// poll fast enough for the polling interval not to count in the timeout
+	button := gpio.NewButtonDriver(rw, in, timeout/10)
+	events := gobottest.RecordEvents(t, button, gpio.ButtonPush, gpio.ButtonRelease)
+	if err := button.Start(); err != nil {
+		t.Fatalf("Starting button: %v", err)
+	}
+	defer button.Halt()
+
+	rw.DigitalWrite(out, 1)
+	events.ExpectEvent(gpio.ButtonPush, timeout, nil)
+	rw.DigitalWrite(out, 0)
+	events.ExpectEvent(gpio.ButtonRelease, timeout, nil)
+}
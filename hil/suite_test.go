@@ -0,0 +1,83 @@
+package hil
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/drivers/gpio/gpiotest"
+	"gobot.io/x/gobot/drivers/i2c/i2ctest"
+	"gobot.io/x/gobot/gobottest"
+)
+
+// testBoard is a virtual board with I2C devices, analog inputs and two
+// digital pins wired together.
+type testBoard struct {
+	*i2ctest.Adaptor
+	gpio *gpiotest.Adaptor
+	out  string
+	in   string
+}
+
+func newTestBoard() *testBoard {
+	return &testBoard{
+		Adaptor: i2ctest.NewAdaptor(),
+		gpio:    gpiotest.NewAdaptor(),
+		out:     "17",
+		in:      "27",
+	}
+}
+
+func (b *testBoard) DigitalRead(pin string) (int, error) { return b.gpio.DigitalRead(pin) }
+func (b *testBoard) AnalogRead(pin string) (int, error)  { return b.gpio.AnalogRead(pin) }
+
+func (b *testBoard) DigitalWrite(pin string, level byte) error {
+	if pin == b.out {
+		b.gpio.SetInput(b.in, int(level))
+	}
+	return b.gpio.DigitalWrite(pin, level)
+}
+
+func TestRun(t *testing.T) {
+	b := newTestBoard()
+	b.AddDevice(0x68).SetRegister(0x75, 0x68)
+	b.AddDevice(0x48).SetRegister(0x00, 0x20)
+	b.gpio.SetAnalogInput("A0", 512)
+
+	c := DefaultConfig()
+	c.I2cBus = b.GetDefaultBus()
+	c.I2c = []I2cCheck{
+		{Address: 0x68, Register: 0x75, Min: 0x68, Max: 0x68},
+		{Address: 0x48, Register: 0x00, Min: 0x10, Max: 0x30},
+	}
+	c.LoopbackOut, c.LoopbackIn = "17", "27"
+	c.Analog = []AnalogCheck{{Pin: "A0", Min: 100, Max: 900}}
+
+	Run(t, b, c)
+
+	gobottest.Assert(t, b.gpio.Waveform("17").Values(), []int{1, 0, 1, 0})
+}
+
+type bareAdaptor struct{}
+
+func (bareAdaptor) Name() string    { return "bare" }
+func (bareAdaptor) SetName(string)  {}
+func (bareAdaptor) Connect() error  { return nil }
+func (bareAdaptor) Finalize() error { return nil }
+
+func TestRunSkipsUnsupported(t *testing.T) {
+	c := DefaultConfig()
+	c.I2c = []I2cCheck{{Address: 0x68}}
+	c.LoopbackOut, c.LoopbackIn = "17", "27"
+	c.Analog = []AnalogCheck{{Pin: "A0"}}
+	Run(t, bareAdaptor{}, c)
+
+	// nothing configured
+	Run(t, newTestBoard(), DefaultConfig())
+}
+
+func TestCheckLoopbackTiming(t *testing.T) {
+	b := newTestBoard()
+	start := time.Now()
+	CheckLoopback(t, b, "17", "27", 50*time.Millisecond)
+	gobottest.Assert(t, time.Since(start) < 100*time.Millisecond, true)
+}
@@ -0,0 +1,177 @@
+package hil
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/sysfs"
+)
+
+// sysfsPwmPeriod is the period of the PWM pins in nanoseconds, 50Hz suiting
+// both servos and LEDs
+const sysfsPwmPeriod = 20000000
+
+// SysfsAdaptor is a generic linux adaptor using the sysfs GPIO numbers, the
+// channels of /sys/class/pwm/pwmchip0 as PWM pins and the /dev/i2c-N buses,
+// enough to run the suite on most linux boards.
+type SysfsAdaptor struct {
+	name        string
+	digitalPins map[int]*sysfs.DigitalPin
+	pwmPins     map[int]*sysfs.PWMPin
+	i2cBuses    map[int]i2c.I2cDevice
+	mutex       sync.Mutex
+}
+
+// NewSysfsAdaptor returns a new SysfsAdaptor
+func NewSysfsAdaptor() *SysfsAdaptor {
+	return &SysfsAdaptor{
+		name:        gobot.DefaultName("HIL"),
+		digitalPins: make(map[int]*sysfs.DigitalPin),
+		pwmPins:     make(map[int]*sysfs.PWMPin),
+		i2cBuses:    make(map[int]i2c.I2cDevice),
+	}
+}
+
+// Name returns the SysfsAdaptor name
+func (a *SysfsAdaptor) Name() string { return a.name }
+
+// SetName sets the SysfsAdaptor name
+func (a *SysfsAdaptor) SetName(n string) { a.name = n }
+
+// Connect does nothing, the pins and buses are opened when first used
+func (a *SysfsAdaptor) Connect() error { return nil }
+
+// Finalize unexports the pins and closes the buses
+func (a *SysfsAdaptor) Finalize() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, pin := range a.digitalPins {
+		if e := pin.Unexport(); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	for _, pin := range a.pwmPins {
+		if e := pin.Unexport(); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	for _, bus := range a.i2cBuses {
+		if e := i2c.CloseBus(bus); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	a.digitalPins = make(map[int]*sysfs.DigitalPin)
+	a.pwmPins = make(map[int]*sysfs.PWMPin)
+	a.i2cBuses = make(map[int]i2c.I2cDevice)
+	return
+}
+
+func (a *SysfsAdaptor) digitalPin(pin string, dir string) (*sysfs.DigitalPin, error) {
+	i, err := strconv.Atoi(pin)
+	if err != nil {
+		return nil, fmt.Errorf("Not a valid pin %q", pin)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	p, ok := a.digitalPins[i]
+	if !ok {
+		p = sysfs.NewDigitalPin(i)
+		if err = p.Export(); err != nil {
+			return nil, err
+		}
+		a.digitalPins[i] = p
+	}
+	return p, p.Direction(dir)
+}
+
+// DigitalRead reads the value of the sysfs GPIO pin
+func (a *SysfsAdaptor) DigitalRead(pin string) (int, error) {
+	p, err := a.digitalPin(pin, sysfs.IN)
+	if err != nil {
+		return 0, err
+	}
+	return p.Read()
+}
+
+// DigitalWrite writes val to the sysfs GPIO pin
+func (a *SysfsAdaptor) DigitalWrite(pin string, val byte) error {
+	p, err := a.digitalPin(pin, sysfs.OUT)
+	if err != nil {
+		return err
+	}
+	return p.Write(int(val))
+}
+
+func (a *SysfsAdaptor) pwmPin(pin string) (*sysfs.PWMPin, error) {
+	i, err := strconv.Atoi(pin)
+	if err != nil {
+		return nil, fmt.Errorf("Not a valid pin %q", pin)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	p, ok := a.pwmPins[i]
+	if !ok {
+		p = sysfs.NewPWMPin(i)
+		if err = p.Export(); err != nil {
+			return nil, err
+		}
+		if err = p.SetPeriod(sysfsPwmPeriod); err != nil {
+			return nil, err
+		}
+		if err = p.Enable(true); err != nil {
+			return nil, err
+		}
+		a.pwmPins[i] = p
+	}
+	return p, nil
+}
+
+// PwmWrite sets the duty cycle of the PWM channel to val/255
+func (a *SysfsAdaptor) PwmWrite(pin string, val byte) error {
+	p, err := a.pwmPin(pin)
+	if err != nil {
+		return err
+	}
+	duty := gobot.FromScale(float64(val), 0, 255)
+	return p.SetDutyCycle(uint32(duty * sysfsPwmPeriod))
+}
+
+// ServoWrite sets the PWM channel to the pulse width of a servo at angle
+// degrees, from 0.5ms at 0 to 2.5ms at 180
+func (a *SysfsAdaptor) ServoWrite(pin string, angle byte) error {
+	p, err := a.pwmPin(pin)
+	if err != nil {
+		return err
+	}
+	const minDuty = 500000
+	const maxDuty = 2500000
+	duty := gobot.ToScale(gobot.FromScale(float64(angle), 0, 180), minDuty, maxDuty)
+	return p.SetDutyCycle(uint32(duty))
+}
+
+// GetConnection returns a connection to the device at address on
+// /dev/i2c-<bus>
+func (a *SysfsAdaptor) GetConnection(address int, bus int) (i2c.Connection, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	device, ok := a.i2cBuses[bus]
+	if !ok {
+		d, err := sysfs.NewI2cDevice(fmt.Sprintf("/dev/i2c-%d", bus))
+		if err != nil {
+			return nil, err
+		}
+		device = d
+		a.i2cBuses[bus] = device
+	}
+	return i2c.NewConnection(device, address), nil
+}
+
+// GetDefaultBus returns 1, the usual user bus of linux boards
+func (a *SysfsAdaptor) GetDefaultBus() int { return 1 }
@@ -0,0 +1,100 @@
+package hil
+
+import (
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/sysfs"
+)
+
+var _ gobot.Adaptor = (*SysfsAdaptor)(nil)
+
+var _ gpio.DigitalReader = (*SysfsAdaptor)(nil)
+var _ gpio.DigitalWriter = (*SysfsAdaptor)(nil)
+var _ gpio.PwmWriter = (*SysfsAdaptor)(nil)
+var _ gpio.ServoWriter = (*SysfsAdaptor)(nil)
+var _ i2c.Connector = (*SysfsAdaptor)(nil)
+
+func TestSysfsAdaptor(t *testing.T) {
+	a := NewSysfsAdaptor()
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "HIL"), true)
+	a.SetName("NewName")
+	gobottest.Assert(t, a.Name(), "NewName")
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.GetDefaultBus(), 1)
+}
+
+func TestSysfsAdaptorDigitalIO(t *testing.T) {
+	fs := sysfs.NewMockFilesystem([]string{
+		"/sys/class/gpio/export",
+		"/sys/class/gpio/unexport",
+		"/sys/class/gpio/gpio17/value",
+		"/sys/class/gpio/gpio17/direction",
+		"/sys/class/gpio/gpio27/value",
+		"/sys/class/gpio/gpio27/direction",
+	})
+	sysfs.SetFilesystem(fs)
+
+	a := NewSysfsAdaptor()
+	gobottest.Assert(t, a.DigitalWrite("17", 1), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio17/value"].Contents, "1")
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio17/direction"].Contents, "out")
+
+	fs.Files["/sys/class/gpio/gpio27/value"].Contents = "1"
+	val, err := a.DigitalRead("27")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio27/direction"].Contents, "in")
+
+	gobottest.Assert(t, a.DigitalWrite("P1", 1).Error(), `Not a valid pin "P1"`)
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+
+func TestSysfsAdaptorPwm(t *testing.T) {
+	fs := sysfs.NewMockFilesystem([]string{
+		"/sys/class/pwm/pwmchip0/export",
+		"/sys/class/pwm/pwmchip0/unexport",
+		"/sys/class/pwm/pwmchip0/pwm0/enable",
+		"/sys/class/pwm/pwmchip0/pwm0/period",
+		"/sys/class/pwm/pwmchip0/pwm0/duty_cycle",
+	})
+	sysfs.SetFilesystem(fs)
+
+	a := NewSysfsAdaptor()
+	gobottest.Assert(t, a.PwmWrite("0", 255), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/pwm/pwmchip0/pwm0/period"].Contents, "20000000")
+	gobottest.Assert(t, fs.Files["/sys/class/pwm/pwmchip0/pwm0/enable"].Contents, "1")
+	gobottest.Assert(t, fs.Files["/sys/class/pwm/pwmchip0/pwm0/duty_cycle"].Contents, "20000000")
+
+	gobottest.Assert(t, a.ServoWrite("0", 90), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/pwm/pwmchip0/pwm0/duty_cycle"].Contents, "1500000")
+
+	gobottest.Assert(t, a.ServoWrite("pwm0", 90).Error(), `Not a valid pin "pwm0"`)
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/pwm/pwmchip0/unexport"].Contents, "0")
+}
+
+func TestSysfsAdaptorI2c(t *testing.T) {
+	fs := sysfs.NewMockFilesystem([]string{
+		"/dev/i2c-1",
+	})
+	sysfs.SetFilesystem(fs)
+	sysfs.SetSyscall(&sysfs.MockSyscall{})
+
+	a := NewSysfsAdaptor()
+	con, err := a.GetConnection(0x68, 1)
+	gobottest.Assert(t, err, nil)
+	con.Write([]byte{0x00, 0x01})
+	data := []byte{42, 42}
+	con.Read(data)
+	gobottest.Assert(t, data, []byte{0x00, 0x01})
+
+	_, err = a.GetConnection(0x68, 2)
+	gobottest.Refute(t, err, nil)
+
+	gobottest.Assert(t, a.Finalize(), nil)
+}
@@ -0,0 +1,91 @@
+package gobot
+
+import (
+	"path"
+	"sync"
+	"time"
+)
+
+// HistoryEvent is an Event of a Device recorded in the event history of a
+// Robot.
+type HistoryEvent struct {
+	// Seq is the sequence number of the Event among the Events recorded by
+	// the Robot, starting at 1
+	Seq    uint64      `json:"seq"`
+	Device string      `json:"device"`
+	Name   string      `json:"name"`
+	Data   interface{} `json:"data"`
+	Time   time.Time   `json:"time"`
+}
+
+// eventHistory is a ring buffer of the last Events of the Devices of a Robot
+type eventHistory struct {
+	events    []HistoryEvent
+	next      int
+	seq       uint64
+	retention time.Duration
+	mutex     sync.Mutex
+}
+
+func newEventHistory(size int, retention time.Duration) *eventHistory {
+	return &eventHistory{events: make([]HistoryEvent, 0, size), retention: retention}
+}
+
+func (h *eventHistory) record(device string, evt *Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.seq++
+	e := HistoryEvent{Seq: h.seq, Device: device, Name: evt.Name, Data: evt.Data, Time: evt.Time}
+	if len(h.events) < cap(h.events) {
+		h.events = append(h.events, e)
+		return
+	}
+	h.events[h.next] = e
+	h.next = (h.next + 1) % len(h.events)
+}
+
+// query returns the Events recorded after since, named after pattern unless
+// it is empty, oldest first.
+func (h *eventHistory) query(since uint64, pattern string) []HistoryEvent {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var oldest time.Time
+	if h.retention > 0 {
+		oldest = DefaultClock().Now().Add(-h.retention)
+	}
+	events := []HistoryEvent{}
+	for i := range h.events {
+		e := h.events[(h.next+i)%len(h.events)]
+		if e.Seq <= since || e.Time.Before(oldest) {
+			continue
+		}
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, e.Name); !ok {
+				continue
+			}
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// EventHistory returns the Events of the Devices recorded after the sequence
+// number since, oldest first, so that a client which received the Events up
+// to since can catch up. Unless pattern is empty, only the Events whose name
+// matches pattern, using path.Match syntax, are returned. Otherwise a gap
+// between since and the sequence number of the first Event means that
+// Events were overwritten or have expired in the meantime.
+//
+// The history is only kept when EventHistorySize is set before the Robot
+// starts.
+func (r *Robot) EventHistory(since uint64, pattern string) []HistoryEvent {
+	r.telemetry.mutex.Lock()
+	h := r.telemetry.history
+	r.telemetry.mutex.Unlock()
+	if h == nil {
+		return []HistoryEvent{}
+	}
+	return h.query(since, pattern)
+}
@@ -0,0 +1,76 @@
+package gobot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestEventHistory(t *testing.T) {
+	h := newEventHistory(3, 0)
+	gobottest.Assert(t, h.query(0, ""), []HistoryEvent{})
+
+	for i, name := range []string{"a", "b", "c", "d"} {
+		h.record("led", &Event{Name: name, Data: i})
+	}
+
+	// the oldest event is overwritten
+	events := h.query(0, "")
+	gobottest.Assert(t, len(events), 3)
+	gobottest.Assert(t, events[0].Seq, uint64(2))
+	gobottest.Assert(t, events[0].Name, "b")
+	gobottest.Assert(t, events[0].Device, "led")
+	gobottest.Assert(t, events[2].Seq, uint64(4))
+
+	events = h.query(3, "")
+	gobottest.Assert(t, len(events), 1)
+	gobottest.Assert(t, events[0].Name, "d")
+	gobottest.Assert(t, len(h.query(0, "[bd]")), 2)
+}
+
+func TestEventHistoryRetention(t *testing.T) {
+	clock := NewFakeClock(time.Unix(100, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	h := newEventHistory(10, time.Minute)
+	h.record("led", NewEvent("on", nil))
+	clock.Advance(50 * time.Second)
+	h.record("led", NewEvent("off", nil))
+	gobottest.Assert(t, len(h.query(0, "")), 2)
+
+	clock.Advance(20 * time.Second)
+	events := h.query(0, "")
+	gobottest.Assert(t, len(events), 1)
+	gobottest.Assert(t, events[0].Name, "off")
+}
+
+func TestRobotEventHistory(t *testing.T) {
+	r := newTestRobot("Robot1")
+	gobottest.Assert(t, r.EventHistory(0, ""), []HistoryEvent{})
+
+	led := &compositeTestDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "led", "0"),
+		Eventer:    NewEventer(),
+		log:        &[]string{},
+		mutex:      &sync.Mutex{},
+	}
+	r.AddDevice(led)
+	r.EventHistorySize = 10
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	led.Publish("on", 1)
+	led.Publish("off", 0)
+	deadline := time.Now().Add(time.Second)
+	for len(r.EventHistory(0, "")) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	events := r.EventHistory(1, "")
+	gobottest.Assert(t, len(events), 1)
+	gobottest.Assert(t, events[0].Device, "led")
+	gobottest.Assert(t, events[0].Name, "off")
+	gobottest.Assert(t, events[0].Data, 0)
+}
@@ -0,0 +1,205 @@
+package gobot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// LogLevel is the severity of a log message
+type LogLevel int
+
+const (
+	// LogDebug is the level for verbose diagnostic messages
+	LogDebug LogLevel = iota
+	// LogInfo is the level for normal operational messages
+	LogInfo
+	// LogWarn is the level for unexpected but recoverable conditions
+	LogWarn
+	// LogError is the level for failures
+	LogError
+	// LogOff disables all log output
+	LogOff
+)
+
+// String returns the name of the LogLevel
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	case LogOff:
+		return "OFF"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// Logger is the interface which describes how Gobot components emit
+// structured, leveled log messages.
+//
+// Every message takes a list of alternating key/value pairs which are
+// appended to the message, e.g.:
+//
+//	logger.Info("Starting device", "pin", "13")
+type Logger interface {
+	// Debug logs a message at LogDebug level
+	Debug(msg string, keyvals ...interface{})
+	// Info logs a message at LogInfo level
+	Info(msg string, keyvals ...interface{})
+	// Warn logs a message at LogWarn level
+	Warn(msg string, keyvals ...interface{})
+	// Error logs a message at LogError level
+	Error(msg string, keyvals ...interface{})
+	// WithComponent returns a Logger for a named sub-component. Component
+	// names are nested using a "." separator, e.g. "Robot1.Device1".
+	WithComponent(name string) Logger
+	// SetLevel sets the minimum level logged for a component and all its
+	// sub-components. An empty component sets the default level.
+	SetLevel(component string, level LogLevel)
+	// Level returns the minimum level logged for a component.
+	Level(component string) LogLevel
+}
+
+// Loggable is the interface which describes an Adaptor or Driver that accepts
+// a Logger from its Robot.
+type Loggable interface {
+	SetLogger(l Logger)
+}
+
+type logLevels struct {
+	sync.RWMutex
+	def        LogLevel
+	components map[string]LogLevel
+}
+
+type logger struct {
+	component string
+	levels    *logLevels
+	output    func(calldepth int, s string) error
+}
+
+var (
+	defaultLogger      Logger = NewLogger()
+	defaultLoggerMutex sync.RWMutex
+)
+
+// NewLogger returns a new Logger that writes to the standard library log
+// package with a default level of LogInfo.
+func NewLogger() Logger {
+	return &logger{
+		levels: &logLevels{
+			def:        LogInfo,
+			components: make(map[string]LogLevel),
+		},
+		output: log.Output,
+	}
+}
+
+// DefaultLogger returns the Logger used by components that have not had
+// one injected.
+func DefaultLogger() Logger {
+	defaultLoggerMutex.RLock()
+	defer defaultLoggerMutex.RUnlock()
+	return defaultLogger
+}
+
+// SetDefaultLogger replaces the Logger used by components that have not had
+// one injected.
+func SetDefaultLogger(l Logger) {
+	defaultLoggerMutex.Lock()
+	defer defaultLoggerMutex.Unlock()
+	defaultLogger = l
+}
+
+// Debug logs a message at LogDebug level
+func (l *logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LogDebug, msg, keyvals)
+}
+
+// Info logs a message at LogInfo level
+func (l *logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LogInfo, msg, keyvals)
+}
+
+// Warn logs a message at LogWarn level
+func (l *logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LogWarn, msg, keyvals)
+}
+
+// Error logs a message at LogError level
+func (l *logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LogError, msg, keyvals)
+}
+
+// WithComponent returns a Logger for a named sub-component
+func (l *logger) WithComponent(name string) Logger {
+	component := name
+	if l.component != "" && name != "" {
+		component = l.component + "." + name
+	} else if name == "" {
+		component = l.component
+	}
+	return &logger{component: component, levels: l.levels, output: l.output}
+}
+
+// SetLevel sets the minimum level logged for a component
+func (l *logger) SetLevel(component string, level LogLevel) {
+	l.levels.Lock()
+	defer l.levels.Unlock()
+	if component == "" {
+		l.levels.def = level
+		return
+	}
+	l.levels.components[component] = level
+}
+
+// Level returns the minimum level logged for a component, walking up the
+// component hierarchy until a configured level is found.
+func (l *logger) Level(component string) LogLevel {
+	l.levels.RLock()
+	defer l.levels.RUnlock()
+	for component != "" {
+		if level, ok := l.levels.components[component]; ok {
+			return level
+		}
+		i := strings.LastIndex(component, ".")
+		if i < 0 {
+			break
+		}
+		component = component[:i]
+	}
+	return l.levels.def
+}
+
+func (l *logger) log(level LogLevel, msg string, keyvals []interface{}) {
+	if level < l.Level(l.component) {
+		return
+	}
+	l.output(3, formatLogLine(level, l.component, msg, keyvals))
+}
+
+func formatLogLine(level LogLevel, component string, msg string, keyvals []interface{}) string {
+	line := level.String()
+	if component != "" {
+		line += " [" + component + "]"
+	}
+	line += " " + msg
+	for i := 0; i < len(keyvals); i += 2 {
+		var val interface{} = "MISSING"
+		if i+1 < len(keyvals) {
+			val = keyvals[i+1]
+		}
+		s := fmt.Sprint(val)
+		if strings.ContainsAny(s, " \t\"=") {
+			s = fmt.Sprintf("%q", s)
+		}
+		line += fmt.Sprintf(" %v=%s", keyvals[i], s)
+	}
+	return line
+}
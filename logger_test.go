@@ -0,0 +1,109 @@
+package gobot
+
+import (
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func newTestLogger() (*logger, *[]string) {
+	lines := []string{}
+	l := NewLogger().(*logger)
+	l.output = func(calldepth int, s string) error {
+		lines = append(lines, s)
+		return nil
+	}
+	return l, &lines
+}
+
+func TestLoggerLevels(t *testing.T) {
+	l, lines := newTestLogger()
+	l.Debug("hidden")
+	l.Info("shown")
+	l.Warn("warning")
+	l.Error("failure")
+	gobottest.Assert(t, *lines, []string{"INFO shown", "WARN warning", "ERROR failure"})
+
+	l.SetLevel("", LogDebug)
+	l.Debug("visible")
+	gobottest.Assert(t, (*lines)[3], "DEBUG visible")
+
+	l.SetLevel("", LogOff)
+	l.Error("silenced")
+	gobottest.Assert(t, len(*lines), 4)
+}
+
+func TestLoggerKeyvals(t *testing.T) {
+	l, lines := newTestLogger()
+	l.Info("Starting device", "device", "led", "pin", 13, "note", "two words", "odd")
+	gobottest.Assert(t, (*lines)[0],
+		`INFO Starting device device=led pin=13 note="two words" odd=MISSING`)
+}
+
+func TestLoggerComponentLevels(t *testing.T) {
+	l, lines := newTestLogger()
+	robot := l.WithComponent("Robot1")
+	device := robot.WithComponent("Device1")
+	other := l.WithComponent("Robot2")
+
+	l.SetLevel("Robot1", LogDebug)
+	device.Debug("from device")
+	other.Debug("from other robot")
+	gobottest.Assert(t, *lines, []string{"DEBUG [Robot1.Device1] from device"})
+
+	l.SetLevel("Robot1.Device1", LogError)
+	device.Warn("dropped")
+	robot.Debug("kept")
+	gobottest.Assert(t, len(*lines), 2)
+	gobottest.Assert(t, l.Level("Robot1.Device1"), LogError)
+	gobottest.Assert(t, l.Level("Robot1.Device2"), LogDebug)
+	gobottest.Assert(t, l.Level("Robot3"), LogInfo)
+}
+
+func TestLogLevelString(t *testing.T) {
+	gobottest.Assert(t, LogWarn.String(), "WARN")
+	gobottest.Assert(t, LogLevel(42).String(), "LogLevel(42)")
+}
+
+func TestDefaultLogger(t *testing.T) {
+	orig := DefaultLogger()
+	defer SetDefaultLogger(orig)
+
+	l, lines := newTestLogger()
+	SetDefaultLogger(l)
+	r := NewRobot("logged")
+	gobottest.Assert(t, strings.Contains((*lines)[0], "[logged] Robot initialized"), true)
+	gobottest.Assert(t, r.Logger().(*logger).component, "logged")
+}
+
+type testLoggableDriver struct {
+	*testDriver
+	logger Logger
+}
+
+func (t *testLoggableDriver) SetLogger(l Logger) { t.logger = l }
+
+func TestRobotInjectsLoggers(t *testing.T) {
+	l, _ := newTestLogger()
+	adaptor := newTestAdaptor("Connection1", "/dev/null")
+	driver := &testLoggableDriver{testDriver: newTestDriver(adaptor, "Device1", "0")}
+	r := NewRobot("Robot1", []Connection{adaptor}, []Device{driver})
+	r.SetLogger(l.WithComponent(r.Name))
+	r.AutoRun = false
+
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, driver.logger.(*logger).component, "Robot1.Device1")
+	gobottest.Assert(t, r.Stop(), nil)
+}
+
+func TestMasterSetLogger(t *testing.T) {
+	l, _ := newTestLogger()
+	g := initTestMaster()
+	g.SetLogger(l)
+	gobottest.Assert(t, g.Logger(), Logger(l))
+	gobottest.Assert(t, g.Robot("Robot1").Logger().(*logger).component, "Robot1")
+
+	r := g.AddRobot(newTestRobot("Robot4"))
+	gobottest.Assert(t, r.Logger().(*logger).component, "Robot4")
+}
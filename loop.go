@@ -0,0 +1,149 @@
+package gobot
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Overrun is the event published by a RateLoop when an iteration ends after
+// the next one was due. Its data is a LoopOverrun.
+const Overrun = "overrun"
+
+// LoopOverrun describes a late RateLoop iteration.
+type LoopOverrun struct {
+	// Iteration is the number of the late iteration, starting at 1
+	Iteration uint64
+	// Duration is the time taken by the function of the loop
+	Duration time.Duration
+	// Late is how long after the due time of the next iteration the late one
+	// ended
+	Late time.Duration
+}
+
+// LoopOption configures a RateLoop.
+type LoopOption func(*RateLoop)
+
+// LoopJitter delays each iteration by a random duration up to jitter, for
+// example to keep several robots polling a shared bus from staying in step.
+// The delay does not accumulate.
+func LoopJitter(jitter time.Duration) LoopOption {
+	return func(l *RateLoop) {
+		l.jitter = jitter
+	}
+}
+
+// RateLoop runs a function at a fixed rate. Iterations are scheduled from the
+// start of the loop rather than from the end of the previous iteration, so
+// the time taken by the function does not make the loop drift.
+type RateLoop struct {
+	Eventer
+	period     time.Duration
+	jitter     time.Duration
+	fn         func()
+	clock      Clock
+	handler    func(PanicReport)
+	record     func(time.Duration)
+	iterations uint64
+	overruns   uint64
+	halt       chan bool
+	done       chan bool
+	stopOnce   sync.Once
+}
+
+// Loop calls fn rate times per second until Stop is called on the returned
+// RateLoop. The first call happens at once. Unlike Every, an iteration never
+// overlaps the previous one: when fn runs late an Overrun event is published
+// and the schedule restarts from the end of the late iteration, instead of
+// running the missed iterations in a burst. A panic in fn is recovered and
+// passed to the handler set with SetPanicHandler.
+func Loop(rate float64, fn func(), opts ...LoopOption) *RateLoop {
+	return newRateLoop(rate, fn, handlePanic, nil, opts)
+}
+
+func newRateLoop(rate float64, fn func(), handler func(PanicReport), record func(time.Duration), opts []LoopOption) *RateLoop {
+	if rate <= 0 {
+		panic("non-positive rate for gobot.Loop")
+	}
+	l := &RateLoop{
+		Eventer: NewEventer(),
+		period:  time.Duration(float64(time.Second) / rate),
+		fn:      fn,
+		clock:   DefaultClock(),
+		handler: handler,
+		record:  record,
+		halt:    make(chan bool),
+		done:    make(chan bool),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.AddEvent(Overrun)
+	go l.run()
+	return l
+}
+
+// Period returns the time between the start of two iterations
+func (l *RateLoop) Period() time.Duration { return l.period }
+
+// Iterations returns the number of completed iterations
+func (l *RateLoop) Iterations() uint64 { return atomic.LoadUint64(&l.iterations) }
+
+// Overruns returns the number of late iterations
+func (l *RateLoop) Overruns() uint64 { return atomic.LoadUint64(&l.overruns) }
+
+// Stop stops the loop. An iteration in progress is completed, Done is closed
+// once it has.
+func (l *RateLoop) Stop() {
+	l.stopOnce.Do(func() { close(l.halt) })
+}
+
+// Done returns a channel closed when the loop has stopped
+func (l *RateLoop) Done() <-chan bool { return l.done }
+
+func (l *RateLoop) run() {
+	defer close(l.done)
+
+	start := l.clock.Now()
+	for n := 0; ; n++ {
+		due := start.Add(time.Duration(n) * l.period)
+		if wait := due.Sub(l.clock.Now()) + l.jitterDelay(); wait > 0 {
+			select {
+			case <-l.clock.After(wait):
+			case <-l.halt:
+				return
+			}
+		} else {
+			select {
+			case <-l.halt:
+				return
+			default:
+			}
+		}
+
+		begin := l.clock.Now()
+		protect("loop", l.handler, l.fn)
+		end := l.clock.Now()
+		iteration := atomic.AddUint64(&l.iterations, 1)
+		if l.record != nil {
+			l.record(end.Sub(begin))
+		}
+
+		if late := end.Sub(due.Add(l.period)); late > 0 {
+			atomic.AddUint64(&l.overruns, 1)
+			l.Publish(Overrun, LoopOverrun{
+				Iteration: iteration,
+				Duration:  end.Sub(begin),
+				Late:      late,
+			})
+			start, n = end, -1
+		}
+	}
+}
+
+func (l *RateLoop) jitterDelay() time.Duration {
+	if l.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(Rand(int(l.jitter)))
+}
@@ -0,0 +1,127 @@
+package gobot
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestLoop(t *testing.T) {
+	var runs int32
+	l := Loop(200, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+	gobottest.Assert(t, l.Period(), 5*time.Millisecond)
+
+	time.Sleep(52 * time.Millisecond)
+	l.Stop()
+	<-l.Done()
+
+	gobottest.Assert(t, atomic.LoadInt32(&runs) >= 8, true)
+	gobottest.Assert(t, atomic.LoadInt32(&runs) <= 12, true)
+	gobottest.Assert(t, l.Iterations(), uint64(atomic.LoadInt32(&runs)))
+	gobottest.Assert(t, l.Overruns(), uint64(0))
+}
+
+func TestLoopDriftCompensation(t *testing.T) {
+	var runs int32
+	l := Loop(100, func() {
+		atomic.AddInt32(&runs, 1)
+		time.Sleep(6 * time.Millisecond)
+	})
+	time.Sleep(105 * time.Millisecond)
+	l.Stop()
+	<-l.Done()
+
+	// a sleep based loop would only run about 6 times
+	gobottest.Assert(t, atomic.LoadInt32(&runs) >= 10, true)
+	gobottest.Assert(t, l.Overruns(), uint64(0))
+}
+
+func TestLoopOverrun(t *testing.T) {
+	overruns := make(chan LoopOverrun, 10)
+	var runs int32
+	l := Loop(100, func() {
+		if atomic.AddInt32(&runs, 1) == 2 {
+			time.Sleep(25 * time.Millisecond)
+		}
+	})
+	l.On(Overrun, func(data interface{}) {
+		overruns <- data.(LoopOverrun)
+	})
+
+	select {
+	case o := <-overruns:
+		gobottest.Assert(t, o.Iteration, uint64(2))
+		gobottest.Assert(t, o.Duration >= 25*time.Millisecond, true)
+		gobottest.Assert(t, o.Late > 0, true)
+	case <-time.After(time.Second):
+		t.Errorf("Overrun event was not published")
+	}
+	l.Stop()
+	<-l.Done()
+	gobottest.Assert(t, l.Overruns(), uint64(1))
+}
+
+func TestLoopJitter(t *testing.T) {
+	l := Loop(1000, func() {}, LoopJitter(2*time.Millisecond))
+	gobottest.Assert(t, l.jitter, 2*time.Millisecond)
+	for i := 0; i < 100; i++ {
+		d := l.jitterDelay()
+		gobottest.Assert(t, d >= 0 && d < 2*time.Millisecond, true)
+	}
+	l.Stop()
+	<-l.Done()
+}
+
+func TestLoopStopBeforeRun(t *testing.T) {
+	l := Loop(1, func() {})
+	time.Sleep(5 * time.Millisecond)
+	l.Stop()
+	l.Stop()
+	select {
+	case <-l.Done():
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Loop did not stop")
+	}
+	gobottest.Assert(t, l.Iterations(), uint64(1))
+}
+
+func TestLoopInvalidRate(t *testing.T) {
+	defer func() {
+		gobottest.Refute(t, recover(), nil)
+	}()
+	Loop(0, func() {})
+}
+
+func TestRobotLoop(t *testing.T) {
+	r := NewRobot("loop")
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	panics := make(chan interface{}, 1)
+	r.On(Panic, func(data interface{}) {
+		panics <- data
+	})
+
+	var runs int32
+	l := r.Loop(200, func() {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			panic("first")
+		}
+	})
+	time.Sleep(30 * time.Millisecond)
+	l.Stop()
+	<-l.Done()
+
+	select {
+	case data := <-panics:
+		gobottest.Assert(t, data.(PanicReport).Source, "loop")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Panic event was not published")
+	}
+	gobottest.Assert(t, atomic.LoadInt32(&runs) > 2, true)
+	gobottest.Assert(t, r.Telemetry().Loop.Runs, uint64(l.Iterations()))
+}
@@ -0,0 +1,24 @@
+/*
+Package mapping maps the surroundings of a robot in an occupancy Grid, from
+the measurements of its lidars and sonars taken at the Poses estimated by its
+odometry. The Grid answers the questions of a navigating robot, how far the
+nearest obstacle is in a direction and whether a spot is free, and can be
+exported as a PNG image.
+
+A rover with a RPLIDAR could map a room like this:
+
+	grid := mapping.NewGrid(10, 10, 0.05)
+	odometry := control.NewOdometry(control.NewDifferentialDrive(0.03, 0.2))
+
+	lidar.On(serial.Scan, func(data interface{}) {
+		scan := data.(serial.RPLidarScan)
+		grid.InsertScan(odometry.Pose(), mapping.LidarRanges(scan.Angles, scan.Distances), 6)
+	})
+
+	gobot.Every(100*time.Millisecond, func() {
+		if d, ok := grid.NearestObstacle(odometry.Pose(), 0, 1); ok && d < 0.3 {
+			stop()
+		}
+	})
+*/
+package mapping // import "gobot.io/x/gobot/mapping"
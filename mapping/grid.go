@@ -0,0 +1,283 @@
+package mapping
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sync"
+
+	"gobot.io/x/gobot/control"
+)
+
+// Default log-odds of the updates and bounds of the cells, a hit making a
+// cell 70% likely occupied and a miss 40%
+const (
+	DefaultHitLogOdds  = 0.85
+	DefaultMissLogOdds = -0.4
+	DefaultMaxLogOdds  = 5.0
+)
+
+// probabilities above which a cell is occupied and below which it is free,
+// a single hit or miss being enough
+const (
+	occupiedProbability = 0.55
+	freeProbability     = 0.45
+)
+
+// Range is a distance measured by a range sensor, along its Angle relative to
+// the heading of the robot, counter-clockwise in radians like control.Pose.
+type Range struct {
+	Angle    float64
+	Distance float64
+}
+
+// LidarRanges converts a lidar scan, whose angles are clockwise in degrees
+// and distances in mm like the ones of a serial.RPLidarScan, to Ranges in
+// radians and meters. The measurements with a zero distance are dropped.
+func LidarRanges(angles []float64, distances []float64) []Range {
+	ranges := make([]Range, 0, len(angles))
+	for i := range angles {
+		if i >= len(distances) || distances[i] <= 0 {
+			continue
+		}
+		ranges = append(ranges, Range{Angle: -angles[i] * math.Pi / 180, Distance: distances[i] / 1000})
+	}
+	return ranges
+}
+
+// Grid is an occupancy grid, mapping the obstacles around a robot from the
+// measurements of its range sensors, such as lidars and sonars, taken at the
+// Poses given by its odometry.
+//
+// Each cell holds the log-odds of being occupied, 0 for an unknown cell: each
+// measurement casts a ray from the robot, decreasing the log-odds of the
+// cells it crosses and increasing the one of the cell it hits.
+type Grid struct {
+	// HitLogOdds is added to the cell hit by a measurement
+	HitLogOdds float64
+	// MissLogOdds is added to the cells crossed by a measurement
+	MissLogOdds float64
+	// MaxLogOdds bounds the log-odds of the cells, so that the map keeps
+	// adapting to moving obstacles
+	MaxLogOdds float64
+
+	resolution    float64
+	width, height int
+	minX, minY    float64
+	cells         []float64
+	mutex         sync.Mutex
+}
+
+// NewGrid returns a new Grid of unknown cells, width by height meters
+// centered on the origin, each cell covering resolution meters.
+func NewGrid(width, height, resolution float64) *Grid {
+	cols, rows := int(math.Ceil(width/resolution)), int(math.Ceil(height/resolution))
+	return &Grid{
+		HitLogOdds:  DefaultHitLogOdds,
+		MissLogOdds: DefaultMissLogOdds,
+		MaxLogOdds:  DefaultMaxLogOdds,
+		resolution:  resolution,
+		width:       cols,
+		height:      rows,
+		minX:        -float64(cols) * resolution / 2,
+		minY:        -float64(rows) * resolution / 2,
+		cells:       make([]float64, cols*rows),
+	}
+}
+
+// Resolution returns the size of the cells, in meters.
+func (g *Grid) Resolution() float64 { return g.resolution }
+
+// Size returns the number of columns and rows of cells.
+func (g *Grid) Size() (cols, rows int) { return g.width, g.height }
+
+// Cell returns the column and row of the cell at x, y, false when it is out
+// of the Grid.
+func (g *Grid) Cell(x, y float64) (col, row int, ok bool) {
+	col = int(math.Floor((x - g.minX) / g.resolution))
+	row = int(math.Floor((y - g.minY) / g.resolution))
+	return col, row, col >= 0 && col < g.width && row >= 0 && row < g.height
+}
+
+// Occupancy returns the probability of the cell at x, y to be occupied, 0.5
+// when it is unknown or out of the Grid.
+func (g *Grid) Occupancy(x, y float64) float64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	col, row, ok := g.Cell(x, y)
+	if !ok {
+		return 0.5
+	}
+	return probability(g.cells[row*g.width+col])
+}
+
+// Occupied returns whether the cell at x, y is likely occupied.
+func (g *Grid) Occupied(x, y float64) bool {
+	return g.Occupancy(x, y) > occupiedProbability
+}
+
+// Clear forgets all the measurements.
+func (g *Grid) Clear() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for i := range g.cells {
+		g.cells[i] = 0
+	}
+}
+
+// Insert updates the Grid with a measurement r taken from pose. A distance of
+// maxRange or more, or of 0, hits no obstacle: the cells up to maxRange are
+// free.
+func (g *Grid) Insert(pose control.Pose, r Range, maxRange float64) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.insert(pose, r, maxRange)
+}
+
+// InsertScan updates the Grid with the measurements of a scan taken from pose.
+func (g *Grid) InsertScan(pose control.Pose, ranges []Range, maxRange float64) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, r := range ranges {
+		g.insert(pose, r, maxRange)
+	}
+}
+
+func (g *Grid) insert(pose control.Pose, r Range, maxRange float64) {
+	hit := r.Distance > 0 && r.Distance < maxRange
+	distance := r.Distance
+	if !hit {
+		distance = maxRange
+	}
+	heading := pose.Theta + r.Angle
+	x1, y1 := pose.X+distance*math.Cos(heading), pose.Y+distance*math.Sin(heading)
+	g.cast(pose.X, pose.Y, x1, y1, func(col, row int, last bool) bool {
+		i := row*g.width + col
+		if last && hit {
+			g.cells[i] = math.Min(g.cells[i]+g.HitLogOdds, g.MaxLogOdds)
+		} else {
+			g.cells[i] = math.Max(g.cells[i]+g.MissLogOdds, -g.MaxLogOdds)
+		}
+		return true
+	})
+}
+
+// cast calls f with the cells of the Grid crossed by the segment from x0, y0
+// to x1, y1, in order, until f returns false
+func (g *Grid) cast(x0, y0, x1, y1 float64, f func(col, row int, last bool) bool) {
+	c0, r0, _ := g.Cell(x0, y0)
+	c1, r1, _ := g.Cell(x1, y1)
+	dc, dr := abs(c1-c0), -abs(r1-r0)
+	sc, sr := sign(c1-c0), sign(r1-r0)
+	e := dc + dr
+	for {
+		last := c0 == c1 && r0 == r1
+		if c0 >= 0 && c0 < g.width && r0 >= 0 && r0 < g.height {
+			if !f(c0, r0, last) {
+				return
+			}
+		}
+		if last {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dr {
+			e += dr
+			c0 += sc
+		}
+		if e2 <= dc {
+			e += dc
+			r0 += sr
+		}
+	}
+}
+
+// NearestObstacle returns the distance from pose to the nearest occupied cell
+// along heading, relative to the heading of pose, up to maxRange.
+func (g *Grid) NearestObstacle(pose control.Pose, heading float64, maxRange float64) (distance float64, ok bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	heading += pose.Theta
+	x1, y1 := pose.X+maxRange*math.Cos(heading), pose.Y+maxRange*math.Sin(heading)
+	g.cast(pose.X, pose.Y, x1, y1, func(col, row int, last bool) bool {
+		if probability(g.cells[row*g.width+col]) <= occupiedProbability {
+			return true
+		}
+		// distance to the center of the cell
+		x := g.minX + (float64(col)+0.5)*g.resolution
+		y := g.minY + (float64(row)+0.5)*g.resolution
+		distance, ok = math.Hypot(x-pose.X, y-pose.Y), true
+		return false
+	})
+	return distance, ok
+}
+
+// IsFree returns whether all the cells within radius of x, y are known to be
+// free, so that a robot of that radius can stand there. The cells out of the
+// Grid are unknown.
+func (g *Grid) IsFree(x, y, radius float64) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	c0, r0, _ := g.Cell(x-radius, y-radius)
+	c1, r1, _ := g.Cell(x+radius, y+radius)
+	for row := r0; row <= r1; row++ {
+		for col := c0; col <= c1; col++ {
+			cx := g.minX + (float64(col)+0.5)*g.resolution
+			cy := g.minY + (float64(row)+0.5)*g.resolution
+			if math.Hypot(cx-x, cy-y) > radius+g.resolution/2 {
+				continue
+			}
+			if col < 0 || col >= g.width || row < 0 || row >= g.height {
+				return false
+			}
+			if probability(g.cells[row*g.width+col]) >= freeProbability {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Image returns the Grid as a grayscale image, black for the occupied cells,
+// white for the free ones and gray for the unknown ones, the Y axis pointing
+// up.
+func (g *Grid) Image() *image.Gray {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	img := image.NewGray(image.Rect(0, 0, g.width, g.height))
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			p := probability(g.cells[row*g.width+col])
+			img.SetGray(col, g.height-1-row, color.Gray{Y: uint8(math.Round((1 - p) * 255))})
+		}
+	}
+	return img
+}
+
+// WritePNG writes the Image of the Grid to w as a PNG.
+func (g *Grid) WritePNG(w io.Writer) error {
+	return png.Encode(w, g.Image())
+}
+
+func probability(logOdds float64) float64 {
+	return 1 - 1/(1+math.Exp(logOdds))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
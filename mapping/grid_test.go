@@ -0,0 +1,106 @@
+package mapping
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"testing"
+
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLidarRanges(t *testing.T) {
+	ranges := LidarRanges([]float64{0, 90, 180}, []float64{1000, 0, 2500})
+	gobottest.Assert(t, len(ranges), 2)
+	gobottest.Assert(t, ranges[0], Range{Angle: 0, Distance: 1})
+	gobottest.Assert(t, near(ranges[1].Angle, -math.Pi), true)
+	gobottest.Assert(t, ranges[1].Distance, 2.5)
+}
+
+func TestGridCell(t *testing.T) {
+	g := NewGrid(2, 1, 0.1)
+	cols, rows := g.Size()
+	gobottest.Assert(t, []int{cols, rows}, []int{20, 10})
+	gobottest.Assert(t, g.Resolution(), 0.1)
+
+	col, row, ok := g.Cell(0, 0)
+	gobottest.Assert(t, []int{col, row}, []int{10, 5})
+	gobottest.Assert(t, ok, true)
+	_, _, ok = g.Cell(-1.05, 0)
+	gobottest.Assert(t, ok, false)
+	gobottest.Assert(t, g.Occupancy(5, 5), 0.5)
+}
+
+func TestGridInsert(t *testing.T) {
+	g := NewGrid(4, 4, 0.1)
+	pose := control.Pose{X: 0.05, Y: 0.05, Theta: math.Pi / 2}
+	// an obstacle 1m to the left of the robot, which faces the Y axis
+	g.Insert(pose, Range{Angle: math.Pi / 2, Distance: 1}, 3)
+
+	gobottest.Assert(t, g.Occupied(-0.95, 0.05), true)
+	gobottest.Assert(t, math.Abs(g.Occupancy(-0.95, 0.05)-0.7) < 0.01, true)
+	gobottest.Assert(t, g.Occupancy(-0.5, 0.05) < 0.45, true)
+	gobottest.Assert(t, g.Occupancy(-1.15, 0.05), 0.5)
+
+	// beyond the range of the sensor, everything is free up to maxRange
+	g.Insert(pose, Range{Angle: 0, Distance: 0}, 1)
+	gobottest.Assert(t, g.Occupancy(0.05, 1.05) < 0.45, true)
+	gobottest.Assert(t, g.Occupancy(0.05, 1.25), 0.5)
+
+	// the log-odds are bounded
+	for i := 0; i < 100; i++ {
+		g.Insert(pose, Range{Angle: math.Pi / 2, Distance: 1}, 3)
+	}
+	gobottest.Assert(t, near(g.Occupancy(-0.95, 0.05), 1-1/(1+math.Exp(DefaultMaxLogOdds))), true)
+
+	g.Clear()
+	gobottest.Assert(t, g.Occupancy(-0.95, 0.05), 0.5)
+}
+
+func TestGridQueries(t *testing.T) {
+	g := NewGrid(4, 4, 0.1)
+	pose := control.Pose{X: 0.05, Y: 0.05}
+	// a wall 1m ahead of the robot
+	ranges := []Range{}
+	for a := -0.4; a <= 0.4; a += 0.01 {
+		ranges = append(ranges, Range{Angle: a, Distance: 1 / math.Cos(a)})
+	}
+	g.InsertScan(pose, ranges, 3)
+
+	d, ok := g.NearestObstacle(pose, 0, 3)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, near(d, 1), true)
+	_, ok = g.NearestObstacle(pose, math.Pi, 3)
+	gobottest.Assert(t, ok, false)
+	_, ok = g.NearestObstacle(pose, 0, 0.5)
+	gobottest.Assert(t, ok, false)
+
+	gobottest.Assert(t, g.IsFree(0.55, 0.05, 0.15), true)
+	gobottest.Assert(t, g.IsFree(0.95, 0.05, 0.15), false)
+	// unknown space behind the wall
+	gobottest.Assert(t, g.IsFree(1.55, 0.05, 0.15), false)
+	gobottest.Assert(t, g.IsFree(1.95, 0.05, 0.15), false)
+}
+
+func TestGridImage(t *testing.T) {
+	g := NewGrid(1, 1, 0.1)
+	pose := control.Pose{X: 0.05, Y: -0.45, Theta: math.Pi / 2}
+	g.Insert(pose, Range{Distance: 0.8}, 3)
+
+	img := g.Image()
+	// the Y axis points up, the robot being at the bottom
+	gobottest.Assert(t, img.GrayAt(5, 9).Y > 128, true)
+	gobottest.Assert(t, img.GrayAt(5, 1).Y < 128, true)
+	gobottest.Assert(t, img.GrayAt(0, 0).Y, uint8(128))
+
+	var b bytes.Buffer
+	gobottest.Assert(t, g.WritePNG(&b), nil)
+	decoded, err := png.Decode(&b)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, decoded.Bounds(), img.Bounds())
+}
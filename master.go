@@ -3,6 +3,7 @@ package gobot
 import (
 	"os"
 	"os/signal"
+	"sync"
 	"sync/atomic"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -38,6 +39,16 @@ type Master struct {
 	trap    func(chan os.Signal)
 	AutoRun bool
 	running atomic.Value
+	logger  Logger
+
+	// DryRun starts all the Robots in dry-run mode, see Robot.DryRun
+	DryRun bool
+
+	restarts restarts
+
+	topics     map[string]*Topic
+	barriers   map[string]*Barrier
+	coordMutex sync.Mutex
 	Commander
 	Eventer
 }
@@ -53,6 +64,7 @@ func NewMaster() *Master {
 		Commander: NewCommander(),
 		Eventer:   NewEventer(),
 	}
+	m.AddEvent(RobotRestarted)
 	m.running.Store(false)
 	return m
 }
@@ -61,13 +73,15 @@ func NewMaster() *Master {
 // error, call Stop to ensure that all robots are returned to a sane, stopped
 // state.
 func (g *Master) Start() (err error) {
+	// running while the robots start, so that those crashing at once are
+	// restarted
+	g.running.Store(true)
 	if rerr := g.robots.Start(!g.AutoRun); rerr != nil {
+		g.running.Store(false)
 		err = multierror.Append(err, rerr)
 		return
 	}
 
-	g.running.Store(true)
-
 	if g.AutoRun {
 		c := make(chan os.Signal, 1)
 		g.trap(c)
@@ -105,10 +119,31 @@ func (g *Master) Robots() *Robots {
 // AddRobot adds a new robot to the internal collection of robots. Returns the
 // added robot
 func (g *Master) AddRobot(r *Robot) *Robot {
+	if g.logger != nil && r.logger == nil {
+		r.SetLogger(g.logger.WithComponent(r.Name))
+	}
+	r.master = g
 	*g.robots = append(*g.robots, r)
 	return r
 }
 
+// Logger returns the Logger used by the Master
+func (g *Master) Logger() Logger {
+	if g.logger != nil {
+		return g.logger
+	}
+	return DefaultLogger()
+}
+
+// SetLogger sets the Logger used by the Master, and injects a Logger scoped
+// to each Robot's name into the Master's robots.
+func (g *Master) SetLogger(l Logger) {
+	g.logger = l
+	g.robots.Each(func(r *Robot) {
+		r.SetLogger(l.WithComponent(r.Name))
+	})
+}
+
 // Robot returns a robot given name. Returns nil if the Robot does not exist.
 func (g *Master) Robot(name string) *Robot {
 	for _, robot := range *g.Robots() {
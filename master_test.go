@@ -97,9 +97,9 @@ func TestMasterStartDriverErrors(t *testing.T) {
 	}
 
 	var expected error
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
+	expected = multierror.Append(expected, WrapError("start", "Device1", e))
+	expected = multierror.Append(expected, WrapError("start", "Device2", e))
+	expected = multierror.Append(expected, WrapError("start", "", e))
 
 	gobottest.Assert(t, g.Start(), expected)
 	gobottest.Assert(t, g.Stop(), nil)
@@ -115,9 +115,9 @@ func TestMasterHaltFromRobotDriverErrors(t *testing.T) {
 	}
 
 	var expected error
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
+	expected = multierror.Append(expected, WrapError("halt", "Device1", e))
+	expected = multierror.Append(expected, WrapError("halt", "Device2", e))
+	expected = multierror.Append(expected, WrapError("halt", "", e))
 
 	gobottest.Assert(t, g.Start(), nil)
 	gobottest.Assert(t, g.Stop(), expected)
@@ -134,9 +134,9 @@ func TestMasterStartRobotAdaptorErrors(t *testing.T) {
 	}
 
 	var expected error
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
+	expected = multierror.Append(expected, WrapError("connect", "Connection1", e))
+	expected = multierror.Append(expected, WrapError("connect", "Connection2", e))
+	expected = multierror.Append(expected, WrapError("connect", "", e))
 
 	gobottest.Assert(t, g.Start(), expected)
 	gobottest.Assert(t, g.Stop(), nil)
@@ -144,6 +144,38 @@ func TestMasterStartRobotAdaptorErrors(t *testing.T) {
 	testAdaptorConnect = func() (err error) { return }
 }
 
+// dryRunAdaptor records whether it was set to dry-run
+type dryRunAdaptor struct {
+	*testAdaptor
+	dryRun bool
+}
+
+func (d *dryRunAdaptor) SetDryRun(enable bool) { d.dryRun = enable }
+func (d *dryRunAdaptor) Connect() error        { return nil }
+func (d *dryRunAdaptor) Finalize() error       { return nil }
+
+func TestMasterDryRun(t *testing.T) {
+	g := initTestMaster1Robot()
+	g.DryRun = true
+	dry := &dryRunAdaptor{testAdaptor: newTestAdaptor("DryRun", "/dev/null")}
+	g.Robots().Each(func(robot *Robot) {
+		*robot.Connections() = append(*robot.Connections(), dry)
+	})
+
+	e := errors.New("no hardware")
+	testAdaptorConnect = func() (err error) { return e }
+	testAdaptorFinalize = func() (err error) { return e }
+	defer func() {
+		testAdaptorConnect = func() (err error) { return }
+		testAdaptorFinalize = func() (err error) { return }
+	}()
+
+	// the test adaptors are neither connected nor finalized
+	gobottest.Assert(t, g.Start(), nil)
+	gobottest.Assert(t, dry.dryRun, true)
+	gobottest.Assert(t, g.Stop(), nil)
+}
+
 func TestMasterFinalizeErrors(t *testing.T) {
 	g := initTestMaster1Robot()
 	e := errors.New("adaptor finalize error 2")
@@ -153,9 +185,9 @@ func TestMasterFinalizeErrors(t *testing.T) {
 	}
 
 	var expected error
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
-	expected = multierror.Append(expected, e)
+	expected = multierror.Append(expected, WrapError("finalize", "Connection1", e))
+	expected = multierror.Append(expected, WrapError("finalize", "Connection2", e))
+	expected = multierror.Append(expected, WrapError("finalize", "", e))
 
 	gobottest.Assert(t, g.Start(), nil)
 	gobottest.Assert(t, g.Stop(), expected)
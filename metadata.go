@@ -0,0 +1,50 @@
+package gobot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetDeviceMetadata sets the metadata of the named Device, such as its
+// location or firmware version, shown along with it by the API.
+func (r *Robot) SetDeviceMetadata(device string, metadata map[string]string) {
+	if r.deviceMetadata == nil {
+		r.deviceMetadata = make(map[string]map[string]string)
+	}
+	r.deviceMetadata[device] = metadata
+}
+
+// DeviceMetadata returns the metadata of the named Device, nil if it has none
+func (r *Robot) DeviceMetadata(device string) map[string]string {
+	return r.deviceMetadata[device]
+}
+
+// MatchLabels reports whether metadata matches all the label selectors. A
+// selector is either "key=value", "key!=value", or "key" for metadata having
+// key with any value. An invalid selector is an error.
+func MatchLabels(metadata map[string]string, selectors ...string) (bool, error) {
+	match := true
+	for _, selector := range selectors {
+		key, value, op := selector, "", ""
+		if i := strings.Index(selector, "!="); i >= 0 {
+			key, value, op = selector[:i], selector[i+2:], "!="
+		} else if i := strings.Index(selector, "="); i >= 0 {
+			key, value, op = selector[:i], selector[i+1:], "="
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return false, fmt.Errorf("invalid label selector %q", selector)
+		}
+
+		v, ok := metadata[key]
+		switch op {
+		case "=":
+			match = match && ok && v == strings.TrimSpace(value)
+		case "!=":
+			match = match && v != strings.TrimSpace(value)
+		default:
+			match = match && ok
+		}
+	}
+	return match, nil
+}
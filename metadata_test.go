@@ -0,0 +1,47 @@
+package gobot
+
+import (
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestMatchLabels(t *testing.T) {
+	metadata := map[string]string{"zone": "garage", "owner": "ada"}
+	var tests = []struct {
+		selectors []string
+		match     bool
+	}{
+		{nil, true},
+		{[]string{"zone=garage"}, true},
+		{[]string{"zone=kitchen"}, false},
+		{[]string{"zone!=kitchen"}, true},
+		{[]string{"zone!=garage"}, false},
+		{[]string{"firmware!=1.2"}, true},
+		{[]string{"owner"}, true},
+		{[]string{"firmware"}, false},
+		{[]string{"zone=garage", "owner=ada"}, true},
+		{[]string{"zone=garage", "owner=bob"}, false},
+	}
+	for _, test := range tests {
+		match, err := MatchLabels(metadata, test.selectors...)
+		gobottest.Assert(t, err, nil)
+		gobottest.Assert(t, match, test.match)
+	}
+
+	_, err := MatchLabels(metadata, "=garage")
+	gobottest.Assert(t, err.Error(), `invalid label selector "=garage"`)
+}
+
+func TestRobotDeviceMetadata(t *testing.T) {
+	r := newTestRobot("Robot1")
+	r.Metadata = map[string]string{"zone": "garage"}
+	gobottest.Assert(t, r.DeviceMetadata("Device1"), map[string]string(nil))
+	r.SetDeviceMetadata("Device1", map[string]string{"firmware": "1.2"})
+	gobottest.Assert(t, r.DeviceMetadata("Device1"), map[string]string{"firmware": "1.2"})
+
+	jsonRobot := NewJSONRobot(r)
+	gobottest.Assert(t, jsonRobot.Metadata, map[string]string{"zone": "garage"})
+	gobottest.Assert(t, jsonRobot.Devices[0].Metadata, map[string]string{"firmware": "1.2"})
+	gobottest.Assert(t, jsonRobot.Devices[1].Metadata, map[string]string(nil))
+}
@@ -0,0 +1,182 @@
+package gobot
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Panic event
+const Panic = "panic"
+
+// PanicPolicy selects what a Robot does after recovering from a panic in its
+// work function or in a callback scheduled with its Every and After methods.
+type PanicPolicy int
+
+const (
+	// PanicContinue logs the panic and keeps the Robot running
+	PanicContinue PanicPolicy = iota
+	// PanicRestart stops the Robot and starts it again
+	PanicRestart
+	// PanicStopMaster stops the Master the Robot belongs to, or only the
+	// Robot when it has not been added to a Master
+	PanicStopMaster
+)
+
+// PanicReport describes a recovered panic. It is the data published with the
+// Panic event.
+type PanicReport struct {
+	// Source tells where the panic happened, e.g. "work", "every" or
+	// "event button"
+	Source string
+	Value  interface{}
+	Stack  []byte
+}
+
+func (p PanicReport) String() string {
+	return fmt.Sprintf("panic in %v: %v", p.Source, p.Value)
+}
+
+var (
+	panicHandlerMutex sync.RWMutex
+	panicHandler      = defaultPanicHandler
+)
+
+func defaultPanicHandler(p PanicReport) {
+	DefaultLogger().Error(p.String(), "stack", string(p.Stack))
+}
+
+// SetPanicHandler sets the function called with panics recovered in callbacks
+// which do not belong to a Robot: those of the package level Every and After
+// functions and of Eventer handlers. The default handler logs the panic with
+// its stack trace and carries on.
+func SetPanicHandler(f func(PanicReport)) {
+	panicHandlerMutex.Lock()
+	defer panicHandlerMutex.Unlock()
+	if f == nil {
+		f = defaultPanicHandler
+	}
+	panicHandler = f
+}
+
+func handlePanic(p PanicReport) {
+	panicHandlerMutex.RLock()
+	f := panicHandler
+	panicHandlerMutex.RUnlock()
+	f(p)
+}
+
+// protect calls f, recovering from a panic and passing it on to handler.
+func protect(source string, handler func(PanicReport), f func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			handler(PanicReport{Source: source, Value: v, Stack: debug.Stack()})
+		}
+	}()
+	f()
+}
+
+// Every is like the package level Every, but panics in f are handled
+// according to the Robot's PanicPolicy, and its run time is reported in the
+// Robot's Telemetry. The Ticker is stopped when the Robot stops.
+func (r *Robot) Every(t time.Duration, f func()) Ticker {
+	ticker := every(t, func() {
+		begin := time.Now()
+		protect("every", r.handlePanic, f)
+		r.telemetry.recordLoop(time.Since(begin))
+	})
+	return &robotTicker{Ticker: ticker, remove: r.addTimer(ticker.Stop)}
+}
+
+// Loop is like the package level Loop, but a panic in fn is handled according
+// to the Robot's PanicPolicy and the iterations are timed in the Robot's
+// Telemetry.
+func (r *Robot) Loop(rate float64, fn func(), opts ...LoopOption) *RateLoop {
+	return newRateLoop(rate, fn, r.handlePanic, r.telemetry.recordLoop, opts)
+}
+
+// After is like the package level After, but a panic in f is handled according
+// to the Robot's PanicPolicy. f is not called if the Robot stops first.
+func (r *Robot) After(t time.Duration, f func()) {
+	var remove func()
+	registered := make(chan bool)
+	timer := DefaultClock().AfterFunc(t, func() {
+		<-registered
+		remove()
+		protect("after", r.handlePanic, f)
+	})
+	remove = r.addTimer(func() { timer.Stop() })
+	close(registered)
+}
+
+// addTimer registers the stop function of a ticker or timer of the Robot,
+// called by stopTimers unless removed with the returned function first.
+func (r *Robot) addTimer(stop func()) (remove func()) {
+	r.timersMutex.Lock()
+	defer r.timersMutex.Unlock()
+	if r.timers == nil {
+		r.timers = make(map[int]func())
+	}
+	r.timerID++
+	id := r.timerID
+	r.timers[id] = stop
+	return func() {
+		r.timersMutex.Lock()
+		defer r.timersMutex.Unlock()
+		delete(r.timers, id)
+	}
+}
+
+// stopTimers stops the tickers and timers of the Robot's Every and After
+func (r *Robot) stopTimers() {
+	r.timersMutex.Lock()
+	timers := r.timers
+	r.timers = nil
+	r.timersMutex.Unlock()
+	for _, stop := range timers {
+		stop()
+	}
+}
+
+// robotTicker is the Ticker of Robot.Every, forgotten by the Robot once
+// stopped
+type robotTicker struct {
+	Ticker
+	remove func()
+}
+
+func (t *robotTicker) Stop() {
+	t.Ticker.Stop()
+	t.remove()
+}
+
+// handlePanic logs p, publishes it as a Panic event and applies the Robot's
+// PanicPolicy.
+func (r *Robot) handlePanic(p PanicReport) {
+	r.Logger().Error(p.String(), "stack", string(p.Stack))
+	r.telemetry.recordPanic()
+	r.Publish(Panic, p)
+
+	// the Master's RestartPolicy prevails for the crashes of the work
+	if p.Source == "work" && r.crashed(crashPanic, p.String()) {
+		return
+	}
+
+	switch r.PanicPolicy {
+	case PanicRestart:
+		go func() {
+			r.Logger().Warn("Restarting Robot after panic", "robot", r.Name)
+			r.Stop()
+			r.start()
+		}()
+	case PanicStopMaster:
+		go func() {
+			if r.master != nil {
+				r.master.Stop()
+				return
+			}
+			r.Stop()
+		}()
+	}
+}
@@ -0,0 +1,195 @@
+package gobot
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestProtect(t *testing.T) {
+	var report PanicReport
+	protect("test", func(p PanicReport) { report = p }, func() { panic("boom") })
+
+	gobottest.Assert(t, report.Source, "test")
+	gobottest.Assert(t, report.Value, "boom")
+	gobottest.Assert(t, strings.Contains(string(report.Stack), "TestProtect"), true)
+	gobottest.Assert(t, report.String(), "panic in test: boom")
+
+	called := false
+	protect("test", func(p PanicReport) { called = true }, func() {})
+	gobottest.Assert(t, called, false)
+}
+
+func capturePanics() (chan PanicReport, func()) {
+	reports := make(chan PanicReport, 10)
+	SetPanicHandler(func(p PanicReport) { reports <- p })
+	return reports, func() { SetPanicHandler(nil) }
+}
+
+func TestEveryRecoversPanic(t *testing.T) {
+	reports, restore := capturePanics()
+	defer restore()
+
+	var count int32
+	ticker := Every(5*time.Millisecond, func() {
+		if atomic.AddInt32(&count, 1) == 1 {
+			panic("every")
+		}
+	})
+	defer ticker.Stop()
+
+	select {
+	case p := <-reports:
+		gobottest.Assert(t, p.Source, "every")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Every panic was not handled")
+	}
+	time.Sleep(20 * time.Millisecond)
+	gobottest.Assert(t, atomic.LoadInt32(&count) > 1, true)
+}
+
+func TestAfterRecoversPanic(t *testing.T) {
+	reports, restore := capturePanics()
+	defer restore()
+
+	After(time.Millisecond, func() { panic("after") })
+
+	select {
+	case p := <-reports:
+		gobottest.Assert(t, p.Source, "after")
+		gobottest.Assert(t, p.Value, "after")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("After panic was not handled")
+	}
+}
+
+func TestEventHandlerRecoversPanic(t *testing.T) {
+	reports, restore := capturePanics()
+	defer restore()
+
+	e := NewEventer()
+	e.AddEvent("push")
+	var count int32
+	e.On("push", func(data interface{}) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			panic("handler")
+		}
+	})
+	e.Publish("push", nil)
+
+	select {
+	case p := <-reports:
+		gobottest.Assert(t, p.Source, "event push")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("event handler panic was not handled")
+	}
+
+	e.Publish("push", nil)
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, atomic.LoadInt32(&count), int32(2))
+}
+
+func TestRobotWorkPanicEvent(t *testing.T) {
+	r := NewRobot("panicky", func() { panic("work") })
+	reports := make(chan PanicReport, 1)
+	r.On(Panic, func(data interface{}) { reports <- data.(PanicReport) })
+
+	gobottest.Assert(t, r.Start(false), nil)
+	select {
+	case p := <-reports:
+		gobottest.Assert(t, p.Source, "work")
+		gobottest.Assert(t, p.Value, "work")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Panic event was not published")
+	}
+	gobottest.Assert(t, r.Running(), true)
+	gobottest.Assert(t, r.Stop(), nil)
+}
+
+func TestRobotEveryPanic(t *testing.T) {
+	r := NewRobot("panicky")
+	reports := make(chan PanicReport, 10)
+	r.On(Panic, func(data interface{}) { reports <- data.(PanicReport) })
+
+	ticker := r.Every(5*time.Millisecond, func() { panic("tick") })
+	defer ticker.Stop()
+	r.After(time.Millisecond, func() { panic("later") })
+
+	sources := map[string]bool{}
+	for len(sources) < 2 {
+		select {
+		case p := <-reports:
+			sources[p.Source] = true
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("Panic events missing, got %v", sources)
+		}
+	}
+}
+
+func TestRobotTimersStoppedWithRobot(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	ticks := make(chan bool, 10)
+	afters := make(chan bool, 10)
+	r := NewRobot("ticking")
+	r.Work = func() {
+		r.Every(time.Second, func() { ticks <- true })
+		r.After(time.Second, func() { afters <- true })
+	}
+
+	// restarted twice, the Robot has the timers of its last Work only
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			gobottest.Assert(t, r.Stop(), nil)
+			gobottest.Assert(t, clock.Waiters(), 0)
+		}
+		gobottest.Assert(t, r.Start(false), nil)
+		clock.BlockUntil(2)
+	}
+	gobottest.Assert(t, clock.Waiters(), 2)
+
+	clock.Advance(time.Second)
+	<-ticks
+	<-afters
+	clock.Advance(time.Second)
+	<-ticks
+	gobottest.Assert(t, len(ticks), 0)
+	gobottest.Assert(t, len(afters), 0)
+
+	gobottest.Assert(t, r.Stop(), nil)
+	gobottest.Assert(t, clock.Waiters(), 0)
+}
+
+func TestRobotPanicRestart(t *testing.T) {
+	var runs int32
+	r := NewRobot("restarting", func() {})
+	r.Work = func() {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			panic("first run")
+		}
+	}
+	r.PanicPolicy = PanicRestart
+
+	gobottest.Assert(t, r.Start(false), nil)
+	time.Sleep(50 * time.Millisecond)
+	gobottest.Assert(t, atomic.LoadInt32(&runs), int32(2))
+	gobottest.Assert(t, r.Running(), true)
+	gobottest.Assert(t, r.Stop(), nil)
+}
+
+func TestRobotPanicStopMaster(t *testing.T) {
+	m := NewMaster()
+	r := m.AddRobot(NewRobot("fatal", func() { panic("fatal") }))
+	r.PanicPolicy = PanicStopMaster
+
+	m.running.Store(true)
+	gobottest.Assert(t, r.Start(false), nil)
+	time.Sleep(50 * time.Millisecond)
+	gobottest.Assert(t, m.Running(), false)
+	gobottest.Assert(t, r.Running(), false)
+}
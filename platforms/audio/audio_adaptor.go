@@ -3,7 +3,6 @@ package audio
 
 import (
 	"errors"
-	"log"
 	"os"
 	"os/exec"
 	"path"
@@ -13,7 +12,8 @@ import (
 
 // Adaptor is gobot Adaptor connection to audio playback
 type Adaptor struct {
-	name string
+	name   string
+	logger gobot.Logger
 }
 
 // NewAdaptor returns a new audio Adaptor
@@ -28,6 +28,17 @@ func (a *Adaptor) Name() string { return a.name }
 // SetName sets the Adaptor Name
 func (a *Adaptor) SetName(n string) { a.name = n }
 
+// Logger returns the Adaptor Logger
+func (a *Adaptor) Logger() gobot.Logger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return gobot.DefaultLogger().WithComponent(a.name)
+}
+
+// SetLogger sets the Adaptor Logger
+func (a *Adaptor) SetLogger(l gobot.Logger) { a.logger = l }
+
 // Connect establishes a connection to the Audio adaptor
 func (a *Adaptor) Connect() error { return nil }
 
@@ -37,38 +48,31 @@ func (a *Adaptor) Finalize() error { return nil }
 // Sound plays a sound and accepts:
 //
 //  string: The filename of the audio to start playing
-func (a *Adaptor) Sound(fileName string) []error {
-	var errorsList []error
-
+func (a *Adaptor) Sound(fileName string) error {
 	if fileName == "" {
-		log.Println("Requires filename for audio file.")
-		errorsList = append(errorsList, errors.New("Requires filename for audio file."))
-		return errorsList
+		a.Logger().Error("Requires filename for audio file.")
+		return errors.New("Requires filename for audio file.")
 	}
 
 	_, err := os.Stat(fileName)
 	if err != nil {
-		log.Println(err)
-		errorsList = append(errorsList, err)
-		return errorsList
+		a.Logger().Error(err.Error())
+		return err
 	}
 
 	// command to play audio file based on file type
 	commandName, err := CommandName(fileName)
 	if err != nil {
-		log.Println(err)
-		errorsList = append(errorsList, err)
-		return errorsList
+		a.Logger().Error(err.Error())
+		return err
 	}
 
 	err = RunCommand(commandName, fileName)
 	if err != nil {
-		log.Println(err)
-		errorsList = append(errorsList, err)
-		return errorsList
+		a.Logger().Error(err.Error())
+		return err
 	}
 
-	// Need to return to fulfill function sig, even though returning an empty
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 )
 
 var _ gobot.Adaptor = (*Adaptor)(nil)
+var _ gobot.Loggable = (*Adaptor)(nil)
 
 func TestAudioAdaptor(t *testing.T) {
 	a := NewAdaptor()
@@ -26,6 +27,14 @@ func TestAudioAdaptorName(t *testing.T) {
 	gobottest.Assert(t, a.Name(), "NewName")
 }
 
+func TestAudioAdaptorLogger(t *testing.T) {
+	a := NewAdaptor()
+	gobottest.Refute(t, a.Logger(), nil)
+	l := gobot.NewLogger()
+	a.SetLogger(l)
+	gobottest.Assert(t, a.Logger(), l)
+}
+
 func TestAudioAdaptorCommandsWav(t *testing.T) {
 	cmd, _ := CommandName("whatever.wav")
 	gobottest.Assert(t, cmd, "aplay")
@@ -45,15 +54,15 @@ func TestAudioAdaptorCommandsUnknown(t *testing.T) {
 func TestAudioAdaptorSoundWithNoFilename(t *testing.T) {
 	a := NewAdaptor()
 
-	errors := a.Sound("")
-	gobottest.Assert(t, errors[0].Error(), "Requires filename for audio file.")
+	err := a.Sound("")
+	gobottest.Assert(t, err.Error(), "Requires filename for audio file.")
 }
 
 func TestAudioAdaptorSoundWithNonexistingFilename(t *testing.T) {
 	a := NewAdaptor()
 
-	errors := a.Sound("doesnotexist.mp3")
-	gobottest.Assert(t, errors[0].Error(), "stat doesnotexist.mp3: no such file or directory")
+	err := a.Sound("doesnotexist.mp3")
+	gobottest.Assert(t, err.Error(), "stat doesnotexist.mp3: no such file or directory")
 }
 
 func TestAudioAdaptorSoundWithValidMP3Filename(t *testing.T) {
@@ -62,7 +71,7 @@ func TestAudioAdaptorSoundWithValidMP3Filename(t *testing.T) {
 	a := NewAdaptor()
 	defer func() { execCommand = exec.Command }()
 
-	errors := a.Sound("../../examples/laser.mp3")
+	err := a.Sound("../../examples/laser.mp3")
 
-	gobottest.Assert(t, len(errors), 0)
+	gobottest.Assert(t, err, nil)
 }
@@ -52,12 +52,12 @@ func (d *Driver) Connection() gobot.Connection {
 // Sound plays back a sound file. It accepts:
 //
 //  string: The filename of the audio to start playing
-func (d *Driver) Sound(fileName string) []error {
+func (d *Driver) Sound(fileName string) error {
 	return d.Connection().(*Adaptor).Sound(fileName)
 }
 
 // Play plays back the current sound file.
-func (d *Driver) Play() []error {
+func (d *Driver) Play() error {
 	return d.Sound(d.Filename())
 }
 
@@ -35,8 +35,8 @@ func TestAudioDriverName(t *testing.T) {
 func TestAudioDriverSoundWithNoFilename(t *testing.T) {
 	d := NewDriver(NewAdaptor(), "")
 
-	errors := d.Sound("")
-	gobottest.Assert(t, errors[0].Error(), "Requires filename for audio file.")
+	err := d.Sound("")
+	gobottest.Assert(t, err.Error(), "Requires filename for audio file.")
 }
 
 func TestAudioDriverSoundWithDefaultFilename(t *testing.T) {
@@ -45,6 +45,6 @@ func TestAudioDriverSoundWithDefaultFilename(t *testing.T) {
 
 	d := NewDriver(NewAdaptor(), "../../examples/laser.mp3")
 
-	errors := d.Play()
-	gobottest.Assert(t, len(errors), 0)
+	err := d.Play()
+	gobottest.Assert(t, err, nil)
 }
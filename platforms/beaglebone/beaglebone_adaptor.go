@@ -104,7 +104,7 @@ func (b *Adaptor) Finalize() (err error) {
 	}
 	for _, bus := range b.i2cBuses {
 		if bus != nil {
-			if e := bus.Close(); e != nil {
+			if e := i2c.CloseBus(bus); e != nil {
 				err = multierror.Append(err, e)
 			}
 		}
@@ -2,7 +2,6 @@ package ble
 
 import (
 	"context"
-	"log"
 	"strings"
 	"sync"
 
@@ -128,7 +127,7 @@ func (b *ClientAdaptor) Finalize() (err error) {
 // requested characteristic uuid
 func (b *ClientAdaptor) ReadCharacteristic(cUUID string) (data []byte, err error) {
 	if !b.connected {
-		log.Fatalf("Cannot read from BLE device until connected")
+		gobot.DefaultLogger().WithComponent(b.Name()).Warn("Cannot read from BLE device until connected")
 		return
 	}
 
@@ -145,7 +144,7 @@ func (b *ClientAdaptor) ReadCharacteristic(cUUID string) (data []byte, err error
 // requested service and characteristic
 func (b *ClientAdaptor) WriteCharacteristic(cUUID string, data []byte) (err error) {
 	if !b.connected {
-		log.Println("Cannot write to BLE device until connected")
+		gobot.DefaultLogger().WithComponent(b.Name()).Warn("Cannot write to BLE device until connected")
 		return
 	}
 
@@ -162,7 +161,7 @@ func (b *ClientAdaptor) WriteCharacteristic(cUUID string, data []byte) (err erro
 // requested service and characteristic
 func (b *ClientAdaptor) Subscribe(cUUID string, f func([]byte, error)) (err error) {
 	if !b.connected {
-		log.Fatalf("Cannot subscribe to BLE device until connected")
+		gobot.DefaultLogger().WithComponent(b.Name()).Warn("Cannot subscribe to BLE device until connected")
 		return
 	}
 
@@ -0,0 +1,240 @@
+package ble
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"gobot.io/x/gobot"
+
+	blelib "github.com/go-ble/ble"
+	"github.com/pkg/errors"
+)
+
+// PeripheralAdaptor lets a Gobot program act as a BLE Peripheral, so that a
+// phone can control the robot without Wi-Fi or an app server. It advertises
+// a GATT service whose characteristics either run the commands of a
+// Commander, such as a Robot, or notify the events of an Eventer.
+//
+// A command is run when its characteristic is written, with the params
+// decoded from the JSON object written, or with the "value" param holding the
+// data written when it is not a JSON object. Reading the characteristic
+// returns the JSON encoded result of the last run. An event is notified with
+// its JSON encoded data, which should fit the MTU of the connection.
+type PeripheralAdaptor struct {
+	name        string
+	LocalName   string
+	DeviceName  string
+	serviceUUID string
+	commander   gobot.Commander
+
+	commands      map[string]string
+	results       map[string][]byte
+	notifications []peripheralNotification
+	cancel        context.CancelFunc
+	mutex         *sync.Mutex
+}
+
+// peripheralNotification is a characteristic notifying an event
+type peripheralNotification struct {
+	uuid    string
+	eventer gobot.Eventer
+	event   string
+}
+
+// NewPeripheralAdaptor returns a new PeripheralAdaptor advertising localName
+// and the service serviceUUID, whose commands are those of commander.
+func NewPeripheralAdaptor(localName string, serviceUUID string, commander gobot.Commander) *PeripheralAdaptor {
+	return &PeripheralAdaptor{
+		name:        gobot.DefaultName("BLEPeripheral"),
+		LocalName:   localName,
+		DeviceName:  "default",
+		serviceUUID: serviceUUID,
+		commander:   commander,
+		commands:    make(map[string]string),
+		results:     make(map[string][]byte),
+		mutex:       &sync.Mutex{},
+	}
+}
+
+// Name returns the name for the adaptor
+func (p *PeripheralAdaptor) Name() string { return p.name }
+
+// SetName sets the name for the adaptor
+func (p *PeripheralAdaptor) SetName(n string) { p.name = n }
+
+// ServiceUUID returns the UUID of the service advertised
+func (p *PeripheralAdaptor) ServiceUUID() string { return p.serviceUUID }
+
+// AddCommand adds the characteristic cUUID running the command of the
+// Commander. It must be called before the adaptor is connected.
+func (p *PeripheralAdaptor) AddCommand(cUUID string, command string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.commands[cUUID] = command
+}
+
+// AddNotification adds the characteristic cUUID notifying the event of
+// eventer. It must be called before the adaptor is connected.
+func (p *PeripheralAdaptor) AddNotification(cUUID string, eventer gobot.Eventer, event string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.notifications = append(p.notifications, peripheralNotification{
+		uuid:    cUUID,
+		eventer: eventer,
+		event:   event,
+	})
+}
+
+// Connect adds the service to the BLE device, and starts advertising it.
+func (p *PeripheralAdaptor) Connect() (err error) {
+	bleMutex.Lock()
+	defer bleMutex.Unlock()
+
+	if _, err = getBLEDevice(p.DeviceName); err != nil {
+		return errors.Wrap(err, "can't connect to device "+p.DeviceName)
+	}
+
+	svc, err := p.service()
+	if err != nil {
+		return err
+	}
+	if err = blelib.AddService(svc); err != nil {
+		return errors.Wrap(err, "can't add service "+p.serviceUUID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.mutex.Lock()
+	p.cancel = cancel
+	p.mutex.Unlock()
+
+	go func() {
+		err := blelib.AdvertiseNameAndServices(ctx, p.LocalName, svc.UUID)
+		if err != nil && ctx.Err() == nil {
+			gobot.DefaultLogger().WithComponent(p.Name()).Error("advertising stopped", "error", err)
+		}
+	}()
+	return
+}
+
+// Finalize stops advertising, and removes the service from the BLE device.
+func (p *PeripheralAdaptor) Finalize() (err error) {
+	p.mutex.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.mutex.Unlock()
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	bleMutex.Lock()
+	defer bleMutex.Unlock()
+	return blelib.RemoveAllServices()
+}
+
+// service builds the GATT service from the commands and notifications
+func (p *PeripheralAdaptor) service() (*blelib.Service, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	uuid, err := blelib.Parse(p.serviceUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid service "+p.serviceUUID)
+	}
+	svc := blelib.NewService(uuid)
+
+	for cUUID := range p.commands {
+		cUUID := cUUID
+		u, err := blelib.Parse(cUUID)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid characteristic "+cUUID)
+		}
+		c := svc.NewCharacteristic(u)
+		c.HandleWrite(blelib.WriteHandlerFunc(func(req blelib.Request, rsp blelib.ResponseWriter) {
+			p.write(cUUID, req.Data())
+		}))
+		c.HandleRead(blelib.ReadHandlerFunc(func(req blelib.Request, rsp blelib.ResponseWriter) {
+			rsp.Write(p.read(cUUID))
+		}))
+	}
+
+	for _, n := range p.notifications {
+		n := n
+		u, err := blelib.Parse(n.uuid)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid characteristic "+n.uuid)
+		}
+		c := svc.NewCharacteristic(u)
+		c.HandleNotify(blelib.NotifyHandlerFunc(func(req blelib.Request, ntf blelib.Notifier) {
+			events := n.eventer.SubscribeWith(gobot.SubscribeOptions{
+				Pattern: n.event,
+				Policy:  gobot.DropOldest,
+			})
+			defer n.eventer.Unsubscribe(events)
+			for {
+				select {
+				case evt := <-events:
+					if _, err := ntf.Write(peripheralEncode(evt.Data)); err != nil {
+						return
+					}
+				case <-ntf.Context().Done():
+					return
+				}
+			}
+		}))
+	}
+	return svc, nil
+}
+
+// write runs the command of the characteristic cUUID given the data written
+// to it, keeping the result for the next read
+func (p *PeripheralAdaptor) write(cUUID string, data []byte) {
+	p.mutex.Lock()
+	name := p.commands[cUUID]
+	p.mutex.Unlock()
+
+	var result interface{}
+	if command := p.commander.Command(name); command != nil {
+		result = command(peripheralParams(data))
+	} else {
+		result = errors.New("unknown command " + name)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.results[cUUID] = peripheralEncode(result)
+}
+
+// read returns the result of the last run of the command of the
+// characteristic cUUID
+func (p *PeripheralAdaptor) read(cUUID string) []byte {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.results[cUUID]
+}
+
+// peripheralParams decodes the params of a command from the data written
+func peripheralParams(data []byte) map[string]interface{} {
+	params := map[string]interface{}{}
+	if len(data) == 0 {
+		return params
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return map[string]interface{}{"value": string(data)}
+	}
+	return params
+}
+
+// peripheralEncode encodes the result of a command or the data of an event,
+// an error being encoded as a JSON object holding its message
+func peripheralEncode(data interface{}) []byte {
+	if err, ok := data.(error); ok {
+		data = map[string]interface{}{"error": err.Error()}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		b, _ = json.Marshal(map[string]interface{}{"error": err.Error()})
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package ble
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*PeripheralAdaptor)(nil)
+
+func initTestPeripheralAdaptor() *PeripheralAdaptor {
+	c := gobot.NewCommander()
+	c.AddCommand("Drive", func(params map[string]interface{}) interface{} {
+		return params
+	})
+	c.AddCommand("Stop", func(params map[string]interface{}) interface{} {
+		return errors.New("already stopped")
+	})
+	p := NewPeripheralAdaptor("gobot", "1ea0", c)
+	p.AddCommand("1ea1", "Drive")
+	p.AddCommand("1ea2", "Stop")
+	p.AddCommand("1ea3", "Jump")
+	return p
+}
+
+func TestPeripheralAdaptor(t *testing.T) {
+	p := NewPeripheralAdaptor("gobot", "1ea0", gobot.NewCommander())
+	gobottest.Assert(t, strings.HasPrefix(p.Name(), "BLEPeripheral"), true)
+	p.SetName("phone")
+	gobottest.Assert(t, p.Name(), "phone")
+	gobottest.Assert(t, p.LocalName, "gobot")
+	gobottest.Assert(t, p.ServiceUUID(), "1ea0")
+	gobottest.Assert(t, p.Finalize(), nil)
+}
+
+func TestPeripheralAdaptorCommands(t *testing.T) {
+	p := initTestPeripheralAdaptor()
+	gobottest.Assert(t, len(p.read("1ea1")), 0)
+
+	p.write("1ea1", []byte(`{"speed":50}`))
+	gobottest.Assert(t, string(p.read("1ea1")), `{"speed":50}`)
+	p.write("1ea1", []byte("fast"))
+	gobottest.Assert(t, string(p.read("1ea1")), `{"value":"fast"}`)
+	p.write("1ea1", nil)
+	gobottest.Assert(t, string(p.read("1ea1")), `{}`)
+
+	p.write("1ea2", nil)
+	gobottest.Assert(t, string(p.read("1ea2")), `{"error":"already stopped"}`)
+	p.write("1ea3", nil)
+	gobottest.Assert(t, string(p.read("1ea3")), `{"error":"unknown command Jump"}`)
+}
+
+func TestPeripheralAdaptorNotification(t *testing.T) {
+	p := initTestPeripheralAdaptor()
+	e := gobot.NewEventer()
+	p.AddNotification("1ea4", e, "distance")
+	gobottest.Assert(t, p.notifications[0].uuid, "1ea4")
+	gobottest.Assert(t, p.notifications[0].event, "distance")
+
+	gobottest.Assert(t, string(peripheralEncode(12.5)), "12.5")
+	gobottest.Assert(t, string(peripheralEncode(make(chan int))),
+		`{"error":"json: unsupported type: chan int"}`)
+}
@@ -0,0 +1,39 @@
+/*
+Package camera contains the Gobot driver streaming the view of a camera, such
+as an USB webcam or the camera module of a Raspberry Pi, as MJPEG over HTTP.
+
+Installing:
+
+	go get gobot.io/x/gobot && go install gobot.io/x/gobot/platforms/camera
+
+Example:
+
+	package main
+
+	import (
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/api"
+		"gobot.io/x/gobot/platforms/camera"
+	)
+
+	func main() {
+		master := gobot.NewMaster()
+		a := api.NewAPI(master)
+
+		front := camera.NewStreamDriver(camera.NewV4L2Source("/dev/video0",
+			camera.Options{Width: 640, Height: 480, FPS: 15}))
+		a.Get("/camera/front", front.ServeHTTP)
+		a.Get("/camera/front.jpg", front.ServeSnapshot)
+		a.Start()
+
+		robot := gobot.NewRobot("cameraBot",
+			[]gobot.Device{front},
+		)
+		master.AddRobot(robot)
+		master.Start()
+	}
+
+For further information refer to camera README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/camera/README.md
+*/
+package camera // import "gobot.io/x/gobot/platforms/camera"
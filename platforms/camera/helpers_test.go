@@ -0,0 +1,37 @@
+package camera
+
+import (
+	"io"
+	"sync"
+)
+
+// testSource returns the frames sent on its channel
+type testSource struct {
+	frames chan []byte
+	opened bool
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newTestSource() *testSource {
+	return &testSource{frames: make(chan []byte), closed: make(chan struct{})}
+}
+
+func (s *testSource) Open() error {
+	s.opened = true
+	return nil
+}
+
+func (s *testSource) ReadFrame() ([]byte, error) {
+	select {
+	case frame := <-s.frames:
+		return frame, nil
+	case <-s.closed:
+		return nil, io.EOF
+	}
+}
+
+func (s *testSource) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
@@ -0,0 +1,127 @@
+package camera
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// ErrInvalidJPEG is returned when a stream of JPEG frames is corrupted.
+var ErrInvalidJPEG = errors.New("Invalid JPEG frame")
+
+// JPEG markers
+const (
+	jpegMarker = 0xFF
+	jpegSOI    = 0xD8
+	jpegEOI    = 0xD9
+	jpegSOS    = 0xDA
+	jpegRST0   = 0xD0
+	jpegRST7   = 0xD7
+	jpegTEM    = 0x01
+)
+
+// ReadJPEG returns the next JPEG frame of a MJPEG stream, a concatenation of
+// JPEG images such as the output of a camera, skipping any bytes before its
+// start. The segments are parsed up to the image data, so that the end of a
+// thumbnail embedded in the headers is not mistaken for the end of the frame.
+func ReadJPEG(r *bufio.Reader) ([]byte, error) {
+	// start of image
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != jpegMarker {
+			continue
+		}
+		next, err := r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if next[0] == jpegSOI {
+			r.ReadByte()
+			break
+		}
+	}
+	frame := []byte{jpegMarker, jpegSOI}
+
+	// header segments, up to the start of scan
+	for {
+		marker, err := readMarker(r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		frame = append(frame, jpegMarker, marker)
+		if marker == jpegEOI {
+			return frame, nil
+		}
+		if marker == jpegTEM || (marker >= jpegRST0 && marker <= jpegRST7) {
+			continue
+		}
+		length := make([]byte, 2)
+		if _, err = io.ReadFull(r, length); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		n := int(length[0])<<8 | int(length[1])
+		if n < 2 {
+			return nil, ErrInvalidJPEG
+		}
+		segment := make([]byte, n)
+		copy(segment, length)
+		if _, err = io.ReadFull(r, segment[2:]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		frame = append(frame, segment...)
+		if marker == jpegSOS {
+			break
+		}
+	}
+
+	// entropy coded data, where a marker byte is followed by a 0 stuffed byte
+	// or a restart marker until the end of image
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		frame = append(frame, b)
+		if b != jpegMarker {
+			continue
+		}
+		next, err := r.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		frame = append(frame, next)
+		if next == jpegEOI {
+			return frame, nil
+		}
+	}
+}
+
+// readMarker reads the next marker, skipping the fill bytes
+func readMarker(r *bufio.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b != jpegMarker {
+		return 0, ErrInvalidJPEG
+	}
+	for {
+		b, err = r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != jpegMarker {
+			return b, nil
+		}
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package camera
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// testJPEG returns a minimal JPEG frame whose image data is data, with an
+// APP1 segment holding a thumbnail
+func testJPEG(data ...byte) []byte {
+	frame := []byte{0xFF, 0xD8}
+	// APP1 with an embedded thumbnail
+	frame = append(frame, 0xFF, 0xE1, 0x00, 0x08, 0xFF, 0xD8, 0x12, 0x34, 0xFF, 0xD9)
+	// start of scan
+	frame = append(frame, 0xFF, 0xDA, 0x00, 0x04, 0x01, 0x02)
+	frame = append(frame, data...)
+	return append(frame, 0xFF, 0xD9)
+}
+
+func TestReadJPEG(t *testing.T) {
+	first := testJPEG(0x11, 0xFF, 0x00, 0x22, 0xFF, 0xD0, 0x33)
+	second := testJPEG(0x44)
+	stream := append([]byte{0x00, 0xFF, 0x42}, first...)
+	stream = append(stream, second...)
+	r := bufio.NewReader(bytes.NewReader(stream))
+
+	frame, err := ReadJPEG(r)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, frame, first)
+
+	frame, err = ReadJPEG(r)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, frame, second)
+
+	_, err = ReadJPEG(r)
+	gobottest.Assert(t, err, io.EOF)
+}
+
+func TestReadJPEGTruncated(t *testing.T) {
+	frame := testJPEG(0x11, 0x22)
+	r := bufio.NewReader(bytes.NewReader(frame[:len(frame)-3]))
+	_, err := ReadJPEG(r)
+	gobottest.Assert(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadJPEGInvalid(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader([]byte{0xFF, 0xD8, 0x12, 0x34}))
+	_, err := ReadJPEG(r)
+	gobottest.Assert(t, err, ErrInvalidJPEG)
+}
@@ -0,0 +1,113 @@
+package camera
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+)
+
+// Source captures the JPEG frames of a camera.
+type Source interface {
+	// Open starts the capture
+	Open() error
+	// ReadFrame blocks until the next frame is captured and returns it,
+	// io.EOF once the Source is closed
+	ReadFrame() ([]byte, error)
+	// Close stops the capture
+	Close() error
+}
+
+// Options are the capture settings of a Source. The zero values let the
+// camera choose.
+type Options struct {
+	// Width and Height of the frames, in pixels
+	Width, Height int
+	// FPS is the number of frames captured per second
+	FPS int
+}
+
+// CommandSource captures the frames from the MJPEG stream written to its
+// standard output by a command, such as libcamera-vid or ffmpeg.
+type CommandSource struct {
+	name   string
+	args   []string
+	cmd    *exec.Cmd
+	reader *bufio.Reader
+	mutex  sync.Mutex
+}
+
+// NewCommandSource returns a new CommandSource running name with args.
+func NewCommandSource(name string, args ...string) *CommandSource {
+	return &CommandSource{name: name, args: args}
+}
+
+// NewLibcameraSource returns a new CommandSource capturing the camera of a
+// Raspberry Pi with the libcamera-vid command of the libcamera apps.
+func NewLibcameraSource(opts Options) *CommandSource {
+	args := []string{"-t", "0", "-n", "--codec", "mjpeg", "-o", "-"}
+	if opts.Width > 0 && opts.Height > 0 {
+		args = append(args, "--width", strconv.Itoa(opts.Width), "--height", strconv.Itoa(opts.Height))
+	}
+	if opts.FPS > 0 {
+		args = append(args, "--framerate", strconv.Itoa(opts.FPS))
+	}
+	return NewCommandSource("libcamera-vid", args...)
+}
+
+// Open starts the command
+func (s *CommandSource) Open() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cmd != nil {
+		return errors.New("Camera command is already running")
+	}
+
+	cmd := exec.Command(s.name, s.args...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	s.cmd = cmd
+	s.reader = bufio.NewReaderSize(out, 64*1024)
+	return nil
+}
+
+// ReadFrame returns the next frame written by the command
+func (s *CommandSource) ReadFrame() ([]byte, error) {
+	s.mutex.Lock()
+	reader := s.reader
+	s.mutex.Unlock()
+	if reader == nil {
+		return nil, io.EOF
+	}
+	frame, err := ReadJPEG(reader)
+	if err != nil {
+		s.mutex.Lock()
+		closed := s.cmd == nil
+		s.mutex.Unlock()
+		if closed || err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+	}
+	return frame, err
+}
+
+// Close stops the command
+func (s *CommandSource) Close() error {
+	s.mutex.Lock()
+	cmd := s.cmd
+	s.cmd = nil
+	s.mutex.Unlock()
+	if cmd == nil {
+		return nil
+	}
+	cmd.Process.Kill()
+	cmd.Wait()
+	return nil
+}
@@ -0,0 +1,61 @@
+package camera
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ Source = (*CommandSource)(nil)
+var _ Source = (*V4L2Source)(nil)
+
+func TestNewLibcameraSource(t *testing.T) {
+	s := NewLibcameraSource(Options{Width: 640, Height: 480, FPS: 15})
+	gobottest.Assert(t, s.name, "libcamera-vid")
+	gobottest.Assert(t, strings.Join(s.args, " "),
+		"-t 0 -n --codec mjpeg -o - --width 640 --height 480 --framerate 15")
+
+	s = NewLibcameraSource(Options{})
+	gobottest.Assert(t, strings.Join(s.args, " "), "-t 0 -n --codec mjpeg -o -")
+}
+
+func TestCommandSource(t *testing.T) {
+	if _, err := exec.LookPath("printf"); err != nil {
+		t.Skip("printf is not available")
+	}
+	escaped := ""
+	for _, b := range append(testJPEG(0x11), testJPEG(0x22)...) {
+		escaped += fmt.Sprintf("\\%03o", b)
+	}
+	s := NewCommandSource("printf", escaped)
+	gobottest.Assert(t, s.Open(), nil)
+	gobottest.Refute(t, s.Open(), nil)
+
+	frame, err := s.ReadFrame()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, frame, testJPEG(0x11))
+	frame, err = s.ReadFrame()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, frame, testJPEG(0x22))
+	_, err = s.ReadFrame()
+	gobottest.Assert(t, err, io.EOF)
+	gobottest.Assert(t, s.Close(), nil)
+}
+
+func TestCommandSourceNotFound(t *testing.T) {
+	s := NewCommandSource("gobot-no-such-camera-command")
+	gobottest.Refute(t, s.Open(), nil)
+	_, err := s.ReadFrame()
+	gobottest.Assert(t, err, io.EOF)
+}
+
+func TestV4L2SourceNotFound(t *testing.T) {
+	s := NewV4L2Source("/dev/gobot-no-such-video", Options{})
+	gobottest.Assert(t, s.Device(), "/dev/gobot-no-such-video")
+	gobottest.Refute(t, s.Open(), nil)
+	gobottest.Assert(t, s.Close(), nil)
+}
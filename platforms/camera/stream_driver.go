@@ -0,0 +1,203 @@
+package camera
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+const (
+	// Frame event, with the JPEG frame
+	Frame = "frame"
+	// Error event
+	Error = "error"
+)
+
+// mjpegBoundary separates the frames of the MJPEG streams served
+const mjpegBoundary = "gobotframe"
+
+// StreamDriver captures the frames of a camera Source and serves them over
+// HTTP as an MJPEG stream, which browsers show in an img tag, so that a
+// dashboard shows the view of the robot without external software.
+type StreamDriver struct {
+	name    string
+	source  Source
+	frame   []byte
+	fresh   chan struct{}
+	clients int
+	done    chan struct{}
+	mutex   sync.Mutex
+	gobot.Eventer
+}
+
+// NewStreamDriver returns a new StreamDriver capturing the frames of source,
+// such as a V4L2Source or the NewLibcameraSource.
+func NewStreamDriver(source Source) *StreamDriver {
+	d := &StreamDriver{
+		name:    gobot.DefaultName("Camera"),
+		source:  source,
+		fresh:   make(chan struct{}),
+		Eventer: gobot.NewEventer(),
+	}
+
+	d.AddEvent(Frame)
+	d.AddEvent(Error)
+
+	return d
+}
+
+// Name returns the Driver name
+func (d *StreamDriver) Name() string { return d.name }
+
+// SetName sets the Driver name
+func (d *StreamDriver) SetName(n string) { d.name = n }
+
+// Connection returns nil, the StreamDriver capturing its Source directly
+func (d *StreamDriver) Connection() gobot.Connection { return nil }
+
+// Start opens the Source and captures its frames
+//
+// Emits the Events:
+// 	Frame []byte - Each JPEG frame captured
+// 	Error error - On error capturing a frame, the capture stopping
+func (d *StreamDriver) Start() (err error) {
+	if err = d.source.Open(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	d.mutex.Lock()
+	d.done = done
+	d.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+		for {
+			frame, err := d.source.ReadFrame()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				d.Publish(Error, err)
+				return
+			}
+			d.setFrame(frame)
+			d.Publish(Frame, frame)
+		}
+	}()
+	return
+}
+
+// Halt closes the Source, ending the MJPEG streams
+func (d *StreamDriver) Halt() (err error) {
+	d.mutex.Lock()
+	done := d.done
+	d.done = nil
+	d.mutex.Unlock()
+	if done == nil {
+		return nil
+	}
+	err = d.source.Close()
+	<-done
+	return err
+}
+
+// LastFrame returns the last JPEG frame captured, nil until the first one.
+func (d *StreamDriver) LastFrame() []byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.frame
+}
+
+// Clients returns the number of MJPEG streams being served.
+func (d *StreamDriver) Clients() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.clients
+}
+
+// setFrame keeps frame as the last one and wakes up the streams
+func (d *StreamDriver) setFrame(frame []byte) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.frame = frame
+	close(d.fresh)
+	d.fresh = make(chan struct{})
+}
+
+// next returns the last frame and the channel closed on the next one
+func (d *StreamDriver) next() ([]byte, chan struct{}) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.frame, d.fresh
+}
+
+// ServeHTTP serves the frames as an MJPEG stream until the client goes away
+// or the Driver halts. A client slower than the camera skips frames rather
+// than slowing down the capture. Use it as a handler of the API:
+//
+// 	a.Get("/camera", camera.ServeHTTP)
+func (d *StreamDriver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mutex.Lock()
+	done := d.done
+	if done == nil {
+		d.mutex.Unlock()
+		http.Error(w, "Camera is not started", http.StatusServiceUnavailable)
+		return
+	}
+	d.clients++
+	d.mutex.Unlock()
+	frame, fresh := d.next()
+	defer func() {
+		d.mutex.Lock()
+		d.clients--
+		d.mutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Connection", "close")
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		if frame != nil {
+			if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpegBoundary, len(frame)); err != nil {
+				return
+			}
+			if _, err := w.Write(frame); err != nil {
+				return
+			}
+			if _, err := io.WriteString(w, "\r\n"); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		select {
+		case <-fresh:
+			frame, fresh = d.next()
+		case <-done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// ServeSnapshot serves the last frame as a JPEG image.
+func (d *StreamDriver) ServeSnapshot(w http.ResponseWriter, r *http.Request) {
+	frame := d.LastFrame()
+	if frame == nil {
+		http.Error(w, "No frame captured", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Length", strconv.Itoa(len(frame)))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Write(frame)
+}
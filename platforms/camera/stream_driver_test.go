@@ -0,0 +1,136 @@
+package camera
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*StreamDriver)(nil)
+
+// readPart reads the next part of a MJPEG stream using its Content-Length,
+// as the browsers do, not to wait for the next boundary
+func readPart(r *bufio.Reader, boundary string) (textproto.MIMEHeader, []byte, error) {
+	tp := textproto.NewReader(r)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, nil, err
+	}
+	if line == "" {
+		if line, err = tp.ReadLine(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if line != "--"+boundary {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+	n, _ := strconv.Atoi(header.Get("Content-Length"))
+	data := make([]byte, n)
+	_, err = io.ReadFull(r, data)
+	return header, data, err
+}
+
+func TestStreamDriver(t *testing.T) {
+	d := NewStreamDriver(newTestSource())
+	gobottest.Assert(t, d.Connection(), nil)
+	d.SetName("front")
+	gobottest.Assert(t, d.Name(), "front")
+	gobottest.Assert(t, d.LastFrame() == nil, true)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestStreamDriverFrames(t *testing.T) {
+	source := newTestSource()
+	d := NewStreamDriver(source)
+	frames := d.Subscribe()
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, source.opened, true)
+
+	source.frames <- testJPEG(0x11)
+	select {
+	case evt := <-frames:
+		gobottest.Assert(t, evt.Name, Frame)
+		gobottest.Assert(t, evt.Data, testJPEG(0x11))
+	case <-time.After(time.Second):
+		t.Fatal("Frame was not published")
+	}
+	gobottest.Assert(t, d.LastFrame(), testJPEG(0x11))
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestStreamDriverServeHTTP(t *testing.T) {
+	source := newTestSource()
+	d := NewStreamDriver(source)
+	server := httptest.NewServer(d)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, resp.StatusCode, http.StatusServiceUnavailable)
+	resp.Body.Close()
+
+	gobottest.Assert(t, d.Start(), nil)
+	source.frames <- testJPEG(0x11)
+	for d.LastFrame() == nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	resp, err = http.Get(server.URL)
+	gobottest.Assert(t, err, nil)
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	gobottest.Assert(t, mediaType, "multipart/x-mixed-replace")
+	r := bufio.NewReader(resp.Body)
+
+	// the last frame at once, then each new one
+	header, data, err := readPart(r, params["boundary"])
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, header.Get("Content-Type"), "image/jpeg")
+	gobottest.Assert(t, data, testJPEG(0x11))
+	gobottest.Assert(t, d.Clients(), 1)
+
+	source.frames <- testJPEG(0x22)
+	_, data, err = readPart(r, params["boundary"])
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, data, testJPEG(0x22))
+
+	// halting ends the stream
+	gobottest.Assert(t, d.Halt(), nil)
+	_, _, err = readPart(r, params["boundary"])
+	gobottest.Refute(t, err, nil)
+	resp.Body.Close()
+}
+
+func TestStreamDriverServeSnapshot(t *testing.T) {
+	source := newTestSource()
+	d := NewStreamDriver(source)
+
+	w := httptest.NewRecorder()
+	d.ServeSnapshot(w, httptest.NewRequest("GET", "/snapshot", nil))
+	gobottest.Assert(t, w.Code, http.StatusServiceUnavailable)
+
+	gobottest.Assert(t, d.Start(), nil)
+	source.frames <- testJPEG(0x11)
+	for d.LastFrame() == nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	w = httptest.NewRecorder()
+	d.ServeSnapshot(w, httptest.NewRequest("GET", "/snapshot", nil))
+	gobottest.Assert(t, w.Code, http.StatusOK)
+	gobottest.Assert(t, w.Header().Get("Content-Type"), "image/jpeg")
+	gobottest.Assert(t, w.Body.Bytes(), testJPEG(0x11))
+	gobottest.Assert(t, d.Halt(), nil)
+}
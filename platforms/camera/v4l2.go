@@ -0,0 +1,18 @@
+package camera
+
+// V4L2Source captures the MJPEG frames of a V4L2 device, such as an USB
+// webcam, with memory mapped buffers. It is only available on Linux.
+type V4L2Source struct {
+	device string
+	opts   Options
+	v4l2
+}
+
+// NewV4L2Source returns a new V4L2Source capturing device, e.g.
+// "/dev/video0".
+func NewV4L2Source(device string, opts Options) *V4L2Source {
+	return &V4L2Source{device: device, opts: opts}
+}
+
+// Device returns the path of the V4L2 device
+func (s *V4L2Source) Device() string { return s.device }
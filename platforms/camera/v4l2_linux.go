@@ -0,0 +1,289 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// V4L2 constants, from linux/videodev2.h
+const (
+	v4l2BufTypeVideoCapture = 1
+	v4l2MemoryMMAP          = 1
+	v4l2FieldAny            = 0
+	v4l2CapVideoCapture     = 0x00000001
+	v4l2CapStreaming        = 0x04000000
+	v4l2PixFmtMJPEG         = 'M' | 'J'<<8 | 'P'<<16 | 'G'<<24
+	v4l2BufferCount         = 4
+)
+
+type v4l2Capability struct {
+	Driver       [16]byte
+	Card         [32]byte
+	BusInfo      [32]byte
+	Version      uint32
+	Capabilities uint32
+	DeviceCaps   uint32
+	Reserved     [3]uint32
+}
+
+type v4l2PixFormat struct {
+	Width        uint32
+	Height       uint32
+	PixelFormat  uint32
+	Field        uint32
+	BytesPerLine uint32
+	SizeImage    uint32
+	ColorSpace   uint32
+	Priv         uint32
+	Flags        uint32
+	YcbcrEnc     uint32
+	Quantization uint32
+	XferFunc     uint32
+}
+
+// v4l2Format holds the pix member of its union, aligned like the pointers of
+// the other members
+type v4l2Format struct {
+	Type uint32
+	_    [unsafe.Sizeof(uintptr(0)) - 4]byte
+	Pix  v4l2PixFormat
+	_    [200 - unsafe.Sizeof(v4l2PixFormat{})]byte
+}
+
+type v4l2CaptureParm struct {
+	Capability   uint32
+	CaptureMode  uint32
+	Numerator    uint32
+	Denominator  uint32
+	ExtendedMode uint32
+	ReadBuffers  uint32
+	Reserved     [4]uint32
+}
+
+type v4l2StreamParm struct {
+	Type    uint32
+	Capture v4l2CaptureParm
+	_       [200 - unsafe.Sizeof(v4l2CaptureParm{})]byte
+}
+
+type v4l2RequestBuffers struct {
+	Count        uint32
+	Type         uint32
+	Memory       uint32
+	Capabilities uint32
+	Flags        uint8
+	Reserved     [3]uint8
+}
+
+type v4l2Buffer struct {
+	Index     uint32
+	Type      uint32
+	BytesUsed uint32
+	Flags     uint32
+	Field     uint32
+	Timestamp syscall.Timeval
+	Timecode  [16]byte
+	Sequence  uint32
+	Memory    uint32
+	// Offset is the offset member of the m union
+	Offset    uintptr
+	Length    uint32
+	Reserved2 uint32
+	RequestFD uint32
+}
+
+// ioctl request numbers, encoding the direction and size of their argument
+var (
+	vidiocQueryCap  = v4l2IOC(2, 0, unsafe.Sizeof(v4l2Capability{}))
+	vidiocSFmt      = v4l2IOC(3, 5, unsafe.Sizeof(v4l2Format{}))
+	vidiocReqBufs   = v4l2IOC(3, 8, unsafe.Sizeof(v4l2RequestBuffers{}))
+	vidiocQueryBuf  = v4l2IOC(3, 9, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocQBuf      = v4l2IOC(3, 15, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocDQBuf     = v4l2IOC(3, 17, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocStreamOn  = v4l2IOC(1, 18, unsafe.Sizeof(int32(0)))
+	vidiocStreamOff = v4l2IOC(1, 19, unsafe.Sizeof(int32(0)))
+	vidiocSParm     = v4l2IOC(3, 22, unsafe.Sizeof(v4l2StreamParm{}))
+)
+
+func v4l2IOC(dir uintptr, nr uintptr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | 'V'<<8 | nr
+}
+
+// v4l2 is the state of an open V4L2Source
+type v4l2 struct {
+	file    *os.File
+	buffers [][]byte
+	closed  bool
+	mutex   sync.Mutex
+}
+
+// Open sets the device to MJPEG at the size and rate of the Options, and
+// starts streaming
+func (s *V4L2Source) Open() (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file != nil {
+		return errors.New("V4L2 device is already open")
+	}
+
+	file, err := os.OpenFile(s.device, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	defer func() {
+		if err != nil {
+			s.release()
+		}
+	}()
+
+	var cap v4l2Capability
+	if err = s.ioctl(vidiocQueryCap, unsafe.Pointer(&cap)); err != nil {
+		return err
+	}
+	if cap.Capabilities&v4l2CapVideoCapture == 0 || cap.Capabilities&v4l2CapStreaming == 0 {
+		return fmt.Errorf("%v is not a streaming capture device", s.device)
+	}
+
+	format := v4l2Format{Type: v4l2BufTypeVideoCapture}
+	format.Pix = v4l2PixFormat{
+		Width:       uint32(s.opts.Width),
+		Height:      uint32(s.opts.Height),
+		PixelFormat: v4l2PixFmtMJPEG,
+		Field:       v4l2FieldAny,
+	}
+	if err = s.ioctl(vidiocSFmt, unsafe.Pointer(&format)); err != nil {
+		return err
+	}
+	if format.Pix.PixelFormat != v4l2PixFmtMJPEG {
+		return fmt.Errorf("%v does not support MJPEG", s.device)
+	}
+
+	if s.opts.FPS > 0 {
+		parm := v4l2StreamParm{Type: v4l2BufTypeVideoCapture}
+		parm.Capture.Numerator = 1
+		parm.Capture.Denominator = uint32(s.opts.FPS)
+		if err = s.ioctl(vidiocSParm, unsafe.Pointer(&parm)); err != nil {
+			return err
+		}
+	}
+
+	req := v4l2RequestBuffers{Count: v4l2BufferCount, Type: v4l2BufTypeVideoCapture, Memory: v4l2MemoryMMAP}
+	if err = s.ioctl(vidiocReqBufs, unsafe.Pointer(&req)); err != nil {
+		return err
+	}
+	for i := uint32(0); i < req.Count; i++ {
+		buf := v4l2Buffer{Index: i, Type: v4l2BufTypeVideoCapture, Memory: v4l2MemoryMMAP}
+		if err = s.ioctl(vidiocQueryBuf, unsafe.Pointer(&buf)); err != nil {
+			return err
+		}
+		var mem []byte
+		mem, err = syscall.Mmap(int(s.file.Fd()), int64(buf.Offset), int(buf.Length), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			return err
+		}
+		s.buffers = append(s.buffers, mem)
+		if err = s.ioctl(vidiocQBuf, unsafe.Pointer(&buf)); err != nil {
+			return err
+		}
+	}
+
+	bufType := int32(v4l2BufTypeVideoCapture)
+	if err = s.ioctl(vidiocStreamOn, unsafe.Pointer(&bufType)); err != nil {
+		return err
+	}
+	s.closed = false
+	return nil
+}
+
+// ReadFrame dequeues the next filled buffer, copies the frame out of it and
+// queues it back
+func (s *V4L2Source) ReadFrame() ([]byte, error) {
+	s.mutex.Lock()
+	file := s.file
+	open := file != nil && !s.closed
+	s.mutex.Unlock()
+	if !open {
+		return nil, io.EOF
+	}
+
+	buf := v4l2Buffer{Type: v4l2BufTypeVideoCapture, Memory: v4l2MemoryMMAP}
+	for {
+		err := s.ioctlFile(file, vidiocDQBuf, unsafe.Pointer(&buf))
+		if err == syscall.EINTR || err == syscall.EAGAIN {
+			continue
+		}
+		if err != nil {
+			if s.isClosed() {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		break
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return nil, io.EOF
+	}
+	frame := append([]byte{}, s.buffers[buf.Index][:buf.BytesUsed]...)
+	if err := s.ioctl(vidiocQBuf, unsafe.Pointer(&buf)); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// Close stops streaming, which wakes up a pending ReadFrame, and releases
+// the device
+func (s *V4L2Source) Close() error {
+	s.mutex.Lock()
+	if s.file == nil {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	file := s.file
+	s.mutex.Unlock()
+
+	bufType := int32(v4l2BufTypeVideoCapture)
+	s.ioctlFile(file, vidiocStreamOff, unsafe.Pointer(&bufType))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.release()
+}
+
+func (s *V4L2Source) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closed
+}
+
+// release unmaps the buffers and closes the device
+func (s *V4L2Source) release() error {
+	for _, mem := range s.buffers {
+		syscall.Munmap(mem)
+	}
+	s.buffers = nil
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *V4L2Source) ioctl(request uintptr, arg unsafe.Pointer) error {
+	return s.ioctlFile(s.file, request, arg)
+}
+
+func (s *V4L2Source) ioctlFile(file *os.File, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
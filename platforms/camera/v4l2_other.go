@@ -0,0 +1,18 @@
+// +build !linux
+
+package camera
+
+import "errors"
+
+type v4l2 struct{}
+
+var errV4L2Unsupported = errors.New("V4L2 is only available on Linux")
+
+// Open returns an error, V4L2 being only available on Linux
+func (s *V4L2Source) Open() error { return errV4L2Unsupported }
+
+// ReadFrame returns an error, V4L2 being only available on Linux
+func (s *V4L2Source) ReadFrame() ([]byte, error) { return nil, errV4L2Unsupported }
+
+// Close does nothing
+func (s *V4L2Source) Close() error { return nil }
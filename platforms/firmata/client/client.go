@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -54,13 +55,30 @@ const (
 	I2CModeContinuousRead    byte = 0x02
 	I2CModeStopReading       byte = 0x03
 	ServoConfig              byte = 0x70
+	SamplingInterval         byte = 0x7A
 )
 
 // Errors
 var (
-	ErrConnected = errors.New("client is already connected")
+	ErrConnected     = errors.New("client is already connected")
+	ErrReservedSysex = errors.New("sysex command is handled by the client")
 )
 
+// reservedSysex are the sysex responses the client handles itself
+var reservedSysex = map[byte]bool{
+	CapabilityResponse:    true,
+	AnalogMappingResponse: true,
+	PinStateResponse:      true,
+	I2CReply:              true,
+	FirmwareQuery:         true,
+	StringData:            true,
+}
+
+// SysexHandler handles the sysex messages sent by the board with a custom
+// command, given the data between the command and the end of the message.
+// It is called from the goroutine reading the board, and must not block.
+type SysexHandler func(data []byte)
+
 // Client represents a client connection to a firmata board
 type Client struct {
 	pins            []Pin
@@ -73,6 +91,12 @@ type Client struct {
 	ConnectTimeout  time.Duration
 	initFunc        func() error
 	initMutex       sync.Mutex
+	eventInterval   time.Duration
+	eventTimes      map[string]time.Time
+	coalesced       map[string]int
+	eventMutex      sync.Mutex
+	sysexHandlers   map[byte]SysexHandler
+	sysexMutex      sync.Mutex
 	gobot.Eventer
 }
 
@@ -101,6 +125,9 @@ func New() *Client {
 		ConnectTimeout:  15 * time.Second,
 		pins:            []Pin{},
 		analogPins:      []int{},
+		eventTimes:      map[string]time.Time{},
+		coalesced:       map[string]int{},
+		sysexHandlers:   map[byte]SysexHandler{},
 		Eventer:         gobot.NewEventer(),
 	}
 
@@ -262,6 +289,33 @@ func (b *Client) DigitalWrite(pin int, value int) error {
 	return b.write([]byte{DigitalMessage | port, portValue & 0x7F, (portValue >> 7) & 0x7F})
 }
 
+// DigitalWritePins writes the values of several pins, keyed by pin, with a
+// single message per port of 8 pins, all sent in one write.
+func (b *Client) DigitalWritePins(values map[int]int) error {
+	touched := map[int]bool{}
+	for pin, value := range values {
+		b.pins[pin].Value = value
+		touched[pin/8] = true
+	}
+	ports := []int{}
+	for port := range touched {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+
+	data := []byte{}
+	for _, port := range ports {
+		portValue := byte(0)
+		for i := 0; i < 8; i++ {
+			if b.pins[8*port+i].Value != 0 {
+				portValue = portValue | (1 << uint(i))
+			}
+		}
+		data = append(data, DigitalMessage|byte(port), portValue&0x7F, (portValue>>7)&0x7F)
+	}
+	return b.write(data)
+}
+
 // ServoConfig sets the min and max pulse width for servo PWM range
 func (b *Client) ServoConfig(pin int, max int, min int) error {
 	ret := []byte{
@@ -318,6 +372,22 @@ func (b *Client) ReportAnalog(pin int, state int) error {
 	return b.togglePinReporting(pin, state, ReportAnalog)
 }
 
+// SetSamplingInterval sets the interval in milliseconds at which the board
+// samples and reports the analog pins, and the i2c devices read continuously.
+func (b *Client) SetSamplingInterval(interval int) error {
+	return b.WriteSysex([]byte{SamplingInterval, byte(interval & 0x7F), byte((interval >> 7) & 0x7F)})
+}
+
+// SetEventInterval sets the minimum interval between two AnalogRead or
+// DigitalRead events of a pin. The reports received in between update the
+// value of the pin, and the latest one is published once the interval has
+// elapsed. A zero interval publishes every report.
+func (b *Client) SetEventInterval(interval time.Duration) {
+	b.eventMutex.Lock()
+	defer b.eventMutex.Unlock()
+	b.eventInterval = interval
+}
+
 // I2cRead reads numBytes from address once.
 func (b *Client) I2cRead(address int, numBytes int) error {
 	return b.WriteSysex([]byte{I2CRequest, byte(address), (I2CModeRead << 3),
@@ -327,11 +397,7 @@ func (b *Client) I2cRead(address int, numBytes int) error {
 // I2cWrite writes data to address.
 func (b *Client) I2cWrite(address int, data []byte) error {
 	ret := []byte{I2CRequest, byte(address), (I2CModeWrite << 3)}
-	for _, val := range data {
-		ret = append(ret, byte(val&0x7F))
-		ret = append(ret, byte((val>>7)&0x7F))
-	}
-	return b.WriteSysex(ret)
+	return b.WriteSysex(append(ret, Encode7Bit(data)...))
 }
 
 // I2cConfig configures the delay in which a register can be read from after it
@@ -356,11 +422,93 @@ func (b *Client) WriteSysex(data []byte) (err error) {
 	return b.write(append([]byte{StartSysex}, append(data, EndSysex)...))
 }
 
+// SendSysex sends a sysex message with a custom command and data, whose bytes
+// must be 7 bits long, see Encode7Bit.
+func (b *Client) SendSysex(command byte, data []byte) error {
+	return b.WriteSysex(append([]byte{command}, data...))
+}
+
+// RegisterSysexHandler registers the handler of the sysex messages with a
+// custom command, which are otherwise published with the SysexResponse event.
+// A nil handler unregisters the previous one. The commands of the responses
+// handled by the client are reserved.
+func (b *Client) RegisterSysexHandler(command byte, handler SysexHandler) error {
+	if reservedSysex[command] {
+		return ErrReservedSysex
+	}
+
+	b.sysexMutex.Lock()
+	defer b.sysexMutex.Unlock()
+	if handler == nil {
+		delete(b.sysexHandlers, command)
+		return nil
+	}
+	b.sysexHandlers[command] = handler
+	return nil
+}
+
+// Encode7Bit splits each byte of data into its 7 least significant bits and
+// its most significant bit, the way firmata sends bytes in a sysex message.
+func Encode7Bit(data []byte) []byte {
+	encoded := make([]byte, 0, 2*len(data))
+	for _, val := range data {
+		encoded = append(encoded, val&0x7F, (val>>7)&0x7F)
+	}
+	return encoded
+}
+
+// Decode7Bit joins the pairs of 7 bit bytes of data encoded by Encode7Bit,
+// ignoring a trailing odd byte.
+func Decode7Bit(data []byte) []byte {
+	decoded := make([]byte, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		decoded = append(decoded, data[i]|data[i+1]<<7)
+	}
+	return decoded
+}
+
 func (b *Client) write(data []byte) (err error) {
 	_, err = b.connection.Write(data[:])
 	return
 }
 
+// publishPin publishes the value reported for a pin, coalescing the reports
+// received within the event interval
+func (b *Client) publishPin(name string, value int) {
+	b.eventMutex.Lock()
+	if b.eventInterval <= 0 {
+		b.eventMutex.Unlock()
+		b.Publish(name, value)
+		return
+	}
+	clock := gobot.DefaultClock()
+	now := clock.Now()
+	last, ok := b.eventTimes[name]
+	if !ok || now.Sub(last) >= b.eventInterval {
+		b.eventTimes[name] = now
+		b.eventMutex.Unlock()
+		b.Publish(name, value)
+		return
+	}
+	_, pending := b.coalesced[name]
+	b.coalesced[name] = value
+	wait := b.eventInterval - now.Sub(last)
+	b.eventMutex.Unlock()
+	if pending {
+		return
+	}
+
+	go func() {
+		<-clock.After(wait)
+		b.eventMutex.Lock()
+		value := b.coalesced[name]
+		delete(b.coalesced, name)
+		b.eventTimes[name] = clock.Now()
+		b.eventMutex.Unlock()
+		b.Publish(name, value)
+	}()
+}
+
 func (b *Client) read(n int) (buf []byte, err error) {
 	buf = make([]byte, n)
 	_, err = io.ReadFull(b.connection, buf)
@@ -395,7 +543,7 @@ func (b *Client) process() (err error) {
 		if len(b.analogPins) > pin {
 			if len(b.pins) > b.analogPins[pin] {
 				b.pins[b.analogPins[pin]].Value = int(value)
-				b.Publish(b.Event(fmt.Sprintf("AnalogRead%v", pin)), b.pins[b.analogPins[pin]].Value)
+				b.publishPin(b.Event(fmt.Sprintf("AnalogRead%v", pin)), b.pins[b.analogPins[pin]].Value)
 			}
 		}
 	case DigitalMessageRangeStart <= messageType &&
@@ -413,7 +561,7 @@ func (b *Client) process() (err error) {
 			if len(b.pins) > pinNumber {
 				if b.pins[pinNumber].Mode == Input {
 					b.pins[pinNumber].Value = int((portValue >> (byte(i) & 0x07)) & 0x01)
-					b.Publish(b.Event(fmt.Sprintf("DigitalRead%v", pinNumber)), b.pins[pinNumber].Value)
+					b.publishPin(b.Event(fmt.Sprintf("DigitalRead%v", pinNumber)), b.pins[pinNumber].Value)
 				}
 			}
 		}
@@ -522,6 +670,16 @@ func (b *Client) process() (err error) {
 			str := currentBuffer[2:]
 			b.Publish(b.Event("StringData"), string(str[:len(str)-1]))
 		default:
+			b.sysexMutex.Lock()
+			handler := b.sysexHandlers[command]
+			b.sysexMutex.Unlock()
+			if handler != nil {
+				data := make([]byte, len(currentBuffer)-3)
+				copy(data, currentBuffer[2:len(currentBuffer)-1])
+				handler(data)
+				break
+			}
+
 			data := make([]byte, len(currentBuffer))
 			copy(data, currentBuffer)
 			b.Publish("SysexResponse", data)
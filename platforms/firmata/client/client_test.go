@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
 )
 
@@ -231,6 +232,17 @@ func TestDigitalWrite(t *testing.T) {
 	gobottest.Assert(t, b.DigitalWrite(13, 0), nil)
 }
 
+func TestDigitalWritePins(t *testing.T) {
+	b := initTestFirmata()
+	b.setConnected(true)
+	testWriteData.Reset()
+	gobottest.Assert(t, b.DigitalWritePins(map[int]int{2: 1, 3: 1, 9: 1, 4: 0}), nil)
+	gobottest.Assert(t, testWriteData.Bytes(), []byte{
+		DigitalMessage | 0, 0x0C, 0x00,
+		DigitalMessage | 1, 0x02, 0x00,
+	})
+}
+
 func TestSetPinMode(t *testing.T) {
 	b := initTestFirmata()
 	b.setConnected(true)
@@ -250,6 +262,49 @@ func TestReportAnalog(t *testing.T) {
 	gobottest.Assert(t, b.ReportAnalog(0, 0), nil)
 }
 
+func TestSetSamplingInterval(t *testing.T) {
+	b := initTestFirmata()
+	b.setConnected(true)
+	writeDataMutex.Lock()
+	testWriteData.Reset()
+	writeDataMutex.Unlock()
+	gobottest.Assert(t, b.SetSamplingInterval(500), nil)
+	writeDataMutex.Lock()
+	defer writeDataMutex.Unlock()
+	gobottest.Assert(t, testWriteData.Bytes(), []byte{StartSysex, SamplingInterval, 0x74, 0x03, EndSysex})
+}
+
+func TestProcessAnalogReadCoalesced(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(1488469265, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	b := initTestFirmata()
+	b.setConnected(true)
+	b.SetEventInterval(100 * time.Millisecond)
+	values := make(chan interface{}, 10)
+	b.On(b.Event("AnalogRead0"), func(data interface{}) {
+		values <- data
+	})
+
+	// the first report is published at once, the next ones are coalesced
+	for _, v := range []byte{1, 2, 3} {
+		SetTestReadData([]byte{0xE0, v, 0x00})
+		b.process()
+	}
+	gobottest.Assert(t, <-values, 1)
+	gobottest.Assert(t, b.pins[14].Value, 3)
+	clock.BlockUntil(1)
+	clock.Advance(100 * time.Millisecond)
+	gobottest.Assert(t, <-values, 3)
+
+	select {
+	case v := <-values:
+		t.Errorf("unexpected event %v", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
 func TestProcessPinState13(t *testing.T) {
 	sem := make(chan bool)
 	b := initTestFirmata()
@@ -466,3 +521,51 @@ func TestProcessSysexData(t *testing.T) {
 		t.Errorf("SysexResponse was not published")
 	}
 }
+
+func TestSendSysex(t *testing.T) {
+	b := initTestFirmata()
+	b.setConnected(true)
+	writeDataMutex.Lock()
+	testWriteData.Reset()
+	writeDataMutex.Unlock()
+	gobottest.Assert(t, b.SendSysex(0x51, Encode7Bit([]byte{0xFF, 0x10})), nil)
+	writeDataMutex.Lock()
+	defer writeDataMutex.Unlock()
+	gobottest.Assert(t, testWriteData.Bytes(), []byte{StartSysex, 0x51, 0x7F, 0x01, 0x10, 0x00, EndSysex})
+}
+
+func TestSysex7Bit(t *testing.T) {
+	gobottest.Assert(t, Encode7Bit([]byte{0x00, 0x80, 0x7F}), []byte{0x00, 0x00, 0x00, 0x01, 0x7F, 0x00})
+	gobottest.Assert(t, Decode7Bit([]byte{0x00, 0x01, 0x7F, 0x01, 0x05}), []byte{0x80, 0xFF})
+}
+
+func TestRegisterSysexHandler(t *testing.T) {
+	b := initTestFirmata()
+	b.setConnected(true)
+	gobottest.Assert(t, b.RegisterSysexHandler(I2CReply, func([]byte) {}), ErrReservedSysex)
+
+	var received []byte
+	gobottest.Assert(t, b.RegisterSysexHandler(17, func(data []byte) {
+		received = data
+	}), nil)
+	SetTestReadData([]byte{240, 17, 1, 2, 3, 247})
+	gobottest.Assert(t, b.process(), nil)
+	gobottest.Assert(t, received, []byte{1, 2, 3})
+
+	// the messages are published again once the handler is unregistered
+	sem := make(chan bool)
+	b.Once("SysexResponse", func(data interface{}) {
+		sem <- true
+	})
+	gobottest.Assert(t, b.RegisterSysexHandler(17, nil), nil)
+	received = nil
+	SetTestReadData([]byte{240, 17, 4, 247})
+	gobottest.Assert(t, b.process(), nil)
+	gobottest.Assert(t, len(received), 0)
+
+	select {
+	case <-sem:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("SysexResponse was not published")
+	}
+}
@@ -20,12 +20,18 @@ type firmataBoard interface {
 	SetPinMode(int, int) error
 	ReportAnalog(int, int) error
 	ReportDigital(int, int) error
+	SetSamplingInterval(int) error
+	SetEventInterval(time.Duration)
 	DigitalWrite(int, int) error
+	DigitalWritePins(map[int]int) error
 	I2cRead(int, int) error
 	I2cWrite(int, []byte) error
 	I2cConfig(int) error
+	PinStateQuery(int) error
 	ServoConfig(int, int, int) error
 	WriteSysex(data []byte) error
+	SendSysex(byte, []byte) error
+	RegisterSysexHandler(byte, client.SysexHandler) error
 	gobot.Eventer
 }
 
@@ -168,6 +174,24 @@ func (f *Adaptor) PwmWrite(pin string, level byte) (err error) {
 	return
 }
 
+// AnalogWrite writes the value to the specified pin in the resolution of its
+// output, e.g. 0-4095 for the DACs of an Arduino Due
+func (f *Adaptor) AnalogWrite(pin string, value int) (err error) {
+	p, err := strconv.Atoi(pin)
+	if err != nil {
+		return err
+	}
+
+	if f.Board.Pins()[p].Mode != client.Pwm {
+		err = f.Board.SetPinMode(p, client.Pwm)
+		if err != nil {
+			return err
+		}
+	}
+	err = f.Board.AnalogWrite(p, value)
+	return
+}
+
 // DigitalWrite writes a value to the pin. Acceptable values are 1 or 0.
 func (f *Adaptor) DigitalWrite(pin string, level byte) (err error) {
 	p, err := strconv.Atoi(pin)
@@ -186,6 +210,25 @@ func (f *Adaptor) DigitalWrite(pin string, level byte) (err error) {
 	return
 }
 
+// DigitalWritePins writes the levels of several pins, keyed by pin, with a
+// single message per port of 8 pins.
+func (f *Adaptor) DigitalWritePins(levels map[string]byte) (err error) {
+	values := map[int]int{}
+	for pin, level := range levels {
+		p, err := strconv.Atoi(pin)
+		if err != nil {
+			return err
+		}
+		if f.Board.Pins()[p].Mode != client.Output {
+			if err = f.Board.SetPinMode(p, client.Output); err != nil {
+				return err
+			}
+		}
+		values[p] = int(level)
+	}
+	return f.Board.DigitalWritePins(values)
+}
+
 // DigitalRead retrieves digital value from specified pin.
 // Returns -1 if the response from the board has timed out
 func (f *Adaptor) DigitalRead(pin string) (val int, err error) {
@@ -231,15 +274,87 @@ func (f *Adaptor) AnalogRead(pin string) (val int, err error) {
 	return f.Board.Pins()[p].Value, nil
 }
 
+// SetSamplingInterval sets the interval at which the board samples and
+// reports the analog pins, 19 Milliseconds by default on most firmwares.
+// A longer interval lowers the traffic of the slower hosts.
+func (f *Adaptor) SetSamplingInterval(interval time.Duration) error {
+	return f.Board.SetSamplingInterval(int(interval / time.Millisecond))
+}
+
+// SetEventInterval sets the minimum interval between two AnalogRead or
+// DigitalRead events of a pin published by the Board. The reports received in
+// between are coalesced, the latest value being published once the interval
+// has elapsed. A zero interval, the default, publishes every report.
+func (f *Adaptor) SetEventInterval(interval time.Duration) {
+	f.Board.SetEventInterval(interval)
+}
+
+// ReportAnalog enables or disables the reporting of the analog pin. A pin
+// whose reporting is disabled keeps its last value.
+func (f *Adaptor) ReportAnalog(pin string, enable bool) (err error) {
+	p, err := strconv.Atoi(pin)
+	if err != nil {
+		return
+	}
+
+	if enable && f.Board.Pins()[f.digitalPin(p)].Mode != client.Analog {
+		if err = f.Board.SetPinMode(f.digitalPin(p), client.Analog); err != nil {
+			return
+		}
+	}
+	return f.Board.ReportAnalog(p, reportState(enable))
+}
+
+// ReportDigital enables or disables the reporting of the digital pin. A pin
+// whose reporting is disabled keeps its last value.
+func (f *Adaptor) ReportDigital(pin string, enable bool) (err error) {
+	p, err := strconv.Atoi(pin)
+	if err != nil {
+		return
+	}
+
+	if enable && f.Board.Pins()[p].Mode != client.Input {
+		if err = f.Board.SetPinMode(p, client.Input); err != nil {
+			return
+		}
+	}
+	return f.Board.ReportDigital(p, reportState(enable))
+}
+
 func (f *Adaptor) WriteSysex(data []byte) error {
 	return f.Board.WriteSysex(data)
 }
 
-// digitalPin converts pin number to digital mapping
+// SendSysex sends a sysex message with a custom command to a board running a
+// firmware with additional features, such as a neopixel or ultrasonic plugin.
+func (f *Adaptor) SendSysex(command byte, data []byte) error {
+	return f.Board.SendSysex(command, data)
+}
+
+// RegisterSysexHandler registers the handler of the sysex messages with a
+// custom command sent by the board. A nil handler unregisters the previous one.
+func (f *Adaptor) RegisterSysexHandler(command byte, handler client.SysexHandler) error {
+	return f.Board.RegisterSysexHandler(command, handler)
+}
+
+// digitalPin converts the analog pin number to its digital pin, using the
+// analog mapping reported by the board, e.g. 54 for A0 on a Mega, or else
+// the layout of an Uno
 func (f *Adaptor) digitalPin(pin int) int {
+	if p, ok := f.analogPin(pin); ok {
+		return p
+	}
 	return pin + 14
 }
 
+// reportState converts enable to the state of a report toggle
+func reportState(enable bool) int {
+	if enable {
+		return 1
+	}
+	return 0
+}
+
 // GetConnection returns an i2c connection to a device on a specified bus.
 // Only supports bus number 0
 func (f *Adaptor) GetConnection(address int, bus int) (connection i2c.Connection, err error) {
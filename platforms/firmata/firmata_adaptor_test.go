@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -21,7 +22,9 @@ import (
 var _ gobot.Adaptor = (*Adaptor)(nil)
 var _ gpio.DigitalReader = (*Adaptor)(nil)
 var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ gpio.DigitalPinsWriter = (*Adaptor)(nil)
 var _ aio.AnalogReader = (*Adaptor)(nil)
+var _ aio.AnalogWriter = (*Adaptor)(nil)
 var _ gpio.PwmWriter = (*Adaptor)(nil)
 var _ gpio.ServoWriter = (*Adaptor)(nil)
 var _ i2c.Connector = (*Adaptor)(nil)
@@ -52,7 +55,12 @@ func (readWriteCloser) Close() error {
 }
 
 type mockFirmataBoard struct {
-	disconnectError error
+	disconnectError  error
+	reports          []string
+	samplingInterval int
+	eventInterval    time.Duration
+	sysex            []byte
+	sysexHandlers    map[byte]client.SysexHandler
 	gobot.Eventer
 	pins []client.Pin
 }
@@ -62,6 +70,7 @@ func newMockFirmataBoard() *mockFirmataBoard {
 		Eventer:         gobot.NewEventer(),
 		disconnectError: nil,
 		pins:            make([]client.Pin, 100),
+		sysexHandlers:   map[byte]client.SysexHandler{},
 	}
 
 	m.pins[1].Value = 1
@@ -78,16 +87,50 @@ func (m mockFirmataBoard) Disconnect() error {
 func (m mockFirmataBoard) Pins() []client.Pin {
 	return m.pins
 }
-func (mockFirmataBoard) AnalogWrite(int, int) error      { return nil }
-func (mockFirmataBoard) SetPinMode(int, int) error       { return nil }
-func (mockFirmataBoard) ReportAnalog(int, int) error     { return nil }
-func (mockFirmataBoard) ReportDigital(int, int) error    { return nil }
+func (mockFirmataBoard) AnalogWrite(int, int) error { return nil }
+func (m *mockFirmataBoard) SetPinMode(pin int, mode int) error {
+	m.reports = append(m.reports, fmt.Sprintf("mode %d=%d", pin, mode))
+	return nil
+}
+func (m *mockFirmataBoard) ReportAnalog(pin int, state int) error {
+	m.reports = append(m.reports, fmt.Sprintf("analog %d=%d", pin, state))
+	return nil
+}
+func (m *mockFirmataBoard) ReportDigital(pin int, state int) error {
+	m.reports = append(m.reports, fmt.Sprintf("digital %d=%d", pin, state))
+	return nil
+}
+func (m *mockFirmataBoard) SetSamplingInterval(interval int) error {
+	m.samplingInterval = interval
+	return nil
+}
+func (m *mockFirmataBoard) SetEventInterval(interval time.Duration) {
+	m.eventInterval = interval
+}
 func (mockFirmataBoard) DigitalWrite(int, int) error     { return nil }
 func (mockFirmataBoard) I2cRead(int, int) error          { return nil }
 func (mockFirmataBoard) I2cWrite(int, []byte) error      { return nil }
 func (mockFirmataBoard) I2cConfig(int) error             { return nil }
 func (mockFirmataBoard) ServoConfig(int, int, int) error { return nil }
 func (mockFirmataBoard) WriteSysex(data []byte) error    { return nil }
+func (m *mockFirmataBoard) PinStateQuery(pin int) error {
+	go m.Publish(fmt.Sprintf("PinState%v", pin), m.pins[pin])
+	return nil
+}
+func (m *mockFirmataBoard) DigitalWritePins(values map[int]int) error {
+	for pin, value := range values {
+		m.pins[pin].Value = value
+	}
+	return nil
+}
+func (m *mockFirmataBoard) SendSysex(command byte, data []byte) error {
+	m.sysex = append([]byte{command}, data...)
+	return nil
+}
+func (m *mockFirmataBoard) RegisterSysexHandler(command byte, handler client.SysexHandler) error {
+	m.sysexHandlers[command] = handler
+	return nil
+}
 
 func initTestAdaptor() *Adaptor {
 	a := NewAdaptor("/dev/null")
@@ -158,11 +201,33 @@ func TestAdaptorPwmWriteBadPin(t *testing.T) {
 	gobottest.Refute(t, a.PwmWrite("xyz", 50), nil)
 }
 
+func TestAdaptorAnalogWrite(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Assert(t, a.AnalogWrite("1", 4095), nil)
+}
+
+func TestAdaptorAnalogWriteBadPin(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Refute(t, a.AnalogWrite("xyz", 50), nil)
+}
+
 func TestAdaptorDigitalWrite(t *testing.T) {
 	a := initTestAdaptor()
 	gobottest.Assert(t, a.DigitalWrite("1", 1), nil)
 }
 
+func TestAdaptorDigitalWritePins(t *testing.T) {
+	a := initTestAdaptor()
+	a.Board.(*mockFirmataBoard).reports = nil
+	gobottest.Assert(t, a.DigitalWritePins(map[string]byte{"2": 1, "9": 1}), nil)
+	reports := a.Board.(*mockFirmataBoard).reports
+	sort.Strings(reports)
+	gobottest.Assert(t, reports, []string{"mode 2=1", "mode 9=1"})
+	gobottest.Assert(t, a.Board.Pins()[2].Value, 1)
+	gobottest.Assert(t, a.Board.Pins()[9].Value, 1)
+	gobottest.Refute(t, a.DigitalWritePins(map[string]byte{"xyz": 1}), nil)
+}
+
 func TestAdaptorDigitalWriteBadPin(t *testing.T) {
 	a := initTestAdaptor()
 	gobottest.Refute(t, a.DigitalWrite("xyz", 50), nil)
@@ -202,6 +267,46 @@ func TestAdaptorAnalogReadBadPin(t *testing.T) {
 	gobottest.Refute(t, err, nil)
 }
 
+func TestAdaptorSamplingInterval(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Assert(t, a.SetSamplingInterval(100*time.Millisecond), nil)
+	gobottest.Assert(t, a.Board.(*mockFirmataBoard).samplingInterval, 100)
+	a.SetEventInterval(50 * time.Millisecond)
+	gobottest.Assert(t, a.Board.(*mockFirmataBoard).eventInterval, 50*time.Millisecond)
+}
+
+func TestAdaptorReport(t *testing.T) {
+	a := initTestAdaptor()
+	b := a.Board.(*mockFirmataBoard)
+	b.pins[4].Mode = client.Output
+	gobottest.Assert(t, a.ReportAnalog("2", true), nil)
+	gobottest.Assert(t, a.ReportAnalog("2", false), nil)
+	gobottest.Assert(t, a.ReportDigital("3", true), nil)
+	gobottest.Assert(t, a.ReportDigital("4", true), nil)
+	gobottest.Assert(t, a.ReportDigital("4", false), nil)
+	gobottest.Assert(t, b.reports, []string{
+		"mode 16=2", "analog 2=1", "analog 2=0",
+		"digital 3=1", "mode 4=0", "digital 4=1", "digital 4=0",
+	})
+
+	gobottest.Refute(t, a.ReportAnalog("xyz", true), nil)
+	gobottest.Refute(t, a.ReportDigital("xyz", true), nil)
+}
+
+func TestAdaptorSysex(t *testing.T) {
+	a := initTestAdaptor()
+	b := a.Board.(*mockFirmataBoard)
+	gobottest.Assert(t, a.SendSysex(0x51, []byte{0x01, 0x02}), nil)
+	gobottest.Assert(t, b.sysex, []byte{0x51, 0x01, 0x02})
+
+	var received []byte
+	gobottest.Assert(t, a.RegisterSysexHandler(0x51, func(data []byte) {
+		received = data
+	}), nil)
+	b.sysexHandlers[0x51]([]byte{0x03})
+	gobottest.Assert(t, received, []byte{0x03})
+}
+
 func TestAdaptorI2cStart(t *testing.T) {
 	a := initTestAdaptor()
 	i2c, err := a.GetConnection(0, 0)
@@ -0,0 +1,199 @@
+package firmata
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/platforms/firmata/client"
+)
+
+// pinStateTimeout is how long PinState waits for the board to answer
+const pinStateTimeout = time.Second
+
+// modeNames are the pin modes as named in the errors
+var modeNames = map[int]string{
+	client.Input:  "digital input",
+	client.Output: "digital output",
+	client.Analog: "analog input",
+	client.Pwm:    "PWM",
+	client.Servo:  "servo",
+}
+
+// PinCapabilities describes a pin of the board, as reported by its answers
+// to the capability and analog mapping queries made on Connect.
+type PinCapabilities struct {
+	// Pin is the digital pin number
+	Pin string
+	// Modes are the client pin modes supported by the pin, e.g. client.Pwm
+	Modes []int
+	// AnalogChannel is the analog pin number of the pin, -1 when it has no
+	// analog input
+	AnalogChannel int
+	// Mode is the mode the pin was last set to
+	Mode int
+}
+
+// Supports returns whether the pin supports the client pin mode.
+func (c PinCapabilities) Supports(mode int) bool {
+	for _, m := range c.Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Pins returns the digital pin numbers of the board, an empty list until it
+// is connected.
+func (f *Adaptor) Pins() []string {
+	pins := []string{}
+	for i := range f.Board.Pins() {
+		pins = append(pins, strconv.Itoa(i))
+	}
+	return pins
+}
+
+// Capabilities returns the capabilities of the digital pin.
+func (f *Adaptor) Capabilities(pin string) (c PinCapabilities, err error) {
+	p, err := strconv.Atoi(pin)
+	if err != nil {
+		return c, err
+	}
+	pins := f.Board.Pins()
+	if p < 0 || p >= len(pins) {
+		return c, fmt.Errorf("pin %v does not exist", pin)
+	}
+
+	c = PinCapabilities{
+		Pin:           pin,
+		Modes:         append([]int{}, pins[p].SupportedModes...),
+		AnalogChannel: -1,
+		Mode:          pins[p].Mode,
+	}
+	if c.Supports(client.Analog) {
+		c.AnalogChannel = pins[p].AnalogChannel
+	}
+	return c, nil
+}
+
+// PinState queries the board for the current mode and state of the digital
+// pin, the state being the value of an output or the position of a servo.
+func (f *Adaptor) PinState(pin string) (mode int, state int, err error) {
+	p, err := strconv.Atoi(pin)
+	if err != nil {
+		return
+	}
+	if p < 0 || p >= len(f.Board.Pins()) {
+		return 0, 0, fmt.Errorf("pin %v does not exist", pin)
+	}
+
+	events := f.Board.SubscribeWith(gobot.SubscribeOptions{Pattern: fmt.Sprintf("PinState%v", p)})
+	defer f.Board.Unsubscribe(events)
+
+	if err = f.Board.PinStateQuery(p); err != nil {
+		return
+	}
+	select {
+	case evt := <-events:
+		state := evt.Data.(client.Pin)
+		return state.Mode, state.State, nil
+	case <-time.After(pinStateTimeout):
+		return 0, 0, fmt.Errorf("pin %v state query timed out", pin)
+	}
+}
+
+// ValidatePin returns an error such as "pin 13 has no PWM" when the digital
+// pin does not support the client pin mode, or the analog pin for
+// client.Analog. Boards which did not report their capabilities support
+// everything.
+func (f *Adaptor) ValidatePin(pin string, mode int) error {
+	if !f.capabilitiesKnown() {
+		return nil
+	}
+
+	if mode == client.Analog {
+		p, err := strconv.Atoi(pin)
+		if err != nil {
+			return err
+		}
+		if _, ok := f.analogPin(p); !ok {
+			return fmt.Errorf("analog pin %v does not exist", pin)
+		}
+		return nil
+	}
+
+	c, err := f.Capabilities(pin)
+	if err != nil {
+		return err
+	}
+	if !c.Supports(mode) {
+		return fmt.Errorf("pin %v has no %v", pin, modeNames[mode])
+	}
+	return nil
+}
+
+// CheckPin validates the pin of the gpio and aio Devices using the board
+// before they start, e.g. failing to start a ServoDriver on a pin without
+// servo.
+func (f *Adaptor) CheckPin(device gobot.Device) error {
+	pinner, ok := device.(gobot.Pinner)
+	if !ok {
+		return nil
+	}
+	mode, ok := pinMode(device)
+	if !ok {
+		return nil
+	}
+	return f.ValidatePin(pinner.Pin(), mode)
+}
+
+// capabilitiesKnown returns whether the board reported the capabilities of
+// its pins
+func (f *Adaptor) capabilitiesKnown() bool {
+	for _, pin := range f.Board.Pins() {
+		if len(pin.SupportedModes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// analogPin returns the digital pin of the analog pin from the analog mapping
+// of the board
+func (f *Adaptor) analogPin(channel int) (int, bool) {
+	for i, pin := range f.Board.Pins() {
+		if pin.AnalogChannel != channel {
+			continue
+		}
+		for _, mode := range pin.SupportedModes {
+			if mode == client.Analog {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// pinMode returns the client pin mode used by the Device
+func pinMode(device gobot.Device) (int, bool) {
+	switch device.(type) {
+	case *gpio.ServoDriver:
+		return client.Servo, true
+	case *aio.AnalogActuatorDriver:
+		return client.Pwm, true
+	case *gpio.LedDriver, *gpio.GroveLedDriver, *gpio.RelayDriver, *gpio.GroveRelayDriver,
+		*gpio.BuzzerDriver, *gpio.GroveBuzzerDriver:
+		return client.Output, true
+	case *gpio.ButtonDriver, *gpio.GroveButtonDriver, *gpio.GroveTouchDriver, *gpio.GroveMagneticSwitchDriver,
+		*gpio.MakeyButtonDriver, *gpio.PIRMotionDriver, *gpio.PulseCounterDriver, *gpio.EndstopDriver:
+		return client.Input, true
+	case *aio.AnalogSensorDriver, *aio.GroveRotaryDriver, *aio.GroveLightSensorDriver,
+		*aio.GrovePiezoVibrationSensorDriver, *aio.GroveSoundSensorDriver, *aio.GroveTemperatureSensorDriver:
+		return client.Analog, true
+	}
+	return 0, false
+}
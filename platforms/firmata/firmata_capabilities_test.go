@@ -0,0 +1,125 @@
+package firmata
+
+import (
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/platforms/firmata/client"
+)
+
+var _ gobot.PinChecker = (*Adaptor)(nil)
+
+// initTestCapabilitiesAdaptor returns an Adaptor whose board has the pins of
+// an Uno: PWM on 3, 5, 6, 9, 10 and 11, and the analog pins A0-A5 on 14-19
+func initTestCapabilitiesAdaptor() (*Adaptor, *mockFirmataBoard) {
+	a := initTestAdaptor()
+	board := a.Board.(*mockFirmataBoard)
+	board.pins = make([]client.Pin, 20)
+	for i := range board.pins {
+		board.pins[i].SupportedModes = []int{client.Input, client.Output, client.Servo}
+		board.pins[i].AnalogChannel = 127
+	}
+	for _, i := range []int{3, 5, 6, 9, 10, 11} {
+		board.pins[i].SupportedModes = append(board.pins[i].SupportedModes, client.Pwm)
+	}
+	for i := 14; i < 20; i++ {
+		board.pins[i].SupportedModes = []int{client.Input, client.Output, client.Analog}
+		board.pins[i].AnalogChannel = i - 14
+	}
+	return a, board
+}
+
+func TestAdaptorPins(t *testing.T) {
+	a, _ := initTestCapabilitiesAdaptor()
+	pins := a.Pins()
+	gobottest.Assert(t, len(pins), 20)
+	gobottest.Assert(t, pins[13], "13")
+}
+
+func TestAdaptorCapabilities(t *testing.T) {
+	a, _ := initTestCapabilitiesAdaptor()
+
+	c, err := a.Capabilities("3")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Pin, "3")
+	gobottest.Assert(t, c.Supports(client.Pwm), true)
+	gobottest.Assert(t, c.Supports(client.Analog), false)
+	gobottest.Assert(t, c.AnalogChannel, -1)
+
+	c, err = a.Capabilities("16")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, c.Supports(client.Analog), true)
+	gobottest.Assert(t, c.AnalogChannel, 2)
+
+	_, err = a.Capabilities("20")
+	gobottest.Assert(t, err.Error(), "pin 20 does not exist")
+	_, err = a.Capabilities("xyz")
+	gobottest.Refute(t, err, nil)
+}
+
+func TestAdaptorPinState(t *testing.T) {
+	a, board := initTestCapabilitiesAdaptor()
+	board.pins[13].Mode = client.Output
+	board.pins[13].State = 1
+
+	mode, state, err := a.PinState("13")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, mode, client.Output)
+	gobottest.Assert(t, state, 1)
+
+	_, _, err = a.PinState("42")
+	gobottest.Assert(t, err.Error(), "pin 42 does not exist")
+}
+
+func TestAdaptorValidatePin(t *testing.T) {
+	a, _ := initTestCapabilitiesAdaptor()
+	gobottest.Assert(t, a.ValidatePin("3", client.Pwm), nil)
+	gobottest.Assert(t, a.ValidatePin("13", client.Pwm).Error(), "pin 13 has no PWM")
+	gobottest.Assert(t, a.ValidatePin("14", client.Servo).Error(), "pin 14 has no servo")
+	gobottest.Assert(t, a.ValidatePin("5", client.Analog), nil)
+	gobottest.Assert(t, a.ValidatePin("6", client.Analog).Error(), "analog pin 6 does not exist")
+	gobottest.Assert(t, a.ValidatePin("25", client.Output).Error(), "pin 25 does not exist")
+}
+
+func TestAdaptorValidatePinUnknownBoard(t *testing.T) {
+	a := initTestAdaptor()
+	gobottest.Assert(t, a.ValidatePin("13", client.Pwm), nil)
+}
+
+func TestAdaptorCheckPin(t *testing.T) {
+	a, _ := initTestCapabilitiesAdaptor()
+	gobottest.Assert(t, a.CheckPin(gpio.NewServoDriver(a, "9")), nil)
+	gobottest.Assert(t, a.CheckPin(gpio.NewLedDriver(a, "13")), nil)
+	gobottest.Assert(t, a.CheckPin(aio.NewAnalogSensorDriver(a, "0")), nil)
+	gobottest.Assert(t, a.CheckPin(aio.NewAnalogActuatorDriver(a, "13")).Error(), "pin 13 has no PWM")
+	gobottest.Assert(t, a.CheckPin(gpio.NewGroveButtonDriver(a, "30")).Error(), "pin 30 does not exist")
+	gobottest.Assert(t, a.CheckPin(gpio.NewDirectPinDriver(a, "30")), nil)
+}
+
+func TestAdaptorCheckPinOnStart(t *testing.T) {
+	a, _ := initTestCapabilitiesAdaptor()
+	servo := gpio.NewServoDriver(a, "14")
+	r := gobot.NewRobot("bot", []gobot.Connection{a}, []gobot.Device{servo})
+	err := r.Start(false)
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, strings.Contains(err.Error(), "pin 14 has no servo"), true)
+}
+
+func TestAdaptorAnalogReadMapping(t *testing.T) {
+	a, board := initTestCapabilitiesAdaptor()
+	// a Mega has its analog pins from 54
+	board.pins = append(board.pins, make([]client.Pin, 40)...)
+	board.pins[16].AnalogChannel = 127
+	board.pins[56].SupportedModes = []int{client.Analog}
+	board.pins[56].AnalogChannel = 2
+	board.pins[56].Mode = client.Analog
+	board.pins[56].Value = 512
+
+	val, err := a.AnalogRead("2")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 512)
+}
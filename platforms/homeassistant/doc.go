@@ -0,0 +1,40 @@
+/*
+Package homeassistant contains the Gobot adaptor and driver announcing Gobot devices to Home Assistant, using MQTT discovery.
+
+Installing:
+
+	go get gobot.io/x/gobot/platforms/homeassistant
+
+Example:
+
+	package main
+
+	import (
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/drivers/gpio"
+		"gobot.io/x/gobot/drivers/i2c"
+		"gobot.io/x/gobot/platforms/homeassistant"
+		"gobot.io/x/gobot/platforms/raspi"
+	)
+
+	func main() {
+		r := raspi.NewAdaptor()
+		bme280 := i2c.NewBME280Driver(r)
+		led := gpio.NewLedDriver(r, "7")
+
+		ha := homeassistant.NewAdaptor("tcp://192.168.1.10:1883", "greenhouse")
+		sensors := homeassistant.NewDriver(ha, bme280)
+		light := homeassistant.NewDriver(ha, led)
+
+		robot := gobot.NewRobot("greenhouse",
+			[]gobot.Connection{r, ha},
+			[]gobot.Device{bme280, led, sensors, light},
+		)
+
+		robot.Start()
+	}
+
+For further information refer to homeassistant README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/homeassistant/README.md
+*/
+package homeassistant // import "gobot.io/x/gobot/platforms/homeassistant"
@@ -0,0 +1,174 @@
+package homeassistant
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/platforms/mqtt"
+)
+
+const (
+	// DefaultDiscoveryPrefix is the MQTT discovery prefix Home Assistant
+	// listens to by default
+	DefaultDiscoveryPrefix = "homeassistant"
+
+	// payloads of the availability topic
+	online  = "online"
+	offline = "offline"
+)
+
+// ErrNotConnected is returned when publishing to the broker before the
+// Adaptor is connected.
+var ErrNotConnected = errors.New("homeassistant: not connected to the MQTT broker")
+
+// mqttClient is the MQTT connection of the Adaptor
+type mqttClient interface {
+	Connect() error
+	Finalize() error
+	Publish(topic string, message []byte) bool
+	Subscribe(topic string, f func(payload []byte)) bool
+}
+
+// mqttAdaptorClient is the mqttClient using an mqtt.Adaptor
+type mqttAdaptorClient struct {
+	*mqtt.Adaptor
+}
+
+func (c mqttAdaptorClient) Subscribe(topic string, f func(payload []byte)) bool {
+	return c.On(topic, func(msg mqtt.Message) {
+		f(msg.Payload())
+	})
+}
+
+// Adaptor is the Gobot Adaptor for Home Assistant. It announces the Devices
+// of its Drivers as Home Assistant entities using MQTT discovery.
+type Adaptor struct {
+	name            string
+	nodeID          string
+	discoveryPrefix string
+	client          mqttClient
+	mutex           *sync.Mutex
+	discovery       map[string][]byte
+}
+
+// NewAdaptor returns a new Home Assistant Adaptor given the MQTT broker
+// host, e.g. "tcp://192.168.1.10:1883", and the node ID identifying the robot
+// in Home Assistant.
+func NewAdaptor(host string, nodeID string) *Adaptor {
+	return newAdaptor(mqtt.NewAdaptor(host, nodeID), nodeID)
+}
+
+// NewAdaptorWithAuth returns a new Home Assistant Adaptor given the MQTT
+// broker host, the node ID, and the username and password of the broker.
+func NewAdaptorWithAuth(host, nodeID, username, password string) *Adaptor {
+	return newAdaptor(mqtt.NewAdaptorWithAuth(host, nodeID, username, password), nodeID)
+}
+
+func newAdaptor(m *mqtt.Adaptor, nodeID string) *Adaptor {
+	return &Adaptor{
+		name:            gobot.DefaultName("HomeAssistant"),
+		nodeID:          objectID(nodeID),
+		discoveryPrefix: DefaultDiscoveryPrefix,
+		client:          mqttAdaptorClient{m},
+		mutex:           &sync.Mutex{},
+		discovery:       map[string][]byte{},
+	}
+}
+
+// Name returns the Adaptor name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptor name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// NodeID returns the node ID of the robot in Home Assistant
+func (a *Adaptor) NodeID() string { return a.nodeID }
+
+// DiscoveryPrefix returns the MQTT discovery prefix
+func (a *Adaptor) DiscoveryPrefix() string { return a.discoveryPrefix }
+
+// SetDiscoveryPrefix sets the MQTT discovery prefix, when Home Assistant is
+// not configured with the default one
+func (a *Adaptor) SetDiscoveryPrefix(prefix string) { a.discoveryPrefix = prefix }
+
+// Connect connects to the MQTT broker and marks the robot online. The
+// entities are announced again each time Home Assistant starts.
+func (a *Adaptor) Connect() (err error) {
+	if err = a.client.Connect(); err != nil {
+		return
+	}
+	a.client.Subscribe(a.discoveryPrefix+"/status", func(payload []byte) {
+		if string(payload) == online {
+			a.rediscover()
+		}
+	})
+	return a.publish(a.availabilityTopic(), []byte(online))
+}
+
+// Finalize marks the robot offline and disconnects from the MQTT broker
+func (a *Adaptor) Finalize() (err error) {
+	a.publish(a.availabilityTopic(), []byte(offline))
+	return a.client.Finalize()
+}
+
+// availabilityTopic is the topic telling whether the robot is running
+func (a *Adaptor) availabilityTopic() string {
+	return "gobot/" + a.nodeID + "/availability"
+}
+
+// topic returns the topic of a value of an entity
+func (a *Adaptor) topic(objectID string, value string) string {
+	return "gobot/" + a.nodeID + "/" + objectID + "/" + value
+}
+
+// discover announces an entity to Home Assistant, and remembers it for
+// announcing it again when Home Assistant restarts
+func (a *Adaptor) discover(component string, objectID string, config []byte) error {
+	topic := a.discoveryPrefix + "/" + component + "/" + a.nodeID + "/" + objectID + "/config"
+
+	a.mutex.Lock()
+	a.discovery[topic] = config
+	a.mutex.Unlock()
+
+	return a.publish(topic, config)
+}
+
+func (a *Adaptor) rediscover() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for topic, config := range a.discovery {
+		a.client.Publish(topic, config)
+	}
+}
+
+func (a *Adaptor) publish(topic string, message []byte) error {
+	if !a.client.Publish(topic, message) {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+func (a *Adaptor) subscribe(topic string, f func(payload []byte)) error {
+	if !a.client.Subscribe(topic, f) {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+// objectID returns name with only the characters allowed in a discovery
+// topic, e.g. "Temperature Sensor-1" becomes "temperature_sensor_1"
+func objectID(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,155 @@
+package homeassistant
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+// testClient is an MQTT broker connection recording the messages published,
+// and delivering the messages sent with Send to the subscribers
+type testClient struct {
+	mtx          sync.Mutex
+	connected    bool
+	connectError error
+	published    map[string]string
+	subscribers  map[string]func([]byte)
+}
+
+func newTestClient() *testClient {
+	return &testClient{
+		published:   map[string]string{},
+		subscribers: map[string]func([]byte){},
+	}
+}
+
+func (c *testClient) Connect() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.connectError != nil {
+		return c.connectError
+	}
+	c.connected = true
+	return nil
+}
+
+func (c *testClient) Finalize() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.connected = false
+	return nil
+}
+
+func (c *testClient) Publish(topic string, message []byte) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.connected {
+		return false
+	}
+	c.published[topic] = string(message)
+	return true
+}
+
+func (c *testClient) Subscribe(topic string, f func([]byte)) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.connected {
+		return false
+	}
+	c.subscribers[topic] = f
+	return true
+}
+
+func (c *testClient) Send(topic string, message string) {
+	c.mtx.Lock()
+	f := c.subscribers[topic]
+	c.mtx.Unlock()
+	if f != nil {
+		f([]byte(message))
+	}
+}
+
+func (c *testClient) Published(topic string) string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.published[topic]
+}
+
+func (c *testClient) Clear() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.published = map[string]string{}
+}
+
+func initTestHomeAssistantAdaptor() (*Adaptor, *testClient) {
+	a := NewAdaptor("tcp://localhost:1883", "Garden Bot")
+	c := newTestClient()
+	a.client = c
+	return a, c
+}
+
+func TestHomeAssistantAdaptor(t *testing.T) {
+	a := NewAdaptor("tcp://localhost:1883", "Garden Bot")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "HomeAssistant"), true)
+	gobottest.Assert(t, a.NodeID(), "garden_bot")
+	gobottest.Assert(t, a.DiscoveryPrefix(), DefaultDiscoveryPrefix)
+
+	a.SetName("NewName")
+	gobottest.Assert(t, a.Name(), "NewName")
+	a.SetDiscoveryPrefix("ha")
+	gobottest.Assert(t, a.DiscoveryPrefix(), "ha")
+
+	a = NewAdaptorWithAuth("tcp://localhost:1883", "bot", "user", "pass")
+	gobottest.Assert(t, a.NodeID(), "bot")
+}
+
+func TestHomeAssistantAdaptorConnectAndFinalize(t *testing.T) {
+	a, c := initTestHomeAssistantAdaptor()
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, c.Published("gobot/garden_bot/availability"), "online")
+
+	// the availability is published before disconnecting
+	c.mtx.Lock()
+	connected := c.connected
+	c.mtx.Unlock()
+	gobottest.Assert(t, connected, true)
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, c.Published("gobot/garden_bot/availability"), "offline")
+}
+
+func TestHomeAssistantAdaptorConnectError(t *testing.T) {
+	a, c := initTestHomeAssistantAdaptor()
+	c.connectError = errors.New("connect error")
+	gobottest.Assert(t, a.Connect(), errors.New("connect error"))
+}
+
+func TestHomeAssistantAdaptorNotConnected(t *testing.T) {
+	a, _ := initTestHomeAssistantAdaptor()
+	gobottest.Assert(t, a.publish("topic", []byte("message")), ErrNotConnected)
+	gobottest.Assert(t, a.subscribe("topic", func([]byte) {}), ErrNotConnected)
+}
+
+func TestHomeAssistantAdaptorRediscover(t *testing.T) {
+	a, c := initTestHomeAssistantAdaptor()
+	a.Connect()
+	gobottest.Assert(t, a.discover("sensor", "bme280_temperature", []byte("{}")), nil)
+	topic := "homeassistant/sensor/garden_bot/bme280_temperature/config"
+	gobottest.Assert(t, c.Published(topic), "{}")
+
+	c.Clear()
+	c.Send("homeassistant/status", "offline")
+	gobottest.Assert(t, c.Published(topic), "")
+	c.Send("homeassistant/status", "online")
+	gobottest.Assert(t, c.Published(topic), "{}")
+}
+
+func TestObjectID(t *testing.T) {
+	gobottest.Assert(t, objectID("Temperature Sensor-1"), "temperature_sensor_1")
+	gobottest.Assert(t, objectID("bme280"), "bme280")
+}
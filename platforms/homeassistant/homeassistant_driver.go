@@ -0,0 +1,302 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+const (
+	// Error event, published with the errors reading the Device or running
+	// the commands of Home Assistant
+	Error = "error"
+
+	// payloads of the state and command topics of switches and lights
+	on  = "ON"
+	off = "OFF"
+)
+
+// Switch is implemented by the Devices turned on and off, such as the
+// gpio.RelayDriver. They are announced as Home Assistant switches.
+type Switch interface {
+	On() error
+	Off() error
+	State() bool
+}
+
+// Light is implemented by the Switches with a brightness, such as the
+// gpio.LedDriver. They are announced as Home Assistant lights.
+type Light interface {
+	Switch
+	Brightness(level byte) error
+}
+
+// sensor is a value of a Device announced as a Home Assistant sensor
+type sensor struct {
+	name        string
+	deviceClass string
+	unit        string
+	read        func() (float32, error)
+}
+
+// Driver is the Gobot Driver announcing a Device to Home Assistant. Its
+// temperature, humidity, pressure and distance readings become sensors, and
+// Switches and Lights accept the commands of Home Assistant.
+type Driver struct {
+	name       string
+	connection gobot.Connection
+	device     gobot.Device
+	interval   time.Duration
+	halt       chan bool
+	sensors    []sensor
+	gobot.Eventer
+}
+
+// NewDriver returns a new Driver announcing device to Home Assistant given a
+// Home Assistant Adaptor. The sensors of device are read every 30 seconds.
+//
+// Optionally accepts:
+// 	time.Duration: Interval at which the sensors are read
+func NewDriver(a *Adaptor, device gobot.Device, v ...time.Duration) *Driver {
+	d := &Driver{
+		name:       gobot.DefaultName("HomeAssistant"),
+		connection: a,
+		device:     device,
+		interval:   30 * time.Second,
+		halt:       make(chan bool),
+		Eventer:    gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		d.interval = v[0]
+	}
+
+	if s, ok := device.(gobot.TemperatureSensor); ok {
+		d.sensors = append(d.sensors, sensor{"temperature", "temperature", "°C", s.Temperature})
+	}
+	if s, ok := device.(gobot.HumiditySensor); ok {
+		d.sensors = append(d.sensors, sensor{"humidity", "humidity", "%", s.Humidity})
+	}
+	if s, ok := device.(gobot.PressureSensor); ok {
+		d.sensors = append(d.sensors, sensor{"pressure", "pressure", "Pa", s.Pressure})
+	}
+	if s, ok := device.(gobot.DistanceSensor); ok {
+		d.sensors = append(d.sensors, sensor{"distance", "", "cm", func() (float32, error) {
+			val, err := s.Distance()
+			return float32(val), err
+		}})
+	}
+
+	d.AddEvent(Error)
+
+	return d
+}
+
+// Name returns the Driver name
+func (d *Driver) Name() string { return d.name }
+
+// SetName sets the Driver name
+func (d *Driver) SetName(n string) { d.name = n }
+
+// Connection returns the Driver Connection
+func (d *Driver) Connection() gobot.Connection { return d.connection }
+
+// Device returns the Device announced to Home Assistant
+func (d *Driver) Device() gobot.Device { return d.device }
+
+func (d *Driver) adaptor() *Adaptor {
+	return d.Connection().(*Adaptor)
+}
+
+// Start announces the Device to Home Assistant, subscribes to its commands,
+// and starts publishing the readings of its sensors.
+//
+// Emits the Events:
+// 	Error error - On error reading a sensor or running a command
+func (d *Driver) Start() (err error) {
+	for _, s := range d.sensors {
+		if err = d.discoverSensor(s); err != nil {
+			return
+		}
+	}
+
+	if l, ok := d.device.(Light); ok {
+		err = d.discoverLight(l)
+	} else if s, ok := d.device.(Switch); ok {
+		err = d.discoverSwitch(s)
+	}
+	if err != nil {
+		return
+	}
+
+	if len(d.sensors) > 0 {
+		gobot.NewPoller(gobot.PollerConfig{Interval: d.interval}).Go(d, d.halt, func() bool {
+			d.publishSensors()
+			return false
+		})
+	}
+	return
+}
+
+// Halt stops publishing the readings of the sensors
+func (d *Driver) Halt() (err error) {
+	if len(d.sensors) > 0 {
+		d.halt <- true
+	}
+	return
+}
+
+// objectID returns the ID of an entity of the Device, e.g. the
+// "temperature" sensor of the "BME280" Device is "bme280_temperature"
+func (d *Driver) objectID(entity string) string {
+	if entity == "" {
+		return objectID(d.device.Name())
+	}
+	return objectID(d.device.Name() + "_" + entity)
+}
+
+// config returns the discovery config of an entity, with the fields common
+// to all entities
+func (d *Driver) config(entity string, fields map[string]interface{}) []byte {
+	a := d.adaptor()
+	name := d.device.Name()
+	if entity != "" {
+		name += " " + entity
+	}
+	config := map[string]interface{}{
+		"name":               name,
+		"unique_id":          a.NodeID() + "_" + d.objectID(entity),
+		"availability_topic": a.availabilityTopic(),
+		"device": map[string]interface{}{
+			"identifiers":  []string{a.NodeID()},
+			"name":         a.NodeID(),
+			"manufacturer": "Gobot",
+		},
+	}
+	for k, v := range fields {
+		config[k] = v
+	}
+	buf, _ := json.Marshal(config)
+	return buf
+}
+
+func (d *Driver) discoverSensor(s sensor) error {
+	a := d.adaptor()
+	id := d.objectID(s.name)
+	fields := map[string]interface{}{
+		"state_topic":         a.topic(id, "state"),
+		"unit_of_measurement": s.unit,
+	}
+	if s.deviceClass != "" {
+		fields["device_class"] = s.deviceClass
+	}
+	return a.discover("sensor", id, d.config(s.name, fields))
+}
+
+func (d *Driver) discoverSwitch(s Switch) (err error) {
+	a := d.adaptor()
+	id := d.objectID("")
+	config := d.config("", map[string]interface{}{
+		"state_topic":   a.topic(id, "state"),
+		"command_topic": a.topic(id, "set"),
+	})
+	if err = a.subscribe(a.topic(id, "set"), func(payload []byte) {
+		d.command(s, payload)
+	}); err != nil {
+		return
+	}
+	if err = a.discover("switch", id, config); err != nil {
+		return
+	}
+	return d.publishState(s)
+}
+
+func (d *Driver) discoverLight(l Light) (err error) {
+	a := d.adaptor()
+	id := d.objectID("")
+	config := d.config("", map[string]interface{}{
+		"state_topic":              a.topic(id, "state"),
+		"command_topic":            a.topic(id, "set"),
+		"brightness_state_topic":   a.topic(id, "brightness"),
+		"brightness_command_topic": a.topic(id, "brightness/set"),
+		"brightness_scale":         255,
+	})
+	if err = a.subscribe(a.topic(id, "set"), func(payload []byte) {
+		d.command(l, payload)
+	}); err != nil {
+		return
+	}
+	if err = a.subscribe(a.topic(id, "brightness/set"), func(payload []byte) {
+		d.brightness(l, payload)
+	}); err != nil {
+		return
+	}
+	if err = a.discover("light", id, config); err != nil {
+		return
+	}
+	return d.publishState(l)
+}
+
+// command turns s on or off as requested by Home Assistant
+func (d *Driver) command(s Switch, payload []byte) {
+	var err error
+	switch string(payload) {
+	case on:
+		err = s.On()
+	case off:
+		err = s.Off()
+	default:
+		return
+	}
+	if err != nil {
+		d.Publish(Error, err)
+		return
+	}
+	if err = d.publishState(s); err != nil {
+		d.Publish(Error, err)
+	}
+}
+
+// brightness sets the brightness of l as requested by Home Assistant
+func (d *Driver) brightness(l Light, payload []byte) {
+	level, err := strconv.ParseUint(string(payload), 10, 8)
+	if err != nil {
+		d.Publish(Error, err)
+		return
+	}
+	if err = l.Brightness(byte(level)); err != nil {
+		d.Publish(Error, err)
+		return
+	}
+	a := d.adaptor()
+	id := d.objectID("")
+	if err = a.publish(a.topic(id, "brightness"), payload); err != nil {
+		d.Publish(Error, err)
+	}
+}
+
+func (d *Driver) publishState(s Switch) error {
+	a := d.adaptor()
+	state := off
+	if s.State() {
+		state = on
+	}
+	return a.publish(a.topic(d.objectID(""), "state"), []byte(state))
+}
+
+func (d *Driver) publishSensors() {
+	a := d.adaptor()
+	for _, s := range d.sensors {
+		val, err := s.read()
+		if err != nil {
+			d.Publish(Error, err)
+			continue
+		}
+		state := strconv.FormatFloat(float64(val), 'f', -1, 32)
+		if err = a.publish(a.topic(d.objectID(s.name), "state"), []byte(state)); err != nil {
+			d.Publish(Error, err)
+		}
+	}
+}
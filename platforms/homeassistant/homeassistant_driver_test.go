@@ -0,0 +1,199 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*Driver)(nil)
+
+var _ Light = (*gpio.LedDriver)(nil)
+var _ Switch = (*gpio.RelayDriver)(nil)
+
+// testDevice is a Device with a temperature and a humidity sensor
+type testDevice struct {
+	name        string
+	mtx         sync.Mutex
+	temperature float32
+	err         error
+}
+
+func (d *testDevice) Name() string                 { return d.name }
+func (d *testDevice) SetName(n string)             { d.name = n }
+func (d *testDevice) Start() error                 { return nil }
+func (d *testDevice) Halt() error                  { return nil }
+func (d *testDevice) Connection() gobot.Connection { return nil }
+
+func (d *testDevice) Temperature() (float32, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.temperature, d.err
+}
+
+func (d *testDevice) Humidity() (float32, error) { return 42.5, nil }
+
+// testLight is a Light
+type testLight struct {
+	testDevice
+	state bool
+	level byte
+}
+
+func (l *testLight) On() error                   { l.state = true; return nil }
+func (l *testLight) Off() error                  { l.state = false; return nil }
+func (l *testLight) State() bool                 { return l.state }
+func (l *testLight) Brightness(level byte) error { l.level = level; return nil }
+
+// testSwitch is a Switch failing to turn on
+type testSwitch struct {
+	testDevice
+}
+
+func (s *testSwitch) On() error   { return errors.New("on error") }
+func (s *testSwitch) Off() error  { return nil }
+func (s *testSwitch) State() bool { return false }
+
+func initTestHomeAssistantDriver(device gobot.Device) (*Driver, *testClient) {
+	a, c := initTestHomeAssistantAdaptor()
+	a.Connect()
+	return NewDriver(a, device, 10*time.Millisecond), c
+}
+
+func discoveryConfig(t *testing.T, c *testClient, topic string) map[string]interface{} {
+	var config map[string]interface{}
+	gobottest.Assert(t, json.Unmarshal([]byte(c.Published(topic)), &config), nil)
+	return config
+}
+
+func TestHomeAssistantDriver(t *testing.T) {
+	d, _ := initTestHomeAssistantDriver(&testDevice{name: "BME280"})
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "HomeAssistant"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+	gobottest.Assert(t, d.Device().Name(), "BME280")
+	gobottest.Assert(t, d.Connection().(*Adaptor).NodeID(), "garden_bot")
+}
+
+func TestHomeAssistantDriverSensors(t *testing.T) {
+	device := &testDevice{name: "BME280", temperature: 21.5}
+	d, c := initTestHomeAssistantDriver(device)
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	config := discoveryConfig(t, c, "homeassistant/sensor/garden_bot/bme280_temperature/config")
+	gobottest.Assert(t, config["name"], "BME280 temperature")
+	gobottest.Assert(t, config["unique_id"], "garden_bot_bme280_temperature")
+	gobottest.Assert(t, config["state_topic"], "gobot/garden_bot/bme280_temperature/state")
+	gobottest.Assert(t, config["availability_topic"], "gobot/garden_bot/availability")
+	gobottest.Assert(t, config["device_class"], "temperature")
+	gobottest.Assert(t, config["unit_of_measurement"], "°C")
+
+	config = discoveryConfig(t, c, "homeassistant/sensor/garden_bot/bme280_humidity/config")
+	gobottest.Assert(t, config["unit_of_measurement"], "%")
+
+	time.Sleep(20 * time.Millisecond)
+	gobottest.Assert(t, c.Published("gobot/garden_bot/bme280_temperature/state"), "21.5")
+	gobottest.Assert(t, c.Published("gobot/garden_bot/bme280_humidity/state"), "42.5")
+
+	device.mtx.Lock()
+	device.temperature = 22
+	device.mtx.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	gobottest.Assert(t, c.Published("gobot/garden_bot/bme280_temperature/state"), "22")
+}
+
+func TestHomeAssistantDriverSensorError(t *testing.T) {
+	device := &testDevice{name: "BME280", err: errors.New("read error")}
+	d, _ := initTestHomeAssistantDriver(device)
+	errs := make(chan interface{}, 1)
+	d.On(Error, func(data interface{}) {
+		select {
+		case errs <- data:
+		default:
+		}
+	})
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	select {
+	case err := <-errs:
+		gobottest.Assert(t, err, errors.New("read error"))
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("read error was not published")
+	}
+}
+
+func TestHomeAssistantDriverLight(t *testing.T) {
+	light := &testLight{testDevice: testDevice{name: "Led"}}
+	d, c := initTestHomeAssistantDriver(light)
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	config := discoveryConfig(t, c, "homeassistant/light/garden_bot/led/config")
+	gobottest.Assert(t, config["name"], "Led")
+	gobottest.Assert(t, config["command_topic"], "gobot/garden_bot/led/set")
+	gobottest.Assert(t, config["brightness_command_topic"], "gobot/garden_bot/led/brightness/set")
+	gobottest.Assert(t, c.Published("gobot/garden_bot/led/state"), "OFF")
+
+	c.Send("gobot/garden_bot/led/set", "ON")
+	gobottest.Assert(t, light.state, true)
+	gobottest.Assert(t, c.Published("gobot/garden_bot/led/state"), "ON")
+
+	c.Send("gobot/garden_bot/led/brightness/set", "128")
+	gobottest.Assert(t, light.level, byte(128))
+	gobottest.Assert(t, c.Published("gobot/garden_bot/led/brightness"), "128")
+
+	c.Send("gobot/garden_bot/led/set", "OFF")
+	gobottest.Assert(t, light.state, false)
+	gobottest.Assert(t, c.Published("gobot/garden_bot/led/state"), "OFF")
+}
+
+func TestHomeAssistantDriverLightBrightnessError(t *testing.T) {
+	d, c := initTestHomeAssistantDriver(&testLight{testDevice: testDevice{name: "Led"}})
+	errs := make(chan interface{}, 1)
+	d.On(Error, func(data interface{}) { errs <- data })
+	d.Start()
+	defer d.Halt()
+
+	c.Send("gobot/garden_bot/led/brightness/set", "300")
+	select {
+	case err := <-errs:
+		gobottest.Refute(t, err, nil)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("brightness error was not published")
+	}
+}
+
+func TestHomeAssistantDriverSwitch(t *testing.T) {
+	d, c := initTestHomeAssistantDriver(&testSwitch{testDevice: testDevice{name: "Relay"}})
+	errs := make(chan interface{}, 1)
+	d.On(Error, func(data interface{}) { errs <- data })
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	config := discoveryConfig(t, c, "homeassistant/switch/garden_bot/relay/config")
+	gobottest.Assert(t, config["state_topic"], "gobot/garden_bot/relay/state")
+	gobottest.Assert(t, config["command_topic"], "gobot/garden_bot/relay/set")
+
+	c.Send("gobot/garden_bot/relay/set", "ON")
+	select {
+	case err := <-errs:
+		gobottest.Assert(t, err, errors.New("on error"))
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("command error was not published")
+	}
+}
+
+func TestHomeAssistantDriverStartError(t *testing.T) {
+	a, _ := initTestHomeAssistantAdaptor()
+	d := NewDriver(a, &testDevice{name: "BME280"})
+	gobottest.Assert(t, d.Start(), ErrNotConnected)
+}
@@ -0,0 +1,302 @@
+package iio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/sysfs"
+)
+
+const (
+	// Data event
+	Data = "data"
+	// Error event
+	Error = "error"
+
+	// TimestampChannel is the channel holding the time of the scans
+	TimestampChannel = "timestamp"
+)
+
+// scanTypeRegexp parses the type of a scan element, e.g. "le:s12/16>>4"
+var scanTypeRegexp = regexp.MustCompile(`^(be|le):(s|u)(\d+)/(\d+)(?:X(\d+))?>>(\d+)$`)
+
+// Scan is the data published with the Data event.
+type Scan struct {
+	// Values holds the value of each channel in its unit
+	Values map[string]float64
+	// Timestamp is the time of the scan, given by the device when the
+	// timestamp channel is captured, the time it was read otherwise
+	Timestamp time.Time
+}
+
+// scanElement is a channel captured in the scans
+type scanElement struct {
+	channel   string
+	index     int
+	bigEndian bool
+	signed    bool
+	bits      uint
+	storage   int
+	repeat    int
+	shift     uint
+	scale     float64
+	offset    float64
+	position  int
+}
+
+// BufferDriver captures the channels of an IIO device through the kernel
+// buffer, the scans being started by a trigger, such as the data ready
+// interrupt of the device or a SysfsTrigger.
+type BufferDriver struct {
+	name       string
+	connection *Adaptor
+	channels   []string
+	// Length is the number of scans the kernel buffer holds, 128 by default
+	Length int
+	// Trigger is the name of the trigger starting the scans, such as
+	// "adxl345-dev0" or "sysfstrig0". The current trigger of the device is
+	// kept when empty.
+	Trigger  string
+	elements []scanElement
+	scanSize int
+	file     sysfs.File
+	halt     chan bool
+	mutex    *sync.Mutex
+	gobot.Eventer
+}
+
+// NewBufferDriver returns a new BufferDriver capturing the channels of the
+// device, such as "accel_x", "accel_y" and "timestamp".
+func NewBufferDriver(a *Adaptor, channels ...string) *BufferDriver {
+	b := &BufferDriver{
+		name:       gobot.DefaultName("IIOBuffer"),
+		connection: a,
+		channels:   channels,
+		Length:     128,
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
+	}
+
+	b.AddEvent(Data)
+	b.AddEvent(Error)
+
+	return b
+}
+
+// Name returns the BufferDrivers name
+func (b *BufferDriver) Name() string { return b.name }
+
+// SetName sets the BufferDrivers name
+func (b *BufferDriver) SetName(n string) { b.name = n }
+
+// Connection returns the BufferDrivers Connection
+func (b *BufferDriver) Connection() gobot.Connection { return b.connection }
+
+// Channels returns the channels captured
+func (b *BufferDriver) Channels() []string { return b.channels }
+
+// Start enables the channels and the buffer, then reads the scans.
+//
+// Emits the Events:
+//	Data Scan - On each scan
+//	Error error - On read error
+func (b *BufferDriver) Start() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err = b.enableElements(); err != nil {
+		return
+	}
+	if b.Trigger != "" {
+		if err = b.connection.SetAttribute("trigger/current_trigger", b.Trigger); err != nil {
+			return
+		}
+	}
+	if err = b.connection.SetAttribute("buffer/length", strconv.Itoa(b.Length)); err != nil {
+		return
+	}
+	if err = b.connection.SetAttribute("buffer/enable", "1"); err != nil {
+		return
+	}
+
+	device := path.Join("/dev", path.Base(b.connection.Path()))
+	if b.file, err = sysfs.OpenFile(device, os.O_RDONLY, 0644); err != nil {
+		b.connection.SetAttribute("buffer/enable", "0")
+		return
+	}
+
+	b.halt = make(chan bool)
+	go b.read(b.file, b.halt)
+	return
+}
+
+// Halt disables the buffer and the channels
+func (b *BufferDriver) Halt() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.halt == nil {
+		return
+	}
+
+	close(b.halt)
+	b.halt = nil
+	err = b.connection.SetAttribute("buffer/enable", "0")
+	for _, e := range b.elements {
+		b.connection.SetAttribute(scanElementAttribute(e.channel, "en"), "0")
+	}
+	if cerr := b.file.Close(); err == nil {
+		err = cerr
+	}
+	return
+}
+
+// enableElements enables the channels, and computes the layout of the scans
+func (b *BufferDriver) enableElements() (err error) {
+	b.elements = nil
+	for _, channel := range b.channels {
+		e := scanElement{channel: channel, scale: 1}
+		var index, typ string
+		if err = b.connection.SetAttribute(scanElementAttribute(channel, "en"), "1"); err != nil {
+			return
+		}
+		if index, err = b.connection.Attribute(scanElementAttribute(channel, "index")); err != nil {
+			return
+		}
+		if e.index, err = strconv.Atoi(index); err != nil {
+			return
+		}
+		if typ, err = b.connection.Attribute(scanElementAttribute(channel, "type")); err != nil {
+			return
+		}
+		if err = e.parseType(typ); err != nil {
+			return
+		}
+		if channel != TimestampChannel {
+			if e.scale, e.offset, err = b.connection.calibration(channel); err != nil {
+				return
+			}
+		}
+		b.elements = append(b.elements, e)
+	}
+
+	// the elements are ordered by index, each one aligned on its storage size
+	sort.Slice(b.elements, func(i, j int) bool { return b.elements[i].index < b.elements[j].index })
+	size, align := 0, 1
+	for i := range b.elements {
+		bytes := b.elements[i].storage / 8
+		if size%bytes != 0 {
+			size += bytes - size%bytes
+		}
+		b.elements[i].position = size
+		size += bytes * b.elements[i].repeat
+		if bytes > align {
+			align = bytes
+		}
+	}
+	if size%align != 0 {
+		size += align - size%align
+	}
+	b.scanSize = size
+	return
+}
+
+// read publishes the scans read from the device, until halted
+func (b *BufferDriver) read(file sysfs.File, halt chan bool) {
+	buf := make([]byte, b.scanSize)
+	for {
+		_, err := io.ReadFull(file, buf)
+		select {
+		case <-halt:
+			return
+		default:
+		}
+		if err != nil {
+			b.Publish(Error, err)
+			return
+		}
+		b.Publish(Data, b.decode(buf))
+	}
+}
+
+// decode returns the values of a scan
+func (b *BufferDriver) decode(buf []byte) Scan {
+	scan := Scan{Values: make(map[string]float64, len(b.elements))}
+	for _, e := range b.elements {
+		raw := e.value(buf[e.position : e.position+e.storage/8])
+		if e.channel == TimestampChannel {
+			scan.Timestamp = time.Unix(0, raw)
+			continue
+		}
+		scan.Values[e.channel] = (float64(raw) + e.offset) * e.scale
+	}
+	if scan.Timestamp.IsZero() {
+		scan.Timestamp = gobot.DefaultClock().Now()
+	}
+	return scan
+}
+
+// parseType parses the type of the element, e.g. "le:s12/16>>4" for a signed
+// little endian 12 bit value stored in 16 bits and shifted by 4 bits
+func (e *scanElement) parseType(typ string) error {
+	m := scanTypeRegexp.FindStringSubmatch(typ)
+	if m == nil {
+		return fmt.Errorf("invalid scan element type %q", typ)
+	}
+	bits, _ := strconv.Atoi(m[3])
+	storage, _ := strconv.Atoi(m[4])
+	shift, _ := strconv.Atoi(m[6])
+	if storage != 8 && storage != 16 && storage != 32 && storage != 64 {
+		return fmt.Errorf("invalid scan element type %q", typ)
+	}
+	e.bigEndian = m[1] == "be"
+	e.signed = m[2] == "s"
+	e.bits = uint(bits)
+	e.storage = storage
+	e.shift = uint(shift)
+	e.repeat = 1
+	if m[5] != "" {
+		e.repeat, _ = strconv.Atoi(m[5])
+	}
+	return nil
+}
+
+// value returns the raw value of the element stored in data
+func (e *scanElement) value(data []byte) int64 {
+	var v uint64
+	var order binary.ByteOrder = binary.LittleEndian
+	if e.bigEndian {
+		order = binary.BigEndian
+	}
+	switch len(data) {
+	case 1:
+		v = uint64(data[0])
+	case 2:
+		v = uint64(order.Uint16(data))
+	case 4:
+		v = uint64(order.Uint32(data))
+	case 8:
+		v = order.Uint64(data)
+	}
+
+	v >>= e.shift
+	if e.bits < 64 {
+		v &= 1<<e.bits - 1
+		if e.signed && v&(1<<(e.bits-1)) != 0 {
+			return int64(v) - 1<<e.bits
+		}
+	}
+	return int64(v)
+}
+
+func scanElementAttribute(channel string, attribute string) string {
+	return "scan_elements/in_" + channel + "_" + attribute
+}
@@ -0,0 +1,137 @@
+package iio
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/sysfs"
+)
+
+var _ gobot.Driver = (*BufferDriver)(nil)
+
+func initTestBufferDriver() (*BufferDriver, *iioTestFilesystem) {
+	a, fs := initTestAdaptor()
+	for name, contents := range map[string]string{
+		"scan_elements/in_accel_x_en":      "0",
+		"scan_elements/in_accel_x_index":   "0",
+		"scan_elements/in_accel_x_type":    "le:s13/16>>0",
+		"scan_elements/in_accel_z_en":      "0",
+		"scan_elements/in_accel_z_index":   "1",
+		"scan_elements/in_accel_z_type":    "be:u12/16>>4",
+		"scan_elements/in_timestamp_en":    "0",
+		"scan_elements/in_timestamp_index": "3",
+		"scan_elements/in_timestamp_type":  "le:s64/64>>0",
+		"trigger/current_trigger":          "",
+		"buffer/length":                    "",
+		"buffer/enable":                    "0",
+	} {
+		fs.Add(testDevice + "/" + name).Contents = contents
+	}
+	fs.device = newIioTestDevice()
+	return NewBufferDriver(a, "accel_x", "timestamp", "accel_z"), fs
+}
+
+func TestBufferDriver(t *testing.T) {
+	a := NewAdaptor("adxl345")
+	b := NewBufferDriver(a, "accel_x")
+	gobottest.Assert(t, strings.HasPrefix(b.Name(), "IIOBuffer"), true)
+	b.SetName("accel")
+	gobottest.Assert(t, b.Name(), "accel")
+	gobottest.Assert(t, b.Connection(), a)
+	gobottest.Assert(t, b.Channels(), []string{"accel_x"})
+	gobottest.Assert(t, b.Length, 128)
+	gobottest.Assert(t, b.Halt(), nil)
+}
+
+func TestBufferDriverCapture(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	b, fs := initTestBufferDriver()
+	b.Trigger = "sysfstrig0"
+	b.Length = 64
+	scans := b.Subscribe()
+	gobottest.Assert(t, b.Start(), nil)
+	for _, name := range []string{"in_accel_x_en", "in_accel_z_en", "in_timestamp_en"} {
+		gobottest.Assert(t, fs.Files[testDevice+"/scan_elements/"+name].Contents, "1")
+	}
+	gobottest.Assert(t, fs.Files[testDevice+"/trigger/current_trigger"].Contents, "sysfstrig0")
+	gobottest.Assert(t, fs.Files[testDevice+"/buffer/length"].Contents, "64")
+	gobottest.Assert(t, fs.Files[testDevice+"/buffer/enable"].Contents, "1")
+	gobottest.Assert(t, b.scanSize, 16)
+
+	// accel_x -2, accel_z 250 - 25 shifted by 4 bits, then the timestamp
+	fs.device.scans <- []byte{0xFE, 0x1F, 0x0F, 0xA0, 0, 0, 0, 0, 0x00, 0xCA, 0x9A, 0x3B, 0, 0, 0, 0}
+	evt := <-scans
+	gobottest.Assert(t, evt.Name, Data)
+	scan := evt.Data.(Scan)
+	gobottest.Assert(t, math.Abs(scan.Values["accel_x"]+0.076) < 1e-9, true)
+	gobottest.Assert(t, math.Abs(scan.Values["accel_z"]-9) < 1e-9, true)
+	gobottest.Assert(t, scan.Timestamp, time.Unix(1, 0))
+	_, ok := scan.Values["timestamp"]
+	gobottest.Assert(t, ok, false)
+
+	gobottest.Assert(t, b.Halt(), nil)
+	gobottest.Assert(t, fs.Files[testDevice+"/buffer/enable"].Contents, "0")
+	gobottest.Assert(t, fs.Files[testDevice+"/scan_elements/in_accel_x_en"].Contents, "0")
+	gobottest.Assert(t, b.Halt(), nil)
+}
+
+func TestBufferDriverReadError(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	clock := gobot.NewFakeClock(time.Unix(1488469265, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	b, fs := initTestBufferDriver()
+	b.channels = []string{"accel_x"}
+	events := b.Subscribe()
+	gobottest.Assert(t, b.Start(), nil)
+	gobottest.Assert(t, b.scanSize, 2)
+
+	// the time of the scans without timestamp is the time they are read
+	fs.device.scans <- []byte{0x01, 0x00}
+	scan := (<-events).Data.(Scan)
+	gobottest.Assert(t, scan.Timestamp, clock.Now())
+
+	fs.device.Close()
+	evt := <-events
+	gobottest.Assert(t, evt.Name, Error)
+	gobottest.Assert(t, evt.Data, io.EOF)
+	gobottest.Assert(t, b.Halt(), nil)
+}
+
+func TestBufferDriverStartError(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	b, fs := initTestBufferDriver()
+	fs.Files[testDevice+"/scan_elements/in_accel_z_type"].Contents = "le:s12/12>>0"
+	gobottest.Assert(t, b.Start(), errors.New(`invalid scan element type "le:s12/12>>0"`))
+	fs.Files[testDevice+"/scan_elements/in_accel_z_type"].Contents = "s12"
+	gobottest.Assert(t, b.Start(), errors.New(`invalid scan element type "s12"`))
+	fs.Files[testDevice+"/scan_elements/in_accel_z_index"].Contents = "x"
+	gobottest.Refute(t, b.Start(), nil)
+
+	b = NewBufferDriver(b.connection, "accel_y")
+	gobottest.Refute(t, b.Start(), nil)
+
+	b, fs = initTestBufferDriver()
+	fs.device = nil
+	gobottest.Refute(t, b.Start(), nil)
+	gobottest.Assert(t, fs.Files[testDevice+"/buffer/enable"].Contents, "0")
+}
+
+func TestScanElementValue(t *testing.T) {
+	e := scanElement{}
+	gobottest.Assert(t, e.parseType("le:u8/8>>0"), nil)
+	gobottest.Assert(t, e.value([]byte{0xFF}), int64(255))
+	gobottest.Assert(t, e.parseType("be:s24/32>>8"), nil)
+	gobottest.Assert(t, e.value([]byte{0xFF, 0xFF, 0xFE, 0x00}), int64(-2))
+	gobottest.Assert(t, e.parseType("le:s16/16X2>>0"), nil)
+	gobottest.Assert(t, e.repeat, 2)
+	gobottest.Assert(t, e.parseType("le:u64/64>>0"), nil)
+	gobottest.Assert(t, e.value([]byte{1, 0, 0, 0, 0, 0, 0, 0}), int64(1))
+}
@@ -0,0 +1,44 @@
+/*
+Package iio provides the Gobot adaptor for the Linux Industrial I/O devices,
+such as the accelerometers, ADCs and light sensors already supported by a
+kernel driver, so that they do not need to be driven over i2c again.
+
+Installing:
+
+	go get -d -u gobot.io/x/gobot/... && go get gobot.io/x/gobot/platforms/iio
+
+Example:
+
+	package main
+
+	import (
+		"fmt"
+
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/platforms/iio"
+	)
+
+	func main() {
+		accel := iio.NewAdaptor("adxl345")
+		buffer := iio.NewBufferDriver(accel, "accel_x", "accel_y", "accel_z", "timestamp")
+		buffer.Trigger = "adxl345-dev0"
+
+		work := func() {
+			buffer.On(iio.Data, func(data interface{}) {
+				fmt.Println(data.(iio.Scan).Values)
+			})
+		}
+
+		robot := gobot.NewRobot("iio",
+			[]gobot.Connection{accel},
+			[]gobot.Device{buffer},
+			work,
+		)
+
+		robot.Start()
+	}
+
+For further information refer to iio readme:
+https://github.com/hybridgroup/gobot/blob/master/platforms/iio/README.md
+*/
+package iio // import "gobot.io/x/gobot/platforms/iio"
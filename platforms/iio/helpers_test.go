@@ -0,0 +1,72 @@
+package iio
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"gobot.io/x/gobot/sysfs"
+)
+
+// iioTestFilesystem is a MockFilesystem whose device file is an
+// iioTestDevice
+type iioTestFilesystem struct {
+	*sysfs.MockFilesystem
+	device *iioTestDevice
+}
+
+func (fs *iioTestFilesystem) OpenFile(name string, flag int, perm os.FileMode) (sysfs.File, error) {
+	if name == "/dev/iio:device1" && fs.device != nil {
+		return fs.device, nil
+	}
+	return fs.MockFilesystem.OpenFile(name, flag, perm)
+}
+
+// Stat returns the FileInfo of the working directory for the files and the
+// directories holding them
+func (fs *iioTestFilesystem) Stat(name string) (os.FileInfo, error) {
+	for path := range fs.Files {
+		if path == name || strings.HasPrefix(path, name+"/") {
+			return os.Stat(".")
+		}
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// iioTestDevice returns the scans sent to it, one per Read
+type iioTestDevice struct {
+	sysfs.File
+	scans chan []byte
+	once  sync.Once
+}
+
+func newIioTestDevice() *iioTestDevice {
+	return &iioTestDevice{scans: make(chan []byte, 10)}
+}
+
+func (d *iioTestDevice) Read(b []byte) (int, error) {
+	scan, ok := <-d.scans
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(b, scan), nil
+}
+
+func (d *iioTestDevice) Close() error {
+	d.once.Do(func() { close(d.scans) })
+	return nil
+}
+
+func initTestFilesystem(files map[string]string) *iioTestFilesystem {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	fs := &iioTestFilesystem{MockFilesystem: sysfs.NewMockFilesystem(names)}
+	for name, contents := range files {
+		fs.Files[name].Contents = contents
+	}
+	sysfs.SetFilesystem(fs)
+	return fs
+}
@@ -0,0 +1,173 @@
+package iio
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/sysfs"
+)
+
+// DevicesPath is the directory of the IIO devices in sysfs
+const DevicesPath = "/sys/bus/iio/devices"
+
+// Adaptor is the Gobot Adaptor for a Linux Industrial I/O device, such as an
+// accelerometer, an ADC or a light sensor already supported by a kernel
+// driver. Its channels, e.g. "accel_x" or "voltage0", are read as the analog
+// pins of the aio drivers, or captured with a BufferDriver.
+type Adaptor struct {
+	name   string
+	device string
+	path   string
+}
+
+// NewAdaptor returns a new IIO Adaptor given the name of the device, as
+// found in its name attribute, such as "adxl345", or its directory, such as
+// "iio:device0".
+func NewAdaptor(device string) *Adaptor {
+	return &Adaptor{
+		name:   gobot.DefaultName("IIO"),
+		device: device,
+	}
+}
+
+// Name returns the Adaptors name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptors name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Path returns the sysfs directory of the device, known once connected
+func (a *Adaptor) Path() string { return a.path }
+
+// Connect finds the device in sysfs
+func (a *Adaptor) Connect() (err error) {
+	if strings.HasPrefix(a.device, "iio:device") {
+		dir := path.Join(DevicesPath, a.device)
+		if _, err = sysfs.Stat(dir); err != nil {
+			return fmt.Errorf("iio device %q not found", a.device)
+		}
+		a.path = dir
+		return
+	}
+
+	for i := 0; ; i++ {
+		dir := fmt.Sprintf("%s/iio:device%d", DevicesPath, i)
+		if _, err := sysfs.Stat(dir); err != nil {
+			break
+		}
+		if name, err := readAttribute(dir + "/name"); err == nil && name == a.device {
+			a.path = dir
+			return nil
+		}
+	}
+	return fmt.Errorf("iio device %q not found", a.device)
+}
+
+// Finalize implements the Adaptor interface
+func (a *Adaptor) Finalize() (err error) { return }
+
+// AnalogRead returns the raw value of the channel
+func (a *Adaptor) AnalogRead(channel string) (val int, err error) {
+	raw, err := a.Attribute("in_" + channel + "_raw")
+	if err != nil {
+		return
+	}
+	return strconv.Atoi(raw)
+}
+
+// ReadChannel returns the value of the channel in its unit, e.g. m/s² for an
+// accelerometer or millivolts for an ADC, from its raw value, offset and
+// scale.
+func (a *Adaptor) ReadChannel(channel string) (val float64, err error) {
+	raw, err := a.AnalogRead(channel)
+	if err != nil {
+		return
+	}
+	scale, offset, err := a.calibration(channel)
+	if err != nil {
+		return
+	}
+	return (float64(raw) + offset) * scale, nil
+}
+
+// SetSamplingFrequency sets the sampling frequency of the device, in Hertz
+func (a *Adaptor) SetSamplingFrequency(hz float64) error {
+	return a.SetAttribute("sampling_frequency", strconv.FormatFloat(hz, 'f', -1, 64))
+}
+
+// Attribute returns the value of an attribute of the device, such as
+// "in_accel_x_raw" or "sampling_frequency"
+func (a *Adaptor) Attribute(name string) (string, error) {
+	return readAttribute(path.Join(a.path, name))
+}
+
+// SetAttribute sets the value of an attribute of the device
+func (a *Adaptor) SetAttribute(name string, value string) error {
+	return writeAttribute(path.Join(a.path, name), value)
+}
+
+// calibration returns the scale and offset of the channel, which are
+// either its own or shared by the channels of its type, 1 and 0 when the
+// device has none
+func (a *Adaptor) calibration(channel string) (scale float64, offset float64, err error) {
+	if scale, err = a.channelFloat(channel, "scale", 1); err != nil {
+		return
+	}
+	offset, err = a.channelFloat(channel, "offset", 0)
+	return
+}
+
+// channelFloat reads the attribute of the channel, or the one of its type
+func (a *Adaptor) channelFloat(channel string, attribute string, def float64) (float64, error) {
+	for _, name := range []string{channel, channelType(channel)} {
+		attr := "in_" + name + "_" + attribute
+		if _, err := sysfs.Stat(path.Join(a.path, attr)); err != nil {
+			continue
+		}
+		value, err := a.Attribute(attr)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(value, 64)
+	}
+	return def, nil
+}
+
+// channelType returns the type of the channel, e.g. "accel" for "accel_x"
+// and "voltage" for "voltage0"
+func channelType(channel string) string {
+	if i := strings.Index(channel, "_"); i >= 0 {
+		channel = channel[:i]
+	}
+	return strings.TrimRight(channel, "0123456789")
+}
+
+func readAttribute(name string) (string, error) {
+	f, err := sysfs.OpenFile(name, os.O_RDONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 4096)
+	n, err := f.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf[:n])), nil
+}
+
+func writeAttribute(name string, value string) error {
+	f, err := sysfs.OpenFile(name, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write([]byte(value))
+	return err
+}
@@ -0,0 +1,109 @@
+package iio
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/sysfs"
+)
+
+// make sure that this Adaptor fullfills all the required interfaces
+var _ gobot.Adaptor = (*Adaptor)(nil)
+var _ aio.AnalogReader = (*Adaptor)(nil)
+
+const testDevice = "/sys/bus/iio/devices/iio:device1"
+
+func initTestAdaptor() (*Adaptor, *iioTestFilesystem) {
+	fs := initTestFilesystem(map[string]string{
+		"/sys/bus/iio/devices/iio:device0/name": "ads1015",
+		testDevice + "/name":                    "adxl345\n",
+		testDevice + "/in_accel_x_raw":          "-12\n",
+		testDevice + "/in_accel_scale":          "0.038",
+		testDevice + "/in_accel_z_raw":          "250",
+		testDevice + "/in_accel_z_scale":        "0.04",
+		testDevice + "/in_accel_z_offset":       "-25",
+		testDevice + "/in_temp_raw":             "oops",
+		testDevice + "/sampling_frequency":      "100",
+	})
+	a := NewAdaptor("adxl345")
+	if err := a.Connect(); err != nil {
+		panic(err)
+	}
+	return a, fs
+}
+
+func TestAdaptor(t *testing.T) {
+	a := NewAdaptor("adxl345")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "IIO"), true)
+	a.SetName("accel")
+	gobottest.Assert(t, a.Name(), "accel")
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+
+func TestAdaptorConnect(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	a, _ := initTestAdaptor()
+	gobottest.Assert(t, a.Path(), testDevice)
+
+	a = NewAdaptor("iio:device0")
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.Path(), "/sys/bus/iio/devices/iio:device0")
+
+	a = NewAdaptor("iio:device2")
+	gobottest.Assert(t, a.Connect(), errors.New(`iio device "iio:device2" not found`))
+	a = NewAdaptor("bmp280")
+	gobottest.Assert(t, a.Connect(), errors.New(`iio device "bmp280" not found`))
+}
+
+func TestAdaptorRead(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	a, fs := initTestAdaptor()
+
+	val, err := a.AnalogRead("accel_x")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, -12)
+	_, err = a.AnalogRead("accel_y")
+	gobottest.Refute(t, err, nil)
+	_, err = a.AnalogRead("temp")
+	gobottest.Refute(t, err, nil)
+
+	// the scale of the type, and the scale and offset of the channel
+	f, err := a.ReadChannel("accel_x")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, math.Abs(f+0.456) < 1e-9, true)
+	f, err = a.ReadChannel("accel_z")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, math.Abs(f-9) < 1e-9, true)
+	_, err = a.ReadChannel("accel_y")
+	gobottest.Refute(t, err, nil)
+
+	fs.Files[testDevice+"/in_accel_scale"].Contents = "x"
+	_, err = a.ReadChannel("accel_x")
+	gobottest.Refute(t, err, nil)
+
+	fs.WithReadError = true
+	_, err = a.ReadChannel("accel_x")
+	gobottest.Assert(t, err, errors.New("read error"))
+}
+
+func TestAdaptorAttribute(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	a, fs := initTestAdaptor()
+	val, err := a.Attribute("sampling_frequency")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, "100")
+	gobottest.Assert(t, a.SetSamplingFrequency(12.5), nil)
+	gobottest.Assert(t, fs.Files[testDevice+"/sampling_frequency"].Contents, "12.5")
+	gobottest.Refute(t, a.SetAttribute("missing", "1"), nil)
+}
+
+func TestChannelType(t *testing.T) {
+	gobottest.Assert(t, channelType("accel_x"), "accel")
+	gobottest.Assert(t, channelType("voltage12"), "voltage")
+	gobottest.Assert(t, channelType("illuminance"), "illuminance")
+}
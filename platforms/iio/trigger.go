@@ -0,0 +1,69 @@
+package iio
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	"gobot.io/x/gobot/sysfs"
+)
+
+// SysfsTriggerPath is the directory of the sysfs triggers, provided by the
+// iio-trig-sysfs kernel module
+const SysfsTriggerPath = DevicesPath + "/iio_sysfs_trigger"
+
+// SysfsTrigger is a software trigger starting a scan of the devices using it
+// each time it is fired, e.g. from gobot.Every.
+type SysfsTrigger struct {
+	id   int
+	path string
+}
+
+// NewSysfsTrigger returns a new SysfsTrigger given its id
+func NewSysfsTrigger(id int) *SysfsTrigger {
+	return &SysfsTrigger{id: id}
+}
+
+// Name returns the name of the trigger, which is the Trigger of the
+// BufferDrivers using it
+func (t *SysfsTrigger) Name() string { return "sysfstrig" + strconv.Itoa(t.id) }
+
+// Create creates the trigger, unless it already exists
+func (t *SysfsTrigger) Create() (err error) {
+	if t.path, err = t.find(); err == nil {
+		return
+	}
+	if err = writeAttribute(SysfsTriggerPath+"/add_trigger", strconv.Itoa(t.id)); err != nil {
+		return
+	}
+	t.path, err = t.find()
+	return
+}
+
+// Fire starts a scan of the devices using the trigger
+func (t *SysfsTrigger) Fire() error {
+	if t.path == "" {
+		return fmt.Errorf("iio trigger %s is not created", t.Name())
+	}
+	return writeAttribute(path.Join(t.path, "trigger_now"), "1")
+}
+
+// Remove removes the trigger
+func (t *SysfsTrigger) Remove() error {
+	t.path = ""
+	return writeAttribute(SysfsTriggerPath+"/remove_trigger", strconv.Itoa(t.id))
+}
+
+// find returns the directory of the trigger
+func (t *SysfsTrigger) find() (string, error) {
+	for i := 0; ; i++ {
+		dir := fmt.Sprintf("%s/trigger%d", DevicesPath, i)
+		if _, err := sysfs.Stat(dir); err != nil {
+			break
+		}
+		if name, err := readAttribute(dir + "/name"); err == nil && name == t.Name() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("iio trigger %s not found", t.Name())
+}
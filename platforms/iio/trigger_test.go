@@ -0,0 +1,37 @@
+package iio
+
+import (
+	"errors"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/sysfs"
+)
+
+func TestSysfsTrigger(t *testing.T) {
+	defer sysfs.SetFilesystem(&sysfs.NativeFilesystem{})
+	fs := initTestFilesystem(map[string]string{
+		SysfsTriggerPath + "/add_trigger":           "",
+		SysfsTriggerPath + "/remove_trigger":        "",
+		"/sys/bus/iio/devices/trigger0/name":        "adxl345-dev1",
+		"/sys/bus/iio/devices/trigger1/name":        "sysfstrig3",
+		"/sys/bus/iio/devices/trigger1/trigger_now": "",
+	})
+
+	tr := NewSysfsTrigger(3)
+	gobottest.Assert(t, tr.Name(), "sysfstrig3")
+	gobottest.Assert(t, tr.Fire(), errors.New("iio trigger sysfstrig3 is not created"))
+	gobottest.Assert(t, tr.Create(), nil)
+	gobottest.Assert(t, fs.Files[SysfsTriggerPath+"/add_trigger"].Contents, "")
+	gobottest.Assert(t, tr.Fire(), nil)
+	gobottest.Assert(t, fs.Files["/sys/bus/iio/devices/trigger1/trigger_now"].Contents, "1")
+	gobottest.Assert(t, tr.Remove(), nil)
+	gobottest.Assert(t, fs.Files[SysfsTriggerPath+"/remove_trigger"].Contents, "3")
+
+	// the trigger is not listed after it is added
+	tr = NewSysfsTrigger(4)
+	gobottest.Assert(t, tr.Create(), errors.New("iio trigger sysfstrig4 not found"))
+	gobottest.Assert(t, fs.Files[SysfsTriggerPath+"/add_trigger"].Contents, "4")
+	fs.WithWriteError = true
+	gobottest.Assert(t, tr.Create(), errors.New("write error"))
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
@@ -71,12 +72,18 @@ func (mockFirmataBoard) AnalogWrite(int, int) error      { return nil }
 func (mockFirmataBoard) SetPinMode(int, int) error       { return nil }
 func (mockFirmataBoard) ReportAnalog(int, int) error     { return nil }
 func (mockFirmataBoard) ReportDigital(int, int) error    { return nil }
+func (mockFirmataBoard) SetSamplingInterval(int) error   { return nil }
+func (mockFirmataBoard) SetEventInterval(time.Duration)  {}
 func (mockFirmataBoard) DigitalWrite(int, int) error     { return nil }
 func (mockFirmataBoard) I2cRead(int, int) error          { return nil }
 func (mockFirmataBoard) I2cWrite(int, []byte) error      { return nil }
 func (mockFirmataBoard) I2cConfig(int) error             { return nil }
 func (mockFirmataBoard) ServoConfig(int, int, int) error { return nil }
 func (mockFirmataBoard) WriteSysex(data []byte) error    { return nil }
+func (mockFirmataBoard) SendSysex(byte, []byte) error    { return nil }
+func (mockFirmataBoard) RegisterSysexHandler(byte, client.SysexHandler) error {
+	return nil
+}
 
 func initTestIMUDriver() *IMUDriver {
 	a := firmata.NewAdaptor("/dev/null")
@@ -119,7 +119,7 @@ func (e *Adaptor) Finalize() (err error) {
 		}
 	}
 	if e.i2cBus != nil {
-		if errs := e.i2cBus.Close(); errs != nil {
+		if errs := i2c.CloseBus(e.i2cBus); errs != nil {
 			err = multierror.Append(err, errs)
 		}
 	}
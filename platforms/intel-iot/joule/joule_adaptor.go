@@ -78,7 +78,7 @@ func (e *Adaptor) Finalize() (err error) {
 	}
 	for _, bus := range e.i2cBuses {
 		if bus != nil {
-			if errs := bus.Close(); errs != nil {
+			if errs := i2c.CloseBus(bus); errs != nil {
 				err = multierror.Append(err, errs)
 			}
 		}
@@ -1,7 +1,6 @@
 package keyboard
 
 import (
-	"log"
 	"os"
 
 	"gobot.io/x/gobot"
@@ -15,6 +14,7 @@ const (
 // Driver is gobot software device to the keyboard
 type Driver struct {
 	name    string
+	logger  gobot.Logger
 	connect func(*Driver) (err error)
 	listen  func(*Driver)
 	stdin   *os.File
@@ -44,7 +44,8 @@ func NewDriver() *Driver {
 				if keybuf == ctrlc {
 					proc, err := os.FindProcess(os.Getpid())
 					if err != nil {
-						log.Fatal(err)
+						k.Logger().Error("could not interrupt the process", "error", err)
+						break
 					}
 
 					proc.Signal(os.Interrupt)
@@ -69,6 +70,17 @@ func (k *Driver) Name() string { return k.name }
 // SetName sets the Driver Name
 func (k *Driver) SetName(n string) { k.name = n }
 
+// Logger returns the Driver Logger
+func (k *Driver) Logger() gobot.Logger {
+	if k.logger != nil {
+		return k.logger
+	}
+	return gobot.DefaultLogger().WithComponent(k.name)
+}
+
+// SetLogger sets the Driver Logger
+func (k *Driver) SetLogger(l gobot.Logger) { k.logger = l }
+
 // Connection returns the Driver Connection
 func (k *Driver) Connection() gobot.Connection { return nil }
 
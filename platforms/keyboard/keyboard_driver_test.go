@@ -33,6 +33,14 @@ func TestKeyboardDriverName(t *testing.T) {
 	gobottest.Assert(t, d.Name(), "NewName")
 }
 
+func TestKeyboardDriverLogger(t *testing.T) {
+	d := initTestKeyboardDriver()
+	gobottest.Refute(t, d.Logger(), nil)
+	l := gobot.NewLogger()
+	d.SetLogger(l)
+	gobottest.Assert(t, d.Logger(), l)
+}
+
 func TestKeyboardDriverStart(t *testing.T) {
 	d := initTestKeyboardDriver()
 	gobottest.Assert(t, d.Start(), nil)
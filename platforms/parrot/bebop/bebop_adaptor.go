@@ -33,6 +33,7 @@ type Adaptor struct {
 	name    string
 	drone   drone
 	connect func(*Adaptor) error
+	logger  gobot.Logger
 }
 
 // NewAdaptor returns a new BebopAdaptor
@@ -52,6 +53,23 @@ func (a *Adaptor) Name() string { return a.name }
 // SetName sets the Bebop Adaptors Name
 func (a *Adaptor) SetName(n string) { a.name = n }
 
+// Logger returns the Bebop Adaptors Logger
+func (a *Adaptor) Logger() gobot.Logger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return gobot.DefaultLogger().WithComponent(a.name)
+}
+
+// SetLogger sets the Bebop Adaptors Logger, which is also the one of its
+// client
+func (a *Adaptor) SetLogger(l gobot.Logger) {
+	a.logger = l
+	if d, ok := a.drone.(gobot.Loggable); ok {
+		d.SetLogger(l)
+	}
+}
+
 // Connect establishes a connection to the ardrone
 func (a *Adaptor) Connect() (err error) {
 	err = a.connect(a)
@@ -7,9 +7,11 @@ import (
 
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/platforms/parrot/bebop/client"
 )
 
 var _ gobot.Adaptor = (*Adaptor)(nil)
+var _ gobot.Loggable = (*Adaptor)(nil)
 
 func initTestBebopAdaptor() *Adaptor {
 	a := NewAdaptor()
@@ -27,6 +29,15 @@ func TestBebopAdaptorName(t *testing.T) {
 	gobottest.Assert(t, a.Name(), "NewName")
 }
 
+func TestBebopAdaptorLogger(t *testing.T) {
+	a := NewAdaptor()
+	gobottest.Refute(t, a.Logger(), nil)
+	l := gobot.NewLogger()
+	a.SetLogger(l)
+	gobottest.Assert(t, a.Logger(), l)
+	gobottest.Assert(t, a.drone.(*client.Bebop).Logger(), l)
+}
+
 func TestBebopAdaptorConnect(t *testing.T) {
 	a := initTestBebopAdaptor()
 	gobottest.Assert(t, a.Connect(), nil)
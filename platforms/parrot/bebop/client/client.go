@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"time"
+
+	"gobot.io/x/gobot"
 )
 
 func validatePitch(val int) int {
@@ -170,6 +172,7 @@ type Bebop struct {
 	networkFrameGenerator func(*bytes.Buffer, byte, byte) *bytes.Buffer
 	video                 chan []byte
 	writeChan             chan []byte
+	logger                gobot.Logger
 }
 
 func New() *Bebop {
@@ -196,6 +199,18 @@ func New() *Bebop {
 	}
 }
 
+// Logger returns the Logger of the client, the DefaultLogger unless set with
+// SetLogger
+func (b *Bebop) Logger() gobot.Logger {
+	if b.logger != nil {
+		return b.logger
+	}
+	return gobot.DefaultLogger()
+}
+
+// SetLogger sets the Logger of the client
+func (b *Bebop) SetLogger(l gobot.Logger) { b.logger = l }
+
 func (b *Bebop) write(buf []byte) (int, error) {
 	b.writeChan <- buf
 	return 0, nil
@@ -274,7 +289,7 @@ func (b *Bebop) Connect() error {
 			_, err := b.c2dClient.Write(<-b.writeChan)
 
 			if err != nil {
-				fmt.Println(err)
+				b.Logger().Error("c2dClient write error", "error", err)
 			}
 		}
 	}()
@@ -284,7 +299,7 @@ func (b *Bebop) Connect() error {
 			data := make([]byte, 40960)
 			i, _, err := b.d2cClient.ReadFromUDP(data)
 			if err != nil {
-				fmt.Println("d2cClient error:", err)
+				b.Logger().Error("d2cClient read error", "error", err)
 			}
 
 			b.packetReceiver(data[0:i])
@@ -298,7 +313,7 @@ func (b *Bebop) Connect() error {
 		for {
 			_, err := b.write(b.generatePcmd().Bytes())
 			if err != nil {
-				fmt.Println("pcmd c2dClient.Write", err)
+				b.Logger().Error("pcmd write error", "error", err)
 			}
 			time.Sleep(25 * time.Millisecond)
 		}
@@ -534,7 +549,7 @@ func (b *Bebop) packetReceiver(buf []byte) {
 		_, err := b.write(ack)
 
 		if err != nil {
-			fmt.Println("ARNETWORKAL_FRAME_TYPE_DATA_WITH_ACK", err)
+			b.Logger().Error("ARNETWORKAL_FRAME_TYPE_DATA_WITH_ACK write error", "error", err)
 		}
 	}
 
@@ -546,7 +561,7 @@ func (b *Bebop) packetReceiver(buf []byte) {
 		ack := b.createARStreamACK(arstreamFrame).Bytes()
 		_, err := b.write(ack)
 		if err != nil {
-			fmt.Println("ARNETWORKAL_FRAME_TYPE_DATA_LOW_LATENCY", err)
+			b.Logger().Error("ARNETWORKAL_FRAME_TYPE_DATA_LOW_LATENCY write error", "error", err)
 		}
 	}
 
@@ -557,7 +572,7 @@ func (b *Bebop) packetReceiver(buf []byte) {
 		pong := b.createPong(frame).Bytes()
 		_, err := b.write(pong)
 		if err != nil {
-			fmt.Println("ARNETWORK_MANAGER_INTERNAL_BUFFER_ID_PING", err)
+			b.Logger().Error("ARNETWORK_MANAGER_INTERNAL_BUFFER_ID_PING write error", "error", err)
 		}
 	}
 }
@@ -3,7 +3,6 @@ package minidrone
 import (
 	"bytes"
 	"encoding/binary"
-	"fmt"
 	"sync"
 	"time"
 
@@ -20,6 +19,7 @@ type Driver struct {
 	pcmdMutex  sync.Mutex
 	flying     bool
 	Pcmd       Pcmd
+	logger     gobot.Logger
 	gobot.Eventer
 }
 
@@ -145,6 +145,17 @@ func (b *Driver) Name() string { return b.name }
 // SetName sets the Driver Name
 func (b *Driver) SetName(n string) { b.name = n }
 
+// Logger returns the Driver Logger
+func (b *Driver) Logger() gobot.Logger {
+	if b.logger != nil {
+		return b.logger
+	}
+	return gobot.DefaultLogger().WithComponent(b.name)
+}
+
+// SetLogger sets the Driver Logger
+func (b *Driver) SetLogger(l gobot.Logger) { b.logger = l }
+
 // adaptor returns BLE adaptor
 func (b *Driver) adaptor() ble.BLEConnector {
 	return b.Connection().(ble.BLEConnector)
@@ -248,7 +259,7 @@ func (b *Driver) StartPcmd() {
 		for {
 			err := b.adaptor().WriteCharacteristic(pcmdCharacteristic, b.generatePcmd().Bytes())
 			if err != nil {
-				fmt.Println("pcmd write error:", err)
+				b.Logger().Error("pcmd write error", "error", err)
 			}
 			time.Sleep(50 * time.Millisecond)
 		}
@@ -9,6 +9,7 @@ import (
 )
 
 var _ gobot.Driver = (*Driver)(nil)
+var _ gobot.Loggable = (*Driver)(nil)
 
 func initTestMinidroneDriver() *Driver {
 	d := NewDriver(NewBleTestAdaptor())
@@ -22,6 +23,14 @@ func TestMinidroneDriver(t *testing.T) {
 	gobottest.Assert(t, d.Name(), "NewName")
 }
 
+func TestMinidroneDriverLogger(t *testing.T) {
+	d := initTestMinidroneDriver()
+	gobottest.Refute(t, d.Logger(), nil)
+	l := gobot.NewLogger()
+	d.SetLogger(l)
+	gobottest.Assert(t, d.Logger(), l)
+}
+
 func TestMinidroneDriverStartAndHalt(t *testing.T) {
 	d := initTestMinidroneDriver()
 	gobottest.Assert(t, d.Start(), nil)
@@ -0,0 +1,24 @@
+package raspi
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mapGPIOMem maps the 4KB of GPIO registers exposed by /dev/gpiomem, which
+// unlike /dev/mem does not require root
+func mapGPIOMem() ([]uint32, func() error, error) {
+	f, err := os.OpenFile("/dev/gpiomem", os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	mem, err := syscall.Mmap(int(f.Fd()), 0, 4096, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	regs := (*[1024]uint32)(unsafe.Pointer(&mem[0]))[:]
+	return regs, func() error { return syscall.Munmap(mem) }, nil
+}
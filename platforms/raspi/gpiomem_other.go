@@ -0,0 +1,9 @@
+// +build !linux
+
+package raspi
+
+import "errors"
+
+func mapGPIOMem() ([]uint32, func() error, error) {
+	return nil, nil, errors.New("/dev/gpiomem is only available on Linux")
+}
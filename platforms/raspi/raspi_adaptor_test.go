@@ -21,6 +21,7 @@ import (
 var _ gobot.Adaptor = (*Adaptor)(nil)
 var _ gpio.DigitalReader = (*Adaptor)(nil)
 var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ gpio.DigitalPinsWriter = (*Adaptor)(nil)
 var _ gpio.PwmWriter = (*Adaptor)(nil)
 var _ gpio.ServoWriter = (*Adaptor)(nil)
 var _ sysfs.DigitalPinnerProvider = (*Adaptor)(nil)
@@ -161,6 +162,37 @@ func TestAdaptorDigitalIO(t *testing.T) {
 	gobottest.Assert(t, err, errors.New("write error"))
 }
 
+func TestAdaptorDigitalWritePins(t *testing.T) {
+	a := initTestAdaptor()
+	fs := sysfs.NewMockFilesystem([]string{
+		"/sys/class/gpio/export",
+		"/sys/class/gpio/unexport",
+		"/sys/class/gpio/gpio4/value",
+		"/sys/class/gpio/gpio4/direction",
+		"/sys/class/gpio/gpio17/value",
+		"/sys/class/gpio/gpio17/direction",
+	})
+	sysfs.SetFilesystem(fs)
+
+	gobottest.Assert(t, a.DigitalWritePins(map[string]byte{"7": 1, "11": 1}), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/value"].Contents, "1")
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio17/value"].Contents, "1")
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/direction"].Contents, "out")
+
+	// the direction of the outputs is not written again
+	fs.Files["/sys/class/gpio/gpio4/direction"].Contents = ""
+	gobottest.Assert(t, a.DigitalWritePins(map[string]byte{"7": 0}), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/value"].Contents, "0")
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/direction"].Contents, "")
+
+	// unless the pin has been read meanwhile
+	a.DigitalRead("7")
+	gobottest.Assert(t, a.DigitalWritePins(map[string]byte{"7": 1}), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/direction"].Contents, "out")
+
+	gobottest.Assert(t, a.DigitalWritePins(map[string]byte{"notexist": 1}), errors.New("Not a valid pin"))
+}
+
 func TestAdaptorI2c(t *testing.T) {
 	a := initTestAdaptor()
 	fs := sysfs.NewMockFilesystem([]string{
@@ -249,3 +281,65 @@ func TestAdaptorPWMPin(t *testing.T) {
 	gobottest.Assert(t, len(a.pwmPins), 2)
 	gobottest.Refute(t, firstSysPin, otherSysPin)
 }
+
+func initTestGPIOMemAdaptor() (*Adaptor, []uint32) {
+	regs := make([]uint32, 1024)
+	openGPIOMem = func() ([]uint32, func() error, error) {
+		return regs, func() error { return nil }, nil
+	}
+	a := NewAdaptor(WithGPIOMem())
+	a.Connect()
+	return a, regs
+}
+
+func TestAdaptorGPIOMem(t *testing.T) {
+	defer func() { openGPIOMem = mapGPIOMem }()
+	a, regs := initTestGPIOMemAdaptor()
+	gobottest.Assert(t, a.UsingGPIOMem(), true)
+
+	// pin 7 is gpio4, pin 13 gpio27
+	regs[gpioRegFsel0] = 0x7 << 12
+	gobottest.Assert(t, a.DigitalWrite("7", 1), nil)
+	gobottest.Assert(t, regs[gpioRegFsel0], uint32(gpioFselOutput<<12))
+	gobottest.Assert(t, regs[gpioRegSet0], uint32(1<<4))
+
+	gobottest.Assert(t, a.DigitalWritePins(map[string]byte{"7": 0, "13": 1, "11": 1}), nil)
+	gobottest.Assert(t, regs[gpioRegSet0], uint32(1<<27|1<<17))
+	gobottest.Assert(t, regs[gpioRegClr0], uint32(1<<4))
+	gobottest.Assert(t, regs[gpioRegFsel0+2], uint32(gpioFselOutput<<21))
+
+	regs[gpioRegLev0] = 1 << 27
+	val, err := a.DigitalRead("13")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+	gobottest.Assert(t, regs[gpioRegFsel0+2], uint32(gpioFselInput))
+	val, _ = a.DigitalRead("7")
+	gobottest.Assert(t, val, 0)
+
+	gobottest.Assert(t, a.DigitalWrite("notexist", 1), errors.New("Not a valid pin"))
+	_, err = a.DigitalRead("notexist")
+	gobottest.Assert(t, err, errors.New("Not a valid pin"))
+
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, a.UsingGPIOMem(), false)
+}
+
+func TestAdaptorGPIOMemFallback(t *testing.T) {
+	defer func() { openGPIOMem = mapGPIOMem }()
+	openGPIOMem = func() ([]uint32, func() error, error) {
+		return nil, nil, errors.New("open /dev/gpiomem: no such file or directory")
+	}
+	a := NewAdaptor(WithGPIOMem())
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.UsingGPIOMem(), false)
+
+	fs := sysfs.NewMockFilesystem([]string{
+		"/sys/class/gpio/export",
+		"/sys/class/gpio/unexport",
+		"/sys/class/gpio/gpio4/value",
+		"/sys/class/gpio/gpio4/direction",
+	})
+	sysfs.SetFilesystem(fs)
+	gobottest.Assert(t, a.DigitalWrite("7", 1), nil)
+	gobottest.Assert(t, fs.Files["/sys/class/gpio/gpio4/value"].Contents, "1")
+}
@@ -0,0 +1,52 @@
+/*
+Package ros provides the Gobot adaptor and driver bridging Gobot and ROS.
+
+The adaptor connects to a rosbridge server with the rosbridge websocket
+protocol, so neither CGo nor a ROS client library is needed. The driver
+publishes Gobot events to ROS topics, and runs Gobot commands with the
+messages received on ROS topics.
+
+Installing:
+
+* First install and run the rosbridge server on the ROS machine:
+
+	ros2 launch rosbridge_server rosbridge_websocket_launch.xml
+
+* Then install the package:
+
+	go get gobot.io/x/gobot/platforms/ros
+
+Example:
+
+	package main
+
+	import (
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/drivers/aio"
+		"gobot.io/x/gobot/drivers/gpio"
+		"gobot.io/x/gobot/platforms/firmata"
+		"gobot.io/x/gobot/platforms/ros"
+	)
+
+	func main() {
+		firmataAdaptor := firmata.NewAdaptor("/dev/ttyACM0")
+		sensor := aio.NewAnalogSensorDriver(firmataAdaptor, "0")
+		led := gpio.NewLedDriver(firmataAdaptor, "13")
+
+		rosAdaptor := ros.NewAdaptor("192.168.1.10:9090")
+		bridge := ros.NewDriver(rosAdaptor)
+		bridge.PublishEvent(sensor, aio.Data, "/light", "std_msgs/Int32")
+		bridge.SubscribeCommand("/led/toggle", "std_msgs/Empty", led, "Toggle")
+
+		robot := gobot.NewRobot("rosBot",
+			[]gobot.Connection{firmataAdaptor, rosAdaptor},
+			[]gobot.Device{sensor, led, bridge},
+		)
+
+		robot.Start()
+	}
+
+For more information refer to the ros README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/ros/README.md
+*/
+package ros // import "gobot.io/x/gobot/platforms/ros"
@@ -0,0 +1,163 @@
+package ros
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"gobot.io/x/gobot"
+
+	"golang.org/x/net/websocket"
+)
+
+// ErrNotConnected is returned when using the Adaptor before connecting it.
+var ErrNotConnected = errors.New("ros: not connected to rosbridge")
+
+// message is a message of the rosbridge protocol
+type message struct {
+	Op    string          `json:"op"`
+	Topic string          `json:"topic,omitempty"`
+	Type  string          `json:"type,omitempty"`
+	Msg   json.RawMessage `json:"msg,omitempty"`
+}
+
+// Adaptor is the Gobot Adaptor for ROS, connected to a rosbridge server with
+// the rosbridge websocket protocol, so no ROS client library is needed.
+type Adaptor struct {
+	name        string
+	host        string
+	ws          io.ReadWriteCloser
+	connect     func(string) (io.ReadWriteCloser, error)
+	mutex       *sync.Mutex
+	subscribers map[string][]func(json.RawMessage)
+}
+
+// NewAdaptor returns a new ROS Adaptor given the host and port of the
+// rosbridge server, e.g. "localhost:9090"
+func NewAdaptor(host string) *Adaptor {
+	return &Adaptor{
+		name: gobot.DefaultName("ROS"),
+		host: host,
+		connect: func(host string) (io.ReadWriteCloser, error) {
+			return websocket.Dial("ws://"+host, "", "http://"+host)
+		},
+		mutex:       &sync.Mutex{},
+		subscribers: map[string][]func(json.RawMessage){},
+	}
+}
+
+// Name returns the Adaptor Name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptor Name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Port returns the host and port of the rosbridge server
+func (a *Adaptor) Port() string { return a.host }
+
+// Connect connects to the rosbridge server and starts receiving the messages
+// of the subscribed topics
+func (a *Adaptor) Connect() (err error) {
+	ws, err := a.connect(a.Port())
+	if err != nil {
+		return
+	}
+
+	a.mutex.Lock()
+	a.ws = ws
+	a.mutex.Unlock()
+
+	go a.receive(ws)
+	return
+}
+
+// Finalize closes the connection to the rosbridge server, which drops the
+// advertisements and subscriptions
+func (a *Adaptor) Finalize() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.ws == nil {
+		return
+	}
+	err = a.ws.Close()
+	a.ws = nil
+	a.subscribers = map[string][]func(json.RawMessage){}
+	return
+}
+
+// Advertise announces that messages of type msgType, e.g. "std_msgs/String",
+// will be published to topic
+func (a *Adaptor) Advertise(topic string, msgType string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.send(message{Op: "advertise", Topic: topic, Type: msgType})
+}
+
+// Publish publishes msg to an advertised topic. msg is encoded as JSON, its
+// fields matching the fields of the ROS message type.
+func (a *Adaptor) Publish(topic string, msg interface{}) error {
+	buf, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.send(message{Op: "publish", Topic: topic, Msg: buf})
+}
+
+// Subscribe calls f with each message of type msgType received on topic,
+// encoded as JSON
+func (a *Adaptor) Subscribe(topic string, msgType string, f func(msg json.RawMessage)) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if len(a.subscribers[topic]) == 0 {
+		if err := a.send(message{Op: "subscribe", Topic: topic, Type: msgType}); err != nil {
+			return err
+		}
+	}
+	a.subscribers[topic] = append(a.subscribers[topic], f)
+	return nil
+}
+
+// send writes m to rosbridge, the mutex being held
+func (a *Adaptor) send(m message) error {
+	if a.ws == nil {
+		return ErrNotConnected
+	}
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	// each write is sent as a websocket frame
+	_, err = a.ws.Write(buf)
+	return err
+}
+
+// receive dispatches the messages received from rosbridge to the
+// subscribers of their topic, until the connection is closed
+func (a *Adaptor) receive(ws io.Reader) {
+	dec := json.NewDecoder(ws)
+	for {
+		var m message
+		if err := dec.Decode(&m); err != nil {
+			return
+		}
+		if m.Op != "publish" {
+			continue
+		}
+
+		a.mutex.Lock()
+		subscribers := a.subscribers[m.Topic]
+		a.mutex.Unlock()
+
+		for _, f := range subscribers {
+			f(m.Msg)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package ros
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+// testServer is a rosbridge server receiving the messages of an Adaptor
+type testServer struct {
+	received chan message
+	w        *io.PipeWriter
+}
+
+// testConn is the Adaptor side of the connection to a testServer
+type testConn struct {
+	io.Reader
+	io.Writer
+	closeError error
+}
+
+func (c *testConn) Close() error { return c.closeError }
+
+// send sends m to the Adaptor
+func (s *testServer) send(m message) {
+	buf, _ := json.Marshal(m)
+	s.w.Write(buf)
+}
+
+// next returns the next message received from the Adaptor
+func (s *testServer) next(t *testing.T) message {
+	select {
+	case m := <-s.received:
+		return m
+	case <-time.After(100 * time.Millisecond):
+		t.Fatalf("no message received")
+		return message{}
+	}
+}
+
+func initTestROSAdaptor() (*Adaptor, *testServer, *testConn) {
+	toAdaptor, fromServer := io.Pipe()
+	fromAdaptor, toServer := io.Pipe()
+	s := &testServer{received: make(chan message, 10), w: fromServer}
+	go func() {
+		dec := json.NewDecoder(fromAdaptor)
+		for {
+			var m message
+			if dec.Decode(&m) != nil {
+				return
+			}
+			s.received <- m
+		}
+	}()
+
+	conn := &testConn{Reader: toAdaptor, Writer: toServer}
+	a := NewAdaptor("localhost:9090")
+	a.connect = func(host string) (io.ReadWriteCloser, error) {
+		return conn, nil
+	}
+	return a, s, conn
+}
+
+func TestROSAdaptor(t *testing.T) {
+	a := NewAdaptor("localhost:9090")
+	gobottest.Assert(t, a.Port(), "localhost:9090")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "ROS"), true)
+	a.SetName("NewName")
+	gobottest.Assert(t, a.Name(), "NewName")
+}
+
+func TestROSAdaptorConnect(t *testing.T) {
+	a, _, _ := initTestROSAdaptor()
+	gobottest.Assert(t, a.Connect(), nil)
+
+	a.connect = func(host string) (io.ReadWriteCloser, error) {
+		return nil, errors.New("connection error")
+	}
+	gobottest.Assert(t, a.Connect(), errors.New("connection error"))
+}
+
+func TestROSAdaptorFinalize(t *testing.T) {
+	a, _, conn := initTestROSAdaptor()
+	gobottest.Assert(t, a.Finalize(), nil)
+
+	a.Connect()
+	conn.closeError = errors.New("close error")
+	gobottest.Assert(t, a.Finalize(), errors.New("close error"))
+	gobottest.Assert(t, a.Advertise("/chatter", "std_msgs/String"), ErrNotConnected)
+}
+
+func TestROSAdaptorNotConnected(t *testing.T) {
+	a, _, _ := initTestROSAdaptor()
+	gobottest.Assert(t, a.Advertise("/chatter", "std_msgs/String"), ErrNotConnected)
+	gobottest.Assert(t, a.Publish("/chatter", "hello"), ErrNotConnected)
+	gobottest.Assert(t, a.Subscribe("/chatter", "std_msgs/String", func(json.RawMessage) {}), ErrNotConnected)
+}
+
+func TestROSAdaptorAdvertiseAndPublish(t *testing.T) {
+	a, s, _ := initTestROSAdaptor()
+	a.Connect()
+
+	gobottest.Assert(t, a.Advertise("/chatter", "std_msgs/String"), nil)
+	gobottest.Assert(t, s.next(t), message{Op: "advertise", Topic: "/chatter", Type: "std_msgs/String"})
+
+	gobottest.Assert(t, a.Publish("/chatter", map[string]string{"data": "hello"}), nil)
+	gobottest.Assert(t, s.next(t), message{Op: "publish", Topic: "/chatter", Msg: json.RawMessage(`{"data":"hello"}`)})
+}
+
+func TestROSAdaptorPublishError(t *testing.T) {
+	a, _, _ := initTestROSAdaptor()
+	a.Connect()
+	_, err := json.Marshal(make(chan int))
+	gobottest.Assert(t, a.Publish("/chatter", make(chan int)), err)
+}
+
+func TestROSAdaptorSubscribe(t *testing.T) {
+	a, s, _ := initTestROSAdaptor()
+	a.Connect()
+
+	received := make(chan json.RawMessage, 2)
+	for i := 0; i < 2; i++ {
+		gobottest.Assert(t, a.Subscribe("/cmd", "std_msgs/String", func(msg json.RawMessage) {
+			received <- msg
+		}), nil)
+	}
+	// rosbridge is asked once for the topic
+	gobottest.Assert(t, s.next(t), message{Op: "subscribe", Topic: "/cmd", Type: "std_msgs/String"})
+
+	s.send(message{Op: "status", Msg: json.RawMessage(`{}`)})
+	s.send(message{Op: "publish", Topic: "/other", Msg: json.RawMessage(`{"data":"other"}`)})
+	s.send(message{Op: "publish", Topic: "/cmd", Msg: json.RawMessage(`{"data":"go"}`)})
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-received:
+			gobottest.Assert(t, msg, json.RawMessage(`{"data":"go"}`))
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("message not received")
+		}
+	}
+	select {
+	case m := <-s.received:
+		t.Errorf("unexpected message %+v", m)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,180 @@
+package ros
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// Error event, published with the errors publishing the events or running
+// the commands
+const Error = "error"
+
+// eventBridge publishes the data of an event to a topic
+type eventBridge struct {
+	source  gobot.Eventer
+	event   string
+	topic   string
+	msgType string
+}
+
+// commandBridge runs a command with the messages of a topic
+type commandBridge struct {
+	topic   string
+	msgType string
+	target  gobot.Commander
+	command string
+}
+
+// Driver is the Gobot Driver bridging Gobot and ROS: it publishes Gobot
+// events to ROS topics, and runs Gobot commands with the messages of ROS
+// topics.
+type Driver struct {
+	name        string
+	connection  gobot.Connection
+	mutex       *sync.Mutex
+	events      []eventBridge
+	commands    []commandBridge
+	unsubscribe []func()
+	gobot.Eventer
+}
+
+// NewDriver returns a new ROS bridge Driver given a ROS Adaptor. The events
+// and commands to bridge are added with PublishEvent and SubscribeCommand
+// before the Driver starts.
+func NewDriver(a *Adaptor) *Driver {
+	d := &Driver{
+		name:       gobot.DefaultName("ROSBridge"),
+		connection: a,
+		mutex:      &sync.Mutex{},
+		Eventer:    gobot.NewEventer(),
+	}
+
+	d.AddEvent(Error)
+
+	return d
+}
+
+// Name returns the Driver Name
+func (d *Driver) Name() string { return d.name }
+
+// SetName sets the Driver Name
+func (d *Driver) SetName(n string) { d.name = n }
+
+// Connection returns the Driver Connection
+func (d *Driver) Connection() gobot.Connection { return d.connection }
+
+func (d *Driver) adaptor() *Adaptor {
+	return d.Connection().(*Adaptor)
+}
+
+// PublishEvent publishes the data of the event of source to topic, as
+// messages of type msgType. The data of the events are sent as the "data"
+// field of the std_msgs types, e.g. "std_msgs/Float64", and as they are
+// for the other types, whose fields they must match once encoded as JSON.
+func (d *Driver) PublishEvent(source gobot.Eventer, event string, topic string, msgType string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.events = append(d.events, eventBridge{source, event, topic, msgType})
+}
+
+// SubscribeCommand runs the command of target with each message of type
+// msgType received on topic, e.g. "/cmd_vel" with "geometry_msgs/Twist". The
+// fields of the message are the params of the command.
+func (d *Driver) SubscribeCommand(topic string, msgType string, target gobot.Commander, command string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.commands = append(d.commands, commandBridge{topic, msgType, target, command})
+}
+
+// Start advertises the topics of the events and subscribes to the topics of
+// the commands.
+//
+// Emits the Events:
+// 	Error error - On error publishing an event or running a command
+func (d *Driver) Start() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	a := d.adaptor()
+	for _, b := range d.events {
+		if err = a.Advertise(b.topic, b.msgType); err != nil {
+			return
+		}
+		d.forward(b)
+	}
+
+	for _, b := range d.commands {
+		b := b
+		if err = a.Subscribe(b.topic, b.msgType, func(msg json.RawMessage) {
+			d.run(b, msg)
+		}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Halt stops publishing the events
+func (d *Driver) Halt() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, unsubscribe := range d.unsubscribe {
+		unsubscribe()
+	}
+	d.unsubscribe = nil
+	return
+}
+
+// forward publishes the events of b to its topic until the Driver halts
+func (d *Driver) forward(b eventBridge) {
+	events := b.source.SubscribeWith(gobot.SubscribeOptions{
+		Pattern: b.event,
+		Policy:  gobot.DropOldest,
+	})
+	done := make(chan struct{})
+	d.unsubscribe = append(d.unsubscribe, func() {
+		b.source.Unsubscribe(events)
+		close(done)
+	})
+
+	go func() {
+		for {
+			select {
+			case evt := <-events:
+				msg := evt.Data
+				if strings.HasPrefix(b.msgType, "std_msgs/") {
+					msg = map[string]interface{}{"data": evt.Data}
+				}
+				if err := d.adaptor().Publish(b.topic, msg); err != nil {
+					d.Publish(Error, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// run runs the command of b with the fields of msg as params
+func (d *Driver) run(b commandBridge, msg json.RawMessage) {
+	command := b.target.Command(b.command)
+	if command == nil {
+		d.Publish(Error, fmt.Errorf("ros: unknown command %q for topic %s", b.command, b.topic))
+		return
+	}
+	params := map[string]interface{}{}
+	if err := json.Unmarshal(msg, &params); err != nil {
+		d.Publish(Error, err)
+		return
+	}
+	if err, ok := command(params).(error); ok {
+		d.Publish(Error, err)
+	}
+}
@@ -0,0 +1,152 @@
+package ros
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*Driver)(nil)
+
+// testRobot is an Eventer and a Commander
+type testRobot struct {
+	gobot.Eventer
+	gobot.Commander
+}
+
+func newTestRobot() *testRobot {
+	r := &testRobot{
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+	r.AddEvent("temperature")
+	r.AddEvent("pose")
+	return r
+}
+
+func initTestROSDriver() (*Driver, *testServer) {
+	a, s, _ := initTestROSAdaptor()
+	a.Connect()
+	return NewDriver(a), s
+}
+
+func waitForError(t *testing.T, errs chan interface{}) interface{} {
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(100 * time.Millisecond):
+		t.Fatalf("error was not published")
+		return nil
+	}
+}
+
+func TestROSDriver(t *testing.T) {
+	d, _ := initTestROSDriver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "ROSBridge"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+	gobottest.Refute(t, d.Connection(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+}
+
+func TestROSDriverPublishEvent(t *testing.T) {
+	d, s := initTestROSDriver()
+	r := newTestRobot()
+	d.PublishEvent(r, "temperature", "/temperature", "std_msgs/Float64")
+	d.PublishEvent(r, "pose", "/pose", "geometry_msgs/Point")
+	gobottest.Assert(t, d.Start(), nil)
+
+	gobottest.Assert(t, s.next(t), message{Op: "advertise", Topic: "/temperature", Type: "std_msgs/Float64"})
+	gobottest.Assert(t, s.next(t), message{Op: "advertise", Topic: "/pose", Type: "geometry_msgs/Point"})
+
+	r.Publish("temperature", 21.5)
+	gobottest.Assert(t, s.next(t), message{Op: "publish", Topic: "/temperature", Msg: json.RawMessage(`{"data":21.5}`)})
+
+	r.Publish("pose", map[string]float64{"x": 1, "y": 2, "z": 0})
+	gobottest.Assert(t, s.next(t), message{Op: "publish", Topic: "/pose", Msg: json.RawMessage(`{"x":1,"y":2,"z":0}`)})
+
+	gobottest.Assert(t, d.Halt(), nil)
+	r.Publish("temperature", 22.0)
+	select {
+	case m := <-s.received:
+		t.Errorf("unexpected message %+v", m)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestROSDriverPublishEventError(t *testing.T) {
+	d, _ := initTestROSDriver()
+	r := newTestRobot()
+	errs := make(chan interface{}, 1)
+	d.On(Error, func(data interface{}) { errs <- data })
+	d.PublishEvent(r, "pose", "/pose", "geometry_msgs/Point")
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	d.adaptor().Finalize()
+	r.Publish("pose", map[string]float64{"x": 1})
+	gobottest.Assert(t, waitForError(t, errs), ErrNotConnected)
+}
+
+func TestROSDriverSubscribeCommand(t *testing.T) {
+	d, s := initTestROSDriver()
+	r := newTestRobot()
+	params := make(chan map[string]interface{}, 1)
+	r.AddCommand("Drive", func(p map[string]interface{}) interface{} {
+		params <- p
+		return nil
+	})
+	d.SubscribeCommand("/cmd_vel", "geometry_msgs/Twist", r, "Drive")
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	gobottest.Assert(t, s.next(t), message{Op: "subscribe", Topic: "/cmd_vel", Type: "geometry_msgs/Twist"})
+
+	s.send(message{Op: "publish", Topic: "/cmd_vel", Msg: json.RawMessage(`{"linear":{"x":0.5},"angular":{"z":1}}`)})
+	select {
+	case p := <-params:
+		gobottest.Assert(t, p["linear"], map[string]interface{}{"x": 0.5})
+		gobottest.Assert(t, p["angular"], map[string]interface{}{"z": 1.0})
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("command was not run")
+	}
+}
+
+func TestROSDriverSubscribeCommandError(t *testing.T) {
+	d, s := initTestROSDriver()
+	r := newTestRobot()
+	r.AddCommand("Fail", func(map[string]interface{}) interface{} {
+		return errors.New("command error")
+	})
+	errs := make(chan interface{}, 1)
+	d.On(Error, func(data interface{}) { errs <- data })
+	d.SubscribeCommand("/fail", "std_msgs/Empty", r, "Fail")
+	d.SubscribeCommand("/unknown", "std_msgs/Empty", r, "Unknown")
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	s.send(message{Op: "publish", Topic: "/fail", Msg: json.RawMessage(`{}`)})
+	gobottest.Assert(t, waitForError(t, errs), errors.New("command error"))
+
+	s.send(message{Op: "publish", Topic: "/fail", Msg: json.RawMessage(`[1]`)})
+	gobottest.Refute(t, waitForError(t, errs), nil)
+
+	s.send(message{Op: "publish", Topic: "/unknown", Msg: json.RawMessage(`{}`)})
+	gobottest.Assert(t, waitForError(t, errs), errors.New(`ros: unknown command "Unknown" for topic /unknown`))
+}
+
+func TestROSDriverStartError(t *testing.T) {
+	a := NewAdaptor("localhost:9090")
+	d := NewDriver(a)
+	d.PublishEvent(newTestRobot(), "temperature", "/temperature", "std_msgs/Float64")
+	gobottest.Assert(t, d.Start(), ErrNotConnected)
+
+	d = NewDriver(a)
+	d.SubscribeCommand("/cmd_vel", "geometry_msgs/Twist", newTestRobot(), "Drive")
+	gobottest.Assert(t, d.Start(), ErrNotConnected)
+}
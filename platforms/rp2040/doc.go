@@ -0,0 +1,47 @@
+/*
+Package rp2040 provides the Gobot adaptor for a Raspberry Pi Pico, or another
+RP2040 board, used as a cheap real-time I/O co-processor over its USB serial
+port.
+
+The board runs the MicroPython bridge of the firmware directory, and exposes
+its GPIO, PWM, ADC and I2C pins.
+
+Installing:
+
+	go get -d -u gobot.io/x/gobot/... && go get gobot.io/x/gobot/platforms/rp2040
+
+Example:
+
+	package main
+
+	import (
+		"time"
+
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/drivers/gpio"
+		"gobot.io/x/gobot/platforms/rp2040"
+	)
+
+	func main() {
+		picoAdaptor := rp2040.NewAdaptor("/dev/ttyACM0")
+		led := gpio.NewLedDriver(picoAdaptor, "GP25")
+
+		work := func() {
+			gobot.Every(1*time.Second, func() {
+				led.Toggle()
+			})
+		}
+
+		robot := gobot.NewRobot("pico",
+			[]gobot.Connection{picoAdaptor},
+			[]gobot.Device{led},
+			work,
+		)
+
+		robot.Start()
+	}
+
+For further information refer to rp2040 readme:
+https://github.com/hybridgroup/gobot/blob/master/platforms/rp2040/README.md
+*/
+package rp2040 // import "gobot.io/x/gobot/platforms/rp2040"
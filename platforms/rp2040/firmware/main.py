@@ -0,0 +1,81 @@
+# gobot-rp2040 bridge for MicroPython.
+#
+# Copy this file as main.py to a Raspberry Pi Pico, or another RP2040 board,
+# running MicroPython. It answers the requests of the Gobot RP2040 Adaptor
+# sent over the USB serial port, one line at a time:
+#
+#   <id> <command> <args...>  ->  <id> ok <value> | <id> err <message>
+import sys
+import ubinascii
+from machine import ADC, I2C, PWM, Pin
+
+VERSION = "gobot-rp2040 1.0"
+
+pins = {}
+pwms = {}
+buses = {}
+
+
+def pin(n, mode):
+    p = pins.get(n)
+    if p is None or p[1] != mode:
+        if n in pwms:
+            pwms.pop(n).deinit()
+        p = (Pin(n, mode), mode)
+        pins[n] = p
+    return p[0]
+
+
+def pwm(n):
+    p = pwms.get(n)
+    if p is None:
+        pins.pop(n, None)
+        p = PWM(Pin(n))
+        pwms[n] = p
+    return p
+
+
+def bus(n, freq=None):
+    b = buses.get(n)
+    if b is None or freq is not None:
+        b = I2C(n, freq=freq or 400000)
+        buses[n] = b
+    return b
+
+
+def handle(command, args):
+    if command == "version":
+        return VERSION
+    if command == "dw":
+        pin(int(args[0]), Pin.OUT).value(int(args[1]))
+        return ""
+    if command == "dr":
+        return str(pin(int(args[0]), Pin.IN).value())
+    if command == "freq":
+        pwm(int(args[0])).freq(int(args[1]))
+        return ""
+    if command == "pwm":
+        pwm(int(args[0])).duty_u16(int(args[1]))
+        return ""
+    if command == "adc":
+        return str(ADC(int(args[0])).read_u16())
+    if command == "i2cf":
+        bus(int(args[0]), int(args[1]))
+        return ""
+    if command == "i2cw":
+        bus(int(args[0])).writeto(int(args[1]), ubinascii.unhexlify(args[2]))
+        return ""
+    if command == "i2cr":
+        data = bus(int(args[0])).readfrom(int(args[1]), int(args[2]))
+        return ubinascii.hexlify(data).decode()
+    raise ValueError("unknown command " + command)
+
+
+while True:
+    fields = sys.stdin.readline().split()
+    if len(fields) < 2:
+        continue
+    try:
+        print(fields[0], "ok", handle(fields[1], fields[2:]))
+    except Exception as e:
+        print(fields[0], "err", e)
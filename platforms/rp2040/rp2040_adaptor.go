@@ -0,0 +1,327 @@
+package rp2040
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	serial "go.bug.st/serial.v1"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+const (
+	// FirmwareName is the name reported by the firmware the Adaptor speaks to
+	FirmwareName = "gobot-rp2040"
+
+	// DefaultPwmFrequency is the frequency of the PWM pins, in Hertz
+	DefaultPwmFrequency = 1000
+
+	// ServoFrequency is the frequency of the servo pins, in Hertz
+	ServoFrequency = 50
+)
+
+var (
+	// ErrNotConnected is returned when the board is used before Connect
+	ErrNotConnected = errors.New("rp2040 board is not connected")
+	// ErrTimeout is returned when the board did not reply in time
+	ErrTimeout = errors.New("rp2040 board did not reply")
+	// ErrClosed is returned when the connection to the board was closed
+	ErrClosed = errors.New("rp2040 connection closed")
+)
+
+// Adaptor is the Gobot Adaptor for a Raspberry Pi Pico, or another RP2040
+// board, used as an I/O co-processor over its USB serial port. The board
+// runs the MicroPython bridge found in the firmware directory, which answers
+// the requests of the Adaptor one line at a time.
+type Adaptor struct {
+	name       string
+	port       string
+	conn       io.ReadWriteCloser
+	PortOpener func(port string) (io.ReadWriteCloser, error)
+	// Timeout is the time the board has to reply to a request, 1 Second by
+	// default
+	Timeout     time.Duration
+	version     string
+	seq         int
+	replies     chan string
+	frequencies map[int]int
+	mutex       *sync.Mutex
+}
+
+// NewAdaptor returns a new RP2040 Adaptor which optionally accepts:
+//
+//	string: port the Adaptor uses to connect to the USB serial port of the board
+//	io.ReadWriteCloser: connection the Adaptor uses to communicate with the board
+//
+// If an io.ReadWriteCloser is supplied, the string port is only used as a
+// label to be displayed in the log and api.
+func NewAdaptor(args ...interface{}) *Adaptor {
+	a := &Adaptor{
+		name: gobot.DefaultName("RP2040"),
+		PortOpener: func(port string) (io.ReadWriteCloser, error) {
+			return serial.Open(port, &serial.Mode{BaudRate: 115200})
+		},
+		Timeout:     time.Second,
+		frequencies: make(map[int]int),
+		mutex:       &sync.Mutex{},
+	}
+
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			a.port = arg
+		case io.ReadWriteCloser:
+			a.conn = arg
+		}
+	}
+
+	return a
+}
+
+// Name returns the Adaptors name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptors name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Port returns the Adaptors port
+func (a *Adaptor) Port() string { return a.port }
+
+// Version returns the version of the firmware, known once connected
+func (a *Adaptor) Version() string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.version
+}
+
+// Connect opens the connection to the board, and checks that it runs the
+// gobot-rp2040 firmware.
+func (a *Adaptor) Connect() (err error) {
+	a.mutex.Lock()
+	if a.conn == nil {
+		if a.conn, err = a.PortOpener(a.port); err != nil {
+			a.mutex.Unlock()
+			return
+		}
+	}
+	a.replies = make(chan string, 16)
+	a.frequencies = make(map[int]int)
+	go a.read(a.conn, a.replies)
+	a.mutex.Unlock()
+
+	version, err := a.request("version")
+	if err != nil {
+		return
+	}
+	if !strings.HasPrefix(version, FirmwareName+" ") {
+		return fmt.Errorf("unknown rp2040 firmware %q", version)
+	}
+
+	a.mutex.Lock()
+	a.version = strings.TrimPrefix(version, FirmwareName+" ")
+	a.mutex.Unlock()
+	return
+}
+
+// Finalize closes the connection to the board
+func (a *Adaptor) Finalize() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.replies == nil {
+		return
+	}
+	a.replies = nil
+	return a.conn.Close()
+}
+
+// DigitalWrite writes a value to the pin. Acceptable values are 1 or 0.
+func (a *Adaptor) DigitalWrite(pin string, level byte) (err error) {
+	p, err := a.pin(pin)
+	if err != nil {
+		return
+	}
+	_, err = a.request("dw", p, level&1)
+	return
+}
+
+// DigitalRead reads the value of the pin, configured as an input
+func (a *Adaptor) DigitalRead(pin string) (val int, err error) {
+	p, err := a.pin(pin)
+	if err != nil {
+		return
+	}
+	reply, err := a.request("dr", p)
+	if err != nil {
+		return
+	}
+	return strconv.Atoi(reply)
+}
+
+// PwmWrite writes the 0-255 duty cycle to the pin
+func (a *Adaptor) PwmWrite(pin string, level byte) (err error) {
+	p, err := a.pin(pin)
+	if err != nil {
+		return
+	}
+	return a.pwm(p, DefaultPwmFrequency, uint32(level)*0xFFFF/0xFF)
+}
+
+// ServoWrite writes the 0-180 degree angle to the pin, as a pulse of 0.5
+// to 2.5 Milliseconds
+func (a *Adaptor) ServoWrite(pin string, angle byte) (err error) {
+	p, err := a.pin(pin)
+	if err != nil {
+		return
+	}
+	if angle > 180 {
+		angle = 180
+	}
+	pulse := 500 + uint32(angle)*2000/180
+	return a.pwm(p, ServoFrequency, pulse*0xFFFF/(1000000/ServoFrequency))
+}
+
+// AnalogRead reads the 12 bit value of an ADC channel, given either its
+// name, A0 to A3 for the pins GP26 to GP29 and A4 for the temperature
+// sensor, or its pin.
+func (a *Adaptor) AnalogRead(pin string) (val int, err error) {
+	channel, err := a.adcChannel(pin)
+	if err != nil {
+		return
+	}
+	reply, err := a.request("adc", channel)
+	if err != nil {
+		return
+	}
+	if val, err = strconv.Atoi(reply); err != nil {
+		return
+	}
+	return val >> 4, nil
+}
+
+// GetConnection returns an i2c connection to a device on a specified bus.
+// The board has the buses 0 and 1.
+func (a *Adaptor) GetConnection(address int, bus int) (connection i2c.Connection, err error) {
+	if bus < 0 || bus > 1 {
+		return nil, fmt.Errorf("Invalid bus number %d, only 0 and 1 are supported", bus)
+	}
+	return i2c.NewConnection(&i2cBus{adaptor: a, bus: bus}, address), nil
+}
+
+// SetBusSpeed sets the clock of the i2c bus, 400kHz by default, in Hertz
+func (a *Adaptor) SetBusSpeed(bus int, hz int) (err error) {
+	if bus < 0 || bus > 1 {
+		return fmt.Errorf("Invalid bus number %d, only 0 and 1 are supported", bus)
+	}
+	_, err = a.request("i2cf", bus, hz)
+	return
+}
+
+// GetDefaultBus returns the default i2c bus for this platform
+func (a *Adaptor) GetDefaultBus() int {
+	return 0
+}
+
+// pin returns the GPIO number of pin, such as "15" or "GP15"
+func (a *Adaptor) pin(pin string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(pin), "GP"))
+	if err != nil || p < 0 || p > 29 {
+		return 0, fmt.Errorf("Invalid pin %q", pin)
+	}
+	return p, nil
+}
+
+// adcChannel returns the ADC channel of pin
+func (a *Adaptor) adcChannel(pin string) (int, error) {
+	if strings.HasPrefix(strings.ToUpper(pin), "A") {
+		c, err := strconv.Atoi(pin[1:])
+		if err != nil || c < 0 || c > 4 {
+			return 0, fmt.Errorf("Invalid analog pin %q", pin)
+		}
+		return c, nil
+	}
+	p, err := a.pin(pin)
+	if err != nil || p < 26 {
+		return 0, fmt.Errorf("Invalid analog pin %q", pin)
+	}
+	return p - 26, nil
+}
+
+// pwm writes the 16 bit duty cycle to the pin, setting its frequency first
+// when it changes
+func (a *Adaptor) pwm(pin int, frequency int, duty uint32) (err error) {
+	a.mutex.Lock()
+	current := a.frequencies[pin]
+	a.mutex.Unlock()
+
+	if current != frequency {
+		if _, err = a.request("freq", pin, frequency); err != nil {
+			return
+		}
+		a.mutex.Lock()
+		a.frequencies[pin] = frequency
+		a.mutex.Unlock()
+	}
+	_, err = a.request("pwm", pin, duty)
+	return
+}
+
+// request sends the command and its args to the board, and returns the value
+// of its reply
+func (a *Adaptor) request(command string, args ...interface{}) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.replies == nil {
+		return "", ErrNotConnected
+	}
+
+	a.seq++
+	id := strconv.Itoa(a.seq)
+	line := id + " " + command
+	for _, arg := range args {
+		line += " " + fmt.Sprint(arg)
+	}
+	if _, err := io.WriteString(a.conn, line+"\n"); err != nil {
+		return "", err
+	}
+
+	timeout := gobot.DefaultClock().After(a.Timeout)
+	for {
+		select {
+		case reply, ok := <-a.replies:
+			if !ok {
+				return "", ErrClosed
+			}
+			fields := strings.SplitN(reply, " ", 3)
+			if fields[0] != id || len(fields) < 2 {
+				// the late reply to a request which timed out
+				continue
+			}
+			value := ""
+			if len(fields) > 2 {
+				value = strings.TrimSpace(fields[2])
+			}
+			if fields[1] != "ok" {
+				return "", fmt.Errorf("%s: %s", command, value)
+			}
+			return value, nil
+		case <-timeout:
+			return "", ErrTimeout
+		}
+	}
+}
+
+// read sends the lines read from the board to replies, until the
+// connection is closed
+func (a *Adaptor) read(conn io.Reader, replies chan string) {
+	defer close(replies)
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		replies <- strings.TrimSpace(scanner.Text())
+	}
+}
@@ -0,0 +1,210 @@
+package rp2040
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+)
+
+// make sure that this Adaptor fullfills all the required interfaces
+var _ gobot.Adaptor = (*Adaptor)(nil)
+var _ gpio.DigitalReader = (*Adaptor)(nil)
+var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ aio.AnalogReader = (*Adaptor)(nil)
+var _ gpio.PwmWriter = (*Adaptor)(nil)
+var _ gpio.ServoWriter = (*Adaptor)(nil)
+var _ i2c.Connector = (*Adaptor)(nil)
+var _ i2c.BusSpeedSetter = (*Adaptor)(nil)
+
+// testBoard answers the requests of the Adaptor like the firmware, recording
+// them without their id
+type testBoard struct {
+	conn     net.Conn
+	mtx      sync.Mutex
+	requests []string
+	replies  map[string]string
+}
+
+func newTestBoard() (*testBoard, net.Conn) {
+	client, conn := net.Pipe()
+	b := &testBoard{
+		conn: conn,
+		replies: map[string]string{
+			"version": "ok gobot-rp2040 1.0",
+		},
+	}
+	go b.serve()
+	return b, client
+}
+
+func (b *testBoard) serve() {
+	scanner := bufio.NewScanner(b.conn)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+		b.mtx.Lock()
+		b.requests = append(b.requests, fields[1])
+		reply, ok := b.replies[fields[1]]
+		if !ok {
+			reply, ok = b.replies[strings.Fields(fields[1])[0]]
+		}
+		b.mtx.Unlock()
+		if !ok {
+			reply = "ok"
+		}
+		if reply == "" {
+			// no reply
+			continue
+		}
+		io.WriteString(b.conn, fields[0]+" "+reply+"\r\n")
+	}
+}
+
+func (b *testBoard) reply(request string, reply string) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.replies[request] = reply
+}
+
+func (b *testBoard) Requests() []string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	requests := b.requests
+	b.requests = nil
+	return requests
+}
+
+func initTestAdaptor() (*Adaptor, *testBoard) {
+	b, conn := newTestBoard()
+	a := NewAdaptor(conn)
+	if err := a.Connect(); err != nil {
+		panic(err)
+	}
+	b.Requests()
+	return a, b
+}
+
+func TestAdaptor(t *testing.T) {
+	a := NewAdaptor("/dev/ttyACM0")
+	gobottest.Assert(t, a.Port(), "/dev/ttyACM0")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "RP2040"), true)
+	a.SetName("pico")
+	gobottest.Assert(t, a.Name(), "pico")
+	gobottest.Assert(t, a.Timeout, time.Second)
+	gobottest.Assert(t, a.GetDefaultBus(), 0)
+	gobottest.Assert(t, a.DigitalWrite("1", 1), ErrNotConnected)
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+
+func TestAdaptorConnect(t *testing.T) {
+	a, _ := initTestAdaptor()
+	gobottest.Assert(t, a.Version(), "1.0")
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, a.Finalize(), nil)
+
+	a = NewAdaptor("/dev/null")
+	a.PortOpener = func(port string) (io.ReadWriteCloser, error) {
+		return nil, errors.New("connect error")
+	}
+	gobottest.Assert(t, a.Connect(), errors.New("connect error"))
+
+	b, conn := newTestBoard()
+	b.reply("version", "ok StandardFirmata 2.5")
+	a = NewAdaptor(conn)
+	gobottest.Assert(t, a.Connect(), errors.New(`unknown rp2040 firmware "StandardFirmata 2.5"`))
+}
+
+func TestAdaptorDigital(t *testing.T) {
+	a, b := initTestAdaptor()
+	gobottest.Assert(t, a.DigitalWrite("GP25", 1), nil)
+	gobottest.Assert(t, a.DigitalWrite("3", 0), nil)
+	b.reply("dr", "ok 1")
+	val, err := a.DigitalRead("gp4")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+	gobottest.Assert(t, b.Requests(), []string{"dw 25 1", "dw 3 0", "dr 4"})
+
+	gobottest.Assert(t, a.DigitalWrite("30", 1), errors.New(`Invalid pin "30"`))
+	_, err = a.DigitalRead("xyz")
+	gobottest.Assert(t, err, errors.New(`Invalid pin "xyz"`))
+
+	b.reply("dw", "err invalid pin")
+	gobottest.Assert(t, a.DigitalWrite("1", 1), errors.New("dw: invalid pin"))
+}
+
+func TestAdaptorPwm(t *testing.T) {
+	a, b := initTestAdaptor()
+	gobottest.Assert(t, a.PwmWrite("2", 255), nil)
+	gobottest.Assert(t, a.PwmWrite("2", 0), nil)
+	gobottest.Assert(t, a.ServoWrite("2", 90), nil)
+	gobottest.Assert(t, a.ServoWrite("2", 200), nil)
+	gobottest.Assert(t, b.Requests(), []string{
+		"freq 2 1000", "pwm 2 65535", "pwm 2 0",
+		"freq 2 50", "pwm 2 4915", "pwm 2 8191",
+	})
+
+	b.reply("freq", "err no pwm")
+	gobottest.Assert(t, a.PwmWrite("3", 1), errors.New("freq: no pwm"))
+	gobottest.Refute(t, a.PwmWrite("x", 1), nil)
+	gobottest.Refute(t, a.ServoWrite("x", 1), nil)
+}
+
+func TestAdaptorAnalogRead(t *testing.T) {
+	a, b := initTestAdaptor()
+	b.reply("adc", "ok 65535")
+	val, err := a.AnalogRead("A0")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 4095)
+	_, err = a.AnalogRead("GP27")
+	gobottest.Assert(t, err, nil)
+	_, err = a.AnalogRead("a4")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b.Requests(), []string{"adc 0", "adc 1", "adc 4"})
+
+	for _, pin := range []string{"A5", "Ax", "25", "x"} {
+		_, err = a.AnalogRead(pin)
+		gobottest.Refute(t, err, nil)
+	}
+	b.reply("adc", "ok nan")
+	_, err = a.AnalogRead("A0")
+	gobottest.Refute(t, err, nil)
+}
+
+func TestAdaptorTimeout(t *testing.T) {
+	a, b := initTestAdaptor()
+	a.Timeout = 10 * time.Millisecond
+	b.reply("dw", "")
+	gobottest.Assert(t, a.DigitalWrite("1", 1), ErrTimeout)
+
+	// the late reply of the previous request is skipped
+	io.WriteString(b.conn, "2 ok\n")
+	gobottest.Assert(t, a.DigitalWrite("2", 1), ErrTimeout)
+	b.reply("dw", "ok")
+	gobottest.Assert(t, a.DigitalWrite("3", 1), nil)
+}
+
+// eofConn is a connection closed by the board
+type eofConn struct{}
+
+func (eofConn) Read([]byte) (int, error)    { return 0, io.EOF }
+func (eofConn) Write(p []byte) (int, error) { return len(p), nil }
+func (eofConn) Close() error                { return nil }
+
+func TestAdaptorClosed(t *testing.T) {
+	a, b := initTestAdaptor()
+	b.conn.Close()
+	gobottest.Refute(t, a.DigitalWrite("1", 1), nil)
+
+	a = NewAdaptor(eofConn{})
+	gobottest.Assert(t, a.Connect(), ErrClosed)
+}
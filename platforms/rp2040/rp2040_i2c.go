@@ -0,0 +1,92 @@
+package rp2040
+
+import "encoding/hex"
+
+// i2cBus is an i2c bus of the board, the transfers being passed through
+// the firmware
+type i2cBus struct {
+	adaptor *Adaptor
+	bus     int
+	address int
+}
+
+// SetAddress sets the address of the device the next transfers target
+func (b *i2cBus) SetAddress(address int) error {
+	b.address = address
+	return nil
+}
+
+// Read reads a full buffer from the device
+func (b *i2cBus) Read(data []byte) (read int, err error) {
+	reply, err := b.adaptor.request("i2cr", b.bus, b.address, len(data))
+	if err != nil {
+		return
+	}
+	result, err := hex.DecodeString(reply)
+	if err != nil {
+		return
+	}
+	return copy(data, result), nil
+}
+
+// Write writes data to the device
+func (b *i2cBus) Write(data []byte) (written int, err error) {
+	if len(data) == 0 {
+		return
+	}
+	if _, err = b.adaptor.request("i2cw", b.bus, b.address, hex.EncodeToString(data)); err != nil {
+		return
+	}
+	return len(data), nil
+}
+
+// Close implements the io.Closer interface, the bus staying open on the board
+func (b *i2cBus) Close() error {
+	return nil
+}
+
+func (b *i2cBus) ReadByte() (val byte, err error) {
+	buf := []byte{0}
+	if _, err = b.Read(buf); err != nil {
+		return
+	}
+	return buf[0], nil
+}
+
+func (b *i2cBus) ReadByteData(reg uint8) (val uint8, err error) {
+	if err = b.WriteByte(reg); err != nil {
+		return
+	}
+	return b.ReadByte()
+}
+
+func (b *i2cBus) ReadWordData(reg uint8) (val uint16, err error) {
+	if err = b.WriteByte(reg); err != nil {
+		return
+	}
+	buf := []byte{0, 0}
+	if _, err = b.Read(buf); err != nil {
+		return
+	}
+	return uint16(buf[1])<<8 | uint16(buf[0]), nil
+}
+
+func (b *i2cBus) WriteByte(val byte) (err error) {
+	_, err = b.Write([]byte{val})
+	return
+}
+
+func (b *i2cBus) WriteByteData(reg uint8, val uint8) (err error) {
+	_, err = b.Write([]byte{reg, val})
+	return
+}
+
+func (b *i2cBus) WriteWordData(reg uint8, val uint16) (err error) {
+	_, err = b.Write([]byte{reg, byte(val), byte(val >> 8)})
+	return
+}
+
+func (b *i2cBus) WriteBlockData(reg uint8, data []byte) (err error) {
+	_, err = b.Write(append([]byte{reg}, data...))
+	return
+}
@@ -0,0 +1,71 @@
+package rp2040
+
+import (
+	"errors"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestI2cConnection(t *testing.T) {
+	a, b := initTestAdaptor()
+	_, err := a.GetConnection(0x40, 2)
+	gobottest.Assert(t, err, errors.New("Invalid bus number 2, only 0 and 1 are supported"))
+
+	con, err := a.GetConnection(0x40, 1)
+	gobottest.Assert(t, err, nil)
+	n, err := con.Write([]byte{0x01, 0xAB})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, n, 2)
+	n, err = con.Write(nil)
+	gobottest.Assert(t, n, 0)
+	gobottest.Assert(t, con.WriteByte(0x02), nil)
+	gobottest.Assert(t, con.WriteByteData(0x03, 0x04), nil)
+	gobottest.Assert(t, con.WriteWordData(0x05, 0x0607), nil)
+	gobottest.Assert(t, con.WriteBlockData(0x08, []byte{0x09, 0x0A}), nil)
+	gobottest.Assert(t, b.Requests(), []string{
+		"i2cw 1 64 01ab", "i2cw 1 64 02", "i2cw 1 64 0304",
+		"i2cw 1 64 050706", "i2cw 1 64 08090a",
+	})
+
+	b.reply("i2cr 1 64 1", "ok 2a")
+	b.reply("i2cr 1 64 2", "ok 3412")
+	val, err := con.ReadByte()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, byte(0x2A))
+	val, err = con.ReadByteData(0x10)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, byte(0x2A))
+	word, err := con.ReadWordData(0x11)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, word, uint16(0x1234))
+	gobottest.Assert(t, b.Requests(), []string{
+		"i2cr 1 64 1", "i2cw 1 64 10", "i2cr 1 64 1", "i2cw 1 64 11", "i2cr 1 64 2",
+	})
+	gobottest.Assert(t, con.Close(), nil)
+}
+
+func TestI2cConnectionError(t *testing.T) {
+	a, b := initTestAdaptor()
+	con, _ := a.GetConnection(0x40, 0)
+	b.reply("i2cw", "err ENODEV")
+	gobottest.Assert(t, con.WriteByte(0x01), errors.New("i2cw: ENODEV"))
+	_, err := con.ReadByteData(0x01)
+	gobottest.Assert(t, err, errors.New("i2cw: ENODEV"))
+	_, err = con.ReadWordData(0x01)
+	gobottest.Assert(t, err, errors.New("i2cw: ENODEV"))
+
+	b.reply("i2cr", "err ETIMEDOUT")
+	_, err = con.ReadByte()
+	gobottest.Assert(t, err, errors.New("i2cr: ETIMEDOUT"))
+	b.reply("i2cr", "ok zz")
+	_, err = con.ReadByte()
+	gobottest.Refute(t, err, nil)
+}
+
+func TestI2cBusSpeed(t *testing.T) {
+	a, b := initTestAdaptor()
+	gobottest.Assert(t, a.SetBusSpeed(1, 100000), nil)
+	gobottest.Assert(t, b.Requests(), []string{"i2cf 1 100000"})
+	gobottest.Assert(t, a.SetBusSpeed(2, 100000), errors.New("Invalid bus number 2, only 0 and 1 are supported"))
+}
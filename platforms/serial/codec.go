@@ -0,0 +1,298 @@
+package serial
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// DefaultMaxFrameLength is the longest frame decoded by the codecs whose
+// MaxLength is not set.
+const DefaultMaxFrameLength = 4096
+
+var (
+	// ErrFrameTooLong is returned when a frame is longer than the MaxLength of
+	// its codec. The frame is skipped, the next one can be decoded.
+	ErrFrameTooLong = errors.New("serial: frame too long")
+
+	// ErrInvalidFrame is returned when a frame cannot be decoded or encoded.
+	// When decoding, the frame is skipped, the next one can be decoded.
+	ErrInvalidFrame = errors.New("serial: invalid frame")
+)
+
+// Codec splits the byte stream of a serial port into frames, and encodes the
+// frames written to it.
+type Codec interface {
+	// Encode returns the bytes to write to send frame.
+	Encode(frame []byte) ([]byte, error)
+	// Decode reads the next frame from r.
+	Decode(r *bufio.Reader) ([]byte, error)
+}
+
+func maxLength(n int) int {
+	if n <= 0 {
+		return DefaultMaxFrameLength
+	}
+	return n
+}
+
+// readUntil reads up to and including delim, and returns the bytes read
+// before it. Frames longer than max are skipped with ErrFrameTooLong.
+func readUntil(r *bufio.Reader, delim byte, max int) ([]byte, error) {
+	var frame []byte
+	tooLong := false
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == delim {
+			break
+		}
+		if len(frame) == max {
+			tooLong = true
+			continue
+		}
+		if !tooLong {
+			frame = append(frame, b)
+		}
+	}
+	if tooLong {
+		return nil, ErrFrameTooLong
+	}
+	return frame, nil
+}
+
+// LineCodec frames text lines ended by Delimiter, a trailing carriage return
+// being removed from the decoded lines.
+type LineCodec struct {
+	Delimiter byte
+	MaxLength int
+}
+
+// NewLineCodec returns a new LineCodec for the lines ended by "\n" or "\r\n".
+func NewLineCodec() *LineCodec {
+	return &LineCodec{Delimiter: '\n'}
+}
+
+// Encode appends the delimiter to frame.
+func (c *LineCodec) Encode(frame []byte) ([]byte, error) {
+	if bytes.IndexByte(frame, c.Delimiter) >= 0 {
+		return nil, ErrInvalidFrame
+	}
+	return append(append([]byte{}, frame...), c.Delimiter), nil
+}
+
+// Decode reads the next line.
+func (c *LineCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	line, err := readUntil(r, c.Delimiter, maxLength(c.MaxLength))
+	if err != nil {
+		return nil, err
+	}
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nil
+}
+
+// LengthPrefixCodec frames the bytes preceded by their length, an unsigned
+// integer of Size bytes in the given byte Order.
+type LengthPrefixCodec struct {
+	// Size is the number of bytes of the length, 1, 2 or 4.
+	Size      int
+	Order     binary.ByteOrder
+	MaxLength int
+}
+
+// NewLengthPrefixCodec returns a new LengthPrefixCodec given the size of the
+// length in bytes and its byte order.
+func NewLengthPrefixCodec(size int, order binary.ByteOrder) *LengthPrefixCodec {
+	return &LengthPrefixCodec{Size: size, Order: order}
+}
+
+// Encode prepends its length to frame.
+func (c *LengthPrefixCodec) Encode(frame []byte) ([]byte, error) {
+	n := len(frame)
+	out := make([]byte, c.Size, c.Size+n)
+	switch {
+	case c.Size == 1 && n <= 0xFF:
+		out[0] = byte(n)
+	case c.Size == 2 && n <= 0xFFFF:
+		c.Order.PutUint16(out, uint16(n))
+	case c.Size == 4 && uint64(n) <= 0xFFFFFFFF:
+		c.Order.PutUint32(out, uint32(n))
+	case c.Size == 1 || c.Size == 2 || c.Size == 4:
+		return nil, ErrFrameTooLong
+	default:
+		return nil, ErrInvalidFrame
+	}
+	return append(out, frame...), nil
+}
+
+// Decode reads the length of the next frame, then the frame.
+func (c *LengthPrefixCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	prefix := make([]byte, c.Size)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, err
+	}
+	var n uint64
+	switch c.Size {
+	case 1:
+		n = uint64(prefix[0])
+	case 2:
+		n = uint64(c.Order.Uint16(prefix))
+	case 4:
+		n = uint64(c.Order.Uint32(prefix))
+	default:
+		return nil, ErrInvalidFrame
+	}
+	if n > uint64(maxLength(c.MaxLength)) {
+		if _, err := io.CopyN(ioutil.Discard, r, int64(n)); err != nil {
+			return nil, err
+		}
+		return nil, ErrFrameTooLong
+	}
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// SLIP special bytes, as defined by RFC 1055
+const (
+	slipEnd    = 0xC0
+	slipEsc    = 0xDB
+	slipEscEnd = 0xDC
+	slipEscEsc = 0xDD
+)
+
+// SLIPCodec frames the bytes as defined by the Serial Line Internet Protocol,
+// RFC 1055: each frame ends with an END byte, the END and ESC bytes of the
+// frame being escaped.
+type SLIPCodec struct {
+	MaxLength int
+}
+
+// NewSLIPCodec returns a new SLIPCodec.
+func NewSLIPCodec() *SLIPCodec {
+	return &SLIPCodec{}
+}
+
+// Encode escapes frame and surrounds it with END bytes, the leading one
+// flushing any noise received by the other end.
+func (c *SLIPCodec) Encode(frame []byte) ([]byte, error) {
+	out := make([]byte, 0, len(frame)+2)
+	out = append(out, slipEnd)
+	for _, b := range frame {
+		switch b {
+		case slipEnd:
+			out = append(out, slipEsc, slipEscEnd)
+		case slipEsc:
+			out = append(out, slipEsc, slipEscEsc)
+		default:
+			out = append(out, b)
+		}
+	}
+	return append(out, slipEnd), nil
+}
+
+// Decode reads the next non-empty frame and unescapes it.
+func (c *SLIPCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	for {
+		// escaped frames are up to twice as long
+		raw, err := readUntil(r, slipEnd, 2*maxLength(c.MaxLength))
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		frame := make([]byte, 0, len(raw))
+		for i := 0; i < len(raw); i++ {
+			if raw[i] != slipEsc {
+				frame = append(frame, raw[i])
+				continue
+			}
+			if i++; i == len(raw) {
+				return nil, ErrInvalidFrame
+			}
+			switch raw[i] {
+			case slipEscEnd:
+				frame = append(frame, slipEnd)
+			case slipEscEsc:
+				frame = append(frame, slipEsc)
+			default:
+				return nil, ErrInvalidFrame
+			}
+		}
+		if len(frame) > maxLength(c.MaxLength) {
+			return nil, ErrFrameTooLong
+		}
+		return frame, nil
+	}
+}
+
+// COBSCodec frames the bytes with Consistent Overhead Byte Stuffing: each
+// frame is encoded without zero bytes and ends with a zero byte.
+type COBSCodec struct {
+	MaxLength int
+}
+
+// NewCOBSCodec returns a new COBSCodec.
+func NewCOBSCodec() *COBSCodec {
+	return &COBSCodec{}
+}
+
+// Encode stuffs frame and appends the zero delimiter.
+func (c *COBSCodec) Encode(frame []byte) ([]byte, error) {
+	out := make([]byte, 1, len(frame)+len(frame)/254+2)
+	code, codeIndex := byte(1), 0
+	for _, b := range frame {
+		if b != 0 {
+			out = append(out, b)
+			code++
+		}
+		if b == 0 || code == 0xFF {
+			out[codeIndex] = code
+			code, codeIndex = 1, len(out)
+			out = append(out, 0)
+		}
+	}
+	out[codeIndex] = code
+	return append(out, 0), nil
+}
+
+// Decode reads the next non-empty frame and unstuffs it.
+func (c *COBSCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	for {
+		// stuffing adds a byte every 254 bytes, and one at the start
+		max := maxLength(c.MaxLength)
+		raw, err := readUntil(r, 0, max+max/254+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		frame := make([]byte, 0, len(raw))
+		for i := 0; i < len(raw); {
+			code := int(raw[i])
+			if i+code > len(raw) {
+				return nil, ErrInvalidFrame
+			}
+			frame = append(frame, raw[i+1:i+code]...)
+			i += code
+			if code < 0xFF && i < len(raw) {
+				frame = append(frame, 0)
+			}
+		}
+		if len(frame) > max {
+			return nil, ErrFrameTooLong
+		}
+		return frame, nil
+	}
+}
@@ -0,0 +1,154 @@
+package serial
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ Codec = (*LineCodec)(nil)
+var _ Codec = (*LengthPrefixCodec)(nil)
+var _ Codec = (*SLIPCodec)(nil)
+var _ Codec = (*COBSCodec)(nil)
+
+func decodeAll(c Codec, data []byte) (frames [][]byte, errs []error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		frame, err := c.Decode(r)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			errs = append(errs, err)
+			if err != ErrFrameTooLong && err != ErrInvalidFrame {
+				return
+			}
+			continue
+		}
+		frames = append(frames, frame)
+	}
+}
+
+func roundTrip(t *testing.T, c Codec, frame []byte) {
+	b, err := c.Encode(frame)
+	gobottest.Assert(t, err, nil)
+	frames, errs := decodeAll(c, b)
+	gobottest.Assert(t, len(errs), 0)
+	gobottest.Assert(t, frames, [][]byte{frame})
+}
+
+func TestLineCodec(t *testing.T) {
+	c := NewLineCodec()
+	b, err := c.Encode([]byte("hello"))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b, []byte("hello\n"))
+
+	frames, errs := decodeAll(c, []byte("one\r\ntwo\n\nthree"))
+	gobottest.Assert(t, len(errs), 0)
+	gobottest.Assert(t, frames, [][]byte{[]byte("one"), []byte("two"), nil})
+}
+
+func TestLineCodecEncodeError(t *testing.T) {
+	_, err := NewLineCodec().Encode([]byte("a\nb"))
+	gobottest.Assert(t, err, ErrInvalidFrame)
+}
+
+func TestLineCodecTooLong(t *testing.T) {
+	c := NewLineCodec()
+	c.MaxLength = 3
+	frames, errs := decodeAll(c, []byte("abcdef\nabc\n"))
+	gobottest.Assert(t, errs, []error{ErrFrameTooLong})
+	gobottest.Assert(t, frames, [][]byte{[]byte("abc")})
+}
+
+func TestLengthPrefixCodec(t *testing.T) {
+	c := NewLengthPrefixCodec(2, binary.BigEndian)
+	b, err := c.Encode([]byte{0xAA, 0xBB, 0xCC})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b, []byte{0x00, 0x03, 0xAA, 0xBB, 0xCC})
+
+	for _, size := range []int{1, 2, 4} {
+		roundTrip(t, NewLengthPrefixCodec(size, binary.LittleEndian), []byte("frame"))
+	}
+}
+
+func TestLengthPrefixCodecTooLong(t *testing.T) {
+	c := NewLengthPrefixCodec(1, binary.BigEndian)
+	_, err := c.Encode(make([]byte, 256))
+	gobottest.Assert(t, err, ErrFrameTooLong)
+
+	c.MaxLength = 2
+	frames, errs := decodeAll(c, []byte{3, 1, 2, 3, 2, 4, 5})
+	gobottest.Assert(t, errs, []error{ErrFrameTooLong})
+	gobottest.Assert(t, frames, [][]byte{{4, 5}})
+}
+
+func TestLengthPrefixCodecInvalidSize(t *testing.T) {
+	c := NewLengthPrefixCodec(3, binary.BigEndian)
+	_, err := c.Encode([]byte{1})
+	gobottest.Assert(t, err, ErrInvalidFrame)
+}
+
+func TestLengthPrefixCodecTruncated(t *testing.T) {
+	_, errs := decodeAll(NewLengthPrefixCodec(1, binary.BigEndian), []byte{3, 1})
+	gobottest.Assert(t, errs, []error{io.ErrUnexpectedEOF})
+}
+
+func TestSLIPCodec(t *testing.T) {
+	c := NewSLIPCodec()
+	b, err := c.Encode([]byte{0x01, slipEnd, 0x02, slipEsc})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b, []byte{slipEnd, 0x01, slipEsc, slipEscEnd, 0x02, slipEsc, slipEscEsc, slipEnd})
+
+	roundTrip(t, c, []byte{slipEnd, slipEsc, slipEscEnd, slipEscEsc})
+}
+
+func TestSLIPCodecInvalid(t *testing.T) {
+	frames, errs := decodeAll(NewSLIPCodec(), []byte{0x01, slipEsc, 0x02, slipEnd, 0x03, slipEnd})
+	gobottest.Assert(t, errs, []error{ErrInvalidFrame})
+	gobottest.Assert(t, frames, [][]byte{{0x03}})
+}
+
+func TestSLIPCodecTooLong(t *testing.T) {
+	c := NewSLIPCodec()
+	c.MaxLength = 2
+	frames, errs := decodeAll(c, []byte{1, 2, 3, slipEnd, 4, slipEnd})
+	gobottest.Assert(t, errs, []error{ErrFrameTooLong})
+	gobottest.Assert(t, frames, [][]byte{{4}})
+}
+
+func TestCOBSCodec(t *testing.T) {
+	c := NewCOBSCodec()
+	b, err := c.Encode([]byte{0x11, 0x22, 0x00, 0x33})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b, []byte{0x03, 0x11, 0x22, 0x02, 0x33, 0x00})
+
+	b, err = c.Encode([]byte{0x00})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b, []byte{0x01, 0x01, 0x00})
+
+	roundTrip(t, c, []byte{0x00, 0x00, 0x01})
+	roundTrip(t, c, []byte{0x01, 0x00})
+}
+
+func TestCOBSCodecLongFrame(t *testing.T) {
+	frame := make([]byte, 600)
+	for i := range frame {
+		frame[i] = byte(i%255) + 1
+	}
+	c := NewCOBSCodec()
+	b, _ := c.Encode(frame)
+	gobottest.Assert(t, bytes.IndexByte(b[:len(b)-1], 0), -1)
+	roundTrip(t, c, frame)
+	roundTrip(t, c, frame[:254])
+}
+
+func TestCOBSCodecInvalid(t *testing.T) {
+	frames, errs := decodeAll(NewCOBSCodec(), []byte{0x05, 0x01, 0x00, 0x02, 0x11, 0x00})
+	gobottest.Assert(t, errs, []error{ErrInvalidFrame})
+	gobottest.Assert(t, frames, [][]byte{{0x11}})
+}
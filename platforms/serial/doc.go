@@ -0,0 +1,49 @@
+/*
+Package serial contains the Gobot adaptor for the devices connected to a serial port.
+
+It reads and writes raw bytes, or frames delimited by a Codec: text lines,
+length-prefixed frames, SLIP or COBS frames. The frames received are
+published as "frame" events. The TFMiniDriver reads the Benewake TFMini
+lidars in UART mode, and the RPLidarDriver the 360 degrees scans of the
+Slamtec RPLIDAR A1 and A2.
+
+Installing:
+
+	go get gobot.io/x/gobot/platforms/serial
+
+Example:
+
+	package main
+
+	import (
+		"fmt"
+
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/platforms/serial"
+	)
+
+	func main() {
+		adaptor := serial.NewAdaptor("/dev/ttyACM0", 115200, serial.NewLineCodec())
+
+		work := func() {
+			adaptor.On(serial.Frame, func(data interface{}) {
+				fmt.Printf("Received %s\n", data)
+			})
+			adaptor.On(serial.Error, func(data interface{}) {
+				fmt.Println("Error", data)
+			})
+			adaptor.WriteFrame([]byte("hello"))
+		}
+
+		robot := gobot.NewRobot("serialBot",
+			[]gobot.Connection{adaptor},
+			work,
+		)
+
+		robot.Start()
+	}
+
+For further information refer to serial README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/serial/README.md
+*/
+package serial // import "gobot.io/x/gobot/platforms/serial"
@@ -0,0 +1,387 @@
+package serial
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Scan event is published by the RPLidarDriver with each complete RPLidarScan
+const Scan = "scan"
+
+// DefaultRPLidarMotorPWM is the duty cycle, out of 1023, of the motor of a
+// RPLIDAR A2 at start, turning at about 10Hz
+const DefaultRPLidarMotorPWM = 660
+
+// RPLIDAR protocol
+const (
+	rplidarSyncByte         = 0xA5
+	rplidarSyncByte2        = 0x5A
+	rplidarCmdStop          = 0x25
+	rplidarCmdReset         = 0x40
+	rplidarCmdScan          = 0x20
+	rplidarCmdGetInfo       = 0x50
+	rplidarCmdGetHealth     = 0x52
+	rplidarCmdSetMotorPWM   = 0xF0
+	rplidarTypeScan         = 0x81
+	rplidarDescriptorLength = 7
+	rplidarNodeLength       = 5
+	// rplidarModeMultiple is the send mode of the responses streamed until
+	// the next request
+	rplidarModeMultiple = 1
+)
+
+var (
+	// ErrRPLidarCodec is returned by the RPLidarDriver when its Adaptor has a
+	// Codec, the driver reading the port itself.
+	ErrRPLidarCodec = errors.New("serial: RPLIDAR adaptor must not have a codec")
+
+	// ErrRPLidarScanning is returned when a request needing an answer is
+	// sent to a RPLIDAR which is scanning.
+	ErrRPLidarScanning = errors.New("serial: RPLIDAR is scanning")
+
+	// ErrRPLidarTimeout is returned when a RPLIDAR does not answer a request.
+	ErrRPLidarTimeout = errors.New("serial: RPLIDAR did not answer")
+)
+
+// RPLidarScan is a 360 degrees scan of a RPLIDAR, the data published with the
+// Scan event. The measurements are in the order they were taken, clockwise
+// when seen from above, a zero distance being an invalid measurement.
+type RPLidarScan struct {
+	// Angles of the measurements, in degrees
+	Angles []float64
+	// Distances of the measurements, in mm
+	Distances []float64
+	// Qualities of the measurements, the strength of the reflected signal
+	// from 0 to 63
+	Qualities []int
+}
+
+// Len returns the number of measurements of the scan.
+func (s RPLidarScan) Len() int { return len(s.Angles) }
+
+// RPLidarHealth is the health status of a RPLIDAR.
+type RPLidarHealth struct {
+	// Status is 0 when the lidar is good, 1 on a warning and 2 on an error
+	Status    int
+	ErrorCode int
+}
+
+// RPLidarInfo describes a RPLIDAR.
+type RPLidarInfo struct {
+	Model    int
+	Firmware string
+	Hardware int
+	Serial   [16]byte
+}
+
+// RPLidarDriver is a driver for the Slamtec RPLIDAR A1 and A2 2D lidars.
+// Once started, the lidar scans around and each complete 360 degrees scan is
+// published with the Scan event.
+//
+// The motor of a RPLIDAR A1 turns as long as the DTR line of its serial
+// adapter is low, whereas the one of a RPLIDAR A2 is driven by SetMotorPWM.
+type RPLidarDriver struct {
+	name      string
+	adaptor   *Adaptor
+	motorPWM  uint16
+	scanning  bool
+	reading   bool
+	lastScan  RPLidarScan
+	responses chan []byte
+	mutex     sync.Mutex
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewRPLidarDriver returns a new RPLidarDriver given the Adaptor of the
+// serial port of the lidar, which has no Codec, usually at 115200 bauds:
+//
+//	adaptor := serial.NewAdaptor("/dev/ttyUSB0", 115200)
+//	lidar := serial.NewRPLidarDriver(adaptor)
+func NewRPLidarDriver(a *Adaptor) *RPLidarDriver {
+	d := &RPLidarDriver{
+		name:      gobot.DefaultName("RPLidar"),
+		adaptor:   a,
+		motorPWM:  DefaultRPLidarMotorPWM,
+		responses: make(chan []byte, 1),
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	d.AddEvent(Scan)
+	d.AddEvent(Error)
+
+	d.AddCommand("StartScan", func(params map[string]interface{}) interface{} {
+		return d.StartScan()
+	})
+	d.AddCommand("StopScan", func(params map[string]interface{}) interface{} {
+		return d.StopScan()
+	})
+	d.AddCommand("SetMotorPWM", func(params map[string]interface{}) interface{} {
+		return d.SetMotorPWM(uint16(params["pwm"].(float64)))
+	})
+	d.AddCommand("Health", func(params map[string]interface{}) interface{} {
+		health, err := d.Health()
+		return map[string]interface{}{"status": health.Status, "errorCode": health.ErrorCode, "err": err}
+	})
+
+	return d
+}
+
+// Name returns the name of the Driver.
+func (d *RPLidarDriver) Name() string { return d.name }
+
+// SetName sets the name of the Driver.
+func (d *RPLidarDriver) SetName(n string) { d.name = n }
+
+// Connection returns the Adaptor of the Driver.
+func (d *RPLidarDriver) Connection() gobot.Connection { return d.adaptor }
+
+// Start starts the motor at DefaultRPLidarMotorPWM, or at the duty cycle
+// last set, and the scan.
+func (d *RPLidarDriver) Start() error {
+	if d.adaptor.Codec() != nil {
+		return ErrRPLidarCodec
+	}
+	d.mutex.Lock()
+	if !d.reading {
+		d.reading = true
+		go d.readResponses(bufio.NewReader(d.adaptor))
+	}
+	pwm := d.motorPWM
+	d.mutex.Unlock()
+
+	if err := d.SetMotorPWM(pwm); err != nil {
+		return err
+	}
+	return d.StartScan()
+}
+
+// Halt stops the scan and the motor.
+func (d *RPLidarDriver) Halt() error {
+	if err := d.StopScan(); err != nil {
+		return err
+	}
+	return d.request(rplidarCmdSetMotorPWM, 0, 0)
+}
+
+// StartScan starts scanning, the complete scans being published with the
+// Scan event.
+func (d *RPLidarDriver) StartScan() error {
+	d.mutex.Lock()
+	d.scanning = true
+	d.mutex.Unlock()
+	return d.request(rplidarCmdScan)
+}
+
+// StopScan stops scanning, the motor keeping on turning.
+func (d *RPLidarDriver) StopScan() error {
+	d.mutex.Lock()
+	d.scanning = false
+	d.mutex.Unlock()
+	return d.request(rplidarCmdStop)
+}
+
+// Scanning returns whether the lidar is scanning.
+func (d *RPLidarDriver) Scanning() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.scanning
+}
+
+// Reset restarts the core of the lidar, which stops scanning.
+func (d *RPLidarDriver) Reset() error {
+	d.mutex.Lock()
+	d.scanning = false
+	d.mutex.Unlock()
+	return d.request(rplidarCmdReset)
+}
+
+// SetMotorPWM sets the duty cycle of the motor of a RPLIDAR A2, from 0 to
+// 1023, the faster the motor the fewer measurements per scan.
+func (d *RPLidarDriver) SetMotorPWM(pwm uint16) error {
+	if pwm > 1023 {
+		pwm = 1023
+	}
+	d.mutex.Lock()
+	d.motorPWM = pwm
+	d.mutex.Unlock()
+	return d.request(rplidarCmdSetMotorPWM, byte(pwm), byte(pwm>>8))
+}
+
+// LastScan returns the latest complete scan.
+func (d *RPLidarDriver) LastScan() RPLidarScan {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.lastScan
+}
+
+// Health returns the health status of the lidar, which must not be scanning.
+func (d *RPLidarDriver) Health() (RPLidarHealth, error) {
+	b, err := d.query(rplidarCmdGetHealth, 3)
+	if err != nil {
+		return RPLidarHealth{}, err
+	}
+	return RPLidarHealth{Status: int(b[0]), ErrorCode: int(binary.LittleEndian.Uint16(b[1:]))}, nil
+}
+
+// Info returns the model, firmware, hardware and serial number of the lidar,
+// which must not be scanning.
+func (d *RPLidarDriver) Info() (RPLidarInfo, error) {
+	b, err := d.query(rplidarCmdGetInfo, 20)
+	if err != nil {
+		return RPLidarInfo{}, err
+	}
+	info := RPLidarInfo{
+		Model:    int(b[0]),
+		Firmware: fmt.Sprintf("%d.%02d", b[2], b[1]),
+		Hardware: int(b[3]),
+	}
+	copy(info.Serial[:], b[4:])
+	return info, nil
+}
+
+// request sends the command, with its payload and checksum if any
+func (d *RPLidarDriver) request(cmd byte, payload ...byte) error {
+	b := []byte{rplidarSyncByte, cmd}
+	if len(payload) > 0 {
+		b = append(b, byte(len(payload)))
+		b = append(b, payload...)
+		var checksum byte
+		for _, v := range b {
+			checksum ^= v
+		}
+		b = append(b, checksum)
+	}
+	_, err := d.adaptor.Write(b)
+	return err
+}
+
+// query sends the command and waits for its response of n bytes
+func (d *RPLidarDriver) query(cmd byte, n int) ([]byte, error) {
+	if d.Scanning() {
+		return nil, ErrRPLidarScanning
+	}
+	// drop a response which came too late
+	select {
+	case <-d.responses:
+	default:
+	}
+	if err := d.request(cmd); err != nil {
+		return nil, err
+	}
+	select {
+	case b := <-d.responses:
+		if len(b) < n {
+			return nil, ErrInvalidFrame
+		}
+		return b, nil
+	case <-time.After(time.Second):
+		return nil, ErrRPLidarTimeout
+	}
+}
+
+// readResponses reads the responses of the lidar until the port is closed
+func (d *RPLidarDriver) readResponses(r *bufio.Reader) {
+	var err error
+	defer func() {
+		d.mutex.Lock()
+		d.reading = false
+		d.mutex.Unlock()
+		if err != io.EOF {
+			d.Publish(Error, err)
+		}
+	}()
+	for {
+		var length, mode int
+		var dataType byte
+		if length, mode, dataType, err = d.readDescriptor(r); err != nil {
+			return
+		}
+		if mode == rplidarModeMultiple && dataType == rplidarTypeScan {
+			if err = d.readScan(r); err != nil {
+				return
+			}
+			continue
+		}
+		b := make([]byte, length)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return
+		}
+		select {
+		case d.responses <- b:
+		default:
+		}
+	}
+}
+
+// readDescriptor reads up to the next response descriptor, made of the sync
+// bytes, the length of the response and its send mode on 32 bits, and its
+// data type
+func (d *RPLidarDriver) readDescriptor(r *bufio.Reader) (length int, mode int, dataType byte, err error) {
+	var last byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if last == rplidarSyncByte && b == rplidarSyncByte2 {
+			break
+		}
+		last = b
+	}
+	b := make([]byte, rplidarDescriptorLength-2)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, 0, 0, err
+	}
+	v := binary.LittleEndian.Uint32(b)
+	return int(v & 0x3FFFFFFF), int(v >> 30), b[4], nil
+}
+
+// readScan reads the measurements of the scan until it is stopped, each one
+// made of a start flag and its inverse, the quality, a check bit, the angle
+// in 1/64 degrees and the distance in 1/4 mm
+func (d *RPLidarDriver) readScan(r *bufio.Reader) error {
+	var scan RPLidarScan
+	// the measurements before the first start flag are a partial scan
+	complete := false
+	for {
+		if !d.Scanning() {
+			// the lidar was stopped, the next bytes are a descriptor
+			if b, err := r.Peek(2); err != nil || (b[0] == rplidarSyncByte && b[1] == rplidarSyncByte2) {
+				return err
+			}
+		}
+		node, err := r.Peek(rplidarNodeLength)
+		if err != nil {
+			return err
+		}
+		start, notStart, check := node[0]&1, node[0]>>1&1, node[1]&1
+		if start == notStart || check != 1 {
+			d.Publish(Error, ErrInvalidFrame)
+			// resynchronize on the next byte
+			r.Discard(1)
+			continue
+		}
+		if start == 1 {
+			if complete {
+				d.mutex.Lock()
+				d.lastScan = scan
+				d.mutex.Unlock()
+				d.Publish(Scan, scan)
+			}
+			scan, complete = RPLidarScan{}, true
+		}
+		scan.Angles = append(scan.Angles, float64(binary.LittleEndian.Uint16(node[1:])>>1)/64)
+		scan.Distances = append(scan.Distances, float64(binary.LittleEndian.Uint16(node[3:]))/4)
+		scan.Qualities = append(scan.Qualities, int(node[0]>>2))
+		r.Discard(rplidarNodeLength)
+	}
+}
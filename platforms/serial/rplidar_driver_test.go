@@ -0,0 +1,123 @@
+package serial
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*RPLidarDriver)(nil)
+
+// rplidarNode encodes a measurement of a scan
+func rplidarNode(start bool, quality int, angle float64, distance float64) []byte {
+	flags := byte(2)
+	if start {
+		flags = 1
+	}
+	a, dist := uint16(angle*64)<<1|1, uint16(distance*4)
+	return []byte{byte(quality)<<2 | flags, byte(a), byte(a >> 8), byte(dist), byte(dist >> 8)}
+}
+
+func initTestRPLidarDriver() (*RPLidarDriver, *testPort) {
+	a, port := initTestSerialAdaptor(115200)
+	a.Connect()
+	return NewRPLidarDriver(a), port
+}
+
+func TestRPLidarDriverName(t *testing.T) {
+	d, _ := initTestRPLidarDriver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "RPLidar"), true)
+	d.SetName("lidar")
+	gobottest.Assert(t, d.Name(), "lidar")
+	gobottest.Assert(t, d.Connection(), gobot.Connection(d.adaptor))
+}
+
+func TestRPLidarDriverScan(t *testing.T) {
+	d, port := initTestRPLidarDriver()
+	defer d.adaptor.Finalize()
+	scans := make(chan interface{}, 1)
+	errs := make(chan interface{}, 1)
+	d.On(Scan, func(data interface{}) { scans <- data })
+	d.On(Error, func(data interface{}) { errs <- data })
+
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Scanning(), true)
+	// motor PWM 660 and scan
+	gobottest.Assert(t, port.Written(), []byte{0xA5, 0xF0, 0x02, 0x94, 0x02, 0xC1, 0xA5, 0x20})
+
+	data := []byte{0xA5, 0x5A, 0x05, 0x00, 0x00, 0x40, 0x81}
+	data = append(data, rplidarNode(false, 10, 359.5, 800)...)
+	data = append(data, rplidarNode(true, 47, 0.5, 1000)...)
+	data = append(data, 0xFF)
+	data = append(data, rplidarNode(false, 46, 90, 1500.25)...)
+	data = append(data, rplidarNode(false, 0, 180, 0)...)
+	data = append(data, rplidarNode(true, 45, 1, 990)...)
+	port.w.Write(data)
+
+	select {
+	case data := <-scans:
+		want := RPLidarScan{
+			Angles:    []float64{0.5, 90, 180},
+			Distances: []float64{1000, 1500.25, 0},
+			Qualities: []int{47, 46, 0},
+		}
+		gobottest.Assert(t, data, want)
+		gobottest.Assert(t, d.LastScan(), want)
+		gobottest.Assert(t, want.Len(), 3)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("scan was not published")
+	}
+	select {
+	case data := <-errs:
+		gobottest.Assert(t, data, ErrInvalidFrame)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("invalid measurement was not reported")
+	}
+	_, err := d.Health()
+	gobottest.Assert(t, err, ErrRPLidarScanning)
+}
+
+func TestRPLidarDriverHalt(t *testing.T) {
+	d, port := initTestRPLidarDriver()
+	defer d.adaptor.Finalize()
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, d.Scanning(), false)
+	gobottest.Assert(t, bytes.HasSuffix(port.Written(), []byte{0xA5, 0x25, 0xA5, 0xF0, 0x02, 0x00, 0x00, 0x57}), true)
+}
+
+func TestRPLidarDriverHealth(t *testing.T) {
+	d, port := initTestRPLidarDriver()
+	defer d.adaptor.Finalize()
+	gobottest.Assert(t, d.Start(), nil)
+	port.w.Write(append([]byte{0xA5, 0x5A, 0x05, 0x00, 0x00, 0x40, 0x81}, rplidarNode(true, 47, 0.5, 1000)...))
+	gobottest.Assert(t, d.StopScan(), nil)
+
+	go func() {
+		// the rest of the scan, then the health
+		port.w.Write(rplidarNode(false, 47, 1, 1000))
+		port.w.Write([]byte{0xA5, 0x5A, 0x03, 0x00, 0x00, 0x00, 0x06, 0x01, 0x02, 0x80})
+	}()
+	health, err := d.Health()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, health, RPLidarHealth{Status: 1, ErrorCode: 0x8002})
+
+	go func() {
+		port.w.Write([]byte{0xA5, 0x5A, 0x14, 0x00, 0x00, 0x00, 0x04, 0x18, 0x1D, 0x01, 0x07})
+		port.w.Write(make([]byte, 16))
+	}()
+	info, err := d.Info()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, info.Model, 0x18)
+	gobottest.Assert(t, info.Firmware, "1.29")
+	gobottest.Assert(t, info.Hardware, 7)
+}
+
+func TestRPLidarDriverStartCodec(t *testing.T) {
+	a, _ := initTestSerialAdaptor(NewLineCodec())
+	gobottest.Assert(t, NewRPLidarDriver(a).Start(), ErrRPLidarCodec)
+}
@@ -0,0 +1,184 @@
+package serial
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sync"
+
+	bugst "go.bug.st/serial.v1"
+	"gobot.io/x/gobot"
+)
+
+const (
+	// Frame event, published with each frame decoded by the codec
+	Frame = "frame"
+
+	// Error event, published with the errors reading the port or decoding
+	// the frames
+	Error = "error"
+)
+
+// DefaultBaudRate is the baud rate used when none is given to NewAdaptor.
+const DefaultBaudRate = 9600
+
+// ErrNoCodec is returned by WriteFrame when the Adaptor has no Codec.
+var ErrNoCodec = errors.New("serial: no codec")
+
+// Adaptor is the Gobot Adaptor for the devices connected to a serial port,
+// such as microcontrollers speaking a custom protocol.
+type Adaptor struct {
+	name     string
+	port     string
+	baudRate int
+	codec    Codec
+	sp       io.ReadWriteCloser
+	connect  func(*Adaptor) (io.ReadWriteCloser, error)
+	mutex    *sync.Mutex
+	done     chan struct{}
+	gobot.Eventer
+}
+
+// NewAdaptor returns a new serial Adaptor given a port, a baud rate and a
+// Codec splitting the data received into frames.
+//
+// Params:
+//		port string - the serial port, e.g. "/dev/ttyACM0"
+//
+// Optional params:
+//		io.ReadWriteCloser - an already open port to use instead of port
+//		int - the baud rate, 9600 by default
+//		Codec - the codec publishing the "frame" events
+func NewAdaptor(args ...interface{}) *Adaptor {
+	s := &Adaptor{
+		name:     gobot.DefaultName("Serial"),
+		baudRate: DefaultBaudRate,
+		connect: func(s *Adaptor) (io.ReadWriteCloser, error) {
+			return bugst.Open(s.Port(), &bugst.Mode{BaudRate: s.baudRate})
+		},
+		mutex:   &sync.Mutex{},
+		Eventer: gobot.NewEventer(),
+	}
+
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case string:
+			s.port = a
+		case io.ReadWriteCloser:
+			s.connect = func(s *Adaptor) (io.ReadWriteCloser, error) {
+				return a, nil
+			}
+		case int:
+			s.baudRate = a
+		case Codec:
+			s.codec = a
+		}
+	}
+
+	s.AddEvent(Frame)
+	s.AddEvent(Error)
+
+	return s
+}
+
+// Name returns the Adaptor Name
+func (s *Adaptor) Name() string { return s.name }
+
+// SetName sets the Adaptor Name
+func (s *Adaptor) SetName(n string) { s.name = n }
+
+// Port returns the Adaptor port
+func (s *Adaptor) Port() string { return s.port }
+
+// BaudRate returns the Adaptor baud rate
+func (s *Adaptor) BaudRate() int { return s.baudRate }
+
+// Codec returns the Adaptor Codec, nil when it has none
+func (s *Adaptor) Codec() Codec { return s.codec }
+
+// Connect opens the serial port. When the Adaptor has a Codec, the frames
+// received are decoded and published as "frame" events.
+func (s *Adaptor) Connect() error {
+	sp, err := s.connect(s)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.sp = sp
+	s.mutex.Unlock()
+	s.done = make(chan struct{})
+	if s.codec != nil {
+		go s.readFrames(bufio.NewReader(sp), s.done)
+	}
+	return nil
+}
+
+// Finalize closes the serial port
+func (s *Adaptor) Finalize() error {
+	s.mutex.Lock()
+	sp := s.sp
+	s.sp = nil
+	s.mutex.Unlock()
+	if sp == nil {
+		return nil
+	}
+	close(s.done)
+	return sp.Close()
+}
+
+// Read reads raw bytes from the serial port. It must not be used along with
+// a Codec, which reads the port itself. It returns io.EOF once the port is
+// closed.
+func (s *Adaptor) Read(b []byte) (int, error) {
+	s.mutex.Lock()
+	sp := s.sp
+	s.mutex.Unlock()
+	if sp == nil {
+		return 0, io.EOF
+	}
+	return sp.Read(b)
+}
+
+// Write writes raw bytes to the serial port.
+func (s *Adaptor) Write(b []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.sp.Write(b)
+}
+
+// WriteFrame encodes frame with the Codec, and writes it to the serial port.
+func (s *Adaptor) WriteFrame(frame []byte) error {
+	if s.codec == nil {
+		return ErrNoCodec
+	}
+	b, err := s.codec.Encode(frame)
+	if err != nil {
+		return err
+	}
+	_, err = s.Write(b)
+	return err
+}
+
+// readFrames publishes the frames decoded from the serial port until it is
+// closed. The invalid frames are reported with an "error" event and skipped,
+// the other errors stop the reading.
+func (s *Adaptor) readFrames(r *bufio.Reader, done chan struct{}) {
+	for {
+		frame, err := s.codec.Decode(r)
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if err != nil {
+			s.Publish(Error, err)
+			if err == ErrFrameTooLong || err == ErrInvalidFrame {
+				continue
+			}
+			return
+		}
+		s.Publish(Frame, frame)
+	}
+}
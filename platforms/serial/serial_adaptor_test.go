@@ -0,0 +1,186 @@
+package serial
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+// testPort is a serial port receiving what is written to its pipe writer.
+type testPort struct {
+	mtx        sync.Mutex
+	r          *io.PipeReader
+	w          *io.PipeWriter
+	written    bytes.Buffer
+	closeError error
+}
+
+func newTestPort() *testPort {
+	r, w := io.Pipe()
+	return &testPort{r: r, w: w}
+}
+
+func (p *testPort) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+func (p *testPort) Write(b []byte) (int, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.written.Write(b)
+}
+
+func (p *testPort) Written() []byte {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.written.Bytes()
+}
+
+func (p *testPort) Close() error {
+	p.r.Close()
+	return p.closeError
+}
+
+func initTestSerialAdaptor(args ...interface{}) (*Adaptor, *testPort) {
+	port := newTestPort()
+	a := NewAdaptor(append([]interface{}{"/dev/null"}, args...)...)
+	a.connect = func(s *Adaptor) (io.ReadWriteCloser, error) {
+		return port, nil
+	}
+	return a, port
+}
+
+func TestSerialAdaptor(t *testing.T) {
+	a := NewAdaptor("/dev/ttyACM0")
+	gobottest.Assert(t, a.Port(), "/dev/ttyACM0")
+	gobottest.Assert(t, a.BaudRate(), DefaultBaudRate)
+	gobottest.Assert(t, a.Codec(), nil)
+
+	c := NewLineCodec()
+	a = NewAdaptor("/dev/ttyACM0", 115200, c)
+	gobottest.Assert(t, a.BaudRate(), 115200)
+	gobottest.Assert(t, a.Codec(), Codec(c))
+}
+
+func TestSerialAdaptorName(t *testing.T) {
+	a := NewAdaptor("/dev/null")
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "Serial"), true)
+	a.SetName("NewName")
+	gobottest.Assert(t, a.Name(), "NewName")
+}
+
+func TestSerialAdaptorReadWriteCloser(t *testing.T) {
+	port := newTestPort()
+	a := NewAdaptor(port)
+	gobottest.Assert(t, a.Connect(), nil)
+	a.Write([]byte("raw"))
+	gobottest.Assert(t, port.Written(), []byte("raw"))
+}
+
+func TestSerialAdaptorConnect(t *testing.T) {
+	a, _ := initTestSerialAdaptor()
+	gobottest.Assert(t, a.Connect(), nil)
+
+	a.connect = func(s *Adaptor) (io.ReadWriteCloser, error) {
+		return nil, errors.New("connection error")
+	}
+	gobottest.Assert(t, a.Connect(), errors.New("connection error"))
+}
+
+func TestSerialAdaptorFinalize(t *testing.T) {
+	a, port := initTestSerialAdaptor(NewLineCodec())
+	gobottest.Assert(t, a.Finalize(), nil)
+
+	a.Connect()
+	gobottest.Assert(t, a.Finalize(), nil)
+
+	port.closeError = errors.New("close error")
+	a.Connect()
+	gobottest.Assert(t, a.Finalize(), errors.New("close error"))
+}
+
+func TestSerialAdaptorRead(t *testing.T) {
+	a, port := initTestSerialAdaptor()
+	a.Connect()
+	go port.w.Write([]byte("raw"))
+
+	b := make([]byte, 3)
+	n, err := a.Read(b)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, b[:n], []byte("raw"))
+}
+
+func TestSerialAdaptorWriteFrame(t *testing.T) {
+	a, port := initTestSerialAdaptor(NewSLIPCodec())
+	a.Connect()
+	defer a.Finalize()
+
+	gobottest.Assert(t, a.WriteFrame([]byte{0x01}), nil)
+	gobottest.Assert(t, port.Written(), []byte{slipEnd, 0x01, slipEnd})
+}
+
+func TestSerialAdaptorWriteFrameError(t *testing.T) {
+	a, _ := initTestSerialAdaptor()
+	a.Connect()
+	gobottest.Assert(t, a.WriteFrame([]byte("frame")), ErrNoCodec)
+
+	a, _ = initTestSerialAdaptor(NewLineCodec())
+	a.Connect()
+	defer a.Finalize()
+	gobottest.Assert(t, a.WriteFrame([]byte("a\nb")), ErrInvalidFrame)
+}
+
+func TestSerialAdaptorFrameEvents(t *testing.T) {
+	c := NewLineCodec()
+	c.MaxLength = 5
+	a, port := initTestSerialAdaptor(c)
+	frames := make(chan interface{}, 2)
+	errs := make(chan interface{}, 1)
+	a.On(Frame, func(data interface{}) { frames <- data })
+	a.On(Error, func(data interface{}) { errs <- data })
+	a.Connect()
+	defer a.Finalize()
+
+	port.w.Write([]byte("one\ntoo long\ntwo\n"))
+
+	for _, want := range []string{"one", "two"} {
+		select {
+		case data := <-frames:
+			gobottest.Assert(t, data, []byte(want))
+		case <-time.After(100 * time.Millisecond):
+			t.Errorf("frame %q was not published", want)
+		}
+	}
+	select {
+	case data := <-errs:
+		gobottest.Assert(t, data, ErrFrameTooLong)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("frame error was not published")
+	}
+}
+
+func TestSerialAdaptorReadError(t *testing.T) {
+	a, port := initTestSerialAdaptor(NewLineCodec())
+	errs := make(chan interface{}, 1)
+	a.On(Error, func(data interface{}) { errs <- data })
+	a.Connect()
+	defer a.Finalize()
+
+	port.w.CloseWithError(errors.New("read error"))
+
+	select {
+	case data := <-errs:
+		gobottest.Assert(t, data, errors.New("read error"))
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("read error was not published")
+	}
+}
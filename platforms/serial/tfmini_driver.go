@@ -0,0 +1,190 @@
+package serial
+
+import (
+	"bufio"
+	"io"
+	"sync"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+// Distance event is published by the TFMiniDriver with each i2c.TFMiniReading
+const Distance = i2c.Distance
+
+// tfminiFrameHeader is the pair of bytes starting a TFMini data frame
+const tfminiFrameHeader = 0x59
+
+// TFMiniCodec frames the 9 bytes data frames sent by the Benewake TFMini
+// lidars, which start with two 0x59 bytes and end with a checksum. The other
+// bytes received, such as the responses to the commands, are skipped. The
+// commands are written as is.
+type TFMiniCodec struct{}
+
+// NewTFMiniCodec returns a new TFMiniCodec.
+func NewTFMiniCodec() *TFMiniCodec {
+	return &TFMiniCodec{}
+}
+
+// Encode returns frame, a command built by i2c.TFMiniCommand.
+func (c *TFMiniCodec) Encode(frame []byte) ([]byte, error) {
+	return frame, nil
+}
+
+// Decode reads up to the next frame header, then the frame, which is
+// returned with its header and checksum. A frame with a bad checksum is
+// skipped with ErrInvalidFrame.
+func (c *TFMiniCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	var last byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if last == tfminiFrameHeader && b == tfminiFrameHeader {
+			break
+		}
+		last = b
+	}
+	frame := make([]byte, 9)
+	frame[0], frame[1] = tfminiFrameHeader, tfminiFrameHeader
+	if _, err := io.ReadFull(r, frame[2:]); err != nil {
+		return nil, err
+	}
+	var sum byte
+	for _, b := range frame[:8] {
+		sum += b
+	}
+	if sum != frame[8] {
+		return nil, ErrInvalidFrame
+	}
+	return frame, nil
+}
+
+// TFMiniDriver is a driver for the Benewake TFMini, TFMini Plus, TFMini-S and
+// TF-Luna lidars in UART mode, which stream their measurements at their frame
+// rate. Each one is published with the Distance event.
+type TFMiniDriver struct {
+	name    string
+	adaptor *Adaptor
+	model   i2c.TFMiniModel
+	reading i2c.TFMiniReading
+	halt    chan bool
+	mutex   sync.Mutex
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewTFMiniDriver returns a new TFMiniDriver given the Adaptor of the serial
+// port of the lidar, which must use a TFMiniCodec, and optionally its model,
+// i2c.TFMiniPlus by default:
+//
+//	adaptor := serial.NewAdaptor("/dev/ttyUSB0", 115200, serial.NewTFMiniCodec())
+//	lidar := serial.NewTFMiniDriver(adaptor, i2c.TFLuna)
+func NewTFMiniDriver(a *Adaptor, model ...i2c.TFMiniModel) *TFMiniDriver {
+	d := &TFMiniDriver{
+		name:      gobot.DefaultName("TFMini"),
+		adaptor:   a,
+		model:     i2c.TFMiniPlus,
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+	if len(model) > 0 {
+		d.model = model[0]
+	}
+
+	d.AddEvent(Distance)
+	d.AddEvent(Error)
+
+	d.AddCommand("Reading", func(params map[string]interface{}) interface{} {
+		r := d.Reading()
+		return map[string]interface{}{
+			"distance": r.Distance, "strength": r.Strength, "temperature": r.Temperature,
+		}
+	})
+	d.AddCommand("SetFrameRate", func(params map[string]interface{}) interface{} {
+		return d.SetFrameRate(uint16(params["rate"].(float64)))
+	})
+
+	return d
+}
+
+// Name returns the name of the Driver.
+func (d *TFMiniDriver) Name() string { return d.name }
+
+// SetName sets the name of the Driver.
+func (d *TFMiniDriver) SetName(n string) { d.name = n }
+
+// Connection returns the Adaptor of the Driver.
+func (d *TFMiniDriver) Connection() gobot.Connection { return d.adaptor }
+
+// Start decodes the frames received by the Adaptor, publishing each reading
+// with the Distance event.
+func (d *TFMiniDriver) Start() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.adaptor.Codec().(*TFMiniCodec); !ok {
+		return ErrNoCodec
+	}
+	frames := d.adaptor.SubscribeWith(gobot.SubscribeOptions{Pattern: Frame})
+	d.halt = make(chan bool)
+	go d.readFrames(frames, d.halt)
+	return nil
+}
+
+// Halt stops publishing the readings.
+func (d *TFMiniDriver) Halt() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.halt != nil {
+		close(d.halt)
+		d.halt = nil
+	}
+	return nil
+}
+
+// Reading returns the latest reading of the lidar.
+func (d *TFMiniDriver) Reading() i2c.TFMiniReading {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.reading
+}
+
+// Distance returns the latest distance measured by the lidar, in cm.
+func (d *TFMiniDriver) Distance() int {
+	return d.Reading().Distance
+}
+
+// SetFrameRate sets the number of readings sent by the lidar per second, 0
+// stopping them.
+func (d *TFMiniDriver) SetFrameRate(hz uint16) error {
+	return d.adaptor.WriteFrame(i2c.TFMiniFrameRateCommand(hz))
+}
+
+// SaveSettings saves the settings of the lidar, such as its frame rate, so
+// that they survive a power cycle.
+func (d *TFMiniDriver) SaveSettings() error {
+	return d.adaptor.WriteFrame(i2c.TFMiniSaveCommand())
+}
+
+func (d *TFMiniDriver) readFrames(frames chan *gobot.Event, halt chan bool) {
+	defer d.adaptor.Unsubscribe(frames)
+	for {
+		select {
+		case <-halt:
+			return
+		case evt := <-frames:
+			r, err := i2c.ParseTFMiniFrame(evt.Data.([]byte), d.model)
+			if err != nil {
+				d.Publish(Error, err)
+				continue
+			}
+			d.mutex.Lock()
+			d.reading = r
+			d.mutex.Unlock()
+			d.Publish(Distance, r)
+		}
+	}
+}
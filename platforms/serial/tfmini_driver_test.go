@@ -0,0 +1,73 @@
+package serial
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*TFMiniDriver)(nil)
+
+// tfminiFrame is a TFMini Plus frame of 100cm, a strength of 1000 and 25
+// celsius degrees
+var tfminiFrame = []byte{0x59, 0x59, 0x64, 0x00, 0xE8, 0x03, 0xC8, 0x08, 0xD1}
+
+func TestTFMiniCodec(t *testing.T) {
+	c := NewTFMiniCodec()
+	corrupted := append([]byte{}, tfminiFrame...)
+	corrupted[8]++
+	// a command response and noise precede the frames
+	r := bufio.NewReader(bytes.NewReader(append(append(
+		[]byte{0x5A, 0x06, 0x03, 0x0A, 0x00, 0x6D, 0x42}, tfminiFrame...), corrupted...)))
+
+	frame, err := c.Decode(r)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, frame, tfminiFrame)
+	_, err = c.Decode(r)
+	gobottest.Assert(t, err, ErrInvalidFrame)
+
+	b, _ := c.Encode(i2c.TFMiniSaveCommand())
+	gobottest.Assert(t, b, []byte{0x5A, 0x04, 0x11, 0x6F})
+}
+
+func TestTFMiniDriver(t *testing.T) {
+	a, port := initTestSerialAdaptor(115200, NewTFMiniCodec())
+	d := NewTFMiniDriver(a)
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "TFMini"), true)
+	d.SetName("lidar")
+	gobottest.Assert(t, d.Name(), "lidar")
+	gobottest.Assert(t, d.Connection(), gobot.Connection(a))
+
+	readings := make(chan interface{}, 1)
+	d.On(Distance, func(data interface{}) { readings <- data })
+	a.Connect()
+	defer a.Finalize()
+	gobottest.Assert(t, d.Start(), nil)
+	defer d.Halt()
+
+	port.w.Write(tfminiFrame)
+	select {
+	case data := <-readings:
+		want := i2c.TFMiniReading{Distance: 100, Strength: 1000, Temperature: 25}
+		gobottest.Assert(t, data, want)
+		gobottest.Assert(t, d.Reading(), want)
+		gobottest.Assert(t, d.Distance(), 100)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("distance was not published")
+	}
+
+	gobottest.Assert(t, d.SetFrameRate(10), nil)
+	gobottest.Assert(t, d.SaveSettings(), nil)
+	gobottest.Assert(t, port.Written(), []byte{0x5A, 0x06, 0x03, 0x0A, 0x00, 0x6D, 0x5A, 0x04, 0x11, 0x6F})
+}
+
+func TestTFMiniDriverStartNoCodec(t *testing.T) {
+	a, _ := initTestSerialAdaptor(NewLineCodec())
+	gobottest.Assert(t, NewTFMiniDriver(a, i2c.TFLuna).Start(), ErrNoCodec)
+}
@@ -0,0 +1,95 @@
+package sim
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Collision is the event published by the Adaptor with the Pose at which the
+// robot bumped into a wall or an obstacle
+const Collision = "collision"
+
+// Adaptor is the Gobot Adaptor running a simulated World, for the drivers of
+// this package to drive its robot and read its sensors. Once connected, the
+// World is stepped every Interval of the gobot.DefaultClock, so that a
+// gobot.FakeClock runs the simulation step by step in tests.
+type Adaptor struct {
+	// Interval is the duration of each Step of the World, 10ms by default
+	Interval time.Duration
+
+	name  string
+	world *World
+	halt  chan bool
+	done  chan bool
+	mutex sync.Mutex
+	gobot.Eventer
+}
+
+// NewAdaptor returns a new simulator Adaptor given the World to run.
+func NewAdaptor(world *World) *Adaptor {
+	a := &Adaptor{
+		Interval: 10 * time.Millisecond,
+		name:     gobot.DefaultName("Sim"),
+		world:    world,
+		Eventer:  gobot.NewEventer(),
+	}
+	a.AddEvent(Collision)
+	return a
+}
+
+// Name returns the Adaptors name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptors name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// World returns the simulated World
+func (a *Adaptor) World() *World { return a.world }
+
+// Connect starts stepping the World every Interval.
+//
+// Emits the Events:
+// 	Collision control.Pose - When the robot bumps into a wall or an obstacle
+func (a *Adaptor) Connect() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.halt != nil {
+		return
+	}
+	a.halt, a.done = make(chan bool), make(chan bool)
+
+	go func(halt, done chan bool) {
+		defer close(done)
+		clock := gobot.DefaultClock()
+		collided := false
+		for {
+			select {
+			case <-clock.After(a.Interval):
+				// publish once per collision, not on every Step against
+				// the wall
+				if a.world.Step(a.Interval) && !collided {
+					a.Publish(Collision, a.world.Pose())
+				}
+				collided = a.world.Collided()
+			case <-halt:
+				return
+			}
+		}
+	}(a.halt, a.done)
+	return
+}
+
+// Finalize stops stepping the World
+func (a *Adaptor) Finalize() (err error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.halt == nil {
+		return
+	}
+	close(a.halt)
+	<-a.done
+	a.halt = nil
+	return
+}
@@ -0,0 +1,79 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+// advance runs n steps of the Adaptor
+func advance(clock *gobot.FakeClock, a *Adaptor, n int) {
+	for i := 0; i < n; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(a.Interval)
+	}
+	clock.BlockUntil(1)
+}
+
+func TestAdaptor(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	gobottest.Assert(t, a.Interval, 10*time.Millisecond)
+	gobottest.Refute(t, a.World(), nil)
+	a.SetName("sim")
+	gobottest.Assert(t, a.Name(), "sim")
+}
+
+func TestAdaptorConnect(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Now())
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := NewAdaptor(newTestWorld())
+	gobottest.Assert(t, a.Connect(), nil)
+	gobottest.Assert(t, a.Connect(), nil)
+	a.World().SetWheel(Left, 1)
+	a.World().SetWheel(Right, 1)
+	advance(clock, a, 100)
+
+	gobottest.Assert(t, a.World().Elapsed(), time.Second)
+	gobottest.Assert(t, round(a.World().Pose().X), 0.5)
+
+	gobottest.Assert(t, a.Finalize(), nil)
+	gobottest.Assert(t, a.Finalize(), nil)
+}
+
+func TestAdaptorCollision(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Now())
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := NewAdaptor(newTestWorld())
+	collisions := make(chan control.Pose, 10)
+	a.On(Collision, func(data interface{}) {
+		collisions <- data.(control.Pose)
+	})
+
+	gobottest.Assert(t, a.Connect(), nil)
+	defer a.Finalize()
+	a.World().SetWheel(Left, 1)
+	a.World().SetWheel(Right, 1)
+	advance(clock, a, 500)
+
+	// the handlers run on the goroutine of the eventer
+	select {
+	case pose := <-collisions:
+		gobottest.Assert(t, pose.X > 1.89, true)
+	case <-time.After(time.Second):
+		t.Fatal("Collision was not published")
+	}
+	select {
+	case <-collisions:
+		t.Error("Collision was published twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Package sim contains the Gobot adaptor and drivers of a simulated
+differential drive robot, moving in a 2D world of walls and obstacles, so
+that navigation and behavior code can be developed and tested without the
+real robot.
+
+Installing:
+
+	go get gobot.io/x/gobot && go install gobot.io/x/gobot/platforms/sim
+
+Example:
+
+	package main
+
+	import (
+		"fmt"
+
+		"gobot.io/x/gobot"
+		"gobot.io/x/gobot/control"
+		"gobot.io/x/gobot/platforms/sim"
+	)
+
+	func main() {
+		drive := control.NewDifferentialDrive(0.03, 0.15)
+		drive.MaxWheelSpeed = 0.5
+		drive.TicksPerRevolution = 360
+
+		world := sim.NewWorld(drive, 0.1)
+		world.AddBox(-2, -2, 2, 2)
+		world.AddObstacle(1, 0.5, 0.2)
+
+		simulator := sim.NewAdaptor(world)
+		left := sim.NewMotorDriver(simulator, sim.Left)
+		right := sim.NewMotorDriver(simulator, sim.Right)
+		sonar := sim.NewRangeSensorDriver(simulator, 0, 2*gobot.Meter)
+
+		follower := control.NewWaypointFollower(world, drive, left, right)
+		follower.SetWaypoints(control.Waypoint{X: 1.5}, control.Waypoint{X: 1.5, Y: 1.5})
+		follower.OnObstacle(func(control.Pose) bool {
+			distance, _ := sonar.Range()
+			return distance < 25*gobot.Centimeter
+		})
+
+		work := func() {
+			follower.On(control.ArrivedEvent, func(data interface{}) {
+				fmt.Println("arrived in", world.Elapsed())
+			})
+		}
+
+		robot := gobot.NewRobot("simBot",
+			[]gobot.Connection{simulator},
+			[]gobot.Device{left, right, sonar, follower},
+			work,
+		)
+
+		robot.Start()
+	}
+
+For further information refer to sim README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/sim/README.md
+*/
+package sim // import "gobot.io/x/gobot/platforms/sim"
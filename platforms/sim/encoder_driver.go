@@ -0,0 +1,77 @@
+package sim
+
+import (
+	"math"
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// EncoderDriver is the quadrature encoder of a wheel of the simulated robot,
+// counting TicksPerRevolution ticks of the DifferentialDrive per revolution
+// like a gpio.EncoderDriver, so that the ticks can feed a control.Odometry.
+// The ticks count the turns of the wheel, not the distance the robot really
+// covered, which the Wheel Noise and the collisions make differ.
+type EncoderDriver struct {
+	name       string
+	side       Side
+	connection *Adaptor
+	offset     int64
+	mutex      sync.Mutex
+}
+
+// NewEncoderDriver returns a new EncoderDriver for the wheel on side of the
+// robot of the Adaptor.
+func NewEncoderDriver(a *Adaptor, side Side) *EncoderDriver {
+	return &EncoderDriver{
+		name:       gobot.DefaultName("SimEncoder"),
+		side:       side,
+		connection: a,
+	}
+}
+
+// Name returns the EncoderDrivers name
+func (e *EncoderDriver) Name() string { return e.name }
+
+// SetName sets the EncoderDrivers name
+func (e *EncoderDriver) SetName(n string) { e.name = n }
+
+// Pin returns the side of the wheel
+func (e *EncoderDriver) Pin() string { return string(e.side) }
+
+// Connection returns the EncoderDrivers Connection
+func (e *EncoderDriver) Connection() gobot.Connection { return e.connection }
+
+// Start implements the Driver interface
+func (e *EncoderDriver) Start() (err error) { return }
+
+// Halt implements the Driver interface
+func (e *EncoderDriver) Halt() (err error) { return }
+
+// Count returns the number of ticks since the last Reset, negative when the
+// wheel turned backward
+func (e *EncoderDriver) Count() int64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.ticks() - e.offset
+}
+
+// Reset sets the count to zero
+func (e *EncoderDriver) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.offset = e.ticks()
+}
+
+// Distance returns the distance covered by the wheel since the last Reset,
+// at the resolution of the encoder
+func (e *EncoderDriver) Distance() gobot.Distance {
+	return gobot.Distance(e.connection.world.Drive().Distance(e.Count())) * gobot.Meter
+}
+
+// ticks returns the ticks counted since the creation of the World
+func (e *EncoderDriver) ticks() int64 {
+	drive := e.connection.world.Drive()
+	revolutions := e.connection.world.Traveled(e.side) / (2 * math.Pi * drive.WheelRadius)
+	return int64(math.Floor(revolutions * drive.TicksPerRevolution))
+}
@@ -0,0 +1,52 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*EncoderDriver)(nil)
+
+func TestEncoderDriver(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	e := NewEncoderDriver(a, Left)
+	gobottest.Assert(t, e.Pin(), "left")
+	gobottest.Assert(t, e.Start(), nil)
+
+	a.World().SetWheel(Left, 1)
+	run(a.World(), time.Second)
+	// 0.5m with 0.05m wheels
+	gobottest.Assert(t, e.Count(), int64(math.Floor(0.5/(0.1*math.Pi)*1000)))
+	gobottest.Assert(t, e.Distance() > 0.499*gobot.Meter, true)
+
+	e.Reset()
+	gobottest.Assert(t, e.Count(), int64(0))
+	a.World().SetWheel(Left, -1)
+	run(a.World(), time.Second)
+	gobottest.Assert(t, e.Count() < -1590, true)
+	gobottest.Assert(t, e.Halt(), nil)
+}
+
+func TestEncoderDriverOdometry(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	left, right := NewEncoderDriver(a, Left), NewEncoderDriver(a, Right)
+	odometry := control.NewOdometry(a.World().Drive())
+	odometry.Update(left.Count(), right.Count())
+
+	a.World().SetWheel(Left, 0.5)
+	a.World().SetWheel(Right, 1)
+	for i := 0; i < 100; i++ {
+		a.World().Step(10 * time.Millisecond)
+		odometry.Update(left.Count(), right.Count())
+	}
+
+	truth, estimate := a.World().Pose(), odometry.Pose()
+	gobottest.Assert(t, math.Abs(truth.X-estimate.X) < 0.01, true)
+	gobottest.Assert(t, math.Abs(truth.Y-estimate.Y) < 0.01, true)
+	gobottest.Assert(t, math.Abs(truth.Theta-estimate.Theta) < 0.01, true)
+}
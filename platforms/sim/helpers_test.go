@@ -0,0 +1,25 @@
+package sim
+
+import (
+	"time"
+
+	"gobot.io/x/gobot/control"
+)
+
+// newTestWorld returns a World in a 4m square room, whose robot moves at
+// 0.5m/s with 10cm wheels turning 1000 ticks per revolution
+func newTestWorld() *World {
+	drive := control.NewDifferentialDrive(0.05, 0.2)
+	drive.MaxWheelSpeed = 0.5
+	drive.TicksPerRevolution = 1000
+	w := NewWorld(drive, 0.1)
+	w.AddBox(-2, -2, 2, 2)
+	return w
+}
+
+// run steps w for d in steps of 10ms
+func run(w *World, d time.Duration) {
+	for t := time.Duration(0); t < d; t += 10 * time.Millisecond {
+		w.Step(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,68 @@
+package sim
+
+import (
+	"gobot.io/x/gobot"
+)
+
+// MotorDriver drives a wheel of the simulated robot, like a gpio.MotorDriver
+// drives a real one, so that it can be given to a control.DifferentialDrive
+// or a control.WaypointFollower.
+type MotorDriver struct {
+	name       string
+	side       Side
+	connection *Adaptor
+}
+
+// NewMotorDriver returns a new MotorDriver for the wheel on side of the robot
+// of the Adaptor.
+func NewMotorDriver(a *Adaptor, side Side) *MotorDriver {
+	return &MotorDriver{
+		name:       gobot.DefaultName("SimMotor"),
+		side:       side,
+		connection: a,
+	}
+}
+
+// Name returns the MotorDrivers name
+func (m *MotorDriver) Name() string { return m.name }
+
+// SetName sets the MotorDrivers name
+func (m *MotorDriver) SetName(n string) { m.name = n }
+
+// Pin returns the side of the wheel
+func (m *MotorDriver) Pin() string { return string(m.side) }
+
+// Connection returns the MotorDrivers Connection
+func (m *MotorDriver) Connection() gobot.Connection { return m.connection }
+
+// Start implements the Driver interface
+func (m *MotorDriver) Start() (err error) { return }
+
+// Halt stops the wheel
+func (m *MotorDriver) Halt() (err error) { return m.Stop() }
+
+// SafeState stops the wheel
+func (m *MotorDriver) SafeState() error { return m.Stop() }
+
+// Forward turns the wheel forward at speed, 255 being the MaxWheelSpeed of
+// the DifferentialDrive
+func (m *MotorDriver) Forward(speed byte) error {
+	m.connection.world.SetWheel(m.side, float64(speed)/255)
+	return nil
+}
+
+// Backward turns the wheel backward at speed, 255 being the MaxWheelSpeed of
+// the DifferentialDrive
+func (m *MotorDriver) Backward(speed byte) error {
+	m.connection.world.SetWheel(m.side, -float64(speed)/255)
+	return nil
+}
+
+// Stop stops the wheel
+func (m *MotorDriver) Stop() error {
+	m.connection.world.SetWheel(m.side, 0)
+	return nil
+}
+
+// Speed returns the speed of the wheel, between -1 and 1
+func (m *MotorDriver) Speed() float64 { return m.connection.world.Wheel(m.side) }
@@ -0,0 +1,43 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Driver = (*MotorDriver)(nil)
+var _ control.Motor = (*MotorDriver)(nil)
+
+func TestMotorDriver(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	m := NewMotorDriver(a, Right)
+	gobottest.Assert(t, m.Pin(), "right")
+	gobottest.Assert(t, m.Connection(), gobot.Connection(a))
+	gobottest.Assert(t, m.Start(), nil)
+
+	gobottest.Assert(t, m.Forward(255), nil)
+	gobottest.Assert(t, a.World().Wheel(Right), 1.0)
+	gobottest.Assert(t, a.World().Wheel(Left), 0.0)
+
+	gobottest.Assert(t, m.Backward(51), nil)
+	gobottest.Assert(t, m.Speed(), -0.2)
+
+	gobottest.Assert(t, m.Halt(), nil)
+	gobottest.Assert(t, m.Speed(), 0.0)
+}
+
+func TestMotorDriverDifferentialDrive(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	left, right := NewMotorDriver(a, Left), NewMotorDriver(a, Right)
+
+	gobottest.Assert(t, a.World().Drive().Drive(left, right, 0.5, 0), nil)
+	run(a.World(), time.Second)
+	gobottest.Assert(t, round(a.World().Pose().X), 0.5)
+
+	gobottest.Assert(t, left.SafeState(), nil)
+	gobottest.Assert(t, left.Speed(), 0.0)
+}
@@ -0,0 +1,142 @@
+package sim
+
+import (
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/mapping"
+)
+
+// Scan is the event published by the LidarDriver with each complete scan, as
+// a []mapping.Range
+const Scan = "scan"
+
+// RangeSensorDriver is a range sensor of the simulated robot, such as a
+// sonar or a time of flight sensor, measuring the distance to the nearest
+// wall or obstacle along its direction from the center of the robot.
+type RangeSensorDriver struct {
+	name       string
+	angle      gobot.Angle
+	maxRange   gobot.Distance
+	connection *Adaptor
+}
+
+// NewRangeSensorDriver returns a new RangeSensorDriver pointing at angle from
+// the heading of the robot of the Adaptor, counterclockwise, and measuring up
+// to maxRange.
+func NewRangeSensorDriver(a *Adaptor, angle gobot.Angle, maxRange gobot.Distance) *RangeSensorDriver {
+	return &RangeSensorDriver{
+		name:       gobot.DefaultName("SimRangeSensor"),
+		angle:      angle,
+		maxRange:   maxRange,
+		connection: a,
+	}
+}
+
+// Name returns the RangeSensorDrivers name
+func (r *RangeSensorDriver) Name() string { return r.name }
+
+// SetName sets the RangeSensorDrivers name
+func (r *RangeSensorDriver) SetName(n string) { r.name = n }
+
+// Connection returns the RangeSensorDrivers Connection
+func (r *RangeSensorDriver) Connection() gobot.Connection { return r.connection }
+
+// Start implements the Driver interface
+func (r *RangeSensorDriver) Start() (err error) { return }
+
+// Halt implements the Driver interface
+func (r *RangeSensorDriver) Halt() (err error) { return }
+
+// Range returns the measured distance, the maximum range when nothing is
+// within it
+func (r *RangeSensorDriver) Range() (gobot.Distance, error) {
+	d := r.connection.world.Range(r.angle.Radians(), r.maxRange.Meters())
+	return gobot.Distance(d) * gobot.Meter, nil
+}
+
+// Distance returns the measured distance in centimeters, like the range
+// sensors of the drivers
+func (r *RangeSensorDriver) Distance() (int, error) {
+	d, err := r.Range()
+	return int(d.Centimeters()), err
+}
+
+// LidarDriver is a lidar of the simulated robot, scanning the walls and
+// obstacles around it like a serial.RPLidarDriver.
+type LidarDriver struct {
+	// Samples is the number of measurements of each scan, 360 by default
+	Samples int
+
+	name       string
+	maxRange   gobot.Distance
+	interval   time.Duration
+	connection *Adaptor
+	halt       chan bool
+	gobot.Eventer
+}
+
+// NewLidarDriver returns a new LidarDriver for the robot of the Adaptor,
+// measuring up to maxRange.
+//
+// Optionally accepts:
+// 	time.Duration: Interval at which the scans are published, 100ms by default
+func NewLidarDriver(a *Adaptor, maxRange gobot.Distance, v ...time.Duration) *LidarDriver {
+	l := &LidarDriver{
+		Samples:    360,
+		name:       gobot.DefaultName("SimLidar"),
+		maxRange:   maxRange,
+		interval:   100 * time.Millisecond,
+		connection: a,
+		halt:       make(chan bool),
+		Eventer:    gobot.NewEventer(),
+	}
+
+	if len(v) > 0 {
+		l.interval = v[0]
+	}
+
+	l.AddEvent(Scan)
+
+	return l
+}
+
+// Name returns the LidarDrivers name
+func (l *LidarDriver) Name() string { return l.name }
+
+// SetName sets the LidarDrivers name
+func (l *LidarDriver) SetName(n string) { l.name = n }
+
+// Connection returns the LidarDrivers Connection
+func (l *LidarDriver) Connection() gobot.Connection { return l.connection }
+
+// Start starts scanning at the interval of the Driver
+//
+// Emits the Events:
+// 	Scan []mapping.Range - The measurements of each scan
+func (l *LidarDriver) Start() (err error) {
+	go func() {
+		clock := gobot.DefaultClock()
+		for {
+			select {
+			case <-clock.After(l.interval):
+				l.Publish(Scan, l.Scan())
+			case <-l.halt:
+				return
+			}
+		}
+	}()
+	return
+}
+
+// Halt stops scanning
+func (l *LidarDriver) Halt() (err error) {
+	l.halt <- true
+	return
+}
+
+// Scan returns the measurements of a scan, the ones finding nothing within
+// the maximum range being dropped
+func (l *LidarDriver) Scan() []mapping.Range {
+	return l.connection.world.Scan(l.Samples, l.maxRange.Meters())
+}
@@ -0,0 +1,68 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/mapping"
+)
+
+var _ gobot.Driver = (*RangeSensorDriver)(nil)
+var _ gobot.DistanceSensor = (*RangeSensorDriver)(nil)
+var _ gobot.Driver = (*LidarDriver)(nil)
+
+func TestRangeSensorDriver(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	a.World().AddObstacle(1, 0, 0.2)
+	front := NewRangeSensorDriver(a, 0, 2*gobot.Meter)
+	left := NewRangeSensorDriver(a, 90*gobot.Degree, 150*gobot.Centimeter)
+	gobottest.Assert(t, front.Start(), nil)
+
+	d, err := front.Range()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, d.String(), "0.8m")
+	cm, err := front.Distance()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, cm, 80)
+
+	d, _ = left.Range()
+	gobottest.Assert(t, d.String(), "1.5m")
+	gobottest.Assert(t, front.Halt(), nil)
+}
+
+func TestRangeSensorDriverNoise(t *testing.T) {
+	a := NewAdaptor(newTestWorld())
+	a.World().SetNoise(Noise{Seed: 3, Range: 0.01})
+	front := NewRangeSensorDriver(a, 0, 5*gobot.Meter)
+
+	d, _ := front.Range()
+	gobottest.Refute(t, d, 2*gobot.Meter)
+	gobottest.Assert(t, math.Abs(d.Meters()-2) < 0.05, true)
+}
+
+func TestLidarDriver(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Now())
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := NewAdaptor(newTestWorld())
+	a.World().SetPose(control.Pose{X: 1})
+	l := NewLidarDriver(a, 2500*gobot.Millimeter)
+	l.Samples = 4
+	scans := make(chan []mapping.Range, 1)
+	l.On(Scan, func(data interface{}) {
+		scans <- data.([]mapping.Range)
+	})
+
+	gobottest.Assert(t, l.Start(), nil)
+	clock.BlockUntil(1)
+	clock.Advance(100 * time.Millisecond)
+	scan := <-scans
+	gobottest.Assert(t, len(scan), 3)
+	gobottest.Assert(t, round(scan[0].Distance), 1.0)
+	gobottest.Assert(t, l.Halt(), nil)
+}
@@ -0,0 +1,338 @@
+package sim
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/mapping"
+)
+
+// Side is a side of the robot, naming the wheel of a MotorDriver or an
+// EncoderDriver
+type Side string
+
+// Sides of the robot
+const (
+	Left  Side = "left"
+	Right Side = "right"
+)
+
+// Noise models the errors of the simulated hardware. Every error is drawn
+// from a normal distribution of the given standard deviation, by a generator
+// seeded with Seed, so that a simulation is repeated exactly.
+type Noise struct {
+	// Seed seeds the generator of the errors
+	Seed int64
+	// Wheel is the relative error of the distance covered by each wheel, the
+	// wheels slipping and their radius not being exactly the one of the
+	// DifferentialDrive
+	Wheel float64
+	// Range is the error of the distances measured by the range sensors, in
+	// meters
+	Range float64
+}
+
+// wall is a segment the robot can not cross
+type wall struct {
+	x0, y0, x1, y1 float64
+}
+
+// obstacle is a disc the robot can not cross
+type obstacle struct {
+	x, y, radius float64
+}
+
+// World is a deterministic 2D world of walls and round obstacles, in which
+// a round differential drive robot moves. Distances are in meters, angles in
+// radians and counterclockwise, like the ones of the control package.
+//
+// The world only moves on Step, each Step advancing the time by the given
+// duration whatever the time it takes, so that a simulation gives the same
+// results on any machine.
+type World struct {
+	drive       *control.DifferentialDrive
+	radius      float64
+	pose        control.Pose
+	left, right float64
+	traveled    [2]float64
+	walls       []wall
+	obstacles   []obstacle
+	noise       Noise
+	rand        *rand.Rand
+	collided    bool
+	elapsed     time.Duration
+	mutex       sync.Mutex
+}
+
+// NewWorld returns a new empty World with a robot of the given radius,
+// driven by drive, at the origin. The MaxWheelSpeed of drive is the speed of
+// the wheels driven at full speed, and its TicksPerRevolution the resolution
+// of the simulated encoders.
+func NewWorld(drive *control.DifferentialDrive, radius float64) *World {
+	return &World{
+		drive:  drive,
+		radius: radius,
+		rand:   rand.New(rand.NewSource(0)),
+	}
+}
+
+// Drive returns the DifferentialDrive of the robot
+func (w *World) Drive() *control.DifferentialDrive { return w.drive }
+
+// SetNoise sets the Noise of the simulated hardware, and reseeds its
+// generator.
+func (w *World) SetNoise(n Noise) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.noise = n
+	w.rand = rand.New(rand.NewSource(n.Seed))
+}
+
+// AddWall adds a wall from x0, y0 to x1, y1.
+func (w *World) AddWall(x0, y0, x1, y1 float64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.walls = append(w.walls, wall{x0, y0, x1, y1})
+}
+
+// AddBox adds the four walls of the rectangle from x0, y0 to x1, y1, such as
+// the room around the robot.
+func (w *World) AddBox(x0, y0, x1, y1 float64) {
+	w.AddWall(x0, y0, x1, y0)
+	w.AddWall(x1, y0, x1, y1)
+	w.AddWall(x1, y1, x0, y1)
+	w.AddWall(x0, y1, x0, y0)
+}
+
+// AddObstacle adds a round obstacle of the given radius centered on x, y.
+func (w *World) AddObstacle(x, y, radius float64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.obstacles = append(w.obstacles, obstacle{x, y, radius})
+}
+
+// Pose returns the true Pose of the robot, which a PoseSource estimating it,
+// such as an Odometry fed by the EncoderDrivers, can be checked against.
+func (w *World) Pose() control.Pose {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.pose
+}
+
+// SetPose moves the robot to p.
+func (w *World) SetPose(p control.Pose) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	p.Theta = gobot.Angle(p.Theta).Normalize().Radians()
+	w.pose = p
+}
+
+// Elapsed returns the simulated time, the sum of the durations of the Steps
+func (w *World) Elapsed() time.Duration {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.elapsed
+}
+
+// Collided returns whether the robot bumped into a wall or an obstacle on
+// the last Step.
+func (w *World) Collided() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.collided
+}
+
+// SetWheel sets the speed of the wheel on side, between -1 and 1 relative to
+// the MaxWheelSpeed of the DifferentialDrive.
+func (w *World) SetWheel(side Side, speed float64) {
+	speed = math.Max(-1, math.Min(1, speed))
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if side == Left {
+		w.left = speed
+	} else {
+		w.right = speed
+	}
+}
+
+// Wheel returns the speed of the wheel on side, between -1 and 1.
+func (w *World) Wheel(side Side) float64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if side == Left {
+		return w.left
+	}
+	return w.right
+}
+
+// Traveled returns the distance covered by the wheel on side since the
+// creation of the World, negative when moving backward. The wheels keep
+// turning when the robot is stuck against a wall, like real ones slipping.
+func (w *World) Traveled(side Side) float64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.traveled[sideIndex(side)]
+}
+
+// Step moves the robot for d at the speeds of its wheels, and returns whether
+// it bumped into a wall or an obstacle, in which case it does not move. The
+// wheels are assumed to reach their speeds at once.
+func (w *World) Step(d time.Duration) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	dt := d.Seconds()
+	w.elapsed += d
+	left := w.left * w.drive.MaxWheelSpeed * dt
+	right := w.right * w.drive.MaxWheelSpeed * dt
+	w.traveled[0] += left
+	w.traveled[1] += right
+
+	// the robot moves by the distances the wheels really covered, which the
+	// encoders do not know
+	if w.noise.Wheel > 0 {
+		left *= 1 + w.rand.NormFloat64()*w.noise.Wheel
+		right *= 1 + w.rand.NormFloat64()*w.noise.Wheel
+	}
+	distance, dtheta := w.drive.Velocity(left, right)
+	heading := w.pose.Theta + dtheta/2
+	x := w.pose.X + distance*math.Cos(heading)
+	y := w.pose.Y + distance*math.Sin(heading)
+
+	w.collided = w.collides(x, y)
+	if !w.collided {
+		w.pose.X, w.pose.Y = x, y
+	}
+	w.pose.Theta = gobot.Angle(w.pose.Theta + dtheta).Normalize().Radians()
+	return w.collided
+}
+
+// Cast returns the distance to the nearest wall or obstacle from x, y along
+// heading, and false when there is none within maxRange.
+func (w *World) Cast(x, y, heading, maxRange float64) (float64, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.cast(x, y, heading, maxRange)
+}
+
+// Range returns the distance measured by a range sensor of the robot,
+// pointing at angle from its heading, with the Range Noise: maxRange when
+// nothing is within maxRange.
+func (w *World) Range(angle, maxRange float64) float64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.measure(angle, maxRange)
+}
+
+// Scan returns the distances measured by a lidar of the robot, with n
+// measurements evenly spread around it from its heading. The measurements
+// finding nothing within maxRange are dropped, like the ones of a real lidar.
+func (w *World) Scan(n int, maxRange float64) []mapping.Range {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	ranges := make([]mapping.Range, 0, n)
+	for i := 0; i < n; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		if distance := w.measure(angle, maxRange); distance < maxRange {
+			ranges = append(ranges, mapping.Range{Angle: angle, Distance: distance})
+		}
+	}
+	return ranges
+}
+
+// measure returns the distance measured along angle from the heading of the
+// robot, from its center
+func (w *World) measure(angle, maxRange float64) float64 {
+	distance, ok := w.cast(w.pose.X, w.pose.Y, w.pose.Theta+angle, maxRange)
+	if !ok {
+		return maxRange
+	}
+	if w.noise.Range > 0 {
+		distance += w.rand.NormFloat64() * w.noise.Range
+	}
+	return math.Max(0, math.Min(maxRange, distance))
+}
+
+func (w *World) cast(x, y, heading, maxRange float64) (float64, bool) {
+	dx, dy := math.Cos(heading), math.Sin(heading)
+	nearest := math.Inf(1)
+
+	for _, l := range w.walls {
+		// solve x + t*dx = x0 + u*(x1-x0), y + t*dy = y0 + u*(y1-y0)
+		ex, ey := l.x1-l.x0, l.y1-l.y0
+		denominator := dx*ey - dy*ex
+		if denominator == 0 {
+			continue
+		}
+		fx, fy := l.x0-x, l.y0-y
+		t := (fx*ey - fy*ex) / denominator
+		u := (fx*dy - fy*dx) / denominator
+		if t >= 0 && u >= 0 && u <= 1 && t < nearest {
+			nearest = t
+		}
+	}
+	for _, o := range w.obstacles {
+		fx, fy := x-o.x, y-o.y
+		b := fx*dx + fy*dy
+		c := fx*fx + fy*fy - o.radius*o.radius
+		discriminant := b*b - c
+		if discriminant < 0 {
+			continue
+		}
+		t := -b - math.Sqrt(discriminant)
+		if t < 0 {
+			if -b+math.Sqrt(discriminant) < 0 {
+				// behind
+				continue
+			}
+			// inside the obstacle
+			t = 0
+		}
+		if t < nearest {
+			nearest = t
+		}
+	}
+
+	if nearest > maxRange {
+		return maxRange, false
+	}
+	return nearest, true
+}
+
+// collides returns whether the robot centered on x, y overlaps a wall or an
+// obstacle
+func (w *World) collides(x, y float64) bool {
+	for _, l := range w.walls {
+		if segmentDistance(x, y, l) < w.radius {
+			return true
+		}
+	}
+	for _, o := range w.obstacles {
+		if math.Hypot(x-o.x, y-o.y) < w.radius+o.radius {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentDistance returns the distance from x, y to the nearest point of l
+func segmentDistance(x, y float64, l wall) float64 {
+	ex, ey := l.x1-l.x0, l.y1-l.y0
+	u := 0.0
+	if length := ex*ex + ey*ey; length > 0 {
+		u = math.Max(0, math.Min(1, ((x-l.x0)*ex+(y-l.y0)*ey)/length))
+	}
+	return math.Hypot(x-l.x0-u*ex, y-l.y0-u*ey)
+}
+
+func sideIndex(side Side) int {
+	if side == Left {
+		return 0
+	}
+	return 1
+}
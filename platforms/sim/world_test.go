@@ -0,0 +1,152 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/control"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func round(v float64) float64 { return math.Round(v*1000) / 1000 }
+
+func TestWorldStepStraight(t *testing.T) {
+	w := newTestWorld()
+	w.SetWheel(Left, 1)
+	w.SetWheel(Right, 1)
+	run(w, time.Second)
+
+	pose := w.Pose()
+	gobottest.Assert(t, round(pose.X), 0.5)
+	gobottest.Assert(t, round(pose.Y), 0.0)
+	gobottest.Assert(t, round(pose.Theta), 0.0)
+	gobottest.Assert(t, w.Elapsed(), time.Second)
+	gobottest.Assert(t, round(w.Traveled(Left)), 0.5)
+}
+
+func TestWorldStepTurn(t *testing.T) {
+	w := newTestWorld()
+	// a wheel at 0.1m/s from the center turns at 1rad/s
+	w.SetWheel(Left, -0.2)
+	w.SetWheel(Right, 0.2)
+	run(w, 1500*time.Millisecond)
+
+	pose := w.Pose()
+	gobottest.Assert(t, round(pose.X), 0.0)
+	gobottest.Assert(t, round(pose.Theta), 1.5)
+	gobottest.Assert(t, round(w.Traveled(Left)), -0.15)
+}
+
+func TestWorldSetWheelBounds(t *testing.T) {
+	w := newTestWorld()
+	w.SetWheel(Left, 2)
+	w.SetWheel(Right, -3)
+	gobottest.Assert(t, w.Wheel(Left), 1.0)
+	gobottest.Assert(t, w.Wheel(Right), -1.0)
+}
+
+func TestWorldCollision(t *testing.T) {
+	w := newTestWorld()
+	w.SetWheel(Left, 1)
+	w.SetWheel(Right, 1)
+	run(w, 5*time.Second)
+
+	// the robot stops touching the wall at x = 2, its wheels still turning
+	gobottest.Assert(t, w.Collided(), true)
+	gobottest.Assert(t, w.Pose().X < 1.9, true)
+	gobottest.Assert(t, w.Pose().X > 1.89, true)
+	gobottest.Assert(t, round(w.Traveled(Right)), 2.5)
+
+	w.SetWheel(Left, -1)
+	w.SetWheel(Right, -1)
+	gobottest.Assert(t, w.Step(10*time.Millisecond), false)
+	gobottest.Assert(t, w.Collided(), false)
+}
+
+func TestWorldObstacle(t *testing.T) {
+	w := newTestWorld()
+	w.AddObstacle(1, 0, 0.2)
+	w.SetWheel(Left, 1)
+	w.SetWheel(Right, 1)
+	run(w, 3*time.Second)
+
+	gobottest.Assert(t, w.Collided(), true)
+	gobottest.Assert(t, w.Pose().X < 0.7, true)
+	gobottest.Assert(t, w.Pose().X > 0.69, true)
+}
+
+func TestWorldCast(t *testing.T) {
+	w := newTestWorld()
+	w.AddObstacle(1, 0, 0.2)
+
+	d, ok := w.Cast(0, 0, 0, 5)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, round(d), 0.8)
+
+	d, ok = w.Cast(0, 0, math.Pi/2, 5)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, round(d), 2.0)
+
+	d, ok = w.Cast(0, 0, math.Pi/4, 5)
+	gobottest.Assert(t, ok, true)
+	gobottest.Assert(t, round(d), round(2*math.Sqrt2))
+
+	d, ok = w.Cast(0, 0, math.Pi, 1)
+	gobottest.Assert(t, ok, false)
+	gobottest.Assert(t, d, 1.0)
+}
+
+func TestWorldRange(t *testing.T) {
+	w := newTestWorld()
+	w.SetPose(control.Pose{X: 1, Theta: math.Pi / 2})
+
+	gobottest.Assert(t, round(w.Range(0, 5)), 2.0)
+	gobottest.Assert(t, round(w.Range(-math.Pi/2, 5)), 1.0)
+	gobottest.Assert(t, w.Range(0, 1.5), 1.5)
+}
+
+func TestWorldScan(t *testing.T) {
+	w := newTestWorld()
+	w.SetPose(control.Pose{X: 1})
+
+	ranges := w.Scan(4, 2.5)
+	// the wall behind is 3m away
+	gobottest.Assert(t, len(ranges), 3)
+	gobottest.Assert(t, round(ranges[0].Distance), 1.0)
+	gobottest.Assert(t, round(ranges[1].Angle), round(math.Pi/2))
+	gobottest.Assert(t, round(ranges[1].Distance), 2.0)
+	gobottest.Assert(t, round(ranges[2].Distance), 2.0)
+}
+
+func TestWorldNoiseRepeatable(t *testing.T) {
+	simulate := func(seed int64) (control.Pose, float64) {
+		w := newTestWorld()
+		w.SetNoise(Noise{Seed: seed, Wheel: 0.05, Range: 0.02})
+		w.SetWheel(Left, 0.8)
+		w.SetWheel(Right, 1)
+		run(w, 2*time.Second)
+		return w.Pose(), w.Range(0, 5)
+	}
+
+	pose, distance := simulate(42)
+	pose2, distance2 := simulate(42)
+	gobottest.Assert(t, pose2, pose)
+	gobottest.Assert(t, distance2, distance)
+
+	pose3, _ := simulate(7)
+	gobottest.Refute(t, pose3, pose)
+}
+
+func TestWorldNoiseOdometryDrift(t *testing.T) {
+	w := newTestWorld()
+	w.SetNoise(Noise{Seed: 1, Wheel: 0.1})
+	w.SetWheel(Left, 1)
+	w.SetWheel(Right, 1)
+	run(w, time.Second)
+
+	// the wheels turned exactly, the robot moved approximately
+	gobottest.Assert(t, round(w.Traveled(Left)), 0.5)
+	gobottest.Refute(t, round(w.Pose().X), 0.5)
+	gobottest.Assert(t, math.Abs(w.Pose().X-0.5) < 0.05, true)
+}
@@ -0,0 +1,146 @@
+package bb9e
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/platforms/ble"
+	"gobot.io/x/gobot/platforms/sphero"
+)
+
+const (
+	// BLE characteristic IDs
+	apiCharacteristic     = "00010002574f4f2053706865726f2121"
+	antiDosCharacteristic = "00020005574f4f2053706865726f2121"
+
+	// unlocks the commands of the BB-9E
+	antiDosUnlock = "usetheforce...band"
+
+	powerDevice  = 0x13
+	sleepCommand = 0x01
+	wakeCommand  = 0x0D
+
+	driveDevice             = 0x16
+	driveWithHeadingCommand = 0x07
+
+	ioDevice       = 0x1A
+	allLEDsCommand = 0x0E
+
+	// LEDs of the BB-9E, as bits of the mask of allLEDsCommand
+	bodyRedLED   = 0x01
+	bodyGreenLED = 0x02
+	bodyBlueLED  = 0x04
+	aimingLED    = 0x08
+	headLED      = 0x10
+)
+
+// BB9EDriver is the Gobot driver for the Sphero BB-9E, which speaks the
+// Sphero API v2 over Bluetooth LE
+type BB9EDriver struct {
+	name       string
+	connection gobot.Connection
+	seq        uint8
+	mtx        sync.Mutex
+	codec      *sphero.PacketV2Codec
+}
+
+// NewDriver creates a Driver for a Sphero BB-9E
+func NewDriver(a ble.BLEConnector) *BB9EDriver {
+	return &BB9EDriver{
+		name:       gobot.DefaultName("BB9E"),
+		connection: a,
+		codec:      sphero.NewPacketV2Codec(),
+	}
+}
+
+// Connection returns the connection to this BB-9E
+func (b *BB9EDriver) Connection() gobot.Connection { return b.connection }
+
+// Name returns the name for the Driver
+func (b *BB9EDriver) Name() string { return b.name }
+
+// SetName sets the Name for the Driver
+func (b *BB9EDriver) SetName(n string) { b.name = n }
+
+// adaptor returns BLE adaptor
+func (b *BB9EDriver) adaptor() ble.BLEConnector {
+	return b.Connection().(ble.BLEConnector)
+}
+
+// Start unlocks the BB-9E and wakes it up
+func (b *BB9EDriver) Start() (err error) {
+	err = b.adaptor().WriteCharacteristic(antiDosCharacteristic, []byte(antiDosUnlock))
+	if err != nil {
+		return
+	}
+	return b.Wake()
+}
+
+// Halt stops the BB-9E and puts it to sleep
+func (b *BB9EDriver) Halt() (err error) {
+	if err = b.Stop(); err != nil {
+		return
+	}
+	time.Sleep(750 * time.Microsecond)
+	return b.Sleep()
+}
+
+// Wake wakes the BB-9E up
+func (b *BB9EDriver) Wake() error {
+	return b.send(powerDevice, wakeCommand, nil)
+}
+
+// Sleep puts the BB-9E to sleep
+func (b *BB9EDriver) Sleep() error {
+	return b.send(powerDevice, sleepCommand, nil)
+}
+
+// Roll tells the BB-9E to roll at speed, with heading in degrees
+func (b *BB9EDriver) Roll(speed uint8, heading uint16) error {
+	return b.send(driveDevice, driveWithHeadingCommand,
+		[]byte{speed, uint8(heading >> 8), uint8(heading & 0xFF), 0x00})
+}
+
+// Stop tells the BB-9E to stop
+func (b *BB9EDriver) Stop() error {
+	return b.Roll(0, 0)
+}
+
+// SetRGB sets the body LED of the BB-9E to the given r, g, and b values
+func (b *BB9EDriver) SetRGB(r uint8, g uint8, bl uint8) error {
+	return b.setLEDs(bodyRedLED|bodyGreenLED|bodyBlueLED, r, g, bl)
+}
+
+// SetBackLED sets the aiming LED of the BB-9E to the given brightness
+func (b *BB9EDriver) SetBackLED(level uint8) error {
+	return b.setLEDs(aimingLED, level)
+}
+
+// SetHeadLED sets the LED of the head of the BB-9E to the given brightness
+func (b *BB9EDriver) SetHeadLED(level uint8) error {
+	return b.setLEDs(headLED, level)
+}
+
+func (b *BB9EDriver) setLEDs(mask uint16, values ...uint8) error {
+	return b.send(ioDevice, allLEDsCommand, append([]byte{uint8(mask >> 8), uint8(mask & 0xFF)}, values...))
+}
+
+func (b *BB9EDriver) send(device, command uint8, data []byte) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.seq++
+	p := &sphero.PacketV2{
+		Flags:     sphero.FlagRequestsOnlyErrorResponse | sphero.FlagIsActivity,
+		DeviceID:  device,
+		CommandID: command,
+		Sequence:  b.seq,
+		Data:      data,
+	}
+	buf, err := b.codec.Encode(p.Marshal())
+	if err != nil {
+		return err
+	}
+	return b.adaptor().WriteCharacteristic(apiCharacteristic, buf)
+}
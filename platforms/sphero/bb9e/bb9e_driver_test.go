@@ -0,0 +1,86 @@
+package bb9e
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/platforms/sphero"
+)
+
+var _ gobot.Driver = (*BB9EDriver)(nil)
+
+func initTestBB9EDriver() (*BB9EDriver, *[]*sphero.PacketV2) {
+	a := NewBleTestAdaptor()
+	packets := &[]*sphero.PacketV2{}
+	a.TestWriteCharacteristic(func(cUUID string, data []byte) error {
+		if cUUID != apiCharacteristic {
+			return nil
+		}
+		frame, err := sphero.NewPacketV2Codec().Decode(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return err
+		}
+		p, err := sphero.UnmarshalPacketV2(frame)
+		if err != nil {
+			return err
+		}
+		*packets = append(*packets, p)
+		return nil
+	})
+	return NewDriver(a), packets
+}
+
+func lastPacket(packets *[]*sphero.PacketV2) *sphero.PacketV2 {
+	return (*packets)[len(*packets)-1]
+}
+
+func TestBB9EDriver(t *testing.T) {
+	d, _ := initTestBB9EDriver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "BB9E"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+}
+
+func TestBB9EDriverStartAndHalt(t *testing.T) {
+	d, packets := initTestBB9EDriver()
+	gobottest.Assert(t, d.Start(), nil)
+	gobottest.Assert(t, lastPacket(packets).CommandID, uint8(wakeCommand))
+
+	gobottest.Assert(t, d.Halt(), nil)
+	gobottest.Assert(t, lastPacket(packets).CommandID, uint8(sleepCommand))
+}
+
+func TestBB9EDriverStartError(t *testing.T) {
+	a := NewBleTestAdaptor()
+	a.TestWriteCharacteristic(func(cUUID string, data []byte) error {
+		return errors.New("write error")
+	})
+	d := NewDriver(a)
+	gobottest.Assert(t, d.Start(), errors.New("write error"))
+}
+
+func TestBB9EDriverRoll(t *testing.T) {
+	d, packets := initTestBB9EDriver()
+	gobottest.Assert(t, d.Roll(0x40, 270), nil)
+	p := lastPacket(packets)
+	gobottest.Assert(t, p.DeviceID, uint8(driveDevice))
+	gobottest.Assert(t, p.CommandID, uint8(driveWithHeadingCommand))
+	gobottest.Assert(t, p.Data, []byte{0x40, 0x01, 0x0E, 0x00})
+}
+
+func TestBB9EDriverLEDs(t *testing.T) {
+	d, packets := initTestBB9EDriver()
+	gobottest.Assert(t, d.SetRGB(1, 2, 3), nil)
+	gobottest.Assert(t, lastPacket(packets).Data, []byte{0x00, 0x07, 1, 2, 3})
+
+	gobottest.Assert(t, d.SetBackLED(4), nil)
+	gobottest.Assert(t, lastPacket(packets).Data, []byte{0x00, 0x08, 4})
+
+	gobottest.Assert(t, d.SetHeadLED(5), nil)
+	gobottest.Assert(t, lastPacket(packets).Data, []byte{0x00, 0x10, 5})
+}
@@ -0,0 +1,7 @@
+/*
+Package bb9e contains the Gobot driver for the Sphero BB-9E.
+
+For more information refer to the BB-9E README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/sphero/bb9e/README.md
+*/
+package bb9e // import "gobot.io/x/gobot/platforms/sphero/bb9e"
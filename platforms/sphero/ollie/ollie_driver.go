@@ -3,7 +3,6 @@ package ollie
 import (
 	"bytes"
 	"encoding/binary"
-	"fmt"
 	"sync"
 	"time"
 
@@ -16,6 +15,7 @@ import (
 type Driver struct {
 	name              string
 	connection        gobot.Connection
+	logger            gobot.Logger
 	seq               uint8
 	mtx               sync.Mutex
 	collisionResponse []uint8
@@ -86,6 +86,17 @@ func (b *Driver) Name() string { return b.name }
 // SetName sets the Name for the Driver
 func (b *Driver) SetName(n string) { b.name = n }
 
+// Logger returns the Driver Logger
+func (b *Driver) Logger() gobot.Logger {
+	if b.logger != nil {
+		return b.logger
+	}
+	return gobot.DefaultLogger().WithComponent(b.name)
+}
+
+// SetLogger sets the Driver Logger
+func (b *Driver) SetLogger(l gobot.Logger) { b.logger = l }
+
 // adaptor returns BLE adaptor
 func (b *Driver) adaptor() ble.BLEConnector {
 	return b.Connection().(ble.BLEConnector)
@@ -145,7 +156,7 @@ func (b *Driver) AntiDOSOff() (err error) {
 
 	err = b.adaptor().WriteCharacteristic(antiDosCharacteristic, buf.Bytes())
 	if err != nil {
-		fmt.Println("AntiDOSOff error:", err)
+		b.Logger().Error("AntiDOSOff error", "error", err)
 		return err
 	}
 
@@ -158,7 +169,7 @@ func (b *Driver) Wake() (err error) {
 
 	err = b.adaptor().WriteCharacteristic(wakeCharacteristic, buf)
 	if err != nil {
-		fmt.Println("Wake error:", err)
+		b.Logger().Error("Wake error", "error", err)
 		return err
 	}
 
@@ -171,7 +182,7 @@ func (b *Driver) SetTXPower(level int) (err error) {
 
 	err = b.adaptor().WriteCharacteristic(txPowerCharacteristic, buf)
 	if err != nil {
-		fmt.Println("SetTXLevel error:", err)
+		b.Logger().Error("SetTXPower error", "error", err)
 		return err
 	}
 
@@ -180,8 +191,6 @@ func (b *Driver) SetTXPower(level int) (err error) {
 
 // HandleResponses handles responses returned from Ollie
 func (b *Driver) HandleResponses(data []byte, e error) {
-	//fmt.Println("response data:", data, e)
-
 	b.handleCollisionDetected(data)
 }
 
@@ -220,7 +229,7 @@ func (b *Driver) write(packet *packet) (err error) {
 	buf = append(buf, packet.checksum)
 	err = b.adaptor().WriteCharacteristic(commandsCharacteristic, buf)
 	if err != nil {
-		fmt.Println("send command error:", err)
+		b.Logger().Error("send command error", "error", err)
 		return err
 	}
 
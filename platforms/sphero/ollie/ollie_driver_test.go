@@ -1,6 +1,7 @@
 package ollie
 
 import (
+	"errors"
 	"testing"
 
 	"gobot.io/x/gobot"
@@ -20,6 +21,33 @@ func TestOllieDriver(t *testing.T) {
 	gobottest.Assert(t, d.Name(), "NewName")
 }
 
+func TestOllieDriverLogger(t *testing.T) {
+	d := initTestOllieDriver()
+	gobottest.Refute(t, d.Logger(), nil)
+	l := gobot.NewLogger()
+	d.SetLogger(l)
+	gobottest.Assert(t, d.Logger(), l)
+}
+
+// errorLogger records the messages logged at LogError level
+type errorLogger struct {
+	gobot.Logger
+	errors []string
+}
+
+func (l *errorLogger) Error(msg string, keyvals ...interface{}) { l.errors = append(l.errors, msg) }
+
+func TestOllieDriverWakeErrorLogged(t *testing.T) {
+	a := NewBleTestAdaptor()
+	a.TestWriteCharacteristic(func(string, []byte) error { return errors.New("write error") })
+	d := NewDriver(a)
+	l := &errorLogger{Logger: gobot.NewLogger()}
+	d.SetLogger(l)
+
+	gobottest.Assert(t, d.Wake(), errors.New("write error"))
+	gobottest.Assert(t, l.errors, []string{"Wake error"})
+}
+
 func TestOllieDriverStartAndHalt(t *testing.T) {
 	d := initTestOllieDriver()
 	gobottest.Assert(t, d.Start(), nil)
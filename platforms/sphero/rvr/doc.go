@@ -0,0 +1,7 @@
+/*
+Package rvr contains the Gobot adaptor and driver for the Sphero RVR.
+
+For more information refer to the RVR README:
+https://github.com/hybridgroup/gobot/blob/master/platforms/sphero/rvr/README.md
+*/
+package rvr // import "gobot.io/x/gobot/platforms/sphero/rvr"
@@ -0,0 +1,398 @@
+package rvr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/platforms/serial"
+	"gobot.io/x/gobot/platforms/sphero"
+)
+
+const (
+	// SensorData event, published with the map of the streamed sensor
+	// values by name, e.g. "IMU.Yaw"
+	SensorData = "sensordata"
+
+	// Error event, published with the errors reported by the RVR
+	Error = "error"
+)
+
+// ErrResponseTimeout is returned when the RVR does not answer a request.
+var ErrResponseTimeout = errors.New("rvr: response timeout")
+
+// responseTimeout is how long a request waits for its response
+var responseTimeout = 1 * time.Second
+
+const (
+	// processors of the RVR, targets of the commands
+	nordicTarget = 0x11
+	stTarget     = 0x12
+
+	// source of the commands
+	hostSource = 0x01
+
+	powerDevice              = 0x13
+	sleepCommand             = 0x01
+	wakeCommand              = 0x0D
+	batteryPercentageCommand = 0x10
+
+	driveDevice             = 0x16
+	rawMotorsCommand        = 0x01
+	resetYawCommand         = 0x06
+	driveWithHeadingCommand = 0x07
+
+	sensorDevice              = 0x18
+	configureStreamingCommand = 0x39
+	startStreamingCommand     = 0x3A
+	stopStreamingCommand      = 0x3B
+	clearStreamingCommand     = 0x3C
+	streamingDataCommand      = 0x3D
+
+	ioDevice       = 0x1A
+	allLEDsCommand = 0x1A
+
+	// token identifying the streaming slot of each processor
+	streamingToken = 0x01
+	// size of the streamed values, 32 bits
+	streamingDataSize = 0x02
+	// number of LED channels, 3 colors for each of the 10 LEDs
+	ledChannels = 30
+)
+
+// Driver is the Gobot driver for the Sphero RVR, connected with the serial
+// port of its expansion header.
+type Driver struct {
+	name       string
+	connection *serial.Adaptor
+	mtx        sync.Mutex
+	seq        uint8
+	heading    uint16
+	responses  map[uint8]chan *sphero.PacketV2
+	streaming  map[uint8][]SensorService
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewAdaptor returns a serial Adaptor for the RVR connected to port, such as
+// "/dev/ttyS0" on a Raspberry Pi.
+func NewAdaptor(port string) *serial.Adaptor {
+	a := serial.NewAdaptor(port, 115200, sphero.NewPacketV2Codec())
+	a.SetName(gobot.DefaultName("RVR"))
+	return a
+}
+
+// NewDriver returns a new Driver for the Sphero RVR given a serial Adaptor
+// created by NewAdaptor.
+//
+// Adds the following API Commands:
+// 	"Drive" - See Driver.Drive
+// 	"Stop" - See Driver.Stop
+// 	"SetRGB" - See Driver.SetRGB
+// 	"Wake" - See Driver.Wake
+// 	"Sleep" - See Driver.Sleep
+func NewDriver(a *serial.Adaptor) *Driver {
+	d := &Driver{
+		name:       gobot.DefaultName("RVR"),
+		connection: a,
+		responses:  map[uint8]chan *sphero.PacketV2{},
+		streaming:  map[uint8][]SensorService{},
+		Eventer:    gobot.NewEventer(),
+		Commander:  gobot.NewCommander(),
+	}
+
+	d.AddEvent(SensorData)
+	d.AddEvent(Error)
+
+	d.AddCommand("Drive", func(params map[string]interface{}) interface{} {
+		speed := uint8(params["speed"].(float64))
+		heading := uint16(params["heading"].(float64))
+		return d.Drive(speed, heading)
+	})
+
+	d.AddCommand("Stop", func(params map[string]interface{}) interface{} {
+		return d.Stop()
+	})
+
+	d.AddCommand("SetRGB", func(params map[string]interface{}) interface{} {
+		r := uint8(params["r"].(float64))
+		g := uint8(params["g"].(float64))
+		b := uint8(params["b"].(float64))
+		return d.SetRGB(r, g, b)
+	})
+
+	d.AddCommand("Wake", func(params map[string]interface{}) interface{} {
+		return d.Wake()
+	})
+
+	d.AddCommand("Sleep", func(params map[string]interface{}) interface{} {
+		return d.Sleep()
+	})
+
+	return d
+}
+
+// Name returns the Driver Name
+func (d *Driver) Name() string { return d.name }
+
+// SetName sets the Driver Name
+func (d *Driver) SetName(n string) { d.name = n }
+
+// Connection returns the Driver's Connection
+func (d *Driver) Connection() gobot.Connection { return d.connection }
+
+// Start wakes the RVR up and starts handling its responses.
+//
+// Emits the Events:
+// 	SensorData map[string]float32 - On sensor streaming data
+// 	Error      error - On error reported by the RVR
+func (d *Driver) Start() (err error) {
+	if err = d.connection.On(serial.Frame, d.handleFrame); err != nil {
+		return
+	}
+	return d.Wake()
+}
+
+// Halt stops the RVR
+func (d *Driver) Halt() (err error) {
+	return d.Stop()
+}
+
+// Wake wakes the RVR up
+func (d *Driver) Wake() error {
+	return d.send(nordicTarget, powerDevice, wakeCommand, nil)
+}
+
+// Sleep puts the RVR to sleep
+func (d *Driver) Sleep() error {
+	return d.send(nordicTarget, powerDevice, sleepCommand, nil)
+}
+
+// BatteryPercentage returns the battery charge, in percent
+func (d *Driver) BatteryPercentage() (uint8, error) {
+	p, err := d.request(nordicTarget, powerDevice, batteryPercentageCommand, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(p.Data) < 1 {
+		return 0, sphero.ErrInvalidPacket
+	}
+	return p.Data[0], nil
+}
+
+// Drive drives the RVR forward at speed, 0 to 255, with heading in degrees
+// relative to the yaw reset by ResetYaw
+func (d *Driver) Drive(speed uint8, heading uint16) error {
+	d.mtx.Lock()
+	d.heading = heading
+	d.mtx.Unlock()
+
+	return d.send(stTarget, driveDevice, driveWithHeadingCommand,
+		[]byte{speed, uint8(heading >> 8), uint8(heading & 0xFF), 0x00})
+}
+
+// Stop stops the RVR, keeping its last heading
+func (d *Driver) Stop() error {
+	d.mtx.Lock()
+	heading := d.heading
+	d.mtx.Unlock()
+
+	return d.Drive(0, heading)
+}
+
+// SetRawMotors sets the speed of the left and right treads, from -255 for
+// full speed backward to 255 for full speed forward
+func (d *Driver) SetRawMotors(left, right int) error {
+	leftMode, leftSpeed := rawMotor(left)
+	rightMode, rightSpeed := rawMotor(right)
+	return d.send(stTarget, driveDevice, rawMotorsCommand,
+		[]byte{leftMode, leftSpeed, rightMode, rightSpeed})
+}
+
+// rawMotor returns the mode, off, forward or backward, and speed of a tread
+func rawMotor(speed int) (mode uint8, value uint8) {
+	switch {
+	case speed > 0:
+		mode = 0x01
+	case speed < 0:
+		mode, speed = 0x02, -speed
+	}
+	if speed > 255 {
+		speed = 255
+	}
+	return mode, uint8(speed)
+}
+
+// ResetYaw makes the current heading of the RVR the 0 heading
+func (d *Driver) ResetYaw() error {
+	return d.send(stTarget, driveDevice, resetYawCommand, nil)
+}
+
+// SetRGB sets all the LEDs of the RVR to the given r, g, and b values
+func (d *Driver) SetRGB(r uint8, g uint8, b uint8) error {
+	data := []byte{0x3F, 0xFF, 0xFF, 0xFF}
+	for i := 0; i < ledChannels/3; i++ {
+		data = append(data, r, g, b)
+	}
+	return d.send(nordicTarget, ioDevice, allLEDsCommand, data)
+}
+
+// StartSensorStreaming streams the values of services every period, which
+// are published as SensorData events
+func (d *Driver) StartSensorStreaming(period time.Duration, services ...SensorService) error {
+	if err := d.StopSensorStreaming(); err != nil {
+		return err
+	}
+
+	byProcessor := map[uint8][]SensorService{}
+	for _, s := range services {
+		byProcessor[s.processor] = append(byProcessor[s.processor], s)
+	}
+
+	d.mtx.Lock()
+	d.streaming = byProcessor
+	d.mtx.Unlock()
+
+	ms := uint16(period / time.Millisecond)
+	for _, target := range []uint8{nordicTarget, stTarget} {
+		if len(byProcessor[target]) == 0 {
+			continue
+		}
+		data := []byte{streamingToken}
+		for _, s := range byProcessor[target] {
+			data = append(data, uint8(s.ID>>8), uint8(s.ID&0xFF), streamingDataSize)
+		}
+		if err := d.send(target, sensorDevice, configureStreamingCommand, data); err != nil {
+			return err
+		}
+		if err := d.send(target, sensorDevice, startStreamingCommand, []byte{uint8(ms >> 8), uint8(ms & 0xFF)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StopSensorStreaming stops streaming the sensor values
+func (d *Driver) StopSensorStreaming() error {
+	for _, target := range []uint8{nordicTarget, stTarget} {
+		if err := d.send(target, sensorDevice, stopStreamingCommand, nil); err != nil {
+			return err
+		}
+		if err := d.send(target, sensorDevice, clearStreamingCommand, nil); err != nil {
+			return err
+		}
+	}
+
+	d.mtx.Lock()
+	d.streaming = map[uint8][]SensorService{}
+	d.mtx.Unlock()
+	return nil
+}
+
+// send sends a command, the RVR answering only on error
+func (d *Driver) send(target, device, command uint8, data []byte) error {
+	p := d.craftPacket(sphero.FlagRequestsOnlyErrorResponse, target, device, command, data)
+	return d.connection.WriteFrame(p.Marshal())
+}
+
+// request sends a command and waits for its response
+func (d *Driver) request(target, device, command uint8, data []byte) (*sphero.PacketV2, error) {
+	p := d.craftPacket(sphero.FlagRequestsResponse, target, device, command, data)
+	response := make(chan *sphero.PacketV2, 1)
+
+	d.mtx.Lock()
+	d.responses[p.Sequence] = response
+	d.mtx.Unlock()
+
+	defer func() {
+		d.mtx.Lock()
+		delete(d.responses, p.Sequence)
+		d.mtx.Unlock()
+	}()
+
+	if err := d.connection.WriteFrame(p.Marshal()); err != nil {
+		return nil, err
+	}
+
+	select {
+	case r := <-response:
+		if r.ErrorCode != 0 {
+			return nil, commandError(r)
+		}
+		return r, nil
+	case <-time.After(responseTimeout):
+		return nil, ErrResponseTimeout
+	}
+}
+
+func (d *Driver) craftPacket(flags, target, device, command uint8, data []byte) *sphero.PacketV2 {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.seq++
+	return &sphero.PacketV2{
+		Flags:     flags | sphero.FlagIsActivity | sphero.FlagHasTargetID | sphero.FlagHasSourceID,
+		TargetID:  target,
+		SourceID:  hostSource,
+		DeviceID:  device,
+		CommandID: command,
+		Sequence:  d.seq,
+		Data:      data,
+	}
+}
+
+// handleFrame handles a packet received from the RVR
+func (d *Driver) handleFrame(data interface{}) {
+	p, err := sphero.UnmarshalPacketV2(data.([]byte))
+	if err != nil {
+		d.Publish(Error, err)
+		return
+	}
+
+	if p.Flags&sphero.FlagIsResponse != 0 {
+		d.mtx.Lock()
+		response, ok := d.responses[p.Sequence]
+		d.mtx.Unlock()
+		if ok {
+			response <- p
+		} else if p.ErrorCode != 0 {
+			d.Publish(Error, commandError(p))
+		}
+		return
+	}
+
+	if p.DeviceID == sensorDevice && p.CommandID == streamingDataCommand {
+		d.handleSensorData(p)
+	}
+}
+
+// handleSensorData publishes the sensor values streamed by a processor
+func (d *Driver) handleSensorData(p *sphero.PacketV2) {
+	d.mtx.Lock()
+	services := d.streaming[p.SourceID]
+	d.mtx.Unlock()
+
+	if len(services) == 0 || len(p.Data) < 1 {
+		return
+	}
+	values := map[string]float32{}
+	buf := p.Data[1:]
+	for _, s := range services {
+		for _, f := range s.fields {
+			if len(buf) < 4 {
+				d.Publish(Error, sphero.ErrInvalidPacket)
+				return
+			}
+			values[s.Name+"."+f.name] = f.value(binary.BigEndian.Uint32(buf))
+			buf = buf[4:]
+		}
+	}
+	d.Publish(SensorData, values)
+}
+
+func commandError(p *sphero.PacketV2) error {
+	return fmt.Errorf("rvr: command 0x%02X 0x%02X failed with error code %d", p.DeviceID, p.CommandID, p.ErrorCode)
+}
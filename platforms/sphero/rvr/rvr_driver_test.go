@@ -0,0 +1,283 @@
+package rvr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+	"gobot.io/x/gobot/platforms/serial"
+	"gobot.io/x/gobot/platforms/sphero"
+)
+
+var _ gobot.Driver = (*Driver)(nil)
+
+// testPort is the serial port of a test RVR, recording the packets written
+// to it, and answering them with respond
+type testPort struct {
+	mtx     sync.Mutex
+	r       *io.PipeReader
+	w       *io.PipeWriter
+	packets []*sphero.PacketV2
+	respond func(p *sphero.PacketV2) *sphero.PacketV2
+}
+
+func newTestPort() *testPort {
+	r, w := io.Pipe()
+	return &testPort{r: r, w: w}
+}
+
+func (t *testPort) Read(b []byte) (int, error) { return t.r.Read(b) }
+
+func (t *testPort) Close() error { return t.r.Close() }
+
+func (t *testPort) Write(b []byte) (int, error) {
+	c := sphero.NewPacketV2Codec()
+	frame, err := c.Decode(bufio.NewReader(bytes.NewReader(b)))
+	if err != nil {
+		return 0, err
+	}
+	p, err := sphero.UnmarshalPacketV2(frame)
+	if err != nil {
+		return 0, err
+	}
+
+	t.mtx.Lock()
+	t.packets = append(t.packets, p)
+	respond := t.respond
+	t.mtx.Unlock()
+
+	if respond != nil {
+		if r := respond(p); r != nil {
+			go t.send(r)
+		}
+	}
+	return len(b), nil
+}
+
+// send sends p to the driver, as if sent by the RVR
+func (t *testPort) send(p *sphero.PacketV2) {
+	frame, _ := sphero.NewPacketV2Codec().Encode(p.Marshal())
+	t.w.Write(frame)
+}
+
+func (t *testPort) Packets() []*sphero.PacketV2 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.packets
+}
+
+func (t *testPort) LastPacket() *sphero.PacketV2 {
+	packets := t.Packets()
+	return packets[len(packets)-1]
+}
+
+func initTestRVRDriver() (*Driver, *testPort) {
+	port := newTestPort()
+	a := serial.NewAdaptor(port, sphero.NewPacketV2Codec())
+	a.Connect()
+	d := NewDriver(a)
+	d.Start()
+	return d, port
+}
+
+func response(p *sphero.PacketV2, errorCode uint8, data ...byte) *sphero.PacketV2 {
+	return &sphero.PacketV2{
+		Flags:     sphero.FlagIsResponse | sphero.FlagHasTargetID | sphero.FlagHasSourceID,
+		TargetID:  p.SourceID,
+		SourceID:  p.TargetID,
+		DeviceID:  p.DeviceID,
+		CommandID: p.CommandID,
+		Sequence:  p.Sequence,
+		ErrorCode: errorCode,
+		Data:      data,
+	}
+}
+
+func TestRVRAdaptor(t *testing.T) {
+	a := NewAdaptor("/dev/ttyS0")
+	gobottest.Assert(t, a.Port(), "/dev/ttyS0")
+	gobottest.Assert(t, a.BaudRate(), 115200)
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "RVR"), true)
+}
+
+func TestRVRDriverName(t *testing.T) {
+	d, _ := initTestRVRDriver()
+	gobottest.Assert(t, strings.HasPrefix(d.Name(), "RVR"), true)
+	d.SetName("NewName")
+	gobottest.Assert(t, d.Name(), "NewName")
+}
+
+func TestRVRDriverStartAndHalt(t *testing.T) {
+	d, port := initTestRVRDriver()
+	p := port.LastPacket()
+	gobottest.Assert(t, p.TargetID, uint8(nordicTarget))
+	gobottest.Assert(t, p.DeviceID, uint8(powerDevice))
+	gobottest.Assert(t, p.CommandID, uint8(wakeCommand))
+	gobottest.Assert(t, p.Flags&sphero.FlagRequestsOnlyErrorResponse != 0, true)
+
+	gobottest.Assert(t, d.Halt(), nil)
+	p = port.LastPacket()
+	gobottest.Assert(t, p.CommandID, uint8(driveWithHeadingCommand))
+	gobottest.Assert(t, p.Data, []byte{0x00, 0x00, 0x00, 0x00})
+}
+
+func TestRVRDriverSequence(t *testing.T) {
+	d, port := initTestRVRDriver()
+	d.Sleep()
+	packets := port.Packets()
+	gobottest.Assert(t, packets[1].Sequence, packets[0].Sequence+1)
+}
+
+func TestRVRDriverDriveAndStop(t *testing.T) {
+	d, port := initTestRVRDriver()
+	gobottest.Assert(t, d.Drive(0x40, 270), nil)
+	p := port.LastPacket()
+	gobottest.Assert(t, p.TargetID, uint8(stTarget))
+	gobottest.Assert(t, p.DeviceID, uint8(driveDevice))
+	gobottest.Assert(t, p.Data, []byte{0x40, 0x01, 0x0E, 0x00})
+
+	gobottest.Assert(t, d.Stop(), nil)
+	gobottest.Assert(t, port.LastPacket().Data, []byte{0x00, 0x01, 0x0E, 0x00})
+}
+
+func TestRVRDriverSetRawMotors(t *testing.T) {
+	d, port := initTestRVRDriver()
+	gobottest.Assert(t, d.SetRawMotors(100, -300), nil)
+	p := port.LastPacket()
+	gobottest.Assert(t, p.CommandID, uint8(rawMotorsCommand))
+	gobottest.Assert(t, p.Data, []byte{0x01, 100, 0x02, 255})
+
+	d.SetRawMotors(0, 0)
+	gobottest.Assert(t, port.LastPacket().Data, []byte{0x00, 0x00, 0x00, 0x00})
+}
+
+func TestRVRDriverSetRGB(t *testing.T) {
+	d, port := initTestRVRDriver()
+	gobottest.Assert(t, d.SetRGB(1, 2, 3), nil)
+	p := port.LastPacket()
+	gobottest.Assert(t, p.DeviceID, uint8(ioDevice))
+	gobottest.Assert(t, len(p.Data), 4+ledChannels)
+	gobottest.Assert(t, p.Data[:7], []byte{0x3F, 0xFF, 0xFF, 0xFF, 1, 2, 3})
+}
+
+func TestRVRDriverCommands(t *testing.T) {
+	d, port := initTestRVRDriver()
+	ret := d.Command("Drive")(map[string]interface{}{"speed": 100.0, "heading": 90.0})
+	gobottest.Assert(t, ret, nil)
+	gobottest.Assert(t, port.LastPacket().Data, []byte{100, 0x00, 90, 0x00})
+
+	gobottest.Assert(t, d.Command("SetRGB")(map[string]interface{}{"r": 1.0, "g": 2.0, "b": 3.0}), nil)
+	gobottest.Assert(t, d.Command("Stop")(nil), nil)
+	gobottest.Assert(t, d.Command("Sleep")(nil), nil)
+	gobottest.Assert(t, port.LastPacket().CommandID, uint8(sleepCommand))
+	gobottest.Assert(t, d.Command("Wake")(nil), nil)
+}
+
+func TestRVRDriverBatteryPercentage(t *testing.T) {
+	d, port := initTestRVRDriver()
+	port.respond = func(p *sphero.PacketV2) *sphero.PacketV2 {
+		return response(p, 0x00, 87)
+	}
+
+	val, err := d.BatteryPercentage()
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, uint8(87))
+	gobottest.Assert(t, port.LastPacket().Flags&sphero.FlagRequestsResponse != 0, true)
+}
+
+func TestRVRDriverBatteryPercentageError(t *testing.T) {
+	d, port := initTestRVRDriver()
+	port.respond = func(p *sphero.PacketV2) *sphero.PacketV2 {
+		return response(p, 0x03)
+	}
+
+	_, err := d.BatteryPercentage()
+	gobottest.Assert(t, err, errors.New("rvr: command 0x13 0x10 failed with error code 3"))
+}
+
+func TestRVRDriverBatteryPercentageTimeout(t *testing.T) {
+	responseTimeout = 10 * time.Millisecond
+	defer func() { responseTimeout = 1 * time.Second }()
+	d, _ := initTestRVRDriver()
+
+	_, err := d.BatteryPercentage()
+	gobottest.Assert(t, err, ErrResponseTimeout)
+}
+
+func TestRVRDriverErrorEvent(t *testing.T) {
+	d, port := initTestRVRDriver()
+	errs := make(chan interface{}, 1)
+	d.On(Error, func(data interface{}) { errs <- data })
+
+	port.send(response(&sphero.PacketV2{TargetID: stTarget, SourceID: hostSource, DeviceID: driveDevice, CommandID: rawMotorsCommand, Sequence: 0x42}, 0x01))
+
+	select {
+	case data := <-errs:
+		gobottest.Assert(t, data, errors.New("rvr: command 0x16 0x01 failed with error code 1"))
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("error event was not published")
+	}
+}
+
+func TestRVRDriverSensorStreaming(t *testing.T) {
+	d, port := initTestRVRDriver()
+	gobottest.Assert(t, d.StartSensorStreaming(100*time.Millisecond, IMU, AmbientLight), nil)
+
+	var configured [][]byte
+	var started [][]byte
+	for _, p := range port.Packets() {
+		switch p.CommandID {
+		case configureStreamingCommand:
+			configured = append(configured, append([]byte{p.TargetID}, p.Data...))
+		case startStreamingCommand:
+			started = append(started, append([]byte{p.TargetID}, p.Data...))
+		}
+	}
+	gobottest.Assert(t, configured, [][]byte{
+		{nordicTarget, streamingToken, 0x00, 0x0A, streamingDataSize},
+		{stTarget, streamingToken, 0x00, 0x01, streamingDataSize},
+	})
+	gobottest.Assert(t, started, [][]byte{
+		{nordicTarget, 0x00, 100},
+		{stTarget, 0x00, 100},
+	})
+
+	values := make(chan interface{}, 1)
+	d.On(SensorData, func(data interface{}) { values <- data })
+
+	data := []byte{streamingToken}
+	for _, raw := range []uint32{0, 0xFFFFFFFF, 0x80000000} {
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, raw)
+		data = append(data, buf...)
+	}
+	port.send(&sphero.PacketV2{
+		Flags:     sphero.FlagHasTargetID | sphero.FlagHasSourceID,
+		TargetID:  hostSource,
+		SourceID:  stTarget,
+		DeviceID:  sensorDevice,
+		CommandID: streamingDataCommand,
+		Data:      data,
+	})
+
+	select {
+	case v := <-values:
+		m := v.(map[string]float32)
+		gobottest.Assert(t, m["IMU.Pitch"], float32(-180))
+		gobottest.Assert(t, m["IMU.Roll"], float32(90))
+		gobottest.Assert(t, m["IMU.Yaw"], float32(0))
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("sensor data was not published")
+	}
+
+	gobottest.Assert(t, d.StopSensorStreaming(), nil)
+	gobottest.Assert(t, port.LastPacket().CommandID, uint8(clearStreamingCommand))
+}
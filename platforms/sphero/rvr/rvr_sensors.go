@@ -0,0 +1,66 @@
+package rvr
+
+// SensorService is a group of sensor values streamed by the RVR.
+type SensorService struct {
+	Name      string
+	ID        uint16
+	processor uint8
+	fields    []sensorField
+}
+
+// sensorField is a value of a SensorService, streamed as an unsigned 32-bit
+// integer spanning the range from min to max
+type sensorField struct {
+	name     string
+	min, max float32
+}
+
+func (f sensorField) value(raw uint32) float32 {
+	return f.min + float32(float64(raw)/float64(^uint32(0))*float64(f.max-f.min))
+}
+
+var (
+	// IMU streams the "Pitch", "Roll" and "Yaw" angles, in degrees
+	IMU = SensorService{Name: "IMU", ID: 0x0001, processor: stTarget, fields: []sensorField{
+		{"Pitch", -180, 180},
+		{"Roll", -90, 90},
+		{"Yaw", -180, 180},
+	}}
+
+	// Accelerometer streams the "X", "Y" and "Z" accelerations, in g
+	Accelerometer = SensorService{Name: "Accelerometer", ID: 0x0002, processor: stTarget, fields: []sensorField{
+		{"X", -16, 16},
+		{"Y", -16, 16},
+		{"Z", -16, 16},
+	}}
+
+	// Gyroscope streams the "X", "Y" and "Z" rotation rates, in degrees per
+	// second
+	Gyroscope = SensorService{Name: "Gyroscope", ID: 0x0004, processor: stTarget, fields: []sensorField{
+		{"X", -2000, 2000},
+		{"Y", -2000, 2000},
+		{"Z", -2000, 2000},
+	}}
+
+	// Locator streams the "X" and "Y" position, in meters
+	Locator = SensorService{Name: "Locator", ID: 0x0006, processor: stTarget, fields: []sensorField{
+		{"X", -16000, 16000},
+		{"Y", -16000, 16000},
+	}}
+
+	// Velocity streams the "X" and "Y" velocities, in meters per second
+	Velocity = SensorService{Name: "Velocity", ID: 0x0007, processor: stTarget, fields: []sensorField{
+		{"X", -5, 5},
+		{"Y", -5, 5},
+	}}
+
+	// Speed streams the "Speed" of the RVR, in meters per second
+	Speed = SensorService{Name: "Speed", ID: 0x0008, processor: stTarget, fields: []sensorField{
+		{"Speed", 0, 5},
+	}}
+
+	// AmbientLight streams the "Light" intensity, in lux
+	AmbientLight = SensorService{Name: "AmbientLight", ID: 0x000A, processor: nordicTarget, fields: []sensorField{
+		{"Light", 0, 120000},
+	}}
+)
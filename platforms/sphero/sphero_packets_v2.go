@@ -0,0 +1,185 @@
+package sphero
+
+import (
+	"bufio"
+	"errors"
+)
+
+// Flags of the packets of the Sphero API v2, used by the newer robots such as
+// the RVR and the BB-9E.
+const (
+	// FlagIsResponse is set on the responses to the commands
+	FlagIsResponse = 0x01
+	// FlagRequestsResponse asks for a response to the command
+	FlagRequestsResponse = 0x02
+	// FlagRequestsOnlyErrorResponse asks for a response only on error
+	FlagRequestsOnlyErrorResponse = 0x04
+	// FlagIsActivity resets the inactivity timeout of the robot
+	FlagIsActivity = 0x08
+	// FlagHasTargetID is set when the packet has a TargetID
+	FlagHasTargetID = 0x10
+	// FlagHasSourceID is set when the packet has a SourceID
+	FlagHasSourceID = 0x20
+)
+
+// special bytes of the API v2 framing
+const (
+	packetV2Start  = 0x8D
+	packetV2End    = 0xD8
+	packetV2Escape = 0xAB
+	// escaped bytes are sent as the escape byte followed by the byte with
+	// these bits cleared
+	packetV2EscapeMask = 0x88
+)
+
+var (
+	// ErrInvalidPacket is returned when a packet is too short or has an
+	// unknown framing
+	ErrInvalidPacket = errors.New("sphero: invalid packet")
+
+	// ErrInvalidChecksum is returned when the checksum of a packet is wrong
+	ErrInvalidChecksum = errors.New("sphero: invalid packet checksum")
+)
+
+// PacketV2 is a command, response or notification of the Sphero API v2.
+type PacketV2 struct {
+	Flags uint8
+	// TargetID is the processor the command is sent to, when FlagHasTargetID
+	// is set
+	TargetID uint8
+	// SourceID is the processor sending the packet, when FlagHasSourceID is
+	// set
+	SourceID  uint8
+	DeviceID  uint8
+	CommandID uint8
+	Sequence  uint8
+	// ErrorCode is the result of the command, for responses only
+	ErrorCode uint8
+	Data      []byte
+}
+
+// Marshal returns the packet bytes, from the flags to the checksum, without
+// framing.
+func (p *PacketV2) Marshal() []byte {
+	buf := []byte{p.Flags}
+	if p.Flags&FlagHasTargetID != 0 {
+		buf = append(buf, p.TargetID)
+	}
+	if p.Flags&FlagHasSourceID != 0 {
+		buf = append(buf, p.SourceID)
+	}
+	buf = append(buf, p.DeviceID, p.CommandID, p.Sequence)
+	if p.Flags&FlagIsResponse != 0 {
+		buf = append(buf, p.ErrorCode)
+	}
+	buf = append(buf, p.Data...)
+	return append(buf, calculateChecksumV2(buf))
+}
+
+// UnmarshalPacketV2 parses the packet bytes returned by Marshal, and checks
+// their checksum.
+func UnmarshalPacketV2(buf []byte) (*PacketV2, error) {
+	if len(buf) < 5 {
+		return nil, ErrInvalidPacket
+	}
+	if buf[len(buf)-1] != calculateChecksumV2(buf[:len(buf)-1]) {
+		return nil, ErrInvalidChecksum
+	}
+
+	p := &PacketV2{Flags: buf[0]}
+	rest := buf[1 : len(buf)-1]
+	header := 3
+	if p.Flags&FlagHasTargetID != 0 {
+		header++
+	}
+	if p.Flags&FlagHasSourceID != 0 {
+		header++
+	}
+	if p.Flags&FlagIsResponse != 0 {
+		header++
+	}
+	if len(rest) < header {
+		return nil, ErrInvalidPacket
+	}
+	if p.Flags&FlagHasTargetID != 0 {
+		p.TargetID, rest = rest[0], rest[1:]
+	}
+	if p.Flags&FlagHasSourceID != 0 {
+		p.SourceID, rest = rest[0], rest[1:]
+	}
+	p.DeviceID, p.CommandID, p.Sequence, rest = rest[0], rest[1], rest[2], rest[3:]
+	if p.Flags&FlagIsResponse != 0 {
+		p.ErrorCode, rest = rest[0], rest[1:]
+	}
+	p.Data = append([]byte{}, rest...)
+	return p, nil
+}
+
+func calculateChecksumV2(buf []byte) byte {
+	var sum byte
+	for _, b := range buf {
+		sum += b
+	}
+	return ^sum
+}
+
+// PacketV2Codec frames the packets of the Sphero API v2 between start and end
+// bytes, escaping these bytes in the packets. It can be used as the codec of
+// a serial platform Adaptor.
+type PacketV2Codec struct{}
+
+// NewPacketV2Codec returns a new PacketV2Codec.
+func NewPacketV2Codec() *PacketV2Codec {
+	return &PacketV2Codec{}
+}
+
+// Encode frames the packet bytes returned by PacketV2.Marshal.
+func (c *PacketV2Codec) Encode(frame []byte) ([]byte, error) {
+	out := make([]byte, 0, len(frame)+2)
+	out = append(out, packetV2Start)
+	for _, b := range frame {
+		switch b {
+		case packetV2Start, packetV2End, packetV2Escape:
+			out = append(out, packetV2Escape, b&^packetV2EscapeMask)
+		default:
+			out = append(out, b)
+		}
+	}
+	return append(out, packetV2End), nil
+}
+
+// Decode reads the next framed packet, and returns its bytes for
+// UnmarshalPacketV2. The bytes received out of a frame are skipped.
+func (c *PacketV2Codec) Decode(r *bufio.Reader) ([]byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == packetV2Start {
+			break
+		}
+	}
+
+	var frame []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		switch b {
+		case packetV2End:
+			return frame, nil
+		case packetV2Start:
+			// the previous frame was truncated, start over
+			frame = nil
+		case packetV2Escape:
+			if b, err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+			frame = append(frame, b|packetV2EscapeMask)
+		default:
+			frame = append(frame, b)
+		}
+	}
+}
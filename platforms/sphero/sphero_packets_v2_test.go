@@ -0,0 +1,68 @@
+package sphero
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestPacketV2Marshal(t *testing.T) {
+	p := &PacketV2{
+		Flags:     FlagRequestsResponse | FlagIsActivity | FlagHasTargetID,
+		TargetID:  0x12,
+		DeviceID:  0x16,
+		CommandID: 0x07,
+		Sequence:  0x01,
+		Data:      []byte{0x20, 0x00, 0x5A, 0x00},
+	}
+	buf := p.Marshal()
+	gobottest.Assert(t, buf, []byte{0x1A, 0x12, 0x16, 0x07, 0x01, 0x20, 0x00, 0x5A, 0x00, 0x3B})
+
+	q, err := UnmarshalPacketV2(buf)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, q, p)
+}
+
+func TestPacketV2MarshalResponse(t *testing.T) {
+	p := &PacketV2{
+		Flags:     FlagIsResponse | FlagHasSourceID,
+		SourceID:  0x11,
+		DeviceID:  0x13,
+		CommandID: 0x10,
+		Sequence:  0x05,
+		ErrorCode: 0x00,
+		Data:      []byte{0x64},
+	}
+	q, err := UnmarshalPacketV2(p.Marshal())
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, q, p)
+}
+
+func TestUnmarshalPacketV2Error(t *testing.T) {
+	_, err := UnmarshalPacketV2([]byte{0x00, 0x13})
+	gobottest.Assert(t, err, ErrInvalidPacket)
+
+	_, err = UnmarshalPacketV2([]byte{0x00, 0x13, 0x0D, 0x01, 0x00})
+	gobottest.Assert(t, err, ErrInvalidChecksum)
+
+	// a target ID is announced but missing
+	buf := []byte{FlagHasTargetID | FlagIsResponse, 0x13, 0x0D, 0x01}
+	_, err = UnmarshalPacketV2(append(buf, calculateChecksumV2(buf)))
+	gobottest.Assert(t, err, ErrInvalidPacket)
+}
+
+func TestPacketV2Codec(t *testing.T) {
+	c := NewPacketV2Codec()
+	frame, err := c.Encode([]byte{0x01, packetV2Start, packetV2End, packetV2Escape})
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, frame, []byte{packetV2Start, 0x01, packetV2Escape, 0x05, packetV2Escape, 0x50, packetV2Escape, 0x23, packetV2End})
+
+	// noise and a truncated frame precede the frame
+	data := append([]byte{0x00, packetV2Start, 0x02}, frame...)
+	r := bufio.NewReader(bytes.NewReader(data))
+	buf, err := c.Decode(r)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, buf, []byte{0x01, packetV2Start, packetV2End, packetV2Escape})
+}
@@ -72,7 +72,7 @@ func (c *Adaptor) Finalize() (err error) {
 	}
 	for _, bus := range c.i2cBuses {
 		if bus != nil {
-			if e := bus.Close(); e != nil {
+			if e := i2c.CloseBus(bus); e != nil {
 				err = multierror.Append(err, e)
 			}
 		}
@@ -0,0 +1,139 @@
+package gobot
+
+import (
+	"sync"
+	"time"
+)
+
+// PollerConfig configures the intervals of a Poller.
+type PollerConfig struct {
+	// Interval is the time between two polls after a change of the polled
+	// value. Defaults to DefaultPollInterval.
+	Interval time.Duration
+	// MaxInterval is the longest time between two polls, reached while the
+	// polled value does not change. When not greater than Interval, the
+	// Poller polls at a fixed Interval.
+	MaxInterval time.Duration
+	// Backoff multiplies the interval after each poll without change.
+	// Defaults to 2.
+	Backoff float64
+	// Scheduler, when set, makes the polls take turns with the other Pollers
+	// sharing it, for example the sensors of an I2C bus.
+	Scheduler *PollScheduler
+}
+
+// DefaultPollInterval is the Interval of a Poller configured without one
+const DefaultPollInterval = 10 * time.Millisecond
+
+// Poller polls a sensor at adaptive intervals: slower while its value does
+// not change, and back to the configured Interval as soon as it does.
+type Poller struct {
+	config   PollerConfig
+	clock    Clock
+	interval time.Duration
+	mutex    sync.Mutex
+}
+
+// NewPoller returns a new Poller given its configuration
+func NewPoller(config PollerConfig) *Poller {
+	if config.Interval <= 0 {
+		config.Interval = DefaultPollInterval
+	}
+	if config.Backoff <= 1 {
+		config.Backoff = 2
+	}
+	return &Poller{
+		config:   config,
+		clock:    DefaultClock(),
+		interval: config.Interval,
+	}
+}
+
+// Interval returns the time until the next poll
+func (p *Poller) Interval() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.interval
+}
+
+// Run calls fn, which reports whether the polled value changed, at once and
+// then at the intervals of the Poller until halt receives. Run blocks, it is
+// meant to be the polling goroutine of a Driver.
+func (p *Poller) Run(halt <-chan bool, fn func() (changed bool)) {
+	for {
+		changed, ok := p.config.Scheduler.do(p.clock, halt, fn)
+		if !ok {
+			return
+		}
+		select {
+		case <-p.clock.After(p.next(changed)):
+		case <-halt:
+			return
+		}
+	}
+}
+
+// Go runs the Poller in a goroutine held by owner, the Device polling, until
+// halt receives, so that a Device halted without stopping it is reported as
+// leaking its "poller" goroutine.
+func (p *Poller) Go(owner interface{}, halt <-chan bool, fn func() (changed bool)) {
+	Go(owner, "poller", func() { p.Run(halt, fn) })
+}
+
+// next adapts the interval to the result of the last poll and returns it
+func (p *Poller) next(changed bool) time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if changed || p.config.MaxInterval <= p.config.Interval {
+		p.interval = p.config.Interval
+		return p.interval
+	}
+	p.interval = time.Duration(float64(p.interval) * p.config.Backoff)
+	if p.interval > p.config.MaxInterval {
+		p.interval = p.config.MaxInterval
+	}
+	return p.interval
+}
+
+// PollScheduler makes the Pollers sharing it poll one at a time, at least
+// Gap apart, so that many sensors on a bus do not contend for it.
+type PollScheduler struct {
+	gap   time.Duration
+	last  time.Time
+	mutex sync.Mutex
+}
+
+// NewPollScheduler returns a new PollScheduler leaving at least gap between
+// the end of a poll and the start of the next one.
+func NewPollScheduler(gap time.Duration) *PollScheduler {
+	return &PollScheduler{gap: gap}
+}
+
+// SetGap changes the time left between two polls
+func (s *PollScheduler) SetGap(gap time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.gap = gap
+}
+
+// do runs fn once it is the turn of the caller. ok is false when halt
+// received meanwhile. A nil PollScheduler runs fn at once.
+func (s *PollScheduler) do(clock Clock, halt <-chan bool, fn func() bool) (changed bool, ok bool) {
+	if s == nil {
+		return fn(), true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.last.IsZero() {
+		if wait := s.last.Add(s.gap).Sub(clock.Now()); wait > 0 {
+			select {
+			case <-clock.After(wait):
+			case <-halt:
+				return false, false
+			}
+		}
+	}
+	changed = fn()
+	s.last = clock.Now()
+	return changed, true
+}
@@ -0,0 +1,138 @@
+package gobot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestPollerNext(t *testing.T) {
+	p := NewPoller(PollerConfig{Interval: 10 * time.Millisecond, MaxInterval: 50 * time.Millisecond})
+	gobottest.Assert(t, p.Interval(), 10*time.Millisecond)
+
+	gobottest.Assert(t, p.next(false), 20*time.Millisecond)
+	gobottest.Assert(t, p.next(false), 40*time.Millisecond)
+	gobottest.Assert(t, p.next(false), 50*time.Millisecond)
+	gobottest.Assert(t, p.next(false), 50*time.Millisecond)
+	gobottest.Assert(t, p.next(true), 10*time.Millisecond)
+	gobottest.Assert(t, p.Interval(), 10*time.Millisecond)
+}
+
+func TestPollerNextFixed(t *testing.T) {
+	p := NewPoller(PollerConfig{Interval: 10 * time.Millisecond})
+	gobottest.Assert(t, p.next(false), 10*time.Millisecond)
+	gobottest.Assert(t, p.next(true), 10*time.Millisecond)
+}
+
+func TestPollerNextBackoff(t *testing.T) {
+	p := NewPoller(PollerConfig{Interval: 10 * time.Millisecond, MaxInterval: time.Second, Backoff: 1.5})
+	gobottest.Assert(t, p.next(false), 15*time.Millisecond)
+}
+
+func TestNewPollerDefaultInterval(t *testing.T) {
+	gobottest.Assert(t, NewPoller(PollerConfig{}).Interval(), DefaultPollInterval)
+	gobottest.Assert(t, NewPoller(PollerConfig{Interval: -time.Second}).Interval(), DefaultPollInterval)
+}
+
+func TestPollerRun(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	polls := make(chan time.Time, 10)
+	changed := false
+	p := NewPoller(PollerConfig{Interval: 10 * time.Millisecond, MaxInterval: 40 * time.Millisecond})
+	halt := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		p.Run(halt, func() bool {
+			polls <- clock.Now()
+			return changed
+		})
+		done <- true
+	}()
+
+	gobottest.Assert(t, <-polls, time.Unix(0, 0))
+	for _, d := range []time.Duration{20, 40, 40} {
+		clock.BlockUntil(1)
+		gobottest.Assert(t, p.Interval(), d*time.Millisecond)
+		clock.Advance(d * time.Millisecond)
+		<-polls
+	}
+
+	halt <- true
+	<-done
+	gobottest.Assert(t, clock.Now(), time.Unix(0, int64(100*time.Millisecond)))
+}
+
+func TestPollSchedulerTakesTurns(t *testing.T) {
+	s := NewPollScheduler(0)
+	var mutex sync.Mutex
+	busy := false
+	overlaps := 0
+	poll := func() bool {
+		mutex.Lock()
+		if busy {
+			overlaps++
+		}
+		busy = true
+		mutex.Unlock()
+		time.Sleep(time.Millisecond)
+		mutex.Lock()
+		busy = false
+		mutex.Unlock()
+		return false
+	}
+
+	halt := make(chan bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewPoller(PollerConfig{Interval: time.Millisecond, Scheduler: s}).Run(halt, poll)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(halt)
+	wg.Wait()
+
+	gobottest.Assert(t, overlaps, 0)
+}
+
+func TestPollSchedulerGap(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	s := NewPollScheduler(5 * time.Millisecond)
+	halt := make(chan bool)
+
+	_, ok := s.do(clock, halt, func() bool { return true })
+	gobottest.Assert(t, ok, true)
+
+	done := make(chan time.Time)
+	go func() {
+		s.do(clock, halt, func() bool { return true })
+		done <- clock.Now()
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Millisecond)
+	gobottest.Assert(t, <-done, time.Unix(0, int64(5*time.Millisecond)))
+
+	s.SetGap(time.Second)
+	go func() {
+		_, ok := s.do(clock, halt, func() bool { return true })
+		done <- time.Unix(0, 0)
+		gobottest.Assert(t, ok, false)
+	}()
+	clock.BlockUntil(1)
+	halt <- true
+	<-done
+}
+
+func TestPollSchedulerNil(t *testing.T) {
+	var s *PollScheduler
+	changed, ok := s.do(SystemClock(), nil, func() bool { return true })
+	gobottest.Assert(t, changed, true)
+	gobottest.Assert(t, ok, true)
+}
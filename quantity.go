@@ -0,0 +1,116 @@
+package gobot
+
+import (
+	"math"
+	"strconv"
+)
+
+// Temperature is a temperature in celsius degrees. Being an offset scale,
+// it is built with Celsius, Fahrenheit or Kelvin rather than multiplied by a
+// unit.
+type Temperature float64
+
+// Celsius returns the Temperature of c celsius degrees
+func Celsius(c float64) Temperature { return Temperature(c) }
+
+// Fahrenheit returns the Temperature of f fahrenheit degrees
+func Fahrenheit(f float64) Temperature { return Temperature((f - 32) * 5 / 9) }
+
+// Kelvin returns the Temperature of k kelvins
+func Kelvin(k float64) Temperature { return Temperature(k - 273.15) }
+
+// Celsius returns the temperature in celsius degrees
+func (t Temperature) Celsius() float64 { return float64(t) }
+
+// Fahrenheit returns the temperature in fahrenheit degrees
+func (t Temperature) Fahrenheit() float64 { return float64(t)*9/5 + 32 }
+
+// Kelvin returns the temperature in kelvins
+func (t Temperature) Kelvin() float64 { return float64(t) + 273.15 }
+
+// String returns the temperature in celsius degrees, e.g. "21.5°C"
+func (t Temperature) String() string { return formatQuantity(float64(t), "°C") }
+
+// Distance is a length in meters. A Distance is built by multiplying a
+// number by a unit, e.g. 15 * gobot.Centimeter.
+type Distance float64
+
+// Units of Distance
+const (
+	Millimeter Distance = 0.001
+	Centimeter Distance = 0.01
+	Meter      Distance = 1
+	Kilometer  Distance = 1000
+	Inch       Distance = 0.0254
+	Foot       Distance = 0.3048
+)
+
+// Millimeters returns the distance in millimeters
+func (d Distance) Millimeters() float64 { return float64(d) * 1000 }
+
+// Centimeters returns the distance in centimeters
+func (d Distance) Centimeters() float64 { return float64(d) * 100 }
+
+// Meters returns the distance in meters
+func (d Distance) Meters() float64 { return float64(d) }
+
+// Inches returns the distance in inches
+func (d Distance) Inches() float64 { return float64(d / Inch) }
+
+// String returns the distance in meters, e.g. "0.15m"
+func (d Distance) String() string { return formatQuantity(float64(d), "m") }
+
+// Angle is an angle in radians. An Angle is built by multiplying a number by
+// a unit, e.g. 90 * gobot.Degree.
+type Angle float64
+
+// Units of Angle
+const (
+	Radian Angle = 1
+	Degree Angle = math.Pi / 180
+)
+
+// Radians returns the angle in radians
+func (a Angle) Radians() float64 { return float64(a) }
+
+// Degrees returns the angle in degrees
+func (a Angle) Degrees() float64 { return float64(a / Degree) }
+
+// Normalize returns the angle within [-π, π)
+func (a Angle) Normalize() Angle {
+	n := math.Mod(float64(a)+math.Pi, 2*math.Pi)
+	if n < 0 {
+		n += 2 * math.Pi
+	}
+	return Angle(n - math.Pi)
+}
+
+// String returns the angle in degrees, e.g. "90°"
+func (a Angle) String() string { return formatQuantity(a.Degrees(), "°") }
+
+// Voltage is an electric potential in volts. A Voltage is built by
+// multiplying a number by a unit, e.g. 3300 * gobot.Millivolt.
+type Voltage float64
+
+// Units of Voltage
+const (
+	Microvolt Voltage = 1e-6
+	Millivolt Voltage = 1e-3
+	Volt      Voltage = 1
+)
+
+// Volts returns the voltage in volts
+func (v Voltage) Volts() float64 { return float64(v) }
+
+// Millivolts returns the voltage in millivolts
+func (v Voltage) Millivolts() float64 { return float64(v) * 1000 }
+
+// String returns the voltage in volts, e.g. "3.3V"
+func (v Voltage) String() string { return formatQuantity(float64(v), "V") }
+
+// formatQuantity formats v with the shortest representation, rounded to 6
+// significant digits to hide the errors of the conversions
+func formatQuantity(v float64, unit string) string {
+	v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', 6, 64), 64)
+	return strconv.FormatFloat(v, 'f', -1, 64) + unit
+}
@@ -0,0 +1,46 @@
+package gobot
+
+import (
+	"math"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestTemperature(t *testing.T) {
+	gobottest.Assert(t, Celsius(100).Fahrenheit(), 212.0)
+	gobottest.Assert(t, Fahrenheit(212).Celsius(), 100.0)
+	gobottest.Assert(t, Kelvin(0).Celsius(), -273.15)
+	gobottest.Assert(t, Celsius(21.5).Kelvin(), 294.65)
+	gobottest.Assert(t, Celsius(21.5).String(), "21.5°C")
+	gobottest.Assert(t, Fahrenheit(98.6).String(), "37°C")
+}
+
+func TestDistance(t *testing.T) {
+	d := 15 * Centimeter
+	gobottest.Assert(t, d.Millimeters(), 150.0)
+	gobottest.Assert(t, d.Centimeters(), 15.0)
+	gobottest.Assert(t, d.Meters(), 0.15)
+	gobottest.Assert(t, d.String(), "0.15m")
+	gobottest.Assert(t, (12 * Inch).String(), Foot.String())
+	gobottest.Assert(t, (2 * Inch).Inches(), 2.0)
+	gobottest.Assert(t, (250*Millimeter + 25*Centimeter).String(), "0.5m")
+}
+
+func TestAngle(t *testing.T) {
+	gobottest.Assert(t, (math.Pi * Radian).Degrees(), 180.0)
+	gobottest.Assert(t, (180 * Degree).Radians(), math.Pi)
+	gobottest.Assert(t, (90 * Degree).String(), "90°")
+	gobottest.Assert(t, (270 * Degree).Normalize().String(), "-90°")
+	gobottest.Assert(t, (-270 * Degree).Normalize().String(), "90°")
+	gobottest.Assert(t, (180 * Degree).Normalize().String(), "-180°")
+	gobottest.Assert(t, (45 * Degree).Normalize().String(), "45°")
+}
+
+func TestVoltage(t *testing.T) {
+	v := 3.3 * Volt
+	gobottest.Assert(t, v.Volts(), 3.3)
+	gobottest.Assert(t, v.Millivolts(), 3300.0)
+	gobottest.Assert(t, v.String(), "3.3V")
+	gobottest.Assert(t, (500 * Microvolt).String(), "0.0005V")
+}
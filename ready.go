@@ -0,0 +1,125 @@
+package gobot
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// The startup events of a Robot, published while Start runs
+const (
+	// ConnectionStarted is published when a Connection has connected. Its
+	// data is the name of the Connection.
+	ConnectionStarted = "connection-started"
+	// DeviceStarted is published when a Device has started. Its data is the
+	// name of the Device.
+	DeviceStarted = "device-started"
+	// RobotReady is published once all the Connections and Devices have
+	// started, along with the work. Its data is the name of the Robot.
+	RobotReady = "robot-ready"
+)
+
+// The states of a Robot, see State
+const (
+	RobotStopped  = "stopped"
+	RobotStarting = "starting"
+	RobotRunning  = "running"
+)
+
+// ErrNotReady is returned by WaitUntilReady when the Robot did not start in
+// time
+var ErrNotReady = errors.New("Robot is not ready")
+
+// readiness tracks the state of a Robot across its starts and stops
+type readiness struct {
+	mutex sync.Mutex
+	state string
+	err   error
+	// done is closed once the current start has ended
+	done chan bool
+}
+
+// get returns the state and the channel closed once the current start has
+// ended, creating it for a Robot never started
+func (s *readiness) get() (string, chan bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.done == nil {
+		s.state = RobotStopped
+		s.done = make(chan bool)
+	}
+	return s.state, s.done
+}
+
+// starting marks the beginning of a start, the previous one having ended
+func (s *readiness) starting() {
+	s.get()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-s.done:
+		s.done = make(chan bool)
+	default:
+	}
+	s.state = RobotStarting
+	s.err = nil
+}
+
+// started marks the end of a start, failed unless err is nil
+func (s *readiness) started(err error) {
+	s.get()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.err = err
+	s.state = RobotRunning
+	if err != nil {
+		s.state = RobotStopped
+	}
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+}
+
+// stopped marks the Robot as stopped, waiting for its next start
+func (s *readiness) stopped() {
+	s.get()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.state = RobotStopped
+	s.err = nil
+	select {
+	case <-s.done:
+		s.done = make(chan bool)
+	default:
+	}
+}
+
+// State returns whether the Robot is stopped, starting its Connections and
+// Devices, or running.
+func (r *Robot) State() string {
+	state, _ := r.readiness.get()
+	return state
+}
+
+// WaitUntilReady waits for the Robot to be running, its Connections and
+// Devices having started, for at most timeout, or without limit if timeout
+// is zero. It may be called before Start, and returns the error of Start if
+// it fails, ErrNotReady if the Robot is not running in time.
+func (r *Robot) WaitUntilReady(timeout time.Duration) error {
+	_, done := r.readiness.get()
+	if timeout > 0 {
+		select {
+		case <-done:
+		case <-DefaultClock().After(timeout):
+			return ErrNotReady
+		}
+	} else {
+		<-done
+	}
+
+	r.readiness.mutex.Lock()
+	defer r.readiness.mutex.Unlock()
+	return r.readiness.err
+}
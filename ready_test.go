@@ -0,0 +1,93 @@
+package gobot
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// eventRecorder records the names and data of the events of an Eventer
+type eventRecorder struct {
+	events []string
+	mutex  sync.Mutex
+}
+
+func (e *eventRecorder) on(eventer Eventer, names ...string) {
+	for _, name := range names {
+		name := name
+		eventer.On(name, func(data interface{}) {
+			e.mutex.Lock()
+			defer e.mutex.Unlock()
+			e.events = append(e.events, name+" "+data.(string))
+		})
+	}
+}
+
+func (e *eventRecorder) recorded() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return append([]string{}, e.events...)
+}
+
+func TestRobotStartupEvents(t *testing.T) {
+	s := &startRecorder{}
+	a := newTestAdaptor("Connection1", "/dev/null")
+	r := NewRobot("startup", []Connection{a}, []Device{s.driver("motor", 0, nil)})
+	e := &eventRecorder{}
+	e.on(r, ConnectionStarted, DeviceStarted, RobotReady)
+
+	gobottest.Assert(t, r.State(), RobotStopped)
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+	gobottest.Assert(t, r.State(), RobotRunning)
+	gobottest.Assert(t, r.WaitUntilReady(0), nil)
+
+	deadline := time.Now().Add(time.Second)
+	for len(e.recorded()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	// the handlers of different events run independently
+	recorded := e.recorded()
+	sort.Strings(recorded)
+	gobottest.Assert(t, recorded, []string{
+		"connection-started Connection1",
+		"device-started motor",
+		"robot-ready startup",
+	})
+	gobottest.Assert(t, NewJSONRobot(r).State, RobotRunning)
+}
+
+func TestRobotWaitUntilReady(t *testing.T) {
+	s := &startRecorder{}
+	r := NewRobot("booting", []Device{s.driver("slow", 50*time.Millisecond, nil)})
+
+	gobottest.Assert(t, r.WaitUntilReady(10*time.Millisecond), ErrNotReady)
+
+	ready := make(chan error, 1)
+	go func() { ready <- r.WaitUntilReady(time.Second) }()
+	go r.Start(false)
+
+	time.Sleep(10 * time.Millisecond)
+	gobottest.Assert(t, r.State(), RobotStarting)
+	gobottest.Assert(t, <-ready, nil)
+	gobottest.Assert(t, r.State(), RobotRunning)
+
+	gobottest.Assert(t, r.Stop(), nil)
+	gobottest.Assert(t, r.State(), RobotStopped)
+	gobottest.Assert(t, r.WaitUntilReady(10*time.Millisecond), ErrNotReady)
+}
+
+func TestRobotWaitUntilReadyStartError(t *testing.T) {
+	s := &startRecorder{}
+	r := NewRobot("failing", []Device{s.driver("sensor", 0, errors.New("no device"))})
+
+	gobottest.Refute(t, r.Start(false), nil)
+	gobottest.Assert(t, r.State(), RobotStopped)
+	err := r.WaitUntilReady(time.Second)
+	gobottest.Refute(t, err, nil)
+	gobottest.Assert(t, Errors(err)[0].Error(), "start sensor: no device")
+}
@@ -0,0 +1,23 @@
+/*
+Package replay records the hardware activity of a Gobot robot and plays it back
+without the hardware, so that robot logic can be developed and tested on a
+laptop.
+
+A Recorder wraps a real adaptor and writes every digital, analog, PWM and servo
+read or write, as well as the events of watched devices, to a file as JSON
+lines:
+
+	f, _ := os.Create("session.jsonl")
+	recorder := replay.NewRecorder(f)
+	firmataAdaptor := recorder.Adaptor(firmata.NewAdaptor("/dev/ttyACM0"))
+	button := gpio.NewButtonDriver(firmataAdaptor, "5")
+	recorder.Watch("button", button)
+
+The replay Adaptor reads the file back and answers the reads with the recorded
+values, in real time or faster, while the writes are collected for inspection:
+
+	replayAdaptor, _ := replay.Open("session.jsonl")
+	replayAdaptor.Speed = 10
+	button := gpio.NewButtonDriver(replayAdaptor, "5")
+*/
+package replay // import "gobot.io/x/gobot/replay"
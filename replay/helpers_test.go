@@ -0,0 +1,61 @@
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+type testAdaptor struct {
+	name   string
+	values map[string]int
+	writes map[string]byte
+}
+
+func newTestAdaptor() *testAdaptor {
+	return &testAdaptor{
+		name:   "test",
+		values: map[string]int{"2": 1, "A0": 512},
+		writes: make(map[string]byte),
+	}
+}
+
+func (t *testAdaptor) Name() string     { return t.name }
+func (t *testAdaptor) SetName(n string) { t.name = n }
+func (t *testAdaptor) Connect() error   { return nil }
+func (t *testAdaptor) Finalize() error  { return nil }
+
+func (t *testAdaptor) DigitalRead(pin string) (int, error) {
+	if v, ok := t.values[pin]; ok {
+		return v, nil
+	}
+	return 0, errors.New("read error")
+}
+func (t *testAdaptor) AnalogRead(pin string) (int, error)    { return t.DigitalRead(pin) }
+func (t *testAdaptor) DigitalWrite(pin string, v byte) error { t.writes[pin] = v; return nil }
+func (t *testAdaptor) PwmWrite(pin string, v byte) error     { t.writes[pin] = v; return nil }
+func (t *testAdaptor) ServoWrite(pin string, v byte) error   { t.writes[pin] = v; return nil }
+
+type nullAdaptor struct{}
+
+func (nullAdaptor) Name() string     { return "null" }
+func (nullAdaptor) SetName(n string) {}
+func (nullAdaptor) Connect() error   { return nil }
+func (nullAdaptor) Finalize() error  { return nil }
+
+type syncBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.buf.String()
+}
@@ -0,0 +1,169 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+// Operations stored in a Record
+const (
+	DigitalRead  = "DigitalRead"
+	DigitalWrite = "DigitalWrite"
+	AnalogRead   = "AnalogRead"
+	PwmWrite     = "PwmWrite"
+	ServoWrite   = "ServoWrite"
+	Event        = "Event"
+)
+
+// Record is a single recorded operation.
+type Record struct {
+	// Time since the start of the recording
+	Time time.Duration `json:"t"`
+	// Op is one of the operation constants
+	Op string `json:"op"`
+	// Pin read or written, empty for events
+	Pin string `json:"pin,omitempty"`
+	// Value read or written
+	Value int `json:"value,omitempty"`
+	// Err is the message of the error returned by the operation, if any
+	Err string `json:"err,omitempty"`
+	// Device and Name identify a recorded event
+	Device string `json:"device,omitempty"`
+	Name   string `json:"name,omitempty"`
+	// Data is the payload of a recorded event
+	Data interface{} `json:"data,omitempty"`
+}
+
+// ReadRecords reads the JSON lines written by a Recorder.
+func ReadRecords(r io.Reader) (records []Record, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err = json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// Recorder writes Records as JSON lines.
+type Recorder struct {
+	encoder *json.Encoder
+	start   time.Time
+	mutex   sync.Mutex
+}
+
+// NewRecorder returns a new Recorder writing to w. Record times are relative
+// to the creation of the Recorder.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		encoder: json.NewEncoder(w),
+		start:   gobot.DefaultClock().Now(),
+	}
+}
+
+// Record writes rec, setting its Time.
+func (r *Recorder) Record(rec Record) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rec.Time = gobot.DefaultClock().Now().Sub(r.start)
+	return r.encoder.Encode(rec)
+}
+
+func (r *Recorder) record(op string, pin string, value int, err error) {
+	rec := Record{Op: op, Pin: pin, Value: value}
+	if err != nil {
+		rec.Err = err.Error()
+	}
+	r.Record(rec)
+}
+
+// Watch records every event published by the Eventer of device.
+func (r *Recorder) Watch(device string, e gobot.Eventer) {
+	out := e.Subscribe()
+	go func() {
+		for evt := range out {
+			r.Record(Record{Op: Event, Device: device, Name: evt.Name, Data: evt.Data})
+		}
+	}()
+}
+
+// Adaptor returns a RecordingAdaptor wrapping a.
+func (r *Recorder) Adaptor(a gobot.Adaptor) *RecordingAdaptor {
+	return &RecordingAdaptor{Adaptor: a, recorder: r}
+}
+
+// RecordingAdaptor passes reads and writes on to the wrapped Adaptor and
+// records them. Operations the wrapped Adaptor does not support return the
+// matching gpio error.
+type RecordingAdaptor struct {
+	gobot.Adaptor
+	recorder *Recorder
+}
+
+// DigitalRead reads the pin from the wrapped Adaptor and records the result
+func (a *RecordingAdaptor) DigitalRead(pin string) (val int, err error) {
+	reader, ok := a.Adaptor.(gpio.DigitalReader)
+	if !ok {
+		return 0, gpio.ErrDigitalReadUnsupported
+	}
+	val, err = reader.DigitalRead(pin)
+	a.recorder.record(DigitalRead, pin, val, err)
+	return
+}
+
+// DigitalWrite writes the pin using the wrapped Adaptor and records it
+func (a *RecordingAdaptor) DigitalWrite(pin string, level byte) (err error) {
+	writer, ok := a.Adaptor.(gpio.DigitalWriter)
+	if !ok {
+		return gpio.ErrDigitalWriteUnsupported
+	}
+	err = writer.DigitalWrite(pin, level)
+	a.recorder.record(DigitalWrite, pin, int(level), err)
+	return
+}
+
+// AnalogRead reads the pin from the wrapped Adaptor and records the result
+func (a *RecordingAdaptor) AnalogRead(pin string) (val int, err error) {
+	reader, ok := a.Adaptor.(aio.AnalogReader)
+	if !ok {
+		return 0, gpio.ErrAnalogReadUnsupported
+	}
+	val, err = reader.AnalogRead(pin)
+	a.recorder.record(AnalogRead, pin, val, err)
+	return
+}
+
+// PwmWrite writes the pin using the wrapped Adaptor and records it
+func (a *RecordingAdaptor) PwmWrite(pin string, level byte) (err error) {
+	writer, ok := a.Adaptor.(gpio.PwmWriter)
+	if !ok {
+		return gpio.ErrPwmWriteUnsupported
+	}
+	err = writer.PwmWrite(pin, level)
+	a.recorder.record(PwmWrite, pin, int(level), err)
+	return
+}
+
+// ServoWrite writes the pin using the wrapped Adaptor and records it
+func (a *RecordingAdaptor) ServoWrite(pin string, angle byte) (err error) {
+	writer, ok := a.Adaptor.(gpio.ServoWriter)
+	if !ok {
+		return gpio.ErrServoWriteUnsupported
+	}
+	err = writer.ServoWrite(pin, angle)
+	a.recorder.record(ServoWrite, pin, int(angle), err)
+	return
+}
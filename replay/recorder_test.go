@@ -0,0 +1,84 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestRecordingAdaptor(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRecorder(&buf)
+	inner := newTestAdaptor()
+	a := r.Adaptor(inner)
+	gobottest.Assert(t, a.Name(), "test")
+
+	val, err := a.DigitalRead("2")
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, val, 1)
+	val, _ = a.AnalogRead("A0")
+	gobottest.Assert(t, val, 512)
+	_, err = a.DigitalRead("9")
+	gobottest.Assert(t, err.Error(), "read error")
+
+	a.DigitalWrite("13", 1)
+	a.PwmWrite("3", 128)
+	a.ServoWrite("4", 90)
+	gobottest.Assert(t, inner.writes["13"], byte(1))
+	gobottest.Assert(t, inner.writes["3"], byte(128))
+	gobottest.Assert(t, inner.writes["4"], byte(90))
+
+	records, err := ReadRecords(&buf)
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(records), 6)
+	gobottest.Assert(t, records[0].Op, DigitalRead)
+	gobottest.Assert(t, records[0].Pin, "2")
+	gobottest.Assert(t, records[0].Value, 1)
+	gobottest.Assert(t, records[2].Err, "read error")
+	gobottest.Assert(t, records[5].Op, ServoWrite)
+	gobottest.Assert(t, records[5].Value, 90)
+	gobottest.Assert(t, records[5].Time >= records[0].Time, true)
+}
+
+func TestRecordingAdaptorUnsupported(t *testing.T) {
+	a := NewRecorder(&bytes.Buffer{}).Adaptor(nullAdaptor{})
+	_, err := a.DigitalRead("1")
+	gobottest.Assert(t, err, gpio.ErrDigitalReadUnsupported)
+	_, err = a.AnalogRead("1")
+	gobottest.Assert(t, err, gpio.ErrAnalogReadUnsupported)
+	gobottest.Assert(t, a.DigitalWrite("1", 1), gpio.ErrDigitalWriteUnsupported)
+	gobottest.Assert(t, a.PwmWrite("1", 1), gpio.ErrPwmWriteUnsupported)
+	gobottest.Assert(t, a.ServoWrite("1", 1), gpio.ErrServoWriteUnsupported)
+}
+
+func TestRecorderWatch(t *testing.T) {
+	buf := &syncBuffer{}
+	r := NewRecorder(buf)
+	e := gobot.NewEventer()
+	e.AddEvent("push")
+	r.Watch("button", e)
+
+	e.Publish("push", 1)
+	time.Sleep(10 * time.Millisecond)
+
+	records, _ := ReadRecords(strings.NewReader(buf.String()))
+	gobottest.Assert(t, len(records), 1)
+	gobottest.Assert(t, records[0].Op, Event)
+	gobottest.Assert(t, records[0].Device, "button")
+	gobottest.Assert(t, records[0].Name, "push")
+	gobottest.Assert(t, records[0].Data, 1.0)
+}
+
+func TestReadRecordsError(t *testing.T) {
+	_, err := ReadRecords(strings.NewReader("{\n"))
+	gobottest.Refute(t, err, nil)
+
+	records, err := ReadRecords(strings.NewReader("\n{\"op\":\"DigitalRead\"}\n\n"))
+	gobottest.Assert(t, err, nil)
+	gobottest.Assert(t, len(records), 1)
+}
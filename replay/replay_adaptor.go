@@ -0,0 +1,211 @@
+package replay
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Adaptor plays back a recording. Reads return the value recorded for the
+// same operation and pin, writes are collected and can be inspected with
+// Writes, and recorded events are published on the attached Eventers.
+type Adaptor struct {
+	name string
+	// Speed is the playback rate: 1 replays in real time, 10 ten times
+	// faster. A Speed of 0 ignores the timestamps: every read returns the
+	// next recorded value and events are published at once.
+	Speed float64
+
+	reads   map[string][]Record
+	events  []Record
+	cursors map[string]int
+	writes  []Record
+	targets map[string]gobot.Eventer
+	started time.Time
+	halt    chan bool
+	mutex   sync.Mutex
+}
+
+// NewAdaptor returns a new replay Adaptor playing back records in real time.
+func NewAdaptor(records []Record) *Adaptor {
+	a := &Adaptor{
+		name:    gobot.DefaultName("Replay"),
+		Speed:   1,
+		reads:   make(map[string][]Record),
+		cursors: make(map[string]int),
+		targets: make(map[string]gobot.Eventer),
+	}
+	for _, rec := range records {
+		switch rec.Op {
+		case DigitalRead, AnalogRead:
+			key := rec.Op + ":" + rec.Pin
+			a.reads[key] = append(a.reads[key], rec)
+		case Event:
+			a.events = append(a.events, rec)
+		}
+	}
+	for _, recs := range a.reads {
+		sort.SliceStable(recs, func(i, j int) bool { return recs[i].Time < recs[j].Time })
+	}
+	sort.SliceStable(a.events, func(i, j int) bool { return a.events[i].Time < a.events[j].Time })
+	return a
+}
+
+// Open returns a new replay Adaptor playing back the recording in file.
+func Open(file string) (*Adaptor, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := ReadRecords(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewAdaptor(records), nil
+}
+
+// Name returns the Adaptor name
+func (a *Adaptor) Name() string { return a.name }
+
+// SetName sets the Adaptor name
+func (a *Adaptor) SetName(n string) { a.name = n }
+
+// Attach publishes the events recorded for device on e during playback.
+func (a *Adaptor) Attach(device string, e gobot.Eventer) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.targets[device] = e
+}
+
+// Connect starts the playback
+func (a *Adaptor) Connect() error {
+	a.mutex.Lock()
+	a.started = gobot.DefaultClock().Now()
+	a.halt = make(chan bool)
+	halt := a.halt
+	a.mutex.Unlock()
+
+	go a.playEvents(halt)
+	return nil
+}
+
+// Finalize stops the playback
+func (a *Adaptor) Finalize() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.halt != nil {
+		close(a.halt)
+		a.halt = nil
+	}
+	return nil
+}
+
+// elapsed returns the position in the recording.
+func (a *Adaptor) elapsed() time.Duration {
+	return time.Duration(float64(gobot.DefaultClock().Now().Sub(a.started)) * a.Speed)
+}
+
+func (a *Adaptor) playEvents(halt chan bool) {
+	for _, rec := range a.events {
+		if a.Speed > 0 {
+			a.mutex.Lock()
+			wait := time.Duration(float64(rec.Time-a.elapsed()) / a.Speed)
+			a.mutex.Unlock()
+			if wait > 0 {
+				select {
+				case <-gobot.DefaultClock().After(wait):
+				case <-halt:
+					return
+				}
+			}
+		}
+		a.mutex.Lock()
+		e, ok := a.targets[rec.Device]
+		a.mutex.Unlock()
+		if ok {
+			e.Publish(rec.Name, rec.Data)
+		}
+	}
+}
+
+// read returns the recorded value of op on pin at the current position.
+func (a *Adaptor) read(op string, pin string) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	key := op + ":" + pin
+	recs := a.reads[key]
+	if len(recs) == 0 {
+		return 0, errors.New("No " + op + " recorded for pin " + pin)
+	}
+
+	var rec Record
+	if a.Speed > 0 {
+		now := a.elapsed()
+		i := sort.Search(len(recs), func(i int) bool { return recs[i].Time > now })
+		if i > 0 {
+			i--
+		}
+		rec = recs[i]
+	} else {
+		i := a.cursors[key]
+		rec = recs[i]
+		if i < len(recs)-1 {
+			a.cursors[key] = i + 1
+		}
+	}
+
+	if rec.Err != "" {
+		return rec.Value, errors.New(rec.Err)
+	}
+	return rec.Value, nil
+}
+
+func (a *Adaptor) write(op string, pin string, value byte) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	rec := Record{Op: op, Pin: pin, Value: int(value)}
+	if !a.started.IsZero() {
+		rec.Time = a.elapsed()
+	}
+	a.writes = append(a.writes, rec)
+	return nil
+}
+
+// DigitalRead returns the recorded value of the pin
+func (a *Adaptor) DigitalRead(pin string) (int, error) {
+	return a.read(DigitalRead, pin)
+}
+
+// AnalogRead returns the recorded value of the pin
+func (a *Adaptor) AnalogRead(pin string) (int, error) {
+	return a.read(AnalogRead, pin)
+}
+
+// DigitalWrite collects the write
+func (a *Adaptor) DigitalWrite(pin string, level byte) error {
+	return a.write(DigitalWrite, pin, level)
+}
+
+// PwmWrite collects the write
+func (a *Adaptor) PwmWrite(pin string, level byte) error {
+	return a.write(PwmWrite, pin, level)
+}
+
+// ServoWrite collects the write
+func (a *Adaptor) ServoWrite(pin string, angle byte) error {
+	return a.write(ServoWrite, pin, angle)
+}
+
+// Writes returns the writes made since the Adaptor was created, with their
+// position in the recording.
+func (a *Adaptor) Writes() []Record {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]Record{}, a.writes...)
+}
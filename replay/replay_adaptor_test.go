@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Adaptor = (*Adaptor)(nil)
+
+var _ gpio.DigitalReader = (*Adaptor)(nil)
+var _ gpio.DigitalWriter = (*Adaptor)(nil)
+var _ gpio.PwmWriter = (*Adaptor)(nil)
+var _ gpio.ServoWriter = (*Adaptor)(nil)
+var _ aio.AnalogReader = (*Adaptor)(nil)
+
+var _ gpio.DigitalReader = (*RecordingAdaptor)(nil)
+var _ aio.AnalogReader = (*RecordingAdaptor)(nil)
+
+func testRecords() []Record {
+	return []Record{
+		{Time: 0, Op: DigitalRead, Pin: "2", Value: 0},
+		{Time: 40 * time.Millisecond, Op: DigitalRead, Pin: "2", Value: 1},
+		{Time: 80 * time.Millisecond, Op: DigitalRead, Pin: "2", Value: 0, Err: "glitch"},
+		{Time: 0, Op: AnalogRead, Pin: "A0", Value: 100},
+		{Time: 10 * time.Millisecond, Op: Event, Device: "button", Name: "push", Data: 1.0},
+		{Time: 20 * time.Millisecond, Op: DigitalWrite, Pin: "13", Value: 1},
+	}
+}
+
+func TestReplayAdaptor(t *testing.T) {
+	a := NewAdaptor(testRecords())
+	gobottest.Assert(t, strings.HasPrefix(a.Name(), "Replay"), true)
+	a.SetName("NewName")
+	gobottest.Assert(t, a.Name(), "NewName")
+	gobottest.Assert(t, a.Speed, 1.0)
+}
+
+func TestReplayAdaptorSequential(t *testing.T) {
+	a := NewAdaptor(testRecords())
+	a.Speed = 0
+	gobottest.Assert(t, a.Connect(), nil)
+	defer a.Finalize()
+
+	val, err := a.DigitalRead("2")
+	gobottest.Assert(t, val, 0)
+	gobottest.Assert(t, err, nil)
+	val, _ = a.DigitalRead("2")
+	gobottest.Assert(t, val, 1)
+	_, err = a.DigitalRead("2")
+	gobottest.Assert(t, err.Error(), "glitch")
+	// the last record repeats
+	_, err = a.DigitalRead("2")
+	gobottest.Assert(t, err.Error(), "glitch")
+
+	val, _ = a.AnalogRead("A0")
+	gobottest.Assert(t, val, 100)
+
+	_, err = a.DigitalRead("7")
+	gobottest.Assert(t, err.Error(), "No DigitalRead recorded for pin 7")
+}
+
+func TestReplayAdaptorTimed(t *testing.T) {
+	clock := gobot.NewFakeClock(time.Unix(0, 0))
+	gobot.SetDefaultClock(clock)
+	defer gobot.SetDefaultClock(gobot.SystemClock())
+
+	a := NewAdaptor(testRecords())
+	a.Speed = 2
+	gobottest.Assert(t, a.Connect(), nil)
+	defer a.Finalize()
+
+	val, _ := a.DigitalRead("2")
+	gobottest.Assert(t, val, 0)
+
+	// 38ms and 40ms of recording at 2x speed
+	clock.Advance(19 * time.Millisecond)
+	val, _ = a.DigitalRead("2")
+	gobottest.Assert(t, val, 0)
+	clock.Advance(time.Millisecond)
+	val, _ = a.DigitalRead("2")
+	gobottest.Assert(t, val, 1)
+}
+
+func TestReplayAdaptorWrites(t *testing.T) {
+	a := NewAdaptor(nil)
+	a.DigitalWrite("13", 1)
+	a.PwmWrite("3", 64)
+	a.ServoWrite("4", 180)
+
+	writes := a.Writes()
+	gobottest.Assert(t, len(writes), 3)
+	gobottest.Assert(t, writes[0], Record{Op: DigitalWrite, Pin: "13", Value: 1})
+	gobottest.Assert(t, writes[2].Op, ServoWrite)
+	gobottest.Assert(t, writes[2].Value, 180)
+}
+
+func TestReplayAdaptorEvents(t *testing.T) {
+	a := NewAdaptor(testRecords())
+	e := gobot.NewEventer()
+	e.AddEvent("push")
+	a.Attach("button", e)
+
+	sem := make(chan interface{}, 1)
+	e.On("push", func(data interface{}) {
+		sem <- data
+	})
+
+	gobottest.Assert(t, a.Connect(), nil)
+	defer a.Finalize()
+
+	select {
+	case data := <-sem:
+		gobottest.Assert(t, data, 1.0)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Recorded event was not published")
+	}
+}
+
+func TestReplayAdaptorWithButton(t *testing.T) {
+	a := NewAdaptor(testRecords())
+	a.Speed = 0
+	button := gpio.NewButtonDriver(a, "2", 5*time.Millisecond)
+
+	sem := make(chan bool, 1)
+	button.Once(gpio.ButtonPush, func(data interface{}) {
+		sem <- true
+	})
+
+	gobottest.Assert(t, a.Connect(), nil)
+	defer a.Finalize()
+	gobottest.Assert(t, button.Start(), nil)
+	defer button.Halt()
+
+	select {
+	case <-sem:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Button push was not replayed")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gobot-replay")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "session.jsonl")
+	ioutil.WriteFile(file, []byte(`{"t":0,"op":"AnalogRead","pin":"A0","value":7}`+"\n"), 0644)
+
+	a, err := Open(file)
+	gobottest.Assert(t, err, nil)
+	val, _ := a.AnalogRead("A0")
+	gobottest.Assert(t, val, 7)
+
+	_, err = Open(filepath.Join(dir, "missing.jsonl"))
+	gobottest.Refute(t, err, nil)
+
+	ioutil.WriteFile(file, []byte("{"), 0644)
+	_, err = Open(file)
+	gobottest.Refute(t, err, nil)
+}
@@ -0,0 +1,181 @@
+package gobot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of the resources held by the Devices and Connections
+const (
+	GoroutineResource = "goroutine"
+	TickerResource    = "ticker"
+	FileResource      = "file"
+)
+
+// DefaultReleaseTimeout is how long a Robot waits for its halted Devices and
+// finalized Connections to release their resources, when its ReleaseTimeout
+// is not set.
+const DefaultReleaseTimeout = 100 * time.Millisecond
+
+// Resource is a goroutine, ticker, file or other resource held by a Device or
+// a Connection, typically acquired in its Start and released by its Halt.
+type Resource struct {
+	Kind     string
+	Name     string
+	Acquired time.Time
+}
+
+func (r Resource) String() string { return r.Kind + " " + r.Name }
+
+// ResourceLeakError is returned by WaitReleased with the resources still
+// held by their owner.
+type ResourceLeakError struct {
+	Owner     string
+	Resources []Resource
+}
+
+func (e *ResourceLeakError) Error() string {
+	held := make([]string, len(e.Resources))
+	for i, r := range e.Resources {
+		held[i] = r.String()
+	}
+	return fmt.Sprintf("%v leaked %v", e.Owner, strings.Join(held, ", "))
+}
+
+// resourceRegistry holds the resources of their owners
+type resourceRegistry struct {
+	held    map[interface{}]map[uint64]Resource
+	seq     uint64
+	changed chan bool
+	mutex   sync.Mutex
+}
+
+var resources = &resourceRegistry{
+	held:    make(map[interface{}]map[uint64]Resource),
+	changed: make(chan bool),
+}
+
+// Acquire registers a resource of kind held by owner, a Device or a
+// Connection, until the returned release function is called. Drivers
+// register in their Start the goroutines, tickers and files their Halt must
+// release, so that leaks are reported:
+//
+//	ticker := gobot.DefaultClock().NewTicker(d.interval)
+//	release := gobot.Acquire(d, gobot.TickerResource, "sampling")
+//	go func() {
+//		defer release()
+//		defer ticker.Stop()
+//		...
+//	}()
+//
+// The resources of an owner which is not a valid map key, such as a slice,
+// are not tracked.
+func Acquire(owner interface{}, kind string, name string) (release func()) {
+	if !canHoldResources(owner) {
+		return func() {}
+	}
+	r := resources
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.seq++
+	id := r.seq
+	if r.held[owner] == nil {
+		r.held[owner] = make(map[uint64]Resource)
+	}
+	r.held[owner][id] = Resource{Kind: kind, Name: name, Acquired: time.Now()}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { r.release(owner, id) })
+	}
+}
+
+// Go runs f in a goroutine held by owner until f returns, such as the
+// polling goroutine of a Driver.
+func Go(owner interface{}, name string, f func()) {
+	release := Acquire(owner, GoroutineResource, name)
+	go func() {
+		defer release()
+		f()
+	}()
+}
+
+// HeldResources returns the resources held by owner, oldest first
+func HeldResources(owner interface{}) []Resource {
+	if !canHoldResources(owner) {
+		return []Resource{}
+	}
+	r := resources
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.list(owner)
+}
+
+// WaitReleased waits up to timeout for owner to release its resources, and
+// returns a *ResourceLeakError with the ones still held after it.
+func WaitReleased(owner interface{}, timeout time.Duration) error {
+	if !canHoldResources(owner) {
+		return nil
+	}
+	deadline := time.After(timeout)
+	for {
+		r := resources
+		r.mutex.Lock()
+		held := r.list(owner)
+		changed := r.changed
+		r.mutex.Unlock()
+		if len(held) == 0 {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return &ResourceLeakError{Owner: ownerName(owner), Resources: held}
+		}
+	}
+}
+
+// release removes the resource id of owner, the mutex must not be held
+func (r *resourceRegistry) release(owner interface{}, id uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.held[owner], id)
+	if len(r.held[owner]) == 0 {
+		delete(r.held, owner)
+	}
+	close(r.changed)
+	r.changed = make(chan bool)
+}
+
+// list returns the resources of owner by id, the mutex must be held
+func (r *resourceRegistry) list(owner interface{}) []Resource {
+	ids := []uint64{}
+	for id := range r.held[owner] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	held := make([]Resource, len(ids))
+	for i, id := range ids {
+		held[i] = r.held[owner][id]
+	}
+	return held
+}
+
+func ownerName(owner interface{}) string {
+	if n, ok := owner.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", owner)
+}
+
+// canHoldResources returns whether owner can hold resources, its type being
+// a valid key of the registry
+func canHoldResources(owner interface{}) bool {
+	return owner != nil && reflect.TypeOf(owner).Comparable()
+}
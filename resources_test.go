@@ -0,0 +1,57 @@
+package gobot
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestResources(t *testing.T) {
+	d := newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "Device1", "0")
+	gobottest.Assert(t, HeldResources(d), []Resource{})
+
+	release := Acquire(d, FileResource, "/dev/i2c-1")
+	halt := make(chan bool)
+	Go(d, "poller", func() { <-halt })
+
+	held := HeldResources(d)
+	gobottest.Assert(t, len(held), 2)
+	gobottest.Assert(t, held[0].String(), "file /dev/i2c-1")
+	gobottest.Assert(t, held[1].Kind, GoroutineResource)
+
+	err := WaitReleased(d, 10*time.Millisecond)
+	gobottest.Assert(t, err.Error(), "Device1 leaked file /dev/i2c-1, goroutine poller")
+	gobottest.Assert(t, len(err.(*ResourceLeakError).Resources), 2)
+
+	release()
+	release()
+	close(halt)
+	gobottest.Assert(t, WaitReleased(d, time.Second), nil)
+	gobottest.Assert(t, HeldResources(d), []Resource{})
+}
+
+func TestResourcesNotComparable(t *testing.T) {
+	owner := []string{"not", "a", "key"}
+	release := Acquire(owner, FileResource, "/dev/null")
+	gobottest.Assert(t, HeldResources(owner), []Resource{})
+	release()
+	Go(nil, "loop", func() {})
+	gobottest.Assert(t, HeldResources(map[string]int{}), []Resource{})
+	gobottest.Assert(t, WaitReleased(nil, time.Millisecond), nil)
+}
+
+func TestRobotStopReportsLeaks(t *testing.T) {
+	r := newTestRobot("Robot1")
+	r.ReleaseTimeout = 10 * time.Millisecond
+	device := r.Device("Device1")
+	halt := make(chan bool)
+	defer close(halt)
+
+	gobottest.Assert(t, r.Start(false), nil)
+	Go(device, "poller", func() { <-halt })
+	start := time.Now()
+	gobottest.Assert(t, r.Stop(), nil)
+	gobottest.Assert(t, time.Since(start) >= 10*time.Millisecond, true)
+	gobottest.Assert(t, len(HeldResources(device)), 1)
+}
@@ -0,0 +1,182 @@
+package gobot
+
+import (
+	"sync"
+	"time"
+)
+
+// RobotRestarted is the Master event published when a crashed Robot has been
+// restarted according to the Master's RestartPolicy. Its data is a
+// RobotRestart.
+const RobotRestarted = "robot-restarted"
+
+// RestartPolicy selects how a Master restarts its crashed Robots. A Robot
+// crashes when its work function panics, when its work function returns with
+// RestartOnWorkReturn set, or when a required Device reports a failure to the
+// Robot's Supervisor with RestartOnUnhealthy set. A crashed Robot is stopped,
+// then started again after a backoff, as many times as needed for it to
+// start.
+type RestartPolicy struct {
+	// MaxRestarts is the number of restarts after which a crashed Robot is
+	// left stopped. Zero means no limit.
+	MaxRestarts int
+	// InitialBackoff is the wait before the first restart, doubled for
+	// every following restart
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between restarts, which does not grow if
+	// MaxBackoff is zero
+	MaxBackoff time.Duration
+	// ResetAfter, unless zero, is how long a Robot must run without
+	// crashing for its restarts to be counted from zero again
+	ResetAfter time.Duration
+	// RestartOnWorkReturn treats the return of the work function as a
+	// crash, for the work functions looping until the Robot stops
+	RestartOnWorkReturn bool
+	// RestartOnUnhealthy treats an Unhealthy Device, which has not been
+	// started with StartOptional, as a crash
+	RestartOnUnhealthy bool
+}
+
+// RobotRestart is the data published with the RobotRestarted event.
+type RobotRestart struct {
+	// Robot is the name of the Robot
+	Robot string
+	// Reason describes the crash, e.g. "panic in work: boom"
+	Reason string
+	// Attempt is the number of restarts so far, starting at 1
+	Attempt int
+	// Err is the error of the restart, nil if the Robot is running again
+	Err error
+}
+
+// The causes of the crash of a Robot
+type crashCause int
+
+const (
+	crashPanic crashCause = iota
+	crashWorkReturn
+	crashUnhealthy
+)
+
+// restarts tracks the restarts of the Robots of a Master
+type restarts struct {
+	mutex      sync.Mutex
+	policy     *RestartPolicy
+	attempts   map[*Robot]int
+	last       map[*Robot]time.Time
+	restarting map[*Robot]bool
+}
+
+// SetRestartPolicy sets how the Master restarts its crashed Robots. A nil
+// policy, the default, leaves them as they are.
+func (g *Master) SetRestartPolicy(policy *RestartPolicy) {
+	g.restarts.mutex.Lock()
+	defer g.restarts.mutex.Unlock()
+	g.restarts.policy = policy
+	g.restarts.attempts = make(map[*Robot]int)
+	g.restarts.last = make(map[*Robot]time.Time)
+	g.restarts.restarting = make(map[*Robot]bool)
+}
+
+// RestartPolicy returns how the Master restarts its crashed Robots, or nil
+func (g *Master) RestartPolicy() *RestartPolicy {
+	g.restarts.mutex.Lock()
+	defer g.restarts.mutex.Unlock()
+	return g.restarts.policy
+}
+
+// restartCrashed restarts r, which crashed for reason, in the background
+// if the RestartPolicy covers cause. It returns whether r is restarted, or
+// stopped for having crashed too many times, in which case r has stopped
+// when it returns.
+func (g *Master) restartCrashed(r *Robot, cause crashCause, reason string) bool {
+	s := &g.restarts
+	s.mutex.Lock()
+
+	p := s.policy
+	switch {
+	case p == nil || !g.Running() || s.restarting[r]:
+		s.mutex.Unlock()
+		return false
+	case cause == crashWorkReturn && !p.RestartOnWorkReturn:
+		s.mutex.Unlock()
+		return false
+	case cause == crashUnhealthy && !p.RestartOnUnhealthy:
+		s.mutex.Unlock()
+		return false
+	}
+
+	now := DefaultClock().Now()
+	if p.ResetAfter > 0 && now.Sub(s.last[r]) > p.ResetAfter {
+		s.attempts[r] = 0
+	}
+	if p.MaxRestarts > 0 && s.attempts[r] >= p.MaxRestarts {
+		restarts := s.attempts[r]
+		// the crashes reported meanwhile are not handled twice
+		s.restarting[r] = true
+		s.mutex.Unlock()
+		r.Logger().Error("Robot crashed too many times, stopping it", "robot", r.Name,
+			"reason", reason, "restarts", restarts)
+		if r.Running() {
+			r.Stop()
+		}
+		s.mutex.Lock()
+		delete(s.restarting, r)
+		s.mutex.Unlock()
+		return true
+	}
+	s.attempts[r]++
+	attempt := s.attempts[r]
+	s.restarting[r] = true
+	s.mutex.Unlock()
+
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+		if backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+
+	go func() {
+		r.Logger().Warn("Restarting crashed Robot", "robot", r.Name, "reason", reason,
+			"attempt", attempt, "backoff", backoff)
+		if r.Running() {
+			r.Stop()
+		}
+		DefaultClock().Sleep(backoff)
+
+		var err error
+		running := g.Running()
+		if running {
+			// the timers the crashed Work set up after Stop must not tick
+			// along with those of the new one
+			r.stopTimers()
+			err = r.start()
+		}
+
+		s.mutex.Lock()
+		s.last[r] = DefaultClock().Now()
+		delete(s.restarting, r)
+		s.mutex.Unlock()
+
+		if !running {
+			return
+		}
+		g.Publish(RobotRestarted, RobotRestart{Robot: r.Name, Reason: reason, Attempt: attempt, Err: err})
+		if err != nil {
+			r.Logger().Error("Robot failed to restart", "robot", r.Name, "error", err)
+			g.restartCrashed(r, cause, reason)
+		}
+	}()
+	return true
+}
+
+// crashed reports a crash of the Robot to its Master, returning whether the
+// Master restarts it
+func (r *Robot) crashed(cause crashCause, reason string) bool {
+	if r.master == nil {
+		return false
+	}
+	return r.master.restartCrashed(r, cause, reason)
+}
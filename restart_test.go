@@ -0,0 +1,160 @@
+package gobot
+
+import (
+	"errors"
+	"log"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func initTestRestartMaster(work func(), policy *RestartPolicy) (*Master, *Robot) {
+	log.SetOutput(&NullReadWriteCloser{})
+	g := NewMaster()
+	r := g.AddRobot(NewRobot("crashy", work))
+	g.SetRestartPolicy(policy)
+	return g, r
+}
+
+// startTestRestartMaster starts g until the returned function is called
+func startTestRestartMaster(t *testing.T, g *Master) func() {
+	interrupt := make(chan chan os.Signal, 1)
+	g.trap = func(c chan os.Signal) { interrupt <- c }
+	done := make(chan error)
+	go func() { done <- g.Start() }()
+	c := <-interrupt
+	return func() {
+		c <- os.Interrupt
+		gobottest.Assert(t, <-done, nil)
+	}
+}
+
+func waitRestart(t *testing.T, restarts chan interface{}) RobotRestart {
+	select {
+	case data := <-restarts:
+		return data.(RobotRestart)
+	case <-time.After(time.Second):
+		t.Fatalf("RobotRestarted event was not published")
+	}
+	return RobotRestart{}
+}
+
+func TestMasterRestartPanickingWork(t *testing.T) {
+	var runs int32
+	g, r := initTestRestartMaster(func() {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			panic("boom")
+		}
+	}, &RestartPolicy{InitialBackoff: time.Millisecond})
+	restarts := make(chan interface{}, 1)
+	g.On(RobotRestarted, func(data interface{}) { restarts <- data })
+
+	defer startTestRestartMaster(t, g)()
+
+	restart := waitRestart(t, restarts)
+	gobottest.Assert(t, restart, RobotRestart{Robot: "crashy", Reason: "panic in work: boom", Attempt: 1})
+	gobottest.Assert(t, r.Running(), true)
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&runs) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	gobottest.Assert(t, atomic.LoadInt32(&runs), int32(2))
+}
+
+func TestMasterRestartLimit(t *testing.T) {
+	var runs int32
+	g, r := initTestRestartMaster(func() {
+		atomic.AddInt32(&runs, 1)
+		panic("boom")
+	}, &RestartPolicy{MaxRestarts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	restarts := make(chan interface{}, 2)
+	g.On(RobotRestarted, func(data interface{}) { restarts <- data })
+
+	defer startTestRestartMaster(t, g)()
+
+	gobottest.Assert(t, waitRestart(t, restarts).Attempt, 1)
+	gobottest.Assert(t, waitRestart(t, restarts).Attempt, 2)
+
+	deadline := time.Now().Add(time.Second)
+	for r.Running() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	gobottest.Assert(t, r.Running(), false)
+	gobottest.Assert(t, atomic.LoadInt32(&runs), int32(3))
+}
+
+func TestMasterRestartStopsWorkTimers(t *testing.T) {
+	var runs int32
+	var r *Robot
+	g, r := initTestRestartMaster(func() {
+		r.Every(time.Hour, func() {})
+		if atomic.AddInt32(&runs, 1) <= 2 {
+			panic("boom")
+		}
+	}, &RestartPolicy{InitialBackoff: time.Millisecond})
+	restarts := make(chan interface{}, 2)
+	g.On(RobotRestarted, func(data interface{}) { restarts <- data })
+
+	defer startTestRestartMaster(t, g)()
+
+	gobottest.Assert(t, waitRestart(t, restarts).Attempt, 1)
+	gobottest.Assert(t, waitRestart(t, restarts).Attempt, 2)
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&runs) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// restarted twice, the Robot ticks for its last Work only
+	r.timersMutex.Lock()
+	defer r.timersMutex.Unlock()
+	gobottest.Assert(t, len(r.timers), 1)
+}
+
+func TestMasterRestartWorkReturn(t *testing.T) {
+	var runs int32
+	g, _ := initTestRestartMaster(func() {
+		atomic.AddInt32(&runs, 1)
+	}, &RestartPolicy{MaxRestarts: 1, RestartOnWorkReturn: true})
+	restarts := make(chan interface{}, 1)
+	g.On(RobotRestarted, func(data interface{}) { restarts <- data })
+
+	defer startTestRestartMaster(t, g)()
+
+	gobottest.Assert(t, waitRestart(t, restarts).Reason, "work returned")
+}
+
+func TestMasterNoRestartPolicy(t *testing.T) {
+	g, r := initTestRestartMaster(func() {}, nil)
+	gobottest.Assert(t, g.RestartPolicy(), (*RestartPolicy)(nil))
+	defer startTestRestartMaster(t, g)()
+
+	// the work returning is not a crash by default
+	g.SetRestartPolicy(&RestartPolicy{})
+	gobottest.Assert(t, r.crashed(crashWorkReturn, "work returned"), false)
+	gobottest.Assert(t, r.Running(), true)
+}
+
+func TestMasterRestartUnhealthyDevice(t *testing.T) {
+	log.SetOutput(&NullReadWriteCloser{})
+	r, driver := newTestSupervisedRobot()
+	s := NewSupervisor(time.Hour)
+	s.AutoReconnect = false
+	r.Supervise(s)
+
+	g := NewMaster()
+	g.AddRobot(r)
+	g.SetRestartPolicy(&RestartPolicy{RestartOnUnhealthy: true})
+	restarts := make(chan interface{}, 1)
+	g.On(RobotRestarted, func(data interface{}) { restarts <- data })
+
+	defer startTestRestartMaster(t, g)()
+
+	driver.setHealth(errors.New("bus dropped"))
+	s.Check()
+	restart := waitRestart(t, restarts)
+	gobottest.Assert(t, restart.Reason, "device Device1 unhealthy: bus dropped")
+	gobottest.Assert(t, restart.Err, nil)
+}
@@ -1,11 +1,14 @@
 package gobot
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
+	"reflect"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 )
@@ -13,15 +16,20 @@ import (
 // JSONRobot a JSON representation of a Robot.
 type JSONRobot struct {
 	Name        string            `json:"name"`
+	State       string            `json:"state"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
 	Commands    []string          `json:"commands"`
 	Connections []*JSONConnection `json:"connections"`
 	Devices     []*JSONDevice     `json:"devices"`
+	Tasks       []*JSONTask       `json:"tasks,omitempty"`
 }
 
 // NewJSONRobot returns a JSONRobot given a Robot.
 func NewJSONRobot(robot *Robot) *JSONRobot {
 	jsonRobot := &JSONRobot{
 		Name:        robot.Name,
+		State:       robot.State(),
+		Metadata:    robot.Metadata,
 		Commands:    []string{},
 		Connections: []*JSONConnection{},
 		Devices:     []*JSONDevice{},
@@ -33,9 +41,17 @@ func NewJSONRobot(robot *Robot) *JSONRobot {
 
 	robot.Devices().Each(func(device Device) {
 		jsonDevice := NewJSONDevice(device)
-		jsonRobot.Connections = append(jsonRobot.Connections, NewJSONConnection(robot.Connection(jsonDevice.Connection)))
+		jsonDevice.Metadata = robot.DeviceMetadata(device.Name())
+		// virtual Devices have no Connection
+		if connection := robot.Connection(jsonDevice.Connection); connection != nil {
+			jsonRobot.Connections = append(jsonRobot.Connections, NewJSONConnection(connection))
+		}
 		jsonRobot.Devices = append(jsonRobot.Devices, jsonDevice)
 	})
+
+	for _, task := range robot.Tasks() {
+		jsonRobot.Tasks = append(jsonRobot.Tasks, NewJSONTask(task))
+	}
 	return jsonRobot
 }
 
@@ -45,16 +61,74 @@ func NewJSONRobot(robot *Robot) *JSONRobot {
 type Robot struct {
 	Name        string
 	Work        func()
+	Metadata    map[string]string
 	connections *Connections
 	devices     *Devices
 	trap        func(chan os.Signal)
 	AutoRun     bool
 	running     atomic.Value
+	readiness   readiness
 	done        chan bool
+	logger      Logger
+	supervisor  *Supervisor
+	watchdog    *Watchdog
+	scheduler   *Scheduler
+	tasks       []*Task
+	tasksMutex  sync.Mutex
+	timers      map[int]func()
+	timerID     int
+	timersMutex sync.Mutex
+
+	// StartTimeout limits how long each Device may take to start. Zero means
+	// no limit. The Start of a Device is not cancelled when it times out: the
+	// Device is counted as failed, and halted if its Start returns
+	// successfully later on.
+	StartTimeout  time.Duration
+	startTimeouts map[string]time.Duration
+	dependencies  map[string][]string
+	startPolicies map[string]StartPolicy
+
+	deviceMetadata map[string]map[string]string
+
+	safeStatePriorities map[string]int
+
+	// PanicPolicy selects what happens after a panic in the work function or
+	// in a callback of the Robot's Every and After methods. A Panic event is
+	// published in every case.
+	PanicPolicy PanicPolicy
+	master      *Master
+
+	// TelemetryInterval enables the periodic Telemetry event when set
+	TelemetryInterval time.Duration
+	telemetry         *telemetry
+
+	// EventHistorySize enables the history of the last Events of the Devices
+	// when set, see EventHistory. EventRetention, unless zero, is how long
+	// the Events are kept.
+	EventHistorySize int
+	EventRetention   time.Duration
+
+	// ReleaseTimeout is how long Stop waits for the Devices and Connections
+	// to release the resources they registered with Acquire or Go, before
+	// logging the leaked ones. DefaultReleaseTimeout when zero.
+	ReleaseTimeout time.Duration
+
+	// DryRun starts the Robot without hardware: the Connections which are
+	// DryRunners log the hardware operations instead of executing them, the
+	// other ones are neither connected nor finalized.
+	DryRun bool
+
 	Commander
 	Eventer
 }
 
+// DryRunner is implemented by the Connections able to log the hardware
+// operations instead of executing them, such as the dryrun Adaptor.
+type DryRunner interface {
+	// SetDryRun enables or disables the dry-run mode
+	SetDryRun(enable bool)
+}
+
 // Robots is a collection of Robot
 type Robots []*Robot
 
@@ -112,6 +186,8 @@ func NewRobot(v ...interface{}) *Robot {
 		trap: func(c chan os.Signal) {
 			signal.Notify(c, os.Interrupt)
 		},
+		scheduler: NewScheduler(),
+		telemetry: newTelemetry(),
 		AutoRun:   true,
 		Work:      nil,
 		Eventer:   NewEventer(),
@@ -123,24 +199,31 @@ func NewRobot(v ...interface{}) *Robot {
 		case string:
 			r.Name = v[i].(string)
 		case []Connection:
-			log.Println("Initializing connections...")
+			r.Logger().Info("Initializing connections...")
 			for _, connection := range v[i].([]Connection) {
 				c := r.AddConnection(connection)
-				log.Println("Initializing connection", c.Name(), "...")
+				r.Logger().Info("Initializing connection", "connection", c.Name())
 			}
 		case []Device:
-			log.Println("Initializing devices...")
+			r.Logger().Info("Initializing devices...")
 			for _, device := range v[i].([]Device) {
 				d := r.AddDevice(device)
-				log.Println("Initializing device", d.Name(), "...")
+				r.Logger().Info("Initializing device", "device", d.Name())
 			}
 		case func():
 			r.Work = v[i].(func())
 		}
 	}
 
+	r.AddEvent(Panic)
+	r.AddEvent(Telemetry)
+	r.AddEvent(DeviceFailure)
+	r.AddEvent(EmergencyStopped)
+	r.AddEvent(ConnectionStarted)
+	r.AddEvent(DeviceStarted)
+	r.AddEvent(RobotReady)
 	r.running.Store(false)
-	log.Println("Robot", r.Name, "initialized.")
+	r.Logger().Info("Robot initialized", "robot", r.Name)
 
 	return r
 }
@@ -150,65 +233,249 @@ func (r *Robot) Start(args ...interface{}) (err error) {
 	if len(args) > 0 && args[0] != nil {
 		r.AutoRun = args[0].(bool)
 	}
-	log.Println("Starting Robot", r.Name, "...")
-	if cerr := r.Connections().Start(); cerr != nil {
+	if err = r.start(); err != nil {
+		return
+	}
+
+	if r.AutoRun {
+		c := make(chan os.Signal, 1)
+		r.trap(c)
+
+		// waiting for interrupt coming on the channel
+		<-c
+
+		// Stop calls the Stop method on itself, if we are "auto-running".
+		r.Stop()
+	}
+
+	return
+}
+
+// start starts the Robot's Connections, Devices, and work without waiting for
+// an interrupt. The startup events are published along the way.
+func (r *Robot) start() (err error) {
+	r.Logger().Info("Starting Robot", "robot", r.Name)
+	ctx, span := StartSpan(context.Background(), RobotStartSpan, Attr("gobot.robot", r.Name))
+	defer func() { EndSpan(span, err) }()
+	r.readiness.starting()
+	defer func() { r.readiness.started(err) }()
+	r.injectLoggers()
+	r.telemetry.clearFailures()
+	if r.dryRun() {
+		r.Connections().Each(func(c Connection) {
+			if _, ok := c.(DryRunner); !ok {
+				r.Logger().Warn("Connection does not support dry-run, not connecting", "connection", c.Name())
+			}
+		})
+	}
+	if cerr := r.activeConnections().start(r.Logger(), func(c Connection) {
+		r.Publish(ConnectionStarted, c.Name())
+	}); cerr != nil {
+		r.telemetry.recordErrors(cerr)
 		err = multierror.Append(err, cerr)
-		log.Println(err)
+		r.Logger().Error(err.Error())
 		return
 	}
-	if derr := r.Devices().Start(); derr != nil {
+	o := r.startOptions()
+	o.ctx = ctx
+	if derr := r.Devices().start(r.Logger(), o); derr != nil {
+		r.telemetry.recordErrors(derr)
 		err = multierror.Append(err, derr)
-		log.Println(err)
+		r.Logger().Error(err.Error())
 		return
 	}
 	if r.Work == nil {
 		r.Work = func() {}
 	}
+	if r.supervisor != nil {
+		r.supervisor.Start()
+	}
+	if r.watchdog != nil {
+		r.watchdog.Start()
+	}
+
+	r.startTelemetry()
 
-	log.Println("Starting work...")
+	r.Logger().Info("Starting work...")
 	go func() {
-		r.Work()
+		panicked := false
+		protect("work", func(p PanicReport) {
+			panicked = true
+			r.handlePanic(p)
+		}, r.Work)
+		if !panicked {
+			r.crashed(crashWorkReturn, "work returned")
+		}
 		<-r.done
 	}()
+	r.startTasks()
 
 	r.running.Store(true)
-	if r.AutoRun {
-		c := make(chan os.Signal, 1)
-		r.trap(c)
-
-		// waiting for interrupt coming on the channel
-		<-c
-
-		// Stop calls the Stop method on itself, if we are "auto-running".
-		r.Stop()
-	}
-
+	r.Logger().Info("Robot ready", "robot", r.Name)
+	r.Publish(RobotReady, r.Name)
 	return
 }
 
 // Stop stops a Robot's connections and Devices
 func (r *Robot) Stop() error {
 	var result error
-	log.Println("Stopping Robot", r.Name, "...")
-	err := r.Devices().Halt()
+	r.Logger().Info("Stopping Robot", "robot", r.Name)
+	ctx, span := StartSpan(context.Background(), RobotStopSpan, Attr("gobot.robot", r.Name))
+	defer func() { EndSpan(span, result) }()
+	if r.supervisor != nil {
+		r.supervisor.Stop()
+	}
+	if r.watchdog != nil {
+		r.watchdog.Stop()
+	}
+	r.scheduler.Stop()
+	r.stopTasks()
+	r.stopTimers()
+	r.stopTelemetry()
+	err := r.Devices().halt(ctx)
 	if err != nil {
 		result = multierror.Append(result, err)
 	}
-	err = r.Connections().Finalize()
+	err = r.activeConnections().Finalize()
 	if err != nil {
 		result = multierror.Append(result, err)
 	}
+	r.checkReleased()
 
 	r.done <- true
 	r.running.Store(false)
+	r.readiness.stopped()
 	return result
 }
 
+// checkReleased logs the resources the Devices and Connections still hold
+// once halted and finalized
+func (r *Robot) checkReleased() {
+	timeout := r.ReleaseTimeout
+	if timeout <= 0 {
+		timeout = DefaultReleaseTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	owners := []interface{}{}
+	r.Devices().Each(func(d Device) { owners = append(owners, d) })
+	r.Connections().Each(func(c Connection) { owners = append(owners, c) })
+	for _, owner := range owners {
+		if err := WaitReleased(owner, time.Until(deadline)); err != nil {
+			r.Logger().Warn("Resources leaked", "robot", r.Name, "error", err)
+		}
+	}
+}
+
+// dryRun returns whether the Robot, or its Master, is in dry-run mode
+func (r *Robot) dryRun() bool {
+	return r.DryRun || (r.master != nil && r.master.DryRun)
+}
+
+// activeConnections returns the Connections to connect and finalize: in
+// dry-run mode, only the DryRunners, set to dry-run.
+func (r *Robot) activeConnections() *Connections {
+	if !r.dryRun() {
+		return r.Connections()
+	}
+	active := Connections{}
+	r.Connections().Each(func(c Connection) {
+		if d, ok := c.(DryRunner); ok {
+			d.SetDryRun(true)
+			active = append(active, c)
+		}
+	})
+	return &active
+}
+
 // Running returns if the Robot is currently started or not
 func (r *Robot) Running() bool {
 	return r.running.Load().(bool)
 }
 
+// Logger returns the Logger used by the Robot. Unless one has been set using
+// SetLogger, this is the DefaultLogger scoped to the Robot's name.
+func (r *Robot) Logger() Logger {
+	if r.logger != nil {
+		return r.logger
+	}
+	return DefaultLogger().WithComponent(r.Name)
+}
+
+// SetLogger sets the Logger used by the Robot. When the Robot is started, its
+// Connections and Devices that implement Loggable receive a Logger scoped to
+// their own name.
+func (r *Robot) SetLogger(l Logger) {
+	r.logger = l
+}
+
+// injectLoggers hands a component scoped Logger to each Loggable Connection
+// and Device.
+func (r *Robot) injectLoggers() {
+	r.Connections().Each(func(c Connection) {
+		if l, ok := c.(Loggable); ok {
+			l.SetLogger(r.Logger().WithComponent(c.Name()))
+		}
+	})
+	all := r.Devices().flatten()
+	all.Each(func(d Device) {
+		if l, ok := d.(Loggable); ok {
+			l.SetLogger(r.Logger().WithComponent(d.Name()))
+		}
+	})
+}
+
+// DependsOn declares that the named Device must only be started once each of
+// names, which are Devices or Connections of the Robot, has started
+// successfully. Devices without dependencies between them are started in
+// parallel, unless they share a Connection.
+func (r *Robot) DependsOn(device string, names ...string) {
+	if r.dependencies == nil {
+		r.dependencies = make(map[string][]string)
+	}
+	r.dependencies[device] = append(r.dependencies[device], names...)
+}
+
+// SetStartTimeout overrides the StartTimeout of the named Device.
+func (r *Robot) SetStartTimeout(device string, timeout time.Duration) {
+	if r.startTimeouts == nil {
+		r.startTimeouts = make(map[string]time.Duration)
+	}
+	r.startTimeouts[device] = timeout
+}
+
+// SetStartPolicy sets the StartPolicy of the named Device. Devices are
+// StartRequired unless set otherwise.
+func (r *Robot) SetStartPolicy(device string, policy StartPolicy) {
+	if r.startPolicies == nil {
+		r.startPolicies = make(map[string]StartPolicy)
+	}
+	r.startPolicies[device] = policy
+}
+
+func (r *Robot) startOptions() *startOptions {
+	return &startOptions{
+		connections:  r.connections,
+		dependencies: r.dependencies,
+		timeout:      r.StartTimeout,
+		parallel:     true,
+		timeouts:     r.startTimeouts,
+		policies:     r.startPolicies,
+		failed: func(device Device, err error) {
+			r.telemetry.recordErrors(err)
+			r.Publish(DeviceFailure, err)
+		},
+		started: func(device Device) {
+			r.Publish(DeviceStarted, device.Name())
+		},
+	}
+}
+
+// Scheduler returns the Robot's Scheduler. Jobs added to it are stopped when
+// the Robot is stopped.
+func (r *Robot) Scheduler() *Scheduler {
+	return r.scheduler
+}
+
 // Devices returns all devices associated with this Robot.
 func (r *Robot) Devices() *Devices {
 	return r.devices
@@ -221,12 +488,13 @@ func (r *Robot) AddDevice(d Device) Device {
 	return d
 }
 
-// Device returns a device given a name. Returns nil if the Device does not exist.
+// Device returns a device given a name, which may be a component of a
+// Composite Device. Returns nil if the Device does not exist.
 func (r *Robot) Device(name string) Device {
 	if r == nil {
 		return nil
 	}
-	for _, device := range *r.devices {
+	for _, device := range r.devices.flatten() {
 		if device.Name() == name {
 			return device
 		}
@@ -234,6 +502,27 @@ func (r *Robot) Device(name string) Device {
 	return nil
 }
 
+// DevicesByCapability returns the devices of the robot implementing the
+// capability interface pointed to by capability, so that the robot can be
+// written against capabilities rather than drivers:
+//
+//	for _, d := range *robot.DevicesByCapability((*gobot.TemperatureSensor)(nil)) {
+//		t, _ := d.(gobot.TemperatureSensor).Temperature()
+//	}
+//
+// The components of Composite Devices are included. It panics when
+// capability does not point to an interface.
+func (r *Robot) DevicesByCapability(capability interface{}) *Devices {
+	t := capabilityType(capability)
+	devices := &Devices{}
+	for _, device := range r.devices.flatten() {
+		if reflect.TypeOf(device).Implements(t) {
+			*devices = append(*devices, device)
+		}
+	}
+	return devices
+}
+
 // Connections returns all connections associated with this robot.
 func (r *Robot) Connections() *Connections {
 	return r.connections
@@ -0,0 +1,84 @@
+package gobot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// EmergencyStopped event, published by the Robot when EmergencyStop is
+// called. Its data is the error returned by the SafeStaters, or nil.
+const EmergencyStopped = "estop"
+
+// SafeStater is the interface which describes a Device which is able to put
+// its hardware in a safe state at once, e.g. a motor stopping, a relay opening
+// or a servo detaching.
+type SafeStater interface {
+	// SafeState puts the hardware in a safe state
+	SafeState() error
+}
+
+// SetSafeStatePriority sets the priority of the named Device during an
+// EmergencyStop. The SafeStaters with the highest priority are put in a safe
+// state first, those with the same priority, zero by default, in the order
+// they were added.
+func (r *Robot) SetSafeStatePriority(device string, priority int) {
+	if r.safeStatePriorities == nil {
+		r.safeStatePriorities = make(map[string]int)
+	}
+	r.safeStatePriorities[device] = priority
+}
+
+// EmergencyStop puts every Device implementing SafeStater, including the
+// components of composite Devices, in a safe state in priority order, then
+// stops the Robot if it is running. A failing or panicking SafeStater does not
+// prevent the others from being put in a safe state. The Watchdog's SafeState
+// can be set to a function calling EmergencyStop.
+func (r *Robot) EmergencyStop() error {
+	r.Logger().Warn("Emergency stop", "robot", r.Name)
+	err := r.safeState()
+	r.Publish(EmergencyStopped, err)
+	if r.Running() {
+		if serr := r.Stop(); serr != nil {
+			err = JoinErrors(err, serr)
+		}
+	}
+	return err
+}
+
+func (r *Robot) safeState() error {
+	staters := []SafeStater{}
+	names := []string{}
+	all := r.Devices().flatten()
+	all.Each(func(d Device) {
+		if s, ok := d.(SafeStater); ok {
+			staters = append(staters, s)
+			names = append(names, d.Name())
+		}
+	})
+	order := make([]int, len(staters))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return r.safeStatePriorities[names[order[i]]] > r.safeStatePriorities[names[order[j]]]
+	})
+
+	errs := []error{}
+	for _, i := range order {
+		if err := callSafeState(staters[i]); err != nil {
+			r.Logger().Error("Failed to put device in safe state", "device", names[i], "error", err)
+			errs = append(errs, WrapError("safe state", names[i], err))
+		}
+	}
+	return JoinErrors(errs...)
+}
+
+// callSafeState calls SafeState, returning a panic as an error
+func callSafeState(s SafeStater) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return s.SafeState()
+}
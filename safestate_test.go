@@ -0,0 +1,75 @@
+package gobot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+// safeStateTestDriver records when it is put in a safe state
+type safeStateTestDriver struct {
+	*testDriver
+	log *[]string
+	err error
+	bad bool
+}
+
+func (s *safeStateTestDriver) SafeState() error {
+	*s.log = append(*s.log, s.Name())
+	if s.bad {
+		panic("stuck")
+	}
+	return s.err
+}
+
+func newSafeStateTestDriver(log *[]string, name string) *safeStateTestDriver {
+	return &safeStateTestDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), name, "0"),
+		log:        log,
+	}
+}
+
+func TestRobotEmergencyStop(t *testing.T) {
+	log := &[]string{}
+	motor := newSafeStateTestDriver(log, "motor")
+	relay := newSafeStateTestDriver(log, "relay")
+	servo := newSafeStateTestDriver(log, "servo")
+	arm := NewCompositeDevice("arm", servo)
+	led := newTestDriver(newTestAdaptor("Connection1", "/dev/null"), "led", "1")
+	r := NewRobot("estop", []Device{led, relay, arm, motor})
+	r.SetSafeStatePriority("motor", 10)
+	r.SetSafeStatePriority("servo", 5)
+	gobottest.Assert(t, r.Start(false), nil)
+	events := r.Subscribe()
+
+	gobottest.Assert(t, r.EmergencyStop(), nil)
+	gobottest.Assert(t, *log, []string{"motor", "servo", "relay"})
+	gobottest.Assert(t, r.Running(), false)
+	evt := <-events
+	gobottest.Assert(t, evt.Name, EmergencyStopped)
+	gobottest.Assert(t, evt.Data, nil)
+
+	// a stopped Robot can still be put in a safe state
+	*log = []string{}
+	gobottest.Assert(t, r.EmergencyStop(), nil)
+	gobottest.Assert(t, *log, []string{"motor", "servo", "relay"})
+}
+
+func TestRobotEmergencyStopErrors(t *testing.T) {
+	log := &[]string{}
+	motor := newSafeStateTestDriver(log, "motor")
+	motor.err = errors.New("bus dropped")
+	relay := newSafeStateTestDriver(log, "relay")
+	relay.bad = true
+	servo := newSafeStateTestDriver(log, "servo")
+	r := NewRobot("estop", []Device{motor, relay, servo})
+
+	err := r.EmergencyStop()
+	gobottest.Assert(t, *log, []string{"motor", "relay", "servo"})
+	errs := Errors(err)
+	gobottest.Assert(t, len(errs), 2)
+	gobottest.Assert(t, errs[0].Error(), "safe state motor: bus dropped")
+	gobottest.Assert(t, strings.Contains(errs[1].Error(), "safe state relay: panic: stuck"), true)
+}
@@ -0,0 +1,203 @@
+package gobot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Unhealthy event
+	Unhealthy = "unhealthy"
+	// Recovered event
+	Recovered = "recovered"
+)
+
+// Healther is the interface which describes a Driver or Adaptor which is able
+// to report whether it is still working correctly, e.g. that the I2C bus it
+// talks to has not dropped.
+type Healther interface {
+	// Health returns nil when healthy, otherwise the error describing the fault
+	Health() error
+}
+
+// HealthStatus is the data published with the Unhealthy and Recovered events.
+type HealthStatus struct {
+	// Name of the Connection or Device
+	Name string
+	// Err is the reported fault, nil when recovered
+	Err error
+	// Attempts is the number of reconnection attempts made so far
+	Attempts int
+}
+
+// Supervisor periodically checks the health of a Robot's Connections and
+// Devices implementing Healther, publishes Unhealthy and Recovered events on the
+// Robot, and optionally reconnects failed components with exponential backoff.
+type Supervisor struct {
+	// Interval between health checks
+	Interval time.Duration
+	// AutoReconnect re-runs Connect (for Connections) or Start (for Devices)
+	// on unhealthy components
+	AutoReconnect bool
+	// InitialBackoff is the wait before the first reconnection attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between reconnection attempts
+	MaxBackoff time.Duration
+
+	robot  *Robot
+	states map[interface{}]*healthState
+	halt   chan bool
+	mutex  sync.Mutex
+}
+
+type healthState struct {
+	err      error
+	attempts int
+	backoff  time.Duration
+	next     time.Time
+}
+
+// NewSupervisor returns a new Supervisor which checks health every interval.
+// AutoReconnect is enabled with a backoff starting at interval and capped at
+// one minute.
+func NewSupervisor(interval time.Duration) *Supervisor {
+	return &Supervisor{
+		Interval:       interval,
+		AutoReconnect:  true,
+		InitialBackoff: interval,
+		MaxBackoff:     time.Minute,
+		states:         make(map[interface{}]*healthState),
+	}
+}
+
+// Supervise attaches s to the Robot. The Supervisor is started and stopped
+// along with the Robot.
+func (r *Robot) Supervise(s *Supervisor) {
+	s.robot = r
+	r.supervisor = s
+	r.AddEvent(Unhealthy)
+	r.AddEvent(Recovered)
+}
+
+// Supervisor returns the Supervisor attached to the Robot, or nil.
+func (r *Robot) Supervisor() *Supervisor {
+	return r.supervisor
+}
+
+// Start begins the periodic health checks
+func (s *Supervisor) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.halt != nil {
+		return
+	}
+	s.halt = make(chan bool)
+	go func(halt chan bool) {
+		ticker := DefaultClock().NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				s.Check()
+			case <-halt:
+				return
+			}
+		}
+	}(s.halt)
+}
+
+// Stop ends the periodic health checks
+func (s *Supervisor) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.halt != nil {
+		close(s.halt)
+		s.halt = nil
+	}
+}
+
+// Check runs a single health check pass over the Robot's Connections and
+// Devices, publishing events and attempting reconnections as needed.
+func (s *Supervisor) Check() {
+	s.robot.Connections().Each(func(c Connection) {
+		s.check(c, c.Name(), c.Connect)
+	})
+	s.robot.Devices().Each(func(d Device) {
+		s.check(d, d.Name(), d.Start)
+	})
+}
+
+// Healthy returns true if no supervised component is currently unhealthy.
+func (s *Supervisor) Healthy() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.states) == 0
+}
+
+// healthErr returns the fault of component if it is currently unhealthy.
+func (s *Supervisor) healthErr(component interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if state, ok := s.states[component]; ok {
+		return state.err
+	}
+	return nil
+}
+
+func (s *Supervisor) check(component interface{}, name string, restart func() error) {
+	h, ok := component.(Healther)
+	if !ok {
+		return
+	}
+	err := h.Health()
+
+	s.mutex.Lock()
+	state, unhealthy := s.states[component]
+	switch {
+	case err == nil && unhealthy:
+		delete(s.states, component)
+		s.mutex.Unlock()
+		s.robot.Publish(Recovered, HealthStatus{Name: name, Attempts: state.attempts})
+		return
+	case err == nil:
+		s.mutex.Unlock()
+		return
+	case !unhealthy:
+		state = &healthState{
+			err:     err,
+			backoff: s.InitialBackoff,
+			next:    DefaultClock().Now().Add(s.InitialBackoff),
+		}
+		s.states[component] = state
+		s.mutex.Unlock()
+		s.robot.Logger().Warn("Unhealthy", "component", name, "error", err)
+		s.robot.Publish(Unhealthy, HealthStatus{Name: name, Err: err})
+		if d, ok := component.(Device); ok && !s.robot.startOptions().policyOf(d).Optional {
+			s.robot.crashed(crashUnhealthy, fmt.Sprintf("device %v unhealthy: %v", name, err))
+		}
+		return
+	}
+	state.err = err
+	if !s.AutoReconnect || DefaultClock().Now().Before(state.next) {
+		s.mutex.Unlock()
+		return
+	}
+	state.attempts++
+	attempts := state.attempts
+	s.mutex.Unlock()
+
+	s.robot.Logger().Info("Reconnecting", "component", name, "attempt", attempts)
+	rerr := restart()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if rerr != nil {
+		state.err = rerr
+		state.backoff *= 2
+		if state.backoff > s.MaxBackoff {
+			state.backoff = s.MaxBackoff
+		}
+	}
+	state.next = DefaultClock().Now().Add(state.backoff)
+}
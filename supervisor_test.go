@@ -0,0 +1,149 @@
+package gobot
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testHealthDriver struct {
+	*testDriver
+	mtx    sync.Mutex
+	health error
+	starts int
+}
+
+func (t *testHealthDriver) Health() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.health
+}
+
+func (t *testHealthDriver) Start() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.starts++
+	return nil
+}
+
+func (t *testHealthDriver) setHealth(err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.health = err
+}
+
+func newTestSupervisedRobot() (*Robot, *testHealthDriver) {
+	adaptor := newTestAdaptor("Connection1", "/dev/null")
+	driver := &testHealthDriver{testDriver: newTestDriver(adaptor, "Device1", "0")}
+	r := NewRobot("supervised", []Connection{adaptor}, []Device{driver})
+	return r, driver
+}
+
+func TestSupervisorEvents(t *testing.T) {
+	r, driver := newTestSupervisedRobot()
+	s := NewSupervisor(time.Hour)
+	s.AutoReconnect = false
+	r.Supervise(s)
+	gobottest.Assert(t, r.Supervisor(), s)
+
+	events := r.Subscribe()
+	e := errors.New("bus dropped")
+	driver.setHealth(e)
+	s.Check()
+	gobottest.Assert(t, s.Healthy(), false)
+
+	evt := <-events
+	gobottest.Assert(t, evt.Name, Unhealthy)
+	gobottest.Assert(t, evt.Data, HealthStatus{Name: "Device1", Err: e})
+
+	driver.setHealth(nil)
+	s.Check()
+	gobottest.Assert(t, s.Healthy(), true)
+
+	evt = <-events
+	gobottest.Assert(t, evt.Name, Recovered)
+	gobottest.Assert(t, evt.Data, HealthStatus{Name: "Device1"})
+}
+
+func TestSupervisorReconnectBackoff(t *testing.T) {
+	r, driver := newTestSupervisedRobot()
+	s := NewSupervisor(time.Hour)
+	s.InitialBackoff = 0
+	r.Supervise(s)
+
+	e := errors.New("start failure")
+	driver.setHealth(e)
+	s.Check()
+	gobottest.Assert(t, driver.starts, 0)
+
+	s.Check()
+	gobottest.Assert(t, driver.starts, 1)
+
+	state := s.states[Device(driver)]
+	gobottest.Assert(t, state.attempts, 1)
+
+	state.backoff = 40 * time.Second
+	state.next = time.Now()
+	s.robot.Devices().Each(func(d Device) {
+		s.check(d, d.Name(), func() error { return e })
+	})
+	gobottest.Assert(t, state.backoff, time.Minute)
+	gobottest.Assert(t, state.err, e)
+}
+
+func TestSupervisorBackoffClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	r, driver := newTestSupervisedRobot()
+	s := NewSupervisor(time.Hour)
+	s.InitialBackoff = time.Second
+	s.MaxBackoff = 4 * time.Second
+	r.Supervise(s)
+
+	e := errors.New("start failure")
+	attempts := 0
+	check := func() {
+		s.check(driver, driver.Name(), func() error {
+			attempts++
+			return e
+		})
+	}
+	driver.setHealth(e)
+	check()
+	check()
+	gobottest.Assert(t, attempts, 0)
+
+	// the attempts are 1s, 2s, then 4s apart
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		want := attempts + 1
+		clock.Advance(backoff - time.Millisecond)
+		check()
+		gobottest.Assert(t, attempts, want-1)
+		clock.Advance(time.Millisecond)
+		check()
+		gobottest.Assert(t, attempts, want)
+	}
+}
+
+func TestSupervisorStartedWithRobot(t *testing.T) {
+	r, driver := newTestSupervisedRobot()
+	r.Supervise(NewSupervisor(time.Millisecond))
+	sem := make(chan bool)
+	r.Once(Unhealthy, func(data interface{}) {
+		sem <- true
+	})
+	driver.setHealth(errors.New("gone"))
+
+	gobottest.Assert(t, r.Start(false), nil)
+	select {
+	case <-sem:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Unhealthy event was not published")
+	}
+	gobottest.Assert(t, r.Stop(), nil)
+}
@@ -46,14 +46,15 @@ type i2cSmbusIoctlData struct {
 }
 
 type i2cDevice struct {
-	file  File
-	funcs uint64 // adapter functionality mask
+	file     File
+	funcs    uint64 // adapter functionality mask
+	location string
 }
 
 // NewI2cDevice returns an io.ReadWriteCloser with the proper ioctrl given
 // an i2c bus location.
 func NewI2cDevice(location string) (d *i2cDevice, err error) {
-	d = &i2cDevice{}
+	d = &i2cDevice{location: location}
 
 	if d.file, err = OpenFile(location, os.O_RDWR, os.ModeExclusive); err != nil {
 		return
@@ -65,6 +66,9 @@ func NewI2cDevice(location string) (d *i2cDevice, err error) {
 	return
 }
 
+// String returns the location of the bus, such as "/dev/i2c-1"
+func (d *i2cDevice) String() string { return d.location }
+
 func (d *i2cDevice) queryFunctionality() (err error) {
 	_, _, errno := Syscall(
 		syscall.SYS_IOCTL,
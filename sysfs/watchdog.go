@@ -0,0 +1,82 @@
+package sysfs
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	// WDIOC_SETTIMEOUT is the ioctl setting the timeout of a watchdog device
+	WDIOC_SETTIMEOUT = 0xC0045706
+	// watchdogMagicClose disarms the watchdog device when written before
+	// closing it
+	watchdogMagicClose = "V"
+)
+
+// Watchdog is a Linux hardware watchdog device, e.g. "/dev/watchdog", which
+// resets the board unless it is kept alive. It implements the
+// gobot.WatchdogKeeper interface, and is opened by the first Keepalive.
+type Watchdog struct {
+	location string
+	timeout  time.Duration
+	file     File
+}
+
+// NewWatchdog returns a new Watchdog for the device at location. Unless
+// timeout is zero, it is set as the timeout of the device, in whole seconds.
+// The device must be kept alive at a shorter interval.
+func NewWatchdog(location string, timeout time.Duration) *Watchdog {
+	return &Watchdog{location: location, timeout: timeout}
+}
+
+// Keepalive opens the device if needed, arming it, and keeps it alive
+func (w *Watchdog) Keepalive() (err error) {
+	if w.file == nil {
+		if err = w.open(); err != nil {
+			return
+		}
+	}
+	_, err = w.file.Write([]byte{0})
+	return
+}
+
+// Close disarms and closes the device. Some devices can not be disarmed, and
+// reset the board once the timeout elapses.
+func (w *Watchdog) Close() (err error) {
+	if w.file == nil {
+		return
+	}
+	_, err = w.file.Write([]byte(watchdogMagicClose))
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+	w.file = nil
+	return
+}
+
+func (w *Watchdog) open() (err error) {
+	file, err := OpenFile(w.location, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	if w.timeout > 0 {
+		seconds := int32(w.timeout / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		if _, _, errno := Syscall(
+			syscall.SYS_IOCTL,
+			file.Fd(),
+			WDIOC_SETTIMEOUT,
+			uintptr(unsafe.Pointer(&seconds)),
+		); errno != 0 {
+			file.Write([]byte(watchdogMagicClose))
+			file.Close()
+			return errno
+		}
+	}
+	w.file = file
+	return
+}
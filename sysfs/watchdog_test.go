@@ -0,0 +1,68 @@
+package sysfs
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.WatchdogKeeper = (*Watchdog)(nil)
+
+func TestWatchdog(t *testing.T) {
+	fs := NewMockFilesystem([]string{"/dev/watchdog"})
+	SetFilesystem(fs)
+	var request uintptr
+	SetSyscall(&MockSyscall{
+		Impl: func(trap, a1, a2, a3 uintptr) (r1, r2 uintptr, err syscall.Errno) {
+			request = a2
+			return 0, 0, 0
+		},
+	})
+
+	w := NewWatchdog("/dev/watchdog", 15*time.Second)
+	gobottest.Assert(t, w.Close(), nil)
+	gobottest.Assert(t, fs.Files["/dev/watchdog"].Opened, false)
+
+	gobottest.Assert(t, w.Keepalive(), nil)
+	gobottest.Assert(t, request, uintptr(WDIOC_SETTIMEOUT))
+	gobottest.Assert(t, fs.Files["/dev/watchdog"].Contents, "\x00")
+
+	gobottest.Assert(t, w.Close(), nil)
+	gobottest.Assert(t, fs.Files["/dev/watchdog"].Contents, "V")
+}
+
+func TestWatchdogOpenError(t *testing.T) {
+	SetFilesystem(NewMockFilesystem([]string{}))
+
+	w := NewWatchdog("/dev/watchdog", 0)
+	gobottest.Refute(t, w.Keepalive(), nil)
+	gobottest.Assert(t, w.Close(), nil)
+}
+
+func TestWatchdogTimeoutError(t *testing.T) {
+	fs := NewMockFilesystem([]string{"/dev/watchdog"})
+	SetFilesystem(fs)
+	SetSyscall(&MockSyscall{
+		Impl: func(trap, a1, a2, a3 uintptr) (r1, r2 uintptr, err syscall.Errno) {
+			return 0, 0, syscall.EINVAL
+		},
+	})
+
+	w := NewWatchdog("/dev/watchdog", time.Hour)
+	gobottest.Assert(t, w.Keepalive(), syscall.EINVAL)
+	// the device is disarmed when its timeout can not be set
+	gobottest.Assert(t, fs.Files["/dev/watchdog"].Contents, "V")
+}
+
+func TestWatchdogWriteError(t *testing.T) {
+	fs := NewMockFilesystem([]string{"/dev/watchdog"})
+	SetFilesystem(fs)
+
+	w := NewWatchdog("/dev/watchdog", 0)
+	fs.WithWriteError = true
+	gobottest.Refute(t, w.Keepalive(), nil)
+	gobottest.Refute(t, w.Close(), nil)
+}
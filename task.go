@@ -0,0 +1,279 @@
+package gobot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TaskState is the state of a Task
+type TaskState string
+
+const (
+	// TaskIdle is the state of a Task waiting for its Robot to start
+	TaskIdle TaskState = "idle"
+	// TaskRunning is the state of a running Task
+	TaskRunning TaskState = "running"
+	// TaskPaused is the state of a paused Task, until it is resumed
+	TaskPaused TaskState = "paused"
+	// TaskStopped is the state of a Task which was stopped or returned
+	TaskStopped TaskState = "stopped"
+)
+
+// Task is a named work function of a Robot. Unlike the single Work of the
+// Robot, each Task can be paused, resumed and stopped on its own while the
+// Robot keeps running, e.g. from the API.
+//
+// A Task function cooperates by calling Wait between its steps, which blocks
+// while the Task is paused and returns false once it has been stopped:
+//
+//	robot.AddTask("patrol", func(t *gobot.Task) {
+//		for t.Wait() {
+//			drive.Forward()
+//			time.Sleep(time.Second)
+//		}
+//	})
+type Task struct {
+	name   string
+	fn     func(*Task)
+	robot  *Robot
+	state  TaskState
+	resume chan bool
+	halt   chan bool
+	mutex  sync.Mutex
+}
+
+// JSONTask is a JSON representation of a Task.
+type JSONTask struct {
+	Name  string    `json:"name"`
+	State TaskState `json:"state"`
+}
+
+// NewJSONTask returns a JSONTask given a Task.
+func NewJSONTask(t *Task) *JSONTask {
+	return &JSONTask{Name: t.Name(), State: t.State()}
+}
+
+// AddTask adds a Task named name running fn to the Robot. The Task is
+// started along with the Robot, or at once when the Robot is running, and
+// stopped with it. Adding a Task replaces the Task having the same name.
+func (r *Robot) AddTask(name string, fn func(*Task)) *Task {
+	t := &Task{name: name, fn: fn, robot: r, state: TaskIdle}
+
+	r.tasksMutex.Lock()
+	replaced := false
+	for i, task := range r.tasks {
+		if task.name == name {
+			task.Stop()
+			r.tasks[i] = t
+			replaced = true
+		}
+	}
+	if !replaced {
+		r.tasks = append(r.tasks, t)
+	}
+	r.tasksMutex.Unlock()
+
+	if r.Running() {
+		t.start()
+	}
+	return t
+}
+
+// AddPeriodicTask adds a Task named name calling f every interval, except
+// while it is paused. f does not need to call Wait.
+func (r *Robot) AddPeriodicTask(name string, interval time.Duration, f func()) *Task {
+	return r.AddTask(name, func(t *Task) {
+		ticker := DefaultClock().NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if !t.Wait() {
+					return
+				}
+				begin := time.Now()
+				f()
+				r.telemetry.recordLoop(time.Since(begin))
+			case <-t.Halt():
+				return
+			}
+		}
+	})
+}
+
+// Tasks returns the Tasks of the Robot, in the order they were added
+func (r *Robot) Tasks() []*Task {
+	r.tasksMutex.Lock()
+	defer r.tasksMutex.Unlock()
+	return append([]*Task{}, r.tasks...)
+}
+
+// Task returns the Task named name, nil if the Robot has no such Task
+func (r *Robot) Task(name string) *Task {
+	r.tasksMutex.Lock()
+	defer r.tasksMutex.Unlock()
+	for _, t := range r.tasks {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// PauseTask pauses the Task named name
+func (r *Robot) PauseTask(name string) error {
+	return r.withTask(name, (*Task).Pause)
+}
+
+// ResumeTask resumes the Task named name
+func (r *Robot) ResumeTask(name string) error {
+	return r.withTask(name, (*Task).Resume)
+}
+
+// StopTask stops the Task named name, the other Tasks and the Robot keep
+// running
+func (r *Robot) StopTask(name string) error {
+	return r.withTask(name, func(t *Task) error {
+		t.Stop()
+		return nil
+	})
+}
+
+func (r *Robot) withTask(name string, f func(*Task) error) error {
+	t := r.Task(name)
+	if t == nil {
+		return fmt.Errorf("No Task found with the name %v", name)
+	}
+	return f(t)
+}
+
+// startTasks starts the Tasks of the Robot
+func (r *Robot) startTasks() {
+	for _, t := range r.Tasks() {
+		t.start()
+	}
+}
+
+// stopTasks stops the Tasks of the Robot
+func (r *Robot) stopTasks() {
+	for _, t := range r.Tasks() {
+		t.Stop()
+	}
+}
+
+// Name returns the name of the Task
+func (t *Task) Name() string { return t.name }
+
+// State returns the state of the Task
+func (t *Task) State() TaskState {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.state
+}
+
+// Halt returns a channel closed when the Task is stopped, to be selected on
+// by the Task function while waiting
+func (t *Task) Halt() <-chan bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.halt == nil {
+		// a Task which never started is as good as stopped
+		halt := make(chan bool)
+		close(halt)
+		return halt
+	}
+	return t.halt
+}
+
+// Wait blocks while the Task is paused. It returns true when the Task may
+// carry on, false when it has been stopped and the Task function should
+// return.
+func (t *Task) Wait() bool {
+	for {
+		t.mutex.Lock()
+		state, resume, halt := t.state, t.resume, t.halt
+		t.mutex.Unlock()
+
+		switch state {
+		case TaskRunning:
+			return true
+		case TaskPaused:
+			select {
+			case <-resume:
+			case <-halt:
+				return false
+			}
+		default:
+			return false
+		}
+	}
+}
+
+// Pause pauses the Task, which blocks on its next call to Wait. Only a
+// running Task can be paused.
+func (t *Task) Pause() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	switch t.state {
+	case TaskPaused:
+		return nil
+	case TaskRunning:
+		t.state = TaskPaused
+		t.resume = make(chan bool)
+		return nil
+	}
+	return fmt.Errorf("Task %v is %v", t.name, t.state)
+}
+
+// Resume resumes the paused Task
+func (t *Task) Resume() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	switch t.state {
+	case TaskRunning:
+		return nil
+	case TaskPaused:
+		t.state = TaskRunning
+		close(t.resume)
+		return nil
+	}
+	return fmt.Errorf("Task %v is %v", t.name, t.state)
+}
+
+// Stop stops the Task: Wait returns false and the channel returned by Halt
+// is closed. A stopped Task is started again along with its Robot.
+func (t *Task) Stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.state == TaskRunning || t.state == TaskPaused {
+		close(t.halt)
+	}
+	t.state = TaskStopped
+}
+
+// start runs the Task function in its own goroutine, a panic in it being
+// handled according to the PanicPolicy of the Robot
+func (t *Task) start() {
+	t.mutex.Lock()
+	if t.state == TaskRunning || t.state == TaskPaused {
+		t.mutex.Unlock()
+		return
+	}
+	t.state = TaskRunning
+	t.halt = make(chan bool)
+	halt := t.halt
+	t.mutex.Unlock()
+
+	go func() {
+		protect("task "+t.name, t.robot.handlePanic, func() { t.fn(t) })
+
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		// the Task may have been stopped and started again meanwhile
+		if t.halt == halt && t.state != TaskStopped {
+			close(t.halt)
+			t.state = TaskStopped
+		}
+	}()
+}
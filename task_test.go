@@ -0,0 +1,137 @@
+package gobot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+func TestRobotTasks(t *testing.T) {
+	r := newTestRobot("Robot1")
+	steps := make(chan int, 10)
+	task := r.AddTask("count", func(t *Task) {
+		for i := 0; t.Wait(); i++ {
+			steps <- i
+			<-time.After(time.Millisecond)
+		}
+	})
+	r.AddTask("idle", func(t *Task) { <-t.Halt() })
+
+	gobottest.Assert(t, task.Name(), "count")
+	gobottest.Assert(t, task.State(), TaskIdle)
+	gobottest.Assert(t, len(r.Tasks()), 2)
+	gobottest.Assert(t, r.Task("count"), task)
+	gobottest.Assert(t, r.Task("unknown"), (*Task)(nil))
+	gobottest.Assert(t, task.Wait(), false)
+
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, <-steps, 0)
+	gobottest.Assert(t, task.State(), TaskRunning)
+
+	gobottest.Assert(t, r.PauseTask("count"), nil)
+	gobottest.Assert(t, task.State(), TaskPaused)
+	// drain the step which may have been taken meanwhile
+	select {
+	case <-steps:
+	case <-time.After(10 * time.Millisecond):
+	}
+	select {
+	case <-steps:
+		t.Errorf("paused Task went on")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	gobottest.Assert(t, r.ResumeTask("count"), nil)
+	gobottest.Assert(t, task.State(), TaskRunning)
+	<-steps
+
+	gobottest.Assert(t, r.StopTask("count"), nil)
+	gobottest.Assert(t, task.State(), TaskStopped)
+	gobottest.Assert(t, r.Task("idle").State(), TaskRunning)
+	gobottest.Assert(t, r.Running(), true)
+	gobottest.Assert(t, task.Pause(), errors.New("Task count is stopped"))
+	gobottest.Assert(t, r.ResumeTask("unknown"), errors.New("No Task found with the name unknown"))
+
+	gobottest.Assert(t, r.Stop(), nil)
+	gobottest.Assert(t, r.Task("idle").State(), TaskStopped)
+
+	// stopped Tasks are started again with the Robot
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, task.State(), TaskRunning)
+	gobottest.Assert(t, r.Stop(), nil)
+}
+
+func TestRobotTaskReturns(t *testing.T) {
+	r := newTestRobot("Robot1")
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	done := make(chan bool)
+	task := r.AddTask("once", func(t *Task) { close(done) })
+	<-done
+	for task.State() != TaskStopped {
+		<-time.After(time.Millisecond)
+	}
+	gobottest.Assert(t, task.Resume(), errors.New("Task once is stopped"))
+}
+
+func TestRobotTaskPanic(t *testing.T) {
+	r := newTestRobot("Robot1")
+	reports := make(chan PanicReport, 1)
+	r.On(Panic, func(data interface{}) { reports <- data.(PanicReport) })
+	r.AddTask("faulty", func(t *Task) { panic("oops") })
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	select {
+	case p := <-reports:
+		gobottest.Assert(t, p.Source, "task faulty")
+	case <-time.After(time.Second):
+		t.Errorf("Task panic was not reported")
+	}
+}
+
+func TestRobotPeriodicTask(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	r := newTestRobot("Robot1")
+	calls := make(chan bool, 10)
+	task := r.AddPeriodicTask("blink", time.Second, func() { calls <- true })
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Errorf("periodic Task was not called")
+	}
+
+	gobottest.Assert(t, task.Pause(), nil)
+	clock.Advance(time.Second)
+	select {
+	case <-calls:
+		t.Errorf("paused periodic Task was called")
+	case <-time.After(10 * time.Millisecond):
+	}
+	gobottest.Assert(t, task.Resume(), nil)
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Errorf("resumed periodic Task was not called")
+	}
+}
+
+func TestRobotAddTaskReplaces(t *testing.T) {
+	r := newTestRobot("Robot1")
+	first := r.AddTask("task", func(t *Task) {})
+	second := r.AddTask("task", func(t *Task) {})
+	gobottest.Assert(t, r.Tasks(), []*Task{second})
+	gobottest.Assert(t, first.State(), TaskStopped)
+	gobottest.Assert(t, NewJSONRobot(r).Tasks, []*JSONTask{{Name: "task", State: TaskIdle}})
+}
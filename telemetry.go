@@ -0,0 +1,255 @@
+package gobot
+
+import (
+	"sync"
+	"time"
+)
+
+// Telemetry event
+const Telemetry = "telemetry"
+
+// Device states reported in DeviceTelemetry
+const (
+	DeviceStopped   = "stopped"
+	DeviceRunning   = "running"
+	DeviceFailed    = "failed"
+	DeviceUnhealthy = "unhealthy"
+)
+
+// TelemetryEvent is the last event seen from a Device.
+type TelemetryEvent struct {
+	Name string      `json:"name"`
+	Data interface{} `json:"data"`
+	Time time.Time   `json:"time"`
+}
+
+// LoopTiming summarizes the run time of the callbacks scheduled with the
+// Robot's Every and Loop methods.
+type LoopTiming struct {
+	Runs    uint64        `json:"runs"`
+	Last    time.Duration `json:"last"`
+	Max     time.Duration `json:"max"`
+	Average time.Duration `json:"average"`
+}
+
+// DeviceTelemetry is a snapshot of a Device's state and activity.
+type DeviceTelemetry struct {
+	Name       string          `json:"name"`
+	Driver     string          `json:"driver"`
+	Connection string          `json:"connection"`
+	State      string          `json:"state"`
+	Events     uint64          `json:"events"`
+	LastEvent  *TelemetryEvent `json:"last_event"`
+	Errors     uint64          `json:"errors"`
+}
+
+// RobotTelemetry is a snapshot of a Robot's state and activity. It is
+// returned by Robot.Telemetry and is the data of the Telemetry event.
+type RobotTelemetry struct {
+	Name      string            `json:"name"`
+	Time      time.Time         `json:"time"`
+	Running   bool              `json:"running"`
+	StartedAt time.Time         `json:"started_at"`
+	Uptime    time.Duration     `json:"uptime"`
+	Devices   []DeviceTelemetry `json:"devices"`
+	Errors    uint64            `json:"errors"`
+	Panics    uint64            `json:"panics"`
+	Loop      LoopTiming        `json:"loop"`
+}
+
+type deviceStats struct {
+	failed    bool
+	events    uint64
+	errors    uint64
+	lastEvent *TelemetryEvent
+}
+
+type telemetry struct {
+	started time.Time
+	devices map[string]*deviceStats
+	errors  uint64
+	panics  uint64
+	loop    LoopTiming
+	total   time.Duration
+	ticker  Ticker
+	halt    chan bool
+	history *eventHistory
+	mutex   sync.Mutex
+}
+
+func newTelemetry() *telemetry {
+	return &telemetry{devices: make(map[string]*deviceStats)}
+}
+
+func (t *telemetry) device(name string) *deviceStats {
+	d, ok := t.devices[name]
+	if !ok {
+		d = &deviceStats{}
+		t.devices[name] = d
+	}
+	return d
+}
+
+// startTelemetry records the start time and the events of each Device, in
+// the event history as well when it is enabled. The history is kept across
+// restarts.
+func (r *Robot) startTelemetry() {
+	t := r.telemetry
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.started = time.Now()
+	t.halt = make(chan bool)
+	if t.history == nil && r.EventHistorySize > 0 {
+		t.history = newEventHistory(r.EventHistorySize, r.EventRetention)
+	}
+	history := t.history
+
+	r.Devices().Each(func(d Device) {
+		e, ok := d.(Eventer)
+		if !ok {
+			return
+		}
+		out := e.SubscribeWith(SubscribeOptions{Policy: DropOldest})
+		go func(name string, halt chan bool) {
+			defer e.Unsubscribe(out)
+			for {
+				select {
+				case evt := <-out:
+					t.recordEvent(name, evt)
+					if history != nil {
+						history.record(name, evt)
+					}
+				case <-halt:
+					return
+				}
+			}
+		}(d.Name(), t.halt)
+	})
+
+	if r.TelemetryInterval > 0 {
+		t.ticker = Every(r.TelemetryInterval, func() {
+			r.Publish(Telemetry, r.Telemetry())
+		})
+	}
+}
+
+func (r *Robot) stopTelemetry() {
+	t := r.telemetry
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.halt != nil {
+		close(t.halt)
+		t.halt = nil
+	}
+	if t.ticker != nil {
+		t.ticker.Stop()
+		t.ticker = nil
+	}
+}
+
+// clearFailures forgets which Devices failed to start, before a new start.
+func (t *telemetry) clearFailures() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, stats := range t.devices {
+		stats.failed = false
+	}
+}
+
+// recordErrors counts the errors returned when starting the Robot, marking the
+// Devices they came from as failed.
+func (t *telemetry) recordErrors(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, err := range Errors(err) {
+		t.errors++
+		if e, ok := err.(*Error); ok && e.Op == "start" {
+			stats := t.device(e.Component)
+			stats.failed = true
+			stats.errors++
+		}
+	}
+}
+
+func (t *telemetry) recordEvent(device string, evt *Event) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	stats := t.device(device)
+	stats.events++
+	stats.lastEvent = &TelemetryEvent{Name: evt.Name, Data: evt.Data, Time: evt.Time}
+	if evt.Name == "error" {
+		stats.errors++
+		t.errors++
+	}
+}
+
+func (t *telemetry) recordPanic() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.panics++
+	t.errors++
+}
+
+func (t *telemetry) recordLoop(d time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.loop.Runs++
+	t.loop.Last = d
+	if d > t.loop.Max {
+		t.loop.Max = d
+	}
+	t.total += d
+	t.loop.Average = t.total / time.Duration(t.loop.Runs)
+}
+
+// Telemetry returns a snapshot of the Robot's uptime, device states, last
+// event and error count per device, panics and the timing of the callbacks
+// scheduled with the Robot's Every and Loop methods. Setting TelemetryInterval
+// publishes the snapshot periodically as a Telemetry event.
+func (r *Robot) Telemetry() RobotTelemetry {
+	t := r.telemetry
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	snapshot := RobotTelemetry{
+		Name:      r.Name,
+		Time:      now,
+		Running:   r.Running(),
+		StartedAt: t.started,
+		Devices:   []DeviceTelemetry{},
+		Errors:    t.errors,
+		Panics:    t.panics,
+		Loop:      t.loop,
+	}
+	if snapshot.Running {
+		snapshot.Uptime = now.Sub(t.started)
+	}
+
+	r.Devices().Each(func(d Device) {
+		json := NewJSONDevice(d)
+		dt := DeviceTelemetry{
+			Name:       json.Name,
+			Driver:     json.Driver,
+			Connection: json.Connection,
+			State:      DeviceStopped,
+		}
+		stats, ok := t.devices[d.Name()]
+		if ok {
+			dt.Events = stats.events
+			dt.Errors = stats.errors
+			dt.LastEvent = stats.lastEvent
+		}
+		switch {
+		case ok && stats.failed:
+			dt.State = DeviceFailed
+		case r.supervisor != nil && r.supervisor.healthErr(d) != nil:
+			dt.State = DeviceUnhealthy
+		case snapshot.Running:
+			dt.State = DeviceRunning
+		}
+		snapshot.Devices = append(snapshot.Devices, dt)
+	})
+	return snapshot
+}
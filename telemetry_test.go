@@ -0,0 +1,128 @@
+package gobot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testEventDriver struct {
+	*testDriver
+	Eventer
+}
+
+func newTestEventDriver(name string) *testEventDriver {
+	d := &testEventDriver{
+		testDriver: newTestDriver(newTestAdaptor("Connection1", "/dev/null"), name, "0"),
+		Eventer:    NewEventer(),
+	}
+	d.AddEvent("data")
+	d.AddEvent("error")
+	return d
+}
+
+func TestRobotTelemetryStopped(t *testing.T) {
+	r := newTestRobot("Robot1")
+	snapshot := r.Telemetry()
+	gobottest.Assert(t, snapshot.Name, "Robot1")
+	gobottest.Assert(t, snapshot.Running, false)
+	gobottest.Assert(t, snapshot.Uptime, time.Duration(0))
+	gobottest.Assert(t, len(snapshot.Devices), 3)
+	gobottest.Assert(t, snapshot.Devices[0].State, DeviceStopped)
+	gobottest.Assert(t, snapshot.Devices[0].Driver, "*gobot.testDriver")
+	gobottest.Assert(t, snapshot.Devices[0].Connection, "Connection1")
+}
+
+func TestRobotTelemetryEvents(t *testing.T) {
+	d := newTestEventDriver("sensor")
+	r := NewRobot("telemetry", []Device{d})
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	d.Publish("data", 42)
+	d.Publish("error", errors.New("read failed"))
+	time.Sleep(20 * time.Millisecond)
+
+	snapshot := r.Telemetry()
+	gobottest.Assert(t, snapshot.Running, true)
+	gobottest.Assert(t, snapshot.Uptime > 0, true)
+	gobottest.Assert(t, snapshot.Errors, uint64(1))
+
+	device := snapshot.Devices[0]
+	gobottest.Assert(t, device.State, DeviceRunning)
+	gobottest.Assert(t, device.Events, uint64(2))
+	gobottest.Assert(t, device.Errors, uint64(1))
+	gobottest.Assert(t, device.LastEvent.Name, "error")
+}
+
+func TestRobotTelemetryFailedDevice(t *testing.T) {
+	s := &startRecorder{}
+	r := NewRobot("telemetry", []Device{
+		s.driver("good", 0, nil),
+		s.driver("bad", 0, errors.New("no device")),
+	})
+	gobottest.Refute(t, r.Start(false), nil)
+
+	snapshot := r.Telemetry()
+	gobottest.Assert(t, snapshot.Errors, uint64(1))
+	gobottest.Assert(t, snapshot.Devices[0].State, DeviceStopped)
+	gobottest.Assert(t, snapshot.Devices[1].State, DeviceFailed)
+	gobottest.Assert(t, snapshot.Devices[1].Errors, uint64(1))
+}
+
+func TestRobotTelemetryUnhealthy(t *testing.T) {
+	r, driver := newTestSupervisedRobot()
+	s := NewSupervisor(time.Hour)
+	s.AutoReconnect = false
+	r.Supervise(s)
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	driver.setHealth(errors.New("bus dropped"))
+	s.Check()
+	gobottest.Assert(t, r.Telemetry().Devices[0].State, DeviceUnhealthy)
+}
+
+func TestRobotTelemetryLoopAndPanics(t *testing.T) {
+	r := NewRobot("telemetry")
+	gobottest.Assert(t, r.Start(false), nil)
+	defer r.Stop()
+
+	runs := 0
+	ticker := r.Every(5*time.Millisecond, func() {
+		runs++
+		if runs == 1 {
+			panic("first")
+		}
+		time.Sleep(time.Millisecond)
+	})
+	time.Sleep(40 * time.Millisecond)
+	ticker.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	snapshot := r.Telemetry()
+	gobottest.Assert(t, snapshot.Panics, uint64(1))
+	gobottest.Assert(t, snapshot.Loop.Runs > 2, true)
+	gobottest.Assert(t, snapshot.Loop.Max >= time.Millisecond, true)
+	gobottest.Assert(t, snapshot.Loop.Average > 0, true)
+}
+
+func TestRobotTelemetryEvent(t *testing.T) {
+	r := NewRobot("telemetry")
+	r.TelemetryInterval = 5 * time.Millisecond
+	snapshots := make(chan RobotTelemetry, 10)
+	r.On(Telemetry, func(data interface{}) {
+		snapshots <- data.(RobotTelemetry)
+	})
+	gobottest.Assert(t, r.Start(false), nil)
+
+	select {
+	case snapshot := <-snapshots:
+		gobottest.Assert(t, snapshot.Name, "telemetry")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Telemetry event was not published")
+	}
+	gobottest.Assert(t, r.Stop(), nil)
+}
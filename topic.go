@@ -0,0 +1,121 @@
+package gobot
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Topic is an event stream of a single payload type. Unlike the string based
+// Eventer, a Topic only accepts values of its payload type, so subscribers
+// can assert the type of the values they receive without checking it.
+//
+// Each subscriber has its own buffered channel. Publish never drops events: when
+// a subscriber's buffer is full, Publish waits until it has room or the
+// subscriber unsubscribes.
+type Topic struct {
+	name    string
+	payload reflect.Type
+	mirrors []Eventer
+	subs    map[*Subscription]bool
+	mutex   sync.RWMutex
+}
+
+// Subscription is a subscriber to a Topic.
+type Subscription struct {
+	// C delivers the published values
+	C     <-chan interface{}
+	c     chan interface{}
+	done  chan struct{}
+	once  sync.Once
+	topic *Topic
+}
+
+// NewTopic returns a new Topic with the given name, whose payload type is the
+// type of payload, e.g. Position{}. A nil payload accepts values of any type.
+// Every value published to the Topic is also published, using the Topic name,
+// to the mirror Eventers so that existing On handlers and the api keep
+// working.
+func NewTopic(name string, payload interface{}, mirrors ...Eventer) *Topic {
+	return &Topic{
+		name:    name,
+		payload: reflect.TypeOf(payload),
+		mirrors: mirrors,
+		subs:    make(map[*Subscription]bool),
+	}
+}
+
+// Name returns the name of the Topic
+func (t *Topic) Name() string {
+	return t.name
+}
+
+// Publish sends data to every subscriber of the Topic. An error is returned,
+// and data is not sent, if it is not of the payload type of the Topic.
+func (t *Topic) Publish(data interface{}) error {
+	if t.payload != nil && reflect.TypeOf(data) != t.payload {
+		return fmt.Errorf("Topic %v payload is %v, not %v", t.name, t.payload, reflect.TypeOf(data))
+	}
+
+	t.mutex.RLock()
+	subs := make([]*Subscription, 0, len(t.subs))
+	for s := range t.subs {
+		subs = append(subs, s)
+	}
+	t.mutex.RUnlock()
+
+	for _, s := range subs {
+		select {
+		case s.c <- data:
+		case <-s.done:
+		}
+	}
+	for _, e := range t.mirrors {
+		e.Publish(t.name, data)
+	}
+	return nil
+}
+
+// Subscribe returns a new Subscription to the Topic with a channel buffer of
+// size values.
+func (t *Topic) Subscribe(size int) *Subscription {
+	c := make(chan interface{}, size)
+	s := &Subscription{
+		C:     c,
+		c:     c,
+		done:  make(chan struct{}),
+		topic: t,
+	}
+	t.mutex.Lock()
+	t.subs[s] = true
+	t.mutex.Unlock()
+	return s
+}
+
+// On calls f with each value published to the Topic until the returned
+// Subscription is unsubscribed.
+func (t *Topic) On(f func(data interface{})) *Subscription {
+	s := t.Subscribe(eventChanBufferSize)
+	go func() {
+		for {
+			select {
+			case data := <-s.C:
+				f(data)
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Unsubscribe removes the Subscription from its Topic. Pending publishers
+// waiting on this subscriber are released.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.topic.mutex.Lock()
+		delete(s.topic.subs, s)
+		s.topic.mutex.Unlock()
+		close(s.done)
+	})
+}
@@ -0,0 +1,118 @@
+package gobot
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testReading struct {
+	Value int
+}
+
+func TestTopicPublishSubscribe(t *testing.T) {
+	topic := NewTopic("reading", testReading{})
+	gobottest.Assert(t, topic.Name(), "reading")
+
+	s1 := topic.Subscribe(2)
+	s2 := topic.Subscribe(2)
+	gobottest.Assert(t, topic.Publish(testReading{Value: 1}), nil)
+	gobottest.Assert(t, topic.Publish(testReading{Value: 2}), nil)
+
+	gobottest.Assert(t, <-s1.C, testReading{Value: 1})
+	gobottest.Assert(t, <-s1.C, testReading{Value: 2})
+	gobottest.Assert(t, (<-s2.C).(testReading).Value, 1)
+	gobottest.Assert(t, (<-s2.C).(testReading).Value, 2)
+}
+
+func TestTopicPayloadType(t *testing.T) {
+	topic := NewTopic("reading", testReading{})
+	s := topic.Subscribe(1)
+	err := topic.Publish(1)
+	gobottest.Assert(t, err.Error(), "Topic reading payload is gobot.testReading, not int")
+	gobottest.Assert(t, len(s.C), 0)
+
+	untyped := NewTopic("any", nil)
+	s = untyped.Subscribe(2)
+	gobottest.Assert(t, untyped.Publish(1), nil)
+	gobottest.Assert(t, untyped.Publish("one"), nil)
+	gobottest.Assert(t, <-s.C, 1)
+	gobottest.Assert(t, <-s.C, "one")
+}
+
+func TestTopicPublishDoesNotDrop(t *testing.T) {
+	topic := NewTopic("count", 0)
+	s := topic.Subscribe(1)
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			topic.Publish(i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		select {
+		case v := <-s.C:
+			gobottest.Assert(t, v, i)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("event %v was not delivered", i)
+		}
+	}
+}
+
+func TestTopicUnsubscribeReleasesPublisher(t *testing.T) {
+	topic := NewTopic("count", 0)
+	s := topic.Subscribe(0)
+
+	sem := make(chan bool)
+	go func() {
+		topic.Publish(1)
+		sem <- true
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	s.Unsubscribe()
+	s.Unsubscribe()
+
+	select {
+	case <-sem:
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("Publish was not released by Unsubscribe")
+	}
+}
+
+func TestTopicOn(t *testing.T) {
+	topic := NewTopic("name", "")
+	sem := make(chan string, 1)
+	s := topic.On(func(data interface{}) {
+		sem <- data.(string)
+	})
+	topic.Publish("gobot")
+
+	select {
+	case name := <-sem:
+		gobottest.Assert(t, name, "gobot")
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("On was not called")
+	}
+	s.Unsubscribe()
+}
+
+func TestTopicMirror(t *testing.T) {
+	e := NewEventer()
+	topic := NewTopic("temperature", 0.0, e)
+
+	sem := make(chan interface{}, 1)
+	e.On("temperature", func(data interface{}) {
+		sem <- data
+	})
+	topic.Publish(21.5)
+
+	select {
+	case data := <-sem:
+		gobottest.Assert(t, data, 21.5)
+	case <-time.After(100 * time.Millisecond):
+		t.Errorf("mirrored event was not published")
+	}
+}
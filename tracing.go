@@ -0,0 +1,134 @@
+package gobot
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Names of the Spans started by the core
+const (
+	RobotStartSpan  = "robot.start"
+	RobotStopSpan   = "robot.stop"
+	DeviceStartSpan = "device.start"
+	DeviceHaltSpan  = "device.halt"
+)
+
+// Attribute is a key and value describing a Span, such as the name of a
+// Device or the address of an I2C device.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr returns an Attribute given its key and value
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is an operation being traced, from its start by a Tracer to End.
+type Span interface {
+	// SetAttributes adds attributes to the Span
+	SetAttributes(attrs ...Attribute)
+	// RecordError records the error the operation failed with
+	RecordError(err error)
+	// End ends the Span
+	End()
+}
+
+// Tracer starts the Spans timing the commands, the start and halt of the
+// Devices, and the I/O of the drivers. The Span is the child of the one in
+// ctx, if any, and the returned context holds it for the Spans of the nested
+// operations.
+//
+// The default Tracer discards the Spans. The gobot.io/x/gobot/tracing
+// package provides one exporting them with OpenTelemetry.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+var (
+	defaultTracer      Tracer = noopTracer{}
+	defaultTracerMutex sync.RWMutex
+	// tracing is 1 while a Tracer other than the default one is set
+	tracing int32
+)
+
+// DefaultTracer returns the Tracer used by the core, the api and the
+// drivers, discarding the Spans unless replaced with SetDefaultTracer.
+func DefaultTracer() Tracer {
+	defaultTracerMutex.RLock()
+	defer defaultTracerMutex.RUnlock()
+	return defaultTracer
+}
+
+// SetDefaultTracer replaces the Tracer used by the core, the api and the
+// drivers. A nil Tracer restores the one discarding the Spans.
+func SetDefaultTracer(t Tracer) {
+	defaultTracerMutex.Lock()
+	defer defaultTracerMutex.Unlock()
+	if t == nil {
+		t = noopTracer{}
+	}
+	defaultTracer = t
+	if _, ok := t.(noopTracer); ok {
+		atomic.StoreInt32(&tracing, 0)
+	} else {
+		atomic.StoreInt32(&tracing, 1)
+	}
+}
+
+// Tracing reports whether a Tracer was set with SetDefaultTracer. The hot
+// paths, such as the I/O of the drivers, check it so as not to build Spans
+// which would be discarded.
+func Tracing() bool {
+	return atomic.LoadInt32(&tracing) == 1
+}
+
+// StartSpan starts a Span with the DefaultTracer
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return DefaultTracer().Start(ctx, name, attrs...)
+}
+
+// EndSpan records err in span unless it is nil, and ends span. A nil span,
+// not started since Tracing is off, is ignored.
+func EndSpan(span Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// deviceAttributes returns the Attributes of the Spans of device
+func deviceAttributes(device Device) []Attribute {
+	attrs := []Attribute{
+		Attr("gobot.device", device.Name()),
+		Attr("gobot.driver", reflect.TypeOf(device).String()),
+	}
+	if c := device.Connection(); c != nil {
+		attrs = append(attrs, Attr("gobot.connection", c.Name()))
+	}
+	if pinner, ok := device.(Pinner); ok {
+		attrs = append(attrs, Attr("gobot.pin", pinner.Pin()))
+	}
+	return attrs
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
@@ -0,0 +1,34 @@
+/*
+Package tracing exports the Spans of Gobot with OpenTelemetry, so that the
+latency of a command can be followed from the API request down to the I2C
+transfers of the drivers.
+
+Once the DefaultTracer is set, the core traces the start and halt of the
+Robots and their Devices, the api traces the commands and their Jobs, and the
+I2C connections trace their reads and writes:
+
+	stop, err := tracing.StartOTLP(context.Background(), "rover")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stop(context.Background())
+
+The Spans are sent to the OTLP/HTTP endpoint of an OpenTelemetry collector,
+"localhost:4318" unless set by the options or by the
+OTEL_EXPORTER_OTLP_ENDPOINT environment variable. A Tracer can also be made
+from the TracerProvider of an application already set up for OpenTelemetry:
+
+	gobot.SetDefaultTracer(tracing.NewTracer(otel.GetTracerProvider()))
+
+The api continues the traces of the callers sending a W3C traceparent header.
+The I2C connections are not given a context, so their Spans are the roots of
+their own traces, found by their time and their i2c.address attribute.
+
+The package needs Go 1.21 or later and the go.opentelemetry.io/otel modules,
+which are not among the dependencies of Gobot. It is only built with the otel
+build tag:
+
+	go get go.opentelemetry.io/otel/sdk go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp
+	go build -tags otel
+*/
+package tracing // import "gobot.io/x/gobot/tracing"
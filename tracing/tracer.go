@@ -0,0 +1,112 @@
+// +build otel
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"gobot.io/x/gobot"
+)
+
+// InstrumentationName is the name of the OpenTelemetry Tracer of Gobot
+const InstrumentationName = "gobot.io/x/gobot"
+
+// Tracer is the gobot.Tracer starting OpenTelemetry Spans. It is also the
+// api.TraceExtractor continuing the traces of the W3C traceparent headers.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracer returns a new Tracer given the OpenTelemetry TracerProvider
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{
+		tracer: provider.Tracer(InstrumentationName,
+			trace.WithInstrumentationVersion(gobot.Version())),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// Start starts an OpenTelemetry Span, the child of the one in ctx
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...gobot.Attribute) (context.Context, gobot.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(keyValues(attrs)...))
+	return ctx, span{s}
+}
+
+// Extract returns ctx holding the remote Span of the traceparent header
+func (t *Tracer) Extract(ctx context.Context, header http.Header) context.Context {
+	return t.propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// StartOTLP sets the DefaultTracer to a Tracer exporting the Spans of the
+// service in batches, with OTLP over HTTP. The returned function flushes the
+// Spans left, stops the exporter and restores the DefaultTracer.
+func StartOTLP(ctx context.Context, service string, options ...otlptracehttp.Option) (stop func(context.Context) error, err error) {
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	)
+	gobot.SetDefaultTracer(NewTracer(provider))
+	return func(ctx context.Context) error {
+		gobot.SetDefaultTracer(nil)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// span is the gobot.Span of an OpenTelemetry Span
+type span struct {
+	trace.Span
+}
+
+func (s span) SetAttributes(attrs ...gobot.Attribute) {
+	s.Span.SetAttributes(keyValues(attrs)...)
+}
+
+func (s span) RecordError(err error) {
+	s.Span.RecordError(err)
+	s.Span.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.Span.End()
+}
+
+// keyValues returns the OpenTelemetry attributes of attrs, the values of
+// other types than the OpenTelemetry ones being formatted as strings
+func keyValues(attrs []gobot.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		var kv attribute.KeyValue
+		switch v := attr.Value.(type) {
+		case string:
+			kv = attribute.String(attr.Key, v)
+		case bool:
+			kv = attribute.Bool(attr.Key, v)
+		case int:
+			kv = attribute.Int(attr.Key, v)
+		case int64:
+			kv = attribute.Int64(attr.Key, v)
+		case float64:
+			kv = attribute.Float64(attr.Key, v)
+		case []string:
+			kv = attribute.StringSlice(attr.Key, v)
+		default:
+			kv = attribute.String(attr.Key, fmt.Sprint(v))
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
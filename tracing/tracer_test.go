@@ -0,0 +1,61 @@
+// +build otel
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/gobottest"
+)
+
+var _ gobot.Tracer = (*Tracer)(nil)
+
+func initTestTracer() (*Tracer, *tracetest.SpanRecorder) {
+	rec := tracetest.NewSpanRecorder()
+	return NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))), rec
+}
+
+func TestTracer(t *testing.T) {
+	tracer, rec := initTestTracer()
+	ctx, parent := tracer.Start(context.Background(), gobot.RobotStartSpan, gobot.Attr("gobot.robot", "rover"))
+	_, child := tracer.Start(ctx, gobot.DeviceStartSpan, gobot.Attr("i2c.address", 0x29))
+	child.SetAttributes(gobot.Attr("gobot.retries", 2), gobot.Attr("gobot.pin", []byte{1}))
+	child.RecordError(errors.New("start error"))
+	child.End()
+	parent.End()
+
+	spans := rec.Ended()
+	gobottest.Assert(t, len(spans), 2)
+	gobottest.Assert(t, spans[0].Name(), gobot.DeviceStartSpan)
+	gobottest.Assert(t, spans[0].Parent().SpanID(), spans[1].SpanContext().SpanID())
+	gobottest.Assert(t, spans[0].Attributes(), []attribute.KeyValue{
+		attribute.Int("i2c.address", 0x29),
+		attribute.Int("gobot.retries", 2),
+		attribute.String("gobot.pin", "[1]"),
+	})
+	gobottest.Assert(t, spans[0].Status().Code, codes.Error)
+	gobottest.Assert(t, spans[0].Status().Description, "start error")
+	gobottest.Assert(t, spans[1].Attributes(), []attribute.KeyValue{attribute.String("gobot.robot", "rover")})
+	gobottest.Assert(t, spans[1].InstrumentationScope().Name, InstrumentationName)
+}
+
+func TestTracerExtract(t *testing.T) {
+	tracer, rec := initTestTracer()
+	header := http.Header{}
+	header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, s := tracer.Start(tracer.Extract(context.Background(), header), "api.command")
+	s.End()
+
+	span := rec.Ended()[0]
+	gobottest.Assert(t, span.SpanContext().TraceID().String(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	gobottest.Assert(t, span.Parent().SpanID().String(), "00f067aa0ba902b7")
+	gobottest.Assert(t, span.Parent().IsRemote(), true)
+}
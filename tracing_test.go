@@ -0,0 +1,143 @@
+package gobot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testSpan struct {
+	name   string
+	parent *testSpan
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+	tracer *testTracer
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+func (s *testSpan) End() {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.ended = true
+}
+
+type testSpanKey struct{}
+
+// testTracer records the Spans it starts
+type testTracer struct {
+	spans []*testSpan
+	mutex sync.Mutex
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s := &testSpan{name: name, attrs: make(map[string]interface{}), tracer: t}
+	s.parent, _ = ctx.Value(testSpanKey{}).(*testSpan)
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, testSpanKey{}, s), s
+}
+
+func (t *testTracer) named(name string) []*testSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	spans := []*testSpan{}
+	for _, s := range t.spans {
+		if s.name == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func TestDefaultTracer(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testSpanKey{}, "value")
+	spanCtx, span := StartSpan(ctx, "noop", Attr("key", "value"))
+	gobottest.Assert(t, spanCtx, ctx)
+	EndSpan(span, errors.New("failed"))
+
+	EndSpan(nil, errors.New("failed"))
+
+	gobottest.Assert(t, Tracing(), false)
+	tracer := &testTracer{}
+	SetDefaultTracer(tracer)
+	gobottest.Assert(t, DefaultTracer(), Tracer(tracer))
+	gobottest.Assert(t, Tracing(), true)
+	SetDefaultTracer(nil)
+	gobottest.Assert(t, DefaultTracer(), Tracer(noopTracer{}))
+	gobottest.Assert(t, Tracing(), false)
+}
+
+func TestRobotTracing(t *testing.T) {
+	tracer := &testTracer{}
+	SetDefaultTracer(tracer)
+	defer SetDefaultTracer(nil)
+
+	r := newTestRobot("Robot99")
+	gobottest.Assert(t, r.Start(false), nil)
+	gobottest.Assert(t, r.Stop(), nil)
+
+	start := tracer.named(RobotStartSpan)
+	gobottest.Assert(t, len(start), 1)
+	gobottest.Assert(t, start[0].attrs["gobot.robot"], "Robot99")
+	gobottest.Assert(t, start[0].ended, true)
+
+	devices := tracer.named(DeviceStartSpan)
+	gobottest.Assert(t, len(devices), 3)
+	for _, s := range devices {
+		gobottest.Assert(t, s.parent, start[0])
+		gobottest.Assert(t, s.ended, true)
+		gobottest.Assert(t, s.attrs["gobot.driver"], "*gobot.testDriver")
+		if s.attrs["gobot.device"] == "Device1" {
+			gobottest.Assert(t, s.attrs["gobot.connection"], "Connection1")
+			gobottest.Assert(t, s.attrs["gobot.pin"], "0")
+		}
+	}
+
+	stop := tracer.named(RobotStopSpan)
+	gobottest.Assert(t, len(stop), 1)
+	halts := tracer.named(DeviceHaltSpan)
+	gobottest.Assert(t, len(halts), 3)
+	gobottest.Assert(t, halts[0].parent, stop[0])
+	gobottest.Assert(t, halts[0].attrs["gobot.device"], "Device1")
+}
+
+func TestRobotTracingStartError(t *testing.T) {
+	tracer := &testTracer{}
+	SetDefaultTracer(tracer)
+	defer SetDefaultTracer(nil)
+
+	e := errors.New("start error")
+	testDriverStart = func() (err error) { return e }
+	defer func() { testDriverStart = func() (err error) { return } }()
+
+	r := newTestRobot("Robot99")
+	gobottest.Refute(t, r.Start(false), nil)
+
+	for _, s := range tracer.named(DeviceStartSpan) {
+		gobottest.Assert(t, s.errs, []error{e})
+	}
+	start := tracer.named(RobotStartSpan)
+	gobottest.Assert(t, len(start[0].errs), 1)
+	gobottest.Assert(t, start[0].ended, true)
+}
@@ -5,21 +5,37 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sync"
 	"time"
 )
 
-// Every triggers f every t time.Duration until the end of days, or when a Stop()
-// is called on the Ticker that is returned by the Every function.
+// Every triggers f every t time.Duration of the DefaultClock until the end
+// of days, or when a Stop() is called on the Ticker that is returned by the
+// Every function.
 // It does not wait for the previous execution of f to finish before
-// it fires the next f.
-func Every(t time.Duration, f func()) *time.Ticker {
-	ticker := time.NewTicker(t)
+// it fires the next f. A panic in f is recovered and passed to the handler set
+// with SetPanicHandler.
+func Every(t time.Duration, f func()) Ticker {
+	return every(t, func() { protect("every", handlePanic, f) })
+}
+
+// After triggers f after t duration of the DefaultClock. A panic in f is
+// recovered and passed to the handler set with SetPanicHandler.
+func After(t time.Duration, f func()) {
+	DefaultClock().AfterFunc(t, func() { protect("after", handlePanic, f) })
+}
+
+// every calls f every t until the returned Ticker is stopped
+func every(t time.Duration, f func()) Ticker {
+	ticker := &everyTicker{Ticker: DefaultClock().NewTicker(t), halt: make(chan bool)}
 
 	go func() {
 		for {
 			select {
-			case <-ticker.C:
+			case <-ticker.Ticker.C():
 				f()
+			case <-ticker.halt:
+				return
 			}
 		}
 	}()
@@ -27,9 +43,19 @@ func Every(t time.Duration, f func()) *time.Ticker {
 	return ticker
 }
 
-// After triggers f after t duration.
-func After(t time.Duration, f func()) {
-	time.AfterFunc(t, f)
+// everyTicker is the Ticker of Every, whose Stop also ends the goroutine
+// calling the function
+type everyTicker struct {
+	Ticker
+	halt chan bool
+	once sync.Once
+}
+
+func (t *everyTicker) Stop() {
+	t.once.Do(func() {
+		t.Ticker.Stop()
+		close(t.halt)
+	})
 }
 
 // Rand returns a positive random int up to max
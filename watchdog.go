@@ -0,0 +1,226 @@
+package gobot
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Expired event, published by the Robot when its Watchdog was not petted in
+// time. Its data is a WatchdogExpiry.
+const Expired = "expired"
+
+// WatchdogExpiry is the data published with the Expired event.
+type WatchdogExpiry struct {
+	// LastPet is when the Watchdog was last petted
+	LastPet time.Time
+	// Timeout is the deadline which was missed
+	Timeout time.Duration
+}
+
+// WatchdogKeeper is the interface which describes an external watchdog, such
+// as systemd or a hardware watchdog, which resets the process or the board
+// unless it is kept alive. A Watchdog keeps its keepers alive only as long as
+// it is petted in time, so they fire when the Robot hangs.
+type WatchdogKeeper interface {
+	// Keepalive tells the external watchdog that all is well
+	Keepalive() error
+	// Close disarms the external watchdog, if possible
+	Close() error
+}
+
+// Watchdog checks that the work of a Robot keeps running: Pet must be called
+// at least every Timeout, otherwise the Expired event is published on the
+// Robot, SafeState is called, e.g. to stop the motors, and the keepers are no
+// longer kept alive until the Watchdog is petted again.
+type Watchdog struct {
+	// Timeout is the deadline between two calls to Pet
+	Timeout time.Duration
+	// SafeState, unless nil, is called when the deadline is missed
+	SafeState func()
+
+	keepers []WatchdogKeeper
+	robot   *Robot
+	last    time.Time
+	expired bool
+	halt    chan bool
+	mutex   sync.Mutex
+}
+
+// NewWatchdog returns a new Watchdog with the given timeout, keeping the
+// keepers alive while it is petted in time.
+func NewWatchdog(timeout time.Duration, keepers ...WatchdogKeeper) *Watchdog {
+	return &Watchdog{
+		Timeout: timeout,
+		keepers: keepers,
+	}
+}
+
+// SetWatchdog attaches w to the Robot. The Watchdog is started and stopped
+// along with the Robot.
+func (r *Robot) SetWatchdog(w *Watchdog) {
+	w.robot = r
+	r.watchdog = w
+	r.AddEvent(Expired)
+}
+
+// Watchdog returns the Watchdog attached to the Robot, or nil.
+func (r *Robot) Watchdog() *Watchdog {
+	return r.watchdog
+}
+
+// Start begins checking the deadline, a quarter of the Timeout at a time,
+// counting from now.
+func (w *Watchdog) Start() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.halt != nil {
+		return
+	}
+	w.last = DefaultClock().Now()
+	w.expired = false
+	w.halt = make(chan bool)
+	w.keepalive()
+	go func(halt chan bool) {
+		ticker := DefaultClock().NewTicker(w.Timeout / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				w.Check()
+			case <-halt:
+				return
+			}
+		}
+	}(w.halt)
+}
+
+// Stop ends checking the deadline and closes the keepers.
+func (w *Watchdog) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.halt == nil {
+		return
+	}
+	close(w.halt)
+	w.halt = nil
+	for _, k := range w.keepers {
+		if err := k.Close(); err != nil {
+			w.logger().Error("Watchdog keeper failed to close", "error", err)
+		}
+	}
+}
+
+// Pet tells the Watchdog that the work is still running, restarting the
+// deadline.
+func (w *Watchdog) Pet() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.last = DefaultClock().Now()
+	if w.expired {
+		w.expired = false
+		w.logger().Info("Watchdog petted again")
+	}
+}
+
+// Expired returns true if the deadline was missed since the last call to Pet.
+func (w *Watchdog) Expired() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.expired
+}
+
+// Check checks the deadline once, keeping the keepers alive when it is met.
+func (w *Watchdog) Check() {
+	w.mutex.Lock()
+	if w.expired {
+		w.mutex.Unlock()
+		return
+	}
+	if DefaultClock().Now().Sub(w.last) < w.Timeout {
+		w.keepalive()
+		w.mutex.Unlock()
+		return
+	}
+	w.expired = true
+	expiry := WatchdogExpiry{LastPet: w.last, Timeout: w.Timeout}
+	w.mutex.Unlock()
+
+	w.logger().Error("Watchdog expired", "last_pet", expiry.LastPet, "timeout", expiry.Timeout)
+	if w.robot != nil {
+		w.robot.Publish(Expired, expiry)
+	}
+	if w.SafeState != nil {
+		protect("watchdog", w.handlePanic, w.SafeState)
+	}
+}
+
+// keepalive keeps the keepers alive, the mutex being held
+func (w *Watchdog) keepalive() {
+	for _, k := range w.keepers {
+		if err := k.Keepalive(); err != nil {
+			w.logger().Error("Watchdog keeper failed", "error", err)
+		}
+	}
+}
+
+func (w *Watchdog) handlePanic(report PanicReport) {
+	if w.robot != nil {
+		w.robot.handlePanic(report)
+		return
+	}
+	handlePanic(report)
+}
+
+func (w *Watchdog) logger() Logger {
+	if w.robot != nil {
+		return w.robot.Logger().WithComponent("watchdog")
+	}
+	return DefaultLogger()
+}
+
+// SystemdWatchdog is a WatchdogKeeper notifying the systemd service manager,
+// when the service has WatchdogSec set. The Timeout of the Watchdog must be
+// shorter than WatchdogSec. Outside of systemd it does nothing.
+type SystemdWatchdog struct {
+	socket string
+}
+
+// NewSystemdWatchdog returns a new SystemdWatchdog notifying the socket named
+// by the NOTIFY_SOCKET environment variable.
+func NewSystemdWatchdog() *SystemdWatchdog {
+	return &SystemdWatchdog{socket: os.Getenv("NOTIFY_SOCKET")}
+}
+
+// Keepalive sends WATCHDOG=1 to systemd
+func (s *SystemdWatchdog) Keepalive() error {
+	return s.notify("WATCHDOG=1")
+}
+
+// Close does nothing, systemd disarms its watchdog when the service stops
+func (s *SystemdWatchdog) Close() error {
+	return nil
+}
+
+func (s *SystemdWatchdog) notify(state string) error {
+	if s.socket == "" {
+		return nil
+	}
+	addr := &net.UnixAddr{Name: s.socket, Net: "unixgram"}
+	// a leading @ names an abstract socket
+	if addr.Name[0] == '@' {
+		addr.Name = "\x00" + addr.Name[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	n, err := conn.Write([]byte(state))
+	if err == nil && n != len(state) {
+		err = errors.New("short write to systemd notify socket")
+	}
+	return err
+}
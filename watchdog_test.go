@@ -0,0 +1,164 @@
+package gobot
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot/gobottest"
+)
+
+type testWatchdogKeeper struct {
+	mtx        sync.Mutex
+	keepalives int
+	closes     int
+	err        error
+}
+
+func (k *testWatchdogKeeper) Keepalive() error {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	k.keepalives++
+	return k.err
+}
+
+func (k *testWatchdogKeeper) Close() error {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	k.closes++
+	return k.err
+}
+
+func (k *testWatchdogKeeper) counts() (int, int) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	return k.keepalives, k.closes
+}
+
+func TestWatchdog(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1488469265, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	keeper := &testWatchdogKeeper{}
+	w := NewWatchdog(time.Second, keeper)
+	safe := make(chan bool, 1)
+	w.SafeState = func() { safe <- true }
+	r := NewRobot("watched")
+	r.SetWatchdog(w)
+	gobottest.Assert(t, r.Watchdog(), w)
+	events := r.Subscribe()
+
+	w.Start()
+	defer w.Stop()
+	keepalives, _ := keeper.counts()
+	gobottest.Assert(t, keepalives, 1)
+
+	clock.Advance(900 * time.Millisecond)
+	w.Pet()
+	clock.Advance(900 * time.Millisecond)
+	w.Check()
+	gobottest.Assert(t, w.Expired(), false)
+	keepalives, _ = keeper.counts()
+	gobottest.Assert(t, keepalives, 2)
+
+	clock.Advance(100 * time.Millisecond)
+	w.Check()
+	gobottest.Assert(t, w.Expired(), true)
+	<-safe
+	evt := <-events
+	gobottest.Assert(t, evt.Name, Expired)
+	gobottest.Assert(t, evt.Data, WatchdogExpiry{LastPet: time.Unix(1488469265, 0).Add(900 * time.Millisecond), Timeout: time.Second})
+
+	// the keepers are starved until the Watchdog is petted again
+	w.Check()
+	keepalives, _ = keeper.counts()
+	gobottest.Assert(t, keepalives, 2)
+	gobottest.Assert(t, len(safe), 0)
+
+	w.Pet()
+	gobottest.Assert(t, w.Expired(), false)
+	w.Check()
+	keepalives, _ = keeper.counts()
+	gobottest.Assert(t, keepalives, 3)
+}
+
+func TestWatchdogTicker(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1488469265, 0))
+	SetDefaultClock(clock)
+	defer SetDefaultClock(SystemClock())
+
+	keeper := &testWatchdogKeeper{}
+	w := NewWatchdog(time.Second, keeper)
+	safe := make(chan bool, 1)
+	w.SafeState = func() { safe <- true }
+	r := NewRobot("watched", func() {})
+	r.SetWatchdog(w)
+	r.Start(false)
+
+	clock.BlockUntil(1)
+	clock.Advance(250 * time.Millisecond)
+	clock.Advance(250 * time.Millisecond)
+	clock.Advance(250 * time.Millisecond)
+	clock.Advance(250 * time.Millisecond)
+	<-safe
+	gobottest.Assert(t, w.Expired(), true)
+
+	gobottest.Assert(t, r.Stop(), nil)
+	_, closes := keeper.counts()
+	gobottest.Assert(t, closes, 1)
+}
+
+func TestWatchdogSafeStatePanic(t *testing.T) {
+	w := NewWatchdog(time.Second)
+	w.SafeState = func() { panic("motors") }
+	r := NewRobot("watched")
+	r.SetWatchdog(w)
+	events := r.Subscribe()
+
+	w.last = time.Now().Add(-time.Minute)
+	w.Check()
+	gobottest.Assert(t, (<-events).Name, Expired)
+	gobottest.Assert(t, (<-events).Name, Panic)
+}
+
+func TestWatchdogKeeperErrors(t *testing.T) {
+	keeper := &testWatchdogKeeper{err: errors.New("keeper error")}
+	w := NewWatchdog(time.Second, keeper)
+	w.Start()
+	w.Check()
+	w.Stop()
+	w.Stop()
+	keepalives, closes := keeper.counts()
+	gobottest.Assert(t, keepalives, 2)
+	gobottest.Assert(t, closes, 1)
+}
+
+func TestSystemdWatchdog(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gobot-watchdog")
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	gobottest.Assert(t, err, nil)
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", socket)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+	s := NewSystemdWatchdog()
+	gobottest.Assert(t, s.Keepalive(), nil)
+	buf := make([]byte, 64)
+	n, _ := conn.Read(buf)
+	gobottest.Assert(t, string(buf[:n]), "WATCHDOG=1")
+	gobottest.Assert(t, s.Close(), nil)
+
+	os.Unsetenv("NOTIFY_SOCKET")
+	gobottest.Assert(t, NewSystemdWatchdog().Keepalive(), nil)
+
+	os.Setenv("NOTIFY_SOCKET", filepath.Join(dir, "missing"))
+	gobottest.Refute(t, NewSystemdWatchdog().Keepalive(), nil)
+}